@@ -0,0 +1,147 @@
+package apis
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// bindAppConfigApi registers the dynamic app config api endpoints.
+func bindAppConfigApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/app-config")
+	sub.GET("", appConfigValues)
+
+	entries := sub.Group("/entries").Bind(RequireSuperuserAuth())
+	entries.GET("", appConfigEntriesList)
+	entries.POST("", appConfigEntryCreate)
+	entries.PATCH("/{key}", appConfigEntryUpdate)
+	entries.DELETE("/{key}", appConfigEntryDelete)
+}
+
+// appConfigValues returns a "key: value" map with the app config entries
+// that are readable by the current request auth context.
+//
+// The response has an ETag header so that unchanged values could be
+// revalidated with If-None-Match (responding with 304).
+func appConfigValues(e *core.RequestEvent) error {
+	entries, err := e.App.FindCachedAppConfigEntries()
+	if err != nil {
+		return e.InternalServerError("Failed to load the app config entries.", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	values := make(map[string]types.JSONRaw, len(entries))
+
+	for _, entry := range entries {
+		canAccess, err := e.App.CanAccessAppConfigEntry(entry, requestInfo)
+		if err != nil {
+			e.App.Logger().Debug("Failed to check the app config entry read rule", "key", entry.Key, "error", err)
+		}
+		if canAccess {
+			values[entry.Key] = entry.Value
+		}
+	}
+
+	raw, err := json.Marshal(values)
+	if err != nil {
+		return e.InternalServerError("Failed to serialize the app config values.", err)
+	}
+
+	hash := sha256.Sum256(raw)
+	etag := `W/"` + hex.EncodeToString(hash[:16]) + `"`
+
+	e.Response.Header().Set("ETag", etag)
+	e.Response.Header().Set("Cache-Control", "no-cache")
+
+	if e.Request.Header.Get("If-None-Match") == etag {
+		return e.NoContent(http.StatusNotModified)
+	}
+
+	return e.Blob(http.StatusOK, "application/json", raw)
+}
+
+func appConfigEntriesList(e *core.RequestEvent) error {
+	entries := []*core.AppConfigEntry{}
+
+	if err := e.App.AppConfigQuery().OrderBy("key ASC").All(&entries); err != nil {
+		return e.InternalServerError("Failed to load the app config entries.", err)
+	}
+
+	return e.JSON(http.StatusOK, entries)
+}
+
+func appConfigEntryCreate(e *core.RequestEvent) error {
+	return saveAppConfigEntry(e, &core.AppConfigEntry{})
+}
+
+func appConfigEntryUpdate(e *core.RequestEvent) error {
+	entry, err := e.App.FindAppConfigEntryByKey(e.Request.PathValue("key"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	return saveAppConfigEntry(e, entry)
+}
+
+func appConfigEntryDelete(e *core.RequestEvent) error {
+	entry, err := e.App.FindAppConfigEntryByKey(e.Request.PathValue("key"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.Delete(entry); err != nil {
+		return e.BadRequestError("Failed to delete app config entry.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+type appConfigEntryForm struct {
+	Key         string        `form:"key" json:"key"`
+	Type        string        `form:"type" json:"type"`
+	Value       types.JSONRaw `form:"value" json:"value"`
+	ReadRule    *string       `form:"readRule" json:"readRule"`
+	Description string        `form:"description" json:"description"`
+}
+
+func saveAppConfigEntry(e *core.RequestEvent, entry *core.AppConfigEntry) error {
+	form := &appConfigEntryForm{
+		Key:         entry.Key,
+		Type:        entry.Type,
+		Value:       entry.Value,
+		ReadRule:    entry.ReadRule,
+		Description: entry.Description,
+	}
+
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	entry.Key = form.Key
+	entry.Type = form.Type
+	entry.Value = form.Value
+	entry.ReadRule = form.ReadRule
+	entry.Description = form.Description
+
+	if err := e.App.Save(entry); err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to save app config entry.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to save app config entry.", err)
+	}
+
+	return e.JSON(http.StatusOK, entry)
+}
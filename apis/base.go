@@ -27,24 +27,59 @@ func NewRouter(app core.App) (*router.Router[*core.RequestEvent], error) {
 	})
 
 	// register default middlewares
+	pbRouter.Bind(localizeErrors())
 	pbRouter.Bind(activityLogger())
 	pbRouter.Bind(panicRecover())
 	pbRouter.Bind(rateLimit())
+	pbRouter.Bind(settingsTimeout())
 	pbRouter.Bind(loadAuthToken())
+	pbRouter.Bind(maintenance())
+	pbRouter.Bind(requestCapture())
+	pbRouter.Bind(metering())
 	pbRouter.Bind(securityHeaders())
 	pbRouter.Bind(BodyLimit(DefaultMaxBodySize))
+	pbRouter.Bind(redirects())
 
 	apiGroup := pbRouter.Group("/api")
 	bindSettingsApi(app, apiGroup)
 	bindCollectionApi(app, apiGroup)
+	bindCollectionSchemaApi(app, apiGroup)
 	bindRecordCrudApi(app, apiGroup)
+	bindListPresetsApi(app, apiGroup)
+	bindRecordScheduledChangesApi(app, apiGroup)
+	bindRecordLocksApi(app, apiGroup)
+	bindRecordSharesApi(app, apiGroup)
+	bindRecordPermissionsApi(app, apiGroup)
+	bindRecordCalendarApi(app, apiGroup)
+	bindRecordFeedApi(app, apiGroup)
+	bindSitemapApi(app, apiGroup)
+	bindRecordOGImageApi(app, apiGroup)
+	bindRecordReactionsApi(app, apiGroup)
+	bindRecordFormApi(app, apiGroup)
+	bindRolesApi(app, apiGroup)
+	bindTeamsApi(app, apiGroup)
+	bindSignupInvitesApi(app, apiGroup)
+	bindAppConfigApi(app, apiGroup)
+	bindFeatureFlagsApi(app, apiGroup)
+	bindExperimentsApi(app, apiGroup)
+	bindDigestsApi(app, apiGroup)
+	bindRedirectsApi(app, apiGroup)
+	bindSyncApi(app, apiGroup)
+	bindRecordBulkEmailApi(app, apiGroup)
 	bindRecordAuthApi(app, apiGroup)
+	bindRecycleBinApi(app, apiGroup)
 	bindLogsApi(app, apiGroup)
+	bindLogsReplayApi(app, apiGroup, pbRouter)
+	bindUsageApi(app, apiGroup)
 	bindBackupApi(app, apiGroup)
+	bindMaintenanceApi(app, apiGroup)
+	bindRateLimitsApi(app, apiGroup)
 	bindCronApi(app, apiGroup)
 	bindFileApi(app, apiGroup)
+	bindFileQuarantineApi(app, apiGroup)
 	bindBatchApi(app, apiGroup)
 	bindRealtimeApi(app, apiGroup)
+	bindChangesApi(app, apiGroup)
 	bindHealthApi(app, apiGroup)
 
 	return pbRouter, nil
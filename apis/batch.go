@@ -24,7 +24,19 @@ import (
 
 func bindBatchApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
 	sub := rg.Group("/batch")
-	sub.POST("", batchTransaction).Unbind(DefaultBodyLimitMiddlewareId) // the body limit is inlined
+	sub.POST("", batchTransaction).Unbind(DefaultBodyLimitMiddlewareId)       // the body limit is inlined
+	sub.POST("/validate", batchValidate).Unbind(DefaultBodyLimitMiddlewareId) // the body limit is inlined
+}
+
+// batchValidate handles the batch rule pre-validation requests.
+//
+// The batch requests are processed as usual (including the API rules,
+// the record validations and the request hooks) but the transaction is always
+// rolled back and the response contains the would-be results or error.
+func batchValidate(e *core.RequestEvent) error {
+	e.Set(requestEventKeyDryRun, true)
+
+	return batchTransaction(e)
 }
 
 type HandleFunc func(e *core.RequestEvent) error
@@ -97,6 +109,11 @@ func batchTransaction(e *core.RequestEvent) error {
 		return e.ForbiddenError("Batch requests are not allowed.", nil)
 	}
 
+	// mark explicitly so that the sub-requests could be also checked with IsDryRunRequest
+	if IsDryRunRequest(e) {
+		e.Set(requestEventKeyDryRun, true)
+	}
+
 	txTimeout := time.Duration(e.App.Settings().Batch.Timeout) * time.Second
 	if txTimeout <= 0 {
 		txTimeout = 3 * time.Second // for now always limit
@@ -156,6 +173,7 @@ func batchTransaction(e *core.RequestEvent) error {
 			app:         e.App,
 			baseEvent:   e.RequestEvent,
 			infoContext: core.RequestInfoContextBatch,
+			dryRun:      IsDryRunRequest(e.RequestEvent),
 		}
 
 		if err := bp.Process(e.Batch, txTimeout); err != nil {
@@ -170,12 +188,17 @@ type batchProcessor struct {
 	app         core.App
 	baseEvent   *core.RequestEvent
 	infoContext string
+	dryRun      bool
 	results     []*BatchRequestResult
 	failedIndex int
 	errCh       chan error
 	stopCh      chan struct{}
 }
 
+// Process executes the batch requests in a single transaction.
+//
+// In dry-run mode the transaction is always rolled back
+// (the results are still populated with the would-be responses).
 func (p *batchProcessor) Process(batch []*core.InternalRequest, timeout time.Duration) error {
 	p.results = make([]*BatchRequestResult, 0, len(batch))
 
@@ -189,7 +212,7 @@ func (p *batchProcessor) Process(batch []*core.InternalRequest, timeout time.Dur
 	}
 	p.errCh = make(chan error, 1)
 
-	return p.app.RunInTransaction(func(txApp core.App) error {
+	txErr := p.app.RunInTransaction(func(txApp core.App) error {
 		// used to interupts the recursive processing calls in case of a timeout or connection close
 		defer func() {
 			p.stopCh <- struct{}{}
@@ -220,6 +243,9 @@ func (p *batchProcessor) Process(batch []*core.InternalRequest, timeout time.Dur
 
 		select {
 		case responseErr := <-p.errCh:
+			if responseErr == nil && p.dryRun {
+				return errDryRun
+			}
 			return responseErr
 		case <-time.After(timeout):
 			// note: we don't return 408 Reques Timeout error because
@@ -230,6 +256,11 @@ func (p *batchProcessor) Process(batch []*core.InternalRequest, timeout time.Dur
 			return errors.New("batch request interrupted")
 		}
 	})
+	if errors.Is(txErr, errDryRun) {
+		return nil
+	}
+
+	return txErr
 }
 
 func (p *batchProcessor) process(activeApp core.App, batch []*core.InternalRequest, i int) error {
@@ -153,6 +153,7 @@ func TestBatchRequest(t *testing.T) {
 				"OnRecordAfterCreateError": 3,
 				"OnRecordValidate":         3,
 				"OnRecordEnrich":           2,
+				"OnRecordRequestTransform": 3,
 			},
 			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
 				records, err := app.FindRecordsByFilter("demo2", `title~"batch"`, "", 0, 0)
@@ -211,6 +212,7 @@ func TestBatchRequest(t *testing.T) {
 				"OnRecordUpdate":             1,
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
+				"OnRecordRequestTransform":   4,
 			},
 			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
 				records, err := app.FindRecordsByFilter("demo2", `title~"batch"`, "", 0, 0)
@@ -266,6 +268,7 @@ func TestBatchRequest(t *testing.T) {
 				"OnRecordAfterDeleteError": 1,
 				"OnRecordEnrich":           1,
 				"OnRecordValidate":         1,
+				"OnRecordRequestTransform": 2,
 			},
 			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
 				_, err := app.FindFirstRecordByFilter("demo2", `title="batch_create"`)
@@ -337,6 +340,7 @@ func TestBatchRequest(t *testing.T) {
 				"OnRecordAfterUpdateSuccess": 1,
 				"OnRecordValidate":           2,
 				"OnRecordEnrich":             2,
+				"OnRecordRequestTransform":   2,
 			},
 			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
 				_, err := app.FindFirstRecordByFilter("demo2", `title="batch_create"`)
@@ -408,6 +412,7 @@ func TestBatchRequest(t *testing.T) {
 				"OnRecordAfterUpdateSuccess": 1,
 				"OnRecordValidate":           2,
 				"OnRecordEnrich":             2,
+				"OnRecordRequestTransform":   2,
 			},
 			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
 				_, err := app.FindFirstRecordByFilter("demo2", `title="batch_create"`)
@@ -521,6 +526,7 @@ func TestBatchRequest(t *testing.T) {
 				"OnRecordAfterCreateError": 1,
 				"OnRecordEnrich":           1,
 				"OnRecordValidate":         1,
+				"OnRecordRequestTransform": 2,
 			},
 			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
 				records, err := app.FindRecordsByFilter("demo2", `title~"batch"`, "", 0, 0)
@@ -556,6 +562,7 @@ func TestBatchRequest(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":              0,
+				"OnFileUpload":   4,
 				"OnBatchRequest": 1,
 				// ---
 				"OnModelCreate":             3,
@@ -576,6 +583,7 @@ func TestBatchRequest(t *testing.T) {
 				"OnRecordAfterUpdateSuccess": 1,
 				"OnRecordValidate":           4,
 				"OnRecordEnrich":             4,
+				"OnRecordRequestTransform":   4,
 			},
 			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
 				batch1, err := app.FindFirstRecordByFilter("demo3", `title="batch1"`)
@@ -660,6 +668,7 @@ func TestBatchRequest(t *testing.T) {
 				"OnRecordAfterUpdateSuccess": 1,
 				"OnRecordValidate":           2,
 				"OnRecordEnrich":             5,
+				"OnRecordRequestTransform":   2,
 			},
 		},
 		{
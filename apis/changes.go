@@ -0,0 +1,251 @@
+package apis
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+)
+
+const (
+	changesDefaultLimit = 100
+	changesMaxLimit     = 1000
+	changesMaxWait      = 60 * time.Second
+
+	// fallback poll interval in case the changes were written by another process
+	changesPollInterval = 5 * time.Second
+)
+
+// bindChangesApi registers the changefeed api endpoints.
+func bindChangesApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	notifier := newChangesNotifier()
+
+	notifyHandler := &hook.Handler[*core.RecordEvent]{
+		Func: func(e *core.RecordEvent) error {
+			if e.App.Settings().Changefeed.Enabled {
+				notifier.notify()
+			}
+			return e.Next()
+		},
+		Priority: -99,
+	}
+	app.OnRecordAfterCreateSuccess().Bind(notifyHandler)
+	app.OnRecordAfterUpdateSuccess().Bind(notifyHandler)
+	app.OnRecordAfterDeleteSuccess().Bind(notifyHandler)
+
+	sub := rg.Group("/changes").Bind(RequireSuperuserAuth())
+	sub.GET("", changesList(notifier))
+	sub.GET("/stream", changesStream(notifier)).Bind(SkipSuccessActivityLog())
+}
+
+// changesList returns the changefeed entries after the specified "cursor".
+//
+// If there are no new entries and "wait" (in seconds) query parameter is set,
+// the request is held until a new change arrives or the wait duration expires (aka. long-polling).
+//
+// The returned "cursor" should be submitted with the next request after
+// the consumer has processed the returned items (at-least-once delivery).
+func changesList(notifier *changesNotifier) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		query := e.Request.URL.Query()
+
+		cursor, err := parseChangesCursor(query.Get("cursor"))
+		if err != nil {
+			return e.BadRequestError("Invalid cursor.", err)
+		}
+
+		limit, _ := strconv.Atoi(query.Get("limit"))
+		if limit <= 0 {
+			limit = changesDefaultLimit
+		} else if limit > changesMaxLimit {
+			limit = changesMaxLimit
+		}
+
+		wait, _ := strconv.ParseFloat(query.Get("wait"), 64)
+		waitDuration := min(time.Duration(wait*float64(time.Second)), changesMaxWait)
+
+		collection := query.Get("collection")
+
+		deadline := time.NewTimer(waitDuration)
+		defer deadline.Stop()
+
+		for {
+			// subscribe before the query to avoid missing changes written in between
+			changed := notifier.wait()
+
+			items, err := e.App.FindChanges(cursor, collection, limit)
+			if err != nil {
+				return e.InternalServerError("Failed to load the changefeed entries.", err)
+			}
+
+			if len(items) > 0 || waitDuration <= 0 {
+				return e.JSON(http.StatusOK, newChangesListResult(cursor, items))
+			}
+
+			select {
+			case <-changed:
+			case <-time.After(changesPollInterval):
+			case <-deadline.C:
+				return e.JSON(http.StatusOK, newChangesListResult(cursor, items))
+			case <-e.Request.Context().Done():
+				return nil
+			}
+		}
+	}
+}
+
+type changesListResult struct {
+	Items  []*core.Change `json:"items"`
+	Cursor int64          `json:"cursor"`
+}
+
+func newChangesListResult(cursor int64, items []*core.Change) *changesListResult {
+	if len(items) > 0 {
+		cursor = items[len(items)-1].Seq
+	}
+
+	return &changesListResult{Cursor: cursor, Items: items}
+}
+
+// changesStream streams the changefeed entries after the specified
+// "cursor" query parameter (or "Last-Event-ID" header) as SSE events.
+//
+// Each SSE event id is the change Seq so that the standard EventSource
+// reconnect resumes from the last received change.
+func changesStream(notifier *changesNotifier) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		rawCursor := e.Request.URL.Query().Get("cursor")
+		if lastEventId := e.Request.Header.Get("Last-Event-ID"); lastEventId != "" {
+			rawCursor = lastEventId
+		}
+
+		cursor, err := parseChangesCursor(rawCursor)
+		if err != nil {
+			return e.BadRequestError("Invalid cursor.", err)
+		}
+
+		collection := e.Request.URL.Query().Get("collection")
+
+		// disable global write deadline for the SSE connection
+		rc := http.NewResponseController(e.Response)
+		writeDeadlineErr := rc.SetWriteDeadline(time.Time{})
+		if writeDeadlineErr != nil && !errors.Is(writeDeadlineErr, http.ErrNotSupported) {
+			return e.InternalServerError("Failed to initialize SSE connection.", writeDeadlineErr)
+		}
+
+		e.Response.Header().Set("Content-Type", "text/event-stream")
+		e.Response.Header().Set("Cache-Control", "no-store")
+		e.Response.Header().Set("X-Accel-Buffering", "no")
+
+		var heartbeatCh <-chan time.Time
+		if interval := e.App.Settings().Realtime.HeartbeatInterval; interval > 0 {
+			heartbeatTicker := time.NewTicker(time.Duration(interval) * time.Second)
+			defer heartbeatTicker.Stop()
+			heartbeatCh = heartbeatTicker.C
+		}
+
+		for {
+			changed := notifier.wait()
+
+			items, err := e.App.FindChanges(cursor, collection, changesMaxLimit)
+			if err != nil {
+				e.App.Logger().Debug("Changefeed stream closed (failed to load the changes)", "error", err)
+				return nil
+			}
+
+			for _, item := range items {
+				data, err := json.Marshal(item)
+				if err != nil {
+					return nil
+				}
+
+				msg := subscriptions.Message{Name: "change", Data: data}
+				if err := msg.WriteSSE(e.Response, strconv.FormatInt(item.Seq, 10)); err != nil {
+					return nil
+				}
+
+				cursor = item.Seq
+			}
+
+			if err := e.Flush(); err != nil {
+				return nil
+			}
+
+			if len(items) == changesMaxLimit {
+				continue // there are more changes
+			}
+
+		waitLoop:
+			for {
+				select {
+				case <-changed:
+					break waitLoop
+				case <-time.After(changesPollInterval):
+					break waitLoop
+				case <-heartbeatCh:
+					if err := subscriptions.WriteSSEComment(e.Response, "ping"); err != nil {
+						return nil
+					}
+					if err := e.Flush(); err != nil {
+						return nil
+					}
+				case <-e.Request.Context().Done():
+					return nil
+				}
+			}
+		}
+	}
+}
+
+func parseChangesCursor(raw string) (int64, error) {
+	if raw == "" {
+		return 0, nil
+	}
+
+	cursor, err := strconv.ParseInt(raw, 10, 64)
+	if err != nil {
+		return 0, err
+	}
+
+	if cursor < 0 {
+		return 0, errors.New("the cursor must be a non-negative integer")
+	}
+
+	return cursor, nil
+}
+
+// -------------------------------------------------------------------
+
+// changesNotifier is a simple broadcaster that signals the waiting
+// changefeed consumers that new changes may be available.
+type changesNotifier struct {
+	ch chan struct{}
+	mu sync.Mutex
+}
+
+func newChangesNotifier() *changesNotifier {
+	return &changesNotifier{ch: make(chan struct{})}
+}
+
+// wait returns a channel that will be closed on the next notify call.
+func (n *changesNotifier) wait() <-chan struct{} {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.ch
+}
+
+func (n *changesNotifier) notify() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	close(n.ch)
+	n.ch = make(chan struct{})
+}
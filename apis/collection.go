@@ -2,6 +2,7 @@ package apis
 
 import (
 	"errors"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -22,6 +23,7 @@ func bindCollectionApi(app core.App, rg *router.RouterGroup[*core.RequestEvent])
 	subGroup.DELETE("/{collection}", collectionDelete)
 	subGroup.DELETE("/{collection}/truncate", collectionTruncate)
 	subGroup.PUT("/import", collectionsImport)
+	subGroup.POST("/export", collectionsExport)
 	subGroup.GET("/meta/scaffolds", collectionScaffolds)
 }
 
@@ -189,6 +191,13 @@ func collectionTruncate(e *core.RequestEvent) error {
 		return e.BadRequestError("Failed to truncate collection (most likely due to required cascade delete record references).", err)
 	}
 
+	logSuperuserAction(
+		e,
+		"collectionTruncate",
+		slog.String("collectionId", collection.Id),
+		slog.String("collectionName", collection.Name),
+	)
+
 	return e.NoContent(http.StatusNoContent)
 }
 
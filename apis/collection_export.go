@@ -0,0 +1,60 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func collectionsExport(e *core.RequestEvent) error {
+	form := new(collectionsExportForm)
+
+	err := e.BindBody(form)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
+	}
+
+	var collections []*core.Collection
+
+	if len(form.Collections) == 0 {
+		collections, err = e.App.FindAllCollections()
+		if err != nil {
+			return e.InternalServerError("Failed to load the collections.", err)
+		}
+	} else {
+		collections = make([]*core.Collection, 0, len(form.Collections))
+		for i, nameOrId := range form.Collections {
+			collection, err := e.App.FindCollectionByNameOrId(nameOrId)
+			if err != nil {
+				return e.BadRequestError("An error occurred while validating the submitted data.", validation.Errors{
+					"collections": validation.Errors{
+						fmt.Sprint(i): validation.NewError("validation_missing_collection", "Missing or invalid collection."),
+					},
+				})
+			}
+			collections = append(collections, collection)
+		}
+	}
+
+	name := fmt.Sprintf("pb_collections_%s.zip", time.Now().UTC().Format("20060102150405"))
+
+	e.Response.Header().Set("Content-Type", "application/zip")
+	e.Response.Header().Set("Content-Disposition", `attachment; filename="`+name+`"`)
+	e.Response.WriteHeader(http.StatusOK)
+
+	return e.App.ExportCollectionsArchive(e.Response, collections, form.Data)
+}
+
+// -------------------------------------------------------------------
+
+type collectionsExportForm struct {
+	// Collections is an optional list with the names or ids of the
+	// collections to export (if empty, all collections are exported).
+	Collections []string `form:"collections" json:"collections"`
+
+	// Data indicates whether to include the collections records and files.
+	Data bool `form:"data" json:"data"`
+}
@@ -1,6 +1,7 @@
 package apis
 
 import (
+	"archive/zip"
 	"errors"
 	"net/http"
 
@@ -16,6 +17,22 @@ func collectionsImport(e *core.RequestEvent) error {
 		return firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
 	}
 
+	// multipart collections archive import (with records and files)
+	var archive *zip.Reader
+	if file, header, _ := e.Request.FormFile("archive"); file != nil {
+		defer file.Close()
+
+		archive, err = zip.NewReader(file, header.Size)
+		if err != nil {
+			return e.BadRequestError("Invalid collections archive.", err)
+		}
+
+		form.Collections, err = core.ReadCollectionsArchive(archive)
+		if err != nil {
+			return e.BadRequestError("Invalid collections archive.", err)
+		}
+	}
+
 	err = form.validate()
 	if err != nil {
 		return firstApiError(err, e.BadRequestError("An error occurred while validating the submitted data.", err))
@@ -25,9 +42,15 @@ func collectionsImport(e *core.RequestEvent) error {
 	event.RequestEvent = e
 	event.CollectionsData = form.Collections
 	event.DeleteMissing = form.DeleteMissing
+	event.Archive = archive
 
 	return event.App.OnCollectionsImportRequest().Trigger(event, func(e *core.CollectionsImportRequestEvent) error {
-		importErr := e.App.ImportCollections(e.CollectionsData, form.DeleteMissing)
+		var importErr error
+		if e.Archive != nil {
+			importErr = e.App.ImportCollectionsArchive(e.CollectionsData, e.Archive, form.DeleteMissing)
+		} else {
+			importErr = e.App.ImportCollections(e.CollectionsData, form.DeleteMissing)
+		}
 		if importErr == nil {
 			return execAfterSuccessTx(true, e.App, func() error {
 				return e.NoContent(http.StatusNoContent)
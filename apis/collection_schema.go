@@ -0,0 +1,148 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+const (
+	RuleAccessSuperusers  = "superusers"
+	RuleAccessPublic      = "public"
+	RuleAccessConditional = "conditional"
+)
+
+// CollectionSchema defines the machine-readable description of the
+// collection records API as returned by the "GET /api/collections/{collection}/schema" endpoint.
+type CollectionSchema struct {
+	Id   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+
+	// Fields lists the collection fields visible to the current request
+	// (the hidden fields are returned only to superusers).
+	Fields []core.Field `json:"fields"`
+
+	// Rules contains the access description of each available records API action
+	// (list, view, create, update, delete and for auth collections - auth and manage).
+	Rules map[string]*CollectionSchemaRule `json:"rules"`
+
+	// QueryParams lists the supported query parameters of each available records API action.
+	QueryParams map[string][]string `json:"queryParams"`
+}
+
+// CollectionSchemaRule defines a single collection API rule description.
+type CollectionSchemaRule struct {
+	// Access is one of "superusers" (locked rule), "public" (empty rule)
+	// or "conditional" (rule with filter expression).
+	Access string `json:"access"`
+
+	// Expression is the raw rule filter expression.
+	//
+	// It is returned only to superusers.
+	Expression *string `json:"expression,omitempty"`
+}
+
+// bindCollectionSchemaApi registers the public collection schema api endpoint.
+func bindCollectionSchemaApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	rg.GET("/collections/{collection}/schema", collectionSchema)
+}
+
+// collectionSchema returns the effective collection fields, rules and query
+// parameters for the current request auth.
+//
+// Non-superusers could access the schema only if the collection
+// list or view API rules are not locked.
+func collectionSchema(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	isSuperuser := e.HasSuperuserAuth()
+
+	if !isSuperuser && collection.ListRule == nil && collection.ViewRule == nil {
+		return e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	return e.JSON(http.StatusOK, newCollectionSchema(collection, isSuperuser))
+}
+
+func newCollectionSchema(collection *core.Collection, withSecrets bool) *CollectionSchema {
+	schema := &CollectionSchema{
+		Id:          collection.Id,
+		Name:        collection.Name,
+		Type:        collection.Type,
+		Fields:      make([]core.Field, 0, len(collection.Fields)),
+		Rules:       map[string]*CollectionSchemaRule{},
+		QueryParams: map[string][]string{},
+	}
+
+	for _, field := range collection.Fields {
+		if field.GetHidden() && !withSecrets {
+			continue
+		}
+		schema.Fields = append(schema.Fields, field)
+	}
+
+	resultParams := []string{expandQueryParam, fieldsQueryParam}
+
+	schema.Rules["list"] = newCollectionSchemaRule(collection.ListRule, withSecrets)
+	schema.QueryParams["list"] = []string{
+		search.PageQueryParam,
+		search.PerPageQueryParam,
+		search.SortQueryParam,
+		search.FilterQueryParam,
+		search.SkipTotalQueryParam,
+		search.CountOnlyQueryParam,
+		search.EstimateTotalQueryParam,
+		expandQueryParam,
+		fieldsQueryParam,
+		listPresetQueryParam,
+	}
+
+	schema.Rules["view"] = newCollectionSchemaRule(collection.ViewRule, withSecrets)
+	schema.QueryParams["view"] = resultParams
+
+	if collection.IsView() {
+		return schema
+	}
+
+	schema.Rules["create"] = newCollectionSchemaRule(collection.CreateRule, withSecrets)
+	schema.QueryParams["create"] = resultParams
+
+	schema.Rules["update"] = newCollectionSchemaRule(collection.UpdateRule, withSecrets)
+	schema.QueryParams["update"] = resultParams
+
+	schema.Rules["delete"] = newCollectionSchemaRule(collection.DeleteRule, withSecrets)
+	schema.QueryParams["delete"] = []string{}
+
+	if collection.IsAuth() {
+		schema.Rules["auth"] = newCollectionSchemaRule(collection.AuthRule, withSecrets)
+		schema.Rules["manage"] = newCollectionSchemaRule(collection.ManageRule, withSecrets)
+	}
+
+	return schema
+}
+
+func newCollectionSchemaRule(rule *string, withExpression bool) *CollectionSchemaRule {
+	result := &CollectionSchemaRule{}
+
+	switch {
+	case rule == nil:
+		result.Access = RuleAccessSuperusers
+	case *rule == "":
+		result.Access = RuleAccessPublic
+	default:
+		result.Access = RuleAccessConditional
+	}
+
+	if withExpression && rule != nil {
+		expr := *rule
+		result.Expression = &expr
+	}
+
+	return result
+}
@@ -0,0 +1,180 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+const digestsCronKey = "__pbDigests__"
+
+// bindDigestsApi registers the digest emails api endpoints and the digests cron job.
+func bindDigestsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/digests").Bind(RequireSuperuserAuth())
+	sub.GET("", digestsList)
+	sub.POST("", digestCreate)
+	sub.PATCH("/{name}", digestUpdate)
+	sub.DELETE("/{name}", digestDelete)
+	sub.POST("/{name}/send", digestSend)
+
+	// prevents overlapping runs in case of large digests
+	var processing atomic.Bool
+
+	app.Cron().Add(digestsCronKey, "* * * * *", func() {
+		if !processing.CompareAndSwap(false, true) {
+			return
+		}
+		defer processing.Store(false)
+
+		queueDueDigests(app, time.Now())
+	})
+}
+
+// queueDueDigests queues the emails of all active digests which schedule matches t.
+func queueDueDigests(app core.App, t time.Time) {
+	digests := []*core.Digest{}
+
+	err := app.DigestQuery().AndWhere(dbx.HashExp{"active": true}).All(&digests)
+	if err != nil {
+		app.Logger().Warn("Failed to load the active digests", "error", err)
+		return
+	}
+
+	for _, digest := range digests {
+		if !digest.IsDue(t) {
+			continue
+		}
+
+		if _, err := mails.QueueDigest(app, digest); err != nil {
+			app.Logger().Warn("Failed to queue digest emails", "name", digest.Name, "error", err)
+		}
+	}
+}
+
+func digestsList(e *core.RequestEvent) error {
+	digests := []*core.Digest{}
+
+	if err := e.App.DigestQuery().OrderBy("name ASC").All(&digests); err != nil {
+		return e.InternalServerError("Failed to load the digests.", err)
+	}
+
+	return e.JSON(http.StatusOK, digests)
+}
+
+func digestCreate(e *core.RequestEvent) error {
+	return saveDigest(e, &core.Digest{MaxItems: 50})
+}
+
+func digestUpdate(e *core.RequestEvent) error {
+	digest, err := e.App.FindDigestByName(e.Request.PathValue("name"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	return saveDigest(e, digest)
+}
+
+func digestDelete(e *core.RequestEvent) error {
+	digest, err := e.App.FindDigestByName(e.Request.PathValue("name"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.Delete(digest); err != nil {
+		return e.BadRequestError("Failed to delete digest.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// digestSend immediately queues the current period emails of the path digest
+// (regardless of its schedule and active state).
+func digestSend(e *core.RequestEvent) error {
+	digest, err := e.App.FindDigestByName(e.Request.PathValue("name"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	total, err := mails.QueueDigest(e.App, digest)
+	if err != nil {
+		return e.BadRequestError("Failed to queue the digest emails.", err)
+	}
+
+	return e.JSON(http.StatusOK, map[string]int{"total": total})
+}
+
+type digestForm struct {
+	Name                    string `form:"name" json:"name"`
+	Active                  bool   `form:"active" json:"active"`
+	Schedule                string `form:"schedule" json:"schedule"`
+	CollectionRef           string `form:"collectionRef" json:"collectionRef"`
+	Filter                  string `form:"filter" json:"filter"`
+	Sort                    string `form:"sort" json:"sort"`
+	MaxItems                int    `form:"maxItems" json:"maxItems"`
+	RecipientsCollectionRef string `form:"recipientsCollectionRef" json:"recipientsCollectionRef"`
+	RecipientsFilter        string `form:"recipientsFilter" json:"recipientsFilter"`
+	Subject                 string `form:"subject" json:"subject"`
+	Body                    string `form:"body" json:"body"`
+	ItemTemplate            string `form:"itemTemplate" json:"itemTemplate"`
+}
+
+func saveDigest(e *core.RequestEvent, digest *core.Digest) error {
+	form := &digestForm{
+		Name:                    digest.Name,
+		Active:                  digest.Active,
+		Schedule:                digest.Schedule,
+		CollectionRef:           digest.CollectionRef,
+		Filter:                  digest.Filter,
+		Sort:                    digest.Sort,
+		MaxItems:                digest.MaxItems,
+		RecipientsCollectionRef: digest.RecipientsCollectionRef,
+		RecipientsFilter:        digest.RecipientsFilter,
+		Subject:                 digest.Subject,
+		Body:                    digest.Body,
+		ItemTemplate:            digest.ItemTemplate,
+	}
+
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	digest.Name = form.Name
+	digest.Active = form.Active
+	digest.Schedule = form.Schedule
+	digest.Filter = form.Filter
+	digest.Sort = form.Sort
+	digest.MaxItems = form.MaxItems
+	digest.RecipientsFilter = form.RecipientsFilter
+	digest.Subject = form.Subject
+	digest.Body = form.Body
+	digest.ItemTemplate = form.ItemTemplate
+
+	// normalize the collection identifiers
+	digest.CollectionRef = form.CollectionRef
+	if collection, err := e.App.FindCachedCollectionByNameOrId(form.CollectionRef); err == nil {
+		digest.CollectionRef = collection.Id
+	}
+
+	digest.RecipientsCollectionRef = form.RecipientsCollectionRef
+	if collection, err := e.App.FindCachedCollectionByNameOrId(form.RecipientsCollectionRef); err == nil {
+		digest.RecipientsCollectionRef = collection.Id
+	}
+
+	if err := e.App.Save(digest); err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to save digest.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to save digest.", err)
+	}
+
+	return e.JSON(http.StatusOK, digest)
+}
@@ -0,0 +1,71 @@
+package apis
+
+import (
+	"errors"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cast"
+)
+
+// DryRunQueryParam is the name of the query parameter that enables
+// the dry-run mode of the batch and the record create/update endpoints.
+//
+// In dry-run mode the request is processed as usual (including the API rules,
+// the record validations and the request hooks) but the changes are never
+// committed and the response contains the would-be result or error.
+const DryRunQueryParam = "dryRun"
+
+// requestEventKeyDryRun is the request event store key used to mark
+// the dry-run requests (it is inherited by the batch sub-requests).
+const requestEventKeyDryRun = "pbDryRun"
+
+// errDryRun is used to rollback the dry-run request transaction.
+var errDryRun = errors.New("dry-run rollback")
+
+// IsDryRunRequest reports whether the current request has the dry-run mode enabled
+// (including the requests of the batch pre-validation endpoint and their sub-requests).
+//
+// It could be used in the request hooks to skip side effects that
+// are not part of the transaction (e.g. sending emails).
+func IsDryRunRequest(e *core.RequestEvent) bool {
+	if v, _ := e.Get(requestEventKeyDryRun).(bool); v {
+		return true
+	}
+
+	return cast.ToBool(e.Request.URL.Query().Get(DryRunQueryParam))
+}
+
+// recordWriteWithDryRun returns the specified record create/update handler
+// with support for the dry-run mode (see [DryRunQueryParam]).
+func recordWriteWithDryRun(handlerFactory func(responseWriteAfterTx bool, optFinalizer func(data any) error) func(e *core.RequestEvent) error) func(e *core.RequestEvent) error {
+	handler := handlerFactory(true, nil)
+
+	// write the response immediately since the transaction is always rolled back
+	dryRunHandler := handlerFactory(false, nil)
+
+	return func(e *core.RequestEvent) error {
+		if !IsDryRunRequest(e) {
+			return handler(e)
+		}
+
+		originalApp := e.App
+		defer func() {
+			e.App = originalApp
+		}()
+
+		err := originalApp.RunInTransaction(func(txApp core.App) error {
+			e.App = txApp
+
+			if err := dryRunHandler(e); err != nil {
+				return err
+			}
+
+			return errDryRun
+		})
+		if errors.Is(err, errDryRun) {
+			return nil
+		}
+
+		return err
+	}
+}
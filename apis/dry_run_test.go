@@ -0,0 +1,293 @@
+package apis_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+
+	ensureNoDryRunRecords := func(t testing.TB, app *tests.TestApp, res *http.Response) {
+		records, err := app.FindRecordsByFilter("demo2", `title~"dry"`, "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(records) != 0 {
+			t.Fatalf("Expected no dry-run records to be persisted, got %d", len(records))
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "create with failed validations",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/demo2/records?dryRun=true",
+			Body:           strings.NewReader(`{"title":""}`),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"title":{"code":"validation_required"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnRecordCreateRequest":    1,
+				"OnModelCreate":            1,
+				"OnModelValidate":          1,
+				"OnModelAfterCreateError":  1,
+				"OnRecordCreate":           1,
+				"OnRecordValidate":         1,
+				"OnRecordAfterCreateError": 1,
+				"OnRecordRequestTransform": 1,
+			},
+			AfterTestFunc: ensureNoDryRunRecords,
+		},
+		{
+			Name:           "create with successful validations",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/demo2/records?dryRun=true",
+			Body:           strings.NewReader(`{"title":"dry1"}`),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"title":"dry1"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnRecordCreateRequest":    1,
+				"OnModelCreate":            1,
+				"OnModelCreateExecute":     1,
+				"OnModelValidate":          1,
+				"OnModelAfterCreateError":  1,
+				"OnRecordCreate":           1,
+				"OnRecordCreateExecute":    1,
+				"OnRecordValidate":         1,
+				"OnRecordAfterCreateError": 1,
+				"OnRecordEnrich":           1,
+				"OnRecordRequestTransform": 1,
+			},
+			AfterTestFunc: ensureNoDryRunRecords,
+		},
+		{
+			Name:           "update with failed rules",
+			Method:         http.MethodPatch,
+			URL:            "/api/collections/demo4/records/qzaqccwrmva4o1n?dryRun=1",
+			Body:           strings.NewReader(`{"title":"dry2"}`),
+			ExpectedStatus: 404,
+			ExpectedContent: []string{
+				`"data":{}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0, "OnRecordRequestTransform": 1},
+		},
+		{
+			Name:           "update with successful validations",
+			Method:         http.MethodPatch,
+			URL:            "/api/collections/demo2/records/achvryl401bhse3?dryRun=1",
+			Body:           strings.NewReader(`{"title":"dry2"}`),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"title":"dry2"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnRecordUpdateRequest":    1,
+				"OnModelUpdate":            1,
+				"OnModelUpdateExecute":     1,
+				"OnModelValidate":          1,
+				"OnModelAfterUpdateError":  1,
+				"OnRecordUpdate":           1,
+				"OnRecordUpdateExecute":    1,
+				"OnRecordValidate":         1,
+				"OnRecordAfterUpdateError": 1,
+				"OnRecordEnrich":           1,
+				"OnRecordRequestTransform": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				record, err := app.FindRecordById("demo2", "achvryl401bhse3")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if v := record.GetString("title"); v != "test2" {
+					t.Fatalf("Expected the record title to remain unchanged, got %q", v)
+				}
+			},
+		},
+		{
+			Name:   "batch with failed request",
+			Method: http.MethodPost,
+			URL:    "/api/batch?dryRun=true",
+			Body: strings.NewReader(`{
+				"requests": [
+					{"method":"POST", "url":"/api/collections/demo2/records", "body": {"title": "dry1"}},
+					{"method":"POST", "url":"/api/collections/demo2/records", "body": {"title": ""}}
+				]
+			}`),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"1":{"code":"batch_request_failed"`,
+				`"response":{"data":{"title":{"code":"validation_required"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnBatchRequest":           1,
+				"OnRecordCreateRequest":    2,
+				"OnModelCreate":            2,
+				"OnModelCreateExecute":     1,
+				"OnModelValidate":          2,
+				"OnModelAfterCreateError":  2,
+				"OnRecordCreate":           2,
+				"OnRecordCreateExecute":    1,
+				"OnRecordValidate":         2,
+				"OnRecordAfterCreateError": 2,
+				"OnRecordEnrich":           1,
+				"OnRecordRequestTransform": 2,
+			},
+			AfterTestFunc: ensureNoDryRunRecords,
+		},
+		{
+			Name:   "batch with successful requests",
+			Method: http.MethodPost,
+			URL:    "/api/batch?dryRun=true",
+			Body: strings.NewReader(`{
+				"requests": [
+					{"method":"POST", "url":"/api/collections/demo2/records", "body": {"title": "dry1"}},
+					{"method":"PATCH", "url":"/api/collections/demo2/records/achvryl401bhse3", "body": {"title": "dry2"}}
+				]
+			}`),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"status":200`,
+				`"title":"dry1"`,
+				`"title":"dry2"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnBatchRequest":           1,
+				"OnModelValidate":          2,
+				"OnRecordValidate":         2,
+				"OnRecordEnrich":           2,
+				"OnRecordCreateRequest":    1,
+				"OnModelCreate":            1,
+				"OnModelCreateExecute":     1,
+				"OnModelAfterCreateError":  1,
+				"OnRecordCreate":           1,
+				"OnRecordCreateExecute":    1,
+				"OnRecordAfterCreateError": 1,
+				"OnRecordUpdateRequest":    1,
+				"OnModelUpdate":            1,
+				"OnModelUpdateExecute":     1,
+				"OnModelAfterUpdateError":  1,
+				"OnRecordUpdate":           1,
+				"OnRecordUpdateExecute":    1,
+				"OnRecordAfterUpdateError": 1,
+				"OnRecordRequestTransform": 2,
+			},
+			AfterTestFunc: ensureNoDryRunRecords,
+		},
+		{
+			Name:   "batch validate endpoint with disabled batch api",
+			Method: http.MethodPost,
+			URL:    "/api/batch/validate",
+			Body: strings.NewReader(`{
+				"requests": [
+					{"method":"POST", "url":"/api/collections/demo2/records", "body": {"title": "dry1"}}
+				]
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.Settings().Batch.Enabled = false
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "batch validate endpoint with failed request",
+			Method: http.MethodPost,
+			URL:    "/api/batch/validate",
+			Body: strings.NewReader(`{
+				"requests": [
+					{"method":"POST", "url":"/api/collections/demo2/records", "body": {"title": "dry1"}},
+					{"method":"POST", "url":"/api/collections/demo2/records", "body": {"title": ""}}
+				]
+			}`),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"1":{"code":"batch_request_failed"`,
+				`"response":{"data":{"title":{"code":"validation_required"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnBatchRequest":           1,
+				"OnRecordCreateRequest":    2,
+				"OnModelCreate":            2,
+				"OnModelCreateExecute":     1,
+				"OnModelValidate":          2,
+				"OnModelAfterCreateError":  2,
+				"OnRecordCreate":           2,
+				"OnRecordCreateExecute":    1,
+				"OnRecordValidate":         2,
+				"OnRecordAfterCreateError": 2,
+				"OnRecordEnrich":           1,
+				"OnRecordRequestTransform": 2,
+			},
+			AfterTestFunc: ensureNoDryRunRecords,
+		},
+		{
+			Name:   "batch validate endpoint with successful requests",
+			Method: http.MethodPost,
+			URL:    "/api/batch/validate",
+			Body: strings.NewReader(`{
+				"requests": [
+					{"method":"POST", "url":"/api/collections/demo2/records", "body": {"title": "dry1"}},
+					{"method":"PATCH", "url":"/api/collections/demo2/records/achvryl401bhse3", "body": {"title": "dry2"}}
+				]
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.OnRecordCreateRequest().BindFunc(func(e *core.RecordRequestEvent) error {
+					if !apis.IsDryRunRequest(e.RequestEvent) {
+						t.Fatal("Expected the batch sub-request to be marked as dry-run")
+					}
+					return e.Next()
+				})
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"status":200`,
+				`"title":"dry1"`,
+				`"title":"dry2"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnBatchRequest":           1,
+				"OnModelValidate":          2,
+				"OnRecordValidate":         2,
+				"OnRecordEnrich":           2,
+				"OnRecordCreateRequest":    1,
+				"OnModelCreate":            1,
+				"OnModelCreateExecute":     1,
+				"OnModelAfterCreateError":  1,
+				"OnRecordCreate":           1,
+				"OnRecordCreateExecute":    1,
+				"OnRecordAfterCreateError": 1,
+				"OnRecordUpdateRequest":    1,
+				"OnModelUpdate":            1,
+				"OnModelUpdateExecute":     1,
+				"OnModelAfterUpdateError":  1,
+				"OnRecordUpdate":           1,
+				"OnRecordUpdateExecute":    1,
+				"OnRecordAfterUpdateError": 1,
+				"OnRecordRequestTransform": 2,
+			},
+			AfterTestFunc: ensureNoDryRunRecords,
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,140 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindExperimentsApi registers the A/B experiments api endpoints.
+func bindExperimentsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/experiments")
+	sub.GET("", experimentsList).Bind(RequireSuperuserAuth())
+	sub.POST("", experimentCreate).Bind(RequireSuperuserAuth())
+	sub.PATCH("/{key}", experimentUpdate).Bind(RequireSuperuserAuth())
+	sub.DELETE("/{key}", experimentDelete).Bind(RequireSuperuserAuth())
+	sub.GET("/{key}/assignment", experimentAssignment)
+	sub.POST("/{key}/exposure", experimentExposure)
+}
+
+type experimentAssignmentResponse struct {
+	Experiment string `json:"experiment"`
+	Variant    string `json:"variant"`
+}
+
+// experimentAssignment returns the experiment variant assigned to the current request subject
+// (the auth record or the [core.FeatureFlagSubjectHeader] value for guests).
+//
+// The variant is empty if the subject is not enrolled in the experiment.
+func experimentAssignment(e *core.RequestEvent) error {
+	return handleExperimentAssignment(e, e.App.AssignExperimentVariant)
+}
+
+// experimentExposure is similar to [experimentAssignment] but it also
+// logs the exposure of the assigned variant (eg. when the variant was shown to the user).
+func experimentExposure(e *core.RequestEvent) error {
+	return handleExperimentAssignment(e, e.App.TrackExperimentExposure)
+}
+
+func handleExperimentAssignment(
+	e *core.RequestEvent,
+	assign func(experiment *core.Experiment, requestInfo *core.RequestInfo) (string, error),
+) error {
+	experiment, err := e.App.FindExperimentByKey(e.Request.PathValue("key"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	variant, err := assign(experiment, requestInfo)
+	if err != nil {
+		return e.BadRequestError("Failed to assign experiment variant.", err)
+	}
+
+	return e.JSON(http.StatusOK, experimentAssignmentResponse{
+		Experiment: experiment.Key,
+		Variant:    variant,
+	})
+}
+
+func experimentsList(e *core.RequestEvent) error {
+	experiments := []*core.Experiment{}
+
+	if err := e.App.ExperimentQuery().OrderBy("key ASC").All(&experiments); err != nil {
+		return e.InternalServerError("Failed to load the experiments.", err)
+	}
+
+	return e.JSON(http.StatusOK, experiments)
+}
+
+func experimentCreate(e *core.RequestEvent) error {
+	return saveExperiment(e, &core.Experiment{})
+}
+
+func experimentUpdate(e *core.RequestEvent) error {
+	experiment, err := e.App.FindExperimentByKey(e.Request.PathValue("key"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	return saveExperiment(e, experiment)
+}
+
+func experimentDelete(e *core.RequestEvent) error {
+	experiment, err := e.App.FindExperimentByKey(e.Request.PathValue("key"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.Delete(experiment); err != nil {
+		return e.BadRequestError("Failed to delete experiment.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+type experimentForm struct {
+	Key         string                   `form:"key" json:"key"`
+	Description string                   `form:"description" json:"description"`
+	Active      bool                     `form:"active" json:"active"`
+	FlagKey     string                   `form:"flagKey" json:"flagKey"`
+	Variants    []core.ExperimentVariant `form:"variants" json:"variants"`
+}
+
+func saveExperiment(e *core.RequestEvent, experiment *core.Experiment) error {
+	form := &experimentForm{
+		Key:         experiment.Key,
+		Description: experiment.Description,
+		Active:      experiment.Active,
+		FlagKey:     experiment.FlagKey,
+		Variants:    experiment.Variants,
+	}
+
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	experiment.Key = form.Key
+	experiment.Description = form.Description
+	experiment.Active = form.Active
+	experiment.FlagKey = form.FlagKey
+	experiment.Variants = form.Variants
+
+	if err := e.App.Save(experiment); err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to save experiment.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to save experiment.", err)
+	}
+
+	return e.JSON(http.StatusOK, experiment)
+}
@@ -0,0 +1,118 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindFeatureFlagsApi registers the feature flags api endpoints.
+func bindFeatureFlagsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/feature-flags")
+	sub.GET("", featureFlagsEvaluate)
+
+	definitions := sub.Group("/definitions").Bind(RequireSuperuserAuth())
+	definitions.GET("", featureFlagsList)
+	definitions.POST("", featureFlagCreate)
+	definitions.PATCH("/{key}", featureFlagUpdate)
+	definitions.DELETE("/{key}", featureFlagDelete)
+}
+
+// featureFlagsEvaluate returns the evaluation results of all feature flags
+// for the current request (keyed by the flag key).
+//
+// Guests could specify a stable rollout subject with the [core.FeatureFlagSubjectHeader] header.
+func featureFlagsEvaluate(e *core.RequestEvent) error {
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	result, err := e.App.EvaluateFeatureFlags(requestInfo)
+	if err != nil {
+		return e.InternalServerError("Failed to evaluate the feature flags.", err)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+func featureFlagsList(e *core.RequestEvent) error {
+	flags := []*core.FeatureFlag{}
+
+	if err := e.App.FeatureFlagQuery().OrderBy("key ASC").All(&flags); err != nil {
+		return e.InternalServerError("Failed to load the feature flags.", err)
+	}
+
+	return e.JSON(http.StatusOK, flags)
+}
+
+func featureFlagCreate(e *core.RequestEvent) error {
+	return saveFeatureFlag(e, &core.FeatureFlag{Rollout: 100})
+}
+
+func featureFlagUpdate(e *core.RequestEvent) error {
+	flag, err := e.App.FindFeatureFlagByKey(e.Request.PathValue("key"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	return saveFeatureFlag(e, flag)
+}
+
+func featureFlagDelete(e *core.RequestEvent) error {
+	flag, err := e.App.FindFeatureFlagByKey(e.Request.PathValue("key"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.Delete(flag); err != nil {
+		return e.BadRequestError("Failed to delete feature flag.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+type featureFlagForm struct {
+	Key         string   `form:"key" json:"key"`
+	Description string   `form:"description" json:"description"`
+	Enabled     bool     `form:"enabled" json:"enabled"`
+	Rule        string   `form:"rule" json:"rule"`
+	Rollout     int      `form:"rollout" json:"rollout"`
+	Variants    []string `form:"variants" json:"variants"`
+}
+
+func saveFeatureFlag(e *core.RequestEvent, flag *core.FeatureFlag) error {
+	form := &featureFlagForm{
+		Key:         flag.Key,
+		Description: flag.Description,
+		Enabled:     flag.Enabled,
+		Rule:        flag.Rule,
+		Rollout:     flag.Rollout,
+		Variants:    flag.Variants,
+	}
+
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	flag.Key = form.Key
+	flag.Description = form.Description
+	flag.Enabled = form.Enabled
+	flag.Rule = form.Rule
+	flag.Rollout = form.Rollout
+	flag.Variants = form.Variants
+
+	if err := e.App.Save(flag); err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to save feature flag.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to save feature flag.", err)
+	}
+
+	return e.JSON(http.StatusOK, flag)
+}
@@ -111,28 +111,39 @@ func (api *fileApi) download(e *core.RequestEvent) error {
 			return e.InternalServerError("Failed to load request info", err)
 		}
 
-		token := e.Request.URL.Query().Get("token")
-		authRecord, _ := e.App.FindAuthRecordByToken(token, core.TokenTypeFile)
+		// the record share token grants access regardless of the view rule
+		if !isSharedRecordRequest(originalRequestInfo, collection, record.Id) {
+			authRecord := findFileTokenAuthRecord(e.App, e.Request.URL.Query().Get("token"), record)
 
-		// create a shallow copy of the cached request data and adjust it to the current auth record (if any)
-		requestInfo := *originalRequestInfo
-		requestInfo.Context = core.RequestInfoContextProtectedFile
-		requestInfo.Auth = authRecord
+			// create a shallow copy of the cached request data and adjust it to the current auth record (if any)
+			requestInfo := *originalRequestInfo
+			requestInfo.Context = core.RequestInfoContextProtectedFile
+			requestInfo.Auth = authRecord
 
-		if ok, _ := e.App.CanAccessRecord(record, &requestInfo, record.Collection().ViewRule); !ok {
-			return e.NotFoundError("", errors.New("insufficient permissions to access the file resource"))
+			if ok, _ := e.App.CanAccessRecord(record, &requestInfo, record.Collection().ViewRule, core.CollectionPolicyActionView); !ok {
+				return e.NotFoundError("", errors.New("insufficient permissions to access the file resource"))
+			}
 		}
 	}
 
-	baseFilesPath := record.BaseFilesPath()
+	fileRecord := record
 
 	// fetch the original view file field related record
 	if collection.IsView() {
-		fileRecord, err := e.App.FindRecordByViewFile(collection.Id, fileField.Name, filename)
+		fileRecord, err = e.App.FindRecordByViewFile(collection.Id, fileField.Name, filename)
 		if err != nil {
 			return e.NotFoundError("", fmt.Errorf("failed to fetch view file field record: %w", err))
 		}
-		baseFilesPath = fileRecord.BaseFilesPath()
+	}
+
+	baseFilesPath := fileRecord.BaseFilesPath()
+
+	// quarantined files are servable only to superusers (e.g. for moderation)
+	if !e.HasSuperuserAuth() && e.App.IsFileQuarantined(fileRecord, filename) {
+		tokenRecord, _ := e.App.FindAuthRecordByToken(e.Request.URL.Query().Get("token"), core.TokenTypeFile)
+		if tokenRecord == nil || !tokenRecord.IsSuperuser() {
+			return e.NotFoundError("", errors.New("the file is quarantined"))
+		}
 	}
 
 	fsys, err := e.App.NewFilesystem()
@@ -175,6 +186,12 @@ func (api *fileApi) download(e *core.RequestEvent) error {
 					servedPath = originalPath
 				}
 			}
+
+			if servedPath != originalPath {
+				if err := e.App.TrackThumbAccess(servedPath); err != nil {
+					e.App.Logger().Warn("Failed to track the thumb access", slog.Any("error", err), slog.String("thumb", servedPath))
+				}
+			}
 		}
 	}
 
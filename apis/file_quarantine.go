@@ -0,0 +1,61 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// bindFileQuarantineApi registers the quarantined files api endpoints.
+func bindFileQuarantineApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/files/quarantine").Bind(RequireSuperuserAuth())
+	sub.GET("", quarantinedFilesList).Bind(SkipSuccessActivityLog())
+	sub.POST("/{id}/approve", quarantinedFileApprove)
+	sub.POST("/{id}/reject", quarantinedFileReject)
+}
+
+var quarantinedFilesFilterFields = []string{
+	"id", "collectionRef", "recordRef", "fieldName", "filename", "reason", "created", "updated",
+}
+
+func quarantinedFilesList(e *core.RequestEvent) error {
+	fieldResolver := search.NewSimpleFieldResolver(quarantinedFilesFilterFields...)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(e.App.QuarantinedFileQuery()).
+		ParseAndExec(e.Request.URL.Query().Encode(), &[]*core.QuarantinedFile{})
+
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+func quarantinedFileApprove(e *core.RequestEvent) error {
+	file, err := e.App.FindQuarantinedFileById(e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.ApproveQuarantinedFile(file); err != nil {
+		return e.BadRequestError("Failed to approve the quarantined file.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+func quarantinedFileReject(e *core.RequestEvent) error {
+	file, err := e.App.FindQuarantinedFileById(e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.RejectQuarantinedFile(file); err != nil {
+		return e.BadRequestError("Failed to reject the quarantined file.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
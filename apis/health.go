@@ -26,8 +26,11 @@ func healthCheck(e *core.RequestEvent) error {
 	}
 
 	if e.HasSuperuserAuth() {
-		resp.Data = make(map[string]any, 3)
+		resp.Data = make(map[string]any, 4)
 		resp.Data["canBackup"] = !e.App.Store().Has(core.StoreKeyActiveBackup)
+		if state, err := e.App.MaintenanceState(); err == nil {
+			resp.Data["readOnly"] = state.ReadOnly
+		}
 		resp.Data["realIP"] = e.RealIP()
 
 		// loosely check if behind a reverse proxy
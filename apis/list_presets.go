@@ -0,0 +1,267 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+const listPresetQueryParam = "preset"
+
+// bindListPresetsApi registers the collection records list presets api endpoints.
+func bindListPresetsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/presets")
+	sub.GET("", listPresetsList)
+	sub.POST("", listPresetCreate).Bind(RequireAuth())
+	sub.PATCH("/{id}", listPresetUpdate).Bind(RequireAuth())
+	sub.DELETE("/{id}", listPresetDelete).Bind(RequireAuth())
+}
+
+// listPresetsList returns the shared collection list presets
+// and the ones owned by the current authenticated record.
+func listPresetsList(e *core.RequestEvent) error {
+	collection, err := listPresetsCollection(e)
+	if err != nil {
+		return err
+	}
+
+	presets, err := e.App.FindAllListPresets(collection, e.Auth)
+	if err != nil {
+		return e.InternalServerError("Failed to load the list presets.", err)
+	}
+
+	return e.JSON(http.StatusOK, presets)
+}
+
+func listPresetCreate(e *core.RequestEvent) error {
+	collection, err := listPresetsCollection(e)
+	if err != nil {
+		return err
+	}
+
+	preset := core.NewListPreset(collection)
+	preset.SetOwner(e.Auth)
+
+	return saveListPreset(e, collection, preset)
+}
+
+func listPresetUpdate(e *core.RequestEvent) error {
+	collection, err := listPresetsCollection(e)
+	if err != nil {
+		return err
+	}
+
+	preset, err := findManageableListPreset(e, collection)
+	if err != nil {
+		return err
+	}
+
+	return saveListPreset(e, collection, preset)
+}
+
+func listPresetDelete(e *core.RequestEvent) error {
+	collection, err := listPresetsCollection(e)
+	if err != nil {
+		return err
+	}
+
+	preset, err := findManageableListPreset(e, collection)
+	if err != nil {
+		return err
+	}
+
+	if err := e.App.Delete(preset); err != nil {
+		return e.BadRequestError("Failed to delete list preset.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// -------------------------------------------------------------------
+
+// listPresetsCollection returns the request path collection and
+// checks whether the current request has access to its records list.
+func listPresetsCollection(e *core.RequestEvent) (*core.Collection, error) {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return nil, e.NotFoundError("Missing collection context.", err)
+	}
+
+	if collection.ListRule == nil && !e.HasSuperuserAuth() {
+		return nil, e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	return collection, nil
+}
+
+// findManageableListPreset returns the request path preset if it is
+// owned by the current auth record (or if it is shared and the request is from a superuser).
+func findManageableListPreset(e *core.RequestEvent, collection *core.Collection) (*core.ListPreset, error) {
+	preset, err := e.App.FindListPresetById(e.Request.PathValue("id"))
+	if err != nil || preset.CollectionRef != collection.Id {
+		return nil, e.NotFoundError("", err)
+	}
+
+	if !preset.IsOwnedBy(e.Auth) && !(preset.IsShared() && e.HasSuperuserAuth()) {
+		return nil, e.NotFoundError("", nil)
+	}
+
+	return preset, nil
+}
+
+type listPresetForm struct {
+	Name   string `form:"name" json:"name"`
+	Filter string `form:"filter" json:"filter"`
+	Sort   string `form:"sort" json:"sort"`
+	Fields string `form:"fields" json:"fields"`
+	Expand string `form:"expand" json:"expand"`
+
+	// Shared indicates whether the preset should be visible to everyone
+	// (could be set only by superusers).
+	Shared bool `form:"shared" json:"shared"`
+}
+
+func saveListPreset(e *core.RequestEvent, collection *core.Collection, preset *core.ListPreset) error {
+	form := &listPresetForm{
+		Name:   preset.Name,
+		Filter: preset.Filter,
+		Sort:   preset.Sort,
+		Fields: preset.Fields,
+		Expand: preset.Expand,
+		Shared: !preset.IsNew() && preset.IsShared(),
+	}
+
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if form.Shared && !e.HasSuperuserAuth() {
+		return e.ForbiddenError("Only superusers can manage shared list presets.", nil)
+	}
+
+	preset.Name = form.Name
+	preset.Filter = form.Filter
+	preset.Sort = form.Sort
+	preset.Fields = form.Fields
+	preset.Expand = form.Expand
+
+	if form.Shared {
+		preset.SetOwner(nil)
+	} else {
+		preset.SetOwner(e.Auth)
+	}
+
+	if err := validateListPresetQuery(e, collection, preset); err != nil {
+		return err
+	}
+
+	if err := e.App.Save(preset); err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to save list preset.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to save list preset.", err)
+	}
+
+	return e.JSON(http.StatusOK, preset)
+}
+
+// validateListPresetQuery checks whether the preset filter and sort
+// expressions could be resolved by the current request auth.
+func validateListPresetQuery(e *core.RequestEvent, collection *core.Collection, preset *core.ListPreset) error {
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	err = checkForSuperuserOnlyRuleFields(&core.RequestInfo{
+		Auth: requestInfo.Auth,
+		Query: map[string]string{
+			search.FilterQueryParam: preset.Filter,
+			search.SortQueryParam:   preset.Sort,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+	resolver.SetAllowHiddenFields(requestInfo.HasSuperuserAuth())
+
+	errs := validation.Errors{}
+
+	if preset.Filter != "" {
+		if _, err := search.FilterData(preset.Filter).BuildExpr(resolver); err != nil {
+			errs["filter"] = validation.NewError("validation_invalid_filter", "Invalid filter expression.")
+		}
+	}
+
+	if preset.Sort != "" {
+		for _, sortField := range search.ParseSortFromString(preset.Sort) {
+			if _, err := sortField.BuildExpr(resolver); err != nil {
+				errs["sort"] = validation.NewError("validation_invalid_sort", "Invalid sort expression.")
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return e.BadRequestError("Failed to save list preset.", errs)
+	}
+
+	return nil
+}
+
+// applyListPreset loads the "preset" query parameter list preset (if any)
+// into the current request query parameters.
+//
+// The preset filter is combined with the request one using AND,
+// while its sort, fields and expand values are used only if the request doesn't specify its own.
+func applyListPreset(e *core.RequestEvent, collection *core.Collection, requestInfo *core.RequestInfo) error {
+	query := e.Request.URL.Query()
+
+	name := query.Get(listPresetQueryParam)
+	if name == "" {
+		return nil
+	}
+
+	preset, err := e.App.FindListPresetByName(collection, e.Auth, name)
+	if err != nil {
+		return e.BadRequestError("Missing or invalid list preset.", err)
+	}
+
+	if preset.Filter != "" {
+		if filter := query.Get(search.FilterQueryParam); filter != "" {
+			query.Set(search.FilterQueryParam, "("+preset.Filter+") && ("+filter+")")
+		} else {
+			query.Set(search.FilterQueryParam, preset.Filter)
+		}
+	}
+
+	defaults := map[string]string{
+		search.SortQueryParam: preset.Sort,
+		fieldsQueryParam:      preset.Fields,
+		expandQueryParam:      preset.Expand,
+	}
+	for param, value := range defaults {
+		if value != "" && query.Get(param) == "" {
+			query.Set(param, value)
+		}
+	}
+
+	e.Request.URL.RawQuery = query.Encode()
+
+	// sync the cached request info
+	for _, param := range []string{search.FilterQueryParam, search.SortQueryParam, fieldsQueryParam, expandQueryParam} {
+		if v := query.Get(param); v != "" {
+			requestInfo.Query[param] = v
+		}
+	}
+
+	return nil
+}
@@ -15,6 +15,8 @@ func bindLogsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
 	sub.GET("", logsList)
 	sub.GET("/stats", logsStats)
 	sub.GET("/{id}", logsView)
+
+	bindSuperuserActionsLog(app)
 }
 
 var logFilterFields = []string{
@@ -0,0 +1,100 @@
+package apis
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/spf13/cast"
+)
+
+// bindLogsReplayApi registers the captured requests replay api endpoint.
+//
+// The replayed requests are executed against the provided (fully loaded) router.
+func bindLogsReplayApi(app core.App, rg *router.RouterGroup[*core.RequestEvent], r *router.Router[*core.RequestEvent]) {
+	sub := rg.Group("/logs").Bind(RequireSuperuserAuth())
+	sub.POST("/{id}/replay", func(e *core.RequestEvent) error {
+		return logsReplay(e, r)
+	})
+}
+
+type logsReplayForm struct {
+	// WithAuth indicates whether to replay the request
+	// as the original auth record (if it still exists).
+	WithAuth bool `form:"withAuth" json:"withAuth"`
+}
+
+func logsReplay(e *core.RequestEvent, r *router.Router[*core.RequestEvent]) error {
+	log, err := e.App.FindLogById(e.Request.PathValue("id"))
+	if err != nil || log == nil || cast.ToString(log.Data["type"]) != "requestCapture" {
+		return e.NotFoundError("Missing or invalid request capture log.", err)
+	}
+
+	form := new(logsReplayForm)
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if cast.ToBool(log.Data["requestBodyTruncated"]) {
+		return e.BadRequestError("The captured request body was truncated or omitted and cannot be replayed.", nil)
+	}
+
+	req, err := http.NewRequestWithContext(
+		context.WithValue(e.Request.Context(), requestCaptureReplayKey{}, log.Id),
+		cast.ToString(log.Data["method"]),
+		cast.ToString(log.Data["url"]),
+		strings.NewReader(cast.ToString(log.Data["requestBody"])),
+	)
+	if err != nil {
+		return e.BadRequestError("Failed to construct the replay request.", err)
+	}
+	req.Host = e.Request.Host
+	req.RemoteAddr = e.Request.RemoteAddr
+
+	for name, value := range cast.ToStringMapString(log.Data["requestHeaders"]) {
+		if value == requestCaptureScrubbed || strings.EqualFold(name, "Content-Length") {
+			continue
+		}
+		req.Header.Set(name, value)
+	}
+
+	if authId := cast.ToString(log.Data["authId"]); form.WithAuth && authId != "" {
+		authRecord, err := e.App.FindRecordById(cast.ToString(log.Data["auth"]), authId)
+		if err != nil {
+			return e.BadRequestError("The original auth record is missing.", err)
+		}
+
+		token, err := authRecord.NewAuthToken()
+		if err != nil {
+			return e.InternalServerError("Failed to generate the replay auth token.", err)
+		}
+
+		req.Header.Set("Authorization", token)
+	}
+
+	mux, err := r.BuildMux()
+	if err != nil {
+		return e.InternalServerError("Failed to build the replay router.", err)
+	}
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	result := rec.Result()
+	defer result.Body.Close()
+
+	var body any = rec.Body.String()
+	if json.Valid(rec.Body.Bytes()) {
+		body = json.RawMessage(rec.Body.Bytes())
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"status":  result.StatusCode,
+		"headers": scrubCapturedHeaders(result.Header, e.App.Settings().RequestCapture.ScrubFields),
+		"body":    body,
+	})
+}
@@ -0,0 +1,304 @@
+package apis
+
+import (
+	"encoding/json"
+	"log/slog"
+	"reflect"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+const superuserActionsLogHookId = "__pbSuperuserActionsLog__"
+
+// bindSuperuserActionsLog registers the app hooks that write the superuser
+// collections, settings and records API mutations as "superuserAction" logs
+// (if enabled with the Logs.LogSuperuserActions setting).
+func bindSuperuserActionsLog(app core.App) {
+	// settings
+	// ---------------------------------------------------------------
+	app.OnSettingsUpdateRequest().Bind(&hook.Handler[*core.SettingsUpdateRequestEvent]{
+		Id: superuserActionsLogHookId,
+		Func: func(e *core.SettingsUpdateRequestEvent) error {
+			if !superuserActionsLogEnabled(e.RequestEvent) {
+				return e.Next()
+			}
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			logSuperuserAction(
+				e.RequestEvent,
+				"settingsUpdate",
+				slog.Any("diff", superuserActionDiff(superuserActionData(e.OldSettings), superuserActionData(e.NewSettings))),
+			)
+
+			return nil
+		},
+	})
+
+	// collections
+	// ---------------------------------------------------------------
+	app.OnCollectionCreateRequest().Bind(&hook.Handler[*core.CollectionRequestEvent]{
+		Id: superuserActionsLogHookId,
+		Func: func(e *core.CollectionRequestEvent) error {
+			if !superuserActionsLogEnabled(e.RequestEvent) {
+				return e.Next()
+			}
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			logSuperuserCollectionAction(e, "collectionCreate", nil, superuserActionData(e.Collection))
+
+			return nil
+		},
+	})
+
+	app.OnCollectionUpdateRequest().Bind(&hook.Handler[*core.CollectionRequestEvent]{
+		Id: superuserActionsLogHookId,
+		Func: func(e *core.CollectionRequestEvent) error {
+			if !superuserActionsLogEnabled(e.RequestEvent) {
+				return e.Next()
+			}
+
+			// load the current db state since e.Collection has already the submitted changes
+			var oldData map[string]any
+			if old, err := e.App.FindCollectionByNameOrId(e.Collection.Id); err == nil {
+				oldData = superuserActionData(old)
+			}
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			logSuperuserCollectionAction(e, "collectionUpdate", oldData, superuserActionData(e.Collection))
+
+			return nil
+		},
+	})
+
+	app.OnCollectionDeleteRequest().Bind(&hook.Handler[*core.CollectionRequestEvent]{
+		Id: superuserActionsLogHookId,
+		Func: func(e *core.CollectionRequestEvent) error {
+			if !superuserActionsLogEnabled(e.RequestEvent) {
+				return e.Next()
+			}
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			logSuperuserCollectionAction(e, "collectionDelete", superuserActionData(e.Collection), nil)
+
+			return nil
+		},
+	})
+
+	app.OnCollectionsImportRequest().Bind(&hook.Handler[*core.CollectionsImportRequestEvent]{
+		Id: superuserActionsLogHookId,
+		Func: func(e *core.CollectionsImportRequestEvent) error {
+			if !superuserActionsLogEnabled(e.RequestEvent) {
+				return e.Next()
+			}
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(e.CollectionsData))
+			for _, data := range e.CollectionsData {
+				if name, ok := data["name"].(string); ok {
+					names = append(names, name)
+				}
+			}
+
+			logSuperuserAction(
+				e.RequestEvent,
+				"collectionsImport",
+				slog.Any("collections", names),
+				slog.Bool("deleteMissing", e.DeleteMissing),
+			)
+
+			return nil
+		},
+	})
+
+	// records
+	// ---------------------------------------------------------------
+	app.OnRecordCreateRequest().Bind(&hook.Handler[*core.RecordRequestEvent]{
+		Id: superuserActionsLogHookId,
+		Func: func(e *core.RecordRequestEvent) error {
+			if !superuserActionsLogEnabled(e.RequestEvent) {
+				return e.Next()
+			}
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			logSuperuserRecordAction(e, "recordCreate", nil, superuserActionRecordData(e.Record))
+
+			return nil
+		},
+	})
+
+	app.OnRecordUpdateRequest().Bind(&hook.Handler[*core.RecordRequestEvent]{
+		Id: superuserActionsLogHookId,
+		Func: func(e *core.RecordRequestEvent) error {
+			if !superuserActionsLogEnabled(e.RequestEvent) {
+				return e.Next()
+			}
+
+			oldData := superuserActionRecordData(e.Record.Original())
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			logSuperuserRecordAction(e, "recordUpdate", oldData, superuserActionRecordData(e.Record))
+
+			return nil
+		},
+	})
+
+	app.OnRecordDeleteRequest().Bind(&hook.Handler[*core.RecordRequestEvent]{
+		Id: superuserActionsLogHookId,
+		Func: func(e *core.RecordRequestEvent) error {
+			if !superuserActionsLogEnabled(e.RequestEvent) {
+				return e.Next()
+			}
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			logSuperuserRecordAction(e, "recordDelete", superuserActionRecordData(e.Record), nil)
+
+			return nil
+		},
+	})
+}
+
+// superuserActionsLogEnabled reports whether the request event
+// is a superuser one that should be logged.
+func superuserActionsLogEnabled(e *core.RequestEvent) bool {
+	if !e.HasSuperuserAuth() {
+		return false
+	}
+
+	logs := e.App.Settings().Logs
+
+	// no logs retention
+	if logs.MaxDays == 0 {
+		return false
+	}
+
+	return logs.LogSuperuserActions
+}
+
+// logSuperuserAction writes the specified superuser action as "superuserAction" info log.
+//
+// It is a no-op if the superuser actions logging is disabled.
+func logSuperuserAction(e *core.RequestEvent, action string, attrs ...any) {
+	if !superuserActionsLogEnabled(e) {
+		return
+	}
+
+	attrs = append(
+		attrs,
+		slog.String("type", "superuserAction"),
+		slog.String("action", action),
+		slog.String("actorId", e.Auth.Id),
+		slog.String("actorEmail", e.Auth.Email()),
+		slog.String("url", cutStr(e.Request.URL.RequestURI(), 3000)),
+		slog.String("userAgent", cutStr(e.Request.UserAgent(), 2000)),
+	)
+
+	if e.App.Settings().Logs.LogIP {
+		attrs = append(
+			attrs,
+			slog.String("userIP", e.RealIP()),
+			slog.String("remoteIP", e.RemoteIP()),
+		)
+	}
+
+	e.App.Logger().Info("Superuser "+action, attrs...)
+}
+
+func logSuperuserCollectionAction(e *core.CollectionRequestEvent, action string, oldData, newData map[string]any) {
+	logSuperuserAction(
+		e.RequestEvent,
+		action,
+		slog.String("collectionId", e.Collection.Id),
+		slog.String("collectionName", e.Collection.Name),
+		slog.Any("diff", superuserActionDiff(oldData, newData)),
+	)
+}
+
+func logSuperuserRecordAction(e *core.RecordRequestEvent, action string, oldData, newData map[string]any) {
+	logSuperuserAction(
+		e.RequestEvent,
+		action,
+		slog.String("collectionId", e.Collection.Id),
+		slog.String("collectionName", e.Collection.Name),
+		slog.String("recordId", e.Record.Id),
+		slog.Any("diff", superuserActionDiff(oldData, newData)),
+	)
+}
+
+// superuserActionData normalizes v into a generic json map
+// (the models secrets are expected to be hidden by their MarshalJSON).
+func superuserActionData(v any) map[string]any {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+
+	data := map[string]any{}
+	if err := json.Unmarshal(raw, &data); err != nil {
+		return nil
+	}
+
+	return data
+}
+
+// superuserActionRecordData returns the normalized record fields
+// data excluding the password hash and the auth token key.
+func superuserActionRecordData(record *core.Record) map[string]any {
+	data := record.FieldsData()
+
+	for _, f := range record.Collection().Fields {
+		if f.Type() == core.FieldTypePassword {
+			delete(data, f.GetName())
+		}
+	}
+
+	if record.Collection().IsAuth() {
+		delete(data, core.FieldNameTokenKey)
+	}
+
+	return superuserActionData(data)
+}
+
+// superuserActionDiff returns a map with the changed oldData and newData
+// keys in the format {"key": {"old": oldValue, "new": newValue}}.
+func superuserActionDiff(oldData, newData map[string]any) map[string]any {
+	diff := map[string]any{}
+
+	for k, v := range newData {
+		if old, ok := oldData[k]; !ok || !reflect.DeepEqual(old, v) {
+			diff[k] = map[string]any{"old": oldData[k], "new": v}
+		}
+	}
+
+	for k, v := range oldData {
+		if _, ok := newData[k]; !ok {
+			diff[k] = map[string]any{"old": v, "new": nil}
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,47 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindMaintenanceApi registers the app maintenance api endpoints.
+func bindMaintenanceApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	subGroup := rg.Group("/maintenance").Bind(RequireSuperuserAuth())
+	subGroup.GET("", maintenanceView)
+	subGroup.PATCH("", maintenanceUpdate)
+}
+
+func maintenanceView(e *core.RequestEvent) error {
+	state, err := e.App.MaintenanceState()
+	if err != nil {
+		return e.InternalServerError("Failed to load the app maintenance state.", err)
+	}
+
+	return e.JSON(http.StatusOK, state)
+}
+
+func maintenanceUpdate(e *core.RequestEvent) error {
+	state, err := e.App.MaintenanceState()
+	if err != nil {
+		return e.InternalServerError("Failed to load the app maintenance state.", err)
+	}
+
+	if err := e.BindBody(state); err != nil {
+		return e.BadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := e.App.SaveMaintenanceState(state); err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while saving the maintenance state.", err))
+	}
+
+	e.App.Logger().Info(
+		"Updated app maintenance state",
+		"readOnly", state.ReadOnly,
+		"actorId", e.Auth.Id,
+	)
+
+	return e.JSON(http.StatusOK, state)
+}
@@ -11,11 +11,14 @@ import (
 	"strings"
 	"time"
 
+	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/router"
 	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cast"
 )
 
@@ -193,11 +196,27 @@ func loadAuthToken() *hook.Handler[*core.RequestEvent] {
 				return e.Next()
 			}
 
-			record, err := e.App.FindAuthRecordByToken(token, core.TokenTypeAuth)
+			record, err := e.App.FindAuthRecordByToken(token, core.TokenTypeAuth, core.TokenTypeScoped)
 			if err != nil {
 				e.App.Logger().Debug("loadAuthToken failure", "error", err)
 			} else if record != nil {
+				// scoped tokens are allowed only for their specific resources
+				if isScopedToken(token) {
+					if err := checkScopedToken(e, token); err != nil {
+						return err
+					}
+				}
+
 				e.Auth = record
+
+				// the token was already verified
+				if authTime := authTimeFromToken(token); !authTime.IsZero() {
+					e.Set(core.RequestEventKeyAuthTime, authTime)
+				}
+
+				if err := checkProfileCompletion(e, token); err != nil {
+					return err
+				}
 			}
 
 			return e.Next()
@@ -205,6 +224,63 @@ func loadAuthToken() *hook.Handler[*core.RequestEvent] {
 	}
 }
 
+// authTimeFromToken extracts the original authentication time from
+// the provided auth token (zero DateTime if the claim is missing).
+func authTimeFromToken(token string) types.DateTime {
+	claims, _ := security.ParseUnverifiedJWT(token)
+
+	unix := cast.ToInt64(claims[core.TokenClaimAuthTime])
+	if unix <= 0 {
+		return types.DateTime{}
+	}
+
+	authTime, _ := types.ParseDateTime(time.Unix(unix, 0))
+
+	return authTime
+}
+
+// checkProfileCompletion restricts the API access of the auth tokens
+// flagged with incomplete profile to only the profile completion routes
+// (the own record view/update and auth refresh) until the required profile fields are filled.
+func checkProfileCompletion(e *core.RequestEvent, token string) error {
+	claims, _ := security.ParseUnverifiedJWT(token)
+	if !cast.ToBool(claims[core.TokenClaimProfileIncomplete]) {
+		return nil
+	}
+
+	// the record could have been completed after the token was issued
+	missing := e.Auth.MissingProfileFields()
+	if len(missing) == 0 || isProfileCompletionRoute(e) {
+		return nil
+	}
+
+	errs := validation.Errors{}
+	for _, name := range missing {
+		errs[name] = validation.NewError("validation_profile_incomplete", "Missing required profile field.")
+	}
+
+	return e.ForbiddenError("The auth record profile must be completed first.", errs)
+}
+
+func isProfileCompletionRoute(e *core.RequestEvent) bool {
+	parts := strings.Split(strings.Trim(e.Request.URL.Path, "/"), "/")
+
+	// /api/collections/{collection}/...
+	if len(parts) < 4 || parts[0] != "api" || parts[1] != "collections" ||
+		(parts[2] != e.Auth.Collection().Id && parts[2] != e.Auth.Collection().Name) {
+		return false
+	}
+
+	switch {
+	case len(parts) == 4 && parts[3] == "auth-refresh":
+		return e.Request.Method == http.MethodPost
+	case len(parts) == 5 && parts[3] == "records" && parts[4] == e.Auth.Id:
+		return e.Request.Method == http.MethodGet || e.Request.Method == http.MethodPatch
+	}
+
+	return false
+}
+
 func getAuthTokenFromRequest(e *core.RequestEvent) string {
 	token := e.Request.Header.Get("Authorization")
 	if token != "" {
@@ -418,6 +494,15 @@ func logRequest(event *core.RequestEvent, err error) {
 		)
 	}
 
+	if geo := event.App.ResolveGeoIP(event.RealIP()); geo != nil {
+		attrs = append(
+			attrs,
+			slog.String("country", geo.Country),
+			slog.Int("asn", geo.ASN),
+			slog.String("asnOrg", geo.ASNOrg),
+		)
+	}
+
 	// don't block on logs write
 	routine.FireAndForget(func() {
 		message := method + " "
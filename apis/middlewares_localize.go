@@ -0,0 +1,102 @@
+package apis
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/i18n"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+const (
+	DefaultLocalizeErrorsMiddlewareId = "pbLocalizeErrors"
+
+	// before the activity logger so that the errors are logged with their original messages
+	DefaultLocalizeErrorsMiddlewarePriority = DefaultActivityLoggerMiddlewarePriority - 10
+)
+
+// localizeErrors middleware translates the returned API error message
+// and its validation error items based on the request locale
+// (see [core.RequestEvent.Locale]) and the app I18n() catalog.
+//
+// The error items are looked up first by their response message text (this allows
+// translating custom messages) and then by their error code.
+// The top-level error message is looked up only by its text.
+//
+// This middleware is registered by default for all routes.
+func localizeErrors() *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Id:       DefaultLocalizeErrorsMiddlewareId,
+		Priority: DefaultLocalizeErrorsMiddlewarePriority,
+		Func: func(e *core.RequestEvent) error {
+			err := e.Next()
+			if err == nil {
+				return nil
+			}
+
+			locale := e.Locale()
+			if locale == "" {
+				return err
+			}
+
+			return localizeApiError(e.App.I18n(), locale, router.ToApiError(err))
+		},
+	}
+}
+
+// localizeApiError returns a new translated copy of the provided ApiError
+// (the original error is left unchanged since it could be a shared instance).
+func localizeApiError(catalog *i18n.Catalog, locale string, apiErr *router.ApiError) *router.ApiError {
+	message := apiErr.Message
+	if translated, ok := catalog.Get(locale, message); ok {
+		message = translated
+	}
+
+	result := router.NewApiError(apiErr.Status, "", apiErr.RawData())
+	result.Message = message
+	result.Data = localizeErrorsData(catalog, locale, apiErr.Data)
+
+	return result
+}
+
+func localizeErrorsData(catalog *i18n.Catalog, locale string, data map[string]any) map[string]any {
+	result := make(map[string]any, len(data))
+
+	for k, v := range data {
+		item, ok := v.(map[string]any)
+		if !ok {
+			result[k] = v
+			continue
+		}
+
+		code, isCodeStr := item["code"].(string)
+		message, isMessageStr := item["message"].(string)
+		if !isCodeStr || !isMessageStr {
+			// nested errors
+			result[k] = localizeErrorsData(catalog, locale, item)
+			continue
+		}
+
+		translated, ok := catalog.Get(locale, message)
+		if !ok {
+			translated, ok = catalog.Get(locale, code)
+		}
+		if !ok {
+			result[k] = item
+			continue
+		}
+
+		localized := make(map[string]any, len(item))
+		for ik, iv := range item {
+			localized[ik] = iv
+		}
+
+		// render the translated message template placeholders (if any)
+		params, _ := item["params"].(map[string]any)
+		localized["message"] = validation.NewError(code, translated).SetParams(params).Error()
+
+		result[k] = localized
+	}
+
+	return result
+}
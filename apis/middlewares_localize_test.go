@@ -0,0 +1,118 @@
+package apis_test
+
+import (
+	"net/http"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestLocalizeErrorsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	beforeTestFunc := func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+		app.I18n().Load("cs", map[string]string{
+			"Failed to test.":                "Test selhal.",
+			"validation_required":            "Nesmí být prázdné.",
+			"validation_length_out_of_range": "Délka musí být mezi {{.min}} a {{.max}}.",
+			"Custom message.":                "vlastní zpráva",
+		})
+
+		e.Router.GET("/test", func(e *core.RequestEvent) error {
+			return e.BadRequestError("Failed to test.", validation.Errors{
+				"a": validation.ErrRequired,
+				"b": validation.ErrLengthOutOfRange.SetParams(map[string]any{"min": 1, "max": 3}),
+				"c": validation.NewError("validation_custom", "custom message"),
+				"d": validation.NewError("validation_untranslated", "untranslated"),
+			})
+		})
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "without Accept-Language and default locale",
+			Method:         http.MethodGet,
+			URL:            "/test",
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"message":"Failed to test."`,
+				`"a":{"code":"validation_required","message":"Cannot be blank."}`,
+				`"c":{"code":"validation_custom","message":"Custom message."}`,
+				`"d":{"code":"validation_untranslated","message":"Untranslated."}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "with unsupported Accept-Language",
+			Method: http.MethodGet,
+			URL:    "/test",
+			Headers: map[string]string{
+				"Accept-Language": "de,fr;q=0.5",
+			},
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"message":"Failed to test."`,
+				`"a":{"code":"validation_required","message":"Cannot be blank."}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "with supported Accept-Language",
+			Method: http.MethodGet,
+			URL:    "/test",
+			Headers: map[string]string{
+				"Accept-Language": "de,cs-CZ;q=0.5",
+			},
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"message":"Test selhal."`,
+				`"a":{"code":"validation_required","message":"Nesmí být prázdné."}`,
+				`"message":"Délka musí být mezi 1 a 3."`,
+				`"c":{"code":"validation_custom","message":"vlastní zpráva"}`,
+				`"d":{"code":"validation_untranslated","message":"Untranslated."}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "with default settings locale fallback",
+			Method: http.MethodGet,
+			URL:    "/test",
+			Headers: map[string]string{
+				"Accept-Language": "de",
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				beforeTestFunc(t, app, e)
+				app.Settings().Meta.Locale = "cs"
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"message":"Test selhal."`,
+				`"a":{"code":"validation_required","message":"Nesmí být prázdné."}`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "non-error response",
+			Method: http.MethodGet,
+			URL:    "/api/health",
+			Headers: map[string]string{
+				"Accept-Language": "cs",
+			},
+			BeforeTestFunc: beforeTestFunc,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"code":200`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
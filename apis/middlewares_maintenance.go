@@ -0,0 +1,77 @@
+package apis
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+const (
+	DefaultMaintenanceMiddlewareId       = "pbMaintenance"
+	DefaultMaintenanceMiddlewarePriority = DefaultLoadAuthTokenMiddlewarePriority + 1 // after the auth token load so that superusers could be allowlisted
+)
+
+// maintenance defines the global read-only mode middleware.
+//
+// When the app read-only mode is enabled, it rejects with 503 all
+// mutation requests except those of authenticated superusers
+// (and the superusers auth requests so that they can still log in).
+//
+// This middleware is registered by default for all routes.
+func maintenance() *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Id:       DefaultMaintenanceMiddlewareId,
+		Priority: DefaultMaintenanceMiddlewarePriority,
+		Func: func(e *core.RequestEvent) error {
+			if isSafeMethod(e.Request.Method) || e.HasSuperuserAuth() || isSuperuserAuthRequest(e.Request) {
+				return e.Next()
+			}
+
+			state, err := e.App.MaintenanceState()
+			if err != nil {
+				return e.InternalServerError("Failed to load the app maintenance state.", err)
+			}
+
+			if !state.ReadOnly {
+				return e.Next()
+			}
+
+			message := state.Message
+			if message == "" {
+				message = "The application is in read-only maintenance mode. Please try again later."
+			}
+
+			e.Response.Header().Set("Retry-After", strconv.Itoa(state.RetryAfterValue()))
+
+			return router.NewApiError(http.StatusServiceUnavailable, message, nil)
+		},
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
+
+// isSuperuserAuthRequest checks whether the request is a superusers
+// collection auth related request (e.g. auth-with-password, auth-refresh).
+func isSuperuserAuthRequest(r *http.Request) bool {
+	for _, prefix := range []string{
+		"/api/collections/" + core.CollectionNameSuperusers + "/auth-",
+		"/api/collections/" + core.CollectionNameSuperusers + "/request-otp",
+	} {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,96 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+const (
+	DefaultMeteringMiddlewareId       = "pbMetering"
+	DefaultMeteringMiddlewarePriority = DefaultLoadAuthTokenMiddlewarePriority + 3 // after the auth token load so that the tenant could be resolved
+)
+
+// metering defines the usage metering middleware that tracks
+// the requests, uploaded storage and egress units of the
+// authenticated non-superuser request tenants (see [core.App.TrackUsage]).
+//
+// This middleware is registered by default for all routes.
+func metering() *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Id:       DefaultMeteringMiddlewareId,
+		Priority: DefaultMeteringMiddlewarePriority,
+		Func: func(e *core.RequestEvent) error {
+			if !e.App.Settings().Metering.Enabled {
+				return e.Next()
+			}
+
+			rw := &meteredResponseWriter{ResponseWriter: e.Response}
+			e.Response = rw
+
+			err := e.Next()
+
+			e.Response = rw.ResponseWriter
+
+			// the auth state is checked after the request execution
+			// in order to include also the auth-with-* requests
+			if e.Auth == nil || e.Auth.IsSuperuser() {
+				return err
+			}
+
+			tenant := core.UsageTenant(e.Auth)
+
+			units := map[string]int64{
+				core.UsageUnitRequests: 1,
+				core.UsageUnitEgress:   rw.size,
+			}
+
+			if err == nil && e.Request.MultipartForm != nil {
+				for _, files := range e.Request.MultipartForm.File {
+					for _, f := range files {
+						units[core.UsageUnitStorage] += f.Size
+					}
+				}
+			}
+
+			for unit, value := range units {
+				if value == 0 {
+					continue
+				}
+
+				if trackErr := e.App.TrackUsage(tenant, unit, value); trackErr != nil {
+					e.App.Logger().Warn("Failed to track the request usage", "unit", unit, "error", trackErr)
+				}
+			}
+
+			return err
+		},
+	}
+}
+
+// -------------------------------------------------------------------
+
+var _ router.RWUnwrapper = (*meteredResponseWriter)(nil)
+
+// meteredResponseWriter is a [http.ResponseWriter] wrapper
+// that counts the written response body bytes.
+type meteredResponseWriter struct {
+	http.ResponseWriter
+
+	size int64
+}
+
+func (w *meteredResponseWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+
+	w.size += int64(n)
+
+	return n, err
+}
+
+// Unwrap returns the underlying ResponseWritter instance (usually used by [http.ResponseController]).
+func (w *meteredResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
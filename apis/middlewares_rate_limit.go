@@ -2,6 +2,7 @@ package apis
 
 import (
 	"errors"
+	"strconv"
 	"sync"
 	"time"
 
@@ -21,6 +22,15 @@ const (
 	rateLimitersSettingsHookId = "__pbRateLimitersSettingsHook__"
 )
 
+// Standard rate limit feedback response headers.
+//
+// See https://datatracker.ietf.org/doc/draft-ietf-httpapi-ratelimit-headers/
+const (
+	RateLimitLimitHeader     = "RateLimit-Limit"
+	RateLimitRemainingHeader = "RateLimit-Remaining"
+	RateLimitResetHeader     = "RateLimit-Reset"
+)
+
 // rateLimit defines the global rate limit middleware.
 //
 // This middleware is registered by default for all routes.
@@ -38,7 +48,7 @@ func rateLimit() *hook.Handler[*core.RequestEvent] {
 				defaultRateLimitAudience(e)...,
 			)
 			if ok {
-				err := checkRateLimit(e, rule.Label+rule.Audience, rule)
+				err := checkRateLimitWithHeaders(e, rule.Label+rule.Audience, rule)
 				if err != nil {
 					return err
 				}
@@ -100,7 +110,7 @@ func checkCollectionRateLimit(e *core.RequestEvent, collection *core.Collection,
 
 	rule, ok := e.App.Settings().RateLimits.FindRateLimitRule(labels, defaultRateLimitAudience(e)...)
 	if ok {
-		return checkRateLimit(e, rtId+rule.Audience, rule)
+		return checkRateLimitWithHeaders(e, rtId+rule.Audience, rule)
 	}
 
 	return nil
@@ -132,16 +142,52 @@ func isClientRateLimited(e *core.RequestEvent, rtId string) bool {
 
 // @todo consider exporting as helper?
 func checkRateLimit(e *core.RequestEvent, rtId string, rule core.RateLimitRule) error {
+	_, err := consumeRateLimit(e, rtId, rule)
+
+	return err
+}
+
+// checkRateLimitWithHeaders is similar to [checkRateLimit] but additionally
+// sets the standard RateLimit-* response headers (and Retry-After on 429)
+// so that the clients could self-throttle.
+func checkRateLimitWithHeaders(e *core.RequestEvent, rtId string, rule core.RateLimitRule) error {
+	status, err := consumeRateLimit(e, rtId, rule)
+
+	if status != nil {
+		header := e.Response.Header()
+		header.Set(RateLimitLimitHeader, strconv.Itoa(status.Limit))
+		header.Set(RateLimitRemainingHeader, strconv.Itoa(status.Remaining))
+		header.Set(RateLimitResetHeader, strconv.FormatInt(status.Reset, 10))
+		if err != nil {
+			header.Set("Retry-After", strconv.FormatInt(max(status.Reset, 1), 10))
+		}
+	}
+
+	return err
+}
+
+// rateLimitStatus represents the client state of a single rate limiter.
+type rateLimitStatus struct {
+	Limit     int   `json:"limit"`
+	Remaining int   `json:"remaining"`
+	Reset     int64 `json:"reset"` // seconds until the allowance resets
+}
+
+// consumeRateLimit consumes a single rate limit token for the current
+// request client and returns its updated state.
+//
+// Returns nil status if the rule is not applicable for the current request.
+func consumeRateLimit(e *core.RequestEvent, rtId string, rule core.RateLimitRule) (*rateLimitStatus, error) {
 	switch rule.Audience {
 	case core.RateLimitRuleAudienceAll:
 		// valid for both guest and regular users
 	case core.RateLimitRuleAudienceGuest:
 		if e.Auth != nil {
-			return nil
+			return nil, nil
 		}
 	case core.RateLimitRuleAudienceAuth:
 		if e.Auth == nil {
-			return nil
+			return nil, nil
 		}
 	}
 
@@ -150,28 +196,75 @@ func checkRateLimit(e *core.RequestEvent, rtId string, rule core.RateLimitRule)
 	}).(*store.Store[string, *rateLimiter])
 	if rateLimiters == nil {
 		e.App.Logger().Warn("Failed to retrieve app rate limiters store")
-		return nil
+		return nil, nil
 	}
 
 	rt := rateLimiters.GetOrSet(rtId, func() *rateLimiter {
-		return newRateLimiter(rule.MaxRequests, rule.Duration, rule.Duration+1800)
+		limiter := newRateLimiter(rule.MaxRequests, rule.Duration, rule.Duration+1800)
+		limiter.rule = rule
+		return limiter
 	})
 	if rt == nil {
 		e.App.Logger().Warn("Failed to retrieve app rate limiter", "id", rtId)
-		return nil
+		return nil, nil
 	}
 
 	key := e.RealIP()
 	if key == "" {
 		e.App.Logger().Warn("Empty rate limit client key")
-		return nil
+		return nil, nil
 	}
 
-	if !rt.isAllowed(key) {
-		return e.TooManyRequestsError("", errors.New("triggered rate limit rule: "+rule.String()))
+	allowed, remaining, reset := rt.consume(key)
+
+	status := &rateLimitStatus{
+		Limit:     rt.maxAllowed,
+		Remaining: remaining,
+		Reset:     reset,
 	}
 
-	return nil
+	if !allowed {
+		return status, e.TooManyRequestsError("", errors.New("triggered rate limit rule: "+rule.String()))
+	}
+
+	return status, nil
+}
+
+// findRateLimitStatus returns the current (most restrictive) client state
+// of the rate limiters created for the specified rule without consuming it.
+//
+// Returns the rule's full allowance if the client hasn't consumed it yet.
+func findRateLimitStatus(e *core.RequestEvent, rule core.RateLimitRule) rateLimitStatus {
+	status := rateLimitStatus{Limit: rule.MaxRequests, Remaining: rule.MaxRequests}
+
+	rateLimiters, ok := e.App.Store().Get(rateLimitersStoreKey).(*store.Store[string, *rateLimiter])
+	if !ok || rateLimiters == nil {
+		return status
+	}
+
+	key := e.RealIP()
+	nowUnix := time.Now().Unix()
+
+	for _, rt := range rateLimiters.GetAll() {
+		if rt.rule != rule {
+			continue
+		}
+
+		client, ok := rt.getClient(key)
+		if !ok || client == nil {
+			continue
+		}
+
+		remaining, reset := client.state(nowUnix)
+		if remaining < status.Remaining {
+			status.Remaining = remaining
+		}
+		if reset > status.Reset {
+			status.Reset = reset
+		}
+	}
+
+	return status
 }
 
 func skipRateLimit(e *core.RequestEvent) bool {
@@ -241,6 +334,9 @@ func newRateLimiter(maxAllowed int, intervalInSec int64, minDeleteIntervalInSec
 type rateLimiter struct {
 	clients map[string]*fixedWindow
 
+	// rule is the settings rule that the limiter was created for (if any)
+	rule core.RateLimitRule
+
 	maxAllowed        int
 	interval          int64
 	minDeleteInterval int64
@@ -249,7 +345,6 @@ type rateLimiter struct {
 	sync.RWMutex
 }
 
-//nolint:unused
 func (rt *rateLimiter) getClient(key string) (*fixedWindow, bool) {
 	rt.RLock()
 	client, ok := rt.clients[key]
@@ -258,7 +353,9 @@ func (rt *rateLimiter) getClient(key string) (*fixedWindow, bool) {
 	return client, ok
 }
 
-func (rt *rateLimiter) isAllowed(key string) bool {
+// consume consumes a single client allowance token and returns whether
+// it was allowed, the remaining client allowance and the seconds until its reset.
+func (rt *rateLimiter) consume(key string) (bool, int, int64) {
 	// lock only reads to minimize locks contention
 	rt.RLock()
 	client, ok := rt.clients[key]
@@ -335,7 +432,9 @@ func (l *fixedWindow) hasExpired(relativeNow int64, minElapsed int64) bool {
 //
 // It returns false if the allowance has been already exhausted and the user
 // has to wait until it resets back to its maxAllowed value.
-func (l *fixedWindow) consume() bool {
+//
+// The remaining allowance and the seconds until its reset are also returned.
+func (l *fixedWindow) consume() (bool, int, int64) {
 	l.Lock()
 	defer l.Unlock()
 
@@ -346,12 +445,24 @@ func (l *fixedWindow) consume() bool {
 		l.available = l.maxAllowed
 	}
 
-	if l.available > 0 {
+	allowed := l.available > 0
+	if allowed {
 		l.available--
 		l.lastConsume = nowUnix
+	}
+
+	return allowed, l.available, l.interval - (nowUnix - l.lastConsume)
+}
+
+// state returns the remaining allowance and the seconds until its reset
+// relative to the provided time without consuming it.
+func (l *fixedWindow) state(relativeNow int64) (int, int64) {
+	l.Lock()
+	defer l.Unlock()
 
-		return true
+	if relativeNow-l.lastConsume >= l.interval {
+		return l.maxAllowed, 0
 	}
 
-	return false
+	return l.available, l.interval - (relativeNow - l.lastConsume)
 }
@@ -1,6 +1,7 @@
 package apis_test
 
 import (
+	"fmt"
 	"net/http/httptest"
 	"testing"
 	"time"
@@ -157,3 +158,87 @@ func TestDefaultRateLimitMiddleware(t *testing.T) {
 		})
 	}
 }
+
+func TestRateLimitMiddlewareHeaders(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().RateLimits.Enabled = true
+	app.Settings().RateLimits.Rules = []core.RateLimitRule{
+		{
+			Label:       "/rate/",
+			MaxRequests: 2,
+			Duration:    100,
+		},
+	}
+
+	pbRouter, err := apis.NewRouter(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	pbRouter.GET("/norate", func(e *core.RequestEvent) error {
+		return e.String(200, "norate")
+	})
+	pbRouter.GET("/rate/a", func(e *core.RequestEvent) error {
+		return e.String(200, "a")
+	})
+
+	mux, err := pbRouter.BuildMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		url               string
+		expectedStatus    int
+		expectedLimit     string
+		expectedRemaining string
+		expectRetryAfter  bool
+	}{
+		{"/norate", 200, "", "", false},
+		{"/rate/a", 200, "2", "1", false},
+		{"/rate/a", 200, "2", "0", false},
+		{"/rate/a", 429, "2", "0", true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.url), func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest("GET", s.url, nil)
+
+			mux.ServeHTTP(rec, req)
+
+			result := rec.Result()
+
+			if result.StatusCode != s.expectedStatus {
+				t.Fatalf("Expected response status %d, got %d", s.expectedStatus, result.StatusCode)
+			}
+
+			if v := result.Header.Get(apis.RateLimitLimitHeader); v != s.expectedLimit {
+				t.Fatalf("Expected %s header %q, got %q", apis.RateLimitLimitHeader, s.expectedLimit, v)
+			}
+
+			if v := result.Header.Get(apis.RateLimitRemainingHeader); v != s.expectedRemaining {
+				t.Fatalf("Expected %s header %q, got %q", apis.RateLimitRemainingHeader, s.expectedRemaining, v)
+			}
+
+			reset := result.Header.Get(apis.RateLimitResetHeader)
+			if s.expectedLimit == "" {
+				if reset != "" {
+					t.Fatalf("Expected no %s header, got %q", apis.RateLimitResetHeader, reset)
+				}
+			} else if reset != "100" && reset != "99" {
+				t.Fatalf("Expected %s header ~100, got %q", apis.RateLimitResetHeader, reset)
+			}
+
+			retryAfter := result.Header.Get("Retry-After")
+			if s.expectRetryAfter {
+				if retryAfter != reset {
+					t.Fatalf("Expected Retry-After header %q, got %q", reset, retryAfter)
+				}
+			} else if retryAfter != "" {
+				t.Fatalf("Expected no Retry-After header, got %q", retryAfter)
+			}
+		})
+	}
+}
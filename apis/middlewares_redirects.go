@@ -0,0 +1,70 @@
+package apis
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+const (
+	DefaultRedirectsMiddlewareId       = "pbRedirects"
+	DefaultRedirectsMiddlewarePriority = DefaultStaticMountsMiddlewarePriority - 1 // before the static mounts so that static paths could be redirected
+)
+
+// redirects defines the managed redirects middleware.
+//
+// GET and HEAD requests matching the path of a non-expired [core.Redirect]
+// are redirected to its target (before the static mounts and routes, including the
+// catch-all static handler). Requests to the /api/* and /_/* paths are never redirected.
+//
+// This middleware is registered by default for all routes.
+func redirects() *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Id:       DefaultRedirectsMiddlewareId,
+		Priority: DefaultRedirectsMiddlewarePriority,
+		Func: func(e *core.RequestEvent) error {
+			if e.Request.Method != http.MethodGet && e.Request.Method != http.MethodHead {
+				return e.Next()
+			}
+
+			urlPath := e.Request.URL.Path
+
+			if strings.HasPrefix(urlPath, "/api/") || strings.HasPrefix(urlPath, "/_/") {
+				return e.Next()
+			}
+
+			redirect, err := e.App.FindCachedRedirectByPath(urlPath)
+			if err != nil || redirect.IsExpired() {
+				return e.Next()
+			}
+
+			e.App.TrackRedirectHit(redirect)
+
+			return e.Redirect(redirect.Status, redirectTargetWithQuery(redirect.Target, e.Request.URL.RawQuery))
+		},
+	}
+}
+
+// redirectTargetWithQuery appends the raw request query parameters to the redirect target
+// (eg. to forward campaign tracking parameters).
+func redirectTargetWithQuery(target string, rawQuery string) string {
+	if rawQuery == "" {
+		return target
+	}
+
+	target, fragment, hasFragment := strings.Cut(target, "#")
+
+	if strings.Contains(target, "?") {
+		target += "&" + rawQuery
+	} else {
+		target += "?" + rawQuery
+	}
+
+	if hasFragment {
+		target += "#" + fragment
+	}
+
+	return target
+}
@@ -0,0 +1,108 @@
+package apis_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRedirectsMiddleware(t *testing.T) {
+	t.Parallel()
+
+	expectRedirect := func(location string, hits int) func(t testing.TB, app *tests.TestApp, res *http.Response) {
+		return func(t testing.TB, app *tests.TestApp, res *http.Response) {
+			if v := res.Header.Get("Location"); v != location {
+				t.Fatalf("Expected Location %q, got %q", location, v)
+			}
+
+			if err := app.FlushRedirectHits(); err != nil {
+				t.Fatal(err)
+			}
+
+			redirect, err := app.FindRedirectById("test_promo")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if redirect.Hits != hits {
+				t.Fatalf("Expected %d hits, got %d", hits, redirect.Hits)
+			}
+
+			if hits > 0 && redirect.LastHit.IsZero() {
+				t.Fatal("Expected non-zero last hit date")
+			}
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "matching path",
+			Method:         http.MethodGet,
+			URL:            "/promo",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { createTestRedirects(t, app) },
+			AfterTestFunc:  expectRedirect("https://example.com/landing", 1),
+			ExpectedStatus: 302,
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "matching path with trailing slash and query",
+			Method:         http.MethodHead,
+			URL:            "/promo/?utm_source=test&a=1",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { createTestRedirects(t, app) },
+			AfterTestFunc:  expectRedirect("https://example.com/landing?utm_source=test&a=1", 1),
+			ExpectedStatus: 302,
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:            "non-GET method",
+			Method:          http.MethodPost,
+			URL:             "/promo",
+			BeforeTestFunc:  func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { createTestRedirects(t, app) },
+			AfterTestFunc:   expectRedirect("", 0),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "non-matching path",
+			Method:          http.MethodGet,
+			URL:             "/promo/test",
+			BeforeTestFunc:  func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { createTestRedirects(t, app) },
+			AfterTestFunc:   expectRedirect("", 0),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "expired redirect",
+			Method:          http.MethodGet,
+			URL:             "/expired",
+			BeforeTestFunc:  func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) { createTestRedirects(t, app) },
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "before the catch-all route",
+			Method: http.MethodGet,
+			URL:    "/promo",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createTestRedirects(t, app)
+
+				e.Router.GET("/{path...}", func(e *core.RequestEvent) error {
+					return e.String(http.StatusOK, "catch-all")
+				})
+			},
+			AfterTestFunc:      expectRedirect("https://example.com/landing", 1),
+			ExpectedStatus:     302,
+			NotExpectedContent: []string{"catch-all"},
+			ExpectedEvents:     map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
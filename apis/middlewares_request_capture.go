@@ -0,0 +1,330 @@
+package apis
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"mime"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+const (
+	DefaultRequestCaptureMiddlewareId       = "pbRequestCapture"
+	DefaultRequestCaptureMiddlewarePriority = DefaultLoadAuthTokenMiddlewarePriority + 2 // after the auth token load so that the auth state could be captured
+)
+
+// requestCaptureScrubbed is the placeholder of the scrubbed captured values.
+const requestCaptureScrubbed = "[scrubbed]"
+
+// requestCaptureSecretHeaders is a list of the always scrubbed (lowercased) header names.
+var requestCaptureSecretHeaders = []string{
+	"authorization",
+	"proxy-authorization",
+	"cookie",
+	"set-cookie",
+	"x-api-key",
+}
+
+// requestCaptureSecretKeywords is a list of (lowercased) keywords
+// whose containing header, query and body field names are always scrubbed.
+var requestCaptureSecretKeywords = []string{
+	"password",
+	"secret",
+	"token",
+	"apikey",
+}
+
+type requestCaptureReplayKey struct{}
+
+// requestCapture defines the debug request/response capture middleware
+// that stores the full (scrubbed) request/response pairs of the
+// routes matching the [core.RequestCaptureConfig] as "requestCapture" logs.
+//
+// This middleware is registered by default for all routes.
+func requestCapture() *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Id:       DefaultRequestCaptureMiddlewareId,
+		Priority: DefaultRequestCaptureMiddlewarePriority,
+		Func: func(e *core.RequestEvent) error {
+			config := e.App.Settings().RequestCapture
+
+			if !config.Enabled ||
+				e.App.Settings().Logs.MaxDays == 0 ||
+				e.Request.Context().Value(requestCaptureReplayKey{}) != nil || // already a replay
+				!config.MatchRoute(e.Request.Method, e.Request.URL.Path) {
+				return e.Next()
+			}
+
+			maxBodySize := config.MaxBodySizeValue()
+
+			requestBody, requestBodyTruncated, err := peekRequestBody(e.Request, maxBodySize)
+			if err != nil {
+				return e.BadRequestError("Failed to read the request body.", err)
+			}
+
+			rw := &captureResponseWriter{ResponseWriter: e.Response, max: maxBodySize}
+			e.Response = rw
+
+			err = e.Next()
+
+			e.Response = rw.ResponseWriter
+
+			logRequestCapture(e, &config, err, requestBody, requestBodyTruncated, rw)
+
+			return err
+		},
+	}
+}
+
+func logRequestCapture(
+	e *core.RequestEvent,
+	config *core.RequestCaptureConfig,
+	err error,
+	requestBody []byte,
+	requestBodyTruncated bool,
+	rw *captureResponseWriter,
+) {
+	status := e.Status()
+	responseBody := rw.body.Bytes()
+	responseBodyTruncated := rw.truncated
+
+	// the error response is not written yet
+	if err != nil && !rw.written {
+		apiErr := router.ToApiError(err)
+		status = apiErr.Status
+		responseBody, _ = json.Marshal(apiErr)
+		responseBodyTruncated = false
+	}
+
+	requestContentType := e.Request.Header.Get("Content-Type")
+	if isMultipartContentType(requestContentType) {
+		// files are not captured
+		requestBody = nil
+		requestBodyTruncated = true
+	}
+
+	attrs := []any{
+		slog.String("type", "requestCapture"),
+		slog.String("method", strings.ToUpper(e.Request.Method)),
+		slog.String("url", scrubCapturedURL(e.Request.URL, config.ScrubFields)),
+		slog.Int("status", status),
+		slog.Any("requestHeaders", scrubCapturedHeaders(e.Request.Header, config.ScrubFields)),
+		slog.String("requestBody", scrubCapturedBody(requestBody, requestContentType, requestBodyTruncated, config.ScrubFields)),
+		slog.Bool("requestBodyTruncated", requestBodyTruncated),
+		slog.Any("responseHeaders", scrubCapturedHeaders(rw.Header(), config.ScrubFields)),
+		slog.String("responseBody", scrubCapturedBody(responseBody, rw.Header().Get("Content-Type"), responseBodyTruncated, config.ScrubFields)),
+		slog.Bool("responseBodyTruncated", responseBodyTruncated),
+	}
+
+	if e.Auth != nil {
+		attrs = append(
+			attrs,
+			slog.String("auth", e.Auth.Collection().Name),
+			slog.String("authId", e.Auth.Id),
+		)
+	} else {
+		attrs = append(attrs, slog.String("auth", ""))
+	}
+
+	e.App.Logger().Info("Captured "+strings.ToUpper(e.Request.Method)+" "+e.Request.URL.Path, attrs...)
+}
+
+// peekRequestBody reads up to max bytes from the request body
+// without consuming it for the next handlers.
+func peekRequestBody(r *http.Request, max int64) ([]byte, bool, error) {
+	if r.Body == nil || r.Body == http.NoBody {
+		return nil, false, nil
+	}
+
+	original := r.Body
+
+	body, err := io.ReadAll(io.LimitReader(original, max+1))
+	if err != nil {
+		return nil, false, err
+	}
+
+	r.Body = &router.RereadableReadCloser{
+		ReadCloser: &multiReadCloser{
+			Reader: io.MultiReader(bytes.NewReader(body), original),
+			Closer: original,
+		},
+	}
+
+	if int64(len(body)) > max {
+		return body[:max], true, nil
+	}
+
+	return body, false, nil
+}
+
+type multiReadCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// -------------------------------------------------------------------
+
+var _ router.RWUnwrapper = (*captureResponseWriter)(nil)
+
+// captureResponseWriter is a [http.ResponseWriter] wrapper that
+// keeps a copy of the first max written response body bytes.
+type captureResponseWriter struct {
+	http.ResponseWriter
+
+	body      bytes.Buffer
+	max       int64
+	truncated bool
+	written   bool
+}
+
+func (w *captureResponseWriter) WriteHeader(status int) {
+	w.written = true
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *captureResponseWriter) Write(b []byte) (int, error) {
+	w.written = true
+
+	if remaining := w.max - int64(w.body.Len()); remaining > 0 {
+		if int64(len(b)) > remaining {
+			w.body.Write(b[:remaining])
+			w.truncated = true
+		} else {
+			w.body.Write(b)
+		}
+	} else if len(b) > 0 {
+		w.truncated = true
+	}
+
+	return w.ResponseWriter.Write(b)
+}
+
+// Unwrap returns the underlying ResponseWritter instance (usually used by [http.ResponseController]).
+func (w *captureResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// -------------------------------------------------------------------
+
+func isCaptureSecretField(name string, extra []string) bool {
+	name = strings.ToLower(name)
+
+	for _, keyword := range requestCaptureSecretKeywords {
+		if strings.Contains(strings.ReplaceAll(name, "-", ""), keyword) {
+			return true
+		}
+	}
+
+	return slices.ContainsFunc(extra, func(v string) bool {
+		return strings.EqualFold(v, name)
+	})
+}
+
+func scrubCapturedHeaders(headers http.Header, extra []string) map[string]string {
+	result := make(map[string]string, len(headers))
+
+	for name, values := range headers {
+		if slices.Contains(requestCaptureSecretHeaders, strings.ToLower(name)) || isCaptureSecretField(name, extra) {
+			result[name] = requestCaptureScrubbed
+		} else {
+			result[name] = strings.Join(values, ", ")
+		}
+	}
+
+	return result
+}
+
+func scrubCapturedURL(u *url.URL, extra []string) string {
+	if u.RawQuery == "" {
+		return u.RequestURI()
+	}
+
+	query := u.Query()
+	scrubCapturedValues(query, extra)
+
+	return u.EscapedPath() + "?" + query.Encode()
+}
+
+func scrubCapturedValues(values url.Values, extra []string) {
+	for name := range values {
+		if isCaptureSecretField(name, extra) {
+			values[name] = []string{requestCaptureScrubbed}
+		}
+	}
+}
+
+// scrubCapturedBody replaces the secret JSON and urlencoded form
+// body field values with a placeholder and returns the body as string.
+//
+// Truncated or non-UTF8 bodies are returned as they are
+// or replaced with a placeholder respectively.
+func scrubCapturedBody(body []byte, contentType string, truncated bool, extra []string) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	if !utf8.Valid(body) && !truncated {
+		return "[binary]"
+	}
+
+	if truncated {
+		return strings.ToValidUTF8(string(body), "")
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch {
+	case mediaType == "application/x-www-form-urlencoded":
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return string(body)
+		}
+		scrubCapturedValues(values, extra)
+		return values.Encode()
+	case strings.HasSuffix(mediaType, "json") || json.Valid(body):
+		var data any
+		if err := json.Unmarshal(body, &data); err != nil {
+			return string(body)
+		}
+		raw, err := json.Marshal(scrubCapturedJSON(data, extra))
+		if err != nil {
+			return string(body)
+		}
+		return string(raw)
+	default:
+		return string(body)
+	}
+}
+
+func scrubCapturedJSON(data any, extra []string) any {
+	switch v := data.(type) {
+	case map[string]any:
+		for key, item := range v {
+			if isCaptureSecretField(key, extra) {
+				v[key] = requestCaptureScrubbed
+			} else {
+				v[key] = scrubCapturedJSON(item, extra)
+			}
+		}
+	case []any:
+		for i, item := range v {
+			v[i] = scrubCapturedJSON(item, extra)
+		}
+	}
+
+	return data
+}
+
+func isMultipartContentType(contentType string) bool {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+	return strings.HasPrefix(mediaType, "multipart/")
+}
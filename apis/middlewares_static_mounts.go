@@ -0,0 +1,196 @@
+package apis
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+const (
+	DefaultStaticMountsMiddlewarePriority = DefaultBodyLimitMiddlewarePriority + 10
+)
+
+// staticMountRuleFields lists the fields that are allowed to be used in a static mount rule.
+var staticMountRuleFields = []string{
+	`^\@request\.context$`,
+	`^\@request\.method$`,
+	`^\@request\.auth\.[\w\.\:]*\w+$`,
+	`^\@request\.query\.[\w\.\:]*\w+$`,
+	`^\@request\.headers\.[\w\.\:]*\w+$`,
+}
+
+// StaticMounts creates a middleware that serves the files of the provided
+// static directory mounts.
+//
+// The mounts could be registered only programmatically (or with the
+// prebuilt executable --staticMount flag) and not from the app settings
+// because they allow serving arbitrary local directories.
+//
+// Requests that doesn't match any of the mount path prefixes are forwarded
+// to the next handler in the chain.
+//
+// Example:
+//
+//	app.OnServe().BindFunc(func(se *core.ServeEvent) error {
+//		se.Router.Bind(apis.StaticMounts(core.StaticMount{
+//			Path:          "/docs",
+//			Dir:           "./pb_docs",
+//			IndexFallback: true,
+//			CacheControl:  "public, max-age=3600",
+//		}))
+//		return se.Next()
+//	})
+func StaticMounts(mounts ...core.StaticMount) *hook.Handler[*core.RequestEvent] {
+	config := core.StaticConfig{Mounts: mounts}
+
+	return &hook.Handler[*core.RequestEvent]{
+		Func: func(e *core.RequestEvent) error {
+			return serveStaticMount(e, &config)
+		},
+		Priority: DefaultStaticMountsMiddlewarePriority,
+	}
+}
+
+func serveStaticMount(e *core.RequestEvent, config *core.StaticConfig) error {
+	if e.Request.Method != http.MethodGet && e.Request.Method != http.MethodHead {
+		return e.Next()
+	}
+
+	urlPath := e.Request.URL.Path
+
+	// never shadow the api and the dashboard routes
+	if strings.HasPrefix(urlPath, "/api/") || strings.HasPrefix(urlPath, "/_/") {
+		return e.Next()
+	}
+
+	mount, ok := config.FindMount(urlPath)
+	if !ok {
+		return e.Next()
+	}
+
+	dir, err := filepath.Abs(mount.Dir)
+	if err != nil {
+		return e.InternalServerError("", err)
+	}
+
+	// eagerly prevent accidentally exposing the app data
+	if isDataDirExposed(e.App, dir) {
+		return e.InternalServerError("", fmt.Errorf("static mount %q exposes the app data directory", mount.Path))
+	}
+
+	if mount.Rule != "" {
+		allowed, err := canAccessStaticMount(e, mount.Rule)
+		if err != nil {
+			return e.InternalServerError("", err)
+		}
+
+		if !allowed {
+			if e.Auth == nil {
+				return e.UnauthorizedError("The request requires valid authorization token.", nil)
+			}
+			return e.ForbiddenError("You are not allowed to access the requested resource.", nil)
+		}
+	}
+
+	if mount.CacheControl != "" {
+		e.Response.Header().Set("Cache-Control", mount.CacheControl)
+	}
+
+	e.Request.SetPathValue(StaticWildcardParam, strings.TrimPrefix(urlPath, mount.CleanPath()))
+
+	return Static(os.DirFS(dir), mount.IndexFallback)(e)
+}
+
+func isDataDirExposed(app core.App, dir string) bool {
+	dataDir, err := filepath.Abs(app.DataDir())
+	if err != nil {
+		return true
+	}
+
+	rel, err := filepath.Rel(dir, dataDir)
+	if err != nil {
+		return false
+	}
+
+	return rel == "." || (rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)))
+}
+
+func canAccessStaticMount(e *core.RequestEvent, rule string) (bool, error) {
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return false, err
+	}
+
+	// the rule is not bound to any collection and only the request fields could be used
+	resolver := core.NewRecordFieldResolver(e.App, core.NewBaseCollection("@static"), requestInfo, true)
+	resolver.SetAllowedFields(staticMountRuleFields)
+
+	expr, err := search.FilterData(rule).BuildExpr(resolver)
+	if err != nil {
+		return false, err
+	}
+
+	var exists int
+
+	err = e.App.ConcurrentDB().Select("(1)").AndWhere(expr).Limit(1).Row(&exists)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	return exists > 0, nil
+}
+
+// ParseStaticMount parses a static mount from its CLI flag
+// representation in the format "path=dir[?option=value&...]".
+//
+// The supported options are "indexFallback", "cacheControl" and "rule".
+//
+// Example:
+//
+//	/docs=./pb_docs?indexFallback=true&cacheControl=public,max-age=3600
+func ParseStaticMount(raw string) (core.StaticMount, error) {
+	mount := core.StaticMount{}
+
+	path, rest, ok := strings.Cut(raw, "=")
+	if !ok {
+		return mount, fmt.Errorf("invalid static mount %q - expected path=dir format", raw)
+	}
+
+	dir, rawOptions, _ := strings.Cut(rest, "?")
+
+	mount.Path = strings.TrimSpace(path)
+	mount.Dir = strings.TrimSpace(dir)
+
+	options, err := url.ParseQuery(rawOptions)
+	if err != nil {
+		return mount, fmt.Errorf("invalid static mount %q options: %w", raw, err)
+	}
+
+	for name := range options {
+		switch name {
+		case "indexFallback":
+			mount.IndexFallback, err = strconv.ParseBool(options.Get(name))
+			if err != nil {
+				return mount, fmt.Errorf("invalid static mount %q indexFallback option: %w", raw, err)
+			}
+		case "cacheControl":
+			mount.CacheControl = options.Get(name)
+		case "rule":
+			mount.Rule = options.Get(name)
+		default:
+			return mount, fmt.Errorf("unknown static mount %q option %q", raw, name)
+		}
+	}
+
+	return mount, mount.Validate()
+}
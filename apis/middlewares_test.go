@@ -2,11 +2,14 @@ package apis_test
 
 import (
 	"net/http"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestPanicRecover(t *testing.T) {
@@ -537,3 +540,204 @@ func TestRequireSameCollectionContextAuth(t *testing.T) {
 		scenario.Test(t)
 	}
 }
+
+func TestLoadAuthTokenRequestInfoRuleFields(t *testing.T) {
+	t.Parallel()
+
+	headers := map[string]string{}
+
+	beforeFunc := func(authTime time.Time) func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+		return func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+			user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token, err := user.NewAuthTokenSince(authTime)
+			if err != nil {
+				t.Fatal(err)
+			}
+			headers["Authorization"] = token
+
+			collection, err := app.FindCollectionByNameOrId("demo1")
+			if err != nil {
+				t.Fatal(err)
+			}
+			collection.ListRule = types.Pointer(`@request.custom.tenant = "abc" && @request.ip = "192.0.2.1" && @request.auth.sessionAge > 3600`)
+			if err := app.Save(collection); err != nil {
+				t.Fatal(err)
+			}
+
+			e.Router.BindFunc(func(e *core.RequestEvent) error {
+				e.SetRequestInfoCustom("tenant", "abc")
+				return e.Next()
+			})
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "recent authentication",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo1/records",
+			Headers:         headers,
+			BeforeTestFunc:  beforeFunc(time.Now()),
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"totalItems":0`},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+			},
+		},
+		{
+			Name:           "old authentication",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo1/records",
+			Headers:        headers,
+			BeforeTestFunc: beforeFunc(time.Now().Add(-2 * time.Hour)),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":3`,
+				`"id":"84nmscqy84lsi1t"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+				"OnRecordEnrich":       3,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestLoadAuthTokenProfileCompletion(t *testing.T) {
+	t.Parallel()
+
+	headers := map[string]string{}
+
+	beforeFunc := func(name string) func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+		return func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+			collection, err := app.FindCachedCollectionByNameOrId("users")
+			if err != nil {
+				t.Fatal(err)
+			}
+			collection.ProfileCompletion.Enabled = true
+			collection.ProfileCompletion.Fields = []string{"name"}
+
+			user, err := app.FindAuthRecordByEmail(collection, "test@example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			// generate the token with the missing profile field
+			user.Set("name", "")
+			token, err := user.NewAuthToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+			headers["Authorization"] = token
+
+			user.Set("name", name)
+			if err := app.SaveNoValidate(user); err != nil {
+				t.Fatal(err)
+			}
+
+			app.ResetEventCalls()
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "incomplete profile accessing other route",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/records",
+			Headers:        headers,
+			BeforeTestFunc: beforeFunc(""),
+			ExpectedStatus: 403,
+			ExpectedContent: []string{
+				`"data":{"name":{"code":"validation_profile_incomplete"`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "incomplete profile accessing another record",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/users/records/oap640cot4yru2s",
+			Headers:        headers,
+			BeforeTestFunc: beforeFunc(""),
+			ExpectedStatus: 403,
+			ExpectedContent: []string{
+				`"data":{"name":{"code":"validation_profile_incomplete"`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "incomplete profile viewing the own record",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/users/records/4q1xlclmfloku33",
+			Headers:        headers,
+			BeforeTestFunc: beforeFunc(""),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"4q1xlclmfloku33"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                   0,
+				"OnRecordViewRequest": 1,
+				"OnRecordEnrich":      1,
+			},
+		},
+		{
+			Name:           "incomplete profile completing the own record",
+			Method:         http.MethodPatch,
+			URL:            "/api/collections/_pb_users_auth_/records/4q1xlclmfloku33",
+			Body:           strings.NewReader(`{"name":"completed"}`),
+			Headers:        headers,
+			BeforeTestFunc: beforeFunc(""),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"name":"completed"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordUpdateRequest": 1,
+			},
+		},
+		{
+			Name:           "incomplete profile refreshing the token",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/users/auth-refresh",
+			Headers:        headers,
+			BeforeTestFunc: beforeFunc(""),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"token":"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordAuthRefreshRequest": 1,
+			},
+		},
+		{
+			Name:           "flagged token with already completed profile",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/records",
+			Headers:        headers,
+			BeforeTestFunc: beforeFunc("completed"),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"totalItems":3`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+				"OnRecordEnrich":       3,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,100 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+const (
+	DefaultTimeoutMiddlewareId       = "pbTimeout"
+	DefaultTimeoutMiddlewarePriority = DefaultRateLimitMiddlewarePriority + 20
+)
+
+// timeoutWriteGracePeriod is the extra time after the request deadline
+// that is reserved for writing the timeout error response.
+const timeoutWriteGracePeriod = 5 * time.Second
+
+// Timeout returns a middleware handler that limits the route processing time.
+//
+// The timeout is applied as deadline to the request context
+// (aka. the db queries and hooks that use it are cancelled once it is reached)
+// and it also replaces the default server read and write timeouts for the request.
+//
+// When registered for a specific route, it overwrites
+// the settings based default timeout middleware.
+//
+// If timeout <= 0, no timeout is applied (including the default server ones).
+func Timeout(timeout time.Duration) *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Id:       DefaultTimeoutMiddlewareId,
+		Priority: DefaultTimeoutMiddlewarePriority,
+		Func: func(e *core.RequestEvent) error {
+			return applyTimeout(e, timeout)
+		},
+	}
+}
+
+// settingsTimeout defines the global request timeout middleware
+// that applies the first matching [core.TimeoutsConfig] rule.
+//
+// This middleware is registered by default for all routes.
+func settingsTimeout() *hook.Handler[*core.RequestEvent] {
+	return &hook.Handler[*core.RequestEvent]{
+		Id:       DefaultTimeoutMiddlewareId,
+		Priority: DefaultTimeoutMiddlewarePriority,
+		Func: func(e *core.RequestEvent) error {
+			config := e.App.Settings().Timeouts
+			if !config.Enabled {
+				return e.Next()
+			}
+
+			rule, ok := config.FindTimeoutRule([]string{
+				e.Request.Method + " " + e.Request.URL.Path,
+				e.Request.URL.Path,
+			})
+			if !ok {
+				return e.Next()
+			}
+
+			return applyTimeout(e, rule.DurationTime())
+		},
+	}
+}
+
+func applyTimeout(e *core.RequestEvent, timeout time.Duration) error {
+	rc := http.NewResponseController(e.Response)
+
+	if timeout <= 0 {
+		setConnDeadlines(e, rc, time.Time{})
+		return e.Next()
+	}
+
+	ctx, cancel := context.WithTimeout(e.Request.Context(), timeout)
+	defer cancel()
+
+	deadline, _ := ctx.Deadline()
+	setConnDeadlines(e, rc, deadline.Add(timeoutWriteGracePeriod))
+
+	e.Request = e.Request.WithContext(ctx)
+
+	err := e.Next()
+
+	if err != nil && errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return e.Error(http.StatusServiceUnavailable, "The request processing timeout has been reached.", err)
+	}
+
+	return err
+}
+
+func setConnDeadlines(e *core.RequestEvent, rc *http.ResponseController, deadline time.Time) {
+	for _, err := range []error{rc.SetReadDeadline(deadline), rc.SetWriteDeadline(deadline)} {
+		if err != nil && !errors.Is(err, http.ErrNotSupported) {
+			e.App.Logger().Debug("Failed to set the request connection deadline", "error", err)
+		}
+	}
+}
@@ -0,0 +1,128 @@
+package apis_test
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestTimeoutMiddleware(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Timeouts.Enabled = true
+	app.Settings().Timeouts.Rules = []core.TimeoutRule{
+		{Label: "/a/", Timeout: 100},
+		{Label: "GET /b", Timeout: 200},
+	}
+
+	pbRouter, err := apis.NewRouter(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// writes the remaining context deadline in seconds
+	// (or "-" if there is no deadline)
+	handler := func(e *core.RequestEvent) error {
+		deadline, ok := e.Request.Context().Deadline()
+		if !ok {
+			return e.String(200, "-")
+		}
+
+		return e.String(200, time.Until(deadline).Round(10*time.Second).String())
+	}
+
+	pbRouter.GET("/a", handler)
+	pbRouter.GET("/b", handler)
+	pbRouter.POST("/b", handler)
+	pbRouter.GET("/c", handler).Bind(apis.Timeout(300 * time.Second))
+	pbRouter.GET("/d", handler)
+	pbRouter.GET("/a/other", handler)
+	pbRouter.GET("/a/unbind", handler).Unbind(apis.DefaultTimeoutMiddlewareId)
+
+	pbRouter.GET("/e", func(e *core.RequestEvent) error {
+		<-e.Request.Context().Done()
+		return e.Request.Context().Err()
+	}).Bind(apis.Timeout(10 * time.Millisecond))
+
+	mux, err := pbRouter.BuildMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		method         string
+		url            string
+		expectedStatus int
+		expectedBody   string
+	}{
+		{"GET", "/a", 200, "1m40s"},
+		{"GET", "/b", 200, "3m20s"},
+		{"POST", "/b", 200, "-"},
+		{"GET", "/c", 200, "5m0s"},
+		{"GET", "/d", 200, "-"},
+		{"GET", "/a/unbind", 200, "-"},
+		{"GET", "/a/other", 200, "1m40s"},
+		{"GET", "/e", 503, `"message":"The request processing timeout has been reached."`},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.method+" "+s.url, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			req := httptest.NewRequest(s.method, s.url, nil)
+			mux.ServeHTTP(rec, req)
+
+			if rec.Code != s.expectedStatus {
+				t.Fatalf("Expected response status %d, got %d", s.expectedStatus, rec.Code)
+			}
+
+			body := rec.Body.String()
+			if s.expectedStatus == 200 && body != s.expectedBody {
+				t.Fatalf("Expected body %q, got %q", s.expectedBody, body)
+			}
+			if s.expectedStatus != 200 && !strings.Contains(body, s.expectedBody) {
+				t.Fatalf("Expected body to contain %q, got %q", s.expectedBody, body)
+			}
+		})
+	}
+}
+
+func TestTimeoutMiddlewareDisabled(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Timeouts.Enabled = false
+	app.Settings().Timeouts.Rules = []core.TimeoutRule{
+		{Label: "/a", Timeout: 100},
+	}
+
+	pbRouter, err := apis.NewRouter(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pbRouter.GET("/a", func(e *core.RequestEvent) error {
+		if _, ok := e.Request.Context().Deadline(); ok {
+			return e.String(200, "deadline")
+		}
+		return e.String(200, "-")
+	})
+
+	mux, err := pbRouter.BuildMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest("GET", "/a", nil)
+	mux.ServeHTTP(rec, req)
+
+	if body := rec.Body.String(); body != "-" {
+		t.Fatalf("Expected no request deadline, got %q", body)
+	}
+}
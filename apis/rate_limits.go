@@ -0,0 +1,50 @@
+package apis
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindRateLimitsApi registers the rate limits api endpoints.
+func bindRateLimitsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	subGroup := rg.Group("/rate-limits")
+	subGroup.GET("", rateLimitsView)
+}
+
+type rateLimitsItem struct {
+	rateLimitStatus
+
+	Label    string `json:"label"`
+	Audience string `json:"audience"`
+}
+
+// rateLimitsView returns the current request client state of the
+// rate limit rules applicable for its audience (guest or auth)
+// so that the clients could self-throttle.
+func rateLimitsView(e *core.RequestEvent) error {
+	config := e.App.Settings().RateLimits
+
+	audience := defaultRateLimitAudience(e)
+
+	items := make([]rateLimitsItem, 0, len(config.Rules))
+
+	for _, rule := range config.Rules {
+		if !slices.Contains(audience, rule.Audience) {
+			continue
+		}
+
+		items = append(items, rateLimitsItem{
+			rateLimitStatus: findRateLimitStatus(e, rule),
+			Label:           rule.Label,
+			Audience:        rule.Audience,
+		})
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"enabled": config.Enabled && !e.HasSuperuserAuth(),
+		"items":   items,
+	})
+}
@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
-	"strings"
 	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -19,6 +18,7 @@ import (
 	"github.com/pocketbase/pocketbase/tools/routine"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"github.com/pocketbase/pocketbase/tools/types"
 	"golang.org/x/sync/errgroup"
 )
 
@@ -28,16 +28,28 @@ const clientsChunkSize = 150
 // RealtimeClientAuthKey is the name of the realtime client store key that holds its auth state.
 const RealtimeClientAuthKey = "auth"
 
+// RealtimeClientConnectedAtKey is the name of the realtime client store key that holds its connection time.
+const RealtimeClientConnectedAtKey = "connectedAt"
+
 // bindRealtimeApi registers the realtime api endpoints.
 func bindRealtimeApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
 	sub := rg.Group("/realtime")
-	sub.GET("", realtimeConnect).Bind(SkipSuccessActivityLog())
+	sub.GET("", realtimeConnect).Bind(SkipSuccessActivityLog(), realtimeCompression()).Unbind(DefaultTimeoutMiddlewareId)
 	sub.POST("", realtimeSetSubscriptions)
 
+	clientsSub := sub.Group("/clients").Bind(RequireSuperuserAuth())
+	clientsSub.GET("", realtimeClientsList)
+	clientsSub.DELETE("/{clientId}", realtimeClientDisconnect)
+
 	bindRealtimeEvents(app)
 }
 
 func realtimeConnect(e *core.RequestEvent) error {
+	maxClients := e.App.Settings().Realtime.MaxClients
+	if maxClients > 0 && e.App.SubscriptionsBroker().TotalClients() >= maxClients {
+		return e.TooManyRequestsError("The maximum allowed realtime connections has been reached.", nil)
+	}
+
 	// disable global write deadline for the SSE connection
 	rc := http.NewResponseController(e.Response)
 	writeDeadlineErr := rc.SetWriteDeadline(time.Time{})
@@ -64,10 +76,13 @@ func realtimeConnect(e *core.RequestEvent) error {
 	connectEvent := new(core.RealtimeConnectRequestEvent)
 	connectEvent.RequestEvent = e
 	connectEvent.Client = subscriptions.NewDefaultClient()
-	connectEvent.IdleTimeout = 5 * time.Minute
+	connectEvent.IdleTimeout = e.App.Settings().Realtime.IdleTimeoutDuration()
+	connectEvent.HeartbeatInterval = time.Duration(e.App.Settings().Realtime.HeartbeatInterval) * time.Second
+	connectEvent.RetryInterval = time.Duration(e.App.Settings().Realtime.RetryInterval) * time.Millisecond
 
 	return e.App.OnRealtimeConnectRequest().Trigger(connectEvent, func(ce *core.RealtimeConnectRequestEvent) error {
 		// register new subscription client
+		ce.Client.Set(RealtimeClientConnectedAtKey, types.NowDateTime())
 		ce.App.SubscriptionsBroker().Register(ce.Client)
 		defer func() {
 			e.App.SubscriptionsBroker().Unregister(ce.Client.Id())
@@ -75,6 +90,18 @@ func realtimeConnect(e *core.RequestEvent) error {
 
 		ce.App.Logger().Debug("Realtime connection established.", slog.String("clientId", ce.Client.Id()))
 
+		// send the reconnection hint (if any)
+		if ce.RetryInterval > 0 {
+			if err := subscriptions.WriteSSERetry(ce.Response, ce.RetryInterval); err != nil {
+				ce.App.Logger().Debug(
+					"Realtime connection closed (failed to deliver retry hint)",
+					slog.String("clientId", ce.Client.Id()),
+					slog.String("error", err.Error()),
+				)
+				return nil
+			}
+		}
+
 		// signalize established connection (aka. fire "connect" message)
 		connectMsgEvent := new(core.RealtimeMessageEvent)
 		connectMsgEvent.RequestEvent = ce.RequestEvent
@@ -103,10 +130,32 @@ func realtimeConnect(e *core.RequestEvent) error {
 		idleTimer := time.NewTimer(ce.IdleTimeout)
 		defer idleTimer.Stop()
 
+		// start a heartbeat ticker to prevent proxies from dropping the idle connection
+		// (the heartbeats are not considered activity and don't reset the idle timer)
+		var heartbeatCh <-chan time.Time
+		if ce.HeartbeatInterval > 0 {
+			heartbeatTicker := time.NewTicker(ce.HeartbeatInterval)
+			defer heartbeatTicker.Stop()
+			heartbeatCh = heartbeatTicker.C
+		}
+
 		for {
 			select {
 			case <-idleTimer.C:
 				cancelRequest()
+			case <-heartbeatCh:
+				err := subscriptions.WriteSSEComment(ce.Response, "ping")
+				if err == nil {
+					err = ce.Flush()
+				}
+				if err != nil {
+					ce.App.Logger().Debug(
+						"Realtime connection closed (failed to deliver heartbeat)",
+						slog.String("clientId", ce.Client.Id()),
+						slog.String("error", err.Error()),
+					)
+					return nil
+				}
 			case msg, ok := <-ce.Client.Channel():
 				if !ok {
 					// channel is closed
@@ -192,6 +241,27 @@ func realtimeSetSubscriptions(e *core.RequestEvent) error {
 		return e.ForbiddenError("The current and the previous request authorization don't match.", nil)
 	}
 
+	// check the per auth clients limit on guest->auth upgrade
+	maxClientsPerAuth := e.App.Settings().Realtime.MaxClientsPerAuth
+	if clientAuth == nil && e.Auth != nil && maxClientsPerAuth > 0 &&
+		realtimeTotalAuthClients(e.App, e.Auth, client.Id()) >= maxClientsPerAuth {
+		return e.TooManyRequestsError("The maximum allowed realtime connections for the current auth has been reached.", nil)
+	}
+
+	// check the parameterized custom topics access rules
+	if len(e.App.Settings().Realtime.Topics) > 0 {
+		requestInfo, err := e.RequestInfo()
+		if err != nil {
+			return e.BadRequestError("", err)
+		}
+
+		for _, sub := range form.Subscriptions {
+			if ok, err := e.App.CanAccessRealtimeTopic(sub, requestInfo); !ok {
+				return e.ForbiddenError("You are not allowed to subscribe to one or more of the requested topics.", err)
+			}
+		}
+	}
+
 	event := new(core.RealtimeSubscribeRequestEvent)
 	event.RequestEvent = e
 	event.Client = client
@@ -219,6 +289,25 @@ func realtimeSetSubscriptions(e *core.RequestEvent) error {
 	})
 }
 
+// realtimeTotalAuthClients returns the total number of registered clients
+// associated with the provided auth record (excluding the one with excludeClientId).
+func realtimeTotalAuthClients(app core.App, authRecord *core.Record, excludeClientId string) int {
+	var total int
+
+	for id, client := range app.SubscriptionsBroker().Clients() {
+		if id == excludeClientId {
+			continue
+		}
+
+		clientAuth, _ := client.Get(RealtimeClientAuthKey).(*core.Record)
+		if isSameAuth(clientAuth, authRecord) {
+			total++
+		}
+	}
+
+	return total
+}
+
 // updateClientsAuth updates the existing clients auth record with the new one (matched by ID).
 func realtimeUpdateClientsAuth(app core.App, newAuthRecord *core.Record) error {
 	chunks := app.SubscriptionsBroker().ChunkedClients(clientsChunkSize)
@@ -495,15 +584,18 @@ func realtimeBroadcastRecord(app core.App, action string, record *core.Record, d
 		return nil // no subscribers
 	}
 
-	subscriptionRuleMap := map[string]*string{
-		(collection.Name + "/" + record.Id + "?"): collection.ViewRule,
-		(collection.Id + "/" + record.Id + "?"):   collection.ViewRule,
-		(collection.Name + "/*?"):                 collection.ListRule,
-		(collection.Id + "/*?"):                   collection.ListRule,
+	viewAccess := realtimeSubscriptionAccess{collection.ViewRule, core.CollectionPolicyActionView}
+	listAccess := realtimeSubscriptionAccess{collection.ListRule, core.CollectionPolicyActionList}
+
+	subscriptionRuleMap := map[string]realtimeSubscriptionAccess{
+		(collection.Name + "/" + record.Id + "?"): viewAccess,
+		(collection.Id + "/" + record.Id + "?"):   viewAccess,
+		(collection.Name + "/*?"):                 listAccess,
+		(collection.Id + "/*?"):                   listAccess,
 
 		// @deprecated: the same as the wildcard topic but kept for backward compatibility
-		(collection.Name + "?"): collection.ListRule,
-		(collection.Id + "?"):   collection.ListRule,
+		(collection.Name + "?"): listAccess,
+		(collection.Id + "?"):   listAccess,
 	}
 
 	dryCacheKey := getDryCacheKey(action, record)
@@ -522,7 +614,7 @@ func realtimeBroadcastRecord(app core.App, action string, record *core.Record, d
 			for _, client := range chunk {
 				// note: not executed concurrently to avoid races and to ensure
 				// that the access checks are applied for the current record db state
-				for prefix, rule := range subscriptionRuleMap {
+				for prefix, access := range subscriptionRuleMap {
 					subs := client.Subscriptions(prefix)
 					if len(subs) == 0 {
 						continue
@@ -540,7 +632,7 @@ func realtimeBroadcastRecord(app core.App, action string, record *core.Record, d
 							Auth:    clientAuth,
 						}
 
-						if !realtimeCanAccessRecord(accessCheckApp, record, requestInfo, rule) {
+						if !realtimeCanAccessRecord(accessCheckApp, record, requestInfo, access.rule, access.policiesAction) {
 							continue
 						}
 
@@ -550,10 +642,19 @@ func realtimeBroadcastRecord(app core.App, action string, record *core.Record, d
 
 						// trigger the enrich hooks
 						enrichErr := triggerRecordEnrichHooks(app, requestInfo, []*core.Record{cleanRecord}, func() error {
+							if err := resolveRecordsLocks(app, []*core.Record{cleanRecord}); err != nil {
+								app.Logger().Debug(
+									"[broadcastRecord] failed to resolve record lock",
+									slog.String("id", cleanRecord.Id),
+									slog.String("collectionName", cleanRecord.Collection().Name),
+									slog.Any("error", err),
+								)
+							}
+
 							// apply expand
 							rawExpand := options.Query[expandQueryParam]
 							if rawExpand != "" {
-								expandErrs := app.ExpandRecord(cleanRecord, strings.Split(rawExpand, ","), expandFetch(app, requestInfo))
+								expandErrs := app.ExpandRecord(cleanRecord, core.SplitExpands(rawExpand), expandFetch(app, requestInfo))
 								if len(expandErrs) > 0 {
 									app.Logger().Debug(
 										"[broadcastRecord] expand errors",
@@ -570,7 +671,7 @@ func realtimeBroadcastRecord(app core.App, action string, record *core.Record, d
 							// for auth owner, superuser or manager
 							if collection.IsAuth() {
 								if isSameAuth(clientAuth, cleanRecord) ||
-									realtimeCanAccessRecord(accessCheckApp, cleanRecord, requestInfo, collection.ManageRule) {
+									realtimeCanAccessRecord(accessCheckApp, cleanRecord, requestInfo, collection.ManageRule, "") {
 									cleanRecord.IgnoreEmailVisibility(true)
 								}
 							}
@@ -731,16 +832,26 @@ func isSameAuth(authA, authB *core.Record) bool {
 	return authA.Id == authB.Id && authA.Collection().Id == authB.Collection().Id
 }
 
+// realtimeSubscriptionAccess defines the access rule and the
+// collection policies action of a record subscription topic.
+type realtimeSubscriptionAccess struct {
+	rule           *string
+	policiesAction string
+}
+
 // realtimeCanAccessRecord checks if the subscription client has access to the specified record model.
+//
+// policiesAction is the optional collection policies action to apply together with the access rule.
 func realtimeCanAccessRecord(
 	app core.App,
 	record *core.Record,
 	requestInfo *core.RequestInfo,
 	accessRule *string,
+	policiesAction string,
 ) bool {
 	// check the access rule
 	// ---
-	if ok, _ := app.CanAccessRecord(record, requestInfo, accessRule); !ok {
+	if ok, _ := app.CanAccessRecord(record, requestInfo, accessRule, policiesAction); !ok {
 		return false
 	}
 
@@ -0,0 +1,106 @@
+package apis
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// realtimeClientAuthInfo defines the public auth info of a single realtime client.
+type realtimeClientAuthInfo struct {
+	Id             string `json:"id"`
+	CollectionId   string `json:"collectionId"`
+	CollectionName string `json:"collectionName"`
+}
+
+// realtimeClientInfo defines the public info of a single realtime client.
+type realtimeClientInfo struct {
+	Auth        *realtimeClientAuthInfo `json:"auth"`
+	Id          string                  `json:"id"`
+	ConnectedAt types.DateTime          `json:"connectedAt"`
+	Topics      []string                `json:"topics"`
+}
+
+func newRealtimeClientInfo(client subscriptions.Client) *realtimeClientInfo {
+	info := &realtimeClientInfo{
+		Id:     client.Id(),
+		Topics: make([]string, 0, len(client.Subscriptions())),
+	}
+
+	info.ConnectedAt, _ = client.Get(RealtimeClientConnectedAtKey).(types.DateTime)
+
+	for topic := range client.Subscriptions() {
+		info.Topics = append(info.Topics, topic)
+	}
+	slices.Sort(info.Topics)
+
+	clientAuth, _ := client.Get(RealtimeClientAuthKey).(*core.Record)
+	if clientAuth != nil {
+		info.Auth = &realtimeClientAuthInfo{
+			Id:             clientAuth.Id,
+			CollectionId:   clientAuth.Collection().Id,
+			CollectionName: clientAuth.Collection().Name,
+		}
+	}
+
+	return info
+}
+
+// realtimeClientsList returns the list with all connected realtime clients
+// sorted by their connection time (oldest first).
+//
+// The result could be filtered by the client auth record id or collection
+// using the optional "authId" and "authCollection" query parameters.
+func realtimeClientsList(e *core.RequestEvent) error {
+	query := e.Request.URL.Query()
+	authId := query.Get("authId")
+	authCollection := query.Get("authCollection")
+
+	clients := e.App.SubscriptionsBroker().Clients()
+
+	result := make([]*realtimeClientInfo, 0, len(clients))
+
+	for _, client := range clients {
+		info := newRealtimeClientInfo(client)
+
+		if authId != "" && (info.Auth == nil || info.Auth.Id != authId) {
+			continue
+		}
+
+		if authCollection != "" && (info.Auth == nil ||
+			(info.Auth.CollectionId != authCollection && info.Auth.CollectionName != authCollection)) {
+			continue
+		}
+
+		result = append(result, info)
+	}
+
+	slices.SortFunc(result, func(a, b *realtimeClientInfo) int {
+		if c := a.ConnectedAt.Time().Compare(b.ConnectedAt.Time()); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Id, b.Id)
+	})
+
+	return e.JSON(http.StatusOK, result)
+}
+
+// realtimeClientDisconnect forcibly closes the specified realtime client connection.
+func realtimeClientDisconnect(e *core.RequestEvent) error {
+	clientId := e.Request.PathValue("clientId")
+
+	client, err := e.App.SubscriptionsBroker().ClientById(clientId)
+	if err != nil {
+		return e.NotFoundError("Missing or invalid client id.", err)
+	}
+
+	// unregister and discard the client, which in turn will
+	// close its channel and terminate the SSE connection
+	e.App.SubscriptionsBroker().Unregister(client.Id())
+
+	return e.NoContent(http.StatusNoContent)
+}
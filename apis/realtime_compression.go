@@ -0,0 +1,25 @@
+package apis
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+)
+
+const DefaultRealtimeCompressionMiddlewareId = "pbRealtimeCompression"
+
+// realtimeCompression returns a middleware that gzip compresses
+// the realtime SSE stream if enabled in the app settings.
+func realtimeCompression() *hook.Handler[*core.RequestEvent] {
+	gzip := Gzip()
+
+	return &hook.Handler[*core.RequestEvent]{
+		Id: DefaultRealtimeCompressionMiddlewareId,
+		Func: func(e *core.RequestEvent) error {
+			if !e.App.Settings().Realtime.Compression {
+				return e.Next()
+			}
+
+			return gzip.Func(e)
+		},
+	}
+}
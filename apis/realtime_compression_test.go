@@ -0,0 +1,105 @@
+package apis_test
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRealtimeConnectCompression(t *testing.T) {
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "disabled compression",
+			Method:         http.MethodGet,
+			URL:            "/api/realtime",
+			Headers:        map[string]string{"Accept-Encoding": "gzip"},
+			Timeout:        100 * time.Millisecond,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`event:PB_CONNECT`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnRealtimeConnectRequest": 1,
+				"OnRealtimeMessageSend":    1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := res.Header.Get("Content-Encoding"); v != "" {
+					t.Fatalf("Expected no Content-Encoding, got %q", v)
+				}
+			},
+		},
+		{
+			Name:           "enabled compression without client support",
+			Method:         http.MethodGet,
+			URL:            "/api/realtime",
+			Timeout:        100 * time.Millisecond,
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`event:PB_CONNECT`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnRealtimeConnectRequest": 1,
+				"OnRealtimeMessageSend":    1,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.Settings().Realtime.Compression = true
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := res.Header.Get("Content-Encoding"); v != "" {
+					t.Fatalf("Expected no Content-Encoding, got %q", v)
+				}
+			},
+		},
+		{
+			Name:           "enabled compression with client support",
+			Method:         http.MethodGet,
+			URL:            "/api/realtime",
+			Headers:        map[string]string{"Accept-Encoding": "gzip"},
+			Timeout:        100 * time.Millisecond,
+			ExpectedStatus: 200,
+			NotExpectedContent: []string{
+				`PB_CONNECT`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnRealtimeConnectRequest": 1,
+				"OnRealtimeMessageSend":    1,
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.Settings().Realtime.Compression = true
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := res.Header.Get("Content-Encoding"); v != "gzip" {
+					t.Fatalf("Expected gzip Content-Encoding, got %q", v)
+				}
+
+				gr, err := gzip.NewReader(res.Body)
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer gr.Close()
+
+				body, err := io.ReadAll(gr)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !strings.Contains(string(body), "event:PB_CONNECT") {
+					t.Fatalf("Expected the decompressed body to contain PB_CONNECT message, got\n%s", body)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
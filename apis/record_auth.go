@@ -36,6 +36,17 @@ func bindRecordAuthApi(app core.App, rg *router.RouterGroup[*core.RequestEvent])
 		collectionPathRateLimit("", "authWithOAuth2", "auth"),
 	)
 
+	sub.POST("/request-oauth2-device", recordRequestOAuth2Device).Bind(
+		collectionPathRateLimit("", "requestOAuth2Device"),
+	)
+	sub.POST("/auth-with-oauth2-device", recordAuthWithOAuth2Device).Bind(
+		collectionPathRateLimit("", "authWithOAuth2Device", "auth"),
+	)
+
+	sub.POST("/auth-with-ldap", recordAuthWithLDAP).Bind(
+		collectionPathRateLimit("", "authWithLDAP", "auth"),
+	)
+
 	sub.POST("/request-otp", recordRequestOTP).Bind(
 		collectionPathRateLimit("", "requestOTP"),
 	)
@@ -65,6 +76,16 @@ func bindRecordAuthApi(app core.App, rg *router.RouterGroup[*core.RequestEvent])
 		collectionPathRateLimit("", "confirmEmailChange"),
 	)
 
+	sub.POST("/scoped-token", recordScopedToken).Bind(
+		collectionPathRateLimit("", "scopedToken"),
+		RequireSameCollectionContextAuth(""),
+	)
+
+	sub.POST("/feed-token", recordFeedToken).Bind(
+		collectionPathRateLimit("", "feedToken"),
+		RequireSameCollectionContextAuth(""),
+	)
+
 	sub.POST("/impersonate/{id}", recordAuthImpersonate).Bind(RequireSuperuserAuth())
 }
 
@@ -5,6 +5,7 @@ import (
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
 	"github.com/pocketbase/pocketbase/tools/security"
 )
 
@@ -26,11 +27,21 @@ func recordConfirmEmailChange(e *core.RequestEvent) error {
 		return firstApiError(err, e.BadRequestError("An error occurred while validating the submitted data.", err))
 	}
 
-	authRecord, newEmail, err := form.parseToken()
+	authRecord, newEmail, stage, err := form.parseToken()
 	if err != nil {
 		return firstApiError(err, e.BadRequestError("Invalid or expired token.", err))
 	}
 
+	// the change was approved from the old email address
+	// -> send the regular new address confirmation email
+	if stage == core.EmailChangeStageApprove {
+		if err := mails.SendRecordChangeEmail(e.App, authRecord, newEmail); err != nil {
+			return firstApiError(err, e.BadRequestError("Failed to send the new email address confirmation.", err))
+		}
+
+		return e.NoContent(http.StatusAccepted)
+	}
+
 	event := new(core.RecordConfirmEmailChangeRequestEvent)
 	event.RequestEvent = e
 	event.Collection = collection
@@ -38,6 +49,8 @@ func recordConfirmEmailChange(e *core.RequestEvent) error {
 	event.NewEmail = newEmail
 
 	return e.App.OnRecordConfirmEmailChangeRequest().Trigger(event, func(e *core.RecordConfirmEmailChangeRequestEvent) error {
+		oldEmail := e.Record.Email()
+
 		e.Record.SetEmail(e.NewEmail)
 		e.Record.SetVerified(true)
 
@@ -46,6 +59,17 @@ func recordConfirmEmailChange(e *core.RequestEvent) error {
 		}
 
 		return execAfterSuccessTx(true, e.App, func() error {
+			if e.Collection.EmailChange.NotifyOldEmail && oldEmail != "" && oldEmail != e.Record.Email() {
+				if err := mails.SendRecordEmailChangedNotification(e.App, e.Record, oldEmail); err != nil {
+					e.App.Logger().Error(
+						"Failed to send email changed notification",
+						"error", err,
+						"recordId", e.Record.Id,
+						"collectionId", e.Record.Collection().Id,
+					)
+				}
+			}
+
 			return e.NoContent(http.StatusNoContent)
 		})
 	})
@@ -76,7 +100,7 @@ func (form *EmailChangeConfirmForm) validate() error {
 }
 
 func (form *EmailChangeConfirmForm) checkToken(value any) error {
-	_, _, err := form.parseToken()
+	_, _, _, err := form.parseToken()
 	return err
 }
 
@@ -86,7 +110,7 @@ func (form *EmailChangeConfirmForm) checkPassword(value any) error {
 		return nil // nothing to check
 	}
 
-	authRecord, _, _ := form.parseToken()
+	authRecord, _, _, _ := form.parseToken()
 	if authRecord == nil || !authRecord.ValidatePassword(v) {
 		return validation.NewError("validation_invalid_password", "Missing or invalid auth record password.")
 	}
@@ -94,29 +118,36 @@ func (form *EmailChangeConfirmForm) checkPassword(value any) error {
 	return nil
 }
 
-func (form *EmailChangeConfirmForm) parseToken() (*core.Record, string, error) {
+// parseToken parses and validates the form token and returns its
+// auth record, new email and email change stage (if any).
+func (form *EmailChangeConfirmForm) parseToken() (*core.Record, string, string, error) {
 	// check token payload
 	claims, _ := security.ParseUnverifiedJWT(form.Token)
 	newEmail, _ := claims[core.TokenClaimNewEmail].(string)
 	if newEmail == "" {
-		return nil, "", validation.NewError("validation_invalid_token_payload", "Invalid token payload - newEmail must be set.")
+		return nil, "", "", validation.NewError("validation_invalid_token_payload", "Invalid token payload - newEmail must be set.")
 	}
 
 	// ensure that there aren't other users with the new email
 	_, err := form.app.FindAuthRecordByEmail(form.collection, newEmail)
 	if err == nil {
-		return nil, "", validation.NewError("validation_existing_token_email", "The new email address is already registered: "+newEmail)
+		return nil, "", "", validation.NewError("validation_existing_token_email", "The new email address is already registered: "+newEmail)
 	}
 
 	// verify that the token is not expired and its signature is valid
 	authRecord, err := form.app.FindAuthRecordByToken(form.Token, core.TokenTypeEmailChange)
 	if err != nil {
-		return nil, "", validation.NewError("validation_invalid_token", "Invalid or expired token.")
+		return nil, "", "", validation.NewError("validation_invalid_token", "Invalid or expired token.")
 	}
 
 	if authRecord.Collection().Id != form.collection.Id {
-		return nil, "", validation.NewError("validation_token_collection_mismatch", "The provided token is for different auth collection.")
+		return nil, "", "", validation.NewError("validation_token_collection_mismatch", "The provided token is for different auth collection.")
+	}
+
+	stage, _ := claims[core.TokenClaimEmailChangeStage].(string)
+	if stage == "" && form.collection.EmailChange.RequireOldEmailApproval {
+		return nil, "", "", validation.NewError("validation_email_change_not_approved", "The email change must be approved from the old email address first.")
 	}
 
-	return authRecord, newEmail, nil
+	return authRecord, newEmail, stage, nil
 }
@@ -1,6 +1,7 @@
 package apis
 
 import (
+	"context"
 	"net/http"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
@@ -39,7 +40,14 @@ func recordRequestEmailChange(e *core.RequestEvent) error {
 	event.NewEmail = form.NewEmail
 
 	return e.App.OnRecordRequestEmailChangeRequest().Trigger(event, func(e *core.RecordRequestEmailChangeRequestEvent) error {
-		if err := mails.SendRecordChangeEmail(e.App, e.Record, e.NewEmail); err != nil {
+		var err error
+		if e.Collection.EmailChange.RequireOldEmailApproval {
+			// the change must be approved first from the old email address
+			err = mails.SendRecordChangeEmailApproval(e.App, e.Record, e.NewEmail)
+		} else {
+			err = mails.SendRecordChangeEmail(e.App, e.Record, e.NewEmail)
+		}
+		if err != nil {
 			return firstApiError(err, e.BadRequestError("Failed to request email change.", err))
 		}
 
@@ -73,6 +81,7 @@ func (form *emailChangeRequestForm) validate() error {
 			is.EmailFormat,
 			validation.NotIn(form.record.Email()),
 			validation.By(form.checkUniqueEmail),
+			validation.By(form.checkEmailDomain),
 		),
 	)
 }
@@ -90,3 +99,9 @@ func (form *emailChangeRequestForm) checkUniqueEmail(value any) error {
 
 	return nil
 }
+
+func (form *emailChangeRequestForm) checkEmailDomain(value any) error {
+	v, _ := value.(string)
+
+	return form.record.Collection().EmailDomains.CheckEmail(context.Background(), v)
+}
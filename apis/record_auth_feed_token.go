@@ -0,0 +1,95 @@
+package apis
+
+import (
+	"net/http"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const maxFeedTokenDuration = 5 * 365 * 86400
+
+// recordFeedToken mints a new long-lived token that could be used only for
+// reading the feeds of a specific collection (e.g. the iCalendar feed).
+func recordFeedToken(e *core.RequestEvent) error {
+	if e.Auth == nil {
+		return e.UnauthorizedError("Missing auth context.", nil)
+	}
+
+	form := &feedTokenForm{app: e.App}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("An error occurred while loading the submitted data.", err)
+	}
+	if err := form.validate(); err != nil {
+		return e.BadRequestError("An error occurred while validating the submitted data.", err)
+	}
+
+	token, err := e.Auth.NewFeedToken(form.collection.Id, time.Duration(form.Duration)*time.Second)
+	if err != nil {
+		return e.InternalServerError("Failed to generate feed token.", err)
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"token":      token,
+		"collection": form.collection.Id,
+	})
+}
+
+// -------------------------------------------------------------------
+
+type feedTokenForm struct {
+	app        core.App
+	collection *core.Collection
+
+	// Collection is the name or id of the feed collection.
+	Collection string `form:"collection" json:"collection"`
+
+	// Duration is the optional custom token duration in seconds.
+	Duration int64 `form:"duration" json:"duration"`
+}
+
+func (form *feedTokenForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Collection, validation.Required, validation.By(form.checkCollection)),
+		validation.Field(&form.Duration, validation.Min(0), validation.Max(maxFeedTokenDuration)),
+	)
+}
+
+func (form *feedTokenForm) checkCollection(value any) error {
+	v, _ := value.(string)
+
+	collection, err := form.app.FindCachedCollectionByNameOrId(v)
+	if err != nil || collection == nil {
+		return validation.NewError("validation_invalid_collection", "Missing or invalid collection.")
+	}
+
+	form.collection = collection
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+// findFeedAuthRecord returns the auth record of a collection feed request.
+//
+// Because the feed readers usually can't send custom headers, the feed token
+// could be specified with the "token" query parameter and when present
+// it takes precedence over the regular Authorization header.
+func findFeedAuthRecord(e *core.RequestEvent, collection *core.Collection) (*core.Record, error) {
+	token := e.Request.URL.Query().Get("token")
+	if token == "" {
+		return e.Auth, nil
+	}
+
+	record, err := e.App.FindAuthRecordByToken(token, core.TokenTypeFeed)
+	if err != nil {
+		return nil, e.UnauthorizedError("Invalid or expired feed token.", err)
+	}
+
+	if core.ParseUnverifiedTokenFeed(token) != collection.Id {
+		return nil, e.ForbiddenError("The feed token doesn't allow access to the requested collection.", nil)
+	}
+
+	return record, nil
+}
@@ -32,9 +32,14 @@ func recordAuthImpersonate(e *core.RequestEvent) error {
 		return e.BadRequestError("An error occurred while validating the submitted data.", err)
 	}
 
-	token, err := record.NewStaticAuthToken(time.Duration(form.Duration) * time.Second)
+	customClaims, err := recordAuthTokenClaims(e.App, record, false)
 	if err != nil {
-		e.InternalServerError("Failed to generate static auth token", err)
+		return e.InternalServerError("Failed to resolve the auth token claims.", err)
+	}
+
+	token, err := record.NewStaticAuthTokenWithClaims(time.Duration(form.Duration)*time.Second, customClaims)
+	if err != nil {
+		return e.InternalServerError("Failed to generate static auth token", err)
 	}
 
 	return recordAuthResponse(e, record, token, "", nil)
@@ -61,9 +61,10 @@ func TestRecordAuthImpersonate(t *testing.T) {
 				`"password"`,
 			},
 			ExpectedEvents: map[string]int{
-				"*":                   0,
-				"OnRecordAuthRequest": 1,
-				"OnRecordEnrich":      1,
+				"*":                       0,
+				"OnRecordAuthTokenClaims": 1,
+				"OnRecordAuthRequest":     1,
+				"OnRecordEnrich":          1,
 			},
 		},
 		{
@@ -96,9 +97,10 @@ func TestRecordAuthImpersonate(t *testing.T) {
 				`"record":{`,
 			},
 			ExpectedEvents: map[string]int{
-				"*":                   0,
-				"OnRecordAuthRequest": 1,
-				"OnRecordEnrich":      1,
+				"*":                       0,
+				"OnRecordAuthTokenClaims": 1,
+				"OnRecordAuthRequest":     1,
+				"OnRecordEnrich":          1,
 			},
 		},
 	}
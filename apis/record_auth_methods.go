@@ -21,6 +21,10 @@ type mfaResponse struct {
 	Duration int64 `json:"duration"` // in seconds
 }
 
+type ldapResponse struct {
+	Enabled bool `json:"enabled"`
+}
+
 type passwordResponse struct {
 	IdentityFields []string `json:"identityFields"`
 	Enabled        bool     `json:"enabled"`
@@ -54,6 +58,7 @@ type authMethodsResponse struct {
 	OAuth2   oauth2Response   `json:"oauth2"`
 	MFA      mfaResponse      `json:"mfa"`
 	OTP      otpResponse      `json:"otp"`
+	LDAP     ldapResponse     `json:"ldap"`
 
 	// legacy fields
 	// @todo remove after dropping v0.22 support
@@ -91,6 +96,9 @@ func recordAuthMethods(e *core.RequestEvent) error {
 		MFA: mfaResponse{
 			Enabled: collection.MFA.Enabled,
 		},
+		LDAP: ldapResponse{
+			Enabled: collection.LDAPAuth.Enabled,
+		},
 	}
 
 	if collection.PasswordAuth.Enabled {
@@ -114,8 +122,10 @@ func recordAuthMethods(e *core.RequestEvent) error {
 
 	result.OAuth2.Enabled = true
 
+	defaultHTTPClient := e.App.Settings().OAuth2HTTPClient
+
 	for _, config := range collection.OAuth2.Providers {
-		provider, err := config.InitProvider()
+		provider, err := config.InitProviderWithDefaults(defaultHTTPClient)
 		if err != nil {
 			e.App.Logger().Debug(
 				"Failed to setup OAuth2 provider",
@@ -152,12 +162,22 @@ func recordAuthMethods(e *core.RequestEvent) error {
 				oauth2.SetAuthURLParam("code_challenge", info.CodeChallenge),
 				oauth2.SetAuthURLParam("code_challenge_method", info.CodeChallengeMethod),
 			)
+
+			// bind the id_token nonce to the PKCE code verifier
+			if _, ok := provider.(auth.NonceValidator); ok {
+				urlOpts = append(urlOpts, oauth2.SetAuthURLParam("nonce", oauth2Nonce(info.CodeVerifier)))
+			}
 		}
 
 		info.AuthURL = provider.BuildAuthURL(
 			info.State,
 			urlOpts...,
-		) + "&redirect_uri=" // empty redirect_uri so that users can append their redirect url
+		)
+
+		// some providers don't have an auth url (e.g. WeChat Mini Program)
+		if info.AuthURL != "" {
+			info.AuthURL += "&redirect_uri=" // empty redirect_uri so that users can append their redirect url
+		}
 
 		info.AuthUrl = info.AuthURL
 
@@ -168,3 +188,12 @@ func recordAuthMethods(e *core.RequestEvent) error {
 
 	return e.JSON(http.StatusOK, result)
 }
+
+// oauth2Nonce returns the OpenID Connect id_token nonce
+// derived from the specified PKCE code verifier.
+//
+// Deriving the nonce from the code verifier allows validating it
+// on the code exchange without the need of extra server-side state.
+func oauth2Nonce(codeVerifier string) string {
+	return security.S256Challenge("nonce:" + codeVerifier)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/auth"
 )
 
 func TestRecordAuthMethodsList(t *testing.T) {
@@ -38,6 +39,7 @@ func TestRecordAuthMethodsList(t *testing.T) {
 				`"oauth2":{"providers":[],"enabled":false}`,
 				`"mfa":{"enabled":false,"duration":0}`,
 				`"otp":{"enabled":false,"duration":0}`,
+				`"ldap":{"enabled":false}`,
 			},
 			ExpectedEvents: map[string]int{"*": 0},
 		},
@@ -50,6 +52,7 @@ func TestRecordAuthMethodsList(t *testing.T) {
 				`"password":{"identityFields":["email","username"],"enabled":true}`,
 				`"mfa":{"enabled":true,"duration":1800}`,
 				`"otp":{"enabled":true,"duration":300}`,
+				`"ldap":{"enabled":false}`,
 				`"oauth2":{`,
 				`"providers":[{`,
 				`"name":"google"`,
@@ -64,6 +67,104 @@ func TestRecordAuthMethodsList(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{"*": 0},
 		},
+		{
+			Name:   "auth collection with enabled LDAP auth",
+			Method: http.MethodGet,
+			URL:    "/api/collections/users/auth-methods",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				usersCol.LDAPAuth = core.LDAPAuthConfig{
+					Enabled:      true,
+					URL:          "ldap://example.com",
+					BindDN:       "cn=admin,dc=example,dc=com",
+					BindPassword: "secret",
+					BaseDN:       "dc=example,dc=com",
+					UserFilter:   "(uid={username})",
+				}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"ldap":{"enabled":true}`,
+			},
+			NotExpectedContent: []string{
+				"ldap://example.com",
+				"secret",
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "auth collection with provider without auth url",
+			Method: http.MethodGet,
+			URL:    "/api/collections/users/auth-methods",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         auth.NameWeChatMiniProgram,
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"name":"wechatmp"`,
+				`"authURL":""`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "auth collection with nonce supporting provider",
+			Method: http.MethodGet,
+			URL:    "/api/collections/users/auth-methods",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         auth.NameLine,
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"name":"line"`,
+				`"codeChallenge":`,
+				`nonce=`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "auth collection without nonce supporting provider",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/users/auth-methods",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"name":"gitlab"`,
+			},
+			NotExpectedContent: []string{
+				`nonce=`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
 
 		// rate limit checks
 		// -----------------------------------------------------------
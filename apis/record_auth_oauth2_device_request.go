@@ -0,0 +1,83 @@
+package apis
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func recordRequestOAuth2Device(e *core.RequestEvent) error {
+	collection, err := findAuthCollection(e)
+	if err != nil {
+		return err
+	}
+
+	if !collection.OAuth2.Enabled {
+		return e.ForbiddenError("The collection is not configured to allow OAuth2 authentication.", nil)
+	}
+
+	form := new(recordOAuth2DeviceRequestForm)
+	form.collection = collection
+	if err = e.BindBody(form); err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
+	}
+	if err = form.validate(); err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while validating the submitted data.", err))
+	}
+
+	providerConfig, ok := collection.OAuth2.GetProviderConfig(form.Provider)
+	if !ok {
+		return e.InternalServerError("Missing or invalid provider config.", nil)
+	}
+
+	provider, err := providerConfig.InitProviderWithDefaults(e.App.Settings().OAuth2HTTPClient)
+	if err != nil {
+		return firstApiError(err, e.InternalServerError("Failed to init provider "+form.Provider, err))
+	}
+
+	if provider.DeviceAuthURL() == "" {
+		return e.BadRequestError("The provider doesn't support the OAuth2 device authorization grant.", nil)
+	}
+
+	ctx, cancel := context.WithTimeout(e.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	provider.SetContext(ctx)
+
+	deviceAuth, err := provider.StartDeviceAuth()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to start the OAuth2 device authorization.", err))
+	}
+
+	var expiresIn int64
+	if !deviceAuth.Expiry.IsZero() {
+		expiresIn = int64(time.Until(deviceAuth.Expiry).Seconds())
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"deviceCode":              deviceAuth.DeviceCode,
+		"userCode":                deviceAuth.UserCode,
+		"verificationURI":         deviceAuth.VerificationURI,
+		"verificationURIComplete": deviceAuth.VerificationURIComplete,
+		"expiresIn":               expiresIn,
+		"interval":                deviceAuth.Interval,
+	})
+}
+
+// -------------------------------------------------------------------
+
+type recordOAuth2DeviceRequestForm struct {
+	collection *core.Collection
+
+	// The name of the OAuth2 client provider (eg. "google")
+	Provider string `form:"provider" json:"provider"`
+}
+
+func (form *recordOAuth2DeviceRequestForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Provider, validation.Required, validation.Length(0, 100), validation.By(checkOAuth2ProviderName(form.collection))),
+	)
+}
@@ -0,0 +1,182 @@
+package apis_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/auth"
+	"golang.org/x/oauth2"
+)
+
+type oauth2MockDeviceProvider struct {
+	oauth2MockProvider
+
+	DeviceAuth *oauth2.DeviceAuthResponse
+}
+
+func (p *oauth2MockDeviceProvider) StartDeviceAuth(opts ...oauth2.AuthCodeOption) (*oauth2.DeviceAuthResponse, error) {
+	if p.DeviceAuth == nil {
+		return nil, errors.New("failed to start device auth")
+	}
+	return p.DeviceAuth, nil
+}
+
+func (p *oauth2MockDeviceProvider) PollDeviceToken(deviceAuth *oauth2.DeviceAuthResponse, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	switch deviceAuth.DeviceCode {
+	case "test_device_code":
+		return p.FetchToken("")
+	case "pending_device_code":
+		return nil, context.DeadlineExceeded
+	default:
+		return nil, errors.New("access_denied")
+	}
+}
+
+// setupOAuth2DeviceProvider registers and enables the "test" mock
+// device provider in the users collection.
+func setupOAuth2DeviceProvider(t testing.TB, app *tests.TestApp, deviceAuthURL string, authUser *auth.AuthUser) {
+	auth.Providers["test"] = func() auth.Provider {
+		return &oauth2MockDeviceProvider{
+			oauth2MockProvider: oauth2MockProvider{
+				AuthUser: authUser,
+				Token:    &oauth2.Token{AccessToken: "abc"},
+			},
+			DeviceAuth: &oauth2.DeviceAuthResponse{
+				DeviceCode:      "test_device_code",
+				UserCode:        "TEST-CODE",
+				VerificationURI: "https://example.com/device",
+				Expiry:          time.Now().Add(10 * time.Minute),
+				Interval:        5,
+			},
+		}
+	}
+
+	usersCol, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	usersCol.MFA.Enabled = false
+	usersCol.OAuth2.Enabled = true
+	usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+		Name:          "test",
+		ClientId:      "123",
+		ClientSecret:  "456",
+		DeviceAuthURL: deviceAuthURL,
+	}}
+	if err := app.Save(usersCol); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestRecordRequestOAuth2Device(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "not an auth collection",
+			Method:          http.MethodPost,
+			URL:             "/api/collections/demo1/request-oauth2-device",
+			Body:            strings.NewReader(`{"provider":"test"}`),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "auth collection with disabled oauth2",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/request-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", nil)
+
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				usersCol.OAuth2.Enabled = false
+
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "missing provider",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/request-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"missing"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", nil)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"provider":{"code":"validation_invalid_provider"`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "provider without device authorization support",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/request-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "", nil)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "valid request",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/request-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", nil)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"deviceCode":"test_device_code"`,
+				`"userCode":"TEST-CODE"`,
+				`"verificationURI":"https://example.com/device"`,
+				`"verificationURIComplete":""`,
+				`"expiresIn":`,
+				`"interval":5`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+
+		// rate limit checks
+		// -----------------------------------------------------------
+		{
+			Name:   "RateLimit rule - users:requestOAuth2Device",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/request-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.Settings().RateLimits.Enabled = true
+				app.Settings().RateLimits.Rules = []core.RateLimitRule{
+					{MaxRequests: 100, Label: "abc"},
+					{MaxRequests: 100, Label: "*:requestOAuth2Device"},
+					{MaxRequests: 0, Label: "users:requestOAuth2Device"},
+				}
+			},
+			ExpectedStatus:  429,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/pocketbase/pocketbase/mails"
 	"github.com/pocketbase/pocketbase/tools/routine"
 	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/sms"
 )
 
 func recordRequestOTP(e *core.RequestEvent) error {
@@ -32,7 +33,18 @@ func recordRequestOTP(e *core.RequestEvent) error {
 		return firstApiError(err, e.BadRequestError("An error occurred while validating the submitted data.", err))
 	}
 
-	record, err := e.App.FindAuthRecordByEmail(collection, form.Email)
+	var record *core.Record
+	if form.Phone != "" {
+		record, err = e.App.FindAuthRecordByPhone(collection, form.Phone)
+
+		// send OTPs only to verified phones or to the authenticated record own phone
+		// (aka. as a phone verification step)
+		if record != nil && !canSendPhoneOTP(e, record, form.Phone) {
+			record = nil
+		}
+	} else {
+		record, err = e.App.FindAuthRecordByEmail(collection, form.Email)
+	}
 
 	// ignore not found errors to allow custom record find implementations
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -54,7 +66,7 @@ func recordRequestOTP(e *core.RequestEvent) error {
 				"otpId": core.GenerateDefaultRandomId(),
 			})
 
-			return fmt.Errorf("missing or invalid %s OTP auth record with email %s", collection.Name, form.Email)
+			return fmt.Errorf("missing or invalid %s OTP auth record with identity %s", collection.Name, form.identity())
 		}
 
 		var otp *core.OTP
@@ -76,7 +88,7 @@ func recordRequestOTP(e *core.RequestEvent) error {
 					otp = otps[0] // otps are DESC sorted
 					e.App.Logger().Warn(
 						"Too many OTP requests - reusing the last issued",
-						"email", form.Email,
+						"identity", form.identity(),
 						"recordId", e.Record.Id,
 						"otpId", existingOTP.Id,
 					)
@@ -97,15 +109,24 @@ func recordRequestOTP(e *core.RequestEvent) error {
 				return err
 			}
 
-			// send OTP email
+			// send OTP email or SMS
 			// (in the background as a very basic timing attacks and emails enumeration protection)
 			// ---
-			routine.FireAndForget(func() {
-				err = mails.SendRecordOTP(originalApp, e.Record, otp.Id, e.Password)
-				if err != nil {
-					originalApp.Logger().Error("Failed to send OTP email", "error", errors.Join(err, originalApp.Delete(otp)))
-				}
-			})
+			if form.Phone != "" {
+				routine.FireAndForget(func() {
+					err = sendRecordOTPSMS(originalApp, otp, form.Phone, e.Password)
+					if err != nil {
+						originalApp.Logger().Error("Failed to send OTP SMS", "error", errors.Join(err, originalApp.Delete(otp)))
+					}
+				})
+			} else {
+				routine.FireAndForget(func() {
+					err = mails.SendRecordOTP(originalApp, e.Record, otp.Id, e.Password)
+					if err != nil {
+						originalApp.Logger().Error("Failed to send OTP email", "error", errors.Join(err, originalApp.Delete(otp)))
+					}
+				})
+			}
 		}
 
 		return execAfterSuccessTx(true, e.App, func() error {
@@ -118,10 +139,100 @@ func recordRequestOTP(e *core.RequestEvent) error {
 
 type createOTPForm struct {
 	Email string `form:"email" json:"email"`
+	Phone string `form:"phone" json:"phone"`
 }
 
 func (form createOTPForm) validate() error {
 	return validation.ValidateStruct(&form,
-		validation.Field(&form.Email, validation.Required, validation.Length(1, 255), is.EmailFormat),
+		validation.Field(
+			&form.Email,
+			validation.When(form.Phone == "", validation.Required),
+			validation.Length(1, 255),
+			is.EmailFormat,
+		),
+		validation.Field(
+			&form.Phone,
+			validation.When(form.Email != "", validation.Empty),
+			validation.By(validatePhone),
+		),
 	)
 }
+
+func (form createOTPForm) identity() string {
+	if form.Phone != "" {
+		return form.Phone
+	}
+
+	return form.Email
+}
+
+func validatePhone(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil
+	}
+
+	if _, err := sms.NormalizePhone(v); err != nil {
+		return validation.NewError("validation_invalid_phone", "Must be a valid phone number in international format (eg. +359888123456)")
+	}
+
+	return nil
+}
+
+// findRecordPhoneField returns the name of the first record phone field
+// whose value matches the provided phone (or empty string if none matches).
+func findRecordPhoneField(record *core.Record, phone string) string {
+	phone, err := sms.NormalizePhone(phone)
+	if err != nil {
+		return ""
+	}
+
+	for _, f := range record.Collection().Fields {
+		if f.Type() == core.FieldTypePhone && record.GetString(f.GetName()) == phone {
+			return f.GetName()
+		}
+	}
+
+	return ""
+}
+
+// canSendPhoneOTP reports whether an OTP could be sent to the specified record phone.
+//
+// The OTPs are sent only to the verified phones, with the exception of
+// the authenticated record own phones in order to allow their verification.
+func canSendPhoneOTP(e *core.RequestEvent, record *core.Record, phone string) bool {
+	field := findRecordPhoneField(record, phone)
+	if field == "" {
+		return false
+	}
+
+	if record.PhoneVerified(field) {
+		return true
+	}
+
+	return e.Auth != nil &&
+		e.Auth.Id == record.Id &&
+		e.Auth.Collection().Id == record.Collection().Id
+}
+
+// sendRecordOTPSMS sends the OTP password to the specified phone
+// and marks it as the OTP "sentTo" target.
+func sendRecordOTPSMS(app core.App, otp *core.OTP, phone string, pass string) error {
+	phone, err := sms.NormalizePhone(phone)
+	if err != nil {
+		return err
+	}
+
+	message := &sms.Message{
+		To:   phone,
+		Body: fmt.Sprintf("Your %s verification code is: %s", app.Settings().Meta.AppName, pass),
+	}
+
+	if err := app.NewSMSClient().Send(message); err != nil {
+		return err
+	}
+
+	otp.SetSentTo(message.To)
+
+	return app.Save(otp)
+}
@@ -23,8 +23,12 @@ func recordAuthRefresh(e *core.RequestEvent) error {
 		// skip token renewal if the token's payload doesn't explicitly allow it (e.g. impersonate tokens)
 		claims, _ := security.ParseUnverifiedJWT(token) //
 		if v, ok := claims[core.TokenClaimRefreshable]; ok && cast.ToBool(v) {
-			var tokenErr error
-			token, tokenErr = e.Record.NewAuthToken()
+			customClaims, tokenErr := recordAuthTokenClaims(e.App, e.Record, true)
+			if tokenErr != nil {
+				return e.InternalServerError("Failed to resolve the auth token claims.", tokenErr)
+			}
+
+			token, tokenErr = e.Record.NewAuthTokenWithClaims(customClaims, authTimeFromToken(token).Time())
 			if tokenErr != nil {
 				return e.InternalServerError("Failed to refresh auth token.", tokenErr)
 			}
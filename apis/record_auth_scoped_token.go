@@ -0,0 +1,186 @@
+package apis
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+const maxScopedTokenDuration = 3600
+
+// recordScopedToken mints a new short-lived token restricted to
+// a specific collection/record and actions from the current full auth token.
+func recordScopedToken(e *core.RequestEvent) error {
+	if e.Auth == nil {
+		return e.UnauthorizedError("Missing auth context.", nil)
+	}
+
+	form := &scopedTokenForm{app: e.App}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("An error occurred while loading the submitted data.", err)
+	}
+	if err := form.validate(); err != nil {
+		return e.BadRequestError("An error occurred while validating the submitted data.", err)
+	}
+
+	scope := core.TokenScope{
+		Collection: form.collection.Id,
+		Record:     form.Record,
+		Actions:    form.Actions,
+	}
+
+	token, err := e.Auth.NewScopedToken(scope, time.Duration(form.Duration)*time.Second)
+	if err != nil {
+		return e.InternalServerError("Failed to generate scoped token.", err)
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{
+		"token": token,
+		"scope": scope,
+	})
+}
+
+// -------------------------------------------------------------------
+
+type scopedTokenForm struct {
+	app        core.App
+	collection *core.Collection
+
+	// Collection is the name or id of the collection the token is restricted to.
+	Collection string `form:"collection" json:"collection"`
+
+	// Record is the optional id of the single record the token is restricted to.
+	Record string `form:"record" json:"record"`
+
+	// Actions is the list with the allowed token actions.
+	Actions []string `form:"actions" json:"actions"`
+
+	// Duration is the optional custom token duration in seconds.
+	Duration int64 `form:"duration" json:"duration"`
+}
+
+func (form *scopedTokenForm) validate() error {
+	allowedActions := core.ScopedTokenActions
+
+	// list and create are collection level actions
+	if form.Record != "" {
+		allowedActions = list.SubtractSlice(allowedActions, []string{
+			core.ScopedTokenActionList,
+			core.ScopedTokenActionCreate,
+		})
+	}
+
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Collection, validation.Required, validation.By(form.checkCollection)),
+		validation.Field(&form.Record, validation.Length(1, 255)),
+		validation.Field(&form.Actions, validation.Required, validation.Each(validation.In(list.ToInterfaceSlice(allowedActions)...))),
+		validation.Field(&form.Duration, validation.Min(0), validation.Max(maxScopedTokenDuration)),
+	)
+}
+
+func (form *scopedTokenForm) checkCollection(value any) error {
+	v, _ := value.(string)
+
+	collection, err := form.app.FindCachedCollectionByNameOrId(v)
+	if err != nil || collection == nil {
+		return validation.NewError("validation_invalid_collection", "Missing or invalid collection.")
+	}
+
+	form.collection = collection
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+// checkScopedToken ensures that the scoped auth token allows access
+// to the current request resource (records CRUD and files).
+func checkScopedToken(e *core.RequestEvent, token string) error {
+	scope, err := core.ParseUnverifiedTokenScope(token)
+	if err != nil {
+		return e.UnauthorizedError("Invalid scoped token.", err)
+	}
+
+	collectionIdOrName, recordId, action := scopedTokenRequestTarget(e)
+	if action != "" {
+		collection, _ := e.App.FindCachedCollectionByNameOrId(collectionIdOrName)
+		if collection != nil && scope.Allows(collection.Id, recordId, action) {
+			return nil
+		}
+	}
+
+	return e.ForbiddenError("The scoped token doesn't allow access to the requested resource.", nil)
+}
+
+// scopedTokenRequestTarget resolves the collection, record and scoped token action of the current request.
+//
+// Returns empty action if the request path is not one of the scoped token supported routes.
+func scopedTokenRequestTarget(e *core.RequestEvent) (collection string, recordId string, action string) {
+	parts := strings.Split(strings.Trim(e.Request.URL.Path, "/"), "/")
+	if len(parts) < 3 || parts[0] != "api" {
+		return "", "", ""
+	}
+
+	// /api/files/{collection}/{recordId}/{filename}
+	if parts[1] == "files" {
+		if len(parts) == 5 && e.Request.Method == http.MethodGet {
+			return parts[2], parts[3], core.ScopedTokenActionFile
+		}
+		return "", "", ""
+	}
+
+	// /api/collections/{collection}/records[/{id}]
+	if parts[1] != "collections" || len(parts) < 4 || parts[3] != "records" {
+		return "", "", ""
+	}
+
+	if len(parts) == 4 {
+		switch e.Request.Method {
+		case http.MethodGet:
+			return parts[2], "", core.ScopedTokenActionList
+		case http.MethodPost:
+			return parts[2], "", core.ScopedTokenActionCreate
+		}
+	} else if len(parts) == 5 {
+		switch e.Request.Method {
+		case http.MethodGet:
+			return parts[2], parts[4], core.ScopedTokenActionView
+		case http.MethodPatch:
+			return parts[2], parts[4], core.ScopedTokenActionUpdate
+		case http.MethodDelete:
+			return parts[2], parts[4], core.ScopedTokenActionDelete
+		}
+	}
+
+	return "", "", ""
+}
+
+// findFileTokenAuthRecord returns the auth record of the provided
+// file token or scoped token with "file" action for the specified record.
+func findFileTokenAuthRecord(app core.App, token string, record *core.Record) *core.Record {
+	authRecord, _ := app.FindAuthRecordByToken(token, core.TokenTypeFile, core.TokenTypeScoped)
+	if authRecord == nil {
+		return nil
+	}
+
+	if isScopedToken(token) {
+		scope, err := core.ParseUnverifiedTokenScope(token)
+		if err != nil || !scope.Allows(record.Collection().Id, record.Id, core.ScopedTokenActionFile) {
+			return nil
+		}
+	}
+
+	return authRecord
+}
+
+// isScopedToken reports whether the provided (already verified) token is a scoped auth token.
+func isScopedToken(token string) bool {
+	claims, _ := security.ParseUnverifiedJWT(token)
+
+	return claims[core.TokenClaimType] == core.TokenTypeScoped
+}
@@ -0,0 +1,287 @@
+package apis
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"reflect"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+func recordAuthWithLDAP(e *core.RequestEvent) error {
+	collection, err := findAuthCollection(e)
+	if err != nil {
+		return err
+	}
+
+	if !collection.LDAPAuth.Enabled {
+		return e.ForbiddenError("The collection is not configured to allow LDAP authentication.", nil)
+	}
+
+	form := &authWithLDAPForm{}
+	if err = e.BindBody(form); err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
+	}
+	if err = form.validate(); err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while validating the submitted data.", err))
+	}
+
+	e.Set(core.RequestEventKeyInfoContext, core.RequestInfoContextLDAP)
+
+	// verify the credentials against the LDAP directory
+	// ---------------------------------------------------------------
+
+	entry, err := func() (*ldap.Entry, error) {
+		ctx, cancel := context.WithTimeout(e.Request.Context(), 30*time.Second)
+		defer cancel()
+
+		return ldap.Authenticate(ctx, collection.LDAPAuth.Config(), form.Username, form.Password)
+	}()
+	if err != nil {
+		failure := &core.RecordAuthFailureEvent{
+			AuthMethod: core.MFAMethodLDAP,
+			Reason:     core.AuthFailureReasonInvalidPassword,
+			Identity:   form.Username,
+			Error:      err,
+		}
+
+		if !errors.Is(err, ldap.ErrInvalidCredentials) {
+			failure.Reason = core.AuthFailureReasonLDAPUnavailable
+
+			e.App.Logger().Warn(
+				"LDAP authentication failure",
+				slog.String("collection", collection.Name),
+				slog.String("error", err.Error()),
+			)
+		}
+
+		triggerRecordAuthFailure(e, collection, failure)
+
+		return e.BadRequestError("Failed to authenticate.", err)
+	}
+
+	providerId := collection.LDAPAuth.EntryId(entry)
+	if providerId == "" {
+		return e.BadRequestError("Failed to authenticate.", fmt.Errorf("missing LDAP entry id attribute %q", collection.LDAPAuth.IdAttribute))
+	}
+
+	var email string
+	if collection.LDAPAuth.EmailAttribute != "" {
+		email = entry.GetAttributeValue(collection.LDAPAuth.EmailAttribute)
+	}
+
+	var authRecord *core.Record
+
+	// check for existing relation with the auth collection
+	externalAuthRel, err := e.App.FindFirstExternalAuthByExpr(dbx.HashExp{
+		"collectionRef": collection.Id,
+		"provider":      core.ExternalAuthProviderLDAP,
+		"providerId":    providerId,
+	})
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return e.InternalServerError("Failed LDAP relation check.", err)
+	}
+
+	switch {
+	case err == nil && externalAuthRel != nil:
+		authRecord, err = e.App.FindRecordById(collection, externalAuthRel.RecordRef())
+		if err != nil {
+			return err
+		}
+	case email != "":
+		// look for an existing auth record by the LDAP entry email
+		authRecord, err = e.App.FindAuthRecordByEmail(collection.Id, email)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return e.InternalServerError("Failed LDAP auth record check.", err)
+		}
+	}
+
+	// ---------------------------------------------------------------
+
+	event := new(core.RecordAuthWithLDAPRequestEvent)
+	event.RequestEvent = e
+	event.Collection = collection
+	event.Username = form.Username
+	event.LDAPEntry = entry
+	event.CreateData = form.CreateData
+	event.Record = authRecord
+	event.IsNewRecord = authRecord == nil
+
+	return e.App.OnRecordAuthWithLDAPRequest().Trigger(event, func(e *core.RecordAuthWithLDAPRequestEvent) error {
+		if err := ldapSubmit(e, providerId, email, externalAuthRel); err != nil {
+			return firstApiError(err, e.BadRequestError("Failed to authenticate.", err))
+		}
+
+		meta := map[string]any{
+			"dn":    e.LDAPEntry.DN,
+			"isNew": e.IsNewRecord,
+		}
+
+		return RecordAuthResponse(e.RequestEvent, e.Record, core.MFAMethodLDAP, meta)
+	})
+}
+
+// -------------------------------------------------------------------
+
+type authWithLDAPForm struct {
+	// Additional data that will be used for creating a new auth record
+	// if a linked LDAP account doesn't exist.
+	CreateData map[string]any `form:"createData" json:"createData"`
+
+	Username string `form:"username" json:"username"`
+	Password string `form:"password" json:"password"`
+}
+
+func (form *authWithLDAPForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Username, validation.Required, validation.Length(1, 255)),
+		validation.Field(&form.Password, validation.Required, validation.Length(1, 255)),
+	)
+}
+
+func ldapSubmit(e *core.RecordAuthWithLDAPRequestEvent, providerId string, email string, optExternalAuth *core.ExternalAuth) error {
+	return e.App.RunInTransaction(func(txApp core.App) error {
+		if e.Record == nil {
+			// extra check to prevent creating a superuser record via
+			// LDAP in case the method is used by another action
+			if e.Collection.Name == core.CollectionNameSuperusers {
+				return errors.New("superusers are not allowed to sign-up with LDAP")
+			}
+
+			payload := maps.Clone(e.CreateData)
+			if payload == nil {
+				payload = map[string]any{}
+			}
+
+			// assign the LDAP email only if the user hasn't submitted one
+			if v, _ := payload[core.FieldNameEmail].(string); v == "" {
+				payload[core.FieldNameEmail] = email
+			}
+
+			// assign the mapped attributes
+			// (they have precedence over CreateData because the directory is the source of truth)
+			for name, attr := range e.Collection.LDAPAuth.MappedFields {
+				mappedField := e.Collection.Fields.GetByName(name)
+				values := e.LDAPEntry.GetAttributeValues(attr)
+				if mappedField != nil && len(values) > 0 {
+					payload[mappedField.GetName()] = ldapAttributeFieldValue(mappedField, values)
+				}
+			}
+
+			createdRecord, err := sendLDAPRecordCreateRequest(txApp, e, payload)
+			if err != nil {
+				return err
+			}
+
+			e.Record = createdRecord
+
+			if e.Record.Email() == email && !e.Record.Verified() {
+				// mark as verified as long as it matches the LDAP data (even if the email is empty)
+				e.Record.SetVerified(true)
+				if err := txApp.Save(e.Record); err != nil {
+					return err
+				}
+			}
+		} else {
+			var needUpdate bool
+
+			// set random password for users with unverified email
+			// (this is in case a malicious actor has registered previously with the user email)
+			if optExternalAuth == nil && e.Record.Email() != "" && !e.Record.Verified() {
+				e.Record.SetRandomPassword()
+				needUpdate = true
+			}
+
+			// update the existing auth record empty email if the LDAP entry has one
+			if e.Record.Email() == "" && email != "" {
+				e.Record.SetEmail(email)
+				needUpdate = true
+			}
+
+			// update the existing auth record verified state
+			// (only if the auth record doesn't have an email or the auth record email match with the LDAP one)
+			if !e.Record.Verified() && (e.Record.Email() == "" || e.Record.Email() == email) {
+				e.Record.SetVerified(true)
+				needUpdate = true
+			}
+
+			// sync the mapped attributes
+			for name, attr := range e.Collection.LDAPAuth.MappedFields {
+				mappedField := e.Collection.Fields.GetByName(name)
+				values := e.LDAPEntry.GetAttributeValues(attr)
+				if mappedField != nil && len(values) > 0 {
+					oldValue := e.Record.Get(mappedField.GetName())
+					e.Record.Set(mappedField.GetName(), ldapAttributeFieldValue(mappedField, values))
+					if !reflect.DeepEqual(oldValue, e.Record.Get(mappedField.GetName())) {
+						needUpdate = true
+					}
+				}
+			}
+
+			if needUpdate {
+				if err := txApp.Save(e.Record); err != nil {
+					return err
+				}
+			}
+		}
+
+		// create ExternalAuth relation if missing
+		if optExternalAuth == nil {
+			optExternalAuth = core.NewExternalAuth(txApp)
+			optExternalAuth.SetCollectionRef(e.Record.Collection().Id)
+			optExternalAuth.SetRecordRef(e.Record.Id)
+			optExternalAuth.SetProvider(core.ExternalAuthProviderLDAP)
+			optExternalAuth.SetProviderId(providerId)
+
+			if err := txApp.Save(optExternalAuth); err != nil {
+				return fmt.Errorf("failed to save linked rel: %w", err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// ldapAttributeFieldValue normalizes the mapped LDAP attribute values
+// according to the type of the mapped record field.
+func ldapAttributeFieldValue(field core.Field, values []string) any {
+	// single-valued attributes are assigned as they are (except for selects to filter the invalid options)
+	if _, ok := field.(*core.SelectField); !ok && len(values) == 1 {
+		return values[0]
+	}
+
+	return oauth2ClaimFieldValue(field, values)
+}
+
+func sendLDAPRecordCreateRequest(txApp core.App, e *core.RecordAuthWithLDAPRequestEvent, payload map[string]any) (*core.Record, error) {
+	ir := &core.InternalRequest{
+		Method: http.MethodPost,
+		URL:    "/api/collections/" + e.Collection.Name + "/records",
+		Body:   payload,
+	}
+
+	var createdRecord *core.Record
+	response, err := processInternalRequest(txApp, e.RequestEvent, ir, core.RequestInfoContextLDAP, func(data any) error {
+		createdRecord, _ = data.(*core.Record)
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Status != http.StatusOK || createdRecord == nil {
+		return nil, errors.New("failed to create LDAP auth record")
+	}
+
+	return createdRecord, nil
+}
@@ -0,0 +1,421 @@
+package apis_test
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordAuthWithLDAP(t *testing.T) {
+	t.Parallel()
+
+	server, err := tests.NewTestLDAPServer(
+		&tests.TestLDAPEntry{
+			DN:       "cn=service,dc=example,dc=com",
+			Password: "service_pass",
+		},
+		&tests.TestLDAPEntry{
+			DN:       "uid=john,ou=users,dc=example,dc=com",
+			Password: "john_pass",
+			Attributes: map[string][]string{
+				"uid":  {"john"},
+				"cn":   {"John Doe"},
+				"mail": {"john@example.com"},
+			},
+		},
+		&tests.TestLDAPEntry{
+			DN:       "uid=test,ou=users,dc=example,dc=com",
+			Password: "test_pass",
+			Attributes: map[string][]string{
+				"uid":  {"test"},
+				"cn":   {"Test User"},
+				"mail": {"test@example.com"},
+			},
+		},
+		&tests.TestLDAPEntry{
+			DN:       "uid=nomail,ou=users,dc=example,dc=com",
+			Password: "nomail_pass",
+			Attributes: map[string][]string{
+				"uid": {"nomail"},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	enableLDAP := func(url string) func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+		return func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+			users, err := app.FindCollectionByNameOrId("users")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			users.MFA.Enabled = false
+			users.LDAPAuth = core.LDAPAuthConfig{
+				Enabled:        true,
+				URL:            url,
+				BindDN:         "cn=service,dc=example,dc=com",
+				BindPassword:   "service_pass",
+				BaseDN:         "ou=users,dc=example,dc=com",
+				UserFilter:     "(uid={username})",
+				EmailAttribute: "mail",
+				MappedFields:   map[string]string{"name": "cn"},
+			}
+			if err := app.Save(users); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "disabled LDAP auth",
+			Method:          http.MethodPost,
+			URL:             "/api/collections/users/auth-with-ldap",
+			Body:            strings.NewReader(`{"username":"john","password":"john_pass"}`),
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "non-auth collection",
+			Method:          http.MethodPost,
+			URL:             "/api/collections/demo1/auth-with-ldap",
+			Body:            strings.NewReader(`{"username":"john","password":"john_pass"}`),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:           "empty body params",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/users/auth-with-ldap",
+			Body:           strings.NewReader(`{"username":"","password":""}`),
+			BeforeTestFunc: enableLDAP(server.URL()),
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{`,
+				`"username":{`,
+				`"password":{`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "invalid credentials",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-ldap",
+			Body:   strings.NewReader(`{"username":"john","password":"invalid"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableLDAP(server.URL())(t, app, e)
+
+				app.OnRecordAuthFailure("users").BindFunc(func(e *core.RecordAuthFailureEvent) error {
+					if e.AuthMethod != core.MFAMethodLDAP ||
+						e.Reason != core.AuthFailureReasonInvalidPassword ||
+						e.Identity != "john" ||
+						e.Record != nil {
+						t.Fatalf("Unexpected failure event data %#v", e)
+					}
+
+					// shouldn't change the response
+					return errors.New("test_hook_error")
+				})
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{}`,
+				`"message":"Failed to authenticate."`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                   0,
+				"OnRecordAuthFailure": 1,
+			},
+		},
+		{
+			Name:   "unavailable LDAP server",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-ldap",
+			Body:   strings.NewReader(`{"username":"john","password":"john_pass"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableLDAP("ldap://127.0.0.1:1")(t, app, e)
+
+				app.OnRecordAuthFailure("users").BindFunc(func(e *core.RecordAuthFailureEvent) error {
+					if e.Reason != core.AuthFailureReasonLDAPUnavailable || e.Error == nil {
+						t.Fatalf("Unexpected failure event data %#v", e)
+					}
+
+					return e.Next()
+				})
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{}`,
+				`"message":"Failed to authenticate."`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                   0,
+				"OnRecordAuthFailure": 1,
+			},
+		},
+		{
+			Name:   "OnRecordAuthWithLDAPRequest tx body write check",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-ldap",
+			Body:   strings.NewReader(`{"username":"john","password":"john_pass"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableLDAP(server.URL())(t, app, e)
+
+				app.OnRecordAuthWithLDAPRequest().BindFunc(func(e *core.RecordAuthWithLDAPRequestEvent) error {
+					original := e.App
+					return e.App.RunInTransaction(func(txApp core.App) error {
+						e.App = txApp
+						defer func() { e.App = original }()
+
+						if err := e.Next(); err != nil {
+							return err
+						}
+
+						return e.BadRequestError("TX_ERROR", nil)
+					})
+				})
+			},
+			ExpectedStatus:  400,
+			ExpectedEvents:  map[string]int{"OnRecordAuthWithLDAPRequest": 1},
+			ExpectedContent: []string{"TX_ERROR"},
+		},
+		{
+			Name:           "creating a new user",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/users/auth-with-ldap",
+			Body:           strings.NewReader(`{"username":"john","password":"john_pass"}`),
+			BeforeTestFunc: enableLDAP(server.URL()),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"meta":{`,
+				`"isNew":true`,
+				`"dn":"uid=john,ou=users,dc=example,dc=com"`,
+				`"email":"john@example.com"`,
+				`"name":"John Doe"`,
+				`"verified":true`,
+			},
+			NotExpectedContent: []string{
+				// hidden fields
+				`"tokenKey"`,
+				`"password"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                           0,
+				"OnRecordAuthTokenClaims":     1,
+				"OnRecordAuthWithLDAPRequest": 1,
+				"OnRecordAuthRequest":         1,
+				"OnRecordCreateRequest":       1,
+				"OnRecordEnrich":              2, // the auth response and from the create request
+				// ---
+				"OnModelCreate":              3, // record + authOrigins + externalAuths
+				"OnModelCreateExecute":       3,
+				"OnModelAfterCreateSuccess":  3,
+				"OnRecordCreate":             3,
+				"OnRecordCreateExecute":      3,
+				"OnRecordAfterCreateSuccess": 3,
+				// ---
+				"OnModelUpdate":              1, // created record verified state change
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				user, err := app.FindAuthRecordByEmail("users", "john@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				rel, err := app.FindFirstExternalAuthByExpr(dbx.HashExp{
+					"collectionRef": user.Collection().Id,
+					"recordRef":     user.Id,
+					"provider":      core.ExternalAuthProviderLDAP,
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if rel.ProviderId() != "uid=john,ou=users,dc=example,dc=com" {
+					t.Fatalf("Expected the entry DN as providerId, got %q", rel.ProviderId())
+				}
+			},
+		},
+		{
+			Name:           "creating a new user without email",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/users/auth-with-ldap",
+			Body:           strings.NewReader(`{"username":"nomail","password":"nomail_pass"}`),
+			BeforeTestFunc: enableLDAP(server.URL()),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":true`,
+				`"email":""`,
+				`"verified":true`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordAuthWithLDAPRequest": 1,
+				"OnRecordAuthRequest":         1,
+				"OnRecordCreateRequest":       1,
+			},
+		},
+		{
+			Name:           "linking an existing unverified user by email",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/users/auth-with-ldap",
+			Body:           strings.NewReader(`{"username":"test","password":"test_pass"}`),
+			BeforeTestFunc: enableLDAP(server.URL()),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":false`,
+				`"id":"4q1xlclmfloku33"`,
+				`"email":"test@example.com"`,
+				`"name":"Test User"`,
+				`"verified":true`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                           0,
+				"OnRecordAuthTokenClaims":     1,
+				"OnRecordAuthWithLDAPRequest": 1,
+				"OnRecordAuthRequest":         1,
+				"OnRecordEnrich":              1,
+				// ---
+				"OnModelCreate":              2, // externalAuths + authOrigins
+				"OnModelCreateExecute":       2,
+				"OnModelAfterCreateSuccess":  2,
+				"OnRecordCreate":             2,
+				"OnRecordCreateExecute":      2,
+				"OnRecordAfterCreateSuccess": 2,
+				// ---
+				"OnModelUpdate":              1, // the verified state, password and mapped name
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":  3,
+				"OnRecordValidate": 3,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// the password of the previously unverified user should be reset
+				if user.ValidatePassword("1234567890") {
+					t.Fatal("Expected the password to be changed")
+				}
+			},
+		},
+		{
+			Name:   "authenticating an already linked user",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-ldap",
+			Body:   strings.NewReader(`{"username":"john","password":"john_pass"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableLDAP(server.URL())(t, app, e)
+
+				user, err := app.FindAuthRecordByEmail("users", "test3@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				rel := core.NewExternalAuth(app)
+				rel.SetCollectionRef(user.Collection().Id)
+				rel.SetRecordRef(user.Id)
+				rel.SetProvider(core.ExternalAuthProviderLDAP)
+				rel.SetProviderId("uid=john,ou=users,dc=example,dc=com")
+				if err := app.Save(rel); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":false`,
+				`"id":"bgs820n361vj1qd"`,
+				`"email":"test3@example.com"`,
+				`"name":"John Doe"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                           0,
+				"OnRecordAuthTokenClaims":     1,
+				"OnRecordAuthWithLDAPRequest": 1,
+				"OnRecordAuthRequest":         1,
+				"OnRecordEnrich":              1,
+				// ---
+				"OnModelCreate":              1, // authOrigins
+				"OnModelCreateExecute":       1,
+				"OnModelAfterCreateSuccess":  1,
+				"OnRecordCreate":             1,
+				"OnRecordCreateExecute":      1,
+				"OnRecordAfterCreateSuccess": 1,
+				// ---
+				"OnModelUpdate":              1, // the mapped name field
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":  2,
+				"OnRecordValidate": 2,
+			},
+		},
+
+		// rate limit checks
+		// -----------------------------------------------------------
+		{
+			Name:   "RateLimit rule - users:authWithLDAP",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-ldap",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.Settings().RateLimits.Enabled = true
+				app.Settings().RateLimits.Rules = []core.RateLimitRule{
+					{MaxRequests: 100, Label: "abc"},
+					{MaxRequests: 100, Label: "*:authWithLDAP"},
+					{MaxRequests: 0, Label: "users:authWithLDAP"},
+				}
+			},
+			ExpectedStatus:  429,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "RateLimit rule - *:auth",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-ldap",
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.Settings().RateLimits.Enabled = true
+				app.Settings().RateLimits.Rules = []core.RateLimitRule{
+					{MaxRequests: 100, Label: "abc"},
+					{MaxRequests: 0, Label: "*:auth"},
+				}
+			},
+			ExpectedStatus:  429,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -6,15 +6,22 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"log/slog"
 	"maps"
 	"net/http"
+	"net/url"
+	"path"
+	"reflect"
+	"slices"
 	"strings"
 	"time"
 
+	"github.com/gabriel-vasile/mimetype"
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/core/validators"
 	"github.com/pocketbase/pocketbase/tools/auth"
 	"github.com/pocketbase/pocketbase/tools/dbutils"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
@@ -62,7 +69,7 @@ func recordAuthWithOAuth2(e *core.RequestEvent) error {
 		return e.InternalServerError("Missing or invalid provider config.", nil)
 	}
 
-	provider, err := providerConfig.InitProvider()
+	provider, err := providerConfig.InitProviderWithDefaults(e.App.Settings().OAuth2HTTPClient)
 	if err != nil {
 		return firstApiError(err, e.InternalServerError("Failed to init provider "+form.Provider, err))
 	}
@@ -73,21 +80,57 @@ func recordAuthWithOAuth2(e *core.RequestEvent) error {
 	provider.SetContext(ctx)
 	provider.SetRedirectURL(form.RedirectURL)
 
+	oauth2Failure := func(reason string, err error) {
+		triggerRecordAuthFailure(e, collection, &core.RecordAuthFailureEvent{
+			AuthMethod: core.MFAMethodOAuth2,
+			Reason:     reason,
+			Provider:   form.Provider,
+			Error:      err,
+		})
+	}
+
 	var opts []oauth2.AuthCodeOption
 
 	if provider.PKCE() {
+		if form.CodeVerifier == "" {
+			oauth2Failure(core.AuthFailureReasonOAuth2Verification, errors.New("missing PKCE code verifier"))
+			return e.BadRequestError("Missing required PKCE code verifier.", nil)
+		}
+
 		opts = append(opts, oauth2.SetAuthURLParam("code_verifier", form.CodeVerifier))
+
+		// validate the id_token nonce against the one generated from the code verifier
+		if nv, ok := provider.(auth.NonceValidator); ok {
+			nv.SetNonce(oauth2Nonce(form.CodeVerifier))
+		}
+	}
+
+	// verify the signed redirect query and load the Shopify shop from it
+	if shopify, ok := provider.(*auth.Shopify); ok {
+		queryKey := oauth2RedirectHMACQueryStoreKeyPrefix + form.Code
+		query, ok := e.App.Store().Get(queryKey).(url.Values)
+		if ok {
+			e.App.Store().Remove(queryKey)
+			if err := shopify.VerifyCallback(query); err != nil {
+				oauth2Failure(core.AuthFailureReasonOAuth2Verification, err)
+				return e.BadRequestError("Failed to verify the OAuth2 redirect data.", err)
+			}
+		} else {
+			e.App.Logger().Debug("Missing or already removed Shopify redirect query")
+		}
 	}
 
 	// fetch token
 	token, err := provider.FetchToken(form.Code, opts...)
 	if err != nil {
+		oauth2Failure(core.AuthFailureReasonOAuth2TokenExchange, err)
 		return firstApiError(err, e.BadRequestError("Failed to fetch OAuth2 token.", err))
 	}
 
 	// fetch external auth user
 	authUser, err := provider.FetchAuthUser(token)
 	if err != nil {
+		oauth2Failure(core.AuthFailureReasonOAuth2UserFetch, err)
 		return firstApiError(err, e.BadRequestError("Failed to fetch OAuth2 user.", err))
 	}
 
@@ -104,12 +147,38 @@ func recordAuthWithOAuth2(e *core.RequestEvent) error {
 		}
 	}
 
+	return oauth2AuthUserSubmit(e, collection, form.Provider, provider, authUser, form.CreateData, fallbackAuthRecord, provider.PKCE(), oauth2Failure)
+}
+
+// oauth2AuthUserSubmit maps the fetched OAuth2 user data, locates its linked
+// auth record (if any) and triggers the OnRecordAuthWithOAuth2Request hook
+// that authenticates (or creates) the auth record.
+func oauth2AuthUserSubmit(
+	e *core.RequestEvent,
+	collection *core.Collection,
+	providerName string,
+	provider auth.Provider,
+	authUser *auth.AuthUser,
+	createData map[string]any,
+	fallbackAuthRecord *core.Record,
+	pkceVerified bool,
+	oauth2Failure func(reason string, err error),
+) error {
+	// map the provider claims and groups (if configured)
+	providerConfig, _ := collection.OAuth2.GetProviderConfig(providerName)
+	if providerConfig.ClaimsMapping != nil {
+		if err := providerConfig.ClaimsMapping.Apply(authUser); err != nil {
+			oauth2Failure(core.AuthFailureReasonOAuth2Forbidden, err)
+			return e.ForbiddenError("The OAuth2 user is not allowed to authenticate.", err)
+		}
+	}
+
 	var authRecord *core.Record
 
 	// check for existing relation with the auth collection
 	externalAuthRel, err := e.App.FindFirstExternalAuthByExpr(dbx.HashExp{
-		"collectionRef": form.collection.Id,
-		"provider":      form.Provider,
+		"collectionRef": collection.Id,
+		"provider":      providerName,
 		"providerId":    authUser.Id,
 	})
 	if err != nil && !errors.Is(err, sql.ErrNoRows) {
@@ -118,16 +187,16 @@ func recordAuthWithOAuth2(e *core.RequestEvent) error {
 
 	switch {
 	case err == nil && externalAuthRel != nil:
-		authRecord, err = e.App.FindRecordById(form.collection, externalAuthRel.RecordRef())
+		authRecord, err = e.App.FindRecordById(collection, externalAuthRel.RecordRef())
 		if err != nil {
 			return err
 		}
-	case fallbackAuthRecord != nil && fallbackAuthRecord.Collection().Id == form.collection.Id:
+	case fallbackAuthRecord != nil && fallbackAuthRecord.Collection().Id == collection.Id:
 		// fallback to the logged auth record (if any)
 		authRecord = fallbackAuthRecord
 	case authUser.Email != "":
 		// look for an existing auth record by the external auth record's email
-		authRecord, err = e.App.FindAuthRecordByEmail(form.collection.Id, authUser.Email)
+		authRecord, err = e.App.FindAuthRecordByEmail(collection.Id, authUser.Email)
 		if err != nil && !errors.Is(err, sql.ErrNoRows) {
 			return e.InternalServerError("Failed OAuth2 auth record check.", err)
 		}
@@ -138,12 +207,16 @@ func recordAuthWithOAuth2(e *core.RequestEvent) error {
 	event := new(core.RecordAuthWithOAuth2RequestEvent)
 	event.RequestEvent = e
 	event.Collection = collection
-	event.ProviderName = form.Provider
+	event.ProviderName = providerName
 	event.ProviderClient = provider
 	event.OAuth2User = authUser
-	event.CreateData = form.CreateData
+	event.CreateData = createData
 	event.Record = authRecord
 	event.IsNewRecord = authRecord == nil
+	event.PKCEVerified = pkceVerified
+	if nv, ok := provider.(auth.NonceValidator); ok {
+		event.NonceVerified = nv.NonceValidated()
+	}
 
 	return e.App.OnRecordAuthWithOAuth2Request().Trigger(event, func(e *core.RecordAuthWithOAuth2RequestEvent) error {
 		if err := oauth2Submit(e, externalAuthRel); err != nil {
@@ -195,22 +268,26 @@ type recordOAuth2LoginForm struct {
 
 func (form *recordOAuth2LoginForm) validate() error {
 	return validation.ValidateStruct(form,
-		validation.Field(&form.Provider, validation.Required, validation.Length(0, 100), validation.By(form.checkProviderName)),
+		validation.Field(&form.Provider, validation.Required, validation.Length(0, 100), validation.By(checkOAuth2ProviderName(form.collection))),
 		validation.Field(&form.Code, validation.Required),
 		validation.Field(&form.RedirectURL, validation.Required),
 	)
 }
 
-func (form *recordOAuth2LoginForm) checkProviderName(value any) error {
-	name, _ := value.(string)
+// checkOAuth2ProviderName returns a validation rule that checks whether
+// the provider with the validated name is enabled for the specified collection.
+func checkOAuth2ProviderName(collection *core.Collection) validation.RuleFunc {
+	return func(value any) error {
+		name, _ := value.(string)
 
-	_, ok := form.collection.OAuth2.GetProviderConfig(name)
-	if !ok {
-		return validation.NewError("validation_invalid_provider", "Provider with name {{.name}} is missing or is not enabled.").
-			SetParams(map[string]any{"name": name})
-	}
+		_, ok := collection.OAuth2.GetProviderConfig(name)
+		if !ok {
+			return validation.NewError("validation_invalid_provider", "Provider with name {{.name}} is missing or is not enabled.").
+				SetParams(map[string]any{"name": name})
+		}
 
-	return nil
+		return nil
+	}
 }
 
 func oldCanAssignUsername(txApp core.App, collection *core.Collection, username string) bool {
@@ -274,25 +351,39 @@ func oauth2Submit(e *core.RecordAuthWithOAuth2RequestEvent, optExternalAuth *cor
 			}
 			if _, ok := payload[e.Collection.OAuth2.MappedFields.AvatarURL]; !ok &&
 				// no explicit avatar payload value and existing OAuth2 mapping
-				e.Collection.OAuth2.MappedFields.AvatarURL != "" &&
-				// non-empty OAuth2 avatar url
-				e.OAuth2User.AvatarURL != "" {
+				e.Collection.OAuth2.MappedFields.AvatarURL != "" {
 				mappedField := e.Collection.Fields.GetByName(e.Collection.OAuth2.MappedFields.AvatarURL)
-				if mappedField != nil && mappedField.Type() == core.FieldTypeFile {
+				if fileField, ok := mappedField.(*core.FileField); ok {
 					// download the avatar if the mapped field is a file
-					avatarFile, err := func() (*filesystem.File, error) {
-						ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
-						defer cancel()
-						return filesystem.NewFileFromURL(ctx, e.OAuth2User.AvatarURL)
-					}()
+					avatarFile, err := oauth2AvatarFile(e.ProviderClient, e.OAuth2User, fileField)
 					if err != nil {
 						txApp.Logger().Warn("Failed to retrieve OAuth2 avatar", slog.String("error", err.Error()))
-					} else {
-						payload[e.Collection.OAuth2.MappedFields.AvatarURL] = avatarFile
+					} else if avatarFile != nil {
+						payload[fileField.GetName()] = avatarFile
 					}
-				} else {
-					// otherwise - assign the url string
-					payload[e.Collection.OAuth2.MappedFields.AvatarURL] = e.OAuth2User.AvatarURL
+				} else if mappedField != nil && e.OAuth2User.AvatarURL != "" {
+					// otherwise - assign the non-empty url string
+					payload[mappedField.GetName()] = e.OAuth2User.AvatarURL
+				}
+			}
+
+			if _, ok := payload[e.Collection.OAuth2.MappedFields.Groups]; !ok &&
+				// no explicit groups payload value and existing OAuth2 mapping
+				e.Collection.OAuth2.MappedFields.Groups != "" &&
+				// groups are supported and fetched by the provider
+				e.OAuth2User.Groups != nil {
+				mappedField := e.Collection.Fields.GetByName(e.Collection.OAuth2.MappedFields.Groups)
+				if mappedField != nil {
+					payload[mappedField.GetName()] = oauth2GroupsFieldValue(mappedField, e.OAuth2User.Groups)
+				}
+			}
+
+			// assign the mapped claims
+			// (they have precedence over CreateData because the IdP is the source of truth, e.g. for roles)
+			for name, value := range e.OAuth2User.Claims {
+				mappedField := e.Collection.Fields.GetByName(name)
+				if mappedField != nil {
+					payload[mappedField.GetName()] = oauth2ClaimFieldValue(mappedField, value)
 				}
 			}
 
@@ -339,6 +430,30 @@ func oauth2Submit(e *core.RecordAuthWithOAuth2RequestEvent, optExternalAuth *cor
 				needUpdate = true
 			}
 
+			// sync the mapped OAuth2 user groups (if fetched by the provider)
+			if e.Collection.OAuth2.MappedFields.Groups != "" && e.OAuth2User.Groups != nil {
+				mappedField := e.Collection.Fields.GetByName(e.Collection.OAuth2.MappedFields.Groups)
+				if mappedField != nil {
+					oldGroups := e.Record.Get(mappedField.GetName())
+					e.Record.Set(mappedField.GetName(), oauth2GroupsFieldValue(mappedField, e.OAuth2User.Groups))
+					if !reflect.DeepEqual(oldGroups, e.Record.Get(mappedField.GetName())) {
+						needUpdate = true
+					}
+				}
+			}
+
+			// sync the mapped OAuth2 user claims
+			for name, value := range e.OAuth2User.Claims {
+				mappedField := e.Collection.Fields.GetByName(name)
+				if mappedField != nil {
+					oldValue := e.Record.Get(mappedField.GetName())
+					e.Record.Set(mappedField.GetName(), oauth2ClaimFieldValue(mappedField, value))
+					if !reflect.DeepEqual(oldValue, e.Record.Get(mappedField.GetName())) {
+						needUpdate = true
+					}
+				}
+			}
+
 			if needUpdate {
 				if err := txApp.Save(e.Record); err != nil {
 					return err
@@ -353,7 +468,23 @@ func oauth2Submit(e *core.RecordAuthWithOAuth2RequestEvent, optExternalAuth *cor
 			optExternalAuth.SetRecordRef(e.Record.Id)
 			optExternalAuth.SetProvider(e.ProviderName)
 			optExternalAuth.SetProviderId(e.OAuth2User.Id)
+		}
+
+		// store the latest OAuth2 tokens
+		// (some providers return a refresh token only on the first authorization)
+		oldAccessToken := optExternalAuth.AccessToken()
+		oldRefreshToken := optExternalAuth.RefreshToken()
+		oldExpiry := optExternalAuth.Expiry()
+		optExternalAuth.SetAccessToken(e.OAuth2User.AccessToken)
+		optExternalAuth.SetExpiry(e.OAuth2User.Expiry)
+		if e.OAuth2User.RefreshToken != "" {
+			optExternalAuth.SetRefreshToken(e.OAuth2User.RefreshToken)
+		}
 
+		if optExternalAuth.IsNew() ||
+			oldAccessToken != optExternalAuth.AccessToken() ||
+			oldRefreshToken != optExternalAuth.RefreshToken() ||
+			!oldExpiry.Equal(optExternalAuth.Expiry()) {
 			if err := txApp.Save(optExternalAuth); err != nil {
 				return fmt.Errorf("failed to save linked rel: %w", err)
 			}
@@ -363,6 +494,138 @@ func oauth2Submit(e *core.RecordAuthWithOAuth2RequestEvent, optExternalAuth *cor
 	})
 }
 
+// oauth2AvatarFile downloads the OAuth2 user avatar for the specified file field.
+//
+// The avatar is fetched with the OAuth2 access token if the provider implements
+// [auth.AvatarFetcher], otherwise it is downloaded from the OAuth2 user AvatarURL.
+//
+// The downloaded avatar must be an image that satisfies the field MaxSize and MimeTypes.
+// Returns nil file if there is no avatar to download.
+func oauth2AvatarFile(provider auth.Provider, authUser *auth.AuthUser, field *core.FileField) (*filesystem.File, error) {
+	maxSize := field.MaxSize
+	if maxSize <= 0 {
+		maxSize = core.DefaultFileFieldMaxSize
+	}
+
+	var data []byte
+	var name string
+	var err error
+
+	if fetcher, ok := provider.(auth.AvatarFetcher); ok {
+		data, err = fetcher.FetchAvatar(&oauth2.Token{
+			AccessToken:  authUser.AccessToken,
+			RefreshToken: authUser.RefreshToken,
+			Expiry:       authUser.Expiry.Time(),
+		})
+		name = "avatar"
+	}
+
+	// fallback to the avatar url (if any)
+	if len(data) == 0 && authUser.AvatarURL != "" {
+		data, err = downloadOAuth2Avatar(authUser.AvatarURL, maxSize)
+		name = path.Base(authUser.AvatarURL)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if int64(len(data)) > maxSize {
+		return nil, fmt.Errorf("the avatar exceeds the max allowed size of %d bytes", maxSize)
+	}
+
+	mt := mimetype.Detect(data)
+	if !strings.HasPrefix(mt.String(), "image/") {
+		return nil, fmt.Errorf("the avatar must be an image, got %q", mt.String())
+	}
+
+	if path.Ext(name) == "" {
+		name += mt.Extension()
+	}
+
+	file, err := filesystem.NewFileFromBytes(data, name)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(field.MimeTypes) > 0 {
+		if err := validators.UploadedFileMimeType(field.MimeTypes)(file); err != nil {
+			return nil, err
+		}
+	}
+
+	return file, nil
+}
+
+// downloadOAuth2Avatar downloads the specified avatar url
+// reading at most maxSize+1 bytes from the response body.
+func downloadOAuth2Avatar(avatarURL string, maxSize int64) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, avatarURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 399 {
+		return nil, fmt.Errorf("failed to download url %s (%d)", avatarURL, res.StatusCode)
+	}
+
+	return io.ReadAll(io.LimitReader(res.Body, maxSize+1))
+}
+
+// oauth2GroupsFieldValue normalizes the OAuth2 user groups
+// according to the type of the mapped record field.
+func oauth2GroupsFieldValue(field core.Field, groups []string) any {
+	switch f := field.(type) {
+	case *core.SelectField:
+		// keep only the groups that are valid select options
+		result := make([]string, 0, len(groups))
+		for _, group := range groups {
+			if slices.Contains(f.Values, group) {
+				result = append(result, group)
+			}
+		}
+		return result
+	case *core.TextField:
+		return strings.Join(groups, ",")
+	default:
+		return groups
+	}
+}
+
+// oauth2ClaimFieldValue normalizes the mapped OAuth2 user claim value
+// according to the type of the mapped record field.
+func oauth2ClaimFieldValue(field core.Field, value any) any {
+	values, ok := value.([]string)
+	if !ok {
+		return value
+	}
+
+	// store only the first (aka. highest priority) value for single select fields
+	if f, ok := field.(*core.SelectField); ok && !f.IsMultiple() {
+		for _, v := range values {
+			if slices.Contains(f.Values, v) {
+				return v
+			}
+		}
+		return ""
+	}
+
+	return oauth2GroupsFieldValue(field, values)
+}
+
 func sendOAuth2RecordCreateRequest(txApp core.App, e *core.RecordAuthWithOAuth2RequestEvent, payload map[string]any) (*core.Record, error) {
 	ir := &core.InternalRequest{
 		Method: http.MethodPost,
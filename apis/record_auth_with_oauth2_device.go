@@ -0,0 +1,126 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"golang.org/x/oauth2"
+)
+
+// oauth2DevicePollTimeout is the max duration of a single device token polling request.
+//
+// If the user hasn't completed the device authorization within it,
+// the client is expected to resend the request.
+const oauth2DevicePollTimeout = 60 * time.Second
+
+func recordAuthWithOAuth2Device(e *core.RequestEvent) error {
+	collection, err := findAuthCollection(e)
+	if err != nil {
+		return err
+	}
+
+	if !collection.OAuth2.Enabled {
+		return e.ForbiddenError("The collection is not configured to allow OAuth2 authentication.", nil)
+	}
+
+	var fallbackAuthRecord *core.Record
+	if e.Auth != nil && e.Auth.Collection().Id == collection.Id {
+		fallbackAuthRecord = e.Auth
+	}
+
+	e.Set(core.RequestEventKeyInfoContext, core.RequestInfoContextOAuth2)
+
+	form := new(recordOAuth2DeviceLoginForm)
+	form.collection = collection
+	if err = e.BindBody(form); err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while loading the submitted data.", err))
+	}
+	if err = form.validate(); err != nil {
+		return firstApiError(err, e.BadRequestError("An error occurred while validating the submitted data.", err))
+	}
+
+	providerConfig, ok := collection.OAuth2.GetProviderConfig(form.Provider)
+	if !ok {
+		return e.InternalServerError("Missing or invalid provider config.", nil)
+	}
+
+	provider, err := providerConfig.InitProviderWithDefaults(e.App.Settings().OAuth2HTTPClient)
+	if err != nil {
+		return firstApiError(err, e.InternalServerError("Failed to init provider "+form.Provider, err))
+	}
+
+	oauth2Failure := func(reason string, err error) {
+		triggerRecordAuthFailure(e, collection, &core.RecordAuthFailureEvent{
+			AuthMethod: core.MFAMethodOAuth2,
+			Reason:     reason,
+			Provider:   form.Provider,
+			Error:      err,
+		})
+	}
+
+	// poll for the device token
+	token, err := func() (*oauth2.Token, error) {
+		ctx, cancel := context.WithTimeout(e.Request.Context(), oauth2DevicePollTimeout)
+		defer cancel()
+
+		provider.SetContext(ctx)
+
+		return provider.PollDeviceToken(&oauth2.DeviceAuthResponse{
+			DeviceCode: form.DeviceCode,
+			Interval:   form.Interval,
+		})
+	}()
+	if err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return e.BadRequestError("The OAuth2 device authorization is still pending. Please try again.", err)
+		}
+
+		oauth2Failure(core.AuthFailureReasonOAuth2TokenExchange, err)
+		return firstApiError(err, e.BadRequestError("Failed to fetch OAuth2 token.", err))
+	}
+
+	ctx, cancel := context.WithTimeout(e.Request.Context(), 30*time.Second)
+	defer cancel()
+
+	provider.SetContext(ctx)
+
+	// fetch external auth user
+	authUser, err := provider.FetchAuthUser(token)
+	if err != nil {
+		oauth2Failure(core.AuthFailureReasonOAuth2UserFetch, err)
+		return firstApiError(err, e.BadRequestError("Failed to fetch OAuth2 user.", err))
+	}
+
+	return oauth2AuthUserSubmit(e, collection, form.Provider, provider, authUser, form.CreateData, fallbackAuthRecord, false, oauth2Failure)
+}
+
+// -------------------------------------------------------------------
+
+type recordOAuth2DeviceLoginForm struct {
+	collection *core.Collection
+
+	// Additional data that will be used for creating a new auth record
+	// if an existing OAuth2 account doesn't exist.
+	CreateData map[string]any `form:"createData" json:"createData"`
+
+	// The name of the OAuth2 client provider (eg. "google")
+	Provider string `form:"provider" json:"provider"`
+
+	// The device code returned from the device authorization request.
+	DeviceCode string `form:"deviceCode" json:"deviceCode"`
+
+	// The optional polling interval in seconds returned from the device
+	// authorization request (if zero, fallbacks to 5 seconds).
+	Interval int64 `form:"interval" json:"interval"`
+}
+
+func (form *recordOAuth2DeviceLoginForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Provider, validation.Required, validation.Length(0, 100), validation.By(checkOAuth2ProviderName(form.collection))),
+		validation.Field(&form.DeviceCode, validation.Required, validation.Length(0, 2000)),
+		validation.Field(&form.Interval, validation.Min(0), validation.Max(60)),
+	)
+}
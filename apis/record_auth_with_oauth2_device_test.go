@@ -0,0 +1,215 @@
+package apis_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/auth"
+)
+
+func TestRecordAuthWithOAuth2Device(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "not an auth collection",
+			Method:          http.MethodPost,
+			URL:             "/api/collections/demo1/auth-with-oauth2-device",
+			Body:            strings.NewReader(`{"provider":"test","deviceCode":"test_device_code"}`),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "empty body",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2-device",
+			Body:   strings.NewReader(``),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", nil)
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"provider":{"code":"validation_required"`,
+				`"deviceCode":{"code":"validation_required"`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:   "invalid interval",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test","deviceCode":"test_device_code","interval":-1}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", nil)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"interval":{"code":"validation_min_greater_equal_than_required"`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "denied device authorization",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test","deviceCode":"invalid"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", nil)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"message":"Failed to fetch OAuth2 token."`},
+			ExpectedEvents: map[string]int{
+				"*":                   0,
+				"OnRecordAuthFailure": 1,
+			},
+		},
+		{
+			Name:   "pending device authorization",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test","deviceCode":"pending_device_code"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", nil)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"message":"The OAuth2 device authorization is still pending. Please try again."`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "failed OAuth2 user fetch",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test","deviceCode":"test_device_code"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", nil)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"message":"Failed to fetch OAuth2 user."`},
+			ExpectedEvents: map[string]int{
+				"*":                   0,
+				"OnRecordAuthFailure": 1,
+			},
+		},
+		{
+			Name:   "existing auth record with matching OAuth2 user email",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test","deviceCode":"test_device_code","interval":1}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", &auth.AuthUser{
+					Id:    "test_id",
+					Email: "test2@example.com",
+				})
+
+				app.OnRecordAuthWithOAuth2Request().BindFunc(func(e *core.RecordAuthWithOAuth2RequestEvent) error {
+					if e.ProviderName != "test" || e.PKCEVerified || e.IsNewRecord {
+						t.Fatalf("Unexpected OAuth2 event data %#v", e)
+					}
+
+					return e.Next()
+				})
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				ea, err := app.FindFirstExternalAuthByExpr(dbx.HashExp{
+					"provider":   "test",
+					"providerId": "test_id",
+				})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if ea.RecordRef() != "oap640cot4yru2s" {
+					t.Fatalf("Expected the external auth to be linked to %q, got %q", "oap640cot4yru2s", ea.RecordRef())
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"id":"oap640cot4yru2s"`,
+				`"email":"test2@example.com"`,
+				`"isNew":false`,
+			},
+			NotExpectedContent: []string{
+				// hidden fields
+				`"tokenKey"`,
+				`"password"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordAuthFailure":           0,
+			},
+		},
+		{
+			Name:   "new auth record",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test","deviceCode":"test_device_code","createData":{"name":"test_name"}}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setupOAuth2DeviceProvider(t, app, "https://example.com/device", &auth.AuthUser{
+					Id:    "test_id",
+					Email: "oauth2_device@example.com",
+				})
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"email":"oauth2_device@example.com"`,
+				`"name":"test_name"`,
+				`"verified":true`,
+				`"isNew":true`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordCreateRequest":         1,
+			},
+		},
+
+		// rate limit checks
+		// -----------------------------------------------------------
+		{
+			Name:   "RateLimit rule - users:authWithOAuth2Device",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test","deviceCode":"test_device_code"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.Settings().RateLimits.Enabled = true
+				app.Settings().RateLimits.Rules = []core.RateLimitRule{
+					{MaxRequests: 100, Label: "abc"},
+					{MaxRequests: 100, Label: "*:authWithOAuth2Device"},
+					{MaxRequests: 0, Label: "users:authWithOAuth2Device"},
+				}
+			},
+			ExpectedStatus:  429,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "RateLimit rule - users:auth",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2-device",
+			Body:   strings.NewReader(`{"provider":"test","deviceCode":"test_device_code"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.Settings().RateLimits.Enabled = true
+				app.Settings().RateLimits.Rules = []core.RateLimitRule{
+					{MaxRequests: 100, Label: "abc"},
+					{MaxRequests: 100, Label: "*:authWithOAuth2Device"},
+					{MaxRequests: 0, Label: "users:auth"},
+				}
+			},
+			ExpectedStatus:  429,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
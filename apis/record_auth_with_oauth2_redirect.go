@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -16,6 +17,7 @@ const (
 	oauth2RedirectFailurePath             string = "../_/#/auth/oauth2-redirect-failure"
 	oauth2RedirectSuccessPath             string = "../_/#/auth/oauth2-redirect-success"
 	oauth2RedirectAppleNameStoreKeyPrefix string = "@redirect_name_"
+	oauth2RedirectHMACQueryStoreKeyPrefix string = "@redirect_hmac_query_"
 )
 
 type oauth2RedirectData struct {
@@ -25,6 +27,9 @@ type oauth2RedirectData struct {
 
 	// returned by Apple only
 	AppleUser string `form:"user" json:"-"`
+
+	// the raw GET redirect query (used for the Shopify HMAC verification)
+	hmacQuery url.Values
 }
 
 func oauth2SubscriptionRedirect(e *core.RequestEvent) error {
@@ -45,6 +50,7 @@ func oauth2SubscriptionRedirect(e *core.RequestEvent) error {
 		data.State = query.Get("state")
 		data.Code = query.Get("code")
 		data.Error = query.Get("error")
+		data.hmacQuery = query
 	}
 
 	if data.State == "" {
@@ -73,6 +79,16 @@ func oauth2SubscriptionRedirect(e *core.RequestEvent) error {
 		}
 	}
 
+	// temporary store the signed redirect query so that its HMAC
+	// could be later verified with the authWithOAuth2 call (e.g. Shopify)
+	if data.hmacQuery.Has("hmac") && data.Error == "" && data.Code != "" {
+		storeRedirectHMACQuery(
+			e.App,
+			oauth2RedirectHMACQueryStoreKeyPrefix+data.Code,
+			data.hmacQuery,
+		)
+	}
+
 	encodedData, err := json.Marshal(data)
 	if err != nil {
 		e.App.Logger().Debug("Failed to marshalize OAuth2 redirect data", "error", err)
@@ -94,6 +110,21 @@ func oauth2SubscriptionRedirect(e *core.RequestEvent) error {
 	return e.Redirect(redirectStatusCode, oauth2RedirectSuccessPath)
 }
 
+// storeRedirectHMACQuery temporary stores the signed redirect query in the app.Store.
+func storeRedirectHMACQuery(app core.App, queryKey string, query url.Values) {
+	// just in case to prevent storing large strings in memory
+	if len(queryKey) > 1000 || len(query.Encode()) > 5000 {
+		app.Logger().Debug("Skip storing too large OAuth2 redirect HMAC query")
+		return
+	}
+
+	// store (and remove)
+	app.Store().Set(queryKey, query)
+	time.AfterFunc(1*time.Minute, func() {
+		app.Store().Remove(queryKey)
+	})
+}
+
 // parseAndStoreAppleRedirectName extracts the first and last name
 // from serializedNameData and temporary store them in the app.Store.
 //
@@ -16,9 +16,9 @@ import (
 func TestRecordAuthWithOAuth2Redirect(t *testing.T) {
 	t.Parallel()
 
-	clientStubs := make([]map[string]subscriptions.Client, 0, 10)
+	clientStubs := make([]map[string]subscriptions.Client, 0, 11)
 
-	for i := 0; i < 10; i++ {
+	for i := 0; i < 11; i++ {
 		c1 := subscriptions.NewDefaultClient()
 
 		c2 := subscriptions.NewDefaultClient()
@@ -335,6 +335,26 @@ func TestRecordAuthWithOAuth2Redirect(t *testing.T) {
 				}
 			},
 		},
+		{
+			Name:   "client with @oauth2 subscription and signed query",
+			Method: http.MethodGet,
+			URL:    "/api/oauth2-redirect?code=123&shop=example.myshopify.com&hmac=abc&state=" + clientStubs[10]["c3"].Id(),
+			BeforeTestFunc: beforeTestFunc(clientStubs[10], map[string][]string{
+				"c3": {`"state":"` + clientStubs[10]["c3"].Id(), `"code":"123"`},
+			}),
+			ExpectedStatus: http.StatusTemporaryRedirect,
+			ExpectedEvents: map[string]int{"*": 0},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				app.Store().Get("cancelFunc").(context.CancelFunc)()
+
+				checkSuccessRedirect(t, app, res)
+
+				storedQuery, _ := app.Store().Get("@redirect_hmac_query_123").(url.Values)
+				if storedQuery.Get("hmac") != "abc" || storedQuery.Get("shop") != "example.myshopify.com" {
+					t.Fatalf("Expected the signed redirect query to be stored, got %v", storedQuery)
+				}
+			},
+		},
 	}
 
 	for _, scenario := range scenarios {
@@ -7,14 +7,18 @@ import (
 	"image/png"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
 	"strings"
 	"testing"
 	"time"
 
+	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/auth"
 	"github.com/pocketbase/pocketbase/tools/dbutils"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
 	"golang.org/x/oauth2"
 )
 
@@ -41,14 +45,46 @@ func (p *oauth2MockProvider) FetchAuthUser(token *oauth2.Token) (*auth.AuthUser,
 	return p.AuthUser, nil
 }
 
+type oauth2MockNonceProvider struct {
+	oauth2MockProvider
+
+	nonce string
+}
+
+func (p *oauth2MockNonceProvider) SetNonce(nonce string) {
+	p.nonce = nonce
+}
+
+func (p *oauth2MockNonceProvider) NonceValidated() bool {
+	return p.nonce != ""
+}
+
+// tinyPNG returns a tiny 1x1 png image.
+func tinyPNG() []byte {
+	buf := new(bytes.Buffer)
+	png.Encode(buf, image.Rect(0, 0, 1, 1))
+	return buf.Bytes()
+}
+
+type oauth2MockAvatarProvider struct {
+	oauth2MockProvider
+
+	Avatar []byte
+}
+
+func (p *oauth2MockAvatarProvider) FetchAvatar(token *oauth2.Token) ([]byte, error) {
+	if token.AccessToken != p.Token.AccessToken {
+		return nil, errors.New("invalid access token")
+	}
+	return p.Avatar, nil
+}
+
 func TestRecordAuthWithOAuth2(t *testing.T) {
 	t.Parallel()
 
 	// start a test server
 	server := httptest.NewServer(http.HandlerFunc(func(res http.ResponseWriter, req *http.Request) {
-		buf := new(bytes.Buffer)
-		png.Encode(buf, image.Rect(0, 0, 1, 1)) // tiny 1x1 png
-		http.ServeContent(res, req, "test_avatar.png", time.Now(), bytes.NewReader(buf.Bytes()))
+		http.ServeContent(res, req, "test_avatar.png", time.Now(), bytes.NewReader(tinyPNG()))
 	}))
 	defer server.Close()
 
@@ -207,6 +243,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordEnrich":                1,
@@ -320,6 +357,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordEnrich":                1,
@@ -350,6 +388,114 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				}
 			},
 		},
+		{
+			Name:   "existing linked OAuth2 (store the latest OAuth2 tokens)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				user, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				expiry, _ := types.ParseDateTime("2030-01-01 00:00:00.000Z")
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{
+							Id:          "test_id",
+							AccessToken: "new_access",
+							Expiry:      expiry,
+						},
+						Token: &oauth2.Token{AccessToken: "new_access"},
+					}
+				}
+
+				// add the test provider in the collection
+				user.Collection().MFA.Enabled = false
+				user.Collection().OAuth2.Enabled = true
+				user.Collection().OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				if err := app.Save(user.Collection()); err != nil {
+					t.Fatal(err)
+				}
+
+				// stub linked provider
+				ea := core.NewExternalAuth(app)
+				ea.SetCollectionRef(user.Collection().Id)
+				ea.SetRecordRef(user.Id)
+				ea.SetProvider("test")
+				ea.SetProviderId("test_id")
+				ea.SetAccessToken("old_access")
+				ea.SetRefreshToken("old_refresh")
+				if err := app.Save(ea); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"record":{`,
+				`"token":"`,
+				`"isNew":false`,
+				`"id":"oap640cot4yru2s"`,
+			},
+			NotExpectedContent: []string{
+				`"old_access"`,
+				`"old_refresh"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordEnrich":                1,
+				// ---
+				"OnModelCreate":              1, // authOrigins
+				"OnModelCreateExecute":       1,
+				"OnModelAfterCreateSuccess":  1,
+				"OnRecordCreate":             1,
+				"OnRecordCreateExecute":      1,
+				"OnRecordAfterCreateSuccess": 1,
+				// ---
+				"OnModelUpdate":              1, // externalAuths
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":  2,
+				"OnRecordValidate": 2,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				ea, err := app.FindFirstExternalAuthByExpr(dbx.HashExp{"provider": "test", "providerId": "test_id"})
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if v := ea.AccessToken(); v != "new_access" {
+					t.Fatalf("Expected accessToken %q, got %q", "new_access", v)
+				}
+
+				// the refresh token should be preserved if not returned by the provider
+				if v := ea.RefreshToken(); v != "old_refresh" {
+					t.Fatalf("Expected refreshToken %q, got %q", "old_refresh", v)
+				}
+
+				if v := ea.Expiry().String(); v != "2030-01-01 00:00:00.000Z" {
+					t.Fatalf("Expected expiry %q, got %q", "2030-01-01 00:00:00.000Z", v)
+				}
+			},
+		},
 		{
 			Name:   "link by email",
 			Method: http.MethodPost,
@@ -412,6 +558,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordEnrich":                1,
@@ -518,6 +665,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordEnrich":                1,
@@ -632,6 +780,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordEnrich":                1,
@@ -738,6 +887,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordEnrich":                1,
@@ -827,6 +977,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordCreateRequest":         1,
@@ -846,8 +997,9 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				// ---
-				"OnModelValidate":  4,
-				"OnRecordValidate": 4,
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
 			},
 		},
 		{
@@ -905,6 +1057,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"*":                             0,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordCreateRequest":         1,
+				"OnRecordRequestTransform":      1,
 			},
 		},
 		{
@@ -964,6 +1117,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"OnModelAfterCreateError":       1,
 				"OnRecordCreate":                1,
 				"OnRecordAfterCreateError":      1,
+				"OnRecordRequestTransform":      1,
 			},
 		},
 		{
@@ -1027,6 +1181,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordCreateRequest":         1,
@@ -1046,8 +1201,9 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				// ---
-				"OnModelValidate":  4,
-				"OnRecordValidate": 4,
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
 			},
 			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
 				user, err := app.FindFirstRecordByData("users", "username", "test_username")
@@ -1123,6 +1279,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordCreateRequest":         1,
@@ -1135,8 +1292,9 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"OnRecordCreateExecute":      3,
 				"OnRecordAfterCreateSuccess": 3,
 				// ---
-				"OnModelValidate":  3,
-				"OnRecordValidate": 3,
+				"OnModelValidate":          3,
+				"OnRecordValidate":         3,
+				"OnRecordRequestTransform": 1,
 			},
 			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
 				user, err := app.FindFirstRecordByData("users", "username", "test_username")
@@ -1217,6 +1375,8 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnFileUpload":                  1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordCreateRequest":         1,
@@ -1236,8 +1396,9 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				// ---
-				"OnModelValidate":  4,
-				"OnRecordValidate": 4,
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
 			},
 		},
 		{
@@ -1297,6 +1458,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordCreateRequest":         1,
@@ -1316,12 +1478,13 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				// ---
-				"OnModelValidate":  4,
-				"OnRecordValidate": 4,
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
 			},
 		},
 		{
-			Name:   "creating user (with mapped OAuth2 fields, case-sensitive username and avatarURL->non-file field)",
+			Name:   "creating user (with avatar fetched by the provider)",
 			Method: http.MethodPost,
 			URL:    "/api/collections/users/auth-with-oauth2",
 			Body: strings.NewReader(`{
@@ -1337,15 +1500,17 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 
 				// register the test provider
 				auth.Providers["test"] = func() auth.Provider {
-					return &oauth2MockProvider{
-						AuthUser: &auth.AuthUser{
-							Id:        "oauth2_id",
-							Email:     "oauth2@example.com",
-							Username:  "tESt2_username", // wouldn't match with existing because the related field index is case-sensitive
-							Name:      "oauth2_name",
-							AvatarURL: server.URL + "/oauth2_avatar.png",
+					return &oauth2MockAvatarProvider{
+						oauth2MockProvider: oauth2MockProvider{
+							AuthUser: &auth.AuthUser{
+								Id:          "oauth2_id",
+								Email:       "oauth2@example.com",
+								AccessToken: "abc",
+								AvatarURL:   "",
+							},
+							Token: &oauth2.Token{AccessToken: "abc"},
 						},
-						Token: &oauth2.Token{AccessToken: "abc"},
+						Avatar: tinyPNG(),
 					}
 				}
 
@@ -1358,8 +1523,7 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 					ClientSecret: "456",
 				}}
 				usersCol.OAuth2.MappedFields = core.OAuth2KnownFields{
-					Username:  "username",
-					AvatarURL: "name",
+					AvatarURL: "avatar",
 				}
 				if err := app.Save(usersCol); err != nil {
 					t.Fatal(err)
@@ -1369,19 +1533,91 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			ExpectedContent: []string{
 				`"isNew":true`,
 				`"email":"oauth2@example.com"`,
-				`"emailVisibility":false`,
-				`"username":"tESt2_username"`,
-				`"name":"http://127.`,
-				`"verified":true`,
-				`"avatar":""`,
+				`"avatar":"avatar_`,
 			},
-			NotExpectedContent: []string{
-				// hidden fields
-				`"tokenKey"`,
-				`"password"`,
+			ExpectedEvents: map[string]int{
+				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnFileUpload":                  1,
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordCreateRequest":         1,
+				"OnRecordEnrich":                2, // the auth response and from the create request
+				// ---
+				"OnModelCreate":              3, // record + authOrigins + externalAuths
+				"OnModelCreateExecute":       3,
+				"OnModelAfterCreateSuccess":  3,
+				"OnRecordCreate":             3,
+				"OnRecordCreateExecute":      3,
+				"OnRecordAfterCreateSuccess": 3,
+				// ---
+				"OnModelUpdate":              1, // created record verified state change
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
+			},
+		},
+		{
+			Name:   "creating user (with non-image avatar fetched by the provider)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockAvatarProvider{
+						oauth2MockProvider: oauth2MockProvider{
+							AuthUser: &auth.AuthUser{
+								Id:          "oauth2_id",
+								Email:       "oauth2@example.com",
+								AccessToken: "abc",
+								AvatarURL:   "",
+							},
+							Token: &oauth2.Token{AccessToken: "abc"},
+						},
+						Avatar: []byte("not an image"),
+					}
+				}
+
+				// add the test provider in the collection
+				usersCol.MFA.Enabled = false
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				usersCol.OAuth2.MappedFields = core.OAuth2KnownFields{
+					AvatarURL: "avatar",
+				}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":true`,
+				`"email":"oauth2@example.com"`,
+				`"avatar":""`,
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordCreateRequest":         1,
@@ -1401,12 +1637,13 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				// ---
-				"OnModelValidate":  4,
-				"OnRecordValidate": 4,
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
 			},
 		},
 		{
-			Name:   "creating user (with mapped OAuth2 fields and duplicated case-insensitive username)",
+			Name:   "creating user (with avatar exceeding the file field max size)",
 			Method: http.MethodPost,
 			URL:    "/api/collections/users/auth-with-oauth2",
 			Body: strings.NewReader(`{
@@ -1424,23 +1661,14 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				auth.Providers["test"] = func() auth.Provider {
 					return &oauth2MockProvider{
 						AuthUser: &auth.AuthUser{
-							Id:       "oauth2_id",
-							Email:    "oauth2@example.com",
-							Username: "tESt2_username",
-							Name:     "oauth2_name",
+							Id:        "oauth2_id",
+							Email:     "oauth2@example.com",
+							AvatarURL: server.URL + "/oauth2_avatar.png",
 						},
 						Token: &oauth2.Token{AccessToken: "abc"},
 					}
 				}
 
-				// make the username index case-insensitive to ensure that case-insensitive match is used
-				index, ok := dbutils.FindSingleColumnUniqueIndex(usersCol.Indexes, "username")
-				if ok {
-					index.Columns[0].Collate = "nocase"
-					usersCol.RemoveIndex(index.IndexName)
-					usersCol.Indexes = append(usersCol.Indexes, index.Build())
-				}
-
 				// add the test provider in the collection
 				usersCol.MFA.Enabled = false
 				usersCol.OAuth2.Enabled = true
@@ -1450,8 +1678,9 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 					ClientSecret: "456",
 				}}
 				usersCol.OAuth2.MappedFields = core.OAuth2KnownFields{
-					Username: "username",
+					AvatarURL: "avatar",
 				}
+				usersCol.Fields.GetByName("avatar").(*core.FileField).MaxSize = 10
 				if err := app.Save(usersCol); err != nil {
 					t.Fatal(err)
 				}
@@ -1460,18 +1689,11 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			ExpectedContent: []string{
 				`"isNew":true`,
 				`"email":"oauth2@example.com"`,
-				`"emailVisibility":false`,
-				`"verified":true`,
 				`"avatar":""`,
-				`"username":"users`,
-			},
-			NotExpectedContent: []string{
-				// hidden fields
-				`"tokenKey"`,
-				`"password"`,
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordCreateRequest":         1,
@@ -1491,12 +1713,13 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				// ---
-				"OnModelValidate":  4,
-				"OnRecordValidate": 4,
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
 			},
 		},
 		{
-			Name:   "creating user (with mapped OAuth2 fields and username that doesn't match the field validations)",
+			Name:   "creating user (with avatar not matching the file field mime types)",
 			Method: http.MethodPost,
 			URL:    "/api/collections/users/auth-with-oauth2",
 			Body: strings.NewReader(`{
@@ -1514,10 +1737,9 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				auth.Providers["test"] = func() auth.Provider {
 					return &oauth2MockProvider{
 						AuthUser: &auth.AuthUser{
-							Id:       "oauth2_id",
-							Email:    "oauth2@example.com",
-							Username: "!@invalid",
-							Name:     "oauth2_name",
+							Id:        "oauth2_id",
+							Email:     "oauth2@example.com",
+							AvatarURL: server.URL + "/oauth2_avatar.png",
 						},
 						Token: &oauth2.Token{AccessToken: "abc"},
 					}
@@ -1532,8 +1754,9 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 					ClientSecret: "456",
 				}}
 				usersCol.OAuth2.MappedFields = core.OAuth2KnownFields{
-					Username: "username",
+					AvatarURL: "avatar",
 				}
+				usersCol.Fields.GetByName("avatar").(*core.FileField).MimeTypes = []string{"image/jpeg"}
 				if err := app.Save(usersCol); err != nil {
 					t.Fatal(err)
 				}
@@ -1542,18 +1765,11 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 			ExpectedContent: []string{
 				`"isNew":true`,
 				`"email":"oauth2@example.com"`,
-				`"emailVisibility":false`,
-				`"verified":true`,
 				`"avatar":""`,
-				`"username":"users`,
-			},
-			NotExpectedContent: []string{
-				// hidden fields
-				`"tokenKey"`,
-				`"password"`,
 			},
 			ExpectedEvents: map[string]int{
 				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
 				"OnRecordAuthWithOAuth2Request": 1,
 				"OnRecordAuthRequest":           1,
 				"OnRecordCreateRequest":         1,
@@ -1573,10 +1789,870 @@ func TestRecordAuthWithOAuth2(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				// ---
-				"OnModelValidate":  4,
-				"OnRecordValidate": 4,
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
 			},
 		},
+		{
+			Name:   "creating user (with mapped OAuth2 fields, case-sensitive username and avatarURL->non-file field)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{
+							Id:        "oauth2_id",
+							Email:     "oauth2@example.com",
+							Username:  "tESt2_username", // wouldn't match with existing because the related field index is case-sensitive
+							Name:      "oauth2_name",
+							AvatarURL: server.URL + "/oauth2_avatar.png",
+						},
+						Token: &oauth2.Token{AccessToken: "abc"},
+					}
+				}
+
+				// add the test provider in the collection
+				usersCol.MFA.Enabled = false
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				usersCol.OAuth2.MappedFields = core.OAuth2KnownFields{
+					Username:  "username",
+					AvatarURL: "name",
+				}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":true`,
+				`"email":"oauth2@example.com"`,
+				`"emailVisibility":false`,
+				`"username":"tESt2_username"`,
+				`"name":"http://127.`,
+				`"verified":true`,
+				`"avatar":""`,
+			},
+			NotExpectedContent: []string{
+				// hidden fields
+				`"tokenKey"`,
+				`"password"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordCreateRequest":         1,
+				"OnRecordEnrich":                2, // the auth response and from the create request
+				// ---
+				"OnModelCreate":              3, // record + authOrigins + externalAuths
+				"OnModelCreateExecute":       3,
+				"OnModelAfterCreateSuccess":  3,
+				"OnRecordCreate":             3,
+				"OnRecordCreateExecute":      3,
+				"OnRecordAfterCreateSuccess": 3,
+				// ---
+				"OnModelUpdate":              1, // created record verified state change
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
+			},
+		},
+		{
+			Name:   "creating user (with mapped OAuth2 fields and duplicated case-insensitive username)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{
+							Id:       "oauth2_id",
+							Email:    "oauth2@example.com",
+							Username: "tESt2_username",
+							Name:     "oauth2_name",
+						},
+						Token: &oauth2.Token{AccessToken: "abc"},
+					}
+				}
+
+				// make the username index case-insensitive to ensure that case-insensitive match is used
+				index, ok := dbutils.FindSingleColumnUniqueIndex(usersCol.Indexes, "username")
+				if ok {
+					index.Columns[0].Collate = "nocase"
+					usersCol.RemoveIndex(index.IndexName)
+					usersCol.Indexes = append(usersCol.Indexes, index.Build())
+				}
+
+				// add the test provider in the collection
+				usersCol.MFA.Enabled = false
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				usersCol.OAuth2.MappedFields = core.OAuth2KnownFields{
+					Username: "username",
+				}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":true`,
+				`"email":"oauth2@example.com"`,
+				`"emailVisibility":false`,
+				`"verified":true`,
+				`"avatar":""`,
+				`"username":"users`,
+			},
+			NotExpectedContent: []string{
+				// hidden fields
+				`"tokenKey"`,
+				`"password"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordCreateRequest":         1,
+				"OnRecordEnrich":                2, // the auth response and from the create request
+				// ---
+				"OnModelCreate":              3, // record + authOrigins + externalAuths
+				"OnModelCreateExecute":       3,
+				"OnModelAfterCreateSuccess":  3,
+				"OnRecordCreate":             3,
+				"OnRecordCreateExecute":      3,
+				"OnRecordAfterCreateSuccess": 3,
+				// ---
+				"OnModelUpdate":              1, // created record verified state change
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
+			},
+		},
+		{
+			Name:   "creating user (with mapped OAuth2 fields and username that doesn't match the field validations)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{
+							Id:       "oauth2_id",
+							Email:    "oauth2@example.com",
+							Username: "!@invalid",
+							Name:     "oauth2_name",
+						},
+						Token: &oauth2.Token{AccessToken: "abc"},
+					}
+				}
+
+				// add the test provider in the collection
+				usersCol.MFA.Enabled = false
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				usersCol.OAuth2.MappedFields = core.OAuth2KnownFields{
+					Username: "username",
+				}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":true`,
+				`"email":"oauth2@example.com"`,
+				`"emailVisibility":false`,
+				`"verified":true`,
+				`"avatar":""`,
+				`"username":"users`,
+			},
+			NotExpectedContent: []string{
+				// hidden fields
+				`"tokenKey"`,
+				`"password"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordCreateRequest":         1,
+				"OnRecordEnrich":                2, // the auth response and from the create request
+				// ---
+				"OnModelCreate":              3, // record + authOrigins + externalAuths
+				"OnModelCreateExecute":       3,
+				"OnModelAfterCreateSuccess":  3,
+				"OnRecordCreate":             3,
+				"OnRecordCreateExecute":      3,
+				"OnRecordAfterCreateSuccess": 3,
+				// ---
+				"OnModelUpdate":              1, // created record verified state change
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
+			},
+		},
+		{
+			Name:   "creating user (with mapped OAuth2 groups field)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{
+							Id:     "oauth2_id",
+							Email:  "oauth2@example.com",
+							Groups: []string{"group1", "org/group2"},
+						},
+						Token: &oauth2.Token{AccessToken: "abc"},
+					}
+				}
+
+				// add the test provider in the collection
+				usersCol.MFA.Enabled = false
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				usersCol.Fields.Add(&core.JSONField{Name: "groups"})
+				usersCol.OAuth2.MappedFields = core.OAuth2KnownFields{
+					Groups: "groups",
+				}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":true`,
+				`"email":"oauth2@example.com"`,
+				`"groups":["group1","org/group2"]`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordCreateRequest":         1,
+				"OnRecordEnrich":                2, // the auth response and from the create request
+				// ---
+				"OnModelCreate":              3, // record + authOrigins + externalAuths
+				"OnModelCreateExecute":       3,
+				"OnModelAfterCreateSuccess":  3,
+				"OnRecordCreate":             3,
+				"OnRecordCreateExecute":      3,
+				"OnRecordAfterCreateSuccess": 3,
+				// ---
+				"OnModelUpdate":              1, // created record verified state change
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
+			},
+		},
+		{
+			Name:   "existing linked OAuth2 (sync mapped OAuth2 groups select field)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{Id: "test_id", Groups: []string{"group1", "unknown"}},
+						Token:    &oauth2.Token{AccessToken: "abc"},
+					}
+				}
+
+				// add the test provider in the collection
+				usersCol.MFA.Enabled = false
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				usersCol.Fields.Add(&core.SelectField{
+					Name:      "groups",
+					MaxSelect: 3,
+					Values:    []string{"group1", "group2", "group3"},
+				})
+				usersCol.OAuth2.MappedFields = core.OAuth2KnownFields{
+					Groups: "groups",
+				}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+
+				user, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+				user.Set("groups", []string{"group2"})
+				if err := app.Save(user); err != nil {
+					t.Fatal(err)
+				}
+
+				// stub linked provider
+				ea := core.NewExternalAuth(app)
+				ea.SetCollectionRef(user.Collection().Id)
+				ea.SetRecordRef(user.Id)
+				ea.SetProvider("test")
+				ea.SetProviderId("test_id")
+				if err := app.Save(ea); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":false`,
+				`"email":"test2@example.com"`,
+				`"groups":["group1"]`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordEnrich":                1,
+				// ---
+				"OnModelCreate":              1, // authOrigins
+				"OnModelCreateExecute":       1,
+				"OnModelAfterCreateSuccess":  1,
+				"OnRecordCreate":             1,
+				"OnRecordCreateExecute":      1,
+				"OnRecordAfterCreateSuccess": 1,
+				// ---
+				"OnModelUpdate":              1, // groups sync
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":  2,
+				"OnRecordValidate": 2,
+			},
+		},
+		{
+			Name:   "creating user (with provider claims mapping)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com",
+				"createData": {"role": "admin"}
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{
+							Id:    "oauth2_id",
+							Email: "oauth2@example.com",
+							RawUser: map[string]any{
+								"locale": "en",
+								"roles":  []any{"staff", "other"},
+							},
+						},
+						Token: &oauth2.Token{AccessToken: "abc"},
+					}
+				}
+
+				// add the test provider in the collection
+				usersCol.MFA.Enabled = false
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+					ClaimsMapping: &core.OAuth2ClaimsMapping{
+						Fields:        map[string]string{"locale": "locale"},
+						GroupsClaim:   "roles",
+						AllowedGroups: []string{"staff"},
+						RoleField:     "role",
+						Roles: []core.OAuth2RoleMapping{
+							{Role: "admin", Groups: []string{"admins"}},
+							{Role: "editor", Groups: []string{"staff"}},
+						},
+						DefaultRole: "user",
+					},
+				}}
+				usersCol.Fields.Add(&core.TextField{Name: "locale"})
+				usersCol.Fields.Add(&core.SelectField{
+					Name:      "role",
+					MaxSelect: 1,
+					Values:    []string{"admin", "editor", "user"},
+				})
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":true`,
+				`"email":"oauth2@example.com"`,
+				`"locale":"en"`,
+				`"role":"editor"`,
+				`"groups":["staff","other"]`,
+				`"claims":{`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordCreateRequest":         1,
+				"OnRecordEnrich":                2, // the auth response and from the create request
+				// ---
+				"OnModelCreate":              3, // record + authOrigins + externalAuths
+				"OnModelCreateExecute":       3,
+				"OnModelAfterCreateSuccess":  3,
+				"OnRecordCreate":             3,
+				"OnRecordCreateExecute":      3,
+				"OnRecordAfterCreateSuccess": 3,
+				// ---
+				"OnModelUpdate":              1, // created record verified state change
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":          4,
+				"OnRecordValidate":         4,
+				"OnRecordRequestTransform": 1,
+			},
+		},
+		{
+			Name:   "existing linked OAuth2 (sync provider claims mapping)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{Id: "test_id", Groups: []string{"editors", "viewers"}},
+						Token:    &oauth2.Token{AccessToken: "abc"},
+					}
+				}
+
+				// add the test provider in the collection
+				usersCol.MFA.Enabled = false
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+					ClaimsMapping: &core.OAuth2ClaimsMapping{
+						RoleField: "roles",
+						Roles: []core.OAuth2RoleMapping{
+							{Role: "admin", Groups: []string{"admins"}},
+							{Role: "editor", Groups: []string{"editors"}},
+							{Role: "viewer", Groups: []string{"editors", "viewers"}},
+						},
+					},
+				}}
+				usersCol.Fields.Add(&core.SelectField{
+					Name:      "roles",
+					MaxSelect: 3,
+					Values:    []string{"admin", "editor", "viewer"},
+				})
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+
+				user, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+				user.Set("roles", []string{"admin"})
+				if err := app.Save(user); err != nil {
+					t.Fatal(err)
+				}
+
+				// stub linked provider
+				ea := core.NewExternalAuth(app)
+				ea.SetCollectionRef(user.Collection().Id)
+				ea.SetRecordRef(user.Id)
+				ea.SetProvider("test")
+				ea.SetProviderId("test_id")
+				if err := app.Save(ea); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"isNew":false`,
+				`"email":"test2@example.com"`,
+				`"roles":["editor","viewer"]`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                             0,
+				"OnRecordAuthTokenClaims":       1,
+				"OnRecordAuthWithOAuth2Request": 1,
+				"OnRecordAuthRequest":           1,
+				"OnRecordEnrich":                1,
+				// ---
+				"OnModelCreate":              1, // authOrigins
+				"OnModelCreateExecute":       1,
+				"OnModelAfterCreateSuccess":  1,
+				"OnRecordCreate":             1,
+				"OnRecordCreateExecute":      1,
+				"OnRecordAfterCreateSuccess": 1,
+				// ---
+				"OnModelUpdate":              1, // roles sync
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				// ---
+				"OnModelValidate":  2,
+				"OnRecordValidate": 2,
+			},
+		},
+		{
+			Name:   "OAuth2 user that is not a member of the allowed groups",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test"] = func() auth.Provider {
+					return &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{Id: "test_id", Groups: []string{"viewers"}},
+						Token:    &oauth2.Token{AccessToken: "abc"},
+					}
+				}
+
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test",
+					ClientId:     "123",
+					ClientSecret: "456",
+					ClaimsMapping: &core.OAuth2ClaimsMapping{
+						AllowedGroups: []string{"admins", "editors"},
+					},
+				}}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+
+				app.OnRecordAuthFailure().BindFunc(func(e *core.RecordAuthFailureEvent) error {
+					if e.AuthMethod != core.MFAMethodOAuth2 ||
+						e.Reason != core.AuthFailureReasonOAuth2Forbidden ||
+						e.Provider != "test" ||
+						!errors.Is(e.Error, core.ErrOAuth2GroupsNotAllowed) {
+						t.Fatalf("Unexpected failure event data %#v", e)
+					}
+
+					return e.Next()
+				})
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"message":"The OAuth2 user is not allowed to authenticate."`},
+			ExpectedEvents:  map[string]int{"*": 0, "OnRecordAuthFailure": 1},
+		},
+		{
+			Name:   "Shopify provider with invalid signed redirect query",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "shopify",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         auth.NameShopify,
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+
+				app.Store().Set("@redirect_hmac_query_123", url.Values{
+					"code": {"123"},
+					"shop": {"example.myshopify.com"},
+					"hmac": {"invalid"},
+				})
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if app.Store().Has("@redirect_hmac_query_123") {
+					t.Fatal("Expected the stored redirect query to be removed")
+				}
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"message":"Failed to verify the OAuth2 redirect data."`},
+			ExpectedEvents:  map[string]int{"*": 0, "OnRecordAuthFailure": 1},
+		},
+		{
+			Name:   "failed OAuth2 token exchange",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test_failure",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test_failure"] = func() auth.Provider {
+					return &oauth2MockProvider{}
+				}
+
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test_failure",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+
+				app.OnRecordAuthFailure().BindFunc(func(e *core.RecordAuthFailureEvent) error {
+					if e.AuthMethod != core.MFAMethodOAuth2 ||
+						e.Reason != core.AuthFailureReasonOAuth2TokenExchange ||
+						e.Provider != "test_failure" ||
+						e.Error == nil {
+						t.Fatalf("Unexpected failure event data %#v", e)
+					}
+
+					return e.Next()
+				})
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"message":"Failed to fetch OAuth2 token."`},
+			ExpectedEvents:  map[string]int{"*": 0, "OnRecordAuthFailure": 1},
+		},
+		{
+			Name:   "PKCE provider without code verifier",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test_pkce",
+				"code":"123",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test_pkce"] = func() auth.Provider {
+					p := &oauth2MockProvider{
+						AuthUser: &auth.AuthUser{Id: "test_id"},
+						Token:    &oauth2.Token{AccessToken: "abc"},
+					}
+					p.SetPKCE(true)
+					return p
+				}
+
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test_pkce",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"message":"Missing required PKCE code verifier."`},
+			ExpectedEvents:  map[string]int{"*": 0, "OnRecordAuthFailure": 1},
+		},
+		{
+			Name:   "PKCE provider with code verifier bound nonce",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-oauth2",
+			Body: strings.NewReader(`{
+				"provider": "test_nonce",
+				"code":"123",
+				"codeVerifier":"test_verifier",
+				"redirectURL": "https://example.com"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				usersCol, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// register the test provider
+				auth.Providers["test_nonce"] = func() auth.Provider {
+					p := &oauth2MockNonceProvider{oauth2MockProvider: oauth2MockProvider{
+						AuthUser: &auth.AuthUser{Id: "test_id"},
+						Token:    &oauth2.Token{AccessToken: "abc"},
+					}}
+					p.SetPKCE(true)
+					return p
+				}
+
+				usersCol.MFA.Enabled = false
+				usersCol.OAuth2.Enabled = true
+				usersCol.OAuth2.Providers = []core.OAuth2ProviderConfig{{
+					Name:         "test_nonce",
+					ClientId:     "123",
+					ClientSecret: "456",
+				}}
+				if err := app.Save(usersCol); err != nil {
+					t.Fatal(err)
+				}
+
+				app.OnRecordAuthWithOAuth2Request().BindFunc(func(e *core.RecordAuthWithOAuth2RequestEvent) error {
+					if !e.PKCEVerified {
+						t.Fatal("Expected PKCEVerified to be true")
+					}
+
+					if !e.NonceVerified {
+						t.Fatal("Expected NonceVerified to be true")
+					}
+
+					expectedNonce := security.S256Challenge("nonce:test_verifier")
+					if nonce := e.ProviderClient.(*oauth2MockNonceProvider).nonce; nonce != expectedNonce {
+						t.Fatalf("Expected nonce %q, got %q", expectedNonce, nonce)
+					}
+
+					return e.Next()
+				})
+			},
+			ExpectedStatus:  200,
+			ExpectedContent: []string{`"token":`},
+			ExpectedEvents:  map[string]int{"OnRecordAuthWithOAuth2Request": 1},
+		},
 		{
 			Name:   "OnRecordAuthWithOAuth2Request tx body write check",
 			Method: http.MethodPost,
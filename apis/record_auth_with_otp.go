@@ -35,42 +35,69 @@ func recordAuthWithOTP(e *core.RequestEvent) error {
 	// extra validations
 	// (note: returns a generic 400 as a very basic OTPs enumeration protection)
 	// ---
+	otpFailure := func(reason string, err error) {
+		triggerRecordAuthFailure(e, collection, &core.RecordAuthFailureEvent{
+			Record:     event.Record,
+			AuthMethod: core.MFAMethodOTP,
+			Reason:     reason,
+			Error:      err,
+		})
+	}
+
 	event.OTP, err = e.App.FindOTPById(form.OTPId)
 	if err != nil {
+		otpFailure(core.AuthFailureReasonInvalidOTP, err)
 		return e.BadRequestError("Invalid or expired OTP", err)
 	}
 
 	if event.OTP.CollectionRef() != collection.Id {
-		return e.BadRequestError("Invalid or expired OTP", errors.New("the OTP is for a different collection"))
+		err = errors.New("the OTP is for a different collection")
+		otpFailure(core.AuthFailureReasonInvalidOTP, err)
+		return e.BadRequestError("Invalid or expired OTP", err)
 	}
 
 	if event.OTP.HasExpired(collection.OTP.DurationTime()) {
-		return e.BadRequestError("Invalid or expired OTP", errors.New("the OTP is expired"))
+		err = errors.New("the OTP is expired")
+		otpFailure(core.AuthFailureReasonExpiredOTP, err)
+		return e.BadRequestError("Invalid or expired OTP", err)
 	}
 
 	event.Record, err = e.App.FindRecordById(event.OTP.CollectionRef(), event.OTP.RecordRef())
 	if err != nil {
-		return e.BadRequestError("Invalid or expired OTP", fmt.Errorf("missing auth record: %w", err))
+		err = fmt.Errorf("missing auth record: %w", err)
+		otpFailure(core.AuthFailureReasonInvalidOTP, err)
+		return e.BadRequestError("Invalid or expired OTP", err)
 	}
 
 	// since otps are usually simple digit numbers, enforce an extra rate limit rule as basic enumaration protection
 	err = checkRateLimit(e, "@pb_otp_"+event.Record.Id, core.RateLimitRule{MaxRequests: 5, Duration: 180})
 	if err != nil {
+		otpFailure(core.AuthFailureReasonTooManyAttempts, err)
 		return e.TooManyRequestsError("Too many attempts, please try again later with a new OTP.", nil)
 	}
 
 	if !event.OTP.ValidatePassword(form.Password) {
-		return e.BadRequestError("Invalid or expired OTP", errors.New("incorrect password"))
+		err = errors.New("incorrect password")
+		otpFailure(core.AuthFailureReasonInvalidOTP, err)
+		return e.BadRequestError("Invalid or expired OTP", err)
 	}
 	// ---
 
 	return e.App.OnRecordAuthWithOTPRequest().Trigger(event, func(e *core.RecordAuthWithOTPRequestEvent) error {
-		// update the user email verified state in case the OTP originate from an email address matching the current record one
+		// update the user email or phone verified state in case the OTP originate from an email address or phone matching the current record one
 		//
 		// note: don't wait for success auth response (it could fail because of MFA) and because we already validated the OTP above
 		otpSentTo := e.OTP.SentTo()
+		var verifiedChanged bool
 		if !e.Record.Verified() && otpSentTo != "" && e.Record.Email() == otpSentTo {
 			e.Record.SetVerified(true)
+			verifiedChanged = true
+		}
+		if phoneField := findRecordPhoneField(e.Record, otpSentTo); phoneField != "" && !e.Record.PhoneVerified(phoneField) {
+			e.Record.SetPhoneVerified(phoneField, true)
+			verifiedChanged = true
+		}
+		if verifiedChanged {
 			err = e.App.Save(e.Record)
 			if err != nil {
 				e.App.Logger().Error("Failed to update record verified state after successful OTP validation",
@@ -106,7 +106,45 @@ func TestRecordAuthWithOTP(t *testing.T) {
 			},
 			ExpectedStatus:  400,
 			ExpectedContent: []string{`"data":{}`},
-			ExpectedEvents:  map[string]int{"*": 0},
+			ExpectedEvents:  map[string]int{"*": 0, "OnRecordAuthFailure": 1},
+		},
+		{
+			Name:   "OnRecordAuthFailure event data",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-otp",
+			Body: strings.NewReader(`{
+				"otpId":"` + strings.Repeat("a", 15) + `",
+				"password":"654321"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+				otp := core.NewOTP(app)
+				otp.Id = strings.Repeat("a", 15)
+				otp.SetCollectionRef(user.Collection().Id)
+				otp.SetRecordRef(user.Id)
+				otp.SetPassword("123456")
+				if err := app.Save(otp); err != nil {
+					t.Fatal(err)
+				}
+
+				app.OnRecordAuthFailure().BindFunc(func(e *core.RecordAuthFailureEvent) error {
+					if e.AuthMethod != core.MFAMethodOTP ||
+						e.Reason != core.AuthFailureReasonInvalidOTP ||
+						e.Record == nil ||
+						e.Record.Id != user.Id ||
+						e.Error == nil {
+						t.Fatalf("Unexpected failure event data %#v", e)
+					}
+
+					return e.Next()
+				})
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0, "OnRecordAuthFailure": 1},
 		},
 		{
 			Name:   "otp for different collection",
@@ -132,7 +170,7 @@ func TestRecordAuthWithOTP(t *testing.T) {
 			},
 			ExpectedStatus:  400,
 			ExpectedContent: []string{`"data":{}`},
-			ExpectedEvents:  map[string]int{"*": 0},
+			ExpectedEvents:  map[string]int{"*": 0, "OnRecordAuthFailure": 1},
 		},
 		{
 			Name:   "otp with wrong password",
@@ -158,7 +196,7 @@ func TestRecordAuthWithOTP(t *testing.T) {
 			},
 			ExpectedStatus:  400,
 			ExpectedContent: []string{`"data":{}`},
-			ExpectedEvents:  map[string]int{"*": 0},
+			ExpectedEvents:  map[string]int{"*": 0, "OnRecordAuthFailure": 1},
 		},
 		{
 			Name:   "expired otp with valid password",
@@ -187,7 +225,7 @@ func TestRecordAuthWithOTP(t *testing.T) {
 			},
 			ExpectedStatus:  400,
 			ExpectedContent: []string{`"data":{}`},
-			ExpectedEvents:  map[string]int{"*": 0},
+			ExpectedEvents:  map[string]int{"*": 0, "OnRecordAuthFailure": 1},
 		},
 		{
 			Name:   "valid otp with valid password (enabled MFA)",
@@ -215,6 +253,7 @@ func TestRecordAuthWithOTP(t *testing.T) {
 			ExpectedContent: []string{`"mfaId":"`},
 			ExpectedEvents: map[string]int{
 				"*":                          0,
+				"OnRecordAuthTokenClaims":    1,
 				"OnRecordAuthWithOTPRequest": 1,
 				"OnRecordAuthRequest":        1,
 				// ---
@@ -298,6 +337,7 @@ func TestRecordAuthWithOTP(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                          0,
+				"OnRecordAuthTokenClaims":    1,
 				"OnRecordAuthWithOTPRequest": 1,
 				"OnRecordAuthRequest":        1,
 				"OnRecordEnrich":             1,
@@ -379,6 +419,7 @@ func TestRecordAuthWithOTP(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                          0,
+				"OnRecordAuthTokenClaims":    1,
 				"OnRecordAuthWithOTPRequest": 1,
 				"OnRecordAuthRequest":        1,
 				"OnRecordEnrich":             1,
@@ -419,6 +460,95 @@ func TestRecordAuthWithOTP(t *testing.T) {
 				}
 			},
 		},
+		{
+			Name:   "valid otp with valid password and nonempty sentTo=phone (disabled MFA)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-otp",
+			Body: strings.NewReader(`{
+				"otpId":"` + strings.Repeat("a", 15) + `",
+				"password":"123456"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setTestUserPhone(t, app, "+359888123456", false)
+
+				user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				// disable MFA
+				user.Collection().MFA.Enabled = false
+				if err = app.Save(user.Collection()); err != nil {
+					t.Fatal(err)
+				}
+
+				otp := core.NewOTP(app)
+				otp.Id = strings.Repeat("a", 15)
+				otp.SetCollectionRef(user.Collection().Id)
+				otp.SetRecordRef(user.Id)
+				otp.SetPassword("123456")
+				otp.SetSentTo("+359888123456")
+				if err := app.Save(otp); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"token":"`,
+				`"record":{`,
+				`"email":"test@example.com"`,
+				`"phone":"+359888123456"`,
+				`"phoneVerified":true`,
+			},
+			NotExpectedContent: []string{
+				`"meta":`,
+				// hidden fields
+				`"tokenKey"`,
+				`"password"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                          0,
+				"OnRecordAuthTokenClaims":    1,
+				"OnRecordAuthWithOTPRequest": 1,
+				"OnRecordAuthRequest":        1,
+				"OnRecordEnrich":             1,
+				// ---
+				"OnModelValidate": 2, // +1 because of the phone verified user update
+				// authOrigin create
+				"OnModelCreate":             1,
+				"OnModelCreateExecute":      1,
+				"OnModelAfterCreateSuccess": 1,
+				// OTP delete
+				"OnModelDelete":             1,
+				"OnModelDeleteExecute":      1,
+				"OnModelAfterDeleteSuccess": 1,
+				// user verified update
+				"OnModelUpdate":             1,
+				"OnModelUpdateExecute":      1,
+				"OnModelAfterUpdateSuccess": 1,
+				// ---
+				"OnRecordValidate":           2,
+				"OnRecordCreate":             1,
+				"OnRecordCreateExecute":      1,
+				"OnRecordAfterCreateSuccess": 1,
+				"OnRecordDelete":             1,
+				"OnRecordDeleteExecute":      1,
+				"OnRecordAfterDeleteSuccess": 1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !user.PhoneVerified("phone") {
+					t.Fatal("Expected the user phone to be marked as verified")
+				}
+			},
+		},
 		{
 			Name:   "OnRecordAuthWithOTPRequest tx body write check",
 			Method: http.MethodPost,
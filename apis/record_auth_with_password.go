@@ -12,6 +12,8 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tools/dbutils"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/sms"
+	"github.com/spf13/cast"
 )
 
 func recordAuthWithPassword(e *core.RequestEvent) error {
@@ -76,6 +78,17 @@ func recordAuthWithPassword(e *core.RequestEvent) error {
 
 	return e.App.OnRecordAuthWithPasswordRequest().Trigger(event, func(e *core.RecordAuthWithPasswordRequestEvent) error {
 		if e.Record == nil || !e.Record.ValidatePassword(e.Password) {
+			failure := &core.RecordAuthFailureEvent{
+				Record:     e.Record,
+				AuthMethod: core.MFAMethodPassword,
+				Reason:     core.AuthFailureReasonInvalidPassword,
+				Identity:   e.Identity,
+			}
+			if e.Record == nil {
+				failure.Reason = core.AuthFailureReasonUnknownIdentity
+			}
+			triggerRecordAuthFailure(e.RequestEvent, e.Collection, failure)
+
 			return e.BadRequestError("Failed to authenticate.", errors.New("invalid login credentials"))
 		}
 
@@ -116,6 +129,16 @@ func findRecordByIdentityField(app core.App, collection *core.Collection, field
 		return nil, errors.New("missing " + field + " unique index constraint")
 	}
 
+	// normalize the phone identity to match the stored E.164 value
+	_, isPhone := collection.Fields.GetByName(field).(*core.PhoneField)
+	if isPhone {
+		phone, err := sms.NormalizePhone(cast.ToString(value))
+		if err != nil {
+			return nil, sql.ErrNoRows
+		}
+		value = phone
+	}
+
 	var expr dbx.Expression
 	if strings.EqualFold(index.Columns[0].Collate, "nocase") {
 		// case-insensitive search
@@ -131,5 +154,10 @@ func findRecordByIdentityField(app core.App, collection *core.Collection, field
 		return nil, err
 	}
 
+	// only the verified phones are allowed to be used as identity
+	if isPhone && !record.PhoneVerified(field) {
+		return nil, sql.ErrNoRows
+	}
+
 	return record, nil
 }
@@ -1,6 +1,7 @@
 package apis_test
 
 import (
+	"errors"
 	"net/http"
 	"strings"
 	"testing"
@@ -121,6 +122,69 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthFailure":             1,
+				"OnRecordAuthWithPasswordRequest": 1,
+			},
+		},
+		{
+			Name:   "OnRecordAuthFailure event data (unknown identity)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"missing@example.com",
+				"password":"invalid"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.OnRecordAuthFailure("clients").BindFunc(func(e *core.RecordAuthFailureEvent) error {
+					if e.Collection.Name != "clients" ||
+						e.AuthMethod != core.MFAMethodPassword ||
+						e.Reason != core.AuthFailureReasonUnknownIdentity ||
+						e.Identity != "missing@example.com" ||
+						e.Record != nil {
+						t.Fatalf("Unexpected failure event data %#v", e)
+					}
+
+					// shouldn't change the response
+					return errors.New("test_hook_error")
+				})
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{}`,
+				`"message":"Failed to authenticate."`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthFailure":             1,
+				"OnRecordAuthWithPasswordRequest": 1,
+			},
+		},
+		{
+			Name:   "OnRecordAuthFailure event data (invalid password)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"test@example.com",
+				"password":"invalid"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.OnRecordAuthFailure().BindFunc(func(e *core.RecordAuthFailureEvent) error {
+					if e.Reason != core.AuthFailureReasonInvalidPassword ||
+						e.Record == nil ||
+						e.Record.Email() != "test@example.com" {
+						t.Fatalf("Unexpected failure event data %#v", e)
+					}
+
+					return e.Next()
+				})
+			},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"data":{}`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthFailure":             1,
 				"OnRecordAuthWithPasswordRequest": 1,
 			},
 		},
@@ -159,6 +223,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				"OnRecordEnrich":                  1,
@@ -196,6 +261,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				"OnRecordEnrich":                  1,
@@ -241,6 +307,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			ExpectedContent: []string{`"data":{}`},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthFailure":             1,
 				"OnRecordAuthWithPasswordRequest": 1,
 			},
 		},
@@ -266,6 +333,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				"OnRecordEnrich":                  1,
@@ -294,6 +362,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			ExpectedContent: []string{`"data":{}`},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 			},
 		},
@@ -321,6 +390,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				"OnRecordEnrich":                  1,
@@ -351,6 +421,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				// mfa create
@@ -416,6 +487,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				"OnRecordEnrich":                  1,
@@ -472,6 +544,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				"OnRecordEnrich":                  1,
@@ -520,6 +593,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			ExpectedContent: []string{`"data":{}`},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthFailure":             1,
 				"OnRecordAuthWithPasswordRequest": 1,
 			},
 		},
@@ -546,6 +620,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				"OnRecordEnrich":                  1,
@@ -584,6 +659,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				"OnRecordEnrich":                  1,
@@ -622,6 +698,7 @@ func TestRecordAuthWithPassword(t *testing.T) {
 			},
 			ExpectedEvents: map[string]int{
 				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
 				"OnRecordAuthWithPasswordRequest": 1,
 				"OnRecordAuthRequest":             1,
 				"OnRecordEnrich":                  1,
@@ -638,6 +715,113 @@ func TestRecordAuthWithPassword(t *testing.T) {
 				"OnMailerRecordAuthAlertSend": 1,
 			},
 		},
+		{
+			Name:   "without explicit identityField and phone field (non-normalized)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"00359 888 123-456",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setTestUserPhone(t, app, "+359888123456", true)
+
+				users, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+				users.MFA.Enabled = false
+				users.PasswordAuth.IdentityFields = []string{"email", "phone"}
+				if err = app.Save(users); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"email":"test@example.com"`,
+				`"phone":"+359888123456"`,
+				`"token":`,
+			},
+			NotExpectedContent: []string{
+				// hidden fields
+				`"tokenKey"`,
+				`"password"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthTokenClaims":         1,
+				"OnRecordAuthWithPasswordRequest": 1,
+				"OnRecordAuthRequest":             1,
+				"OnRecordEnrich":                  1,
+				// authOrigin track
+				"OnModelCreate":              1,
+				"OnModelCreateExecute":       1,
+				"OnModelAfterCreateSuccess":  1,
+				"OnModelValidate":            1,
+				"OnRecordCreate":             1,
+				"OnRecordCreateExecute":      1,
+				"OnRecordAfterCreateSuccess": 1,
+				"OnRecordValidate":           1,
+			},
+		},
+		{
+			Name:   "without explicit identityField and unverified phone field",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identity":"+359888123456",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setTestUserPhone(t, app, "+359888123456", false)
+
+				users, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+				users.MFA.Enabled = false
+				users.PasswordAuth.IdentityFields = []string{"email", "phone"}
+				if err = app.Save(users); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthWithPasswordRequest": 1,
+				"OnRecordAuthFailure":             1,
+			},
+		},
+		{
+			Name:   "with explicit phone identityField and invalid phone",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/auth-with-password",
+			Body: strings.NewReader(`{
+				"identityField":"phone",
+				"identity":"invalid",
+				"password":"1234567890"
+			}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				setTestUserPhone(t, app, "+359888123456", true)
+
+				users, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					t.Fatal(err)
+				}
+				users.PasswordAuth.IdentityFields = []string{"email", "phone"}
+				if err = app.Save(users); err != nil {
+					t.Fatal(err)
+				}
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents: map[string]int{
+				"*":                               0,
+				"OnRecordAuthFailure":             1,
+				"OnRecordAuthWithPasswordRequest": 1,
+			},
+		},
 
 		// rate limit checks
 		// -----------------------------------------------------------
@@ -0,0 +1,66 @@
+package apis
+
+import (
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindRecordBulkEmailApi registers the auth records bulk email api endpoint.
+func bindRecordBulkEmailApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	rg.POST("/collections/{collection}/bulk-email", recordsBulkEmail).Bind(RequireSuperuserAuth())
+}
+
+type bulkEmailForm struct {
+	// Filter is an optional auth records filter expression (e.g. "verified = true").
+	Filter string `form:"filter" json:"filter"`
+
+	Subject string `form:"subject" json:"subject"`
+	Body    string `form:"body" json:"body"`
+
+	// Placeholders is an optional map with custom template placeholders (e.g. {"{PROMO}": "..."}).
+	Placeholders map[string]any `form:"placeholders" json:"placeholders"`
+}
+
+func (form *bulkEmailForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Subject, validation.Required),
+		validation.Field(&form.Body, validation.Required),
+	)
+}
+
+func recordsBulkEmail(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	if !collection.IsAuth() {
+		return e.BadRequestError("Bulk emails can be sent only to auth collection records.", nil)
+	}
+
+	form := &bulkEmailForm{}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	if err := form.validate(); err != nil {
+		return e.BadRequestError("Invalid bulk email data.", err)
+	}
+
+	total, err := mails.QueueBulkEmail(
+		e.App,
+		collection,
+		form.Filter,
+		core.EmailTemplate{Subject: form.Subject, Body: form.Body},
+		form.Placeholders,
+	)
+	if err != nil {
+		return e.BadRequestError("Failed to queue the bulk email.", err)
+	}
+
+	return e.JSON(http.StatusOK, map[string]any{"queued": total})
+}
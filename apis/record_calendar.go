@@ -0,0 +1,76 @@
+package apis
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/ical"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// calendarFeedMaxEvents is the max number of the latest events included in a calendar feed.
+const calendarFeedMaxEvents = 1000
+
+// bindRecordCalendarApi registers the collection records iCalendar feed api endpoint.
+func bindRecordCalendarApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	rg.GET("/collections/{collection}/calendar.ics", recordsCalendar).Bind(
+		collectionPathRateLimit("", "calendar"),
+	)
+}
+
+// recordsCalendar returns the collection records accessible by the
+// request auth record (see [findFeedAuthRecord]) as iCalendar events
+// based on the collection [core.CalendarConfig] field mapping.
+func recordsCalendar(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || collection.Calendar == nil {
+		return e.NotFoundError("Missing or invalid calendar collection context.", err)
+	}
+
+	return feedRecordsRequest(e, collection, collection.Calendar.StartField, calendarFeedMaxEvents, func(e *core.RecordsListRequestEvent) error {
+		calendar := &ical.Calendar{
+			ProdId: "-//" + e.App.Settings().Meta.AppName + "//" + e.Collection.Name + "//EN",
+			Name:   e.Collection.Name,
+			Events: make([]ical.Event, 0, len(e.Records)),
+		}
+
+		for _, record := range e.Records {
+			calendar.Events = append(calendar.Events, recordCalendarEvent(e.Collection.Calendar, record))
+		}
+
+		var buf bytes.Buffer
+		if err := calendar.Encode(&buf); err != nil {
+			return e.InternalServerError("Failed to encode the calendar feed.", err)
+		}
+
+		return execAfterSuccessTx(true, e.App, func() error {
+			return e.Blob(http.StatusOK, ical.ContentType, buf.Bytes())
+		})
+	})
+}
+
+// recordCalendarEvent maps the provided record to a calendar event.
+func recordCalendarEvent(config *core.CalendarConfig, record *core.Record) ical.Event {
+	event := ical.Event{
+		UID:     record.Id + "@" + record.Collection().Id,
+		Start:   record.GetDateTime(config.StartField).Time(),
+		Summary: record.GetString(config.TitleField),
+	}
+
+	if config.EndField != "" {
+		event.End = record.GetDateTime(config.EndField).Time()
+	}
+
+	if config.DescriptionField != "" {
+		event.Description = record.GetString(config.DescriptionField)
+	}
+
+	event.Stamp = record.GetDateTime("updated").Time()
+	if event.Stamp.IsZero() {
+		event.Stamp = time.Now()
+	}
+
+	return event
+}
@@ -2,6 +2,7 @@ package apis
 
 import (
 	cryptoRand "crypto/rand"
+	"database/sql"
 	"errors"
 	"fmt"
 	"math/big"
@@ -18,6 +19,7 @@ import (
 	"github.com/pocketbase/pocketbase/tools/router"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/spf13/cast"
 )
 
 // bindRecordCrudApi registers the record crud api endpoints and
@@ -28,9 +30,11 @@ func bindRecordCrudApi(app core.App, rg *router.RouterGroup[*core.RequestEvent])
 	subGroup := rg.Group("/collections/{collection}/records").Unbind(DefaultRateLimitMiddlewareId)
 	subGroup.GET("", recordsList)
 	subGroup.GET("/{id}", recordView)
-	subGroup.POST("", recordCreate(true, nil)).Bind(dynamicCollectionBodyLimit(""))
-	subGroup.PATCH("/{id}", recordUpdate(true, nil)).Bind(dynamicCollectionBodyLimit(""))
+	subGroup.POST("", recordWriteWithDryRun(recordCreate)).Bind(dynamicCollectionBodyLimit(""))
+	subGroup.PATCH("/{id}", recordWriteWithDryRun(recordUpdate)).Bind(dynamicCollectionBodyLimit(""))
 	subGroup.DELETE("/{id}", recordDelete(true, nil))
+	subGroup.POST("/{id}/duplicate", recordDuplicate).Bind(dynamicCollectionBodyLimit(""))
+	subGroup.GET("/{id}/relations/{relation}", recordBackRelationsList)
 }
 
 func recordsList(e *core.RequestEvent) error {
@@ -53,27 +57,52 @@ func recordsList(e *core.RequestEvent) error {
 		return e.ForbiddenError("Only superusers can perform this action.", nil)
 	}
 
+	err = applyListPreset(e, collection, requestInfo)
+	if err != nil {
+		return err
+	}
+
 	// forbid users and guests to query special filter/sort fields
 	err = checkForSuperuserOnlyRuleFields(requestInfo)
 	if err != nil {
 		return err
 	}
 
-	query := e.App.RecordQuery(collection)
+	tombstones, err := findListTombstones(e, collection)
+	if err != nil {
+		return err
+	}
+
+	query := e.App.RecordQuery(collection).WithContext(e.Request.Context())
 
 	fieldsResolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
 
-	if !requestInfo.HasSuperuserAuth() && collection.ListRule != nil && *collection.ListRule != "" {
-		expr, err := search.FilterData(*collection.ListRule).BuildExpr(fieldsResolver)
+	if !requestInfo.HasSuperuserAuth() && collection.ListRule != nil {
+		if *collection.ListRule != "" {
+			expr, err := search.FilterData(*collection.ListRule).BuildExpr(fieldsResolver)
+			if err != nil {
+				return err
+			}
+			query.AndWhere(expr)
+		}
+
+		policiesExpr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionList, fieldsResolver)
 		if err != nil {
 			return err
 		}
-		query.AndWhere(expr)
+		if policiesExpr != nil {
+			query.AndWhere(policiesExpr)
+		}
 
 		// will be applied by the search provider right before executing the query
 		// fieldsResolver.UpdateQuery(query)
 	}
 
+	err = applyRecordsSearch(e, collection, requestInfo, query)
+	if err != nil {
+		return err
+	}
+
 	// hidden fields are searchable only by superusers
 	fieldsResolver.SetAllowHiddenFields(requestInfo.HasSuperuserAuth())
 
@@ -84,6 +113,14 @@ func recordsList(e *core.RequestEvent) error {
 		searchProvider.CountCol("_rowid_")
 	}
 
+	if isRecordsListStreamRequest(e) {
+		if tombstones != nil {
+			return e.BadRequestError("The deletions are not supported with the records list streaming.", nil)
+		}
+
+		return recordsListStream(e, collection, searchProvider)
+	}
+
 	records := []*core.Record{}
 	result, err := searchProvider.ParseAndExec(e.Request.URL.Query().Encode(), &records)
 	if err != nil {
@@ -116,12 +153,17 @@ func recordsList(e *core.RequestEvent) error {
 			(collection.ListRule != nil && *collection.ListRule != "") &&
 			(requestInfo.Query["filter"] != "") &&
 			len(e.Records) == 0 &&
+			(e.Result == nil || e.Result.TotalItems <= 0) && // countOnly doesn't return records
 			checkRateLimit(e.RequestEvent, "@pb_list_timing_check_"+collection.Id, listTimingRateLimitRule) != nil {
 			e.App.Logger().Debug("Randomized throttle because of too many failed searches", "collectionId", collection.Id)
 			randomizedThrottle(150)
 		}
 
 		return execAfterSuccessTx(true, e.App, func() error {
+			if tombstones != nil {
+				return e.JSON(http.StatusOK, recordsListWithTombstones{Result: e.Result, Tombstones: tombstones})
+			}
+
 			return e.JSON(http.StatusOK, e.Result)
 		})
 	})
@@ -162,19 +204,33 @@ func recordView(e *core.RequestEvent) error {
 		return firstApiError(err, e.BadRequestError("", err))
 	}
 
-	if collection.ViewRule == nil && !requestInfo.HasSuperuserAuth() {
+	// the record share token grants access regardless of the view rule
+	shared := isSharedRecordRequest(requestInfo, collection, recordId)
+
+	if collection.ViewRule == nil && !requestInfo.HasSuperuserAuth() && !shared {
 		return e.ForbiddenError("Only superusers can perform this action.", nil)
 	}
 
 	ruleFunc := func(q *dbx.SelectQuery) error {
-		if !requestInfo.HasSuperuserAuth() && collection.ViewRule != nil && *collection.ViewRule != "" {
+		q.WithContext(e.Request.Context())
+
+		if !requestInfo.HasSuperuserAuth() && !shared && collection.ViewRule != nil {
 			resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
-			expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+			if *collection.ViewRule != "" {
+				expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				q.AndWhere(expr)
+			}
+			policiesExpr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionView, resolver)
 			if err != nil {
 				return err
 			}
+			if policiesExpr != nil {
+				q.AndWhere(policiesExpr)
+			}
 			resolver.UpdateQuery(q)
-			q.AndWhere(expr)
 		}
 		return nil
 	}
@@ -222,7 +278,11 @@ func recordCreate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		}
 
 		hasSuperuserAuth := requestInfo.HasSuperuserAuth()
-		if !hasSuperuserAuth && collection.CreateRule == nil {
+
+		// the signup invite is already verified by the signup-with-invite handler
+		isSignupInvite := requestInfo.Context == core.RequestInfoContextSignupInvite
+
+		if !hasSuperuserAuth && !isSignupInvite && collection.CreateRule == nil {
 			return e.ForbiddenError("Only superusers can perform this action.", nil)
 		}
 
@@ -233,9 +293,9 @@ func recordCreate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 			return firstApiError(err, e.BadRequestError("Failed to read the submitted data.", err))
 		}
 
-		// set a random password for the OAuth2 ignoring its plain password validators
+		// set a random password for the OAuth2 and LDAP ignoring its plain password validators
 		var skipPlainPasswordRecordValidators bool
-		if requestInfo.Context == core.RequestInfoContextOAuth2 {
+		if requestInfo.Context == core.RequestInfoContextOAuth2 || requestInfo.Context == core.RequestInfoContextLDAP {
 			if _, ok := data[core.FieldNamePassword]; !ok {
 				data[core.FieldNamePassword] = security.RandomString(30)
 				data[core.FieldNamePassword+"Confirm"] = data[core.FieldNamePassword]
@@ -261,69 +321,9 @@ func recordCreate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		}
 
 		// check the request and record data against the create and manage rules
-		if !hasSuperuserAuth && collection.CreateRule != nil {
-			dummyRecord := record.Clone()
-
-			dummyRandomPart := "__pb_create__" + security.PseudorandomString(6)
-
-			// set an id if it doesn't have already
-			// (the value doesn't matter; it is used only to minimize the breaking changes with earlier versions)
-			if dummyRecord.Id == "" {
-				dummyRecord.Id = "__temp_id__" + dummyRandomPart
-			}
-
-			// unset the verified field to prevent manage API rule misuse in case the rule relies on it
-			dummyRecord.SetVerified(false)
-
-			// export the dummy record data into db params
-			dummyExport, err := dummyRecord.DBExport(e.App)
-			if err != nil {
-				return e.BadRequestError("Failed to create record", fmt.Errorf("dummy DBExport error: %w", err))
-			}
-
-			dummyParams := make(dbx.Params, len(dummyExport))
-			selects := make([]string, 0, len(dummyExport))
-			var param string
-			for k, v := range dummyExport {
-				k = inflector.Columnify(k) // columnify is just as extra measure in case of custom fields
-				param = "__pb_create__" + k
-				dummyParams[param] = v
-				selects = append(selects, "{:"+param+"} AS [["+k+"]]")
-			}
-
-			// shallow clone the current collection
-			dummyCollection := *collection
-			dummyCollection.Id += dummyRandomPart
-			dummyCollection.Name += inflector.Columnify(dummyRandomPart)
-
-			withFrom := fmt.Sprintf("WITH {{%s}} as (SELECT %s)", dummyCollection.Name, strings.Join(selects, ","))
-
-			// check non-empty create rule
-			if *dummyCollection.CreateRule != "" {
-				ruleQuery := e.App.ConcurrentDB().Select("(1)").PreFragment(withFrom).From(dummyCollection.Name).AndBind(dummyParams)
-
-				resolver := core.NewRecordFieldResolver(e.App, &dummyCollection, requestInfo, true)
-
-				expr, err := search.FilterData(*dummyCollection.CreateRule).BuildExpr(resolver)
-				if err != nil {
-					return e.BadRequestError("Failed to create record", fmt.Errorf("create rule build expression failure: %w", err))
-				}
-				ruleQuery.AndWhere(expr)
-
-				resolver.UpdateQuery(ruleQuery)
-
-				var exists int
-				err = ruleQuery.Limit(1).Row(&exists)
-				if err != nil || exists == 0 {
-					return e.BadRequestError("Failed to create record", fmt.Errorf("create rule failure: %w", err))
-				}
-			}
-
-			// check for manage rule access
-			manageRuleQuery := e.App.ConcurrentDB().Select("(1)").PreFragment(withFrom).From(dummyCollection.Name).AndBind(dummyParams)
-			if !form.HasManageAccess() &&
-				hasAuthManageAccess(e.App, requestInfo, &dummyCollection, manageRuleQuery) {
-				form.GrantManagerAccess()
+		if !hasSuperuserAuth && !isSignupInvite {
+			if err := checkRecordCreateRule(e, requestInfo, record, form); err != nil {
+				return err
 			}
 		}
 
@@ -337,6 +337,7 @@ func recordCreate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		hookErr := e.App.OnRecordCreateRequest().Trigger(event, func(e *core.RecordRequestEvent) error {
 			form.SetApp(e.App)
 			form.SetRecord(e.Record)
+			form.SetContext(e.Request.Context())
 
 			err := form.Submit()
 			if err != nil {
@@ -380,6 +381,94 @@ func recordCreate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 	}
 }
 
+// checkRecordCreateRule checks the request and the new record data
+// against the collection create and manage API rules.
+//
+// It is expected to be called only for non-superuser requests and
+// grants the form manager access if the auth manage rule is satisfied.
+func checkRecordCreateRule(e *core.RequestEvent, requestInfo *core.RequestInfo, record *core.Record, form *forms.RecordUpsert) error {
+	collection := record.Collection()
+	if collection.CreateRule == nil {
+		return e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	dummyRecord := record.Clone()
+
+	dummyRandomPart := "__pb_create__" + security.PseudorandomString(6)
+
+	// set an id if it doesn't have already
+	// (the value doesn't matter; it is used only to minimize the breaking changes with earlier versions)
+	if dummyRecord.Id == "" {
+		dummyRecord.Id = "__temp_id__" + dummyRandomPart
+	}
+
+	// unset the verified field to prevent manage API rule misuse in case the rule relies on it
+	dummyRecord.SetVerified(false)
+
+	// export the dummy record data into db params
+	dummyExport, err := dummyRecord.DBExport(e.App)
+	if err != nil {
+		return e.BadRequestError("Failed to create record", fmt.Errorf("dummy DBExport error: %w", err))
+	}
+
+	dummyParams := make(dbx.Params, len(dummyExport))
+	selects := make([]string, 0, len(dummyExport))
+	var param string
+	for k, v := range dummyExport {
+		k = inflector.Columnify(k) // columnify is just as extra measure in case of custom fields
+		param = "__pb_create__" + k
+		dummyParams[param] = v
+		selects = append(selects, "{:"+param+"} AS [["+k+"]]")
+	}
+
+	// shallow clone the current collection
+	dummyCollection := *collection
+	dummyCollection.Id += dummyRandomPart
+	dummyCollection.Name += inflector.Columnify(dummyRandomPart)
+
+	withFrom := fmt.Sprintf("WITH {{%s}} as (SELECT %s)", dummyCollection.Name, strings.Join(selects, ","))
+
+	// check non-empty create rule and policies
+	if *dummyCollection.CreateRule != "" || len(dummyCollection.ActionPolicies(core.CollectionPolicyActionCreate)) > 0 {
+		ruleQuery := e.App.ConcurrentDB().Select("(1)").PreFragment(withFrom).From(dummyCollection.Name).AndBind(dummyParams)
+
+		resolver := core.NewRecordFieldResolver(e.App, &dummyCollection, requestInfo, true)
+
+		if *dummyCollection.CreateRule != "" {
+			expr, err := search.FilterData(*dummyCollection.CreateRule).BuildExpr(resolver)
+			if err != nil {
+				return e.BadRequestError("Failed to create record", fmt.Errorf("create rule build expression failure: %w", err))
+			}
+			ruleQuery.AndWhere(expr)
+		}
+
+		policiesExpr, err := dummyCollection.BuildPoliciesExpr(core.CollectionPolicyActionCreate, resolver)
+		if err != nil {
+			return e.BadRequestError("Failed to create record", fmt.Errorf("create policies build expression failure: %w", err))
+		}
+		if policiesExpr != nil {
+			ruleQuery.AndWhere(policiesExpr)
+		}
+
+		resolver.UpdateQuery(ruleQuery)
+
+		var exists int
+		err = ruleQuery.Limit(1).Row(&exists)
+		if err != nil || exists == 0 {
+			return e.BadRequestError("Failed to create record", fmt.Errorf("create rule failure: %w", err))
+		}
+	}
+
+	// check for manage rule access
+	manageRuleQuery := e.App.ConcurrentDB().Select("(1)").PreFragment(withFrom).From(dummyCollection.Name).AndBind(dummyParams)
+	if !form.HasManageAccess() &&
+		hasAuthManageAccess(e.App, requestInfo, &dummyCollection, manageRuleQuery) {
+		form.GrantManagerAccess()
+	}
+
+	return nil
+}
+
 func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error) func(e *core.RequestEvent) error {
 	return func(e *core.RequestEvent) error {
 		collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
@@ -412,6 +501,12 @@ func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 			return firstApiError(err, e.ForbiddenError("Only superusers can perform this action.", nil))
 		}
 
+		// forbid users and guests to use special precondition fields
+		err = checkForSuperuserOnlyRuleFields(requestInfo)
+		if err != nil {
+			return err
+		}
+
 		// eager fetch the record so that the modifiers field values can be resolved
 		record, err := e.App.FindRecordById(collection, recordId)
 		if err != nil {
@@ -427,15 +522,32 @@ func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		// available when accessing requestInfo.Body
 		requestInfo.Body = data
 
+		// the rules of the requested state transitions are checked together with the update rule
+		rules := recordStateTransitionRules(record, data)
+		if collection.UpdateRule != nil && *collection.UpdateRule != "" {
+			rules = append([]string{*collection.UpdateRule}, rules...)
+		}
+
 		ruleFunc := func(q *dbx.SelectQuery) error {
-			if !hasSuperuserAuth && collection.UpdateRule != nil && *collection.UpdateRule != "" {
+			q.WithContext(e.Request.Context())
+
+			if !hasSuperuserAuth {
 				resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
-				expr, err := search.FilterData(*collection.UpdateRule).BuildExpr(resolver)
+				for _, rule := range rules {
+					expr, err := search.FilterData(rule).BuildExpr(resolver)
+					if err != nil {
+						return err
+					}
+					q.AndWhere(expr)
+				}
+				policiesExpr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionUpdate, resolver)
 				if err != nil {
 					return err
 				}
+				if policiesExpr != nil {
+					q.AndWhere(policiesExpr)
+				}
 				resolver.UpdateQuery(q)
-				q.AndWhere(expr)
 			}
 			return nil
 		}
@@ -468,10 +580,18 @@ func recordUpdate(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		event.Record = record
 
 		hookErr := e.App.OnRecordUpdateRequest().Trigger(event, func(e *core.RecordRequestEvent) error {
-			form.SetApp(e.App)
-			form.SetRecord(e.Record)
+			err := e.App.RunInTransaction(func(txApp core.App) error {
+				err := checkRecordWritePrecondition(txApp, e.RequestEvent, requestInfo, e.Record)
+				if err != nil {
+					return err
+				}
 
-			err := form.Submit()
+				form.SetApp(txApp)
+				form.SetRecord(e.Record)
+				form.SetContext(e.Request.Context())
+
+				return form.Submit()
+			})
 			if err != nil {
 				return firstApiError(err, e.BadRequestError("Failed to update record.", err))
 			}
@@ -543,15 +663,32 @@ func recordDelete(responseWriteAfterTx bool, optFinalizer func(data any) error)
 			return e.ForbiddenError("Only superusers can perform this action.", nil)
 		}
 
+		// forbid users and guests to use special precondition fields
+		err = checkForSuperuserOnlyRuleFields(requestInfo)
+		if err != nil {
+			return err
+		}
+
 		ruleFunc := func(q *dbx.SelectQuery) error {
-			if !requestInfo.HasSuperuserAuth() && collection.DeleteRule != nil && *collection.DeleteRule != "" {
+			q.WithContext(e.Request.Context())
+
+			if !requestInfo.HasSuperuserAuth() && collection.DeleteRule != nil {
 				resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
-				expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
+				if *collection.DeleteRule != "" {
+					expr, err := search.FilterData(*collection.DeleteRule).BuildExpr(resolver)
+					if err != nil {
+						return err
+					}
+					q.AndWhere(expr)
+				}
+				policiesExpr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionDelete, resolver)
 				if err != nil {
 					return err
 				}
+				if policiesExpr != nil {
+					q.AndWhere(policiesExpr)
+				}
 				resolver.UpdateQuery(q)
-				q.AndWhere(expr)
 			}
 			return nil
 		}
@@ -569,7 +706,15 @@ func recordDelete(responseWriteAfterTx bool, optFinalizer func(data any) error)
 		event.Record = record
 
 		hookErr := e.App.OnRecordDeleteRequest().Trigger(event, func(e *core.RecordRequestEvent) error {
-			if err := e.App.Delete(e.Record); err != nil {
+			err := e.App.RunInTransaction(func(txApp core.App) error {
+				err := checkRecordWritePrecondition(txApp, e.RequestEvent, requestInfo, e.Record)
+				if err != nil {
+					return err
+				}
+
+				return txApp.DeleteWithContext(e.Request.Context(), e.Record)
+			})
+			if err != nil {
 				return firstApiError(err, e.BadRequestError("Failed to delete record. Make sure that the record is not part of a required relation reference.", err))
 			}
 
@@ -663,7 +808,18 @@ func recordDataFromRequest(e *core.RequestEvent, record *core.Record) (map[strin
 		}
 	}
 
-	return result, nil
+	event := new(core.RecordRequestTransformEvent)
+	event.RequestEvent = e
+	event.Collection = record.Collection()
+	event.Record = record
+	event.Data = result
+
+	err = e.App.OnRecordRequestTransform().Trigger(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event.Data, nil
 }
 
 func extractUploadedFiles(re *core.RequestEvent, collection *core.Collection, prefix string) (map[string][]*filesystem.File, error) {
@@ -708,6 +864,84 @@ func extractUploadedFiles(re *core.RequestEvent, collection *core.Collection, pr
 // hasAuthManageAccess checks whether the client is allowed to have
 // [forms.RecordUpsert] auth management permissions
 // (e.g. allowing to change system auth fields without oldPassword).
+// recordStateTransitionRules returns the non-empty rules of the
+// record state fields transitions requested with the submitted data.
+//
+// The invalid state transitions are ignored because they are
+// reported later as part of the record validations.
+func recordStateTransitionRules(record *core.Record, data map[string]any) []string {
+	var rules []string
+
+	for _, field := range record.Collection().Fields {
+		f, ok := field.(*core.StateField)
+		if !ok {
+			continue
+		}
+
+		raw, ok := data[f.Name]
+		if !ok {
+			continue
+		}
+
+		oldState := record.GetString(f.Name)
+		newState := cast.ToString(raw)
+		if oldState == newState {
+			continue // no state change
+		}
+
+		t := f.FindTransition(oldState, newState)
+		if t != nil && t.Rule != "" {
+			rules = append(rules, t.Rule)
+		}
+	}
+
+	return rules
+}
+
+// checkRecordWritePrecondition checks whether the currently stored
+// record state satisfies the optional "when" request query filter.
+//
+// It is expected to be called in the same transaction as the record
+// update/delete so that the record can't be changed in the meantime.
+//
+// Returns 412 Precondition Failed ApiError on mismatch.
+func checkRecordWritePrecondition(txApp core.App, e *core.RequestEvent, requestInfo *core.RequestInfo, record *core.Record) error {
+	rawWhen := e.Request.URL.Query().Get(whenQueryParam)
+	if rawWhen == "" {
+		return nil // no precondition
+	}
+
+	collection := record.Collection()
+
+	resolver := core.NewRecordFieldResolver(txApp, collection, requestInfo, requestInfo.HasSuperuserAuth())
+
+	expr, err := search.FilterData(rawWhen).BuildExpr(resolver)
+	if err != nil {
+		return e.BadRequestError("Invalid \""+whenQueryParam+"\" precondition filter.", err)
+	}
+
+	query := txApp.DB().Select("(1)").
+		From(collection.Name).
+		WithContext(e.Request.Context()).
+		AndWhere(dbx.HashExp{collection.Name + ".id": record.Id}).
+		AndWhere(expr)
+
+	resolver.UpdateQuery(query)
+
+	var exists int
+
+	err = query.Limit(1).Row(&exists)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if exists == 0 {
+		return router.NewApiError(http.StatusPreconditionFailed, "The record doesn't satisfy the request precondition.", nil)
+	}
+
+	return nil
+}
+
 func hasAuthManageAccess(app core.App, requestInfo *core.RequestInfo, collection *core.Collection, query *dbx.SelectQuery) bool {
 	if !collection.IsAuth() {
 		return false
@@ -237,6 +237,7 @@ func TestRecordCrudAuthOriginCreate(t *testing.T) {
 				"OnRecordCreateExecute":      1,
 				"OnRecordAfterCreateSuccess": 1,
 				"OnRecordValidate":           1,
+				"OnRecordRequestTransform":   1,
 			},
 		},
 	}
@@ -304,6 +305,7 @@ func TestRecordCrudAuthOriginUpdate(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				"OnRecordValidate":           1,
+				"OnRecordRequestTransform":   1,
 			},
 		},
 	}
@@ -0,0 +1,173 @@
+package apis
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/dbutils"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+var backRelationRegex = regexp.MustCompile(`^(\w+)_via_(\w+)$`)
+
+// recordBackRelationsList handles the paginated listing of a single
+// record back-relations (e.g. "/api/collections/posts/records/RECORD_ID/relations/comments_via_post").
+//
+// It is intended as companion of the "*_via_*" expand for records with
+// large number of back-relations and it accepts the same query parameters
+// as the regular records list request (page, perPage, sort, filter, expand, etc.).
+//
+// Similar to the expand, the request is checked against the parent collection view API rule
+// and the back-related records are filtered with the back-related collection view API rule
+// (with "expand" request context).
+//
+// The regular OnRecordsListRequest hook is triggered for the back-related collection.
+func recordBackRelationsList(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	matches := backRelationRegex.FindStringSubmatch(e.Request.PathValue("relation"))
+	if len(matches) != 3 {
+		return e.BadRequestError("Invalid back-relation format. Expected relCollection_via_relField.", nil)
+	}
+
+	relCollection, err := e.App.FindCachedCollectionByNameOrId(matches[1])
+	if err != nil || relCollection == nil {
+		return e.NotFoundError("Missing back-related collection context.", err)
+	}
+
+	relField, _ := relCollection.Fields.GetByName(matches[2]).(*core.RelationField)
+	if relField == nil || relField.CollectionId != collection.Id {
+		return e.NotFoundError(fmt.Sprintf("Missing back-relation field %q.", matches[2]), nil)
+	}
+
+	err = checkCollectionRateLimit(e, relCollection, "list")
+	if err != nil {
+		return err
+	}
+
+	recordId := e.Request.PathValue("id")
+	if recordId == "" {
+		return e.NotFoundError("", nil)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	hasSuperuserAuth := requestInfo.HasSuperuserAuth()
+	if !hasSuperuserAuth && (collection.ViewRule == nil || relCollection.ViewRule == nil) {
+		return e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	// forbid users and guests to query special filter/sort fields
+	err = checkForSuperuserOnlyRuleFields(requestInfo)
+	if err != nil {
+		return err
+	}
+
+	// check the parent record access
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		q.WithContext(e.Request.Context())
+
+		if !hasSuperuserAuth {
+			resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+			if *collection.ViewRule != "" {
+				expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				q.AndWhere(expr)
+			}
+			policiesExpr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionView, resolver)
+			if err != nil {
+				return err
+			}
+			if policiesExpr != nil {
+				q.AndWhere(policiesExpr)
+			}
+			resolver.UpdateQuery(q)
+		}
+		return nil
+	}
+
+	record, fetchErr := e.App.FindRecordById(collection, recordId, ruleFunc)
+	if fetchErr != nil || record == nil {
+		return firstApiError(err, e.NotFoundError("", fetchErr))
+	}
+
+	query := e.App.RecordQuery(relCollection).WithContext(e.Request.Context())
+
+	relColumn := relCollection.Name + "." + relField.Name
+	if relField.IsMultiple() {
+		query.AndWhere(dbx.Exists(dbx.NewExp(
+			fmt.Sprintf("SELECT 1 FROM %s __br__ WHERE __br__.value = {:backRelParentId}", dbutils.JSONEach(relColumn)),
+			dbx.Params{"backRelParentId": record.Id},
+		)))
+	} else {
+		query.AndWhere(dbx.HashExp{relColumn: record.Id})
+	}
+
+	// shallow clone the request info to resolve the
+	// back-related collection rule with "expand" context
+	expandRequestInfo := *requestInfo
+	expandRequestInfo.Context = core.RequestInfoContextExpand
+
+	fieldsResolver := core.NewRecordFieldResolver(e.App, relCollection, &expandRequestInfo, true)
+
+	if !hasSuperuserAuth {
+		if *relCollection.ViewRule != "" {
+			expr, err := search.FilterData(*relCollection.ViewRule).BuildExpr(fieldsResolver)
+			if err != nil {
+				return err
+			}
+			query.AndWhere(expr)
+		}
+
+		policiesExpr, err := relCollection.BuildPoliciesExpr(core.CollectionPolicyActionView, fieldsResolver)
+		if err != nil {
+			return err
+		}
+		if policiesExpr != nil {
+			query.AndWhere(policiesExpr)
+		}
+	}
+
+	// hidden fields are searchable only by superusers
+	fieldsResolver.SetAllowHiddenFields(hasSuperuserAuth)
+
+	searchProvider := search.NewProvider(fieldsResolver).Query(query)
+
+	// use rowid when available to minimize the need of a covering index with the "id" field
+	if !relCollection.IsView() {
+		searchProvider.CountCol("_rowid_")
+	}
+
+	records := []*core.Record{}
+	result, err := searchProvider.ParseAndExec(e.Request.URL.Query().Encode(), &records)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	event := new(core.RecordsListRequestEvent)
+	event.RequestEvent = e
+	event.Collection = relCollection
+	event.Records = records
+	event.Result = result
+
+	return e.App.OnRecordsListRequest().Trigger(event, func(e *core.RecordsListRequestEvent) error {
+		if err := EnrichRecords(e.RequestEvent, e.Records); err != nil {
+			return firstApiError(err, e.InternalServerError("Failed to enrich records", err))
+		}
+
+		return execAfterSuccessTx(true, e.App, func() error {
+			return e.JSON(http.StatusOK, e.Result)
+		})
+	})
+}
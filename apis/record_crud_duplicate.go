@@ -0,0 +1,333 @@
+package apis
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/forms"
+	"github.com/pocketbase/pocketbase/tools/dbutils"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/spf13/cast"
+)
+
+// recordDuplicate handles the record duplication requests.
+//
+// The new record is created with the source record field values
+// (excluding the id, autodate and password fields) and with copies
+// of the source record files stored under new keys.
+//
+// The request body could be used to override the duplicated values
+// (it accepts the same data as the regular create request).
+//
+// Superusers could also specify the "deep" query parameter to clone
+// the single-use relations, aka. referenced records that are not referenced by
+// any other record (the references of the cloned records are not followed).
+//
+// The request is checked against the source collection view and create API rules
+// and the regular OnRecordCreateRequest hook is triggered for the new record.
+func recordDuplicate(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	if !collection.IsBase() {
+		return e.BadRequestError("Unsupported collection type.", nil)
+	}
+
+	err = checkCollectionRateLimit(e, collection, "create")
+	if err != nil {
+		return err
+	}
+
+	recordId := e.Request.PathValue("id")
+	if recordId == "" {
+		return e.NotFoundError("", nil)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	hasSuperuserAuth := requestInfo.HasSuperuserAuth()
+	if !hasSuperuserAuth && (collection.ViewRule == nil || collection.CreateRule == nil) {
+		return e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	deep := cast.ToBool(e.Request.URL.Query().Get("deep"))
+	if deep && !hasSuperuserAuth {
+		return e.ForbiddenError("Only superusers can duplicate the record relations.", nil)
+	}
+
+	ruleFunc := func(q *dbx.SelectQuery) error {
+		if !hasSuperuserAuth {
+			resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+			if *collection.ViewRule != "" {
+				expr, err := search.FilterData(*collection.ViewRule).BuildExpr(resolver)
+				if err != nil {
+					return err
+				}
+				q.AndWhere(expr)
+			}
+			policiesExpr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionView, resolver)
+			if err != nil {
+				return err
+			}
+			if policiesExpr != nil {
+				q.AndWhere(policiesExpr)
+			}
+			resolver.UpdateQuery(q)
+		}
+		return nil
+	}
+
+	source, fetchErr := e.App.FindRecordById(collection, recordId, ruleFunc)
+	if fetchErr != nil || source == nil {
+		return e.NotFoundError("", fetchErr)
+	}
+
+	fsys, err := e.App.NewFilesystem()
+	if err != nil {
+		return e.InternalServerError("Failed to initialize the filesystem.", err)
+	}
+	defer fsys.Close()
+
+	record := core.NewRecord(collection)
+
+	form := forms.NewRecordUpsert(e.App, record)
+	if hasSuperuserAuth {
+		form.GrantSuperuserAccess()
+	}
+
+	sourceData, err := duplicateRecordData(fsys, source)
+	if err != nil {
+		return e.InternalServerError("Failed to read the source record files.", err)
+	}
+	form.Load(sourceData)
+
+	// load the submitted overrides
+	// (modifiers are resolved against the already duplicated values)
+	data, err := recordDataFromRequest(e, record)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to read the submitted data.", err))
+	}
+	form.Load(data)
+
+	// replace modifiers fields so that the resolved value is always
+	// available when accessing requestInfo.Body
+	for k, v := range data {
+		sourceData[k] = v
+	}
+	requestInfo.Body = sourceData
+
+	// check the request and record data against the create rule
+	if !hasSuperuserAuth {
+		if err := checkRecordCreateRule(e, requestInfo, record, form); err != nil {
+			return err
+		}
+	}
+
+	event := new(core.RecordRequestEvent)
+	event.RequestEvent = e
+	event.Collection = collection
+	event.Record = record
+
+	return e.App.OnRecordCreateRequest().Trigger(event, func(e *core.RecordRequestEvent) error {
+		originalApp := e.App
+
+		txErr := e.App.RunInTransaction(func(txApp core.App) error {
+			e.App = txApp
+
+			if deep {
+				if err := duplicateSingleUseRelations(txApp, fsys, source, e.Record); err != nil {
+					return err
+				}
+			}
+
+			form.SetApp(txApp)
+			form.SetRecord(e.Record)
+
+			return form.Submit()
+		})
+
+		e.App = originalApp
+
+		if txErr != nil {
+			return firstApiError(txErr, e.BadRequestError("Failed to duplicate record.", txErr))
+		}
+
+		if err := EnrichRecord(e.RequestEvent, e.Record); err != nil {
+			return firstApiError(err, e.InternalServerError("Failed to enrich record", err))
+		}
+
+		return execAfterSuccessTx(true, e.App, func() error {
+			return e.JSON(http.StatusOK, e.Record)
+		})
+	})
+}
+
+// duplicateRecordData returns the source record field values suitable
+// for loading in a new record (the id, autodate and password fields are skipped
+// and the stored files are replaced with reuploadable file copies).
+func duplicateRecordData(fsys *filesystem.System, source *core.Record) (map[string]any, error) {
+	data := map[string]any{}
+
+	for _, field := range source.Collection().Fields {
+		name := field.GetName()
+
+		switch field.Type() {
+		case core.FieldTypeAutodate, core.FieldTypePassword:
+			continue
+		case core.FieldTypeFile:
+			names := source.GetStringSlice(name)
+			files := make([]any, 0, len(names))
+			for _, filename := range names {
+				file, err := fsys.GetReuploadableFile(source.BaseFilesPath()+"/"+filename, false)
+				if err != nil {
+					return nil, fmt.Errorf("failed to load file %q: %w", filename, err)
+				}
+				files = append(files, file)
+			}
+			data[name] = files
+		default:
+			if name == core.FieldNameId {
+				continue
+			}
+			data[name] = source.GetRaw(name)
+		}
+	}
+
+	return data, nil
+}
+
+// duplicateSingleUseRelations clones the records referenced only by
+// the source record and replaces their ids in the duplicated record relation fields.
+//
+// NB! This method is expected to be called from inside of a transaction.
+func duplicateSingleUseRelations(app core.App, fsys *filesystem.System, source *core.Record, duplicate *core.Record) error {
+	// reuse the same clone in case the record is referenced in more than one field
+	clonedIds := map[string]string{}
+
+	for _, field := range source.Collection().Fields {
+		relField, ok := field.(*core.RelationField)
+		if !ok {
+			continue
+		}
+
+		// use the duplicate values in case they were explicitly changed
+		ids := duplicate.GetStringSlice(relField.Name)
+		if len(ids) == 0 || !slices.Equal(ids, source.GetStringSlice(relField.Name)) {
+			continue
+		}
+
+		relCollection, err := app.FindCachedCollectionByNameOrId(relField.CollectionId)
+		if err != nil {
+			return err
+		}
+
+		if !relCollection.IsBase() {
+			continue // clone only base collection records
+		}
+
+		refs, err := app.FindCollectionReferences(relCollection)
+		if err != nil {
+			return err
+		}
+
+		newIds := make([]string, 0, len(ids))
+
+		for _, id := range ids {
+			if clonedId, ok := clonedIds[relCollection.Id+id]; ok {
+				newIds = append(newIds, clonedId)
+				continue
+			}
+
+			isSingleUse, err := isSingleUseRelation(app, refs, id, source)
+			if err != nil {
+				return err
+			}
+
+			if !isSingleUse {
+				newIds = append(newIds, id)
+				continue
+			}
+
+			relRecord, err := app.FindRecordById(relCollection, id)
+			if err != nil {
+				return err
+			}
+
+			relData, err := duplicateRecordData(fsys, relRecord)
+			if err != nil {
+				return err
+			}
+
+			clone := core.NewRecord(relCollection)
+			clone.Load(relData)
+
+			if err := app.Save(clone); err != nil {
+				return fmt.Errorf("failed to duplicate %s relation record %q: %w", relField.Name, id, err)
+			}
+
+			clonedIds[relCollection.Id+id] = clone.Id
+			newIds = append(newIds, clone.Id)
+		}
+
+		duplicate.Set(relField.Name, newIds)
+	}
+
+	return nil
+}
+
+// isSingleUseRelation checks whether the record with the specified id is
+// referenced only by the owner record.
+func isSingleUseRelation(app core.App, refs map[*core.Collection][]core.Field, id string, owner *core.Record) (bool, error) {
+	for refCollection, fields := range refs {
+		if refCollection.IsView() {
+			continue
+		}
+
+		recordTableName := inflector.Columnify(refCollection.Name)
+
+		for _, field := range fields {
+			prefixedFieldName := recordTableName + "." + inflector.Columnify(field.GetName())
+
+			query := app.RecordQuery(refCollection).Select("(1)")
+
+			if refCollection.Id == owner.Collection().Id {
+				query.AndWhere(dbx.Not(dbx.HashExp{recordTableName + ".id": owner.Id}))
+			}
+
+			if opt, ok := field.(core.MultiValuer); !ok || !opt.IsMultiple() {
+				query.AndWhere(dbx.HashExp{prefixedFieldName: id})
+			} else {
+				query.AndWhere(dbx.Exists(dbx.NewExp(fmt.Sprintf(
+					`SELECT 1 FROM %s {{__je__}} WHERE [[__je__.value]]={:jevalue}`,
+					dbutils.JSONEach(prefixedFieldName),
+				), dbx.Params{
+					"jevalue": id,
+				})))
+			}
+
+			var exists int
+			err := query.Limit(1).Row(&exists)
+			if err != nil && !errors.Is(err, sql.ErrNoRows) {
+				return false, err
+			}
+
+			if exists > 0 {
+				return false, nil
+			}
+		}
+	}
+
+	return true, nil
+}
@@ -239,6 +239,7 @@ func TestRecordCrudExternalAuthCreate(t *testing.T) {
 				"OnRecordCreateExecute":      1,
 				"OnRecordAfterCreateSuccess": 1,
 				"OnRecordValidate":           1,
+				"OnRecordRequestTransform":   1,
 			},
 		},
 	}
@@ -306,6 +307,7 @@ func TestRecordCrudExternalAuthUpdate(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				"OnRecordValidate":           1,
+				"OnRecordRequestTransform":   1,
 			},
 		},
 	}
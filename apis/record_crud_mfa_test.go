@@ -314,6 +314,7 @@ func TestRecordCrudMFACreate(t *testing.T) {
 				"OnRecordCreateExecute":      1,
 				"OnRecordAfterCreateSuccess": 1,
 				"OnRecordValidate":           1,
+				"OnRecordRequestTransform":   1,
 			},
 		},
 	}
@@ -395,6 +396,7 @@ func TestRecordCrudMFAUpdate(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				"OnRecordValidate":           1,
+				"OnRecordRequestTransform":   1,
 			},
 		},
 	}
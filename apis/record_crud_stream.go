@@ -0,0 +1,144 @@
+package apis
+
+import (
+	"encoding/json"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/picker"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// recordsListStreamContentType is the records list Accept header value
+// that enables the NDJSON streaming mode.
+const recordsListStreamContentType = "application/x-ndjson"
+
+// recordsListStreamBatchSize is the max number of streamed records
+// that are enriched (expanded, etc.) and flushed together.
+const recordsListStreamBatchSize = 100
+
+// isRecordsListStreamRequest checks whether the request
+// accepts the NDJSON records list streaming response.
+func isRecordsListStreamRequest(e *core.RequestEvent) bool {
+	for _, part := range strings.Split(e.Request.Header.Get("Accept"), ",") {
+		mediaType, _, _ := mime.ParseMediaType(strings.TrimSpace(part))
+		if mediaType == recordsListStreamContentType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// recordsListStream writes the records matching the list query parameters
+// (without the pagination) as newline delimited JSON, one record per line.
+//
+// The records are written as they are read from the db so that very large
+// result sets could be processed by the clients without loading them in memory.
+func recordsListStream(e *core.RequestEvent, collection *core.Collection, searchProvider *search.Provider) error {
+	err := searchProvider.Parse(e.Request.URL.Query().Encode())
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	query, err := searchProvider.BuildQuery()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	event := new(core.RecordsListRequestEvent)
+	event.RequestEvent = e
+	event.Collection = collection
+	event.Records = []*core.Record{}
+	event.Result = &search.Result{
+		Items:      event.Records,
+		Page:       1,
+		PerPage:    -1,
+		TotalItems: -1,
+		TotalPages: -1,
+	}
+
+	return e.App.OnRecordsListRequest().Trigger(event, func(e *core.RecordsListRequestEvent) error {
+		return execAfterSuccessTx(true, e.App, func() error {
+			return writeRecordsStream(e.RequestEvent, collection, query.WithContext(e.Request.Context()))
+		})
+	})
+}
+
+// writeRecordsStream writes the query records in batches as newline delimited JSON.
+//
+// Once the first batch is written, the errors are only logged
+// because the response status and headers were already sent.
+func writeRecordsStream(e *core.RequestEvent, collection *core.Collection, query *dbx.SelectQuery) error {
+	rawFields := e.Request.URL.Query().Get(fieldsQueryParam)
+
+	encoder := json.NewEncoder(e.Response)
+
+	var started bool
+
+	batch := make([]*core.Record, 0, recordsListStreamBatchSize)
+
+	writeBatch := func() error {
+		if err := EnrichRecords(e, batch); err != nil {
+			return err
+		}
+
+		if !started {
+			e.Response.Header().Set("Content-Type", recordsListStreamContentType)
+			e.Response.Header().Set("Cache-Control", "no-store")
+			e.Response.Header().Set("X-Accel-Buffering", "no")
+			e.Response.WriteHeader(http.StatusOK)
+			started = true
+		}
+
+		for _, record := range batch {
+			var data any = record
+
+			if rawFields != "" {
+				picked, err := picker.Pick(record, rawFields)
+				if err != nil {
+					return err
+				}
+				data = picked
+			}
+
+			if err := encoder.Encode(data); err != nil {
+				return err
+			}
+		}
+
+		batch = batch[:0]
+
+		return e.Flush()
+	}
+
+	err := core.EachRecord(collection, query, func(record *core.Record) error {
+		batch = append(batch, record)
+
+		if len(batch) < recordsListStreamBatchSize {
+			return nil
+		}
+
+		return writeBatch()
+	})
+	if err == nil && (len(batch) > 0 || !started) {
+		err = writeBatch()
+	}
+
+	if err != nil {
+		if !started {
+			return firstApiError(err, e.BadRequestError("Failed to stream the records.", err))
+		}
+
+		e.App.Logger().Debug(
+			"Records list stream closed",
+			"collectionId", collection.Id,
+			"error", err,
+		)
+	}
+
+	return nil
+}
@@ -256,6 +256,7 @@ func TestRecordCrudSuperuserCreate(t *testing.T) {
 				"OnRecordCreateExecute":      1,
 				"OnRecordAfterCreateSuccess": 1,
 				"OnRecordValidate":           1,
+				"OnRecordRequestTransform":   1,
 			},
 		},
 	}
@@ -326,6 +327,7 @@ func TestRecordCrudSuperuserUpdate(t *testing.T) {
 				"OnRecordUpdateExecute":      1,
 				"OnRecordAfterUpdateSuccess": 1,
 				"OnRecordValidate":           1,
+				"OnRecordRequestTransform":   1,
 			},
 		},
 	}
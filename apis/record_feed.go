@@ -0,0 +1,137 @@
+package apis
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/feed"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindRecordFeedApi registers the collection records RSS/Atom feed api endpoints.
+func bindRecordFeedApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}").Bind(collectionPathRateLimit("", "feed"))
+	sub.GET("/feed.rss", recordsFeed(feed.RSSContentType, (*feed.Feed).EncodeRSS))
+	sub.GET("/feed.atom", recordsFeed(feed.AtomContentType, (*feed.Feed).EncodeAtom))
+}
+
+// recordsFeed returns a handler that writes the latest collection records
+// accessible by the request auth record (see [findFeedAuthRecord])
+// as feed entries based on the collection [core.FeedConfig] options.
+//
+// The response has an ETag header so that unchanged feeds could be
+// revalidated with If-None-Match (responding with 304).
+func recordsFeed(contentType string, encode func(f *feed.Feed, w io.Writer) error) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+		if err != nil || collection == nil || collection.Feed == nil {
+			return e.NotFoundError("Missing or invalid feed collection context.", err)
+		}
+
+		config := collection.Feed
+
+		return feedRecordsRequest(e, collection, config.DateField, config.Limit(), func(e *core.RecordsListRequestEvent) error {
+			var buf bytes.Buffer
+			if err := encode(newRecordsFeed(e.App, e.Collection, e.Records), &buf); err != nil {
+				return e.InternalServerError("Failed to encode the feed.", err)
+			}
+
+			hash := sha256.Sum256(buf.Bytes())
+			etag := `W/"` + hex.EncodeToString(hash[:16]) + `"`
+
+			e.Response.Header().Set("ETag", etag)
+			e.Response.Header().Set("Cache-Control", feedCacheControl(e.RequestEvent, config))
+
+			if e.Request.Header.Get("If-None-Match") == etag {
+				return e.NoContent(http.StatusNotModified)
+			}
+
+			return execAfterSuccessTx(true, e.App, func() error {
+				return e.Blob(http.StatusOK, contentType, buf.Bytes())
+			})
+		})
+	}
+}
+
+// feedCacheControl returns the feed response Cache-Control header value.
+//
+// Feeds authenticated with a feed token are allowed to be cached only by the feed readers.
+func feedCacheControl(e *core.RequestEvent, config *core.FeedConfig) string {
+	if config.CacheMaxAge <= 0 {
+		return "no-cache"
+	}
+
+	visibility := "public"
+	if e.Auth != nil || e.Request.URL.Query().Get("token") != "" {
+		visibility = "private"
+	}
+
+	return visibility + ", max-age=" + strconv.Itoa(config.CacheMaxAge)
+}
+
+// newRecordsFeed maps the provided records to feed entries.
+func newRecordsFeed(app core.App, collection *core.Collection, records []*core.Record) *feed.Feed {
+	config := collection.Feed
+
+	appURL := strings.TrimRight(app.Settings().Meta.AppURL, "/")
+
+	result := &feed.Feed{
+		Id:          appURL + "/api/collections/" + collection.Id + "/feed",
+		Title:       config.Title,
+		Link:        config.Link,
+		Description: config.Description,
+		Items:       make([]feed.Item, 0, len(records)),
+	}
+
+	if result.Title == "" {
+		result.Title = collection.Name
+	}
+
+	if result.Link == "" {
+		result.Link = appURL
+	}
+
+	for _, record := range records {
+		item := feed.Item{
+			Id:        appURL + "/api/collections/" + collection.Id + "/records/" + record.Id,
+			Title:     record.GetString(config.TitleField),
+			Published: record.GetDateTime(config.DateField).Time(),
+			Updated:   record.GetDateTime("updated").Time(),
+		}
+
+		if config.ContentField != "" {
+			item.Content = record.GetString(config.ContentField)
+		}
+
+		if config.AuthorField != "" {
+			item.Author = record.GetString(config.AuthorField)
+		}
+
+		if config.LinkTemplate != "" {
+			item.Link = core.ResolveRecordURLTemplate(config.LinkTemplate, record)
+		}
+
+		if item.Updated.Before(item.Published) {
+			item.Updated = item.Published
+		}
+
+		if item.Updated.After(result.Updated) {
+			result.Updated = item.Updated
+		}
+
+		result.Items = append(result.Items, item)
+	}
+
+	// fallback to the collection date so that the empty feed ETag remains stable
+	if result.Updated.IsZero() {
+		result.Updated = collection.Updated.Time()
+	}
+
+	return result
+}
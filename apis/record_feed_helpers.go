@@ -0,0 +1,88 @@
+package apis
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// feedRecordsRequest loads the latest (by dateField) collection records accessible by the
+// feed request auth record (see [findFeedAuthRecord]) and triggers with them the
+// OnRecordsListRequest hook, calling render as its final handler.
+//
+// Records with empty dateField are ignored.
+func feedRecordsRequest(
+	e *core.RequestEvent,
+	collection *core.Collection,
+	dateField string,
+	limit int,
+	render func(e *core.RecordsListRequestEvent) error,
+) error {
+	authRecord, err := findFeedAuthRecord(e, collection)
+	if err != nil {
+		return err
+	}
+
+	originalRequestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	// create a shallow copy of the cached request data and adjust it to the feed auth record (if any)
+	requestInfo := *originalRequestInfo
+	requestInfo.Context = core.RequestInfoContextFeed
+	requestInfo.Auth = authRecord
+
+	if collection.ListRule == nil && !requestInfo.HasSuperuserAuth() {
+		return e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	dateColumn := collection.Name + "." + dateField
+
+	query := e.App.RecordQuery(collection).
+		WithContext(e.Request.Context()).
+		AndWhere(dbx.Not(dbx.HashExp{dateColumn: ""})).
+		OrderBy(dateColumn + " DESC").
+		Limit(int64(limit))
+
+	if !requestInfo.HasSuperuserAuth() {
+		fieldsResolver := core.NewRecordFieldResolver(e.App, collection, &requestInfo, true)
+
+		if *collection.ListRule != "" {
+			expr, err := search.FilterData(*collection.ListRule).BuildExpr(fieldsResolver)
+			if err != nil {
+				return err
+			}
+			query.AndWhere(expr)
+		}
+
+		policiesExpr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionList, fieldsResolver)
+		if err != nil {
+			return err
+		}
+		if policiesExpr != nil {
+			query.AndWhere(policiesExpr)
+		}
+
+		fieldsResolver.UpdateQuery(query)
+	}
+
+	records := []*core.Record{}
+	if err := query.All(&records); err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	event := new(core.RecordsListRequestEvent)
+	event.RequestEvent = e
+	event.Collection = collection
+	event.Records = records
+	event.Result = &search.Result{
+		Items:      records,
+		Page:       1,
+		PerPage:    len(records),
+		TotalItems: -1,
+		TotalPages: -1,
+	}
+
+	return e.App.OnRecordsListRequest().Trigger(event, render)
+}
@@ -0,0 +1,202 @@
+package apis_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestRecordsFeed(t *testing.T) {
+	t.Parallel()
+
+	// generate the token in advance since it is part of the scenario URL
+	app, err := tests.NewTestApp()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo2Token, err := user.NewFeedToken("sz5l5z67tg7gku0", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app.Cleanup()
+
+	setupFeed := func(listRule *string, cacheMaxAge int) func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+		return func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+			collection, err := app.FindCollectionByNameOrId("demo2")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			collection.ListRule = listRule
+			collection.Feed = &core.FeedConfig{
+				Title:        "Demo feed",
+				TitleField:   "title",
+				DateField:    "created",
+				LinkTemplate: "https://example.com/posts/{title}",
+				MaxItems:     2,
+				CacheMaxAge:  cacheMaxAge,
+			}
+
+			if err := app.Save(collection); err != nil {
+				t.Fatal(err)
+			}
+
+			app.ResetEventCalls()
+		}
+	}
+
+	var etag string
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "collection without feed config",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/feed.rss",
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:           "rss feed with public list rule",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/feed.rss",
+			BeforeTestFunc: setupFeed(types.Pointer(""), 60),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`<rss version="2.0"><channel><title>Demo feed</title><link>http://localhost:8090</link>`,
+				`<item><title>test3</title><link>https://example.com/posts/test3</link>`,
+				`<guid isPermaLink="false">http://localhost:8090/api/collections/sz5l5z67tg7gku0/records/0yxhwia2amd8gec</guid>`,
+				`<item><title>test2</title>`,
+			},
+			NotExpectedContent: []string{
+				`<title>test1</title>`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := res.Header.Get("Content-Type"); v != "application/rss+xml; charset=utf-8" {
+					t.Fatalf("Expected the rss content type, got %q", v)
+				}
+
+				if v := res.Header.Get("Cache-Control"); v != "public, max-age=60" {
+					t.Fatalf("Expected public Cache-Control, got %q", v)
+				}
+
+				etag = res.Header.Get("ETag")
+				if etag == "" {
+					t.Fatal("Expected ETag header")
+				}
+			},
+		},
+		{
+			Name:           "atom feed with public list rule",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/feed.atom",
+			BeforeTestFunc: setupFeed(types.Pointer(""), 0),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`<feed xmlns="http://www.w3.org/2005/Atom"><id>http://localhost:8090/api/collections/sz5l5z67tg7gku0/feed</id><title>Demo feed</title>`,
+				`<entry><id>http://localhost:8090/api/collections/sz5l5z67tg7gku0/records/0yxhwia2amd8gec</id><title>test3</title><link href="https://example.com/posts/test3" rel="alternate"></link><published>2022-10-12T11:42:58Z</published>`,
+				`<title>test2</title>`,
+			},
+			NotExpectedContent: []string{
+				`<title>test1</title>`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := res.Header.Get("Content-Type"); v != "application/atom+xml; charset=utf-8" {
+					t.Fatalf("Expected the atom content type, got %q", v)
+				}
+
+				if v := res.Header.Get("Cache-Control"); v != "no-cache" {
+					t.Fatalf("Expected no-cache Cache-Control, got %q", v)
+				}
+			},
+		},
+		{
+			Name:               "guest with auth only list rule",
+			Method:             http.MethodGet,
+			URL:                "/api/collections/demo2/feed.rss",
+			BeforeTestFunc:     setupFeed(types.Pointer(`@request.auth.id != ""`), 60),
+			ExpectedStatus:     200,
+			ExpectedContent:    []string{`<title>Demo feed</title>`},
+			NotExpectedContent: []string{`<item>`},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+			},
+		},
+		{
+			Name:           "feed token with auth only list rule",
+			Method:         http.MethodGet,
+			URL:            "/api/collections/demo2/feed.rss?token=" + demo2Token,
+			BeforeTestFunc: setupFeed(types.Pointer(`@request.auth.id != ""`), 60),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`<title>test3</title>`,
+				`<title>test2</title>`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                    0,
+				"OnRecordsListRequest": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := res.Header.Get("Cache-Control"); v != "private, max-age=60" {
+					t.Fatalf("Expected private Cache-Control, got %q", v)
+				}
+			},
+		},
+		{
+			Name:            "invalid feed token",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/feed.atom?token=invalid",
+			BeforeTestFunc:  setupFeed(types.Pointer(`@request.auth.id != ""`), 60),
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "superusers only list rule",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo2/feed.atom?token=" + demo2Token,
+			BeforeTestFunc:  setupFeed(nil, 60),
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+	// revalidate with the ETag of the rss feed scenario
+	(&tests.ApiScenario{
+		Name:   "unchanged feed",
+		Method: http.MethodGet,
+		URL:    "/api/collections/demo2/feed.rss",
+		Headers: map[string]string{
+			"If-None-Match": etag,
+		},
+		BeforeTestFunc: setupFeed(types.Pointer(""), 60),
+		ExpectedStatus: 304,
+		ExpectedEvents: map[string]int{
+			"*":                    0,
+			"OnRecordsListRequest": 1,
+		},
+	}).Test(t)
+}
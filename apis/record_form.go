@@ -0,0 +1,117 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tools/captcha"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/spf13/cast"
+)
+
+// formCaptchaParam is the generic form submission body parameter
+// with the captcha response token.
+//
+// The provider specific widget parameter (e.g. "cf-turnstile-response") is also accepted.
+const formCaptchaParam = "captcha"
+
+// bindRecordFormApi registers the public form submission api endpoint.
+func bindRecordFormApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	rg.POST("/collections/{collection}/form", recordFormSubmit)
+}
+
+// recordFormSubmit creates a new record from the whitelisted
+// form fields of the path collection [core.FormConfig].
+//
+// The collection create API rule is not applied.
+func recordFormSubmit(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || collection.Form == nil {
+		return e.NotFoundError("Missing or invalid collection context.", err)
+	}
+
+	config := collection.Form
+
+	// the form limit is always applied to prevent spamming
+	// even when the global rate limits are disabled
+	rule := config.RateLimitRule()
+	err = checkRateLimitWithHeaders(e, "form:"+collection.Id+rule.String(), rule)
+	if err != nil {
+		return err
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	// silently discard the bot submissions so that they
+	// can't distinguish them from the regular ones
+	if config.HoneypotField != "" && cast.ToString(requestInfo.Body[config.HoneypotField]) != "" {
+		e.App.Logger().Debug("Discarded form honeypot submission", "collection", collection.Name, "ip", e.RealIP())
+		return e.NoContent(http.StatusNoContent)
+	}
+
+	if config.Captcha {
+		if err := verifyFormCaptcha(e, requestInfo); err != nil {
+			return err
+		}
+	}
+
+	record := core.NewRecord(collection)
+	for _, name := range config.Fields {
+		if v, ok := requestInfo.Body[name]; ok {
+			record.Set(name, v)
+		}
+	}
+
+	if err := e.App.Save(record); err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to submit the form.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to submit the form.", err)
+	}
+
+	if err := mails.QueueFormSubmissionNotification(e.App, record); err != nil {
+		e.App.Logger().Warn("Failed to queue form submission notification", "collection", collection.Name, "id", record.Id, "error", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// verifyFormCaptcha verifies the submitted form captcha response token
+// with the app settings captcha provider.
+func verifyFormCaptcha(e *core.RequestEvent, requestInfo *core.RequestInfo) error {
+	settings := e.App.Settings().Captcha
+	if !settings.Enabled() {
+		return e.BadRequestError("The captcha verification is not configured.", nil)
+	}
+
+	token := cast.ToString(requestInfo.Body[formCaptchaParam])
+	if token == "" {
+		token = cast.ToString(requestInfo.Body[captcha.ResponseParam(settings.Provider)])
+	}
+
+	verifier := &captcha.Verifier{
+		Provider:  settings.Provider,
+		Secret:    settings.Secret,
+		VerifyURL: settings.VerifyURL,
+	}
+
+	if err := verifier.Verify(e.Request.Context(), token, e.RealIP()); err != nil {
+		if errors.Is(err, captcha.ErrInvalidToken) {
+			return e.BadRequestError("Missing or invalid captcha.", validation.Errors{
+				formCaptchaParam: validation.NewError("validation_invalid_captcha", "Missing or invalid captcha."),
+			})
+		}
+
+		return e.InternalServerError("Failed to verify the captcha.", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,259 @@
+package apis_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordFormSubmit(t *testing.T) {
+	t.Parallel()
+
+	captchaServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		if r.PostForm.Get("secret") == "test_secret" && r.PostForm.Get("response") == "valid" {
+			w.Write([]byte(`{"success":true}`))
+		} else {
+			w.Write([]byte(`{"success":false}`))
+		}
+	}))
+	defer captchaServer.Close()
+
+	enableForm := func(t testing.TB, app *tests.TestApp, config *core.FormConfig) {
+		collection, err := app.FindCollectionByNameOrId("demo1")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		collection.CreateRule = nil
+		collection.Form = config
+		if err := app.Save(collection); err != nil {
+			t.Fatal(err)
+		}
+
+		app.Settings().Captcha.Provider = "turnstile"
+		app.Settings().Captcha.Secret = "test_secret"
+		app.Settings().Captcha.VerifyURL = captchaServer.URL
+
+		app.ResetEventCalls()
+	}
+
+	findSubmission := func(t testing.TB, app *tests.TestApp) *core.Record {
+		record, err := app.FindFirstRecordByData("demo1", "email", "submit@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+		return record
+	}
+
+	countQueuedMails := func(t testing.TB, app *tests.TestApp) int {
+		items := []*core.QueuedMail{}
+		if err := app.MailQueueQuery().All(&items); err != nil {
+			t.Fatal(err)
+		}
+		return len(items)
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "collection without form",
+			Method:          http.MethodPost,
+			URL:             "/api/collections/demo1/form",
+			Body:            strings.NewReader(`{"text":"test"}`),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "filled honeypot",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo1/form",
+			Body:   strings.NewReader(`{"text":"test","email":"submit@example.com","website":"spam"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableForm(t, app, &core.FormConfig{
+					Fields:        []string{"text", "email"},
+					HoneypotField: "website",
+				})
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{"*": 0},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if _, err := app.FindFirstRecordByData("demo1", "email", "submit@example.com"); err == nil {
+					t.Fatal("Expected the honeypot submission to be discarded")
+				}
+			},
+		},
+		{
+			Name:   "missing captcha",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo1/form",
+			Body:   strings.NewReader(`{"text":"test","email":"submit@example.com"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableForm(t, app, &core.FormConfig{
+					Fields:  []string{"text", "email"},
+					Captcha: true,
+				})
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"captcha":{"code":"validation_invalid_captcha"`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "invalid captcha",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo1/form",
+			Body:   strings.NewReader(`{"text":"test","email":"submit@example.com","captcha":"invalid"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableForm(t, app, &core.FormConfig{
+					Fields:  []string{"text", "email"},
+					Captcha: true,
+				})
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"captcha":{"code":"validation_invalid_captcha"`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "captcha without configured provider",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo1/form",
+			Body:   strings.NewReader(`{"text":"test","email":"submit@example.com","captcha":"valid"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableForm(t, app, &core.FormConfig{
+					Fields:  []string{"text", "email"},
+					Captcha: true,
+				})
+				app.Settings().Captcha.Provider = ""
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "invalid field value",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo1/form",
+			Body:   strings.NewReader(`{"text":"test","email":"invalid"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableForm(t, app, &core.FormConfig{Fields: []string{"text", "email"}})
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"email":{"code":"validation_is_email"`},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnModelCreate":            1,
+				"OnModelValidate":          1,
+				"OnRecordCreate":           1,
+				"OnRecordValidate":         1,
+				"OnModelAfterCreateError":  1,
+				"OnRecordAfterCreateError": 1,
+			},
+		},
+		{
+			Name:   "valid submission with captcha widget param (json)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo1/form",
+			Body:   strings.NewReader(`{"text":"test","email":"submit@example.com","bool":true,"number":123,"cf-turnstile-response":"valid"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableForm(t, app, &core.FormConfig{
+					Fields:        []string{"text", "email", "bool"},
+					HoneypotField: "website",
+					Captcha:       true,
+					NotifyEmails:  []string{"admin@example.com"},
+				})
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnRecordCreate":             1,
+				"OnRecordAfterCreateSuccess": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				record := findSubmission(t, app)
+
+				if v := record.GetString("text"); v != "test" {
+					t.Fatalf("Expected text %q, got %q", "test", v)
+				}
+
+				if !record.GetBool("bool") {
+					t.Fatal("Expected bool to be true")
+				}
+
+				if v := record.GetInt("number"); v != 0 {
+					t.Fatalf("Expected the non-whitelisted number field to be ignored, got %d", v)
+				}
+
+				if total := countQueuedMails(t, app); total != 1 {
+					t.Fatalf("Expected 1 queued notification email, got %d", total)
+				}
+			},
+		},
+		{
+			Name:   "valid submission (urlencoded)",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo1/form",
+			Body:   strings.NewReader(`text=test&email=submit%40example.com&website=`),
+			Headers: map[string]string{
+				"Content-Type": "application/x-www-form-urlencoded",
+			},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableForm(t, app, &core.FormConfig{
+					Fields:        []string{"text", "email"},
+					HoneypotField: "website",
+				})
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnRecordCreate":             1,
+				"OnRecordAfterCreateSuccess": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				findSubmission(t, app)
+
+				if total := countQueuedMails(t, app); total != 0 {
+					t.Fatalf("Expected no queued notification emails, got %d", total)
+				}
+			},
+		},
+		{
+			Name:   "rate limited",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo1/form",
+			Body:   strings.NewReader(`{"text":"test","email":"submit@example.com"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				enableForm(t, app, &core.FormConfig{
+					Fields:      []string{"text", "email"},
+					MaxRequests: 1,
+					Duration:    60,
+				})
+
+				// consume the only allowed submission
+				mux, err := e.Router.BuildMux()
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				req := httptest.NewRequest(http.MethodPost, "/api/collections/demo1/form", strings.NewReader(`{"text":"test"}`))
+				req.Header.Set("Content-Type", "application/json")
+				rec := httptest.NewRecorder()
+				mux.ServeHTTP(rec, req)
+				if rec.Code != 204 {
+					t.Fatalf("Expected the first submission to succeed, got %d (%s)", rec.Code, rec.Body.String())
+				}
+
+				app.ResetEventCalls()
+			},
+			ExpectedStatus:  429,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
 	"time"
@@ -20,6 +21,7 @@ import (
 const (
 	expandQueryParam = "expand"
 	fieldsQueryParam = "fields"
+	whenQueryParam   = "when"
 )
 
 var ErrMFA = errors.New("mfa required")
@@ -33,7 +35,12 @@ var ErrMFA = errors.New("mfa required")
 // Set authMethod to empty string if you want to ignore the MFA checks and the login alerts
 // (can be also adjusted additionally via the OnRecordAuthRequest hook).
 func RecordAuthResponse(e *core.RequestEvent, authRecord *core.Record, authMethod string, meta any) error {
-	token, tokenErr := authRecord.NewAuthToken()
+	claims, tokenErr := recordAuthTokenClaims(e.App, authRecord, true)
+	if tokenErr != nil {
+		return e.InternalServerError("Failed to resolve the auth token claims.", tokenErr)
+	}
+
+	token, tokenErr := authRecord.NewAuthTokenWithClaims(claims)
 	if tokenErr != nil {
 		return e.InternalServerError("Failed to create auth token.", tokenErr)
 	}
@@ -41,6 +48,23 @@ func RecordAuthResponse(e *core.RequestEvent, authRecord *core.Record, authMetho
 	return recordAuthResponse(e, authRecord, token, authMethod, meta)
 }
 
+// recordAuthTokenClaims triggers the [core.App.OnRecordAuthTokenClaims] hook
+// and returns the resolved custom claims for the new authRecord token.
+func recordAuthTokenClaims(app core.App, authRecord *core.Record, refreshable bool) (map[string]any, error) {
+	event := new(core.RecordAuthTokenClaimsEvent)
+	event.App = app
+	event.Record = authRecord
+	event.Refreshable = refreshable
+	event.Claims = map[string]any{}
+
+	err := app.OnRecordAuthTokenClaims().Trigger(event)
+	if err != nil {
+		return nil, err
+	}
+
+	return event.Claims, nil
+}
+
 func recordAuthResponse(e *core.RequestEvent, authRecord *core.Record, token string, authMethod string, meta any) error {
 	originalRequestInfo, err := e.RequestInfo()
 	if err != nil {
@@ -96,7 +120,7 @@ func recordAuthResponse(e *core.RequestEvent, authRecord *core.Record, token str
 			e.Record.IgnoreEmailVisibility(true)
 
 			// expand record relations
-			expands := strings.Split(e.Request.URL.Query().Get(expandQueryParam), ",")
+			expands := core.SplitExpands(e.Request.URL.Query().Get(expandQueryParam))
 			if len(expands) > 0 {
 				failed := e.App.ExpandRecord(e.Record, expands, expandFetch(e.App, &requestInfo))
 				if len(failed) > 0 {
@@ -270,7 +294,7 @@ func EnrichRecords(e *core.RequestEvent, records []*core.Record, defaultExpands
 	return triggerRecordEnrichHooks(e.App, info, records, func() error {
 		expands := defaultExpands
 		if param := info.Query[expandQueryParam]; param != "" {
-			expands = append(expands, strings.Split(param, ",")...)
+			expands = append(expands, core.SplitExpands(param)...)
 		}
 
 		err := defaultEnrichRecords(e.App, info, records, expands...)
@@ -346,6 +370,11 @@ func defaultEnrichRecords(app core.App, requestInfo *core.RequestInfo, records [
 		return fmt.Errorf("failed to resolve records flags: %w", err)
 	}
 
+	err = resolveRecordsLocks(app, records)
+	if err != nil {
+		return fmt.Errorf("failed to resolve records locks: %w", err)
+	}
+
 	if len(expands) > 0 {
 		expandErrs := app.ExpandRecords(records, expands, expandFetch(app, requestInfo))
 		if len(expandErrs) > 0 {
@@ -377,16 +406,26 @@ func expandFetch(app core.App, originalRequestInfo *core.RequestInfo) core.Expan
 				return fmt.Errorf("only superusers can view collection %q records", relCollection.Name)
 			}
 
+			resolver := core.NewRecordFieldResolver(app, relCollection, requestInfoPtr, true)
+
 			if *relCollection.ViewRule != "" {
-				resolver := core.NewRecordFieldResolver(app, relCollection, requestInfoPtr, true)
 				expr, err := search.FilterData(*(relCollection.ViewRule)).BuildExpr(resolver)
 				if err != nil {
 					return err
 				}
-				resolver.UpdateQuery(q)
 				q.AndWhere(expr)
 			}
 
+			policiesExpr, err := relCollection.BuildPoliciesExpr(core.CollectionPolicyActionView, resolver)
+			if err != nil {
+				return err
+			}
+			if policiesExpr != nil {
+				q.AndWhere(policiesExpr)
+			}
+
+			resolver.UpdateQuery(q)
+
 			return nil
 		})
 		if findErr != nil {
@@ -483,7 +522,7 @@ func autoResolveRecordsFlags(app core.App, records []*core.Record, requestInfo *
 	return nil
 }
 
-var ruleQueryParams = []string{search.FilterQueryParam, search.SortQueryParam}
+var ruleQueryParams = []string{search.FilterQueryParam, search.SortQueryParam, whenQueryParam}
 var superuserOnlyRuleFields = []string{"@collection.", "@request."}
 
 // checkForSuperuserOnlyRuleFields loosely checks and returns an error if
@@ -560,6 +599,58 @@ func execAfterSuccessTx(checkTx bool, app core.App, fn func() error) error {
 
 // -------------------------------------------------------------------
 
+// triggerRecordAuthFailure triggers the OnRecordAuthFailure hook for the
+// specified failed auth attempt and writes it as "authFailure" warning log.
+//
+// Hook errors are only logged to prevent changing the original failure response.
+func triggerRecordAuthFailure(e *core.RequestEvent, collection *core.Collection, failure *core.RecordAuthFailureEvent) {
+	failure.RequestEvent = e
+	failure.Collection = collection
+
+	err := e.App.OnRecordAuthFailure().Trigger(failure, func(f *core.RecordAuthFailureEvent) error {
+		// no logs retention
+		if f.App.Settings().Logs.MaxDays == 0 {
+			return nil
+		}
+
+		attrs := []any{
+			slog.String("type", "authFailure"),
+			slog.String("collectionId", f.Collection.Id),
+			slog.String("collectionName", f.Collection.Name),
+			slog.String("authMethod", f.AuthMethod),
+			slog.String("reason", f.Reason),
+			slog.String("identity", cutStr(f.Identity, 255)),
+			slog.String("provider", f.Provider),
+			slog.String("userAgent", cutStr(f.Request.UserAgent(), 2000)),
+		}
+
+		if f.Record != nil {
+			attrs = append(attrs, slog.String("recordId", f.Record.Id))
+		}
+
+		if f.App.Settings().Logs.LogIP {
+			attrs = append(
+				attrs,
+				slog.String("userIP", f.RealIP()),
+				slog.String("remoteIP", f.RemoteIP()),
+			)
+		}
+
+		if f.Error != nil {
+			attrs = append(attrs, slog.String("error", f.Error.Error()))
+		}
+
+		f.App.Logger().Warn("Failed "+f.AuthMethod+" auth attempt", attrs...)
+
+		return nil
+	})
+	if err != nil {
+		e.App.Logger().Debug("OnRecordAuthFailure hook error", slog.String("error", err.Error()))
+	}
+}
+
+// -------------------------------------------------------------------
+
 const maxAuthOrigins = 5
 
 func authAlert(e *core.RequestEvent, authRecord *core.Record) error {
@@ -602,6 +693,14 @@ func authAlert(e *core.RequestEvent, authRecord *core.Record) error {
 	//       The goroutine technically "leaks" but we assume that the OS will
 	//       terminate the connection after some time (usually after 3-4 mins).
 	if !isFirstLogin && currentOrigin.IsNew() && authRecord.Email() != "" {
+		info := mails.AuthAlertInfo{
+			IP:        e.RealIP(),
+			UserAgent: userAgent,
+		}
+		if geo := e.App.ResolveGeoIP(info.IP); geo != nil {
+			info.Country = geo.Country
+		}
+
 		mailSent := make(chan error, 1)
 
 		timer := time.AfterFunc(15*time.Second, func() {
@@ -609,7 +708,7 @@ func authAlert(e *core.RequestEvent, authRecord *core.Record) error {
 		})
 
 		routine.FireAndForget(func() {
-			err := mails.SendRecordAuthAlert(e.App, authRecord)
+			err := mails.SendRecordAuthAlert(e.App, authRecord, info)
 			timer.Stop()
 			mailSent <- err
 		})
@@ -759,3 +759,120 @@ func TestRecordAuthResponseMFACheck(t *testing.T) {
 		}
 	})
 }
+
+func TestRecordRequestTransform(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "create",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo2/records",
+			Body:   strings.NewReader(`{"name":"new","active":true}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.OnRecordRequestTransform("demo2").BindFunc(func(e *core.RecordRequestTransformEvent) error {
+					if !e.Record.IsNew() {
+						t.Fatal("Expected a new record")
+					}
+
+					// rename
+					e.Data["title"] = e.Data["name"]
+					delete(e.Data, "name")
+
+					// strip
+					delete(e.Data, "active")
+
+					return e.Next()
+				})
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"title":"new"`,
+				`"active":false`,
+			},
+			NotExpectedContent: []string{
+				`"name":`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                          0,
+				"OnRecordRequestTransform":   1,
+				"OnRecordCreateRequest":      1,
+				"OnModelCreate":              1,
+				"OnModelCreateExecute":       1,
+				"OnModelAfterCreateSuccess":  1,
+				"OnModelValidate":            1,
+				"OnRecordCreate":             1,
+				"OnRecordCreateExecute":      1,
+				"OnRecordAfterCreateSuccess": 1,
+				"OnRecordValidate":           1,
+				"OnRecordEnrich":             1,
+			},
+		},
+		{
+			Name:   "update (the transformed data is used by the API rules)",
+			Method: http.MethodPatch,
+			URL:    "/api/collections/demo2/records/0yxhwia2amd8gec",
+			Body:   strings.NewReader(`{"title":"test_update"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				collection, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					t.Fatal(err)
+				}
+				collection.UpdateRule = types.Pointer("@request.body.active = true")
+				if err := app.Save(collection); err != nil {
+					t.Fatal(err)
+				}
+
+				app.OnRecordRequestTransform("demo2").BindFunc(func(e *core.RecordRequestTransformEvent) error {
+					if e.Record.IsNew() {
+						t.Fatal("Expected an existing record")
+					}
+
+					e.Data["active"] = true
+
+					return e.Next()
+				})
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"title":"test_update"`,
+				`"active":true`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                          0,
+				"OnRecordRequestTransform":   1,
+				"OnRecordUpdateRequest":      1,
+				"OnModelUpdate":              1,
+				"OnModelUpdateExecute":       1,
+				"OnModelAfterUpdateSuccess":  1,
+				"OnModelValidate":            1,
+				"OnRecordUpdate":             1,
+				"OnRecordUpdateExecute":      1,
+				"OnRecordAfterUpdateSuccess": 1,
+				"OnRecordValidate":           1,
+				"OnRecordEnrich":             1,
+			},
+		},
+		{
+			Name:   "hook error",
+			Method: http.MethodPost,
+			URL:    "/api/collections/demo2/records",
+			Body:   strings.NewReader(`{"title":"new"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				app.OnRecordRequestTransform("demo2").BindFunc(func(e *core.RecordRequestTransformEvent) error {
+					return errors.New("example")
+				})
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents: map[string]int{
+				"*":                        0,
+				"OnRecordRequestTransform": 1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,172 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// recordLockKey is the custom record key with the active record lock
+// that is included in the records API and realtime responses.
+const recordLockKey = "@lock"
+
+const (
+	defaultRecordLockTTL = 300
+	maxRecordLockTTL     = 3600
+)
+
+// bindRecordLocksApi registers the advisory record lock api endpoints.
+func bindRecordLocksApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/records/{id}/lock").Bind(RequireAuth())
+	sub.POST("", recordLockAcquire)
+	sub.DELETE("", recordLockRelease)
+}
+
+type recordLockForm struct {
+	// TTL is the lock duration in seconds.
+	TTL int `form:"ttl" json:"ttl"`
+}
+
+func (form *recordLockForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.TTL, validation.Required, validation.Min(1), validation.Max(maxRecordLockTTL)),
+	)
+}
+
+// recordLockAcquire locks (or renews the lock of) the request path record
+// for the current authenticated record.
+func recordLockAcquire(e *core.RequestEvent) error {
+	record, err := recordLockTarget(e)
+	if err != nil {
+		return err
+	}
+
+	form := &recordLockForm{TTL: defaultRecordLockTTL}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if err := form.validate(); err != nil {
+		return e.BadRequestError("An error occurred while validating the submitted data.", err)
+	}
+
+	lock, err := e.App.AcquireRecordLock(record, e.Auth, time.Duration(form.TTL)*time.Second)
+	if err != nil {
+		if errors.Is(err, core.ErrRecordLocked) {
+			return NewApiError(http.StatusConflict, "The record is locked by another user.", nil)
+		}
+
+		return e.BadRequestError("Failed to lock the record.", err)
+	}
+
+	broadcastRecordLockChange(e.App, record)
+
+	return e.JSON(http.StatusOK, lock)
+}
+
+// recordLockRelease releases the request path record lock.
+//
+// Superusers could release locks held by other users.
+func recordLockRelease(e *core.RequestEvent) error {
+	record, err := recordLockTarget(e)
+	if err != nil {
+		return err
+	}
+
+	owner := e.Auth
+	if e.HasSuperuserAuth() {
+		owner = nil // force release
+	}
+
+	if err := e.App.ReleaseRecordLock(record, owner); err != nil {
+		if errors.Is(err, core.ErrRecordLocked) {
+			return NewApiError(http.StatusConflict, "The record is locked by another user.", nil)
+		}
+
+		return e.BadRequestError("Failed to release the record lock.", err)
+	}
+
+	broadcastRecordLockChange(e.App, record)
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// recordLockTarget returns the request path record if the
+// current authenticated record is allowed to update it.
+func recordLockTarget(e *core.RequestEvent) (*core.Record, error) {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return nil, e.NotFoundError("Missing collection context.", err)
+	}
+
+	if collection.IsView() {
+		return nil, e.BadRequestError("View collection records cannot be locked.", nil)
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return nil, e.NotFoundError("", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return nil, firstApiError(err, e.BadRequestError("", err))
+	}
+
+	canAccess, err := e.App.CanAccessRecord(record, requestInfo, collection.UpdateRule, core.CollectionPolicyActionUpdate)
+	if !canAccess {
+		return nil, e.ForbiddenError("You are not allowed to lock this record.", err)
+	}
+
+	return record, nil
+}
+
+// broadcastRecordLockChange notifies the record realtime subscribers
+// for the record lock change with a regular "update" event.
+func broadcastRecordLockChange(app core.App, record *core.Record) {
+	if err := realtimeBroadcastRecord(app, "update", record, false); err != nil {
+		app.Logger().Debug("Failed to broadcast record lock change", "error", err)
+	}
+}
+
+// resolveRecordsLocks sets the active lock (if any) of each provided record
+// as custom recordLockKey value.
+//
+// Note: Expects all records to be from the same collection!
+func resolveRecordsLocks(app core.App, records []*core.Record) error {
+	if len(records) == 0 || records[0].Collection().IsView() {
+		return nil
+	}
+
+	ids := make([]string, len(records))
+	for i, record := range records {
+		ids[i] = record.Id
+	}
+
+	locks, err := app.FindRecordLocks(records[0].Collection(), ids...)
+	if err != nil {
+		return err
+	}
+
+	if len(locks) == 0 {
+		return nil
+	}
+
+	mappedLocks := make(map[string]*core.RecordLock, len(locks))
+	for _, lock := range locks {
+		mappedLocks[lock.RecordRef] = lock
+	}
+
+	for _, record := range records {
+		if lock, ok := mappedLocks[record.Id]; ok {
+			record.WithCustomData(true)
+			record.Set(recordLockKey, lock)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,324 @@
+package apis
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"image"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/disintegration/imaging"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/ogimage"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/sync/singleflight"
+)
+
+// ogImagesDir is the record storage subdirectory with the generated Open Graph images.
+const ogImagesDir = "og"
+
+// ogImageStaleAfter is the min age of the outdated record Open Graph images before
+// deleting them (to avoid deleting images of concurrent generations that are about to be served).
+const ogImageStaleAfter = 5 * time.Minute
+
+// bindRecordOGImageApi registers the record Open Graph image api endpoint.
+func bindRecordOGImageApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	api := recordOGImageApi{
+		genPending: new(singleflight.Group),
+		genSem:     semaphore.NewWeighted(int64(runtime.NumCPU() + 2)),
+		genMaxWait: 60 * time.Second,
+	}
+
+	rg.GET("/collections/{collection}/records/{id}/og.png", api.image).
+		Bind(collectionPathRateLimit("", "ogImage"))
+}
+
+type recordOGImageApi struct {
+	// genSem is a semaphore to prevent too much concurrent
+	// requests generating new images at the same time.
+	genSem *semaphore.Weighted
+
+	// genPending represents a group of currently pending
+	// image generation processes.
+	genPending *singleflight.Group
+
+	// genMaxWait is the maximum waiting time for starting a new
+	// image generation process.
+	genMaxWait time.Duration
+}
+
+// image serves the Open Graph image of the request path record
+// based on the collection [core.OGImageConfig] options.
+//
+// The image is rendered on the first request and then served from
+// the storage until any of its source values change.
+func (api *recordOGImageApi) image(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || collection.OGImage == nil {
+		return e.NotFoundError("Missing or invalid Open Graph image collection context.", err)
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	if ok, _ := e.App.CanAccessRecord(record, requestInfo, collection.ViewRule, core.CollectionPolicyActionView); !ok {
+		return e.NotFoundError("", nil)
+	}
+
+	config := collection.OGImage
+
+	fsys, err := e.App.NewFilesystem()
+	if err != nil {
+		return e.InternalServerError("Filesystem initialization failure.", err)
+	}
+	defer fsys.Close()
+
+	key, err := ogImageKey(record, config)
+	if err != nil {
+		return e.InternalServerError("Failed to generate the image key.", err)
+	}
+
+	dir := record.BaseFilesPath() + "/" + ogImagesDir + "/"
+	servedPath := dir + key + ".png"
+
+	if exists, _ := fsys.Exists(servedPath); !exists {
+		if err := api.createImage(e, fsys, record, dir, servedPath); err != nil {
+			return e.InternalServerError("Failed to generate the image.", err)
+		}
+	}
+
+	e.Response.Header().Set("ETag", `"`+key+`"`)
+	e.Response.Header().Set("Cache-Control", ogImageCacheControl(collection))
+
+	// the image is expected to be embedded in other sites
+	e.Response.Header().Del("X-Frame-Options")
+
+	return execAfterSuccessTx(true, e.App, func() error {
+		if err := fsys.Serve(e.Response, e.Request, servedPath, "og.png"); err != nil {
+			return e.NotFoundError("", err)
+		}
+
+		return nil
+	})
+}
+
+// createImage renders and stores a new record Open Graph image at the imagePath
+// location, deleting the outdated record images from dir.
+func (api *recordOGImageApi) createImage(
+	e *core.RequestEvent,
+	fsys *filesystem.System,
+	record *core.Record,
+	dir string,
+	imagePath string,
+) error {
+	ch := api.genPending.DoChan(imagePath, func() (any, error) {
+		ctx, cancel := context.WithTimeout(e.Request.Context(), api.genMaxWait)
+		defer cancel()
+
+		if err := api.genSem.Acquire(ctx, 1); err != nil {
+			return nil, err
+		}
+		defer api.genSem.Release(1)
+
+		opts, err := ogImageOptions(e.App, fsys, record)
+		if err != nil {
+			return nil, err
+		}
+
+		var buf bytes.Buffer
+		if err := ogimage.Render(&buf, opts); err != nil {
+			return nil, err
+		}
+
+		if err := fsys.Upload(buf.Bytes(), imagePath); err != nil {
+			return nil, err
+		}
+
+		deleteOutdatedOGImages(e.App, fsys, dir, imagePath)
+
+		return nil, nil
+	})
+
+	res := <-ch
+
+	api.genPending.Forget(imagePath)
+
+	return res.Err
+}
+
+// deleteOutdatedOGImages deletes the dir images other than currentPath
+// that are older than [ogImageStaleAfter].
+//
+// Delete failures are only logged.
+func deleteOutdatedOGImages(app core.App, fsys *filesystem.System, dir string, currentPath string) {
+	files, err := fsys.List(dir)
+	if err != nil {
+		app.Logger().Warn("Failed to list the Open Graph images", slog.Any("error", err), slog.String("dir", dir))
+		return
+	}
+
+	staleBefore := time.Now().Add(-ogImageStaleAfter)
+
+	for _, f := range files {
+		if f.Key == currentPath || f.ModTime.After(staleBefore) {
+			continue
+		}
+
+		if err := fsys.Delete(f.Key); err != nil {
+			app.Logger().Warn("Failed to delete the outdated Open Graph image", slog.Any("error", err), slog.String("path", f.Key))
+		}
+	}
+}
+
+// ogImageOptions returns the render options of the provided record.
+//
+// Invalid background images are logged and ignored.
+func ogImageOptions(app core.App, fsys *filesystem.System, record *core.Record) (ogimage.Options, error) {
+	config := record.Collection().OGImage
+
+	opts := ogimage.Options{
+		Title: record.GetString(config.TitleField),
+	}
+
+	if config.SubtitleField != "" {
+		opts.Subtitle = record.GetString(config.SubtitleField)
+	}
+
+	if config.BackgroundColor != "" {
+		c, err := ogimage.ParseHexColor(config.BackgroundColor)
+		if err != nil {
+			return opts, err
+		}
+		opts.BackgroundColor = c
+	}
+
+	if config.TextColor != "" {
+		c, err := ogimage.ParseHexColor(config.TextColor)
+		if err != nil {
+			return opts, err
+		}
+		opts.TextColor = c
+	}
+
+	if filename := ogImageBackgroundFile(record); filename != "" {
+		background, err := loadOGImageBackground(app, fsys, record, filename)
+		if err != nil {
+			app.Logger().Warn(
+				"Fallback to background color - failed to load the Open Graph image background",
+				slog.Any("error", err),
+				slog.String("collectionId", record.Collection().Id),
+				slog.String("recordId", record.Id),
+				slog.String("filename", filename),
+			)
+		} else {
+			opts.Background = background
+		}
+	}
+
+	return opts, nil
+}
+
+func loadOGImageBackground(app core.App, fsys *filesystem.System, record *core.Record, filename string) (image.Image, error) {
+	collection := record.Collection()
+
+	fileRecord := record
+
+	// fetch the original view file field related record
+	if collection.IsView() {
+		var err error
+		fileRecord, err = app.FindRecordByViewFile(collection.Id, collection.OGImage.BackgroundField, filename)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	r, err := fsys.GetReader(fileRecord.BaseFilesPath() + "/" + filename)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+
+	// check the dimensions before decoding to prevent decompression bombs
+	config, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(config.Width)*int64(config.Height) > validators.DefaultMaxImagePixels {
+		return nil, errors.New("the background image dimensions are too large")
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+
+	// note: only the first frame for animated image formats
+	return imaging.Decode(r, imaging.AutoOrientation(true))
+}
+
+// ogImageBackgroundFile returns the first background field file of the record (if any).
+func ogImageBackgroundFile(record *core.Record) string {
+	field := record.Collection().OGImage.BackgroundField
+	if field == "" {
+		return ""
+	}
+
+	files := record.GetStringSlice(field)
+	if len(files) == 0 {
+		return ""
+	}
+
+	return files[0]
+}
+
+// ogImageKey returns a unique key based on the record image source values
+// so that the image could be regenerated only when any of them changes.
+func ogImageKey(record *core.Record, config *core.OGImageConfig) (string, error) {
+	raw, err := json.Marshal([]any{
+		config,
+		record.GetString(config.TitleField),
+		record.GetString(config.SubtitleField),
+		ogImageBackgroundFile(record),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	hash := sha256.Sum256(raw)
+
+	return hex.EncodeToString(hash[:16]), nil
+}
+
+// ogImageCacheControl returns the image response Cache-Control header value.
+//
+// Images of collections with non-empty view rule (including rules depending
+// on the request headers or query parameters) are allowed to be cached only by the client.
+func ogImageCacheControl(collection *core.Collection) string {
+	if collection.OGImage.CacheMaxAge <= 0 {
+		return "no-cache"
+	}
+
+	visibility := "private"
+	if collection.ViewRule != nil && *collection.ViewRule == "" {
+		visibility = "public"
+	}
+
+	return visibility + ", max-age=" + strconv.Itoa(collection.OGImage.CacheMaxAge)
+}
@@ -0,0 +1,71 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindRecordPermissionsApi registers the record computed permissions api endpoint.
+func bindRecordPermissionsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	rg.GET("/collections/{collection}/records/{id}/permissions", recordPermissions)
+}
+
+// recordPermissions returns which of the record API actions the
+// current request auth context is allowed to perform on the request path record
+// (evaluated against the collection API rules and policies).
+//
+// Note that the rules are evaluated with the current request data
+// (aka. `@request.body.*` fields are always empty).
+//
+// Records that are not viewable by the current auth context
+// are reported as 404 to avoid leaking their existence.
+func recordPermissions(e *core.RequestEvent) error {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return e.NotFoundError("Missing collection context.", err)
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	canView, err := e.App.CanAccessRecord(record, requestInfo, collection.ViewRule, core.CollectionPolicyActionView)
+	if !canView {
+		return e.NotFoundError("", err)
+	}
+
+	canList, err := e.App.CanAccessRecord(record, requestInfo, collection.ListRule, core.CollectionPolicyActionList)
+	if err != nil {
+		return e.BadRequestError("Failed to resolve the record list permission.", err)
+	}
+
+	var canUpdate, canDelete bool
+
+	// view collections are read-only
+	if !collection.IsView() {
+		canUpdate, err = e.App.CanAccessRecord(record, requestInfo, collection.UpdateRule, core.CollectionPolicyActionUpdate)
+		if err != nil {
+			return e.BadRequestError("Failed to resolve the record update permission.", err)
+		}
+
+		canDelete, err = e.App.CanAccessRecord(record, requestInfo, collection.DeleteRule, core.CollectionPolicyActionDelete)
+		if err != nil {
+			return e.BadRequestError("Failed to resolve the record delete permission.", err)
+		}
+	}
+
+	return e.JSON(http.StatusOK, map[string]bool{
+		"list":   canList,
+		"view":   canView,
+		"update": canUpdate,
+		"delete": canDelete,
+	})
+}
@@ -0,0 +1,135 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindRecordReactionsApi registers the record reactions api endpoints.
+func bindRecordReactionsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/records/{id}/reactions")
+	sub.GET("", recordReactionsView)
+	sub.POST("/{type}", recordReactionToggle).Bind(
+		RequireAuth(),
+		collectionPathRateLimit("", "reaction"),
+	)
+}
+
+type recordReactionsResponse struct {
+	// Counts is the reactions count of each collection reaction type.
+	Counts map[string]int `json:"counts"`
+
+	// Reacted is the list with the reaction types of the current authenticated record.
+	Reacted []string `json:"reacted"`
+}
+
+// recordReactionsView returns the reaction counts of the request path record
+// together with the reactions of the current authenticated record (if any).
+func recordReactionsView(e *core.RequestEvent) error {
+	record, err := recordReactionsTarget(e)
+	if err != nil {
+		return err
+	}
+
+	return recordReactionsJSON(e, record)
+}
+
+type recordReactionForm struct {
+	// Active is an optional explicit reaction state.
+	//
+	// If not set, the current reaction state is toggled.
+	Active *bool `form:"active" json:"active"`
+}
+
+// recordReactionToggle adds or removes the path type reaction of
+// the current authenticated record to the request path record.
+//
+// Clients could submit an explicit "active" state to make
+// the request idempotent (e.g. safe to retry).
+func recordReactionToggle(e *core.RequestEvent) error {
+	record, err := recordReactionsTarget(e)
+	if err != nil {
+		return err
+	}
+
+	form := &recordReactionForm{}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	reactionType := e.Request.PathValue("type")
+
+	if form.Active != nil {
+		err = e.App.SetRecordReaction(record, e.Auth, reactionType, *form.Active)
+	} else {
+		_, err = e.App.ToggleRecordReaction(record, e.Auth, reactionType)
+	}
+	if err != nil {
+		if errors.Is(err, core.ErrInvalidReactionType) {
+			return e.BadRequestError("Invalid or unsupported reaction type.", nil)
+		}
+
+		return e.BadRequestError("Failed to save the record reaction.", err)
+	}
+
+	if record.Collection().Reactions.CountsField != "" {
+		broadcastRecordReactionsChange(e.App, record)
+	}
+
+	return recordReactionsJSON(e, record)
+}
+
+// recordReactionsTarget returns the request path record if its collection
+// has enabled reactions and the current request is allowed to view it.
+func recordReactionsTarget(e *core.RequestEvent) (*core.Record, error) {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || collection.Reactions == nil {
+		return nil, e.NotFoundError("Missing or invalid collection context.", err)
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return nil, e.NotFoundError("", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return nil, firstApiError(err, e.BadRequestError("", err))
+	}
+
+	canAccess, err := e.App.CanAccessRecord(record, requestInfo, collection.ViewRule, core.CollectionPolicyActionView)
+	if !canAccess {
+		return nil, e.NotFoundError("", err)
+	}
+
+	return record, nil
+}
+
+func recordReactionsJSON(e *core.RequestEvent, record *core.Record) error {
+	counts, err := e.App.FindRecordReactionCounts(record)
+	if err != nil {
+		return e.InternalServerError("Failed to load the record reactions.", err)
+	}
+
+	result := recordReactionsResponse{Counts: counts, Reacted: []string{}}
+
+	if e.Auth != nil {
+		result.Reacted, err = e.App.FindAuthRecordReactions(record, e.Auth)
+		if err != nil {
+			return e.InternalServerError("Failed to load the record reactions.", err)
+		}
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+// broadcastRecordReactionsChange notifies the record realtime subscribers
+// for the reaction counts change with a regular "update" event.
+func broadcastRecordReactionsChange(app core.App, record *core.Record) {
+	if err := realtimeBroadcastRecord(app, "update", record, false); err != nil {
+		app.Logger().Debug("Failed to broadcast record reactions change", "error", err)
+	}
+}
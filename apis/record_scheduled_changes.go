@@ -0,0 +1,97 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// bindRecordScheduledChangesApi registers the record scheduled changes api endpoints.
+func bindRecordScheduledChangesApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/records/{id}/scheduled-changes").Bind(RequireSuperuserAuth())
+	sub.GET("", scheduledChangesList)
+	sub.POST("", scheduledChangeCreate)
+	sub.DELETE("/{changeId}", scheduledChangeDelete)
+}
+
+func scheduledChangesList(e *core.RequestEvent) error {
+	record, err := scheduledChangesRecord(e)
+	if err != nil {
+		return err
+	}
+
+	changes, err := e.App.FindRecordScheduledChanges(record)
+	if err != nil {
+		return e.InternalServerError("Failed to load the scheduled changes.", err)
+	}
+
+	return e.JSON(http.StatusOK, changes)
+}
+
+type scheduledChangeForm struct {
+	Data        types.JSONMap[any] `form:"data" json:"data"`
+	ScheduledAt types.DateTime     `form:"scheduledAt" json:"scheduledAt"`
+}
+
+func scheduledChangeCreate(e *core.RequestEvent) error {
+	record, err := scheduledChangesRecord(e)
+	if err != nil {
+		return err
+	}
+
+	form := &scheduledChangeForm{}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	change := core.NewScheduledChange(record)
+	change.Data = form.Data
+	change.ScheduledAt = form.ScheduledAt
+
+	if err := e.App.Save(change); err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to create scheduled change.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to create scheduled change.", err)
+	}
+
+	return e.JSON(http.StatusOK, change)
+}
+
+func scheduledChangeDelete(e *core.RequestEvent) error {
+	record, err := scheduledChangesRecord(e)
+	if err != nil {
+		return err
+	}
+
+	change, err := e.App.FindScheduledChangeById(e.Request.PathValue("changeId"))
+	if err != nil || change.CollectionRef != record.Collection().Id || change.RecordRef != record.Id {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.Delete(change); err != nil {
+		return e.BadRequestError("Failed to delete scheduled change.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+func scheduledChangesRecord(e *core.RequestEvent) (*core.Record, error) {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return nil, e.NotFoundError("Missing collection context.", err)
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return nil, e.NotFoundError("", err)
+	}
+
+	return record, nil
+}
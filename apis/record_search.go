@@ -0,0 +1,49 @@
+package apis
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	searchQueryParam     = "search"
+	searchModeQueryParam = "searchMode"
+)
+
+// applyRecordsSearch narrows the records list query with the
+// collection search index matches of the "search" query parameter
+// (see [core.BuildRecordSearchExpr]).
+//
+// Hidden indexed fields are searched only by superusers.
+func applyRecordsSearch(e *core.RequestEvent, collection *core.Collection, requestInfo *core.RequestInfo, query *dbx.SelectQuery) error {
+	term := requestInfo.Query[searchQueryParam]
+	if term == "" {
+		return nil
+	}
+
+	if collection.Search == nil {
+		return e.BadRequestError("The collection doesn't support search.", nil)
+	}
+
+	fields := make([]string, 0, len(collection.Search.Fields))
+	for _, name := range collection.Search.Fields {
+		field := collection.Fields.GetByName(name)
+		if field == nil || (field.GetHidden() && !requestInfo.HasSuperuserAuth()) {
+			continue
+		}
+		fields = append(fields, name)
+	}
+
+	if len(fields) == 0 {
+		return e.BadRequestError("The collection doesn't have searchable fields.", nil)
+	}
+
+	expr, err := core.BuildRecordSearchExpr(collection, term, requestInfo.Query[searchModeQueryParam], fields...)
+	if err != nil {
+		return e.BadRequestError("Invalid search parameters.", err)
+	}
+
+	query.AndWhere(expr)
+
+	return nil
+}
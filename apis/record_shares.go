@@ -0,0 +1,151 @@
+package apis
+
+import (
+	"net/http"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+const (
+	defaultRecordShareTTL = 7 * 86400
+	maxRecordShareTTL     = 365 * 86400
+)
+
+// bindRecordSharesApi registers the record share links api endpoints.
+func bindRecordSharesApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/records/{id}/shares").Bind(RequireAuth())
+	sub.GET("", recordSharesList)
+	sub.POST("", recordShareCreate)
+	sub.DELETE("/{shareId}", recordShareDelete)
+}
+
+type recordShareForm struct {
+	Scope string `form:"scope" json:"scope"`
+
+	// TTL is the share duration in seconds.
+	TTL int `form:"ttl" json:"ttl"`
+}
+
+func (form *recordShareForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Scope, validation.Required, validation.In(core.RecordShareScopeView, core.RecordShareScopeComment)),
+		validation.Field(&form.TTL, validation.Required, validation.Min(1), validation.Max(maxRecordShareTTL)),
+	)
+}
+
+// recordSharesList returns the active shares of the request path record.
+func recordSharesList(e *core.RequestEvent) error {
+	record, err := recordShareTarget(e)
+	if err != nil {
+		return err
+	}
+
+	shares, err := e.App.FindRecordShares(record)
+	if err != nil {
+		return e.BadRequestError("Failed to load the record shares.", err)
+	}
+
+	return e.JSON(http.StatusOK, shares)
+}
+
+// recordShareCreate creates a new share link of the request path record.
+//
+// The plain share token is returned only once with the create response.
+func recordShareCreate(e *core.RequestEvent) error {
+	record, err := recordShareTarget(e)
+	if err != nil {
+		return err
+	}
+
+	form := &recordShareForm{
+		Scope: core.RecordShareScopeView,
+		TTL:   defaultRecordShareTTL,
+	}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if err := form.validate(); err != nil {
+		return e.BadRequestError("An error occurred while validating the submitted data.", err)
+	}
+
+	share, token, err := e.App.CreateRecordShare(record, e.Auth, form.Scope, time.Duration(form.TTL)*time.Second)
+	if err != nil {
+		return e.BadRequestError("Failed to create the record share.", err)
+	}
+
+	return e.JSON(http.StatusOK, struct {
+		*core.RecordShare
+		Token string `json:"token"`
+	}{share, token})
+}
+
+// recordShareDelete revokes the specified share of the request path record.
+func recordShareDelete(e *core.RequestEvent) error {
+	record, err := recordShareTarget(e)
+	if err != nil {
+		return err
+	}
+
+	share := &core.RecordShare{}
+
+	err = e.App.RecordShareQuery().
+		AndWhere(dbx.HashExp{
+			"id":            e.Request.PathValue("shareId"),
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+		}).
+		Limit(1).
+		One(share)
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.Delete(share); err != nil {
+		return e.BadRequestError("Failed to delete the record share.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// recordShareTarget returns the request path record if the
+// current authenticated record is allowed to update it.
+func recordShareTarget(e *core.RequestEvent) (*core.Record, error) {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return nil, e.NotFoundError("Missing collection context.", err)
+	}
+
+	if collection.IsView() {
+		return nil, e.BadRequestError("View collection records cannot be shared.", nil)
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return nil, e.NotFoundError("", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return nil, firstApiError(err, e.BadRequestError("", err))
+	}
+
+	canAccess, err := e.App.CanAccessRecord(record, requestInfo, collection.UpdateRule, core.CollectionPolicyActionUpdate)
+	if !canAccess {
+		return nil, e.ForbiddenError("You are not allowed to share this record.", err)
+	}
+
+	return record, nil
+}
+
+// isSharedRecordRequest checks whether the request has a valid share token
+// for the specified collection record.
+func isSharedRecordRequest(requestInfo *core.RequestInfo, collection *core.Collection, recordId string) bool {
+	return requestInfo.Share != nil &&
+		requestInfo.Share.CollectionRef == collection.Id &&
+		requestInfo.Share.RecordRef == recordId
+}
@@ -0,0 +1,72 @@
+package apis
+
+import (
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	includeDeletedQueryParam = "includeDeleted"
+	deletedAfterQueryParam   = "deletedAfter"
+
+	includeDeletedTombstones = "tombstones"
+
+	// maxListTombstones is the max number of tombstones returned with a single records list response.
+	//
+	// Clients could fetch the remaining ones by using the last returned
+	// tombstone deletedAt as deletedAfter query param.
+	maxListTombstones = 1000
+)
+
+// recordsListWithTombstones defines the records list response
+// when ?includeDeleted=tombstones is requested.
+type recordsListWithTombstones struct {
+	*search.Result
+
+	Tombstones []*core.Tombstone `json:"tombstones"`
+}
+
+// SearchResult implements the [picker.SearchResultWrapper] interface.
+func (r recordsListWithTombstones) SearchResult() *search.Result {
+	return r.Result
+}
+
+// findListTombstones loads the collection deletions log entries
+// requested with the ?includeDeleted=tombstones&deletedAfter=... query params.
+//
+// Returns nil if no tombstones were requested.
+func findListTombstones(e *core.RequestEvent, collection *core.Collection) ([]*core.Tombstone, error) {
+	query := e.Request.URL.Query()
+
+	include := query.Get(includeDeletedQueryParam)
+	if include == "" {
+		return nil, nil
+	}
+
+	if include != includeDeletedTombstones {
+		return nil, e.BadRequestError("Invalid includeDeleted value - only 'tombstones' is supported.", nil)
+	}
+
+	if !e.App.Settings().Tombstones.Enabled {
+		return nil, e.BadRequestError("The deletions tracking is not enabled.", nil)
+	}
+
+	var deletedAfter time.Time
+	if raw := query.Get(deletedAfterQueryParam); raw != "" {
+		dt, err := types.ParseDateTime(raw)
+		if err != nil || dt.IsZero() {
+			return nil, e.BadRequestError("Invalid deletedAfter datetime.", err)
+		}
+		deletedAfter = dt.Time()
+	}
+
+	tombstones, err := e.App.FindTombstones(collection.Id, deletedAfter, maxListTombstones)
+	if err != nil {
+		return nil, e.InternalServerError("Failed to load the collection tombstones.", err)
+	}
+
+	return tombstones, nil
+}
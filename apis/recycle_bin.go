@@ -0,0 +1,93 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// bindRecycleBinApi registers the deleted records recycle bin api endpoints.
+func bindRecycleBinApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/recycle-bin").Bind(RequireSuperuserAuth())
+	sub.GET("", recycleBinList).Bind(SkipSuccessActivityLog())
+	sub.GET("/{id}", recycleBinView).Bind(SkipSuccessActivityLog())
+	sub.POST("/{id}/restore", recycleBinRestore)
+	sub.DELETE("/{id}", recycleBinPurge)
+}
+
+var recycleBinFilterFields = []string{
+	"id", "created", "collectionId", "collectionName", "recordId",
+}
+
+func recycleBinList(e *core.RequestEvent) error {
+	fieldResolver := search.NewSimpleFieldResolver(recycleBinFilterFields...)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(e.App.RecycleBinQuery()).
+		ParseAndExec(e.Request.URL.Query().Encode(), &[]*core.RecycledRecord{})
+
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+func recycleBinView(e *core.RequestEvent) error {
+	item, err := findRecycledRecord(e)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, item)
+}
+
+func recycleBinRestore(e *core.RequestEvent) error {
+	item, err := findRecycledRecord(e)
+	if err != nil {
+		return err
+	}
+
+	record, err := e.App.RestoreRecycledRecord(item)
+	if err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to restore record.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to restore record.", err)
+	}
+
+	return e.JSON(http.StatusOK, record)
+}
+
+func recycleBinPurge(e *core.RequestEvent) error {
+	item, err := findRecycledRecord(e)
+	if err != nil {
+		return err
+	}
+
+	if err := e.App.PurgeRecycledRecord(item); err != nil {
+		return e.BadRequestError("Failed to purge recycle bin entry.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+func findRecycledRecord(e *core.RequestEvent) (*core.RecycledRecord, error) {
+	id := e.Request.PathValue("id")
+	if id == "" {
+		return nil, e.NotFoundError("", nil)
+	}
+
+	item, err := e.App.FindRecycledRecordById(id)
+	if err != nil || item == nil {
+		return nil, e.NotFoundError("", err)
+	}
+
+	return item, nil
+}
@@ -0,0 +1,105 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// shortLinkPathPrefix is the path prefix of the redirects created without explicit path.
+const shortLinkPathPrefix = "/s/"
+
+const shortLinkCodeAlphabet = "abcdefghijkmnpqrstuvwxyz23456789"
+
+// bindRedirectsApi registers the managed redirects api endpoints.
+func bindRedirectsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/redirects").Bind(RequireSuperuserAuth())
+	sub.GET("", redirectsList)
+	sub.POST("", redirectCreate)
+	sub.PATCH("/{id}", redirectUpdate)
+	sub.DELETE("/{id}", redirectDelete)
+}
+
+func redirectsList(e *core.RequestEvent) error {
+	redirects := []*core.Redirect{}
+
+	if err := e.App.RedirectQuery().OrderBy("path ASC").All(&redirects); err != nil {
+		return e.InternalServerError("Failed to load the redirects.", err)
+	}
+
+	return e.JSON(http.StatusOK, redirects)
+}
+
+func redirectCreate(e *core.RequestEvent) error {
+	return saveRedirect(e, &core.Redirect{Status: http.StatusFound})
+}
+
+func redirectUpdate(e *core.RequestEvent) error {
+	redirect, err := e.App.FindRedirectById(e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	return saveRedirect(e, redirect)
+}
+
+func redirectDelete(e *core.RequestEvent) error {
+	redirect, err := e.App.FindRedirectById(e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.Delete(redirect); err != nil {
+		return e.BadRequestError("Failed to delete redirect.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+type redirectForm struct {
+	Path    string         `form:"path" json:"path"`
+	Target  string         `form:"target" json:"target"`
+	Status  int            `form:"status" json:"status"`
+	Expires types.DateTime `form:"expires" json:"expires"`
+}
+
+// saveRedirect binds the request body to the provided redirect and persists it.
+//
+// New redirects without path are assigned with a random short link path (eg. "/s/abc123").
+func saveRedirect(e *core.RequestEvent, redirect *core.Redirect) error {
+	form := &redirectForm{
+		Path:    redirect.Path,
+		Target:  redirect.Target,
+		Status:  redirect.Status,
+		Expires: redirect.Expires,
+	}
+
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if form.Path == "" && redirect.IsNew() {
+		form.Path = shortLinkPathPrefix + security.RandomStringWithAlphabet(7, shortLinkCodeAlphabet)
+	}
+
+	redirect.Path = form.Path
+	redirect.Target = form.Target
+	redirect.Status = form.Status
+	redirect.Expires = form.Expires
+
+	if err := e.App.Save(redirect); err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to save redirect.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to save redirect.", err)
+	}
+
+	return e.JSON(http.StatusOK, redirect)
+}
@@ -0,0 +1,164 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+// bindRolesApi registers the roles and role assignments api endpoints.
+func bindRolesApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	roles := rg.Group("/roles").Bind(RequireSuperuserAuth())
+	roles.GET("", rolesList)
+	roles.POST("", roleCreate)
+	roles.PATCH("/{id}", roleUpdate)
+	roles.DELETE("/{id}", roleDelete)
+
+	assignments := rg.Group("/collections/{collection}/records/{id}/roles").Bind(RequireSuperuserAuth())
+	assignments.GET("", recordRolesList)
+	assignments.PUT("/{roleId}", recordRoleAssign)
+	assignments.DELETE("/{roleId}", recordRoleUnassign)
+}
+
+func rolesList(e *core.RequestEvent) error {
+	roles := []*core.Role{}
+
+	if err := e.App.RoleQuery().OrderBy("name ASC").All(&roles); err != nil {
+		return e.InternalServerError("Failed to load the roles.", err)
+	}
+
+	return e.JSON(http.StatusOK, roles)
+}
+
+func roleCreate(e *core.RequestEvent) error {
+	return saveRole(e, &core.Role{})
+}
+
+func roleUpdate(e *core.RequestEvent) error {
+	role, err := e.App.FindRoleById(e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	return saveRole(e, role)
+}
+
+func roleDelete(e *core.RequestEvent) error {
+	role, err := e.App.FindRoleById(e.Request.PathValue("id"))
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.Delete(role); err != nil {
+		return e.BadRequestError("Failed to delete role.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+type roleForm struct {
+	Name        string   `form:"name" json:"name"`
+	Description string   `form:"description" json:"description"`
+	Permissions []string `form:"permissions" json:"permissions"`
+}
+
+func saveRole(e *core.RequestEvent, role *core.Role) error {
+	form := &roleForm{
+		Name:        role.Name,
+		Description: role.Description,
+		Permissions: role.Permissions,
+	}
+
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	role.Name = form.Name
+	role.Description = form.Description
+	role.Permissions = form.Permissions
+	if role.Permissions == nil {
+		role.Permissions = []string{}
+	}
+
+	if err := e.App.Save(role); err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to save role.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to save role.", err)
+	}
+
+	return e.JSON(http.StatusOK, role)
+}
+
+// -------------------------------------------------------------------
+
+// recordRolesList returns the roles assigned to the request path auth record.
+func recordRolesList(e *core.RequestEvent) error {
+	record, err := roleAssignmentTarget(e)
+	if err != nil {
+		return err
+	}
+
+	roles, err := e.App.FindAuthRecordRoles(record)
+	if err != nil {
+		return e.InternalServerError("Failed to load the record roles.", err)
+	}
+
+	return e.JSON(http.StatusOK, roles)
+}
+
+func recordRoleAssign(e *core.RequestEvent) error {
+	record, err := roleAssignmentTarget(e)
+	if err != nil {
+		return err
+	}
+
+	role, err := e.App.FindRoleById(e.Request.PathValue("roleId"))
+	if err != nil {
+		return e.NotFoundError("Missing role.", err)
+	}
+
+	if err := e.App.AssignRole(record, role); err != nil {
+		return e.BadRequestError("Failed to assign role.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+func recordRoleUnassign(e *core.RequestEvent) error {
+	record, err := roleAssignmentTarget(e)
+	if err != nil {
+		return err
+	}
+
+	role, err := e.App.FindRoleById(e.Request.PathValue("roleId"))
+	if err != nil {
+		return e.NotFoundError("Missing role.", err)
+	}
+
+	if err := e.App.UnassignRole(record, role); err != nil {
+		return e.BadRequestError("Failed to unassign role.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// roleAssignmentTarget returns the request path auth record.
+func roleAssignmentTarget(e *core.RequestEvent) (*core.Record, error) {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || !collection.IsAuth() {
+		return nil, e.NotFoundError("Missing or invalid auth collection context.", err)
+	}
+
+	record, err := e.App.FindRecordById(collection, e.Request.PathValue("id"))
+	if err != nil {
+		return nil, e.NotFoundError("", err)
+	}
+
+	return record, nil
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/tls"
 	"errors"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"path/filepath"
@@ -14,8 +15,10 @@ import (
 
 	"github.com/fatih/color"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/acmedns"
 	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/listenfd"
 	"github.com/pocketbase/pocketbase/tools/routine"
 	"github.com/pocketbase/pocketbase/ui"
 	"golang.org/x/crypto/acme"
@@ -41,8 +44,41 @@ type ServeConfig struct {
 	// redirect will be automatically added.
 	CertificateDomains []string
 
+	// CertificateDNSProvider is an optional ACME DNS-01 challenge provider.
+	//
+	// If set, the TLS certificates are issued using the DNS-01 challenge
+	// instead of the default HTTP-01/TLS-ALPN-01 challenges, which allows
+	// wildcard CertificateDomains (eg. "*.example.com") and instances
+	// that are not publicly reachable on port 80/443.
+	CertificateDNSProvider acmedns.Provider
+
+	// CertificateHostPolicy is an optional function that allows issuing
+	// TLS certificates on-demand for host names that are not listed in
+	// CertificateDomains (eg. SaaS tenants custom domains).
+	//
+	// The host is allowed if the function returns nil error.
+	// The results are cached for a short period of time.
+	//
+	// See also [RecordHostPolicy].
+	CertificateHostPolicy func(ctx context.Context, host string) error
+
+	// CertificateCache is an optional storage for the issued TLS certificates
+	// (default to a local "pb_data/.autocert_cache" directory).
+	//
+	// It could be used to share the certificates between multiple
+	// instances behind a load balancer (see the "tools/certcache" package).
+	CertificateCache autocert.Cache
+
 	// AllowedOrigins is an optional list of CORS origins (default to "*").
 	AllowedOrigins []string
+
+	// RestartTimeout is the max time to wait for the in-flight requests
+	// to complete on app restart before replacing the process (default to 30s).
+	//
+	// On UNIX based systems the server listeners are passed to the new
+	// process, so the connections established during the restart are
+	// queued and accepted once the new process is ready.
+	RestartTimeout time.Duration
 }
 
 // Serve starts a new app web server.
@@ -61,6 +97,10 @@ func Serve(app core.App, config ServeConfig) error {
 		config.AllowedOrigins = []string{"*"}
 	}
 
+	if config.RestartTimeout <= 0 {
+		config.RestartTimeout = 30 * time.Second
+	}
+
 	// ensure that the latest migrations are applied before starting the server
 	err := app.RunAllMigrations()
 	if err != nil {
@@ -75,6 +115,12 @@ func Serve(app core.App, config ServeConfig) error {
 	pbRouter.Bind(CORS(CORSConfig{
 		AllowOrigins: config.AllowedOrigins,
 		AllowMethods: []string{http.MethodGet, http.MethodHead, http.MethodPut, http.MethodPatch, http.MethodPost, http.MethodDelete},
+		ExposeHeaders: []string{
+			RateLimitLimitHeader,
+			RateLimitRemainingHeader,
+			RateLimitResetHeader,
+			"Retry-After",
+		},
 	}))
 
 	pbRouter.GET("/_/{path...}", Static(ui.DistDirFS, false)).
@@ -109,8 +155,8 @@ func Serve(app core.App, config ServeConfig) error {
 		hostNames = append(hostNames, host)
 	}
 	for _, host := range hostNames {
-		if strings.HasPrefix(host, "www.") {
-			continue // explicitly set www host
+		if strings.HasPrefix(host, "www.") || strings.HasPrefix(host, "*.") {
+			continue // explicitly set www host or wildcard
 		}
 
 		wwwHost := "www." + host
@@ -125,10 +171,27 @@ func Serve(app core.App, config ServeConfig) error {
 		pbRouter.Bind(wwwRedirect(wwwRedirects))
 	}
 
+	hostPolicy := autocert.HostWhitelist(hostNames...)
+	if config.CertificateHostPolicy != nil {
+		onDemandPolicy := newCachedHostPolicy(config.CertificateHostPolicy)
+		whitelistPolicy := hostPolicy
+		hostPolicy = func(ctx context.Context, host string) error {
+			if whitelistPolicy(ctx, host) == nil {
+				return nil
+			}
+			return onDemandPolicy.check(ctx, host)
+		}
+	}
+
+	certCache := config.CertificateCache
+	if certCache == nil {
+		certCache = autocert.DirCache(filepath.Join(app.DataDir(), core.LocalAutocertCacheDirName))
+	}
+
 	certManager := &autocert.Manager{
 		Prompt:     autocert.AcceptTOS,
-		Cache:      autocert.DirCache(filepath.Join(app.DataDir(), core.LocalAutocertCacheDirName)),
-		HostPolicy: autocert.HostWhitelist(hostNames...),
+		Cache:      certCache,
+		HostPolicy: hostPolicy,
 	}
 
 	// base request context used for cancelling long running requests
@@ -136,12 +199,28 @@ func Serve(app core.App, config ServeConfig) error {
 	baseCtx, cancelBaseCtx := context.WithCancel(context.Background())
 	defer cancelBaseCtx()
 
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certManager.GetCertificate,
+		NextProtos:     []string{acme.ALPNProto},
+	}
+
+	if config.CertificateDNSProvider != nil {
+		dnsCertManager := &acmedns.Manager{
+			Provider: config.CertificateDNSProvider,
+			Cache:    certManager.Cache,
+			Domains:  hostNames,
+		}
+		if config.CertificateHostPolicy != nil {
+			dnsCertManager.HostPolicy = hostPolicy
+		}
+
+		tlsConfig.GetCertificate = dnsCertManager.GetCertificate
+		tlsConfig.NextProtos = nil // the TLS-ALPN-01 challenge is not used
+	}
+
 	server := &http.Server{
-		TLSConfig: &tls.Config{
-			MinVersion:     tls.VersionTLS12,
-			GetCertificate: certManager.GetCertificate,
-			NextProtos:     []string{acme.ALPNProto},
-		},
+		TLSConfig: tlsConfig,
 		// higher defaults to accommodate large file uploads/downloads
 		WriteTimeout:      5 * time.Minute,
 		ReadTimeout:       5 * time.Minute,
@@ -154,6 +233,8 @@ func Serve(app core.App, config ServeConfig) error {
 	}
 
 	var listener net.Listener
+	var listenerAddr string
+	var redirectListener net.Listener
 
 	// graceful shutdown
 	// ---------------------------------------------------------------
@@ -167,7 +248,17 @@ func Serve(app core.App, config ServeConfig) error {
 		Func: func(te *core.TerminateEvent) error {
 			cancelBaseCtx()
 
-			ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+			timeout := 1 * time.Second
+
+			if te.IsRestart {
+				// keep the listeners open for the new process
+				// while waiting for the in-flight requests
+				timeout = config.RestartTimeout
+				passListener(app, listenerAddr, listener)
+				passListener(app, config.HttpAddr, redirectListener)
+			}
+
+			ctx, cancel := context.WithTimeout(context.Background(), timeout)
 			defer cancel()
 
 			wg.Add(1)
@@ -221,7 +312,7 @@ func Serve(app core.App, config ServeConfig) error {
 		} else {
 			baseURL = "https://"
 			if len(config.CertificateDomains) > 0 {
-				baseURL += config.CertificateDomains[0]
+				baseURL += strings.TrimPrefix(config.CertificateDomains[0], "*.")
 			} else {
 				baseURL += serverAddrToHost(serveEvent.Server.Addr)
 			}
@@ -238,13 +329,14 @@ func Serve(app core.App, config ServeConfig) error {
 		}
 
 		if e.Listener == nil {
-			listener, err = net.Listen("tcp", addr)
+			listener, err = listen(addr)
 			if err != nil {
 				return err
 			}
 		} else {
 			listener = e.Listener
 		}
+		listenerAddr = addr
 
 		if e.InstallerFunc != nil {
 			app := e.App
@@ -287,7 +379,10 @@ func Serve(app core.App, config ServeConfig) error {
 	if config.HttpsAddr != "" {
 		if config.HttpAddr != "" {
 			// start an additional HTTP server for redirecting the traffic to the HTTPS version
-			go http.ListenAndServe(config.HttpAddr, certManager.HTTPHandler(nil))
+			redirectListener, err = listen(config.HttpAddr)
+			if err == nil {
+				go http.Serve(redirectListener, certManager.HTTPHandler(nil))
+			}
 		}
 
 		// start HTTPS server
@@ -303,6 +398,33 @@ func Serve(app core.App, config ServeConfig) error {
 	return nil
 }
 
+// listen returns the listener inherited from the parent process
+// for addr (see [listenfd.Inherit]) or creates a new TCP one.
+func listen(addr string) (net.Listener, error) {
+	l, err := listenfd.Inherit(addr)
+	if err != nil || l != nil {
+		return l, err
+	}
+
+	return net.Listen("tcp", addr)
+}
+
+// passListener passes the listener to the restarted app process
+// (the errors are only logged because the restart fallbacks to a new listener).
+func passListener(app core.App, addr string, l net.Listener) {
+	if l == nil {
+		return
+	}
+
+	if err := listenfd.Pass(addr, l); err != nil {
+		app.Logger().Debug(
+			"Failed to pass the server listener to the restarted process",
+			slog.String("addr", addr),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
 // serverAddrToHost loosely converts http.Server.Addr string into a host to print.
 func serverAddrToHost(addr string) string {
 	if addr == "" || strings.HasSuffix(addr, ":http") || strings.HasSuffix(addr, ":https") {
@@ -0,0 +1,119 @@
+package apis
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+const (
+	hostPolicyAllowedCacheTTL = 5 * time.Minute
+	hostPolicyDeniedCacheTTL  = 1 * time.Minute
+	hostPolicyMaxCacheSize    = 10000
+)
+
+// RecordHostPolicy returns a certificate host policy function that allows
+// issuing TLS certificates on-demand only for the host names that exist
+// as field value in the specified collection (eg. a "domains" collection
+// with the tenants custom domains).
+//
+// optFilter is an optional additional filter expression
+// that the matching record must satisfy (eg. "verified = true").
+//
+// Example:
+//
+//	apis.Serve(app, apis.ServeConfig{
+//		HttpAddr:              "0.0.0.0:80",
+//		HttpsAddr:             "0.0.0.0:443",
+//		CertificateDomains:    []string{"example.com"},
+//		CertificateHostPolicy: apis.RecordHostPolicy(app, "domains", "host", "verified = true"),
+//	})
+func RecordHostPolicy(app core.App, collectionNameOrId string, field string, optFilter ...string) func(ctx context.Context, host string) error {
+	filter := field + " = {:host}"
+	for _, f := range optFilter {
+		if f != "" {
+			filter += " && (" + f + ")"
+		}
+	}
+
+	return func(ctx context.Context, host string) error {
+		host = strings.TrimSuffix(strings.ToLower(host), ".")
+
+		_, err := app.FindFirstRecordByFilter(collectionNameOrId, filter, dbx.Params{"host": host})
+		if err != nil {
+			return fmt.Errorf("host %q is not allowed: %w", host, err)
+		}
+
+		return nil
+	}
+}
+
+// cachedHostPolicy wraps the provided host policy function
+// and caches its results for a short period of time.
+//
+// This is necessary because the certificate manager invokes
+// the host policy on each TLS handshake.
+type cachedHostPolicy struct {
+	policy  func(ctx context.Context, host string) error
+	entries map[string]hostPolicyEntry
+	mu      sync.Mutex
+}
+
+type hostPolicyEntry struct {
+	expires time.Time
+	err     error
+}
+
+func newCachedHostPolicy(policy func(ctx context.Context, host string) error) *cachedHostPolicy {
+	return &cachedHostPolicy{
+		policy:  policy,
+		entries: map[string]hostPolicyEntry{},
+	}
+}
+
+func (c *cachedHostPolicy) check(ctx context.Context, host string) error {
+	now := time.Now()
+
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+
+	if ok && now.Before(entry.expires) {
+		return entry.err
+	}
+
+	err := c.policy(ctx, host)
+
+	// don't cache the context errors (eg. cancelled handshake)
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+
+	ttl := hostPolicyAllowedCacheTTL
+	if err != nil {
+		ttl = hostPolicyDeniedCacheTTL
+	}
+
+	c.mu.Lock()
+	if len(c.entries) >= hostPolicyMaxCacheSize {
+		// evict the expired entries and if still full - reset
+		for k, v := range c.entries {
+			if now.After(v.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= hostPolicyMaxCacheSize {
+			c.entries = map[string]hostPolicyEntry{}
+		}
+	}
+	c.entries[host] = hostPolicyEntry{expires: now.Add(ttl), err: err}
+	c.mu.Unlock()
+
+	return err
+}
@@ -0,0 +1,61 @@
+package apis_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecordHostPolicy(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("domains")
+	collection.Fields.Add(
+		&core.TextField{Name: "host"},
+		&core.BoolField{Name: "verified"},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for host, verified := range map[string]bool{"a.example.com": true, "b.example.com": false} {
+		record := core.NewRecord(collection)
+		record.Set("host", host)
+		record.Set("verified", verified)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		name        string
+		policy      func(ctx context.Context, host string) error
+		host        string
+		expectError bool
+	}{
+		{"missing collection", apis.RecordHostPolicy(app, "missing", "host"), "a.example.com", true},
+		{"missing host", apis.RecordHostPolicy(app, "domains", "host"), "c.example.com", true},
+		{"existing host", apis.RecordHostPolicy(app, "domains", "host"), "a.example.com", false},
+		{"existing host (normalized)", apis.RecordHostPolicy(app, "domains", "host"), "A.Example.com.", false},
+		{"existing unverified host without filter", apis.RecordHostPolicy(app, "domains", "host"), "b.example.com", false},
+		{"existing unverified host with filter", apis.RecordHostPolicy(app, "domains", "host", "verified = true"), "b.example.com", true},
+		{"existing verified host with filter", apis.RecordHostPolicy(app, "domains", "host", "verified = true"), "a.example.com", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.policy(context.Background(), s.host)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,219 @@
+package apis
+
+import (
+	"maps"
+	"net/http"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/spf13/cast"
+)
+
+const (
+	defaultSignupInviteTTL = 7 * 86400
+	maxSignupInviteTTL     = 365 * 86400
+)
+
+// signupInviteTokenField is the signup-with-invite request body field with the plain invite token.
+const signupInviteTokenField = "inviteToken"
+
+// bindSignupInvitesApi registers the signup invites api endpoints.
+func bindSignupInvitesApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}")
+	sub.POST("/signup-with-invite", recordSignupWithInvite)
+
+	invites := sub.Group("/signup-invites").Bind(RequireSuperuserAuth())
+	invites.GET("", signupInvitesList)
+	invites.POST("", signupInviteCreate)
+	invites.DELETE("/{id}", signupInviteDelete)
+}
+
+func signupInvitesList(e *core.RequestEvent) error {
+	collection, err := signupInviteCollection(e)
+	if err != nil {
+		return err
+	}
+
+	invites := []*core.SignupInvite{}
+
+	err = e.App.SignupInviteQuery().
+		AndWhere(dbx.HashExp{"collectionRef": collection.Id}).
+		OrderBy("created DESC", "id DESC").
+		All(&invites)
+	if err != nil {
+		return e.InternalServerError("Failed to load the signup invites.", err)
+	}
+
+	return e.JSON(http.StatusOK, invites)
+}
+
+type signupInviteForm struct {
+	Email    string   `form:"email" json:"email"`
+	MaxUses  int      `form:"maxUses" json:"maxUses"`
+	Roles    []string `form:"roles" json:"roles"`
+	TeamId   string   `form:"teamId" json:"teamId"`
+	TeamRole string   `form:"teamRole" json:"teamRole"`
+
+	// TTL is the invite duration in seconds.
+	TTL int `form:"ttl" json:"ttl"`
+
+	// Send indicates whether to send the invite to the form email address.
+	Send bool `form:"send" json:"send"`
+}
+
+func (form *signupInviteForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Email, validation.When(form.Send, validation.Required), is.EmailFormat),
+		validation.Field(&form.TTL, validation.Required, validation.Min(1), validation.Max(maxSignupInviteTTL)),
+	)
+}
+
+// signupInviteCreate creates a new signup invite and optionally sends it by email.
+//
+// The plain invite token is returned only once with the create response.
+func signupInviteCreate(e *core.RequestEvent) error {
+	collection, err := signupInviteCollection(e)
+	if err != nil {
+		return err
+	}
+
+	form := &signupInviteForm{
+		MaxUses: 1,
+		TTL:     defaultSignupInviteTTL,
+	}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if err := form.validate(); err != nil {
+		return e.BadRequestError("An error occurred while validating the submitted data.", err)
+	}
+
+	invite := &core.SignupInvite{
+		CollectionRef: collection.Id,
+		Email:         form.Email,
+		MaxUses:       form.MaxUses,
+		Roles:         form.Roles,
+		TeamRef:       form.TeamId,
+		TeamRole:      form.TeamRole,
+	}
+
+	token, err := e.App.CreateSignupInvite(invite, time.Duration(form.TTL)*time.Second)
+	if err != nil {
+		return teamSaveError(e, "Failed to create signup invite.", err)
+	}
+
+	if form.Send {
+		if err := mails.SendSignupInvite(e.App, invite, token); err != nil {
+			e.App.Logger().Error("Failed to send signup invite email", "error", err, "inviteId", invite.Id)
+		}
+	}
+
+	return e.JSON(http.StatusOK, struct {
+		*core.SignupInvite
+		Token string `json:"token"`
+	}{invite, token})
+}
+
+func signupInviteDelete(e *core.RequestEvent) error {
+	collection, err := signupInviteCollection(e)
+	if err != nil {
+		return err
+	}
+
+	invite := &core.SignupInvite{}
+
+	err = e.App.SignupInviteQuery().
+		AndWhere(dbx.HashExp{
+			"id":            e.Request.PathValue("id"),
+			"collectionRef": collection.Id,
+		}).
+		Limit(1).
+		One(invite)
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if err := e.App.Delete(invite); err != nil {
+		return e.BadRequestError("Failed to delete signup invite.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// recordSignupWithInvite creates a new auth record with the submitted
+// data if the request has a valid signup invite token
+// (the collection create API rule is not checked).
+func recordSignupWithInvite(e *core.RequestEvent) error {
+	collection, err := signupInviteCollection(e)
+	if err != nil {
+		return err
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("", err))
+	}
+
+	payload := maps.Clone(requestInfo.Body)
+	token := cast.ToString(payload[signupInviteTokenField])
+	delete(payload, signupInviteTokenField)
+
+	invite, err := e.App.FindSignupInviteByToken(token)
+	if err != nil || invite.CollectionRef != collection.Id {
+		return e.BadRequestError("Invalid or expired invite token.", err)
+	}
+
+	if !invite.AllowsEmail(cast.ToString(payload[core.FieldNameEmail])) {
+		return e.BadRequestError("The invite is for a different email address.", nil)
+	}
+
+	var createdRecord *core.Record
+
+	err = e.App.RunInTransaction(func(txApp core.App) error {
+		ir := &core.InternalRequest{
+			Method: http.MethodPost,
+			URL:    "/api/collections/" + collection.Name + "/records",
+			Body:   payload,
+		}
+
+		response, err := processInternalRequest(txApp, e, ir, core.RequestInfoContextSignupInvite, func(data any) error {
+			createdRecord, _ = data.(*core.Record)
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+
+		if response.Status != http.StatusOK || createdRecord == nil {
+			return e.BadRequestError("Failed to create the invited auth record.", nil)
+		}
+
+		if err := txApp.UseSignupInvite(invite, createdRecord); err != nil {
+			return e.BadRequestError("Failed to use the signup invite.", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to signup with invite.", err))
+	}
+
+	return e.JSON(http.StatusOK, createdRecord)
+}
+
+// signupInviteCollection returns the request path auth collection.
+func signupInviteCollection(e *core.RequestEvent) (*core.Collection, error) {
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil || !collection.IsAuth() {
+		return nil, e.NotFoundError("Missing or invalid auth collection context.", err)
+	}
+
+	return collection, nil
+}
@@ -0,0 +1,342 @@
+package apis_test
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// createTestSignupInvite creates a new users signup invite with the specified token
+// (the token is also used as invite id).
+func createTestSignupInvite(t testing.TB, app *tests.TestApp, token string, email string, maxUses int, uses int) *core.SignupInvite {
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expires, err := types.ParseDateTime(time.Now().Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invite := &core.SignupInvite{
+		CollectionRef: users.Id,
+		Email:         email,
+		MaxUses:       maxUses,
+		Uses:          uses,
+		Roles:         []string{},
+		TokenHash:     security.SHA256(token),
+		Expires:       expires,
+	}
+	invite.Id = token
+	if err := app.Save(invite); err != nil {
+		t.Fatal(err)
+	}
+
+	app.ResetEventCalls()
+
+	return invite
+}
+
+func TestSignupInvitesManage(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:            "list as regular user",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/users/signup-invites",
+			Headers:         map[string]string{"Authorization": teamsTestUserToken},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "list for non-auth collection",
+			Method:          http.MethodGet,
+			URL:             "/api/collections/demo1/signup-invites",
+			Headers:         map[string]string{"Authorization": teamsTestSuperuserToken},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:    "list as superuser",
+			Method:  http.MethodGet,
+			URL:     "/api/collections/users/signup-invites",
+			Headers: map[string]string{"Authorization": teamsTestSuperuserToken},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createTestSignupInvite(t, app, "invite_token", "new@example.com", 1, 0)
+			},
+			ExpectedStatus:     200,
+			ExpectedContent:    []string{`"id":"invite_token"`, `"email":"new@example.com"`},
+			NotExpectedContent: []string{`"tokenHash"`},
+			ExpectedEvents:     map[string]int{"*": 0},
+		},
+		{
+			Name:           "create as superuser with invalid data",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/users/signup-invites",
+			Body:           strings.NewReader(`{"email":"invalid","ttl":-1}`),
+			Headers:        map[string]string{"Authorization": teamsTestSuperuserToken},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"email":{"code":"validation_is_email"`,
+				`"ttl":{"code":"validation_min_greater_equal_than_required"`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "create as superuser with missing role and team",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/users/signup-invites",
+			Body:           strings.NewReader(`{"roles":["missing"],"teamId":"missing","teamRole":"member"}`),
+			Headers:        map[string]string{"Authorization": teamsTestSuperuserToken},
+			ExpectedStatus: 400,
+			ExpectedContent: []string{
+				`"roles":{`,
+				`"teamRef":{`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                       0,
+				"OnModelCreate":           1,
+				"OnModelValidate":         1,
+				"OnModelAfterCreateError": 1,
+			},
+		},
+		{
+			Name:           "create as superuser without sending",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/users/signup-invites",
+			Body:           strings.NewReader(`{"maxUses":10}`),
+			Headers:        map[string]string{"Authorization": teamsTestSuperuserToken},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"maxUses":10`,
+				`"uses":0`,
+				`"email":""`,
+				`"token":"`,
+			},
+			NotExpectedContent: []string{`"tokenHash"`},
+			ExpectedEvents: map[string]int{
+				"*":                         0,
+				"OnModelCreate":             1,
+				"OnModelCreateExecute":      1,
+				"OnModelAfterCreateSuccess": 1,
+				"OnModelValidate":           1,
+			},
+		},
+		{
+			Name:           "create as superuser and send",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/users/signup-invites",
+			Body:           strings.NewReader(`{"email":"new@example.com","send":true}`),
+			Headers:        map[string]string{"Authorization": teamsTestSuperuserToken},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"email":"new@example.com"`,
+				`"maxUses":1`,
+				`"token":"`,
+			},
+			ExpectedEvents: map[string]int{
+				"*":                         0,
+				"OnModelCreate":             1,
+				"OnModelCreateExecute":      1,
+				"OnModelAfterCreateSuccess": 1,
+				"OnModelValidate":           1,
+				"OnMailerSend":              1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if to := app.TestMailer.LastMessage().To; len(to) != 1 || to[0].Address != "new@example.com" {
+					t.Fatalf("Expected the invite to be sent to new@example.com, got %v", to)
+				}
+			},
+		},
+		{
+			Name:    "delete as superuser",
+			Method:  http.MethodDelete,
+			URL:     "/api/collections/users/signup-invites/invite_token",
+			Headers: map[string]string{"Authorization": teamsTestSuperuserToken},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createTestSignupInvite(t, app, "invite_token", "", 1, 0)
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"*":                         0,
+				"OnModelDelete":             1,
+				"OnModelDeleteExecute":      1,
+				"OnModelAfterDeleteSuccess": 1,
+			},
+		},
+		{
+			Name:    "delete from another collection",
+			Method:  http.MethodDelete,
+			URL:     "/api/collections/clients/signup-invites/invite_token",
+			Headers: map[string]string{"Authorization": teamsTestSuperuserToken},
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createTestSignupInvite(t, app, "invite_token", "", 1, 0)
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestRecordSignupWithInvite(t *testing.T) {
+	t.Parallel()
+
+	// ensures that the signup is allowed even for superusers-only create rule
+	lockCreateRule := func(t testing.TB, app *tests.TestApp) {
+		users, err := app.FindCachedCollectionByNameOrId("users")
+		if err != nil {
+			t.Fatal(err)
+		}
+		users.CreateRule = nil
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:   "missing invite token",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/signup-with-invite",
+			Body:   strings.NewReader(`{"email":"new@example.com","password":"1234567890","passwordConfirm":"1234567890"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				lockCreateRule(t, app)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "exhausted invite token",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/signup-with-invite",
+			Body:   strings.NewReader(`{"inviteToken":"invite_token","email":"new@example.com","password":"1234567890","passwordConfirm":"1234567890"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				lockCreateRule(t, app)
+				createTestSignupInvite(t, app, "invite_token", "", 2, 2)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "invite token for another collection",
+			Method: http.MethodPost,
+			URL:    "/api/collections/clients/signup-with-invite",
+			Body:   strings.NewReader(`{"inviteToken":"invite_token","email":"new@example.com","password":"1234567890","passwordConfirm":"1234567890"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createTestSignupInvite(t, app, "invite_token", "", 1, 0)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "invite for a different email",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/signup-with-invite",
+			Body:   strings.NewReader(`{"inviteToken":"invite_token","email":"new@example.com","password":"1234567890","passwordConfirm":"1234567890"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				lockCreateRule(t, app)
+				createTestSignupInvite(t, app, "invite_token", "other@example.com", 1, 0)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "valid invite with invalid record data",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/signup-with-invite",
+			Body:   strings.NewReader(`{"inviteToken":"invite_token","email":"new@example.com","password":"1234567890","passwordConfirm":"123"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				lockCreateRule(t, app)
+				createTestSignupInvite(t, app, "invite_token", "new@example.com", 1, 0)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"passwordConfirm":{`},
+			ExpectedEvents: map[string]int{
+				"OnRecordCreateRequest": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				invite, err := app.FindSignupInviteByToken("invite_token")
+				if err != nil || invite.Uses != 0 {
+					t.Fatalf("Expected the invite to remain unused, got %v (%v)", invite, err)
+				}
+			},
+		},
+		{
+			Name:   "valid invite",
+			Method: http.MethodPost,
+			URL:    "/api/collections/users/signup-with-invite",
+			Body:   strings.NewReader(`{"inviteToken":"invite_token","email":"new@example.com","password":"1234567890","passwordConfirm":"1234567890"}`),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				lockCreateRule(t, app)
+
+				team := createTestTeam(t, app, "team_a", "")
+				role := createTestRole(t, app, "role_a", "editor", false)
+
+				invite := createTestSignupInvite(t, app, "invite_token", "new@example.com", 1, 0)
+				invite.Roles = []string{role.Name}
+				invite.TeamRef = team.Id
+				invite.TeamRole = core.TeamRoleAdmin
+				if err := app.Save(invite); err != nil {
+					t.Fatal(err)
+				}
+
+				app.ResetEventCalls()
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"collectionName":"users"`,
+				`"verified":false`,
+			},
+			NotExpectedContent: []string{`"inviteToken"`, `"password"`},
+			ExpectedEvents: map[string]int{
+				"OnRecordCreateRequest":      1,
+				"OnRecordEnrich":             1,
+				"OnRecordAfterCreateSuccess": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				user, err := app.FindAuthRecordByEmail("users", "new@example.com")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if !app.AuthRecordHasRole(user, "editor") {
+					t.Fatal("Expected the new user to have the invite role")
+				}
+
+				team, err := app.FindTeamById("team_a")
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				membership, err := app.FindTeamMembership(team, user)
+				if err != nil || membership.Role != core.TeamRoleAdmin {
+					t.Fatalf("Expected the new user to be a team admin, got %v (%v)", membership, err)
+				}
+
+				if _, err := app.FindSignupInviteByToken("invite_token"); err == nil {
+					t.Fatal("Expected the single-use invite to be exhausted")
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,221 @@
+package apis
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/sitemap"
+	"github.com/pocketbase/pocketbase/tools/store"
+)
+
+// bindSitemapApi registers the sitemap api endpoints.
+//
+// The generated sitemaps are cached in memory and the cache is reset
+// on every collection change or record change of a sitemap collection.
+func bindSitemapApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	cache := store.New[string, []byte](nil)
+
+	recordResetHandler := &hook.Handler[*core.RecordEvent]{
+		Func: func(e *core.RecordEvent) error {
+			if e.Record.Collection().Sitemap != nil {
+				cache.RemoveAll()
+			}
+			return e.Next()
+		},
+		Priority: -99,
+	}
+	app.OnRecordAfterCreateSuccess().Bind(recordResetHandler)
+	app.OnRecordAfterUpdateSuccess().Bind(recordResetHandler)
+	app.OnRecordAfterDeleteSuccess().Bind(recordResetHandler)
+
+	collectionResetHandler := &hook.Handler[*core.CollectionEvent]{
+		Func: func(e *core.CollectionEvent) error {
+			cache.RemoveAll()
+			return e.Next()
+		},
+		Priority: -99,
+	}
+	app.OnCollectionAfterCreateSuccess().Bind(collectionResetHandler)
+	app.OnCollectionAfterUpdateSuccess().Bind(collectionResetHandler)
+	app.OnCollectionAfterDeleteSuccess().Bind(collectionResetHandler)
+
+	rg.GET("/sitemap.xml", sitemapIndex(cache))
+	rg.GET("/sitemaps/{collection}/{page}", sitemapPage(cache))
+}
+
+// sitemapIndex returns a sitemap index with the pages of all sitemap collections.
+func sitemapIndex(cache *store.Store[string, []byte]) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		return serveCachedSitemap(e, cache, func(buf *bytes.Buffer) error {
+			collections, err := e.App.FindAllCollections()
+			if err != nil {
+				return err
+			}
+
+			slices.SortFunc(collections, func(a, b *core.Collection) int {
+				return strings.Compare(a.Name, b.Name)
+			})
+
+			appURL := strings.TrimRight(e.App.Settings().Meta.AppURL, "/")
+
+			sitemaps := []sitemap.Sitemap{}
+
+			for _, collection := range collections {
+				if collection.Sitemap == nil || collection.ListRule == nil {
+					continue
+				}
+
+				total, err := countSitemapRecords(e.App, collection)
+				if err != nil {
+					return err
+				}
+
+				limit := collection.Sitemap.Limit()
+				for page := 1; (page-1)*limit < total; page++ {
+					sitemaps = append(sitemaps, sitemap.Sitemap{
+						Loc: appURL + "/api/sitemaps/" + collection.Name + "/" + strconv.Itoa(page),
+					})
+				}
+			}
+
+			return sitemap.EncodeIndex(buf, sitemaps)
+		})
+	}
+}
+
+// sitemapPage returns a single sitemap page with the path collection records urls.
+func sitemapPage(cache *store.Store[string, []byte]) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+		if err != nil || collection == nil || collection.Sitemap == nil || collection.ListRule == nil {
+			return e.NotFoundError("Missing or invalid sitemap collection context.", err)
+		}
+
+		page, err := strconv.Atoi(e.Request.PathValue("page"))
+		if err != nil || page < 1 {
+			return e.NotFoundError("Invalid sitemap page.", err)
+		}
+
+		return serveCachedSitemap(e, cache, func(buf *bytes.Buffer) error {
+			config := collection.Sitemap
+
+			query, err := sitemapRecordsQuery(e.App, collection)
+			if err != nil {
+				return err
+			}
+
+			records := []*core.Record{}
+
+			err = query.
+				OrderBy(collection.Name + ".id ASC").
+				Offset(int64((page - 1) * config.Limit())).
+				Limit(int64(config.Limit())).
+				All(&records)
+			if err != nil {
+				return err
+			}
+
+			if len(records) == 0 && page > 1 {
+				return e.NotFoundError("Invalid sitemap page.", nil)
+			}
+
+			urls := make([]sitemap.URL, 0, len(records))
+
+			for _, record := range records {
+				u := sitemap.URL{
+					Loc:        core.ResolveRecordURLTemplate(config.URLTemplate, record),
+					ChangeFreq: config.ChangeFreq,
+					Priority:   config.Priority,
+				}
+
+				// fallback to the "updated" autodate field (if any)
+				lastmodField := config.LastmodField
+				if lastmodField == "" {
+					lastmodField = "updated"
+				}
+				u.Lastmod = record.GetDateTime(lastmodField).Time()
+
+				urls = append(urls, u)
+			}
+
+			return sitemap.EncodeURLSet(buf, urls)
+		})
+	}
+}
+
+// serveCachedSitemap writes the cached request path sitemap
+// or generates and caches a new one with the provided generate func.
+func serveCachedSitemap(e *core.RequestEvent, cache *store.Store[string, []byte], generate func(buf *bytes.Buffer) error) error {
+	key := e.Request.URL.Path
+
+	if data, ok := cache.GetOk(key); ok {
+		return e.Blob(http.StatusOK, sitemap.ContentType, data)
+	}
+
+	var buf bytes.Buffer
+	if err := generate(&buf); err != nil {
+		return firstApiError(err, e.InternalServerError("Failed to generate the sitemap.", err))
+	}
+
+	cache.Set(key, buf.Bytes())
+
+	return e.Blob(http.StatusOK, sitemap.ContentType, buf.Bytes())
+}
+
+// countSitemapRecords returns the total number of sitemap records of the provided collection.
+func countSitemapRecords(app core.App, collection *core.Collection) (int, error) {
+	query, err := sitemapRecordsQuery(app, collection)
+	if err != nil {
+		return 0, err
+	}
+
+	var total int
+	err = query.Select("COUNT(DISTINCT [[" + collection.Name + ".id]])").Row(&total)
+
+	return total, err
+}
+
+// sitemapRecordsQuery returns a new query for the collection records
+// that are accessible by guests and match the collection sitemap filter.
+func sitemapRecordsQuery(app core.App, collection *core.Collection) (*dbx.SelectQuery, error) {
+	query := app.RecordQuery(collection)
+
+	requestInfo := &core.RequestInfo{
+		Context: core.RequestInfoContextDefault,
+		Method:  http.MethodGet,
+	}
+
+	resolver := core.NewRecordFieldResolver(app, collection, requestInfo, true)
+
+	for _, filter := range []string{*collection.ListRule, collection.Sitemap.Filter} {
+		if filter == "" {
+			continue
+		}
+
+		expr, err := search.FilterData(filter).BuildExpr(resolver)
+		if err != nil {
+			return nil, err
+		}
+		query.AndWhere(expr)
+	}
+
+	policiesExpr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionList, resolver)
+	if err != nil {
+		return nil, err
+	}
+	if policiesExpr != nil {
+		query.AndWhere(policiesExpr)
+	}
+
+	resolver.UpdateQuery(query)
+
+	return query, nil
+}
@@ -0,0 +1,245 @@
+package apis_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestSitemap(t *testing.T) {
+	t.Parallel()
+
+	setupSitemap := func(listRule *string, filter string) func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+		return func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+			collection, err := app.FindCollectionByNameOrId("demo2")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			collection.ListRule = listRule
+			collection.Sitemap = &core.SitemapConfig{
+				URLTemplate: "https://example.com/posts/{title}",
+				Filter:      filter,
+				ChangeFreq:  "weekly",
+				Priority:    0.5,
+				PageSize:    2,
+			}
+
+			if err := app.Save(collection); err != nil {
+				t.Fatal(err)
+			}
+
+			app.ResetEventCalls()
+		}
+	}
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "index without sitemap collections",
+			Method:         http.MethodGet,
+			URL:            "/api/sitemap.xml",
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9"></sitemapindex>`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "index with public sitemap collection",
+			Method:         http.MethodGet,
+			URL:            "/api/sitemap.xml",
+			BeforeTestFunc: setupSitemap(types.Pointer(""), ""),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`<sitemap><loc>http://localhost:8090/api/sitemaps/demo2/1</loc></sitemap>`,
+				`<sitemap><loc>http://localhost:8090/api/sitemaps/demo2/2</loc></sitemap>`,
+			},
+			NotExpectedContent: []string{
+				`/api/sitemaps/demo2/3`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "index with filtered sitemap collection",
+			Method:         http.MethodGet,
+			URL:            "/api/sitemap.xml",
+			BeforeTestFunc: setupSitemap(types.Pointer(""), "active = true"),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`<sitemap><loc>http://localhost:8090/api/sitemaps/demo2/1</loc></sitemap>`,
+			},
+			NotExpectedContent: []string{
+				`/api/sitemaps/demo2/2`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "index with superusers only sitemap collection",
+			Method:         http.MethodGet,
+			URL:            "/api/sitemap.xml",
+			BeforeTestFunc: setupSitemap(nil, ""),
+			ExpectedStatus: 200,
+			NotExpectedContent: []string{
+				`/api/sitemaps/demo2`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "index with non-guest list rule",
+			Method:         http.MethodGet,
+			URL:            "/api/sitemap.xml",
+			BeforeTestFunc: setupSitemap(types.Pointer("@request.auth.id != ''"), ""),
+			ExpectedStatus: 200,
+			NotExpectedContent: []string{
+				`/api/sitemaps/demo2`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:            "page of collection without sitemap config",
+			Method:          http.MethodGet,
+			URL:             "/api/sitemaps/demo2/1",
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "page of superusers only sitemap collection",
+			Method:          http.MethodGet,
+			URL:             "/api/sitemaps/demo2/1",
+			BeforeTestFunc:  setupSitemap(nil, ""),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "invalid page",
+			Method:          http.MethodGet,
+			URL:             "/api/sitemaps/demo2/0",
+			BeforeTestFunc:  setupSitemap(types.Pointer(""), ""),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:            "out of range page",
+			Method:          http.MethodGet,
+			URL:             "/api/sitemaps/demo2/3",
+			BeforeTestFunc:  setupSitemap(types.Pointer(""), ""),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:           "first page",
+			Method:         http.MethodGet,
+			URL:            "/api/sitemaps/demo2/1",
+			BeforeTestFunc: setupSitemap(types.Pointer(""), ""),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`,
+				`<url><loc>https://example.com/posts/test3</loc><lastmod>2022-10-14T10:52:49Z</lastmod><changefreq>weekly</changefreq><priority>0.5</priority></url>`,
+				`<url><loc>https://example.com/posts/test2</loc>`,
+			},
+			NotExpectedContent: []string{
+				`/posts/test1`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+		{
+			Name:           "second page",
+			Method:         http.MethodGet,
+			URL:            "/api/sitemaps/demo2/2",
+			BeforeTestFunc: setupSitemap(types.Pointer(""), ""),
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`<url><loc>https://example.com/posts/test1</loc><lastmod>2022-10-12T11:42:51Z</lastmod>`,
+			},
+			NotExpectedContent: []string{
+				`/posts/test2`,
+				`/posts/test3`,
+			},
+			ExpectedEvents: map[string]int{"*": 0},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestSitemapCacheReset(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.ListRule = types.Pointer("")
+	collection.Sitemap = &core.SitemapConfig{URLTemplate: "https://example.com/posts/{title}"}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	pbRouter, err := apis.NewRouter(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	mux, err := pbRouter.BuildMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fetch := func() string {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/api/sitemaps/demo2/1", nil)
+		mux.ServeHTTP(rec, req)
+
+		result := rec.Result()
+		defer result.Body.Close()
+
+		body, _ := io.ReadAll(result.Body)
+
+		return string(body)
+	}
+
+	if body := fetch(); !strings.Contains(body, "/posts/test1") {
+		t.Fatalf("Expected the test1 url, got\n%s", body)
+	}
+
+	record, err := app.FindRecordById("demo2", "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// direct db change (shouldn't reset the cache)
+	_, err = app.DB().Update("demo2", map[string]any{"title": "direct"}, dbx.HashExp{"id": record.Id}).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if body := fetch(); !strings.Contains(body, "/posts/test1") || strings.Contains(body, "/posts/direct") {
+		t.Fatalf("Expected the cached sitemap, got\n%s", body)
+	}
+
+	record.Set("title", "changed")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if body := fetch(); !strings.Contains(body, "/posts/changed") || strings.Contains(body, "/posts/test1") {
+		t.Fatalf("Expected the regenerated sitemap, got\n%s", body)
+	}
+}
@@ -0,0 +1,483 @@
+package apis
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	syncDefaultLimit = 100
+	syncMaxLimit     = 1000
+	syncMaxMutations = 100
+)
+
+const (
+	SyncPolicyServerWins = "server-wins"
+	SyncPolicyClientWins = "client-wins"
+	SyncPolicyMerge      = "merge"
+)
+
+const (
+	SyncChangeUpsert = "upsert"
+	SyncChangeDelete = "delete"
+)
+
+const (
+	SyncStatusApplied  = "applied"
+	SyncStatusConflict = "conflict"
+	SyncStatusFailed   = "failed"
+)
+
+// bindSyncApi registers the offline sync api endpoints.
+//
+// The sync API relies on the changefeed and requires it to be enabled.
+func bindSyncApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/collections/{collection}/sync")
+	sub.GET("/pull", syncPull)
+	sub.POST("/push", syncPush)
+}
+
+// SyncPullResult defines the response of a sync pull request.
+type SyncPullResult struct {
+	// Checkpoint is the changefeed sequence that should be
+	// submitted with the next pull request.
+	Checkpoint int64 `json:"checkpoint"`
+
+	// HasMore indicates whether there are more changes after Checkpoint.
+	HasMore bool `json:"hasMore"`
+
+	Changes []*SyncChange `json:"changes"`
+}
+
+// SyncChange defines a single pulled record change.
+type SyncChange struct {
+	Seq int64 `json:"seq"`
+
+	// Action is either "upsert" or "delete" (aka. tombstone).
+	Action string `json:"action"`
+
+	Id string `json:"id"`
+
+	// Record is the current record state (set only for the "upsert" action).
+	Record *core.Record `json:"record,omitempty"`
+}
+
+// syncPull returns the collection record changes after the "checkpoint" query parameter.
+//
+// Multiple changes of the same record are collapsed to the latest one.
+// Records that were deleted or are no longer accessible
+// by the list API rule are returned as "delete" tombstones.
+func syncPull(e *core.RequestEvent) error {
+	collection, requestInfo, err := syncCollection(e)
+	if err != nil {
+		return err
+	}
+
+	query := e.Request.URL.Query()
+
+	checkpoint, err := parseChangesCursor(query.Get("checkpoint"))
+	if err != nil {
+		return e.BadRequestError("Invalid checkpoint.", err)
+	}
+
+	limit, _ := strconv.Atoi(query.Get("limit"))
+	if limit <= 0 {
+		limit = syncDefaultLimit
+	} else if limit > syncMaxLimit {
+		limit = syncMaxLimit
+	}
+
+	changes, err := e.App.FindChanges(checkpoint, collection.Id, limit+1)
+	if err != nil {
+		return e.InternalServerError("Failed to load the changes.", err)
+	}
+
+	result := &SyncPullResult{
+		Checkpoint: checkpoint,
+		Changes:    []*SyncChange{},
+	}
+
+	if len(changes) > limit {
+		result.HasMore = true
+		changes = changes[:limit]
+	}
+
+	if len(changes) > 0 {
+		result.Checkpoint = changes[len(changes)-1].Seq
+	}
+
+	// collapse to the latest change of each record
+	latest := make(map[string]*core.Change, len(changes))
+	upsertIds := []any{}
+	for _, change := range changes {
+		if _, ok := latest[change.RecordId]; !ok && change.Action != core.ChangeActionDelete {
+			upsertIds = append(upsertIds, change.RecordId)
+		}
+		latest[change.RecordId] = change
+	}
+
+	records, err := findSyncRecords(e, collection, requestInfo, upsertIds)
+	if err != nil {
+		return firstApiError(err, e.BadRequestError("Failed to load the changed records.", err))
+	}
+
+	for _, change := range changes {
+		if latest[change.RecordId] != change {
+			continue // not the latest
+		}
+
+		syncChange := &SyncChange{
+			Seq:    change.Seq,
+			Action: SyncChangeDelete,
+			Id:     change.RecordId,
+		}
+
+		if record, ok := records[change.RecordId]; ok && change.Action != core.ChangeActionDelete {
+			syncChange.Action = SyncChangeUpsert
+			syncChange.Record = record
+		}
+
+		result.Changes = append(result.Changes, syncChange)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
+
+// findSyncRecords returns the current state of the specified
+// collection records that are accessible by the list API rule.
+func findSyncRecords(e *core.RequestEvent, collection *core.Collection, requestInfo *core.RequestInfo, ids []any) (map[string]*core.Record, error) {
+	result := make(map[string]*core.Record, len(ids))
+
+	if len(ids) == 0 {
+		return result, nil
+	}
+
+	query := e.App.RecordQuery(collection).
+		AndWhere(dbx.In(e.App.ConcurrentDB().QuoteSimpleColumnName(collection.Name)+".id", ids...))
+
+	if !requestInfo.HasSuperuserAuth() {
+		resolver := core.NewRecordFieldResolver(e.App, collection, requestInfo, true)
+
+		if *collection.ListRule != "" {
+			expr, err := search.FilterData(*collection.ListRule).BuildExpr(resolver)
+			if err != nil {
+				return nil, err
+			}
+			query.AndWhere(expr)
+		}
+
+		policiesExpr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionList, resolver)
+		if err != nil {
+			return nil, err
+		}
+		if policiesExpr != nil {
+			query.AndWhere(policiesExpr)
+		}
+
+		resolver.UpdateQuery(query)
+	}
+
+	records := []*core.Record{}
+	if err := query.All(&records); err != nil {
+		return nil, err
+	}
+
+	if err := EnrichRecords(e, records); err != nil {
+		return nil, err
+	}
+
+	for _, record := range records {
+		result[record.Id] = record
+	}
+
+	return result, nil
+}
+
+// -------------------------------------------------------------------
+
+type syncPushForm struct {
+	// Checkpoint is the last pulled changefeed sequence of the client.
+	//
+	// A mutation is considered conflicting if its record was changed after the checkpoint.
+	Checkpoint int64 `form:"checkpoint" json:"checkpoint"`
+
+	// Policy is the conflicts resolution policy
+	// ("server-wins" (default), "client-wins" or "merge").
+	Policy string `form:"policy" json:"policy"`
+
+	Mutations []*syncMutation `form:"mutations" json:"mutations"`
+}
+
+func (form *syncPushForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Checkpoint, validation.Min(0)),
+		validation.Field(&form.Policy, validation.In(SyncPolicyServerWins, SyncPolicyClientWins, SyncPolicyMerge)),
+		validation.Field(&form.Mutations, validation.Required, validation.Length(0, syncMaxMutations)),
+	)
+}
+
+type syncMutation struct {
+	Action string         `form:"action" json:"action"`
+	Id     string         `form:"id" json:"id"`
+	Data   map[string]any `form:"data" json:"data"`
+}
+
+func (m syncMutation) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Action, validation.Required, validation.In(
+			core.ChangeActionCreate,
+			core.ChangeActionUpdate,
+			core.ChangeActionDelete,
+		)),
+		validation.Field(&m.Id, validation.Required),
+	)
+}
+
+// SyncPushResult defines the result of a single pushed mutation.
+type SyncPushResult struct {
+	Id     string `json:"id"`
+	Action string `json:"action"`
+
+	// Status is one of "applied", "conflict" or "failed".
+	Status string `json:"status"`
+
+	// Record is the applied record (for applied create and update mutations)
+	// or the current server record (for conflicts, if accessible).
+	Record types.JSONRaw `json:"record,omitempty"`
+
+	// Error is the mutation error response data (for failed mutations).
+	Error *router.ApiError `json:"error,omitempty"`
+}
+
+// syncPush applies the submitted client mutations using the regular
+// records API create, update and delete actions (aka. with API rules checks).
+//
+// Each mutation is applied independently and its result is
+// returned in the same order as the submitted mutations.
+func syncPush(e *core.RequestEvent) error {
+	collection, _, err := syncCollection(e)
+	if err != nil {
+		return err
+	}
+
+	if collection.IsView() {
+		return e.BadRequestError("View collection records cannot be modified.", nil)
+	}
+
+	form := &syncPushForm{}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if form.Policy == "" {
+		form.Policy = SyncPolicyServerWins
+	}
+
+	if err := form.validate(); err != nil {
+		return e.BadRequestError("An error occurred while validating the submitted data.", err)
+	}
+
+	// the changes made by the current push shouldn't be considered as conflicts
+	checkpoints := map[string]int64{}
+
+	results := make([]*SyncPushResult, len(form.Mutations))
+
+	for i, mutation := range form.Mutations {
+		checkpoint, ok := checkpoints[mutation.Id]
+		if !ok {
+			checkpoint = form.Checkpoint
+		}
+
+		results[i] = applySyncMutation(e, collection, mutation, checkpoint, form.Policy)
+
+		if results[i].Status == SyncStatusApplied {
+			checkpoints[mutation.Id], _ = lastRecordChangeSeq(e.App, collection, mutation.Id)
+		}
+	}
+
+	return e.JSON(http.StatusOK, results)
+}
+
+func applySyncMutation(
+	e *core.RequestEvent,
+	collection *core.Collection,
+	mutation *syncMutation,
+	checkpoint int64,
+	policy string,
+) *SyncPushResult {
+	result := &SyncPushResult{Id: mutation.Id, Action: mutation.Action}
+
+	existing, _ := e.App.FindRecordById(collection, mutation.Id)
+
+	var conflict bool
+	if mutation.Action == core.ChangeActionCreate {
+		conflict = existing != nil
+	} else {
+		lastSeq, err := lastRecordChangeSeq(e.App, collection, mutation.Id)
+		if err != nil {
+			result.Status = SyncStatusFailed
+			result.Error = router.ToApiError(err)
+			return result
+		}
+		conflict = lastSeq > checkpoint
+	}
+
+	if !conflict {
+		return execSyncMutation(e, collection, mutation.Action, mutation.Id, mutation.Data, existing, result)
+	}
+
+	switch policy {
+	case SyncPolicyClientWins:
+		return execSyncMutation(e, collection, mutation.Action, mutation.Id, mutation.Data, existing, result)
+	case SyncPolicyMerge:
+		event := new(core.RecordSyncConflictRequestEvent)
+		event.RequestEvent = e
+		event.Collection = collection
+		event.Record = existing
+		event.Action = mutation.Action
+		event.Data = mutation.Data
+
+		err := e.App.OnRecordSyncConflictRequest().Trigger(event, func(ev *core.RecordSyncConflictRequestEvent) error {
+			execSyncMutation(ev.RequestEvent, collection, ev.Action, mutation.Id, ev.Data, ev.Record, result)
+			return nil
+		})
+		if err != nil {
+			result.Status = SyncStatusConflict
+			result.Error = router.ToApiError(err)
+			result.Record = viewSyncRecord(e, collection, mutation.Id)
+		}
+
+		return result
+	default: // server-wins
+		result.Status = SyncStatusConflict
+		result.Record = viewSyncRecord(e, collection, mutation.Id)
+		return result
+	}
+}
+
+// execSyncMutation executes the mutation as internal records API request.
+//
+// The create and update actions are executed as upsert in order to
+// handle the records that were created or deleted in the meantime.
+func execSyncMutation(
+	e *core.RequestEvent,
+	collection *core.Collection,
+	action string,
+	id string,
+	data map[string]any,
+	existing *core.Record,
+	result *SyncPushResult,
+) *SyncPushResult {
+	ir := &core.InternalRequest{}
+
+	if action == core.ChangeActionDelete {
+		if existing == nil {
+			result.Status = SyncStatusApplied // already deleted
+			return result
+		}
+
+		ir.Method = http.MethodDelete
+		ir.URL = "/api/collections/" + collection.Id + "/records/" + id
+	} else {
+		body := make(map[string]any, len(data)+1)
+		for k, v := range data {
+			body[k] = v
+		}
+		body[core.FieldNameId] = id
+
+		ir.Method = http.MethodPut
+		ir.URL = "/api/collections/" + collection.Id + "/records"
+		ir.Body = body
+	}
+
+	res, err := processInternalRequest(e.App, e, ir, core.RequestInfoContextDefault, nil)
+	if err != nil {
+		result.Status = SyncStatusFailed
+		result.Error = router.ToApiError(err)
+		return result
+	}
+
+	result.Status = SyncStatusApplied
+	if res.Status == http.StatusOK {
+		result.Record, _ = res.Body.(types.JSONRaw)
+	}
+
+	return result
+}
+
+// viewSyncRecord returns the serialized current server record
+// (if it exists and it is accessible by the view API rule).
+func viewSyncRecord(e *core.RequestEvent, collection *core.Collection, id string) types.JSONRaw {
+	record, err := e.App.FindRecordById(collection, id)
+	if err != nil {
+		return nil
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return nil
+	}
+
+	if collection.ViewRule == nil && !requestInfo.HasSuperuserAuth() {
+		return nil
+	}
+
+	if canAccess, _ := e.App.CanAccessRecord(record, requestInfo, collection.ViewRule, core.CollectionPolicyActionView); !canAccess {
+		return nil
+	}
+
+	if err := EnrichRecord(e, record); err != nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(record)
+	if err != nil {
+		return nil
+	}
+
+	return raw
+}
+
+// lastRecordChangeSeq returns the changefeed sequence of the last record change (if any).
+func lastRecordChangeSeq(app core.App, collection *core.Collection, recordId string) (int64, error) {
+	var seq int64
+
+	err := app.ChangeQuery().
+		Select("COALESCE(MAX([[seq]]), 0)").
+		AndWhere(dbx.HashExp{"collectionId": collection.Id, "recordId": recordId}).
+		Row(&seq)
+
+	return seq, err
+}
+
+// syncCollection returns the request path collection and checks
+// whether the sync API is enabled and the collection records list is accessible.
+func syncCollection(e *core.RequestEvent) (*core.Collection, *core.RequestInfo, error) {
+	if !e.App.Settings().Changefeed.Enabled {
+		return nil, nil, e.ForbiddenError("The sync API requires the changefeed to be enabled.", nil)
+	}
+
+	collection, err := e.App.FindCachedCollectionByNameOrId(e.Request.PathValue("collection"))
+	if err != nil || collection == nil {
+		return nil, nil, e.NotFoundError("Missing collection context.", err)
+	}
+
+	requestInfo, err := e.RequestInfo()
+	if err != nil {
+		return nil, nil, firstApiError(err, e.BadRequestError("", err))
+	}
+
+	if collection.ListRule == nil && !requestInfo.HasSuperuserAuth() {
+		return nil, nil, e.ForbiddenError("Only superusers can perform this action.", nil)
+	}
+
+	return collection, requestInfo, nil
+}
@@ -0,0 +1,389 @@
+package apis
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tools/router"
+)
+
+const (
+	defaultTeamInvitationTTL = 7 * 86400
+	maxTeamInvitationTTL     = 30 * 86400
+)
+
+// bindTeamsApi registers the teams, team members and invitations api endpoints.
+func bindTeamsApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/teams").Bind(RequireAuth())
+	sub.GET("", teamsList)
+	sub.POST("", teamCreate)
+	sub.POST("/accept-invitation", teamInvitationAccept)
+	sub.GET("/{id}", teamView)
+	sub.PATCH("/{id}", teamUpdate)
+	sub.DELETE("/{id}", teamDelete)
+	sub.GET("/{id}/members", teamMembersList)
+	sub.PATCH("/{id}/members/{membershipId}", teamMemberUpdate)
+	sub.DELETE("/{id}/members/{membershipId}", teamMemberDelete)
+	sub.GET("/{id}/invitations", teamInvitationsList)
+	sub.POST("/{id}/invitations", teamInvitationCreate)
+	sub.DELETE("/{id}/invitations/{invitationId}", teamInvitationDelete)
+}
+
+// teamsList returns the teams of the current auth record (or all teams for superusers).
+func teamsList(e *core.RequestEvent) error {
+	teams := []*core.Team{}
+
+	var err error
+	if e.HasSuperuserAuth() {
+		err = e.App.TeamQuery().OrderBy("name ASC").All(&teams)
+	} else {
+		teams, err = e.App.FindAuthRecordTeams(e.Auth)
+	}
+	if err != nil {
+		return e.InternalServerError("Failed to load the teams.", err)
+	}
+
+	return e.JSON(http.StatusOK, teams)
+}
+
+type teamForm struct {
+	Name string `form:"name" json:"name"`
+}
+
+// teamCreate creates a new team with the current auth record as its owner
+// (superusers create teams without members).
+func teamCreate(e *core.RequestEvent) error {
+	form := &teamForm{}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	team := &core.Team{Name: form.Name}
+
+	err := e.App.RunInTransaction(func(txApp core.App) error {
+		if err := txApp.Save(team); err != nil {
+			return err
+		}
+
+		if e.HasSuperuserAuth() {
+			return nil
+		}
+
+		_, err := txApp.AddTeamMember(team, e.Auth, core.TeamRoleOwner)
+		return err
+	})
+	if err != nil {
+		return teamSaveError(e, "Failed to create team.", err)
+	}
+
+	return e.JSON(http.StatusOK, team)
+}
+
+func teamView(e *core.RequestEvent) error {
+	team, _, err := teamRequestContext(e)
+	if err != nil {
+		return err
+	}
+
+	return e.JSON(http.StatusOK, team)
+}
+
+func teamUpdate(e *core.RequestEvent) error {
+	team, membership, err := teamRequestContext(e)
+	if err != nil {
+		return err
+	}
+
+	if membership != nil && !membership.CanManage(core.TeamRoleMember) {
+		return e.ForbiddenError("Only the team owners and admins can update the team.", nil)
+	}
+
+	form := &teamForm{Name: team.Name}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	team.Name = form.Name
+
+	if err := e.App.Save(team); err != nil {
+		return teamSaveError(e, "Failed to update team.", err)
+	}
+
+	return e.JSON(http.StatusOK, team)
+}
+
+func teamDelete(e *core.RequestEvent) error {
+	team, membership, err := teamRequestContext(e)
+	if err != nil {
+		return err
+	}
+
+	if membership != nil && membership.Role != core.TeamRoleOwner {
+		return e.ForbiddenError("Only the team owners can delete the team.", nil)
+	}
+
+	if err := e.App.Delete(team); err != nil {
+		return e.BadRequestError("Failed to delete team.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// -------------------------------------------------------------------
+
+func teamMembersList(e *core.RequestEvent) error {
+	team, _, err := teamRequestContext(e)
+	if err != nil {
+		return err
+	}
+
+	memberships, err := e.App.FindTeamMemberships(team)
+	if err != nil {
+		return e.InternalServerError("Failed to load the team members.", err)
+	}
+
+	return e.JSON(http.StatusOK, memberships)
+}
+
+type teamMemberForm struct {
+	Role string `form:"role" json:"role"`
+}
+
+func teamMemberUpdate(e *core.RequestEvent) error {
+	team, membership, err := teamRequestContext(e)
+	if err != nil {
+		return err
+	}
+
+	target, err := findTeamRequestMembership(e, team)
+	if err != nil {
+		return err
+	}
+
+	form := &teamMemberForm{Role: target.Role}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if membership != nil && (!membership.CanManage(target.Role) || !membership.CanManage(form.Role)) {
+		return e.ForbiddenError("You are not allowed to change the member role.", nil)
+	}
+
+	target.Role = form.Role
+
+	if err := e.App.Save(target); err != nil {
+		return teamSaveError(e, "Failed to update team member.", err)
+	}
+
+	return e.JSON(http.StatusOK, target)
+}
+
+// teamMemberDelete removes a member from the team
+// (regular members are allowed to remove only themselves, aka. to leave the team).
+func teamMemberDelete(e *core.RequestEvent) error {
+	team, membership, err := teamRequestContext(e)
+	if err != nil {
+		return err
+	}
+
+	target, err := findTeamRequestMembership(e, team)
+	if err != nil {
+		return err
+	}
+
+	if membership != nil && membership.Id != target.Id && !membership.CanManage(target.Role) {
+		return e.ForbiddenError("You are not allowed to remove the member.", nil)
+	}
+
+	if err := e.App.Delete(target); err != nil {
+		return e.BadRequestError("Failed to remove team member.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// -------------------------------------------------------------------
+
+func teamInvitationsList(e *core.RequestEvent) error {
+	team, membership, err := teamRequestContext(e)
+	if err != nil {
+		return err
+	}
+
+	if membership != nil && !membership.CanManage(core.TeamRoleMember) {
+		return e.ForbiddenError("Only the team owners and admins can manage the team invitations.", nil)
+	}
+
+	invitations := []*core.TeamInvitation{}
+
+	err = e.App.TeamInvitationQuery().
+		AndWhere(dbx.HashExp{"teamRef": team.Id}).
+		OrderBy("created ASC", "id ASC").
+		All(&invitations)
+	if err != nil {
+		return e.InternalServerError("Failed to load the team invitations.", err)
+	}
+
+	return e.JSON(http.StatusOK, invitations)
+}
+
+type teamInvitationForm struct {
+	Email string `form:"email" json:"email"`
+	Role  string `form:"role" json:"role"`
+
+	// TTL is the invitation duration in seconds.
+	TTL int `form:"ttl" json:"ttl"`
+}
+
+func (form *teamInvitationForm) validate() error {
+	return validation.ValidateStruct(form,
+		validation.Field(&form.Email, validation.Required, validation.Length(1, 255), is.EmailFormat),
+		validation.Field(&form.Role, validation.Required, validation.In(core.TeamRoleOwner, core.TeamRoleAdmin, core.TeamRoleMember)),
+		validation.Field(&form.TTL, validation.Required, validation.Min(1), validation.Max(maxTeamInvitationTTL)),
+	)
+}
+
+// teamInvitationCreate creates a new team invitation and sends it to the submitted email address.
+func teamInvitationCreate(e *core.RequestEvent) error {
+	team, membership, err := teamRequestContext(e)
+	if err != nil {
+		return err
+	}
+
+	form := &teamInvitationForm{
+		Role: core.TeamRoleMember,
+		TTL:  defaultTeamInvitationTTL,
+	}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	if err := form.validate(); err != nil {
+		return e.BadRequestError("An error occurred while validating the submitted data.", err)
+	}
+
+	if membership != nil && !membership.CanManage(form.Role) {
+		return e.ForbiddenError("You are not allowed to invite members with the specified role.", nil)
+	}
+
+	invitation, token, err := e.App.CreateTeamInvitation(team, form.Email, form.Role, e.Auth, time.Duration(form.TTL)*time.Second)
+	if err != nil {
+		return teamSaveError(e, "Failed to create team invitation.", err)
+	}
+
+	if err := mails.SendTeamInvitation(e.App, team, invitation, token); err != nil {
+		e.App.Logger().Error("Failed to send team invitation email", "error", err, "invitationId", invitation.Id)
+	}
+
+	return e.JSON(http.StatusOK, invitation)
+}
+
+func teamInvitationDelete(e *core.RequestEvent) error {
+	team, membership, err := teamRequestContext(e)
+	if err != nil {
+		return err
+	}
+
+	invitation := &core.TeamInvitation{}
+
+	err = e.App.TeamInvitationQuery().
+		AndWhere(dbx.HashExp{
+			"id":      e.Request.PathValue("invitationId"),
+			"teamRef": team.Id,
+		}).
+		Limit(1).
+		One(invitation)
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if membership != nil && !membership.CanManage(invitation.Role) {
+		return e.ForbiddenError("You are not allowed to delete the invitation.", nil)
+	}
+
+	if err := e.App.Delete(invitation); err != nil {
+		return e.BadRequestError("Failed to delete team invitation.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+type teamInvitationAcceptForm struct {
+	Token string `form:"token" json:"token"`
+}
+
+// teamInvitationAccept adds the current auth record to the team of the submitted invitation token.
+func teamInvitationAccept(e *core.RequestEvent) error {
+	form := &teamInvitationAcceptForm{}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	invitation, err := e.App.FindTeamInvitationByToken(form.Token)
+	if err != nil {
+		return e.BadRequestError("Invalid or expired invitation token.", err)
+	}
+
+	membership, err := e.App.AcceptTeamInvitation(invitation, e.Auth)
+	if err != nil {
+		return e.BadRequestError("Failed to accept the team invitation.", err)
+	}
+
+	return e.JSON(http.StatusOK, membership)
+}
+
+// -------------------------------------------------------------------
+
+// teamRequestContext returns the request path team and the team
+// membership of the current auth record.
+//
+// The returned membership is nil for superusers.
+func teamRequestContext(e *core.RequestEvent) (*core.Team, *core.TeamMembership, error) {
+	team, err := e.App.FindTeamById(e.Request.PathValue("id"))
+	if err != nil {
+		return nil, nil, e.NotFoundError("", err)
+	}
+
+	if e.HasSuperuserAuth() {
+		return team, nil, nil
+	}
+
+	membership, err := e.App.FindTeamMembership(team, e.Auth)
+	if err != nil {
+		return nil, nil, e.NotFoundError("", err)
+	}
+
+	return team, membership, nil
+}
+
+func findTeamRequestMembership(e *core.RequestEvent, team *core.Team) (*core.TeamMembership, error) {
+	membership := &core.TeamMembership{}
+
+	err := e.App.TeamMembershipQuery().
+		AndWhere(dbx.HashExp{
+			"id":      e.Request.PathValue("membershipId"),
+			"teamRef": team.Id,
+		}).
+		Limit(1).
+		One(membership)
+	if err != nil {
+		return nil, e.NotFoundError("", err)
+	}
+
+	return membership, nil
+}
+
+func teamSaveError(e *core.RequestEvent, message string, err error) error {
+	var validationErrors validation.Errors
+	if errors.As(err, &validationErrors) {
+		return e.BadRequestError(message, validationErrors)
+	}
+
+	return e.BadRequestError(message, err)
+}
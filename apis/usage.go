@@ -0,0 +1,33 @@
+package apis
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// bindUsageApi registers the metered usage api endpoints.
+func bindUsageApi(app core.App, rg *router.RouterGroup[*core.RequestEvent]) {
+	sub := rg.Group("/usage").Bind(RequireSuperuserAuth(), SkipSuccessActivityLog())
+	sub.GET("", usageList)
+}
+
+var usageFilterFields = []string{
+	"id", "tenant", "unit", "period", "value", "created", "updated",
+}
+
+func usageList(e *core.RequestEvent) error {
+	fieldResolver := search.NewSimpleFieldResolver(usageFilterFields...)
+
+	result, err := search.NewProvider(fieldResolver).
+		Query(e.App.UsageQuery()).
+		ParseAndExec(e.Request.URL.Query().Encode(), &[]*core.UsageRecord{})
+
+	if err != nil {
+		return e.BadRequestError("", err)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,368 @@
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cobra"
+)
+
+const (
+	DumpFormatSQL   = "sql"
+	DumpFormatJSONL = "jsonl"
+)
+
+// dumpBatchSize is the max number of records loaded at once.
+const dumpBatchSize = 500
+
+// NewDumpCommand creates and returns new command for exporting
+// the collections data as portable SQL or as JSONL file per collection.
+//
+// The internal collections (superusers, MFAs, OTPs, etc.) and tables
+// and the view collections are not exported.
+// The password and tokenKey field values are also excluded.
+func NewDumpCommand(app core.App) *cobra.Command {
+	var format string
+	var output string
+
+	command := &cobra.Command{
+		Use:   "dump [collection(s)]",
+		Args:  cobra.ArbitraryArgs,
+		Short: "Exports the collections data as portable SQL or JSONL",
+		Example: "dump > data.sql\n" +
+			"dump posts users --output=data.sql\n" +
+			"dump --format=jsonl --output=./dump",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			collections, err := dumpCollections(app, args)
+			if err != nil {
+				return err
+			}
+
+			switch format {
+			case DumpFormatSQL:
+				if output == "" {
+					return dumpSQL(app, command.OutOrStdout(), collections)
+				}
+
+				f, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer f.Close()
+
+				if err := dumpSQL(app, f, collections); err != nil {
+					return err
+				}
+			case DumpFormatJSONL:
+				if output == "" {
+					return errors.New("the --output directory is required for the jsonl format")
+				}
+
+				if err := dumpJSONL(app, output, collections); err != nil {
+					return err
+				}
+			default:
+				return fmt.Errorf("invalid --format value %q, expected %s or %s", format, DumpFormatSQL, DumpFormatJSONL)
+			}
+
+			color.Green("Successfully exported %d collection(s) to %q!", len(collections), output)
+
+			return nil
+		},
+	}
+
+	command.PersistentFlags().StringVar(
+		&format,
+		"format",
+		DumpFormatSQL,
+		"the dump format (sql or jsonl)",
+	)
+
+	command.PersistentFlags().StringVar(
+		&output,
+		"output",
+		"",
+		"the output file for the sql format (default to stdout) or the output directory for the jsonl format",
+	)
+
+	return command
+}
+
+// dumpCollections returns the exportable collections.
+//
+// If names are specified only the matching collections are returned.
+func dumpCollections(app core.App, names []string) ([]*core.Collection, error) {
+	if len(names) > 0 {
+		result := make([]*core.Collection, 0, len(names))
+
+		for _, name := range names {
+			collection, err := app.FindCollectionByNameOrId(name)
+			if err != nil {
+				return nil, fmt.Errorf("failed to find collection %q: %w", name, err)
+			}
+
+			if !isDumpable(collection) {
+				return nil, fmt.Errorf("collection %q cannot be exported", collection.Name)
+			}
+
+			result = append(result, collection)
+		}
+
+		return result, nil
+	}
+
+	all := []*core.Collection{}
+	if err := app.CollectionQuery().OrderBy("name ASC").All(&all); err != nil {
+		return nil, err
+	}
+
+	result := make([]*core.Collection, 0, len(all))
+	for _, collection := range all {
+		if isDumpable(collection) {
+			result = append(result, collection)
+		}
+	}
+
+	return result, nil
+}
+
+// internalCollections lists the system collections with sensitive auth data.
+var internalCollections = []string{
+	core.CollectionNameSuperusers,
+	core.CollectionNameExternalAuths,
+	core.CollectionNameMFAs,
+	core.CollectionNameOTPs,
+	core.CollectionNameAuthOrigins,
+}
+
+func isDumpable(collection *core.Collection) bool {
+	return !collection.IsView() && !slices.Contains(internalCollections, collection.Name)
+}
+
+// dumpFields returns the collection fields that are allowed to be exported.
+func dumpFields(collection *core.Collection) []core.Field {
+	result := make([]core.Field, 0, len(collection.Fields))
+
+	for _, field := range collection.Fields {
+		if field.Type() == core.FieldTypePassword {
+			continue
+		}
+
+		if collection.IsAuth() && field.GetName() == core.FieldNameTokenKey {
+			continue
+		}
+
+		result = append(result, field)
+	}
+
+	return result
+}
+
+// eachDumpRecord calls fn for every collection record ordered by their id.
+func eachDumpRecord(app core.App, collection *core.Collection, fn func(record *core.Record) error) error {
+	var lastId string
+
+	for {
+		records := []*core.Record{}
+
+		query := app.RecordQuery(collection).OrderBy("id ASC").Limit(dumpBatchSize)
+		if lastId != "" {
+			query.AndWhere(dbx.NewExp("[[id]] > {:lastId}", dbx.Params{"lastId": lastId}))
+		}
+
+		if err := query.All(&records); err != nil {
+			return fmt.Errorf("failed to load %q records: %w", collection.Name, err)
+		}
+
+		for _, record := range records {
+			if err := fn(record); err != nil {
+				return err
+			}
+		}
+
+		if len(records) < dumpBatchSize {
+			return nil
+		}
+
+		lastId = records[len(records)-1].Id
+	}
+}
+
+// -------------------------------------------------------------------
+// SQL
+// -------------------------------------------------------------------
+
+func dumpSQL(app core.App, w io.Writer, collections []*core.Collection) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString("BEGIN;\n")
+
+	for _, collection := range collections {
+		fields := dumpFields(collection)
+
+		columns := make([]string, len(fields))
+		for i, field := range fields {
+			columns[i] = sqlIdentifier(field.GetName())
+		}
+
+		bw.WriteString("\n")
+		bw.WriteString(sqlCreateTable(collection.Name, fields))
+		bw.WriteString("\n")
+
+		insertPrefix := "INSERT INTO " + sqlIdentifier(collection.Name) + " (" + strings.Join(columns, ", ") + ") VALUES ("
+
+		err := eachDumpRecord(app, collection, func(record *core.Record) error {
+			values := make([]string, len(fields))
+			for i, field := range fields {
+				literal, err := sqlLiteral(record.GetRaw(field.GetName()))
+				if err != nil {
+					return fmt.Errorf("failed to export %s.%s value: %w", collection.Name, field.GetName(), err)
+				}
+				values[i] = literal
+			}
+
+			bw.WriteString(insertPrefix)
+			bw.WriteString(strings.Join(values, ", "))
+			bw.WriteString(");\n")
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	bw.WriteString("\nCOMMIT;\n")
+
+	return bw.Flush()
+}
+
+func sqlCreateTable(tableName string, fields []core.Field) string {
+	columns := make([]string, len(fields))
+
+	for i, field := range fields {
+		name := field.GetName()
+
+		if name == core.FieldNameId {
+			columns[i] = "\t" + sqlIdentifier(name) + " TEXT PRIMARY KEY NOT NULL"
+			continue
+		}
+
+		var columnType string
+		switch field.Type() {
+		case core.FieldTypeNumber:
+			columnType = "NUMERIC"
+		case core.FieldTypeBool:
+			columnType = "BOOLEAN"
+		default:
+			// json, multiple values and dates are stored as text for portability
+			columnType = "TEXT"
+		}
+
+		columns[i] = "\t" + sqlIdentifier(name) + " " + columnType
+	}
+
+	return "CREATE TABLE " + sqlIdentifier(tableName) + " (\n" + strings.Join(columns, ",\n") + "\n);"
+}
+
+func sqlIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+func sqlLiteral(value any) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "NULL", nil
+	case bool:
+		if v {
+			return "TRUE", nil
+		}
+		return "FALSE", nil
+	case int:
+		return strconv.Itoa(v), nil
+	case int64:
+		return strconv.FormatInt(v, 10), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case string:
+		return sqlString(v), nil
+	case types.DateTime:
+		if v.IsZero() {
+			return "NULL", nil
+		}
+		return sqlString(v.String()), nil
+	case types.JSONRaw:
+		if len(v) == 0 {
+			return "NULL", nil
+		}
+		return sqlString(string(v)), nil
+	default:
+		raw, err := json.Marshal(v)
+		if err != nil {
+			return "", err
+		}
+		return sqlString(string(raw)), nil
+	}
+}
+
+func sqlString(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", "''") + "'"
+}
+
+// -------------------------------------------------------------------
+// JSONL
+// -------------------------------------------------------------------
+
+func dumpJSONL(app core.App, dir string, collections []*core.Collection) error {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	for _, collection := range collections {
+		if err := dumpCollectionJSONL(app, filepath.Join(dir, collection.Name+".jsonl"), collection); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func dumpCollectionJSONL(app core.App, path string, collection *core.Collection) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %q: %w", path, err)
+	}
+	defer f.Close()
+
+	bw := bufio.NewWriter(f)
+	encoder := json.NewEncoder(bw)
+
+	fields := dumpFields(collection)
+
+	err = eachDumpRecord(app, collection, func(record *core.Record) error {
+		data := make(map[string]any, len(fields))
+		for _, field := range fields {
+			data[field.GetName()] = record.GetRaw(field.GetName())
+		}
+
+		return encoder.Encode(data)
+	})
+	if err != nil {
+		return err
+	}
+
+	return bw.Flush()
+}
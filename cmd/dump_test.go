@@ -0,0 +1,197 @@
+package cmd_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestDumpCommandSQL(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	out := new(bytes.Buffer)
+
+	command := cmd.NewDumpCommand(app)
+	command.SetOut(out)
+	command.SetArgs([]string{"demo2", "users"})
+
+	if err := command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	dump := out.String()
+
+	expectedParts := []string{
+		"BEGIN;\n",
+		"CREATE TABLE \"demo2\" (\n\t\"id\" TEXT PRIMARY KEY NOT NULL,\n\t\"title\" TEXT,\n\t\"active\" BOOLEAN,\n",
+		`INSERT INTO "demo2" ("id", "title", "active", "created", "updated") VALUES ('0yxhwia2amd8gec', 'test3', TRUE, `,
+		`INSERT INTO "demo2" ("id", "title", "active", "created", "updated") VALUES ('llvuca81nly1qls', 'test1', FALSE, `,
+		`CREATE TABLE "users"`,
+		`'test@example.com'`,
+		"COMMIT;\n",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(dump, part) {
+			t.Fatalf("Missing %q in\n%s", part, dump)
+		}
+	}
+
+	notExpectedParts := []string{
+		`"password"`,
+		`"tokenKey"`,
+		`"demo1"`,
+		`"_superusers"`,
+	}
+	for _, part := range notExpectedParts {
+		if strings.Contains(dump, part) {
+			t.Fatalf("Didn't expect %q in\n%s", part, dump)
+		}
+	}
+}
+
+func TestDumpCommandSQLAllCollections(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	output := filepath.Join(app.DataDir(), "dump.sql")
+
+	command := cmd.NewDumpCommand(app)
+	command.SetArgs([]string{"--output", output})
+
+	if err := command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	raw, err := os.ReadFile(output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	dump := string(raw)
+
+	expectedTables := []string{"demo1", "demo2", "demo3", "demo4", "demo5", "users", "clients", "nologin"}
+	for _, table := range expectedTables {
+		if !strings.Contains(dump, `CREATE TABLE "`+table+`"`) {
+			t.Fatalf("Missing %q table", table)
+		}
+	}
+
+	// system and view collections
+	notExpectedTables := []string{"_superusers", "_mfas", "_otps", "_externalAuths", "_authOrigins", "view1", "view2"}
+	for _, table := range notExpectedTables {
+		if strings.Contains(dump, `"`+table+`"`) {
+			t.Fatalf("Didn't expect %q table", table)
+		}
+	}
+}
+
+func TestDumpCommandJSONL(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := filepath.Join(app.DataDir(), "dump")
+
+	command := cmd.NewDumpCommand(app)
+	command.SetArgs([]string{"demo2", "users", "--format", "jsonl", "--output", dir})
+
+	if err := command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 dump files, got %d", len(entries))
+	}
+
+	scenarios := []struct {
+		collection string
+		total      int
+	}{
+		{"demo2", 3},
+		{"users", 3},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.collection, func(t *testing.T) {
+			f, err := os.Open(filepath.Join(dir, s.collection+".jsonl"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer f.Close()
+
+			var total int
+
+			scanner := bufio.NewScanner(f)
+			for scanner.Scan() {
+				total++
+
+				data := map[string]any{}
+				if err := json.Unmarshal(scanner.Bytes(), &data); err != nil {
+					t.Fatalf("Failed to decode line %d: %v", total, err)
+				}
+
+				if _, ok := data["id"]; !ok {
+					t.Fatalf("Missing id in line %d: %v", total, data)
+				}
+
+				for _, k := range []string{"password", "tokenKey"} {
+					if _, ok := data[k]; ok {
+						t.Fatalf("Didn't expect %q in line %d", k, total)
+					}
+				}
+			}
+
+			if total != s.total {
+				t.Fatalf("Expected %d records, got %d", s.total, total)
+			}
+		})
+	}
+}
+
+func TestDumpCommandErrors(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name string
+		args []string
+	}{
+		{"missing collection", []string{"missing"}},
+		{"system collection", []string{"_superusers"}},
+		{"view collection", []string{"view1"}},
+		{"invalid format", []string{"--format", "csv"}},
+		{"jsonl without output", []string{"--format", "jsonl"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			command := cmd.NewDumpCommand(app)
+			command.SetOut(new(bytes.Buffer))
+			command.SetErr(new(bytes.Buffer))
+			command.SetArgs(s.args)
+
+			if err := command.Execute(); err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+		})
+	}
+}
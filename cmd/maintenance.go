@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/fatih/color"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+)
+
+// NewMaintenanceCommand creates and returns new command for managing
+// the app read-only maintenance mode (on, off, status).
+//
+// The change is picked up by an already running app within a few seconds.
+func NewMaintenanceCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "maintenance",
+		Short: "Manage the app read-only maintenance mode",
+	}
+
+	command.AddCommand(maintenanceOnCommand(app))
+	command.AddCommand(maintenanceOffCommand(app))
+	command.AddCommand(maintenanceStatusCommand(app))
+
+	return command
+}
+
+func maintenanceOnCommand(app core.App) *cobra.Command {
+	var message string
+	var retryAfter int
+
+	command := &cobra.Command{
+		Use:          "on",
+		Example:      "maintenance on --message=\"Database migration\" --retry-after=300",
+		Short:        "Enables the app read-only mode",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			state := &core.MaintenanceState{
+				ReadOnly:   true,
+				Message:    message,
+				RetryAfter: retryAfter,
+			}
+
+			if err := app.SaveMaintenanceState(state); err != nil {
+				return fmt.Errorf("failed to enable the read-only mode: %w", err)
+			}
+
+			color.Green("Successfully enabled the read-only mode!")
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&message, "message", "", "optional message returned with the rejected requests")
+	command.Flags().IntVar(&retryAfter, "retry-after", 0, fmt.Sprintf("Retry-After header value in seconds (default %d)", core.DefaultMaintenanceRetryAfter))
+
+	return command
+}
+
+func maintenanceOffCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:          "off",
+		Example:      "maintenance off",
+		Short:        "Disables the app read-only mode",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			if err := app.SaveMaintenanceState(&core.MaintenanceState{}); err != nil {
+				return fmt.Errorf("failed to disable the read-only mode: %w", err)
+			}
+
+			color.Green("Successfully disabled the read-only mode!")
+			return nil
+		},
+	}
+
+	return command
+}
+
+func maintenanceStatusCommand(app core.App) *cobra.Command {
+	command := &cobra.Command{
+		Use:          "status",
+		Example:      "maintenance status",
+		Short:        "Prints the app read-only mode status",
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			state, err := app.MaintenanceState()
+			if err != nil {
+				return fmt.Errorf("failed to load the maintenance state: %w", err)
+			}
+
+			status := "off"
+			if state.ReadOnly {
+				status = "on"
+			}
+
+			fmt.Fprintf(command.OutOrStdout(), "read-only: %s\n", status)
+			if state.ReadOnly {
+				fmt.Fprintf(command.OutOrStdout(), "message: %s\n", state.Message)
+				fmt.Fprintf(command.OutOrStdout(), "retry-after: %d\n", state.RetryAfterValue())
+			}
+			if !state.Updated.IsZero() {
+				fmt.Fprintf(command.OutOrStdout(), "updated: %s\n", state.Updated.String())
+			}
+
+			return nil
+		},
+	}
+
+	return command
+}
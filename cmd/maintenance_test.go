@@ -0,0 +1,86 @@
+package cmd_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/cmd"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestMaintenanceCommand(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	status := func() string {
+		var out bytes.Buffer
+
+		command := cmd.NewMaintenanceCommand(app)
+		command.SetOut(&out)
+		command.SetArgs([]string{"status"})
+
+		if err := command.Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		return out.String()
+	}
+
+	if out := status(); !strings.Contains(out, "read-only: off") {
+		t.Fatalf("Expected initial off status, got\n%s", out)
+	}
+
+	// invalid flags
+	command := cmd.NewMaintenanceCommand(app)
+	command.SetArgs([]string{"on", "--retry-after=-1"})
+	if err := command.Execute(); err == nil {
+		t.Fatal("Expected error for invalid --retry-after, got nil")
+	}
+
+	// on
+	command = cmd.NewMaintenanceCommand(app)
+	command.SetArgs([]string{"on", "--message=test", "--retry-after=120"})
+	if err := command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Store().Remove(core.StoreKeyMaintenanceState)
+	state, err := app.MaintenanceState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.ReadOnly || state.Message != "test" || state.RetryAfter != 120 {
+		t.Fatalf("Unexpected maintenance state %#v", state)
+	}
+
+	out := status()
+	for _, part := range []string{"read-only: on", "message: test", "retry-after: 120", "updated: "} {
+		if !strings.Contains(out, part) {
+			t.Fatalf("Missing %q in\n%s", part, out)
+		}
+	}
+
+	// off
+	command = cmd.NewMaintenanceCommand(app)
+	command.SetArgs([]string{"off"})
+	if err := command.Execute(); err != nil {
+		t.Fatal(err)
+	}
+
+	app.Store().Remove(core.StoreKeyMaintenanceState)
+	state, err = app.MaintenanceState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ReadOnly {
+		t.Fatal("Expected the read-only mode to be disabled")
+	}
+
+	if out := status(); !strings.Contains(out, "read-only: off") {
+		t.Fatalf("Expected off status, got\n%s", out)
+	}
+}
@@ -1,12 +1,19 @@
 package cmd
 
 import (
+	"context"
 	"errors"
 	"net/http"
+	"os"
+	"os/signal"
+	"strings"
 
 	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/acmedns"
+	"github.com/pocketbase/pocketbase/tools/certcache"
 	"github.com/spf13/cobra"
+	"golang.org/x/crypto/acme/autocert"
 )
 
 // NewServeCommand creates and returns new command responsible for
@@ -15,6 +22,9 @@ func NewServeCommand(app core.App, showStartBanner bool) *cobra.Command {
 	var allowedOrigins []string
 	var httpAddr string
 	var httpsAddr string
+	var dnsProvider string
+	var onDemandTLS string
+	var certCache string
 
 	command := &cobra.Command{
 		Use:          "serve [domain(s)]",
@@ -22,9 +32,39 @@ func NewServeCommand(app core.App, showStartBanner bool) *cobra.Command {
 		Short:        "Starts the web server (default to 127.0.0.1:8090 if no domain is specified)",
 		SilenceUsage: true,
 		RunE: func(command *cobra.Command, args []string) error {
+			var certDNSProvider acmedns.Provider
+			if dnsProvider != "" {
+				var err error
+				certDNSProvider, err = acmedns.NewProviderFromEnv(dnsProvider)
+				if err != nil {
+					return err
+				}
+			}
+
+			var certHostPolicy func(ctx context.Context, host string) error
+			if onDemandTLS != "" {
+				collection, field, ok := strings.Cut(onDemandTLS, ".")
+				if !ok || collection == "" || field == "" {
+					return errors.New("invalid --onDemandTLS value, expected collection.field format")
+				}
+				certHostPolicy = apis.RecordHostPolicy(app, collection, field)
+			}
+
+			var certificateCache autocert.Cache
+			switch certCache {
+			case "":
+				// use the default local cache dir
+			case "storage":
+				certificateCache = certcache.NewFilesystem(app.NewFilesystem, core.LocalAutocertCacheDirName)
+			default:
+				return errors.New("invalid --certCache value, expected storage")
+			}
+
 			// set default listener addresses if at least one domain is specified
-			if len(args) > 0 {
-				if httpAddr == "" {
+			// (or the on-demand TLS is enabled)
+			if len(args) > 0 || certHostPolicy != nil {
+				// (the HTTP server is not required for the DNS-01 challenge)
+				if httpAddr == "" && certDNSProvider == nil {
 					httpAddr = "0.0.0.0:80"
 				}
 				if httpsAddr == "" {
@@ -36,12 +76,31 @@ func NewServeCommand(app core.App, showStartBanner bool) *cobra.Command {
 				}
 			}
 
+			// listen for restart signal to gracefully replace the application process
+			// (e.g. after updating the executable)
+			if len(restartSignals) > 0 {
+				sigch := make(chan os.Signal, 1)
+				signal.Notify(sigch, restartSignals...)
+				defer signal.Stop(sigch)
+
+				go func() {
+					for range sigch {
+						if err := app.Restart(); err != nil {
+							app.Logger().Error("Failed to restart the application", "error", err)
+						}
+					}
+				}()
+			}
+
 			err := apis.Serve(app, apis.ServeConfig{
-				HttpAddr:           httpAddr,
-				HttpsAddr:          httpsAddr,
-				ShowStartBanner:    showStartBanner,
-				AllowedOrigins:     allowedOrigins,
-				CertificateDomains: args,
+				HttpAddr:               httpAddr,
+				HttpsAddr:              httpsAddr,
+				ShowStartBanner:        showStartBanner,
+				AllowedOrigins:         allowedOrigins,
+				CertificateDomains:     args,
+				CertificateDNSProvider: certDNSProvider,
+				CertificateHostPolicy:  certHostPolicy,
+				CertificateCache:       certificateCache,
 			})
 
 			if errors.Is(err, http.ErrServerClosed) {
@@ -73,5 +132,26 @@ func NewServeCommand(app core.App, showStartBanner bool) *cobra.Command {
 		"TCP address to listen for the HTTPS server\n(if domain args are specified - default to 0.0.0.0:443, otherwise - default to empty string, aka. no TLS)\nThe incoming HTTP traffic also will be auto redirected to the HTTPS version",
 	)
 
+	command.PersistentFlags().StringVar(
+		&dnsProvider,
+		"dns",
+		"",
+		"ACME DNS-01 challenge provider for issuing the domain(s) TLS certificates (cloudflare, route53 or rfc2136)\nIt allows wildcard domains and instances that are not reachable on port 80\nThe provider credentials are loaded from env variables (eg. CLOUDFLARE_API_TOKEN)",
+	)
+
+	command.PersistentFlags().StringVar(
+		&onDemandTLS,
+		"onDemandTLS",
+		"",
+		"enable on-demand TLS certificates for the host names stored in the specified collection field\n(in the format collection.field, eg. domains.host)",
+	)
+
+	command.PersistentFlags().StringVar(
+		&certCache,
+		"certCache",
+		"",
+		"shared storage for the issued TLS certificates when running multiple instances\n(storage - the app files storage, aka. S3 if configured)",
+	)
+
 	return command
 }
@@ -0,0 +1,9 @@
+//go:build !unix
+
+package cmd
+
+import "os"
+
+// restartSignals are the signals that trigger graceful app restart
+// (not supported because the restart relies on execve).
+var restartSignals []os.Signal
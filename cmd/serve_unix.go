@@ -0,0 +1,11 @@
+//go:build unix
+
+package cmd
+
+import (
+	"os"
+	"syscall"
+)
+
+// restartSignals are the signals that trigger graceful app restart.
+var restartSignals = []os.Signal{syscall.SIGUSR2}
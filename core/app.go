@@ -4,15 +4,21 @@
 package core
 
 import (
+	"archive/zip"
 	"context"
+	"io"
 	"log/slog"
+	"net/http"
 	"time"
 
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/cron"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/geoip"
 	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/i18n"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/sms"
 	"github.com/pocketbase/pocketbase/tools/store"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
 )
@@ -76,9 +82,20 @@ type App interface {
 	// Settings returns the loaded app settings.
 	Settings() *Settings
 
+	// SettingsOverlay returns a copy of the flattened environment specific
+	// settings overlay (e.g. {"smtp.host": "example.com"}) that is applied
+	// on top of the stored app settings.
+	//
+	// The overlaid settings keys are read-only and cannot be changed at runtime.
+	SettingsOverlay() map[string]any
+
 	// Store returns the app runtime store.
 	Store() *store.Store[string, any]
 
+	// I18n returns the app translations catalog used for localizing
+	// the API error messages (could be extended or overwritten with custom translations).
+	I18n() *i18n.Catalog
+
 	// Cron returns the app cron instance.
 	Cron() *cron.Cron
 
@@ -89,6 +106,12 @@ type App interface {
 	// based on the current app settings.
 	NewMailClient() mailer.Mailer
 
+	// NewSMSClient creates and returns a new SMS client
+	// based on the current app settings.
+	//
+	// Every message send triggers the [App.OnSMSSend] hook.
+	NewSMSClient() sms.Sender
+
 	// NewFilesystem creates a new local or S3 filesystem instance
 	// for managing regular app files (ex. record uploads)
 	// based on the current app settings.
@@ -107,6 +130,16 @@ type App interface {
 	// ReloadSettings reinitializes and reloads the stored application settings.
 	ReloadSettings() error
 
+	// MaintenanceState returns the current app maintenance (aka. read-only mode) state.
+	//
+	// The state is cached for a short period of time so that changes
+	// made by another process (e.g. the maintenance console command)
+	// are eventually picked up without restarting the app.
+	MaintenanceState() (*MaintenanceState, error)
+
+	// SaveMaintenanceState persists the provided app maintenance state.
+	SaveMaintenanceState(state *MaintenanceState) error
+
 	// CreateBackup creates a new backup of the current app pb_data directory.
 	//
 	// Backups can be stored on S3 if it is configured in app.Settings().Backups.
@@ -371,6 +404,542 @@ type App interface {
 
 	// ---------------------------------------------------------------
 
+	// ChangeQuery returns a new changefeed entries select query.
+	ChangeQuery() *dbx.SelectQuery
+
+	// FindChanges returns up to limit changefeed entries with Seq greater than
+	// afterSeq ordered by their Seq (oldest first).
+	//
+	// collectionIdOrName is optional and when set only the changes
+	// of the matching collection are returned.
+	FindChanges(afterSeq int64, collectionIdOrName string, limit int) ([]*Change, error)
+
+	// DeleteOldChanges deletes all changefeed entries that are created before createdBefore.
+	DeleteOldChanges(createdBefore time.Time) error
+
+	// ---------------------------------------------------------------
+
+	// ListPresetQuery returns a new ListPreset select query.
+	ListPresetQuery() *dbx.SelectQuery
+
+	// FindListPresetById returns a single ListPreset model by its id.
+	FindListPresetById(id string) (*ListPreset, error)
+
+	// FindAllListPresets returns all shared collection ListPreset models
+	// and the ones owned by the optional authRecord (ordered by name).
+	FindAllListPresets(collection *Collection, authRecord *Record) ([]*ListPreset, error)
+
+	// FindListPresetByName returns a single collection ListPreset model by its name.
+	//
+	// The presets owned by the optional authRecord take precedence over
+	// the shared ones with the same name.
+	FindListPresetByName(collection *Collection, authRecord *Record, name string) (*ListPreset, error)
+
+	// ---------------------------------------------------------------
+
+	// RecycleBinQuery returns a new RecycledRecord select query.
+	RecycleBinQuery() *dbx.SelectQuery
+
+	// FindRecycledRecordById returns a single RecycledRecord model by its id.
+	FindRecycledRecordById(id string) (*RecycledRecord, error)
+
+	// RestoreRecycledRecord recreates the deleted record from the provided
+	// recycle bin entry and removes the entry from the recycle bin.
+	//
+	// The restored record is saved with the regular record create validations and hooks
+	// (aka. it will fail if the record collection was deleted or if
+	// a record with the same unique values was created in the meantime).
+	//
+	// Note that the relations to the restored record that were removed
+	// during its deletion are not restored.
+	RestoreRecycledRecord(item *RecycledRecord) (*Record, error)
+
+	// PurgeRecycledRecord permanently deletes the provided recycle bin entry
+	// together with its stored record files.
+	PurgeRecycledRecord(item *RecycledRecord) error
+
+	// DeleteOldRecycledRecords purges all recycle bin entries that are created before createdBefore.
+	DeleteOldRecycledRecords(createdBefore time.Time) error
+
+	// ---------------------------------------------------------------
+
+	// TombstoneQuery returns a new deletions log select query.
+	TombstoneQuery() *dbx.SelectQuery
+
+	// FindTombstones returns up to limit collection tombstones deleted
+	// after deletedAfter ordered by their delete date (oldest first).
+	//
+	// deletedAfter is optional and when zero all tombstones of the collection are returned.
+	FindTombstones(collectionIdOrName string, deletedAfter time.Time, limit int) ([]*Tombstone, error)
+
+	// DeleteOldTombstones deletes all tombstones that are created before deletedBefore.
+	DeleteOldTombstones(deletedBefore time.Time) error
+
+	// ---------------------------------------------------------------
+
+	// ScheduledChangeQuery returns a new ScheduledChange select query.
+	ScheduledChangeQuery() *dbx.SelectQuery
+
+	// FindScheduledChangeById returns a single ScheduledChange model by its id.
+	FindScheduledChangeById(id string) (*ScheduledChange, error)
+
+	// FindRecordScheduledChanges returns all ScheduledChange models
+	// of the provided record (ordered by their scheduled date).
+	FindRecordScheduledChanges(record *Record) ([]*ScheduledChange, error)
+
+	// ApplyScheduledChange applies the provided change to its record
+	// and deletes the change on success.
+	//
+	// The change is applied as regular record update (aka. with the
+	// standard record update validations and hooks).
+	//
+	// On failure the change is kept and its Error is updated with the apply error.
+	ApplyScheduledChange(change *ScheduledChange) error
+
+	// ApplyDueScheduledChanges applies all non-failed scheduled changes
+	// with scheduledAt before or equal to the provided date (ordered by their scheduled date).
+	//
+	// A failed change doesn't prevent applying the remaining ones.
+	ApplyDueScheduledChanges(date time.Time) error
+
+	// ---------------------------------------------------------------
+
+	// RecordLockQuery returns a new RecordLock select query.
+	RecordLockQuery() *dbx.SelectQuery
+
+	// FindRecordLock returns the active (aka. non-expired) lock of the provided record.
+	//
+	// Returns [sql.ErrNoRows] if the record is not locked.
+	FindRecordLock(record *Record) (*RecordLock, error)
+
+	// FindRecordLocks returns the active (aka. non-expired) locks
+	// of the specified collection records.
+	FindRecordLocks(collection *Collection, recordIds ...string) ([]*RecordLock, error)
+
+	// AcquireRecordLock locks the provided record for the specified owner
+	// auth record until ttl passes.
+	//
+	// If the record is already locked by the same owner, the lock expiration is renewed.
+	//
+	// Returns [ErrRecordLocked] if the record has an active lock held by another owner.
+	AcquireRecordLock(record *Record, owner *Record, ttl time.Duration) (*RecordLock, error)
+
+	// ReleaseRecordLock releases the lock of the provided record (if any).
+	//
+	// Set owner to nil to force release the lock regardless of its owner.
+	//
+	// Returns [ErrRecordLocked] if the record has an active lock held by another owner.
+	ReleaseRecordLock(record *Record, owner *Record) error
+
+	// DeleteExpiredRecordLocks deletes all expired record locks.
+	DeleteExpiredRecordLocks() error
+
+	// ---------------------------------------------------------------
+
+	// RecordShareQuery returns a new RecordShare select query.
+	RecordShareQuery() *dbx.SelectQuery
+
+	// FindRecordShares returns the active (aka. non-expired) shares of the provided record.
+	FindRecordShares(record *Record) ([]*RecordShare, error)
+
+	// FindRecordShareByToken finds the active (aka. non-expired) record share by its plain token.
+	FindRecordShareByToken(token string) (*RecordShare, error)
+
+	// CreateRecordShare creates a new share of the provided record
+	// with the specified scope that expires after ttl.
+	//
+	// Returns the created share and its plain token (it is not stored and cannot be retrieved later).
+	CreateRecordShare(record *Record, owner *Record, scope string, ttl time.Duration) (*RecordShare, string, error)
+
+	// DeleteExpiredRecordShares deletes all expired record shares.
+	DeleteExpiredRecordShares() error
+
+	// ---------------------------------------------------------------
+
+	// RoleQuery returns a new Role select query.
+	RoleQuery() *dbx.SelectQuery
+
+	// FindRoleById returns a single Role model by its id.
+	FindRoleById(id string) (*Role, error)
+
+	// FindRoleByName returns a single Role model by its name.
+	FindRoleByName(name string) (*Role, error)
+
+	// FindAuthRecordRoles returns all roles assigned to the provided auth record (ordered by name).
+	FindAuthRecordRoles(authRecord *Record) ([]*Role, error)
+
+	// AssignRole assigns the role to the provided auth record.
+	//
+	// It does nothing if the role is already assigned.
+	AssignRole(authRecord *Record, role *Role) error
+
+	// UnassignRole removes the role assignment of the provided auth record (if any).
+	UnassignRole(authRecord *Record, role *Role) error
+
+	// AuthRecordHasRole reports whether the provided auth record has the named role.
+	AuthRecordHasRole(authRecord *Record, roleName string) bool
+
+	// AuthRecordHasPermission reports whether any of the provided
+	// auth record roles grants the specified permission.
+	//
+	// Superusers have all permissions.
+	AuthRecordHasPermission(authRecord *Record, permission string) bool
+
+	// ---------------------------------------------------------------
+
+	// TeamQuery returns a new Team select query.
+	TeamQuery() *dbx.SelectQuery
+
+	// TeamMembershipQuery returns a new TeamMembership select query.
+	TeamMembershipQuery() *dbx.SelectQuery
+
+	// TeamInvitationQuery returns a new TeamInvitation select query.
+	TeamInvitationQuery() *dbx.SelectQuery
+
+	// FindTeamById returns a single Team model by its id.
+	FindTeamById(id string) (*Team, error)
+
+	// FindAuthRecordTeams returns all teams that the provided auth record is member of (ordered by name).
+	FindAuthRecordTeams(authRecord *Record) ([]*Team, error)
+
+	// FindTeamMemberships returns all memberships of the provided team (ordered by their creation date).
+	FindTeamMemberships(team *Team) ([]*TeamMembership, error)
+
+	// FindTeamMembership returns the team membership of the provided auth record.
+	FindTeamMembership(team *Team, authRecord *Record) (*TeamMembership, error)
+
+	// AddTeamMember adds the provided auth record to the team with the specified role.
+	//
+	// If the auth record is already a team member, only its membership role is updated.
+	AddTeamMember(team *Team, authRecord *Record, role string) (*TeamMembership, error)
+
+	// RemoveTeamMember removes the provided auth record from the team (if it is a member).
+	RemoveTeamMember(team *Team, authRecord *Record) error
+
+	// FindTeamInvitationByToken finds the active (aka. non-expired) team invitation by its plain token.
+	FindTeamInvitationByToken(token string) (*TeamInvitation, error)
+
+	// CreateTeamInvitation creates a new team invitation for the specified
+	// email address and role that expires after ttl.
+	//
+	// Returns the created invitation and its plain token (it is not stored and cannot be retrieved later).
+	CreateTeamInvitation(team *Team, email string, role string, inviter *Record, ttl time.Duration) (*TeamInvitation, string, error)
+
+	// AcceptTeamInvitation adds the provided auth record to the invitation
+	// team and deletes the invitation.
+	//
+	// The auth record email must match with the invitation one.
+	AcceptTeamInvitation(invitation *TeamInvitation, authRecord *Record) (*TeamMembership, error)
+
+	// DeleteExpiredTeamInvitations deletes all expired team invitations.
+	DeleteExpiredTeamInvitations() error
+
+	// ---------------------------------------------------------------
+
+	// SignupInviteQuery returns a new SignupInvite select query.
+	SignupInviteQuery() *dbx.SelectQuery
+
+	// FindSignupInviteByToken finds the active (aka. non-expired and non-exhausted)
+	// signup invite by its plain token.
+	FindSignupInviteByToken(token string) (*SignupInvite, error)
+
+	// CreateSignupInvite generates a new token for the provided
+	// (unsaved) signup invite and saves it with expiration after ttl.
+	//
+	// Returns the plain invite token (it is not stored and cannot be retrieved later).
+	CreateSignupInvite(invite *SignupInvite, ttl time.Duration) (string, error)
+
+	// UseSignupInvite registers a single use of the invite by the provided
+	// (usually newly created) auth record and assigns to it the invite roles and team.
+	UseSignupInvite(invite *SignupInvite, authRecord *Record) error
+
+	// DeleteExpiredSignupInvites deletes all expired and exhausted signup invites.
+	DeleteExpiredSignupInvites() error
+
+	// ---------------------------------------------------------------
+
+	// AppConfigQuery returns a new AppConfigEntry select query.
+	AppConfigQuery() *dbx.SelectQuery
+
+	// FindAppConfigEntryByKey returns a single AppConfigEntry model by its key.
+	FindAppConfigEntryByKey(key string) (*AppConfigEntry, error)
+
+	// FindCachedAppConfigEntries returns all AppConfigEntry models (ordered by key).
+	//
+	// The entries are cached and the cache is reset on every app config entry change.
+	//
+	// NB! The returned entries are shared and must not be modified.
+	FindCachedAppConfigEntries() ([]*AppConfigEntry, error)
+
+	// CanAccessAppConfigEntry checks if the app config entry
+	// could be read by the specified requestInfo.
+	//
+	// Rule and db checks are ignored in case requestInfo.Auth is a superuser.
+	//
+	// The method always return false on invalid rule or db query error.
+	CanAccessAppConfigEntry(entry *AppConfigEntry, requestInfo *RequestInfo) (bool, error)
+
+	// ---------------------------------------------------------------
+
+	// FeatureFlagQuery returns a new FeatureFlag select query.
+	FeatureFlagQuery() *dbx.SelectQuery
+
+	// FindFeatureFlagByKey returns a single FeatureFlag model by its key.
+	FindFeatureFlagByKey(key string) (*FeatureFlag, error)
+
+	// FindCachedFeatureFlags returns all FeatureFlag models (ordered by key).
+	//
+	// The flags are cached and the cache is reset on every feature flag change.
+	//
+	// NB! The returned flags are shared and must not be modified.
+	FindCachedFeatureFlags() ([]*FeatureFlag, error)
+
+	// EvaluateFeatureFlag evaluates the feature flag with the specified key
+	// against the provided requestInfo (it could be nil, eg. for background jobs).
+	//
+	// Returns [sql.ErrNoRows] if the flag doesn't exist.
+	EvaluateFeatureFlag(key string, requestInfo *RequestInfo) (*FeatureFlagEvaluation, error)
+
+	// EvaluateFeatureFlags evaluates all feature flags against the provided requestInfo
+	// and returns the evaluation results keyed by the flag key.
+	//
+	// Flags with invalid rule are evaluated as disabled.
+	EvaluateFeatureFlags(requestInfo *RequestInfo) (map[string]*FeatureFlagEvaluation, error)
+
+	// ---------------------------------------------------------------
+
+	// ExperimentQuery returns a new Experiment select query.
+	ExperimentQuery() *dbx.SelectQuery
+
+	// FindExperimentByKey returns a single Experiment model by its key.
+	FindExperimentByKey(key string) (*Experiment, error)
+
+	// AssignExperimentVariant returns the experiment variant of the requestInfo subject,
+	// persisting a new weighted variant assignment if the subject doesn't have one yet.
+	//
+	// Returns an empty string if the subject is not enrolled in the experiment
+	// (eg. inactive experiment, disabled related feature flag or missing subject).
+	AssignExperimentVariant(experiment *Experiment, requestInfo *RequestInfo) (string, error)
+
+	// TrackExperimentExposure assigns the experiment variant of the requestInfo subject
+	// (see [App.AssignExperimentVariant]) and writes it as "experimentExposure" info log.
+	//
+	// The exposure is not logged if the subject is not enrolled in the experiment
+	// or the logs persistence is disabled.
+	TrackExperimentExposure(experiment *Experiment, requestInfo *RequestInfo) (string, error)
+
+	// ---------------------------------------------------------------
+
+	// DigestQuery returns a new Digest select query.
+	DigestQuery() *dbx.SelectQuery
+
+	// FindDigestByName returns a single Digest model by its name.
+	FindDigestByName(name string) (*Digest, error)
+
+	// FindDigestRecords returns the current period digest records of the specified recipient
+	// (see [Digest] for the supported filter fields and placeholders).
+	FindDigestRecords(digest *Digest, recipient *Record) ([]*Record, error)
+
+	// ---------------------------------------------------------------
+
+	// RedirectQuery returns a new Redirect select query.
+	RedirectQuery() *dbx.SelectQuery
+
+	// FindRedirectById returns a single Redirect model by its id.
+	FindRedirectById(id string) (*Redirect, error)
+
+	// FindCachedRedirectByPath returns a single Redirect model by its path
+	// (trailing slashes are ignored).
+	//
+	// The redirects are cached and the cache is reset on every redirect change.
+	//
+	// NB! The returned redirect is shared and must not be modified
+	// (its Hits and LastHit values could be also outdated).
+	FindCachedRedirectByPath(path string) (*Redirect, error)
+
+	// TrackRedirectHit increments the in-memory hits counter of the provided
+	// redirect that will be persisted with the next [App.FlushRedirectHits] call.
+	TrackRedirectHit(redirect *Redirect)
+
+	// FlushRedirectHits persists the in-memory tracked redirect hits
+	// by incrementing the redirects hits counter and updating their last hit date.
+	//
+	// It is called automatically every minute and before the app termination.
+	FlushRedirectHits() error
+
+	// ---------------------------------------------------------------
+
+	// ReactionQuery returns a new Reaction select query.
+	ReactionQuery() *dbx.SelectQuery
+
+	// FindRecordReactionCounts returns the reactions count of each
+	// reaction type of the provided record.
+	//
+	// All collection reactions config types are included (even if their count is 0).
+	FindRecordReactionCounts(record *Record) (map[string]int, error)
+
+	// FindAuthRecordReactions returns the reaction types of authRecord to the provided record.
+	FindAuthRecordReactions(record *Record, authRecord *Record) ([]string, error)
+
+	// SetRecordReaction adds (active=true) or removes (active=false)
+	// the reactionType reaction of authRecord to the provided record.
+	//
+	// The operation is idempotent, aka. adding an existing or removing
+	// a missing reaction is a no-op.
+	//
+	// Returns [ErrInvalidReactionType] if reactionType is not one of
+	// the record collection reactions config types.
+	SetRecordReaction(record *Record, authRecord *Record, reactionType string, active bool) error
+
+	// ToggleRecordReaction adds the reactionType reaction of authRecord
+	// to the provided record or removes it if it already exists.
+	//
+	// Returns the new reaction state (true if the reaction was added).
+	//
+	// Returns [ErrInvalidReactionType] if reactionType is not one of
+	// the record collection reactions config types.
+	ToggleRecordReaction(record *Record, authRecord *Record, reactionType string) (bool, error)
+
+	// ---------------------------------------------------------------
+
+	// ReindexCollectionSearch (re)builds the search index of the
+	// provided collection from its existing records.
+	//
+	// The collection search index is deleted if the collection
+	// doesn't have search config.
+	//
+	// Note that the index is automatically rebuilt on search config change
+	// and it is necessary to call this method only after modifying the
+	// collection records with raw SQL queries.
+	ReindexCollectionSearch(collection *Collection) error
+
+	// ---------------------------------------------------------------
+
+	// UsageQuery returns a new UsageRecord select query.
+	UsageQuery() *dbx.SelectQuery
+
+	// TrackUsage adds value to the in-memory usage counter of the tenant unit
+	// that will be persisted with the next [App.FlushUsage] rollup.
+	//
+	// It does nothing if the metering is disabled, the tenant is empty or value is 0.
+	TrackUsage(tenant string, unit string, value int64) error
+
+	// FlushUsage persists the in-memory tracked usage counters
+	// as increments of their current day rollups.
+	//
+	// By default it is executed every minute and on app terminate.
+	FlushUsage() error
+
+	// DeleteOldUsage deletes all usage rollups with period before the provided date.
+	DeleteOldUsage(periodBefore time.Time) error
+
+	// ---------------------------------------------------------------
+
+	// MailQueueQuery returns a new QueuedMail select query.
+	MailQueueQuery() *dbx.SelectQuery
+
+	// ---------------------------------------------------------------
+
+	// TrackThumbAccess updates the last access date of the generated thumb
+	// located at thumbPath (the storage file key).
+	//
+	// It does nothing if the idle thumbs expiration is disabled
+	// or delegated to an S3 bucket lifecycle rule.
+	TrackThumbAccess(thumbPath string) error
+
+	// DeleteExpiredThumbs deletes all generated thumbs of the collections
+	// files that were last accessed (or created if never accessed) before idleBefore.
+	//
+	// Returns the number of the deleted thumbs.
+	DeleteExpiredThumbs(idleBefore time.Time) (int, error)
+
+	// ---------------------------------------------------------------
+
+	// QuarantinedFileQuery returns a new QuarantinedFile select query.
+	QuarantinedFileQuery() *dbx.SelectQuery
+
+	// FindQuarantinedFileById finds a single QuarantinedFile entry by its id.
+	FindQuarantinedFileById(id string) (*QuarantinedFile, error)
+
+	// IsFileQuarantined checks whether the specified record file is quarantined.
+	//
+	// For security reasons it also returns true if the check fails.
+	IsFileQuarantined(record *Record, filename string) bool
+
+	// ApproveQuarantinedFile releases the quarantined file making it servable.
+	ApproveQuarantinedFile(file *QuarantinedFile) error
+
+	// RejectQuarantinedFile removes the quarantined file from its record
+	// (which also deletes it from the storage) and releases the quarantine entry.
+	RejectQuarantinedFile(file *QuarantinedFile) error
+
+	// FindQueuedMailById returns a single QueuedMail model by its id.
+	FindQueuedMailById(id string) (*QueuedMail, error)
+
+	// QueueMail adds the provided message in the mail queue
+	// to be sent later by the mail queue cron job.
+	QueueMail(message *mailer.Message) (*QueuedMail, error)
+
+	// ProcessMailQueue sends up to limit pending queued emails (oldest first)
+	// and returns the number of the processed ones.
+	//
+	// Failed sends are retried on the next run until
+	// the [MailQueueConfig.MaxAttempts] is reached.
+	//
+	// If limit is <= 0, all pending emails are processed.
+	ProcessMailQueue(limit int) (int, error)
+
+	// DeleteOldQueuedMails deletes all sent and failed queued emails that are created before createdBefore.
+	//
+	// The pending emails are never deleted.
+	DeleteOldQueuedMails(createdBefore time.Time) error
+
+	// OutboxQuery returns a new OutboxMessage select query.
+	OutboxQuery() *dbx.SelectQuery
+
+	// FindOutboxMessageById returns a single OutboxMessage model by its id.
+	FindOutboxMessageById(id string) (*OutboxMessage, error)
+
+	// EnqueueOutboxMessage persists a new outbox message with the
+	// provided topic and payload to be delivered after commit.
+	//
+	// When called with a transactional app (e.g. e.App in a record create hook
+	// within a transaction), the message is stored as part of the transaction
+	// and it is delivered only if the transaction commits.
+	// Outside of a transaction the message is delivered right away.
+	//
+	// The delivery is performed in the background via the
+	// [App.OnOutboxMessageDeliver] hook handlers and failed
+	// deliveries are retried with exponential backoff.
+	EnqueueOutboxMessage(topic string, payload any) (*OutboxMessage, error)
+
+	// DispatchOutbox delivers up to limit due pending outbox messages
+	// (oldest first) and returns the number of the processed ones.
+	//
+	// If limit is <= 0, all due messages are processed.
+	DispatchOutbox(limit int) (int, error)
+
+	// DeleteOldOutboxMessages deletes all delivered and failed outbox messages that are created before createdBefore.
+	//
+	// The pending messages are never deleted.
+	DeleteOldOutboxMessages(createdBefore time.Time) error
+
+	// ---------------------------------------------------------------
+
+	// ResolveGeoIP returns the country and ASN of the provided IP address
+	// based on the CSV databases in the pb_data/geoip directory.
+	//
+	// The databases are loaded on the first call.
+	// To refresh them after a files change you can call [App.ReloadGeoIP].
+	//
+	// Returns nil if the GeoIP resolving is disabled or no data was found for the IP.
+	ResolveGeoIP(ip string) *geoip.Info
+
+	// ReloadGeoIP (re)loads the GeoIP CSV databases from the pb_data/geoip directory.
+	ReloadGeoIP() error
+
+	// ---------------------------------------------------------------
+
 	// CollectionQuery returns a new Collection select query.
 	CollectionQuery() *dbx.SelectQuery
 
@@ -468,6 +1037,25 @@ type App interface {
 	// but accept marshaled json array as import data (usually used for the autogenerated snapshots).
 	ImportCollectionsByMarshaledJSON(rawSliceOfMaps []byte, deleteMissing bool) error
 
+	// ImportCollectionsArchive imports the provided collections data (usually loaded with [ReadCollectionsArchive])
+	// and the related archive records and files in a single transaction.
+	//
+	// Only the records and files of the collections in toImport are imported.
+	// The records are inserted as raw db rows (existing records with the same id are replaced)
+	// and no record model hooks or validations are triggered.
+	//
+	// NB! If deleteMissing is true, ALL NON-SYSTEM COLLECTIONS AND SCHEMA FIELDS,
+	// that are not present in the imported configuration, WILL BE DELETED
+	// (this includes their related records data).
+	ImportCollectionsArchive(toImport []map[string]any, archive *zip.Reader, deleteMissing bool) error
+
+	// ExportCollectionsArchive streams into w a zip archive with the
+	// configuration of the provided collections.
+	//
+	// If includeData is true, the archive will contain also the raw records
+	// and files of the non-view collections (thumbs are not exported).
+	ExportCollectionsArchive(w io.Writer, collections []*Collection, includeData bool) error
+
 	// SyncRecordTableSchema compares the two provided collections
 	// and applies the necessary related record table changes.
 	//
@@ -490,6 +1078,12 @@ type App interface {
 	// ExternalAuth model that satisfies the non-nil expression.
 	FindFirstExternalAuthByExpr(expr dbx.Expression) (*ExternalAuth, error)
 
+	// ProviderClient returns an http client that sends requests to the provider
+	// APIs on behalf of the specified auth record using its stored OAuth2 tokens.
+	//
+	// The stored access token is transparently refreshed and persisted when expired.
+	ProviderClient(authRecord *Record, providerName string) (*http.Client, error)
+
 	// ---------------------------------------------------------------
 
 	// FindAllMFAsByRecord returns all MFA models linked to the provided auth record.
@@ -652,11 +1246,22 @@ type App interface {
 	// Returns an error if it is not an auth collection or the record is not found.
 	FindAuthRecordByEmail(collectionModelOrIdentifier any, email string) (*Record, error)
 
+	// FindAuthRecordByPhone finds the auth record associated with the provided phone number.
+	//
+	// The phone is normalized to E.164 before the lookup and only the
+	// [PhoneField] fields with a single column UNIQUE index are checked.
+	//
+	// Returns an error if it is not an auth collection or the record is not found.
+	FindAuthRecordByPhone(collectionModelOrIdentifier any, phone string) (*Record, error)
+
 	// CanAccessRecord checks if a record is allowed to be accessed by the
 	// specified requestInfo and accessRule.
 	//
 	// Rule and db checks are ignored in case requestInfo.Auth is a superuser.
 	//
+	// If optPoliciesAction is specified (eg. [CollectionPolicyActionView]),
+	// the record collection policies of the action are also applied (see [CollectionPolicy]).
+	//
 	// The returned error indicate that something unexpected happened during
 	// the check (eg. invalid rule or db query error).
 	//
@@ -667,10 +1272,23 @@ type App interface {
 	//	requestInfo, _ := e.RequestInfo()
 	//	record, _ := app.FindRecordById("example", "RECORD_ID")
 	//	rule := types.Pointer("@request.auth.id != '' || status = 'public'")
-	//	// ... or use one of the record collection's rule, eg. record.Collection().ViewRule
 	//
 	//	if ok, _ := app.CanAccessRecord(record, requestInfo, rule); ok { ... }
-	CanAccessRecord(record *Record, requestInfo *RequestInfo, accessRule *string) (bool, error)
+	//
+	//	// or use one of the record collection's rule together with its policies action
+	//	if ok, _ := app.CanAccessRecord(record, requestInfo, record.Collection().ViewRule, core.CollectionPolicyActionView); ok { ... }
+	CanAccessRecord(record *Record, requestInfo *RequestInfo, accessRule *string, optPoliciesAction ...string) (bool, error)
+
+	// CanAccessRealtimeTopic checks if the custom realtime subscription topic
+	// is allowed to be subscribed by the specified requestInfo based on the
+	// first matching app.Settings().Realtime.Topics pattern rule.
+	//
+	// Topics that don't match any of the configured patterns are always allowed.
+	//
+	// Rule and db checks are ignored in case requestInfo.Auth is a superuser.
+	//
+	// The method always return false on invalid rule or db query error.
+	CanAccessRealtimeTopic(topic string, requestInfo *RequestInfo) (bool, error)
 
 	// ExpandRecord expands the relations of a single Record model.
 	//
@@ -1059,6 +1677,26 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnRecordAfterUpdateError(tags ...string) *hook.TaggedHook[*RecordErrorEvent]
 
+	// OnRecordTransition is triggered on every [StateField] record value change
+	// (right before the record db update and after the transition was validated).
+	//
+	// It could be used for example to perform transition specific side effects
+	// or to abort the record update by returning an error. For example:
+	//
+	//  app.OnRecordTransition("orders.ship").BindFunc(func(e *core.RecordTransitionEvent) error {
+	//      if e.Record.GetString("trackingNumber") == "" {
+	//          return errors.New("missing tracking number")
+	//      }
+	//
+	//      return e.Next()
+	//  })
+	//
+	// If the optional "tags" list (Collection ids or names, or named
+	// transitions in the format "collectionName.transitionName") is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordTransition(tags ...string) *hook.TaggedHook[*RecordTransitionEvent]
+
 	// ---------------------------------------------------------------
 
 	// OnRecordDelete is a Record proxy model hook of [OnModelDelete].
@@ -1204,6 +1842,10 @@ type App interface {
 	// sending a new device login auth alert email, allowing you to
 	// intercept and customize the email message that is being sent.
 	//
+	// The login device details are available in e.Meta under the
+	// "ip", "userAgent" and "country" keys.
+	// To suppress the email, simply don't call e.Next().
+	//
 	// If the optional "tags" list (Collection ids or names) is specified,
 	// then all event handlers registered via the created hook will be
 	// triggered and called only if their event data origin matches the tags.
@@ -1231,6 +1873,10 @@ type App interface {
 	// confirmation new address email to an auth record, allowing
 	// you to intercept and customize the email message that is being sent.
 	//
+	// The hook is also triggered for the old address approval and
+	// changed email notification messages (the e.Meta["stage"] is
+	// set to "approve" and "notify" respectively).
+	//
 	// If the optional "tags" list (Collection ids or names) is specified,
 	// then all event handlers registered via the created hook will be
 	// triggered and called only if their event data origin matches the tags.
@@ -1245,6 +1891,78 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnMailerRecordOTPSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent]
 
+	// OnMailerRecordBulkSend hook is triggered for each recipient auth record
+	// of a bulk email before adding its message in the mail queue, allowing
+	// you to customize the message per recipient or to skip it (by not calling e.Next()).
+	//
+	// The e.Meta contains the resolved template placeholders of the recipient.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnMailerRecordBulkSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent]
+
+	// ---------------------------------------------------------------
+	// SMS event hooks
+	// ---------------------------------------------------------------
+
+	// OnSMSSend hook is triggered every time when a new SMS is
+	// being send using the [App.NewSMSClient()] instance.
+	//
+	// It allows intercepting the SMS message or to use a custom SMS client.
+	OnSMSSend() *hook.Hook[*SMSEvent]
+
+	// ---------------------------------------------------------------
+	// Usage event hooks
+	// ---------------------------------------------------------------
+
+	// OnUsageTrack hook is triggered on every [App.TrackUsage] call
+	// (e.g. for each metered API request unit).
+	//
+	// Could be used to remap the default auth record tenant (e.g. to an
+	// organization id) or to skip the tracking by not calling e.Next().
+	OnUsageTrack() *hook.Hook[*UsageTrackEvent]
+
+	// OnUsageRollup hook is triggered on every [App.FlushUsage] call
+	// with the tracked usage increments since the last flush.
+	//
+	// Could be used to forward the usage to an external billing provider.
+	OnUsageRollup() *hook.Hook[*UsageRollupEvent]
+
+	// ---------------------------------------------------------------
+	// Outbox event hooks
+	// ---------------------------------------------------------------
+
+	// OnOutboxMessageDeliver hook is triggered on every outbox message
+	// delivery attempt (see [App.EnqueueOutboxMessage]).
+	//
+	// The handler that delivered the message must set e.Handled to true.
+	// Messages that are not handled by any handler (e.g. because the handler
+	// for their topic is not registered yet) remain pending and are retried later.
+	//
+	// Returning an error marks the attempt as failed and the message
+	// is retried later. Because a message could be delivered more than once
+	// (e.g. on crash before the delivery result is stored), the handlers
+	// should be idempotent. For example:
+	//
+	//  app.OnOutboxMessageDeliver("orders.sync").BindFunc(func(e *core.OutboxMessageEvent) error {
+	//      var order map[string]any
+	//      if err := e.Message.UnmarshalPayload(&order); err != nil {
+	//          return err
+	//      }
+	//
+	//      // call the external service with e.Message.Id as idempotency key...
+	//
+	//      e.Handled = true
+	//
+	//      return e.Next()
+	//  })
+	//
+	// If the optional "tags" list (message topics) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnOutboxMessageDeliver(tags ...string) *hook.TaggedHook[*OutboxMessageEvent]
+
 	// ---------------------------------------------------------------
 	// Realtime API event hooks
 	// ---------------------------------------------------------------
@@ -1287,6 +2005,18 @@ type App interface {
 	// File API event hooks
 	// ---------------------------------------------------------------
 
+	// OnFileUpload hook is triggered for each new record file before storing it
+	// (e.g. on record create or update).
+	//
+	// Could be used to reject the file by returning an error or to store it
+	// as quarantined (aka. not servable) until approved by setting e.Quarantine,
+	// for example to run asynchronous virus or moderation checks.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnFileUpload(tags ...string) *hook.TaggedHook[*FileUploadEvent]
+
 	// OnFileDownloadRequest hook is triggered before each API File download request.
 	//
 	// Could be used to validate or modify the file response before
@@ -1315,6 +2045,40 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnRecordAuthRequest(tags ...string) *hook.TaggedHook[*RecordAuthRequestEvent]
 
+	// OnRecordAuthTokenClaims hook is triggered before issuing a new
+	// record auth token via the API (sign-in, token refresh, impersonate).
+	//
+	// Could be used to append custom claims to the token payload, either
+	// from the record fields or computed values, so that downstream services
+	// could authorize the request without extra lookups, e.g.:
+	//
+	//	app.OnRecordAuthTokenClaims("users").BindFunc(func(e *core.RecordAuthTokenClaimsEvent) error {
+	//		e.Claims["plan"] = e.Record.GetString("plan")
+	//		return e.Next()
+	//	})
+	//
+	// The reserved system claims cannot be overwritten and the serialized
+	// custom claims must not exceed [MaxTokenCustomClaimsSize] bytes.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAuthTokenClaims(tags ...string) *hook.TaggedHook[*RecordAuthTokenClaimsEvent]
+
+	// OnRecordAuthFailure hook is triggered on each failed API
+	// record password, OTP or OAuth2 authentication attempt.
+	//
+	// Could be used to build alerting, fail2ban-style or other audit tooling.
+	// By default the failed attempt is also written as "authFailure" warning log.
+	//
+	// Note that the hook cannot change the failure response
+	// (returned hook errors are only logged).
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAuthFailure(tags ...string) *hook.TaggedHook[*RecordAuthFailureEvent]
+
 	// OnRecordAuthWithPasswordRequest hook is triggered on each
 	// Record auth with password API request.
 	//
@@ -1340,6 +2104,21 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnRecordAuthWithOAuth2Request(tags ...string) *hook.TaggedHook[*RecordAuthWithOAuth2RequestEvent]
 
+	// OnRecordAuthWithLDAPRequest hook is triggered on each Record
+	// LDAP sign-in/sign-up API request (after the successful directory
+	// bind and before the auth record linking).
+	//
+	// If [RecordAuthWithLDAPRequestEvent.Record] is not set, then the LDAP
+	// request will try to create a new auth Record.
+	//
+	// To assign or link a different existing record model you can
+	// change the [RecordAuthWithLDAPRequestEvent.Record] field.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordAuthWithLDAPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithLDAPRequestEvent]
+
 	// OnRecordAuthRefreshRequest hook is triggered on each Record
 	// auth refresh API request (right before generating a new auth token).
 	//
@@ -1488,6 +2267,51 @@ type App interface {
 	// triggered and called only if their event data origin matches the tags.
 	OnRecordDeleteRequest(tags ...string) *hook.TaggedHook[*RecordRequestEvent]
 
+	// OnRecordSyncConflictRequest hook is triggered on each conflicting
+	// mutation of an API sync push request with the "merge" conflict policy.
+	//
+	// Could be used to merge the client and server changes by modifying
+	// the event Data (by default the client data is applied as it is)
+	// or to reject the mutation by returning an error.
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordSyncConflictRequest(tags ...string) *hook.TaggedHook[*RecordSyncConflictRequestEvent]
+
+	// OnRecordRequestTransform hook is triggered on each API Record create
+	// and update request (including the batch and duplicate requests)
+	// right after the submitted data is read and before it is loaded in the record.
+	//
+	// Could be used to centrally transform the submitted record data by
+	// modifying e.Data (rename fields, inject server-side values, strip fields by role, etc.).
+	// The transformed data is also available as @request.body in the API rules.
+	//
+	// For transforming the serialized record responses use the [OnRecordEnrich] hook
+	// (e.g. Hide/Unhide fields or assign computed values with WithCustomData).
+	//
+	// Example:
+	//
+	//	app.OnRecordRequestTransform("posts").BindFunc(func(e *core.RecordRequestTransformEvent) error {
+	//		// accept the "publishedAt" alias of the "published" field
+	//		if v, ok := e.Data["publishedAt"]; ok {
+	//			e.Data["published"] = v
+	//			delete(e.Data, "publishedAt")
+	//		}
+	//
+	//		// always assign the authenticated user as author
+	//		if e.Record.IsNew() && e.Auth != nil {
+	//			e.Data["author"] = e.Auth.Id
+	//		}
+	//
+	//		return e.Next()
+	//	})
+	//
+	// If the optional "tags" list (Collection ids or names) is specified,
+	// then all event handlers registered via the created hook will be
+	// triggered and called only if their event data origin matches the tags.
+	OnRecordRequestTransform(tags ...string) *hook.TaggedHook[*RecordRequestTransformEvent]
+
 	// ---------------------------------------------------------------
 	// Collection API event hooks
 	// ---------------------------------------------------------------
@@ -0,0 +1,261 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const AppConfigTableName = "_appConfig"
+
+const (
+	AppConfigTypeString = "string"
+	AppConfigTypeNumber = "number"
+	AppConfigTypeBool   = "bool"
+	AppConfigTypeJSON   = "json"
+)
+
+const storeKeyAppConfigCache = "@appConfigCache"
+
+var (
+	_ Model         = (*AppConfigEntry)(nil)
+	_ PostValidator = (*AppConfigEntry)(nil)
+)
+
+var appConfigKeyRegex = regexp.MustCompile(`^[\w\-\.]+$`)
+
+// AppConfigEntry defines a single typed dynamic app config entry
+// (eg. feature flag or runtime option) that could be read by the clients
+// without the need of a dedicated singleton collection.
+//
+// ReadRule follows the same semantic as the collection API rules:
+// nil means that only superusers could read the entry, empty string - everyone,
+// and non-empty string is a filter expression that could use only
+// the @request.* fields (eg. `@request.auth.id != ""`).
+type AppConfigEntry struct {
+	BaseModel
+
+	Key         string         `db:"key" json:"key"`
+	Type        string         `db:"type" json:"type"`
+	Value       types.JSONRaw  `db:"value" json:"value"`
+	ReadRule    *string        `db:"readRule" json:"readRule"`
+	Description string         `db:"description" json:"description"`
+	Created     types.DateTime `db:"created" json:"created"`
+	Updated     types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the AppConfigEntry model table name.
+func (m *AppConfigEntry) TableName() string {
+	return AppConfigTableName
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *AppConfigEntry) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(
+			&m.Key,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(appConfigKeyRegex),
+			validation.By(m.checkUniqueKey(app)),
+		),
+		validation.Field(
+			&m.Type,
+			validation.Required,
+			validation.In(AppConfigTypeString, AppConfigTypeNumber, AppConfigTypeBool, AppConfigTypeJSON),
+		),
+		validation.Field(&m.Value, validation.By(m.checkValue)),
+		validation.Field(&m.ReadRule, validation.Length(0, 1000), validation.By(m.checkReadRule(app))),
+		validation.Field(&m.Description, validation.Length(0, 1000)),
+	)
+}
+
+func (m *AppConfigEntry) checkUniqueKey(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		var exists int
+
+		err := app.AppConfigQuery().
+			Select("(1)").
+			AndWhere(dbx.HashExp{"key": v}).
+			AndWhere(dbx.Not(dbx.HashExp{"id": m.Id})).
+			Limit(1).
+			Row(&exists)
+
+		if exists > 0 || (err != nil && !errors.Is(err, sql.ErrNoRows)) {
+			return validation.NewError("validation_app_config_key_exists", "Entry with the provided key already exists.")
+		}
+
+		return nil
+	}
+}
+
+func (m *AppConfigEntry) checkValue(value any) error {
+	raw, _ := value.(types.JSONRaw)
+	if len(raw) == 0 {
+		return validation.ErrRequired
+	}
+
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return validation.NewError("validation_invalid_json", "Must be a valid json value.")
+	}
+
+	var valid bool
+	switch m.Type {
+	case AppConfigTypeString:
+		_, valid = v.(string)
+	case AppConfigTypeNumber:
+		_, valid = v.(float64)
+	case AppConfigTypeBool:
+		_, valid = v.(bool)
+	default:
+		valid = true
+	}
+
+	if !valid {
+		return validation.NewError("validation_app_config_invalid_value", "The value doesn't match the entry type.").
+			SetParams(map[string]any{"type": m.Type})
+	}
+
+	return nil
+}
+
+func (m *AppConfigEntry) checkReadRule(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(*string)
+		if v == nil || *v == "" {
+			return nil // nothing to check
+		}
+
+		if err := validateRequestRule(app, *v); err != nil {
+			return validation.NewError("validation_invalid_rule", "Invalid rule. Raw error: "+err.Error())
+		}
+
+		return nil
+	}
+}
+
+// -------------------------------------------------------------------
+
+// AppConfigQuery returns a new AppConfigEntry select query.
+func (app *BaseApp) AppConfigQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&AppConfigEntry{})
+}
+
+// FindAppConfigEntryByKey returns a single AppConfigEntry model by its key.
+func (app *BaseApp) FindAppConfigEntryByKey(key string) (*AppConfigEntry, error) {
+	result := &AppConfigEntry{}
+
+	err := app.AppConfigQuery().
+		AndWhere(dbx.HashExp{"key": key}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindCachedAppConfigEntries returns all AppConfigEntry models (ordered by key).
+//
+// The entries are cached and the cache is reset on every app config entry change.
+//
+// NB! The returned entries are shared and must not be modified.
+func (app *BaseApp) FindCachedAppConfigEntries() ([]*AppConfigEntry, error) {
+	if entries, ok := app.Store().Get(storeKeyAppConfigCache).([]*AppConfigEntry); ok {
+		return entries, nil
+	}
+
+	entries := []*AppConfigEntry{}
+
+	err := app.AppConfigQuery().OrderBy("key ASC").All(&entries)
+	if err != nil {
+		return nil, err
+	}
+
+	app.Store().Set(storeKeyAppConfigCache, entries)
+
+	return entries, nil
+}
+
+// CanAccessAppConfigEntry checks if the app config entry
+// could be read by the specified requestInfo.
+//
+// Rule and db checks are ignored in case requestInfo.Auth is a superuser.
+//
+// The method always return false on invalid rule or db query error.
+func (app *BaseApp) CanAccessAppConfigEntry(entry *AppConfigEntry, requestInfo *RequestInfo) (bool, error) {
+	// superusers can access everything
+	if requestInfo.HasSuperuserAuth() {
+		return true, nil
+	}
+
+	// only superusers can access this entry
+	if entry.ReadRule == nil {
+		return false, nil
+	}
+
+	// empty public rule, aka. everyone can access
+	if *entry.ReadRule == "" {
+		return true, nil
+	}
+
+	return checkRequestRule(app, *entry.ReadRule, requestInfo)
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerAppConfigHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*AppConfigEntry); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*AppConfigEntry); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// reset the entries cache on every app config change
+	resetCache := func(e *ModelEvent) error {
+		if _, ok := e.Model.(*AppConfigEntry); ok {
+			app.Store().Remove(storeKeyAppConfigCache)
+		}
+
+		return e.Next()
+	}
+	app.OnModelAfterCreateSuccess().BindFunc(resetCache)
+	app.OnModelAfterUpdateSuccess().BindFunc(resetCache)
+	app.OnModelAfterDeleteSuccess().BindFunc(resetCache)
+}
@@ -0,0 +1,202 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestAppConfigEntryTableName(t *testing.T) {
+	t.Parallel()
+
+	if v := (&core.AppConfigEntry{}).TableName(); v != "_appConfig" {
+		t.Fatalf("Expected %q, got %q", "_appConfig", v)
+	}
+}
+
+func TestAppConfigEntryValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	existing := &core.AppConfigEntry{Key: "test", Type: core.AppConfigTypeBool, Value: types.JSONRaw("true")}
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	newEntry := func(key string, typ string, value string, readRule *string) *core.AppConfigEntry {
+		entry := &core.AppConfigEntry{Key: key, Type: typ, Value: types.JSONRaw(value), ReadRule: readRule}
+		entry.Id = "test"
+		return entry
+	}
+
+	scenarios := []struct {
+		name         string
+		entry        *core.AppConfigEntry
+		expectedErrs []string
+	}{
+		{
+			"empty",
+			&core.AppConfigEntry{},
+			[]string{"id", "key", "type", "value"},
+		},
+		{
+			"invalid key, type and rule",
+			newEntry("a b", "invalid", "1", types.Pointer("missing = 1")),
+			[]string{"key", "type", "readRule"},
+		},
+		{
+			"duplicated key",
+			newEntry("test", core.AppConfigTypeBool, "false", nil),
+			[]string{"key"},
+		},
+		{
+			"invalid json value",
+			newEntry("new", core.AppConfigTypeJSON, "{", nil),
+			[]string{"value"},
+		},
+		{
+			"string type with non-string value",
+			newEntry("new", core.AppConfigTypeString, "1", nil),
+			[]string{"value"},
+		},
+		{
+			"number type with non-number value",
+			newEntry("new", core.AppConfigTypeNumber, `"1"`, nil),
+			[]string{"value"},
+		},
+		{
+			"bool type with non-bool value",
+			newEntry("new", core.AppConfigTypeBool, "null", nil),
+			[]string{"value"},
+		},
+		{
+			"updating the existing entry",
+			existing,
+			[]string{},
+		},
+		{
+			"valid string",
+			newEntry("new", core.AppConfigTypeString, `"abc"`, types.Pointer("")),
+			[]string{},
+		},
+		{
+			"valid number",
+			newEntry("new", core.AppConfigTypeNumber, "1.5", types.Pointer("@request.auth.id != ''")),
+			[]string{},
+		},
+		{
+			"valid json",
+			newEntry("new.nested-key_1", core.AppConfigTypeJSON, `{"a":[1,2]}`, types.Pointer("@request.headers.x_test = 'a'")),
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, app.Validate(s.entry), s.expectedErrs)
+		})
+	}
+}
+
+func TestFindCachedAppConfigEntries(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	entries, err := app.FindCachedAppConfigEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("Expected no entries, got %d", len(entries))
+	}
+
+	b := &core.AppConfigEntry{Key: "b", Type: core.AppConfigTypeBool, Value: types.JSONRaw("true")}
+	a := &core.AppConfigEntry{Key: "a", Type: core.AppConfigTypeNumber, Value: types.JSONRaw("1")}
+	for _, entry := range []*core.AppConfigEntry{b, a} {
+		if err := app.Save(entry); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	entries, err = app.FindCachedAppConfigEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 2 || entries[0].Key != "a" || entries[1].Key != "b" {
+		t.Fatalf("Expected entries [a, b], got %v", entries)
+	}
+
+	a.Value = types.JSONRaw("2")
+	if err := app.Save(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(b); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = app.FindCachedAppConfigEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Key != "a" || entries[0].Value.String() != "2" {
+		t.Fatalf("Expected the cache to be reset and contain only the updated entry a, got %v", entries)
+	}
+
+	found, err := app.FindAppConfigEntryByKey("a")
+	if err != nil || found.Id != a.Id {
+		t.Fatalf("Expected to find entry a, got %v (%v)", found, err)
+	}
+}
+
+func TestCanAccessAppConfigEntry(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		rule        *string
+		requestInfo *core.RequestInfo
+		expected    bool
+	}{
+		{"nil rule (guest)", nil, &core.RequestInfo{}, false},
+		{"nil rule (user)", nil, &core.RequestInfo{Auth: user}, false},
+		{"nil rule (superuser)", nil, &core.RequestInfo{Auth: superuser}, true},
+		{"empty rule (guest)", types.Pointer(""), &core.RequestInfo{}, true},
+		{"auth rule (guest)", types.Pointer("@request.auth.id != ''"), &core.RequestInfo{}, false},
+		{"auth rule (user)", types.Pointer("@request.auth.id != ''"), &core.RequestInfo{Auth: user}, true},
+		{"auth field rule (user)", types.Pointer("@request.auth.name = 'test1'"), &core.RequestInfo{Auth: user}, true},
+		{"query rule (mismatch)", types.Pointer("@request.query.a = '1'"), &core.RequestInfo{Query: map[string]string{"a": "2"}}, false},
+		{"query rule (match)", types.Pointer("@request.query.a = '1'"), &core.RequestInfo{Query: map[string]string{"a": "1"}}, true},
+		{"not allowed field rule", types.Pointer("@request.body.a = '1'"), &core.RequestInfo{Body: map[string]any{"a": "1"}}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			entry := &core.AppConfigEntry{Key: "test", Type: core.AppConfigTypeBool, Value: types.JSONRaw("true"), ReadRule: s.rule}
+
+			result, _ := app.CanAccessAppConfigEntry(entry, s.requestInfo)
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"log"
 	"log/slog"
+	"maps"
 	"os"
 	"path/filepath"
 	"regexp"
@@ -19,9 +20,11 @@ import (
 	"github.com/pocketbase/pocketbase/tools/cron"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/i18n"
 	"github.com/pocketbase/pocketbase/tools/logger"
 	"github.com/pocketbase/pocketbase/tools/mailer"
 	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/sms"
 	"github.com/pocketbase/pocketbase/tools/store"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
 	"github.com/pocketbase/pocketbase/tools/types"
@@ -62,6 +65,11 @@ type BaseAppConfig struct {
 	AuxMaxOpenConns  int
 	AuxMaxIdleConns  int
 	IsDev            bool
+
+	// SettingsOverlayFile is an optional json file with environment
+	// specific settings that are applied on top of the stored ones
+	// (see also [SettingsOverlayEnvPrefix]).
+	SettingsOverlayFile string
 }
 
 // ensures that the BaseApp implements the App interface.
@@ -72,8 +80,10 @@ type BaseApp struct {
 	config              *BaseAppConfig
 	txInfo              *TxAppInfo
 	store               *store.Store[string, any]
+	i18n                *i18n.Catalog
 	cron                *cron.Cron
 	settings            *Settings
+	settingsOverlay     map[string]any
 	subscriptionsBroker *subscriptions.Broker
 	logger              *slog.Logger
 	concurrentDB        dbx.Builder
@@ -114,6 +124,7 @@ type BaseApp struct {
 	onRecordUpdateExecute      *hook.Hook[*RecordEvent]
 	onRecordAfterUpdateSuccess *hook.Hook[*RecordEvent]
 	onRecordAfterUpdateError   *hook.Hook[*RecordErrorEvent]
+	onRecordTransition         *hook.Hook[*RecordTransitionEvent]
 	onRecordDelete             *hook.Hook[*RecordEvent]
 	onRecordDeleteExecute      *hook.Hook[*RecordEvent]
 	onRecordAfterDeleteSuccess *hook.Hook[*RecordEvent]
@@ -141,6 +152,17 @@ type BaseApp struct {
 	onMailerRecordEmailChangeSend   *hook.Hook[*MailerRecordEvent]
 	onMailerRecordOTPSend           *hook.Hook[*MailerRecordEvent]
 	onMailerRecordAuthAlertSend     *hook.Hook[*MailerRecordEvent]
+	onMailerRecordBulkSend          *hook.Hook[*MailerRecordEvent]
+
+	// sms event hooks
+	onSMSSend *hook.Hook[*SMSEvent]
+
+	// usage event hooks
+	onUsageTrack  *hook.Hook[*UsageTrackEvent]
+	onUsageRollup *hook.Hook[*UsageRollupEvent]
+
+	// outbox event hooks
+	onOutboxMessageDeliver *hook.Hook[*OutboxMessageEvent]
 
 	// realtime api event hooks
 	onRealtimeConnectRequest   *hook.Hook[*RealtimeConnectRequestEvent]
@@ -153,13 +175,17 @@ type BaseApp struct {
 	onSettingsReload        *hook.Hook[*SettingsReloadEvent]
 
 	// file api event hooks
+	onFileUpload          *hook.Hook[*FileUploadEvent]
 	onFileDownloadRequest *hook.Hook[*FileDownloadRequestEvent]
 	onFileTokenRequest    *hook.Hook[*FileTokenRequestEvent]
 
 	// record auth API event hooks
 	onRecordAuthRequest                 *hook.Hook[*RecordAuthRequestEvent]
+	onRecordAuthFailure                 *hook.Hook[*RecordAuthFailureEvent]
+	onRecordAuthTokenClaims             *hook.Hook[*RecordAuthTokenClaimsEvent]
 	onRecordAuthWithPasswordRequest     *hook.Hook[*RecordAuthWithPasswordRequestEvent]
 	onRecordAuthWithOAuth2Request       *hook.Hook[*RecordAuthWithOAuth2RequestEvent]
+	onRecordAuthWithLDAPRequest         *hook.Hook[*RecordAuthWithLDAPRequestEvent]
 	onRecordAuthRefreshRequest          *hook.Hook[*RecordAuthRefreshRequestEvent]
 	onRecordRequestPasswordResetRequest *hook.Hook[*RecordRequestPasswordResetRequestEvent]
 	onRecordConfirmPasswordResetRequest *hook.Hook[*RecordConfirmPasswordResetRequestEvent]
@@ -177,6 +203,9 @@ type BaseApp struct {
 	onRecordUpdateRequest *hook.Hook[*RecordRequestEvent]
 	onRecordDeleteRequest *hook.Hook[*RecordRequestEvent]
 
+	onRecordSyncConflictRequest *hook.Hook[*RecordSyncConflictRequestEvent]
+	onRecordRequestTransform    *hook.Hook[*RecordRequestTransformEvent]
+
 	// collection API event hooks
 	onCollectionsListRequest   *hook.Hook[*CollectionsListRequestEvent]
 	onCollectionViewRequest    *hook.Hook[*CollectionRequestEvent]
@@ -196,6 +225,7 @@ func NewBaseApp(config BaseAppConfig) *BaseApp {
 	app := &BaseApp{
 		settings:            newDefaultSettings(),
 		store:               store.New[string, any](nil),
+		i18n:                i18n.NewCatalog(),
 		cron:                cron.New(),
 		subscriptionsBroker: subscriptions.NewBroker(),
 		config:              &config,
@@ -262,6 +292,7 @@ func (app *BaseApp) initHooks() {
 	app.onRecordUpdateExecute = &hook.Hook[*RecordEvent]{}
 	app.onRecordAfterUpdateSuccess = &hook.Hook[*RecordEvent]{}
 	app.onRecordAfterUpdateError = &hook.Hook[*RecordErrorEvent]{}
+	app.onRecordTransition = &hook.Hook[*RecordTransitionEvent]{}
 	app.onRecordDelete = &hook.Hook[*RecordEvent]{}
 	app.onRecordDeleteExecute = &hook.Hook[*RecordEvent]{}
 	app.onRecordAfterDeleteSuccess = &hook.Hook[*RecordEvent]{}
@@ -289,6 +320,17 @@ func (app *BaseApp) initHooks() {
 	app.onMailerRecordEmailChangeSend = &hook.Hook[*MailerRecordEvent]{}
 	app.onMailerRecordOTPSend = &hook.Hook[*MailerRecordEvent]{}
 	app.onMailerRecordAuthAlertSend = &hook.Hook[*MailerRecordEvent]{}
+	app.onMailerRecordBulkSend = &hook.Hook[*MailerRecordEvent]{}
+
+	// sms event hooks
+	app.onSMSSend = &hook.Hook[*SMSEvent]{}
+
+	// usage event hooks
+	app.onUsageTrack = &hook.Hook[*UsageTrackEvent]{}
+	app.onUsageRollup = &hook.Hook[*UsageRollupEvent]{}
+
+	// outbox event hooks
+	app.onOutboxMessageDeliver = &hook.Hook[*OutboxMessageEvent]{}
 
 	// realtime API event hooks
 	app.onRealtimeConnectRequest = &hook.Hook[*RealtimeConnectRequestEvent]{}
@@ -301,13 +343,17 @@ func (app *BaseApp) initHooks() {
 	app.onSettingsReload = &hook.Hook[*SettingsReloadEvent]{}
 
 	// file API event hooks
+	app.onFileUpload = &hook.Hook[*FileUploadEvent]{}
 	app.onFileDownloadRequest = &hook.Hook[*FileDownloadRequestEvent]{}
 	app.onFileTokenRequest = &hook.Hook[*FileTokenRequestEvent]{}
 
 	// record auth API event hooks
 	app.onRecordAuthRequest = &hook.Hook[*RecordAuthRequestEvent]{}
+	app.onRecordAuthFailure = &hook.Hook[*RecordAuthFailureEvent]{}
+	app.onRecordAuthTokenClaims = &hook.Hook[*RecordAuthTokenClaimsEvent]{}
 	app.onRecordAuthWithPasswordRequest = &hook.Hook[*RecordAuthWithPasswordRequestEvent]{}
 	app.onRecordAuthWithOAuth2Request = &hook.Hook[*RecordAuthWithOAuth2RequestEvent]{}
+	app.onRecordAuthWithLDAPRequest = &hook.Hook[*RecordAuthWithLDAPRequestEvent]{}
 	app.onRecordAuthRefreshRequest = &hook.Hook[*RecordAuthRefreshRequestEvent]{}
 	app.onRecordRequestPasswordResetRequest = &hook.Hook[*RecordRequestPasswordResetRequestEvent]{}
 	app.onRecordConfirmPasswordResetRequest = &hook.Hook[*RecordConfirmPasswordResetRequestEvent]{}
@@ -324,6 +370,8 @@ func (app *BaseApp) initHooks() {
 	app.onRecordCreateRequest = &hook.Hook[*RecordRequestEvent]{}
 	app.onRecordUpdateRequest = &hook.Hook[*RecordRequestEvent]{}
 	app.onRecordDeleteRequest = &hook.Hook[*RecordRequestEvent]{}
+	app.onRecordSyncConflictRequest = &hook.Hook[*RecordSyncConflictRequestEvent]{}
+	app.onRecordRequestTransform = &hook.Hook[*RecordRequestTransformEvent]{}
 
 	// collection API event hooks
 	app.onCollectionsListRequest = &hook.Hook[*CollectionsListRequestEvent]{}
@@ -419,6 +467,12 @@ func (app *BaseApp) Bootstrap() error {
 			return err
 		}
 
+		overlay, err := LoadSettingsOverlay(app.config.SettingsOverlayFile, os.Environ())
+		if err != nil {
+			return err
+		}
+		app.settingsOverlay = overlay
+
 		if err := app.ReloadSettings(); err != nil {
 			return err
 		}
@@ -596,11 +650,24 @@ func (app *BaseApp) Settings() *Settings {
 	return app.settings
 }
 
+// SettingsOverlay returns a copy of the flattened environment specific
+// settings overlay (e.g. {"smtp.host": "example.com"}) that is applied
+// on top of the stored app settings.
+func (app *BaseApp) SettingsOverlay() map[string]any {
+	return maps.Clone(app.settingsOverlay)
+}
+
 // Store returns the app runtime store.
 func (app *BaseApp) Store() *store.Store[string, any] {
 	return app.store
 }
 
+// I18n returns the app translations catalog used for localizing
+// the API error messages (could be extended or overwritten with custom translations).
+func (app *BaseApp) I18n() *i18n.Catalog {
+	return app.i18n
+}
+
 // Cron returns the app cron instance.
 func (app *BaseApp) Cron() *cron.Cron {
 	return app.cron
@@ -702,6 +769,73 @@ func (app *BaseApp) NewMailClient() mailer.Mailer {
 	return client
 }
 
+// NewSMSClient creates and returns a new Twilio or webhook SMS client
+// based on the current app settings.
+//
+// If the SMS settings are not enabled, the returned client will
+// return an error on send (unless replaced by an OnSMSSend hook handler).
+func (app *BaseApp) NewSMSClient() sms.Sender {
+	var client sms.Sender
+
+	settings := app.Settings().SMS
+	if settings.Enabled {
+		switch settings.Provider {
+		case SMSProviderTwilio:
+			client = &sms.Twilio{
+				AccountSid: settings.AccountSid,
+				AuthToken:  settings.AuthToken,
+				From:       settings.From,
+			}
+		case SMSProviderWebhook:
+			webhook := &sms.Webhook{URL: settings.WebhookURL}
+			if settings.AuthToken != "" {
+				webhook.Headers = map[string]string{"Authorization": settings.AuthToken}
+			}
+			client = webhook
+		}
+	}
+
+	return &appSMSClient{app: app, client: client}
+}
+
+var _ sms.Sender = (*appSMSClient)(nil)
+
+// appSMSClient wraps an SMS client and triggers the OnSMSSend app hook on send.
+type appSMSClient struct {
+	app    *BaseApp
+	client sms.Sender
+}
+
+// Send implements [sms.Sender.Send] interface.
+func (c *appSMSClient) Send(message *sms.Message) error {
+	event := new(SMSEvent)
+	event.App = c.app
+	event.Client = c.client
+	event.Message = message
+
+	return c.app.OnSMSSend().Trigger(event, func(e *SMSEvent) error {
+		// print the message in the console to assist with the debugging
+		if e.App.IsDev() {
+			logDate := new(strings.Builder)
+			log.New(logDate, "", log.LstdFlags).Print()
+
+			smsLog := new(strings.Builder)
+			smsLog.WriteString(strings.TrimSpace(logDate.String()))
+			smsLog.WriteString(" SMS sent\n")
+			fmt.Fprintf(smsLog, "├─ To: %v\n", e.Message.To)
+			fmt.Fprintf(smsLog, "└─ Body: %v", e.Message.Body)
+
+			color.HiBlack("%s", smsLog.String())
+		}
+
+		if e.Client == nil {
+			return errors.New("SMS sending is not enabled")
+		}
+
+		return e.Client.Send(e.Message)
+	})
+}
+
 // NewFilesystem creates a new local or S3 filesystem instance
 // for managing regular app files (ex. record uploads)
 // based on the current app settings.
@@ -745,6 +879,12 @@ func (app *BaseApp) NewBackupsFilesystem() (*filesystem.System, error) {
 	return filesystem.NewLocal(filepath.Join(app.DataDir(), LocalBackupsDirName))
 }
 
+// startExecPath is the path of the executable that started the process.
+//
+// It is resolved on init because on some systems the current executable
+// path follows the file renames (e.g. when replaced by the update command).
+var startExecPath, startExecPathErr = os.Executable()
+
 // Restart restarts (aka. replaces) the current running application process.
 //
 // NB! It relies on execve which is supported only on UNIX based systems.
@@ -753,7 +893,7 @@ func (app *BaseApp) Restart() error {
 		return errors.New("restart is not supported on windows")
 	}
 
-	execPath, err := os.Executable()
+	execPath, err := startExecPath, startExecPathErr
 	if err != nil {
 		return err
 	}
@@ -916,6 +1056,10 @@ func (app *BaseApp) OnRecordAfterUpdateError(tags ...string) *hook.TaggedHook[*R
 	return hook.NewTaggedHook(app.onRecordAfterUpdateError, tags...)
 }
 
+func (app *BaseApp) OnRecordTransition(tags ...string) *hook.TaggedHook[*RecordTransitionEvent] {
+	return hook.NewTaggedHook(app.onRecordTransition, tags...)
+}
+
 func (app *BaseApp) OnRecordDelete(tags ...string) *hook.TaggedHook[*RecordEvent] {
 	return hook.NewTaggedHook(app.onRecordDelete, tags...)
 }
@@ -1012,6 +1156,38 @@ func (app *BaseApp) OnMailerRecordAuthAlertSend(tags ...string) *hook.TaggedHook
 	return hook.NewTaggedHook(app.onMailerRecordAuthAlertSend, tags...)
 }
 
+func (app *BaseApp) OnMailerRecordBulkSend(tags ...string) *hook.TaggedHook[*MailerRecordEvent] {
+	return hook.NewTaggedHook(app.onMailerRecordBulkSend, tags...)
+}
+
+// -------------------------------------------------------------------
+// SMS event hooks
+// -------------------------------------------------------------------
+
+func (app *BaseApp) OnSMSSend() *hook.Hook[*SMSEvent] {
+	return app.onSMSSend
+}
+
+// -------------------------------------------------------------------
+// Usage event hooks
+// -------------------------------------------------------------------
+
+func (app *BaseApp) OnUsageTrack() *hook.Hook[*UsageTrackEvent] {
+	return app.onUsageTrack
+}
+
+func (app *BaseApp) OnUsageRollup() *hook.Hook[*UsageRollupEvent] {
+	return app.onUsageRollup
+}
+
+// -------------------------------------------------------------------
+// Outbox event hooks
+// -------------------------------------------------------------------
+
+func (app *BaseApp) OnOutboxMessageDeliver(tags ...string) *hook.TaggedHook[*OutboxMessageEvent] {
+	return hook.NewTaggedHook(app.onOutboxMessageDeliver, tags...)
+}
+
 // -------------------------------------------------------------------
 // Realtime API event hooks
 // -------------------------------------------------------------------
@@ -1048,6 +1224,10 @@ func (app *BaseApp) OnSettingsReload() *hook.Hook[*SettingsReloadEvent] {
 // File API event hooks
 // -------------------------------------------------------------------
 
+func (app *BaseApp) OnFileUpload(tags ...string) *hook.TaggedHook[*FileUploadEvent] {
+	return hook.NewTaggedHook(app.onFileUpload, tags...)
+}
+
 func (app *BaseApp) OnFileDownloadRequest(tags ...string) *hook.TaggedHook[*FileDownloadRequestEvent] {
 	return hook.NewTaggedHook(app.onFileDownloadRequest, tags...)
 }
@@ -1064,6 +1244,14 @@ func (app *BaseApp) OnRecordAuthRequest(tags ...string) *hook.TaggedHook[*Record
 	return hook.NewTaggedHook(app.onRecordAuthRequest, tags...)
 }
 
+func (app *BaseApp) OnRecordAuthFailure(tags ...string) *hook.TaggedHook[*RecordAuthFailureEvent] {
+	return hook.NewTaggedHook(app.onRecordAuthFailure, tags...)
+}
+
+func (app *BaseApp) OnRecordAuthTokenClaims(tags ...string) *hook.TaggedHook[*RecordAuthTokenClaimsEvent] {
+	return hook.NewTaggedHook(app.onRecordAuthTokenClaims, tags...)
+}
+
 func (app *BaseApp) OnRecordAuthWithPasswordRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithPasswordRequestEvent] {
 	return hook.NewTaggedHook(app.onRecordAuthWithPasswordRequest, tags...)
 }
@@ -1072,6 +1260,10 @@ func (app *BaseApp) OnRecordAuthWithOAuth2Request(tags ...string) *hook.TaggedHo
 	return hook.NewTaggedHook(app.onRecordAuthWithOAuth2Request, tags...)
 }
 
+func (app *BaseApp) OnRecordAuthWithLDAPRequest(tags ...string) *hook.TaggedHook[*RecordAuthWithLDAPRequestEvent] {
+	return hook.NewTaggedHook(app.onRecordAuthWithLDAPRequest, tags...)
+}
+
 func (app *BaseApp) OnRecordAuthRefreshRequest(tags ...string) *hook.TaggedHook[*RecordAuthRefreshRequestEvent] {
 	return hook.NewTaggedHook(app.onRecordAuthRefreshRequest, tags...)
 }
@@ -1132,6 +1324,14 @@ func (app *BaseApp) OnRecordDeleteRequest(tags ...string) *hook.TaggedHook[*Reco
 	return hook.NewTaggedHook(app.onRecordDeleteRequest, tags...)
 }
 
+func (app *BaseApp) OnRecordSyncConflictRequest(tags ...string) *hook.TaggedHook[*RecordSyncConflictRequestEvent] {
+	return hook.NewTaggedHook(app.onRecordSyncConflictRequest, tags...)
+}
+
+func (app *BaseApp) OnRecordRequestTransform(tags ...string) *hook.TaggedHook[*RecordRequestTransformEvent] {
+	return hook.NewTaggedHook(app.onRecordRequestTransform, tags...)
+}
+
 // -------------------------------------------------------------------
 // Collection API event hooks
 // -------------------------------------------------------------------
@@ -1273,6 +1473,11 @@ func supportFiles(m Model) bool {
 		return true
 	}
 
+	// stored generated Open Graph images
+	if collection.OGImage != nil {
+		return true
+	}
+
 	for _, f := range collection.Fields {
 		if f.Type() == FieldTypeFile {
 			return true
@@ -1309,7 +1514,7 @@ func (app *BaseApp) registerBaseHooks() {
 	app.OnModelAfterDeleteSuccess().Bind(&hook.Handler[*ModelEvent]{
 		Id: "__pbFilesManagerDelete__",
 		Func: func(e *ModelEvent) error {
-			if m, ok := e.Model.(FilesManager); ok && m.BaseFilesPath() != "" && supportFiles(e.Model) {
+			if m, ok := e.Model.(FilesManager); ok && m.BaseFilesPath() != "" && supportFiles(e.Model) && !isRecycledModel(e.Model) {
 				// ensure that there is a trailing slash so that the list iterator could start walking from the prefix dir
 				// (https://github.com/pocketbase/pocketbase/discussions/5246#discussioncomment-10128955)
 				prefix := strings.TrimRight(m.BaseFilesPath(), "/") + "/"
@@ -1343,6 +1548,10 @@ func (app *BaseApp) registerBaseHooks() {
 		Priority: -99,
 	})
 
+	app.registerChangefeedHooks()
+	app.registerRecycleBinHooks()
+	app.registerTombstoneHooks()
+
 	app.OnServe().Bind(&hook.Handler[*ServeEvent]{
 		Id: "__pbCronStart__",
 		Func: func(e *ServeEvent) error {
@@ -1379,6 +1588,25 @@ func (app *BaseApp) registerBaseHooks() {
 	app.registerMFAHooks()
 	app.registerOTPHooks()
 	app.registerAuthOriginHooks()
+	app.registerListPresetHooks()
+	app.registerScheduledChangeHooks()
+	app.registerRecordLockHooks()
+	app.registerRecordShareHooks()
+	app.registerRoleHooks()
+	app.registerTeamHooks()
+	app.registerSignupInviteHooks()
+	app.registerAppConfigHooks()
+	app.registerFeatureFlagHooks()
+	app.registerExperimentHooks()
+	app.registerDigestHooks()
+	app.registerRedirectHooks()
+	app.registerReactionHooks()
+	app.registerSearchHooks()
+	app.registerMailQueueHooks()
+	app.registerOutboxHooks()
+	app.registerUsageHooks()
+	app.registerThumbsHooks()
+	app.registerFileQuarantineHooks()
 }
 
 // getLoggerMinLevel returns the logger min level based on the
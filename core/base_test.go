@@ -3,7 +3,10 @@ package core_test
 import (
 	"context"
 	"database/sql"
+	"io"
 	"log/slog"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"slices"
 	"testing"
@@ -16,6 +19,7 @@ import (
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/logger"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/sms"
 )
 
 func TestNewBaseApp(t *testing.T) {
@@ -207,6 +211,56 @@ func TestBaseAppNewMailClient(t *testing.T) {
 	}
 }
 
+func TestBaseAppNewSMSClient(t *testing.T) {
+	const testDataDir = "./pb_base_app_test_data_dir/"
+	defer os.RemoveAll(testDataDir)
+
+	app := core.NewBaseApp(core.BaseAppConfig{
+		DataDir:       testDataDir,
+		EncryptionEnv: "pb_test_env",
+	})
+	defer app.ResetBootstrapState()
+
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received = append(received, r.Header.Get("Authorization")+" "+string(body))
+	}))
+	defer server.Close()
+
+	var hookCalls int
+	app.OnSMSSend().BindFunc(func(e *core.SMSEvent) error {
+		hookCalls++
+		return e.Next()
+	})
+
+	// disabled
+	err := app.NewSMSClient().Send(&sms.Message{To: "+359888123456", Body: "test"})
+	if err == nil {
+		t.Fatal("Expected error for disabled SMS settings")
+	}
+
+	// webhook
+	app.Settings().SMS.Enabled = true
+	app.Settings().SMS.Provider = core.SMSProviderWebhook
+	app.Settings().SMS.WebhookURL = server.URL
+	app.Settings().SMS.AuthToken = "Bearer test"
+
+	err = app.NewSMSClient().Send(&sms.Message{To: "+359888123456", Body: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if hookCalls != 2 {
+		t.Fatalf("Expected OnSMSSend hook to be called 2 times, got %d", hookCalls)
+	}
+
+	expected := []string{`Bearer test {"to":"+359888123456","body":"test"}`}
+	if !slices.Equal(received, expected) {
+		t.Fatalf("Expected webhook requests\n%v\ngot\n%v", expected, received)
+	}
+}
+
 func TestBaseAppNewFilesystem(t *testing.T) {
 	const testDataDir = "./pb_base_app_test_data_dir/"
 	defer os.RemoveAll(testDataDir)
@@ -0,0 +1,180 @@
+package core
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const ChangesTableName = "_changes"
+
+const (
+	ChangeActionCreate = "create"
+	ChangeActionUpdate = "update"
+	ChangeActionDelete = "delete"
+)
+
+// Change defines a single changefeed entry.
+//
+// The Seq is a monotonically increasing (and never reused) sequence number
+// that could be used as cursor for resuming the changes consumption.
+type Change struct {
+	Seq            int64          `db:"seq" json:"seq"`
+	CollectionId   string         `db:"collectionId" json:"collectionId"`
+	CollectionName string         `db:"collectionName" json:"collectionName"`
+	RecordId       string         `db:"recordId" json:"recordId"`
+	Action         string         `db:"action" json:"action"`
+	Data           types.JSONRaw  `db:"data" json:"data"`
+	Version        int            `db:"version" json:"version"`
+	Created        types.DateTime `db:"created" json:"created"`
+}
+
+// ChangeQuery returns a new changefeed entries select query.
+func (app *BaseApp) ChangeQuery() *dbx.SelectQuery {
+	return app.ConcurrentDB().Select("{{" + ChangesTableName + "}}.*").From(ChangesTableName)
+}
+
+// FindChanges returns up to limit changefeed entries with Seq greater than
+// afterSeq ordered by their Seq (oldest first).
+//
+// collectionIdOrName is optional and when set only the changes
+// of the matching collection are returned.
+func (app *BaseApp) FindChanges(afterSeq int64, collectionIdOrName string, limit int) ([]*Change, error) {
+	query := app.ChangeQuery().
+		AndWhere(dbx.NewExp("[[seq]] > {:seq}", dbx.Params{"seq": afterSeq})).
+		OrderBy("seq ASC")
+
+	if collectionIdOrName != "" {
+		query.AndWhere(dbx.Or(
+			dbx.HashExp{"collectionId": collectionIdOrName},
+			dbx.HashExp{"collectionName": collectionIdOrName},
+		))
+	}
+
+	if limit > 0 {
+		query.Limit(int64(limit))
+	}
+
+	result := []*Change{}
+
+	err := query.All(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteOldChanges deletes all changefeed entries that are created before createdBefore.
+//
+// For better performance the changes delete is executed as plain SQL statement,
+// aka. no delete model hook events will be fired.
+func (app *BaseApp) DeleteOldChanges(createdBefore time.Time) error {
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+	expr := dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": formattedDate})
+
+	_, err := app.NonconcurrentDB().Delete(ChangesTableName, expr).Execute()
+
+	return err
+}
+
+// registerChangefeedHooks registers the record hooks that persist the
+// record changes in the changefeed table (when enabled in the settings).
+//
+// The change entry is inserted within the same transaction as the record
+// db write so that both either succeed or fail together.
+func (app *BaseApp) registerChangefeedHooks() {
+	capture := func(action string) func(e *RecordEvent) error {
+		return func(e *RecordEvent) error {
+			if !e.App.Settings().Changefeed.Enabled || e.Record.Collection().System {
+				return e.Next()
+			}
+
+			if e.App.IsTransactional() {
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				return insertChange(e.App, action, e.Record)
+			}
+
+			originalApp := e.App
+			defer func() {
+				e.App = originalApp
+			}()
+
+			return originalApp.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				return insertChange(txApp, action, e.Record)
+			})
+		}
+	}
+
+	app.OnRecordCreateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Id:       "__pbChangefeedCreate__",
+		Func:     capture(ChangeActionCreate),
+		Priority: 99,
+	})
+
+	app.OnRecordUpdateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Id:       "__pbChangefeedUpdate__",
+		Func:     capture(ChangeActionUpdate),
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Id:       "__pbChangefeedDelete__",
+		Func:     capture(ChangeActionDelete),
+		Priority: 99,
+	})
+
+	// cleanup old changes
+	app.Cron().Add("__pbChangefeedCleanup__", "0 */6 * * *", func() {
+		maxDays := app.Settings().Changefeed.MaxDays
+		if maxDays <= 0 {
+			return // no limit
+		}
+
+		deleteErr := app.DeleteOldChanges(time.Now().AddDate(0, 0, -1*maxDays))
+		if deleteErr != nil {
+			app.Logger().Warn("Failed to delete old changefeed entries", "error", deleteErr)
+		}
+	})
+}
+
+func insertChange(txApp App, action string, record *Record) error {
+	version, err := ResolveRecordPayloadVersion(txApp.Settings().Changefeed.PayloadVersion)
+	if err != nil {
+		return err
+	}
+
+	export, err := RecordPayloadData(version, record)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(export)
+	if err != nil {
+		return err
+	}
+
+	_, err = txApp.NonconcurrentDB().Insert(ChangesTableName, dbx.Params{
+		"collectionId":   record.Collection().Id,
+		"collectionName": record.Collection().Name,
+		"recordId":       record.Id,
+		"action":         action,
+		"data":           string(data),
+		"version":        version,
+		"created":        types.NowDateTime().String(),
+	}).Execute()
+
+	return err
+}
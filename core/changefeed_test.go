@@ -0,0 +1,237 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestChangefeedCapture(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("changefeed_test")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// disabled
+	// ---
+	record := core.NewRecord(collection)
+	record.Set("title", "disabled")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := app.FindChanges(0, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 0 {
+		t.Fatalf("Expected no changes while disabled, got %d", len(changes))
+	}
+
+	// enabled
+	// ---
+	app.Settings().Changefeed.Enabled = true
+
+	record.Set("title", "update")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	created := core.NewRecord(collection)
+	created.Set("title", "create")
+	if err := app.Save(created); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// rollbacked transaction
+	txErr := app.RunInTransaction(func(txApp core.App) error {
+		r := core.NewRecord(collection)
+		r.Set("title", "rollback")
+		if err := txApp.Save(r); err != nil {
+			return err
+		}
+
+		return errors.New("rollback")
+	})
+	if txErr == nil {
+		t.Fatal("Expected transaction error")
+	}
+
+	// system collection
+	superusers, err := app.FindCollectionByNameOrId(core.CollectionNameSuperusers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	superuser := core.NewRecord(superusers)
+	superuser.SetEmail("changefeed@example.com")
+	superuser.SetPassword("1234567890")
+	if err := app.Save(superuser); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err = app.FindChanges(0, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []struct {
+		action   string
+		recordId string
+		title    string
+	}{
+		{core.ChangeActionUpdate, record.Id, "update"},
+		{core.ChangeActionCreate, created.Id, "create"},
+		{core.ChangeActionDelete, record.Id, "update"},
+	}
+
+	if len(changes) != len(expected) {
+		t.Fatalf("Expected %d changes, got %d", len(expected), len(changes))
+	}
+
+	for i, change := range changes {
+		if i > 0 && change.Seq <= changes[i-1].Seq {
+			t.Fatalf("[%d] Expected seq > %d, got %d", i, changes[i-1].Seq, change.Seq)
+		}
+
+		if change.Action != expected[i].action {
+			t.Fatalf("[%d] Expected action %q, got %q", i, expected[i].action, change.Action)
+		}
+
+		if change.RecordId != expected[i].recordId {
+			t.Fatalf("[%d] Expected recordId %q, got %q", i, expected[i].recordId, change.RecordId)
+		}
+
+		if change.CollectionId != collection.Id || change.CollectionName != collection.Name {
+			t.Fatalf("[%d] Expected collection %q (%q), got %q (%q)", i, collection.Id, collection.Name, change.CollectionId, change.CollectionName)
+		}
+
+		if change.Version != core.LatestRecordPayloadVersion {
+			t.Fatalf("[%d] Expected payload version %d, got %d", i, core.LatestRecordPayloadVersion, change.Version)
+		}
+
+		data := map[string]any{}
+		if err := json.Unmarshal(change.Data, &data); err != nil {
+			t.Fatal(err)
+		}
+		if data["title"] != expected[i].title {
+			t.Fatalf("[%d] Expected data title %q, got %v", i, expected[i].title, data["title"])
+		}
+	}
+}
+
+func TestFindChanges(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Changefeed.Enabled = true
+
+	c1 := core.NewBaseCollection("changefeed_test1")
+	c2 := core.NewBaseCollection("changefeed_test2")
+	for _, c := range []*core.Collection{c1, c2} {
+		if err := app.Save(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, c := range []*core.Collection{c1, c2, c1, c2, c1} {
+		if err := app.Save(core.NewRecord(c)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	all, err := app.FindChanges(0, "", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 5 {
+		t.Fatalf("Expected 5 changes, got %d", len(all))
+	}
+
+	scenarios := []struct {
+		name       string
+		afterSeq   int64
+		collection string
+		limit      int
+		expected   []int64
+	}{
+		{"all", 0, "", 0, []int64{all[0].Seq, all[1].Seq, all[2].Seq, all[3].Seq, all[4].Seq}},
+		{"after seq", all[2].Seq, "", 0, []int64{all[3].Seq, all[4].Seq}},
+		{"with limit", 0, "", 2, []int64{all[0].Seq, all[1].Seq}},
+		{"by collection name", 0, c1.Name, 0, []int64{all[0].Seq, all[2].Seq, all[4].Seq}},
+		{"by collection id", all[1].Seq, c2.Id, 0, []int64{all[3].Seq}},
+		{"missing collection", 0, "missing", 0, []int64{}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			changes, err := app.FindChanges(s.afterSeq, s.collection, s.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(changes) != len(s.expected) {
+				t.Fatalf("Expected %d changes, got %d", len(s.expected), len(changes))
+			}
+
+			for i, change := range changes {
+				if change.Seq != s.expected[i] {
+					t.Fatalf("[%d] Expected seq %d, got %d", i, s.expected[i], change.Seq)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteOldChanges(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Changefeed.Enabled = true
+
+	collection := core.NewBaseCollection("changefeed_test")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := app.Save(core.NewRecord(collection)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.DeleteOldChanges(time.Now().Add(-1 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, _ := app.FindChanges(0, "", 0)
+	if len(changes) != 3 {
+		t.Fatalf("Expected the changes to remain, got %d", len(changes))
+	}
+
+	if err := app.DeleteOldChanges(time.Now().Add(1 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, _ = app.FindChanges(0, "", 0)
+	if len(changes) != 0 {
+		t.Fatalf("Expected all changes to be deleted, got %d", len(changes))
+	}
+}
@@ -0,0 +1,429 @@
+package core
+
+import (
+	"archive/zip"
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// Collections archive entries layout:
+//
+//	collections.json                          - the collections configuration (the same as the regular json export)
+//	records/{collectionId}.jsonl              - the raw collection records db rows (one json object per line)
+//	files/{collectionId}/{recordId}/{name}    - the collection records files
+const (
+	CollectionsArchiveCollectionsFile = "collections.json"
+	CollectionsArchiveRecordsDir      = "records"
+	CollectionsArchiveFilesDir        = "files"
+)
+
+// CollectionsArchiveMaxEntrySize is the max allowed uncompressed size
+// of a single imported collections archive entry (records list or file).
+var CollectionsArchiveMaxEntrySize int64 = 1 << 30
+
+// CollectionsArchiveMaxTotalSize is the max allowed total uncompressed size
+// of the imported collections archive entries.
+var CollectionsArchiveMaxTotalSize int64 = 50 << 30
+
+// ExportCollectionsArchive streams into w a zip archive with the
+// configuration of the provided collections.
+//
+// If includeData is true, the archive will contain also the raw records
+// and files of the non-view collections (thumbs are not exported).
+func (app *BaseApp) ExportCollectionsArchive(w io.Writer, collections []*Collection, includeData bool) error {
+	zw := zip.NewWriter(w)
+
+	collectionsWriter, err := zw.Create(CollectionsArchiveCollectionsFile)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(collectionsWriter)
+	encoder.SetIndent("", "    ")
+	if err := encoder.Encode(collections); err != nil {
+		return err
+	}
+
+	if includeData {
+		for _, collection := range collections {
+			if collection.IsView() {
+				continue
+			}
+
+			if err := app.exportCollectionArchiveData(zw, collection); err != nil {
+				return fmt.Errorf("failed to export %q records: %w", collection.Name, err)
+			}
+		}
+	}
+
+	return zw.Close()
+}
+
+func (app *BaseApp) exportCollectionArchiveData(zw *zip.Writer, collection *Collection) error {
+	recordsWriter, err := zw.Create(CollectionsArchiveRecordsDir + "/" + collection.Id + ".jsonl")
+	if err != nil {
+		return err
+	}
+
+	rows, err := app.DB().Select("*").From(collection.Name).OrderBy("rowid ASC").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var fileFields []Field
+	for _, field := range collection.Fields {
+		if field.Type() == FieldTypeFile {
+			fileFields = append(fileFields, field)
+		}
+	}
+
+	// list of the record files keys (relative to the collection files path)
+	var files []string
+
+	encoder := json.NewEncoder(recordsWriter)
+
+	for rows.Next() {
+		row := dbx.NullStringMap{}
+		if err := rows.ScanMap(row); err != nil {
+			return err
+		}
+
+		raw := make(map[string]any, len(collection.Fields))
+		for _, field := range collection.Fields {
+			if v, ok := row[field.GetName()]; ok && v.Valid {
+				raw[field.GetName()] = v.String
+			} else {
+				raw[field.GetName()] = nil
+			}
+		}
+
+		if err := encoder.Encode(raw); err != nil {
+			return err
+		}
+
+		if len(fileFields) == 0 {
+			continue
+		}
+
+		record, err := newRecordFromNullStringMap(collection, row)
+		if err != nil {
+			return err
+		}
+
+		for _, field := range fileFields {
+			for _, name := range record.GetStringSlice(field.GetName()) {
+				files = append(files, record.Id+"/"+name)
+			}
+		}
+	}
+
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	for _, file := range files {
+		r, err := fsys.GetReader(collection.BaseFilesPath() + "/" + file)
+		if err != nil {
+			return fmt.Errorf("failed to read file %q: %w", file, err)
+		}
+
+		err = copyToArchive(zw, CollectionsArchiveFilesDir+"/"+collection.Id+"/"+file, r)
+		r.Close()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func copyToArchive(zw *zip.Writer, name string, r io.Reader) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(w, r)
+
+	return err
+}
+
+// ReadCollectionsArchive loads the collections configuration data
+// from the provided collections archive.
+func ReadCollectionsArchive(archive *zip.Reader) ([]map[string]any, error) {
+	f, err := archive.Open(CollectionsArchiveCollectionsFile)
+	if err != nil {
+		return nil, fmt.Errorf("invalid collections archive: %w", err)
+	}
+	defer f.Close()
+
+	data := []map[string]any{}
+
+	if err := json.NewDecoder(f).Decode(&data); err != nil {
+		return nil, fmt.Errorf("invalid collections archive %s: %w", CollectionsArchiveCollectionsFile, err)
+	}
+
+	return data, nil
+}
+
+// ImportCollectionsArchive imports the provided collections data (usually loaded with [ReadCollectionsArchive])
+// and the related archive records and files in a single transaction.
+//
+// Only the records and files of the collections in toImport are imported.
+// The records are inserted as raw db rows (existing records with the same id are replaced),
+// meaning that their ids, password hashes, autodate values, etc. are preserved as they are
+// and no record model hooks or validations are triggered.
+//
+// The archive files are streamed to the app storage (the already existing ones are skipped)
+// and they are deleted if the transaction fails. The uncompressed archive entries size is limited
+// by [CollectionsArchiveMaxEntrySize] and [CollectionsArchiveMaxTotalSize].
+//
+// NB! If deleteMissing is true, ALL NON-SYSTEM COLLECTIONS AND SCHEMA FIELDS,
+// that are not present in the imported configuration, WILL BE DELETED
+// (this includes their related records data).
+func (app *BaseApp) ImportCollectionsArchive(toImport []map[string]any, archive *zip.Reader, deleteMissing bool) error {
+	return app.RunInTransaction(func(txApp App) error {
+		if err := txApp.ImportCollections(toImport, deleteMissing); err != nil {
+			return err
+		}
+
+		collections := make(map[string]*Collection, len(toImport))
+		for _, data := range toImport {
+			id, _ := data["id"].(string)
+			if id == "" {
+				continue
+			}
+
+			collection, err := txApp.FindCollectionByNameOrId(id)
+			if err != nil {
+				return err
+			}
+
+			if !collection.IsView() {
+				collections[collection.Id] = collection
+			}
+		}
+
+		return importCollectionsArchiveData(txApp, archive, collections)
+	})
+}
+
+func importCollectionsArchiveData(txApp App, archive *zip.Reader, collections map[string]*Collection) error {
+	limiter := &archiveSizeLimiter{}
+
+	var fileEntries []*zip.File
+
+	for _, entry := range archive.File {
+		if entry.FileInfo().IsDir() {
+			continue
+		}
+
+		if strings.HasPrefix(entry.Name, CollectionsArchiveFilesDir+"/") {
+			fileEntries = append(fileEntries, entry)
+			continue
+		}
+
+		collectionId, ok := strings.CutPrefix(entry.Name, CollectionsArchiveRecordsDir+"/")
+		if !ok {
+			continue
+		}
+
+		collection, ok := collections[strings.TrimSuffix(collectionId, ".jsonl")]
+		if !ok {
+			continue
+		}
+
+		if err := importArchiveRecords(txApp, limiter, entry, collection); err != nil {
+			return fmt.Errorf("failed to import %q records: %w", collection.Name, err)
+		}
+	}
+
+	if len(fileEntries) == 0 {
+		return nil
+	}
+
+	fsys, err := txApp.NewFilesystem()
+	if err != nil {
+		return err
+	}
+
+	// delete the uploaded files on transaction failure
+	// (the filesystem is closed after the cleanup)
+	var uploaded []string
+	txInfo := txApp.TxInfo()
+	if txInfo == nil {
+		defer fsys.Close()
+	} else {
+		txInfo.OnComplete(func(txErr error) error {
+			defer fsys.Close()
+
+			if txErr == nil {
+				return nil
+			}
+
+			var errs []error
+			for _, key := range uploaded {
+				if err := fsys.Delete(key); err != nil {
+					errs = append(errs, fmt.Errorf("failed to delete imported archive file %q: %w", key, err))
+				}
+			}
+
+			return errors.Join(errs...)
+		})
+	}
+
+	for _, entry := range fileEntries {
+		// files/{collectionId}/{recordId}/{name}
+		parts := strings.Split(entry.Name, "/")
+		if len(parts) != 4 || path.Clean(entry.Name) != entry.Name || list.ExistInSlice("..", parts) {
+			return fmt.Errorf("invalid archive file path %q", entry.Name)
+		}
+
+		collection, ok := collections[parts[1]]
+		if !ok {
+			continue
+		}
+
+		key := collection.BaseFilesPath() + "/" + parts[2] + "/" + parts[3]
+
+		// skip the existing files to prevent overwriting them
+		// (the stored file names have random suffix so usually they are the same file)
+		exists, err := fsys.Exists(key)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		if err := uploadArchiveFile(fsys, limiter, entry, key); err != nil {
+			return fmt.Errorf("failed to upload archive file %q: %w", entry.Name, err)
+		}
+
+		uploaded = append(uploaded, key)
+	}
+
+	return nil
+}
+
+func importArchiveRecords(txApp App, limiter *archiveSizeLimiter, entry *zip.File, collection *Collection) error {
+	f, err := limiter.open(entry)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(nil, 64<<20) // allow larger rows (e.g. with big json or editor fields)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
+			continue
+		}
+
+		raw := map[string]any{}
+		if err := json.Unmarshal(line, &raw); err != nil {
+			return err
+		}
+
+		id, _ := raw[FieldNameId].(string)
+		if id == "" {
+			return errors.New("missing record id")
+		}
+
+		params := make(dbx.Params, len(raw))
+		for _, field := range collection.Fields {
+			name := field.GetName()
+			if v, ok := raw[name]; ok {
+				params[name] = v
+			}
+		}
+
+		_, err := txApp.DB().Delete(collection.Name, dbx.HashExp{FieldNameId: id}).Execute()
+		if err != nil {
+			return err
+		}
+
+		_, err = txApp.DB().Insert(collection.Name, params).Execute()
+		if err != nil {
+			return fmt.Errorf("failed to insert record %q: %w", id, err)
+		}
+	}
+
+	return scanner.Err()
+}
+
+func uploadArchiveFile(fsys *filesystem.System, limiter *archiveSizeLimiter, entry *zip.File, key string) error {
+	f, err := limiter.open(entry)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	return fsys.UploadReader(f, key)
+}
+
+// archiveSizeLimiter limits the uncompressed size of the read archive entries
+// (the actual read bytes are counted because the zip headers could be forged).
+type archiveSizeLimiter struct {
+	total int64
+}
+
+func (l *archiveSizeLimiter) open(entry *zip.File) (io.ReadCloser, error) {
+	if entry.UncompressedSize64 > uint64(CollectionsArchiveMaxEntrySize) {
+		return nil, fmt.Errorf("archive entry %q exceeds the max allowed size of %d bytes", entry.Name, CollectionsArchiveMaxEntrySize)
+	}
+
+	f, err := entry.Open()
+	if err != nil {
+		return nil, err
+	}
+
+	return &archiveLimitedReader{ReadCloser: f, limiter: l, name: entry.Name}, nil
+}
+
+type archiveLimitedReader struct {
+	io.ReadCloser
+	limiter *archiveSizeLimiter
+	name    string
+	read    int64
+}
+
+// Read implements [io.Reader] interface.
+func (r *archiveLimitedReader) Read(p []byte) (int, error) {
+	n, err := r.ReadCloser.Read(p)
+
+	r.read += int64(n)
+	r.limiter.total += int64(n)
+
+	if r.read > CollectionsArchiveMaxEntrySize {
+		return n, fmt.Errorf("archive entry %q exceeds the max allowed size of %d bytes", r.name, CollectionsArchiveMaxEntrySize)
+	}
+
+	if r.limiter.total > CollectionsArchiveMaxTotalSize {
+		return n, fmt.Errorf("archive entries exceed the max allowed total size of %d bytes", CollectionsArchiveMaxTotalSize)
+	}
+
+	return n, err
+}
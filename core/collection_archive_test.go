@@ -0,0 +1,393 @@
+package core_test
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestExportCollectionsArchive(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo1, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	view1, err := app.FindCollectionByNameOrId("view1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name          string
+		includeData   bool
+		expectedFiles []string
+	}{
+		{
+			"without data",
+			false,
+			[]string{core.CollectionsArchiveCollectionsFile},
+		},
+		{
+			"with data",
+			true,
+			[]string{
+				core.CollectionsArchiveCollectionsFile,
+				"records/" + demo1.Id + ".jsonl",
+				"files/" + demo1.Id + "/84nmscqy84lsi1t/300_WlbFWSGmW9.png",
+				"files/" + demo1.Id + "/84nmscqy84lsi1t/logo_vcfJJG5TAh.svg",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			buf := new(bytes.Buffer)
+
+			err := app.ExportCollectionsArchive(buf, []*core.Collection{demo1, view1}, s.includeData)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			names := make(map[string]bool, len(archive.File))
+			for _, f := range archive.File {
+				names[f.Name] = true
+
+				if strings.Contains(f.Name, "thumbs_") {
+					t.Fatalf("Expected no thumbs to be exported, got %q", f.Name)
+				}
+
+				if strings.Contains(f.Name, view1.Id) {
+					t.Fatalf("Expected no view collection data to be exported, got %q", f.Name)
+				}
+			}
+
+			for _, name := range s.expectedFiles {
+				if !names[name] {
+					t.Fatalf("Missing expected archive file %q in\n%v", name, names)
+				}
+			}
+
+			if !s.includeData && len(names) != 1 {
+				t.Fatalf("Expected only the collections file, got\n%v", names)
+			}
+
+			collections, err := core.ReadCollectionsArchive(archive)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(collections) != 2 || collections[0]["name"] != "demo1" || collections[1]["name"] != "view1" {
+				t.Fatalf("Unexpected archive collections %v", collections)
+			}
+		})
+	}
+}
+
+func TestImportCollectionsArchive(t *testing.T) {
+	t.Parallel()
+
+	srcApp, _ := tests.NewTestApp()
+	defer srcApp.Cleanup()
+
+	users, err := srcApp.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1, err := srcApp.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	if err := srcApp.ExportCollectionsArchive(buf, []*core.Collection{users, demo1}, true); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	toImport, err := core.ReadCollectionsArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	// clear the existing records and files
+	// (directly to avoid the async files delete)
+	for _, c := range []*core.Collection{users, demo1} {
+		if _, err := app.DB().Delete(c.Name, nil).Execute(); err != nil {
+			t.Fatal(err)
+		}
+
+		if failed := fsys.DeletePrefix(c.BaseFilesPath() + "/"); len(failed) > 0 {
+			t.Fatal(failed)
+		}
+	}
+
+	if err := app.ImportCollectionsArchive(toImport, archive, false); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"users", "demo1"} {
+		expected, err := srcApp.CountRecords(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		total, err := app.CountRecords(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if total == 0 || total != expected {
+			t.Fatalf("Expected %d %s records, got %d", expected, name, total)
+		}
+	}
+
+	// the password hash and the autodate values should be preserved
+	srcUser, err := srcApp.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !user.ValidatePassword("1234567890") {
+		t.Fatal("Expected the imported user password to be valid")
+	}
+
+	if user.GetString("created") != srcUser.GetString("created") || user.GetString("updated") != srcUser.GetString("updated") {
+		t.Fatalf("Expected the autodate values to be preserved, got %v", user.FieldsData())
+	}
+
+	// the record files should be restored
+	exists, err := fsys.Exists(demo1.BaseFilesPath() + "/84nmscqy84lsi1t/300_WlbFWSGmW9.png")
+	if err != nil || !exists {
+		t.Fatalf("Expected the record file to be imported (%v)", err)
+	}
+}
+
+func TestImportCollectionsArchiveFilesRollback(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name    string
+		files   map[string]string
+		txErr   error
+		wrapTx  bool
+		created []string
+	}{
+		{
+			"import error after uploaded file",
+			map[string]string{
+				"files/wsmn24bux7wo113/84nmscqy84lsi1t/a.txt":  "a",
+				"files/wsmn24bux7wo113/84nmscqy84lsi1t/z/../b": "b",
+			},
+			nil,
+			false,
+			nil,
+		},
+		{
+			"outer transaction rollback",
+			map[string]string{
+				"files/wsmn24bux7wo113/84nmscqy84lsi1t/a.txt": "a",
+			},
+			errors.New("test_rollback"),
+			true,
+			nil,
+		},
+		{
+			"successful import",
+			map[string]string{
+				"files/wsmn24bux7wo113/84nmscqy84lsi1t/a.txt": "a",
+			},
+			nil,
+			true,
+			[]string{"wsmn24bux7wo113/84nmscqy84lsi1t/a.txt"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			archive, toImport := newTestCollectionsArchive(t, app, s.files)
+
+			var err error
+			if s.wrapTx {
+				err = app.RunInTransaction(func(txApp core.App) error {
+					if err := txApp.ImportCollectionsArchive(toImport, archive, false); err != nil {
+						return err
+					}
+					return s.txErr
+				})
+			} else {
+				err = app.ImportCollectionsArchive(toImport, archive, false)
+			}
+
+			hasErr := err != nil
+			expectErr := len(s.created) == 0
+			if hasErr != expectErr {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", expectErr, hasErr, err)
+			}
+
+			fsys, err := app.NewFilesystem()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer fsys.Close()
+
+			exists, _ := fsys.Exists("wsmn24bux7wo113/84nmscqy84lsi1t/a.txt")
+			if exists != slices.Contains(s.created, "wsmn24bux7wo113/84nmscqy84lsi1t/a.txt") {
+				t.Fatalf("Expected the uploaded file exists state to be %v", !exists)
+			}
+
+			// the existing record files should remain untouched
+			exists, _ = fsys.Exists("wsmn24bux7wo113/84nmscqy84lsi1t/300_WlbFWSGmW9.png")
+			if !exists {
+				t.Fatal("Expected the existing record file to remain")
+			}
+		})
+	}
+}
+
+func TestImportCollectionsArchiveMaxSize(t *testing.T) {
+	originalMaxEntrySize := core.CollectionsArchiveMaxEntrySize
+	originalMaxTotalSize := core.CollectionsArchiveMaxTotalSize
+	defer func() {
+		core.CollectionsArchiveMaxEntrySize = originalMaxEntrySize
+		core.CollectionsArchiveMaxTotalSize = originalMaxTotalSize
+	}()
+
+	scenarios := []struct {
+		name         string
+		maxEntrySize int64
+		maxTotalSize int64
+		expectError  bool
+	}{
+		{"exceeding the max entry size", 150, 1 << 20, true},
+		{"exceeding the max total size", 1 << 20, 250, true},
+		{"within the limits", 200, 1 << 20, false},
+	}
+
+	files := map[string]string{
+		"files/wsmn24bux7wo113/84nmscqy84lsi1t/a.txt": strings.Repeat("a", 200),
+		"files/wsmn24bux7wo113/84nmscqy84lsi1t/b.txt": strings.Repeat("b", 200),
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			archive, toImport := newTestCollectionsArchive(t, app, files)
+
+			core.CollectionsArchiveMaxEntrySize = s.maxEntrySize
+			core.CollectionsArchiveMaxTotalSize = s.maxTotalSize
+
+			err := app.ImportCollectionsArchive(toImport, archive, false)
+
+			// restore the defaults before the checks
+			core.CollectionsArchiveMaxEntrySize = originalMaxEntrySize
+			core.CollectionsArchiveMaxTotalSize = originalMaxTotalSize
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			fsys, err := app.NewFilesystem()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer fsys.Close()
+
+			for _, name := range []string{"a.txt", "b.txt"} {
+				exists, _ := fsys.Exists("wsmn24bux7wo113/84nmscqy84lsi1t/" + name)
+				if exists == s.expectError {
+					t.Fatalf("Expected %s exists state %v, got %v", name, !s.expectError, exists)
+				}
+			}
+		})
+	}
+}
+
+// newTestCollectionsArchive creates a new demo1 collection archive with the provided files.
+func newTestCollectionsArchive(t testing.TB, app core.App, files map[string]string) (*zip.Reader, []map[string]any) {
+	demo1, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := new(bytes.Buffer)
+	zw := zip.NewWriter(buf)
+
+	w, err := zw.Create(core.CollectionsArchiveCollectionsFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := json.NewEncoder(w).Encode([]*core.Collection{demo1}); err != nil {
+		t.Fatal(err)
+	}
+
+	// sorted for deterministic entries order
+	names := slices.Sorted(maps.Keys(files))
+	for _, name := range names {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(files[name])); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	toImport, err := core.ReadCollectionsArchive(archive)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return archive, toImport
+}
@@ -0,0 +1,74 @@
+package core
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// CalendarConfig defines the collection records iCalendar feed field mapping.
+//
+// When set, the collection records are exposed as calendar events via
+// the "/api/collections/{collection}/calendar.ics" endpoint
+// (the collection list API rule is applied as usual).
+type CalendarConfig struct {
+	// StartField is the name of the date field with the event start date.
+	StartField string `form:"startField" json:"startField"`
+
+	// EndField is the name of the optional date field with the event end date.
+	EndField string `form:"endField" json:"endField"`
+
+	// TitleField is the name of the field with the event title.
+	TitleField string `form:"titleField" json:"titleField"`
+
+	// DescriptionField is the name of the optional field with the event description.
+	DescriptionField string `form:"descriptionField" json:"descriptionField"`
+}
+
+func (c *CalendarConfig) validate(cv *collectionValidator) error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.StartField, validation.Required, validation.By(cv.checkMappedDateField)),
+		validation.Field(&c.EndField, validation.By(cv.checkMappedDateField)),
+		validation.Field(&c.TitleField, validation.Required, validation.By(cv.checkMappedField)),
+		validation.Field(&c.DescriptionField, validation.By(cv.checkMappedField)),
+	)
+}
+
+func checkCalendarConfig(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		config, _ := value.(*CalendarConfig)
+		if config == nil {
+			return nil // disabled
+		}
+
+		return config.validate(cv)
+	}
+}
+
+func (cv *collectionValidator) checkMappedField(value any) error {
+	name, _ := value.(string)
+	if name == "" {
+		return nil // nothing to check
+	}
+
+	field := cv.new.Fields.GetByName(name)
+	if field == nil || field.GetHidden() || name == FieldNamePassword || name == FieldNameTokenKey {
+		return validation.NewError("validation_missing_field", "Invalid or missing field {{.fieldName}}").
+			SetParams(map[string]any{"fieldName": name})
+	}
+
+	return nil
+}
+
+func (cv *collectionValidator) checkMappedDateField(value any) error {
+	name, _ := value.(string)
+	if name == "" {
+		return nil // nothing to check
+	}
+
+	switch cv.new.Fields.GetByName(name).(type) {
+	case *DateField, *AutodateField:
+		return nil
+	default:
+		return validation.NewError("validation_invalid_date_field", "Missing or non-date field {{.fieldName}}").
+			SetParams(map[string]any{"fieldName": name})
+	}
+}
@@ -0,0 +1,83 @@
+package core
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// DefaultFeedMaxItems is the default number of the latest records included
+// in a collection feed if [FeedConfig.MaxItems] is not set.
+const DefaultFeedMaxItems = 50
+
+// FeedConfig defines the collection records RSS/Atom feed options.
+//
+// When set, the latest collection records (by DateField) are exposed via the
+// "/api/collections/{collection}/feed.rss" and "/api/collections/{collection}/feed.atom"
+// endpoints (the collection list API rule is applied as usual).
+type FeedConfig struct {
+	// Title is the optional feed title (default to the collection name).
+	Title string `form:"title" json:"title"`
+
+	// Description is the optional feed description.
+	Description string `form:"description" json:"description"`
+
+	// Link is the optional feed website url (default to the app url).
+	Link string `form:"link" json:"link"`
+
+	// TitleField is the name of the field with the entry title.
+	TitleField string `form:"titleField" json:"titleField"`
+
+	// DateField is the name of the date field with the entry publish date.
+	DateField string `form:"dateField" json:"dateField"`
+
+	// ContentField is the name of the optional field with the entry HTML content.
+	ContentField string `form:"contentField" json:"contentField"`
+
+	// AuthorField is the name of the optional field with the entry author name.
+	AuthorField string `form:"authorField" json:"authorField"`
+
+	// LinkTemplate is the optional entry url template with {fieldName}
+	// record field placeholders (e.g. "https://example.com/posts/{slug}").
+	LinkTemplate string `form:"linkTemplate" json:"linkTemplate"`
+
+	// MaxItems is the max number of the latest feed entries (default to [DefaultFeedMaxItems]).
+	MaxItems int `form:"maxItems" json:"maxItems"`
+
+	// CacheMaxAge specifies the optional feed Cache-Control max-age in seconds.
+	CacheMaxAge int `form:"cacheMaxAge" json:"cacheMaxAge"`
+}
+
+// Limit returns the max number of feed entries.
+func (c *FeedConfig) Limit() int {
+	if c.MaxItems <= 0 {
+		return DefaultFeedMaxItems
+	}
+
+	return c.MaxItems
+}
+
+func (c *FeedConfig) validate(cv *collectionValidator) error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.Title, validation.Length(0, 255)),
+		validation.Field(&c.Description, validation.Length(0, 1000)),
+		validation.Field(&c.Link, is.URL),
+		validation.Field(&c.TitleField, validation.Required, validation.By(cv.checkMappedField)),
+		validation.Field(&c.DateField, validation.Required, validation.By(cv.checkMappedDateField)),
+		validation.Field(&c.ContentField, validation.By(cv.checkMappedField)),
+		validation.Field(&c.AuthorField, validation.By(cv.checkMappedField)),
+		validation.Field(&c.LinkTemplate, validation.Length(0, 2000), validation.By(cv.checkRecordURLTemplate)),
+		validation.Field(&c.MaxItems, validation.Min(0), validation.Max(500)),
+		validation.Field(&c.CacheMaxAge, validation.Min(0), validation.Max(86400)),
+	)
+}
+
+func checkFeedConfig(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		config, _ := value.(*FeedConfig)
+		if config == nil {
+			return nil // disabled
+		}
+
+		return config.validate(cv)
+	}
+}
@@ -0,0 +1,129 @@
+package core
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+const (
+	defaultFormMaxRequests = 5
+	defaultFormDuration    = 600
+)
+
+// FormConfig defines the collection public form submissions options.
+//
+// When set, guests could create new collection records through the
+// dedicated "/api/collections/{collection}/form" endpoint regardless
+// of the collection create API rule, allowing contact-like forms
+// without exposing the general records create endpoint.
+type FormConfig struct {
+	// Fields is the list of the collection fields that could be submitted with the form.
+	//
+	// All other submitted values are ignored.
+	Fields []string `form:"fields" json:"fields"`
+
+	// HoneypotField is the name of an optional decoy form input
+	// (e.g. "website") that should be left empty by humans.
+	//
+	// Submissions with non-empty honeypot value are silently discarded.
+	HoneypotField string `form:"honeypotField" json:"honeypotField"`
+
+	// Captcha specifies whether the submissions should include a valid
+	// captcha response token (see [CaptchaConfig]).
+	Captcha bool `form:"captcha" json:"captcha"`
+
+	// NotifyEmails is an optional list of email addresses to notify
+	// for each new submission.
+	NotifyEmails []string `form:"notifyEmails" json:"notifyEmails"`
+
+	// MaxRequests is the max allowed submissions per client IP for
+	// the specified Duration (default to 5).
+	//
+	// The limit is applied regardless of the global rate limits settings.
+	MaxRequests int `form:"maxRequests" json:"maxRequests"`
+
+	// Duration is the submissions rate limit interval in seconds (default to 600).
+	Duration int64 `form:"duration" json:"duration"`
+}
+
+// RateLimitRule returns the form submissions rate limit rule
+// based on the MaxRequests and Duration options.
+func (c *FormConfig) RateLimitRule() RateLimitRule {
+	rule := RateLimitRule{
+		MaxRequests: c.MaxRequests,
+		Duration:    c.Duration,
+	}
+
+	if rule.MaxRequests <= 0 {
+		rule.MaxRequests = defaultFormMaxRequests
+	}
+
+	if rule.Duration <= 0 {
+		rule.Duration = defaultFormDuration
+	}
+
+	return rule
+}
+
+func (c *FormConfig) validate(cv *collectionValidator) error {
+	return validation.ValidateStruct(c,
+		validation.Field(
+			&c.Fields,
+			validation.Required,
+			validation.Each(validation.By(cv.checkFormField)),
+		),
+		validation.Field(&c.HoneypotField, validation.Length(1, 100), validation.By(cv.checkFormHoneypotField)),
+		validation.Field(&c.NotifyEmails, validation.Length(0, 20), validation.Each(validation.Required, is.EmailFormat)),
+		validation.Field(&c.MaxRequests, validation.Min(0), validation.Max(1000)),
+		validation.Field(&c.Duration, validation.Min(0), validation.Max(86400)),
+	)
+}
+
+func checkFormConfig(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		config, _ := value.(*FormConfig)
+		if config == nil {
+			return nil // disabled
+		}
+
+		if !cv.new.IsBase() {
+			return validation.NewError("validation_form_non_base_collection", "Public forms are supported only for base collections.")
+		}
+
+		return config.validate(cv)
+	}
+}
+
+// checkFormField checks whether the value is a regular collection field
+// that could be submitted with a public form.
+func (cv *collectionValidator) checkFormField(value any) error {
+	name, _ := value.(string)
+
+	field := cv.new.Fields.GetByName(name)
+
+	switch field.(type) {
+	case nil, *FileField, *PasswordField, *AutodateField:
+		return validation.NewError("validation_invalid_form_field", "Missing or non-submittable field {{.fieldName}}").
+			SetParams(map[string]any{"fieldName": name})
+	}
+
+	if field.GetSystem() || name == FieldNameId {
+		return validation.NewError("validation_invalid_form_field", "Missing or non-submittable field {{.fieldName}}").
+			SetParams(map[string]any{"fieldName": name})
+	}
+
+	return nil
+}
+
+func (cv *collectionValidator) checkFormHoneypotField(value any) error {
+	name, _ := value.(string)
+	if name == "" {
+		return nil // nothing to check
+	}
+
+	if cv.new.Fields.GetByName(name) != nil {
+		return validation.NewError("validation_invalid_honeypot_field", "The honeypot field must not be a collection field.")
+	}
+
+	return nil
+}
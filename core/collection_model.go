@@ -375,6 +375,8 @@ type baseCollection struct {
 	// System prevents the collection rename, deletion and rules change.
 	// It is used primarily for internal purposes for collections like "_superusers", "_externalAuths", etc.
 	System bool `db:"system" json:"system" form:"system"`
+
+	collectionCommonOptions
 }
 
 // Collection defines the table, fields and various options related to a set of records.
@@ -506,6 +508,10 @@ func (m *Collection) unmarshalRawOptions() error {
 		return nil
 	}
 
+	if err := json.Unmarshal(raw, &m.collectionCommonOptions); err != nil {
+		return err
+	}
+
 	switch m.Type {
 	case CollectionTypeView:
 		return json.Unmarshal(raw, &m.collectionViewOptions)
@@ -573,6 +579,7 @@ func (m Collection) MarshalJSON() ([]byte, error) {
 		for i := range alias.OAuth2.Providers {
 			alias.OAuth2.Providers[i].ClientSecret = ""
 		}
+		alias.LDAPAuth.BindPassword = ""
 
 		return json.Marshal(alias)
 	default:
@@ -605,21 +612,29 @@ func (m *Collection) DBExport(app App) (map[string]any, error) {
 		"options":    `{}`,
 	}
 
+	var options any
+
 	switch m.Type {
 	case CollectionTypeView:
-		if raw, err := types.ParseJSONRaw(m.collectionViewOptions); err == nil {
-			result["options"] = raw
-		} else {
-			return nil, err
-		}
+		options = struct {
+			collectionCommonOptions
+			collectionViewOptions
+		}{m.collectionCommonOptions, m.collectionViewOptions}
 	case CollectionTypeAuth:
-		if raw, err := types.ParseJSONRaw(m.collectionAuthOptions); err == nil {
-			result["options"] = raw
-		} else {
-			return nil, err
-		}
+		options = struct {
+			collectionCommonOptions
+			collectionAuthOptions
+		}{m.collectionCommonOptions, m.collectionAuthOptions}
+	default:
+		options = m.collectionCommonOptions
 	}
 
+	raw, err := types.ParseJSONRaw(options)
+	if err != nil {
+		return nil, err
+	}
+	result["options"] = raw
+
 	return result, nil
 }
 
@@ -811,6 +826,8 @@ func onCollectionSave(e *CollectionEvent) error {
 		e.Collection.unsetMissingOAuth2MappedFields()
 	}
 
+	e.Collection.unsetMissingErrorMessagesFields()
+
 	e.Collection.updateGeneratedIdIfExists(e.App)
 
 	return e.Next()
@@ -915,6 +932,7 @@ func (c *Collection) initDefaultFields() {
 		c.initEmailField()
 		c.initEmailVisibilityField()
 		c.initVerifiedField()
+		c.initPhoneVerifiedFields()
 	case CollectionTypeView:
 		// view fields are autogenerated
 	}
@@ -1054,6 +1072,22 @@ func (c *Collection) initVerifiedField() {
 	}
 }
 
+func (c *Collection) initPhoneVerifiedFields() {
+	var names []string
+	for _, f := range c.Fields {
+		if phoneField, ok := f.(*PhoneField); ok {
+			names = append(names, phoneField.VerifiedFieldName())
+		}
+	}
+
+	for _, name := range names {
+		if c.Fields.GetByName(name) == nil {
+			// load default field
+			c.Fields.Add(&BoolField{Name: name})
+		}
+	}
+}
+
 func (c *Collection) fieldIndexName(field string) string {
 	name := "idx_" + field + "_"
 
@@ -1,6 +1,8 @@
 package core
 
 import (
+	"errors"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
@@ -8,6 +10,7 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/tools/auth"
+	"github.com/pocketbase/pocketbase/tools/ldap"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/types"
@@ -42,6 +45,12 @@ func (m *Collection) unsetMissingOAuth2MappedFields() {
 			m.OAuth2.MappedFields.AvatarURL = ""
 		}
 	}
+
+	if m.OAuth2.MappedFields.Groups != "" {
+		if m.Fields.GetByName(m.OAuth2.MappedFields.Groups) == nil {
+			m.OAuth2.MappedFields.Groups = ""
+		}
+	}
 }
 
 func (m *Collection) setDefaultAuthOptions() {
@@ -54,6 +63,11 @@ func (m *Collection) setDefaultAuthOptions() {
 			Enabled:       true,
 			EmailTemplate: defaultAuthAlertTemplate,
 		},
+		EmailChange: EmailChangeConfig{
+			NotifyOldEmail:   true,
+			ApprovalTemplate: defaultEmailChangeApprovalTemplate,
+			NotifyTemplate:   defaultEmailChangeNotifyTemplate,
+		},
 		PasswordAuth: PasswordAuthConfig{
 			Enabled:        true,
 			IdentityFields: []string{FieldNameEmail},
@@ -62,6 +76,11 @@ func (m *Collection) setDefaultAuthOptions() {
 			Enabled:  false,
 			Duration: 1800, // 30min
 		},
+		LDAPAuth: LDAPAuthConfig{
+			Enabled:        false,
+			UserFilter:     "(uid=" + ldap.UsernamePlaceholder + ")",
+			EmailAttribute: "mail",
+		},
 		OTP: OTPConfig{
 			Enabled:       false,
 			Duration:      180, // 3min
@@ -118,6 +137,17 @@ type collectionAuthOptions struct {
 	// AuthAlert defines options related to the auth alerts on new device login.
 	AuthAlert AuthAlertConfig `form:"authAlert" json:"authAlert"`
 
+	// EmailChange defines options related to the auth record email change flow.
+	EmailChange EmailChangeConfig `form:"emailChange" json:"emailChange"`
+
+	// EmailDomains defines the email domain restrictions applied
+	// on auth record create and email change.
+	EmailDomains EmailDomainsConfig `form:"emailDomains" json:"emailDomains"`
+
+	// ProfileCompletion defines the auth record fields that are required
+	// to be completed after signup (e.g. for OAuth2 created accounts).
+	ProfileCompletion ProfileCompletionConfig `form:"profileCompletion" json:"profileCompletion"`
+
 	// OAuth2 specifies whether OAuth2 auth is enabled for the collection
 	// and which OAuth2 providers are allowed.
 	OAuth2 OAuth2Config `form:"oauth2" json:"oauth2"`
@@ -125,6 +155,10 @@ type collectionAuthOptions struct {
 	// PasswordAuth defines options related to the collection password authentication.
 	PasswordAuth PasswordAuthConfig `form:"passwordAuth" json:"passwordAuth"`
 
+	// LDAPAuth defines options related to the collection LDAP (Active Directory)
+	// password authentication.
+	LDAPAuth LDAPAuthConfig `form:"ldapAuth" json:"ldapAuth"`
+
 	// MFA defines options related to the Multi-factor authentication (MFA).
 	MFA MFAConfig `form:"mfa" json:"mfa"`
 
@@ -160,8 +194,12 @@ func (o *collectionAuthOptions) validate(cv *collectionValidator) error {
 			validation.By(cv.ensureNoSystemRuleChange(cv.original.ManageRule)),
 		),
 		validation.Field(&o.AuthAlert),
+		validation.Field(&o.EmailChange),
+		validation.Field(&o.EmailDomains),
+		validation.Field(&o.ProfileCompletion),
 		validation.Field(&o.PasswordAuth),
 		validation.Field(&o.OAuth2),
+		validation.Field(&o.LDAPAuth),
 		validation.Field(&o.OTP),
 		validation.Field(&o.MFA),
 		validation.Field(&o.AuthToken),
@@ -192,6 +230,9 @@ func (o *collectionAuthOptions) validate(cv *collectionValidator) error {
 		if o.OTP.Enabled {
 			authsEnabled++
 		}
+		if o.LDAPAuth.Enabled {
+			authsEnabled++
+		}
 		if authsEnabled < 2 {
 			return validation.Errors{
 				"mfa": validation.Errors{
@@ -219,6 +260,18 @@ func (o *collectionAuthOptions) validate(cv *collectionValidator) error {
 		}
 	}
 
+	// extra check to ensure that only existing non-system auth fields are required for completion
+	if o.ProfileCompletion.Enabled {
+		err = validation.Validate(o.ProfileCompletion.Fields, validation.By(cv.checkProfileCompletionFields))
+		if err != nil {
+			return validation.Errors{
+				"profileCompletion": validation.Errors{
+					"fields": err,
+				},
+			}
+		}
+	}
+
 	// extra check to ensure that only unique identity fields are used
 	if o.PasswordAuth.Enabled {
 		err = validation.Validate(o.PasswordAuth.IdentityFields, validation.By(cv.checkFieldsForUniqueIndex))
@@ -286,6 +339,77 @@ func (c AuthAlertConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+type EmailChangeConfig struct {
+	// RequireOldEmailApproval requires the email change request to be
+	// approved from the current (old) email address before sending
+	// the confirmation email to the new address.
+	RequireOldEmailApproval bool `form:"requireOldEmailApproval" json:"requireOldEmailApproval"`
+
+	// NotifyOldEmail specifies whether to send a notification
+	// to the old email address after a successful email change.
+	NotifyOldEmail bool `form:"notifyOldEmail" json:"notifyOldEmail"`
+
+	// ApprovalTemplate is the email template sent to the old email address
+	// when RequireOldEmailApproval is enabled.
+	//
+	// Fallbacks to the default template if empty.
+	ApprovalTemplate EmailTemplate `form:"approvalTemplate" json:"approvalTemplate"`
+
+	// NotifyTemplate is the email template sent to the old email address
+	// when NotifyOldEmail is enabled.
+	//
+	// Fallbacks to the default template if empty.
+	NotifyTemplate EmailTemplate `form:"notifyTemplate" json:"notifyTemplate"`
+}
+
+// Validate makes EmailChangeConfig validatable by implementing [validation.Validatable] interface.
+func (c EmailChangeConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.ApprovalTemplate, validation.Skip.When(c.ApprovalTemplate == EmailTemplate{})),
+		validation.Field(&c.NotifyTemplate, validation.Skip.When(c.NotifyTemplate == EmailTemplate{})),
+	)
+}
+
+// ApprovalTemplateOrDefault returns the configured ApprovalTemplate
+// or the default one if empty.
+func (c EmailChangeConfig) ApprovalTemplateOrDefault() EmailTemplate {
+	if c.ApprovalTemplate == (EmailTemplate{}) {
+		return defaultEmailChangeApprovalTemplate
+	}
+	return c.ApprovalTemplate
+}
+
+// NotifyTemplateOrDefault returns the configured NotifyTemplate
+// or the default one if empty.
+func (c EmailChangeConfig) NotifyTemplateOrDefault() EmailTemplate {
+	if c.NotifyTemplate == (EmailTemplate{}) {
+		return defaultEmailChangeNotifyTemplate
+	}
+	return c.NotifyTemplate
+}
+
+// -------------------------------------------------------------------
+
+type ProfileCompletionConfig struct {
+	// Enabled marks the auth tokens of the records with missing
+	// profile fields and restricts their API access until completed
+	// (only the own record view/update and the auth refresh are allowed).
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Fields is a list with the auth record field names that are
+	// required to be non-empty after signup.
+	Fields []string `form:"fields" json:"fields"`
+}
+
+// Validate makes ProfileCompletionConfig validatable by implementing [validation.Validatable] interface.
+func (c ProfileCompletionConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Fields, validation.When(c.Enabled, validation.Required)),
+	)
+}
+
+// -------------------------------------------------------------------
+
 type TokenConfig struct {
 	Secret string `form:"secret" json:"secret,omitempty"`
 
@@ -394,11 +518,150 @@ func (c PasswordAuthConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+type LDAPAuthConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// URL is the LDAP server address in the format "ldap://host:port" or "ldaps://host:port".
+	URL string `form:"url" json:"url"`
+
+	// StartTLS specifies whether to upgrade the plain "ldap://" connection with StartTLS.
+	StartTLS bool `form:"startTLS" json:"startTLS"`
+
+	// SkipVerify disables the server TLS certificate verification
+	// (should be used only for testing or with self-signed certificates).
+	SkipVerify bool `form:"skipVerify" json:"skipVerify"`
+
+	// BindDN and BindPassword are the optional service account
+	// credentials used for the user search.
+	//
+	// Leave BindDN empty to perform an anonymous search.
+	BindDN       string `form:"bindDN" json:"bindDN"`
+	BindPassword string `form:"bindPassword" json:"bindPassword,omitempty"`
+
+	// BaseDN is the directory subtree where the users are searched (eg. "ou=users,dc=example,dc=com").
+	BaseDN string `form:"baseDN" json:"baseDN"`
+
+	// UserFilter is the LDAP search filter used to locate the user entry
+	// (eg. "(sAMAccountName={username})").
+	//
+	// The [ldap.UsernamePlaceholder] is replaced with the escaped submitted username.
+	UserFilter string `form:"userFilter" json:"userFilter"`
+
+	// IdAttribute is an optional LDAP attribute with a stable unique
+	// user identifier (eg. "objectGUID", "entryUUID").
+	//
+	// Leave it empty to identify the users by their DN.
+	IdAttribute string `form:"idAttribute" json:"idAttribute"`
+
+	// EmailAttribute is the LDAP attribute holding the user email address (default to "mail").
+	EmailAttribute string `form:"emailAttribute" json:"emailAttribute"`
+
+	// MappedFields is an optional map with the auth record field names
+	// and the LDAP attributes whose values to sync on each login
+	// (eg. {"name": "displayName"}).
+	MappedFields map[string]string `form:"mappedFields" json:"mappedFields"`
+}
+
+// Validate makes LDAPAuthConfig validatable by implementing [validation.Validatable] interface.
+func (c LDAPAuthConfig) Validate() error {
+	if !c.Enabled {
+		return nil // no need to validate
+	}
+
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.URL, validation.Required, validation.By(checkLDAPURL)),
+		validation.Field(&c.BindPassword, validation.When(c.BindDN != "", validation.Required)),
+		validation.Field(&c.BaseDN, validation.Required),
+		validation.Field(&c.UserFilter, validation.Required, validation.By(checkLDAPUserFilter)),
+		validation.Field(&c.MappedFields, validation.By(checkLDAPMappedFields)),
+	)
+}
+
+// Config returns the current config as [ldap.Config].
+func (c LDAPAuthConfig) Config() ldap.Config {
+	attributes := []string{c.IdAttribute, c.EmailAttribute}
+	for _, attr := range c.MappedFields {
+		attributes = append(attributes, attr)
+	}
+
+	return ldap.Config{
+		URL:          c.URL,
+		StartTLS:     c.StartTLS,
+		SkipVerify:   c.SkipVerify,
+		BindDN:       c.BindDN,
+		BindPassword: c.BindPassword,
+		BaseDN:       c.BaseDN,
+		UserFilter:   c.UserFilter,
+		Attributes:   list.ToUniqueStringSlice(attributes),
+	}
+}
+
+// EntryId returns the unique identifier of the specified LDAP entry,
+// aka. the IdAttribute value or the entry DN if IdAttribute is not set.
+func (c LDAPAuthConfig) EntryId(entry *ldap.Entry) string {
+	if c.IdAttribute == "" {
+		return entry.DN
+	}
+
+	return entry.GetAttributeValue(c.IdAttribute)
+}
+
+func checkLDAPURL(value any) error {
+	v, _ := value.(string)
+
+	if !strings.HasPrefix(v, "ldap://") && !strings.HasPrefix(v, "ldaps://") {
+		return validation.NewError("validation_invalid_ldap_url", "Must be a valid ldap:// or ldaps:// url.")
+	}
+
+	return nil
+}
+
+func checkLDAPUserFilter(value any) error {
+	v, _ := value.(string)
+
+	if !strings.Contains(v, ldap.UsernamePlaceholder) {
+		return validation.NewError("validation_missing_username_placeholder", "The filter must contain the {{.placeholder}} placeholder.").
+			SetParams(map[string]any{"placeholder": ldap.UsernamePlaceholder})
+	}
+
+	if _, err := ldap.CompileFilter(strings.ReplaceAll(v, ldap.UsernamePlaceholder, "test")); err != nil {
+		return validation.NewError("validation_invalid_ldap_filter", "Invalid LDAP filter - {{.err}}.").
+			SetParams(map[string]any{"err": err.Error()})
+	}
+
+	return nil
+}
+
+func checkLDAPMappedFields(value any) error {
+	fields, _ := value.(map[string]string)
+
+	for name, attr := range fields {
+		if name == "" || attr == "" {
+			return validation.NewError("validation_invalid_ldap_mapping", "The field names and LDAP attributes must be non-empty.")
+		}
+
+		if err := checkClaimsMappingFieldName(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
 type OAuth2KnownFields struct {
 	Id        string `form:"id" json:"id"`
 	Name      string `form:"name" json:"name"`
 	Username  string `form:"username" json:"username"`
 	AvatarURL string `form:"avatarURL" json:"avatarURL"`
+
+	// Groups is the name of the field in which to store (and sync on each auth)
+	// the OAuth2 user group memberships (e.g. GitLab with "fetchGroups" enabled).
+	//
+	// For select fields only the groups matching the field values are stored
+	// and for text fields the groups are stored as comma separated string.
+	Groups string `form:"groups" json:"groups"`
 }
 
 type OAuth2Config struct {
@@ -472,6 +735,20 @@ type OAuth2ProviderConfig struct {
 	UserInfoURL  string         `form:"userInfoURL" json:"userInfoURL"`
 	DisplayName  string         `form:"displayName" json:"displayName"`
 	Extra        map[string]any `form:"extra" json:"extra"`
+
+	// DeviceAuthURL overwrites the default provider device authorization
+	// endpoint used by the OAuth2 device authorization grant (RFC 8628).
+	DeviceAuthURL string `form:"deviceAuthURL" json:"deviceAuthURL"`
+
+	// HTTPClient is an optional custom http client configuration
+	// of the provider requests (e.g. proxy, CA certificates, timeout).
+	//
+	// The non-empty fields overwrite the app level Settings.OAuth2HTTPClient ones.
+	HTTPClient *auth.HTTPClientConfig `form:"httpClient" json:"httpClient,omitempty"`
+
+	// ClaimsMapping is an optional configuration for mapping the OAuth2 user
+	// claims and groups to the auth record fields (synced on each login).
+	ClaimsMapping *OAuth2ClaimsMapping `form:"claimsMapping" json:"claimsMapping,omitempty"`
 }
 
 // Validate makes OAuth2ProviderConfig validatable by implementing [validation.Validatable] interface.
@@ -483,6 +760,9 @@ func (c OAuth2ProviderConfig) Validate() error {
 		validation.Field(&c.AuthURL, is.URL),
 		validation.Field(&c.TokenURL, is.URL),
 		validation.Field(&c.UserInfoURL, is.URL),
+		validation.Field(&c.DeviceAuthURL, is.URL),
+		validation.Field(&c.HTTPClient),
+		validation.Field(&c.ClaimsMapping),
 	)
 }
 
@@ -527,6 +807,10 @@ func (c OAuth2ProviderConfig) InitProvider() (auth.Provider, error) {
 		provider.SetTokenURL(c.TokenURL)
 	}
 
+	if c.DeviceAuthURL != "" {
+		provider.SetDeviceAuthURL(c.DeviceAuthURL)
+	}
+
 	if c.DisplayName != "" {
 		provider.SetDisplayName(c.DisplayName)
 	}
@@ -539,5 +823,181 @@ func (c OAuth2ProviderConfig) InitProvider() (auth.Provider, error) {
 		provider.SetExtra(c.Extra)
 	}
 
+	if c.HTTPClient != nil && !c.HTTPClient.IsZero() {
+		client, err := c.HTTPClient.NewClient()
+		if err != nil {
+			return nil, err
+		}
+		provider.SetHTTPClient(client)
+	}
+
 	return provider, nil
 }
+
+// InitProviderWithDefaults is similar to [OAuth2ProviderConfig.InitProvider]
+// but additionally applies the specified default http client configuration
+// (the non-empty fields of [OAuth2ProviderConfig.HTTPClient] have precedence).
+func (c OAuth2ProviderConfig) InitProviderWithDefaults(defaultHTTPClient auth.HTTPClientConfig) (auth.Provider, error) {
+	if !defaultHTTPClient.IsZero() {
+		merged := defaultHTTPClient
+		if c.HTTPClient != nil {
+			merged = merged.Merge(*c.HTTPClient)
+		}
+		c.HTTPClient = &merged
+	}
+
+	return c.InitProvider()
+}
+
+// -------------------------------------------------------------------
+
+// ErrOAuth2GroupsNotAllowed is returned when the OAuth2 user
+// is not a member of any of the [OAuth2ClaimsMapping.AllowedGroups].
+var ErrOAuth2GroupsNotAllowed = errors.New("the OAuth2 user is not a member of any of the allowed groups")
+
+// OAuth2ClaimsMapping defines the rules for mapping the OAuth2 user claims
+// and group memberships to the auth record fields.
+type OAuth2ClaimsMapping struct {
+	// Fields maps auth record field names to dot-notation raw OAuth2 user claim paths
+	// (e.g. {"department": "ocs.data.department", "locale": "locale"}).
+	Fields map[string]string `form:"fields" json:"fields"`
+
+	// GroupsClaim is an optional dot-notation raw OAuth2 user claim path
+	// from which to load the user groups (e.g. "groups" or "realm_access.roles").
+	//
+	// If not set, the groups resolved by the provider are used (if any).
+	GroupsClaim string `form:"groupsClaim" json:"groupsClaim"`
+
+	// AllowedGroups restricts the OAuth2 login only to the users that
+	// are member of at least one of the listed groups (if non-empty).
+	AllowedGroups []string `form:"allowedGroups" json:"allowedGroups"`
+
+	// RoleField is the name of the auth record field in which to store the mapped Roles.
+	RoleField string `form:"roleField" json:"roleField"`
+
+	// Roles is an ordered list with the roles and the groups granting them.
+	//
+	// For single value RoleField only the first matching role is stored.
+	Roles []OAuth2RoleMapping `form:"roles" json:"roles"`
+
+	// DefaultRole is an optional role to assign when none of the Roles groups match.
+	DefaultRole string `form:"defaultRole" json:"defaultRole"`
+}
+
+// Validate makes OAuth2ClaimsMapping validatable by implementing [validation.Validatable] interface.
+func (m OAuth2ClaimsMapping) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Fields, validation.By(checkClaimsMappingFields)),
+		validation.Field(
+			&m.RoleField,
+			validation.When(len(m.Roles) > 0 || m.DefaultRole != "", validation.Required),
+			validation.By(checkClaimsMappingFieldName),
+		),
+		validation.Field(&m.Roles),
+	)
+}
+
+// claimsMappingForbiddenFields are the auth record fields
+// that are managed by the OAuth2 flow and can't be mapped.
+var claimsMappingForbiddenFields = []string{
+	FieldNameId,
+	FieldNameEmail,
+	FieldNameEmailVisibility,
+	FieldNameVerified,
+	FieldNameTokenKey,
+	FieldNamePassword,
+}
+
+func checkClaimsMappingFields(value any) error {
+	fields, _ := value.(map[string]string)
+
+	for name, path := range fields {
+		if name == "" || path == "" {
+			return validation.NewError("validation_invalid_claims_mapping", "The field names and claim paths must be non-empty.")
+		}
+
+		if err := checkClaimsMappingFieldName(name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func checkClaimsMappingFieldName(value any) error {
+	name, _ := value.(string)
+
+	if slices.Contains(claimsMappingForbiddenFields, name) {
+		return validation.NewError("validation_forbidden_claims_mapping_field", "The {{.name}} field can't be mapped.").
+			SetParams(map[string]any{"name": name})
+	}
+
+	return nil
+}
+
+// Apply applies the claims mapping to the specified OAuth2 user.
+//
+// It loads the user groups from the GroupsClaim (if set), checks the
+// AllowedGroups gate and assigns the mapped field values to authUser.Claims.
+//
+// Returns [ErrOAuth2GroupsNotAllowed] if the user doesn't satisfy the AllowedGroups.
+func (m OAuth2ClaimsMapping) Apply(authUser *auth.AuthUser) error {
+	if m.GroupsClaim != "" {
+		// missing claim is treated as no group memberships
+		raw, _ := authUser.RawClaim(m.GroupsClaim)
+		authUser.Groups = list.ToUniqueStringSlice(raw)
+	}
+
+	isMember := func(group string) bool {
+		return slices.Contains(authUser.Groups, group)
+	}
+
+	if len(m.AllowedGroups) > 0 && !slices.ContainsFunc(m.AllowedGroups, isMember) {
+		return ErrOAuth2GroupsNotAllowed
+	}
+
+	if authUser.Claims == nil {
+		authUser.Claims = map[string]any{}
+	}
+
+	for name, path := range m.Fields {
+		if slices.Contains(claimsMappingForbiddenFields, name) {
+			continue // extra check in case the mapping wasn't validated
+		}
+
+		if v, ok := authUser.RawClaim(path); ok {
+			authUser.Claims[name] = v
+		}
+	}
+
+	if m.RoleField != "" && !slices.Contains(claimsMappingForbiddenFields, m.RoleField) {
+		roles := []string{}
+		for _, r := range m.Roles {
+			if !slices.Contains(roles, r.Role) && slices.ContainsFunc(r.Groups, isMember) {
+				roles = append(roles, r.Role)
+			}
+		}
+
+		if len(roles) == 0 && m.DefaultRole != "" {
+			roles = append(roles, m.DefaultRole)
+		}
+
+		authUser.Claims[m.RoleField] = roles
+	}
+
+	return nil
+}
+
+// OAuth2RoleMapping defines a single role and the OAuth2 user groups granting it.
+type OAuth2RoleMapping struct {
+	Role   string   `form:"role" json:"role"`
+	Groups []string `form:"groups" json:"groups"`
+}
+
+// Validate makes OAuth2RoleMapping validatable by implementing [validation.Validatable] interface.
+func (m OAuth2RoleMapping) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(&m.Role, validation.Required),
+		validation.Field(&m.Groups, validation.Required),
+	)
+}
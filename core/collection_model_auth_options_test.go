@@ -3,6 +3,7 @@ package core_test
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 	"testing"
@@ -11,6 +12,7 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/auth"
+	"github.com/pocketbase/pocketbase/tools/ldap"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
@@ -168,6 +170,38 @@ func TestCollectionAuthOptionsValidate(t *testing.T) {
 			expectedErrors: []string{"otp"},
 		},
 
+		// ldapAuth
+		{
+			name: "trigger ldapAuth validations",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.LDAPAuth = core.LDAPAuthConfig{
+					Enabled: true,
+					URL:     "http://example.com",
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"ldapAuth"},
+		},
+		{
+			name: "mfa enabled with password and ldap auth methods",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.MFA.Enabled = true
+				c.PasswordAuth.Enabled = true
+				c.OTP.Enabled = false
+				c.OAuth2.Enabled = false
+				c.LDAPAuth = core.LDAPAuthConfig{
+					Enabled:    true,
+					URL:        "ldap://example.com",
+					BaseDN:     "dc=example,dc=com",
+					UserFilter: "(uid={username})",
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+
 		// mfa
 		{
 			name: "trigger mfa validations",
@@ -289,6 +323,57 @@ func TestCollectionAuthOptionsValidate(t *testing.T) {
 			expectedErrors: []string{"fileToken"},
 		},
 
+		// profileCompletion
+		{
+			name: "profileCompletion disabled with missing fields",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.ProfileCompletion.Fields = []string{"missing"}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "profileCompletion enabled with no fields",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.ProfileCompletion.Enabled = true
+				return c, nil
+			},
+			expectedErrors: []string{"profileCompletion"},
+		},
+		{
+			name: "profileCompletion enabled with missing field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.ProfileCompletion.Enabled = true
+				c.ProfileCompletion.Fields = []string{"email", "missing"}
+				return c, nil
+			},
+			expectedErrors: []string{"profileCompletion"},
+		},
+		{
+			name: "profileCompletion enabled with forbidden system field",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.ProfileCompletion.Enabled = true
+				c.ProfileCompletion.Fields = []string{"password"}
+				return c, nil
+			},
+			expectedErrors: []string{"profileCompletion"},
+		},
+		{
+			name: "profileCompletion enabled with valid fields",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewAuthCollection("new_auth")
+				c.Fields.Add(&core.TextField{Name: "name"})
+				c.ProfileCompletion.Enabled = true
+				c.ProfileCompletion.Fields = []string{"email", "name"}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+
 		// templates
 		{
 			name: "trigger verificationTemplate validations",
@@ -516,6 +601,78 @@ func TestAuthAlertConfigValidate(t *testing.T) {
 	}
 }
 
+func TestEmailChangeConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.EmailChangeConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.EmailChangeConfig{},
+			[]string{},
+		},
+		{
+			"zero value (enabled)",
+			core.EmailChangeConfig{RequireOldEmailApproval: true, NotifyOldEmail: true},
+			[]string{},
+		},
+		{
+			"invalid templates",
+			core.EmailChangeConfig{
+				ApprovalTemplate: core.EmailTemplate{Body: "", Subject: "b"},
+				NotifyTemplate:   core.EmailTemplate{Body: "a", Subject: ""},
+			},
+			[]string{"approvalTemplate", "notifyTemplate"},
+		},
+		{
+			"valid data",
+			core.EmailChangeConfig{
+				RequireOldEmailApproval: true,
+				NotifyOldEmail:          true,
+				ApprovalTemplate:        core.EmailTemplate{Body: "a", Subject: "b"},
+				NotifyTemplate:          core.EmailTemplate{Body: "c", Subject: "d"},
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestEmailChangeConfigTemplatesOrDefault(t *testing.T) {
+	defaults := core.NewAuthCollection("test").EmailChange
+
+	empty := core.EmailChangeConfig{}
+
+	if v := empty.ApprovalTemplateOrDefault(); v != defaults.ApprovalTemplate {
+		t.Fatalf("Expected the default approval template, got %v", v)
+	}
+
+	if v := empty.NotifyTemplateOrDefault(); v != defaults.NotifyTemplate {
+		t.Fatalf("Expected the default notify template, got %v", v)
+	}
+
+	custom := core.EmailChangeConfig{
+		ApprovalTemplate: core.EmailTemplate{Body: "a", Subject: "b"},
+		NotifyTemplate:   core.EmailTemplate{Body: "c", Subject: "d"},
+	}
+
+	if v := custom.ApprovalTemplateOrDefault(); v != custom.ApprovalTemplate {
+		t.Fatalf("Expected the custom approval template, got %v", v)
+	}
+
+	if v := custom.NotifyTemplateOrDefault(); v != custom.NotifyTemplate {
+		t.Fatalf("Expected the custom notify template, got %v", v)
+	}
+}
+
 func TestOTPConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		name           string
@@ -712,6 +869,106 @@ func TestPasswordAuthConfigValidate(t *testing.T) {
 	}
 }
 
+func TestLDAPAuthConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.LDAPAuthConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value (disabled)",
+			core.LDAPAuthConfig{},
+			[]string{},
+		},
+		{
+			"zero value (enabled)",
+			core.LDAPAuthConfig{Enabled: true},
+			[]string{"url", "baseDN", "userFilter"},
+		},
+		{
+			"invalid data",
+			core.LDAPAuthConfig{
+				Enabled:      true,
+				URL:          "https://example.com",
+				BindDN:       "cn=admin,dc=example,dc=com",
+				BaseDN:       "dc=example,dc=com",
+				UserFilter:   "(uid=test)",
+				MappedFields: map[string]string{"name": ""},
+			},
+			[]string{"url", "bindPassword", "userFilter", "mappedFields"},
+		},
+		{
+			"invalid filter syntax",
+			core.LDAPAuthConfig{
+				Enabled:    true,
+				URL:        "ldap://example.com",
+				BaseDN:     "dc=example,dc=com",
+				UserFilter: "(uid={username}",
+			},
+			[]string{"userFilter"},
+		},
+		{
+			"forbidden mapped field",
+			core.LDAPAuthConfig{
+				Enabled:      true,
+				URL:          "ldap://example.com",
+				BaseDN:       "dc=example,dc=com",
+				UserFilter:   "(uid={username})",
+				MappedFields: map[string]string{"verified": "cn"},
+			},
+			[]string{"mappedFields"},
+		},
+		{
+			"valid data",
+			core.LDAPAuthConfig{
+				Enabled:      true,
+				URL:          "ldaps://example.com:636",
+				BindDN:       "cn=admin,dc=example,dc=com",
+				BindPassword: "secret",
+				BaseDN:       "dc=example,dc=com",
+				UserFilter:   "(&(objectClass=person)(sAMAccountName={username}))",
+				MappedFields: map[string]string{"name": "displayName"},
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestLDAPAuthConfigEntryId(t *testing.T) {
+	entry := &ldap.Entry{
+		DN:         "uid=test,dc=example,dc=com",
+		Attributes: map[string][]string{"entryUUID": {"123"}},
+	}
+
+	scenarios := []struct {
+		idAttribute string
+		expected    string
+	}{
+		{"", "uid=test,dc=example,dc=com"},
+		{"entryuuid", "123"},
+		{"missing", ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.idAttribute, func(t *testing.T) {
+			config := core.LDAPAuthConfig{IdAttribute: s.idAttribute}
+
+			result := config.EntryId(entry)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
 func TestOAuth2ConfigGetProviderConfig(t *testing.T) {
 	scenarios := []struct {
 		name           string
@@ -853,8 +1110,10 @@ func TestOAuth2ProviderConfigValidate(t *testing.T) {
 				AuthURL:      "!invalid!",
 				TokenURL:     "!invalid!",
 				UserInfoURL:  "!invalid!",
+
+				DeviceAuthURL: "!invalid!",
 			},
-			[]string{"authURL", "tokenURL", "userInfoURL"},
+			[]string{"authURL", "tokenURL", "userInfoURL", "deviceAuthURL"},
 		},
 		{
 			"valid urls",
@@ -865,6 +1124,48 @@ func TestOAuth2ProviderConfigValidate(t *testing.T) {
 				AuthURL:      "https://example.com/a",
 				TokenURL:     "https://example.com/b",
 				UserInfoURL:  "https://example.com/c",
+
+				DeviceAuthURL: "https://example.com/d",
+			},
+			[]string{},
+		},
+		{
+			"invalid http client",
+			core.OAuth2ProviderConfig{
+				Name:         "gitlab",
+				ClientId:     "abc",
+				ClientSecret: "456",
+				HTTPClient:   &auth.HTTPClientConfig{ProxyURL: "ftp://example.com", Timeout: -1},
+			},
+			[]string{"httpClient"},
+		},
+		{
+			"valid http client",
+			core.OAuth2ProviderConfig{
+				Name:         "gitlab",
+				ClientId:     "abc",
+				ClientSecret: "456",
+				HTTPClient:   &auth.HTTPClientConfig{ProxyURL: "socks5://127.0.0.1:1080", Timeout: 10},
+			},
+			[]string{},
+		},
+		{
+			"invalid claims mapping",
+			core.OAuth2ProviderConfig{
+				Name:          "gitlab",
+				ClientId:      "abc",
+				ClientSecret:  "456",
+				ClaimsMapping: &core.OAuth2ClaimsMapping{DefaultRole: "test"},
+			},
+			[]string{"claimsMapping"},
+		},
+		{
+			"valid claims mapping",
+			core.OAuth2ProviderConfig{
+				Name:          "gitlab",
+				ClientId:      "abc",
+				ClientSecret:  "456",
+				ClaimsMapping: &core.OAuth2ClaimsMapping{RoleField: "role", DefaultRole: "test"},
 			},
 			[]string{},
 		},
@@ -930,6 +1231,8 @@ func TestOAuth2ProviderConfigInitProvider(t *testing.T) {
 				UserInfoURL:  "https://gitlab.com/api/v4/user",
 				DisplayName:  "GitLab",
 				PKCE:         types.Pointer(true),
+
+				DeviceAuthURL: "https://gitlab.com/oauth/authorize_device",
 			},
 			false,
 		},
@@ -945,6 +1248,8 @@ func TestOAuth2ProviderConfigInitProvider(t *testing.T) {
 				DisplayName:  "test_DisplayName",
 				PKCE:         types.Pointer(true),
 				Extra:        map[string]any{"a": 1},
+
+				DeviceAuthURL: "test_DeviceAuthURL",
 			},
 			core.OAuth2ProviderConfig{
 				Name:         "gitlab",
@@ -956,6 +1261,8 @@ func TestOAuth2ProviderConfigInitProvider(t *testing.T) {
 				DisplayName:  "test_DisplayName",
 				PKCE:         types.Pointer(true),
 				Extra:        map[string]any{"a": 1},
+
+				DeviceAuthURL: "test_DeviceAuthURL",
 			},
 			false,
 		},
@@ -1008,6 +1315,10 @@ func TestOAuth2ProviderConfigInitProvider(t *testing.T) {
 				t.Fatalf("Expected TokenURL %q, got %q", s.expectedConfig.TokenURL, provider.TokenURL())
 			}
 
+			if provider.DeviceAuthURL() != s.expectedConfig.DeviceAuthURL {
+				t.Fatalf("Expected DeviceAuthURL %q, got %q", s.expectedConfig.DeviceAuthURL, provider.DeviceAuthURL())
+			}
+
 			if provider.DisplayName() != s.expectedConfig.DisplayName {
 				t.Fatalf("Expected DisplayName %q, got %q", s.expectedConfig.DisplayName, provider.DisplayName())
 			}
@@ -1024,3 +1335,262 @@ func TestOAuth2ProviderConfigInitProvider(t *testing.T) {
 		})
 	}
 }
+
+func TestOAuth2ProviderConfigInitProviderWithDefaults(t *testing.T) {
+	scenarios := []struct {
+		name            string
+		config          core.OAuth2ProviderConfig
+		defaults        auth.HTTPClientConfig
+		expectedClient  bool
+		expectedTimeout time.Duration
+	}{
+		{
+			"no http client configs",
+			core.OAuth2ProviderConfig{Name: "gitlab"},
+			auth.HTTPClientConfig{},
+			false,
+			0,
+		},
+		{
+			"only provider http client config",
+			core.OAuth2ProviderConfig{Name: "gitlab", HTTPClient: &auth.HTTPClientConfig{Timeout: 5}},
+			auth.HTTPClientConfig{},
+			true,
+			5 * time.Second,
+		},
+		{
+			"only default http client config",
+			core.OAuth2ProviderConfig{Name: "gitlab"},
+			auth.HTTPClientConfig{Timeout: 10},
+			true,
+			10 * time.Second,
+		},
+		{
+			"provider http client config with precedence",
+			core.OAuth2ProviderConfig{Name: "gitlab", HTTPClient: &auth.HTTPClientConfig{Timeout: 5}},
+			auth.HTTPClientConfig{Timeout: 10, ProxyURL: "http://127.0.0.1:3128"},
+			true,
+			5 * time.Second,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			provider, err := s.config.InitProviderWithDefaults(s.defaults)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			client := provider.HTTPClient()
+
+			if (client != nil) != s.expectedClient {
+				t.Fatalf("Expected http client %v, got %v", s.expectedClient, client)
+			}
+
+			if client != nil && client.Timeout != s.expectedTimeout {
+				t.Fatalf("Expected timeout %v, got %v", s.expectedTimeout, client.Timeout)
+			}
+		})
+	}
+}
+
+func TestOAuth2ClaimsMappingValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.OAuth2ClaimsMapping
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.OAuth2ClaimsMapping{},
+			[]string{},
+		},
+		{
+			"empty fields mapping",
+			core.OAuth2ClaimsMapping{Fields: map[string]string{"a": ""}},
+			[]string{"fields"},
+		},
+		{
+			"forbidden fields mapping",
+			core.OAuth2ClaimsMapping{Fields: map[string]string{"verified": "email_verified"}},
+			[]string{"fields"},
+		},
+		{
+			"roles without role field",
+			core.OAuth2ClaimsMapping{Roles: []core.OAuth2RoleMapping{{Role: "admin", Groups: []string{"a"}}}},
+			[]string{"roleField"},
+		},
+		{
+			"forbidden role field",
+			core.OAuth2ClaimsMapping{RoleField: "password"},
+			[]string{"roleField"},
+		},
+		{
+			"invalid roles",
+			core.OAuth2ClaimsMapping{RoleField: "role", Roles: []core.OAuth2RoleMapping{{}}},
+			[]string{"roles"},
+		},
+		{
+			"valid data",
+			core.OAuth2ClaimsMapping{
+				Fields:        map[string]string{"locale": "locale"},
+				GroupsClaim:   "groups",
+				AllowedGroups: []string{"a"},
+				RoleField:     "role",
+				Roles:         []core.OAuth2RoleMapping{{Role: "admin", Groups: []string{"a"}}},
+				DefaultRole:   "user",
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestOAuth2ClaimsMappingApply(t *testing.T) {
+	newAuthUser := func() *auth.AuthUser {
+		return &auth.AuthUser{
+			Groups: []string{"provider_group"},
+			RawUser: map[string]any{
+				"locale": "en",
+				"realm_access": map[string]any{
+					"roles": []any{"editors", "staff"},
+				},
+			},
+		}
+	}
+
+	scenarios := []struct {
+		name           string
+		mapping        core.OAuth2ClaimsMapping
+		expectError    bool
+		expectedGroups []string
+		expectedClaims string
+	}{
+		{
+			"zero value",
+			core.OAuth2ClaimsMapping{},
+			false,
+			[]string{"provider_group"},
+			`{}`,
+		},
+		{
+			"groups claim",
+			core.OAuth2ClaimsMapping{GroupsClaim: "realm_access.roles"},
+			false,
+			[]string{"editors", "staff"},
+			`{}`,
+		},
+		{
+			"missing groups claim",
+			core.OAuth2ClaimsMapping{GroupsClaim: "missing"},
+			false,
+			[]string{},
+			`{}`,
+		},
+		{
+			"unsatisfied allowed groups",
+			core.OAuth2ClaimsMapping{GroupsClaim: "realm_access.roles", AllowedGroups: []string{"provider_group"}},
+			true,
+			nil,
+			``,
+		},
+		{
+			"satisfied allowed groups",
+			core.OAuth2ClaimsMapping{AllowedGroups: []string{"a", "provider_group"}},
+			false,
+			[]string{"provider_group"},
+			`{}`,
+		},
+		{
+			"fields mapping",
+			core.OAuth2ClaimsMapping{
+				Fields: map[string]string{
+					"a":        "locale",
+					"b":        "realm_access.roles.1",
+					"c":        "missing",
+					"verified": "locale", // forbidden
+				},
+			},
+			false,
+			[]string{"provider_group"},
+			`{"a":"en","b":"staff"}`,
+		},
+		{
+			"roles mapping",
+			core.OAuth2ClaimsMapping{
+				GroupsClaim: "realm_access.roles",
+				RoleField:   "role",
+				Roles: []core.OAuth2RoleMapping{
+					{Role: "admin", Groups: []string{"admins"}},
+					{Role: "editor", Groups: []string{"staff", "editors"}},
+					{Role: "viewer", Groups: []string{"staff"}},
+				},
+				DefaultRole: "guest",
+			},
+			false,
+			[]string{"editors", "staff"},
+			`{"role":["editor","viewer"]}`,
+		},
+		{
+			"default role",
+			core.OAuth2ClaimsMapping{
+				RoleField:   "role",
+				Roles:       []core.OAuth2RoleMapping{{Role: "admin", Groups: []string{"admins"}}},
+				DefaultRole: "guest",
+			},
+			false,
+			[]string{"provider_group"},
+			`{"role":["guest"]}`,
+		},
+		{
+			"no matching roles and no default role",
+			core.OAuth2ClaimsMapping{
+				RoleField: "role",
+				Roles:     []core.OAuth2RoleMapping{{Role: "admin", Groups: []string{"admins"}}},
+			},
+			false,
+			[]string{"provider_group"},
+			`{"role":[]}`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user := newAuthUser()
+
+			err := s.mapping.Apply(user)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				if !errors.Is(err, core.ErrOAuth2GroupsNotAllowed) {
+					t.Fatalf("Expected ErrOAuth2GroupsNotAllowed, got %v", err)
+				}
+				return
+			}
+
+			if strings.Join(user.Groups, ",") != strings.Join(s.expectedGroups, ",") {
+				t.Fatalf("Expected groups %v, got %v", s.expectedGroups, user.Groups)
+			}
+
+			rawClaims, err := json.Marshal(user.Claims)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if str := string(rawClaims); str != s.expectedClaims {
+				t.Fatalf("Expected claims\n%s\ngot\n%s", s.expectedClaims, str)
+			}
+		})
+	}
+}
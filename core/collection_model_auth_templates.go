@@ -2,11 +2,17 @@ package core
 
 // Common settings placeholder tokens
 const (
-	EmailPlaceholderAppName string = "{APP_NAME}"
-	EmailPlaceholderAppURL  string = "{APP_URL}"
-	EmailPlaceholderToken   string = "{TOKEN}"
-	EmailPlaceholderOTP     string = "{OTP}"
-	EmailPlaceholderOTPId   string = "{OTP_ID}"
+	EmailPlaceholderAppName  string = "{APP_NAME}"
+	EmailPlaceholderAppURL   string = "{APP_URL}"
+	EmailPlaceholderToken    string = "{TOKEN}"
+	EmailPlaceholderOTP      string = "{OTP}"
+	EmailPlaceholderOTPId    string = "{OTP_ID}"
+	EmailPlaceholderOldEmail string = "{OLD_EMAIL}"
+	EmailPlaceholderNewEmail string = "{NEW_EMAIL}"
+
+	EmailPlaceholderAlertIP        string = "{ALERT_IP}"
+	EmailPlaceholderAlertUserAgent string = "{ALERT_USER_AGENT}"
+	EmailPlaceholderAlertCountry   string = "{ALERT_COUNTRY}"
 )
 
 var defaultVerificationTemplate = EmailTemplate{
@@ -66,6 +72,10 @@ var defaultAuthAlertTemplate = EmailTemplate{
 	Subject: "Login from a new location",
 	Body: `<p>Hello,</p>
 <p>We noticed a login to your ` + EmailPlaceholderAppName + ` account from a new location.</p>
+<p>
+  IP address: ` + EmailPlaceholderAlertIP + `<br/>
+  Device: ` + EmailPlaceholderAlertUserAgent + `
+</p>
 <p>If this was you, you may disregard this email.</p>
 <p><strong>If this wasn't you, you should immediately change your ` + EmailPlaceholderAppName + ` account password to revoke access from all other locations.</strong></p>
 <p>
@@ -73,3 +83,30 @@ var defaultAuthAlertTemplate = EmailTemplate{
   ` + EmailPlaceholderAppName + ` team
 </p>`,
 }
+
+var defaultEmailChangeApprovalTemplate = EmailTemplate{
+	Subject: "Approve your " + EmailPlaceholderAppName + " email address change",
+	Body: `<p>Hello,</p>
+<p>We received a request to change your ` + EmailPlaceholderAppName + ` account email address to <strong>` + EmailPlaceholderNewEmail + `</strong>.</p>
+<p>Click on the button below to approve the change. After that a confirmation link will be sent to the new email address.</p>
+<p>
+  <a class="btn" href="` + EmailPlaceholderAppURL + "/_/#/auth/confirm-email-change/" + EmailPlaceholderToken + `" target="_blank" rel="noopener">Approve email change</a>
+</p>
+<p><strong>If you didn't ask to change your email address, you should immediately change your ` + EmailPlaceholderAppName + ` account password.</strong></p>
+<p>
+  Thanks,<br/>
+  ` + EmailPlaceholderAppName + ` team
+</p>`,
+}
+
+var defaultEmailChangeNotifyTemplate = EmailTemplate{
+	Subject: "Your " + EmailPlaceholderAppName + " email address was changed",
+	Body: `<p>Hello,</p>
+<p>The email address of your ` + EmailPlaceholderAppName + ` account was changed from <strong>` + EmailPlaceholderOldEmail + `</strong> to <strong>` + EmailPlaceholderNewEmail + `</strong>.</p>
+<p>If this was you, you may disregard this email.</p>
+<p><strong>If this wasn't you, please contact us as soon as possible to recover your account.</strong></p>
+<p>
+  Thanks,<br/>
+  ` + EmailPlaceholderAppName + ` team
+</p>`,
+}
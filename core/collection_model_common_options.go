@@ -0,0 +1,156 @@
+package core
+
+import (
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core/validators"
+)
+
+var _ optionsValidator = (*collectionCommonOptions)(nil)
+
+// collectionCommonOptions defines the options shared by all collection types.
+type collectionCommonOptions struct {
+	// ErrorMessages specifies custom field validation error messages that
+	// will be returned instead of the default generic ones.
+	//
+	// The map key is the field name and the value is a map with custom
+	// messages keyed by their validation error code, for example:
+	//
+	//	{
+	//		"title": {
+	//			"validation_required": "Please enter a title.",
+	//			"validation_max_text_constraint": "The title must be no more than {{.max}} characters.",
+	//		},
+	//		"slug": {
+	//			"validation_not_unique": "The slug is already taken.",
+	//		},
+	//	}
+	//
+	// The messages could contain the same template placeholders
+	// as the replaced error params (ex. "{{.max}}").
+	ErrorMessages map[string]map[string]string `form:"errorMessages" json:"errorMessages,omitempty" db:"-"`
+
+	// Policies specifies the named collection security policies that are
+	// evaluated in addition to the collection API rules (see [CollectionPolicy]).
+	Policies []*CollectionPolicy `form:"policies" json:"policies,omitempty" db:"-"`
+
+	// Calendar specifies the optional records iCalendar feed field mapping (see [CalendarConfig]).
+	Calendar *CalendarConfig `form:"calendar" json:"calendar,omitempty" db:"-"`
+
+	// Feed specifies the optional records RSS/Atom feed options (see [FeedConfig]).
+	Feed *FeedConfig `form:"feed" json:"feed,omitempty" db:"-"`
+
+	// Sitemap specifies the optional records sitemap entries options (see [SitemapConfig]).
+	Sitemap *SitemapConfig `form:"sitemap" json:"sitemap,omitempty" db:"-"`
+
+	// OGImage specifies the optional records Open Graph image options (see [OGImageConfig]).
+	OGImage *OGImageConfig `form:"ogImage" json:"ogImage,omitempty" db:"-"`
+
+	// Reactions specifies the optional records reactions options (see [ReactionsConfig]).
+	Reactions *ReactionsConfig `form:"reactions" json:"reactions,omitempty" db:"-"`
+
+	// Form specifies the optional public form submissions options (see [FormConfig]).
+	Form *FormConfig `form:"form" json:"form,omitempty" db:"-"`
+
+	// Search specifies the optional search-as-you-type index options (see [SearchConfig]).
+	Search *SearchConfig `form:"search" json:"search,omitempty" db:"-"`
+}
+
+func (o *collectionCommonOptions) validate(cv *collectionValidator) error {
+	return validation.ValidateStruct(o,
+		validation.Field(&o.ErrorMessages, validation.By(checkErrorMessages)),
+		validation.Field(&o.Policies, validation.By(checkCollectionPolicies(cv))),
+		validation.Field(&o.Calendar, validation.By(checkCalendarConfig(cv))),
+		validation.Field(&o.Feed, validation.By(checkFeedConfig(cv))),
+		validation.Field(&o.Sitemap, validation.By(checkSitemapConfig(cv))),
+		validation.Field(&o.OGImage, validation.By(checkOGImageConfig(cv))),
+		validation.Field(&o.Reactions, validation.By(checkReactionsConfig(cv))),
+		validation.Field(&o.Form, validation.By(checkFormConfig(cv))),
+		validation.Field(&o.Search, validation.By(checkSearchConfig(cv))),
+	)
+}
+
+func checkErrorMessages(value any) error {
+	v, ok := value.(map[string]map[string]string)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	errs := validation.Errors{}
+
+	for field, messages := range v {
+		fieldErrs := validation.Errors{}
+
+		for code, message := range messages {
+			if code == "" {
+				fieldErrs[code] = validation.NewError("validation_invalid_error_code", "The error code must not be empty.")
+				continue
+			}
+
+			err := validation.Validate(message, validation.Required, validation.Length(1, 500))
+			if err != nil {
+				fieldErrs[code] = err
+			}
+		}
+
+		if len(fieldErrs) > 0 {
+			errs[field] = fieldErrs
+		}
+	}
+
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+func (m *Collection) unsetMissingErrorMessagesFields() {
+	for name := range m.ErrorMessages {
+		if m.Fields.GetByName(name) == nil {
+			delete(m.ErrorMessages, name)
+		}
+	}
+}
+
+// applyErrorMessages replaces the field validation errors of the provided
+// error with their custom collection ErrorMessages counterpart (if any).
+//
+// Returns the original error if it is not [validation.Errors] or
+// there are no matching custom messages.
+func (m *Collection) applyErrorMessages(err error) error {
+	if len(m.ErrorMessages) == 0 {
+		return err
+	}
+
+	var errs validation.Errors
+	if !errors.As(err, &errs) {
+		return err
+	}
+
+	result := make(validation.Errors, len(errs))
+
+	for name, fieldErr := range errs {
+		result[name] = fieldErr
+
+		messages := m.ErrorMessages[name]
+		if len(messages) == 0 {
+			continue
+		}
+
+		vErr, ok := fieldErr.(validation.Error)
+		if !ok {
+			continue
+		}
+
+		message := messages[vErr.Code()]
+		if message == "" {
+			continue
+		}
+
+		result[name] = validation.NewError(vErr.Code(), message).SetParams(vErr.Params())
+	}
+
+	return result
+}
@@ -0,0 +1,875 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/sitemap"
+)
+
+func TestCollectionCommonOptionsValidate(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		collection     func(app core.App) (*core.Collection, error)
+		expectedErrors []string
+	}{
+		{
+			name: "nil error messages",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.ErrorMessages = nil
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "empty error message",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Fields.Add(&core.TextField{Name: "title"})
+				c.ErrorMessages = map[string]map[string]string{
+					"title": {"validation_required": ""},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"errorMessages"},
+		},
+		{
+			name: "empty error code",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Fields.Add(&core.TextField{Name: "title"})
+				c.ErrorMessages = map[string]map[string]string{
+					"title": {"": "test"},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"errorMessages"},
+		},
+		{
+			name: "valid error messages",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo1")
+				if err != nil {
+					return nil, err
+				}
+				c.ErrorMessages = map[string]map[string]string{
+					"text": {"validation_required": "test"},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "invalid policies",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Policies = []*core.CollectionPolicy{
+					{Name: "", Effect: "allow", Actions: []string{"list"}},
+					{Name: "a", Effect: "invalid", Actions: []string{"list"}},
+					{Name: "b", Effect: "deny", Actions: []string{"invalid"}},
+					{Name: "c", Effect: "deny", Actions: []string{"list"}, Rule: "missing = 1"},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"policies"},
+		},
+		{
+			name: "duplicated policy names",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Policies = []*core.CollectionPolicy{
+					{Name: "a", Effect: "allow", Actions: []string{"list"}},
+					{Name: "a", Effect: "deny", Actions: []string{"view"}},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"policies"},
+		},
+		{
+			name: "valid policies",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					return nil, err
+				}
+				c.Policies = []*core.CollectionPolicy{
+					{Name: "a", Effect: "deny", Actions: []string{"list", "view"}, Rule: "active = false"},
+					{Name: "b", Effect: "allow", Actions: []string{"list", "view", "create", "update", "delete"}, Priority: 10},
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "empty calendar config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Calendar = &core.CalendarConfig{}
+				return c, nil
+			},
+			expectedErrors: []string{"calendar"},
+		},
+		{
+			name: "invalid calendar fields",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					return nil, err
+				}
+				c.Calendar = &core.CalendarConfig{
+					StartField:       "title",
+					EndField:         "missing",
+					TitleField:       "missing",
+					DescriptionField: "missing",
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"calendar"},
+		},
+		{
+			name: "valid calendar config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					return nil, err
+				}
+				c.Calendar = &core.CalendarConfig{
+					StartField: "created",
+					EndField:   "updated",
+					TitleField: "title",
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "empty feed config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Feed = &core.FeedConfig{}
+				return c, nil
+			},
+			expectedErrors: []string{"feed"},
+		},
+		{
+			name: "invalid feed config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					return nil, err
+				}
+				c.Feed = &core.FeedConfig{
+					Link:         "invalid",
+					TitleField:   "title",
+					DateField:    "title",
+					LinkTemplate: "https://example.com/{missing}",
+					MaxItems:     501,
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"feed"},
+		},
+		{
+			name: "valid feed config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					return nil, err
+				}
+				c.Feed = &core.FeedConfig{
+					Link:         "https://example.com",
+					TitleField:   "title",
+					DateField:    "created",
+					LinkTemplate: "https://example.com/{id}",
+					CacheMaxAge:  60,
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "empty sitemap config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Sitemap = &core.SitemapConfig{}
+				return c, nil
+			},
+			expectedErrors: []string{"sitemap"},
+		},
+		{
+			name: "invalid sitemap config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					return nil, err
+				}
+				c.Sitemap = &core.SitemapConfig{
+					URLTemplate:  "https://example.com/{missing}",
+					Filter:       "missing = 1",
+					LastmodField: "title",
+					ChangeFreq:   "invalid",
+					Priority:     1.1,
+					PageSize:     50001,
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"sitemap"},
+		},
+		{
+			name: "valid sitemap config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo2")
+				if err != nil {
+					return nil, err
+				}
+				c.Sitemap = &core.SitemapConfig{
+					URLTemplate:  "https://example.com/{id}",
+					Filter:       "active = true",
+					LastmodField: "created",
+					ChangeFreq:   "weekly",
+					Priority:     0.5,
+					PageSize:     100,
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "empty Open Graph image config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.OGImage = &core.OGImageConfig{}
+				return c, nil
+			},
+			expectedErrors: []string{"ogImage"},
+		},
+		{
+			name: "invalid Open Graph image config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo1")
+				if err != nil {
+					return nil, err
+				}
+				c.OGImage = &core.OGImageConfig{
+					TitleField:      "text",
+					BackgroundField: "file_one", // protected
+					BackgroundColor: "red",
+				}
+				return c, nil
+			},
+			expectedErrors: []string{"ogImage"},
+		},
+		{
+			name: "valid Open Graph image config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo1")
+				if err != nil {
+					return nil, err
+				}
+				c.OGImage = &core.OGImageConfig{
+					TitleField:      "text",
+					SubtitleField:   "id",
+					BackgroundField: "file_many",
+					BackgroundColor: "#000000",
+					TextColor:       "#FFFFFF",
+					CacheMaxAge:     3600,
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "empty reactions config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Reactions = &core.ReactionsConfig{}
+				return c, nil
+			},
+			expectedErrors: []string{"reactions"},
+		},
+		{
+			name: "reactions config for view collection",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("view1")
+				if err != nil {
+					return nil, err
+				}
+				c.Reactions = &core.ReactionsConfig{Types: []string{"like"}}
+				return c, nil
+			},
+			expectedErrors: []string{"reactions"},
+		},
+		{
+			name: "valid reactions config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo1")
+				if err != nil {
+					return nil, err
+				}
+				c.Reactions = &core.ReactionsConfig{
+					Types:       []string{"like", "thumbs_up"},
+					CountsField: "json",
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "empty form config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Form = &core.FormConfig{}
+				return c, nil
+			},
+			expectedErrors: []string{"form"},
+		},
+		{
+			name: "form config for auth collection",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("users")
+				if err != nil {
+					return nil, err
+				}
+				c.Form = &core.FormConfig{Fields: []string{"name"}}
+				return c, nil
+			},
+			expectedErrors: []string{"form"},
+		},
+		{
+			name: "valid form config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo1")
+				if err != nil {
+					return nil, err
+				}
+				c.Form = &core.FormConfig{
+					Fields:        []string{"text", "email", "bool"},
+					HoneypotField: "website",
+					Captcha:       true,
+					NotifyEmails:  []string{"test@example.com"},
+					MaxRequests:   10,
+					Duration:      60,
+				}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "empty search config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c := core.NewBaseCollection("new_base")
+				c.Search = &core.SearchConfig{}
+				return c, nil
+			},
+			expectedErrors: []string{"search"},
+		},
+		{
+			name: "search config for view collection",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("view1")
+				if err != nil {
+					return nil, err
+				}
+				c.Search = &core.SearchConfig{Fields: []string{"text"}}
+				return c, nil
+			},
+			expectedErrors: []string{"search"},
+		},
+		{
+			name: "valid search config",
+			collection: func(app core.App) (*core.Collection, error) {
+				c, err := app.FindCollectionByNameOrId("demo1")
+				if err != nil {
+					return nil, err
+				}
+				c.Search = &core.SearchConfig{Fields: []string{"text"}}
+				return c, nil
+			},
+			expectedErrors: []string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			collection, err := s.collection(app)
+			if err != nil {
+				t.Fatalf("Failed to retrieve test collection: %v", err)
+			}
+
+			result := app.Validate(collection)
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestCollectionErrorMessagesUnsetMissingFields(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_messages")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.ErrorMessages = map[string]map[string]string{
+		"title":   {"validation_required": "a"},
+		"missing": {"validation_required": "b"},
+	}
+
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	collection, err := app.FindCollectionByNameOrId(collection.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(collection.ErrorMessages) != 1 || collection.ErrorMessages["title"] == nil {
+		t.Fatalf("Expected only the title error messages to be persisted, got %v", collection.ErrorMessages)
+	}
+}
+
+func TestRecordValidateWithCustomErrorMessages(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("validate_messages_test")
+	collection.Fields.Add(
+		&core.TextField{Name: "f1", Min: 3},
+		&core.NumberField{Name: "f2", Required: true},
+		&core.TextField{Name: "f3"},
+	)
+	collection.AddIndex("idx_unique_f3", true, "f3", "")
+	collection.ErrorMessages = map[string]map[string]string{
+		"f1": {"validation_min_text_constraint": "custom min {{.min}}"},
+		"f3": {"validation_not_unique": "custom unique"},
+	}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("f1", "a")
+	record.Set("f3", "test")
+
+	err := app.Validate(record)
+
+	tests.TestValidationErrors(t, err, []string{"f1", "f2"})
+
+	var errs validation.Errors
+	errors.As(err, &errs)
+
+	if msg := errs["f1"].Error(); msg != "custom min 3" {
+		t.Fatalf("Expected f1 custom error message, got %q", msg)
+	}
+
+	if code := errs["f1"].(validation.Error).Code(); code != "validation_min_text_constraint" {
+		t.Fatalf("Expected the original f1 error code to be preserved, got %q", code)
+	}
+
+	if msg := errs["f2"].Error(); msg != "cannot be blank" {
+		t.Fatalf("Expected f2 default error message, got %q", msg)
+	}
+
+	// unique constraint
+	record.Set("f1", "abc")
+	record.Set("f2", 1)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	duplicated := core.NewRecord(collection)
+	duplicated.Set("f1", "abc")
+	duplicated.Set("f2", 1)
+	duplicated.Set("f3", "test")
+
+	err = app.Save(duplicated)
+
+	tests.TestValidationErrors(t, err, []string{"f3"})
+
+	errs = nil
+	errors.As(err, &errs)
+
+	if msg := errs["f3"].Error(); msg != "custom unique" {
+		t.Fatalf("Expected f3 custom error message, got %q", msg)
+	}
+}
+
+func TestCollectionCalendarConfigValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Calendar = &core.CalendarConfig{
+		StartField:       "title",
+		EndField:         "missing",
+		TitleField:       "missing",
+		DescriptionField: "title",
+	}
+
+	var errs validation.Errors
+	if !errors.As(app.Validate(collection), &errs) {
+		t.Fatal("Expected validation errors")
+	}
+
+	var calendarErrs validation.Errors
+	if !errors.As(errs["calendar"], &calendarErrs) {
+		t.Fatalf("Expected calendar validation errors, got %v", errs["calendar"])
+	}
+
+	tests.TestValidationErrors(t, calendarErrs, []string{"startField", "endField", "titleField"})
+}
+
+func TestCollectionFeedConfigValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Feed = &core.FeedConfig{
+		Link:         "invalid",
+		TitleField:   "missing",
+		DateField:    "title",
+		ContentField: "title",
+		LinkTemplate: "https://example.com/{id}/{missing}",
+		MaxItems:     501,
+		CacheMaxAge:  -1,
+	}
+
+	var errs validation.Errors
+	if !errors.As(app.Validate(collection), &errs) {
+		t.Fatal("Expected validation errors")
+	}
+
+	var feedErrs validation.Errors
+	if !errors.As(errs["feed"], &feedErrs) {
+		t.Fatalf("Expected feed validation errors, got %v", errs["feed"])
+	}
+
+	tests.TestValidationErrors(t, feedErrs, []string{"link", "titleField", "dateField", "linkTemplate", "maxItems", "cacheMaxAge"})
+}
+
+func TestFeedConfigLimit(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		maxItems int
+		expected int
+	}{
+		{-1, core.DefaultFeedMaxItems},
+		{0, core.DefaultFeedMaxItems},
+		{10, 10},
+	}
+
+	for _, s := range scenarios {
+		config := &core.FeedConfig{MaxItems: s.maxItems}
+		if v := config.Limit(); v != s.expected {
+			t.Fatalf("[%d] Expected %d, got %d", s.maxItems, s.expected, v)
+		}
+	}
+}
+
+func TestCollectionSitemapConfigValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Sitemap = &core.SitemapConfig{
+		URLTemplate:  "https://example.com/{id}/{missing}",
+		Filter:       "missing = 1",
+		LastmodField: "title",
+		ChangeFreq:   "invalid",
+		Priority:     -0.1,
+		PageSize:     50001,
+	}
+
+	var errs validation.Errors
+	if !errors.As(app.Validate(collection), &errs) {
+		t.Fatal("Expected validation errors")
+	}
+
+	var sitemapErrs validation.Errors
+	if !errors.As(errs["sitemap"], &sitemapErrs) {
+		t.Fatalf("Expected sitemap validation errors, got %v", errs["sitemap"])
+	}
+
+	tests.TestValidationErrors(t, sitemapErrs, []string{"urlTemplate", "filter", "lastmodField", "changeFreq", "priority", "pageSize"})
+}
+
+func TestSitemapConfigLimit(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		pageSize int
+		expected int
+	}{
+		{-1, sitemap.MaxURLs},
+		{0, sitemap.MaxURLs},
+		{10, 10},
+	}
+
+	for _, s := range scenarios {
+		config := &core.SitemapConfig{PageSize: s.pageSize}
+		if v := config.Limit(); v != s.expected {
+			t.Fatalf("[%d] Expected %d, got %d", s.pageSize, s.expected, v)
+		}
+	}
+}
+
+func TestCollectionOGImageConfigValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.OGImage = &core.OGImageConfig{
+		TitleField:      "missing",
+		SubtitleField:   "missing",
+		BackgroundField: "text",
+		BackgroundColor: "#fff",
+		TextColor:       "#gggggg",
+		CacheMaxAge:     -1,
+	}
+
+	var errs validation.Errors
+	if !errors.As(app.Validate(collection), &errs) {
+		t.Fatal("Expected validation errors")
+	}
+
+	var ogImageErrs validation.Errors
+	if !errors.As(errs["ogImage"], &ogImageErrs) {
+		t.Fatalf("Expected ogImage validation errors, got %v", errs["ogImage"])
+	}
+
+	tests.TestValidationErrors(t, ogImageErrs, []string{"titleField", "subtitleField", "backgroundField", "backgroundColor", "textColor", "cacheMaxAge"})
+}
+
+func TestCollectionReactionsConfigValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Reactions = &core.ReactionsConfig{
+		Types:       []string{"like", "thumbs up"},
+		CountsField: "text",
+	}
+
+	var errs validation.Errors
+	if !errors.As(app.Validate(collection), &errs) {
+		t.Fatal("Expected validation errors")
+	}
+
+	var reactionsErrs validation.Errors
+	if !errors.As(errs["reactions"], &reactionsErrs) {
+		t.Fatalf("Expected reactions validation errors, got %v", errs["reactions"])
+	}
+
+	tests.TestValidationErrors(t, reactionsErrs, []string{"types", "countsField"})
+}
+
+func TestReactionsConfigHasType(t *testing.T) {
+	t.Parallel()
+
+	config := &core.ReactionsConfig{Types: []string{"like", "love"}}
+
+	scenarios := []struct {
+		reactionType string
+		expected     bool
+	}{
+		{"", false},
+		{"missing", false},
+		{"Like", false},
+		{"like", true},
+		{"love", true},
+	}
+
+	for _, s := range scenarios {
+		if v := config.HasType(s.reactionType); v != s.expected {
+			t.Fatalf("[%s] Expected %v, got %v", s.reactionType, s.expected, v)
+		}
+	}
+}
+
+func TestCollectionFormConfigValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Form = &core.FormConfig{
+		Fields:        []string{"text", "missing", "file_one", "created", "id"},
+		HoneypotField: "text",
+		NotifyEmails:  []string{"invalid"},
+		MaxRequests:   -1,
+		Duration:      -1,
+	}
+
+	var errs validation.Errors
+	if !errors.As(app.Validate(collection), &errs) {
+		t.Fatal("Expected validation errors")
+	}
+
+	var formErrs validation.Errors
+	if !errors.As(errs["form"], &formErrs) {
+		t.Fatalf("Expected form validation errors, got %v", errs["form"])
+	}
+
+	tests.TestValidationErrors(t, formErrs, []string{"fields", "honeypotField", "notifyEmails", "maxRequests", "duration"})
+
+	var fieldsErrs validation.Errors
+	if !errors.As(formErrs["fields"], &fieldsErrs) {
+		t.Fatalf("Expected fields validation errors, got %v", formErrs["fields"])
+	}
+
+	tests.TestValidationErrors(t, fieldsErrs, []string{"1", "2", "3", "4"})
+}
+
+func TestFormConfigRateLimitRule(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		maxRequests      int
+		duration         int64
+		expectedRequests int
+		expectedDuration int64
+	}{
+		{0, 0, 5, 600},
+		{-1, -1, 5, 600},
+		{10, 60, 10, 60},
+	}
+
+	for i, s := range scenarios {
+		config := &core.FormConfig{MaxRequests: s.maxRequests, Duration: s.duration}
+
+		rule := config.RateLimitRule()
+
+		if rule.MaxRequests != s.expectedRequests {
+			t.Fatalf("[%d] Expected MaxRequests %d, got %d", i, s.expectedRequests, rule.MaxRequests)
+		}
+
+		if rule.Duration != s.expectedDuration {
+			t.Fatalf("[%d] Expected Duration %d, got %d", i, s.expectedDuration, rule.Duration)
+		}
+	}
+}
+
+func TestCollectionSearchConfigValidateErrors(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		fields         []string
+		expectedErrors []string
+	}{
+		{
+			"invalid fields",
+			[]string{"text", "missing", "email", "id"},
+			[]string{"1", "2", "3"},
+		},
+		{
+			"duplicated fields",
+			[]string{"text", "text"},
+			nil,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			collection, err := app.FindCollectionByNameOrId("demo1")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			collection.Search = &core.SearchConfig{Fields: s.fields}
+
+			var errs validation.Errors
+			if !errors.As(app.Validate(collection), &errs) {
+				t.Fatal("Expected validation errors")
+			}
+
+			var searchErrs validation.Errors
+			if !errors.As(errs["search"], &searchErrs) {
+				t.Fatalf("Expected search validation errors, got %v", errs["search"])
+			}
+
+			tests.TestValidationErrors(t, searchErrs, []string{"fields"})
+
+			var fieldsErrs validation.Errors
+			if errors.As(searchErrs["fields"], &fieldsErrs) {
+				tests.TestValidationErrors(t, fieldsErrs, s.expectedErrors)
+			} else if s.expectedErrors != nil {
+				t.Fatalf("Expected fields item validation errors, got %v", searchErrs["fields"])
+			}
+		})
+	}
+}
+
+func TestSearchConfigFieldIndex(t *testing.T) {
+	t.Parallel()
+
+	config := &core.SearchConfig{Fields: []string{"title", "description"}}
+
+	scenarios := []struct {
+		name     string
+		expected int
+	}{
+		{"", -1},
+		{"missing", -1},
+		{"title", 0},
+		{"description", 1},
+	}
+
+	for _, s := range scenarios {
+		if v := config.FieldIndex(s.name); v != s.expected {
+			t.Fatalf("[%s] Expected %d, got %d", s.name, s.expected, v)
+		}
+	}
+}
@@ -0,0 +1,87 @@
+package core
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/ogimage"
+)
+
+// OGImageConfig defines the collection records Open Graph image options.
+//
+// When set, a preview image with the record title and subtitle text over
+// the configured background is rendered on demand via the
+// "/api/collections/{collection}/records/{id}/og.png" endpoint
+// (the collection view API rule is applied as usual).
+//
+// The rendered images are stored next to the record files
+// and are regenerated only when their source values change.
+type OGImageConfig struct {
+	// TitleField is the name of the field with the image title text.
+	TitleField string `form:"titleField" json:"titleField"`
+
+	// SubtitleField is the name of the optional field with the image subtitle text.
+	SubtitleField string `form:"subtitleField" json:"subtitleField"`
+
+	// BackgroundField is the name of the optional file field with the
+	// image background (the first file is used if it is a multiple files field).
+	BackgroundField string `form:"backgroundField" json:"backgroundField"`
+
+	// BackgroundColor is the optional "#RRGGBB" background color
+	// used when there is no background image.
+	BackgroundColor string `form:"backgroundColor" json:"backgroundColor"`
+
+	// TextColor is the optional "#RRGGBB" text color.
+	TextColor string `form:"textColor" json:"textColor"`
+
+	// CacheMaxAge is the optional Cache-Control max-age of the image response in seconds.
+	CacheMaxAge int `form:"cacheMaxAge" json:"cacheMaxAge"`
+}
+
+func (c *OGImageConfig) validate(cv *collectionValidator) error {
+	return validation.ValidateStruct(c,
+		validation.Field(&c.TitleField, validation.Required, validation.By(cv.checkMappedField)),
+		validation.Field(&c.SubtitleField, validation.By(cv.checkMappedField)),
+		validation.Field(&c.BackgroundField, validation.By(cv.checkMappedFileField)),
+		validation.Field(&c.BackgroundColor, validation.By(checkHexColor)),
+		validation.Field(&c.TextColor, validation.By(checkHexColor)),
+		validation.Field(&c.CacheMaxAge, validation.Min(0), validation.Max(2592000)),
+	)
+}
+
+func checkOGImageConfig(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		config, _ := value.(*OGImageConfig)
+		if config == nil {
+			return nil // disabled
+		}
+
+		return config.validate(cv)
+	}
+}
+
+func (cv *collectionValidator) checkMappedFileField(value any) error {
+	name, _ := value.(string)
+	if name == "" {
+		return nil // nothing to check
+	}
+
+	field, ok := cv.new.Fields.GetByName(name).(*FileField)
+	if !ok || field.Hidden || field.Protected {
+		return validation.NewError("validation_invalid_file_field", "Missing or non-public file field {{.fieldName}}").
+			SetParams(map[string]any{"fieldName": name})
+	}
+
+	return nil
+}
+
+func checkHexColor(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := ogimage.ParseHexColor(v); err != nil {
+		return validation.NewError("validation_invalid_color", "Invalid color - must be in #RRGGBB format.")
+	}
+
+	return nil
+}
@@ -0,0 +1,218 @@
+package core
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// The supported collection policy effects.
+const (
+	CollectionPolicyEffectAllow = "allow"
+	CollectionPolicyEffectDeny  = "deny"
+)
+
+// The collection records API actions that a policy could be attached to.
+const (
+	CollectionPolicyActionList   = "list"
+	CollectionPolicyActionView   = "view"
+	CollectionPolicyActionCreate = "create"
+	CollectionPolicyActionUpdate = "update"
+	CollectionPolicyActionDelete = "delete"
+)
+
+// CollectionPolicy defines a single named collection security policy.
+//
+// The policies are evaluated in addition to the collection API rules
+// (aka. the action must satisfy both the API rule and the policies) and
+// are not applied for superusers and for actions with superusers only (nil) API rule.
+//
+// For each action the policies are checked from the highest to the
+// lowest Priority (on equal priority "deny" is checked first) and the
+// first policy which Rule matches decides whether the action is allowed.
+// If none of the policies match, the action is allowed.
+//
+// For example, to deny access to the archived records except for editors:
+//
+//	collection.Policies = []*core.CollectionPolicy{
+//		{Name: "hide archived", Effect: "deny", Actions: []string{"list", "view"}, Rule: "status = 'archived'"},
+//		{Name: "editors", Effect: "allow", Actions: []string{"list", "view"}, Priority: 10, Rule: "@request.auth.role = 'editor'"},
+//	}
+type CollectionPolicy struct {
+	// Name is the unique policy name (used mainly for identification purposes).
+	Name string `form:"name" json:"name"`
+
+	// Effect specifies whether the matching policy allows or denies the action ("allow" or "deny").
+	Effect string `form:"effect" json:"effect"`
+
+	// Actions specifies the records API actions that the policy applies to
+	// ("list", "view", "create", "update", "delete").
+	Actions []string `form:"actions" json:"actions"`
+
+	// Priority specifies the policy evaluation order (higher is checked first).
+	Priority int `form:"priority" json:"priority"`
+
+	// Rule is the policy filter expression with the same syntax as the API rules.
+	//
+	// Empty rule matches always.
+	Rule string `form:"rule" json:"rule"`
+}
+
+// HasAction checks whether the policy applies to the specified action.
+func (p *CollectionPolicy) HasAction(action string) bool {
+	return slices.Contains(p.Actions, action)
+}
+
+func (p *CollectionPolicy) validate(cv *collectionValidator) error {
+	return validation.ValidateStruct(p,
+		validation.Field(&p.Name, validation.Required, validation.Length(1, 100)),
+		validation.Field(&p.Effect, validation.Required, validation.In(CollectionPolicyEffectAllow, CollectionPolicyEffectDeny)),
+		validation.Field(
+			&p.Actions,
+			validation.Required,
+			validation.Each(validation.In(
+				CollectionPolicyActionList,
+				CollectionPolicyActionView,
+				CollectionPolicyActionCreate,
+				CollectionPolicyActionUpdate,
+				CollectionPolicyActionDelete,
+			)),
+		),
+		validation.Field(&p.Rule, validation.By(cv.checkRule)),
+	)
+}
+
+func checkCollectionPolicies(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		policies, _ := value.([]*CollectionPolicy)
+
+		errs := validation.Errors{}
+
+		names := make(map[string]struct{}, len(policies))
+
+		for i, p := range policies {
+			if p == nil {
+				errs[strconv.Itoa(i)] = validation.NewError("validation_required", "Cannot be blank.")
+				continue
+			}
+
+			if err := p.validate(cv); err != nil {
+				errs[strconv.Itoa(i)] = err
+				continue
+			}
+
+			if _, ok := names[p.Name]; ok {
+				errs[strconv.Itoa(i)] = validation.Errors{
+					"name": validation.NewError("validation_duplicated_policy_name", "The policy name must be unique."),
+				}
+				continue
+			}
+			names[p.Name] = struct{}{}
+		}
+
+		if len(errs) > 0 {
+			return errs
+		}
+
+		return nil
+	}
+}
+
+// ActionPolicies returns the collection policies of the specified
+// action sorted in their evaluation order.
+func (m *Collection) ActionPolicies(action string) []*CollectionPolicy {
+	result := make([]*CollectionPolicy, 0, len(m.Policies))
+
+	for _, p := range m.Policies {
+		if p != nil && p.HasAction(action) {
+			result = append(result, p)
+		}
+	}
+
+	slices.SortStableFunc(result, func(a, b *CollectionPolicy) int {
+		if a.Priority != b.Priority {
+			return cmp.Compare(b.Priority, a.Priority)
+		}
+
+		// deny first
+		if a.Effect != b.Effect {
+			if a.Effect == CollectionPolicyEffectDeny {
+				return -1
+			}
+			return 1
+		}
+
+		return 0
+	})
+
+	return result
+}
+
+// BuildPoliciesExpr builds a single db expression from the collection
+// policies of the specified action.
+//
+// Returns nil if the policies don't restrict the action
+// (e.g. the collection doesn't have policies for the action).
+//
+// Note that the caller is responsible to call resolver.UpdateQuery(query)
+// after all rule expressions are built.
+func (m *Collection) BuildPoliciesExpr(action string, resolver search.FieldResolver) (dbx.Expression, error) {
+	policies := m.ActionPolicies(action)
+	if len(policies) == 0 {
+		return nil, nil
+	}
+
+	// fold the policies starting from the lowest priority one where
+	// a nil result means "allowed" (aka. no further restrictions)
+	var result dbx.Expression
+
+	for i := len(policies) - 1; i >= 0; i-- {
+		p := policies[i]
+
+		var match dbx.Expression
+		if p.Rule != "" {
+			expr, err := search.FilterData(p.Rule).BuildExpr(resolver)
+			if err != nil {
+				return nil, err
+			}
+			match = expr
+		}
+
+		if p.Effect == CollectionPolicyEffectAllow {
+			if match == nil || result == nil {
+				result = nil
+			} else {
+				result = dbx.Or(match, result)
+			}
+			continue
+		}
+
+		// deny
+		if match == nil {
+			result = dbx.NewExp("1=0")
+		} else {
+			result = dbx.And(&notTrueExpr{match}, result)
+		}
+	}
+
+	return result, nil
+}
+
+// notTrueExpr negates an expression treating NULL as false.
+type notTrueExpr struct {
+	exp dbx.Expression
+}
+
+// Build implements the [dbx.Expression] interface.
+func (e *notTrueExpr) Build(db *dbx.DB, params dbx.Params) string {
+	sql := e.exp.Build(db, params)
+	if sql == "" {
+		return "1=0" // empty expressions are always true
+	}
+
+	return "NOT COALESCE((" + sql + "), 0)"
+}
@@ -0,0 +1,358 @@
+package core_test
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestCollectionPolicyHasAction(t *testing.T) {
+	t.Parallel()
+
+	p := &core.CollectionPolicy{Actions: []string{"list", "view"}}
+
+	scenarios := []struct {
+		action   string
+		expected bool
+	}{
+		{"", false},
+		{"create", false},
+		{"list", true},
+		{"view", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.action, func(t *testing.T) {
+			result := p.HasAction(s.action)
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestCollectionActionPolicies(t *testing.T) {
+	t.Parallel()
+
+	collection := core.NewBaseCollection("test")
+	collection.Policies = []*core.CollectionPolicy{
+		{Name: "a", Effect: "allow", Actions: []string{"list"}},
+		{Name: "b", Effect: "allow", Actions: []string{"list", "view"}, Priority: 10},
+		nil,
+		{Name: "c", Effect: "deny", Actions: []string{"list"}},
+		{Name: "d", Effect: "deny", Actions: []string{"view"}, Priority: 20},
+		{Name: "e", Effect: "deny", Actions: []string{"list"}, Priority: -1},
+	}
+
+	scenarios := []struct {
+		action   string
+		expected []string
+	}{
+		{"create", []string{}},
+		{"list", []string{"b", "c", "a", "e"}},
+		{"view", []string{"d", "b"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.action, func(t *testing.T) {
+			policies := collection.ActionPolicies(s.action)
+
+			names := make([]string, len(policies))
+			for i, p := range policies {
+				names[i] = p.Name
+			}
+
+			if !slices.Equal(names, s.expected) {
+				t.Fatalf("Expected policies %v, got %v", s.expected, names)
+			}
+		})
+	}
+}
+
+func TestCollectionBuildPoliciesExpr(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// demo2 records: llvuca81nly1qls (active=false), achvryl401bhse3 and 0yxhwia2amd8gec (active=true)
+	scenarios := []struct {
+		name        string
+		policies    []*core.CollectionPolicy
+		auth        *core.Record
+		expectNil   bool
+		expectError bool
+		expected    []string
+	}{
+		{
+			name:      "no policies",
+			expectNil: true,
+			expected:  []string{"0yxhwia2amd8gec", "achvryl401bhse3", "llvuca81nly1qls"},
+		},
+		{
+			name: "policies of other action",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "deny", Actions: []string{"view"}},
+			},
+			expectNil: true,
+			expected:  []string{"0yxhwia2amd8gec", "achvryl401bhse3", "llvuca81nly1qls"},
+		},
+		{
+			name: "allow only policies",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "allow", Actions: []string{"list"}, Rule: "active = true"},
+			},
+			expectNil: true,
+			expected:  []string{"0yxhwia2amd8gec", "achvryl401bhse3", "llvuca81nly1qls"},
+		},
+		{
+			name: "invalid policy rule",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "deny", Actions: []string{"list"}, Rule: "missing = 1"},
+			},
+			expectError: true,
+		},
+		{
+			name: "single deny policy",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "deny", Actions: []string{"list"}, Rule: "active = false"},
+			},
+			expected: []string{"0yxhwia2amd8gec", "achvryl401bhse3"},
+		},
+		{
+			name: "deny policy with empty rule",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "deny", Actions: []string{"list"}},
+			},
+			expected: []string{},
+		},
+		{
+			name: "deny policy with non-matching rule",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "deny", Actions: []string{"list"}, Rule: "@request.auth.id != '' && active = false"},
+			},
+			expected: []string{"0yxhwia2amd8gec", "achvryl401bhse3", "llvuca81nly1qls"},
+		},
+		{
+			name: "higher priority allow policy (guest)",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "deny", Actions: []string{"list"}},
+				{Name: "b", Effect: "allow", Actions: []string{"list"}, Priority: 10, Rule: "@request.auth.id != '' || id = '0yxhwia2amd8gec'"},
+			},
+			expected: []string{"0yxhwia2amd8gec"},
+		},
+		{
+			name: "higher priority allow policy (auth)",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "deny", Actions: []string{"list"}},
+				{Name: "b", Effect: "allow", Actions: []string{"list"}, Priority: 10, Rule: "@request.auth.id != '' || id = '0yxhwia2amd8gec'"},
+			},
+			auth:     user,
+			expected: []string{"0yxhwia2amd8gec", "achvryl401bhse3", "llvuca81nly1qls"},
+		},
+		{
+			name: "lower priority allow policy",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "deny", Actions: []string{"list"}, Priority: 10, Rule: "active = false"},
+				{Name: "b", Effect: "allow", Actions: []string{"list"}, Rule: "@request.auth.id != ''"},
+			},
+			auth:     user,
+			expected: []string{"0yxhwia2amd8gec", "achvryl401bhse3"},
+		},
+		{
+			name: "deny before allow on equal priority",
+			policies: []*core.CollectionPolicy{
+				{Name: "a", Effect: "allow", Actions: []string{"list"}, Rule: "@request.auth.id != ''"},
+				{Name: "b", Effect: "deny", Actions: []string{"list"}, Rule: "id = 'achvryl401bhse3'"},
+			},
+			auth:     user,
+			expected: []string{"0yxhwia2amd8gec", "llvuca81nly1qls"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			collection, err := app.FindCollectionByNameOrId("demo2")
+			if err != nil {
+				t.Fatal(err)
+			}
+			collection.Policies = s.policies
+
+			requestInfo := &core.RequestInfo{Auth: s.auth}
+
+			resolver := core.NewRecordFieldResolver(app, collection, requestInfo, true)
+
+			expr, err := collection.BuildPoliciesExpr(core.CollectionPolicyActionList, resolver)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if (expr == nil) != s.expectNil {
+				t.Fatalf("Expected nil expression %v, got %v", s.expectNil, expr)
+			}
+
+			ids := []string{}
+
+			query := app.RecordQuery(collection).Select("demo2.id").OrderBy("demo2.id ASC")
+			if expr != nil {
+				query.AndWhere(expr)
+			}
+			resolver.UpdateQuery(query)
+
+			if err := query.Column(&ids); err != nil {
+				t.Fatal(err)
+			}
+
+			if !slices.Equal(ids, s.expected) {
+				t.Fatalf("Expected ids %v, got %v", s.expected, ids)
+			}
+		})
+	}
+}
+
+func TestCanAccessRecordWithPolicies(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := app.FindRecordById("demo2", "llvuca81nly1qls")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record.Collection().Policies = []*core.CollectionPolicy{
+		{Name: "inactive", Effect: "deny", Actions: []string{"view"}, Rule: "active = false"},
+	}
+
+	scenarios := []struct {
+		name     string
+		auth     *core.Record
+		rule     *string
+		action   []string
+		expected bool
+	}{
+		{
+			"collection view rule with view action",
+			nil,
+			record.Collection().ViewRule,
+			[]string{core.CollectionPolicyActionView},
+			false,
+		},
+		{
+			"collection view rule with view action (superuser)",
+			superuser,
+			record.Collection().ViewRule,
+			[]string{core.CollectionPolicyActionView},
+			true,
+		},
+		{
+			"collection view rule without action",
+			nil,
+			record.Collection().ViewRule,
+			nil,
+			true,
+		},
+		{
+			"copied collection view rule with view action",
+			nil,
+			types.Pointer(*record.Collection().ViewRule),
+			[]string{core.CollectionPolicyActionView},
+			false,
+		},
+		{
+			"collection list rule with list action (policies of other action)",
+			nil,
+			record.Collection().ListRule,
+			[]string{core.CollectionPolicyActionList},
+			true,
+		},
+		{
+			"custom rule without action",
+			nil,
+			types.Pointer(""),
+			nil,
+			true,
+		},
+		{
+			"custom rule with view action",
+			nil,
+			types.Pointer(""),
+			[]string{core.CollectionPolicyActionView},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := app.CanAccessRecord(record, &core.RequestInfo{Auth: s.auth}, s.rule, s.action...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestCollectionPoliciesPersistence(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Policies = []*core.CollectionPolicy{
+		{Name: "inactive", Effect: "deny", Actions: []string{"list", "view"}, Priority: 5, Rule: "active = false"},
+	}
+
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	var rawOptions string
+	err = app.DB().Select("options").From("_collections").AndWhere(dbx.HashExp{"id": collection.Id}).Row(&rawOptions)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(rawOptions, `"policies":[{"name":"inactive","effect":"deny","actions":["list","view"],"priority":5,"rule":"active = false"}]`) {
+		t.Fatalf("Expected the policies to be stored in the collection options, got %s", rawOptions)
+	}
+
+	collection, err = app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(collection.Policies) != 1 || collection.Policies[0].Name != "inactive" {
+		t.Fatalf("Expected the policies to be loaded, got %v", collection.Policies)
+	}
+}
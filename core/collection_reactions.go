@@ -0,0 +1,77 @@
+package core
+
+import (
+	"regexp"
+	"slices"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// reactionTypeRegex is the allowed reaction type name format (e.g. "like", "thumbs_up").
+var reactionTypeRegex = regexp.MustCompile(`^\w+$`)
+
+const maxReactionTypes = 50
+
+// ReactionsConfig defines the collection records reactions options.
+//
+// When set, auth records that can view a collection record could
+// toggle their reactions to it via the records reactions API.
+// Each auth record could have at most one reaction of each type per record.
+type ReactionsConfig struct {
+	// Types is the list of the allowed reaction types (e.g. ["like", "love"]).
+	Types []string `form:"types" json:"types"`
+
+	// CountsField is the name of the optional json field where to store
+	// the denormalized reactions count of each type (e.g. {"like": 10, "love": 2}).
+	//
+	// The field value is recalculated on every reaction change so it is
+	// recommended to exclude it from the create and update API rules
+	// (e.g. "@request.body.reactions:isset = false").
+	CountsField string `form:"countsField" json:"countsField"`
+}
+
+// HasType reports whether reactionType is one of the config Types.
+func (c *ReactionsConfig) HasType(reactionType string) bool {
+	return slices.Contains(c.Types, reactionType)
+}
+
+func (c *ReactionsConfig) validate(cv *collectionValidator) error {
+	return validation.ValidateStruct(c,
+		validation.Field(
+			&c.Types,
+			validation.Required,
+			validation.Length(1, maxReactionTypes),
+			validation.Each(validation.Required, validation.Length(1, 50), validation.Match(reactionTypeRegex)),
+		),
+		validation.Field(&c.CountsField, validation.By(cv.checkMappedJSONField)),
+	)
+}
+
+func checkReactionsConfig(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		config, _ := value.(*ReactionsConfig)
+		if config == nil {
+			return nil // disabled
+		}
+
+		if cv.new.IsView() {
+			return validation.NewError("validation_reactions_view_collection", "Reactions are not supported for view collections.")
+		}
+
+		return config.validate(cv)
+	}
+}
+
+func (cv *collectionValidator) checkMappedJSONField(value any) error {
+	name, _ := value.(string)
+	if name == "" {
+		return nil // nothing to check
+	}
+
+	if _, ok := cv.new.Fields.GetByName(name).(*JSONField); !ok {
+		return validation.NewError("validation_invalid_json_field", "Missing or non-json field {{.fieldName}}").
+			SetParams(map[string]any{"fieldName": name})
+	}
+
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/collation"
 	"github.com/pocketbase/pocketbase/tools/dbutils"
 	"github.com/pocketbase/pocketbase/tools/security"
 )
@@ -132,6 +133,14 @@ func (app *BaseApp) SyncRecordTableSchema(newCollection *Collection, oldCollecti
 			return err
 		}
 
+		if err := normalizeTextCollationChanges(txApp, newCollection, oldCollection); err != nil {
+			return err
+		}
+
+		if err := normalizeTextNormalizeChanges(txApp, newCollection, oldCollection); err != nil {
+			return err
+		}
+
 		if needIndexesUpdate {
 			return createCollectionIndexes(txApp, newCollection)
 		}
@@ -177,80 +186,38 @@ func normalizeSingleVsMultipleFieldChanges(app App, newCollection *Collection, o
 				continue // no change
 			}
 
-			// -------------------------------------------------------
-			// update the field column definition
-			// -------------------------------------------------------
-
-			// temporary drop all views to prevent reference errors during the columns renaming
-			// (this is used as an "alternative" to the writable_schema PRAGMA)
-			views := []struct {
-				Name string `db:"name"`
-				SQL  string `db:"sql"`
-			}{}
-			err := txApp.DB().Select("name", "sql").
-				From("sqlite_master").
-				AndWhere(dbx.NewExp("sql is not null")).
-				AndWhere(dbx.HashExp{"type": "view"}).
-				All(&views)
-			if err != nil {
-				return err
-			}
-			for _, view := range views {
-				err = txApp.DeleteView(view.Name)
-				if err != nil {
-					return err
-				}
-			}
-
-			originalName := newField.GetName()
-			oldTempName := "_" + newField.GetName() + security.PseudorandomString(5)
-
-			// rename temporary the original column to something else to allow inserting a new one in its place
-			_, err = txApp.DB().RenameColumn(newCollection.Name, originalName, oldTempName).Execute()
-			if err != nil {
-				return err
-			}
-
-			// reinsert the field column with the new type
-			_, err = txApp.DB().AddColumn(newCollection.Name, originalName, newField.ColumnType(txApp)).Execute()
-			if err != nil {
-				return err
-			}
-
-			var copyQuery *dbx.Query
+			var copyExpr func(oldColumn string) string
 
 			if !isOldMultiple && isNewMultiple {
 				// single -> multiple (convert to array)
-				copyQuery = txApp.DB().NewQuery(fmt.Sprintf(
-					`UPDATE {{%s}} set [[%s]] = (
-							CASE
-								WHEN COALESCE([[%s]], '') = ''
-								THEN '[]'
-								ELSE (
-									CASE
-										WHEN json_valid([[%s]]) AND json_type([[%s]]) == 'array'
-										THEN [[%s]]
-										ELSE json_array([[%s]])
-									END
-								)
-							END
-						)`,
-					newCollection.Name,
-					originalName,
-					oldTempName,
-					oldTempName,
-					oldTempName,
-					oldTempName,
-					oldTempName,
-				))
+				copyExpr = func(oldColumn string) string {
+					return fmt.Sprintf(
+						`CASE
+							WHEN COALESCE([[%s]], '') = ''
+							THEN '[]'
+							ELSE (
+								CASE
+									WHEN json_valid([[%s]]) AND json_type([[%s]]) == 'array'
+									THEN [[%s]]
+									ELSE json_array([[%s]])
+								END
+							)
+						END`,
+						oldColumn,
+						oldColumn,
+						oldColumn,
+						oldColumn,
+						oldColumn,
+					)
+				}
 			} else {
 				// multiple -> single (keep only the last element)
 				//
 				// note: for file fields the actual file objects are not
 				// deleted allowing additional custom handling via migration
-				copyQuery = txApp.DB().NewQuery(fmt.Sprintf(
-					`UPDATE {{%s}} set [[%s]] = (
-						CASE
+				copyExpr = func(oldColumn string) string {
+					return fmt.Sprintf(
+						`CASE
 							WHEN COALESCE([[%s]], '[]') = '[]'
 							THEN ''
 							ELSE (
@@ -260,36 +227,50 @@ func normalizeSingleVsMultipleFieldChanges(app App, newCollection *Collection, o
 									ELSE [[%s]]
 								END
 							)
-						END
-					)`,
-					newCollection.Name,
-					originalName,
-					oldTempName,
-					oldTempName,
-					oldTempName,
-					oldTempName,
-					oldTempName,
-				))
+						END`,
+						oldColumn,
+						oldColumn,
+						oldColumn,
+						oldColumn,
+						oldColumn,
+					)
+				}
 			}
 
-			// copy the normalized values
-			_, err = copyQuery.Execute()
-			if err != nil {
+			if err := recreateFieldColumn(txApp, newCollection, newField, copyExpr); err != nil {
 				return err
 			}
+		}
 
-			// drop the original column
-			_, err = txApp.DB().DropColumn(newCollection.Name, oldTempName).Execute()
-			if err != nil {
-				return err
+		return nil
+	})
+}
+
+// normalizeTextCollationChanges recreates the columns of the text
+// fields with changed collation since SQLite doesn't support
+// altering the collation of an existing column.
+func normalizeTextCollationChanges(app App, newCollection *Collection, oldCollection *Collection) error {
+	if newCollection.IsView() || oldCollection == nil {
+		return nil // view or not an update
+	}
+
+	return app.RunInTransaction(func(txApp App) error {
+		for _, newField := range newCollection.Fields {
+			newText, ok := newField.(*TextField)
+			if !ok {
+				continue
 			}
 
-			// restore views
-			for _, view := range views {
-				_, err = txApp.DB().NewQuery(view.SQL).Execute()
-				if err != nil {
-					return err
-				}
+			oldText, ok := oldCollection.Fields.GetById(newField.GetId()).(*TextField)
+			if !ok || oldText.Collation == newText.Collation {
+				continue // new field or no change
+			}
+
+			err := recreateFieldColumn(txApp, newCollection, newField, func(oldColumn string) string {
+				return "[[" + oldColumn + "]]"
+			})
+			if err != nil {
+				return err
 			}
 		}
 
@@ -297,6 +278,147 @@ func normalizeSingleVsMultipleFieldChanges(app App, newCollection *Collection, o
 	})
 }
 
+// normalizeTextNormalizeChanges converts the existing values of the
+// text fields with enabled Normalize option to their NFC form
+// (otherwise the old non-normalized values will not match the
+// normalized equality lookups).
+func normalizeTextNormalizeChanges(app App, newCollection *Collection, oldCollection *Collection) error {
+	if newCollection.IsView() || oldCollection == nil {
+		return nil // view or not an update
+	}
+
+	for _, newField := range newCollection.Fields {
+		newText, ok := newField.(*TextField)
+		if !ok || !newText.Normalize {
+			continue
+		}
+
+		oldText, ok := oldCollection.Fields.GetById(newField.GetId()).(*TextField)
+		if !ok || oldText.Normalize {
+			continue // new field or no change
+		}
+
+		if err := backfillTextNFC(app, newCollection.Name, newText.Name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// backfillTextNFC updates the non-NFC values of the specified table column in batches.
+func backfillTextNFC(app App, tableName string, column string) error {
+	const batchSize = 1000
+
+	var lastRowid int64
+
+	for {
+		rows := []struct {
+			Rowid int64  `db:"rowid"`
+			Value string `db:"value"`
+		}{}
+
+		err := app.DB().Select("rowid", "[["+column+"]] as value").
+			From(tableName).
+			AndWhere(dbx.NewExp("[[rowid]] > {:lastRowid}", dbx.Params{"lastRowid": lastRowid})).
+			AndWhere(dbx.NewExp("[[" + column + "]] != ''")).
+			OrderBy("rowid ASC").
+			Limit(batchSize).
+			All(&rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			lastRowid = row.Rowid
+
+			normalized := collation.NFC(row.Value)
+			if normalized == row.Value {
+				continue
+			}
+
+			_, err := app.DB().Update(tableName, dbx.Params{column: normalized}, dbx.HashExp{"rowid": row.Rowid}).Execute()
+			if err != nil {
+				return err
+			}
+		}
+
+		if len(rows) < batchSize {
+			return nil
+		}
+	}
+}
+
+// recreateFieldColumn replaces the existing field column with a new one
+// using the current field column definition.
+//
+// copyExpr returns the SQL expression used to populate the new column
+// from the values of the old (temporary renamed) column.
+func recreateFieldColumn(txApp App, collection *Collection, field Field, copyExpr func(oldColumn string) string) error {
+	// temporary drop all views to prevent reference errors during the columns renaming
+	// (this is used as an "alternative" to the writable_schema PRAGMA)
+	views := []struct {
+		Name string `db:"name"`
+		SQL  string `db:"sql"`
+	}{}
+	err := txApp.DB().Select("name", "sql").
+		From("sqlite_master").
+		AndWhere(dbx.NewExp("sql is not null")).
+		AndWhere(dbx.HashExp{"type": "view"}).
+		All(&views)
+	if err != nil {
+		return err
+	}
+	for _, view := range views {
+		err = txApp.DeleteView(view.Name)
+		if err != nil {
+			return err
+		}
+	}
+
+	originalName := field.GetName()
+	oldTempName := "_" + field.GetName() + security.PseudorandomString(5)
+
+	// rename temporary the original column to something else to allow inserting a new one in its place
+	_, err = txApp.DB().RenameColumn(collection.Name, originalName, oldTempName).Execute()
+	if err != nil {
+		return err
+	}
+
+	// reinsert the field column with the new definition
+	_, err = txApp.DB().AddColumn(collection.Name, originalName, field.ColumnType(txApp)).Execute()
+	if err != nil {
+		return err
+	}
+
+	// copy the normalized values
+	_, err = txApp.DB().NewQuery(fmt.Sprintf(
+		"UPDATE {{%s}} set [[%s]] = (%s)",
+		collection.Name,
+		originalName,
+		copyExpr(oldTempName),
+	)).Execute()
+	if err != nil {
+		return err
+	}
+
+	// drop the original column
+	_, err = txApp.DB().DropColumn(collection.Name, oldTempName).Execute()
+	if err != nil {
+		return err
+	}
+
+	// restore views
+	for _, view := range views {
+		_, err = txApp.DB().NewQuery(view.SQL).Execute()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
 func dropCollectionIndexes(app App, collection *Collection) error {
 	if collection.IsView() {
 		return nil // views don't have indexes
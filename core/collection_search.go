@@ -0,0 +1,78 @@
+package core
+
+import (
+	"slices"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+const maxSearchFields = 10
+
+// SearchConfig defines the collection search-as-you-type index options.
+//
+// When set, the values of the listed text fields are indexed in a
+// dedicated trigram full-text table allowing fast prefix and
+// typo-tolerant records lookups (see [BuildRecordSearchExpr]).
+type SearchConfig struct {
+	// Fields is the list of the indexed text field names.
+	Fields []string `form:"fields" json:"fields"`
+}
+
+// FieldIndex returns the position of the named field in the search
+// index or -1 if the field is not indexed.
+func (c *SearchConfig) FieldIndex(name string) int {
+	return slices.Index(c.Fields, name)
+}
+
+func (c *SearchConfig) validate(cv *collectionValidator) error {
+	return validation.ValidateStruct(c,
+		validation.Field(
+			&c.Fields,
+			validation.Required,
+			validation.Length(1, maxSearchFields),
+			validation.By(checkUniqueSearchFields),
+			validation.Each(validation.By(cv.checkSearchField)),
+		),
+	)
+}
+
+func checkSearchConfig(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		config, _ := value.(*SearchConfig)
+		if config == nil {
+			return nil // disabled
+		}
+
+		if cv.new.IsView() {
+			return validation.NewError("validation_search_view_collection", "The search index is not supported for view collections.")
+		}
+
+		return config.validate(cv)
+	}
+}
+
+func checkUniqueSearchFields(value any) error {
+	names, _ := value.([]string)
+
+	for i, name := range names {
+		if slices.Index(names, name) != i {
+			return validation.NewError("validation_duplicated_search_field", "Duplicated search field {{.fieldName}}.").
+				SetParams(map[string]any{"fieldName": name})
+		}
+	}
+
+	return nil
+}
+
+// checkSearchField checks whether the value is a non-primary key collection text field.
+func (cv *collectionValidator) checkSearchField(value any) error {
+	name, _ := value.(string)
+
+	field, ok := cv.new.Fields.GetByName(name).(*TextField)
+	if !ok || field.PrimaryKey {
+		return validation.NewError("validation_invalid_search_field", "Missing or non-text field {{.fieldName}}.").
+			SetParams(map[string]any{"fieldName": name})
+	}
+
+	return nil
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/sitemap"
+)
+
+// SitemapConfig defines the collection records sitemap entries options.
+//
+// When set, the collection records that are publicly listable (aka. matching
+// the collection list API rule for guests) and the optional Filter are included
+// in the "/api/sitemap.xml" index as one or more "/api/sitemaps/{collection}/{page}" sitemaps.
+type SitemapConfig struct {
+	// URLTemplate is the page url template with {fieldName}
+	// record field placeholders (e.g. "https://example.com/posts/{slug}").
+	URLTemplate string `form:"urlTemplate" json:"urlTemplate"`
+
+	// Filter is an optional filter expression for the sitemap records
+	// (e.g. "published = true").
+	Filter string `form:"filter" json:"filter"`
+
+	// LastmodField is the name of the optional date field with the page last modification date.
+	LastmodField string `form:"lastmodField" json:"lastmodField"`
+
+	// ChangeFreq is the optional page change frequency hint
+	// ("always", "hourly", "daily", "weekly", "monthly", "yearly", "never").
+	ChangeFreq string `form:"changeFreq" json:"changeFreq"`
+
+	// Priority is the optional page priority in the 0-1 range.
+	Priority float64 `form:"priority" json:"priority"`
+
+	// PageSize is the max number of urls per sitemap page (default and max to [sitemap.MaxURLs]).
+	PageSize int `form:"pageSize" json:"pageSize"`
+}
+
+// Limit returns the max number of urls per sitemap page.
+func (c *SitemapConfig) Limit() int {
+	if c.PageSize <= 0 {
+		return sitemap.MaxURLs
+	}
+
+	return c.PageSize
+}
+
+func (c *SitemapConfig) validate(cv *collectionValidator) error {
+	return validation.ValidateStruct(c,
+		validation.Field(
+			&c.URLTemplate,
+			validation.Required,
+			validation.Length(1, 2000),
+			validation.By(cv.checkRecordURLTemplate),
+		),
+		validation.Field(&c.Filter, validation.By(cv.checkRule)),
+		validation.Field(&c.LastmodField, validation.By(cv.checkMappedDateField)),
+		validation.Field(
+			&c.ChangeFreq,
+			validation.In(
+				sitemap.ChangeFreqAlways,
+				sitemap.ChangeFreqHourly,
+				sitemap.ChangeFreqDaily,
+				sitemap.ChangeFreqWeekly,
+				sitemap.ChangeFreqMonthly,
+				sitemap.ChangeFreqYearly,
+				sitemap.ChangeFreqNever,
+			),
+		),
+		validation.Field(&c.Priority, validation.Min(0.0), validation.Max(1.0)),
+		validation.Field(&c.PageSize, validation.Min(0), validation.Max(sitemap.MaxURLs)),
+	)
+}
+
+func checkSitemapConfig(cv *collectionValidator) validation.RuleFunc {
+	return func(value any) error {
+		config, _ := value.(*SitemapConfig)
+		if config == nil {
+			return nil // disabled
+		}
+
+		return config.validate(cv)
+	}
+}
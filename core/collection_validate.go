@@ -412,6 +412,20 @@ func (cv *collectionValidator) checkMinFields(value any) error {
 			return validation.Errors{FieldNameVerified: ErrMustBeSystem}
 		}
 
+		for _, f := range fields {
+			phoneField, ok := f.(*PhoneField)
+			if !ok {
+				continue
+			}
+
+			if _, ok := fields.GetByName(phoneField.VerifiedFieldName()).(*BoolField); !ok {
+				return validation.NewError(
+					"validation_invalid_phone_verified_field",
+					fmt.Sprintf("The phone field %q requires %q to be a bool field.", phoneField.Name, phoneField.VerifiedFieldName()),
+				)
+			}
+		}
+
 		return nil
 	}
 
@@ -443,6 +457,23 @@ func (validator *collectionValidator) ensureNoSystemFieldsChange(value any) erro
 	return nil
 }
 
+func (cv *collectionValidator) checkProfileCompletionFields(value any) error {
+	names, ok := value.([]string)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	for _, name := range names {
+		field := cv.new.Fields.GetByName(name)
+		if field == nil || name == FieldNameId || name == FieldNamePassword || name == FieldNameTokenKey {
+			return validation.NewError("validation_missing_field", "Invalid or missing field {{.fieldName}}").
+				SetParams(map[string]any{"fieldName": name})
+		}
+	}
+
+	return nil
+}
+
 func (cv *collectionValidator) checkFieldsForUniqueIndex(value any) error {
 	names, ok := value.([]string)
 	if !ok {
@@ -683,12 +714,15 @@ func (cv *collectionValidator) checkIndexes(value any) error {
 }
 
 func (validator *collectionValidator) validateOptions() error {
+	commonErr := validator.new.collectionCommonOptions.validate(validator)
+
+	var typeErr error
 	switch validator.new.Type {
 	case CollectionTypeAuth:
-		return validator.new.collectionAuthOptions.validate(validator)
+		typeErr = validator.new.collectionAuthOptions.validate(validator)
 	case CollectionTypeView:
-		return validator.new.collectionViewOptions.validate(validator)
+		typeErr = validator.new.collectionViewOptions.validate(validator)
 	}
 
-	return nil
+	return validators.JoinValidationErrors(commonErr, typeErr)
 }
@@ -4,9 +4,15 @@ package core
 
 import (
 	"github.com/pocketbase/dbx"
-	_ "modernc.org/sqlite"
+	"modernc.org/sqlite"
 )
 
+func init() {
+	for name, impl := range TextCollations {
+		sqlite.MustRegisterCollationUtf8(name, impl)
+	}
+}
+
 func DefaultDBConnect(dbPath string) (*dbx.DB, error) {
 	// Note: the busy_timeout pragma must be first because
 	// the connection needs to be set to block on busy before WAL mode
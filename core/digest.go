@@ -0,0 +1,301 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/cron"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const DigestsTableName = "_digests"
+
+// Digest email placeholder tokens.
+const (
+	EmailPlaceholderDigest      string = "{DIGEST}"
+	EmailPlaceholderDigestCount string = "{DIGEST_COUNT}"
+)
+
+var (
+	_ Model         = (*Digest)(nil)
+	_ PostValidator = (*Digest)(nil)
+)
+
+var digestNameRegex = regexp.MustCompile(`^[\w\-]+$`)
+
+// Digest defines a periodic digest email definition.
+//
+// On each Schedule tick (UTC) the records of the Collection matching the Filter
+// are rendered with the ItemTemplate and sent in a single email
+// to each of the recipients (aka. the RecipientsCollection auth records matching the RecipientsFilter).
+//
+// The Filter could use the recipient auth record fields via @request.auth.*
+// (eg. "author = @request.auth.id") and the {:since} placeholder
+// with the previous digest run date (eg. "created > {:since}").
+// Recipients without matching records are skipped.
+//
+// The ItemTemplate could contain {ITEM:*} record field placeholders and
+// the email Subject and Body - {DIGEST}, {DIGEST_COUNT}, {APP_NAME}, {APP_URL} and {RECORD:*} (the recipient).
+type Digest struct {
+	BaseModel
+
+	Name                    string         `db:"name" json:"name"`
+	Active                  bool           `db:"active" json:"active"`
+	Schedule                string         `db:"schedule" json:"schedule"`
+	CollectionRef           string         `db:"collectionRef" json:"collectionRef"`
+	Filter                  string         `db:"filter" json:"filter"`
+	Sort                    string         `db:"sort" json:"sort"`
+	MaxItems                int            `db:"maxItems" json:"maxItems"`
+	RecipientsCollectionRef string         `db:"recipientsCollectionRef" json:"recipientsCollectionRef"`
+	RecipientsFilter        string         `db:"recipientsFilter" json:"recipientsFilter"`
+	Subject                 string         `db:"subject" json:"subject"`
+	Body                    string         `db:"body" json:"body"`
+	ItemTemplate            string         `db:"itemTemplate" json:"itemTemplate"`
+	LastRun                 types.DateTime `db:"lastRun" json:"lastRun"`
+	Created                 types.DateTime `db:"created" json:"created"`
+	Updated                 types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the Digest model table name.
+func (m *Digest) TableName() string {
+	return DigestsTableName
+}
+
+// EmailTemplate returns the digest email template.
+func (m *Digest) EmailTemplate() EmailTemplate {
+	return EmailTemplate{Subject: m.Subject, Body: m.Body}
+}
+
+// IsDue reports whether the digest is active and its schedule matches the provided time (in UTC).
+func (m *Digest) IsDue(t time.Time) bool {
+	if !m.Active {
+		return false
+	}
+
+	schedule, err := cron.NewSchedule(m.Schedule)
+	if err != nil {
+		return false
+	}
+
+	return schedule.IsDue(cron.NewMoment(t.UTC()))
+}
+
+// Since returns the start date of the current digest period
+// (aka. the previous run date or the digest creation date for the first run).
+func (m *Digest) Since() types.DateTime {
+	if m.LastRun.IsZero() {
+		return m.Created
+	}
+
+	return m.LastRun
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *Digest) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(
+			&m.Name,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(digestNameRegex),
+			validation.By(m.checkUniqueName(app)),
+		),
+		validation.Field(&m.Schedule, validation.Required, validation.By(checkCronExpression)),
+		validation.Field(&m.CollectionRef, validation.Required, validation.By(validateCollectionId(app))),
+		validation.Field(&m.Filter, validation.Length(0, 3500), validation.By(m.checkFilter(app))),
+		validation.Field(&m.Sort, validation.Length(0, 1000)),
+		validation.Field(&m.MaxItems, validation.Required, validation.Max(1000)),
+		validation.Field(
+			&m.RecipientsCollectionRef,
+			validation.Required,
+			validation.By(validateCollectionId(app, CollectionTypeAuth)),
+		),
+		validation.Field(&m.RecipientsFilter, validation.Length(0, 3500), validation.By(m.checkRecipientsFilter(app))),
+		validation.Field(&m.Subject, validation.Required, validation.Length(1, 1000)),
+		validation.Field(&m.Body, validation.Required),
+		validation.Field(&m.ItemTemplate, validation.Required),
+	)
+}
+
+func (m *Digest) checkUniqueName(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		var exists int
+
+		err := app.DigestQuery().
+			Select("(1)").
+			AndWhere(dbx.HashExp{"name": v}).
+			AndWhere(dbx.Not(dbx.HashExp{"id": m.Id})).
+			Limit(1).
+			Row(&exists)
+
+		if exists > 0 || (err != nil && !errors.Is(err, sql.ErrNoRows)) {
+			return validation.NewError("validation_digest_name_exists", "Digest with the provided name already exists.")
+		}
+
+		return nil
+	}
+}
+
+func (m *Digest) checkFilter(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		collection, err := app.FindCachedCollectionByNameOrId(m.CollectionRef)
+		if err != nil {
+			return nil // the collection error is reported separately
+		}
+
+		requestInfo := &RequestInfo{}
+		if recipients, err := app.FindCachedCollectionByNameOrId(m.RecipientsCollectionRef); err == nil {
+			requestInfo.Auth = NewRecord(recipients)
+		}
+
+		resolver := NewRecordFieldResolver(app, collection, requestInfo, true)
+
+		if _, err := search.FilterData(v).BuildExpr(resolver, dbx.Params{"since": ""}); err != nil {
+			return validation.NewError("validation_invalid_filter", "Invalid filter expression. Raw error: "+err.Error())
+		}
+
+		return nil
+	}
+}
+
+func (m *Digest) checkRecipientsFilter(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		collection, err := app.FindCachedCollectionByNameOrId(m.RecipientsCollectionRef)
+		if err != nil {
+			return nil // the collection error is reported separately
+		}
+
+		resolver := NewRecordFieldResolver(app, collection, nil, true)
+
+		if _, err := search.FilterData(v).BuildExpr(resolver); err != nil {
+			return validation.NewError("validation_invalid_filter", "Invalid filter expression. Raw error: "+err.Error())
+		}
+
+		return nil
+	}
+}
+
+// -------------------------------------------------------------------
+
+// DigestQuery returns a new Digest select query.
+func (app *BaseApp) DigestQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&Digest{})
+}
+
+// FindDigestByName returns a single Digest model by its name.
+func (app *BaseApp) FindDigestByName(name string) (*Digest, error) {
+	result := &Digest{}
+
+	err := app.DigestQuery().
+		AndWhere(dbx.HashExp{"name": name}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindDigestRecords returns the current period digest records of the specified recipient
+// (see [Digest] for the supported filter fields and placeholders).
+func (app *BaseApp) FindDigestRecords(digest *Digest, recipient *Record) ([]*Record, error) {
+	collection, err := app.FindCachedCollectionByNameOrId(digest.CollectionRef)
+	if err != nil {
+		return nil, err
+	}
+
+	q := app.RecordQuery(collection)
+
+	resolver := NewRecordFieldResolver(app, collection, &RequestInfo{Auth: recipient}, true)
+
+	if digest.Filter != "" {
+		expr, err := search.FilterData(digest.Filter).BuildExpr(resolver, dbx.Params{
+			"since": digest.Since().String(),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("invalid filter expression: %w", err)
+		}
+		q.AndWhere(expr)
+	}
+
+	if digest.Sort != "" {
+		for _, sortField := range search.ParseSortFromString(digest.Sort) {
+			expr, err := sortField.BuildExpr(resolver)
+			if err != nil {
+				return nil, err
+			}
+			if expr != "" {
+				q.AndOrderBy(expr)
+			}
+		}
+	}
+
+	resolver.UpdateQuery(q)
+
+	if digest.MaxItems > 0 {
+		q.Limit(int64(digest.MaxItems))
+	}
+
+	records := []*Record{}
+	if err := q.All(&records); err != nil {
+		return nil, err
+	}
+
+	return records, nil
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerDigestHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*Digest); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*Digest); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+}
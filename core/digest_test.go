@@ -0,0 +1,236 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestDigestTableName(t *testing.T) {
+	t.Parallel()
+
+	if v := (&core.Digest{}).TableName(); v != "_digests" {
+		t.Fatalf("Expected %q, got %q", "_digests", v)
+	}
+}
+
+func TestDigestValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo2, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newDigest := func(name string) *core.Digest {
+		digest := &core.Digest{
+			Name:                    name,
+			Schedule:                "@daily",
+			CollectionRef:           demo2.Id,
+			MaxItems:                10,
+			RecipientsCollectionRef: users.Id,
+			Subject:                 "test",
+			Body:                    "{DIGEST}",
+			ItemTemplate:            "{ITEM:title}",
+		}
+		digest.Id = "test"
+		return digest
+	}
+
+	existing := newDigest("existing")
+	existing.Id = ""
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		digest       func() *core.Digest
+		expectedErrs []string
+	}{
+		{
+			"empty",
+			func() *core.Digest { return &core.Digest{} },
+			[]string{"id", "name", "schedule", "collectionRef", "maxItems", "recipientsCollectionRef", "subject", "body", "itemTemplate"},
+		},
+		{
+			"invalid name and schedule",
+			func() *core.Digest {
+				d := newDigest("a b")
+				d.Schedule = "invalid"
+				return d
+			},
+			[]string{"name", "schedule"},
+		},
+		{
+			"duplicated name",
+			func() *core.Digest { return newDigest("existing") },
+			[]string{"name"},
+		},
+		{
+			"missing collection and non-auth recipients collection",
+			func() *core.Digest {
+				d := newDigest("new")
+				d.CollectionRef = "missing"
+				d.RecipientsCollectionRef = demo2.Id
+				return d
+			},
+			[]string{"collectionRef", "recipientsCollectionRef"},
+		},
+		{
+			"invalid filters and max items",
+			func() *core.Digest {
+				d := newDigest("new")
+				d.Filter = "missing = 1"
+				d.RecipientsFilter = "missing = 1"
+				d.MaxItems = 1001
+				return d
+			},
+			[]string{"filter", "recipientsFilter", "maxItems"},
+		},
+		{
+			"updating the existing digest",
+			func() *core.Digest { return existing },
+			[]string{},
+		},
+		{
+			"valid digest",
+			func() *core.Digest {
+				d := newDigest("new-digest")
+				d.Filter = "created > {:since} && title != @request.auth.name"
+				d.RecipientsFilter = "verified = true"
+				return d
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, app.Validate(s.digest()), s.expectedErrs)
+		})
+	}
+}
+
+func TestDigestIsDue(t *testing.T) {
+	t.Parallel()
+
+	moment := time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)
+
+	scenarios := []struct {
+		name     string
+		digest   *core.Digest
+		expected bool
+	}{
+		{"inactive", &core.Digest{Schedule: "0 10 * * *"}, false},
+		{"invalid schedule", &core.Digest{Active: true, Schedule: "invalid"}, false},
+		{"non-matching schedule", &core.Digest{Active: true, Schedule: "0 11 * * *"}, false},
+		{"matching schedule", &core.Digest{Active: true, Schedule: "0 10 * * *"}, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if v := s.digest.IsDue(moment); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestDigestSince(t *testing.T) {
+	t.Parallel()
+
+	created, _ := types.ParseDateTime("2024-01-01 10:00:00.000Z")
+	lastRun, _ := types.ParseDateTime("2024-01-02 10:00:00.000Z")
+
+	digest := &core.Digest{Created: created}
+	if v := digest.Since(); !v.Time().Equal(created.Time()) {
+		t.Fatalf("Expected the created date, got %v", v)
+	}
+
+	digest.LastRun = lastRun
+	if v := digest.Since(); !v.Time().Equal(lastRun.Time()) {
+		t.Fatalf("Expected the last run date, got %v", v)
+	}
+}
+
+func TestFindDigestRecords(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user3, err := app.FindAuthRecordByEmail("users", "test3@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastRun, _ := types.ParseDateTime("2022-10-12 11:42:52.000Z")
+
+	scenarios := []struct {
+		name      string
+		digest    *core.Digest
+		recipient *core.Record
+		expected  []string
+	}{
+		{
+			"no filter",
+			&core.Digest{CollectionRef: "demo2", Sort: "title"},
+			user1,
+			[]string{"test1", "test2", "test3"},
+		},
+		{
+			"since and auth filter (test1)",
+			&core.Digest{CollectionRef: "demo2", Sort: "title", LastRun: lastRun, Filter: "created > {:since} && title != @request.auth.name"},
+			user1,
+			[]string{"test2", "test3"},
+		},
+		{
+			"since and auth filter (test3)",
+			&core.Digest{CollectionRef: "demo2", Sort: "title", LastRun: lastRun, Filter: "created > {:since} && title != @request.auth.name"},
+			user3,
+			[]string{"test2"},
+		},
+		{
+			"max items",
+			&core.Digest{CollectionRef: "demo2", Sort: "-title", MaxItems: 2},
+			user1,
+			[]string{"test3", "test2"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			records, err := app.FindDigestRecords(s.digest, s.recipient)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(records) != len(s.expected) {
+				t.Fatalf("Expected %d records, got %d", len(s.expected), len(records))
+			}
+
+			for i, title := range s.expected {
+				if v := records[i].GetString("title"); v != title {
+					t.Fatalf("[%d] Expected title %q, got %q", i, title, v)
+				}
+			}
+		})
+	}
+}
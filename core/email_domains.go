@@ -0,0 +1,176 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+)
+
+// DisposableEmailDomains is the list with the known disposable (aka. temporary)
+// email domains that are rejected when [EmailDomainsConfig.DenyDisposable] is enabled.
+//
+// The subdomains of the listed domains are also considered disposable.
+//
+// It could be extended or replaced with your own list during the app initialization.
+var DisposableEmailDomains = map[string]struct{}{
+	"10minutemail.com":       {},
+	"20minutemail.com":       {},
+	"33mail.com":             {},
+	"discard.email":          {},
+	"dispostable.com":        {},
+	"emailondeck.com":        {},
+	"fakeinbox.com":          {},
+	"getairmail.com":         {},
+	"getnada.com":            {},
+	"guerrillamail.biz":      {},
+	"guerrillamail.com":      {},
+	"guerrillamail.de":       {},
+	"guerrillamail.info":     {},
+	"guerrillamail.net":      {},
+	"guerrillamail.org":      {},
+	"guerrillamailblock.com": {},
+	"maildrop.cc":            {},
+	"mailinator.com":         {},
+	"mailinator.net":         {},
+	"mailnesia.com":          {},
+	"mintemail.com":          {},
+	"mohmal.com":             {},
+	"mytemp.email":           {},
+	"sharklasers.com":        {},
+	"spamgourmet.com":        {},
+	"temp-mail.org":          {},
+	"tempail.com":            {},
+	"tempmail.com":           {},
+	"tempmailo.com":          {},
+	"tempr.email":            {},
+	"throwawaymail.com":      {},
+	"trashmail.com":          {},
+	"trashmail.de":           {},
+	"yopmail.com":            {},
+	"yopmail.fr":             {},
+	"yopmail.net":            {},
+}
+
+// EmailMXLookup is the function used to resolve the email domain
+// MX records when [EmailDomainsConfig.CheckMX] is enabled.
+//
+// It could be replaced with a custom resolver (e.g. in tests).
+var EmailMXLookup = net.DefaultResolver.LookupMX
+
+const emailMXLookupTimeout = 5 * time.Second
+
+// EmailDomainsConfig defines the auth collection email domain restrictions
+// applied on auth record create and email change.
+type EmailDomainsConfig struct {
+	// Allowlist requires the email domain (or its parent domain)
+	// to be one of the listed (if not empty).
+	Allowlist []string `form:"allowlist" json:"allowlist"`
+
+	// Denylist requires the email domain (and its parent domains)
+	// to NOT be one of the listed.
+	Denylist []string `form:"denylist" json:"denylist"`
+
+	// DenyDisposable rejects the known disposable email domains (see [DisposableEmailDomains]).
+	DenyDisposable bool `form:"denyDisposable" json:"denyDisposable"`
+
+	// CheckMX requires the email domain to have at least one valid MX DNS record.
+	//
+	// Temporary DNS errors are ignored and are not considered invalid.
+	CheckMX bool `form:"checkMX" json:"checkMX"`
+}
+
+// Validate makes EmailDomainsConfig validatable by implementing [validation.Validatable] interface.
+func (c EmailDomainsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Allowlist, validation.Each(is.Domain)),
+		validation.Field(&c.Denylist, validation.Each(is.Domain)),
+	)
+}
+
+// IsEnabled reports whether at least one email domain restriction is configured.
+func (c EmailDomainsConfig) IsEnabled() bool {
+	return len(c.Allowlist) > 0 || len(c.Denylist) > 0 || c.DenyDisposable || c.CheckMX
+}
+
+// CheckEmail checks whether the domain of the provided email address
+// satisfies the configured restrictions.
+//
+// Returns a [validation.Error] on failure.
+func (c EmailDomainsConfig) CheckEmail(ctx context.Context, email string) error {
+	if !c.IsEnabled() || email == "" {
+		return nil
+	}
+
+	domain := strings.ToLower(email[strings.LastIndex(email, "@")+1:])
+
+	if len(c.Allowlist) > 0 && !matchEmailDomain(domain, c.Allowlist...) {
+		return validation.NewError("validation_email_domain_not_allowed", "Email domain is not allowed.")
+	}
+
+	if len(c.Denylist) > 0 && matchEmailDomain(domain, c.Denylist...) {
+		return validation.NewError("validation_email_domain_not_allowed", "Email domain is not allowed.")
+	}
+
+	if c.DenyDisposable && isDisposableEmailDomain(domain) {
+		return validation.NewError("validation_email_domain_disposable", "Disposable email addresses are not allowed.")
+	}
+
+	if c.CheckMX && !hasValidMX(ctx, domain) {
+		return validation.NewError("validation_email_domain_no_mx", "Email domain doesn't accept emails.")
+	}
+
+	return nil
+}
+
+// matchEmailDomain reports whether domain is equal to or a subdomain of any of the listed ones.
+func matchEmailDomain(domain string, list ...string) bool {
+	for _, d := range list {
+		d = strings.ToLower(d)
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isDisposableEmailDomain(domain string) bool {
+	for {
+		if _, ok := DisposableEmailDomains[domain]; ok {
+			return true
+		}
+
+		idx := strings.IndexByte(domain, '.')
+		if idx < 0 {
+			return false
+		}
+
+		domain = domain[idx+1:]
+	}
+}
+
+func hasValidMX(ctx context.Context, domain string) bool {
+	ctx, cancel := context.WithTimeout(ctx, emailMXLookupTimeout)
+	defer cancel()
+
+	records, err := EmailMXLookup(ctx, domain)
+	if err != nil {
+		// ignore temporary and other lookup errors
+		var dnsErr *net.DNSError
+		return !errors.As(err, &dnsErr) || !dnsErr.IsNotFound
+	}
+
+	for _, r := range records {
+		// "." is the RFC 7505 null MX record
+		if r.Host != "" && r.Host != "." {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,236 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestEmailDomainsConfigValidate(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name         string
+		config       core.EmailDomainsConfig
+		expectedErrs []string
+	}{
+		{
+			"zero value",
+			core.EmailDomainsConfig{},
+			[]string{},
+		},
+		{
+			"invalid domains",
+			core.EmailDomainsConfig{
+				Allowlist: []string{"example.com", "invalid"},
+				Denylist:  []string{"@test"},
+			},
+			[]string{"allowlist", "denylist"},
+		},
+		{
+			"valid domains",
+			core.EmailDomainsConfig{
+				Allowlist:      []string{"example.com"},
+				Denylist:       []string{"sub.example.com"},
+				DenyDisposable: true,
+				CheckMX:        true,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, s.config.Validate(), s.expectedErrs)
+		})
+	}
+}
+
+func TestEmailDomainsConfigIsEnabled(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		config   core.EmailDomainsConfig
+		expected bool
+	}{
+		{core.EmailDomainsConfig{}, false},
+		{core.EmailDomainsConfig{Allowlist: []string{"example.com"}}, true},
+		{core.EmailDomainsConfig{Denylist: []string{"example.com"}}, true},
+		{core.EmailDomainsConfig{DenyDisposable: true}, true},
+		{core.EmailDomainsConfig{CheckMX: true}, true},
+	}
+
+	for i, s := range scenarios {
+		if v := s.config.IsEnabled(); v != s.expected {
+			t.Fatalf("[%d] Expected %v, got %v", i, s.expected, v)
+		}
+	}
+}
+
+func TestEmailDomainsConfigCheckEmail(t *testing.T) {
+	// not parallel because of the EmailMXLookup replacement
+	originalLookup := core.EmailMXLookup
+	defer func() {
+		core.EmailMXLookup = originalLookup
+	}()
+
+	core.EmailMXLookup = func(ctx context.Context, name string) ([]*net.MX, error) {
+		switch name {
+		case "example.com", "sub.example.com":
+			return []*net.MX{{Host: "mx.example.com.", Pref: 10}}, nil
+		case "nullmx.com":
+			return []*net.MX{{Host: ".", Pref: 0}}, nil
+		case "temporary.com":
+			return nil, &net.DNSError{Err: "timeout", Name: name, IsTemporary: true, IsTimeout: true}
+		default:
+			return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+		}
+	}
+
+	scenarios := []struct {
+		name         string
+		config       core.EmailDomainsConfig
+		email        string
+		expectedCode string
+	}{
+		{"disabled", core.EmailDomainsConfig{}, "test@missing.com", ""},
+		{"empty email", core.EmailDomainsConfig{CheckMX: true}, "", ""},
+		{
+			"allowlist match",
+			core.EmailDomainsConfig{Allowlist: []string{"example.com"}},
+			"test@EXAMPLE.com",
+			"",
+		},
+		{
+			"allowlist subdomain match",
+			core.EmailDomainsConfig{Allowlist: []string{"example.com"}},
+			"test@sub.example.com",
+			"",
+		},
+		{
+			"allowlist mismatch",
+			core.EmailDomainsConfig{Allowlist: []string{"example.com"}},
+			"test@notexample.com",
+			"validation_email_domain_not_allowed",
+		},
+		{
+			"denylist match",
+			core.EmailDomainsConfig{Denylist: []string{"example.com"}},
+			"test@sub.example.com",
+			"validation_email_domain_not_allowed",
+		},
+		{
+			"denylist mismatch",
+			core.EmailDomainsConfig{Denylist: []string{"sub.example.com"}},
+			"test@example.com",
+			"",
+		},
+		{
+			"disposable",
+			core.EmailDomainsConfig{DenyDisposable: true},
+			"test@inbox.mailinator.com",
+			"validation_email_domain_disposable",
+		},
+		{
+			"non-disposable",
+			core.EmailDomainsConfig{DenyDisposable: true},
+			"test@example.com",
+			"",
+		},
+		{
+			"valid mx",
+			core.EmailDomainsConfig{CheckMX: true},
+			"test@example.com",
+			"",
+		},
+		{
+			"null mx",
+			core.EmailDomainsConfig{CheckMX: true},
+			"test@nullmx.com",
+			"validation_email_domain_no_mx",
+		},
+		{
+			"missing mx",
+			core.EmailDomainsConfig{CheckMX: true},
+			"test@missing.com",
+			"validation_email_domain_no_mx",
+		},
+		{
+			"temporary mx lookup error",
+			core.EmailDomainsConfig{CheckMX: true},
+			"test@temporary.com",
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.config.CheckEmail(context.Background(), s.email)
+
+			if s.expectedCode == "" {
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				return
+			}
+
+			var validationErr validation.Error
+			if !errors.As(err, &validationErr) || validationErr.Code() != s.expectedCode {
+				t.Fatalf("Expected error code %q, got %v", s.expectedCode, err)
+			}
+		})
+	}
+}
+
+func TestAuthRecordEmailDomainsValidation(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	collection.EmailDomains.Denylist = []string{"example.com"}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// existing records with unchanged email
+	existing, err := app.FindAuthRecordByEmail(collection, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing.Set("name", "changed")
+	if err := app.Validate(existing); err != nil {
+		t.Fatalf("Expected the unchanged email to be valid, got %v", err)
+	}
+
+	// email change
+	existing.SetEmail("new@example.com")
+	err = app.Validate(existing)
+	if err == nil || !strings.Contains(err.Error(), "email") {
+		t.Fatalf("Expected email validation error, got %v", err)
+	}
+
+	// new record
+	record := core.NewRecord(collection)
+	record.SetEmail("new@test.com")
+	record.SetPassword("1234567890")
+	if err := app.Validate(record); err != nil {
+		t.Fatalf("Expected the new record to be valid, got %v", err)
+	}
+
+	record.SetEmail("new@sub.example.com")
+	err = app.Validate(record)
+	if err == nil || !strings.Contains(err.Error(), "email") {
+		t.Fatalf("Expected email validation error, got %v", err)
+	}
+}
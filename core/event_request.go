@@ -5,14 +5,20 @@ import (
 	"net/netip"
 	"strings"
 	"sync"
+	"time"
 
+	"github.com/pocketbase/pocketbase/tools/geoip"
+	"github.com/pocketbase/pocketbase/tools/i18n"
 	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 // Common request store keys used by the middlewares and api handlers.
 const (
 	RequestEventKeyInfoContext = "infoContext"
+	RequestEventKeyInfoCustom  = "infoCustom"
+	RequestEventKeyAuthTime    = "authTime"
 )
 
 // RequestEvent defines the PocketBase router handler event.
@@ -74,11 +80,47 @@ func (e *RequestEvent) RealIP() string {
 	return e.RemoteIP()
 }
 
+// Locale returns the preferred request locale resolved from the
+// Accept-Language header and the available app I18n() translations.
+//
+// If none of the requested locales has registered translations,
+// it fallbacks to the app Settings.Meta.Locale (could be empty).
+func (e *RequestEvent) Locale() string {
+	preferred := i18n.ParseAcceptLanguage(e.Request.Header.Get("Accept-Language"))
+
+	if locale := e.App.I18n().MatchLocale(preferred); locale != "" {
+		return locale
+	}
+
+	return i18n.NormalizeLocale(e.App.Settings().Meta.Locale)
+}
+
 // HasSuperuserAuth checks whether the current RequestEvent has superuser authentication loaded.
 func (e *RequestEvent) HasSuperuserAuth() bool {
 	return e.Auth != nil && e.Auth.IsSuperuser()
 }
 
+// SetRequestInfoCustom registers a custom request value that could be
+// accessed in the API rules and filters with `@request.custom.*`
+// (e.g. a tenant id or a feature flag resolved by a middleware).
+//
+// Note that the value should be a plain scalar, slice or map.
+func (e *RequestEvent) SetRequestInfoCustom(key string, value any) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	custom, _ := e.Get(RequestEventKeyInfoCustom).(map[string]any)
+
+	// copy to avoid modifying the previous request infos
+	custom = maps.Clone(custom)
+	if custom == nil {
+		custom = map[string]any{}
+	}
+	custom[key] = value
+
+	e.Set(RequestEventKeyInfoCustom, custom)
+}
+
 // RequestInfo parses the current request into RequestInfo instance.
 //
 // Note that the returned result is cached to avoid copying the request data multiple times
@@ -89,6 +131,8 @@ func (e *RequestEvent) RequestInfo() (*RequestInfo, error) {
 
 	if e.cachedRequestInfo != nil {
 		e.cachedRequestInfo.Auth = e.Auth
+		e.cachedRequestInfo.AuthTime = e.authTime()
+		e.cachedRequestInfo.Custom = e.infoCustom()
 
 		infoCtx, _ := e.Get(RequestEventKeyInfoContext).(string)
 		if infoCtx != "" {
@@ -141,12 +185,54 @@ func (e *RequestEvent) initRequestInfo() error {
 	}
 
 	info.Auth = e.Auth
+	info.AuthTime = e.authTime()
+	info.Custom = e.infoCustom()
+
+	if e.App != nil {
+		info.IP = e.RealIP()
+		info.Geo = e.App.ResolveGeoIP(info.IP)
+
+		if token := e.ShareToken(); token != "" {
+			info.Share, _ = e.App.FindRecordShareByToken(token)
+		}
+	}
 
 	e.cachedRequestInfo = info
 
 	return nil
 }
 
+// ShareToken returns the record share token from the
+// [RecordShareTokenHeader] request header or the "share" query parameter.
+func (e *RequestEvent) ShareToken() string {
+	if token := e.Request.Header.Get(RecordShareTokenHeader); token != "" {
+		return token
+	}
+
+	return e.Request.URL.Query().Get("share")
+}
+
+// authTime returns the original authentication time of the loaded
+// auth token (zero if there is no auth or the token doesn't have it).
+func (e *RequestEvent) authTime() types.DateTime {
+	if e.Auth == nil {
+		return types.DateTime{}
+	}
+
+	authTime, _ := e.Get(RequestEventKeyAuthTime).(types.DateTime)
+
+	return authTime
+}
+
+func (e *RequestEvent) infoCustom() map[string]any {
+	custom, _ := e.Get(RequestEventKeyInfoCustom).(map[string]any)
+	if custom == nil {
+		return map[string]any{}
+	}
+
+	return custom
+}
+
 // -------------------------------------------------------------------
 
 const (
@@ -158,6 +244,9 @@ const (
 	RequestInfoContextOAuth2        = "oauth2"
 	RequestInfoContextOTP           = "otp"
 	RequestInfoContextPasswordAuth  = "password"
+	RequestInfoContextLDAP          = "ldap"
+	RequestInfoContextSignupInvite  = "signupInvite"
+	RequestInfoContextFeed          = "feed"
 )
 
 // RequestInfo defines a HTTP request data struct, usually used
@@ -171,6 +260,22 @@ type RequestInfo struct {
 	Auth    *Record           `json:"auth"`
 	Method  string            `json:"method"`
 	Context string            `json:"context"`
+
+	// IP is the client IP address (resolved from the trusted proxy headers, if configured).
+	IP string `json:"ip"`
+
+	// AuthTime is the original authentication time of the current auth state
+	// (it is preserved on auth refresh and it is zero for tokens issued before its introduction).
+	AuthTime types.DateTime `json:"authTime"`
+
+	// Custom holds the custom request values registered with [RequestEvent.SetRequestInfoCustom].
+	Custom map[string]any `json:"custom"`
+
+	// Geo is the resolved client country and ASN (if GeoIP is enabled).
+	Geo *geoip.Info `json:"geo,omitempty"`
+
+	// Share is the active record share loaded from the request share token (if any).
+	Share *RecordShare `json:"share,omitempty"`
 }
 
 // HasSuperuserAuth checks whether the current RequestInfo instance
@@ -179,14 +284,37 @@ func (info *RequestInfo) HasSuperuserAuth() bool {
 	return info.Auth != nil && info.Auth.IsSuperuser()
 }
 
+// SessionAge returns the elapsed time since the original authentication
+// of the current auth state (or -1 if it is unknown).
+func (info *RequestInfo) SessionAge() time.Duration {
+	if info.Auth == nil || info.AuthTime.IsZero() {
+		return -1
+	}
+
+	return time.Since(info.AuthTime.Time())
+}
+
 // Clone creates a new shallow copy of the current RequestInfo and its Auth record (if any).
 func (info *RequestInfo) Clone() *RequestInfo {
 	clone := &RequestInfo{
-		Method:  info.Method,
-		Context: info.Context,
-		Query:   maps.Clone(info.Query),
-		Body:    maps.Clone(info.Body),
-		Headers: maps.Clone(info.Headers),
+		Method:   info.Method,
+		Context:  info.Context,
+		IP:       info.IP,
+		AuthTime: info.AuthTime,
+		Query:    maps.Clone(info.Query),
+		Body:     maps.Clone(info.Body),
+		Headers:  maps.Clone(info.Headers),
+		Custom:   maps.Clone(info.Custom),
+	}
+
+	if info.Geo != nil {
+		geo := *info.Geo
+		clone.Geo = &geo
+	}
+
+	if info.Share != nil {
+		share := *info.Share
+		clone.Share = &share
 	}
 
 	if info.Auth != nil {
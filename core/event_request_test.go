@@ -5,9 +5,11 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestEventRequestRealIP(t *testing.T) {
@@ -106,6 +108,53 @@ func TestEventRequestRealIP(t *testing.T) {
 	}
 }
 
+func TestEventRequestLocale(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		acceptLanguage string
+		defaultLocale  string
+		expected       string
+	}{
+		{"no header and default locale", "", "", ""},
+		{"no header with default locale", "", "pt_BR", "pt-br"},
+		{"unsupported header locales", "de,fr;q=0.9", "", ""},
+		{"unsupported header locales with default locale", "de,fr;q=0.9", "en", "en"},
+		{"supported header locale", "de,cs-CZ;q=0.9", "en", "cs"},
+		{"supported header locales priority", "cs;q=0.5,de-at;q=0.8", "en", "de-at"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, err := tests.NewTestApp()
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer app.Cleanup()
+
+			app.I18n().Set("cs", "a", "a")
+			app.I18n().Set("de-AT", "a", "a")
+			app.Settings().Meta.Locale = s.defaultLocale
+
+			event := core.RequestEvent{}
+			event.App = app
+
+			event.Request, err = http.NewRequest(http.MethodGet, "/", nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			event.Request.Header.Set("Accept-Language", s.acceptLanguage)
+
+			result := event.Locale()
+
+			if result != s.expected {
+				t.Fatalf("Expected locale %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
 func TestEventRequestHasSuperUserAuth(t *testing.T) {
 	t.Parallel()
 
@@ -189,15 +238,18 @@ func TestRequestEventRequestInfo(t *testing.T) {
 		}
 		rawStr := string(raw)
 
-		expected := `{"query":{"q1":"123","q2":"456"},"headers":{"content_type":"application/json","x_test":"test"},"body":{"a":123,"b":"test"},"auth":{"avatar":"","collectionId":"_pb_users_auth_","collectionName":"users","created":"","emailVisibility":false,"file":[],"id":"user1","name":"","rel":"","updated":"","username":"","verified":false},"method":"POST","context":"test"}`
+		expected := `{"query":{"q1":"123","q2":"456"},"headers":{"content_type":"application/json","x_test":"test"},"body":{"a":123,"b":"test"},"auth":{"avatar":"","collectionId":"_pb_users_auth_","collectionName":"users","created":"","emailVisibility":false,"file":[],"id":"user1","name":"","rel":"","updated":"","username":"","verified":false},"method":"POST","context":"test","ip":"","authTime":"","custom":{}}`
 
 		if expected != rawStr {
 			t.Fatalf("Expected\n%v\ngot\n%v", expected, rawStr)
 		}
 	})
 
-	t.Run("change user and context", func(t *testing.T) {
+	t.Run("change user, context, auth time and custom values", func(t *testing.T) {
 		event.Set(core.RequestEventKeyInfoContext, "test2")
+		event.Set(core.RequestEventKeyAuthTime, types.NowDateTime().Add(-10*time.Minute))
+		event.SetRequestInfoCustom("tenant", "abc")
+		event.SetRequestInfoCustom("level", 2)
 		event.Auth = user2
 
 		info, err := event.RequestInfo()
@@ -211,11 +263,32 @@ func TestRequestEventRequestInfo(t *testing.T) {
 		}
 		rawStr := string(raw)
 
-		expected := `{"query":{"q1":"123","q2":"456"},"headers":{"content_type":"application/json","x_test":"test"},"body":{"a":123,"b":"test"},"auth":{"avatar":"","collectionId":"_pb_users_auth_","collectionName":"users","created":"","emailVisibility":false,"file":[],"id":"user2","name":"","rel":"","updated":"","username":"","verified":false},"method":"POST","context":"test2"}`
+		expected := `{"query":{"q1":"123","q2":"456"},"headers":{"content_type":"application/json","x_test":"test"},"body":{"a":123,"b":"test"},"auth":{"avatar":"","collectionId":"_pb_users_auth_","collectionName":"users","created":"","emailVisibility":false,"file":[],"id":"user2","name":"","rel":"","updated":"","username":"","verified":false},"method":"POST","context":"test2","ip":"","authTime":"` + event.Get(core.RequestEventKeyAuthTime).(types.DateTime).String() + `","custom":{"level":2,"tenant":"abc"}}`
 
 		if expected != rawStr {
 			t.Fatalf("Expected\n%v\ngot\n%v", expected, rawStr)
 		}
+
+		if age := info.SessionAge(); age < 10*time.Minute || age > 11*time.Minute {
+			t.Fatalf("Expected ~10min session age, got %v", age)
+		}
+	})
+
+	t.Run("no auth", func(t *testing.T) {
+		event.Auth = nil
+
+		info, err := event.RequestInfo()
+		if err != nil {
+			t.Fatalf("Failed to resolve request info: %v", err)
+		}
+
+		if !info.AuthTime.IsZero() {
+			t.Fatalf("Expected zero auth time, got %v", info.AuthTime)
+		}
+
+		if age := info.SessionAge(); age != -1 {
+			t.Fatalf("Expected -1 session age, got %v", age)
+		}
 	})
 }
 
@@ -304,6 +377,7 @@ func TestRequestInfoClone(t *testing.T) {
 	clone.Headers["new_header"] = "test"
 	clone.Query["new_query"] = "test"
 	clone.Body["new_body"] = "test"
+	clone.Custom["new_custom"] = "test"
 	clone.Auth.Id = "user2" // should be a Fresh copy of the record
 
 	// check the original data
@@ -314,7 +388,7 @@ func TestRequestInfoClone(t *testing.T) {
 	}
 	originalRawStr := string(originalRaw)
 
-	expectedRawStr := `{"query":{"q1":"123","q2":"456"},"headers":{"content_type":"application/json"},"body":{"a":123,"b":"test"},"auth":{"avatar":"","collectionId":"_pb_users_auth_","collectionName":"users","created":"","emailVisibility":false,"file":[],"id":"user1","name":"","rel":"","updated":"","username":"","verified":false},"method":"POST","context":"default"}`
+	expectedRawStr := `{"query":{"q1":"123","q2":"456"},"headers":{"content_type":"application/json"},"body":{"a":123,"b":"test"},"auth":{"avatar":"","collectionId":"_pb_users_auth_","collectionName":"users","created":"","emailVisibility":false,"file":[],"id":"user1","name":"","rel":"","updated":"","username":"","verified":false},"method":"POST","context":"default","ip":"","authTime":"","custom":{}}`
 	if expectedRawStr != originalRawStr {
 		t.Fatalf("Expected original info\n%v\ngot\n%v", expectedRawStr, originalRawStr)
 	}
@@ -327,7 +401,7 @@ func TestRequestInfoClone(t *testing.T) {
 	}
 	cloneRawStr := string(cloneRaw)
 
-	expectedCloneStr := `{"query":{"new_query":"test","q1":"123","q2":"456"},"headers":{"content_type":"application/json","new_header":"test"},"body":{"a":123,"b":"test","new_body":"test"},"auth":{"avatar":"","collectionId":"_pb_users_auth_","collectionName":"users","created":"","emailVisibility":false,"file":[],"id":"user2","name":"","rel":"","updated":"","username":"","verified":false},"method":"POST","context":"default"}`
+	expectedCloneStr := `{"query":{"new_query":"test","q1":"123","q2":"456"},"headers":{"content_type":"application/json","new_header":"test"},"body":{"a":123,"b":"test","new_body":"test"},"auth":{"avatar":"","collectionId":"_pb_users_auth_","collectionName":"users","created":"","emailVisibility":false,"file":[],"id":"user2","name":"","rel":"","updated":"","username":"","verified":false},"method":"POST","context":"default","ip":"","authTime":"","custom":{"new_custom":"test"}}`
 	if expectedCloneStr != cloneRawStr {
 		t.Fatalf("Expected clone info\n%v\ngot\n%v", expectedCloneStr, cloneRawStr)
 	}
@@ -1,16 +1,20 @@
 package core
 
 import (
+	"archive/zip"
 	"context"
 	"net"
 	"net/http"
 	"time"
 
 	"github.com/pocketbase/pocketbase/tools/auth"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/ldap"
 	"github.com/pocketbase/pocketbase/tools/mailer"
 	"github.com/pocketbase/pocketbase/tools/router"
 	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/sms"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
 	"golang.org/x/crypto/acme/autocert"
 )
@@ -169,6 +173,66 @@ type MailerRecordEvent struct {
 	Meta map[string]any
 }
 
+// -------------------------------------------------------------------
+// SMS events data
+// -------------------------------------------------------------------
+
+type SMSEvent struct {
+	hook.Event
+	App App
+
+	Client  sms.Sender
+	Message *sms.Message
+}
+
+// -------------------------------------------------------------------
+// Usage events data
+// -------------------------------------------------------------------
+
+type UsageTrackEvent struct {
+	hook.Event
+	App App
+
+	Tenant string
+	Unit   string
+	Value  int64
+}
+
+type UsageRollupEvent struct {
+	hook.Event
+	App App
+
+	// Items are the flushed usage increments (not the total period values).
+	Items []*UsageRecord
+}
+
+// -------------------------------------------------------------------
+// Outbox events data
+// -------------------------------------------------------------------
+
+type OutboxMessageEvent struct {
+	hook.Event
+	App App
+
+	Message *OutboxMessage
+
+	// Handled must be set by the handler that delivered the message.
+	//
+	// If none of the handlers marks the message as handled (e.g. because there
+	// are no handlers for its topic yet), the message remains pending
+	// and it is retried later without counting it as a delivery attempt.
+	Handled bool
+}
+
+// Tags implements the [hook.Tagger] interface.
+func (e *OutboxMessageEvent) Tags() []string {
+	if e.Message == nil {
+		return nil
+	}
+
+	return []string{e.Message.Topic}
+}
+
 // -------------------------------------------------------------------
 // Model events data
 // -------------------------------------------------------------------
@@ -222,6 +286,43 @@ type RecordErrorEvent struct {
 	RecordEvent
 }
 
+type RecordTransitionEvent struct {
+	hook.Event
+	App App
+	baseRecordEventData
+	Context context.Context
+
+	// Field is the record state field which value was changed.
+	Field *StateField
+
+	// From and To are the old and the new field state.
+	From string
+	To   string
+
+	// Transition is the matching Field transition
+	// (could be nil if the field doesn't have any transitions
+	// or the record doesn't have a previous state).
+	Transition *StateTransition
+}
+
+// Tags implements the [hook.Tagger] interface.
+//
+// In addition to the record collection id and name, the tags also include
+// the "collectionName.transitionName" and "collectionId.transitionName"
+// values of the named transitions.
+func (e *RecordTransitionEvent) Tags() []string {
+	tags := e.baseRecordEventData.Tags()
+
+	if e.Record != nil && e.Transition != nil && e.Transition.Name != "" {
+		tags = append(tags,
+			e.Record.Collection().Name+"."+e.Transition.Name,
+			e.Record.Collection().Id+"."+e.Transition.Name,
+		)
+	}
+
+	return tags
+}
+
 func syncModelEventWithRecordEvent(me *ModelEvent, re *RecordEvent) {
 	me.App = re.App
 	me.Context = re.Context
@@ -375,6 +476,24 @@ type FileTokenRequestEvent struct {
 	Token string
 }
 
+// FileUploadEvent is triggered for each new record file before storing it.
+type FileUploadEvent struct {
+	hook.Event
+	App App
+	baseRecordEventData
+	Context context.Context
+
+	FileField *FileField
+	File      *filesystem.File
+
+	// Quarantine indicates whether to store the file as not servable
+	// until approved with [App.ApproveQuarantinedFile].
+	Quarantine bool
+
+	// QuarantineReason is an optional quarantine description (e.g. "pending virus scan").
+	QuarantineReason string
+}
+
 type FileDownloadRequestEvent struct {
 	hook.Event
 	*RequestEvent
@@ -404,6 +523,10 @@ type CollectionsImportRequestEvent struct {
 
 	CollectionsData []map[string]any
 	DeleteMissing   bool
+
+	// Archive is the uploaded collections archive
+	// (nil for the regular json collections import).
+	Archive *zip.Reader
 }
 
 type CollectionRequestEvent struct {
@@ -424,6 +547,18 @@ type RealtimeConnectRequestEvent struct {
 
 	// note: modifying it after the connect has no effect
 	IdleTimeout time.Duration
+
+	// HeartbeatInterval specifies the interval of the SSE keep-alive
+	// comments sent to the client (zero or negative value disables them).
+	//
+	// note: modifying it after the connect has no effect
+	HeartbeatInterval time.Duration
+
+	// RetryInterval specifies the SSE "retry:" reconnection hint sent
+	// to the client on connect (zero or negative value skips it).
+	//
+	// note: modifying it after the connect has no effect
+	RetryInterval time.Duration
 }
 
 type RealtimeMessageEvent struct {
@@ -464,6 +599,26 @@ type RecordRequestEvent struct {
 	Record *Record
 }
 
+// RecordSyncConflictRequestEvent defines the conflict event of a
+// single pushed offline sync mutation resolved with the "merge" policy.
+type RecordSyncConflictRequestEvent struct {
+	hook.Event
+	*RequestEvent
+	baseCollectionEventData
+
+	// Record is the current server record state
+	// (nil if the record was deleted in the meantime).
+	Record *Record
+
+	// Action is the client mutation action (create, update or delete).
+	Action string
+
+	// Data is the client mutation data that will be applied.
+	//
+	// It could be modified to merge the client and server changes.
+	Data map[string]any
+}
+
 type RecordEnrichEvent struct {
 	hook.Event
 	App App
@@ -472,6 +627,22 @@ type RecordEnrichEvent struct {
 	RequestInfo *RequestInfo
 }
 
+// RecordRequestTransformEvent defines the event of the
+// submitted API record create or update data transformation.
+type RecordRequestTransformEvent struct {
+	hook.Event
+	*RequestEvent
+	baseCollectionEventData
+
+	// Record is the record in which the submitted data will be loaded
+	// (it is a new record for the create requests).
+	Record *Record
+
+	// Data is the submitted record data (with resolved field modifiers)
+	// that could be modified before loading it in the record.
+	Data map[string]any
+}
+
 // -------------------------------------------------------------------
 // Auth Record API events data
 // -------------------------------------------------------------------
@@ -505,6 +676,63 @@ type RecordAuthRequestEvent struct {
 	AuthMethod string
 }
 
+// RecordAuthTokenClaimsEvent is triggered before issuing a new
+// auth token and allows appending custom claims to its payload.
+type RecordAuthTokenClaimsEvent struct {
+	hook.Event
+	App App
+	baseRecordEventData
+
+	// Refreshable indicates whether the token is a regular refreshable
+	// auth token or a static one (e.g. impersonate token).
+	Refreshable bool
+
+	// Claims are the custom claims that will be appended to the token payload
+	// (the reserved system claims like "id", "type", "exp" are not allowed).
+	Claims map[string]any
+}
+
+// Known [RecordAuthFailureEvent.Reason] values.
+const (
+	AuthFailureReasonUnknownIdentity     = "unknown_identity"
+	AuthFailureReasonInvalidPassword     = "invalid_password"
+	AuthFailureReasonInvalidOTP          = "invalid_otp"
+	AuthFailureReasonExpiredOTP          = "expired_otp"
+	AuthFailureReasonTooManyAttempts     = "too_many_attempts"
+	AuthFailureReasonOAuth2Verification  = "oauth2_verification"
+	AuthFailureReasonOAuth2TokenExchange = "oauth2_token_exchange"
+	AuthFailureReasonOAuth2UserFetch     = "oauth2_user_fetch"
+	AuthFailureReasonOAuth2Forbidden     = "oauth2_forbidden"
+	AuthFailureReasonLDAPUnavailable     = "ldap_unavailable"
+)
+
+type RecordAuthFailureEvent struct {
+	hook.Event
+	*RequestEvent
+	baseCollectionEventData
+
+	// Record is the related auth record (could be nil if unknown).
+	Record *Record
+
+	// AuthMethod is the auth method of the failed attempt
+	// (usually one of the MFAMethod* constants).
+	AuthMethod string
+
+	// Reason is a short machine-readable failure reason
+	// (usually one of the AuthFailureReason* constants).
+	Reason string
+
+	// Identity is the submitted identity (if any),
+	// e.g. the password auth identity field value.
+	Identity string
+
+	// Provider is the OAuth2 provider name (if any).
+	Provider string
+
+	// Error is the underlying failure error (if any).
+	Error error
+}
+
 type RecordAuthWithPasswordRequestEvent struct {
 	hook.Event
 	*RequestEvent
@@ -527,6 +755,35 @@ type RecordAuthWithOAuth2RequestEvent struct {
 	OAuth2User     *auth.AuthUser
 	CreateData     map[string]any
 	IsNewRecord    bool
+
+	// PKCEVerified indicates whether the authorization code
+	// was exchanged with a PKCE code verifier.
+	PKCEVerified bool
+
+	// NonceVerified indicates whether the provider id_token "nonce"
+	// claim was validated against the expected one.
+	NonceVerified bool
+}
+
+type RecordAuthWithLDAPRequestEvent struct {
+	hook.Event
+	*RequestEvent
+	baseCollectionEventData
+
+	// Username is the submitted LDAP username.
+	Username string
+
+	// LDAPEntry is the authenticated LDAP directory user entry.
+	LDAPEntry *ldap.Entry
+
+	// Record is the linked auth record (nil if a new one should be created).
+	Record *Record
+
+	// CreateData is the data of the new auth record (if IsNewRecord is true).
+	CreateData map[string]any
+
+	// IsNewRecord indicates whether a new auth record will be created.
+	IsNewRecord bool
 }
 
 type RecordAuthRefreshRequestEvent struct {
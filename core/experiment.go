@@ -0,0 +1,368 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	ExperimentsTableName           = "_experiments"
+	ExperimentAssignmentsTableName = "_experimentAssignments"
+)
+
+var (
+	_ Model         = (*Experiment)(nil)
+	_ PostValidator = (*Experiment)(nil)
+	_ Model         = (*ExperimentAssignment)(nil)
+)
+
+var experimentKeyRegex = regexp.MustCompile(`^\w+$`)
+
+// ExperimentVariant defines a single weighted experiment variant.
+type ExperimentVariant struct {
+	Name   string `json:"name"`
+	Weight int    `json:"weight"`
+}
+
+// Experiment defines a single A/B experiment.
+//
+// Subjects (the auth record or the [FeatureFlagSubjectHeader] value for guests)
+// are assigned with one of the weighted Variants on their first evaluation and
+// the assignment is persisted, meaning that later weights changes
+// affect only the new subjects.
+//
+// If FlagKey is set, only the subjects with enabled evaluation of the
+// related feature flag are enrolled in the experiment.
+type Experiment struct {
+	BaseModel
+
+	Key         string                             `db:"key" json:"key"`
+	Description string                             `db:"description" json:"description"`
+	Active      bool                               `db:"active" json:"active"`
+	FlagKey     string                             `db:"flagKey" json:"flagKey"`
+	Variants    types.JSONArray[ExperimentVariant] `db:"variants" json:"variants"`
+	Created     types.DateTime                     `db:"created" json:"created"`
+	Updated     types.DateTime                     `db:"updated" json:"updated"`
+}
+
+// TableName returns the Experiment model table name.
+func (m *Experiment) TableName() string {
+	return ExperimentsTableName
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *Experiment) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(
+			&m.Key,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(experimentKeyRegex),
+			validation.By(m.checkUniqueKey(app)),
+		),
+		validation.Field(&m.Description, validation.Length(0, 1000)),
+		validation.Field(&m.FlagKey, validation.By(m.checkFlagKey(app))),
+		validation.Field(&m.Variants, validation.Required, validation.Length(1, 100), validation.By(m.checkVariants)),
+	)
+}
+
+func (m *Experiment) checkUniqueKey(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		var exists int
+
+		err := app.ExperimentQuery().
+			Select("(1)").
+			AndWhere(dbx.HashExp{"key": v}).
+			AndWhere(dbx.Not(dbx.HashExp{"id": m.Id})).
+			Limit(1).
+			Row(&exists)
+
+		if exists > 0 || (err != nil && !errors.Is(err, sql.ErrNoRows)) {
+			return validation.NewError("validation_experiment_key_exists", "Experiment with the provided key already exists.")
+		}
+
+		return nil
+	}
+}
+
+func (m *Experiment) checkFlagKey(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		if _, err := app.FindFeatureFlagByKey(v); err != nil {
+			return validation.NewError("validation_invalid_feature_flag", "Missing or invalid feature flag.")
+		}
+
+		return nil
+	}
+}
+
+func (m *Experiment) checkVariants(value any) error {
+	variants, _ := value.(types.JSONArray[ExperimentVariant])
+
+	var totalWeight int
+	names := make(map[string]struct{}, len(variants))
+
+	for _, v := range variants {
+		if v.Name == "" || len(v.Name) > 100 {
+			return validation.NewError("validation_invalid_experiment_variant_name", "Each variant must have a non-empty name with max 100 characters.")
+		}
+
+		if _, ok := names[v.Name]; ok {
+			return validation.NewError("validation_duplicated_experiment_variant", "Duplicated variant name.").
+				SetParams(map[string]any{"name": v.Name})
+		}
+		names[v.Name] = struct{}{}
+
+		if v.Weight < 0 {
+			return validation.NewError("validation_invalid_experiment_variant_weight", "The variant weight must be a non-negative number.")
+		}
+		totalWeight += v.Weight
+	}
+
+	if len(variants) > 0 && totalWeight <= 0 {
+		return validation.NewError("validation_invalid_experiment_variant_weight", "At least one variant must have a positive weight.")
+	}
+
+	return nil
+}
+
+// pickVariant returns the stable weighted variant of the subject.
+func (m *Experiment) pickVariant(subject string) string {
+	var totalWeight int
+	for _, v := range m.Variants {
+		totalWeight += v.Weight
+	}
+
+	if totalWeight <= 0 {
+		return ""
+	}
+
+	bucket := featureFlagBucket("experiment:"+m.Key, subject, totalWeight)
+
+	for _, v := range m.Variants {
+		if bucket < v.Weight {
+			return v.Name
+		}
+		bucket -= v.Weight
+	}
+
+	return ""
+}
+
+// ExperimentAssignment defines a single persisted experiment variant assignment.
+type ExperimentAssignment struct {
+	BaseModel
+
+	ExperimentRef string         `db:"experimentRef" json:"experimentRef"`
+	Subject       string         `db:"subject" json:"subject"`
+	Variant       string         `db:"variant" json:"variant"`
+	Created       types.DateTime `db:"created" json:"created"`
+}
+
+// TableName returns the ExperimentAssignment model table name.
+func (m *ExperimentAssignment) TableName() string {
+	return ExperimentAssignmentsTableName
+}
+
+// -------------------------------------------------------------------
+
+// ExperimentQuery returns a new Experiment select query.
+func (app *BaseApp) ExperimentQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&Experiment{})
+}
+
+// FindExperimentByKey returns a single Experiment model by its key.
+func (app *BaseApp) FindExperimentByKey(key string) (*Experiment, error) {
+	result := &Experiment{}
+
+	err := app.ExperimentQuery().
+		AndWhere(dbx.HashExp{"key": key}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AssignExperimentVariant returns the experiment variant of the requestInfo subject,
+// persisting a new weighted variant assignment if the subject doesn't have one yet.
+//
+// Returns an empty string if the subject is not enrolled in the experiment
+// (eg. inactive experiment, disabled related feature flag or missing subject).
+func (app *BaseApp) AssignExperimentVariant(experiment *Experiment, requestInfo *RequestInfo) (string, error) {
+	if !experiment.Active {
+		return "", nil
+	}
+
+	subject := featureFlagSubject(requestInfo)
+	if subject == "" {
+		return "", nil
+	}
+
+	if experiment.FlagKey != "" {
+		evaluation, err := app.EvaluateFeatureFlag(experiment.FlagKey, requestInfo)
+		if err != nil || !evaluation.Enabled {
+			return "", err
+		}
+	}
+
+	assignment, err := app.findExperimentAssignment(experiment, subject)
+	if err == nil {
+		return assignment.Variant, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return "", err
+	}
+
+	assignment = &ExperimentAssignment{
+		ExperimentRef: experiment.Id,
+		Subject:       subject,
+		Variant:       experiment.pickVariant(subject),
+	}
+
+	if err := app.Save(assignment); err != nil {
+		// concurrently assigned
+		if existing, findErr := app.findExperimentAssignment(experiment, subject); findErr == nil {
+			return existing.Variant, nil
+		}
+
+		return "", err
+	}
+
+	return assignment.Variant, nil
+}
+
+// TrackExperimentExposure assigns the experiment variant of the requestInfo subject
+// (see [App.AssignExperimentVariant]) and writes it as "experimentExposure" info log.
+//
+// The exposure is not logged if the subject is not enrolled in the experiment
+// or the logs persistence is disabled.
+func (app *BaseApp) TrackExperimentExposure(experiment *Experiment, requestInfo *RequestInfo) (string, error) {
+	variant, err := app.AssignExperimentVariant(experiment, requestInfo)
+	if err != nil || variant == "" {
+		return variant, err
+	}
+
+	// no logs retention
+	if app.Settings().Logs.MaxDays == 0 {
+		return variant, nil
+	}
+
+	app.Logger().Info(
+		"Experiment exposure",
+		slog.String("type", "experimentExposure"),
+		slog.String("experiment", experiment.Key),
+		slog.String("variant", variant),
+		slog.String("subject", featureFlagSubject(requestInfo)),
+	)
+
+	return variant, nil
+}
+
+func (app *BaseApp) findExperimentAssignment(experiment *Experiment, subject string) (*ExperimentAssignment, error) {
+	result := &ExperimentAssignment{}
+
+	err := app.ModelQuery(result).
+		AndWhere(dbx.HashExp{
+			"experimentRef": experiment.Id,
+			"subject":       subject,
+		}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerExperimentHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			switch m := e.Model.(type) {
+			case *Experiment:
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			case *ExperimentAssignment:
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*Experiment); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// delete the experiment assignments together with the experiment
+	app.OnModelDeleteExecute().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if m, ok := e.Model.(*Experiment); ok {
+				_, err := e.App.NonconcurrentDB().Delete(ExperimentAssignmentsTableName, dbx.HashExp{"experimentRef": m.Id}).Execute()
+				return err
+			}
+
+			return nil
+		},
+		Priority: 99,
+	})
+
+	// delete the auth record experiment assignments
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil || !e.Record.Collection().IsAuth() {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(ExperimentAssignmentsTableName, dbx.HashExp{
+				"subject": e.Record.Collection().Id + ":" + e.Record.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+}
@@ -0,0 +1,316 @@
+package core_test
+
+import (
+	"context"
+	"strconv"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/logger"
+)
+
+func TestExperimentTableName(t *testing.T) {
+	t.Parallel()
+
+	if v := (&core.Experiment{}).TableName(); v != "_experiments" {
+		t.Fatalf("Expected %q, got %q", "_experiments", v)
+	}
+
+	if v := (&core.ExperimentAssignment{}).TableName(); v != "_experimentAssignments" {
+		t.Fatalf("Expected %q, got %q", "_experimentAssignments", v)
+	}
+}
+
+func TestExperimentValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := app.Save(&core.FeatureFlag{Key: "flag", Enabled: true, Rollout: 100}); err != nil {
+		t.Fatal(err)
+	}
+
+	existing := &core.Experiment{Key: "test", Variants: []core.ExperimentVariant{{Name: "a", Weight: 1}}}
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	newExperiment := func(key string, flagKey string, variants ...core.ExperimentVariant) *core.Experiment {
+		experiment := &core.Experiment{Key: key, FlagKey: flagKey, Variants: variants}
+		experiment.Id = "test"
+		return experiment
+	}
+
+	scenarios := []struct {
+		name         string
+		experiment   *core.Experiment
+		expectedErrs []string
+	}{
+		{
+			"empty",
+			&core.Experiment{},
+			[]string{"id", "key", "variants"},
+		},
+		{
+			"invalid key and missing flag",
+			newExperiment("a.b", "missing", core.ExperimentVariant{Name: "a", Weight: 1}),
+			[]string{"key", "flagKey"},
+		},
+		{
+			"duplicated key",
+			newExperiment("test", "", core.ExperimentVariant{Name: "a", Weight: 1}),
+			[]string{"key"},
+		},
+		{
+			"empty variant name",
+			newExperiment("new", "", core.ExperimentVariant{Name: "", Weight: 1}),
+			[]string{"variants"},
+		},
+		{
+			"duplicated variant name",
+			newExperiment("new", "", core.ExperimentVariant{Name: "a", Weight: 1}, core.ExperimentVariant{Name: "a", Weight: 1}),
+			[]string{"variants"},
+		},
+		{
+			"negative variant weight",
+			newExperiment("new", "", core.ExperimentVariant{Name: "a", Weight: 2}, core.ExperimentVariant{Name: "b", Weight: -1}),
+			[]string{"variants"},
+		},
+		{
+			"zero total weight",
+			newExperiment("new", "", core.ExperimentVariant{Name: "a", Weight: 0}),
+			[]string{"variants"},
+		},
+		{
+			"updating the existing experiment",
+			existing,
+			[]string{},
+		},
+		{
+			"valid experiment",
+			newExperiment("new_experiment", "flag", core.ExperimentVariant{Name: "a", Weight: 1}, core.ExperimentVariant{Name: "b", Weight: 0}),
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, app.Validate(s.experiment), s.expectedErrs)
+		})
+	}
+}
+
+func TestAssignExperimentVariant(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Save(&core.FeatureFlag{Key: "users_only", Enabled: true, Rollout: 100, Rule: "@request.auth.id != ''"}); err != nil {
+		t.Fatal(err)
+	}
+
+	inactive := &core.Experiment{Key: "inactive", Variants: []core.ExperimentVariant{{Name: "a", Weight: 1}}}
+	gated := &core.Experiment{Key: "gated", Active: true, FlagKey: "users_only", Variants: []core.ExperimentVariant{{Name: "a", Weight: 1}}}
+	weighted := &core.Experiment{Key: "weighted", Active: true, Variants: []core.ExperimentVariant{{Name: "a", Weight: 0}, {Name: "b", Weight: 1}}}
+	for _, experiment := range []*core.Experiment{inactive, gated, weighted} {
+		if err := app.Save(experiment); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		name        string
+		experiment  *core.Experiment
+		requestInfo *core.RequestInfo
+		expected    string
+	}{
+		{"inactive", inactive, &core.RequestInfo{Auth: user}, ""},
+		{"missing subject", weighted, &core.RequestInfo{}, ""},
+		{"nil requestInfo", weighted, nil, ""},
+		{"disabled flag", gated, &core.RequestInfo{Headers: map[string]string{"x_device_id": "abc"}}, ""},
+		{"enabled flag", gated, &core.RequestInfo{Auth: user}, "a"},
+		{"weighted (auth)", weighted, &core.RequestInfo{Auth: user}, "b"},
+		{"weighted (device)", weighted, &core.RequestInfo{Headers: map[string]string{"x_device_id": "abc"}}, "b"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			variant, err := app.AssignExperimentVariant(s.experiment, s.requestInfo)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if variant != s.expected {
+				t.Fatalf("Expected variant %q, got %q", s.expected, variant)
+			}
+		})
+	}
+
+	t.Run("persisted assignments", func(t *testing.T) {
+		var total int
+		err := app.DB().Select("count(*)").From(core.ExperimentAssignmentsTableName).Row(&total)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// enabled flag, weighted (auth) and weighted (device)
+		if total != 3 {
+			t.Fatalf("Expected 3 persisted assignments, got %d", total)
+		}
+
+		// change the weights so that only "a" could be picked for new subjects
+		weighted.Variants = []core.ExperimentVariant{{Name: "a", Weight: 1}, {Name: "b", Weight: 0}}
+		if err := app.Save(weighted); err != nil {
+			t.Fatal(err)
+		}
+
+		variant, err := app.AssignExperimentVariant(weighted, &core.RequestInfo{Auth: user})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if variant != "b" {
+			t.Fatalf("Expected the persisted variant %q, got %q", "b", variant)
+		}
+
+		variant, err = app.AssignExperimentVariant(weighted, &core.RequestInfo{Headers: map[string]string{"x_device_id": "new"}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if variant != "a" {
+			t.Fatalf("Expected the new subject variant %q, got %q", "a", variant)
+		}
+	})
+
+	t.Run("weights distribution", func(t *testing.T) {
+		experiment := &core.Experiment{Key: "distribution", Active: true, Variants: []core.ExperimentVariant{{Name: "a", Weight: 1}, {Name: "b", Weight: 3}}}
+		if err := app.Save(experiment); err != nil {
+			t.Fatal(err)
+		}
+
+		counts := map[string]int{}
+		for i := 0; i < 1000; i++ {
+			variant, err := app.AssignExperimentVariant(experiment, &core.RequestInfo{Headers: map[string]string{"x_device_id": "device" + strconv.Itoa(i)}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			counts[variant]++
+		}
+
+		if counts["a"] < 150 || counts["a"] > 350 || counts["a"]+counts["b"] != 1000 {
+			t.Fatalf("Expected ~25%% a and ~75%% b variants, got %v", counts)
+		}
+	})
+
+	t.Run("delete cascade", func(t *testing.T) {
+		if err := app.Delete(user); err != nil {
+			t.Fatal(err)
+		}
+
+		var total int
+		err := app.DB().Select("count(*)").From(core.ExperimentAssignmentsTableName).
+			AndWhere(dbx.HashExp{"subject": user.Collection().Id + ":" + user.Id}).
+			Row(&total)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 0 {
+			t.Fatalf("Expected the auth record assignments to be deleted, got %d", total)
+		}
+
+		if err := app.Delete(weighted); err != nil {
+			t.Fatal(err)
+		}
+
+		err = app.DB().Select("count(*)").From(core.ExperimentAssignmentsTableName).
+			AndWhere(dbx.HashExp{"experimentRef": weighted.Id}).
+			Row(&total)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total != 0 {
+			t.Fatalf("Expected the experiment assignments to be deleted, got %d", total)
+		}
+	})
+}
+
+func TestTrackExperimentExposure(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	experiment := &core.Experiment{Key: "test", Active: true, Variants: []core.ExperimentVariant{{Name: "a", Weight: 1}}}
+	if err := app.Save(experiment); err != nil {
+		t.Fatal(err)
+	}
+
+	countExposureLogs := func() int {
+		handler, ok := app.Logger().Handler().(*logger.BatchHandler)
+		if !ok {
+			t.Fatalf("Expected BatchHandler, got %v", app.Logger().Handler())
+		}
+		if err := handler.WriteAll(context.Background()); err != nil {
+			t.Fatalf("Failed to write all logs: %v", err)
+		}
+
+		var total int
+		err := app.AuxModelQuery(&core.Log{}).
+			Select("count(*)").
+			AndWhere(dbx.NewExp("json_extract([[data]], '$.type') = 'experimentExposure'")).
+			AndWhere(dbx.NewExp("json_extract([[data]], '$.experiment') = 'test'")).
+			AndWhere(dbx.NewExp("json_extract([[data]], '$.variant') = 'a'")).
+			Row(&total)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return total
+	}
+
+	// not enrolled
+	variant, err := app.TrackExperimentExposure(experiment, &core.RequestInfo{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if variant != "" {
+		t.Fatalf("Expected empty variant, got %q", variant)
+	}
+	if total := countExposureLogs(); total != 0 {
+		t.Fatalf("Expected no exposure logs, got %d", total)
+	}
+
+	// enrolled with disabled logs retention
+	app.Settings().Logs.MaxDays = 0
+	variant, err = app.TrackExperimentExposure(experiment, &core.RequestInfo{Headers: map[string]string{"x_device_id": "abc"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if variant != "a" {
+		t.Fatalf("Expected variant %q, got %q", "a", variant)
+	}
+	if total := countExposureLogs(); total != 0 {
+		t.Fatalf("Expected no exposure logs, got %d", total)
+	}
+
+	// enrolled
+	app.Settings().Logs.MaxDays = 7
+	variant, err = app.TrackExperimentExposure(experiment, &core.RequestInfo{Headers: map[string]string{"x_device_id": "abc"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if variant != "a" {
+		t.Fatalf("Expected variant %q, got %q", "a", variant)
+	}
+	if total := countExposureLogs(); total != 1 {
+		t.Fatalf("Expected 1 exposure log, got %d", total)
+	}
+}
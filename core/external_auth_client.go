@@ -0,0 +1,179 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/auth"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/oauth2"
+)
+
+// ErrExternalAuthTokenExpired is returned when the stored OAuth2
+// access token is expired and there is no refresh token to renew it.
+var ErrExternalAuthTokenExpired = errors.New("the stored OAuth2 access token is expired and cannot be refreshed")
+
+const storeKeyExternalAuthRefreshLocks = "@externalAuthRefreshLocks"
+
+// ProviderClient returns an http client that sends requests to the provider APIs
+// on behalf of the specified auth record using its stored OAuth2 tokens
+// (aka. the tokens from the record last "auth-with-oauth2" request).
+//
+// The stored access token is transparently refreshed and persisted
+// when it is expired (before the first and any of the following requests).
+//
+// Example usage:
+//
+//	client, err := app.ProviderClient(user, "google")
+//	if err != nil {
+//	    return err
+//	}
+//
+//	res, err := client.Get("https://www.googleapis.com/calendar/v3/users/me/calendarList")
+func (app *BaseApp) ProviderClient(authRecord *Record, providerName string) (*http.Client, error) {
+	collection := authRecord.Collection()
+
+	providerConfig, ok := collection.OAuth2.GetProviderConfig(providerName)
+	if !ok {
+		return nil, fmt.Errorf("missing %q OAuth2 provider config", providerName)
+	}
+
+	provider, err := providerConfig.InitProviderWithDefaults(app.Settings().OAuth2HTTPClient)
+	if err != nil {
+		return nil, err
+	}
+
+	externalAuth, err := app.FindFirstExternalAuthByExpr(dbx.HashExp{
+		"collectionRef": collection.Id,
+		"recordRef":     authRecord.Id,
+		"provider":      providerName,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	source := &externalAuthTokenSource{
+		app:          app,
+		provider:     provider,
+		externalAuth: externalAuth,
+	}
+
+	// resolve the initial token to report early any missing or expired tokens
+	token, err := source.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := provider.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if provider.HTTPClient() != nil {
+		ctx = context.WithValue(ctx, oauth2.HTTPClient, provider.HTTPClient())
+	}
+
+	client := oauth2.NewClient(ctx, oauth2.ReuseTokenSource(token, source))
+
+	// the oauth2 client reuses only the transport of the context client
+	if provider.HTTPClient() != nil {
+		client.Timeout = provider.HTTPClient().Timeout
+	}
+
+	return client, nil
+}
+
+// externalAuthRefreshLocks holds the per ExternalAuth token refresh locks.
+type externalAuthRefreshLocks struct {
+	items map[string]*sync.Mutex // external auth id -> lock
+	mu    sync.Mutex
+}
+
+// externalAuthRefreshLock returns the app level token refresh lock
+// of the ExternalAuth with the specified id.
+func externalAuthRefreshLock(app App, externalAuthId string) *sync.Mutex {
+	locks := app.Store().GetOrSet(storeKeyExternalAuthRefreshLocks, func() any {
+		return &externalAuthRefreshLocks{items: map[string]*sync.Mutex{}}
+	}).(*externalAuthRefreshLocks)
+
+	locks.mu.Lock()
+	defer locks.mu.Unlock()
+
+	lock, ok := locks.items[externalAuthId]
+	if !ok {
+		lock = &sync.Mutex{}
+		locks.items[externalAuthId] = lock
+	}
+
+	return lock
+}
+
+// externalAuthTokenSource is an [oauth2.TokenSource] that returns
+// the stored ExternalAuth OAuth2 token and refreshes it when expired.
+type externalAuthTokenSource struct {
+	app          App
+	provider     auth.Provider
+	externalAuth *ExternalAuth
+}
+
+// Token implements the [oauth2.TokenSource] interface.
+//
+// The token refreshes of the same ExternalAuth are serialized across all token sources
+// to prevent reusing an already rotated refresh token (e.g. with concurrent ProviderClient calls).
+func (s *externalAuthTokenSource) Token() (*oauth2.Token, error) {
+	lock := externalAuthRefreshLock(s.app, s.externalAuth.Id)
+	lock.Lock()
+	defer lock.Unlock()
+
+	token := s.storedToken()
+	if token.Valid() {
+		return token, nil
+	}
+
+	// reload the external auth in case its token was already refreshed
+	// by another token source while waiting for the lock
+	externalAuth, err := s.app.FindFirstExternalAuthByExpr(dbx.HashExp{"id": s.externalAuth.Id})
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload the stored OAuth2 token: %w", err)
+	}
+	s.externalAuth = externalAuth
+
+	token = s.storedToken()
+	if token.Valid() {
+		return token, nil
+	}
+
+	if token.RefreshToken == "" {
+		return nil, ErrExternalAuthTokenExpired
+	}
+
+	refreshed, err := s.provider.RefreshToken(token.RefreshToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to refresh the stored OAuth2 token: %w", err)
+	}
+
+	expiry, _ := types.ParseDateTime(refreshed.Expiry)
+
+	s.externalAuth.SetAccessToken(refreshed.AccessToken)
+	s.externalAuth.SetExpiry(expiry)
+	if refreshed.RefreshToken != "" {
+		s.externalAuth.SetRefreshToken(refreshed.RefreshToken)
+	}
+
+	if err := s.app.Save(s.externalAuth); err != nil {
+		return nil, fmt.Errorf("failed to persist the refreshed OAuth2 token: %w", err)
+	}
+
+	return refreshed, nil
+}
+
+func (s *externalAuthTokenSource) storedToken() *oauth2.Token {
+	return &oauth2.Token{
+		AccessToken:  s.externalAuth.AccessToken(),
+		RefreshToken: s.externalAuth.RefreshToken(),
+		Expiry:       s.externalAuth.Expiry().Time(),
+	}
+}
@@ -0,0 +1,322 @@
+package core_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestProviderClient(t *testing.T) {
+	t.Parallel()
+
+	var refreshCalls atomic.Int32
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshCalls.Add(1)
+
+		if r.FormValue("refresh_token") != "test_refresh" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"new_access","refresh_token":"new_refresh","token_type":"bearer","expires_in":3600}`))
+	})
+	mux.HandleFunc("/api", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	past := types.NowDateTime().Add(-1 * time.Hour)
+	future := types.NowDateTime().Add(1 * time.Hour)
+
+	scenarios := []struct {
+		name                 string
+		providerName         string
+		accessToken          string
+		refreshToken         string
+		expiry               types.DateTime
+		expectError          bool
+		expectedAuthHeader   string
+		expectedRefreshCalls int32
+		expectedRefreshToken string
+		expectExpiredErr     bool
+	}{
+		{
+			"missing provider config",
+			"missing",
+			"test_access",
+			"test_refresh",
+			future,
+			true,
+			"",
+			0,
+			"",
+			false,
+		},
+		{
+			"valid access token",
+			"gitlab",
+			"test_access",
+			"test_refresh",
+			future,
+			false,
+			"Bearer test_access",
+			0,
+			"test_refresh",
+			false,
+		},
+		{
+			"access token without expiry",
+			"gitlab",
+			"test_access",
+			"",
+			types.DateTime{},
+			false,
+			"Bearer test_access",
+			0,
+			"",
+			false,
+		},
+		{
+			"expired access token without refresh token",
+			"gitlab",
+			"test_access",
+			"",
+			past,
+			true,
+			"",
+			0,
+			"",
+			true,
+		},
+		{
+			"expired access token with invalid refresh token",
+			"gitlab",
+			"test_access",
+			"invalid",
+			past,
+			true,
+			"",
+			2, // the oauth2 auth style autodetection retries the failed request
+			"invalid",
+			false,
+		},
+		{
+			"expired access token with valid refresh token",
+			"gitlab",
+			"test_access",
+			"test_refresh",
+			past,
+			false,
+			"Bearer new_access",
+			1,
+			"new_refresh",
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			refreshCalls.Store(0)
+
+			user, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			user.Collection().OAuth2.Providers = []core.OAuth2ProviderConfig{{
+				Name:         "gitlab",
+				ClientId:     "test_client",
+				ClientSecret: "test_secret",
+				TokenURL:     server.URL + "/token",
+			}}
+
+			ea := core.NewExternalAuth(app)
+			ea.SetCollectionRef(user.Collection().Id)
+			ea.SetRecordRef(user.Id)
+			ea.SetProvider("gitlab")
+			ea.SetProviderId("test_id")
+			ea.SetAccessToken(s.accessToken)
+			ea.SetRefreshToken(s.refreshToken)
+			ea.SetExpiry(s.expiry)
+			if err := app.Save(ea); err != nil {
+				t.Fatal(err)
+			}
+
+			client, err := app.ProviderClient(user, s.providerName)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if s.expectExpiredErr && !errors.Is(err, core.ErrExternalAuthTokenExpired) {
+				t.Fatalf("Expected ErrExternalAuthTokenExpired, got %v", err)
+			}
+
+			if v := refreshCalls.Load(); v != s.expectedRefreshCalls {
+				t.Fatalf("Expected %d refresh calls, got %d", s.expectedRefreshCalls, v)
+			}
+
+			if !hasErr {
+				res, err := client.Get(server.URL + "/api")
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer res.Body.Close()
+
+				body, _ := io.ReadAll(res.Body)
+				if string(body) != s.expectedAuthHeader {
+					t.Fatalf("Expected Authorization header %q, got %q", s.expectedAuthHeader, body)
+				}
+			}
+
+			if s.expectedRefreshToken == "" {
+				return
+			}
+
+			// check the persisted tokens
+			updated, err := app.FindFirstExternalAuthByExpr(dbx.HashExp{"id": ea.Id})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := updated.RefreshToken(); v != s.expectedRefreshToken {
+				t.Fatalf("Expected stored refresh token %q, got %q", s.expectedRefreshToken, v)
+			}
+
+			if s.expectedRefreshCalls > 0 && !hasErr {
+				if v := updated.AccessToken(); v != "new_access" {
+					t.Fatalf("Expected stored access token %q, got %q", "new_access", v)
+				}
+
+				if !updated.Expiry().Time().After(time.Now()) {
+					t.Fatalf("Expected future stored expiry, got %q", updated.Expiry())
+				}
+			}
+		})
+	}
+
+	t.Run("missing external auth", func(t *testing.T) {
+		app, _ := tests.NewTestApp()
+		defer app.Cleanup()
+
+		user, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		user.Collection().OAuth2.Providers = []core.OAuth2ProviderConfig{{Name: "github"}}
+
+		_, err = app.ProviderClient(user, "github")
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
+
+func TestProviderClientConcurrentRefresh(t *testing.T) {
+	t.Parallel()
+
+	var refreshCalls atomic.Int32
+	var rotated atomic.Bool
+
+	// simulates a provider that rotates (aka. invalidates) the used refresh token
+	mux := http.NewServeMux()
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		refreshCalls.Add(1)
+
+		if r.FormValue("refresh_token") != "test_refresh" || !rotated.CompareAndSwap(false, true) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"access_token":"new_access","refresh_token":"new_refresh","token_type":"bearer","expires_in":3600}`))
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user.Collection().OAuth2.Providers = []core.OAuth2ProviderConfig{{
+		Name:         "gitlab",
+		ClientId:     "test_client",
+		ClientSecret: "test_secret",
+		TokenURL:     server.URL + "/token",
+	}}
+
+	ea := core.NewExternalAuth(app)
+	ea.SetCollectionRef(user.Collection().Id)
+	ea.SetRecordRef(user.Id)
+	ea.SetProvider("gitlab")
+	ea.SetProviderId("test_id")
+	ea.SetAccessToken("test_access")
+	ea.SetRefreshToken("test_refresh")
+	ea.SetExpiry(types.NowDateTime().Add(-1 * time.Hour))
+	if err := app.Save(ea); err != nil {
+		t.Fatal(err)
+	}
+
+	const total = 5
+
+	var wg sync.WaitGroup
+	errs := make(chan error, total)
+
+	for i := 0; i < total; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := app.ProviderClient(user, "gitlab")
+			errs <- err
+		}()
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Fatalf("Expected nil error, got %v", err)
+		}
+	}
+
+	if v := refreshCalls.Load(); v != 1 {
+		t.Fatalf("Expected 1 refresh call, got %d", v)
+	}
+
+	updated, err := app.FindFirstExternalAuthByExpr(dbx.HashExp{"id": ea.Id})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := updated.RefreshToken(); v != "new_refresh" {
+		t.Fatalf("Expected stored refresh token %q, got %q", "new_refresh", v)
+	}
+}
@@ -18,6 +18,9 @@ var (
 
 const CollectionNameExternalAuths = "_externalAuths"
 
+// ExternalAuthProviderLDAP is the provider name of the LDAP auth record links.
+const ExternalAuthProviderLDAP = "ldap"
+
 // ExternalAuth defines a Record proxy for working with the externalAuths collection.
 type ExternalAuth struct {
 	*Record
@@ -108,6 +111,37 @@ func (m *ExternalAuth) SetProviderId(providerId string) {
 	m.Set("providerId", providerId)
 }
 
+// AccessToken returns the "accessToken" record field value.
+func (m *ExternalAuth) AccessToken() string {
+	return m.GetString("accessToken")
+}
+
+// SetAccessToken sets the "accessToken" record field value.
+func (m *ExternalAuth) SetAccessToken(accessToken string) {
+	m.Set("accessToken", accessToken)
+}
+
+// RefreshToken returns the "refreshToken" record field value.
+func (m *ExternalAuth) RefreshToken() string {
+	return m.GetString("refreshToken")
+}
+
+// SetRefreshToken sets the "refreshToken" record field value.
+func (m *ExternalAuth) SetRefreshToken(refreshToken string) {
+	m.Set("refreshToken", refreshToken)
+}
+
+// Expiry returns the "expiry" record field value
+// (aka. the access token expiration date).
+func (m *ExternalAuth) Expiry() types.DateTime {
+	return m.GetDateTime("expiry")
+}
+
+// SetExpiry sets the "expiry" record field value.
+func (m *ExternalAuth) SetExpiry(expiry types.DateTime) {
+	m.Set("expiry", expiry)
+}
+
 // Created returns the "created" record field value.
 func (m *ExternalAuth) Created() types.DateTime {
 	return m.GetDateTime("created")
@@ -127,6 +161,7 @@ func (app *BaseApp) registerExternalAuthHooks() {
 			for name := range auth.Providers {
 				providerNames = append(providerNames, name)
 			}
+			providerNames = append(providerNames, ExternalAuthProviderLDAP)
 
 			provider := e.Record.GetString("provider")
 			if err := validation.Validate(provider, validation.Required, validation.In(providerNames...)); err != nil {
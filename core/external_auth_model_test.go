@@ -132,6 +132,78 @@ func TestExternalAuthProviderId(t *testing.T) {
 	}
 }
 
+func TestExternalAuthAccessToken(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	ea := core.NewExternalAuth(app)
+
+	testValues := []string{"test_1", "test2", ""}
+	for i, testValue := range testValues {
+		t.Run(fmt.Sprintf("%d_%q", i, testValue), func(t *testing.T) {
+			ea.SetAccessToken(testValue)
+
+			if v := ea.AccessToken(); v != testValue {
+				t.Fatalf("Expected getter %q, got %q", testValue, v)
+			}
+
+			if v := ea.GetString("accessToken"); v != testValue {
+				t.Fatalf("Expected field value %q, got %q", testValue, v)
+			}
+		})
+	}
+}
+
+func TestExternalAuthRefreshToken(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	ea := core.NewExternalAuth(app)
+
+	testValues := []string{"test_1", "test2", ""}
+	for i, testValue := range testValues {
+		t.Run(fmt.Sprintf("%d_%q", i, testValue), func(t *testing.T) {
+			ea.SetRefreshToken(testValue)
+
+			if v := ea.RefreshToken(); v != testValue {
+				t.Fatalf("Expected getter %q, got %q", testValue, v)
+			}
+
+			if v := ea.GetString("refreshToken"); v != testValue {
+				t.Fatalf("Expected field value %q, got %q", testValue, v)
+			}
+		})
+	}
+}
+
+func TestExternalAuthExpiry(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	ea := core.NewExternalAuth(app)
+
+	if v := ea.Expiry().String(); v != "" {
+		t.Fatalf("Expected empty expiry, got %q", v)
+	}
+
+	now := types.NowDateTime()
+	ea.SetExpiry(now)
+
+	if v := ea.Expiry().String(); v != now.String() {
+		t.Fatalf("Expected %q expiry, got %q", now.String(), v)
+	}
+
+	if v := ea.GetDateTime("expiry").String(); v != now.String() {
+		t.Fatalf("Expected %q field value, got %q", now.String(), v)
+	}
+}
+
 func TestExternalAuthCreated(t *testing.T) {
 	t.Parallel()
 
@@ -299,6 +371,18 @@ func TestExternalAuthValidateHook(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"valid ref (ldap)",
+			func() *core.ExternalAuth {
+				ea := core.NewExternalAuth(app)
+				ea.SetCollectionRef(user.Collection().Id)
+				ea.SetRecordRef(user.Id)
+				ea.SetProvider(core.ExternalAuthProviderLDAP)
+				ea.SetProviderId("uid=test,dc=example,dc=com")
+				return ea
+			},
+			[]string{},
+		},
 	}
 
 	for _, s := range scenarios {
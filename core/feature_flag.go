@@ -0,0 +1,312 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"hash/fnv"
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const FeatureFlagsTableName = "_featureFlags"
+
+// FeatureFlagSubjectHeader is the name of the request header that could be
+// used by guests to specify a stable subject identifier (eg. a device id)
+// for the feature flags percentage rollout.
+//
+// For authenticated requests the auth record is always used as subject.
+const FeatureFlagSubjectHeader = "X-Device-Id"
+
+const storeKeyFeatureFlagsCache = "@featureFlagsCache"
+
+var (
+	_ Model         = (*FeatureFlag)(nil)
+	_ PostValidator = (*FeatureFlag)(nil)
+)
+
+// note: dots are not allowed so that the key could be used as @request.flags.* rule variable
+var featureFlagKeyRegex = regexp.MustCompile(`^\w+$`)
+
+// FeatureFlag defines a single managed boolean or variant feature flag.
+//
+// A flag is evaluated as enabled for a request only if:
+//   - the flag is Enabled
+//   - the flag Rule is empty or it is satisfied by the request (it could use only the @request.* fields)
+//   - the request subject falls within the Rollout percentage (0-100)
+//
+// The rollout and the variant assignment use stable hashing of the
+// flag key and the request subject (the auth record or the [FeatureFlagSubjectHeader] value),
+// meaning that the same subject always gets the same evaluation result.
+//
+// If Variants is not empty, enabled flags are also assigned with one of the variants.
+type FeatureFlag struct {
+	BaseModel
+
+	Key         string                  `db:"key" json:"key"`
+	Description string                  `db:"description" json:"description"`
+	Enabled     bool                    `db:"enabled" json:"enabled"`
+	Rule        string                  `db:"rule" json:"rule"`
+	Rollout     int                     `db:"rollout" json:"rollout"`
+	Variants    types.JSONArray[string] `db:"variants" json:"variants"`
+	Created     types.DateTime          `db:"created" json:"created"`
+	Updated     types.DateTime          `db:"updated" json:"updated"`
+}
+
+// TableName returns the FeatureFlag model table name.
+func (m *FeatureFlag) TableName() string {
+	return FeatureFlagsTableName
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *FeatureFlag) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(
+			&m.Key,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(featureFlagKeyRegex),
+			validation.By(m.checkUniqueKey(app)),
+		),
+		validation.Field(&m.Description, validation.Length(0, 1000)),
+		validation.Field(&m.Rule, validation.Length(0, 1000), validation.By(m.checkRule(app))),
+		validation.Field(&m.Rollout, validation.Min(0), validation.Max(100)),
+		validation.Field(&m.Variants, validation.Length(0, 100), validation.Each(validation.Required, validation.Length(1, 100))),
+	)
+}
+
+func (m *FeatureFlag) checkUniqueKey(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		var exists int
+
+		err := app.FeatureFlagQuery().
+			Select("(1)").
+			AndWhere(dbx.HashExp{"key": v}).
+			AndWhere(dbx.Not(dbx.HashExp{"id": m.Id})).
+			Limit(1).
+			Row(&exists)
+
+		if exists > 0 || (err != nil && !errors.Is(err, sql.ErrNoRows)) {
+			return validation.NewError("validation_feature_flag_key_exists", "Feature flag with the provided key already exists.")
+		}
+
+		return nil
+	}
+}
+
+func (m *FeatureFlag) checkRule(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		if err := validateRequestRule(app, v); err != nil {
+			return validation.NewError("validation_invalid_rule", "Invalid rule. Raw error: "+err.Error())
+		}
+
+		return nil
+	}
+}
+
+// FeatureFlagEvaluation defines a single feature flag evaluation result.
+type FeatureFlagEvaluation struct {
+	Key     string `json:"key"`
+	Enabled bool   `json:"enabled"`
+	Variant string `json:"variant"`
+}
+
+// -------------------------------------------------------------------
+
+// FeatureFlagQuery returns a new FeatureFlag select query.
+func (app *BaseApp) FeatureFlagQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&FeatureFlag{})
+}
+
+// FindFeatureFlagByKey returns a single FeatureFlag model by its key.
+func (app *BaseApp) FindFeatureFlagByKey(key string) (*FeatureFlag, error) {
+	result := &FeatureFlag{}
+
+	err := app.FeatureFlagQuery().
+		AndWhere(dbx.HashExp{"key": key}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindCachedFeatureFlags returns all FeatureFlag models (ordered by key).
+//
+// The flags are cached and the cache is reset on every feature flag change.
+//
+// NB! The returned flags are shared and must not be modified.
+func (app *BaseApp) FindCachedFeatureFlags() ([]*FeatureFlag, error) {
+	if flags, ok := app.Store().Get(storeKeyFeatureFlagsCache).([]*FeatureFlag); ok {
+		return flags, nil
+	}
+
+	flags := []*FeatureFlag{}
+
+	err := app.FeatureFlagQuery().OrderBy("key ASC").All(&flags)
+	if err != nil {
+		return nil, err
+	}
+
+	app.Store().Set(storeKeyFeatureFlagsCache, flags)
+
+	return flags, nil
+}
+
+// EvaluateFeatureFlag evaluates the feature flag with the specified key
+// against the provided requestInfo (it could be nil, eg. for background jobs).
+//
+// Returns [sql.ErrNoRows] if the flag doesn't exist.
+func (app *BaseApp) EvaluateFeatureFlag(key string, requestInfo *RequestInfo) (*FeatureFlagEvaluation, error) {
+	flags, err := app.FindCachedFeatureFlags()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, flag := range flags {
+		if flag.Key == key {
+			return evaluateFeatureFlag(app, flag, requestInfo)
+		}
+	}
+
+	return nil, sql.ErrNoRows
+}
+
+// EvaluateFeatureFlags evaluates all feature flags against the provided requestInfo
+// and returns the evaluation results keyed by the flag key.
+//
+// Flags with invalid rule are evaluated as disabled.
+func (app *BaseApp) EvaluateFeatureFlags(requestInfo *RequestInfo) (map[string]*FeatureFlagEvaluation, error) {
+	flags, err := app.FindCachedFeatureFlags()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*FeatureFlagEvaluation, len(flags))
+
+	for _, flag := range flags {
+		evaluation, err := evaluateFeatureFlag(app, flag, requestInfo)
+		if err != nil {
+			app.Logger().Debug("Failed to evaluate feature flag", "key", flag.Key, "error", err)
+		}
+		result[flag.Key] = evaluation
+	}
+
+	return result, nil
+}
+
+func evaluateFeatureFlag(app App, flag *FeatureFlag, requestInfo *RequestInfo) (*FeatureFlagEvaluation, error) {
+	result := &FeatureFlagEvaluation{Key: flag.Key}
+
+	if !flag.Enabled {
+		return result, nil
+	}
+
+	if flag.Rule != "" {
+		ok, err := checkRequestRule(app, flag.Rule, requestInfo)
+		if err != nil || !ok {
+			return result, err
+		}
+	}
+
+	subject := featureFlagSubject(requestInfo)
+
+	if flag.Rollout < 100 && (subject == "" || featureFlagBucket(flag.Key, subject, 100) >= flag.Rollout) {
+		return result, nil
+	}
+
+	result.Enabled = true
+
+	if len(flag.Variants) > 0 {
+		var i int
+		if subject != "" {
+			i = featureFlagBucket(flag.Key+":variant", subject, len(flag.Variants))
+		}
+		result.Variant = flag.Variants[i]
+	}
+
+	return result, nil
+}
+
+// featureFlagSubject returns the stable rollout subject identifier of the requestInfo
+// (or empty string if there is none).
+func featureFlagSubject(requestInfo *RequestInfo) string {
+	if requestInfo == nil {
+		return ""
+	}
+
+	if requestInfo.Auth != nil {
+		return requestInfo.Auth.Collection().Id + ":" + requestInfo.Auth.Id
+	}
+
+	return requestInfo.Headers[inflector.Snakecase(FeatureFlagSubjectHeader)]
+}
+
+// featureFlagBucket returns the stable [0, n) bucket of the salt and subject pair.
+func featureFlagBucket(salt string, subject string, n int) int {
+	h := fnv.New32a()
+	h.Write([]byte(salt + ":" + subject))
+
+	return int(h.Sum32() % uint32(n))
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerFeatureFlagHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*FeatureFlag); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*FeatureFlag); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// reset the flags cache on every feature flag change
+	resetCache := func(e *ModelEvent) error {
+		if _, ok := e.Model.(*FeatureFlag); ok {
+			app.Store().Remove(storeKeyFeatureFlagsCache)
+		}
+
+		return e.Next()
+	}
+	app.OnModelAfterCreateSuccess().BindFunc(resetCache)
+	app.OnModelAfterUpdateSuccess().BindFunc(resetCache)
+	app.OnModelAfterDeleteSuccess().BindFunc(resetCache)
+}
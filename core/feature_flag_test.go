@@ -0,0 +1,249 @@
+package core_test
+
+import (
+	"database/sql"
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestFeatureFlagTableName(t *testing.T) {
+	t.Parallel()
+
+	if v := (&core.FeatureFlag{}).TableName(); v != "_featureFlags" {
+		t.Fatalf("Expected %q, got %q", "_featureFlags", v)
+	}
+}
+
+func TestFeatureFlagValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	existing := &core.FeatureFlag{Key: "test", Enabled: true, Rollout: 100}
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	newFlag := func(key string, rule string, rollout int, variants ...string) *core.FeatureFlag {
+		flag := &core.FeatureFlag{Key: key, Rule: rule, Rollout: rollout, Variants: variants}
+		flag.Id = "test"
+		return flag
+	}
+
+	scenarios := []struct {
+		name         string
+		flag         *core.FeatureFlag
+		expectedErrs []string
+	}{
+		{
+			"empty",
+			&core.FeatureFlag{},
+			[]string{"id", "key"},
+		},
+		{
+			"invalid key, rule, rollout and variants",
+			newFlag("a.b", "missing = 1", 101, "a", ""),
+			[]string{"key", "rule", "rollout", "variants"},
+		},
+		{
+			"negative rollout",
+			newFlag("new", "", -1),
+			[]string{"rollout"},
+		},
+		{
+			"duplicated key",
+			newFlag("test", "", 100),
+			[]string{"key"},
+		},
+		{
+			"updating the existing flag",
+			existing,
+			[]string{},
+		},
+		{
+			"valid flag",
+			newFlag("new_flag", "@request.auth.id != ''", 50, "a", "b"),
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, app.Validate(s.flag), s.expectedErrs)
+		})
+	}
+}
+
+func TestFindCachedFeatureFlags(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	flags, err := app.FindCachedFeatureFlags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flags) != 0 {
+		t.Fatalf("Expected no flags, got %d", len(flags))
+	}
+
+	b := &core.FeatureFlag{Key: "b", Rollout: 100}
+	a := &core.FeatureFlag{Key: "a", Rollout: 100}
+	for _, flag := range []*core.FeatureFlag{b, a} {
+		if err := app.Save(flag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	flags, err = app.FindCachedFeatureFlags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flags) != 2 || flags[0].Key != "a" || flags[1].Key != "b" {
+		t.Fatalf("Expected flags [a, b], got %v", flags)
+	}
+
+	a.Enabled = true
+	if err := app.Save(a); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(b); err != nil {
+		t.Fatal(err)
+	}
+
+	flags, err = app.FindCachedFeatureFlags()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(flags) != 1 || flags[0].Key != "a" || !flags[0].Enabled {
+		t.Fatalf("Expected the cache to be reset and contain only the updated flag a, got %v", flags)
+	}
+
+	found, err := app.FindFeatureFlagByKey("a")
+	if err != nil || found.Id != a.Id {
+		t.Fatalf("Expected to find flag a, got %v (%v)", found, err)
+	}
+}
+
+func TestEvaluateFeatureFlag(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flags := []*core.FeatureFlag{
+		{Key: "disabled", Enabled: false, Rollout: 100},
+		{Key: "everyone", Enabled: true, Rollout: 100},
+		{Key: "nobody", Enabled: true, Rollout: 0},
+		{Key: "users", Enabled: true, Rollout: 100, Rule: "@request.auth.name = 'test1'"},
+		{Key: "variants", Enabled: true, Rollout: 100, Variants: []string{"a"}},
+		{Key: "half", Enabled: true, Rollout: 50},
+	}
+	for _, flag := range flags {
+		if err := app.Save(flag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	scenarios := []struct {
+		name            string
+		key             string
+		requestInfo     *core.RequestInfo
+		expectedEnabled bool
+		expectedVariant string
+	}{
+		{"disabled", "disabled", &core.RequestInfo{Auth: user}, false, ""},
+		{"everyone (nil requestInfo)", "everyone", nil, true, ""},
+		{"everyone (guest)", "everyone", &core.RequestInfo{}, true, ""},
+		{"nobody", "nobody", &core.RequestInfo{Auth: user}, false, ""},
+		{"rule (guest)", "users", &core.RequestInfo{}, false, ""},
+		{"rule (user)", "users", &core.RequestInfo{Auth: user}, true, ""},
+		{"variants", "variants", &core.RequestInfo{Auth: user}, true, "a"},
+		{"partial rollout without subject", "half", &core.RequestInfo{}, false, ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := app.EvaluateFeatureFlag(s.key, s.requestInfo)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Key != s.key {
+				t.Fatalf("Expected key %q, got %q", s.key, result.Key)
+			}
+
+			if result.Enabled != s.expectedEnabled {
+				t.Fatalf("Expected enabled %v, got %v", s.expectedEnabled, result.Enabled)
+			}
+
+			if result.Variant != s.expectedVariant {
+				t.Fatalf("Expected variant %q, got %q", s.expectedVariant, result.Variant)
+			}
+		})
+	}
+
+	t.Run("missing flag", func(t *testing.T) {
+		_, err := app.EvaluateFeatureFlag("missing", &core.RequestInfo{})
+		if !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("Expected sql.ErrNoRows, got %v", err)
+		}
+	})
+
+	t.Run("stable partial rollout", func(t *testing.T) {
+		var enabled int
+
+		for i := 0; i < 1000; i++ {
+			info := &core.RequestInfo{Headers: map[string]string{"x_device_id": "device" + strconv.Itoa(i)}}
+
+			first, err := app.EvaluateFeatureFlag("half", info)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			second, err := app.EvaluateFeatureFlag("half", info)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if first.Enabled != second.Enabled {
+				t.Fatalf("Expected the same evaluation result for subject %d", i)
+			}
+
+			if first.Enabled {
+				enabled++
+			}
+		}
+
+		if enabled < 400 || enabled > 600 {
+			t.Fatalf("Expected ~50%% of the subjects to be enabled, got %d/1000", enabled)
+		}
+	})
+
+	t.Run("evaluate all", func(t *testing.T) {
+		result, err := app.EvaluateFeatureFlags(&core.RequestInfo{Auth: user})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(result) != len(flags) {
+			t.Fatalf("Expected %d evaluations, got %d", len(flags), len(result))
+		}
+
+		if !result["users"].Enabled || result["disabled"].Enabled {
+			t.Fatalf("Unexpected evaluations %v", result)
+		}
+	})
+}
@@ -2,9 +2,11 @@ package core
 
 import (
 	"context"
+	"time"
 
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/pocketbase/pocketbase/tools/store"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
@@ -16,11 +18,20 @@ func init() {
 
 const FieldTypeDate = "date"
 
-var _ Field = (*DateField)(nil)
+var (
+	_ Field        = (*DateField)(nil)
+	_ GetterFinder = (*DateField)(nil)
+)
 
 // DateField defines "date" type field to store a single [types.DateTime] value.
 //
 // The respective zero record field value is the zero [types.DateTime].
+//
+// The following additional getter keys are available:
+//
+//   - "fieldName:tz" - returns the date value formatted in the field [DateField.Timezone]
+//     with explicit offset (e.g. "2024-01-02 11:00:00.000+01:00").
+//     For example: `record.GetString("published:tz")`
 type DateField struct {
 	// Name (required) is the unique name of the field.
 	Name string `form:"name" json:"name"`
@@ -54,6 +65,12 @@ type DateField struct {
 
 	// Required will require the field value to be non-zero [types.DateTime].
 	Required bool `form:"required" json:"required"`
+
+	// Timezone is an optional IANA timezone name (e.g. "Europe/Prague") in which
+	// to interpret the submitted date values without explicit timezone offset.
+	//
+	// Note that the values are always stored in UTC.
+	Timezone string `form:"timezone" json:"timezone,omitempty"`
 }
 
 // Type implements [Field.Type] interface method.
@@ -110,7 +127,7 @@ func (f *DateField) ColumnType(app App) string {
 func (f *DateField) PrepareValue(record *Record, raw any) (any, error) {
 	// ignore scan errors since the format may change between versions
 	// and to allow running db adjusting migrations
-	val, _ := types.ParseDateTime(raw)
+	val, _ := types.ParseDateTimeIn(raw, f.location())
 	return val, nil
 }
 
@@ -149,9 +166,41 @@ func (f *DateField) ValidateSettings(ctx context.Context, app App, collection *C
 		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
 		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
 		validation.Field(&f.Max, validation.By(f.checkRange(f.Min, f.Max))),
+		validation.Field(&f.Timezone, validation.By(checkTimezone)),
 	)
 }
 
+// FindGetter implements the [GetterFinder] interface.
+func (f *DateField) FindGetter(key string) GetterFunc {
+	switch key {
+	case f.Name:
+		return func(record *Record) any {
+			return record.GetRaw(f.Name)
+		}
+	case f.Name + ":tz":
+		return func(record *Record) any {
+			val, _ := record.GetRaw(f.Name).(types.DateTime)
+			return val.StringIn(f.location())
+		}
+	default:
+		return nil
+	}
+}
+
+// location returns the field timezone location (fallbacks to UTC).
+func (f *DateField) location() *time.Location {
+	if f.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := loadCachedLocation(f.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
 func (f *DateField) checkRange(min types.DateTime, max types.DateTime) validation.RuleFunc {
 	return func(value any) error {
 		v, _ := value.(types.DateTime)
@@ -172,3 +221,37 @@ func (f *DateField) checkRange(min types.DateTime, max types.DateTime) validatio
 		return dr.Validate(v.String())
 	}
 }
+
+// -------------------------------------------------------------------
+
+var cachedLocations = store.New[string, *time.Location](nil)
+
+// loadCachedLocation returns the location with the specified IANA name
+// (the loaded locations are cached to avoid reading the tz database on every call).
+func loadCachedLocation(name string) (*time.Location, error) {
+	if loc, ok := cachedLocations.GetOk(name); ok {
+		return loc, nil
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, err
+	}
+
+	cachedLocations.Set(name, loc)
+
+	return loc, nil
+}
+
+func checkTimezone(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if _, err := loadCachedLocation(v); err != nil {
+		return validation.NewError("validation_invalid_timezone", "Invalid or unknown IANA timezone.")
+	}
+
+	return nil
+}
@@ -64,6 +64,77 @@ func TestDateFieldPrepareValue(t *testing.T) {
 	}
 }
 
+func TestDateFieldPrepareValueWithTimezone(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.DateField{Timezone: "Europe/Prague"}
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		raw      any
+		expected string
+	}{
+		{"", ""},
+		{"invalid", ""},
+		{"2024-01-01 00:11:22.345Z", "2024-01-01 00:11:22.345Z"},
+		{"2024-01-01 10:11:22", "2024-01-01 09:11:22.000Z"},
+		{"2024-07-01 10:11:22.345", "2024-07-01 08:11:22.345Z"},
+		{"2024-01-01 10:11:22.345+03:00", "2024-01-01 07:11:22.345Z"},
+		{time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), "2024-01-02 03:04:05.000Z"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			v, err := f.PrepareValue(record, s.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			vDate, ok := v.(types.DateTime)
+			if !ok {
+				t.Fatalf("Expected types.DateTime instance, got %T", v)
+			}
+
+			if vDate.String() != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestDateFieldFindGetter(t *testing.T) {
+	collection := core.NewBaseCollection("test")
+	collection.Fields.Add(
+		&core.DateField{Name: "utc"},
+		&core.DateField{Name: "prague", Timezone: "Europe/Prague"},
+	)
+
+	record := core.NewRecord(collection)
+	record.Set("utc", "2024-01-02 10:00:00.000Z")
+	record.Set("prague", "2024-01-02 10:00:00.000Z")
+
+	scenarios := []struct {
+		key      string
+		expected any
+	}{
+		{"utc", "2024-01-02 10:00:00.000Z"},
+		{"utc:tz", "2024-01-02 10:00:00.000+00:00"},
+		{"prague", "2024-01-02 10:00:00.000Z"},
+		{"prague:tz", "2024-01-02 11:00:00.000+01:00"},
+		{"prague:unknown", ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.key, func(t *testing.T) {
+			result := record.GetString(s.key)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
 func TestDateFieldValidateValue(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -217,6 +288,28 @@ func TestDateFieldValidateSettings(t *testing.T) {
 			},
 			[]string{},
 		},
+		{
+			"invalid Timezone",
+			func() *core.DateField {
+				return &core.DateField{
+					Id:       "test",
+					Name:     "test",
+					Timezone: "Europe/Missing",
+				}
+			},
+			[]string{"timezone"},
+		},
+		{
+			"valid Timezone",
+			func() *core.DateField {
+				return &core.DateField{
+					Id:       "test",
+					Name:     "test",
+					Timezone: "Europe/Prague",
+				}
+			},
+			[]string{},
+		},
 	}
 
 	for _, s := range scenarios {
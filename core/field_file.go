@@ -109,6 +109,14 @@ type FileField struct {
 	// Leave it empty to disable the validator.
 	MimeTypes []string `form:"mimeTypes" json:"mimeTypes"`
 
+	// VerifyImages enables an additional check that ensures that the
+	// uploaded jpeg, png, gif, webp, bmp and tiff files (detected by their content)
+	// are valid and fully decodable images.
+	//
+	// Note that the MimeTypes validator is always content based and
+	// doesn't rely on the client provided content type or file extension.
+	VerifyImages bool `form:"verifyImages" json:"verifyImages"`
+
 	// Thumbs specifies an optional list of the supported thumbs for image based files.
 	//
 	// Each entry must be in one of the following formats:
@@ -296,6 +304,14 @@ func (f *FileField) ValidateValue(ctx context.Context, app App, record *Record)
 				return err
 			}
 		}
+
+		// check image content
+		if f.VerifyImages {
+			err = validators.UploadedImageDecodable()(upload)
+			if err != nil {
+				return err
+			}
+		}
 	}
 
 	return nil
@@ -524,14 +540,46 @@ func (f *FileField) processFilesToUpload(ctx context.Context, app App, record *R
 	var failed []error     // list of upload errors
 	var succeeded []string // list of uploaded file names
 
+	quarantined := map[string]string{} // filename -> reason
+
 	for _, upload := range uploads {
-		path := record.BaseFilesPath() + "/" + upload.Name
-		if err := fsys.UploadFile(upload, path); err == nil {
+		var uploaded bool
+
+		event := new(FileUploadEvent)
+		event.App = app
+		event.Context = ctx
+		event.Record = record
+		event.FileField = f
+		event.File = upload
+
+		err := app.OnFileUpload().Trigger(event, func(e *FileUploadEvent) error {
+			if err := fsys.UploadFile(upload, record.BaseFilesPath()+"/"+upload.Name); err != nil {
+				return err
+			}
+
 			succeeded = append(succeeded, upload.Name)
-		} else {
+			uploaded = true
+
+			return nil
+		})
+		if err == nil && !uploaded {
+			err = errors.New("the file upload was skipped")
+		}
+
+		if err != nil {
 			failed = append(failed, fmt.Errorf("%q: %w", upload.Name, err))
 			break // for now stop on the first error since we currently don't allow partial uploads
 		}
+
+		if event.Quarantine {
+			quarantined[upload.Name] = event.QuarantineReason
+		}
+	}
+
+	if len(failed) == 0 && len(quarantined) > 0 {
+		if err := quarantineFiles(app, record, f.Name, quarantined); err != nil {
+			failed = append(failed, fmt.Errorf("failed to quarantine files: %w", err))
+		}
 	}
 
 	if len(failed) > 0 {
@@ -586,6 +634,7 @@ func (f *FileField) deleteFilesByNamesList(ctx context.Context, app App, record
 	fsys.SetContext(ctx)
 
 	var failures []error
+	var deleted []string
 
 	for i := len(filenames) - 1; i >= 0; i-- {
 		filename := filenames[i]
@@ -602,6 +651,7 @@ func (f *FileField) deleteFilesByNamesList(ctx context.Context, app App, record
 		} else {
 			// remove the deleted file from the list
 			filenames = append(filenames[:i], filenames[i+1:]...)
+			deleted = append(deleted, filename)
 
 			// try to delete the related file thumbs (if any)
 			thumbsErr := fsys.DeletePrefix(record.BaseFilesPath() + "/thumbs_" + filename + "/")
@@ -611,6 +661,11 @@ func (f *FileField) deleteFilesByNamesList(ctx context.Context, app App, record
 		}
 	}
 
+	// release the quarantine entries of the deleted files (if any)
+	if err := deleteQuarantinedFiles(app, record, deleted); err != nil {
+		app.Logger().Warn("Failed to delete the quarantined files entries", "error", err)
+	}
+
 	if len(failures) > 0 {
 		return filenames, fmt.Errorf("failed to delete all files: %w", errors.Join(failures...))
 	}
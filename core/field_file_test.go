@@ -6,6 +6,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"image"
+	"image/png"
 	"slices"
 	"strings"
 	"testing"
@@ -251,6 +253,21 @@ func TestFileFieldValidateValue(t *testing.T) {
 		t.Fatal(err)
 	}
 
+	var pngBuf bytes.Buffer
+	if err = png.Encode(&pngBuf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+
+	f6, err := filesystem.NewFileFromBytes(pngBuf.Bytes(), "test6.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f7, err := filesystem.NewFileFromBytes(pngBuf.Bytes()[:pngBuf.Len()-20], "test7.png") // truncated
+	if err != nil {
+		t.Fatal(err)
+	}
+
 	scenarios := []struct {
 		name        string
 		field       *core.FileField
@@ -378,6 +395,36 @@ func TestFileFieldValidateValue(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"corrupted image (VerifyImages: false)",
+			&core.FileField{Name: "test", MaxSize: 999, MaxSelect: 3, MimeTypes: []string{"image/png"}},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", []any{f6, f7})
+				return record
+			},
+			false,
+		},
+		{
+			"corrupted image (VerifyImages: true)",
+			&core.FileField{Name: "test", MaxSize: 999, MaxSelect: 3, MimeTypes: []string{"image/png"}, VerifyImages: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", []any{f6, f7})
+				return record
+			},
+			true,
+		},
+		{
+			"valid image and non-image files (VerifyImages: true)",
+			&core.FileField{Name: "test", MaxSize: 999, MaxSelect: 3, VerifyImages: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", []any{f1, f6})
+				return record
+			},
+			false,
+		},
 		{
 			"existing files > MaxSelect",
 			&core.FileField{Name: "file_many", MaxSize: 999, MaxSelect: 2},
@@ -1,7 +1,9 @@
 package core
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"slices"
 	"strconv"
 	"strings"
@@ -22,14 +24,29 @@ const FieldTypeJSON = "json"
 
 const DefaultJSONFieldMaxSize int64 = 1 << 20
 
+const (
+	JSONMergeArraysReplace = "replace"
+	JSONMergeArraysAppend  = "append"
+	JSONMergeArraysUnique  = "unique"
+)
+
 var (
 	_ Field                 = (*JSONField)(nil)
+	_ SetterFinder          = (*JSONField)(nil)
 	_ MaxBodySizeCalculator = (*JSONField)(nil)
 )
 
 // JSONField defines "json" type field for storing any serialized JSON value.
 //
 // The respective zero record field value is the zero [types.JSONRaw].
+//
+// The following additional setter keys are available:
+//
+//   - "fieldName+" - deep merges the submitted JSON into the existing field value
+//     following the JSON Merge Patch (RFC 7386) semantics, aka. object keys
+//     with null values are removed and the arrays are merged according
+//     to the field [JSONField.MergeArrays] strategy.
+//     For example: `record.Set("meta+", map[string]any{"a": 1, "b": nil})`
 type JSONField struct {
 	// Name (required) is the unique name of the field.
 	Name string `form:"name" json:"name"`
@@ -59,6 +76,15 @@ type JSONField struct {
 	// Required will require the field value to be non-empty JSON value
 	// (aka. not "null", `""`, "[]", "{}").
 	Required bool `form:"required" json:"required"`
+
+	// MergeArrays specifies how the arrays are merged when
+	// updating the field value with the "fieldName+" modifier.
+	//
+	// Supported values:
+	//   - "replace" (default) - the submitted array replaces the existing one
+	//   - "append" - the submitted array items are appended to the existing ones
+	//   - "unique" - same as "append" but skips the already existing items
+	MergeArrays string `form:"mergeArrays" json:"mergeArrays,omitempty"`
 }
 
 // Type implements [Field.Type] interface method.
@@ -182,9 +208,128 @@ func (f *JSONField) ValidateSettings(ctx context.Context, app App, collection *C
 		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
 		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
 		validation.Field(&f.MaxSize, validation.Min(0), validation.Max(maxSafeJSONInt)),
+		validation.Field(
+			&f.MergeArrays,
+			validation.In(JSONMergeArraysReplace, JSONMergeArraysAppend, JSONMergeArraysUnique),
+		),
 	)
 }
 
+// FindSetter implements the [SetterFinder] interface.
+func (f *JSONField) FindSetter(key string) SetterFunc {
+	switch key {
+	case f.Name:
+		return f.setValue
+	case f.Name + "+":
+		return f.mergeValue
+	default:
+		return nil
+	}
+}
+
+func (f *JSONField) setValue(record *Record, raw any) {
+	val, _ := f.PrepareValue(record, raw)
+	record.SetRaw(f.Name, val)
+}
+
+func (f *JSONField) mergeValue(record *Record, raw any) {
+	patchRaw, err := f.PrepareValue(record, raw)
+	if err != nil {
+		record.SetRaw(f.Name, patchRaw)
+		return
+	}
+
+	patch, err := decodeJSONValue(patchRaw.(types.JSONRaw))
+	if err != nil {
+		// leave the invalid value to be reported by the field validator
+		record.SetRaw(f.Name, patchRaw)
+		return
+	}
+
+	var target any
+	if existing, ok := record.GetRaw(f.Name).(types.JSONRaw); ok && len(existing) > 0 {
+		// an invalid existing value is treated as null
+		target, _ = decodeJSONValue(existing)
+	}
+
+	merged, err := json.Marshal(mergeJSONPatch(target, patch, f.MergeArrays))
+	if err != nil {
+		record.SetRaw(f.Name, patchRaw)
+		return
+	}
+
+	record.SetRaw(f.Name, types.JSONRaw(merged))
+}
+
+// decodeJSONValue unmarshals the raw JSON value preserving the number precision.
+func decodeJSONValue(raw []byte) (any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var result any
+
+	err := decoder.Decode(&result)
+
+	return result, err
+}
+
+// mergeJSONPatch applies the RFC 7386 patch to the target value
+// with the specified arrays merge strategy.
+func mergeJSONPatch(target any, patch any, arraysStrategy string) any {
+	switch p := patch.(type) {
+	case map[string]any:
+		t, ok := target.(map[string]any)
+		if !ok {
+			t = make(map[string]any, len(p))
+		}
+
+		for k, v := range p {
+			if v == nil {
+				delete(t, k)
+			} else {
+				t[k] = mergeJSONPatch(t[k], v, arraysStrategy)
+			}
+		}
+
+		return t
+	case []any:
+		t, ok := target.([]any)
+		if !ok {
+			return p
+		}
+
+		switch arraysStrategy {
+		case JSONMergeArraysAppend:
+			return append(t, p...)
+		case JSONMergeArraysUnique:
+			existing := make(map[string]struct{}, len(t)+len(p))
+			for _, item := range t {
+				existing[jsonItemKey(item)] = struct{}{}
+			}
+
+			for _, item := range p {
+				key := jsonItemKey(item)
+				if _, ok := existing[key]; ok {
+					continue
+				}
+				existing[key] = struct{}{}
+				t = append(t, item)
+			}
+
+			return t
+		default:
+			return p
+		}
+	default:
+		return patch
+	}
+}
+
+func jsonItemKey(item any) string {
+	raw, _ := json.Marshal(item)
+	return string(raw)
+}
+
 // CalculateMaxBodySize implements the [MaxBodySizeCalculator] interface.
 func (f *JSONField) CalculateMaxBodySize() int64 {
 	if f.MaxSize <= 0 {
@@ -242,6 +242,28 @@ func TestJSONFieldValidateSettings(t *testing.T) {
 			},
 			[]string{"maxSize"},
 		},
+		{
+			"invalid MergeArrays",
+			func() *core.JSONField {
+				return &core.JSONField{
+					Id:          "test",
+					Name:        "test",
+					MergeArrays: "invalid",
+				}
+			},
+			[]string{"mergeArrays"},
+		},
+		{
+			"valid MergeArrays",
+			func() *core.JSONField {
+				return &core.JSONField{
+					Id:          "test",
+					Name:        "test",
+					MergeArrays: core.JSONMergeArraysUnique,
+				}
+			},
+			[]string{},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -275,3 +297,137 @@ func TestJSONFieldCalculateMaxBodySize(t *testing.T) {
 		})
 	}
 }
+
+func TestJSONFieldFindSetter(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		key         string
+		mergeArrays string
+		existing    any
+		value       any
+		expected    string
+	}{
+		{
+			"no match",
+			"abc",
+			"",
+			`{"a":1}`,
+			`{"b":2}`,
+			"",
+		},
+		{
+			"direct name match",
+			"test",
+			"",
+			`{"a":1}`,
+			map[string]any{"b": 2},
+			`{"b":2}`,
+		},
+		{
+			"name+ match with empty existing value",
+			"test+",
+			"",
+			nil,
+			map[string]any{"b": 2},
+			`{"b":2}`,
+		},
+		{
+			"name+ match with non-object existing value",
+			"test+",
+			"",
+			`[1,2]`,
+			`{"b":2}`,
+			`{"b":2}`,
+		},
+		{
+			"name+ match with non-object patch",
+			"test+",
+			"",
+			`{"a":1}`,
+			"abc",
+			`"abc"`,
+		},
+		{
+			"name+ match with null patch",
+			"test+",
+			"",
+			`{"a":1}`,
+			nil,
+			`null`,
+		},
+		{
+			"name+ match deep merge",
+			"test+",
+			"",
+			`{"a":1,"b":{"c":2,"d":3},"e":[1,2],"f":12345678901234567890}`,
+			`{"a":null,"b":{"c":null,"x":{"y":true}},"e":[2,3],"g":"new"}`,
+			`{"b":{"d":3,"x":{"y":true}},"e":[2,3],"f":12345678901234567890,"g":"new"}`,
+		},
+		{
+			"name+ match with replace arrays strategy",
+			"test+",
+			core.JSONMergeArraysReplace,
+			`{"e":[1,2]}`,
+			`{"e":[2,3]}`,
+			`{"e":[2,3]}`,
+		},
+		{
+			"name+ match with append arrays strategy",
+			"test+",
+			core.JSONMergeArraysAppend,
+			`{"e":[1,2],"f":{"g":[{"a":1}]}}`,
+			`{"e":[2,3],"f":{"g":[{"a":1}]}}`,
+			`{"e":[1,2,2,3],"f":{"g":[{"a":1},{"a":1}]}}`,
+		},
+		{
+			"name+ match with unique arrays strategy",
+			"test+",
+			core.JSONMergeArraysUnique,
+			`[1,2,{"a":1}]`,
+			`[2,3,3,{"a":1},{"a":2}]`,
+			`[1,2,{"a":1},3,{"a":2}]`,
+		},
+		{
+			"name+ match with invalid patch",
+			"test+",
+			"",
+			`{"a":1}`,
+			types.JSONRaw(`{"b":`),
+			`{"b":`,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			field := &core.JSONField{Name: "test", MergeArrays: s.mergeArrays}
+
+			collection := core.NewBaseCollection("test_collection")
+			collection.Fields.Add(field)
+
+			setter := field.FindSetter(s.key)
+
+			if s.expected == "" {
+				if setter != nil {
+					t.Fatal("Expected nil setter")
+				}
+				return
+			}
+
+			if setter == nil {
+				t.Fatal("Expected non-nil setter")
+			}
+
+			record := core.NewRecord(collection)
+			if s.existing != nil {
+				record.SetRaw("test", types.JSONRaw(s.existing.(string)))
+			}
+
+			setter(record, s.value)
+
+			raw, _ := record.GetRaw("test").(types.JSONRaw)
+			if str := raw.String(); str != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, str)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package core
+
+import (
+	"context"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/pocketbase/pocketbase/tools/sms"
+	"github.com/spf13/cast"
+)
+
+func init() {
+	Fields[FieldTypePhone] = func() Field {
+		return &PhoneField{}
+	}
+}
+
+const FieldTypePhone = "phone"
+
+var (
+	_ Field        = (*PhoneField)(nil)
+	_ SetterFinder = (*PhoneField)(nil)
+)
+
+// PhoneField defines "phone" type field for storing a single phone number.
+//
+// The value is normalized on set to the E.164 format (eg. "+359888123456").
+// If the normalization fails, the raw string is stored as it is and
+// the record validation will fail with "validation_invalid_phone" error.
+//
+// When the field is part of an auth collection and has a single column UNIQUE index,
+// it could be used as identity field for the password and OTP auth methods.
+//
+// The auth collection phone fields have a companion "{name}Verified" bool field
+// that stores the phone verified state (it is auto created on collection save).
+// The state is set after a successful OTP auth with an OTP sent to the phone
+// and it is reset when the phone changes. Only the verified phones are allowed
+// to be used as auth identity.
+//
+// The respective zero record field value is empty string.
+type PhoneField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// Required will require the field value to be non-empty phone number.
+	Required bool `form:"required" json:"required"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *PhoneField) Type() string {
+	return FieldTypePhone
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *PhoneField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *PhoneField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *PhoneField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *PhoneField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *PhoneField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *PhoneField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *PhoneField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *PhoneField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+func (f *PhoneField) ColumnType(app App) string {
+	return "TEXT DEFAULT '' NOT NULL"
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+func (f *PhoneField) PrepareValue(record *Record, raw any) (any, error) {
+	return cast.ToString(raw), nil
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *PhoneField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	val, ok := record.GetRaw(f.Name).(string)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	if f.Required {
+		if err := validation.Required.Validate(val); err != nil {
+			return err
+		}
+	}
+
+	if val == "" {
+		return nil // nothing to check
+	}
+
+	if !sms.IsE164(val) {
+		return validation.NewError("validation_invalid_phone", "Must be a valid phone number in international format (eg. +359888123456)")
+	}
+
+	return nil
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *PhoneField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+	)
+}
+
+// VerifiedFieldName returns the name of the companion auth collection
+// bool field that stores the phone verified state (aka. "{name}Verified").
+func (f *PhoneField) VerifiedFieldName() string {
+	return f.Name + "Verified"
+}
+
+// FindSetter implements the [SetterFinder] interface.
+func (f *PhoneField) FindSetter(key string) SetterFunc {
+	switch key {
+	case f.Name:
+		return func(record *Record, raw any) {
+			v := cast.ToString(raw)
+
+			if v != "" {
+				if normalized, err := sms.NormalizePhone(v); err == nil {
+					v = normalized
+				}
+			}
+
+			record.SetRaw(f.Name, v)
+		}
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,261 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestPhoneFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypePhone)
+}
+
+func TestPhoneFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.PhoneField{}
+
+	expected := "TEXT DEFAULT '' NOT NULL"
+
+	if v := f.ColumnType(app); v != expected {
+		t.Fatalf("Expected\n%q\ngot\n%q", expected, v)
+	}
+}
+
+func TestPhoneFieldPrepareValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.PhoneField{}
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		raw      any
+		expected string
+	}{
+		{"", ""},
+		{"test", "test"},
+		{"+359888123456", "+359888123456"},
+		{false, "false"},
+		{123.456, "123.456"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			v, err := f.PrepareValue(record, s.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			vStr, ok := v.(string)
+			if !ok {
+				t.Fatalf("Expected string instance, got %T", v)
+			}
+
+			if vStr != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestPhoneFieldValidateValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+
+	scenarios := []struct {
+		name        string
+		field       *core.PhoneField
+		record      func() *core.Record
+		expectError bool
+	}{
+		{
+			"invalid raw value",
+			&core.PhoneField{Name: "test"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", 123)
+				return record
+			},
+			true,
+		},
+		{
+			"zero field value (not required)",
+			&core.PhoneField{Name: "test"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "")
+				return record
+			},
+			false,
+		},
+		{
+			"zero field value (required)",
+			&core.PhoneField{Name: "test", Required: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "")
+				return record
+			},
+			true,
+		},
+		{
+			"non-zero field value (required)",
+			&core.PhoneField{Name: "test", Required: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "+359888123456")
+				return record
+			},
+			false,
+		},
+		{
+			"non-normalized phone",
+			&core.PhoneField{Name: "test"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "+359 888 123 456")
+				return record
+			},
+			true,
+		},
+		{
+			"phone without country code",
+			&core.PhoneField{Name: "test"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "0888123456")
+				return record
+			},
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.field.ValidateValue(context.Background(), app, s.record())
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestPhoneFieldValidateSettings(t *testing.T) {
+	testDefaultFieldIdValidation(t, core.FieldTypePhone)
+	testDefaultFieldNameValidation(t, core.FieldTypePhone)
+}
+
+func TestPhoneFieldFindSetter(t *testing.T) {
+	field := &core.PhoneField{Name: "test"}
+
+	collection := core.NewBaseCollection("test_collection")
+	collection.Fields.Add(field)
+
+	t.Run("no match", func(t *testing.T) {
+		f := field.FindSetter("abc")
+		if f != nil {
+			t.Fatal("Expected nil setter")
+		}
+	})
+
+	scenarios := []struct {
+		raw      any
+		expected string
+	}{
+		{"", ""},
+		{"+1 (555) 123-4567", "+15551234567"},
+		{"0044 20 7946 0958", "+442079460958"},
+		{"invalid", "invalid"},
+		{359888123456, "359888123456"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			f := field.FindSetter("test")
+			if f == nil {
+				t.Fatal("Expected non-nil setter")
+			}
+
+			record := core.NewRecord(collection)
+
+			f(record, s.raw)
+
+			if v := record.GetString("test"); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestPhoneFieldVerifiedFieldName(t *testing.T) {
+	f := &core.PhoneField{Name: "test"}
+
+	if v := f.VerifiedFieldName(); v != "testVerified" {
+		t.Fatalf("Expected %q, got %q", "testVerified", v)
+	}
+}
+
+func TestPhoneFieldVerifiedState(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	users.Fields.Add(&core.PhoneField{Name: "phone"})
+	if err = app.Save(users); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := users.Fields.GetByName("phoneVerified").(*core.BoolField); !ok {
+		t.Fatalf("Expected the phoneVerified bool field to be created, got %#v", users.Fields.GetByName("phoneVerified"))
+	}
+
+	user, err := app.FindAuthRecordByEmail(users, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user.Set("phone", "+359888123456")
+	user.SetPhoneVerified("phone", true)
+	if err = app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if !user.PhoneVerified("phone") {
+		t.Fatal("Expected the phone to be verified when changed together with the verified state")
+	}
+
+	user.Set("name", "test_name")
+	if err = app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if !user.PhoneVerified("phone") {
+		t.Fatal("Expected the phone to remain verified on unrelated field change")
+	}
+
+	user.Set("phone", "+359888000000")
+	if err = app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if user.PhoneVerified("phone") {
+		t.Fatal("Expected the phone verified state to be reset on phone change")
+	}
+
+	// non-bool companion field
+	users.Fields.Add(&core.TextField{Name: "phoneVerified"})
+	tests.TestValidationErrors(t, app.Validate(users), []string{"fields"})
+}
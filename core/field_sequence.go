@@ -0,0 +1,214 @@
+package core
+
+import (
+	"context"
+	"fmt"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/spf13/cast"
+)
+
+func init() {
+	Fields[FieldTypeSequence] = func() Field {
+		return &SequenceField{}
+	}
+}
+
+const FieldTypeSequence = "sequence"
+
+// sequenceParamKeyPrefix is the prefix of the [paramsTable] keys
+// used to store the last generated sequence field values.
+const sequenceParamKeyPrefix = "sequence_"
+
+var (
+	_ Field             = (*SequenceField)(nil)
+	_ SetterFinder      = (*SequenceField)(nil)
+	_ RecordInterceptor = (*SequenceField)(nil)
+)
+
+// SequenceField defines "sequence" type field, aka. an auto-increment
+// numeric field which value is auto assigned on record create
+// (eg. for invoice numbers and other human-friendly identifiers).
+//
+// The generated values are strictly increasing within the collection
+// field but gaps are possible (eg. on failed record create or delete).
+//
+// The last generated value is stored in the app params table and it is
+// incremented with a single atomic statement as part of the record create
+// execution (so it is safe to use inside transactions and with concurrent writes).
+//
+// Note that the field value can't be changed with record.Set() but
+// you can still assign an explicit positive value with record.SetRaw()
+// (eg. when importing existing data).
+//
+// The respective zero record field value is 0.
+type SequenceField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// Start specifies the first generated sequence value.
+	//
+	// If zero, fallback to 1.
+	Start int64 `form:"start" json:"start"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *SequenceField) Type() string {
+	return FieldTypeSequence
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *SequenceField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *SequenceField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *SequenceField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *SequenceField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *SequenceField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *SequenceField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *SequenceField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *SequenceField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+func (f *SequenceField) ColumnType(app App) string {
+	return "NUMERIC DEFAULT 0 NOT NULL"
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+func (f *SequenceField) PrepareValue(record *Record, raw any) (any, error) {
+	return cast.ToInt64(raw), nil
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *SequenceField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	if _, ok := record.GetRaw(f.Name).(int64); !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	return nil
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *SequenceField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+		validation.Field(&f.Start, validation.Min(0), validation.Max(maxSafeJSONInt)),
+	)
+}
+
+// FindSetter implements the [SetterFinder] interface.
+func (f *SequenceField) FindSetter(key string) SetterFunc {
+	switch key {
+	case f.Name:
+		// return noopSetter to disallow updating the value with record.Set()
+		return noopSetter
+	default:
+		return nil
+	}
+}
+
+// Intercept implements the [RecordInterceptor] interface.
+func (f *SequenceField) Intercept(
+	ctx context.Context,
+	app App,
+	record *Record,
+	actionName string,
+	actionFunc func() error,
+) error {
+	// assign only if a value wasn't already set
+	// (eg. explicitly with SetRaw or from a previous failed create attempt)
+	if actionName == InterceptorActionCreateExecute && record.GetInt(f.Name) <= 0 {
+		next, err := f.next(app, record.Collection())
+		if err != nil {
+			return fmt.Errorf("failed to generate %q sequence value: %w", f.Name, err)
+		}
+
+		record.SetRaw(f.Name, next)
+	}
+
+	return actionFunc()
+}
+
+// ParamKey returns the app params key where the last generated
+// sequence value of the field is stored.
+func (f *SequenceField) ParamKey(collection *Collection) string {
+	return sequenceParamKeyPrefix + collection.Id + "_" + f.Id
+}
+
+func (f *SequenceField) start() int64 {
+	if f.Start <= 0 {
+		return 1
+	}
+
+	return f.Start
+}
+
+// next increments and returns the next sequence value.
+//
+// The new value is also guaranteed to be greater than
+// the current max stored field value (eg. in case of explicitly set values).
+func (f *SequenceField) next(app App, collection *Collection) (int64, error) {
+	var next int64
+
+	err := app.NonconcurrentDB().NewQuery(
+		"INSERT INTO {{" + paramsTable + "}} ([[id]], [[value]]) " +
+			"VALUES ({:key}, MAX({:start}, COALESCE((SELECT MAX([[" + f.Name + "]]) FROM {{" + collection.Name + "}}), 0) + 1)) " +
+			"ON CONFLICT ([[id]]) DO UPDATE SET " +
+			"[[value]] = MAX(CAST([[value]] AS INTEGER) + 1, {:start}, COALESCE((SELECT MAX([[" + f.Name + "]]) FROM {{" + collection.Name + "}}), 0) + 1), " +
+			"[[updated]] = strftime('%Y-%m-%d %H:%M:%fZ') " +
+			"RETURNING CAST([[value]] AS INTEGER)",
+	).Bind(dbx.Params{
+		"key":   f.ParamKey(collection),
+		"start": f.start(),
+	}).Row(&next)
+
+	return next, err
+}
@@ -0,0 +1,225 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestSequenceFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypeSequence)
+}
+
+func TestSequenceFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.SequenceField{}
+
+	expected := "NUMERIC DEFAULT 0 NOT NULL"
+
+	if v := f.ColumnType(app); v != expected {
+		t.Fatalf("Expected\n%q\ngot\n%q", expected, v)
+	}
+}
+
+func TestSequenceFieldPrepareValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.SequenceField{}
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		raw      any
+		expected int64
+	}{
+		{"", 0},
+		{"invalid", 0},
+		{"12", 12},
+		{12.6, 12},
+		{int64(123), 123},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			v, err := f.PrepareValue(record, s.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v != s.expected {
+				t.Fatalf("Expected %v, got %v (%T)", s.expected, v, v)
+			}
+		})
+	}
+}
+
+func TestSequenceFieldValidateValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+	field := &core.SequenceField{Name: "test"}
+
+	t.Run("invalid raw value", func(t *testing.T) {
+		record := core.NewRecord(collection)
+		record.SetRaw("test", "123")
+
+		if err := field.ValidateValue(context.Background(), app, record); err == nil {
+			t.Fatal("Expected error")
+		}
+	})
+
+	t.Run("int64 raw value", func(t *testing.T) {
+		record := core.NewRecord(collection)
+		record.SetRaw("test", int64(123))
+
+		if err := field.ValidateValue(context.Background(), app, record); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+	})
+}
+
+func TestSequenceFieldValidateSettings(t *testing.T) {
+	testDefaultFieldIdValidation(t, core.FieldTypeSequence)
+	testDefaultFieldNameValidation(t, core.FieldTypeSequence)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+
+	scenarios := []struct {
+		name         string
+		field        *core.SequenceField
+		expectErrors []string
+	}{
+		{
+			"zero start",
+			&core.SequenceField{Id: "test", Name: "test"},
+			[]string{},
+		},
+		{
+			"negative start",
+			&core.SequenceField{Id: "test", Name: "test", Start: -1},
+			[]string{"start"},
+		},
+		{
+			"positive start",
+			&core.SequenceField{Id: "test", Name: "test", Start: 1000},
+			[]string{},
+		},
+		{
+			"start > safe json int",
+			&core.SequenceField{Id: "test", Name: "test", Start: 1 << 53},
+			[]string{"start"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := s.field.ValidateSettings(context.Background(), app, collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestSequenceFieldFindSetter(t *testing.T) {
+	field := &core.SequenceField{Name: "test"}
+
+	collection := core.NewBaseCollection("test_collection")
+	collection.Fields.Add(field)
+
+	record := core.NewRecord(collection)
+	record.SetRaw("test", int64(5))
+
+	t.Run("no matching setter", func(t *testing.T) {
+		if f := field.FindSetter("abc"); f != nil {
+			t.Fatal("Expected nil setter")
+		}
+	})
+
+	t.Run("matching setter", func(t *testing.T) {
+		f := field.FindSetter("test")
+		if f == nil {
+			t.Fatal("Expected non-nil setter")
+		}
+
+		f(record, 10) // should be ignored
+
+		if v := record.GetInt("test"); v != 5 {
+			t.Fatalf("Expected no value change, got %d", v)
+		}
+	})
+}
+
+func TestSequenceFieldIntercept(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("invoices")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	collection.Fields.Add(&core.SequenceField{Name: "number", Start: 100})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	result := []int{}
+
+	create := func(explicit int64) *core.Record {
+		record := core.NewRecord(collection)
+		if explicit > 0 {
+			record.SetRaw("number", explicit)
+		}
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		result = append(result, record.GetInt("number"))
+		return record
+	}
+
+	create(0)
+	create(0)
+
+	// deleted values must not be reused
+	last := create(0)
+	if err := app.Delete(last); err != nil {
+		t.Fatal(err)
+	}
+	create(0)
+
+	// explicit values are kept and the sequence continues after them
+	create(200)
+	create(0)
+
+	// failed transaction doesn't reuse the already generated value
+	// (the counter update is rolled back together with the record)
+	_ = app.RunInTransaction(func(txApp core.App) error {
+		record := core.NewRecord(collection)
+		if err := txApp.Save(record); err != nil {
+			t.Fatal(err)
+		}
+		return fmt.Errorf("rollback")
+	})
+	create(0)
+
+	// updates don't change the value
+	updated := create(0)
+	updated.Set("title", "test")
+	updated.Set("number", 1)
+	if err := app.Save(updated); err != nil {
+		t.Fatal(err)
+	}
+	result = append(result, updated.GetInt("number"))
+
+	expected := []int{100, 101, 102, 103, 200, 201, 202, 203, 203}
+	if !slices.Equal(result, expected) {
+		t.Fatalf("Expected sequence\n%v\ngot\n%v", expected, result)
+	}
+}
@@ -0,0 +1,350 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/pocketbase/pocketbase/tools/inflector"
+	"github.com/spf13/cast"
+)
+
+func init() {
+	Fields[FieldTypeSlug] = func() Field {
+		return &SlugField{}
+	}
+}
+
+const FieldTypeSlug = "slug"
+
+// Supported [SlugField.Regenerate] policies.
+const (
+	// SlugRegenerateNever generates the slug only if the field value is empty (default).
+	SlugRegenerateNever = ""
+
+	// SlugRegenerateOnSourceChange regenerates the slug every time the source field
+	// value changes, unless the slug itself was also explicitly changed.
+	SlugRegenerateOnSourceChange = "onSourceChange"
+
+	// SlugRegenerateAlways regenerates the slug on every record save,
+	// ignoring any explicitly set value.
+	SlugRegenerateAlways = "always"
+)
+
+// maxSlugSuffixAttempts is the max number of the numeric suffixes
+// to try when looking for an unique slug.
+const maxSlugSuffixAttempts = 1000
+
+var (
+	_ Field             = (*SlugField)(nil)
+	_ RecordInterceptor = (*SlugField)(nil)
+)
+
+// SlugField defines "slug" type field for storing an URL friendly
+// identifier generated from another (source) record field value.
+//
+// The generated slug is unique within the collection - if the slug
+// already exists, a numeric suffix is appended (eg. "hello-world-2").
+//
+// Manually set values are also allowed but they must be valid
+// and unique slugs.
+//
+// The respective zero record field value is empty string.
+type SlugField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// Source (required) is the name of the collection field
+	// whose value is used to generate the slug (eg. "title").
+	Source string `form:"source" json:"source"`
+
+	// Max specifies the max allowed slug length.
+	//
+	// If zero, fallback to max 100.
+	Max int `form:"max" json:"max"`
+
+	// Transliterate specifies whether to convert the most common
+	// non-ASCII letters of the source value to their ASCII equivalent
+	// (eg. "Crème Brûlée" -> "creme-brulee").
+	//
+	// If false, the non-ASCII letters are preserved in the slug.
+	Transliterate bool `form:"transliterate" json:"transliterate"`
+
+	// Regenerate specifies the slug regeneration policy of the existing records.
+	//
+	// Supported values: [SlugRegenerateNever] (default), [SlugRegenerateOnSourceChange] and [SlugRegenerateAlways].
+	Regenerate string `form:"regenerate" json:"regenerate"`
+
+	// Required will require the field value to be non-empty.
+	Required bool `form:"required" json:"required"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *SlugField) Type() string {
+	return FieldTypeSlug
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *SlugField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *SlugField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *SlugField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *SlugField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *SlugField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *SlugField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *SlugField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *SlugField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+func (f *SlugField) ColumnType(app App) string {
+	return "TEXT DEFAULT '' NOT NULL"
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+func (f *SlugField) PrepareValue(record *Record, raw any) (any, error) {
+	return cast.ToString(raw), nil
+}
+
+// Slugify converts the provided string into a slug according to the field options
+// (without the uniqueness suffix).
+func (f *SlugField) Slugify(str string) string {
+	if f.Transliterate {
+		str = inflector.Transliterate(str)
+	}
+
+	return truncateSlug(inflector.Slugify(str), f.maxLength())
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *SlugField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	val, ok := record.GetRaw(f.Name).(string)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	if val == "" {
+		if f.Required {
+			return validation.ErrRequired
+		}
+		return nil // nothing to check
+	}
+
+	max := f.maxLength()
+	if len([]rune(val)) > max {
+		return validation.NewError("validation_max_text_constraint", "Must be no more than {{.max}} character(s)").
+			SetParams(map[string]any{"max": max})
+	}
+
+	if f.Slugify(val) != val {
+		return validation.NewError("validation_invalid_slug", "Must be a valid slug (eg. hello-world)")
+	}
+
+	// skip the unique check if the slug wasn't changed
+	if !record.IsNew() && record.Original().GetString(f.Name) == val {
+		return nil
+	}
+
+	exists, err := f.slugExists(app, record, val)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return validation.NewError("validation_not_unique", "The slug is already in use.")
+	}
+
+	return nil
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *SlugField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+		validation.Field(&f.Source, validation.Required, validation.By(f.checkSource(collection))),
+		validation.Field(&f.Max, validation.Min(0), validation.Max(maxSafeJSONInt)),
+		validation.Field(
+			&f.Regenerate,
+			validation.In(SlugRegenerateNever, SlugRegenerateOnSourceChange, SlugRegenerateAlways),
+		),
+	)
+}
+
+func (f *SlugField) checkSource(collection *Collection) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		source := collection.Fields.GetByName(v)
+		if source == nil || source.GetName() == f.Name {
+			return validation.NewError("validation_invalid_slug_source", "The slug source must be another existing collection field.")
+		}
+
+		switch source.(type) {
+		case *PasswordField, *FileField, *RelationField, *JSONField, *SlugField:
+			return validation.NewError("validation_unsupported_slug_source", "The slug source field type is not supported.")
+		}
+
+		return nil
+	}
+}
+
+// Intercept implements the [RecordInterceptor] interface.
+func (f *SlugField) Intercept(
+	ctx context.Context,
+	app App,
+	record *Record,
+	actionName string,
+	actionFunc func() error,
+) error {
+	switch actionName {
+	case InterceptorActionValidate, InterceptorActionCreate, InterceptorActionUpdate:
+		if f.shouldGenerate(record) {
+			slug, err := f.generate(app, record)
+			if err != nil {
+				return fmt.Errorf("failed to generate %q slug: %w", f.Name, err)
+			}
+			record.SetRaw(f.Name, slug)
+		}
+	}
+
+	return actionFunc()
+}
+
+func (f *SlugField) shouldGenerate(record *Record) bool {
+	if f.Regenerate == SlugRegenerateAlways || record.GetString(f.Name) == "" {
+		return true
+	}
+
+	if record.IsNew() || f.Regenerate != SlugRegenerateOnSourceChange {
+		return false
+	}
+
+	original := record.Original()
+
+	// regenerate only if the source was changed and the slug was not explicitly modified
+	return cast.ToString(original.GetRaw(f.Source)) != cast.ToString(record.GetRaw(f.Source)) &&
+		original.GetString(f.Name) == record.GetString(f.Name)
+}
+
+func (f *SlugField) generate(app App, record *Record) (string, error) {
+	base := f.Slugify(cast.ToString(record.GetRaw(f.Source)))
+	if base == "" {
+		return "", nil
+	}
+
+	max := f.maxLength()
+
+	slug := base
+
+	for i := 2; i < maxSlugSuffixAttempts; i++ {
+		exists, err := f.slugExists(app, record, slug)
+		if err != nil {
+			return "", err
+		}
+		if !exists {
+			return slug, nil
+		}
+
+		suffix := "-" + strconv.Itoa(i)
+		slug = truncateSlug(base, max-len(suffix)) + suffix
+	}
+
+	return "", errors.New("too many slug conflicts")
+}
+
+func (f *SlugField) slugExists(app App, record *Record, slug string) (bool, error) {
+	var exists int
+
+	query := app.ConcurrentDB().
+		Select("(1)").
+		From(record.TableName()).
+		Where(dbx.HashExp{f.Name: slug}).
+		Limit(1)
+
+	if !record.IsNew() {
+		query.AndWhere(dbx.Not(dbx.HashExp{FieldNameId: record.LastSavedPK()}))
+	}
+
+	err := query.Row(&exists)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	return exists > 0, nil
+}
+
+func (f *SlugField) maxLength() int {
+	if f.Max <= 0 {
+		return 100
+	}
+
+	return f.Max
+}
+
+// truncateSlug truncates the slug to max runes (trimming any trailing dash).
+func truncateSlug(slug string, max int) string {
+	runes := []rune(slug)
+	if len(runes) <= max {
+		return slug
+	}
+
+	if max <= 0 {
+		return ""
+	}
+
+	return strings.TrimRight(string(runes[:max]), "-")
+}
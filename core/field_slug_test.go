@@ -0,0 +1,424 @@
+package core_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestSlugFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypeSlug)
+}
+
+func TestSlugFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.SlugField{}
+
+	expected := "TEXT DEFAULT '' NOT NULL"
+
+	if v := f.ColumnType(app); v != expected {
+		t.Fatalf("Expected\n%q\ngot\n%q", expected, v)
+	}
+}
+
+func TestSlugFieldPrepareValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.SlugField{}
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		raw      any
+		expected string
+	}{
+		{"", ""},
+		{"hello-world", "hello-world"},
+		{false, "false"},
+		{123, "123"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			v, err := f.PrepareValue(record, s.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			vStr, ok := v.(string)
+			if !ok {
+				t.Fatalf("Expected string instance, got %T", v)
+			}
+
+			if vStr != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestSlugFieldSlugify(t *testing.T) {
+	scenarios := []struct {
+		field    *core.SlugField
+		value    string
+		expected string
+	}{
+		{&core.SlugField{}, "", ""},
+		{&core.SlugField{}, " Crème Brûlée! ", "crème-brûlée"},
+		{&core.SlugField{Transliterate: true}, " Crème Brûlée! ", "creme-brulee"},
+		{&core.SlugField{Max: 8}, "hello world", "hello-wo"},
+		{&core.SlugField{Max: 6}, "hello world", "hello"},
+		{&core.SlugField{}, strings.Repeat("a", 101), strings.Repeat("a", 100)},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.value), func(t *testing.T) {
+			result := s.field.Slugify(s.value)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestSlugFieldValidateValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing, err := app.FindRecordById(collection, "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	existing.Set("text", "taken")
+	if err := app.SaveNoValidate(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		field       *core.SlugField
+		record      func() *core.Record
+		expectError bool
+	}{
+		{
+			"invalid raw value",
+			&core.SlugField{Name: "text"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("text", 123)
+				return record
+			},
+			true,
+		},
+		{
+			"zero field value (not required)",
+			&core.SlugField{Name: "text"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("text", "")
+				return record
+			},
+			false,
+		},
+		{
+			"zero field value (required)",
+			&core.SlugField{Name: "text", Required: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("text", "")
+				return record
+			},
+			true,
+		},
+		{
+			"invalid slug",
+			&core.SlugField{Name: "text"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("text", "Hello World")
+				return record
+			},
+			true,
+		},
+		{
+			"non-transliterated slug",
+			&core.SlugField{Name: "text", Transliterate: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("text", "crème")
+				return record
+			},
+			true,
+		},
+		{
+			"> max",
+			&core.SlugField{Name: "text", Max: 3},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("text", "abcd")
+				return record
+			},
+			true,
+		},
+		{
+			"valid unique slug",
+			&core.SlugField{Name: "text", Required: true},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("text", "hello-world")
+				return record
+			},
+			false,
+		},
+		{
+			"existing slug (new record)",
+			&core.SlugField{Name: "text"},
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("text", "taken")
+				return record
+			},
+			true,
+		},
+		{
+			"existing slug (another record)",
+			&core.SlugField{Name: "text"},
+			func() *core.Record {
+				record, err := app.FindRecordById(collection, "al1h9ijdeojtsjy")
+				if err != nil {
+					t.Fatal(err)
+				}
+				record.SetRaw("text", "taken")
+				return record
+			},
+			true,
+		},
+		{
+			"existing slug (same record)",
+			&core.SlugField{Name: "text"},
+			func() *core.Record {
+				record, err := app.FindRecordById(collection, "84nmscqy84lsi1t")
+				if err != nil {
+					t.Fatal(err)
+				}
+				return record
+			},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.field.ValidateValue(context.Background(), app, s.record())
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestSlugFieldValidateSettings(t *testing.T) {
+	testDefaultFieldIdValidation(t, core.FieldTypeSlug)
+	testDefaultFieldNameValidation(t, core.FieldTypeSlug)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name         string
+		field        func() *core.SlugField
+		expectErrors []string
+	}{
+		{
+			"zero minimal",
+			func() *core.SlugField {
+				return &core.SlugField{
+					Id:   "test",
+					Name: "test",
+				}
+			},
+			[]string{"source"},
+		},
+		{
+			"missing source field",
+			func() *core.SlugField {
+				return &core.SlugField{
+					Id:     "test",
+					Name:   "test",
+					Source: "missing",
+				}
+			},
+			[]string{"source"},
+		},
+		{
+			"self source field",
+			func() *core.SlugField {
+				return &core.SlugField{
+					Id:     "test",
+					Name:   "test",
+					Source: "test",
+				}
+			},
+			[]string{"source"},
+		},
+		{
+			"unsupported source field",
+			func() *core.SlugField {
+				return &core.SlugField{
+					Id:     "test",
+					Name:   "test",
+					Source: "file",
+				}
+			},
+			[]string{"source"},
+		},
+		{
+			"invalid max and regenerate",
+			func() *core.SlugField {
+				return &core.SlugField{
+					Id:         "test",
+					Name:       "test",
+					Source:     "title",
+					Max:        -1,
+					Regenerate: "invalid",
+				}
+			},
+			[]string{"max", "regenerate"},
+		},
+		{
+			"valid settings",
+			func() *core.SlugField {
+				return &core.SlugField{
+					Id:            "test",
+					Name:          "test",
+					Source:        "title",
+					Max:           50,
+					Transliterate: true,
+					Regenerate:    core.SlugRegenerateOnSourceChange,
+				}
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			field := s.field()
+
+			collection := core.NewBaseCollection("test_collection")
+			collection.Fields.Add(&core.TextField{Name: "title"})
+			collection.Fields.Add(&core.FileField{Name: "file"})
+			collection.Fields.Add(field)
+
+			errs := field.ValidateSettings(context.Background(), app, collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestSlugFieldIntercept(t *testing.T) {
+	scenarios := []struct {
+		regenerate string
+		expected   []string
+	}{
+		{
+			core.SlugRegenerateNever,
+			[]string{"hello-world", "hello-world-2", "hello-world", "custom", "custom", "explicit", "explicit-2"},
+		},
+		{
+			core.SlugRegenerateOnSourceChange,
+			[]string{"hello-world", "hello-world-2", "new-title", "custom", "other-title", "explicit", "explicit-2"},
+		},
+		{
+			core.SlugRegenerateAlways,
+			[]string{"hello-world", "hello-world-2", "new-title", "new-title", "other-title", "another-title", "explicit"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.regenerate, func(t *testing.T) {
+			app, _ := tests.NewTestApp()
+			defer app.Cleanup()
+
+			collection := core.NewBaseCollection("posts")
+			collection.Fields.Add(&core.TextField{Name: "title"})
+			collection.Fields.Add(&core.SlugField{
+				Name:          "slug",
+				Source:        "title",
+				Transliterate: true,
+				Regenerate:    s.regenerate,
+			})
+			if err := app.Save(collection); err != nil {
+				t.Fatal(err)
+			}
+
+			result := make([]string, 0, len(s.expected))
+
+			save := func(record *core.Record) *core.Record {
+				if err := app.Save(record); err != nil {
+					t.Fatal(err)
+				}
+
+				// reload to refresh the original record state
+				fresh, err := app.FindRecordById(collection, record.Id)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				result = append(result, fresh.GetString("slug"))
+
+				return fresh
+			}
+
+			// new record with generated slug
+			r1 := core.NewRecord(collection)
+			r1.Set("title", "Hello, World!")
+			r1 = save(r1)
+
+			// new record with conflicting generated slug
+			r2 := core.NewRecord(collection)
+			r2.Set("title", "Héllo World")
+			save(r2)
+
+			// source change
+			r1.Set("title", "New title")
+			r1 = save(r1)
+
+			// explicit slug change
+			r1.Set("slug", "custom")
+			r1 = save(r1)
+
+			// source change after explicit slug change
+			r1.Set("title", "Other title")
+			r1 = save(r1)
+
+			// explicit slug and source change
+			r1.Set("title", "Another title")
+			r1.Set("slug", "explicit")
+			r1 = save(r1)
+
+			// new record with slug conflicting with the last r1 slug (if not regenerated)
+			r3 := core.NewRecord(collection)
+			r3.Set("title", "Explicit")
+			save(r3)
+
+			if strings.Join(result, ",") != strings.Join(s.expected, ",") {
+				t.Fatalf("Expected slugs\n%v\ngot\n%v", s.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,299 @@
+package core
+
+import (
+	"context"
+	"slices"
+	"strconv"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/spf13/cast"
+)
+
+func init() {
+	Fields[FieldTypeState] = func() Field {
+		return &StateField{}
+	}
+}
+
+const FieldTypeState = "state"
+
+var (
+	_ Field             = (*StateField)(nil)
+	_ RecordInterceptor = (*StateField)(nil)
+)
+
+// StateField defines "state" type field for storing a single
+// state machine value from a predefined list of states.
+//
+// Requires the States option to be set.
+//
+// If Transitions are set, the field value of an existing record could be
+// changed only to a state reachable with one of the listed transitions
+// (the state of records without a previous value could be set freely).
+// Each successful state change triggers the [App.OnRecordTransition] hook.
+//
+// The respective zero record field value is empty string.
+type StateField struct {
+	// Name (required) is the unique name of the field.
+	Name string `form:"name" json:"name"`
+
+	// Id is the unique stable field identifier.
+	//
+	// It is automatically generated from the name when adding to a collection FieldsList.
+	Id string `form:"id" json:"id"`
+
+	// System prevents the renaming and removal of the field.
+	System bool `form:"system" json:"system"`
+
+	// Hidden hides the field from the API response.
+	Hidden bool `form:"hidden" json:"hidden"`
+
+	// Presentable hints the Dashboard UI to use the underlying
+	// field record value in the relation preview label.
+	Presentable bool `form:"presentable" json:"presentable"`
+
+	// ---
+
+	// States specifies the list of the allowed field states.
+	States []string `form:"states" json:"states"`
+
+	// Initial is an optional state that is assigned to the new records
+	// if no other state was set.
+	Initial string `form:"initial" json:"initial"`
+
+	// Transitions specifies the list of the allowed state changes.
+	//
+	// If empty, the state could be changed to any of the States.
+	Transitions []StateTransition `form:"transitions" json:"transitions"`
+
+	// Required will require the field value to be non-empty.
+	Required bool `form:"required" json:"required"`
+}
+
+// StateTransition defines a single allowed [StateField] state change.
+type StateTransition struct {
+	// Name is an optional unique transition identifier (eg. "ship").
+	//
+	// It could be used as "collectionName.transitionName" tag of the [App.OnRecordTransition] hook.
+	Name string `form:"name" json:"name"`
+
+	// From specifies the list of the source states of the transition.
+	From []string `form:"from" json:"from"`
+
+	// To specifies the target state of the transition.
+	To string `form:"to" json:"to"`
+
+	// Rule is an optional API rule filter that must be satisfied
+	// (in addition to the collection UpdateRule) when the transition
+	// is performed as part of a records update API request.
+	//
+	// The rule is ignored for superusers.
+	Rule string `form:"rule" json:"rule"`
+}
+
+// Type implements [Field.Type] interface method.
+func (f *StateField) Type() string {
+	return FieldTypeState
+}
+
+// GetId implements [Field.GetId] interface method.
+func (f *StateField) GetId() string {
+	return f.Id
+}
+
+// SetId implements [Field.SetId] interface method.
+func (f *StateField) SetId(id string) {
+	f.Id = id
+}
+
+// GetName implements [Field.GetName] interface method.
+func (f *StateField) GetName() string {
+	return f.Name
+}
+
+// SetName implements [Field.SetName] interface method.
+func (f *StateField) SetName(name string) {
+	f.Name = name
+}
+
+// GetSystem implements [Field.GetSystem] interface method.
+func (f *StateField) GetSystem() bool {
+	return f.System
+}
+
+// SetSystem implements [Field.SetSystem] interface method.
+func (f *StateField) SetSystem(system bool) {
+	f.System = system
+}
+
+// GetHidden implements [Field.GetHidden] interface method.
+func (f *StateField) GetHidden() bool {
+	return f.Hidden
+}
+
+// SetHidden implements [Field.SetHidden] interface method.
+func (f *StateField) SetHidden(hidden bool) {
+	f.Hidden = hidden
+}
+
+// ColumnType implements [Field.ColumnType] interface method.
+func (f *StateField) ColumnType(app App) string {
+	return "TEXT DEFAULT '' NOT NULL"
+}
+
+// PrepareValue implements [Field.PrepareValue] interface method.
+func (f *StateField) PrepareValue(record *Record, raw any) (any, error) {
+	return cast.ToString(raw), nil
+}
+
+// FindTransition returns the first transition that allows changing
+// the state from the "from" state to the "to" state.
+//
+// Returns nil if no such transition exists.
+func (f *StateField) FindTransition(from string, to string) *StateTransition {
+	for i, t := range f.Transitions {
+		if t.To == to && slices.Contains(t.From, from) {
+			return &f.Transitions[i]
+		}
+	}
+
+	return nil
+}
+
+// ValidateValue implements [Field.ValidateValue] interface method.
+func (f *StateField) ValidateValue(ctx context.Context, app App, record *Record) error {
+	val, ok := record.GetRaw(f.Name).(string)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	if val == "" {
+		if f.Required {
+			return validation.ErrRequired
+		}
+		return nil // nothing to check
+	}
+
+	if !slices.Contains(f.States, val) {
+		return validation.NewError("validation_invalid_state", "Invalid state {{.state}}").
+			SetParams(map[string]any{"state": val})
+	}
+
+	if record.IsNew() || len(f.Transitions) == 0 {
+		return nil // no transition to check
+	}
+
+	old := record.Original().GetString(f.Name)
+	if old != "" && old != val && f.FindTransition(old, val) == nil {
+		return validation.NewError("validation_invalid_state_transition", "Cannot change the state from {{.from}} to {{.to}}").
+			SetParams(map[string]any{"from": old, "to": val})
+	}
+
+	return nil
+}
+
+// ValidateSettings implements [Field.ValidateSettings] interface method.
+func (f *StateField) ValidateSettings(ctx context.Context, app App, collection *Collection) error {
+	return validation.ValidateStruct(f,
+		validation.Field(&f.Id, validation.By(DefaultFieldIdValidationRule)),
+		validation.Field(&f.Name, validation.By(DefaultFieldNameValidationRule)),
+		validation.Field(&f.States, validation.Required, validation.Each(validation.Required), validation.By(checkUniqueStates)),
+		validation.Field(&f.Initial, validation.In(list.ToInterfaceSlice(f.States)...)),
+		validation.Field(&f.Transitions, validation.By(f.checkTransitions(app, collection))),
+	)
+}
+
+func checkUniqueStates(value any) error {
+	states, _ := value.([]string)
+
+	if len(list.ToUniqueStringSlice(states)) != len(states) {
+		return validation.NewError("validation_duplicated_state", "The states must be unique.")
+	}
+
+	return nil
+}
+
+func (f *StateField) checkTransitions(app App, collection *Collection) validation.RuleFunc {
+	return func(value any) error {
+		transitions, _ := value.([]StateTransition)
+
+		names := make(map[string]struct{}, len(transitions))
+
+		for i, t := range transitions {
+			err := validation.ValidateStruct(&t,
+				validation.Field(&t.Name, validation.By(func(value any) error {
+					if t.Name == "" {
+						return nil
+					}
+
+					if _, ok := names[t.Name]; ok {
+						return validation.NewError("validation_duplicated_transition", "The transition name must be unique.")
+					}
+					names[t.Name] = struct{}{}
+
+					return nil
+				})),
+				validation.Field(&t.From, validation.Required, validation.Each(validation.In(list.ToInterfaceSlice(f.States)...))),
+				validation.Field(&t.To, validation.Required, validation.In(list.ToInterfaceSlice(f.States)...)),
+				validation.Field(&t.Rule, validation.By(func(value any) error {
+					if t.Rule == "" {
+						return nil
+					}
+
+					r := NewRecordFieldResolver(app, collection, nil, true)
+					if _, err := search.FilterData(t.Rule).BuildExpr(r); err != nil {
+						return validation.NewError("validation_invalid_rule", "Invalid rule. Raw error: "+err.Error())
+					}
+
+					return nil
+				})),
+			)
+			if err != nil {
+				return validation.Errors{strconv.Itoa(i): err}
+			}
+		}
+
+		return nil
+	}
+}
+
+// Intercept implements the [RecordInterceptor] interface.
+func (f *StateField) Intercept(
+	ctx context.Context,
+	app App,
+	record *Record,
+	actionName string,
+	actionFunc func() error,
+) error {
+	switch actionName {
+	case InterceptorActionValidate, InterceptorActionCreate:
+		// set the initial state if missing for new records
+		if f.Initial != "" && record.IsNew() && record.GetString(f.Name) == "" {
+			record.SetRaw(f.Name, f.Initial)
+		}
+	case InterceptorActionUpdateExecute:
+		oldState := record.Original().GetString(f.Name)
+		newState := record.GetString(f.Name)
+		if oldState == newState {
+			return actionFunc()
+		}
+
+		event := new(RecordTransitionEvent)
+		event.App = app
+		event.Context = ctx
+		event.Record = record
+		event.Field = f
+		event.From = oldState
+		event.To = newState
+		event.Transition = f.FindTransition(oldState, newState)
+
+		return app.OnRecordTransition().Trigger(event, func(e *RecordTransitionEvent) error {
+			return actionFunc()
+		})
+	}
+
+	return actionFunc()
+}
@@ -0,0 +1,492 @@
+package core_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestStateFieldBaseMethods(t *testing.T) {
+	testFieldBaseMethods(t, core.FieldTypeState)
+}
+
+func TestStateFieldColumnType(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.StateField{}
+
+	expected := "TEXT DEFAULT '' NOT NULL"
+
+	if v := f.ColumnType(app); v != expected {
+		t.Fatalf("Expected\n%q\ngot\n%q", expected, v)
+	}
+}
+
+func TestStateFieldPrepareValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	f := &core.StateField{}
+	record := core.NewRecord(core.NewBaseCollection("test"))
+
+	scenarios := []struct {
+		raw      any
+		expected string
+	}{
+		{"", ""},
+		{"draft", "draft"},
+		{false, "false"},
+		{123, "123"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.raw), func(t *testing.T) {
+			v, err := f.PrepareValue(record, s.raw)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			vStr, ok := v.(string)
+			if !ok {
+				t.Fatalf("Expected string instance, got %T", v)
+			}
+
+			if vStr != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestStateFieldFindTransition(t *testing.T) {
+	f := &core.StateField{
+		States: []string{"a", "b", "c"},
+		Transitions: []core.StateTransition{
+			{Name: "t1", From: []string{"a"}, To: "b"},
+			{Name: "t2", From: []string{"a", "b"}, To: "c"},
+		},
+	}
+
+	scenarios := []struct {
+		from     string
+		to       string
+		expected string
+	}{
+		{"a", "a", ""},
+		{"a", "b", "t1"},
+		{"b", "a", ""},
+		{"a", "c", "t2"},
+		{"b", "c", "t2"},
+		{"c", "a", ""},
+		{"missing", "b", ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.from+"_"+s.to, func(t *testing.T) {
+			transition := f.FindTransition(s.from, s.to)
+
+			var name string
+			if transition != nil {
+				name = transition.Name
+			}
+
+			if name != s.expected {
+				t.Fatalf("Expected transition %q, got %q", s.expected, name)
+			}
+		})
+	}
+}
+
+func TestStateFieldValidateValue(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collection")
+	collection.Fields.Add(&core.TextField{Name: "test"})
+
+	newField := func(required bool) *core.StateField {
+		return &core.StateField{
+			Name:     "test",
+			Required: required,
+			States:   []string{"draft", "paid", "shipped"},
+			Transitions: []core.StateTransition{
+				{From: []string{"draft"}, To: "paid"},
+				{From: []string{"paid"}, To: "shipped"},
+			},
+		}
+	}
+
+	existingRecord := func(oldState, newState string) *core.Record {
+		record := core.NewRecord(collection)
+		record.Id = "test_id"
+		record.SetRaw("test", oldState)
+		if err := record.PostScan(); err != nil {
+			t.Fatal(err)
+		}
+		record.SetRaw("test", newState)
+		return record
+	}
+
+	scenarios := []struct {
+		name        string
+		field       *core.StateField
+		record      func() *core.Record
+		expectError bool
+	}{
+		{
+			"invalid raw value",
+			newField(false),
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", 123)
+				return record
+			},
+			true,
+		},
+		{
+			"zero field value (not required)",
+			newField(false),
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "")
+				return record
+			},
+			false,
+		},
+		{
+			"zero field value (required)",
+			newField(true),
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "")
+				return record
+			},
+			true,
+		},
+		{
+			"unknown state",
+			newField(false),
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "unknown")
+				return record
+			},
+			true,
+		},
+		{
+			"new record with any known state",
+			newField(true),
+			func() *core.Record {
+				record := core.NewRecord(collection)
+				record.SetRaw("test", "shipped")
+				return record
+			},
+			false,
+		},
+		{
+			"existing record with unchanged state",
+			newField(true),
+			func() *core.Record {
+				return existingRecord("shipped", "shipped")
+			},
+			false,
+		},
+		{
+			"existing record with allowed transition",
+			newField(true),
+			func() *core.Record {
+				return existingRecord("draft", "paid")
+			},
+			false,
+		},
+		{
+			"existing record with not allowed transition",
+			newField(true),
+			func() *core.Record {
+				return existingRecord("draft", "shipped")
+			},
+			true,
+		},
+		{
+			"existing record with not allowed backward transition",
+			newField(true),
+			func() *core.Record {
+				return existingRecord("paid", "draft")
+			},
+			true,
+		},
+		{
+			"existing record without previous state",
+			newField(true),
+			func() *core.Record {
+				return existingRecord("", "shipped")
+			},
+			false,
+		},
+		{
+			"existing record and no transitions",
+			&core.StateField{Name: "test", States: []string{"draft", "paid", "shipped"}},
+			func() *core.Record {
+				return existingRecord("shipped", "draft")
+			},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := s.field.ValidateValue(context.Background(), app, s.record())
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestStateFieldValidateSettings(t *testing.T) {
+	testDefaultFieldIdValidation(t, core.FieldTypeState)
+	testDefaultFieldNameValidation(t, core.FieldTypeState)
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name         string
+		field        func() *core.StateField
+		expectErrors []string
+	}{
+		{
+			"zero minimal",
+			func() *core.StateField {
+				return &core.StateField{
+					Id:   "test",
+					Name: "test",
+				}
+			},
+			[]string{"states"},
+		},
+		{
+			"empty and duplicated states",
+			func() *core.StateField {
+				return &core.StateField{
+					Id:     "test",
+					Name:   "test",
+					States: []string{"a", "", "a"},
+				}
+			},
+			[]string{"states"},
+		},
+		{
+			"unknown initial state",
+			func() *core.StateField {
+				return &core.StateField{
+					Id:      "test",
+					Name:    "test",
+					States:  []string{"a", "b"},
+					Initial: "c",
+				}
+			},
+			[]string{"initial"},
+		},
+		{
+			"transition with missing from and to",
+			func() *core.StateField {
+				return &core.StateField{
+					Id:          "test",
+					Name:        "test",
+					States:      []string{"a", "b"},
+					Transitions: []core.StateTransition{{}},
+				}
+			},
+			[]string{"transitions"},
+		},
+		{
+			"transition with unknown states",
+			func() *core.StateField {
+				return &core.StateField{
+					Id:     "test",
+					Name:   "test",
+					States: []string{"a", "b"},
+					Transitions: []core.StateTransition{
+						{From: []string{"a"}, To: "b"},
+						{From: []string{"c"}, To: "a"},
+					},
+				}
+			},
+			[]string{"transitions"},
+		},
+		{
+			"duplicated transition names",
+			func() *core.StateField {
+				return &core.StateField{
+					Id:     "test",
+					Name:   "test",
+					States: []string{"a", "b"},
+					Transitions: []core.StateTransition{
+						{Name: "x", From: []string{"a"}, To: "b"},
+						{Name: "x", From: []string{"b"}, To: "a"},
+					},
+				}
+			},
+			[]string{"transitions"},
+		},
+		{
+			"invalid transition rule",
+			func() *core.StateField {
+				return &core.StateField{
+					Id:     "test",
+					Name:   "test",
+					States: []string{"a", "b"},
+					Transitions: []core.StateTransition{
+						{From: []string{"a"}, To: "b", Rule: "missing = 1"},
+					},
+				}
+			},
+			[]string{"transitions"},
+		},
+		{
+			"valid settings",
+			func() *core.StateField {
+				return &core.StateField{
+					Id:      "test",
+					Name:    "test",
+					States:  []string{"a", "b", "c"},
+					Initial: "a",
+					Transitions: []core.StateTransition{
+						{Name: "x", From: []string{"a"}, To: "b", Rule: "@request.auth.id != '' && test = 'a'"},
+						{Name: "y", From: []string{"a", "b"}, To: "c"},
+						{From: []string{"c"}, To: "a"},
+					},
+				}
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			field := s.field()
+
+			collection := core.NewBaseCollection("test_collection")
+			collection.Fields.Add(field)
+
+			errs := field.ValidateSettings(context.Background(), app, collection)
+
+			tests.TestValidationErrors(t, errs, s.expectErrors)
+		})
+	}
+}
+
+func TestStateFieldIntercept(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("orders")
+	collection.Fields.Add(&core.StateField{
+		Name:    "status",
+		States:  []string{"draft", "paid", "shipped"},
+		Initial: "draft",
+		Transitions: []core.StateTransition{
+			{Name: "pay", From: []string{"draft"}, To: "paid"},
+			{Name: "ship", From: []string{"paid"}, To: "shipped"},
+		},
+	})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record.GetString("status"); v != "draft" {
+		t.Fatalf("Expected the initial state %q, got %q", "draft", v)
+	}
+
+	var calls []string
+	app.OnRecordTransition().BindFunc(func(e *core.RecordTransitionEvent) error {
+		var name string
+		if e.Transition != nil {
+			name = e.Transition.Name
+		}
+		calls = append(calls, e.From+">"+e.To+":"+name)
+		return e.Next()
+	})
+
+	var taggedCalls int
+	app.OnRecordTransition("orders.ship").BindFunc(func(e *core.RecordTransitionEvent) error {
+		taggedCalls++
+		return errors.New("ship_error")
+	})
+
+	t.Run("unchanged state", func(t *testing.T) {
+		record, err := app.FindRecordById(collection, record.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		if len(calls) != 0 {
+			t.Fatalf("Expected no transition calls, got %v", calls)
+		}
+	})
+
+	t.Run("state change", func(t *testing.T) {
+		record, err := app.FindRecordById(collection, record.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		record.Set("status", "paid")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		expected := []string{"draft>paid:pay"}
+		if !slices.Equal(calls, expected) {
+			t.Fatalf("Expected calls %v, got %v", expected, calls)
+		}
+
+		if taggedCalls != 0 {
+			t.Fatalf("Expected no tagged calls, got %d", taggedCalls)
+		}
+	})
+
+	t.Run("hook error", func(t *testing.T) {
+		record, err := app.FindRecordById(collection, record.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		record.Set("status", "shipped")
+		err = app.Save(record)
+		if err == nil || err.Error() != "ship_error" {
+			t.Fatalf("Expected ship_error, got %v", err)
+		}
+
+		if taggedCalls != 1 {
+			t.Fatalf("Expected 1 tagged call, got %d", taggedCalls)
+		}
+
+		fresh, err := app.FindRecordById(collection, record.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v := fresh.GetString("status"); v != "paid" {
+			t.Fatalf("Expected the state to remain %q, got %q", "paid", v)
+		}
+	})
+}
@@ -11,6 +11,7 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/pocketbase/pocketbase/tools/collation"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/spf13/cast"
 )
@@ -25,6 +26,28 @@ const FieldTypeText = "text"
 
 const autogenerateModifier = ":autogenerate"
 
+// Supported [TextField.Collation] values.
+const (
+	// TextCollationNoCase compares the field values case-insensitively
+	// using the full Unicode case folding (e.g. "ÉCOLE" == "école").
+	TextCollationNoCase = "nocase"
+
+	// TextCollationNoAccent compares the field values case and
+	// diacritic insensitively (e.g. "École" == "ecole").
+	TextCollationNoAccent = "noaccent"
+)
+
+// TextCollations is the list of the custom SQLite collation functions
+// used by the [TextField.Collation] option, keyed by their SQL name.
+//
+// They are registered automatically with the default SQLite driver.
+// When a custom DBConnect driver is used they must be registered
+// manually (e.g. with the mattn/go-sqlite3 ConnectHook conn.RegisterCollation).
+var TextCollations = map[string]func(a, b string) int{
+	"pb_" + TextCollationNoCase:   collation.NoCase,
+	"pb_" + TextCollationNoAccent: collation.NoAccent,
+}
+
 var (
 	_ Field             = (*TextField)(nil)
 	_ SetterFinder      = (*TextField)(nil)
@@ -91,6 +114,26 @@ type TextField struct {
 	//
 	// A single collection can have only 1 field marked as primary key.
 	PrimaryKey bool `form:"primaryKey" json:"primaryKey"`
+
+	// Collation specifies an optional column collation used for the
+	// field values comparisons, affecting the unique indexes, sorting
+	// and the filter equality and range operators (LIKE is not affected).
+	//
+	// The supported values are [TextCollationNoCase] and [TextCollationNoAccent].
+	// Leave it empty to use the default binary (case-sensitive) comparison.
+	//
+	// Note that the collation is part of the column schema definition, meaning that
+	// external tools (e.g. the sqlite3 CLI for indexed queries, .dump or VACUUM INTO)
+	// and drivers without the registered [TextCollations] will fail to operate
+	// with the collection table.
+	Collation string `form:"collation" json:"collation"`
+
+	// Normalize specifies whether to normalize the field values to the
+	// Unicode Normalization Form C (NFC) when set, ensuring that visually
+	// identical strings are stored with the same byte sequence.
+	//
+	// When enabled for an existing field, its stored values are also normalized.
+	Normalize bool `form:"normalize" json:"normalize"`
 }
 
 // Type implements [Field.Type] interface method.
@@ -147,6 +190,10 @@ func (f *TextField) ColumnType(app App) string {
 		return "TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL"
 	}
 
+	if f.Collation != "" {
+		return "TEXT COLLATE pb_" + f.Collation + " DEFAULT '' NOT NULL"
+	}
+
 	return "TEXT DEFAULT '' NOT NULL"
 }
 
@@ -265,6 +312,11 @@ func (f *TextField) ValidateSettings(ctx context.Context, app App, collection *C
 		validation.Field(&f.Hidden, validation.When(f.PrimaryKey, validation.Empty)),
 		validation.Field(&f.Required, validation.When(f.PrimaryKey, validation.Required)),
 		validation.Field(&f.AutogeneratePattern, validation.By(validators.IsRegex), validation.By(f.checkAutogeneratePattern)),
+		validation.Field(
+			&f.Collation,
+			validation.When(f.PrimaryKey, validation.Empty),
+			validation.In(TextCollationNoCase, TextCollationNoAccent),
+		),
 	)
 }
 
@@ -348,7 +400,7 @@ func (f *TextField) FindSetter(key string) SetterFunc {
 	switch key {
 	case f.Name:
 		return func(record *Record, raw any) {
-			record.SetRaw(f.Name, cast.ToString(raw))
+			record.SetRaw(f.Name, f.normalize(cast.ToString(raw)))
 		}
 	case f.Name + autogenerateModifier:
 		return func(record *Record, raw any) {
@@ -359,9 +411,18 @@ func (f *TextField) FindSetter(key string) SetterFunc {
 				v += generated
 			}
 
-			record.SetRaw(f.Name, v)
+			record.SetRaw(f.Name, f.normalize(v))
 		}
 	default:
 		return nil
 	}
 }
+
+// normalize returns the NFC form of v if the field Normalize option is enabled.
+func (f *TextField) normalize(v string) string {
+	if !f.Normalize {
+		return v
+	}
+
+	return collation.NFC(v)
+}
@@ -18,12 +18,34 @@ func TestTextFieldColumnType(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
 
-	f := &core.TextField{}
-
-	expected := "TEXT DEFAULT '' NOT NULL"
+	scenarios := []struct {
+		name     string
+		field    *core.TextField
+		expected string
+	}{
+		{
+			"default",
+			&core.TextField{},
+			"TEXT DEFAULT '' NOT NULL",
+		},
+		{
+			"nocase collation",
+			&core.TextField{Collation: core.TextCollationNoCase},
+			"TEXT COLLATE pb_nocase DEFAULT '' NOT NULL",
+		},
+		{
+			"noaccent collation",
+			&core.TextField{Collation: core.TextCollationNoAccent},
+			"TEXT COLLATE pb_noaccent DEFAULT '' NOT NULL",
+		},
+	}
 
-	if v := f.ColumnType(app); v != expected {
-		t.Fatalf("Expected\n%q\ngot\n%q", expected, v)
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if v := s.field.ColumnType(app); v != s.expected {
+				t.Fatalf("Expected\n%q\ngot\n%q", s.expected, v)
+			}
+		})
 	}
 }
 
@@ -474,6 +496,43 @@ func TestTextFieldValidateSettings(t *testing.T) {
 			},
 			[]string{"min"},
 		},
+		{
+			"invalid collation",
+			func() *core.TextField {
+				return &core.TextField{
+					Id:        "test",
+					Name:      "test",
+					Collation: "invalid",
+				}
+			},
+			[]string{"collation"},
+		},
+		{
+			"valid collation",
+			func() *core.TextField {
+				return &core.TextField{
+					Id:        "test",
+					Name:      "test",
+					Collation: core.TextCollationNoAccent,
+					Normalize: true,
+				}
+			},
+			[]string{},
+		},
+		{
+			"primaryKey with collation",
+			func() *core.TextField {
+				return &core.TextField{
+					Id:         "test",
+					Name:       "id",
+					PrimaryKey: true,
+					Required:   true,
+					Pattern:    `\d+`,
+					Collation:  core.TextCollationNoCase,
+				}
+			},
+			[]string{"collation"},
+		},
 	}
 
 	for _, s := range scenarios {
@@ -622,6 +681,30 @@ func TestTextFieldFindSetter(t *testing.T) {
 			true,
 			"abc",
 		},
+		{
+			"exact match without Normalize option",
+			"test",
+			"Cafe\u0301",
+			&core.TextField{Name: "test"},
+			true,
+			"Cafe\u0301",
+		},
+		{
+			"exact match with Normalize option",
+			"test",
+			"Cafe\u0301",
+			&core.TextField{Name: "test", Normalize: true},
+			true,
+			"Caf\u00e9",
+		},
+		{
+			"autogenerate modifier with Normalize option",
+			"test:autogenerate",
+			"Cafe\u0301",
+			&core.TextField{Name: "test", Normalize: true, AutogeneratePattern: "test"},
+			true,
+			"Caf\u00e9test",
+		},
 	}
 
 	for _, s := range scenarios {
@@ -652,3 +735,154 @@ func TestTextFieldFindSetter(t *testing.T) {
 		})
 	}
 }
+
+func TestTextFieldCollation(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_collation")
+	collection.Fields.Add(
+		&core.TextField{Name: "title", Collation: core.TextCollationNoAccent},
+		&core.TextField{Name: "code"},
+	)
+	collection.AddIndex("idx_test_collation_title", true, "title", "")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, title := range []string{"École", "zebra", "Apple"} {
+		record := core.NewRecord(collection)
+		record.Set("title", title)
+		record.Set("code", title)
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("uniqueness", func(t *testing.T) {
+		record := core.NewRecord(collection)
+		record.Set("title", "ecole")
+		err := app.Save(record)
+		if err == nil || !strings.Contains(err.Error(), "title") {
+			t.Fatalf("Expected title unique constraint error, got %v", err)
+		}
+	})
+
+	t.Run("filtering", func(t *testing.T) {
+		records, err := app.FindRecordsByFilter(collection, "title = 'ECOLE'", "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 1 || records[0].GetString("title") != "École" {
+			t.Fatalf("Expected to find École, got %v", records)
+		}
+
+		// binary comparison for the fields without collation
+		records, err = app.FindRecordsByFilter(collection, "code = 'ECOLE'", "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 0 {
+			t.Fatalf("Expected no code matches, got %v", records)
+		}
+	})
+
+	t.Run("sorting", func(t *testing.T) {
+		records, err := app.FindRecordsByFilter(collection, "", "title", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		titles := make([]string, len(records))
+		for i, r := range records {
+			titles[i] = r.GetString("title")
+		}
+
+		expected := "Apple,École,zebra"
+		if v := strings.Join(titles, ","); v != expected {
+			t.Fatalf("Expected %q, got %q", expected, v)
+		}
+	})
+
+	t.Run("collation change", func(t *testing.T) {
+		collection.Fields.GetByName("title").(*core.TextField).Collation = ""
+		if err := app.Save(collection); err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := app.FindRecordsByFilter(collection, "title = 'ECOLE'", "", 0, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(records) != 0 {
+			t.Fatalf("Expected no matches after the collation removal, got %v", records)
+		}
+
+		// the unique index should be preserved
+		record := core.NewRecord(collection)
+		record.Set("title", "zebra")
+		if err := app.Save(record); err == nil {
+			t.Fatal("Expected title unique constraint error")
+		}
+
+		// allowed with the default binary collation
+		record = core.NewRecord(collection)
+		record.Set("title", "ZEBRA")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		collection.Fields.GetByName("title").(*core.TextField).Collation = core.TextCollationNoCase
+		// should fail because of the "zebra" and "ZEBRA" unique index conflict
+		if err := app.Save(collection); err == nil {
+			t.Fatal("Expected the collation change to fail because of the duplicated values")
+		}
+	})
+}
+
+func TestTextFieldNormalizeBackfill(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("test_normalize")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "code"},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	decomposed := "Cafe\u0301" // NFD "Café"
+	composed := "Caf\u00e9"    // NFC "Café"
+
+	record := core.NewRecord(collection)
+	record.Set("title", decomposed)
+	record.Set("code", decomposed)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Fields.GetByName("title").(*core.TextField).Normalize = true
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := record.GetString("title"); v != composed {
+		t.Fatalf("Expected the title to be normalized to %q, got %q", composed, v)
+	}
+
+	if v := record.GetString("code"); v != decomposed {
+		t.Fatalf("Expected the code to remain %q, got %q", decomposed, v)
+	}
+
+	found, err := app.FindFirstRecordByData(collection, "title", composed)
+	if err != nil || found.Id != record.Id {
+		t.Fatalf("Expected to find the record by its normalized title, got %v (%v)", found, err)
+	}
+}
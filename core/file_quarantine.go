@@ -0,0 +1,183 @@
+package core
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const QuarantinedFilesTableName = "_quarantinedFiles"
+
+var _ Model = (*QuarantinedFile)(nil)
+
+// QuarantinedFile defines a stored record file that is not servable
+// until approved (e.g. pending an asynchronous virus or moderation check).
+type QuarantinedFile struct {
+	BaseModel
+
+	CollectionRef string         `db:"collectionRef" json:"collectionRef"`
+	RecordRef     string         `db:"recordRef" json:"recordRef"`
+	FieldName     string         `db:"fieldName" json:"fieldName"`
+	Filename      string         `db:"filename" json:"filename"`
+	Reason        string         `db:"reason" json:"reason"`
+	Created       types.DateTime `db:"created" json:"created"`
+	Updated       types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the QuarantinedFile model table name.
+func (m *QuarantinedFile) TableName() string {
+	return QuarantinedFilesTableName
+}
+
+// -------------------------------------------------------------------
+
+// QuarantinedFileQuery returns a new QuarantinedFile select query.
+func (app *BaseApp) QuarantinedFileQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&QuarantinedFile{})
+}
+
+// FindQuarantinedFileById finds a single QuarantinedFile entry by its id.
+func (app *BaseApp) FindQuarantinedFileById(id string) (*QuarantinedFile, error) {
+	m := &QuarantinedFile{}
+
+	err := app.QuarantinedFileQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// IsFileQuarantined checks whether the specified record file is quarantined.
+//
+// For security reasons it also returns true if the check fails.
+func (app *BaseApp) IsFileQuarantined(record *Record, filename string) bool {
+	var exists int
+
+	err := app.QuarantinedFileQuery().
+		Select("(1)").
+		AndWhere(dbx.HashExp{
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+			"filename":      filename,
+		}).
+		Limit(1).
+		Row(&exists)
+
+	if errors.Is(err, sql.ErrNoRows) {
+		return false
+	}
+
+	return err != nil || exists > 0
+}
+
+// ApproveQuarantinedFile releases the quarantined file making it servable.
+func (app *BaseApp) ApproveQuarantinedFile(file *QuarantinedFile) error {
+	return app.Delete(file)
+}
+
+// RejectQuarantinedFile removes the quarantined file from its record
+// (which also deletes it from the storage) and releases the quarantine entry.
+func (app *BaseApp) RejectQuarantinedFile(file *QuarantinedFile) error {
+	return app.RunInTransaction(func(txApp App) error {
+		record, err := txApp.FindRecordById(file.CollectionRef, file.RecordRef)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		if record != nil {
+			record.Set(file.FieldName+"-", file.Filename)
+			if err := txApp.Save(record); err != nil {
+				return err
+			}
+		}
+
+		// the entry could be already deleted with the record file
+		_, err = txApp.NonconcurrentDB().Delete(QuarantinedFilesTableName, dbx.HashExp{"id": file.Id}).Execute()
+
+		return err
+	})
+}
+
+// quarantineFiles creates a new quarantine entry for each of the provided record field files.
+func quarantineFiles(app App, record *Record, fieldName string, files map[string]string) error {
+	for filename, reason := range files {
+		m := &QuarantinedFile{
+			CollectionRef: record.Collection().Id,
+			RecordRef:     record.Id,
+			FieldName:     fieldName,
+			Filename:      filename,
+			Reason:        reason,
+		}
+		if err := app.Save(m); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// deleteQuarantinedFiles deletes the quarantine entries of the provided record files (if any).
+func deleteQuarantinedFiles(app App, record *Record, filenames []string) error {
+	if len(filenames) == 0 {
+		return nil
+	}
+
+	_, err := app.NonconcurrentDB().Delete(QuarantinedFilesTableName, dbx.And(
+		dbx.HashExp{
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+		},
+		dbx.In("filename", list.ToInterfaceSlice(filenames)...),
+	)).Execute()
+
+	return err
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerFileQuarantineHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*QuarantinedFile); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+	})
+
+	// release the quarantine entries of the deleted records and collections
+	app.OnModelAfterDeleteSuccess().BindFunc(func(e *ModelEvent) error {
+		var exp dbx.Expression
+
+		switch m := e.Model.(type) {
+		case *Collection:
+			exp = dbx.HashExp{"collectionRef": m.Id}
+		case *Record:
+			if !isRecycledModel(m) {
+				exp = dbx.HashExp{"collectionRef": m.Collection().Id, "recordRef": m.Id}
+			}
+		}
+
+		if exp != nil {
+			_, err := e.App.NonconcurrentDB().Delete(QuarantinedFilesTableName, exp).Execute()
+			if err != nil {
+				e.App.Logger().Warn("Failed to delete the quarantined files entries", "error", err)
+			}
+		}
+
+		return e.Next()
+	})
+}
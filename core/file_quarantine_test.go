@@ -0,0 +1,171 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+func TestFileUploadQuarantine(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.OnFileUpload("demo3").BindFunc(func(e *core.FileUploadEvent) error {
+		if e.File.OriginalName == "reject.txt" {
+			return errors.New("rejected")
+		}
+
+		if e.File.OriginalName == "quarantine.txt" {
+			e.Quarantine = true
+			e.QuarantineReason = "pending scan"
+		}
+
+		return e.Next()
+	})
+
+	collection, err := app.FindCollectionByNameOrId("demo3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newFile := func(t *testing.T, name string) *filesystem.File {
+		f, err := filesystem.NewFileFromBytes([]byte("test"), name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	// rejected upload
+	// ---
+	rejected := core.NewRecord(collection)
+	rejected.Set("title", "rejected")
+	rejected.Set("files", []any{newFile(t, "reject.txt")})
+	if err := app.Save(rejected); err == nil {
+		t.Fatal("Expected the rejected upload to fail the record save")
+	}
+
+	// quarantined upload
+	// ---
+	record := core.NewRecord(collection)
+	record.Set("title", "quarantined")
+	record.Set("files", []any{newFile(t, "quarantine.txt"), newFile(t, "regular.txt")})
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	files := record.GetStringSlice("files")
+	if len(files) != 2 {
+		t.Fatalf("Expected 2 record files, got %v", files)
+	}
+
+	if !app.IsFileQuarantined(record, files[0]) {
+		t.Fatalf("Expected file %q to be quarantined", files[0])
+	}
+
+	if app.IsFileQuarantined(record, files[1]) {
+		t.Fatalf("Expected file %q to not be quarantined", files[1])
+	}
+
+	entry := &core.QuarantinedFile{}
+	err = app.QuarantinedFileQuery().AndWhere(dbx.HashExp{"filename": files[0]}).One(entry)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entry.RecordRef != record.Id || entry.FieldName != "files" || entry.Reason != "pending scan" {
+		t.Fatalf("Unexpected quarantine entry %#v", entry)
+	}
+
+	// approve
+	// ---
+	if err := app.ApproveQuarantinedFile(entry); err != nil {
+		t.Fatal(err)
+	}
+	if app.IsFileQuarantined(record, files[0]) {
+		t.Fatalf("Expected file %q to be approved", files[0])
+	}
+
+	// reject
+	// ---
+	record.Set("files+", newFile(t, "quarantine.txt"))
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+	files = record.GetStringSlice("files")
+
+	entry, err = func() (*core.QuarantinedFile, error) {
+		m := &core.QuarantinedFile{}
+		return m, app.QuarantinedFileQuery().AndWhere(dbx.HashExp{"filename": files[2]}).One(m)
+	}()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.RejectQuarantinedFile(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err = app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if remaining := record.GetStringSlice("files"); len(remaining) != 2 {
+		t.Fatalf("Expected the rejected file to be removed from the record, got %v", remaining)
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	if exists, _ := fsys.Exists(record.BaseFilesPath() + "/" + files[2]); exists {
+		t.Fatalf("Expected the rejected file %q to be deleted", files[2])
+	}
+
+	if _, err := app.FindQuarantinedFileById(entry.Id); err == nil {
+		t.Fatal("Expected the quarantine entry to be deleted")
+	}
+}
+
+func TestQuarantinedFilesDeleteWithRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById("demo3", "mk5fmymtx4wsprk")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filename := record.GetStringSlice("files")[0]
+
+	entry := &core.QuarantinedFile{
+		CollectionRef: record.Collection().Id,
+		RecordRef:     record.Id,
+		FieldName:     "files",
+		Filename:      filename,
+	}
+	if err := app.Save(entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if !app.IsFileQuarantined(record, filename) {
+		t.Fatalf("Expected file %q to be quarantined", filename)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindQuarantinedFileById(entry.Id); err == nil {
+		t.Fatal("Expected the quarantine entry to be deleted with the record")
+	}
+}
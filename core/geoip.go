@@ -0,0 +1,105 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/geoip"
+)
+
+// GeoIPDirName is the name of the app data subdirectory
+// where the GeoIP CSV databases are stored (see [geoip.DB.LoadCSV]).
+const GeoIPDirName = "geoip"
+
+// StoreKeyGeoIP is the app store key of the loaded GeoIP database.
+const StoreKeyGeoIP = "pbAppGeoIP"
+
+// ResolveGeoIP returns the country and ASN of the provided IP address
+// based on the CSV databases in the pb_data/geoip directory.
+//
+// The databases are loaded on the first call.
+// To refresh them after a files change you can call [App.ReloadGeoIP].
+//
+// Returns nil if the GeoIP resolving is disabled or no data was found for the IP.
+func (app *BaseApp) ResolveGeoIP(ip string) *geoip.Info {
+	if !app.Settings().GeoIP.Enabled || ip == "" {
+		return nil
+	}
+
+	db, _ := app.Store().GetOrSet(StoreKeyGeoIP, func() any {
+		db, err := loadGeoIPDB(filepath.Join(app.DataDir(), GeoIPDirName))
+		if err != nil {
+			app.Logger().Warn("Failed to load the GeoIP databases", "error", err)
+		}
+		return db
+	}).(*geoip.DB)
+
+	if db == nil {
+		return nil
+	}
+
+	info, ok := db.Lookup(ip)
+	if !ok {
+		return nil
+	}
+
+	return &info
+}
+
+// ReloadGeoIP (re)loads the GeoIP CSV databases from the pb_data/geoip directory.
+func (app *BaseApp) ReloadGeoIP() error {
+	db, err := loadGeoIPDB(filepath.Join(app.DataDir(), GeoIPDirName))
+
+	// always replace to avoid the lazy load of the old files
+	app.Store().Set(StoreKeyGeoIP, db)
+
+	return err
+}
+
+// loadGeoIPDB loads all *.csv files from the provided directory
+// into a new GeoIP database.
+//
+// A missing directory is not considered an error.
+func loadGeoIPDB(dir string) (*geoip.DB, error) {
+	db := geoip.New()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return db, nil
+		}
+		return db, err
+	}
+
+	var errs []error
+
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.EqualFold(filepath.Ext(entry.Name()), ".csv") {
+			continue
+		}
+
+		if err := loadGeoIPFile(db, filepath.Join(dir, entry.Name())); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return db, errors.Join(errs...)
+}
+
+func loadGeoIPFile(db *geoip.DB, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := db.LoadCSV(f); err != nil {
+		return fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+
+	return nil
+}
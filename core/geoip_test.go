@@ -0,0 +1,104 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/geoip"
+)
+
+func TestResolveGeoIP(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	dir := filepath.Join(app.DataDir(), core.GeoIPDirName)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{
+		"country.csv": "1.0.0.0,1.0.0.255,AU\n",
+		"asn.CSV":     "1.0.0.0,1.0.0.255,13335,Cloudflare\n",
+		"invalid.csv": "invalid",
+		"ignored.txt": "1.0.1.0,1.0.1.255,CN\n",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// disabled
+	if info := app.ResolveGeoIP("1.0.0.1"); info != nil {
+		t.Fatalf("Expected nil info while disabled, got %v", info)
+	}
+
+	app.Settings().GeoIP.Enabled = true
+
+	scenarios := []struct {
+		ip       string
+		expected *geoip.Info
+	}{
+		{"", nil},
+		{"invalid", nil},
+		{"1.0.1.1", nil},
+		{"1.0.0.1", &geoip.Info{Country: "AU", ASN: 13335, ASNOrg: "Cloudflare"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.ip, func(t *testing.T) {
+			info := app.ResolveGeoIP(s.ip)
+
+			if s.expected == nil {
+				if info != nil {
+					t.Fatalf("Expected nil info, got %v", info)
+				}
+				return
+			}
+
+			if info == nil || *info != *s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, info)
+			}
+		})
+	}
+
+	// reload
+	// ---
+	if err := os.WriteFile(filepath.Join(dir, "country.csv"), []byte("1.0.1.0,1.0.1.255,CN\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if info := app.ResolveGeoIP("1.0.1.1"); info != nil {
+		t.Fatalf("Expected the cached databases to be used before reload, got %v", info)
+	}
+
+	if err := app.ReloadGeoIP(); err == nil {
+		t.Fatal("Expected the invalid.csv load error")
+	}
+
+	if info := app.ResolveGeoIP("1.0.1.1"); info == nil || info.Country != "CN" {
+		t.Fatalf("Expected CN country after reload, got %v", info)
+	}
+}
+
+func TestResolveGeoIPMissingDir(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().GeoIP.Enabled = true
+
+	if err := app.ReloadGeoIP(); err != nil {
+		t.Fatalf("Expected no error for missing dir, got %v", err)
+	}
+
+	if info := app.ResolveGeoIP("1.0.0.1"); info != nil {
+		t.Fatalf("Expected nil info, got %v", info)
+	}
+}
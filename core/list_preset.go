@@ -0,0 +1,275 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const ListPresetsTableName = "_listPresets"
+
+var (
+	_ Model         = (*ListPreset)(nil)
+	_ PostValidator = (*ListPreset)(nil)
+)
+
+// ListPreset defines a named records list preset (filter, sort, fields and expand)
+// that could be applied to a collection list request with "?preset=name".
+//
+// Presets without owner are shared with everyone that has access to
+// the collection records list, otherwise they are visible only to their owner.
+type ListPreset struct {
+	BaseModel
+
+	CollectionRef      string         `db:"collectionRef" json:"collectionRef"`
+	OwnerCollectionRef string         `db:"ownerCollectionRef" json:"ownerCollectionRef"`
+	OwnerRef           string         `db:"ownerRef" json:"ownerRef"`
+	Name               string         `db:"name" json:"name"`
+	Filter             string         `db:"filter" json:"filter"`
+	Sort               string         `db:"sort" json:"sort"`
+	Fields             string         `db:"fields" json:"fields"`
+	Expand             string         `db:"expand" json:"expand"`
+	Created            types.DateTime `db:"created" json:"created"`
+	Updated            types.DateTime `db:"updated" json:"updated"`
+}
+
+// NewListPreset initializes and returns a new blank ListPreset
+// model associated with the provided collection.
+func NewListPreset(collection *Collection) *ListPreset {
+	return &ListPreset{CollectionRef: collection.Id}
+}
+
+// TableName returns the ListPreset model table name.
+func (m *ListPreset) TableName() string {
+	return ListPresetsTableName
+}
+
+// IsShared reports whether the preset has no owner.
+func (m *ListPreset) IsShared() bool {
+	return m.OwnerRef == ""
+}
+
+// IsOwnedBy reports whether the preset belongs to the provided auth record.
+func (m *ListPreset) IsOwnedBy(authRecord *Record) bool {
+	return authRecord != nil &&
+		m.OwnerRef == authRecord.Id &&
+		m.OwnerCollectionRef == authRecord.Collection().Id
+}
+
+// SetOwner updates the preset owner.
+//
+// Set authRecord to nil to make the preset shared.
+func (m *ListPreset) SetOwner(authRecord *Record) {
+	if authRecord == nil {
+		m.OwnerCollectionRef = ""
+		m.OwnerRef = ""
+		return
+	}
+
+	m.OwnerCollectionRef = authRecord.Collection().Id
+	m.OwnerRef = authRecord.Id
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *ListPreset) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(&m.CollectionRef, validation.Required, validation.By(validateCollectionId(app))),
+		validation.Field(&m.OwnerCollectionRef, validation.When(
+			m.OwnerRef != "",
+			validation.Required,
+			validation.By(validateCollectionId(app, CollectionTypeAuth)),
+		).Else(validation.Empty)),
+		validation.Field(&m.OwnerRef, validation.When(
+			m.OwnerCollectionRef != "",
+			validation.Required,
+			validation.By(validateRecordId(app, m.OwnerCollectionRef)),
+		)),
+		validation.Field(
+			&m.Name,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(listPresetNameRegex),
+			validation.By(m.checkUniqueName(app)),
+		),
+		validation.Field(&m.Filter, validation.Length(0, 3500)),
+		validation.Field(&m.Sort, validation.Length(0, 1000)),
+		validation.Field(&m.Fields, validation.Length(0, 1000)),
+		validation.Field(&m.Expand, validation.Length(0, 1000)),
+	)
+}
+
+func (m *ListPreset) checkUniqueName(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		var exists int
+
+		err := app.ListPresetQuery().
+			Select("(1)").
+			AndWhere(dbx.HashExp{
+				"collectionRef":      m.CollectionRef,
+				"ownerCollectionRef": m.OwnerCollectionRef,
+				"ownerRef":           m.OwnerRef,
+				"name":               v,
+			}).
+			AndWhere(dbx.Not(dbx.HashExp{"id": m.Id})).
+			Limit(1).
+			Row(&exists)
+
+		if exists > 0 || (err != nil && !errors.Is(err, sql.ErrNoRows)) {
+			return validation.NewError("validation_list_preset_name_exists", "Preset with the provided name already exists.")
+		}
+
+		return nil
+	}
+}
+
+var listPresetNameRegex = regexp.MustCompile(`^[\w\-]+$`)
+
+// -------------------------------------------------------------------
+
+// ListPresetQuery returns a new ListPreset select query.
+func (app *BaseApp) ListPresetQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&ListPreset{})
+}
+
+// FindListPresetById returns a single ListPreset model by its id.
+func (app *BaseApp) FindListPresetById(id string) (*ListPreset, error) {
+	result := &ListPreset{}
+
+	err := app.ListPresetQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindAllListPresets returns all shared collection ListPreset models
+// and the ones owned by the optional authRecord (ordered by name).
+func (app *BaseApp) FindAllListPresets(collection *Collection, authRecord *Record) ([]*ListPreset, error) {
+	result := []*ListPreset{}
+
+	err := app.ListPresetQuery().
+		AndWhere(dbx.HashExp{"collectionRef": collection.Id}).
+		AndWhere(listPresetVisibilityExpr(authRecord)).
+		OrderBy("name ASC", "ownerRef DESC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindListPresetByName returns a single collection ListPreset model by its name.
+//
+// The presets owned by the optional authRecord take precedence over
+// the shared ones with the same name.
+func (app *BaseApp) FindListPresetByName(collection *Collection, authRecord *Record, name string) (*ListPreset, error) {
+	result := &ListPreset{}
+
+	err := app.ListPresetQuery().
+		AndWhere(dbx.HashExp{"collectionRef": collection.Id, "name": name}).
+		AndWhere(listPresetVisibilityExpr(authRecord)).
+		OrderBy("ownerRef DESC"). // owned first
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func listPresetVisibilityExpr(authRecord *Record) dbx.Expression {
+	shared := dbx.HashExp{"ownerRef": ""}
+
+	if authRecord == nil {
+		return shared
+	}
+
+	return dbx.Or(shared, dbx.HashExp{
+		"ownerCollectionRef": authRecord.Collection().Id,
+		"ownerRef":           authRecord.Id,
+	})
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerListPresetHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*ListPreset); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*ListPreset); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// delete the collection and owner presets
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(ListPresetsTableName, dbx.Or(
+				dbx.HashExp{"collectionRef": e.Collection.Id},
+				dbx.HashExp{"ownerCollectionRef": e.Collection.Id},
+			)).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil || !e.Record.Collection().IsAuth() {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(ListPresetsTableName, dbx.HashExp{
+				"ownerCollectionRef": e.Record.Collection().Id,
+				"ownerRef":           e.Record.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+}
@@ -0,0 +1,345 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestListPresetIsShared(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	preset := &core.ListPreset{}
+
+	if !preset.IsShared() {
+		t.Fatal("Expected the preset to be shared")
+	}
+
+	if preset.IsOwnedBy(user) {
+		t.Fatal("Expected the preset to not be owned by the user")
+	}
+
+	preset.SetOwner(user)
+
+	if preset.IsShared() {
+		t.Fatal("Expected the preset to not be shared")
+	}
+
+	if !preset.IsOwnedBy(user) {
+		t.Fatal("Expected the preset to be owned by the user")
+	}
+
+	if preset.IsOwnedBy(nil) {
+		t.Fatal("Expected the preset to not be owned by nil")
+	}
+
+	preset.SetOwner(nil)
+
+	if !preset.IsShared() || preset.OwnerCollectionRef != "" {
+		t.Fatalf("Expected the owner to be cleared, got %q %q", preset.OwnerCollectionRef, preset.OwnerRef)
+	}
+}
+
+func TestListPresetValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo2, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	existing := core.NewListPreset(demo2)
+	existing.Name = "existing"
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		preset         func() *core.ListPreset
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			func() *core.ListPreset {
+				return &core.ListPreset{}
+			},
+			[]string{"collectionRef", "name"},
+		},
+		{
+			"invalid collectionRef and name",
+			func() *core.ListPreset {
+				return &core.ListPreset{CollectionRef: "missing", Name: "a b"}
+			},
+			[]string{"collectionRef", "name"},
+		},
+		{
+			"non-auth owner collection",
+			func() *core.ListPreset {
+				p := core.NewListPreset(demo2)
+				p.Name = "test"
+				p.OwnerCollectionRef = demo2.Id
+				p.OwnerRef = "llvuca81nly1qls"
+				return p
+			},
+			[]string{"ownerCollectionRef"},
+		},
+		{
+			"missing owner record",
+			func() *core.ListPreset {
+				p := core.NewListPreset(demo2)
+				p.Name = "test"
+				p.OwnerCollectionRef = user.Collection().Id
+				p.OwnerRef = "missing"
+				return p
+			},
+			[]string{"ownerRef"},
+		},
+		{
+			"duplicated shared name",
+			func() *core.ListPreset {
+				p := core.NewListPreset(demo2)
+				p.Name = "existing"
+				return p
+			},
+			[]string{"name"},
+		},
+		{
+			"same name with different owner",
+			func() *core.ListPreset {
+				p := core.NewListPreset(demo2)
+				p.Name = "existing"
+				p.SetOwner(user)
+				return p
+			},
+			[]string{},
+		},
+		{
+			"valid shared",
+			func() *core.ListPreset {
+				p := core.NewListPreset(demo2)
+				p.Name = "active-items_1"
+				p.Filter = "active = true"
+				p.Sort = "-created"
+				p.Fields = "id,title"
+				return p
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			preset := s.preset()
+
+			err := app.Save(preset)
+
+			tests.TestValidationErrors(t, err, s.expectedErrors)
+
+			if err == nil {
+				if preset.Id == "" || preset.Created.IsZero() || preset.Updated.IsZero() {
+					t.Fatalf("Expected id and dates to be set, got %#v", preset)
+				}
+
+				if _, err := app.FindListPresetById(preset.Id); err != nil {
+					t.Fatalf("Failed to find the saved preset: %v", err)
+				}
+			}
+		})
+	}
+}
+
+func TestFindListPresets(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo2, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	presets := []struct {
+		name   string
+		filter string
+		owner  *core.Record
+	}{
+		{"b", "shared_b", nil},
+		{"a", "shared_a", nil},
+		{"a", "user1_a", user1},
+		{"c", "user2_c", user2},
+	}
+	for _, p := range presets {
+		preset := core.NewListPreset(demo2)
+		preset.Name = p.name
+		preset.Filter = p.filter
+		preset.SetOwner(p.owner)
+		if err := app.Save(preset); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("FindAllListPresets", func(t *testing.T) {
+		scenarios := []struct {
+			name     string
+			auth     *core.Record
+			expected []string
+		}{
+			{"guest", nil, []string{"shared_a", "shared_b"}},
+			{"user1", user1, []string{"user1_a", "shared_a", "shared_b"}},
+			{"user2", user2, []string{"shared_a", "shared_b", "user2_c"}},
+		}
+
+		for _, s := range scenarios {
+			t.Run(s.name, func(t *testing.T) {
+				result, err := app.FindAllListPresets(demo2, s.auth)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if len(result) != len(s.expected) {
+					t.Fatalf("Expected %d presets, got %d", len(s.expected), len(result))
+				}
+
+				for i, p := range result {
+					if p.Filter != s.expected[i] {
+						t.Fatalf("Expected preset %d to be %q, got %q", i, s.expected[i], p.Filter)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("FindListPresetByName", func(t *testing.T) {
+		scenarios := []struct {
+			name     string
+			auth     *core.Record
+			expected string
+		}{
+			{"a", nil, "shared_a"},
+			{"a", user1, "user1_a"},
+			{"a", user2, "shared_a"},
+			{"c", nil, ""},
+			{"c", user1, ""},
+			{"c", user2, "user2_c"},
+			{"missing", user2, ""},
+		}
+
+		for _, s := range scenarios {
+			authId := "guest"
+			if s.auth != nil {
+				authId = s.auth.Id
+			}
+
+			t.Run(s.name+"_"+authId, func(t *testing.T) {
+				preset, err := app.FindListPresetByName(demo2, s.auth, s.name)
+
+				hasErr := err != nil
+				expectErr := s.expected == ""
+				if hasErr != expectErr {
+					t.Fatalf("Expected hasErr %v, got %v (%v)", expectErr, hasErr, err)
+				}
+
+				if !hasErr && preset.Filter != s.expected {
+					t.Fatalf("Expected preset %q, got %q", s.expected, preset.Filter)
+				}
+			})
+		}
+	})
+}
+
+func TestListPresetsDeleteCleanup(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo2, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo3 := core.NewBaseCollection("new_demo3")
+	if err := app.Save(demo3); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	create := func(collection *core.Collection, owner *core.Record) *core.ListPreset {
+		preset := core.NewListPreset(collection)
+		preset.Name = "test"
+		preset.SetOwner(owner)
+		if err := app.Save(preset); err != nil {
+			t.Fatal(err)
+		}
+		return preset
+	}
+
+	sharedDemo2 := create(demo2, nil)
+	userDemo2 := create(demo2, user)
+	sharedDemo3 := create(demo3, nil)
+	userDemo3 := create(demo3, user)
+
+	exists := func(preset *core.ListPreset) bool {
+		_, err := app.FindListPresetById(preset.Id)
+		return err == nil
+	}
+
+	// auth record delete
+	if err := app.Delete(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if !exists(sharedDemo2) || !exists(sharedDemo3) {
+		t.Fatal("Expected the shared presets to remain")
+	}
+
+	if exists(userDemo2) || exists(userDemo3) {
+		t.Fatal("Expected the user presets to be deleted")
+	}
+
+	// collection delete
+	if err := app.Delete(demo3); err != nil {
+		t.Fatal(err)
+	}
+
+	if !exists(sharedDemo2) {
+		t.Fatal("Expected the demo2 preset to remain")
+	}
+
+	if exists(sharedDemo3) {
+		t.Fatal("Expected the demo3 preset to be deleted")
+	}
+}
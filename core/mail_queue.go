@@ -0,0 +1,242 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const MailQueueTableName = "_mailQueue"
+
+const (
+	QueuedMailStatusPending = "pending"
+	QueuedMailStatusSent    = "sent"
+	QueuedMailStatusFailed  = "failed"
+)
+
+var _ Model = (*QueuedMail)(nil)
+
+// QueuedMail defines a single email message that is waiting
+// (or was already processed) in the mail queue.
+//
+// The queued emails are sent in the background by the mail queue cron job
+// with respect to the [MailQueueConfig] throttling and retry settings.
+type QueuedMail struct {
+	BaseModel
+
+	// Message is the serialized [mailer.Message].
+	Message  types.JSONRaw  `db:"message" json:"message"`
+	Status   string         `db:"status" json:"status"`
+	Attempts int            `db:"attempts" json:"attempts"`
+	Error    string         `db:"error" json:"error"`
+	Created  types.DateTime `db:"created" json:"created"`
+	Updated  types.DateTime `db:"updated" json:"updated"`
+}
+
+// NewQueuedMail creates a new pending QueuedMail model from the provided message.
+//
+// Note that the message attachments are not supported.
+func NewQueuedMail(message *mailer.Message) (*QueuedMail, error) {
+	if len(message.Attachments) > 0 || len(message.InlineAttachments) > 0 {
+		return nil, errors.New("the queued emails don't support attachments")
+	}
+
+	raw, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+
+	return &QueuedMail{
+		Message: raw,
+		Status:  QueuedMailStatusPending,
+	}, nil
+}
+
+// TableName returns the QueuedMail model table name.
+func (m *QueuedMail) TableName() string {
+	return MailQueueTableName
+}
+
+// ParseMessage unmarshalizes the queued [mailer.Message].
+func (m *QueuedMail) ParseMessage() (*mailer.Message, error) {
+	message := &mailer.Message{}
+
+	if err := json.Unmarshal(m.Message, message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// -------------------------------------------------------------------
+
+// MailQueueQuery returns a new QueuedMail select query.
+func (app *BaseApp) MailQueueQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&QueuedMail{})
+}
+
+// FindQueuedMailById returns a single QueuedMail model by its id.
+func (app *BaseApp) FindQueuedMailById(id string) (*QueuedMail, error) {
+	result := &QueuedMail{}
+
+	err := app.MailQueueQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// QueueMail adds the provided message in the mail queue
+// to be sent later by the mail queue cron job.
+func (app *BaseApp) QueueMail(message *mailer.Message) (*QueuedMail, error) {
+	item, err := NewQueuedMail(message)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := app.Save(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+// ProcessMailQueue sends up to limit pending queued emails (oldest first)
+// and returns the number of the processed ones.
+//
+// Failed sends are retried on the next run until
+// the [MailQueueConfig.MaxAttempts] is reached.
+//
+// If limit is <= 0, all pending emails are processed.
+func (app *BaseApp) ProcessMailQueue(limit int) (int, error) {
+	query := app.MailQueueQuery().
+		AndWhere(dbx.HashExp{"status": QueuedMailStatusPending}).
+		OrderBy("created ASC", "rowid ASC")
+
+	if limit > 0 {
+		query.Limit(int64(limit))
+	}
+
+	items := []*QueuedMail{}
+	if err := query.All(&items); err != nil {
+		return 0, err
+	}
+
+	maxAttempts := max(1, app.Settings().MailQueue.MaxAttempts)
+
+	for _, item := range items {
+		item.Attempts++
+
+		sendErr := app.sendQueuedMail(item)
+		if sendErr == nil {
+			item.Status = QueuedMailStatusSent
+			item.Error = ""
+		} else {
+			item.Error = sendErr.Error()
+			if item.Attempts >= maxAttempts {
+				item.Status = QueuedMailStatusFailed
+			}
+		}
+
+		if err := app.Save(item); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(items), nil
+}
+
+// DeleteOldQueuedMails deletes all sent and failed queued emails that are created before createdBefore.
+//
+// The pending emails are never deleted.
+func (app *BaseApp) DeleteOldQueuedMails(createdBefore time.Time) error {
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+
+	_, err := app.NonconcurrentDB().Delete(MailQueueTableName, dbx.And(
+		dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": formattedDate}),
+		dbx.In("status", QueuedMailStatusSent, QueuedMailStatusFailed),
+	)).Execute()
+
+	return err
+}
+
+func (app *BaseApp) sendQueuedMail(item *QueuedMail) error {
+	message, err := item.ParseMessage()
+	if err != nil {
+		return err
+	}
+
+	return app.NewMailClient().Send(message)
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerMailQueueHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*QueuedMail); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				if m.Status == "" {
+					m.Status = QueuedMailStatusPending
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*QueuedMail); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// prevents overlapping queue runs in case of slow mail server
+	var processing atomic.Bool
+
+	app.Cron().Add("__pbMailQueue__", "* * * * *", func() {
+		if !processing.CompareAndSwap(false, true) {
+			return
+		}
+		defer processing.Store(false)
+
+		_, err := app.ProcessMailQueue(app.Settings().MailQueue.MaxPerMinute)
+		if err != nil {
+			app.Logger().Warn("Failed to process the mail queue", "error", err)
+		}
+	})
+
+	// cleanup old processed emails
+	app.Cron().Add("__pbMailQueueCleanup__", "0 */6 * * *", func() {
+		maxDays := app.Settings().MailQueue.MaxDays
+		if maxDays <= 0 {
+			return // no limit
+		}
+
+		deleteErr := app.DeleteOldQueuedMails(time.Now().AddDate(0, 0, -1*maxDays))
+		if deleteErr != nil {
+			app.Logger().Warn("Failed to delete old queued emails", "error", deleteErr)
+		}
+	})
+}
@@ -0,0 +1,186 @@
+package core_test
+
+import (
+	"errors"
+	"io"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+func TestNewQueuedMail(t *testing.T) {
+	t.Parallel()
+
+	_, err := core.NewQueuedMail(&mailer.Message{
+		Attachments: map[string]io.Reader{"test.txt": strings.NewReader("test")},
+	})
+	if err == nil {
+		t.Fatal("Expected attachments error")
+	}
+
+	item, err := core.NewQueuedMail(&mailer.Message{
+		From:    mail.Address{Name: "Sender", Address: "sender@example.com"},
+		To:      []mail.Address{{Address: "to@example.com"}},
+		Subject: "test_subject",
+		HTML:    "test_html",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item.Status != core.QueuedMailStatusPending {
+		t.Fatalf("Expected status %q, got %q", core.QueuedMailStatusPending, item.Status)
+	}
+
+	message, err := item.ParseMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if message.From.Address != "sender@example.com" ||
+		len(message.To) != 1 ||
+		message.To[0].Address != "to@example.com" ||
+		message.Subject != "test_subject" ||
+		message.HTML != "test_html" {
+		t.Fatalf("Unexpected parsed message %#v", message)
+	}
+}
+
+func TestProcessMailQueue(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().MailQueue.MaxAttempts = 2
+
+	items := make([]*core.QueuedMail, 3)
+	for i := range items {
+		item, err := app.QueueMail(&mailer.Message{
+			To:      []mail.Address{{Address: "test" + string(rune('0'+i)) + "@example.com"}},
+			Subject: "test",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		items[i] = item
+	}
+
+	// with limit
+	// ---
+	processed, err := app.ProcessMailQueue(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processed != 1 {
+		t.Fatalf("Expected 1 processed email, got %d", processed)
+	}
+	if total := app.TestMailer.TotalSend(); total != 1 {
+		t.Fatalf("Expected 1 sent email, got %d", total)
+	}
+	if to := app.TestMailer.LastMessage().To[0].Address; to != "test0@example.com" {
+		t.Fatalf("Expected the oldest email to be sent first, got %q", to)
+	}
+
+	first, err := app.FindQueuedMailById(items[0].Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first.Status != core.QueuedMailStatusSent || first.Attempts != 1 {
+		t.Fatalf("Expected sent status with 1 attempt, got %q (%d)", first.Status, first.Attempts)
+	}
+
+	// failed sends
+	// ---
+	app.OnMailerSend().Bind(&hook.Handler[*core.MailerEvent]{
+		Id: "test_fail",
+		Func: func(e *core.MailerEvent) error {
+			return errors.New("test_send_error")
+		},
+	})
+
+	for i := 0; i < 2; i++ {
+		processed, err = app.ProcessMailQueue(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if processed != 2 {
+			t.Fatalf("[%d] Expected 2 processed emails, got %d", i, processed)
+		}
+	}
+
+	// no more pending emails
+	processed, err = app.ProcessMailQueue(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if processed != 0 {
+		t.Fatalf("Expected no processed emails, got %d", processed)
+	}
+
+	for _, item := range items[1:] {
+		failed, err := app.FindQueuedMailById(item.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if failed.Status != core.QueuedMailStatusFailed || failed.Attempts != 2 || failed.Error != "test_send_error" {
+			t.Fatalf("Expected failed status with 2 attempts and error, got %q (%d) %q", failed.Status, failed.Attempts, failed.Error)
+		}
+	}
+}
+
+func TestDeleteOldQueuedMails(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	for _, status := range []string{core.QueuedMailStatusPending, core.QueuedMailStatusSent, core.QueuedMailStatusFailed} {
+		item, err := core.NewQueuedMail(&mailer.Message{Subject: status})
+		if err != nil {
+			t.Fatal(err)
+		}
+		item.Status = status
+		if err := app.Save(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	count := func() int {
+		var total int
+		if err := app.MailQueueQuery().Select("count(*)").Row(&total); err != nil {
+			t.Fatal(err)
+		}
+		return total
+	}
+
+	if err := app.DeleteOldQueuedMails(time.Now().Add(-1 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if total := count(); total != 3 {
+		t.Fatalf("Expected 3 queued emails to remain, got %d", total)
+	}
+
+	if err := app.DeleteOldQueuedMails(time.Now().Add(1 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	if total := count(); total != 1 {
+		t.Fatalf("Expected only the pending email to remain, got %d", total)
+	}
+
+	pending := []*core.QueuedMail{}
+	if err := app.MailQueueQuery().AndWhere(dbx.HashExp{"status": core.QueuedMailStatusPending}).All(&pending); err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != 1 {
+		t.Fatalf("Expected the pending email to remain, got %d", len(pending))
+	}
+}
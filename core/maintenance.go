@@ -0,0 +1,149 @@
+package core
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	paramsKeyMaintenance = "maintenance"
+
+	// StoreKeyMaintenanceState is the app store key of the cached app maintenance state.
+	StoreKeyMaintenanceState = "@maintenanceState"
+
+	// DefaultMaintenanceRetryAfter is the default Retry-After value
+	// (in seconds) returned by the read-only mode rejected requests.
+	DefaultMaintenanceRetryAfter = 60
+)
+
+// maintenanceStateCacheTTL is the max duration for which the loaded
+// maintenance state is cached before being fetched again from the db.
+var maintenanceStateCacheTTL = 5 * time.Second
+
+// MaintenanceState defines the app maintenance state.
+//
+// When ReadOnly is enabled, all mutation (aka. non GET/HEAD/OPTIONS)
+// API requests of non-superusers are rejected, making it safe to
+// perform backups, migrations and other incident response tasks.
+type MaintenanceState struct {
+	// ReadOnly enables the app read-only mode.
+	ReadOnly bool `form:"readOnly" json:"readOnly"`
+
+	// Message is an optional message returned with the rejected requests errors
+	// (if empty, fallbacks to a generic message).
+	Message string `form:"message" json:"message"`
+
+	// RetryAfter specifies the number of seconds returned
+	// as Retry-After header with the rejected requests errors
+	// (if zero, fallbacks to [DefaultMaintenanceRetryAfter]).
+	RetryAfter int `form:"retryAfter" json:"retryAfter"`
+
+	// Updated is the date when the state was last changed.
+	Updated types.DateTime `form:"-" json:"updated"`
+}
+
+// Validate makes MaintenanceState validatable by implementing [validation.Validatable] interface.
+func (s MaintenanceState) Validate() error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Message, validation.Length(0, 500)),
+		validation.Field(&s.RetryAfter, validation.Min(0), validation.Max(86400)),
+	)
+}
+
+// RetryAfterValue returns the normalized RetryAfter value.
+func (s MaintenanceState) RetryAfterValue() int {
+	if s.RetryAfter <= 0 {
+		return DefaultMaintenanceRetryAfter
+	}
+
+	return s.RetryAfter
+}
+
+type cachedMaintenanceState struct {
+	state   MaintenanceState
+	expires time.Time
+}
+
+// MaintenanceState returns the current app maintenance (aka. read-only mode) state.
+//
+// The state is cached for a short period of time so that changes
+// made by another process (e.g. the maintenance console command)
+// are eventually picked up without restarting the app.
+//
+// The returned state is a copy and it is safe to be modified.
+func (app *BaseApp) MaintenanceState() (*MaintenanceState, error) {
+	cached, ok := app.Store().Get(StoreKeyMaintenanceState).(*cachedMaintenanceState)
+	if ok && cached.expires.After(time.Now()) {
+		state := cached.state
+		return &state, nil
+	}
+
+	state := &MaintenanceState{}
+
+	param := &Param{}
+	err := app.ModelQuery(param).Model(paramsKeyMaintenance, param)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return nil, err
+	}
+
+	if param.Id != "" && len(param.Value) > 0 {
+		if err := json.Unmarshal(param.Value, state); err != nil {
+			return nil, err
+		}
+	}
+
+	app.cacheMaintenanceState(*state)
+
+	return state, nil
+}
+
+// SaveMaintenanceState validates and persists the provided app maintenance state.
+func (app *BaseApp) SaveMaintenanceState(state *MaintenanceState) error {
+	if state == nil {
+		return errors.New("missing maintenance state")
+	}
+
+	if err := state.Validate(); err != nil {
+		return err
+	}
+
+	state.Updated = types.NowDateTime()
+
+	raw, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	param := &Param{}
+	err = app.ModelQuery(param).Model(paramsKeyMaintenance, param)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if param.Id == "" {
+		param.Id = paramsKeyMaintenance
+		param.Created = state.Updated
+	}
+	param.Updated = state.Updated
+	param.Value = raw
+
+	if err := app.Save(param); err != nil {
+		return err
+	}
+
+	app.cacheMaintenanceState(*state)
+
+	return nil
+}
+
+func (app *BaseApp) cacheMaintenanceState(state MaintenanceState) {
+	app.Store().Set(StoreKeyMaintenanceState, &cachedMaintenanceState{
+		state:   state,
+		expires: time.Now().Add(maintenanceStateCacheTTL),
+	})
+}
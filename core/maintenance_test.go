@@ -0,0 +1,150 @@
+package core_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestMaintenanceStateValidate(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		state          core.MaintenanceState
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.MaintenanceState{},
+			nil,
+		},
+		{
+			"invalid data",
+			core.MaintenanceState{
+				Message:    strings.Repeat("a", 501),
+				RetryAfter: -1,
+			},
+			[]string{"message", "retryAfter"},
+		},
+		{
+			"too large retryAfter",
+			core.MaintenanceState{RetryAfter: 86401},
+			[]string{"retryAfter"},
+		},
+		{
+			"valid data",
+			core.MaintenanceState{
+				ReadOnly:   true,
+				Message:    strings.Repeat("a", 500),
+				RetryAfter: 86400,
+			},
+			nil,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, s.state.Validate(), s.expectedErrors)
+		})
+	}
+}
+
+func TestMaintenanceStateRetryAfterValue(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		retryAfter int
+		expected   int
+	}{
+		{-1, core.DefaultMaintenanceRetryAfter},
+		{0, core.DefaultMaintenanceRetryAfter},
+		{1, 1},
+		{300, 300},
+	}
+
+	for _, s := range scenarios {
+		state := core.MaintenanceState{RetryAfter: s.retryAfter}
+
+		if v := state.RetryAfterValue(); v != s.expected {
+			t.Fatalf("[%d] Expected %d, got %d", s.retryAfter, s.expected, v)
+		}
+	}
+}
+
+func TestMaintenanceStateLoadAndSave(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// default state
+	state, err := app.MaintenanceState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ReadOnly || state.Message != "" || state.RetryAfter != 0 || !state.Updated.IsZero() {
+		t.Fatalf("Expected zero default state, got %#v", state)
+	}
+
+	// invalid state
+	err = app.SaveMaintenanceState(&core.MaintenanceState{RetryAfter: -1})
+	if err == nil {
+		t.Fatal("Expected validation error, got nil")
+	}
+
+	// save
+	err = app.SaveMaintenanceState(&core.MaintenanceState{
+		ReadOnly:   true,
+		Message:    "test",
+		RetryAfter: 120,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// load from the cache
+	state, err = app.MaintenanceState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.ReadOnly || state.Message != "test" || state.RetryAfter != 120 || state.Updated.IsZero() {
+		t.Fatalf("Unexpected cached state %#v", state)
+	}
+
+	// modifying the returned state shouldn't affect the cached one
+	state.ReadOnly = false
+	state, err = app.MaintenanceState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.ReadOnly {
+		t.Fatal("Expected the cached state to remain unchanged")
+	}
+
+	// load from the db
+	app.Store().Remove(core.StoreKeyMaintenanceState)
+	state, err = app.MaintenanceState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !state.ReadOnly || state.Message != "test" || state.RetryAfter != 120 || state.Updated.IsZero() {
+		t.Fatalf("Unexpected db state %#v", state)
+	}
+
+	// update the existing param
+	err = app.SaveMaintenanceState(&core.MaintenanceState{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	app.Store().Remove(core.StoreKeyMaintenanceState)
+	state, err = app.MaintenanceState()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if state.ReadOnly || state.Message != "" {
+		t.Fatalf("Expected disabled read-only state, got %#v", state)
+	}
+}
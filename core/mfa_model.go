@@ -13,6 +13,7 @@ const (
 	MFAMethodPassword = "password"
 	MFAMethodOAuth2   = "oauth2"
 	MFAMethodOTP      = "otp"
+	MFAMethodLDAP     = "ldap"
 )
 
 const CollectionNameMFAs = "_mfas"
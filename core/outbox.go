@@ -0,0 +1,386 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/routine"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const OutboxTableName = "_outbox"
+
+const (
+	OutboxMessageStatusPending   = "pending"
+	OutboxMessageStatusDelivered = "delivered"
+	OutboxMessageStatusFailed    = "failed"
+)
+
+// DefaultOutboxMaxAttempts is the max delivery attempts of an outbox
+// message that doesn't have explicit [OutboxMessage.MaxAttempts].
+const DefaultOutboxMaxAttempts = 10
+
+const (
+	// outboxClaimDuration is the time for which a message is reserved
+	// for a single delivery attempt (after that it is considered
+	// abandoned, e.g. because of a crash, and it is retried).
+	outboxClaimDuration = 5 * time.Minute
+
+	outboxMinRetryDelay = 30 * time.Second
+	outboxMaxRetryDelay = 1 * time.Hour
+
+	// outboxUnhandledRetryDelay is the delay before retrying a message
+	// that wasn't handled by any of the delivery hook handlers.
+	outboxUnhandledRetryDelay = 5 * time.Minute
+
+	// outboxMaxDays is the number of days after which
+	// the processed outbox messages are deleted.
+	outboxMaxDays = 7
+)
+
+// ErrOutboxMessageUnhandled is the error stored for the outbox messages
+// that were not marked as handled by any of the delivery hook handlers.
+var ErrOutboxMessageUnhandled = errors.New("the outbox message was not handled by any of the OnOutboxMessageDeliver handlers")
+
+var _ Model = (*OutboxMessage)(nil)
+
+// OutboxMessage defines a single side effect intent (e.g. an external
+// API call) that is delivered after the transaction in which it was
+// created is committed.
+//
+// The messages are delivered via the [App.OnOutboxMessageDeliver] hook
+// handlers with retries on error (see [App.EnqueueOutboxMessage]).
+type OutboxMessage struct {
+	BaseModel
+
+	// Topic is the message delivery hook tag (e.g. "orders.sync").
+	Topic string `db:"topic" json:"topic"`
+
+	// Payload is the serialized message data.
+	Payload types.JSONRaw `db:"payload" json:"payload"`
+
+	Status string `db:"status" json:"status"`
+
+	// Attempts is the number of the performed delivery attempts.
+	Attempts int `db:"attempts" json:"attempts"`
+
+	// MaxAttempts is the max delivery attempts before marking the message as failed.
+	//
+	// If zero, fallbacks to [DefaultOutboxMaxAttempts].
+	MaxAttempts int `db:"maxAttempts" json:"maxAttempts"`
+
+	// Error is the error of the last failed delivery attempt.
+	Error string `db:"error" json:"error"`
+
+	// NextAttempt is the time after which the message is due for delivery.
+	//
+	// If zero on create, it defaults to the create time.
+	NextAttempt types.DateTime `db:"nextAttempt" json:"nextAttempt"`
+
+	Created types.DateTime `db:"created" json:"created"`
+	Updated types.DateTime `db:"updated" json:"updated"`
+}
+
+// NewOutboxMessage creates a new pending OutboxMessage model
+// with the provided topic and JSON serialized payload.
+func NewOutboxMessage(topic string, payload any) (*OutboxMessage, error) {
+	if topic == "" {
+		return nil, errors.New("the outbox message topic is required")
+	}
+
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	return &OutboxMessage{
+		Topic:   topic,
+		Payload: raw,
+		Status:  OutboxMessageStatusPending,
+	}, nil
+}
+
+// TableName returns the OutboxMessage model table name.
+func (m *OutboxMessage) TableName() string {
+	return OutboxTableName
+}
+
+// UnmarshalPayload unmarshalizes the message payload into result.
+func (m *OutboxMessage) UnmarshalPayload(result any) error {
+	return json.Unmarshal(m.Payload, result)
+}
+
+// retryDelay returns the exponential backoff delay before the next delivery attempt.
+func (m *OutboxMessage) retryDelay() time.Duration {
+	delay := outboxMinRetryDelay
+
+	for i := 1; i < m.Attempts && delay < outboxMaxRetryDelay; i++ {
+		delay *= 2
+	}
+
+	return min(delay, outboxMaxRetryDelay)
+}
+
+// -------------------------------------------------------------------
+
+// OutboxQuery returns a new OutboxMessage select query.
+func (app *BaseApp) OutboxQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&OutboxMessage{})
+}
+
+// FindOutboxMessageById returns a single OutboxMessage model by its id.
+func (app *BaseApp) FindOutboxMessageById(id string) (*OutboxMessage, error) {
+	result := &OutboxMessage{}
+
+	err := app.OutboxQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// EnqueueOutboxMessage persists a new outbox message with the
+// provided topic and payload to be delivered after commit.
+//
+// When called with a transactional app (e.g. e.App in a record create hook
+// within a transaction), the message is stored as part of the transaction
+// and it is delivered only if the transaction commits.
+// Outside of a transaction the message is delivered right away.
+//
+// The delivery is performed in the background via the
+// [App.OnOutboxMessageDeliver] hook handlers and failed
+// deliveries are retried with exponential backoff.
+func (app *BaseApp) EnqueueOutboxMessage(topic string, payload any) (*OutboxMessage, error) {
+	message, err := NewOutboxMessage(topic, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := app.Save(message); err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+// DispatchOutbox delivers up to limit due pending outbox messages
+// (oldest first) and returns the number of the processed ones.
+//
+// If limit is <= 0, all due messages are processed.
+func (app *BaseApp) DispatchOutbox(limit int) (int, error) {
+	query := app.OutboxQuery().
+		AndWhere(dbx.HashExp{"status": OutboxMessageStatusPending}).
+		AndWhere(dbx.NewExp("[[nextAttempt]] <= {:now}", dbx.Params{"now": types.NowDateTime().String()})).
+		OrderBy("created ASC", "rowid ASC")
+
+	if limit > 0 {
+		query.Limit(int64(limit))
+	}
+
+	messages := []*OutboxMessage{}
+	if err := query.All(&messages); err != nil {
+		return 0, err
+	}
+
+	var total int
+
+	for _, m := range messages {
+		processed, err := app.dispatchOutboxMessage(m)
+		if err != nil {
+			return total, err
+		}
+
+		if processed {
+			total++
+		}
+	}
+
+	return total, nil
+}
+
+// DeleteOldOutboxMessages deletes all delivered and failed outbox messages that are created before createdBefore.
+//
+// The pending messages are never deleted.
+func (app *BaseApp) DeleteOldOutboxMessages(createdBefore time.Time) error {
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+
+	_, err := app.NonconcurrentDB().Delete(OutboxTableName, dbx.And(
+		dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": formattedDate}),
+		dbx.In("status", OutboxMessageStatusDelivered, OutboxMessageStatusFailed),
+	)).Execute()
+
+	return err
+}
+
+// dispatchOutboxMessage performs a single delivery attempt of the provided message.
+//
+// It returns false if the message was already claimed by another dispatcher.
+func (app *BaseApp) dispatchOutboxMessage(m *OutboxMessage) (bool, error) {
+	now := types.NowDateTime()
+
+	// claim the message to prevent concurrent deliveries
+	// (e.g. the post-commit dispatch and the cron job)
+	result, err := app.NonconcurrentDB().Update(
+		OutboxTableName,
+		dbx.Params{"nextAttempt": now.Add(outboxClaimDuration).String()},
+		dbx.And(
+			dbx.HashExp{"id": m.Id, "status": OutboxMessageStatusPending},
+			dbx.NewExp("[[nextAttempt]] <= {:now}", dbx.Params{"now": now.String()}),
+		),
+	).Execute()
+	if err != nil {
+		return false, err
+	}
+
+	claimed, err := result.RowsAffected()
+	if err != nil || claimed == 0 {
+		return false, err
+	}
+
+	event := new(OutboxMessageEvent)
+	event.App = app
+	event.Message = m
+
+	deliverErr := app.OnOutboxMessageDeliver().Trigger(event)
+
+	switch {
+	case deliverErr != nil:
+		m.Attempts++
+		m.Error = deliverErr.Error()
+
+		maxAttempts := m.MaxAttempts
+		if maxAttempts <= 0 {
+			maxAttempts = DefaultOutboxMaxAttempts
+		}
+
+		if m.Attempts >= maxAttempts {
+			m.Status = OutboxMessageStatusFailed
+		} else {
+			m.NextAttempt = types.NowDateTime().Add(m.retryDelay())
+		}
+	case !event.Handled:
+		// keep the message pending for the handlers that could be registered later
+		m.Error = ErrOutboxMessageUnhandled.Error()
+		m.NextAttempt = types.NowDateTime().Add(outboxUnhandledRetryDelay)
+	default:
+		m.Attempts++
+		m.Status = OutboxMessageStatusDelivered
+		m.Error = ""
+	}
+
+	if err := app.Save(m); err != nil {
+		return true, err
+	}
+
+	return true, nil
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerOutboxHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*OutboxMessage); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				if m.Status == "" {
+					m.Status = OutboxMessageStatusPending
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+				if m.NextAttempt.IsZero() {
+					m.NextAttempt = m.Created
+				}
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*OutboxMessage); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// tracks the in-flight background deliveries
+	var wg sync.WaitGroup
+
+	// deliver right away after commit
+	// (the after success model hooks of transactional saves are deferred until the transaction completes)
+	app.OnModelAfterCreateSuccess().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*OutboxMessage); ok && m.Status == OutboxMessageStatusPending {
+				message := *m
+
+				wg.Add(1)
+				routine.FireAndForget(func() {
+					defer wg.Done()
+
+					if _, err := app.dispatchOutboxMessage(&message); err != nil {
+						app.Logger().Warn(
+							"Failed to dispatch outbox message",
+							slog.String("id", message.Id),
+							slog.String("error", err.Error()),
+						)
+					}
+				})
+			}
+
+			return e.Next()
+		},
+	})
+
+	// retry the failed and abandoned deliveries
+	var dispatching atomic.Bool
+
+	app.Cron().Add("__pbOutbox__", "* * * * *", func() {
+		if !dispatching.CompareAndSwap(false, true) {
+			return
+		}
+		defer dispatching.Store(false)
+
+		_, err := app.DispatchOutbox(0)
+		if err != nil {
+			app.Logger().Warn("Failed to dispatch the outbox messages", "error", err)
+		}
+	})
+
+	// cleanup old processed messages
+	app.Cron().Add("__pbOutboxCleanup__", "0 */6 * * *", func() {
+		deleteErr := app.DeleteOldOutboxMessages(time.Now().AddDate(0, 0, -1*outboxMaxDays))
+		if deleteErr != nil {
+			app.Logger().Warn("Failed to delete old outbox messages", "error", deleteErr)
+		}
+	})
+
+	// wait for the in-flight deliveries to complete before exit
+	// (the undelivered messages are picked up by the cron job on next start)
+	app.OnTerminate().Bind(&hook.Handler[*TerminateEvent]{
+		Id: "__pbOutboxOnTerminate__",
+		Func: func(e *TerminateEvent) error {
+			wg.Wait()
+
+			return e.Next()
+		},
+		Priority: -1000, // before the logger flush
+	})
+}
@@ -0,0 +1,387 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestNewOutboxMessage(t *testing.T) {
+	t.Parallel()
+
+	if _, err := core.NewOutboxMessage("", nil); err == nil {
+		t.Fatal("Expected missing topic error")
+	}
+
+	m, err := core.NewOutboxMessage("test", map[string]any{"a": 123})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if m.Topic != "test" {
+		t.Fatalf("Expected topic %q, got %q", "test", m.Topic)
+	}
+
+	if m.Status != core.OutboxMessageStatusPending {
+		t.Fatalf("Expected status %q, got %q", core.OutboxMessageStatusPending, m.Status)
+	}
+
+	var payload struct {
+		A int `json:"a"`
+	}
+	if err := m.UnmarshalPayload(&payload); err != nil {
+		t.Fatal(err)
+	}
+
+	if payload.A != 123 {
+		t.Fatalf("Expected payload a 123, got %d", payload.A)
+	}
+}
+
+func TestEnqueueOutboxMessageTransaction(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	delivered := make(chan string, 10)
+
+	app.OnOutboxMessageDeliver("test").BindFunc(func(e *core.OutboxMessageEvent) error {
+		delivered <- e.Message.Id
+		e.Handled = true
+		return e.Next()
+	})
+
+	t.Run("rollback", func(t *testing.T) {
+		var id string
+
+		txErr := app.RunInTransaction(func(txApp core.App) error {
+			m, err := txApp.EnqueueOutboxMessage("test", "rollback")
+			if err != nil {
+				return err
+			}
+			id = m.Id
+
+			return errors.New("rollback")
+		})
+		if txErr == nil {
+			t.Fatal("Expected transaction error")
+		}
+
+		if _, err := app.FindOutboxMessageById(id); err == nil {
+			t.Fatal("Expected the outbox message to be rolled back")
+		}
+	})
+
+	t.Run("commit", func(t *testing.T) {
+		var id string
+
+		txErr := app.RunInTransaction(func(txApp core.App) error {
+			m, err := txApp.EnqueueOutboxMessage("test", "commit")
+			if err != nil {
+				return err
+			}
+			id = m.Id
+
+			select {
+			case <-delivered:
+				t.Fatal("Expected the message to not be delivered before commit")
+			case <-time.After(50 * time.Millisecond):
+			}
+
+			return nil
+		})
+		if txErr != nil {
+			t.Fatal(txErr)
+		}
+
+		select {
+		case deliveredId := <-delivered:
+			if deliveredId != id {
+				t.Fatalf("Expected delivered message %q, got %q", id, deliveredId)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatal("Expected the message to be delivered after commit")
+		}
+
+		// wait for the delivery result to be stored
+		for i := 0; i < 100; i++ {
+			m, err := app.FindOutboxMessageById(id)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if m.Status == core.OutboxMessageStatusDelivered {
+				if m.Attempts != 1 {
+					t.Fatalf("Expected 1 attempt, got %d", m.Attempts)
+				}
+				return
+			}
+
+			time.Sleep(20 * time.Millisecond)
+		}
+
+		t.Fatal("Expected the message to be marked as delivered")
+	})
+}
+
+func TestDispatchOutbox(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	var calls = map[string]int{}
+	var fail bool
+
+	app.OnOutboxMessageDeliver("test").BindFunc(func(e *core.OutboxMessageEvent) error {
+		calls[e.Message.Topic]++
+		if fail {
+			return errors.New("test_error")
+		}
+		e.Handled = true
+		return e.Next()
+	})
+
+	app.OnOutboxMessageDeliver("other").BindFunc(func(e *core.OutboxMessageEvent) error {
+		calls[e.Message.Topic]++
+		e.Handled = true
+		return e.Next()
+	})
+
+	// create with future next attempt to skip the immediate post-create delivery
+	m, err := core.NewOutboxMessage("test", "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.MaxAttempts = 2
+	m.NextAttempt = types.NowDateTime().Add(1 * time.Hour)
+	if err := app.Save(m); err != nil {
+		t.Fatal(err)
+	}
+
+	makeDue := func(t *testing.T) {
+		_, err := app.NonconcurrentDB().Update(
+			core.OutboxTableName,
+			dbx.Params{"nextAttempt": types.NowDateTime().Add(-1 * time.Second).String()},
+			dbx.HashExp{"id": m.Id},
+		).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dispatch := func(t *testing.T, expectedTotal int) *core.OutboxMessage {
+		total, err := app.DispatchOutbox(0)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if total != expectedTotal {
+			t.Fatalf("Expected %d processed messages, got %d", expectedTotal, total)
+		}
+
+		updated, err := app.FindOutboxMessageById(m.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return updated
+	}
+
+	t.Run("not due", func(t *testing.T) {
+		updated := dispatch(t, 0)
+		if updated.Attempts != 0 || len(calls) != 0 {
+			t.Fatalf("Expected no delivery attempts, got %d (%v)", updated.Attempts, calls)
+		}
+	})
+
+	t.Run("failed attempt", func(t *testing.T) {
+		fail = true
+		makeDue(t)
+
+		updated := dispatch(t, 1)
+
+		if updated.Status != core.OutboxMessageStatusPending {
+			t.Fatalf("Expected status %q, got %q", core.OutboxMessageStatusPending, updated.Status)
+		}
+
+		if updated.Attempts != 1 || updated.Error != "test_error" {
+			t.Fatalf("Expected 1 attempt with test_error, got %d (%q)", updated.Attempts, updated.Error)
+		}
+
+		if !updated.NextAttempt.After(types.NowDateTime().Add(20 * time.Second)) {
+			t.Fatalf("Expected the next attempt to be delayed, got %v", updated.NextAttempt)
+		}
+
+		// the retry is not due yet
+		dispatch(t, 0)
+	})
+
+	t.Run("max attempts", func(t *testing.T) {
+		makeDue(t)
+
+		updated := dispatch(t, 1)
+
+		if updated.Status != core.OutboxMessageStatusFailed {
+			t.Fatalf("Expected status %q, got %q", core.OutboxMessageStatusFailed, updated.Status)
+		}
+
+		if updated.Attempts != 2 {
+			t.Fatalf("Expected 2 attempts, got %d", updated.Attempts)
+		}
+
+		// failed messages are not retried
+		makeDue(t)
+		dispatch(t, 0)
+	})
+
+	t.Run("delivered", func(t *testing.T) {
+		fail = false
+
+		_, err := app.NonconcurrentDB().Update(
+			core.OutboxTableName,
+			dbx.Params{"status": core.OutboxMessageStatusPending},
+			dbx.HashExp{"id": m.Id},
+		).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		updated := dispatch(t, 1)
+
+		if updated.Status != core.OutboxMessageStatusDelivered || updated.Error != "" {
+			t.Fatalf("Expected status %q without error, got %q (%q)", core.OutboxMessageStatusDelivered, updated.Status, updated.Error)
+		}
+
+		if calls["test"] != 3 || calls["other"] != 0 {
+			t.Fatalf("Unexpected topic handler calls %v", calls)
+		}
+	})
+}
+
+func TestDispatchOutboxUnhandled(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// handler that doesn't mark the message as handled
+	app.OnOutboxMessageDeliver().BindFunc(func(e *core.OutboxMessageEvent) error {
+		return e.Next()
+	})
+
+	m, err := core.NewOutboxMessage("test", "data")
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.NextAttempt = types.NowDateTime().Add(1 * time.Hour)
+	if err := app.Save(m); err != nil {
+		t.Fatal(err)
+	}
+
+	makeDue := func(t *testing.T) {
+		_, err := app.NonconcurrentDB().Update(
+			core.OutboxTableName,
+			dbx.Params{"nextAttempt": types.NowDateTime().Add(-1 * time.Second).String()},
+			dbx.HashExp{"id": m.Id},
+		).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	t.Run("without topic handler", func(t *testing.T) {
+		makeDue(t)
+
+		if _, err := app.DispatchOutbox(0); err != nil {
+			t.Fatal(err)
+		}
+
+		updated, err := app.FindOutboxMessageById(m.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if updated.Status != core.OutboxMessageStatusPending {
+			t.Fatalf("Expected status %q, got %q", core.OutboxMessageStatusPending, updated.Status)
+		}
+
+		if updated.Attempts != 0 || updated.Error != core.ErrOutboxMessageUnhandled.Error() {
+			t.Fatalf("Expected 0 attempts with unhandled error, got %d (%q)", updated.Attempts, updated.Error)
+		}
+
+		if !updated.NextAttempt.After(types.NowDateTime()) {
+			t.Fatalf("Expected the next attempt to be delayed, got %v", updated.NextAttempt)
+		}
+	})
+
+	t.Run("with later registered topic handler", func(t *testing.T) {
+		app.OnOutboxMessageDeliver("test").BindFunc(func(e *core.OutboxMessageEvent) error {
+			e.Handled = true
+			return e.Next()
+		})
+
+		makeDue(t)
+
+		if _, err := app.DispatchOutbox(0); err != nil {
+			t.Fatal(err)
+		}
+
+		updated, err := app.FindOutboxMessageById(m.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if updated.Status != core.OutboxMessageStatusDelivered || updated.Error != "" {
+			t.Fatalf("Expected status %q without error, got %q (%q)", core.OutboxMessageStatusDelivered, updated.Status, updated.Error)
+		}
+
+		if updated.Attempts != 1 {
+			t.Fatalf("Expected 1 attempt, got %d", updated.Attempts)
+		}
+	})
+}
+
+func TestDeleteOldOutboxMessages(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	statuses := []string{
+		core.OutboxMessageStatusPending,
+		core.OutboxMessageStatusDelivered,
+		core.OutboxMessageStatusFailed,
+	}
+
+	for _, status := range statuses {
+		m, err := core.NewOutboxMessage("test", nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.Status = status
+		m.NextAttempt = types.NowDateTime().Add(1 * time.Hour)
+		if err := app.Save(m); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.DeleteOldOutboxMessages(time.Now().Add(1 * time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	var remaining []*core.OutboxMessage
+	if err := app.OutboxQuery().All(&remaining); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(remaining) != 1 || remaining[0].Status != core.OutboxMessageStatusPending {
+		t.Fatalf("Expected only the pending message to remain, got %v", remaining)
+	}
+}
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const ReactionsTableName = "_reactions"
+
+// ErrInvalidReactionType is returned when trying to react to a record
+// with a reaction type that is not allowed by its collection reactions config.
+var ErrInvalidReactionType = errors.New("invalid or disabled reaction type")
+
+var (
+	_ Model         = (*Reaction)(nil)
+	_ PostValidator = (*Reaction)(nil)
+)
+
+// Reaction defines a single auth record reaction to a collection record
+// (see [ReactionsConfig]).
+type Reaction struct {
+	BaseModel
+
+	CollectionRef     string         `db:"collectionRef" json:"collectionRef"`
+	RecordRef         string         `db:"recordRef" json:"recordRef"`
+	AuthCollectionRef string         `db:"authCollectionRef" json:"authCollectionRef"`
+	AuthRef           string         `db:"authRef" json:"authRef"`
+	Type              string         `db:"type" json:"type"`
+	Created           types.DateTime `db:"created" json:"created"`
+}
+
+// TableName returns the Reaction model table name.
+func (m *Reaction) TableName() string {
+	return ReactionsTableName
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *Reaction) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(&m.CollectionRef, validation.Required, validation.By(validateCollectionId(app))),
+		validation.Field(&m.RecordRef, validation.Required, validation.By(validateRecordId(app, m.CollectionRef))),
+		validation.Field(&m.AuthCollectionRef, validation.Required, validation.By(validateCollectionId(app, CollectionTypeAuth))),
+		validation.Field(&m.AuthRef, validation.Required, validation.By(validateRecordId(app, m.AuthCollectionRef))),
+		validation.Field(&m.Type, validation.Required, validation.Length(1, 50), validation.Match(reactionTypeRegex)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// ReactionQuery returns a new Reaction select query.
+func (app *BaseApp) ReactionQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&Reaction{})
+}
+
+// FindRecordReactionCounts returns the reactions count of each
+// reaction type of the provided record.
+//
+// All collection reactions config types are included (even if their count is 0).
+func (app *BaseApp) FindRecordReactionCounts(record *Record) (map[string]int, error) {
+	rows := []struct {
+		Type  string `db:"type"`
+		Total int    `db:"total"`
+	}{}
+
+	err := app.ReactionQuery().
+		Select("type", "COUNT(*) as total").
+		AndWhere(dbx.HashExp{
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+		}).
+		GroupBy("type").
+		All(&rows)
+	if err != nil {
+		return nil, err
+	}
+
+	result := map[string]int{}
+
+	if config := record.Collection().Reactions; config != nil {
+		for _, t := range config.Types {
+			result[t] = 0
+		}
+	}
+
+	for _, row := range rows {
+		result[row.Type] = row.Total
+	}
+
+	return result, nil
+}
+
+// FindAuthRecordReactions returns the reaction types of authRecord to the provided record.
+func (app *BaseApp) FindAuthRecordReactions(record *Record, authRecord *Record) ([]string, error) {
+	result := []string{}
+
+	err := app.ReactionQuery().
+		Select("type").
+		AndWhere(dbx.HashExp{
+			"collectionRef":     record.Collection().Id,
+			"recordRef":         record.Id,
+			"authCollectionRef": authRecord.Collection().Id,
+			"authRef":           authRecord.Id,
+		}).
+		OrderBy("type ASC").
+		Column(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// SetRecordReaction adds (active=true) or removes (active=false)
+// the reactionType reaction of authRecord to the provided record.
+//
+// The operation is idempotent, aka. adding an existing or removing
+// a missing reaction is a no-op.
+//
+// Returns [ErrInvalidReactionType] if reactionType is not one of
+// the record collection reactions config types.
+func (app *BaseApp) SetRecordReaction(record *Record, authRecord *Record, reactionType string, active bool) error {
+	_, err := app.saveRecordReaction(record, authRecord, reactionType, func(bool) bool {
+		return active
+	})
+
+	return err
+}
+
+// ToggleRecordReaction adds the reactionType reaction of authRecord
+// to the provided record or removes it if it already exists.
+//
+// Returns the new reaction state (true if the reaction was added).
+//
+// Returns [ErrInvalidReactionType] if reactionType is not one of
+// the record collection reactions config types.
+func (app *BaseApp) ToggleRecordReaction(record *Record, authRecord *Record, reactionType string) (bool, error) {
+	return app.saveRecordReaction(record, authRecord, reactionType, func(exists bool) bool {
+		return !exists
+	})
+}
+
+// saveRecordReaction adds or removes a single record reaction based on
+// the resolveActive result and recalculates the record counts field (if any)
+// within a single transaction.
+func (app *BaseApp) saveRecordReaction(
+	record *Record,
+	authRecord *Record,
+	reactionType string,
+	resolveActive func(exists bool) bool,
+) (bool, error) {
+	if authRecord == nil {
+		return false, errors.New("missing reaction auth record")
+	}
+
+	config := record.Collection().Reactions
+	if config == nil || !config.HasType(reactionType) {
+		return false, ErrInvalidReactionType
+	}
+
+	var active bool
+
+	err := app.RunInTransaction(func(txApp App) error {
+		reaction := &Reaction{}
+
+		findErr := txApp.ReactionQuery().
+			AndWhere(dbx.HashExp{
+				"collectionRef":     record.Collection().Id,
+				"recordRef":         record.Id,
+				"authCollectionRef": authRecord.Collection().Id,
+				"authRef":           authRecord.Id,
+				"type":              reactionType,
+			}).
+			Limit(1).
+			One(reaction)
+		exists := findErr == nil
+
+		active = resolveActive(exists)
+		if active == exists {
+			return nil // no change
+		}
+
+		if active {
+			reaction = &Reaction{
+				CollectionRef:     record.Collection().Id,
+				RecordRef:         record.Id,
+				AuthCollectionRef: authRecord.Collection().Id,
+				AuthRef:           authRecord.Id,
+				Type:              reactionType,
+			}
+
+			if err := txApp.Save(reaction); err != nil {
+				return err
+			}
+		} else if err := txApp.Delete(reaction); err != nil {
+			return err
+		}
+
+		return syncRecordReactionCounts(txApp, record)
+	})
+	if err != nil {
+		return false, err
+	}
+
+	return active, nil
+}
+
+// syncRecordReactionCounts recalculates the denormalized
+// reactions counts field value of the provided record (if configured).
+//
+// The field is updated with a direct db query to avoid triggering
+// the record hooks and changing its autodate fields.
+func syncRecordReactionCounts(app App, record *Record) error {
+	config := record.Collection().Reactions
+	if config == nil || config.CountsField == "" {
+		return nil
+	}
+
+	counts, err := app.FindRecordReactionCounts(record)
+	if err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(counts)
+	if err != nil {
+		return err
+	}
+
+	_, err = app.NonconcurrentDB().Update(
+		record.Collection().Name,
+		dbx.Params{config.CountsField: string(raw)},
+		dbx.HashExp{"id": record.Id},
+	).Execute()
+	if err != nil {
+		return err
+	}
+
+	record.Set(config.CountsField, types.JSONRaw(raw))
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerReactionHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*Reaction); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// delete the collection reactions and the reactions of its auth records
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(ReactionsTableName, dbx.Or(
+				dbx.HashExp{"collectionRef": e.Collection.Id},
+				dbx.HashExp{"authCollectionRef": e.Collection.Id},
+			)).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	// delete the record reactions and the reactions of the deleted auth record
+	// (note: the affected records counts are not recalculated and will be synced with their next reaction change)
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(ReactionsTableName, dbx.Or(
+				dbx.HashExp{
+					"collectionRef": e.Record.Collection().Id,
+					"recordRef":     e.Record.Id,
+				},
+				dbx.HashExp{
+					"authCollectionRef": e.Record.Collection().Id,
+					"authRef":           e.Record.Id,
+				},
+			)).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+}
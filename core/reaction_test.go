@@ -0,0 +1,184 @@
+package core_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func enableTestReactions(t testing.TB, app core.App) (*core.Record, *core.Record) {
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Reactions = &core.ReactionsConfig{Types: []string{"like", "love"}, CountsField: "json"}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record, err := app.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return record, user
+}
+
+func TestReactionTableName(t *testing.T) {
+	t.Parallel()
+
+	if v := (&core.Reaction{}).TableName(); v != "_reactions" {
+		t.Fatalf("Expected %q, got %q", "_reactions", v)
+	}
+}
+
+func TestToggleRecordReaction(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, user := enableTestReactions(t, app)
+
+	if _, err := app.ToggleRecordReaction(record, user, "missing"); !errors.Is(err, core.ErrInvalidReactionType) {
+		t.Fatalf("Expected ErrInvalidReactionType, got %v", err)
+	}
+
+	if _, err := app.ToggleRecordReaction(record, nil, "like"); err == nil {
+		t.Fatal("Expected missing auth record error")
+	}
+
+	scenarios := []struct {
+		expectedActive bool
+		expectedCounts string
+	}{
+		{true, `{"like":1,"love":0}`},
+		{false, `{"like":0,"love":0}`},
+		{true, `{"like":1,"love":0}`},
+	}
+
+	for i, s := range scenarios {
+		active, err := app.ToggleRecordReaction(record, user, "like")
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if active != s.expectedActive {
+			t.Fatalf("[%d] Expected active %v, got %v", i, s.expectedActive, active)
+		}
+
+		if v := record.GetString("json"); v != s.expectedCounts {
+			t.Fatalf("[%d] Expected record counts %s, got %s", i, s.expectedCounts, v)
+		}
+
+		refreshed, err := app.FindRecordById("demo1", record.Id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if v := refreshed.GetString("json"); v != s.expectedCounts {
+			t.Fatalf("[%d] Expected persisted counts %s, got %s", i, s.expectedCounts, v)
+		}
+
+		if !refreshed.GetDateTime("updated").Equal(record.GetDateTime("updated")) {
+			t.Fatalf("[%d] Expected the record updated date to remain unchanged", i)
+		}
+	}
+}
+
+func TestSetRecordReaction(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, user := enableTestReactions(t, app)
+
+	if err := app.SetRecordReaction(record, user, "missing", true); !errors.Is(err, core.ErrInvalidReactionType) {
+		t.Fatalf("Expected ErrInvalidReactionType, got %v", err)
+	}
+
+	// repeated calls shouldn't change the result
+	for i := 0; i < 2; i++ {
+		if err := app.SetRecordReaction(record, user, "love", true); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.SetRecordReaction(record, user, "like", true); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	counts, err := app.FindRecordReactionCounts(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if counts["like"] != 1 || counts["love"] != 1 {
+		t.Fatalf("Expected 1 like and 1 love, got %v", counts)
+	}
+
+	reacted, err := app.FindAuthRecordReactions(record, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(reacted, []string{"like", "love"}) {
+		t.Fatalf("Expected [like love] reactions, got %v", reacted)
+	}
+
+	for i := 0; i < 2; i++ {
+		if err := app.SetRecordReaction(record, user, "love", false); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reacted, err = app.FindAuthRecordReactions(record, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(reacted, []string{"like"}) {
+		t.Fatalf("Expected [like] reactions, got %v", reacted)
+	}
+
+	if v := record.GetString("json"); v != `{"like":1,"love":0}` {
+		t.Fatalf("Expected record counts to be synced, got %s", v)
+	}
+}
+
+func TestReactionsDeleteWithRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, user := enableTestReactions(t, app)
+
+	if err := app.SetRecordReaction(record, user, "like", true); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(user); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int
+	err := app.ReactionQuery().
+		Select("count(*)").
+		AndWhere(dbx.HashExp{"authRef": user.Id}).
+		Row(&total)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 0 {
+		t.Fatalf("Expected the auth record reactions to be deleted, found %d", total)
+	}
+}
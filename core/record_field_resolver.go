@@ -4,10 +4,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"slices"
 	"strconv"
 	"strings"
 
+	"github.com/ganigeorgiev/fexpr"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/security"
@@ -26,6 +28,9 @@ const (
 // ensure that `search.FieldResolver` interface is implemented
 var _ search.FieldResolver = (*RecordFieldResolver)(nil)
 
+// ensure that `search.FunctionResolver` interface is implemented
+var _ search.FunctionResolver = (*RecordFieldResolver)(nil)
+
 // RecordFieldResolver defines a custom search resolver struct for
 // managing Record model search fields.
 //
@@ -87,10 +92,15 @@ func NewRecordFieldResolver(
 			`^\w+[\w\.\:]*$`,
 			`^\@request\.context$`,
 			`^\@request\.method$`,
+			`^\@request\.ip$`,
 			`^\@request\.auth\.[\w\.\:]*\w+$`,
 			`^\@request\.body\.[\w\.\:]*\w+$`,
 			`^\@request\.query\.[\w\.\:]*\w+$`,
 			`^\@request\.headers\.[\w\.\:]*\w+$`,
+			`^\@request\.geo\.\w+(\:\w+)?$`,
+			`^\@request\.share\.\w+(\:\w+)?$`,
+			`^\@request\.custom\.[\w\.\:]*\w+$`,
+			`^\@request\.flags\.\w+$`,
 			`^\@collection\.\w+(\:\w+)?\.[\w\.\:]*\w+$`,
 		},
 	}
@@ -102,13 +112,41 @@ func NewRecordFieldResolver(
 		r.staticRequestInfo["query"] = r.requestInfo.Query
 		r.staticRequestInfo["headers"] = r.requestInfo.Headers
 		r.staticRequestInfo["body"] = r.requestInfo.Body
+		r.staticRequestInfo["ip"] = r.requestInfo.IP
+		r.staticRequestInfo["custom"] = r.requestInfo.Custom
 		r.staticRequestInfo["auth"] = nil
 		if r.requestInfo.Auth != nil {
 			authClone := r.requestInfo.Auth.Clone()
-			r.staticRequestInfo["auth"] = authClone.
+			authData := authClone.
 				Unhide(authClone.Collection().Fields.FieldNames()...).
 				IgnoreEmailVisibility(true).
 				PublicExport()
+
+			// the auth collection fields have precedence
+			if _, ok := authData[requestAuthSessionAge]; !ok {
+				authData[requestAuthSessionAge] = nil
+				if age := r.requestInfo.SessionAge(); age >= 0 {
+					authData[requestAuthSessionAge] = int64(age.Seconds())
+				}
+			}
+
+			r.staticRequestInfo["auth"] = authData
+		}
+		r.staticRequestInfo["share"] = nil
+		if r.requestInfo.Share != nil {
+			r.staticRequestInfo["share"] = map[string]any{
+				"collectionId": r.requestInfo.Share.CollectionRef,
+				"recordId":     r.requestInfo.Share.RecordRef,
+				"scope":        r.requestInfo.Share.Scope,
+			}
+		}
+		r.staticRequestInfo["geo"] = nil
+		if r.requestInfo.Geo != nil {
+			r.staticRequestInfo["geo"] = map[string]any{
+				"country": r.requestInfo.Geo.Country,
+				"asn":     r.requestInfo.Geo.ASN,
+				"asnOrg":  r.requestInfo.Geo.ASNOrg,
+			}
 		}
 	}
 
@@ -144,18 +182,144 @@ func (r *RecordFieldResolver) UpdateQuery(query *dbx.SelectQuery) error {
 //	screen.project_via_prototype.name
 //	@request.context
 //	@request.method
+//	@request.ip
 //	@request.query.filter
 //	@request.headers.x_token
 //	@request.auth.someRelation.name
+//	@request.auth.sessionAge
 //	@request.body.someRelation.name
 //	@request.body.someField
 //	@request.body.someSelect:each
 //	@request.body.someField:isset
+//	@request.geo.country
+//	@request.share.scope
+//	@request.custom.someKey
+//	@request.flags.someFlag
 //	@collection.product.name
 func (r *RecordFieldResolver) Resolve(fieldName string) (*search.ResolverResult, error) {
 	return parseAndRun(fieldName, r)
 }
 
+// ResolveFunction implements `search.FunctionResolver` interface.
+//
+// Example of some resolvable function calls:
+//
+//	@request.auth.hasRole("editor")
+//	@request.auth.hasPermission("orders.approve")
+//	@request.auth.memberOf(team)
+//	@request.auth.memberOf("TEAM_ID", "owner")
+func (r *RecordFieldResolver) ResolveFunction(name string, args ...fexpr.Token) (*search.ResolverResult, error) {
+	var check func(auth *Record, arg string) bool
+
+	switch name {
+	case "@request.auth.memberOf":
+		return r.resolveMemberOf(args...)
+	case "@request.auth.hasRole":
+		check = r.app.AuthRecordHasRole
+	case "@request.auth.hasPermission":
+		check = r.app.AuthRecordHasPermission
+	default:
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+
+	if len(args) != 1 || args[0].Type != fexpr.TokenText {
+		return nil, fmt.Errorf("[%s] expects a single text argument", name)
+	}
+
+	if r.requestInfo == nil || r.requestInfo.Auth == nil || !check(r.requestInfo.Auth, args[0].Literal) {
+		return &search.ResolverResult{Identifier: "FALSE"}, nil
+	}
+
+	return &search.ResolverResult{Identifier: "TRUE"}, nil
+}
+
+// resolveMemberOf resolves the @request.auth.memberOf(team, [role]) function
+// where team could be a text literal or a field identifier holding the team id.
+func (r *RecordFieldResolver) resolveMemberOf(args ...fexpr.Token) (*search.ResolverResult, error) {
+	if len(args) == 0 || len(args) > 2 {
+		return nil, errors.New("[@request.auth.memberOf] expects 1 or 2 arguments")
+	}
+
+	if len(args) == 2 && args[1].Type != fexpr.TokenText {
+		return nil, errors.New("[@request.auth.memberOf] expects the role argument to be a text literal")
+	}
+
+	result := &search.ResolverResult{Params: dbx.Params{}}
+
+	var teamIdentifier string
+	switch args[0].Type {
+	case fexpr.TokenText:
+		placeholder := "t" + security.PseudorandomString(8)
+		result.Params[placeholder] = args[0].Literal
+		teamIdentifier = "{:" + placeholder + "}"
+	case fexpr.TokenIdentifier:
+		team, err := r.Resolve(args[0].Literal)
+		if err != nil {
+			return nil, err
+		}
+		maps.Copy(result.Params, team.Params)
+		teamIdentifier = team.Identifier
+	default:
+		return nil, errors.New("[@request.auth.memberOf] expects the team argument to be a text literal or a field identifier")
+	}
+
+	if r.requestInfo == nil || r.requestInfo.Auth == nil {
+		result.Identifier = "FALSE"
+		return result, nil
+	}
+
+	collectionPlaceholder := "t" + security.PseudorandomString(8)
+	recordPlaceholder := "t" + security.PseudorandomString(8)
+	result.Params[collectionPlaceholder] = r.requestInfo.Auth.Collection().Id
+	result.Params[recordPlaceholder] = r.requestInfo.Auth.Id
+
+	var roleCondition string
+	if len(args) == 2 {
+		rolePlaceholder := "t" + security.PseudorandomString(8)
+		result.Params[rolePlaceholder] = args[1].Literal
+		roleCondition = " AND [[__tm.role]] = {:" + rolePlaceholder + "}"
+	}
+
+	result.Identifier = fmt.Sprintf(
+		"EXISTS (SELECT 1 FROM {{%s}} [[__tm]] WHERE [[__tm.teamRef]] = %s AND [[__tm.collectionRef]] = {:%s} AND [[__tm.recordRef]] = {:%s}%s)",
+		TeamMembershipsTableName,
+		teamIdentifier,
+		collectionPlaceholder,
+		recordPlaceholder,
+		roleCondition,
+	)
+	result.NoCoalesce = true
+
+	return result, nil
+}
+
+// resolveRequestFlagField resolves the @request.flags.* feature flag evaluation
+// (the assigned variant for variant flags or TRUE/FALSE otherwise).
+//
+// Missing flags are resolved as FALSE.
+func (r *RecordFieldResolver) resolveRequestFlagField(key string) (*search.ResolverResult, error) {
+	evaluation, err := r.app.EvaluateFeatureFlag(key, r.requestInfo)
+	if err != nil {
+		r.app.Logger().Debug("resolveRequestFlagField graceful fallback", "key", key, "error", err.Error())
+		return &search.ResolverResult{Identifier: "FALSE"}, nil
+	}
+
+	if evaluation.Variant != "" {
+		placeholder := "f" + security.PseudorandomString(8)
+
+		return &search.ResolverResult{
+			Identifier: "{:" + placeholder + "}",
+			Params:     dbx.Params{placeholder: evaluation.Variant},
+		}, nil
+	}
+
+	if evaluation.Enabled {
+		return &search.ResolverResult{Identifier: "TRUE"}, nil
+	}
+
+	return &search.ResolverResult{Identifier: "FALSE"}, nil
+}
+
 func (r *RecordFieldResolver) resolveStaticRequestField(path ...string) (*search.ResolverResult, error) {
 	if len(path) == 0 {
 		return nil, errors.New("at least one path key should be provided")
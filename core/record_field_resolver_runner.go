@@ -23,6 +23,9 @@ const maxNestedRels = 6
 
 // list of auth filter fields that don't require join with the auth
 // collection or any other extra checks to be resolved.
+// requestAuthSessionAge is the name of the virtual @request.auth.* session age field.
+const requestAuthSessionAge = "sessionAge"
+
 var plainRequestAuthFields = map[string]struct{}{
 	"@request.auth." + FieldNameId:              {},
 	"@request.auth." + FieldNameCollectionId:    {},
@@ -89,6 +92,10 @@ func (r *runner) run() (*search.ResolverResult, error) {
 			return r.processRequestAuthField()
 		}
 
+		if strings.HasPrefix(r.fieldName, "@request.flags.") && len(r.activeProps) == 3 {
+			return r.resolver.resolveRequestFlagField(r.activeProps[2])
+		}
+
 		if strings.HasPrefix(r.fieldName, "@request.body.") && len(r.activeProps) > 2 {
 			name, modifier, err := splitModifier(r.activeProps[2])
 			if err != nil {
@@ -207,9 +214,17 @@ func (r *runner) processRequestAuthField() (*search.ResolverResult, error) {
 		return r.resolver.resolveStaticRequestField(r.activeProps[1:]...)
 	}
 
+	collection := r.resolver.requestInfo.Auth.Collection()
+
+	// the seconds since the original authentication
+	// (unless there is an auth collection field with the same name)
+	// ---
+	if r.fieldName == "@request.auth."+requestAuthSessionAge && collection.Fields.GetByName(requestAuthSessionAge) == nil {
+		return r.resolver.resolveStaticRequestField(r.activeProps[1:]...)
+	}
+
 	// resolve the auth collection field
 	// ---
-	collection := r.resolver.requestInfo.Auth.Collection()
 
 	r.activeCollectionName = collection.Name
 	r.activeTableAlias = "__auth_" + inflector.Columnify(r.activeCollectionName)
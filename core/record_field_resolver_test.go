@@ -6,9 +6,11 @@ import (
 	"slices"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/geoip"
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/types"
@@ -28,8 +30,8 @@ func TestRecordFieldResolverAllowedFields(t *testing.T) {
 	r := core.NewRecordFieldResolver(app, collection, nil, false)
 
 	fields := r.AllowedFields()
-	if len(fields) != 8 {
-		t.Fatalf("Expected %d original allowed fields, got %d", 8, len(fields))
+	if len(fields) != 13 {
+		t.Fatalf("Expected %d original allowed fields, got %d", 13, len(fields))
 	}
 
 	// change the allowed fields
@@ -705,7 +707,18 @@ func TestRecordFieldResolverResolveStaticRequestInfoFields(t *testing.T) {
 		Headers: map[string]string{
 			"d": "789",
 		},
+		Custom: map[string]any{
+			"tenant": "abc",
+			"nested": map[string]any{"level": 2},
+		},
 		Auth: authRecord,
+		IP:   "127.0.0.1",
+		Geo:  &geoip.Info{Country: "BG", ASN: 13335, ASNOrg: "test_org"},
+		Share: &core.RecordShare{
+			CollectionRef: "share_collection",
+			RecordRef:     "share_record",
+			Scope:         core.RecordShareScopeComment,
+		},
 	}
 
 	r := core.NewRecordFieldResolver(app, collection, requestInfo, true)
@@ -750,6 +763,22 @@ func TestRecordFieldResolverResolveStaticRequestInfoFields(t *testing.T) {
 		{"@request.body.raw_json_arr1.3", false, `NULL`},
 		{"@request.body.raw_json_arr2.0.a", false, `123`},
 		{"@request.body.raw_json_arr2.0.b", false, `NULL`},
+		{"@request.geo.country", false, `"BG"`},
+		{"@request.geo.asn", false, `13335`},
+		{"@request.geo.asnOrg", false, `"test_org"`},
+		{"@request.geo.missing", false, ``},
+		{"@request.share", true, ``},
+		{"@request.share.collectionId", false, `"share_collection"`},
+		{"@request.share.recordId", false, `"share_record"`},
+		{"@request.share.scope", false, `"comment"`},
+		{"@request.share.missing", false, ``},
+		{"@request.ip", false, `"127.0.0.1"`},
+		{"@request.ip.missing", true, ``},
+		{"@request.custom", true, ``},
+		{"@request.custom.tenant", false, `"abc"`},
+		{"@request.custom.nested.level", false, `2`},
+		{"@request.custom.missing", false, ``},
+		{"@request.auth.sessionAge", false, `NULL`}, // no auth time
 	}
 
 	for _, s := range scenarios {
@@ -806,3 +835,139 @@ func TestRecordFieldResolverResolveStaticRequestInfoFields(t *testing.T) {
 		t.Fatalf("Expected the original authRecord email to not be exported, got %q", v)
 	}
 }
+
+func TestRecordFieldResolverResolveRequestAuthSessionAge(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authRecord, err := app.FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestInfo := &core.RequestInfo{
+		Auth:     authRecord,
+		AuthTime: types.NowDateTime().Add(-100 * time.Second),
+	}
+
+	t.Run("virtual field", func(t *testing.T) {
+		r, err := core.NewRecordFieldResolver(app, collection, requestInfo, true).Resolve("@request.auth.sessionAge")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(r.Params) != 1 {
+			t.Fatalf("Expected 1 placeholder parameter, got %v", r.Params)
+		}
+
+		for _, v := range r.Params {
+			if age, _ := v.(int64); age < 100 || age > 102 {
+				t.Fatalf("Expected ~100 seconds session age, got %v", v)
+			}
+		}
+	})
+
+	t.Run("auth collection field precedence", func(t *testing.T) {
+		authRecord.Collection().Fields.Add(&core.NumberField{Name: "sessionAge"})
+		if err := app.Save(authRecord.Collection()); err != nil {
+			t.Fatal(err)
+		}
+
+		r, err := core.NewRecordFieldResolver(app, collection, requestInfo, true).Resolve("@request.auth.sessionAge")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "[[__auth_users.sessionAge]]"
+		if r.Identifier != expected {
+			t.Fatalf("Expected identifier %q, got %q", expected, r.Identifier)
+		}
+	})
+}
+
+func TestRecordFieldResolverResolveRequestFlags(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authRecord, err := app.FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	flags := []*core.FeatureFlag{
+		{Key: "on", Enabled: true, Rollout: 100},
+		{Key: "off", Enabled: false, Rollout: 100},
+		{Key: "variant", Enabled: true, Rollout: 100, Variants: []string{"b"}},
+	}
+	for _, flag := range flags {
+		if err := app.Save(flag); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	r := core.NewRecordFieldResolver(app, collection, &core.RequestInfo{Auth: authRecord}, true)
+
+	scenarios := []struct {
+		fieldName          string
+		expectError        bool
+		expectedIdentifier string
+		expectedParam      any
+	}{
+		{"@request.flags", true, "", nil},
+		{"@request.flags.on.nested", true, "", nil},
+		{"@request.flags.on", false, "TRUE", nil},
+		{"@request.flags.off", false, "FALSE", nil},
+		{"@request.flags.missing", false, "FALSE", nil},
+		{"@request.flags.variant", false, "", "b"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.fieldName, func(t *testing.T) {
+			result, err := r.Resolve(s.fieldName)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if s.expectedParam == nil {
+				if result.Identifier != s.expectedIdentifier {
+					t.Fatalf("Expected identifier %q, got %q", s.expectedIdentifier, result.Identifier)
+				}
+				return
+			}
+
+			if len(result.Params) != 1 {
+				t.Fatalf("Expected 1 placeholder parameter, got %v", result.Params)
+			}
+
+			for k, v := range result.Params {
+				if result.Identifier != "{:"+k+"}" {
+					t.Fatalf("Expected identifier %q, got %q", "{:"+k+"}", result.Identifier)
+				}
+
+				if v != s.expectedParam {
+					t.Fatalf("Expected param %v, got %v", s.expectedParam, v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,287 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const RecordLocksTableName = "_recordLocks"
+
+// ErrRecordLocked is returned when trying to acquire or release
+// a record lock that is held by another owner.
+var ErrRecordLocked = errors.New("the record is locked by another owner")
+
+var (
+	_ Model         = (*RecordLock)(nil)
+	_ PostValidator = (*RecordLock)(nil)
+)
+
+// RecordLock defines an advisory record lock that could be used
+// to prevent concurrent edits of the same record (e.g. in dashboards).
+//
+// The lock is not enforced by the records API
+// and it is automatically released after its Expires date.
+type RecordLock struct {
+	BaseModel
+
+	CollectionRef      string         `db:"collectionRef" json:"collectionRef"`
+	RecordRef          string         `db:"recordRef" json:"recordRef"`
+	OwnerCollectionRef string         `db:"ownerCollectionRef" json:"ownerCollectionRef"`
+	OwnerRef           string         `db:"ownerRef" json:"ownerRef"`
+	Expires            types.DateTime `db:"expires" json:"expires"`
+	Created            types.DateTime `db:"created" json:"created"`
+	Updated            types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the RecordLock model table name.
+func (m *RecordLock) TableName() string {
+	return RecordLocksTableName
+}
+
+// IsExpired reports whether the lock has expired.
+func (m *RecordLock) IsExpired() bool {
+	return !m.Expires.Time().After(time.Now())
+}
+
+// IsOwnedBy reports whether the lock belongs to the provided auth record.
+func (m *RecordLock) IsOwnedBy(authRecord *Record) bool {
+	return authRecord != nil &&
+		m.OwnerRef == authRecord.Id &&
+		m.OwnerCollectionRef == authRecord.Collection().Id
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *RecordLock) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(&m.CollectionRef, validation.Required, validation.By(validateCollectionId(app))),
+		validation.Field(&m.RecordRef, validation.Required, validation.By(validateRecordId(app, m.CollectionRef))),
+		validation.Field(&m.OwnerCollectionRef, validation.Required, validation.By(validateCollectionId(app, CollectionTypeAuth))),
+		validation.Field(&m.OwnerRef, validation.Required, validation.By(validateRecordId(app, m.OwnerCollectionRef))),
+		validation.Field(&m.Expires, validation.By(validateDatetimeRequired)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// RecordLockQuery returns a new RecordLock select query.
+func (app *BaseApp) RecordLockQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&RecordLock{})
+}
+
+// FindRecordLock returns the active (aka. non-expired) lock of the provided record.
+//
+// Returns [sql.ErrNoRows] if the record is not locked.
+func (app *BaseApp) FindRecordLock(record *Record) (*RecordLock, error) {
+	result := &RecordLock{}
+
+	err := app.RecordLockQuery().
+		AndWhere(dbx.HashExp{
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+		}).
+		AndWhere(activeRecordLockExpr()).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRecordLocks returns the active (aka. non-expired) locks
+// of the specified collection records.
+func (app *BaseApp) FindRecordLocks(collection *Collection, recordIds ...string) ([]*RecordLock, error) {
+	result := []*RecordLock{}
+
+	if len(recordIds) == 0 {
+		return result, nil
+	}
+
+	ids := make([]any, len(recordIds))
+	for i, id := range recordIds {
+		ids[i] = id
+	}
+
+	err := app.RecordLockQuery().
+		AndWhere(dbx.HashExp{"collectionRef": collection.Id}).
+		AndWhere(dbx.In("recordRef", ids...)).
+		AndWhere(activeRecordLockExpr()).
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AcquireRecordLock locks the provided record for the specified owner
+// auth record until ttl passes.
+//
+// If the record is already locked by the same owner, the lock expiration is renewed.
+//
+// Returns [ErrRecordLocked] if the record has an active lock held by another owner.
+func (app *BaseApp) AcquireRecordLock(record *Record, owner *Record, ttl time.Duration) (*RecordLock, error) {
+	if owner == nil {
+		return nil, errors.New("missing lock owner")
+	}
+
+	if ttl <= 0 {
+		return nil, errors.New("the lock ttl must be positive")
+	}
+
+	lock := &RecordLock{}
+
+	err := app.RunInTransaction(func(txApp App) error {
+		err := txApp.RecordLockQuery().
+			AndWhere(dbx.HashExp{
+				"collectionRef": record.Collection().Id,
+				"recordRef":     record.Id,
+			}).
+			Limit(1).
+			One(lock)
+		if err != nil {
+			// new lock
+			lock = &RecordLock{
+				CollectionRef: record.Collection().Id,
+				RecordRef:     record.Id,
+			}
+		} else if !lock.IsExpired() && !lock.IsOwnedBy(owner) {
+			return ErrRecordLocked
+		}
+
+		lock.OwnerCollectionRef = owner.Collection().Id
+		lock.OwnerRef = owner.Id
+		lock.Expires = types.NowDateTime().Add(ttl)
+
+		return txApp.Save(lock)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return lock, nil
+}
+
+// ReleaseRecordLock releases the lock of the provided record (if any).
+//
+// Set owner to nil to force release the lock regardless of its owner.
+//
+// Returns [ErrRecordLocked] if the record has an active lock held by another owner.
+func (app *BaseApp) ReleaseRecordLock(record *Record, owner *Record) error {
+	return app.RunInTransaction(func(txApp App) error {
+		lock := &RecordLock{}
+
+		err := txApp.RecordLockQuery().
+			AndWhere(dbx.HashExp{
+				"collectionRef": record.Collection().Id,
+				"recordRef":     record.Id,
+			}).
+			Limit(1).
+			One(lock)
+		if err != nil {
+			return nil // not locked
+		}
+
+		if owner != nil && !lock.IsExpired() && !lock.IsOwnedBy(owner) {
+			return ErrRecordLocked
+		}
+
+		return txApp.Delete(lock)
+	})
+}
+
+// DeleteExpiredRecordLocks deletes all expired record locks.
+func (app *BaseApp) DeleteExpiredRecordLocks() error {
+	_, err := app.NonconcurrentDB().Delete(RecordLocksTableName, dbx.Not(activeRecordLockExpr())).Execute()
+
+	return err
+}
+
+func activeRecordLockExpr() dbx.Expression {
+	return dbx.NewExp("[[expires]] > {:now}", dbx.Params{"now": types.NowDateTime().String()})
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerRecordLockHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*RecordLock); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*RecordLock); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// delete the collection, record and owner locks
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(RecordLocksTableName, dbx.Or(
+				dbx.HashExp{"collectionRef": e.Collection.Id},
+				dbx.HashExp{"ownerCollectionRef": e.Collection.Id},
+			)).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(RecordLocksTableName, dbx.Or(
+				dbx.HashExp{
+					"collectionRef": e.Record.Collection().Id,
+					"recordRef":     e.Record.Id,
+				},
+				dbx.HashExp{
+					"ownerCollectionRef": e.Record.Collection().Id,
+					"ownerRef":           e.Record.Id,
+				},
+			)).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.Cron().Add("__pbRecordLocksCleanup__", "0 * * * *", func() {
+		if err := app.DeleteExpiredRecordLocks(); err != nil {
+			app.Logger().Warn("Failed to delete expired record locks", "error", err)
+		}
+	})
+}
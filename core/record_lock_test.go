@@ -0,0 +1,230 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestRecordLockAcquireAndRelease(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRecordLock(record); err == nil {
+		t.Fatal("Expected the record to not be locked")
+	}
+
+	lock, err := app.AcquireRecordLock(record, user1, 1*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !lock.IsOwnedBy(user1) || lock.IsExpired() {
+		t.Fatalf("Expected active lock owned by user1, got %v", lock)
+	}
+
+	// renew
+	renewed, err := app.AcquireRecordLock(record, user1, 1*time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renewed.Id != lock.Id || !renewed.Expires.Time().After(lock.Expires.Time()) {
+		t.Fatalf("Expected the lock to be renewed, got %v (previous %v)", renewed, lock)
+	}
+
+	// locked by another owner
+	if _, err := app.AcquireRecordLock(record, user2, 1*time.Minute); !errors.Is(err, core.ErrRecordLocked) {
+		t.Fatalf("Expected ErrRecordLocked, got %v", err)
+	}
+	if err := app.ReleaseRecordLock(record, user2); !errors.Is(err, core.ErrRecordLocked) {
+		t.Fatalf("Expected ErrRecordLocked, got %v", err)
+	}
+
+	found, err := app.FindRecordLock(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !found.IsOwnedBy(user1) {
+		t.Fatalf("Expected the lock to be owned by user1, got %v", found)
+	}
+
+	if err := app.ReleaseRecordLock(record, user1); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.FindRecordLock(record); err == nil {
+		t.Fatal("Expected the lock to be released")
+	}
+
+	// force release
+	if _, err := app.AcquireRecordLock(record, user2, 1*time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.ReleaseRecordLock(record, nil); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.FindRecordLock(record); err == nil {
+		t.Fatal("Expected the lock to be force released")
+	}
+
+	// releasing a non-locked record is no-op
+	if err := app.ReleaseRecordLock(record, user1); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+}
+
+func TestRecordLockExpired(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lock, err := app.AcquireRecordLock(record, user1, 1*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// manually expire
+	lock.Expires = types.NowDateTime().Add(-1 * time.Second)
+	if err := app.Save(lock); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRecordLock(record); err == nil {
+		t.Fatal("Expected the expired lock to be ignored")
+	}
+
+	locks, err := app.FindRecordLocks(record.Collection(), record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locks) != 0 {
+		t.Fatalf("Expected no active locks, got %d", len(locks))
+	}
+
+	// take over
+	taken, err := app.AcquireRecordLock(record, user2, 1*time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !taken.IsOwnedBy(user2) {
+		t.Fatalf("Expected the lock to be owned by user2, got %v", taken)
+	}
+
+	// expire again and cleanup
+	taken.Expires = types.NowDateTime().Add(-1 * time.Second)
+	if err := app.Save(taken); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.DeleteExpiredRecordLocks(); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int
+	if err := app.RecordLockQuery().Select("count(*)").Row(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected the expired locks to be deleted, got %d", total)
+	}
+}
+
+func TestRecordLockDeleteCleanup(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record1, err := app.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record2, err := app.FindRecordById("demo2", "0yxhwia2amd8gec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user1, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindAuthRecordByEmail("users", "test2@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.AcquireRecordLock(record1, user1, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.AcquireRecordLock(record2, user2, time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	locks, err := app.FindRecordLocks(record1.Collection(), record1.Id, record2.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locks) != 2 {
+		t.Fatalf("Expected 2 locks, got %d", len(locks))
+	}
+
+	// delete the locked record
+	if err := app.Delete(record1); err != nil {
+		t.Fatal(err)
+	}
+
+	// delete the lock owner
+	if err := app.Delete(user2); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int
+	err = app.RecordLockQuery().
+		Select("count(*)").
+		AndWhere(dbx.HashExp{"collectionRef": record1.Collection().Id}).
+		Row(&total)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected all locks to be deleted, got %d", total)
+	}
+}
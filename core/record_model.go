@@ -1419,8 +1419,16 @@ func onRecordValidate(e *RecordEvent) error {
 		}
 	}
 
+	// check the auth collection email domain restrictions on create and email change
+	if _, ok := errs[FieldNameEmail]; !ok && e.Record.Collection().IsAuth() &&
+		(e.Record.IsNew() || e.Record.Email() != e.Record.Original().Email()) {
+		if err := e.Record.Collection().EmailDomains.CheckEmail(e.Context, e.Record.Email()); err != nil {
+			errs[FieldNameEmail] = err
+		}
+	}
+
 	if len(errs) > 0 {
-		return errs
+		return e.Record.Collection().applyErrorMessages(errs)
 	}
 
 	return e.Next()
@@ -1440,6 +1448,20 @@ func onRecordSaveExecute(e *RecordEvent) error {
 					lastSavedRecord.Email() != e.Record.Email()) {
 				e.Record.RefreshTokenKey()
 			}
+
+			// reset the phone verified state on phone change
+			// (unless it was explicitly changed together with the phone)
+			for _, f := range e.Record.Collection().Fields {
+				if f.Type() != FieldTypePhone {
+					continue
+				}
+
+				name := f.GetName()
+				if lastSavedRecord.GetString(name) != e.Record.GetString(name) &&
+					lastSavedRecord.PhoneVerified(name) == e.Record.PhoneVerified(name) {
+					e.Record.SetPhoneVerified(name, false)
+				}
+			}
 		}
 
 		// cross-check that the auth record id is unique across all auth collections.
@@ -1465,11 +1487,11 @@ func onRecordSaveExecute(e *RecordEvent) error {
 		return nil
 	}
 
-	return validators.NormalizeUniqueIndexError(
+	return e.Record.Collection().applyErrorMessages(validators.NormalizeUniqueIndexError(
 		err,
 		e.Record.Collection().Name,
 		e.Record.Collection().Fields.FieldNames(),
-	)
+	))
 }
 
 func onRecordDeleteExecute(e *RecordEvent) error {
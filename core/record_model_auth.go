@@ -1,6 +1,9 @@
 package core
 
-import "github.com/pocketbase/pocketbase/tools/security"
+import (
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
 
 // Email returns the "email" record field value (usually available with Auth collections).
 func (m *Record) Email() string {
@@ -32,6 +35,18 @@ func (m *Record) SetVerified(verified bool) {
 	m.Set(FieldNameVerified, verified)
 }
 
+// PhoneVerified returns the verified state of the specified auth record phone field
+// (aka. the value of its "{phoneField}Verified" companion field).
+func (m *Record) PhoneVerified(phoneField string) bool {
+	return m.GetBool(phoneField + "Verified")
+}
+
+// SetPhoneVerified sets the verified state of the specified auth record phone field
+// (aka. the value of its "{phoneField}Verified" companion field).
+func (m *Record) SetPhoneVerified(phoneField string, verified bool) {
+	m.Set(phoneField+"Verified", verified)
+}
+
 // TokenKey returns the "tokenKey" record field value (usually available with Auth collections).
 func (m *Record) TokenKey() string {
 	return m.GetString(FieldNameTokenKey)
@@ -83,3 +98,33 @@ func (m *Record) ValidatePassword(password string) bool {
 
 	return pv.Validate(password)
 }
+
+// MissingProfileFields returns the names of the auth collection
+// [ProfileCompletionConfig.Fields] that are still empty for the current record.
+//
+// Returns nil if the profile completion is not enabled for the record collection.
+func (m *Record) MissingProfileFields() []string {
+	config := m.Collection().ProfileCompletion
+	if !m.Collection().IsAuth() || !config.Enabled {
+		return nil
+	}
+
+	var missing []string
+
+	for _, name := range config.Fields {
+		raw := m.GetRaw(name)
+
+		var isEmpty bool
+		if z, ok := raw.(interface{ IsZero() bool }); ok {
+			isEmpty = z.IsZero()
+		} else {
+			isEmpty = validation.IsEmpty(raw)
+		}
+
+		if isEmpty {
+			missing = append(missing, name)
+		}
+	}
+
+	return missing
+}
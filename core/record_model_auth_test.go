@@ -2,6 +2,7 @@ package core_test
 
 import (
 	"context"
+	"slices"
 	"testing"
 
 	"github.com/pocketbase/pocketbase/core"
@@ -52,6 +53,27 @@ func TestRecordVerified(t *testing.T) {
 	}
 }
 
+func TestRecordPhoneVerified(t *testing.T) {
+	collection := core.NewAuthCollection("test")
+	collection.Fields.Add(&core.BoolField{Name: "phoneVerified"})
+
+	record := core.NewRecord(collection)
+
+	if record.PhoneVerified("phone") != false {
+		t.Fatalf("Expected phone verified %v, got %v", false, record.PhoneVerified("phone"))
+	}
+
+	record.SetPhoneVerified("phone", true)
+
+	if record.PhoneVerified("phone") != true {
+		t.Fatalf("Expected phone verified %v, got %v", true, record.PhoneVerified("phone"))
+	}
+
+	if record.GetBool("phoneVerified") != true {
+		t.Fatalf("Expected phoneVerified field value %v, got %v", true, record.GetBool("phoneVerified"))
+	}
+}
+
 func TestRecordTokenKey(t *testing.T) {
 	record := core.NewRecord(core.NewAuthCollection("test"))
 
@@ -158,3 +180,50 @@ func TestRecordSetRandomPassword(t *testing.T) {
 		t.Fatalf("Expected password field plain value validators to be ignored, got %v", err)
 	}
 }
+
+func TestRecordMissingProfileFields(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1Record, err := app.FindRecordById("demo1", "imy661ixudk5izi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := demo1Record.MissingProfileFields(); v != nil {
+		t.Fatalf("Expected nil for non-auth record, got %v", v)
+	}
+
+	user.Collection().ProfileCompletion.Fields = []string{"name", "avatar", "verified"}
+
+	if v := user.MissingProfileFields(); v != nil {
+		t.Fatalf("Expected nil for disabled profile completion, got %v", v)
+	}
+
+	user.Collection().ProfileCompletion.Enabled = true
+
+	user.Set("name", "")
+	user.Set("avatar", "")
+	user.Set("verified", false)
+	if v := user.MissingProfileFields(); !slices.Equal(v, []string{"name", "avatar", "verified"}) {
+		t.Fatalf("Expected all fields to be missing, got %v", v)
+	}
+
+	user.Set("name", "test")
+	user.Set("verified", true)
+	if v := user.MissingProfileFields(); !slices.Equal(v, []string{"avatar"}) {
+		t.Fatalf("Expected only the avatar to be missing, got %v", v)
+	}
+
+	user.Set("avatar", "test.png")
+	if v := user.MissingProfileFields(); len(v) != 0 {
+		t.Fatalf("Expected no missing fields, got %v", v)
+	}
+}
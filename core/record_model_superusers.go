@@ -95,6 +95,9 @@ func (app *BaseApp) registerSuperuserHooks() {
 			e.Collection.OAuth2.Enabled = false
 			e.Collection.OAuth2.Providers = nil
 
+			// same for the LDAP auth
+			e.Collection.LDAPAuth.Enabled = false
+
 			// force password auth
 			e.Collection.PasswordAuth.Enabled = true
 
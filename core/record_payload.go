@@ -0,0 +1,59 @@
+package core
+
+import (
+	"errors"
+	"slices"
+)
+
+const (
+	// RecordPayloadVersion1 is the initial record event payload schema version
+	// (the record public export with its email always visible).
+	RecordPayloadVersion1 = 1
+
+	// LatestRecordPayloadVersion is the record event payload schema version
+	// used when the consumer hasn't pinned a specific one.
+	LatestRecordPayloadVersion = RecordPayloadVersion1
+)
+
+// RecordPayloadVersions is the list of the supported record event payload schema versions.
+var RecordPayloadVersions = []int{RecordPayloadVersion1}
+
+var ErrUnsupportedRecordPayloadVersion = errors.New("unsupported record payload version")
+
+// ResolveRecordPayloadVersion returns the provided record event payload
+// schema version or [LatestRecordPayloadVersion] if version is empty (0).
+//
+// Returns [ErrUnsupportedRecordPayloadVersion] if the version is not one of [RecordPayloadVersions].
+func ResolveRecordPayloadVersion(version int) (int, error) {
+	if version == 0 {
+		return LatestRecordPayloadVersion, nil
+	}
+
+	if !slices.Contains(RecordPayloadVersions, version) {
+		return 0, ErrUnsupportedRecordPayloadVersion
+	}
+
+	return version, nil
+}
+
+// RecordPayloadData returns the JSON serializable record data in the
+// format of the specified payload schema version.
+//
+// It is intended to be used by the external integrations (changefeed, event sinks, custom hooks, etc.)
+// so that the emitted payloads remain the same for the pinned version
+// regardless of the future record serialization changes.
+func RecordPayloadData(version int, record *Record) (any, error) {
+	version, err := ResolveRecordPayloadVersion(version)
+	if err != nil {
+		return nil, err
+	}
+
+	switch version {
+	case RecordPayloadVersion1:
+		export := record.Fresh()
+		export.IgnoreEmailVisibility(true)
+		return export.PublicExport(), nil
+	default:
+		return nil, ErrUnsupportedRecordPayloadVersion
+	}
+}
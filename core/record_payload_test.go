@@ -0,0 +1,96 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestResolveRecordPayloadVersion(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		version     int
+		expected    int
+		expectError bool
+	}{
+		{0, core.LatestRecordPayloadVersion, false},
+		{core.RecordPayloadVersion1, core.RecordPayloadVersion1, false},
+		{-1, 0, true},
+		{999, 0, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%d", s.version), func(t *testing.T) {
+			result, err := core.ResolveRecordPayloadVersion(s.version)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr && !errors.Is(err, core.ErrUnsupportedRecordPayloadVersion) {
+				t.Fatalf("Expected ErrUnsupportedRecordPayloadVersion, got %v", err)
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected version %d, got %d", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestRecordPayloadData(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.WithCustomData(true)
+	user.Set("custom", 123)
+
+	if _, err := core.RecordPayloadData(999, user); !errors.Is(err, core.ErrUnsupportedRecordPayloadVersion) {
+		t.Fatalf("Expected ErrUnsupportedRecordPayloadVersion, got %v", err)
+	}
+
+	for _, version := range []int{0, core.RecordPayloadVersion1} {
+		t.Run(fmt.Sprintf("%d", version), func(t *testing.T) {
+			data, err := core.RecordPayloadData(version, user)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			raw, err := json.Marshal(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			result := map[string]any{}
+			if err := json.Unmarshal(raw, &result); err != nil {
+				t.Fatal(err)
+			}
+
+			if result["id"] != user.Id {
+				t.Fatalf("Expected id %q, got %v", user.Id, result["id"])
+			}
+
+			if result["email"] != user.Email() {
+				t.Fatalf("Expected email %q to be always visible, got %v", user.Email(), result["email"])
+			}
+
+			for _, field := range []string{core.FieldNamePassword, core.FieldNameTokenKey, "custom"} {
+				if _, ok := result[field]; ok {
+					t.Fatalf("Expected %q to not be exported, got\n%s", field, raw)
+				}
+			}
+		})
+	}
+}
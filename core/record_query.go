@@ -14,6 +14,7 @@ import (
 	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/search"
 	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/sms"
 )
 
 var recordProxyType = reflect.TypeOf((*RecordProxy)(nil)).Elem()
@@ -164,6 +165,37 @@ func (app *BaseApp) RecordQuery(collectionModelOrIdentifier any) *dbx.SelectQuer
 	})
 }
 
+// EachRecord executes the provided collection records query and calls fn
+// for each record as it is read from the result rows
+// (aka. without loading the entire result set in memory).
+//
+// The iteration stops on the first fn error.
+func EachRecord(collection *Collection, query *dbx.SelectQuery, fn func(record *Record) error) error {
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		row := dbx.NullStringMap{}
+		if err := rows.ScanMap(row); err != nil {
+			return err
+		}
+
+		record, err := newRecordFromNullStringMap(collection, row)
+		if err != nil {
+			return err
+		}
+
+		if err := fn(record); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
 func resolveRecordOneHook(collection *Collection, op func(dst any) error) (*Record, error) {
 	data := dbx.NullStringMap{}
 	if err := op(&data); err != nil {
@@ -501,7 +533,7 @@ func (app *BaseApp) FindAuthRecordByToken(token string, validTypes ...string) (*
 
 	var baseTokenKey string
 	switch tokenType {
-	case TokenTypeAuth:
+	case TokenTypeAuth, TokenTypeScoped, TokenTypeFeed:
 		baseTokenKey = record.Collection().AuthToken.Secret
 	case TokenTypeFile:
 		baseTokenKey = record.Collection().FileToken.Secret
@@ -565,11 +597,63 @@ func (app *BaseApp) FindAuthRecordByEmail(collectionModelOrIdentifier any, email
 	return record, nil
 }
 
+// FindAuthRecordByPhone finds the auth record associated with the provided phone number.
+//
+// The phone is normalized to E.164 before the lookup and only the
+// [PhoneField] fields with a single column UNIQUE index are checked.
+//
+// Returns an error if it is not an auth collection or the record is not found.
+func (app *BaseApp) FindAuthRecordByPhone(collectionModelOrIdentifier any, phone string) (*Record, error) {
+	collection, err := getCollectionByModelOrIdentifier(app, collectionModelOrIdentifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch auth collection: %w", err)
+	}
+
+	if !collection.IsAuth() {
+		return nil, fmt.Errorf("%q is not an auth collection", collection.Name)
+	}
+
+	phone, err = sms.NormalizePhone(phone)
+	if err != nil {
+		return nil, sql.ErrNoRows
+	}
+
+	exprs := []dbx.Expression{}
+	for _, field := range collection.Fields {
+		if field.Type() != FieldTypePhone {
+			continue
+		}
+
+		if _, ok := dbutils.FindSingleColumnUniqueIndex(collection.Indexes, field.GetName()); ok {
+			exprs = append(exprs, dbx.HashExp{field.GetName(): phone})
+		}
+	}
+
+	if len(exprs) == 0 {
+		return nil, sql.ErrNoRows
+	}
+
+	record := &Record{}
+
+	err = app.RecordQuery(collection).
+		AndWhere(dbx.Or(exprs...)).
+		Limit(1).
+		One(record)
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
 // CanAccessRecord checks if a record is allowed to be accessed by the
 // specified requestInfo and accessRule.
 //
 // Rule and db checks are ignored in case requestInfo.Auth is a superuser.
 //
+// If optPoliciesAction is specified (eg. [CollectionPolicyActionView]),
+// the record collection policies of the action are also applied (see [CollectionPolicy]).
+//
 // The returned error indicate that something unexpected happened during
 // the check (eg. invalid rule or db query error).
 //
@@ -580,10 +664,11 @@ func (app *BaseApp) FindAuthRecordByEmail(collectionModelOrIdentifier any, email
 //	requestInfo, _ := e.RequestInfo()
 //	record, _ := app.FindRecordById("example", "RECORD_ID")
 //	rule := types.Pointer("@request.auth.id != '' || status = 'public'")
-//	// ... or use one of the record collection's rule, eg. record.Collection().ViewRule
-//
 //	if ok, _ := app.CanAccessRecord(record, requestInfo, rule); ok { ... }
-func (app *BaseApp) CanAccessRecord(record *Record, requestInfo *RequestInfo, accessRule *string) (bool, error) {
+//
+//	// or use one of the record collection's rule together with its policies action
+//	if ok, _ := app.CanAccessRecord(record, requestInfo, record.Collection().ViewRule, core.CollectionPolicyActionView); ok { ... }
+func (app *BaseApp) CanAccessRecord(record *Record, requestInfo *RequestInfo, accessRule *string, optPoliciesAction ...string) (bool, error) {
 	// superusers can access everything
 	if requestInfo.HasSuperuserAuth() {
 		return true, nil
@@ -594,8 +679,13 @@ func (app *BaseApp) CanAccessRecord(record *Record, requestInfo *RequestInfo, ac
 		return false, nil
 	}
 
+	var policiesAction string
+	if len(optPoliciesAction) > 0 && len(record.Collection().ActionPolicies(optPoliciesAction[0])) > 0 {
+		policiesAction = optPoliciesAction[0]
+	}
+
 	// empty public rule, aka. everyone can access
-	if *accessRule == "" {
+	if *accessRule == "" && policiesAction == "" {
 		return true, nil
 	}
 
@@ -605,9 +695,86 @@ func (app *BaseApp) CanAccessRecord(record *Record, requestInfo *RequestInfo, ac
 		Select("(1)").
 		AndWhere(dbx.HashExp{record.Collection().Name + ".id": record.Id})
 
-	// parse and apply the access rule filter
 	resolver := NewRecordFieldResolver(app, record.Collection(), requestInfo, true)
-	expr, err := search.FilterData(*accessRule).BuildExpr(resolver)
+
+	// parse and apply the access rule filter
+	if *accessRule != "" {
+		expr, err := search.FilterData(*accessRule).BuildExpr(resolver)
+		if err != nil {
+			return false, err
+		}
+		query.AndWhere(expr)
+	}
+
+	if policiesAction != "" {
+		expr, err := record.Collection().BuildPoliciesExpr(policiesAction, resolver)
+		if err != nil {
+			return false, err
+		}
+		if expr != nil {
+			query.AndWhere(expr)
+		}
+	}
+
+	resolver.UpdateQuery(query)
+
+	err := query.Limit(1).Row(&exists)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	return exists > 0, nil
+}
+
+// CanAccessRealtimeTopic checks if the custom realtime subscription topic
+// is allowed to be subscribed by the specified requestInfo based on the
+// first matching [RealtimeConfig.Topics] pattern rule.
+//
+// Topics that don't match any of the configured patterns are always allowed.
+//
+// Rule and db checks are ignored in case requestInfo.Auth is a superuser.
+//
+// The method always return false on invalid rule or db query error.
+func (app *BaseApp) CanAccessRealtimeTopic(topic string, requestInfo *RequestInfo) (bool, error) {
+	config, params, ok := app.Settings().Realtime.FindTopic(topic)
+	if !ok {
+		return true, nil
+	}
+
+	// superusers can access everything
+	if requestInfo.HasSuperuserAuth() {
+		return true, nil
+	}
+
+	// only superusers can access this topic
+	if config.Rule == nil {
+		return false, nil
+	}
+
+	// empty public rule, aka. everyone can access
+	if *config.Rule == "" {
+		return true, nil
+	}
+
+	collection, err := app.FindCachedCollectionByNameOrId(config.Collection)
+	if err != nil {
+		return false, err
+	}
+
+	// replace the @topic.* variables with placeholders
+	placeholders := make(dbx.Params, len(params))
+	rule := realtimeTopicVarRegex.ReplaceAllStringFunc(*config.Rule, func(match string) string {
+		name := strings.TrimPrefix(match, "@topic.")
+		placeholders["topic_"+name] = params[name]
+		return "{:topic_" + name + "}"
+	})
+
+	var exists int
+
+	query := app.RecordQuery(collection).Select("(1)")
+
+	resolver := NewRecordFieldResolver(app, collection, requestInfo, true)
+	expr, err := search.FilterData(rule).BuildExpr(resolver, placeholders)
 	if err != nil {
 		return false, err
 	}
@@ -5,16 +5,46 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/dbutils"
 	"github.com/pocketbase/pocketbase/tools/list"
+	"github.com/pocketbase/pocketbase/tools/search"
 )
 
 // ExpandFetchFunc defines the function that is used to fetch the expanded relation records.
 type ExpandFetchFunc func(relCollection *Collection, relIds []string) ([]*Record, error)
 
+// ExpandOptions defines the optional per relation expand options
+// (e.g. "comments_via_post(filter=approved=true,sort=-created,limit=5)").
+//
+// The options are applied individually for each expanded record.
+//
+// Note that the Filter and Sort expressions can't reference
+// hidden fields and "@request.*" fields.
+type ExpandOptions struct {
+	// Filter is a filter expression that the relation records must satisfy.
+	Filter string
+
+	// Sort is a comma separated sort expression (e.g. "-created,title").
+	// If empty, the order of the relation field value is preserved.
+	Sort string
+
+	// Limit specifies the max number of expanded relation records per record.
+	// If zero, no limit is applied.
+	Limit int
+}
+
+// SplitExpands splits the provided comma separated expand string
+// ignoring the commas inside the expand options parenthesis and quotes
+// (e.g. "a(sort=-created,limit=5),b.c" -> ["a(sort=-created,limit=5)", "b.c"]).
+func SplitExpands(str string) []string {
+	return splitExpandOutside(str, ',')
+}
+
 // ExpandRecord expands the relations of a single Record model.
 //
 // If optFetchFunc is not set, then a default function will be used
@@ -71,7 +101,17 @@ func (app *BaseApp) expandRecords(records []*Record, expandPath string, fetchFun
 	var relField *RelationField
 	var relCollection *Collection
 
-	parts := strings.SplitN(expandPath, ".", 2)
+	parts := splitExpandOutside(expandPath, '.')
+	if len(parts) > 2 {
+		parts = []string{parts[0], strings.Join(parts[1:], ".")}
+	}
+
+	relName, options, err := parseExpandOptions(parts[0])
+	if err != nil {
+		return err
+	}
+	parts[0] = relName
+
 	var matches []string
 
 	// @todo remove the old syntax support
@@ -174,6 +214,16 @@ func (app *BaseApp) expandRecords(records []*Record, expandPath string, fetchFun
 		return relsErr
 	}
 
+	// apply the expand options filter and sort
+	// (the related records order is stored in relsOrder)
+	var relsOrder map[string]int
+	if options != nil && (options.Filter != "" || options.Sort != "") {
+		rels, relsOrder, err = app.filterExpandRels(relCollection, rels, options)
+		if err != nil {
+			return err
+		}
+	}
+
 	// expand nested fields
 	if len(parts) > 1 {
 		err := app.expandRecords(rels, parts[1], fetchFunc, recursionLevel+1)
@@ -204,6 +254,16 @@ func (app *BaseApp) expandRecords(records []*Record, expandPath string, fetchFun
 			}
 		}
 
+		if relsOrder != nil {
+			slices.SortStableFunc(validRels, func(a, b *Record) int {
+				return relsOrder[a.Id] - relsOrder[b.Id]
+			})
+		}
+
+		if options != nil && options.Limit > 0 && len(validRels) > options.Limit {
+			validRels = validRels[:options.Limit]
+		}
+
 		if len(validRels) == 0 {
 			continue // no valid relations
 		}
@@ -246,14 +306,191 @@ func (app *BaseApp) expandRecords(records []*Record, expandPath string, fetchFun
 	return nil
 }
 
+// filterExpandRels filters and sorts the provided relation records
+// according to the expand options.
+//
+// Returns the filtered records and a map with their sort position.
+func (app *BaseApp) filterExpandRels(relCollection *Collection, rels []*Record, options *ExpandOptions) ([]*Record, map[string]int, error) {
+	if len(rels) == 0 {
+		return rels, nil, nil
+	}
+
+	ids := make([]any, len(rels))
+	for i, rel := range rels {
+		ids[i] = rel.Id
+	}
+
+	idColumn := relCollection.Name + ".id"
+
+	q := app.ConcurrentDB().Select("[[" + idColumn + "]]").
+		From(relCollection.Name).
+		AndWhere(dbx.In(idColumn, ids...))
+
+	resolver := NewRecordFieldResolver(
+		app,
+		relCollection,
+		nil,   // no request data
+		false, // disallow searching hidden fields since the options are usually client submitted
+	)
+
+	if options.Filter != "" {
+		expr, err := search.FilterData(options.Filter).BuildExpr(resolver)
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid expand filter expression: %w", err)
+		}
+		q.AndWhere(expr)
+	}
+
+	if options.Sort != "" {
+		for _, sortField := range search.ParseSortFromString(options.Sort) {
+			expr, err := sortField.BuildExpr(resolver)
+			if err != nil {
+				return nil, nil, fmt.Errorf("invalid expand sort expression: %w", err)
+			}
+			if expr != "" {
+				q.AndOrderBy(expr)
+			}
+		}
+	}
+
+	if err := resolver.UpdateQuery(q); err != nil {
+		return nil, nil, err
+	}
+
+	var filteredIds []string
+	if err := q.Column(&filteredIds); err != nil {
+		return nil, nil, err
+	}
+
+	order := make(map[string]int, len(filteredIds))
+	for i, id := range filteredIds {
+		order[id] = i
+	}
+
+	result := make([]*Record, 0, len(filteredIds))
+	for _, rel := range rels {
+		if _, ok := order[rel.Id]; ok {
+			result = append(result, rel)
+		}
+	}
+
+	if options.Sort == "" {
+		return result, nil, nil // preserve the relation field value order
+	}
+
+	return result, order, nil
+}
+
+// parseExpandOptions parses a single expand path segment
+// in the format "name(key1=value1,key2=value2)".
+//
+// Returns the plain segment as name and nil options
+// if the segment doesn't have options.
+func parseExpandOptions(segment string) (string, *ExpandOptions, error) {
+	start := strings.Index(segment, "(")
+	if start <= 0 || !strings.HasSuffix(segment, ")") {
+		return segment, nil, nil
+	}
+
+	rawOptions := segment[start+1 : len(segment)-1]
+
+	// old "collection(field)" back-relation syntax
+	if !strings.Contains(rawOptions, "=") {
+		return segment, nil, nil
+	}
+
+	name := segment[:start]
+	options := &ExpandOptions{}
+
+	for _, rawOption := range splitExpandOutside(rawOptions, ',') {
+		key, value, _ := strings.Cut(rawOption, "=")
+
+		switch key {
+		case "filter":
+			options.Filter = value
+		case "sort":
+			options.Sort = value
+		case "limit":
+			limit, err := strconv.Atoi(value)
+			if err != nil || limit < 0 {
+				return "", nil, fmt.Errorf("invalid %q expand limit option %q", name, value)
+			}
+			options.Limit = limit
+		default:
+			return "", nil, fmt.Errorf("unknown %q expand option %q", name, key)
+		}
+	}
+
+	return name, options, nil
+}
+
+// splitExpandOutside splits str by sep ignoring the
+// separators inside parenthesis and quoted strings.
+func splitExpandOutside(str string, sep byte) []string {
+	var result []string
+
+	var depth int
+	var quote byte
+	var start int
+
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == sep && depth <= 0:
+			result = append(result, str[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(result, str[start:])
+}
+
+// removeExpandSpaces removes all spaces outside of quoted strings.
+func removeExpandSpaces(str string) string {
+	var sb strings.Builder
+	sb.Grow(len(str))
+
+	var quote byte
+
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+
+		switch {
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '\'' || c == '"' || c == '`':
+			quote = c
+		case c == ' ':
+			continue
+		}
+
+		sb.WriteByte(c)
+	}
+
+	return sb.String()
+}
+
 // normalizeExpands normalizes expand strings and merges self containing paths
 // (eg. ["a.b.c", "a.b", "   test  ", "  ", "test"] -> ["a.b.c", "test"]).
 func normalizeExpands(paths []string) []string {
 	// normalize paths
 	normalized := make([]string, 0, len(paths))
 	for _, p := range paths {
-		p = strings.ReplaceAll(p, " ", "") // replace spaces
-		p = strings.Trim(p, ".")           // trim incomplete paths
+		p = removeExpandSpaces(p) // remove spaces (except in quoted strings)
+		p = strings.Trim(p, ".")  // trim incomplete paths
 		if p != "" {
 			normalized = append(normalized, p)
 		}
@@ -3,6 +3,7 @@ package core_test
 import (
 	"encoding/json"
 	"errors"
+	"slices"
 	"strings"
 	"testing"
 
@@ -484,3 +485,186 @@ func TestBackRelationExpandSingeVsArrayResult(t *testing.T) {
 		}
 	}
 }
+
+func TestExpandRecordWithOptions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	fetchFunc := func(c *core.Collection, ids []string) ([]*core.Record, error) {
+		return app.FindRecordsByIds(c.Id, ids, nil)
+	}
+
+	scenarios := []struct {
+		name        string
+		expand      string
+		expectError bool
+		expectedIds []string
+	}{
+		{
+			"no options",
+			"rel_many_no_cascade_required",
+			false,
+			[]string{"lcl9d87w22ml6jy", "1tmknxy2868d869"},
+		},
+		{
+			"old back-relation syntax like options",
+			"rel_many_no_cascade_required(title)",
+			true,
+			nil,
+		},
+		{
+			"unknown option",
+			"rel_many_no_cascade_required(missing=1)",
+			true,
+			nil,
+		},
+		{
+			"invalid limit",
+			"rel_many_no_cascade_required(limit=abc)",
+			true,
+			nil,
+		},
+		{
+			"invalid filter",
+			"rel_many_no_cascade_required(filter=missing=1)",
+			true,
+			nil,
+		},
+		{
+			"invalid sort",
+			"rel_many_no_cascade_required(sort=missing)",
+			true,
+			nil,
+		},
+		{
+			"sort",
+			"rel_many_no_cascade_required(sort=title)",
+			false,
+			[]string{"1tmknxy2868d869", "lcl9d87w22ml6jy"},
+		},
+		{
+			"limit",
+			"rel_many_no_cascade_required(limit=1)",
+			false,
+			[]string{"lcl9d87w22ml6jy"},
+		},
+		{
+			"sort and limit",
+			"rel_many_no_cascade_required(sort=title,limit=1)",
+			false,
+			[]string{"1tmknxy2868d869"},
+		},
+		{
+			"filter",
+			"rel_many_no_cascade_required(filter=title='test1')",
+			false,
+			[]string{"1tmknxy2868d869"},
+		},
+		{
+			"filter with special characters in quotes",
+			"rel_many_no_cascade_required(filter=title != 'a.b, (c)')",
+			false,
+			[]string{"lcl9d87w22ml6jy", "1tmknxy2868d869"},
+		},
+		{
+			"filter with no matches",
+			"rel_many_no_cascade_required(filter=title='missing',limit=1)",
+			false,
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			record, err := app.FindRecordById("demo4", "qzaqccwrmva4o1n")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			failed := app.ExpandRecord(record, []string{s.expand}, fetchFunc)
+
+			hasErr := len(failed) > 0
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, failed)
+			}
+
+			if hasErr {
+				return
+			}
+
+			ids := []string{}
+			for _, rel := range record.ExpandedAll("rel_many_no_cascade_required") {
+				ids = append(ids, rel.Id)
+			}
+
+			if !slices.Equal(ids, s.expectedIds) {
+				t.Fatalf("Expected ids %v, got %v", s.expectedIds, ids)
+			}
+		})
+	}
+}
+
+func TestExpandRecordWithNestedOptions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById("demo4", "qzaqccwrmva4o1n")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	failed := app.ExpandRecord(
+		record,
+		[]string{"rel_many_no_cascade_required(sort=title).demo4_via_rel_many_no_cascade_required(filter=title='test1',limit=1)"},
+		func(c *core.Collection, ids []string) ([]*core.Record, error) {
+			return app.FindRecordsByIds(c.Id, ids, nil)
+		},
+	)
+	if len(failed) > 0 {
+		t.Fatal(failed)
+	}
+
+	rels := record.ExpandedAll("rel_many_no_cascade_required")
+	if len(rels) != 2 || rels[0].Id != "1tmknxy2868d869" || rels[1].Id != "lcl9d87w22ml6jy" {
+		t.Fatalf("Expected 2 sorted expanded relations, got %v", rels)
+	}
+
+	for _, rel := range rels {
+		backRels := rel.ExpandedAll("demo4_via_rel_many_no_cascade_required")
+		if len(backRels) != 1 || backRels[0].Id != "qzaqccwrmva4o1n" {
+			t.Fatalf("Expected a single qzaqccwrmva4o1n back-relation for %q, got %v", rel.Id, backRels)
+		}
+	}
+}
+
+func TestSplitExpands(t *testing.T) {
+	scenarios := []struct {
+		expand   string
+		expected []string
+	}{
+		{"", []string{""}},
+		{"a,b.c", []string{"a", "b.c"}},
+		{
+			"a(sort=-created,limit=5),b",
+			[]string{"a(sort=-created,limit=5)", "b"},
+		},
+		{
+			`a(filter=title='x,y' && b="(,)"),b(filter=c=1).d(limit=1)`,
+			[]string{`a(filter=title='x,y' && b="(,)")`, "b(filter=c=1).d(limit=1)"},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.expand, func(t *testing.T) {
+			result := core.SplitExpands(s.expand)
+
+			if !slices.Equal(result, s.expected) {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
@@ -216,6 +216,65 @@ func TestRecordQueryAll(t *testing.T) {
 	}
 }
 
+func TestEachRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Run("all records", func(t *testing.T) {
+		ids := []string{}
+
+		err := core.EachRecord(collection, app.RecordQuery(collection).OrderBy("title DESC"), func(record *core.Record) error {
+			if record.Collection().Id != collection.Id {
+				t.Fatalf("Expected collection %q, got %q", collection.Id, record.Collection().Id)
+			}
+			ids = append(ids, record.Id)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		expected := "0yxhwia2amd8gec,achvryl401bhse3,llvuca81nly1qls"
+		if v := strings.Join(ids, ","); v != expected {
+			t.Fatalf("Expected ids %q, got %q", expected, v)
+		}
+	})
+
+	t.Run("stop on fn error", func(t *testing.T) {
+		var calls int
+
+		fnErr := errors.New("test")
+
+		err := core.EachRecord(collection, app.RecordQuery(collection), func(record *core.Record) error {
+			calls++
+			return fnErr
+		})
+		if !errors.Is(err, fnErr) {
+			t.Fatalf("Expected the fn error, got %v", err)
+		}
+
+		if calls != 1 {
+			t.Fatalf("Expected 1 fn call, got %d", calls)
+		}
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		err := core.EachRecord(collection, app.RecordQuery(collection).AndWhere(dbx.NewExp("missing = 1")), func(record *core.Record) error {
+			return nil
+		})
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+}
+
 func TestFindRecordById(t *testing.T) {
 	t.Parallel()
 
@@ -1025,6 +1084,70 @@ func TestFindAuthRecordByEmail(t *testing.T) {
 	}
 }
 
+func TestFindAuthRecordByPhone(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users.Fields.Add(
+		&core.PhoneField{Name: "phone"},
+		&core.PhoneField{Name: "phone_not_unique"},
+	)
+	users.AddIndex("idx_test_users_phone", true, "phone", "phone != ''")
+	if err = app.Save(users); err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail(users, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	user.Set("phone", "+359 888 123 456")
+	user.Set("phone_not_unique", "+359888654321")
+	if err = app.Save(user); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		collectionIdOrName string
+		phone              string
+		expectError        bool
+	}{
+		{"missing", "+359888123456", true},
+		{"demo2", "+359888123456", true},
+		{"clients", "+359888123456", true},
+		{"users", "invalid", true},
+		{"users", "+359888000000", true},
+		{"users", "+359888654321", true}, // non-unique phone field
+		{"users", "+359888123456", false},
+		{"users", "00359 (888) 123-456", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%s_%s", s.collectionIdOrName, s.phone), func(t *testing.T) {
+			record, err := app.FindAuthRecordByPhone(s.collectionIdOrName, s.phone)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr to be %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if record.Id != user.Id {
+				t.Fatalf("Expected record %q, got %q", user.Id, record.Id)
+			}
+		})
+	}
+}
+
 func TestCanAccessRecord(t *testing.T) {
 	t.Parallel()
 
@@ -1195,3 +1318,136 @@ func TestCanAccessRecord(t *testing.T) {
 		})
 	}
 }
+
+func TestCanAccessRealtimeTopic(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app.Settings().Realtime.Topics = []core.RealtimeTopic{
+		{Pattern: "superusers/{id}"},
+		{Pattern: "public/{id}", Rule: types.Pointer("")},
+		{Pattern: "invalid/{id}", Collection: "demo1", Rule: types.Pointer("id ?!@ 1")},
+		{Pattern: "missing/{id}", Collection: "missing", Rule: types.Pointer("id = @topic.id")},
+		{Pattern: "chat/{roomId}/{userId}", Collection: "demo1", Rule: types.Pointer("id = @topic.roomId && @request.auth.id = @topic.userId")},
+	}
+
+	scenarios := []struct {
+		name        string
+		topic       string
+		requestInfo *core.RequestInfo
+		expected    bool
+		expectError bool
+	}{
+		{
+			"as guest with non-matching topic",
+			"unknown/123",
+			&core.RequestInfo{},
+			true,
+			false,
+		},
+		{
+			"as guest with nil rule topic",
+			"superusers/123",
+			&core.RequestInfo{},
+			false,
+			false,
+		},
+		{
+			"as superuser with nil rule topic",
+			"superusers/123",
+			&core.RequestInfo{Auth: superuser},
+			true,
+			false,
+		},
+		{
+			"as guest with empty rule topic",
+			"public/123",
+			&core.RequestInfo{},
+			true,
+			false,
+		},
+		{
+			"as guest with invalid rule topic",
+			"invalid/123",
+			&core.RequestInfo{},
+			false,
+			true,
+		},
+		{
+			"as guest with missing collection topic",
+			"missing/123",
+			&core.RequestInfo{},
+			false,
+			true,
+		},
+		{
+			"as superuser with missing collection topic",
+			"missing/123",
+			&core.RequestInfo{Auth: superuser},
+			true,
+			false,
+		},
+		{
+			"as guest with mismatched rule",
+			"chat/imy661ixudk5izi/" + user.Id,
+			&core.RequestInfo{},
+			false,
+			false,
+		},
+		{
+			"as auth record with mismatched topic param",
+			"chat/missing/" + user.Id,
+			&core.RequestInfo{Auth: user},
+			false,
+			false,
+		},
+		{
+			"as auth record with quotes in the topic param",
+			"chat/imy661ixudk5izi' || id != '/" + user.Id,
+			&core.RequestInfo{Auth: user},
+			false,
+			false,
+		},
+		{
+			"as auth record with matched rule",
+			"chat/imy661ixudk5izi/" + user.Id,
+			&core.RequestInfo{Auth: user},
+			true,
+			false,
+		},
+		{
+			"as auth record with matched rule and subscription options",
+			"chat/imy661ixudk5izi/" + user.Id + "?options={}",
+			&core.RequestInfo{Auth: user},
+			true,
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := app.CanAccessRealtimeTopic(s.topic, s.requestInfo)
+
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
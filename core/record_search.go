@@ -0,0 +1,372 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/spf13/cast"
+)
+
+const (
+	// SearchModePrefix matches the indexed values with a word
+	// starting with the search term (case-insensitive).
+	SearchModePrefix = "prefix"
+
+	// SearchModeFuzzy matches the indexed values containing at least
+	// half of the search term trigrams, tolerating minor typos.
+	SearchModeFuzzy = "fuzzy"
+)
+
+// MaxSearchTermLength is the max allowed search term characters.
+const MaxSearchTermLength = 100
+
+// searchFuzzyMinSimilarity is the min ratio of the search term trigrams
+// that an indexed value must contain to be considered a fuzzy match.
+const searchFuzzyMinSimilarity = 0.5
+
+var (
+	ErrSearchNotEnabled  = errors.New("the collection doesn't have a search index")
+	ErrInvalidSearchMode = errors.New("invalid search mode")
+)
+
+// SearchTableName returns the name of the collection search index table.
+//
+// The index is a SQLite FTS5 virtual table with trigram tokenizer
+// created on demand when the collection search config is set.
+func SearchTableName(collection *Collection) string {
+	return "_search_" + collection.Id
+}
+
+// searchColumn returns the search index column of the i-th search config field.
+//
+// Positional column names are used to avoid conflicts with the reserved FTS5 column names.
+func searchColumn(i int) string {
+	return "f" + strconv.Itoa(i)
+}
+
+// BuildRecordSearchExpr builds a records query filter expression matching
+// the collection records which indexed fields values match the search term.
+//
+// mode could be [SearchModePrefix] (default if empty) or [SearchModeFuzzy].
+//
+// fields could be used to limit the search to a subset of the
+// collection search config fields (all indexed fields are searched if empty).
+//
+// Note that terms with less than 3 characters can't make use of the
+// trigram index and they are always matched with a full index scan.
+func BuildRecordSearchExpr(collection *Collection, term string, mode string, fields ...string) (dbx.Expression, error) {
+	if collection.Search == nil {
+		return nil, ErrSearchNotEnabled
+	}
+
+	term = strings.TrimSpace(term)
+	if term == "" {
+		return nil, errors.New("empty search term")
+	}
+
+	if utf8.RuneCountInString(term) > MaxSearchTermLength {
+		return nil, fmt.Errorf("the search term must be no more than %d characters", MaxSearchTermLength)
+	}
+
+	if len(fields) == 0 {
+		fields = collection.Search.Fields
+	}
+
+	columns := make([]string, 0, len(fields))
+	for _, name := range fields {
+		i := collection.Search.FieldIndex(name)
+		if i < 0 {
+			return nil, fmt.Errorf("field %q is not part of the collection search index", name)
+		}
+		columns = append(columns, searchColumn(i))
+	}
+
+	tableName := SearchTableName(collection)
+
+	var condition string
+	var params dbx.Params
+
+	switch mode {
+	case "", SearchModePrefix:
+		condition, params = buildSearchPrefixCondition(columns, term)
+	case SearchModeFuzzy:
+		condition, params = buildSearchFuzzyCondition(tableName, columns, term)
+	default:
+		return nil, ErrInvalidSearchMode
+	}
+
+	return dbx.NewExp(fmt.Sprintf(
+		"[[%s.id]] IN (SELECT [[recordId]] FROM {{%s}} WHERE %s)",
+		collection.Name,
+		tableName,
+		condition,
+	), params), nil
+}
+
+// buildSearchPrefixCondition builds a search index condition matching the values
+// that starts with the term or have a space separated word starting with it.
+//
+// Note that the FTS5 trigram LIKE optimization doesn't support the ESCAPE clause
+// and the term "%" and "_" characters act as regular LIKE wildcards.
+func buildSearchPrefixCondition(columns []string, term string) (string, dbx.Params) {
+	prefixParam := "s" + security.PseudorandomString(8)
+	wordPrefixParam := "s" + security.PseudorandomString(8)
+
+	params := dbx.Params{
+		prefixParam:     term + "%",
+		wordPrefixParam: "% " + term + "%",
+	}
+
+	parts := make([]string, len(columns))
+	for i, col := range columns {
+		parts[i] = fmt.Sprintf("[[%s]] LIKE {:%s} OR [[%s]] LIKE {:%s}", col, prefixParam, col, wordPrefixParam)
+	}
+
+	return "(" + strings.Join(parts, " OR ") + ")", params
+}
+
+// buildSearchFuzzyCondition builds a search index condition matching the values
+// that contain at least [searchFuzzyMinSimilarity] of the term trigrams.
+//
+// Terms with less than 3 characters fallback to a prefix condition.
+func buildSearchFuzzyCondition(tableName string, columns []string, term string) (string, dbx.Params) {
+	trigrams := searchTrigrams(term)
+	if len(trigrams) == 0 {
+		return buildSearchPrefixCondition(columns, term)
+	}
+
+	params := dbx.Params{}
+
+	// the MATCH query selects the candidate rows containing any of the
+	// trigrams using the index and the scores filter the close ones
+	quoted := make([]string, len(trigrams))
+	scoreParts := make([]string, len(trigrams))
+	for i, trigram := range trigrams {
+		quoted[i] = `"` + strings.ReplaceAll(trigram, `"`, `""`) + `"`
+
+		param := "s" + security.PseudorandomString(8)
+		params[param] = trigram
+		scoreParts[i] = "(instr(lower([[%[1]s]]), {:" + param + "}) > 0)"
+	}
+
+	matchParam := "s" + security.PseudorandomString(8)
+	params[matchParam] = "{" + strings.Join(columns, " ") + "} : (" + strings.Join(quoted, " OR ") + ")"
+
+	minParam := "s" + security.PseudorandomString(8)
+	params[minParam] = int(math.Ceil(float64(len(trigrams)) * searchFuzzyMinSimilarity))
+
+	score := strings.Join(scoreParts, " + ")
+
+	scores := make([]string, len(columns))
+	for i, col := range columns {
+		scores[i] = "(" + fmt.Sprintf(score, col) + ") >= {:" + minParam + "}"
+	}
+
+	return fmt.Sprintf("{{%s}} MATCH {:%s} AND (%s)", tableName, matchParam, strings.Join(scores, " OR ")), params
+}
+
+// searchTrigrams returns the unique lowercased 3 characters sequences of term.
+func searchTrigrams(term string) []string {
+	runes := []rune(strings.ToLower(term))
+
+	result := make([]string, 0, max(len(runes)-2, 0))
+	for i := 0; i+3 <= len(runes); i++ {
+		trigram := string(runes[i : i+3])
+		if !slices.Contains(result, trigram) {
+			result = append(result, trigram)
+		}
+	}
+
+	return result
+}
+
+// ReindexCollectionSearch (re)builds the search index of the
+// provided collection from its existing records.
+//
+// The collection search index is deleted if the collection
+// doesn't have search config.
+func (app *BaseApp) ReindexCollectionSearch(collection *Collection) error {
+	tableName := SearchTableName(collection)
+
+	return app.RunInTransaction(func(txApp App) error {
+		if err := txApp.DeleteTable(tableName); err != nil {
+			return err
+		}
+
+		if collection.Search == nil || len(collection.Search.Fields) == 0 {
+			return nil
+		}
+
+		columns := make([]string, 0, len(collection.Search.Fields)+1)
+		values := make([]string, 0, len(collection.Search.Fields)+1)
+
+		// the record id is indexed (but never searched) to allow
+		// trigram LIKE lookups when updating and deleting the index rows
+		columns = append(columns, "[[recordId]]")
+		values = append(values, "[[id]]")
+
+		for i, name := range collection.Search.Fields {
+			columns = append(columns, "[["+searchColumn(i)+"]]")
+			values = append(values, "[["+name+"]]")
+		}
+
+		_, err := txApp.NonconcurrentDB().NewQuery(fmt.Sprintf(
+			"CREATE VIRTUAL TABLE {{%s}} USING fts5(%s, tokenize='trigram')",
+			tableName,
+			strings.Join(columns, ", "),
+		)).Execute()
+		if err != nil {
+			return fmt.Errorf("failed to create the search index table: %w", err)
+		}
+
+		_, err = txApp.NonconcurrentDB().NewQuery(fmt.Sprintf(
+			"INSERT INTO {{%s}} (%s) SELECT %s FROM {{%s}}",
+			tableName,
+			strings.Join(columns, ", "),
+			strings.Join(values, ", "),
+			collection.Name,
+		)).Execute()
+
+		return err
+	})
+}
+
+// syncRecordSearchIndex replaces the search index row of the provided record.
+//
+// oldId is the record id before the save and it is used to delete
+// the old index row (could be empty for new records).
+func syncRecordSearchIndex(app App, record *Record, oldId string) error {
+	config := record.Collection().Search
+	if config == nil {
+		return nil
+	}
+
+	if oldId != "" {
+		if err := deleteRecordSearchIndex(app, record.Collection(), oldId); err != nil {
+			return err
+		}
+	}
+
+	params := dbx.Params{"recordId": record.Id}
+	for i, name := range config.Fields {
+		params[searchColumn(i)] = record.GetString(name)
+	}
+
+	_, err := app.NonconcurrentDB().Insert(SearchTableName(record.Collection()), params).Execute()
+
+	return err
+}
+
+// deleteRecordSearchIndex deletes the search index row of the specified collection record.
+func deleteRecordSearchIndex(app App, collection *Collection, recordId string) error {
+	// note: the LIKE condition is only to allow the trigram index lookup
+	// instead of full table scan (the "_" id characters are LIKE wildcards)
+	_, err := app.NonconcurrentDB().NewQuery(fmt.Sprintf(
+		"DELETE FROM {{%s}} WHERE [[recordId]] LIKE {:id} AND [[recordId]] = {:id}",
+		SearchTableName(collection),
+	)).Bind(dbx.Params{"id": recordId}).Execute()
+
+	return err
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerSearchHooks() {
+	// rebuild the search index on search config change
+	onCollectionSave := func(e *CollectionEvent) error {
+		var oldFields []string
+		if !e.Collection.IsNew() {
+			oldCollection, err := e.App.FindCachedCollectionByNameOrId(e.Collection.Id)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve old cached collection: %w", err)
+			}
+
+			if oldCollection.Search != nil {
+				oldFields = oldCollection.Search.Fields
+			}
+		}
+
+		if err := e.Next(); err != nil {
+			return err
+		}
+
+		var newFields []string
+		if e.Collection.Search != nil {
+			newFields = e.Collection.Search.Fields
+		}
+
+		if slices.Equal(oldFields, newFields) {
+			return nil
+		}
+
+		return e.App.ReindexCollectionSearch(e.Collection)
+	}
+
+	app.OnCollectionCreate().Bind(&hook.Handler[*CollectionEvent]{
+		Func:     onCollectionSave,
+		Priority: 99,
+	})
+
+	app.OnCollectionUpdate().Bind(&hook.Handler[*CollectionEvent]{
+		Func:     onCollectionSave,
+		Priority: 99,
+	})
+
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			return e.App.DeleteTable(SearchTableName(e.Collection))
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordCreateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			return syncRecordSearchIndex(e.App, e.Record, "")
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordUpdateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			oldId := cast.ToString(e.Record.LastSavedPK())
+
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			return syncRecordSearchIndex(e.App, e.Record, oldId)
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if e.Record.Collection().Search == nil {
+				return nil
+			}
+
+			return deleteRecordSearchIndex(e.App, e.Record.Collection(), e.Record.Id)
+		},
+		Priority: 99,
+	})
+}
@@ -0,0 +1,270 @@
+package core_test
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestBuildRecordSearchExprErrors(t *testing.T) {
+	t.Parallel()
+
+	withoutSearch := core.NewBaseCollection("test")
+
+	withSearch := core.NewBaseCollection("test")
+	withSearch.Fields.Add(&core.TextField{Name: "title"})
+	withSearch.Search = &core.SearchConfig{Fields: []string{"title"}}
+
+	scenarios := []struct {
+		name        string
+		collection  *core.Collection
+		term        string
+		mode        string
+		fields      []string
+		expectError bool
+	}{
+		{"collection without search config", withoutSearch, "test", "", nil, true},
+		{"empty term", withSearch, "  ", "", nil, true},
+		{"too long term", withSearch, strings.Repeat("a", core.MaxSearchTermLength+1), "", nil, true},
+		{"invalid mode", withSearch, "test", "invalid", nil, true},
+		{"non-indexed field", withSearch, "test", "", []string{"missing"}, true},
+		{"default mode", withSearch, "test", "", nil, false},
+		{"prefix mode", withSearch, "test", core.SearchModePrefix, []string{"title"}, false},
+		{"fuzzy mode", withSearch, "test", core.SearchModeFuzzy, nil, false},
+		{"fuzzy mode with short term", withSearch, "te", core.SearchModeFuzzy, nil, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			expr, err := core.BuildRecordSearchExpr(s.collection, s.term, s.mode, s.fields...)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && expr == nil {
+				t.Fatal("Expected non-nil expression")
+			}
+		})
+	}
+
+	t.Run("errors identity", func(t *testing.T) {
+		_, err := core.BuildRecordSearchExpr(withoutSearch, "test", "")
+		if !errors.Is(err, core.ErrSearchNotEnabled) {
+			t.Fatalf("Expected ErrSearchNotEnabled, got %v", err)
+		}
+
+		_, err = core.BuildRecordSearchExpr(withSearch, "test", "invalid")
+		if !errors.Is(err, core.ErrInvalidSearchMode) {
+			t.Fatalf("Expected ErrInvalidSearchMode, got %v", err)
+		}
+	})
+}
+
+func TestRecordSearchIndex(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("search_test")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.TextField{Name: "description"},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if app.HasTable(core.SearchTableName(collection)) {
+		t.Fatal("Expected the search index table to not be created without search config")
+	}
+
+	data := map[string][2]string{
+		"search000000001": {"Hello world", "first record"},
+		"search000000002": {"Autocomplete example", "second record"},
+		"search000000003": {"PocketBase backend", "third record"},
+	}
+	for id, values := range data {
+		record := core.NewRecord(collection)
+		record.Id = id
+		record.Set("title", values[0])
+		record.Set("description", values[1])
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// the existing records should be indexed on config change
+	collection.Search = &core.SearchConfig{Fields: []string{"title"}}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	search := func(t *testing.T, term string, mode string, fields ...string) []string {
+		expr, err := core.BuildRecordSearchExpr(collection, term, mode, fields...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		records, err := app.FindAllRecords(collection, expr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		titles := make([]string, len(records))
+		for i, r := range records {
+			titles[i] = r.GetString("title")
+		}
+		slices.Sort(titles)
+
+		return titles
+	}
+
+	checkSearch := func(t *testing.T, term string, mode string, expected []string) {
+		result := search(t, term, mode)
+
+		if !slices.Equal(result, expected) {
+			t.Fatalf("[%s %q] Expected %v, got %v", mode, term, expected, result)
+		}
+	}
+
+	t.Run("initial index", func(t *testing.T) {
+		checkSearch(t, "hel", core.SearchModePrefix, []string{"Hello world"})
+		checkSearch(t, "WOR", core.SearchModePrefix, []string{"Hello world"})
+		checkSearch(t, "orld", core.SearchModePrefix, []string{})
+		checkSearch(t, "a", "", []string{"Autocomplete example"})
+		checkSearch(t, "record", "", []string{}) // description is not indexed
+		checkSearch(t, "pocketbsae", core.SearchModeFuzzy, []string{"PocketBase backend"})
+		checkSearch(t, "autocompleet", core.SearchModeFuzzy, []string{"Autocomplete example"})
+		checkSearch(t, "xyzxyz", core.SearchModeFuzzy, []string{})
+	})
+
+	t.Run("record changes", func(t *testing.T) {
+		record, err := app.FindRecordById(collection, "search000000001")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		record.Set("title", "Goodbye world")
+		if err := app.Save(record); err != nil {
+			t.Fatal(err)
+		}
+
+		checkSearch(t, "hel", "", []string{})
+		checkSearch(t, "good", "", []string{"Goodbye world"})
+
+		newRecord := core.NewRecord(collection)
+		newRecord.Set("title", "Hello again")
+		if err := app.Save(newRecord); err != nil {
+			t.Fatal(err)
+		}
+
+		checkSearch(t, "hel", "", []string{"Hello again"})
+
+		if err := app.Delete(record); err != nil {
+			t.Fatal(err)
+		}
+
+		checkSearch(t, "good", "", []string{})
+		checkSearch(t, "world", "", []string{})
+	})
+
+	t.Run("config change", func(t *testing.T) {
+		collection.Search = &core.SearchConfig{Fields: []string{"title", "description"}}
+		if err := app.Save(collection); err != nil {
+			t.Fatal(err)
+		}
+
+		checkSearch(t, "record", "", []string{"Autocomplete example", "PocketBase backend"})
+
+		result := search(t, "record", "", "title")
+		if len(result) != 0 {
+			t.Fatalf("Expected no title matches, got %v", result)
+		}
+	})
+
+	t.Run("config removal", func(t *testing.T) {
+		collection.Search = nil
+		if err := app.Save(collection); err != nil {
+			t.Fatal(err)
+		}
+
+		if app.HasTable(core.SearchTableName(collection)) {
+			t.Fatal("Expected the search index table to be deleted")
+		}
+	})
+
+	t.Run("collection delete", func(t *testing.T) {
+		collection.Search = &core.SearchConfig{Fields: []string{"title"}}
+		if err := app.Save(collection); err != nil {
+			t.Fatal(err)
+		}
+
+		if !app.HasTable(core.SearchTableName(collection)) {
+			t.Fatal("Expected the search index table to be created")
+		}
+
+		if err := app.Delete(collection); err != nil {
+			t.Fatal(err)
+		}
+
+		if app.HasTable(core.SearchTableName(collection)) {
+			t.Fatal("Expected the search index table to be deleted")
+		}
+	})
+}
+
+func TestReindexCollectionSearch(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection.Search = &core.SearchConfig{Fields: []string{"text"}}
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	countIndexRows := func() int {
+		var total int
+		err := app.DB().Select("count(*)").From(core.SearchTableName(collection)).Row(&total)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return total
+	}
+
+	totalRecords, err := app.CountRecords(collection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total := countIndexRows(); int64(total) != totalRecords {
+		t.Fatalf("Expected %d index rows, got %d", totalRecords, total)
+	}
+
+	// simulate raw changes
+	_, err = app.DB().NewQuery("DELETE FROM {{" + core.SearchTableName(collection) + "}}").Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.ReindexCollectionSearch(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	if total := countIndexRows(); int64(total) != totalRecords {
+		t.Fatalf("Expected %d index rows after reindex, got %d", totalRecords, total)
+	}
+}
@@ -0,0 +1,253 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const RecordSharesTableName = "_recordShares"
+
+// The supported record share scopes.
+const (
+	// RecordShareScopeView grants view access to the shared record and its files.
+	RecordShareScopeView = "view"
+
+	// RecordShareScopeComment grants the same access as [RecordShareScopeView]
+	// and could be additionally checked in the API rules of other collections
+	// via @request.share.scope (e.g. to allow creating comments of the shared record).
+	RecordShareScopeComment = "comment"
+)
+
+// RecordShareTokenHeader is the request header name of the record share token
+// (alternatively the token could be sent as "share" query parameter).
+const RecordShareTokenHeader = "X-Share-Token"
+
+const recordShareTokenLength = 40
+
+var (
+	_ Model         = (*RecordShare)(nil)
+	_ PostValidator = (*RecordShare)(nil)
+)
+
+// RecordShare defines a scoped and expiring share link of a single record
+// that grants access to it regardless of the collection API rules.
+//
+// Only the share token hash is stored and the plain token is available
+// only once on creation (see [App.CreateRecordShare]).
+type RecordShare struct {
+	BaseModel
+
+	CollectionRef      string         `db:"collectionRef" json:"collectionRef"`
+	RecordRef          string         `db:"recordRef" json:"recordRef"`
+	OwnerCollectionRef string         `db:"ownerCollectionRef" json:"ownerCollectionRef"`
+	OwnerRef           string         `db:"ownerRef" json:"ownerRef"`
+	TokenHash          string         `db:"tokenHash" json:"-"`
+	Scope              string         `db:"scope" json:"scope"`
+	Expires            types.DateTime `db:"expires" json:"expires"`
+	Created            types.DateTime `db:"created" json:"created"`
+	Updated            types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the RecordShare model table name.
+func (m *RecordShare) TableName() string {
+	return RecordSharesTableName
+}
+
+// IsExpired reports whether the share has expired.
+func (m *RecordShare) IsExpired() bool {
+	return !m.Expires.Time().After(time.Now())
+}
+
+// IsFor reports whether the share is for the provided record.
+func (m *RecordShare) IsFor(record *Record) bool {
+	return record != nil &&
+		m.RecordRef == record.Id &&
+		m.CollectionRef == record.Collection().Id
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *RecordShare) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(&m.CollectionRef, validation.Required, validation.By(validateCollectionId(app, CollectionTypeBase, CollectionTypeAuth))),
+		validation.Field(&m.RecordRef, validation.Required, validation.By(validateRecordId(app, m.CollectionRef))),
+		validation.Field(&m.OwnerCollectionRef, validation.Required, validation.By(validateCollectionId(app, CollectionTypeAuth))),
+		validation.Field(&m.OwnerRef, validation.Required, validation.By(validateRecordId(app, m.OwnerCollectionRef))),
+		validation.Field(&m.TokenHash, validation.Required),
+		validation.Field(&m.Scope, validation.Required, validation.In(RecordShareScopeView, RecordShareScopeComment)),
+		validation.Field(&m.Expires, validation.By(validateDatetimeRequired)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// RecordShareQuery returns a new RecordShare select query.
+func (app *BaseApp) RecordShareQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&RecordShare{})
+}
+
+// FindRecordShares returns the active (aka. non-expired) shares of the provided record.
+func (app *BaseApp) FindRecordShares(record *Record) ([]*RecordShare, error) {
+	result := []*RecordShare{}
+
+	err := app.RecordShareQuery().
+		AndWhere(dbx.HashExp{
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+		}).
+		AndWhere(activeRecordShareExpr()).
+		OrderBy("created DESC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRecordShareByToken finds the active (aka. non-expired) record share by its plain token.
+func (app *BaseApp) FindRecordShareByToken(token string) (*RecordShare, error) {
+	if token == "" {
+		return nil, errors.New("missing share token")
+	}
+
+	result := &RecordShare{}
+
+	err := app.RecordShareQuery().
+		AndWhere(dbx.HashExp{"tokenHash": security.SHA256(token)}).
+		AndWhere(activeRecordShareExpr()).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateRecordShare creates a new share of the provided record
+// with the specified scope that expires after ttl.
+//
+// Returns the created share and its plain token (it is not stored and cannot be retrieved later).
+func (app *BaseApp) CreateRecordShare(record *Record, owner *Record, scope string, ttl time.Duration) (*RecordShare, string, error) {
+	if owner == nil {
+		return nil, "", errors.New("missing share owner")
+	}
+
+	if ttl <= 0 {
+		return nil, "", errors.New("the share ttl must be positive")
+	}
+
+	token := security.RandomString(recordShareTokenLength)
+
+	share := &RecordShare{
+		CollectionRef:      record.Collection().Id,
+		RecordRef:          record.Id,
+		OwnerCollectionRef: owner.Collection().Id,
+		OwnerRef:           owner.Id,
+		TokenHash:          security.SHA256(token),
+		Scope:              scope,
+		Expires:            types.NowDateTime().Add(ttl),
+	}
+
+	if err := app.Save(share); err != nil {
+		return nil, "", err
+	}
+
+	return share, token, nil
+}
+
+// DeleteExpiredRecordShares deletes all expired record shares.
+func (app *BaseApp) DeleteExpiredRecordShares() error {
+	_, err := app.NonconcurrentDB().Delete(RecordSharesTableName, dbx.Not(activeRecordShareExpr())).Execute()
+
+	return err
+}
+
+func activeRecordShareExpr() dbx.Expression {
+	return dbx.NewExp("[[expires]] > {:now}", dbx.Params{"now": types.NowDateTime().String()})
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerRecordShareHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*RecordShare); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*RecordShare); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// delete the collection, record and owner shares
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(RecordSharesTableName, dbx.Or(
+				dbx.HashExp{"collectionRef": e.Collection.Id},
+				dbx.HashExp{"ownerCollectionRef": e.Collection.Id},
+			)).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(RecordSharesTableName, dbx.Or(
+				dbx.HashExp{
+					"collectionRef": e.Record.Collection().Id,
+					"recordRef":     e.Record.Id,
+				},
+				dbx.HashExp{
+					"ownerCollectionRef": e.Record.Collection().Id,
+					"ownerRef":           e.Record.Id,
+				},
+			)).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.Cron().Add("__pbRecordSharesCleanup__", "0 * * * *", func() {
+		if err := app.DeleteExpiredRecordShares(); err != nil {
+			app.Logger().Warn("Failed to delete expired record shares", "error", err)
+		}
+	})
+}
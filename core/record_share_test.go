@@ -0,0 +1,188 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestRecordShareIsExpired(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		expires  time.Time
+		expected bool
+	}{
+		{time.Now().Add(-1 * time.Minute), true},
+		{time.Now().Add(1 * time.Minute), false},
+	}
+
+	for i, s := range scenarios {
+		share := &core.RecordShare{}
+		share.Expires, _ = types.ParseDateTime(s.expires)
+
+		if v := share.IsExpired(); v != s.expected {
+			t.Fatalf("[%d] Expected IsExpired %v, got %v", i, s.expected, v)
+		}
+	}
+}
+
+func TestRecordShareIsFor(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById("demo1", "imy661ixudk5izi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := app.FindRecordById("demo1", "al1h9ijdeojtsjy")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	share := &core.RecordShare{CollectionRef: record.Collection().Id, RecordRef: record.Id}
+
+	if share.IsFor(nil) {
+		t.Fatal("Expected false for nil record")
+	}
+
+	if share.IsFor(other) {
+		t.Fatal("Expected false for other record")
+	}
+
+	if !share.IsFor(record) {
+		t.Fatal("Expected true for the shared record")
+	}
+}
+
+func TestCreateRecordShare(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById("demo1", "imy661ixudk5izi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := app.CreateRecordShare(record, nil, core.RecordShareScopeView, time.Hour); err == nil {
+		t.Fatal("Expected missing owner error")
+	}
+
+	if _, _, err := app.CreateRecordShare(record, owner, core.RecordShareScopeView, 0); err == nil {
+		t.Fatal("Expected invalid ttl error")
+	}
+
+	if _, _, err := app.CreateRecordShare(record, owner, "invalid", time.Hour); err == nil {
+		t.Fatal("Expected invalid scope error")
+	}
+
+	share, token, err := app.CreateRecordShare(record, owner, core.RecordShareScopeComment, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token == "" || share.TokenHash == "" || share.TokenHash == token {
+		t.Fatalf("Expected hashed token, got token %q and hash %q", token, share.TokenHash)
+	}
+
+	if !share.IsFor(record) || share.OwnerRef != owner.Id || share.Scope != core.RecordShareScopeComment {
+		t.Fatalf("Unexpected share %#v", share)
+	}
+
+	found, err := app.FindRecordShareByToken(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if found.Id != share.Id {
+		t.Fatalf("Expected share %q, got %q", share.Id, found.Id)
+	}
+
+	if _, err := app.FindRecordShareByToken("invalid"); err == nil {
+		t.Fatal("Expected invalid token error")
+	}
+
+	if _, err := app.FindRecordShareByToken(""); err == nil {
+		t.Fatal("Expected empty token error")
+	}
+}
+
+func TestFindRecordSharesAndDeleteExpired(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById("demo1", "imy661ixudk5izi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	owner, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	active, _, err := app.CreateRecordShare(record, owner, core.RecordShareScopeView, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, expiredToken, err := app.CreateRecordShare(record, owner, core.RecordShareScopeView, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expired.Expires = types.NowDateTime().Add(-1 * time.Minute)
+	if err := app.SaveNoValidate(expired); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRecordShareByToken(expiredToken); err == nil {
+		t.Fatal("Expected expired share to not be found")
+	}
+
+	shares, err := app.FindRecordShares(record)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(shares) != 1 || shares[0].Id != active.Id {
+		t.Fatalf("Expected only the active share, got %v", shares)
+	}
+
+	if err := app.DeleteExpiredRecordShares(); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int
+	if err := app.RecordShareQuery().Select("count(*)").Row(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Fatalf("Expected 1 remaining share, got %d", total)
+	}
+
+	// delete with the shared record
+	// ---
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.RecordShareQuery().Select("count(*)").Row(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected the record shares to be deleted, got %d", total)
+	}
+}
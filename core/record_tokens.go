@@ -1,7 +1,9 @@
 package core
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,6 +17,8 @@ const (
 	TokenTypeVerification  = "verification"
 	TokenTypePasswordReset = "passwordReset"
 	TokenTypeEmailChange   = "emailChange"
+	TokenTypeScoped        = "scoped"
+	TokenTypeFeed          = "feed"
 )
 
 // List with commonly used record token claims
@@ -25,14 +29,56 @@ const (
 	TokenClaimEmail        = "email"
 	TokenClaimNewEmail     = "newEmail"
 	TokenClaimRefreshable  = "refreshable"
+	TokenClaimAuthTime     = "authTime"
+	TokenClaimScope        = "scope"
+	TokenClaimFeed         = "feed"
+
+	TokenClaimProfileIncomplete = "profileIncomplete"
+
+	TokenClaimEmailChangeStage = "emailChangeStage"
+)
+
+// List with the email change token stages
+// (see [EmailChangeConfig.RequireOldEmailApproval]).
+const (
+	// EmailChangeStageApprove is the stage of the token sent to the old
+	// email address for approving the email change.
+	EmailChangeStageApprove = "approve"
+
+	// EmailChangeStageConfirm is the stage of the token sent to the new
+	// email address after the email change was approved.
+	EmailChangeStageConfirm = "confirm"
 )
 
 // Common token related errors
 var (
-	ErrNotAuthRecord     = errors.New("not an auth collection record")
-	ErrMissingSigningKey = errors.New("missing or invalid signing key")
+	ErrNotAuthRecord          = errors.New("not an auth collection record")
+	ErrMissingSigningKey      = errors.New("missing or invalid signing key")
+	ErrReservedTokenClaim     = errors.New("reserved token claim")
+	ErrTokenCustomClaimsLimit = errors.New("custom token claims size limit exceeded")
 )
 
+// MaxTokenCustomClaimsSize is the max allowed size in bytes of the
+// JSON serialized custom auth token claims (see [Record.NewAuthTokenWithClaims]).
+var MaxTokenCustomClaimsSize = 4096
+
+// reservedAuthTokenClaims lists the auth token claims that cannot be
+// overwritten by the custom claims.
+var reservedAuthTokenClaims = []string{
+	TokenClaimId,
+	TokenClaimType,
+	TokenClaimCollectionId,
+	TokenClaimEmail,
+	TokenClaimNewEmail,
+	TokenClaimRefreshable,
+	TokenClaimAuthTime,
+	TokenClaimScope,
+	TokenClaimFeed,
+	TokenClaimProfileIncomplete,
+	TokenClaimEmailChangeStage,
+	"exp", "iat", "nbf", "iss", "sub", "aud", "jti",
+}
+
 // NewStaticAuthToken generates and returns a new static record authentication token.
 //
 // Static auth tokens are similar to the regular auth tokens, but are
@@ -40,19 +86,50 @@ var (
 //
 // Zero or negative duration will fallback to the duration from the auth collection settings.
 func (m *Record) NewStaticAuthToken(duration time.Duration) (string, error) {
-	return m.newAuthToken(duration, false)
+	return m.newAuthToken(duration, false, nil)
+}
+
+// NewStaticAuthTokenWithClaims is similar to [Record.NewStaticAuthToken]
+// but also appends the provided custom claims to the token payload.
+//
+// See [Record.NewAuthTokenWithClaims] for the custom claims restrictions.
+func (m *Record) NewStaticAuthTokenWithClaims(duration time.Duration, customClaims map[string]any) (string, error) {
+	return m.newAuthToken(duration, false, customClaims)
 }
 
 // NewAuthToken generates and returns a new record authentication token.
 func (m *Record) NewAuthToken() (string, error) {
-	return m.newAuthToken(0, true)
+	return m.newAuthToken(0, true, nil)
 }
 
-func (m *Record) newAuthToken(duration time.Duration, refreshable bool) (string, error) {
+// NewAuthTokenWithClaims generates and returns a new record authentication token
+// with the provided custom claims appended to the token payload
+// (optionally preserving the original authentication time similar to [Record.NewAuthTokenSince]).
+//
+// Returns [ErrReservedTokenClaim] if a custom claim overlaps with one of the
+// system token claims (e.g. "id", "type", "exp") and [ErrTokenCustomClaimsLimit]
+// if the JSON serialized custom claims exceed [MaxTokenCustomClaimsSize] bytes.
+func (m *Record) NewAuthTokenWithClaims(customClaims map[string]any, optAuthTime ...time.Time) (string, error) {
+	return m.newAuthToken(0, true, customClaims, optAuthTime...)
+}
+
+// NewAuthTokenSince generates and returns a new record authentication token
+// that preserves the provided original authentication time (usually on token refresh).
+//
+// Zero authTime will fallback to the current time (aka. the same as [Record.NewAuthToken]).
+func (m *Record) NewAuthTokenSince(authTime time.Time) (string, error) {
+	return m.newAuthToken(0, true, nil, authTime)
+}
+
+func (m *Record) newAuthToken(duration time.Duration, refreshable bool, customClaims map[string]any, optAuthTime ...time.Time) (string, error) {
 	if !m.Collection().IsAuth() {
 		return "", ErrNotAuthRecord
 	}
 
+	if err := validateTokenCustomClaims(customClaims); err != nil {
+		return "", err
+	}
+
 	key := (m.TokenKey() + m.Collection().AuthToken.Secret)
 	if key == "" {
 		return "", ErrMissingSigningKey
@@ -63,6 +140,20 @@ func (m *Record) newAuthToken(duration time.Duration, refreshable bool) (string,
 		TokenClaimId:           m.Id,
 		TokenClaimCollectionId: m.Collection().Id,
 		TokenClaimRefreshable:  refreshable,
+		TokenClaimAuthTime:     time.Now().Unix(),
+	}
+
+	for k, v := range customClaims {
+		claims[k] = v
+	}
+
+	if len(optAuthTime) > 0 && !optAuthTime[0].IsZero() {
+		claims[TokenClaimAuthTime] = optAuthTime[0].Unix()
+	}
+
+	// flag the token until the required profile fields are completed
+	if len(m.MissingProfileFields()) > 0 {
+		claims[TokenClaimProfileIncomplete] = true
 	}
 
 	if duration <= 0 {
@@ -72,6 +163,29 @@ func (m *Record) newAuthToken(duration time.Duration, refreshable bool) (string,
 	return security.NewJWT(claims, key, duration)
 }
 
+func validateTokenCustomClaims(customClaims map[string]any) error {
+	if len(customClaims) == 0 {
+		return nil
+	}
+
+	for _, name := range reservedAuthTokenClaims {
+		if _, ok := customClaims[name]; ok {
+			return fmt.Errorf("%w %q", ErrReservedTokenClaim, name)
+		}
+	}
+
+	raw, err := json.Marshal(customClaims)
+	if err != nil {
+		return err
+	}
+
+	if len(raw) > MaxTokenCustomClaimsSize {
+		return fmt.Errorf("%w (%d > %d bytes)", ErrTokenCustomClaimsLimit, len(raw), MaxTokenCustomClaimsSize)
+	}
+
+	return nil
+}
+
 // NewVerificationToken generates and returns a new record verification token.
 func (m *Record) NewVerificationToken() (string, error) {
 	if !m.Collection().IsAuth() {
@@ -120,6 +234,14 @@ func (m *Record) NewPasswordResetToken() (string, error) {
 
 // NewEmailChangeToken generates and returns a new auth record change email request token.
 func (m *Record) NewEmailChangeToken(newEmail string) (string, error) {
+	return m.NewEmailChangeStageToken(newEmail, "")
+}
+
+// NewEmailChangeStageToken generates and returns a new auth record change email
+// request token for the specified stage (EmailChangeStageApprove or EmailChangeStageConfirm).
+//
+// An empty stage is the same as calling [Record.NewEmailChangeToken].
+func (m *Record) NewEmailChangeStageToken(newEmail string, stage string) (string, error) {
 	if !m.Collection().IsAuth() {
 		return "", ErrNotAuthRecord
 	}
@@ -129,14 +251,19 @@ func (m *Record) NewEmailChangeToken(newEmail string) (string, error) {
 		return "", ErrMissingSigningKey
 	}
 
+	claims := jwt.MapClaims{
+		TokenClaimType:         TokenTypeEmailChange,
+		TokenClaimId:           m.Id,
+		TokenClaimCollectionId: m.Collection().Id,
+		TokenClaimEmail:        m.Email(),
+		TokenClaimNewEmail:     newEmail,
+	}
+	if stage != "" {
+		claims[TokenClaimEmailChangeStage] = stage
+	}
+
 	return security.NewJWT(
-		jwt.MapClaims{
-			TokenClaimType:         TokenTypeEmailChange,
-			TokenClaimId:           m.Id,
-			TokenClaimCollectionId: m.Collection().Id,
-			TokenClaimEmail:        m.Email(),
-			TokenClaimNewEmail:     newEmail,
-		},
+		claims,
 		key,
 		m.Collection().EmailChangeToken.DurationTime(),
 	)
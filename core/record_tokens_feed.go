@@ -0,0 +1,62 @@
+package core
+
+import (
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// DefaultFeedTokenDuration is the default duration of the feed
+// tokens if not explicitly set (see [Record.NewFeedToken]).
+const DefaultFeedTokenDuration = 365 * 24 * time.Hour
+
+// NewFeedToken generates and returns a new long-lived non-refreshable
+// record token that could be used only for reading the feeds of the
+// specified collection (e.g. as query parameter of a calendar subscription URL).
+//
+// The token could be revoked by changing the auth record tokenKey
+// (e.g. on password change).
+//
+// Zero or negative duration will fallback to [DefaultFeedTokenDuration].
+func (m *Record) NewFeedToken(collectionId string, duration time.Duration) (string, error) {
+	if !m.Collection().IsAuth() {
+		return "", ErrNotAuthRecord
+	}
+
+	key := (m.TokenKey() + m.Collection().AuthToken.Secret)
+	if key == "" {
+		return "", ErrMissingSigningKey
+	}
+
+	if duration <= 0 {
+		duration = DefaultFeedTokenDuration
+	}
+
+	return security.NewJWT(
+		jwt.MapClaims{
+			TokenClaimType:         TokenTypeFeed,
+			TokenClaimId:           m.Id,
+			TokenClaimCollectionId: m.Collection().Id,
+			TokenClaimRefreshable:  false,
+			TokenClaimFeed:         collectionId,
+		},
+		key,
+		duration,
+	)
+}
+
+// ParseUnverifiedTokenFeed extracts the feed collection id claim of a feed token
+// WITHOUT verifying its signature (e.g. after [App.FindAuthRecordByToken]).
+//
+// Returns an empty string if the token is not a feed token.
+func ParseUnverifiedTokenFeed(token string) string {
+	claims, err := security.ParseUnverifiedJWT(token)
+	if err != nil || claims[TokenClaimType] != TokenTypeFeed {
+		return ""
+	}
+
+	collectionId, _ := claims[TokenClaimFeed].(string)
+
+	return collectionId
+}
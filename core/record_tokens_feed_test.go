@@ -0,0 +1,104 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func TestNewFeedToken(t *testing.T) {
+	t.Parallel()
+
+	testRecordToken(t, core.TokenTypeFeed, func(record *core.Record) (string, error) {
+		return record.NewFeedToken("c1", 0)
+	}, map[string]any{
+		core.TokenClaimRefreshable: false,
+		core.TokenClaimFeed:        "c1",
+	})
+}
+
+func TestNewFeedTokenDuration(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		duration time.Duration
+		expected time.Duration
+	}{
+		{0, core.DefaultFeedTokenDuration},
+		{-1, core.DefaultFeedTokenDuration},
+		{time.Hour, time.Hour},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.duration.String(), func(t *testing.T) {
+			now := time.Now()
+
+			token, err := user.NewFeedToken("c1", s.duration)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, _ := security.ParseUnverifiedJWT(token)
+			exp, _ := claims.GetExpirationTime()
+			if exp == nil {
+				t.Fatal("Expected exp claim")
+			}
+
+			expectedExp := now.Add(s.expected).Unix()
+			if v := exp.Unix(); v < expectedExp-1 || v > expectedExp+1 {
+				t.Fatalf("Expected exp %d, got %d", expectedExp, v)
+			}
+		})
+	}
+}
+
+func TestParseUnverifiedTokenFeed(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	feedToken, err := user.NewFeedToken("c1", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authToken, err := user.NewAuthToken()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name     string
+		token    string
+		expected string
+	}{
+		{"invalid token", "invalid", ""},
+		{"non-feed token", authToken, ""},
+		{"feed token", feedToken, "c1"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			if v := core.ParseUnverifiedTokenFeed(s.token); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,117 @@
+package core
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// DefaultScopedTokenDuration is the default duration of the scoped
+// auth tokens if not explicitly set (see [Record.NewScopedToken]).
+const DefaultScopedTokenDuration = 5 * time.Minute
+
+// List with the supported scoped token actions.
+const (
+	ScopedTokenActionList   = "list"
+	ScopedTokenActionView   = "view"
+	ScopedTokenActionCreate = "create"
+	ScopedTokenActionUpdate = "update"
+	ScopedTokenActionDelete = "delete"
+	ScopedTokenActionFile   = "file"
+)
+
+// ScopedTokenActions lists all supported scoped token actions.
+var ScopedTokenActions = []string{
+	ScopedTokenActionList,
+	ScopedTokenActionView,
+	ScopedTokenActionCreate,
+	ScopedTokenActionUpdate,
+	ScopedTokenActionDelete,
+	ScopedTokenActionFile,
+}
+
+// TokenScope defines the resource restrictions of a scoped auth token.
+//
+// Note that the scope only narrows the access of the token auth record,
+// aka. the collection API rules are still applied as usual.
+type TokenScope struct {
+	// Collection is the id of the collection the token is restricted to.
+	Collection string `json:"collection"`
+
+	// Record is the optional id of the single collection record the token is restricted to.
+	Record string `json:"record,omitempty"`
+
+	// Actions is a list with the allowed ScopedTokenAction* values.
+	Actions []string `json:"actions"`
+}
+
+// Allows reports whether the scope permits the specified action
+// for the provided collection id and record id.
+//
+// recordId could be empty for the collection level actions (list and create).
+func (s TokenScope) Allows(collectionId string, recordId string, action string) bool {
+	if s.Collection == "" || s.Collection != collectionId {
+		return false
+	}
+
+	if s.Record != "" && s.Record != recordId {
+		return false
+	}
+
+	return slices.Contains(s.Actions, action)
+}
+
+// ParseUnverifiedTokenScope extracts the scope claim of a scoped auth token
+// WITHOUT verifying its signature (e.g. after [App.FindAuthRecordByToken]).
+func ParseUnverifiedTokenScope(token string) (TokenScope, error) {
+	scope := TokenScope{}
+
+	claims, err := security.ParseUnverifiedJWT(token)
+	if err != nil {
+		return scope, err
+	}
+
+	raw, err := json.Marshal(claims[TokenClaimScope])
+	if err != nil {
+		return scope, err
+	}
+
+	err = json.Unmarshal(raw, &scope)
+
+	return scope, err
+}
+
+// NewScopedToken generates and returns a new short-lived non-refreshable
+// record auth token restricted to the provided scope
+// (e.g. for passing to iframes, workers or third-party services).
+//
+// Zero or negative duration will fallback to [DefaultScopedTokenDuration].
+func (m *Record) NewScopedToken(scope TokenScope, duration time.Duration) (string, error) {
+	if !m.Collection().IsAuth() {
+		return "", ErrNotAuthRecord
+	}
+
+	key := (m.TokenKey() + m.Collection().AuthToken.Secret)
+	if key == "" {
+		return "", ErrMissingSigningKey
+	}
+
+	if duration <= 0 {
+		duration = DefaultScopedTokenDuration
+	}
+
+	return security.NewJWT(
+		jwt.MapClaims{
+			TokenClaimType:         TokenTypeScoped,
+			TokenClaimId:           m.Id,
+			TokenClaimCollectionId: m.Collection().Id,
+			TokenClaimRefreshable:  false,
+			TokenClaimScope:        scope,
+		},
+		key,
+		duration,
+	)
+}
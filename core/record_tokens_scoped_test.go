@@ -0,0 +1,130 @@
+package core_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+func TestTokenScopeAllows(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		scope      core.TokenScope
+		collection string
+		record     string
+		action     string
+		expected   bool
+	}{
+		{core.TokenScope{}, "", "", "", false},
+		{core.TokenScope{Actions: []string{"view"}}, "", "", "view", false},
+		{core.TokenScope{Collection: "c1", Actions: []string{"view"}}, "c2", "r1", "view", false},
+		{core.TokenScope{Collection: "c1", Actions: []string{"view"}}, "c1", "r1", "update", false},
+		{core.TokenScope{Collection: "c1", Actions: []string{"view"}}, "c1", "r1", "view", true},
+		{core.TokenScope{Collection: "c1", Actions: []string{"list"}}, "c1", "", "list", true},
+		{core.TokenScope{Collection: "c1", Record: "r1", Actions: []string{"view"}}, "c1", "r2", "view", false},
+		{core.TokenScope{Collection: "c1", Record: "r1", Actions: []string{"view"}}, "c1", "", "view", false},
+		{core.TokenScope{Collection: "c1", Record: "r1", Actions: []string{"view", "file"}}, "c1", "r1", "file", true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s_%s_%s", i, s.collection, s.record, s.action), func(t *testing.T) {
+			result := s.scope.Allows(s.collection, s.record, s.action)
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestNewScopedToken(t *testing.T) {
+	t.Parallel()
+
+	scope := core.TokenScope{Collection: "c1", Record: "r1", Actions: []string{"view", "file"}}
+
+	testRecordToken(t, core.TokenTypeScoped, func(record *core.Record) (string, error) {
+		return record.NewScopedToken(scope, 0)
+	}, map[string]any{
+		core.TokenClaimRefreshable: false,
+	})
+}
+
+func TestNewScopedTokenDuration(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		duration time.Duration
+		expected time.Duration
+	}{
+		{0, core.DefaultScopedTokenDuration},
+		{-1, core.DefaultScopedTokenDuration},
+		{time.Minute, time.Minute},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.duration.String(), func(t *testing.T) {
+			now := time.Now()
+
+			token, err := user.NewScopedToken(core.TokenScope{Collection: "c1", Actions: []string{"view"}}, s.duration)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, _ := security.ParseUnverifiedJWT(token)
+			exp, _ := claims.GetExpirationTime()
+			if exp == nil {
+				t.Fatal("Expected exp claim")
+			}
+
+			expectedExp := now.Add(s.expected).Unix()
+			if v := exp.Unix(); v < expectedExp-1 || v > expectedExp+1 {
+				t.Fatalf("Expected exp %d, got %d", expectedExp, v)
+			}
+		})
+	}
+}
+
+func TestParseUnverifiedTokenScope(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scope := core.TokenScope{Collection: "c1", Record: "r1", Actions: []string{"view", "file"}}
+
+	token, err := user.NewScopedToken(scope, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsed, err := core.ParseUnverifiedTokenScope(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parsed.Collection != scope.Collection || parsed.Record != scope.Record || !slices.Equal(parsed.Actions, scope.Actions) {
+		t.Fatalf("Expected scope %v, got %v", scope, parsed)
+	}
+
+	if _, err := core.ParseUnverifiedTokenScope("invalid"); err == nil {
+		t.Fatal("Expected error for invalid token")
+	}
+}
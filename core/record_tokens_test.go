@@ -1,7 +1,9 @@
 package core_test
 
 import (
+	"errors"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
 
@@ -83,6 +85,150 @@ func TestNewAuthToken(t *testing.T) {
 	})
 }
 
+func TestNewAuthTokenSince(t *testing.T) {
+	t.Parallel()
+
+	authTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testRecordToken(t, core.TokenTypeAuth, func(record *core.Record) (string, error) {
+		return record.NewAuthTokenSince(authTime)
+	}, map[string]any{
+		core.TokenClaimRefreshable: true,
+		core.TokenClaimAuthTime:    float64(authTime.Unix()),
+	})
+}
+
+func TestNewAuthTokenSinceZero(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now().Unix()
+
+	token, err := user.NewAuthTokenSince(time.Time{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims, err := security.ParseUnverifiedJWT(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authTime := cast.ToInt64(claims[core.TokenClaimAuthTime])
+	if authTime < now-1 || authTime > now+1 {
+		t.Fatalf("Expected the authTime claim to fallback to the current time %d, got %d", now, authTime)
+	}
+}
+
+func TestNewStaticAuthTokenWithClaims(t *testing.T) {
+	t.Parallel()
+
+	testRecordToken(t, core.TokenTypeAuth, func(record *core.Record) (string, error) {
+		return record.NewStaticAuthTokenWithClaims(0, map[string]any{"plan": "pro"})
+	}, map[string]any{
+		core.TokenClaimRefreshable: false,
+		"plan":                     "pro",
+	})
+}
+
+func TestNewAuthTokenWithClaims(t *testing.T) {
+	t.Parallel()
+
+	authTime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	testRecordToken(t, core.TokenTypeAuth, func(record *core.Record) (string, error) {
+		return record.NewAuthTokenWithClaims(map[string]any{"plan": "pro", "orgs": []string{"a", "b"}}, authTime)
+	}, map[string]any{
+		core.TokenClaimRefreshable: true,
+		core.TokenClaimAuthTime:    float64(authTime.Unix()),
+		"plan":                     "pro",
+	})
+}
+
+func TestNewAuthTokenWithClaimsErrors(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name          string
+		claims        map[string]any
+		expectedError error
+	}{
+		{"nil claims", nil, nil},
+		{"valid claims", map[string]any{"plan": "pro"}, nil},
+		{"reserved system claim", map[string]any{"plan": "pro", core.TokenClaimId: "abc"}, core.ErrReservedTokenClaim},
+		{"reserved jwt claim", map[string]any{"exp": 1}, core.ErrReservedTokenClaim},
+		{"exceeding size limit", map[string]any{"data": strings.Repeat("a", core.MaxTokenCustomClaimsSize)}, core.ErrTokenCustomClaimsLimit},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			_, err := user.NewAuthTokenWithClaims(s.claims)
+
+			if !errors.Is(err, s.expectedError) {
+				t.Fatalf("Expected error %v, got %v", s.expectedError, err)
+			}
+		})
+	}
+}
+
+func TestNewAuthTokenProfileIncomplete(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user.Collection().ProfileCompletion.Enabled = true
+	user.Collection().ProfileCompletion.Fields = []string{"name"}
+
+	scenarios := []struct {
+		name     string
+		expected bool
+	}{
+		{"", true},
+		{"test", false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			user.Set("name", s.name)
+
+			token, err := user.NewAuthToken()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			claims, err := security.ParseUnverifiedJWT(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := cast.ToBool(claims[core.TokenClaimProfileIncomplete]); v != s.expected {
+				t.Fatalf("Expected %s claim %v, got %v", core.TokenClaimProfileIncomplete, s.expected, v)
+			}
+		})
+	}
+}
+
 func TestNewVerificationToken(t *testing.T) {
 	t.Parallel()
 
@@ -107,6 +253,17 @@ func TestNewEmailChangeToken(t *testing.T) {
 	}, nil)
 }
 
+func TestNewEmailChangeStageToken(t *testing.T) {
+	t.Parallel()
+
+	testRecordToken(t, core.TokenTypeEmailChange, func(record *core.Record) (string, error) {
+		return record.NewEmailChangeStageToken("new@example.com", core.EmailChangeStageApprove)
+	}, map[string]any{
+		core.TokenClaimNewEmail:         "new@example.com",
+		core.TokenClaimEmailChangeStage: core.EmailChangeStageApprove,
+	})
+}
+
 func TestNewFileToken(t *testing.T) {
 	t.Parallel()
 
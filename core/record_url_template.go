@@ -0,0 +1,29 @@
+package core
+
+import (
+	"net/url"
+	"regexp"
+)
+
+var recordURLTemplatePlaceholderRegex = regexp.MustCompile(`\{(\w+)\}`)
+
+// ResolveRecordURLTemplate replaces the {fieldName} placeholders of the
+// provided url template with the path escaped record field values
+// (e.g. "https://example.com/posts/{slug}").
+func ResolveRecordURLTemplate(template string, record *Record) string {
+	return recordURLTemplatePlaceholderRegex.ReplaceAllStringFunc(template, func(match string) string {
+		return url.PathEscape(record.GetString(match[1 : len(match)-1]))
+	})
+}
+
+func (cv *collectionValidator) checkRecordURLTemplate(value any) error {
+	template, _ := value.(string)
+
+	for _, match := range recordURLTemplatePlaceholderRegex.FindAllStringSubmatch(template, -1) {
+		if err := cv.checkMappedField(match[1]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,40 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func TestResolveRecordURLTemplate(t *testing.T) {
+	t.Parallel()
+
+	collection := core.NewBaseCollection("test")
+	collection.Fields.Add(&core.TextField{Name: "slug"})
+	collection.Fields.Add(&core.NumberField{Name: "num"})
+
+	record := core.NewRecord(collection)
+	record.Id = "r1"
+	record.Set("slug", "hello world/2")
+	record.Set("num", 12)
+
+	scenarios := []struct {
+		template string
+		expected string
+	}{
+		{"", ""},
+		{"https://example.com", "https://example.com"},
+		{"https://example.com/{id}", "https://example.com/r1"},
+		{"https://example.com/{slug}?n={num}", "https://example.com/hello%20world%2F2?n=12"},
+		{"https://example.com/{missing}", "https://example.com/"},
+		{"https://example.com/{invalid-name}", "https://example.com/{invalid-name}"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.template, func(t *testing.T) {
+			if v := core.ResolveRecordURLTemplate(s.template, record); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
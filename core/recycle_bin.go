@@ -0,0 +1,329 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const RecycleBinTableName = "_recycleBin"
+
+// recycledRecordKey is the internal record key used to mark
+// the deleted records that were moved in the recycle bin.
+const recycledRecordKey = internalCustomFieldKeyPrefix + "_recycled_"
+
+var _ Model = (*RecycledRecord)(nil)
+
+// RecycledRecord defines a single deleted record recycle bin entry.
+//
+// The record files are not deleted from the storage until the entry is purged.
+type RecycledRecord struct {
+	BaseModel
+
+	CollectionId   string `db:"collectionId" json:"collectionId"`
+	CollectionName string `db:"collectionName" json:"collectionName"`
+	RecordId       string `db:"recordId" json:"recordId"`
+
+	// Data contains the raw db values of the deleted record.
+	Data types.JSONMap[any] `db:"data" json:"data"`
+
+	// Files lists the names of the record files that are kept in the storage.
+	Files types.JSONArray[string] `db:"files" json:"files"`
+
+	// Created is the record delete date.
+	Created types.DateTime `db:"created" json:"created"`
+}
+
+// TableName returns the RecycledRecord model table name.
+func (m *RecycledRecord) TableName() string {
+	return RecycleBinTableName
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+//
+// The password hash and tokenKey values are excluded from the serialized data.
+func (m RecycledRecord) MarshalJSON() ([]byte, error) {
+	type alias RecycledRecord // prevent recursion
+
+	data := make(types.JSONMap[any], len(m.Data))
+	for k, v := range m.Data {
+		if k == FieldNamePassword || k == FieldNameTokenKey {
+			continue
+		}
+		data[k] = v
+	}
+	m.Data = data
+
+	return json.Marshal(alias(m))
+}
+
+// BaseFilesPath returns the storage dir path of the recycled record files.
+func (m *RecycledRecord) BaseFilesPath() string {
+	return m.CollectionId + "/" + m.RecordId
+}
+
+// -------------------------------------------------------------------
+
+// RecycleBinQuery returns a new RecycledRecord select query.
+func (app *BaseApp) RecycleBinQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&RecycledRecord{})
+}
+
+// FindRecycledRecordById returns a single RecycledRecord model by its id.
+func (app *BaseApp) FindRecycledRecordById(id string) (*RecycledRecord, error) {
+	result := &RecycledRecord{}
+
+	err := app.RecycleBinQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// RestoreRecycledRecord recreates the deleted record from the provided
+// recycle bin entry and removes the entry from the recycle bin.
+//
+// The restored record is saved with the regular record create validations and hooks
+// (aka. it will fail if the record collection was deleted or if
+// a record with the same unique values was created in the meantime).
+//
+// Note that the relations to the restored record that were removed
+// during its deletion are not restored.
+func (app *BaseApp) RestoreRecycledRecord(item *RecycledRecord) (*Record, error) {
+	collection, err := app.FindCollectionByNameOrId(item.CollectionId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the record collection: %w", err)
+	}
+
+	record := NewRecord(collection)
+
+	for _, field := range collection.Fields {
+		name := field.GetName()
+
+		raw, ok := item.Data[name]
+		if !ok {
+			continue
+		}
+
+		value, err := field.PrepareValue(record, raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to prepare field %q value: %w", name, err)
+		}
+
+		record.SetRaw(name, value)
+
+		// register the existing filenames as original so that
+		// they could pass the file field "new files" validation
+		if field.Type() == FieldTypeFile {
+			record.originalData[name] = value
+		}
+	}
+
+	if record.Id == "" {
+		record.Id = item.RecordId
+	}
+
+	err = app.RunInTransaction(func(txApp App) error {
+		if err := txApp.Save(record); err != nil {
+			return err
+		}
+
+		_, err := txApp.NonconcurrentDB().Delete(RecycleBinTableName, dbx.HashExp{"id": item.Id}).Execute()
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return record, nil
+}
+
+// PurgeRecycledRecord permanently deletes the provided recycle bin entry
+// together with its stored record files.
+func (app *BaseApp) PurgeRecycledRecord(item *RecycledRecord) error {
+	_, err := app.NonconcurrentDB().Delete(RecycleBinTableName, dbx.HashExp{"id": item.Id}).Execute()
+	if err != nil {
+		return err
+	}
+
+	return app.deleteRecycledRecordFiles(item)
+}
+
+// DeleteOldRecycledRecords purges all recycle bin entries that are created before createdBefore.
+func (app *BaseApp) DeleteOldRecycledRecords(createdBefore time.Time) error {
+	formattedDate := createdBefore.UTC().Format(types.DefaultDateLayout)
+
+	for {
+		items := []*RecycledRecord{}
+
+		err := app.RecycleBinQuery().
+			AndWhere(dbx.NewExp("[[created]] <= {:date}", dbx.Params{"date": formattedDate})).
+			OrderBy("created ASC").
+			Limit(100).
+			All(&items)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			if err := app.PurgeRecycledRecord(item); err != nil {
+				return err
+			}
+		}
+
+		if len(items) < 100 {
+			return nil
+		}
+	}
+}
+
+func (app *BaseApp) deleteRecycledRecordFiles(item *RecycledRecord) error {
+	if len(item.Files) == 0 {
+		return nil
+	}
+
+	// the id was reused by another record (e.g. restored from a backup)
+	// so leave the files as they are
+	collection, err := app.FindCachedCollectionByNameOrId(item.CollectionId)
+	if err == nil {
+		if _, err := app.FindRecordById(collection, item.RecordId); err == nil {
+			return nil
+		}
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	if failed := fsys.DeletePrefix(strings.TrimRight(item.BaseFilesPath(), "/") + "/"); len(failed) > 0 {
+		return errors.Join(failed...)
+	}
+
+	return nil
+}
+
+// isRecycledModel reports whether the provided model is a deleted record moved in the recycle bin.
+func isRecycledModel(m Model) bool {
+	var record *Record
+	switch v := m.(type) {
+	case *Record:
+		record = v
+	case RecordProxy:
+		record = v.ProxyRecord()
+	}
+
+	if record == nil {
+		return false
+	}
+
+	recycled, _ := record.GetRaw(recycledRecordKey).(bool)
+
+	return recycled
+}
+
+// registerRecycleBinHooks registers the record delete hook that moves
+// the deleted records in the recycle bin (when enabled in the settings).
+func (app *BaseApp) registerRecycleBinHooks() {
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Id: "__pbRecycleBinDelete__",
+		Func: func(e *RecordEvent) error {
+			collection := e.Record.Collection()
+			if !e.App.Settings().RecycleBin.Enabled || collection.System || collection.IsView() {
+				return e.Next()
+			}
+
+			if e.App.IsTransactional() {
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				return insertRecycledRecord(e.App, e.Record)
+			}
+
+			originalApp := e.App
+			defer func() {
+				e.App = originalApp
+			}()
+
+			return originalApp.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				return insertRecycledRecord(txApp, e.Record)
+			})
+		},
+		Priority: 99,
+	})
+
+	// purge old entries
+	app.Cron().Add("__pbRecycleBinCleanup__", "0 */6 * * *", func() {
+		maxDays := app.Settings().RecycleBin.MaxDays
+		if maxDays <= 0 {
+			return // no limit
+		}
+
+		deleteErr := app.DeleteOldRecycledRecords(time.Now().AddDate(0, 0, -1*maxDays))
+		if deleteErr != nil {
+			app.Logger().Warn("Failed to purge old recycle bin entries", "error", deleteErr)
+		}
+	})
+}
+
+func insertRecycledRecord(txApp App, record *Record) error {
+	data, err := record.dbExport()
+	if err != nil {
+		return err
+	}
+
+	files := []string{}
+	for _, field := range record.Collection().Fields {
+		if field.Type() == FieldTypeFile {
+			files = append(files, record.GetStringSlice(field.GetName())...)
+		}
+	}
+
+	rawData, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	rawFiles, err := json.Marshal(files)
+	if err != nil {
+		return err
+	}
+
+	_, err = txApp.NonconcurrentDB().Insert(RecycleBinTableName, dbx.Params{
+		"id":             GenerateDefaultRandomId(),
+		"collectionId":   record.Collection().Id,
+		"collectionName": record.Collection().Name,
+		"recordId":       record.Id,
+		"data":           string(rawData),
+		"files":          string(rawFiles),
+		"created":        types.NowDateTime().String(),
+	}).Execute()
+	if err != nil {
+		return err
+	}
+
+	// keep the record files in the storage
+	record.SetRaw(recycledRecordKey, true)
+
+	return nil
+}
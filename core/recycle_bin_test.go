@@ -0,0 +1,318 @@
+package core_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRecycleBinDisabled(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	total, err := countRecycledRecords(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected no recycle bin entries, got %d", total)
+	}
+}
+
+func TestRecycleBinDeleteAndRestore(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().RecycleBin.Enabled = true
+
+	record, err := app.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+	originalFiles := storageFiles(t, app, record.BaseFilesPath())
+	if len(originalFiles) == 0 {
+		t.Fatal("Expected the record to have files")
+	}
+	originalJSON, _ := json.Marshal(record)
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRecordById("demo1", record.Id); err == nil {
+		t.Fatal("Expected the record to be deleted")
+	}
+
+	item := &core.RecycledRecord{}
+	if err := app.RecycleBinQuery().AndWhere(dbxRecordIdExp(record.Id)).One(item); err != nil {
+		t.Fatalf("Expected recycle bin entry, got %v", err)
+	}
+
+	if item.CollectionId != record.Collection().Id || item.CollectionName != "demo1" {
+		t.Fatalf("Unexpected entry collection %q (%q)", item.CollectionId, item.CollectionName)
+	}
+	if len(item.Files) != len(record.GetStringSlice("file_many"))+len(record.GetStringSlice("file_one")) {
+		t.Fatalf("Unexpected entry files %v", item.Files)
+	}
+
+	// wait for the background files delete (if any)
+	time.Sleep(100 * time.Millisecond)
+
+	if files := storageFiles(t, app, record.BaseFilesPath()); len(files) != len(originalFiles) {
+		t.Fatalf("Expected the record files to be kept, got %v", files)
+	}
+
+	restored, err := app.RestoreRecycledRecord(item)
+	if err != nil {
+		t.Fatalf("Failed to restore: %v", err)
+	}
+
+	restoredJSON, _ := json.Marshal(restored)
+	if string(restoredJSON) != string(originalJSON) {
+		t.Fatalf("Expected restored record\n%s\ngot\n%s", originalJSON, restoredJSON)
+	}
+
+	if _, err := app.FindRecordById("demo1", record.Id); err != nil {
+		t.Fatalf("Expected the record to be restored, got %v", err)
+	}
+
+	if _, err := app.FindRecycledRecordById(item.Id); err == nil {
+		t.Fatal("Expected the recycle bin entry to be removed")
+	}
+
+	if files := storageFiles(t, app, record.BaseFilesPath()); len(files) != len(originalFiles) {
+		t.Fatalf("Expected the record files to be kept, got %v", files)
+	}
+}
+
+func TestRecycleBinAuthRecord(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().RecycleBin.Enabled = true
+
+	record, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	item := &core.RecycledRecord{}
+	if err := app.RecycleBinQuery().AndWhere(dbxRecordIdExp(record.Id)).One(item); err != nil {
+		t.Fatal(err)
+	}
+
+	if item.Data[core.FieldNamePassword] == "" || item.Data[core.FieldNameTokenKey] == "" {
+		t.Fatal("Expected the password and tokenKey to be stored")
+	}
+
+	raw, err := json.Marshal(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, key := range []string{`"password"`, `"tokenKey"`} {
+		if strings.Contains(string(raw), key) {
+			t.Fatalf("Didn't expect %s in the serialized entry:\n%s", key, raw)
+		}
+	}
+
+	restored, err := app.RestoreRecycledRecord(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !restored.ValidatePassword("1234567890") {
+		t.Fatal("Expected the restored record password to be preserved")
+	}
+
+	if restored.TokenKey() != record.TokenKey() {
+		t.Fatalf("Expected tokenKey %q, got %q", record.TokenKey(), restored.TokenKey())
+	}
+}
+
+func TestRecycleBinSkipSystemCollections(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().RecycleBin.Enabled = true
+
+	superusers, err := app.FindAllRecords(core.CollectionNameSuperusers)
+	if err != nil || len(superusers) == 0 {
+		t.Fatalf("Expected superusers, got %v (%v)", superusers, err)
+	}
+
+	if err := app.Delete(superusers[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	total, err := countRecycledRecords(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected no recycle bin entries, got %d", total)
+	}
+}
+
+func TestRecycleBinRestoreConflict(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().RecycleBin.Enabled = true
+
+	record, err := app.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	item := &core.RecycledRecord{}
+	if err := app.RecycleBinQuery().AndWhere(dbxRecordIdExp(record.Id)).One(item); err != nil {
+		t.Fatal(err)
+	}
+
+	// create a new record with the same id
+	conflict := core.NewRecord(record.Collection())
+	conflict.Id = record.Id
+	if err := app.Save(conflict); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.RestoreRecycledRecord(item); err == nil {
+		t.Fatal("Expected restore error")
+	}
+
+	if _, err := app.FindRecycledRecordById(item.Id); err != nil {
+		t.Fatalf("Expected the recycle bin entry to be kept, got %v", err)
+	}
+
+	// the files must be left untouched since the id is in use
+	if err := app.PurgeRecycledRecord(item); err != nil {
+		t.Fatal(err)
+	}
+	if files := storageFiles(t, app, record.BaseFilesPath()); len(files) == 0 {
+		t.Fatal("Expected the record files to be kept")
+	}
+}
+
+func TestRecycleBinPurgeAndDeleteOld(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().RecycleBin.Enabled = true
+
+	for _, id := range []string{"84nmscqy84lsi1t", "al1h9ijdeojtsjy"} {
+		record, err := app.FindRecordById("demo1", id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := app.Delete(record); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	item := &core.RecycledRecord{}
+	if err := app.RecycleBinQuery().AndWhere(dbxRecordIdExp("84nmscqy84lsi1t")).One(item); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.PurgeRecycledRecord(item); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRecycledRecordById(item.Id); err == nil {
+		t.Fatal("Expected the entry to be deleted")
+	}
+	if files := storageFiles(t, app, item.BaseFilesPath()); len(files) != 0 {
+		t.Fatalf("Expected the record files to be deleted, got %v", files)
+	}
+
+	// entries newer than the date are kept
+	if err := app.DeleteOldRecycledRecords(time.Now().Add(-1 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	total, err := countRecycledRecords(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 1 {
+		t.Fatalf("Expected 1 recycle bin entry, got %d", total)
+	}
+
+	if err := app.DeleteOldRecycledRecords(time.Now().Add(1 * time.Hour)); err != nil {
+		t.Fatal(err)
+	}
+	total, err = countRecycledRecords(app)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if total != 0 {
+		t.Fatalf("Expected no recycle bin entries, got %d", total)
+	}
+}
+
+func countRecycledRecords(app core.App) (int, error) {
+	var total int
+	err := app.RecycleBinQuery().Select("count(*)").Row(&total)
+	return total, err
+}
+
+func dbxRecordIdExp(id string) dbx.Expression {
+	return dbx.HashExp{"recordId": id}
+}
+
+func storageFiles(t testing.TB, app core.App, prefix string) []string {
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	objects, err := fsys.List(prefix + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result := make([]string, 0, len(objects))
+	for _, obj := range objects {
+		// exclude the generated thumbs
+		if strings.Contains(obj.Key, "/thumbs_") {
+			continue
+		}
+		result = append(result, obj.Key)
+	}
+
+	return result
+}
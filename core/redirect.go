@@ -0,0 +1,343 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const RedirectsTableName = "_redirects"
+
+const (
+	storeKeyRedirectsCache     = "@redirectsCache"
+	storeKeyRedirectHitsBuffer = "@redirectHitsBuffer"
+)
+
+var (
+	_ Model         = (*Redirect)(nil)
+	_ PostValidator = (*Redirect)(nil)
+)
+
+// Redirect defines a single managed server-side redirect (aka. short link).
+//
+// GET and HEAD requests matching the Path are redirected to the Target url
+// with the specified Status code (the request query parameters are forwarded).
+// Each redirect has its number of Hits and the LastHit date tracked.
+//
+// Expired redirects (aka. with non-zero Expires date in the past) are ignored.
+type Redirect struct {
+	BaseModel
+
+	Path    string         `db:"path" json:"path"`
+	Target  string         `db:"target" json:"target"`
+	Status  int            `db:"status" json:"status"`
+	Hits    int            `db:"hits" json:"hits"`
+	LastHit types.DateTime `db:"lastHit" json:"lastHit"`
+	Expires types.DateTime `db:"expires" json:"expires"`
+	Created types.DateTime `db:"created" json:"created"`
+	Updated types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the Redirect model table name.
+func (m *Redirect) TableName() string {
+	return RedirectsTableName
+}
+
+// IsExpired reports whether the redirect has an expiration date in the past.
+func (m *Redirect) IsExpired() bool {
+	return !m.Expires.IsZero() && m.Expires.Time().Before(time.Now())
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *Redirect) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(
+			&m.Path,
+			validation.Required,
+			validation.Length(1, 500),
+			validation.By(checkRedirectPath),
+			validation.By(m.checkUniquePath(app)),
+		),
+		validation.Field(&m.Target, validation.Required, validation.Length(1, 2000), validation.By(checkRedirectTarget)),
+		validation.Field(&m.Status, validation.Required, validation.In(301, 302, 307, 308)),
+	)
+}
+
+func checkRedirectPath(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if !strings.HasPrefix(v, "/") ||
+		strings.ContainsAny(v, "?# ") ||
+		(v != "/" && strings.HasSuffix(v, "/")) {
+		return validation.NewError("validation_invalid_redirect_path", "The path must start with / and must not contain query, fragment, whitespace or trailing slash.")
+	}
+
+	// never shadow the api and the dashboard routes
+	if v == "/api" || v == "/_" || strings.HasPrefix(v, "/api/") || strings.HasPrefix(v, "/_/") {
+		return validation.NewError("validation_reserved_redirect_path", "The /api and /_ paths are reserved.")
+	}
+
+	return nil
+}
+
+func checkRedirectTarget(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	// local path
+	if strings.HasPrefix(v, "/") && !strings.HasPrefix(v, "//") {
+		return nil
+	}
+
+	if !strings.HasPrefix(v, "http://") && !strings.HasPrefix(v, "https://") {
+		return validation.NewError("validation_invalid_redirect_target", "The target must be a local path or an absolute http(s) url.")
+	}
+
+	return is.URL.Validate(v)
+}
+
+func (m *Redirect) checkUniquePath(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		var exists int
+
+		err := app.RedirectQuery().
+			Select("(1)").
+			AndWhere(dbx.HashExp{"path": v}).
+			AndWhere(dbx.Not(dbx.HashExp{"id": m.Id})).
+			Limit(1).
+			Row(&exists)
+
+		if exists > 0 || (err != nil && !errors.Is(err, sql.ErrNoRows)) {
+			return validation.NewError("validation_redirect_path_exists", "Redirect with the provided path already exists.")
+		}
+
+		return nil
+	}
+}
+
+// -------------------------------------------------------------------
+
+// RedirectQuery returns a new Redirect select query.
+func (app *BaseApp) RedirectQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&Redirect{})
+}
+
+// FindRedirectById returns a single Redirect model by its id.
+func (app *BaseApp) FindRedirectById(id string) (*Redirect, error) {
+	result := &Redirect{}
+
+	err := app.RedirectQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindCachedRedirectByPath returns a single Redirect model by its path
+// (trailing slashes are ignored).
+//
+// The redirects are cached and the cache is reset on every redirect change.
+//
+// NB! The returned redirect is shared and must not be modified
+// (its Hits and LastHit values could be also outdated).
+func (app *BaseApp) FindCachedRedirectByPath(path string) (*Redirect, error) {
+	redirects, ok := app.Store().Get(storeKeyRedirectsCache).(map[string]*Redirect)
+	if !ok {
+		all := []*Redirect{}
+
+		if err := app.RedirectQuery().All(&all); err != nil {
+			return nil, err
+		}
+
+		redirects = make(map[string]*Redirect, len(all))
+		for _, r := range all {
+			redirects[r.Path] = r
+		}
+
+		app.Store().Set(storeKeyRedirectsCache, redirects)
+	}
+
+	if len(path) > 1 {
+		path = strings.TrimRight(path, "/")
+		if path == "" {
+			path = "/"
+		}
+	}
+
+	redirect, ok := redirects[path]
+	if !ok {
+		return nil, sql.ErrNoRows
+	}
+
+	return redirect, nil
+}
+
+type redirectHits struct {
+	count   int
+	lastHit types.DateTime
+}
+
+type redirectHitsBuffer struct {
+	items map[string]*redirectHits // redirect id -> hits since the last flush
+	mu    sync.Mutex
+}
+
+func (app *BaseApp) redirectHitsBuffer() *redirectHitsBuffer {
+	return app.Store().GetOrSet(storeKeyRedirectHitsBuffer, func() any {
+		return &redirectHitsBuffer{items: map[string]*redirectHits{}}
+	}).(*redirectHitsBuffer)
+}
+
+// TrackRedirectHit increments the in-memory hits counter of the provided
+// redirect that will be persisted with the next [App.FlushRedirectHits] call.
+func (app *BaseApp) TrackRedirectHit(redirect *Redirect) {
+	buf := app.redirectHitsBuffer()
+
+	buf.mu.Lock()
+	defer buf.mu.Unlock()
+
+	hits, ok := buf.items[redirect.Id]
+	if !ok {
+		hits = &redirectHits{}
+		buf.items[redirect.Id] = hits
+	}
+	hits.count++
+	hits.lastHit = types.NowDateTime()
+}
+
+// FlushRedirectHits persists the in-memory tracked redirect hits
+// by incrementing the redirects hits counter and updating their last hit date.
+//
+// Note that the counters are updated directly in the db
+// without triggering the model hooks.
+func (app *BaseApp) FlushRedirectHits() error {
+	buf := app.redirectHitsBuffer()
+
+	buf.mu.Lock()
+	items := buf.items
+	buf.items = map[string]*redirectHits{}
+	buf.mu.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	err := app.RunInTransaction(func(txApp App) error {
+		for id, hits := range items {
+			_, err := txApp.NonconcurrentDB().Update(
+				RedirectsTableName,
+				dbx.Params{
+					"hits":    dbx.NewExp("[[hits]] + {:hits}", dbx.Params{"hits": hits.count}),
+					"lastHit": hits.lastHit.String(),
+				},
+				dbx.HashExp{"id": id},
+			).Execute()
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	// restore the counters so that they could be retried with the next flush
+	if err != nil {
+		buf.mu.Lock()
+		for id, hits := range items {
+			if current, ok := buf.items[id]; ok {
+				current.count += hits.count
+			} else {
+				buf.items[id] = hits
+			}
+		}
+		buf.mu.Unlock()
+	}
+
+	return err
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerRedirectHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*Redirect); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*Redirect); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// reset the redirects cache on every redirect change
+	resetCache := func(e *ModelEvent) error {
+		if _, ok := e.Model.(*Redirect); ok {
+			app.Store().Remove(storeKeyRedirectsCache)
+		}
+
+		return e.Next()
+	}
+	app.OnModelAfterCreateSuccess().BindFunc(resetCache)
+	app.OnModelAfterUpdateSuccess().BindFunc(resetCache)
+	app.OnModelAfterDeleteSuccess().BindFunc(resetCache)
+
+	app.Cron().Add("__pbRedirectHits__", "* * * * *", func() {
+		if err := app.FlushRedirectHits(); err != nil {
+			app.Logger().Warn("Failed to flush the redirect hits", "error", err)
+		}
+	})
+
+	// persist the remaining hits before exit
+	app.OnTerminate().Bind(&hook.Handler[*TerminateEvent]{
+		Id: "__pbRedirectHitsOnTerminate__",
+		Func: func(e *TerminateEvent) error {
+			if err := e.App.FlushRedirectHits(); err != nil {
+				e.App.Logger().Warn("Failed to flush the redirect hits", "error", err)
+			}
+
+			return e.Next()
+		},
+		Priority: -998, // before the logger flush
+	})
+}
@@ -0,0 +1,251 @@
+package core_test
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestRedirectTableName(t *testing.T) {
+	t.Parallel()
+
+	if v := (&core.Redirect{}).TableName(); v != "_redirects" {
+		t.Fatalf("Expected %q, got %q", "_redirects", v)
+	}
+}
+
+func TestRedirectIsExpired(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name     string
+		expires  types.DateTime
+		expected bool
+	}{
+		{"zero", types.DateTime{}, false},
+		{"past", types.NowDateTime().Add(-1000), true},
+		{"future", types.NowDateTime().AddDate(0, 0, 1), false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			redirect := &core.Redirect{Expires: s.expires}
+
+			if v := redirect.IsExpired(); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestRedirectValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	existing := &core.Redirect{Path: "/test", Target: "/new", Status: 302}
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	newRedirect := func(path string, target string, status int) *core.Redirect {
+		redirect := &core.Redirect{Path: path, Target: target, Status: status}
+		redirect.Id = "test"
+		return redirect
+	}
+
+	scenarios := []struct {
+		name         string
+		redirect     *core.Redirect
+		expectedErrs []string
+	}{
+		{
+			"empty",
+			&core.Redirect{},
+			[]string{"id", "path", "target", "status"},
+		},
+		{
+			"duplicated path",
+			newRedirect("/test", "/new", 302),
+			[]string{"path"},
+		},
+		{
+			"relative path and target",
+			newRedirect("test", "new", 302),
+			[]string{"path", "target"},
+		},
+		{
+			"path with query and protocol-relative target",
+			newRedirect("/new?a=1", "//example.com", 302),
+			[]string{"path", "target"},
+		},
+		{
+			"path with trailing slash and non-http target",
+			newRedirect("/new/", "ftp://example.com", 302),
+			[]string{"path", "target"},
+		},
+		{
+			"reserved api path",
+			newRedirect("/api/test", "/new", 302),
+			[]string{"path"},
+		},
+		{
+			"reserved dashboard path",
+			newRedirect("/_", "/new", 302),
+			[]string{"path"},
+		},
+		{
+			"invalid status",
+			newRedirect("/new", "/test", 200),
+			[]string{"status"},
+		},
+		{
+			"updating the existing redirect",
+			existing,
+			[]string{},
+		},
+		{
+			"valid local target",
+			newRedirect("/", "/new?a=1#b", 301),
+			[]string{},
+		},
+		{
+			"valid absolute target",
+			newRedirect("/s/abc", "https://example.com/test?a=1", 308),
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, app.Validate(s.redirect), s.expectedErrs)
+		})
+	}
+}
+
+func TestFindCachedRedirectByPath(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	redirect := &core.Redirect{Path: "/test", Target: "/new", Status: 302}
+	if err := app.Save(redirect); err != nil {
+		t.Fatal(err)
+	}
+
+	root := &core.Redirect{Path: "/", Target: "/home", Status: 302}
+	if err := app.Save(root); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		path     string
+		expected string
+	}{
+		{"", ""},
+		{"/missing", ""},
+		{"/test/a", ""},
+		{"/", root.Id},
+		{"//", root.Id},
+		{"/test", redirect.Id},
+		{"/test/", redirect.Id},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.path, func(t *testing.T) {
+			result, err := app.FindCachedRedirectByPath(s.path)
+
+			if s.expected == "" {
+				if !errors.Is(err, sql.ErrNoRows) {
+					t.Fatalf("Expected sql.ErrNoRows, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result.Id != s.expected {
+				t.Fatalf("Expected redirect %q, got %q", s.expected, result.Id)
+			}
+		})
+	}
+
+	t.Run("cache reset", func(t *testing.T) {
+		redirect.Target = "/changed"
+		if err := app.Save(redirect); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := app.FindCachedRedirectByPath("/test")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if result.Target != "/changed" {
+			t.Fatalf("Expected the updated target, got %q", result.Target)
+		}
+
+		if err := app.Delete(redirect); err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err := app.FindCachedRedirectByPath("/test"); !errors.Is(err, sql.ErrNoRows) {
+			t.Fatalf("Expected sql.ErrNoRows after delete, got %v", err)
+		}
+	})
+}
+
+func TestTrackRedirectHit(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	redirect := &core.Redirect{Path: "/test", Target: "/new", Status: 302}
+	if err := app.Save(redirect); err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 3; i++ {
+		app.TrackRedirectHit(redirect)
+	}
+
+	// not persisted until flush
+	beforeFlush, err := app.FindRedirectById(redirect.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if beforeFlush.Hits != 0 {
+		t.Fatalf("Expected 0 hits before flush, got %d", beforeFlush.Hits)
+	}
+
+	if err := app.FlushRedirectHits(); err != nil {
+		t.Fatal(err)
+	}
+
+	// subsequent flush without new hits shouldn't change the counter
+	if err := app.FlushRedirectHits(); err != nil {
+		t.Fatal(err)
+	}
+
+	updated, err := app.FindRedirectById(redirect.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if updated.Hits != 3 {
+		t.Fatalf("Expected 3 hits, got %d", updated.Hits)
+	}
+
+	if updated.LastHit.IsZero() {
+		t.Fatal("Expected non-zero last hit date")
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"database/sql"
+	"errors"
+
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+// requestRuleFields lists the fields that are allowed to be used in
+// rules that are not bound to a collection (eg. app config entry read rules).
+var requestRuleFields = []string{
+	`^\@request\.context$`,
+	`^\@request\.method$`,
+	`^\@request\.auth\.[\w\.\:]*\w+$`,
+	`^\@request\.query\.[\w\.\:]*\w+$`,
+	`^\@request\.headers\.[\w\.\:]*\w+$`,
+}
+
+// newRequestRuleResolver returns a new resolver for rules that are not bound
+// to any collection and where only the request fields could be used.
+func newRequestRuleResolver(app App, requestInfo *RequestInfo) *RecordFieldResolver {
+	resolver := NewRecordFieldResolver(app, NewBaseCollection("@requestRule"), requestInfo, true)
+	resolver.SetAllowedFields(requestRuleFields)
+
+	return resolver
+}
+
+// validateRequestRule checks whether the specified rule is a valid request rule filter expression.
+func validateRequestRule(app App, rule string) error {
+	_, err := search.FilterData(rule).BuildExpr(newRequestRuleResolver(app, nil))
+
+	return err
+}
+
+// checkRequestRule reports whether the non-empty request rule
+// is satisfied by the specified requestInfo.
+func checkRequestRule(app App, rule string, requestInfo *RequestInfo) (bool, error) {
+	resolver := newRequestRuleResolver(app, requestInfo)
+
+	expr, err := search.FilterData(rule).BuildExpr(resolver)
+	if err != nil {
+		return false, err
+	}
+
+	// the rule is not bound to a table so a dummy single row source is used
+	// to allow joining the @request.auth.* related fields
+	query := app.ConcurrentDB().Select("(1)").From("(SELECT 1) {{_requestRule}}")
+
+	if err := resolver.UpdateQuery(query); err != nil {
+		return false, err
+	}
+
+	var exists int
+
+	err = query.AndWhere(expr).Limit(1).Row(&exists)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return false, err
+	}
+
+	return exists > 0, nil
+}
@@ -0,0 +1,510 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	RolesTableName           = "_roles"
+	RoleAssignmentsTableName = "_roleAssignments"
+)
+
+// RolePermissionWildcard is the permission that grants all other permissions.
+const RolePermissionWildcard = "*"
+
+const storeKeyRolesCache = "@rolesCache"
+
+var (
+	_ Model         = (*Role)(nil)
+	_ PostValidator = (*Role)(nil)
+	_ Model         = (*RoleAssignment)(nil)
+	_ PostValidator = (*RoleAssignment)(nil)
+)
+
+var (
+	roleNameRegex       = regexp.MustCompile(`^[\w\-\.]+$`)
+	rolePermissionRegex = regexp.MustCompile(`^(\*|[\w\-]+(\.[\w\-]+)*(\.\*)?)$`)
+)
+
+// Role defines a named set of permissions that could be assigned to auth records
+// and checked in the API rules with @request.auth.hasRole("name") and
+// @request.auth.hasPermission("permission").
+//
+// The permissions are free-form dot separated strings (eg. "orders.approve")
+// and could end with a wildcard to grant all permissions with the same prefix
+// (eg. "orders.*"). The "*" permission grants all permissions.
+type Role struct {
+	BaseModel
+
+	Name        string                  `db:"name" json:"name"`
+	Description string                  `db:"description" json:"description"`
+	Permissions types.JSONArray[string] `db:"permissions" json:"permissions"`
+	Created     types.DateTime          `db:"created" json:"created"`
+	Updated     types.DateTime          `db:"updated" json:"updated"`
+}
+
+// TableName returns the Role model table name.
+func (m *Role) TableName() string {
+	return RolesTableName
+}
+
+// HasPermission reports whether the role grants the specified permission.
+func (m *Role) HasPermission(permission string) bool {
+	if permission == "" {
+		return false
+	}
+
+	for _, p := range m.Permissions {
+		if p == RolePermissionWildcard || p == permission {
+			return true
+		}
+
+		if prefix, ok := strings.CutSuffix(p, ".*"); ok && strings.HasPrefix(permission, prefix+".") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *Role) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(
+			&m.Name,
+			validation.Required,
+			validation.Length(1, 100),
+			validation.Match(roleNameRegex),
+			validation.By(m.checkUniqueName(app)),
+		),
+		validation.Field(&m.Description, validation.Length(0, 1000)),
+		validation.Field(
+			&m.Permissions,
+			validation.Each(validation.Required, validation.Length(1, 255), validation.Match(rolePermissionRegex)),
+		),
+	)
+}
+
+func (m *Role) checkUniqueName(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		var exists int
+
+		err := app.RoleQuery().
+			Select("(1)").
+			AndWhere(dbx.HashExp{"name": v}).
+			AndWhere(dbx.Not(dbx.HashExp{"id": m.Id})).
+			Limit(1).
+			Row(&exists)
+
+		if exists > 0 || (err != nil && !errors.Is(err, sql.ErrNoRows)) {
+			return validation.NewError("validation_role_name_exists", "Role with the provided name already exists.")
+		}
+
+		return nil
+	}
+}
+
+// RoleAssignment defines a single role assignment to an auth record.
+type RoleAssignment struct {
+	BaseModel
+
+	RoleRef       string         `db:"roleRef" json:"roleRef"`
+	CollectionRef string         `db:"collectionRef" json:"collectionRef"`
+	RecordRef     string         `db:"recordRef" json:"recordRef"`
+	Created       types.DateTime `db:"created" json:"created"`
+	Updated       types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the RoleAssignment model table name.
+func (m *RoleAssignment) TableName() string {
+	return RoleAssignmentsTableName
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *RoleAssignment) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(&m.RoleRef, validation.Required, validation.By(func(value any) error {
+			v, _ := value.(string)
+			if _, err := app.FindRoleById(v); err != nil {
+				return validation.NewError("validation_invalid_role", "Missing or invalid role.")
+			}
+			return nil
+		})),
+		validation.Field(&m.CollectionRef, validation.Required, validation.By(validateCollectionId(app, CollectionTypeAuth))),
+		validation.Field(&m.RecordRef, validation.Required, validation.By(validateRecordId(app, m.CollectionRef))),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// RoleQuery returns a new Role select query.
+func (app *BaseApp) RoleQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&Role{})
+}
+
+// FindRoleById returns a single Role model by its id.
+func (app *BaseApp) FindRoleById(id string) (*Role, error) {
+	result := &Role{}
+
+	err := app.RoleQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRoleByName returns a single Role model by its name.
+func (app *BaseApp) FindRoleByName(name string) (*Role, error) {
+	result := &Role{}
+
+	err := app.RoleQuery().
+		AndWhere(dbx.HashExp{"name": name}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindAuthRecordRoles returns all roles assigned to the provided auth record (ordered by name).
+func (app *BaseApp) FindAuthRecordRoles(authRecord *Record) ([]*Role, error) {
+	result := []*Role{}
+
+	err := app.RoleQuery().
+		InnerJoin(
+			RoleAssignmentsTableName+" ra",
+			dbx.NewExp("[[ra.roleRef]] = {{"+RolesTableName+"}}.[[id]]"),
+		).
+		AndWhere(dbx.HashExp{
+			"ra.collectionRef": authRecord.Collection().Id,
+			"ra.recordRef":     authRecord.Id,
+		}).
+		OrderBy(RolesTableName + ".name ASC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AssignRole assigns the role to the provided auth record.
+//
+// It does nothing if the role is already assigned.
+func (app *BaseApp) AssignRole(authRecord *Record, role *Role) error {
+	if !authRecord.Collection().IsAuth() {
+		return errors.New("roles could be assigned only to auth records")
+	}
+
+	var exists int
+
+	err := app.ModelQuery(&RoleAssignment{}).
+		Select("(1)").
+		AndWhere(dbx.HashExp{
+			"roleRef":       role.Id,
+			"collectionRef": authRecord.Collection().Id,
+			"recordRef":     authRecord.Id,
+		}).
+		Limit(1).
+		Row(&exists)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	if exists > 0 {
+		return nil // already assigned
+	}
+
+	return app.Save(&RoleAssignment{
+		RoleRef:       role.Id,
+		CollectionRef: authRecord.Collection().Id,
+		RecordRef:     authRecord.Id,
+	})
+}
+
+// UnassignRole removes the role assignment of the provided auth record (if any).
+func (app *BaseApp) UnassignRole(authRecord *Record, role *Role) error {
+	assignments := []*RoleAssignment{}
+
+	err := app.ModelQuery(&RoleAssignment{}).
+		AndWhere(dbx.HashExp{
+			"roleRef":       role.Id,
+			"collectionRef": authRecord.Collection().Id,
+			"recordRef":     authRecord.Id,
+		}).
+		All(&assignments)
+	if err != nil {
+		return err
+	}
+
+	for _, a := range assignments {
+		if err := app.Delete(a); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// AuthRecordHasRole reports whether the provided auth record has the named role.
+//
+// The auth record roles are cached and the cache is reset on every roles change.
+//
+// It returns false on nil authRecord or if the roles loading fails.
+func (app *BaseApp) AuthRecordHasRole(authRecord *Record, roleName string) bool {
+	roles, err := app.cachedAuthRecordRoles(authRecord)
+	if err != nil {
+		app.Logger().Debug("Failed to load the auth record roles", "error", err)
+		return false
+	}
+
+	return slices.ContainsFunc(roles, func(r *Role) bool {
+		return r.Name == roleName
+	})
+}
+
+// AuthRecordHasPermission reports whether any of the provided
+// auth record roles grants the specified permission.
+//
+// Superusers have all permissions.
+//
+// The auth record roles are cached and the cache is reset on every roles change.
+//
+// It returns false on nil authRecord or if the roles loading fails.
+func (app *BaseApp) AuthRecordHasPermission(authRecord *Record, permission string) bool {
+	if authRecord != nil && authRecord.IsSuperuser() {
+		return true
+	}
+
+	roles, err := app.cachedAuthRecordRoles(authRecord)
+	if err != nil {
+		app.Logger().Debug("Failed to load the auth record roles", "error", err)
+		return false
+	}
+
+	return slices.ContainsFunc(roles, func(r *Role) bool {
+		return r.HasPermission(permission)
+	})
+}
+
+// maxRolesCacheItems is the max number of the cached auth records roles
+// (when the limit is reached, a random cached item is evicted).
+const maxRolesCacheItems = 5000
+
+type rolesCache struct {
+	items map[string][]*Role // "collectionId/recordId" -> auth record roles
+	mu    sync.RWMutex
+
+	// generation is incremented on every cache reset and it is used
+	// to discard the roles loaded before the reset (aka. stale roles)
+	generation uint64
+}
+
+func (app *BaseApp) rolesCache() *rolesCache {
+	return app.Store().GetOrSet(storeKeyRolesCache, func() any {
+		return &rolesCache{items: map[string][]*Role{}}
+	}).(*rolesCache)
+}
+
+func (app *BaseApp) resetRolesCache() {
+	cache := app.rolesCache()
+
+	cache.mu.Lock()
+	cache.items = map[string][]*Role{}
+	cache.generation++
+	cache.mu.Unlock()
+}
+
+func (app *BaseApp) cachedAuthRecordRoles(authRecord *Record) ([]*Role, error) {
+	if authRecord == nil {
+		return nil, nil
+	}
+
+	// the roles could be changed as part of the transaction
+	// and the transaction could be rolled back so skip the cache
+	if app.IsTransactional() {
+		return app.FindAuthRecordRoles(authRecord)
+	}
+
+	key := authRecord.Collection().Id + "/" + authRecord.Id
+
+	cache := app.rolesCache()
+
+	cache.mu.RLock()
+	roles, ok := cache.items[key]
+	generation := cache.generation
+	cache.mu.RUnlock()
+	if ok {
+		return roles, nil
+	}
+
+	roles, err := app.FindAuthRecordRoles(authRecord)
+	if err != nil {
+		return nil, err
+	}
+
+	cache.mu.Lock()
+	// store only if there were no roles changes while loading
+	if cache.generation == generation {
+		if len(cache.items) >= maxRolesCacheItems {
+			for k := range cache.items {
+				delete(cache.items, k)
+				break
+			}
+		}
+		cache.items[key] = roles
+	}
+	cache.mu.Unlock()
+
+	return roles, nil
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerRoleHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			var created *types.DateTime
+			var updated *types.DateTime
+
+			switch m := e.Model.(type) {
+			case *Role:
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				created, updated = &m.Created, &m.Updated
+			case *RoleAssignment:
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				created, updated = &m.Created, &m.Updated
+			}
+
+			if created != nil {
+				*created = types.NowDateTime()
+				*updated = *created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			switch m := e.Model.(type) {
+			case *Role:
+				m.Updated = types.NowDateTime()
+			case *RoleAssignment:
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// delete the role assignments together with the role
+	app.OnModelDeleteExecute().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if m, ok := e.Model.(*Role); ok {
+				_, err := e.App.NonconcurrentDB().Delete(RoleAssignmentsTableName, dbx.HashExp{"roleRef": m.Id}).Execute()
+				return err
+			}
+
+			return nil
+		},
+		Priority: 99,
+	})
+
+	// delete the collection and auth record role assignments
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil || !e.Collection.IsAuth() {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(RoleAssignmentsTableName, dbx.HashExp{
+				"collectionRef": e.Collection.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil || !e.Record.Collection().IsAuth() {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(RoleAssignmentsTableName, dbx.HashExp{
+				"collectionRef": e.Record.Collection().Id,
+				"recordRef":     e.Record.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	// reset the roles cache on every roles change
+	resetCache := func(e *ModelEvent) error {
+		switch m := e.Model.(type) {
+		case *Role, *RoleAssignment:
+			app.resetRolesCache()
+		case *Collection:
+			if m.IsAuth() {
+				app.resetRolesCache()
+			}
+		case *Record:
+			if m.Collection().IsAuth() {
+				app.resetRolesCache()
+			}
+		}
+
+		return e.Next()
+	}
+	app.OnModelAfterCreateSuccess().BindFunc(resetCache)
+	app.OnModelAfterUpdateSuccess().BindFunc(func(e *ModelEvent) error {
+		switch e.Model.(type) {
+		case *Role, *RoleAssignment:
+			app.resetRolesCache()
+		}
+
+		return e.Next()
+	})
+	app.OnModelAfterDeleteSuccess().BindFunc(resetCache)
+}
@@ -0,0 +1,472 @@
+package core_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+)
+
+func TestRoleTableName(t *testing.T) {
+	t.Parallel()
+
+	if v := (&core.Role{}).TableName(); v != "_roles" {
+		t.Fatalf("Expected %q, got %q", "_roles", v)
+	}
+
+	if v := (&core.RoleAssignment{}).TableName(); v != "_roleAssignments" {
+		t.Fatalf("Expected %q, got %q", "_roleAssignments", v)
+	}
+}
+
+func TestRoleHasPermission(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		permissions []string
+		permission  string
+		expected    bool
+	}{
+		{nil, "orders.approve", false},
+		{[]string{"orders.approve"}, "", false},
+		{[]string{"orders.approve"}, "orders.approve", true},
+		{[]string{"orders.approve"}, "orders.approve.all", false},
+		{[]string{"orders.approve"}, "orders", false},
+		{[]string{"orders.*"}, "orders.approve", true},
+		{[]string{"orders.*"}, "orders.approve.all", true},
+		{[]string{"orders.*"}, "orders", false},
+		{[]string{"orders.*"}, "ordersx.approve", false},
+		{[]string{"products.view", "*"}, "orders.approve", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.permission, func(t *testing.T) {
+			role := &core.Role{Permissions: s.permissions}
+
+			result := role.HasPermission(s.permission)
+			if result != s.expected {
+				t.Fatalf("Expected %v for %v, got %v", s.expected, s.permissions, result)
+			}
+		})
+	}
+}
+
+func TestRoleValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	existing := &core.Role{Name: "editor", Permissions: []string{"posts.*"}}
+	if err := app.Save(existing); err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		role         *core.Role
+		expectedErrs []string
+	}{
+		{
+			"empty",
+			&core.Role{},
+			[]string{"id", "name"},
+		},
+		{
+			"invalid name and permissions",
+			&core.Role{BaseModel: core.BaseModel{Id: "test"}, Name: "a b", Permissions: []string{"", "orders..approve", "orders.*.x"}},
+			[]string{"name", "permissions"},
+		},
+		{
+			"duplicated name",
+			&core.Role{BaseModel: core.BaseModel{Id: "test"}, Name: "editor"},
+			[]string{"name"},
+		},
+		{
+			"updating the existing role",
+			existing,
+			[]string{},
+		},
+		{
+			"valid",
+			&core.Role{BaseModel: core.BaseModel{Id: "test"}, Name: "orders.manager", Permissions: []string{"*", "orders.approve", "orders.*"}},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, app.Validate(s.role), s.expectedErrs)
+		})
+	}
+}
+
+func TestRoleAssignmentValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	role := &core.Role{Name: "editor"}
+	if err := app.Save(role); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		assignment   *core.RoleAssignment
+		expectedErrs []string
+	}{
+		{
+			"empty",
+			&core.RoleAssignment{},
+			[]string{"id", "roleRef", "collectionRef", "recordRef"},
+		},
+		{
+			"missing role and record",
+			&core.RoleAssignment{BaseModel: core.BaseModel{Id: "test"}, RoleRef: "missing", CollectionRef: users.Id, RecordRef: "missing"},
+			[]string{"roleRef", "recordRef"},
+		},
+		{
+			"non-auth collection",
+			&core.RoleAssignment{BaseModel: core.BaseModel{Id: "test"}, RoleRef: role.Id, CollectionRef: demo1.Id, RecordRef: "imy661ixudk5izi"},
+			[]string{"collectionRef"},
+		},
+		{
+			"valid",
+			&core.RoleAssignment{BaseModel: core.BaseModel{Id: "test"}, RoleRef: role.Id, CollectionRef: users.Id, RecordRef: "4q1xlclmfloku33"},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, app.Validate(s.assignment), s.expectedErrs)
+		})
+	}
+}
+
+func TestFindRoleByIdAndName(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	role := &core.Role{Name: "editor"}
+	if err := app.Save(role); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.FindRoleById("missing"); err == nil {
+		t.Fatal("Expected FindRoleById error")
+	}
+
+	if _, err := app.FindRoleByName("missing"); err == nil {
+		t.Fatal("Expected FindRoleByName error")
+	}
+
+	r1, err := app.FindRoleById(role.Id)
+	if err != nil || r1.Name != "editor" {
+		t.Fatalf("Expected to find role %q, got %v (%v)", role.Id, r1, err)
+	}
+
+	r2, err := app.FindRoleByName("editor")
+	if err != nil || r2.Id != role.Id {
+		t.Fatalf("Expected to find role %q, got %v (%v)", role.Id, r2, err)
+	}
+}
+
+func TestAuthRecordRoles(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	editor := &core.Role{Name: "editor", Permissions: []string{"posts.*"}}
+	if err := app.Save(editor); err != nil {
+		t.Fatal(err)
+	}
+
+	approver := &core.Role{Name: "approver", Permissions: []string{"orders.approve"}}
+	if err := app.Save(approver); err != nil {
+		t.Fatal(err)
+	}
+
+	demo1Record, err := app.FindRecordById("demo1", "imy661ixudk5izi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.AssignRole(demo1Record, editor); err == nil {
+		t.Fatal("Expected non-auth record assign error")
+	}
+
+	// initial state (also warms the cache)
+	if app.AuthRecordHasRole(user, "editor") || app.AuthRecordHasPermission(user, "orders.approve") {
+		t.Fatal("Expected the user to not have roles")
+	}
+
+	if !app.AuthRecordHasPermission(superuser, "orders.approve") {
+		t.Fatal("Expected the superuser to have all permissions")
+	}
+
+	if app.AuthRecordHasRole(nil, "editor") || app.AuthRecordHasPermission(nil, "orders.approve") {
+		t.Fatal("Expected nil auth record to not have roles and permissions")
+	}
+
+	// assign (twice to ensure that it doesn't fail)
+	for i := 0; i < 2; i++ {
+		if err := app.AssignRole(user, editor); err != nil {
+			t.Fatal(err)
+		}
+		if err := app.AssignRole(user, approver); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	roles, err := app.FindAuthRecordRoles(user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, len(roles))
+	for i, r := range roles {
+		names[i] = r.Name
+	}
+	if expected := []string{"approver", "editor"}; !slices.Equal(names, expected) {
+		t.Fatalf("Expected roles %v, got %v", expected, names)
+	}
+
+	if !app.AuthRecordHasRole(user, "editor") ||
+		!app.AuthRecordHasPermission(user, "orders.approve") ||
+		!app.AuthRecordHasPermission(user, "posts.delete") ||
+		app.AuthRecordHasPermission(user, "orders.delete") {
+		t.Fatal("Expected the assigned roles permissions to be applied")
+	}
+
+	// role update
+	approver.Permissions = []string{"orders.*"}
+	if err := app.Save(approver); err != nil {
+		t.Fatal(err)
+	}
+	if !app.AuthRecordHasPermission(user, "orders.delete") {
+		t.Fatal("Expected the updated role permissions to be applied")
+	}
+
+	// unassign
+	if err := app.UnassignRole(user, approver); err != nil {
+		t.Fatal(err)
+	}
+	if app.AuthRecordHasRole(user, "approver") || app.AuthRecordHasPermission(user, "orders.approve") {
+		t.Fatal("Expected the unassigned role to be no longer applied")
+	}
+
+	// role delete
+	if err := app.Delete(editor); err != nil {
+		t.Fatal(err)
+	}
+	if app.AuthRecordHasRole(user, "editor") {
+		t.Fatal("Expected the deleted role to be no longer applied")
+	}
+}
+
+func TestAuthRecordRolesTransactionCache(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	editor := &core.Role{Name: "editor", Permissions: []string{"posts.*"}}
+	if err := app.Save(editor); err != nil {
+		t.Fatal(err)
+	}
+
+	// warm the cache
+	if app.AuthRecordHasRole(user, "editor") {
+		t.Fatal("Expected the user to not have the editor role")
+	}
+
+	rollbackErr := errors.New("rollback")
+
+	txErr := app.RunInTransaction(func(txApp core.App) error {
+		if err := txApp.AssignRole(user, editor); err != nil {
+			return err
+		}
+
+		if !txApp.AuthRecordHasRole(user, "editor") {
+			t.Fatal("Expected the user to have the editor role within the transaction")
+		}
+
+		return rollbackErr
+	})
+	if !errors.Is(txErr, rollbackErr) {
+		t.Fatalf("Expected the rollback error, got %v", txErr)
+	}
+
+	if app.AuthRecordHasRole(user, "editor") {
+		t.Fatal("Expected the rolled back role assignment to not be applied")
+	}
+}
+
+func TestRoleAssignmentsDeleteCleanup(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	role := &core.Role{Name: "editor"}
+	if err := app.Save(role); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, id := range []string{"4q1xlclmfloku33", "oap640cot4yru2s"} {
+		user, err := app.FindRecordById("users", id)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if err := app.AssignRole(user, role); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	countAssignments := func() int {
+		var total int
+		if err := app.ModelQuery(&core.RoleAssignment{}).Select("count(*)").Row(&total); err != nil {
+			t.Fatal(err)
+		}
+		return total
+	}
+
+	if total := countAssignments(); total != 2 {
+		t.Fatalf("Expected 2 assignments, got %d", total)
+	}
+
+	user, err := app.FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(user); err != nil {
+		t.Fatal(err)
+	}
+
+	if total := countAssignments(); total != 1 {
+		t.Fatalf("Expected 1 assignment after the record delete, got %d", total)
+	}
+
+	if err := app.Delete(role); err != nil {
+		t.Fatal(err)
+	}
+
+	if total := countAssignments(); total != 0 {
+		t.Fatalf("Expected 0 assignments after the role delete, got %d", total)
+	}
+}
+
+func TestRecordFieldResolverRoleFunctions(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	superuser, err := app.FindAuthRecordByEmail(core.CollectionNameSuperusers, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	role := &core.Role{Name: "approver", Permissions: []string{"orders.approve"}}
+	if err := app.Save(role); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.AssignRole(user, role); err != nil {
+		t.Fatal(err)
+	}
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		auth        *core.Record
+		filter      string
+		expectError bool
+		expected    int
+	}{
+		{"unknown function", user, `@request.auth.missing("approver") = true`, true, 0},
+		{"no arguments", user, `@request.auth.hasRole() = true`, true, 0},
+		{"non-text argument", user, `@request.auth.hasRole(title) = true`, true, 0},
+		{"hasRole (guest)", nil, `@request.auth.hasRole("approver") = true`, false, 0},
+		{"hasRole (missing)", user, `@request.auth.hasRole("editor") = true`, false, 0},
+		{"hasRole (assigned)", user, `@request.auth.hasRole("approver") = true`, false, 3},
+		{"hasRole negated", user, `@request.auth.hasRole("approver") = false`, false, 0},
+		{"hasPermission (guest)", nil, `@request.auth.hasPermission("orders.approve") = true`, false, 0},
+		{"hasPermission (missing)", user, `@request.auth.hasPermission("orders.delete") = true`, false, 0},
+		{"hasPermission (granted)", user, `@request.auth.hasPermission("orders.approve") = true && active = true`, false, 2},
+		{"hasPermission (superuser)", superuser, `@request.auth.hasPermission("orders.delete") = true`, false, 3},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			resolver := core.NewRecordFieldResolver(app, collection, &core.RequestInfo{Auth: s.auth}, true)
+
+			expr, err := search.FilterData(s.filter).BuildExpr(resolver)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			ids := []string{}
+
+			query := app.RecordQuery(collection).Select("demo2.id").AndWhere(expr)
+			resolver.UpdateQuery(query)
+
+			if err := query.Column(&ids); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(ids) != s.expected {
+				t.Fatalf("Expected %d records, got %d (%v)", s.expected, len(ids), ids)
+			}
+		})
+	}
+}
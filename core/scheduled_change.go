@@ -0,0 +1,307 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const ScheduledChangesTableName = "_scheduledChanges"
+
+var (
+	_ Model         = (*ScheduledChange)(nil)
+	_ PostValidator = (*ScheduledChange)(nil)
+)
+
+// ScheduledChange defines a declarative future record change
+// (e.g. set status=published at a specific date).
+//
+// The due changes are applied by a cron job as regular record updates
+// (aka. with the standard record update validations and hooks).
+type ScheduledChange struct {
+	BaseModel
+
+	CollectionRef string `db:"collectionRef" json:"collectionRef"`
+	RecordRef     string `db:"recordRef" json:"recordRef"`
+
+	// Data contains the record field values to set
+	// (the field modifiers like "total+" are also supported).
+	Data types.JSONMap[any] `db:"data" json:"data"`
+
+	ScheduledAt types.DateTime `db:"scheduledAt" json:"scheduledAt"`
+
+	// Error contains the last apply error (if any).
+	//
+	// Failed changes are not retried.
+	Error string `db:"error" json:"error"`
+
+	Created types.DateTime `db:"created" json:"created"`
+	Updated types.DateTime `db:"updated" json:"updated"`
+}
+
+// NewScheduledChange initializes and returns a new blank ScheduledChange
+// model associated with the provided record.
+func NewScheduledChange(record *Record) *ScheduledChange {
+	return &ScheduledChange{
+		CollectionRef: record.Collection().Id,
+		RecordRef:     record.Id,
+	}
+}
+
+// TableName returns the ScheduledChange model table name.
+func (m *ScheduledChange) TableName() string {
+	return ScheduledChangesTableName
+}
+
+// IsFailed reports whether the last apply of the change has failed.
+func (m *ScheduledChange) IsFailed() bool {
+	return m.Error != ""
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *ScheduledChange) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(
+			&m.CollectionRef,
+			validation.Required,
+			validation.By(validateCollectionId(app, CollectionTypeBase, CollectionTypeAuth)),
+		),
+		validation.Field(&m.RecordRef, validation.Required, validation.By(validateRecordId(app, m.CollectionRef))),
+		validation.Field(&m.Data, validation.Required, validation.By(m.checkDataFields(app))),
+		validation.Field(&m.ScheduledAt, validation.By(validateDatetimeRequired)),
+	)
+}
+
+func (m *ScheduledChange) checkDataFields(app App) validation.RuleFunc {
+	return func(value any) error {
+		data, _ := value.(types.JSONMap[any])
+
+		collection, err := app.FindCachedCollectionByNameOrId(m.CollectionRef)
+		if err != nil {
+			return nil // already checked by the collectionRef validator
+		}
+
+		for key := range data {
+			name := strings.TrimSuffix(strings.TrimPrefix(key, "+"), "+")
+			name = strings.TrimSuffix(name, "-")
+
+			field := collection.Fields.GetByName(name)
+			if field == nil || name == FieldNameId {
+				return validation.NewError(
+					"validation_invalid_scheduled_field",
+					fmt.Sprintf("Unknown or not schedulable field %q.", key),
+				)
+			}
+
+			switch field.Type() {
+			case FieldTypeFile, FieldTypeAutodate:
+				return validation.NewError(
+					"validation_invalid_scheduled_field",
+					fmt.Sprintf("Unknown or not schedulable field %q.", key),
+				)
+			}
+		}
+
+		return nil
+	}
+}
+
+func validateDatetimeRequired(value any) error {
+	v, _ := value.(types.DateTime)
+	if v.IsZero() {
+		return validation.ErrRequired
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+// ScheduledChangeQuery returns a new ScheduledChange select query.
+func (app *BaseApp) ScheduledChangeQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&ScheduledChange{})
+}
+
+// FindScheduledChangeById returns a single ScheduledChange model by its id.
+func (app *BaseApp) FindScheduledChangeById(id string) (*ScheduledChange, error) {
+	result := &ScheduledChange{}
+
+	err := app.ScheduledChangeQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindRecordScheduledChanges returns all ScheduledChange models
+// of the provided record (ordered by their scheduled date).
+func (app *BaseApp) FindRecordScheduledChanges(record *Record) ([]*ScheduledChange, error) {
+	result := []*ScheduledChange{}
+
+	err := app.ScheduledChangeQuery().
+		AndWhere(dbx.HashExp{
+			"collectionRef": record.Collection().Id,
+			"recordRef":     record.Id,
+		}).
+		OrderBy("scheduledAt ASC", "created ASC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ApplyScheduledChange applies the provided change to its record
+// and deletes the change on success.
+//
+// On failure the change is kept and its Error is updated with the apply error.
+func (app *BaseApp) ApplyScheduledChange(change *ScheduledChange) error {
+	applyErr := app.RunInTransaction(func(txApp App) error {
+		record, err := txApp.FindRecordById(change.CollectionRef, change.RecordRef)
+		if err != nil {
+			return fmt.Errorf("failed to find the change record: %w", err)
+		}
+
+		for key, value := range change.Data {
+			record.Set(key, value)
+		}
+
+		if err := txApp.Save(record); err != nil {
+			return err
+		}
+
+		return txApp.Delete(change)
+	})
+
+	if applyErr != nil {
+		change.Error = applyErr.Error()
+
+		if err := app.SaveNoValidate(change); err != nil {
+			return fmt.Errorf("failed to store the scheduled change apply error: %w", err)
+		}
+
+		return applyErr
+	}
+
+	return nil
+}
+
+// ApplyDueScheduledChanges applies all non-failed scheduled changes
+// with scheduledAt before or equal to the provided date (ordered by their scheduled date).
+//
+// A failed change doesn't prevent applying the remaining ones.
+// The returned error (if any) contains the apply errors of all failed changes.
+func (app *BaseApp) ApplyDueScheduledChanges(date time.Time) error {
+	formattedDate := date.UTC().Format(types.DefaultDateLayout)
+
+	var errs []error
+
+	for {
+		changes := []*ScheduledChange{}
+
+		err := app.ScheduledChangeQuery().
+			AndWhere(dbx.NewExp("[[scheduledAt]] <= {:date}", dbx.Params{"date": formattedDate})).
+			AndWhere(dbx.HashExp{"error": ""}).
+			OrderBy("scheduledAt ASC", "created ASC").
+			Limit(100).
+			All(&changes)
+		if err != nil {
+			return err
+		}
+
+		for _, change := range changes {
+			if err := app.ApplyScheduledChange(change); err != nil {
+				errs = append(errs, fmt.Errorf("scheduled change %q: %w", change.Id, err))
+			}
+		}
+
+		if len(changes) < 100 {
+			break
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerScheduledChangeHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*ScheduledChange); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*ScheduledChange); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// delete the collection and record changes
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(ScheduledChangesTableName, dbx.HashExp{
+				"collectionRef": e.Collection.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(ScheduledChangesTableName, dbx.HashExp{
+				"collectionRef": e.Record.Collection().Id,
+				"recordRef":     e.Record.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.Cron().Add("__pbScheduledChanges__", "* * * * *", func() {
+		if err := app.ApplyDueScheduledChanges(time.Now()); err != nil {
+			app.Logger().Warn("Failed to apply scheduled record changes", "error", err)
+		}
+	})
+}
@@ -0,0 +1,251 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestScheduledChangeValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	demo2Record, err := app.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1Record, err := app.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	future := types.NowDateTime().Add(1 * time.Hour)
+
+	scenarios := []struct {
+		name           string
+		change         func() *core.ScheduledChange
+		expectedErrors []string
+	}{
+		{
+			"empty",
+			func() *core.ScheduledChange {
+				return &core.ScheduledChange{}
+			},
+			[]string{"id", "collectionRef", "recordRef", "data", "scheduledAt"},
+		},
+		{
+			"missing record",
+			func() *core.ScheduledChange {
+				change := core.NewScheduledChange(demo2Record)
+				change.Id = "test"
+				change.RecordRef = "missing"
+				change.Data = types.JSONMap[any]{"active": false}
+				change.ScheduledAt = future
+				return change
+			},
+			[]string{"recordRef"},
+		},
+		{
+			"unknown field",
+			func() *core.ScheduledChange {
+				change := core.NewScheduledChange(demo2Record)
+				change.Id = "test"
+				change.Data = types.JSONMap[any]{"missing": 1}
+				change.ScheduledAt = future
+				return change
+			},
+			[]string{"data"},
+		},
+		{
+			"id field",
+			func() *core.ScheduledChange {
+				change := core.NewScheduledChange(demo2Record)
+				change.Id = "test"
+				change.Data = types.JSONMap[any]{"id": "abc"}
+				change.ScheduledAt = future
+				return change
+			},
+			[]string{"data"},
+		},
+		{
+			"autodate field",
+			func() *core.ScheduledChange {
+				change := core.NewScheduledChange(demo2Record)
+				change.Id = "test"
+				change.Data = types.JSONMap[any]{"updated": "2024-01-01 00:00:00.000Z"}
+				change.ScheduledAt = future
+				return change
+			},
+			[]string{"data"},
+		},
+		{
+			"file field",
+			func() *core.ScheduledChange {
+				change := core.NewScheduledChange(demo1Record)
+				change.Id = "test"
+				change.Data = types.JSONMap[any]{"file_one": ""}
+				change.ScheduledAt = future
+				return change
+			},
+			[]string{"data"},
+		},
+		{
+			"valid data with modifiers",
+			func() *core.ScheduledChange {
+				change := core.NewScheduledChange(demo1Record)
+				change.Id = "test"
+				change.Data = types.JSONMap[any]{"text": "abc", "number+": 1, "select_many-": "optionB"}
+				change.ScheduledAt = future
+				return change
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			errs := app.Validate(s.change())
+			tests.TestValidationErrors(t, errs, s.expectedErrors)
+		})
+	}
+}
+
+func TestApplyDueScheduledChanges(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record, err := app.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := types.NowDateTime()
+
+	due := core.NewScheduledChange(record)
+	due.Data = types.JSONMap[any]{"active": false, "title": "published"}
+	due.ScheduledAt = now.Add(-1 * time.Minute)
+
+	failing := core.NewScheduledChange(record)
+	failing.Data = types.JSONMap[any]{"title": "a"} // min 2 chars
+	failing.ScheduledAt = now.Add(-2 * time.Minute)
+
+	future := core.NewScheduledChange(record)
+	future.Data = types.JSONMap[any]{"active": true}
+	future.ScheduledAt = now.Add(1 * time.Hour)
+
+	for _, change := range []*core.ScheduledChange{due, failing, future} {
+		if err := app.Save(change); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	app.ResetEventCalls()
+
+	if err := app.ApplyDueScheduledChanges(now.Time()); err == nil {
+		t.Fatal("Expected the failing change error")
+	}
+
+	if app.EventCalls["OnRecordAfterUpdateSuccess"] != 1 {
+		t.Fatalf("Expected the record update hooks to be triggered once, got %v", app.EventCalls)
+	}
+
+	updated, err := app.FindRecordById("demo2", record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.GetString("title") != "published" || updated.GetBool("active") {
+		t.Fatalf("Expected the due change to be applied, got %v", updated)
+	}
+
+	if _, err := app.FindScheduledChangeById(due.Id); err == nil {
+		t.Fatal("Expected the applied change to be deleted")
+	}
+
+	failed, err := app.FindScheduledChangeById(failing.Id)
+	if err != nil {
+		t.Fatalf("Expected the failed change to be kept, got %v", err)
+	}
+	if !failed.IsFailed() {
+		t.Fatal("Expected the failed change to have an error")
+	}
+
+	if _, err := app.FindScheduledChangeById(future.Id); err != nil {
+		t.Fatalf("Expected the future change to be kept, got %v", err)
+	}
+
+	// failed changes are not retried
+	app.ResetEventCalls()
+	if err := app.ApplyDueScheduledChanges(now.Time()); err != nil {
+		t.Fatalf("Expected no errors on the second run, got %v", err)
+	}
+	if total := len(app.EventCalls); total != 0 {
+		t.Fatalf("Expected no events, got %v", app.EventCalls)
+	}
+}
+
+func TestFindRecordScheduledChanges(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	record1, err := app.FindRecordById("demo2", "achvryl401bhse3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record2, err := app.FindRecordById("demo2", "0yxhwia2amd8gec")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := types.NowDateTime()
+
+	for i, date := range []types.DateTime{now.Add(2 * time.Hour), now.Add(1 * time.Hour)} {
+		for _, record := range []*core.Record{record1, record2} {
+			change := core.NewScheduledChange(record)
+			change.Id = record.Id + "_" + string(rune('a'+i))
+			change.Data = types.JSONMap[any]{"active": false}
+			change.ScheduledAt = date
+			if err := app.Save(change); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	changes, err := app.FindRecordScheduledChanges(record1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedIds := []string{"achvryl401bhse3_b", "achvryl401bhse3_a"}
+	if len(changes) != len(expectedIds) {
+		t.Fatalf("Expected %d changes, got %d", len(expectedIds), len(changes))
+	}
+	for i, id := range expectedIds {
+		if changes[i].Id != id {
+			t.Fatalf("Expected change %d to be %q, got %q", i, id, changes[i].Id)
+		}
+	}
+
+	// delete the record and check whether its changes were also deleted
+	if err := app.Delete(record1); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int
+	if err := app.ScheduledChangeQuery().Select("count(*)").Row(&total); err != nil {
+		t.Fatal(err)
+	}
+	if total != 2 {
+		t.Fatalf("Expected only the other record changes to remain, got %d", total)
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"maps"
 	"os"
 	"regexp"
 	"slices"
@@ -16,8 +17,11 @@ import (
 	validation "github.com/go-ozzo/ozzo-validation/v4"
 	"github.com/go-ozzo/ozzo-validation/v4/is"
 	"github.com/pocketbase/pocketbase/core/validators"
+	"github.com/pocketbase/pocketbase/tools/auth"
+	"github.com/pocketbase/pocketbase/tools/captcha"
 	"github.com/pocketbase/pocketbase/tools/cron"
 	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/mailer"
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/types"
@@ -121,13 +125,34 @@ var (
 
 type settings struct {
 	SMTP         SMTPConfig         `form:"smtp" json:"smtp"`
+	SMS          SMSConfig          `form:"sms" json:"sms"`
+	Captcha      CaptchaConfig      `form:"captcha" json:"captcha"`
+	MailQueue    MailQueueConfig    `form:"mailQueue" json:"mailQueue"`
 	Backups      BackupsConfig      `form:"backups" json:"backups"`
 	S3           S3Config           `form:"s3" json:"s3"`
 	Meta         MetaConfig         `form:"meta" json:"meta"`
 	RateLimits   RateLimitsConfig   `form:"rateLimits" json:"rateLimits"`
+	Timeouts     TimeoutsConfig     `form:"timeouts" json:"timeouts"`
 	TrustedProxy TrustedProxyConfig `form:"trustedProxy" json:"trustedProxy"`
+	GeoIP        GeoIPConfig        `form:"geoIP" json:"geoIP"`
 	Batch        BatchConfig        `form:"batch" json:"batch"`
+	Realtime     RealtimeConfig     `form:"realtime" json:"realtime"`
+	Changefeed   ChangefeedConfig   `form:"changefeed" json:"changefeed"`
+	RecycleBin   RecycleBinConfig   `form:"recycleBin" json:"recycleBin"`
+	Tombstones   TombstonesConfig   `form:"tombstones" json:"tombstones"`
+	Metering     MeteringConfig     `form:"metering" json:"metering"`
+	Thumbs       ThumbsConfig       `form:"thumbs" json:"thumbs"`
 	Logs         LogsConfig         `form:"logs" json:"logs"`
+
+	// RequestCapture is the opt-in debug capture of the full
+	// request/response pairs of the selected routes.
+	RequestCapture RequestCaptureConfig `form:"requestCapture" json:"requestCapture"`
+
+	// OAuth2HTTPClient is the default http client configuration
+	// of the outgoing OAuth2 provider requests.
+	//
+	// It could be overwritten per provider with OAuth2ProviderConfig.HTTPClient.
+	OAuth2HTTPClient auth.HTTPClientConfig `form:"oauth2HTTPClient" json:"oauth2HTTPClient"`
 }
 
 // Settings defines the PocketBase app settings.
@@ -136,6 +161,11 @@ type Settings struct {
 
 	mu    sync.RWMutex
 	isNew bool
+
+	// the applied settings overlay values and their
+	// base (aka. stored) ones (see [Settings.ApplyOverlay])
+	overlay     map[string]any
+	overlayBase map[string]any
 }
 
 func newDefaultSettings() *Settings {
@@ -164,11 +194,25 @@ func newDefaultSettings() *Settings {
 			Backups: BackupsConfig{
 				CronMaxKeep: 3,
 			},
+			MailQueue: MailQueueConfig{
+				MaxPerMinute: 60,
+				MaxAttempts:  3,
+				MaxDays:      7,
+			},
 			Batch: BatchConfig{
 				Enabled:     false,
 				MaxRequests: 50,
 				Timeout:     3,
 			},
+			Realtime: RealtimeConfig{
+				HeartbeatInterval: 30,
+			},
+			RecycleBin: RecycleBinConfig{
+				MaxDays: 30,
+			},
+			Tombstones: TombstonesConfig{
+				MaxDays: 30,
+			},
 			RateLimits: RateLimitsConfig{
 				Enabled: false, // @todo once tested enough enable by default for new installations
 				Rules: []RateLimitRule{
@@ -252,7 +296,7 @@ func (s *Settings) DBExport(app App) (map[string]any, error) {
 	}
 	result["updated"] = now
 
-	encoded, err := json.Marshal(s.settings)
+	encoded, err := s.exportWithoutOverlay()
 	if err != nil {
 		return nil, err
 	}
@@ -278,15 +322,31 @@ func (s *Settings) PostValidate(ctx context.Context, app App) error {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
+	if err := s.validateOverlay(); err != nil {
+		return err
+	}
+
 	return validation.ValidateStructWithContext(ctx, s,
 		validation.Field(&s.Meta),
 		validation.Field(&s.Logs),
 		validation.Field(&s.SMTP),
+		validation.Field(&s.SMS),
+		validation.Field(&s.Captcha),
+		validation.Field(&s.MailQueue),
 		validation.Field(&s.S3),
 		validation.Field(&s.Backups),
 		validation.Field(&s.Batch),
+		validation.Field(&s.Realtime),
+		validation.Field(&s.Changefeed),
+		validation.Field(&s.RecycleBin),
+		validation.Field(&s.Tombstones),
+		validation.Field(&s.Metering),
+		validation.Field(&s.Thumbs),
 		validation.Field(&s.RateLimits),
+		validation.Field(&s.Timeouts),
+		validation.Field(&s.RequestCapture),
 		validation.Field(&s.TrustedProxy),
+		validation.Field(&s.OAuth2HTTPClient),
 	)
 }
 
@@ -316,6 +376,11 @@ func (s *Settings) Clone() (*Settings, error) {
 		return nil, err
 	}
 
+	s.mu.RLock()
+	clone.overlay = maps.Clone(s.overlay)
+	clone.overlayBase = maps.Clone(s.overlayBase)
+	s.mu.RUnlock()
+
 	return clone, nil
 }
 
@@ -329,6 +394,8 @@ func (s *Settings) MarshalJSON() ([]byte, error) {
 
 	sensitiveFields := []*string{
 		&copy.SMTP.Password,
+		&copy.SMS.AuthToken,
+		&copy.Captcha.Secret,
 		&copy.S3.Secret,
 		&copy.Backups.S3.Secret,
 	}
@@ -394,6 +461,113 @@ func (c SMTPConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+const (
+	SMSProviderTwilio  = "twilio"
+	SMSProviderWebhook = "webhook"
+)
+
+type SMSConfig struct {
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// Provider is the SMS provider to use ("twilio" or "webhook").
+	Provider string `form:"provider" json:"provider"`
+
+	// From is the sender phone number or Messaging Service SID (twilio only).
+	From string `form:"from" json:"from"`
+
+	// AccountSid is the Twilio account identifier (twilio only).
+	AccountSid string `form:"accountSid" json:"accountSid"`
+
+	// AuthToken is the Twilio auth token or the webhook
+	// "Authorization" header value (optional for webhook).
+	AuthToken string `form:"authToken" json:"authToken,omitempty"`
+
+	// WebhookURL is the endpoint where the messages will be submitted
+	// as JSON POST request (webhook only).
+	WebhookURL string `form:"webhookURL" json:"webhookURL"`
+}
+
+// Validate makes SMSConfig validatable by implementing [validation.Validatable] interface.
+func (c SMSConfig) Validate() error {
+	isTwilio := c.Enabled && c.Provider == SMSProviderTwilio
+	isWebhook := c.Enabled && c.Provider == SMSProviderWebhook
+
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Provider,
+			validation.When(c.Enabled, validation.Required),
+			validation.In(SMSProviderTwilio, SMSProviderWebhook),
+		),
+		validation.Field(&c.From, validation.When(isTwilio, validation.Required)),
+		validation.Field(&c.AccountSid, validation.When(isTwilio, validation.Required)),
+		validation.Field(&c.AuthToken, validation.When(isTwilio, validation.Required)),
+		validation.Field(&c.WebhookURL, validation.When(isWebhook, validation.Required), is.URL),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type CaptchaConfig struct {
+	// Provider is the captcha provider used to verify the public
+	// form submissions ("turnstile", "hcaptcha" or "recaptcha").
+	//
+	// Leave it empty to disable the captcha verification.
+	Provider string `form:"provider" json:"provider"`
+
+	// Secret is the captcha provider server-side secret key.
+	Secret string `form:"secret" json:"secret,omitempty"`
+
+	// VerifyURL is an optional custom siteverify endpoint
+	// (e.g. for self-hosted provider compatible services).
+	VerifyURL string `form:"verifyURL" json:"verifyURL"`
+}
+
+// Enabled reports whether the captcha verification is configured.
+func (c CaptchaConfig) Enabled() bool {
+	return c.Provider != ""
+}
+
+// Validate makes CaptchaConfig validatable by implementing [validation.Validatable] interface.
+func (c CaptchaConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Provider,
+			validation.In(captcha.ProviderTurnstile, captcha.ProviderHCaptcha, captcha.ProviderReCAPTCHA),
+		),
+		validation.Field(&c.Secret, validation.When(c.Enabled(), validation.Required)),
+		validation.Field(&c.VerifyURL, is.URL),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type MailQueueConfig struct {
+	// MaxPerMinute is the max number of queued emails to send per minute.
+	//
+	// Leave it empty (0) to send all pending emails on each queue run.
+	MaxPerMinute int `form:"maxPerMinute" json:"maxPerMinute"`
+
+	// MaxAttempts is the max number of send attempts before
+	// marking a queued email as failed.
+	MaxAttempts int `form:"maxAttempts" json:"maxAttempts"`
+
+	// MaxDays is the max number of days to keep the sent and failed queued emails.
+	//
+	// Leave it empty (0) to keep them forever.
+	MaxDays int `form:"maxDays" json:"maxDays"`
+}
+
+// Validate makes MailQueueConfig validatable by implementing [validation.Validatable] interface.
+func (c MailQueueConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxPerMinute, validation.Min(0)),
+		validation.Field(&c.MaxAttempts, validation.Min(1)),
+		validation.Field(&c.MaxDays, validation.Min(0)),
+	)
+}
+
+// -------------------------------------------------------------------
+
 type S3Config struct {
 	Enabled        bool   `form:"enabled" json:"enabled"`
 	Bucket         string `form:"bucket" json:"bucket"`
@@ -417,6 +591,17 @@ func (c S3Config) Validate() error {
 
 // -------------------------------------------------------------------
 
+type GeoIPConfig struct {
+	// Enabled enables the resolving of the client country and ASN
+	// (available as @request.geo.* and stored in the request logs).
+	//
+	// The geo data is resolved from the CSV databases in the pb_data/geoip
+	// directory (e.g. the free DB-IP Lite "IP to Country" and "IP to ASN" CSV files).
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// -------------------------------------------------------------------
+
 type BatchConfig struct {
 	Enabled bool `form:"enabled" json:"enabled"`
 
@@ -443,6 +628,368 @@ func (c BatchConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+type RealtimeConfig struct {
+	// MaxClients is the maximum allowed number of simultaneously
+	// connected realtime clients.
+	//
+	// Leave it empty (0) to disable the limit.
+	MaxClients int `form:"maxClients" json:"maxClients"`
+
+	// MaxClientsPerAuth is the maximum allowed number of simultaneously
+	// connected realtime clients associated with the same auth record.
+	//
+	// Leave it empty (0) to disable the limit.
+	MaxClientsPerAuth int `form:"maxClientsPerAuth" json:"maxClientsPerAuth"`
+
+	// IdleTimeout is the max duration in seconds without any sent message
+	// after which an inactive realtime connection is closed.
+	//
+	// If not set, fallbacks to 5 minutes.
+	IdleTimeout int64 `form:"idleTimeout" json:"idleTimeout"`
+
+	// HeartbeatInterval is the interval in seconds for sending SSE keep-alive
+	// comments to the connected clients (usually it should be lower than
+	// the idle timeout of the proxies in front of the app).
+	//
+	// Leave it empty (0) to disable the heartbeats.
+	HeartbeatInterval int64 `form:"heartbeatInterval" json:"heartbeatInterval"`
+
+	// RetryInterval is the SSE "retry:" reconnection hint in milliseconds
+	// sent to the clients on connect.
+	//
+	// Leave it empty (0) to use the client default.
+	RetryInterval int64 `form:"retryInterval" json:"retryInterval"`
+
+	// Compression enables gzip compression of the realtime SSE stream
+	// for the clients that support it (aka. "Accept-Encoding: gzip").
+	Compression bool `form:"compression" json:"compression"`
+
+	// Topics is a list of parameterized custom subscription topics
+	// (e.g. "chat/{roomId}") with declarative access rules.
+	//
+	// The custom topics that don't match any of the listed
+	// patterns are not restricted.
+	Topics []RealtimeTopic `form:"topics" json:"topics"`
+}
+
+// FindTopic returns the first topic config matching the provided
+// subscription topic together with its extracted params.
+func (c RealtimeConfig) FindTopic(topic string) (RealtimeTopic, map[string]string, bool) {
+	for _, t := range c.Topics {
+		if params, ok := t.Match(topic); ok {
+			return t, params, true
+		}
+	}
+
+	return RealtimeTopic{}, nil, false
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (c RealtimeConfig) MarshalJSON() ([]byte, error) {
+	type alias RealtimeConfig
+
+	// serialize as empty array
+	if c.Topics == nil {
+		c.Topics = []RealtimeTopic{}
+	}
+
+	return json.Marshal(alias(c))
+}
+
+// IdleTimeoutDuration returns the IdleTimeout as [time.Duration]
+// (with fallback to 5 minutes if not set).
+func (c RealtimeConfig) IdleTimeoutDuration() time.Duration {
+	if c.IdleTimeout <= 0 {
+		return 5 * time.Minute
+	}
+
+	return time.Duration(c.IdleTimeout) * time.Second
+}
+
+// Validate makes RealtimeConfig validatable by implementing [validation.Validatable] interface.
+func (c RealtimeConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxClients, validation.Min(0)),
+		validation.Field(&c.MaxClientsPerAuth, validation.Min(0)),
+		validation.Field(&c.IdleTimeout, validation.Min(0)),
+		validation.Field(&c.HeartbeatInterval, validation.Min(0)),
+		validation.Field(&c.RetryInterval, validation.Min(0), validation.Max(int64(24*time.Hour/time.Millisecond))),
+		validation.Field(&c.Topics, validation.By(checkUniqueRealtimeTopicPattern)),
+	)
+}
+
+func checkUniqueRealtimeTopicPattern(value any) error {
+	topics, ok := value.([]RealtimeTopic)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	existing := make(map[string]struct{}, len(topics))
+
+	for i, topic := range topics {
+		if _, ok := existing[topic.Pattern]; ok {
+			return validation.Errors{
+				strconv.Itoa(i): validation.Errors{
+					"pattern": validation.NewError("validation_conflicting_realtime_topic", "Realtime topic configuration with pattern {{.pattern}} already exists.").
+						SetParams(map[string]any{"pattern": topic.Pattern}),
+				},
+			}
+		}
+
+		existing[topic.Pattern] = struct{}{}
+	}
+
+	return nil
+}
+
+var (
+	realtimeTopicSegmentRegex = regexp.MustCompile(`^(\{\w+\}|[^\s\{\}\?\/]+)$`)
+	realtimeTopicVarRegex     = regexp.MustCompile(`@topic\.(\w+)`)
+)
+
+// RealtimeTopic defines a parameterized custom realtime topic access rule.
+type RealtimeTopic struct {
+	// Pattern is the "/" separated topic pattern where the
+	// segments in the format "{name}" match any single non-empty
+	// topic segment (e.g. "chat/{roomId}" matches "chat/abc").
+	//
+	// Note that the pattern is matched against all subscription topics, including
+	// the collection records ones (e.g. "{a}/{b}" will match also "posts/*").
+	Pattern string `form:"pattern" json:"pattern"`
+
+	// Collection is the name or id of the collection whose records
+	// are checked against the Rule.
+	//
+	// The subscription is allowed if at least one of the collection
+	// records satisfies the Rule (required for non-empty rules).
+	Collection string `form:"collection" json:"collection"`
+
+	// Rule is the subscription access rule expression.
+	//
+	// The topic params could be referenced in the expression with
+	// the "@topic.*" variables (e.g. "id = @topic.roomId && members ?= @request.auth.id").
+	//
+	// Similar to the collection API rules, nil means that only superusers
+	// can subscribe and empty string means that everyone can subscribe.
+	Rule *string `form:"rule" json:"rule"`
+}
+
+// Params returns the names of the topic pattern params.
+func (t RealtimeTopic) Params() []string {
+	var result []string
+
+	for _, segment := range strings.Split(t.Pattern, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			result = append(result, segment[1:len(segment)-1])
+		}
+	}
+
+	return result
+}
+
+// Match reports whether the provided subscription topic matches the
+// topic pattern and returns its extracted params.
+//
+// The subscription topic options query (if any) is ignored.
+func (t RealtimeTopic) Match(topic string) (map[string]string, bool) {
+	topic, _, _ = strings.Cut(topic, "?")
+
+	patternSegments := strings.Split(t.Pattern, "/")
+	topicSegments := strings.Split(topic, "/")
+
+	if len(patternSegments) != len(topicSegments) {
+		return nil, false
+	}
+
+	params := map[string]string{}
+
+	for i, segment := range patternSegments {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			if topicSegments[i] == "" {
+				return nil, false
+			}
+			params[segment[1:len(segment)-1]] = topicSegments[i]
+			continue
+		}
+
+		if segment != topicSegments[i] {
+			return nil, false
+		}
+	}
+
+	return params, true
+}
+
+// Validate makes RealtimeTopic validatable by implementing [validation.Validatable] interface.
+func (t RealtimeTopic) Validate() error {
+	return validation.ValidateStruct(&t,
+		validation.Field(&t.Pattern, validation.Required, validation.Length(1, 255), validation.By(t.checkPattern)),
+		validation.Field(
+			&t.Collection,
+			validation.When(t.Rule != nil && *t.Rule != "", validation.Required),
+			validation.Length(0, 255),
+		),
+		validation.Field(&t.Rule, validation.By(t.checkRuleVars)),
+	)
+}
+
+func (t RealtimeTopic) checkPattern(value any) error {
+	v, _ := value.(string)
+
+	existing := map[string]struct{}{}
+
+	for _, segment := range strings.Split(v, "/") {
+		if !realtimeTopicSegmentRegex.MatchString(segment) {
+			return validation.NewError("validation_invalid_realtime_topic_pattern", "Invalid realtime topic pattern segment {{.segment}}.").
+				SetParams(map[string]any{"segment": segment})
+		}
+
+		if strings.HasPrefix(segment, "{") {
+			if _, ok := existing[segment]; ok {
+				return validation.NewError("validation_duplicated_realtime_topic_param", "Duplicated realtime topic param {{.param}}.").
+					SetParams(map[string]any{"param": segment})
+			}
+			existing[segment] = struct{}{}
+		}
+	}
+
+	return nil
+}
+
+func (t RealtimeTopic) checkRuleVars(value any) error {
+	v, _ := value.(*string)
+	if v == nil {
+		return nil
+	}
+
+	params := t.Params()
+
+	for _, match := range realtimeTopicVarRegex.FindAllStringSubmatch(*v, -1) {
+		if !slices.Contains(params, match[1]) {
+			return validation.NewError("validation_unknown_realtime_topic_param", "Unknown realtime topic param @topic.{{.param}}.").
+				SetParams(map[string]any{"param": match[1]})
+		}
+	}
+
+	return nil
+}
+
+// -------------------------------------------------------------------
+
+type ChangefeedConfig struct {
+	// Enabled enables the persisting of the non-system collections
+	// record changes in the changefeed table.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxDays is the max number of days to keep the changefeed entries.
+	//
+	// Leave it empty (0) to keep the entries forever.
+	MaxDays int `form:"maxDays" json:"maxDays"`
+
+	// PayloadVersion pins the record data payload schema version
+	// of the new changefeed entries (see [RecordPayloadVersions]).
+	//
+	// Leave it empty (0) to use the [LatestRecordPayloadVersion].
+	PayloadVersion int `form:"payloadVersion" json:"payloadVersion"`
+}
+
+// Validate makes ChangefeedConfig validatable by implementing [validation.Validatable] interface.
+func (c ChangefeedConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxDays, validation.Min(0)),
+		validation.Field(&c.PayloadVersion, validation.In(list.ToInterfaceSlice(RecordPayloadVersions)...)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type RecycleBinConfig struct {
+	// Enabled enables the retaining of the deleted non-system collections
+	// records (and their files) in the recycle bin.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxDays is the max number of days to keep the deleted records
+	// in the recycle bin before purging them.
+	//
+	// Leave it empty (0) to keep the deleted records forever.
+	MaxDays int `form:"maxDays" json:"maxDays"`
+}
+
+// Validate makes RecycleBinConfig validatable by implementing [validation.Validatable] interface.
+func (c RecycleBinConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxDays, validation.Min(0)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type TombstonesConfig struct {
+	// Enabled enables the tracking of the deleted non-system collections
+	// records ids in a lightweight deletions log.
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxDays is the max number of days to keep the deletion tombstones.
+	//
+	// Leave it empty (0) to keep the tombstones forever.
+	MaxDays int `form:"maxDays" json:"maxDays"`
+}
+
+// Validate makes TombstonesConfig validatable by implementing [validation.Validatable] interface.
+func (c TombstonesConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxDays, validation.Min(0)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type MeteringConfig struct {
+	// Enabled enables the per tenant (usually auth record) counting of
+	// the billable usage units (requests, uploaded storage bytes, egress and emails).
+	Enabled bool `form:"enabled" json:"enabled"`
+
+	// MaxDays is the max number of days to keep the daily usage rollups.
+	//
+	// Leave it empty (0) to keep the usage rollups forever.
+	MaxDays int `form:"maxDays" json:"maxDays"`
+}
+
+// Validate makes MeteringConfig validatable by implementing [validation.Validatable] interface.
+func (c MeteringConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxDays, validation.Min(0)),
+	)
+}
+
+// -------------------------------------------------------------------
+
+type ThumbsConfig struct {
+	// MaxIdleDays is the max number of days to keep the generated
+	// thumbs that were not accessed (they are regenerated on demand).
+	//
+	// Leave it empty (0) to keep the generated thumbs forever.
+	MaxIdleDays int `form:"maxIdleDays" json:"maxIdleDays"`
+
+	// S3Lifecycle delegates the thumbs expiration to an S3 bucket
+	// lifecycle rule instead of the periodic storage cleanup job
+	// (applicable only when the S3 storage is enabled).
+	//
+	// Note that the lifecycle rule expires the thumbs MaxIdleDays after
+	// their creation (S3 doesn't track the objects access) and only the
+	// thumbs generated with v0.31.0+ are matched by the rule.
+	S3Lifecycle bool `form:"s3Lifecycle" json:"s3Lifecycle"`
+}
+
+// Validate makes ThumbsConfig validatable by implementing [validation.Validatable] interface.
+func (c ThumbsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxIdleDays, validation.Min(0)),
+	)
+}
+
+// -------------------------------------------------------------------
+
 type BackupsConfig struct {
 	// Cron is a cron expression to schedule auto backups, eg. "* * * * *".
 	//
@@ -488,12 +1035,21 @@ func checkCronExpression(value any) error {
 
 // -------------------------------------------------------------------
 
+var localeRegex = regexp.MustCompile(`^[a-zA-Z]{2,8}([_-][a-zA-Z0-9]{1,8})*$`)
+
 type MetaConfig struct {
 	AppName       string `form:"appName" json:"appName"`
 	AppURL        string `form:"appURL" json:"appURL"`
 	SenderName    string `form:"senderName" json:"senderName"`
 	SenderAddress string `form:"senderAddress" json:"senderAddress"`
 	HideControls  bool   `form:"hideControls" json:"hideControls"`
+
+	// Locale is the default locale used for localizing the API error
+	// messages when the client Accept-Language header doesn't match
+	// any of the available app translations (e.g. "cs", "pt-BR").
+	//
+	// Leave it empty to fallback to the default English messages.
+	Locale string `form:"locale" json:"locale"`
 }
 
 // Validate makes MetaConfig validatable by implementing [validation.Validatable] interface.
@@ -503,6 +1059,7 @@ func (c MetaConfig) Validate() error {
 		validation.Field(&c.AppURL, validation.Required, is.URL),
 		validation.Field(&c.SenderName, validation.Required, validation.Length(1, 255)),
 		validation.Field(&c.SenderAddress, is.EmailFormat, validation.Required),
+		validation.Field(&c.Locale, validation.Length(0, 35), validation.Match(localeRegex)),
 	)
 }
 
@@ -513,6 +1070,13 @@ type LogsConfig struct {
 	MinLevel  int  `form:"minLevel" json:"minLevel"`
 	LogIP     bool `form:"logIP" json:"logIP"`
 	LogAuthId bool `form:"logAuthId" json:"logAuthId"`
+
+	// LogSuperuserActions enables logging of the superuser collections,
+	// settings and records API mutations (with the actor and the changed data).
+	//
+	// It is independent from the regular request logs and it could be
+	// enabled to keep a more detailed audit trail of the dashboard changes.
+	LogSuperuserActions bool `form:"logSuperuserActions" json:"logSuperuserActions"`
 }
 
 // Validate makes LogsConfig validatable by implementing [validation.Validatable] interface.
@@ -524,6 +1088,90 @@ func (c LogsConfig) Validate() error {
 
 // -------------------------------------------------------------------
 
+// DefaultRequestCaptureMaxBodySize is the default max number of
+// captured request and response body bytes.
+const DefaultRequestCaptureMaxBodySize = 64 << 10
+
+// RequestCaptureConfig defines the debug request/response capture configuration.
+//
+// The captured pairs are stored as "requestCapture" app logs
+// and therefore they are subject to the Logs.MaxDays retention.
+type RequestCaptureConfig struct {
+	// Routes is a list of labels of the routes to capture.
+	//
+	// It has the same format as the [TimeoutRule] label, aka. a complete path
+	// or path prefix (when ends with `/`) optionally prefixed with the request method.
+	//
+	// Example supported labels:
+	//   - /api/collections/posts/records
+	//   - POST /api/collections/
+	Routes []string `form:"routes" json:"routes"`
+
+	// ScrubFields is an optional list of extra header and body field names
+	// whose values will be replaced with a placeholder in the captured data
+	// (the common secret fields like "Authorization", "password", "token", etc. are always scrubbed).
+	ScrubFields []string `form:"scrubFields" json:"scrubFields"`
+
+	// MaxBodySize specifies the max number of the captured
+	// request and response body bytes
+	// (if zero, fallbacks to [DefaultRequestCaptureMaxBodySize]).
+	MaxBodySize int64 `form:"maxBodySize" json:"maxBodySize"`
+
+	Enabled bool `form:"enabled" json:"enabled"`
+}
+
+// MatchRoute reports whether the request with the specified
+// method and path should be captured.
+func (c *RequestCaptureConfig) MatchRoute(method string, path string) bool {
+	timeouts := TimeoutsConfig{Rules: make([]TimeoutRule, len(c.Routes))}
+	for i, route := range c.Routes {
+		timeouts.Rules[i].Label = route
+	}
+
+	_, ok := timeouts.FindTimeoutRule([]string{method + " " + path, path})
+
+	return ok
+}
+
+// MaxBodySizeValue returns the normalized MaxBodySize value.
+func (c *RequestCaptureConfig) MaxBodySizeValue() int64 {
+	if c.MaxBodySize <= 0 {
+		return DefaultRequestCaptureMaxBodySize
+	}
+
+	return c.MaxBodySize
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (c RequestCaptureConfig) MarshalJSON() ([]byte, error) {
+	type alias RequestCaptureConfig
+
+	// serialize as empty arrays
+	if c.Routes == nil {
+		c.Routes = []string{}
+	}
+	if c.ScrubFields == nil {
+		c.ScrubFields = []string{}
+	}
+
+	return json.Marshal(alias(c))
+}
+
+// Validate makes RequestCaptureConfig validatable by implementing [validation.Validatable] interface.
+func (c RequestCaptureConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Routes,
+			validation.When(c.Enabled, validation.Required),
+			validation.Each(validation.Required, validation.Match(timeoutRuleLabelRegex)),
+		),
+		validation.Field(&c.ScrubFields, validation.Each(validation.Required, validation.Length(1, 100))),
+		validation.Field(&c.MaxBodySize, validation.Min(0)),
+	)
+}
+
+// -------------------------------------------------------------------
+
 type TrustedProxyConfig struct {
 	// Headers is a list of explicit trusted header(s) to check.
 	Headers []string `form:"headers" json:"headers"`
@@ -714,3 +1362,123 @@ func (c RateLimitRule) String() string {
 
 	return string(raw)
 }
+
+// -------------------------------------------------------------------
+
+// TimeoutsConfig defines the per route request timeouts configuration.
+//
+// The matched rule timeout is applied as deadline to the request context
+// (aka. the db queries and hooks that use it are cancelled once it is reached)
+// and it also replaces the default server read and write timeouts for the request.
+type TimeoutsConfig struct {
+	Rules   []TimeoutRule `form:"rules" json:"rules"`
+	Enabled bool          `form:"enabled" json:"enabled"`
+}
+
+// FindTimeoutRule returns the first matching rule based on the provided labels.
+//
+// The labels are checked in the order they are provided first for direct match
+// and then for prefix match (for the rules whose label ends with `/`).
+func (c *TimeoutsConfig) FindTimeoutRule(searchLabels []string) (TimeoutRule, bool) {
+	var prefixRules []int
+
+	for i, label := range searchLabels {
+		// check for direct match
+		for j := range c.Rules {
+			if label == c.Rules[j].Label {
+				return c.Rules[j], true
+			}
+
+			if i == 0 && strings.HasSuffix(c.Rules[j].Label, "/") {
+				prefixRules = append(prefixRules, j)
+			}
+		}
+
+		// check for prefix match
+		for _, j := range prefixRules {
+			if strings.HasPrefix(label+"/", c.Rules[j].Label) {
+				return c.Rules[j], true
+			}
+		}
+	}
+
+	return TimeoutRule{}, false
+}
+
+// MarshalJSON implements the [json.Marshaler] interface.
+func (c TimeoutsConfig) MarshalJSON() ([]byte, error) {
+	type alias TimeoutsConfig
+
+	// serialize as empty array
+	if c.Rules == nil {
+		c.Rules = []TimeoutRule{}
+	}
+
+	return json.Marshal(alias(c))
+}
+
+// Validate makes TimeoutsConfig validatable by implementing [validation.Validatable] interface.
+func (c TimeoutsConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(
+			&c.Rules,
+			validation.When(c.Enabled, validation.Required),
+			validation.By(checkUniqueTimeoutRuleLabel),
+		),
+	)
+}
+
+func checkUniqueTimeoutRuleLabel(value any) error {
+	rules, ok := value.([]TimeoutRule)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	existing := make(map[string]struct{}, len(rules))
+
+	for i, rule := range rules {
+		if _, ok := existing[rule.Label]; ok {
+			return validation.Errors{
+				strconv.Itoa(i): validation.Errors{
+					"label": validation.NewError("validation_conflicting_timeout_rule", "Timeout rule configuration with label {{.label}} already exists.").
+						SetParams(map[string]any{"label": rule.Label}),
+				},
+			}
+		}
+
+		existing[rule.Label] = struct{}{}
+	}
+
+	return nil
+}
+
+var timeoutRuleLabelRegex = regexp.MustCompile(`^(\w+\ \/[\w\/-]*|\/[\w\/-]*)$`)
+
+type TimeoutRule struct {
+	// Label is the identifier of the current rule.
+	//
+	// It could be a complete path or path prefix (when ends with `/`)
+	// optionally prefixed with the request method.
+	//
+	// Example supported labels:
+	//   - /api/backups/upload
+	//   - POST /api/collections/
+	//   - /api/
+	Label string `form:"label" json:"label"`
+
+	// Timeout specifies the max allowed request processing duration (in seconds).
+	Timeout int64 `form:"timeout" json:"timeout"`
+}
+
+// Validate makes TimeoutRule validatable by implementing [validation.Validatable] interface.
+func (c TimeoutRule) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Label, validation.Required, validation.Match(timeoutRuleLabelRegex)),
+		validation.Field(&c.Timeout, validation.Required, validation.Min(1)),
+	)
+}
+
+// DurationTime returns the rule Timeout as [time.Duration].
+func (c TimeoutRule) DurationTime() time.Duration {
+	return time.Duration(c.Timeout) * time.Second
+}
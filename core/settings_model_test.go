@@ -3,6 +3,7 @@ package core_test
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
 	"strings"
 	"testing"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/tests"
 	"github.com/pocketbase/pocketbase/tools/mailer"
+	"github.com/pocketbase/pocketbase/tools/types"
 )
 
 func TestSettingsDelete(t *testing.T) {
@@ -99,6 +101,8 @@ func TestSettingsMarshalJSON(t *testing.T) {
 	settings.SMTP.Password = testSecret
 	settings.S3.Secret = testSecret
 	settings.Backups.S3.Secret = testSecret
+	settings.SMS.AuthToken = testSecret
+	settings.Captcha.Secret = testSecret
 
 	raw, err := json.Marshal(settings)
 	if err != nil {
@@ -106,7 +110,7 @@ func TestSettingsMarshalJSON(t *testing.T) {
 	}
 	rawStr := string(raw)
 
-	expected := `{"smtp":{"enabled":false,"port":0,"host":"","username":"abc","authMethod":"","tls":false,"localName":""},"backups":{"cron":"","cronMaxKeep":0,"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false}},"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false},"meta":{"appName":"test123","appURL":"","senderName":"","senderAddress":"","hideControls":false},"rateLimits":{"rules":[],"enabled":false},"trustedProxy":{"headers":[],"useLeftmostIP":false},"batch":{"enabled":false,"maxRequests":0,"timeout":0,"maxBodySize":0},"logs":{"maxDays":0,"minLevel":0,"logIP":false,"logAuthId":false}}`
+	expected := `{"smtp":{"enabled":false,"port":0,"host":"","username":"abc","authMethod":"","tls":false,"localName":""},"sms":{"enabled":false,"provider":"","from":"","accountSid":"","webhookURL":""},"captcha":{"provider":"","verifyURL":""},"mailQueue":{"maxPerMinute":0,"maxAttempts":0,"maxDays":0},"backups":{"cron":"","cronMaxKeep":0,"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false}},"s3":{"enabled":false,"bucket":"","region":"","endpoint":"","accessKey":"","forcePathStyle":false},"meta":{"appName":"test123","appURL":"","senderName":"","senderAddress":"","hideControls":false,"locale":""},"rateLimits":{"rules":[],"enabled":false},"timeouts":{"rules":[],"enabled":false},"trustedProxy":{"headers":[],"useLeftmostIP":false},"geoIP":{"enabled":false},"batch":{"enabled":false,"maxRequests":0,"timeout":0,"maxBodySize":0},"realtime":{"maxClients":0,"maxClientsPerAuth":0,"idleTimeout":0,"heartbeatInterval":0,"retryInterval":0,"compression":false,"topics":[]},"changefeed":{"enabled":false,"maxDays":0,"payloadVersion":0},"recycleBin":{"enabled":false,"maxDays":0},"tombstones":{"enabled":false,"maxDays":0},"metering":{"enabled":false,"maxDays":0},"thumbs":{"maxIdleDays":0,"s3Lifecycle":false},"logs":{"maxDays":0,"minLevel":0,"logIP":false,"logAuthId":false,"logSuperuserActions":false},"requestCapture":{"routes":[],"scrubFields":[],"maxBodySize":0,"enabled":false},"oauth2HTTPClient":{"proxyURL":"","caCerts":"","timeout":0}}`
 
 	if rawStr != expected {
 		t.Fatalf("Expected\n%v\ngot\n%v", expected, rawStr)
@@ -126,15 +130,27 @@ func TestSettingsValidate(t *testing.T) {
 	s.Logs.MaxDays = -10
 	s.SMTP.Enabled = true
 	s.SMTP.Host = ""
+	s.Captcha.Provider = "invalid"
 	s.S3.Enabled = true
 	s.S3.Endpoint = "invalid"
 	s.Backups.Cron = "invalid"
 	s.Backups.CronMaxKeep = -10
+	s.MailQueue.MaxAttempts = -1
 	s.Batch.Enabled = true
 	s.Batch.MaxRequests = -1
 	s.Batch.Timeout = -1
+	s.Realtime.MaxClients = -1
+	s.Changefeed.MaxDays = -1
+	s.RecycleBin.MaxDays = -1
+	s.Tombstones.MaxDays = -1
+	s.Metering.MaxDays = -1
+	s.Thumbs.MaxIdleDays = -1
 	s.RateLimits.Enabled = true
 	s.RateLimits.Rules = nil
+	s.Timeouts.Enabled = true
+	s.Timeouts.Rules = nil
+	s.RequestCapture.Enabled = true
+	s.OAuth2HTTPClient.Timeout = -1
 
 	// check if Validate() is triggering the members validate methods.
 	err := app.Validate(s)
@@ -146,10 +162,21 @@ func TestSettingsValidate(t *testing.T) {
 		`"meta":{`,
 		`"logs":{`,
 		`"smtp":{`,
+		`"captcha":{`,
 		`"s3":{`,
+		`"mailQueue":{`,
 		`"backups":{`,
 		`"batch":{`,
+		`"realtime":{`,
+		`"changefeed":{`,
+		`"recycleBin":{`,
+		`"tombstones":{`,
+		`"metering":{`,
+		`"thumbs":{`,
 		`"rateLimits":{`,
+		`"timeouts":{`,
+		`"requestCapture":{`,
+		`"oauth2HTTPClient":{`,
 	}
 
 	errBytes, _ := json.Marshal(err)
@@ -184,12 +211,14 @@ func TestMetaConfigValidate(t *testing.T) {
 				AppURL:        "test",
 				SenderName:    strings.Repeat("a", 300),
 				SenderAddress: "invalid_email",
+				Locale:        "invalid locale",
 			},
 			[]string{
 				"appName",
 				"appURL",
 				"senderName",
 				"senderAddress",
+				"locale",
 			},
 		},
 		{
@@ -199,6 +228,7 @@ func TestMetaConfigValidate(t *testing.T) {
 				AppURL:        "https://example.com",
 				SenderName:    "test",
 				SenderAddress: "test@example.com",
+				Locale:        "pt_BR",
 			},
 			[]string{},
 		},
@@ -304,6 +334,143 @@ func TestSMTPConfigValidate(t *testing.T) {
 	}
 }
 
+func TestSMSConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.SMSConfig
+		expectedErrors []string
+	}{
+		{
+			"zero values (disabled)",
+			core.SMSConfig{},
+			[]string{},
+		},
+		{
+			"zero values (enabled)",
+			core.SMSConfig{Enabled: true},
+			[]string{"provider"},
+		},
+		{
+			"invalid provider",
+			core.SMSConfig{Enabled: true, Provider: "invalid"},
+			[]string{"provider"},
+		},
+		{
+			"zero twilio values",
+			core.SMSConfig{Enabled: true, Provider: core.SMSProviderTwilio},
+			[]string{"from", "accountSid", "authToken"},
+		},
+		{
+			"valid twilio values",
+			core.SMSConfig{
+				Enabled:    true,
+				Provider:   core.SMSProviderTwilio,
+				From:       "+15551234567",
+				AccountSid: "AC123",
+				AuthToken:  "test",
+			},
+			[]string{},
+		},
+		{
+			"zero webhook values",
+			core.SMSConfig{Enabled: true, Provider: core.SMSProviderWebhook},
+			[]string{"webhookURL"},
+		},
+		{
+			"invalid webhook url",
+			core.SMSConfig{Enabled: true, Provider: core.SMSProviderWebhook, WebhookURL: "invalid"},
+			[]string{"webhookURL"},
+		},
+		{
+			"valid webhook values",
+			core.SMSConfig{Enabled: true, Provider: core.SMSProviderWebhook, WebhookURL: "https://example.com/sms"},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestCaptchaConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.CaptchaConfig
+		expectedErrors []string
+	}{
+		{
+			"zero values (disabled)",
+			core.CaptchaConfig{},
+			[]string{},
+		},
+		{
+			"invalid provider",
+			core.CaptchaConfig{Provider: "invalid", Secret: "test"},
+			[]string{"provider"},
+		},
+		{
+			"missing secret",
+			core.CaptchaConfig{Provider: "turnstile"},
+			[]string{"secret"},
+		},
+		{
+			"invalid verify url",
+			core.CaptchaConfig{Provider: "hcaptcha", Secret: "test", VerifyURL: "invalid"},
+			[]string{"verifyURL"},
+		},
+		{
+			"valid values",
+			core.CaptchaConfig{Provider: "recaptcha", Secret: "test", VerifyURL: "https://example.com/siteverify"},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestMailQueueConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.MailQueueConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.MailQueueConfig{},
+			[]string{},
+		},
+		{
+			"invalid data",
+			core.MailQueueConfig{MaxPerMinute: -1, MaxAttempts: -1, MaxDays: -1},
+			[]string{"maxPerMinute", "maxAttempts", "maxDays"},
+		},
+		{
+			"valid data",
+			core.MailQueueConfig{MaxPerMinute: 10, MaxAttempts: 1, MaxDays: 5},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
 func TestS3ConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		name           string
@@ -485,6 +652,389 @@ func TestBatchConfigValidate(t *testing.T) {
 	}
 }
 
+func TestRealtimeConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.RealtimeConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.RealtimeConfig{},
+			[]string{},
+		},
+		{
+			"invalid data (negative values)",
+			core.RealtimeConfig{
+				MaxClients:        -1,
+				MaxClientsPerAuth: -1,
+				IdleTimeout:       -1,
+				HeartbeatInterval: -1,
+				RetryInterval:     -1,
+			},
+			[]string{"maxClients", "maxClientsPerAuth", "idleTimeout", "heartbeatInterval", "retryInterval"},
+		},
+		{
+			"too large retryInterval",
+			core.RealtimeConfig{
+				RetryInterval: 86400001,
+			},
+			[]string{"retryInterval"},
+		},
+		{
+			"duplicated topic patterns",
+			core.RealtimeConfig{
+				Topics: []core.RealtimeTopic{
+					{Pattern: "chat/{roomId}", Rule: types.Pointer("")},
+					{Pattern: "chat/{roomId}"},
+				},
+			},
+			[]string{"topics"},
+		},
+		{
+			"invalid topic",
+			core.RealtimeConfig{
+				Topics: []core.RealtimeTopic{
+					{Pattern: "chat/{roomId"},
+				},
+			},
+			[]string{"topics"},
+		},
+		{
+			"valid data",
+			core.RealtimeConfig{
+				MaxClients:        100,
+				MaxClientsPerAuth: 5,
+				IdleTimeout:       60,
+				HeartbeatInterval: 15,
+				RetryInterval:     3000,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestRealtimeConfigFindTopic(t *testing.T) {
+	config := core.RealtimeConfig{
+		Topics: []core.RealtimeTopic{
+			{Pattern: "chat/{roomId}", Collection: "a"},
+			{Pattern: "chat/{roomId}/{userId}", Collection: "b"},
+			{Pattern: "{any}/{roomId}", Collection: "c"},
+		},
+	}
+
+	scenarios := []struct {
+		topic              string
+		expectedCollection string
+		expectedParams     map[string]string
+	}{
+		{"", "", nil},
+		{"chat", "", nil},
+		{"chat/", "", nil},
+		{"chat/123", "a", map[string]string{"roomId": "123"}},
+		{"chat/123?options={}", "a", map[string]string{"roomId": "123"}},
+		{"chat/123/456", "b", map[string]string{"roomId": "123", "userId": "456"}},
+		{"other/123", "c", map[string]string{"any": "other", "roomId": "123"}},
+		{"other/123/456/789", "", nil},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.topic, func(t *testing.T) {
+			topic, params, ok := config.FindTopic(s.topic)
+
+			if ok != (s.expectedCollection != "") {
+				t.Fatalf("Expected found %v, got %v", s.expectedCollection != "", ok)
+			}
+
+			if topic.Collection != s.expectedCollection {
+				t.Fatalf("Expected topic with collection %q, got %q", s.expectedCollection, topic.Collection)
+			}
+
+			if len(params) != len(s.expectedParams) {
+				t.Fatalf("Expected params %v, got %v", s.expectedParams, params)
+			}
+			for k, v := range s.expectedParams {
+				if params[k] != v {
+					t.Fatalf("Expected param %q to be %q, got %q", k, v, params[k])
+				}
+			}
+		})
+	}
+}
+
+func TestRealtimeTopicParams(t *testing.T) {
+	scenarios := []struct {
+		pattern  string
+		expected []string
+	}{
+		{"", nil},
+		{"chat", nil},
+		{"chat/{roomId}", []string{"roomId"}},
+		{"{a}/b/{c}", []string{"a", "c"}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.pattern, func(t *testing.T) {
+			params := core.RealtimeTopic{Pattern: s.pattern}.Params()
+
+			if !slices.Equal(params, s.expected) {
+				t.Fatalf("Expected %v, got %v", s.expected, params)
+			}
+		})
+	}
+}
+
+func TestRealtimeTopicValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		topic          core.RealtimeTopic
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.RealtimeTopic{},
+			[]string{"pattern"},
+		},
+		{
+			"invalid pattern segments",
+			core.RealtimeTopic{Pattern: "chat//{roomId}"},
+			[]string{"pattern"},
+		},
+		{
+			"invalid pattern param",
+			core.RealtimeTopic{Pattern: "chat/{room-id}"},
+			[]string{"pattern"},
+		},
+		{
+			"duplicated pattern params",
+			core.RealtimeTopic{Pattern: "chat/{roomId}/{roomId}"},
+			[]string{"pattern"},
+		},
+		{
+			"non-empty rule without collection",
+			core.RealtimeTopic{Pattern: "chat/{roomId}", Rule: types.Pointer("id = @topic.roomId")},
+			[]string{"collection"},
+		},
+		{
+			"unknown rule topic param",
+			core.RealtimeTopic{Pattern: "chat/{roomId}", Collection: "demo1", Rule: types.Pointer("id = @topic.missing")},
+			[]string{"rule"},
+		},
+		{
+			"nil rule without collection",
+			core.RealtimeTopic{Pattern: "chat/{roomId}"},
+			[]string{},
+		},
+		{
+			"empty rule without collection",
+			core.RealtimeTopic{Pattern: "chat/{roomId}", Rule: types.Pointer("")},
+			[]string{},
+		},
+		{
+			"valid data",
+			core.RealtimeTopic{Pattern: "chat/{roomId}/{userId}", Collection: "demo1", Rule: types.Pointer("id = @topic.roomId && @request.auth.id = @topic.userId")},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.topic.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestRealtimeConfigIdleTimeoutDuration(t *testing.T) {
+	scenarios := []struct {
+		idleTimeout int64
+		expected    time.Duration
+	}{
+		{-1, 5 * time.Minute},
+		{0, 5 * time.Minute},
+		{10, 10 * time.Second},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%d", s.idleTimeout), func(t *testing.T) {
+			config := core.RealtimeConfig{IdleTimeout: s.idleTimeout}
+
+			if v := config.IdleTimeoutDuration(); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestChangefeedConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.ChangefeedConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.ChangefeedConfig{},
+			[]string{},
+		},
+		{
+			"invalid data",
+			core.ChangefeedConfig{MaxDays: -1, PayloadVersion: 999},
+			[]string{"maxDays", "payloadVersion"},
+		},
+		{
+			"valid data",
+			core.ChangefeedConfig{Enabled: true, MaxDays: 10, PayloadVersion: core.RecordPayloadVersion1},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestRecycleBinConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.RecycleBinConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.RecycleBinConfig{},
+			[]string{},
+		},
+		{
+			"invalid data",
+			core.RecycleBinConfig{MaxDays: -1},
+			[]string{"maxDays"},
+		},
+		{
+			"valid data",
+			core.RecycleBinConfig{Enabled: true, MaxDays: 10},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestTombstonesConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.TombstonesConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.TombstonesConfig{},
+			[]string{},
+		},
+		{
+			"invalid data",
+			core.TombstonesConfig{MaxDays: -1},
+			[]string{"maxDays"},
+		},
+		{
+			"valid data",
+			core.TombstonesConfig{Enabled: true, MaxDays: 10},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestMeteringConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.MeteringConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.MeteringConfig{},
+			[]string{},
+		},
+		{
+			"invalid data",
+			core.MeteringConfig{MaxDays: -1},
+			[]string{"maxDays"},
+		},
+		{
+			"valid data",
+			core.MeteringConfig{Enabled: true, MaxDays: 10},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestThumbsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.ThumbsConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.ThumbsConfig{},
+			[]string{},
+		},
+		{
+			"invalid data",
+			core.ThumbsConfig{MaxIdleDays: -1},
+			[]string{"maxIdleDays"},
+		},
+		{
+			"valid data",
+			core.ThumbsConfig{MaxIdleDays: 30},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
 func TestRateLimitsConfigValidate(t *testing.T) {
 	scenarios := []struct {
 		name           string
@@ -844,3 +1394,263 @@ func TestRateLimitRuleString(t *testing.T) {
 		})
 	}
 }
+
+func TestTimeoutsConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.TimeoutsConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value (disabled)",
+			core.TimeoutsConfig{},
+			[]string{},
+		},
+		{
+			"zero value (enabled)",
+			core.TimeoutsConfig{Enabled: true},
+			[]string{"rules"},
+		},
+		{
+			"invalid data",
+			core.TimeoutsConfig{
+				Enabled: true,
+				Rules: []core.TimeoutRule{
+					{Label: "/abc/", Timeout: 1},
+					{Label: "abc", Timeout: -1},
+				},
+			},
+			[]string{"rules"},
+		},
+		{
+			"duplicated rules",
+			core.TimeoutsConfig{
+				Enabled: true,
+				Rules: []core.TimeoutRule{
+					{Label: "/a", Timeout: 1},
+					{Label: "/a", Timeout: 2},
+				},
+			},
+			[]string{"rules"},
+		},
+		{
+			"valid data",
+			core.TimeoutsConfig{
+				Enabled: true,
+				Rules: []core.TimeoutRule{
+					{Label: "/a", Timeout: 1},
+					{Label: "POST /a", Timeout: 2},
+					{Label: "/a/", Timeout: 3},
+				},
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestTimeoutsConfigFindTimeoutRule(t *testing.T) {
+	timeouts := core.TimeoutsConfig{
+		Rules: []core.TimeoutRule{
+			{Label: "/test/a"},
+			{Label: "POST /test/a"},
+			{Label: "/test/a/"},
+			{Label: "POST /test/b/"},
+		},
+	}
+
+	scenarios := []struct {
+		labels   []string
+		expected string
+	}{
+		{[]string{}, ""},
+		{[]string{"missing"}, ""},
+		{[]string{"/test"}, ""},
+		{[]string{"/test/a"}, "/test/a"},
+		{[]string{"GET /test/a"}, ""},
+		{[]string{"POST /test/a"}, "POST /test/a"},
+		{[]string{"/test/a/b/c"}, "/test/a/"},
+		{[]string{"GET /test/b/c"}, ""},
+		{[]string{"POST /test/b/c"}, "POST /test/b/"},
+		{[]string{"POST /test/a", "/test/a"}, "POST /test/a"}, // priority checks
+		{[]string{"GET /test/a", "/test/a"}, "/test/a"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(strings.Join(s.labels, "_"), func(t *testing.T) {
+			rule, ok := timeouts.FindTimeoutRule(s.labels)
+
+			hasLabel := rule.Label != ""
+			if hasLabel != ok {
+				t.Fatalf("Expected hasLabel %v, got %v", hasLabel, ok)
+			}
+
+			if rule.Label != s.expected {
+				t.Fatalf("Expected rule with label %q, got %q", s.expected, rule.Label)
+			}
+		})
+	}
+}
+
+func TestTimeoutRuleValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		rule           core.TimeoutRule
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.TimeoutRule{},
+			[]string{"label", "timeout"},
+		},
+		{
+			"invalid data",
+			core.TimeoutRule{Label: "users:auth", Timeout: -1},
+			[]string{"label", "timeout"},
+		},
+		{
+			"valid data (path /a/b)",
+			core.TimeoutRule{Label: "/a/b", Timeout: 1},
+			[]string{},
+		},
+		{
+			"valid data (path POST /a/b/)",
+			core.TimeoutRule{Label: "POST /a/b/", Timeout: 1},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.rule.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestTimeoutRuleDurationTime(t *testing.T) {
+	scenarios := []struct {
+		rule     core.TimeoutRule
+		expected time.Duration
+	}{
+		{core.TimeoutRule{}, 0 * time.Second},
+		{core.TimeoutRule{Timeout: 1234}, 1234 * time.Second},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%d", i, s.rule.Timeout), func(t *testing.T) {
+			result := s.rule.DurationTime()
+
+			if result != s.expected {
+				t.Fatalf("Expected duration %d, got %d", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestRequestCaptureConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.RequestCaptureConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value (disabled)",
+			core.RequestCaptureConfig{},
+			[]string{},
+		},
+		{
+			"zero value (enabled)",
+			core.RequestCaptureConfig{Enabled: true},
+			[]string{"routes"},
+		},
+		{
+			"invalid data",
+			core.RequestCaptureConfig{
+				Enabled:     true,
+				Routes:      []string{"/a", "abc", ""},
+				ScrubFields: []string{"a", "", strings.Repeat("b", 101)},
+				MaxBodySize: -1,
+			},
+			[]string{"routes", "scrubFields", "maxBodySize"},
+		},
+		{
+			"valid data",
+			core.RequestCaptureConfig{
+				Enabled:     true,
+				Routes:      []string{"/a", "POST /a/"},
+				ScrubFields: []string{"a", strings.Repeat("b", 100)},
+				MaxBodySize: 100,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestRequestCaptureConfigMatchRoute(t *testing.T) {
+	config := core.RequestCaptureConfig{
+		Routes: []string{
+			"/test/a",
+			"POST /test/b/",
+		},
+	}
+
+	scenarios := []struct {
+		method   string
+		path     string
+		expected bool
+	}{
+		{"GET", "/test", false},
+		{"GET", "/test/a", true},
+		{"POST", "/test/a", true},
+		{"GET", "/test/a/b", false},
+		{"GET", "/test/b/c", false},
+		{"POST", "/test/b/c", true},
+		{"POST", "/test/b", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.method+" "+s.path, func(t *testing.T) {
+			result := config.MatchRoute(s.method, s.path)
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestRequestCaptureConfigMaxBodySizeValue(t *testing.T) {
+	scenarios := []struct {
+		maxBodySize int64
+		expected    int64
+	}{
+		{-1, core.DefaultRequestCaptureMaxBodySize},
+		{0, core.DefaultRequestCaptureMaxBodySize},
+		{1, 1},
+		{100, 100},
+	}
+
+	for _, s := range scenarios {
+		config := core.RequestCaptureConfig{MaxBodySize: s.maxBodySize}
+
+		if v := config.MaxBodySizeValue(); v != s.expected {
+			t.Fatalf("[%d] Expected %d, got %d", s.maxBodySize, s.expected, v)
+		}
+	}
+}
@@ -0,0 +1,285 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"reflect"
+	"slices"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+)
+
+// SettingsOverlayEnvPrefix is the prefix of the env variables that are loaded as settings overlay.
+//
+// The "__" separated parts after the prefix are the settings json keys path,
+// e.g. "PB_SETTINGS__smtp__host=example.com".
+const SettingsOverlayEnvPrefix = "PB_SETTINGS__"
+
+// LoadSettingsOverlay loads a flattened settings overlay (e.g. {"smtp.host": "example.com"})
+// from the optional json file and the [SettingsOverlayEnvPrefix] env variables in environ
+// (the env variables have precedence over the file values).
+//
+// The env variables values are kept as plain strings and are
+// casted to the related setting type when the overlay is applied.
+func LoadSettingsOverlay(file string, environ []string) (map[string]any, error) {
+	overlay := map[string]any{}
+
+	if file != "" {
+		raw, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the settings overlay file: %w", err)
+		}
+
+		data := map[string]any{}
+		if err := json.Unmarshal(raw, &data); err != nil {
+			return nil, fmt.Errorf("invalid settings overlay file %q: %w", file, err)
+		}
+
+		flattenSettingsOverlay("", data, overlay)
+	}
+
+	for _, env := range environ {
+		name, value, _ := strings.Cut(env, "=")
+
+		path, ok := strings.CutPrefix(name, SettingsOverlayEnvPrefix)
+		if !ok || path == "" {
+			continue
+		}
+
+		overlay[strings.ReplaceAll(path, "__", ".")] = value
+	}
+
+	return overlay, nil
+}
+
+// flattenSettingsOverlay flattens the nested data objects into result
+// using "." as path separator (arrays are not flattened).
+func flattenSettingsOverlay(prefix string, data map[string]any, result map[string]any) {
+	for k, v := range data {
+		if nested, ok := v.(map[string]any); ok && len(nested) > 0 {
+			flattenSettingsOverlay(prefix+k+".", nested, result)
+		} else {
+			result[prefix+k] = v
+		}
+	}
+}
+
+// ApplyOverlay applies the provided flattened settings overlay
+// (usually loaded with [LoadSettingsOverlay]) on top of the current settings.
+//
+// The overlaid keys become read-only, aka. saving the settings with a
+// different value for any of them will fail with a validation error.
+// The overlaid values are also never persisted in the db
+// (the stored values remain the ones before the overlay).
+//
+// Passing an empty overlay resets the previously applied one (if any).
+func (s *Settings) ApplyOverlay(overlay map[string]any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.overlay = nil
+	s.overlayBase = nil
+
+	if len(overlay) == 0 {
+		return nil
+	}
+
+	data, err := settingsToMap(s.settings)
+	if err != nil {
+		return err
+	}
+
+	base := make(map[string]any, len(overlay))
+
+	for _, key := range slices.Sorted(maps.Keys(overlay)) {
+		parent, last, ok := lookupSettingsPath(data, key)
+		if !ok {
+			return fmt.Errorf("unknown settings overlay key %q", key)
+		}
+
+		// omitempty fields (e.g. passwords) may not be present
+		if current, exists := parent[last]; exists {
+			base[key] = current
+		}
+
+		parent[last] = castSettingsOverlayValue(parent[last], overlay[key])
+	}
+
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	newSettings := s.settings
+
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.DisallowUnknownFields() // catch misspelled overlay keys
+	if err := decoder.Decode(&newSettings); err != nil {
+		return fmt.Errorf("invalid settings overlay: %w", err)
+	}
+
+	// normalize the applied values
+	data, err = settingsToMap(newSettings)
+	if err != nil {
+		return err
+	}
+
+	applied := make(map[string]any, len(overlay))
+	for key := range overlay {
+		parent, last, _ := lookupSettingsPath(data, key)
+		applied[key] = parent[last]
+	}
+
+	s.settings = newSettings
+	s.overlay = applied
+	s.overlayBase = base
+
+	return nil
+}
+
+// OverlaidKeys returns the sorted list of the read-only
+// overlaid settings keys (e.g. "smtp.host").
+func (s *Settings) OverlaidKeys() []string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return slices.Sorted(maps.Keys(s.overlay))
+}
+
+// validateOverlay checks whether the overlaid settings values were changed.
+//
+// Note that the caller is expected to hold the settings lock.
+func (s *Settings) validateOverlay() error {
+	if len(s.overlay) == 0 {
+		return nil
+	}
+
+	data, err := settingsToMap(s.settings)
+	if err != nil {
+		return err
+	}
+
+	errs := validation.Errors{}
+
+	for key, expected := range s.overlay {
+		parent, last, ok := lookupSettingsPath(data, key)
+		if ok && reflect.DeepEqual(parent[last], expected) {
+			continue
+		}
+
+		// build the nested error
+		parts := strings.Split(key, ".")
+		current := errs
+		for _, part := range parts[:len(parts)-1] {
+			next, ok := current[part].(validation.Errors)
+			if !ok {
+				next = validation.Errors{}
+				current[part] = next
+			}
+			current = next
+		}
+		current[parts[len(parts)-1]] = validation.NewError(
+			"validation_settings_overlaid",
+			"The setting is read-only because it is defined by the environment settings overlay.",
+		)
+	}
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return errs
+}
+
+// exportWithoutOverlay returns the json encoded settings
+// with the overlaid values replaced with their base ones.
+//
+// Note that the caller is expected to hold the settings lock.
+func (s *Settings) exportWithoutOverlay() ([]byte, error) {
+	if len(s.overlay) == 0 {
+		return json.Marshal(s.settings)
+	}
+
+	data, err := settingsToMap(s.settings)
+	if err != nil {
+		return nil, err
+	}
+
+	for key := range s.overlay {
+		parent, last, ok := lookupSettingsPath(data, key)
+		if !ok {
+			continue
+		}
+
+		if value, ok := s.overlayBase[key]; ok {
+			parent[last] = value
+		} else {
+			delete(parent, last)
+		}
+	}
+
+	return json.Marshal(data)
+}
+
+func settingsToMap(s settings) (map[string]any, error) {
+	raw, err := json.Marshal(s)
+	if err != nil {
+		return nil, err
+	}
+
+	data := map[string]any{}
+
+	return data, json.Unmarshal(raw, &data)
+}
+
+// lookupSettingsPath returns the parent object and the last
+// path segment of the provided "." separated settings key
+// (the last segment itself may not be present in the parent object).
+func lookupSettingsPath(data map[string]any, key string) (map[string]any, string, bool) {
+	parts := strings.Split(key, ".")
+
+	parent := data
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := parent[part].(map[string]any)
+		if !ok {
+			return nil, "", false
+		}
+		parent = next
+	}
+
+	return parent, parts[len(parts)-1], true
+}
+
+// castSettingsOverlayValue casts the plain string overlay values
+// (e.g. from env variables) to the type of the current setting value.
+func castSettingsOverlayValue(current any, value any) any {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	if _, ok := current.(string); ok {
+		return str
+	}
+
+	var decoded any
+	if err := json.Unmarshal([]byte(str), &decoded); err != nil {
+		return str
+	}
+
+	// missing omitempty or null values - decode only arrays and objects
+	if current == nil {
+		switch decoded.(type) {
+		case []any, map[string]any:
+			return decoded
+		default:
+			return str
+		}
+	}
+
+	return decoded
+}
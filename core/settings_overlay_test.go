@@ -0,0 +1,232 @@
+package core_test
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestLoadSettingsOverlay(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	validFile := filepath.Join(dir, "valid.json")
+	err := os.WriteFile(validFile, []byte(`{"smtp":{"host":"file_host","port":25},"meta":{"appName":"file_name"},"rateLimits":{"rules":[]}}`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalidFile := filepath.Join(dir, "invalid.json")
+	err = os.WriteFile(invalidFile, []byte(`{invalid`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		file        string
+		environ     []string
+		expectError bool
+		expected    map[string]any
+	}{
+		{
+			"no file and env variables",
+			"",
+			[]string{"PB_TEST=1", "PB_SETTINGS__=2"},
+			false,
+			map[string]any{},
+		},
+		{
+			"missing file",
+			filepath.Join(dir, "missing.json"),
+			nil,
+			true,
+			nil,
+		},
+		{
+			"invalid file",
+			invalidFile,
+			nil,
+			true,
+			nil,
+		},
+		{
+			"file and env variables",
+			validFile,
+			[]string{"PB_SETTINGS__smtp__host=env_host", "PB_SETTINGS__smtp__password=a=b", "OTHER__smtp__port=1"},
+			false,
+			map[string]any{
+				"smtp.host":        "env_host",
+				"smtp.port":        float64(25),
+				"smtp.password":    "a=b",
+				"meta.appName":     "file_name",
+				"rateLimits.rules": []any{},
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			overlay, err := core.LoadSettingsOverlay(s.file, s.environ)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			rawOverlay, _ := json.Marshal(overlay)
+			rawExpected, _ := json.Marshal(s.expected)
+			if string(rawOverlay) != string(rawExpected) {
+				t.Fatalf("Expected overlay\n%s\ngot\n%s", rawExpected, rawOverlay)
+			}
+		})
+	}
+}
+
+func TestSettingsApplyOverlay(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name        string
+		overlay     map[string]any
+		expectError bool
+	}{
+		{"unknown nested key", map[string]any{"smtp.missing": "test"}, true},
+		{"unknown parent key", map[string]any{"missing.host": "test"}, true},
+		{"invalid value type", map[string]any{"smtp.port": "abc"}, true},
+		{
+			"valid overlay",
+			map[string]any{
+				"smtp.host":     "overlay_host",
+				"smtp.port":     "587",
+				"smtp.enabled":  "true",
+				"smtp.password": "123",
+				"meta.appName":  "overlay_name",
+			},
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			settings := &core.Settings{}
+			settings.SMTP.Host = "base_host"
+			settings.Meta.AppName = "base_name"
+
+			err := settings.ApplyOverlay(s.overlay)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				if len(settings.OverlaidKeys()) != 0 {
+					t.Fatalf("Expected no overlaid keys, got %v", settings.OverlaidKeys())
+				}
+				return
+			}
+
+			if settings.SMTP.Host != "overlay_host" ||
+				settings.SMTP.Port != 587 ||
+				!settings.SMTP.Enabled ||
+				settings.SMTP.Password != "123" ||
+				settings.Meta.AppName != "overlay_name" {
+				t.Fatalf("The overlay was not applied properly: %#v", settings.SMTP)
+			}
+
+			expectedKeys := []string{"meta.appName", "smtp.enabled", "smtp.host", "smtp.password", "smtp.port"}
+			if !slices.Equal(settings.OverlaidKeys(), expectedKeys) {
+				t.Fatalf("Expected overlaid keys %v, got %v", expectedKeys, settings.OverlaidKeys())
+			}
+
+			// reset
+			if err := settings.ApplyOverlay(nil); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(settings.OverlaidKeys()) != 0 {
+				t.Fatalf("Expected the overlay to be reset, got %v", settings.OverlaidKeys())
+			}
+		})
+	}
+}
+
+func TestSettingsOverlayReadOnly(t *testing.T) {
+	// not parallel because of the env variables
+	t.Setenv("PB_SETTINGS__meta__appName", "overlay_name")
+	t.Setenv("PB_SETTINGS__smtp__password", "overlay_password")
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if app.Settings().Meta.AppName != "overlay_name" || app.Settings().SMTP.Password != "overlay_password" {
+		t.Fatalf("Expected the overlay to be applied, got %q, %q", app.Settings().Meta.AppName, app.Settings().SMTP.Password)
+	}
+
+	// change of overlaid setting
+	// ---
+	clone, err := app.Settings().Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone.Meta.AppName = "changed"
+
+	err = app.Save(clone)
+
+	var validationErrs validation.Errors
+	if !errors.As(err, &validationErrs) {
+		t.Fatalf("Expected validation errors, got %v", err)
+	}
+
+	metaErrs, _ := validationErrs["meta"].(validation.Errors)
+	if _, ok := metaErrs["appName"]; !ok || len(validationErrs) != 1 {
+		t.Fatalf("Expected only meta.appName error, got %v", validationErrs)
+	}
+
+	// change of non-overlaid setting
+	// ---
+	clone, err = app.Settings().Clone()
+	if err != nil {
+		t.Fatal(err)
+	}
+	clone.Meta.SenderName = "changed"
+
+	if err := app.Save(clone); err != nil {
+		t.Fatal(err)
+	}
+
+	if app.Settings().Meta.SenderName != "changed" || app.Settings().Meta.AppName != "overlay_name" {
+		t.Fatalf("Expected the reloaded settings to be changed and still overlaid, got %#v", app.Settings().Meta)
+	}
+
+	// the overlaid values shouldn't be persisted
+	// ---
+	param := &core.Param{}
+	if err := app.ModelQuery(param).Model("settings", param); err != nil {
+		t.Fatal(err)
+	}
+
+	stored := string(param.Value)
+
+	if !strings.Contains(stored, `"senderName":"changed"`) {
+		t.Fatalf("Expected the changed senderName to be stored, got %s", stored)
+	}
+
+	if strings.Contains(stored, "overlay_") {
+		t.Fatalf("Expected the overlay values to not be stored, got %s", stored)
+	}
+}
@@ -45,7 +45,11 @@ func (app *BaseApp) ReloadSettings() error {
 	event.App = app
 
 	return app.OnSettingsReload().Trigger(event, func(e *SettingsReloadEvent) error {
-		return e.App.Settings().loadParam(e.App, param)
+		if err := e.App.Settings().loadParam(e.App, param); err != nil {
+			return err
+		}
+
+		return e.App.Settings().ApplyOverlay(e.App.SettingsOverlay())
 	})
 }
 
@@ -0,0 +1,306 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const SignupInvitesTableName = "_signupInvites"
+
+const signupInviteTokenLength = 40
+
+var (
+	_ Model         = (*SignupInvite)(nil)
+	_ PostValidator = (*SignupInvite)(nil)
+)
+
+// SignupInvite defines an expiring invite token that allows creating
+// new auth records via the signup-with-invite API regardless of the
+// collection create API rule (e.g. for closed beta signups).
+//
+// On successful signup the invite Roles and team membership
+// (if TeamRef is set) are assigned to the new auth record.
+//
+// Only the invite token hash is stored and the plain token is available
+// only once on creation (see [App.CreateSignupInvite]).
+type SignupInvite struct {
+	BaseModel
+
+	// CollectionRef is the auth collection id of the invite.
+	CollectionRef string `db:"collectionRef" json:"collectionRef"`
+
+	// Email optionally restricts the invite to a single email address.
+	Email string `db:"email" json:"email"`
+
+	// MaxUses is the max number of signups allowed with the invite (0 means unlimited).
+	MaxUses int `db:"maxUses" json:"maxUses"`
+
+	// Uses is the number of the already made signups with the invite.
+	Uses int `db:"uses" json:"uses"`
+
+	// Roles is a list with role names to assign to the new auth record.
+	Roles types.JSONArray[string] `db:"roles" json:"roles"`
+
+	// TeamRef optionally specifies a team to join the new auth record to with TeamRole.
+	TeamRef  string `db:"teamRef" json:"teamRef"`
+	TeamRole string `db:"teamRole" json:"teamRole"`
+
+	TokenHash string         `db:"tokenHash" json:"-"`
+	Expires   types.DateTime `db:"expires" json:"expires"`
+	Created   types.DateTime `db:"created" json:"created"`
+	Updated   types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the SignupInvite model table name.
+func (m *SignupInvite) TableName() string {
+	return SignupInvitesTableName
+}
+
+// IsExpired reports whether the invite has expired.
+func (m *SignupInvite) IsExpired() bool {
+	return !m.Expires.Time().After(time.Now())
+}
+
+// IsExhausted reports whether the invite has reached its max uses.
+func (m *SignupInvite) IsExhausted() bool {
+	return m.MaxUses > 0 && m.Uses >= m.MaxUses
+}
+
+// AllowsEmail reports whether the invite could be used for signup with the specified email.
+func (m *SignupInvite) AllowsEmail(email string) bool {
+	return m.Email == "" || strings.EqualFold(m.Email, strings.TrimSpace(email))
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *SignupInvite) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(&m.CollectionRef, validation.Required, validation.By(validateCollectionId(app, CollectionTypeAuth))),
+		validation.Field(&m.Email, validation.Length(1, 255), is.EmailFormat),
+		validation.Field(&m.MaxUses, validation.Min(0)),
+		validation.Field(&m.Uses, validation.Min(0)),
+		validation.Field(&m.Roles, validation.Each(validation.Required, validation.By(func(value any) error {
+			v, _ := value.(string)
+			if _, err := app.FindRoleByName(v); err != nil {
+				return validation.NewError("validation_invalid_role", "Missing or invalid role.")
+			}
+			return nil
+		}))),
+		validation.Field(&m.TeamRef, validation.By(validateTeamId(app))),
+		validation.Field(
+			&m.TeamRole,
+			validation.When(m.TeamRef != "", validation.Required),
+			validation.When(m.TeamRef == "", validation.Empty),
+			validation.In(TeamRoleOwner, TeamRoleAdmin, TeamRoleMember),
+		),
+		validation.Field(&m.TokenHash, validation.Required),
+		validation.Field(&m.Expires, validation.Required),
+	)
+}
+
+// -------------------------------------------------------------------
+
+// SignupInviteQuery returns a new SignupInvite select query.
+func (app *BaseApp) SignupInviteQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&SignupInvite{})
+}
+
+// FindSignupInviteByToken finds the active (aka. non-expired and non-exhausted)
+// signup invite by its plain token.
+func (app *BaseApp) FindSignupInviteByToken(token string) (*SignupInvite, error) {
+	if token == "" {
+		return nil, errors.New("missing invite token")
+	}
+
+	result := &SignupInvite{}
+
+	err := app.SignupInviteQuery().
+		AndWhere(dbx.HashExp{"tokenHash": security.SHA256(token)}).
+		AndWhere(activeSignupInviteExpr()).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateSignupInvite generates a new token for the provided
+// (unsaved) signup invite and saves it with expiration after ttl.
+//
+// Returns the plain invite token (it is not stored and cannot be retrieved later).
+func (app *BaseApp) CreateSignupInvite(invite *SignupInvite, ttl time.Duration) (string, error) {
+	if ttl <= 0 {
+		return "", errors.New("the invite ttl must be positive")
+	}
+
+	token := security.RandomString(signupInviteTokenLength)
+
+	invite.Email = strings.ToLower(strings.TrimSpace(invite.Email))
+	invite.Uses = 0
+	invite.TokenHash = security.SHA256(token)
+	invite.Expires = types.NowDateTime().Add(ttl)
+	if invite.Roles == nil {
+		invite.Roles = types.JSONArray[string]{}
+	}
+
+	if err := app.Save(invite); err != nil {
+		return "", err
+	}
+
+	return token, nil
+}
+
+// UseSignupInvite registers a single use of the invite by the provided
+// (usually newly created) auth record and assigns to it the invite roles and team.
+//
+// It is recommended to call it in the same transaction as the auth record create.
+func (app *BaseApp) UseSignupInvite(invite *SignupInvite, authRecord *Record) error {
+	if invite.CollectionRef != authRecord.Collection().Id {
+		return errors.New("the invite is for a different collection")
+	}
+
+	if !invite.AllowsEmail(authRecord.Email()) {
+		return errors.New("the invite is for a different email address")
+	}
+
+	return app.RunInTransaction(func(txApp App) error {
+		// increment with a condition to prevent concurrent overuse
+		result, err := txApp.NonconcurrentDB().Update(
+			SignupInvitesTableName,
+			dbx.Params{
+				"uses":    dbx.NewExp("[[uses]] + 1"),
+				"updated": types.NowDateTime().String(),
+			},
+			dbx.And(dbx.HashExp{"id": invite.Id}, activeSignupInviteExpr()),
+		).Execute()
+		if err != nil {
+			return err
+		}
+
+		if n, _ := result.RowsAffected(); n == 0 {
+			return errors.New("the invite has expired or reached its max uses")
+		}
+
+		invite.Uses++
+
+		for _, name := range invite.Roles {
+			role, err := txApp.FindRoleByName(name)
+			if err != nil {
+				txApp.Logger().Warn("Missing signup invite role", "inviteId", invite.Id, "role", name)
+				continue
+			}
+
+			if err := txApp.AssignRole(authRecord, role); err != nil {
+				return err
+			}
+		}
+
+		if invite.TeamRef != "" {
+			team, err := txApp.FindTeamById(invite.TeamRef)
+			if err != nil {
+				return err
+			}
+
+			if _, err := txApp.AddTeamMember(team, authRecord, invite.TeamRole); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// DeleteExpiredSignupInvites deletes all expired and exhausted signup invites.
+func (app *BaseApp) DeleteExpiredSignupInvites() error {
+	_, err := app.NonconcurrentDB().Delete(SignupInvitesTableName, dbx.Not(activeSignupInviteExpr())).Execute()
+
+	return err
+}
+
+func activeSignupInviteExpr() dbx.Expression {
+	return dbx.NewExp(
+		"[[expires]] > {:now} AND ([[maxUses]] = 0 OR [[uses]] < [[maxUses]])",
+		dbx.Params{"now": types.NowDateTime().String()},
+	)
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerSignupInviteHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*SignupInvite); ok {
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				m.Created = types.NowDateTime()
+				m.Updated = m.Created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if m, ok := e.Model.(*SignupInvite); ok {
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// delete the team invites together with the team
+	app.OnModelDeleteExecute().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			if m, ok := e.Model.(*Team); ok {
+				_, err := e.App.NonconcurrentDB().Delete(SignupInvitesTableName, dbx.HashExp{"teamRef": m.Id}).Execute()
+				return err
+			}
+
+			return nil
+		},
+		Priority: 99,
+	})
+
+	// delete the collection invites
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil || !e.Collection.IsAuth() {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(SignupInvitesTableName, dbx.HashExp{
+				"collectionRef": e.Collection.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.Cron().Add("__pbSignupInvitesCleanup__", "0 * * * *", func() {
+		if err := app.DeleteExpiredSignupInvites(); err != nil {
+			app.Logger().Warn("Failed to delete expired signup invites", "error", err)
+		}
+	})
+}
@@ -0,0 +1,318 @@
+package core_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestSignupInviteTableName(t *testing.T) {
+	t.Parallel()
+
+	m := &core.SignupInvite{}
+	if m.TableName() != "_signupInvites" {
+		t.Fatalf("Unexpected table name, got %q", m.TableName())
+	}
+}
+
+func TestSignupInviteIsExhausted(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		maxUses  int
+		uses     int
+		expected bool
+	}{
+		{0, 0, false},
+		{0, 100, false},
+		{1, 0, false},
+		{1, 1, true},
+		{2, 1, false},
+		{2, 3, true},
+	}
+
+	for _, s := range scenarios {
+		m := &core.SignupInvite{MaxUses: s.maxUses, Uses: s.uses}
+
+		if v := m.IsExhausted(); v != s.expected {
+			t.Fatalf("[%d/%d] Expected %v, got %v", s.uses, s.maxUses, s.expected, v)
+		}
+	}
+}
+
+func TestSignupInviteAllowsEmail(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		inviteEmail string
+		email       string
+		expected    bool
+	}{
+		{"", "", true},
+		{"", "test@example.com", true},
+		{"test@example.com", "", false},
+		{"test@example.com", "other@example.com", false},
+		{"test@example.com", "test@example.com", true},
+		{"test@example.com", " Test@Example.com ", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.inviteEmail+"_"+s.email, func(t *testing.T) {
+			m := &core.SignupInvite{Email: s.inviteEmail}
+
+			if v := m.AllowsEmail(s.email); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestSignupInviteValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	team := &core.Team{Name: "test"}
+	if err := app.Save(team); err != nil {
+		t.Fatal(err)
+	}
+
+	role := &core.Role{Name: "editor"}
+	if err := app.Save(role); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		model        *core.SignupInvite
+		expectedErrs []string
+	}{
+		{
+			"empty",
+			&core.SignupInvite{},
+			[]string{"id", "collectionRef", "TokenHash", "expires"},
+		},
+		{
+			"invalid fields",
+			&core.SignupInvite{
+				BaseModel:     core.BaseModel{Id: "test"},
+				CollectionRef: demo1.Id,
+				Email:         "invalid",
+				MaxUses:       -1,
+				Uses:          -1,
+				Roles:         []string{"missing"},
+				TeamRef:       "missing",
+				TeamRole:      "invalid",
+				TokenHash:     "test",
+				Expires:       types.NowDateTime(),
+			},
+			[]string{"collectionRef", "email", "maxUses", "uses", "roles", "teamRef", "teamRole"},
+		},
+		{
+			"team without role",
+			&core.SignupInvite{
+				BaseModel:     core.BaseModel{Id: "test"},
+				CollectionRef: users.Id,
+				TeamRef:       team.Id,
+				TokenHash:     "test",
+				Expires:       types.NowDateTime(),
+			},
+			[]string{"teamRole"},
+		},
+		{
+			"role without team",
+			&core.SignupInvite{
+				BaseModel:     core.BaseModel{Id: "test"},
+				CollectionRef: users.Id,
+				TeamRole:      core.TeamRoleMember,
+				TokenHash:     "test",
+				Expires:       types.NowDateTime(),
+			},
+			[]string{"teamRole"},
+		},
+		{
+			"valid",
+			&core.SignupInvite{
+				BaseModel:     core.BaseModel{Id: "test"},
+				CollectionRef: users.Id,
+				Email:         "new@example.com",
+				MaxUses:       5,
+				Roles:         []string{role.Name},
+				TeamRef:       team.Id,
+				TeamRole:      core.TeamRoleAdmin,
+				TokenHash:     "test",
+				Expires:       types.NowDateTime(),
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, app.Validate(s.model), s.expectedErrs)
+		})
+	}
+}
+
+func TestSignupInvites(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user1, err := app.FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	role := &core.Role{Name: "editor"}
+	if err := app.Save(role); err != nil {
+		t.Fatal(err)
+	}
+
+	team := &core.Team{Name: "test"}
+	if err := app.Save(team); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.CreateSignupInvite(&core.SignupInvite{CollectionRef: users.Id}, 0); err == nil {
+		t.Fatal("Expected ttl error")
+	}
+
+	invite := &core.SignupInvite{
+		CollectionRef: users.Id,
+		MaxUses:       2,
+		Uses:          10, // should be reset
+		Roles:         []string{role.Name},
+		TeamRef:       team.Id,
+		TeamRole:      core.TeamRoleMember,
+	}
+
+	token, err := app.CreateSignupInvite(invite, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if invite.Uses != 0 || invite.TokenHash == token || len(token) != 40 {
+		t.Fatalf("Unexpected invite %v (token %q)", invite, token)
+	}
+
+	if _, err := app.FindSignupInviteByToken(""); err == nil {
+		t.Fatal("Expected empty token error")
+	}
+
+	if _, err := app.FindSignupInviteByToken("missing"); err == nil {
+		t.Fatal("Expected missing token error")
+	}
+
+	found, err := app.FindSignupInviteByToken(token)
+	if err != nil || found.Id != invite.Id {
+		t.Fatalf("Expected to find invite %q, got %v (%v)", invite.Id, found, err)
+	}
+
+	for _, user := range []*core.Record{user1, user2} {
+		if err := app.UseSignupInvite(found, user); err != nil {
+			t.Fatal(err)
+		}
+
+		if !app.AuthRecordHasRole(user, role.Name) {
+			t.Fatalf("Expected %q to have role %q", user.Id, role.Name)
+		}
+
+		membership, err := app.FindTeamMembership(team, user)
+		if err != nil || membership.Role != core.TeamRoleMember {
+			t.Fatalf("Expected %q to be a team member, got %v (%v)", user.Id, membership, err)
+		}
+	}
+
+	if found.Uses != 2 {
+		t.Fatalf("Expected 2 uses, got %d", found.Uses)
+	}
+
+	// exhausted
+	if err := app.UseSignupInvite(found, user1); err == nil {
+		t.Fatal("Expected exhausted invite error")
+	}
+	if _, err := app.FindSignupInviteByToken(token); err == nil {
+		t.Fatal("Expected exhausted invite find error")
+	}
+
+	// email restricted
+	emailInvite := &core.SignupInvite{CollectionRef: users.Id, Email: " Other@example.com "}
+	if _, err := app.CreateSignupInvite(emailInvite, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if emailInvite.Email != "other@example.com" {
+		t.Fatalf("Expected the invite email to be normalized, got %q", emailInvite.Email)
+	}
+	if err := app.UseSignupInvite(emailInvite, user1); err == nil {
+		t.Fatal("Expected different email error")
+	}
+
+	// cleanup of the exhausted and expired invites
+	expiredInvite := &core.SignupInvite{CollectionRef: users.Id, MaxUses: 0}
+	if _, err := app.CreateSignupInvite(expiredInvite, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	expiredInvite.Expires = types.NowDateTime().Add(-time.Minute)
+	if err := app.Save(expiredInvite); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.DeleteExpiredSignupInvites(); err != nil {
+		t.Fatal(err)
+	}
+	if total := countTeamRows(t, app, &core.SignupInvite{}); total != 1 {
+		t.Fatalf("Expected only the email invite to remain, got %d invites", total)
+	}
+
+	// team delete
+	teamInvite := &core.SignupInvite{CollectionRef: users.Id, TeamRef: team.Id, TeamRole: core.TeamRoleAdmin}
+	if _, err := app.CreateSignupInvite(teamInvite, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(team); err != nil {
+		t.Fatal(err)
+	}
+	if total := countTeamRows(t, app, &core.SignupInvite{}); total != 1 {
+		t.Fatalf("Expected the team invite to be deleted, got %d invites", total)
+	}
+
+	// collection delete
+	newAuth := core.NewAuthCollection("new_auth")
+	if err := app.Save(newAuth); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.CreateSignupInvite(&core.SignupInvite{CollectionRef: newAuth.Id}, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(newAuth); err != nil {
+		t.Fatal(err)
+	}
+	if total := countTeamRows(t, app, &core.SignupInvite{}); total != 1 {
+		t.Fatalf("Expected the new_auth invite to be deleted, got %d invites", total)
+	}
+}
@@ -0,0 +1,150 @@
+package core
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/pocketbase/core/validators"
+)
+
+// StaticConfig defines a list of static directory mounts.
+//
+// Note that the static mounts are intentionally not part of the app settings
+// because they allow serving arbitrary local directories, so they could be
+// registered only programmatically or with a CLI flag (see apis.StaticMounts).
+type StaticConfig struct {
+	// Mounts is a list of local directories to serve
+	// as static files under the specified path prefixes.
+	Mounts []StaticMount `form:"mounts" json:"mounts"`
+}
+
+// FindMount returns the mount with the longest path prefix matching the provided url path.
+func (c *StaticConfig) FindMount(urlPath string) (StaticMount, bool) {
+	var result StaticMount
+	var found bool
+
+	for _, mount := range c.Mounts {
+		if !mount.Match(urlPath) {
+			continue
+		}
+
+		if !found || len(mount.CleanPath()) > len(result.CleanPath()) {
+			result = mount
+			found = true
+		}
+	}
+
+	return result, found
+}
+
+// Validate makes StaticConfig validatable by implementing [validation.Validatable] interface.
+func (c StaticConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Mounts, validation.By(checkUniqueStaticMountPath)),
+	)
+}
+
+func checkUniqueStaticMountPath(value any) error {
+	mounts, ok := value.([]StaticMount)
+	if !ok {
+		return validators.ErrUnsupportedValueType
+	}
+
+	existing := make(map[string]struct{}, len(mounts))
+
+	for i, mount := range mounts {
+		path := mount.CleanPath()
+
+		if _, ok := existing[path]; ok {
+			return validation.Errors{
+				strconv.Itoa(i): validation.Errors{
+					"path": validation.NewError("validation_duplicated_static_mount", "Static mount with path {{.path}} already exists.").
+						SetParams(map[string]any{"path": mount.Path}),
+				},
+			}
+		}
+
+		existing[path] = struct{}{}
+	}
+
+	return nil
+}
+
+// StaticMount defines a single static directory mount.
+type StaticMount struct {
+	// Path is the url path prefix of the mount, eg. "/docs".
+	Path string `form:"path" json:"path"`
+
+	// Dir is the local directory to serve (relative paths are resolved against the current working directory).
+	Dir string `form:"dir" json:"dir"`
+
+	// CacheControl is an optional Cache-Control header value
+	// to send with the served files, eg. "public, max-age=3600".
+	CacheControl string `form:"cacheControl" json:"cacheControl"`
+
+	// Rule is an optional filter expression that the request must satisfy
+	// in order to access the mount files, eg. "@request.auth.id != ''".
+	//
+	// Only the "@request.*" fields are allowed.
+	// Leave it empty to allow public access.
+	Rule string `form:"rule" json:"rule"`
+
+	// IndexFallback forwards the missing file requests to the mount
+	// root index.html (useful for SPA with pretty urls).
+	IndexFallback bool `form:"indexFallback" json:"indexFallback"`
+}
+
+// CleanPath returns the normalized mount path prefix without trailing slash ("/" for the root mount).
+func (m StaticMount) CleanPath() string {
+	return "/" + strings.Trim(m.Path, "/")
+}
+
+// Match reports whether the provided url path is under the mount path prefix.
+func (m StaticMount) Match(urlPath string) bool {
+	prefix := m.CleanPath()
+	if prefix == "/" {
+		return true
+	}
+
+	return urlPath == prefix || strings.HasPrefix(urlPath, prefix+"/")
+}
+
+// Validate makes StaticMount validatable by implementing [validation.Validatable] interface.
+func (m StaticMount) Validate() error {
+	return validation.ValidateStruct(&m,
+		validation.Field(
+			&m.Path,
+			validation.Required,
+			validation.Length(1, 255),
+			validation.Match(staticMountPathRegex),
+			validation.By(checkReservedStaticMountPath),
+		),
+		validation.Field(&m.Dir, validation.Required, validation.Length(1, 1000)),
+		validation.Field(&m.CacheControl, validation.Length(0, 255), is.PrintableASCII),
+		validation.Field(&m.Rule, validation.Length(0, 1000)),
+	)
+}
+
+var staticMountPathRegex = regexp.MustCompile(`^\/[\w\-\.\/~]*$`)
+
+func checkReservedStaticMountPath(value any) error {
+	v, _ := value.(string)
+
+	path := StaticMount{Path: v}.CleanPath()
+
+	if path == "/" {
+		return validation.NewError("validation_root_static_mount", "The root path cannot be used as static mount.")
+	}
+
+	for _, reserved := range []string{"/api", "/_"} {
+		if path == reserved || strings.HasPrefix(path, reserved+"/") {
+			return validation.NewError("validation_reserved_static_mount", "The {{.path}} path is reserved and cannot be used as static mount.").
+				SetParams(map[string]any{"path": reserved})
+		}
+	}
+
+	return nil
+}
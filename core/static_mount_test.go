@@ -0,0 +1,142 @@
+package core_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestStaticConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         core.StaticConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.StaticConfig{},
+			[]string{},
+		},
+		{
+			"invalid mount",
+			core.StaticConfig{Mounts: []core.StaticMount{{Path: "/docs", Dir: "./docs"}, {}}},
+			[]string{"mounts"},
+		},
+		{
+			"duplicated mount paths",
+			core.StaticConfig{Mounts: []core.StaticMount{{Path: "/docs", Dir: "./docs"}, {Path: "/docs/", Dir: "./other"}}},
+			[]string{"mounts"},
+		},
+		{
+			"valid mounts",
+			core.StaticConfig{Mounts: []core.StaticMount{{Path: "/docs", Dir: "./docs"}, {Path: "/docs/v2", Dir: "./other"}}},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
+func TestStaticConfigFindMount(t *testing.T) {
+	config := core.StaticConfig{
+		Mounts: []core.StaticMount{
+			{Path: "/docs", Dir: "a"},
+			{Path: "/docs/v2/", Dir: "b"},
+			{Path: "/assets", Dir: "c"},
+		},
+	}
+
+	scenarios := []struct {
+		path     string
+		expected string
+	}{
+		{"/", ""},
+		{"/missing", ""},
+		{"/docsx", ""},
+		{"/docs", "a"},
+		{"/docs/", "a"},
+		{"/docs/v1/test.html", "a"},
+		{"/docs/v2", "b"},
+		{"/docs/v2/test.html", "b"},
+		{"/assets/app.js", "c"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.path, func(t *testing.T) {
+			mount, ok := config.FindMount(s.path)
+
+			if ok != (s.expected != "") {
+				t.Fatalf("Expected found %v, got %v", s.expected != "", ok)
+			}
+
+			if mount.Dir != s.expected {
+				t.Fatalf("Expected mount with dir %q, got %q", s.expected, mount.Dir)
+			}
+		})
+	}
+}
+
+func TestStaticMountValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		mount          core.StaticMount
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			core.StaticMount{},
+			[]string{"path", "dir"},
+		},
+		{
+			"invalid path format",
+			core.StaticMount{Path: "docs test", Dir: "./docs"},
+			[]string{"path"},
+		},
+		{
+			"root path",
+			core.StaticMount{Path: "/", Dir: "./docs"},
+			[]string{"path"},
+		},
+		{
+			"reserved /api path",
+			core.StaticMount{Path: "/api/docs", Dir: "./docs"},
+			[]string{"path"},
+		},
+		{
+			"reserved /_ path",
+			core.StaticMount{Path: "/_/", Dir: "./docs"},
+			[]string{"path"},
+		},
+		{
+			"non-printable cache control",
+			core.StaticMount{Path: "/docs", Dir: "./docs", CacheControl: "max-age=10\n"},
+			[]string{"cacheControl"},
+		},
+		{
+			"valid data",
+			core.StaticMount{
+				Path:          "/api-docs/v1.0",
+				Dir:           "./docs",
+				CacheControl:  "public, max-age=3600",
+				Rule:          "@request.auth.id != ''",
+				IndexFallback: true,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.mount.Validate()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
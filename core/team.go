@@ -0,0 +1,544 @@
+package core
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/go-ozzo/ozzo-validation/v4/is"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/security"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	TeamsTableName           = "_teams"
+	TeamMembershipsTableName = "_teamMemberships"
+	TeamInvitationsTableName = "_teamInvitations"
+)
+
+// The supported team membership roles.
+const (
+	// TeamRoleOwner could manage the team, its members and invitations.
+	TeamRoleOwner = "owner"
+
+	// TeamRoleAdmin could manage the team members and invitations
+	// (except the owners).
+	TeamRoleAdmin = "admin"
+
+	// TeamRoleMember is a regular team member.
+	TeamRoleMember = "member"
+)
+
+const teamInvitationTokenLength = 40
+
+var (
+	_ Model         = (*Team)(nil)
+	_ PostValidator = (*Team)(nil)
+	_ Model         = (*TeamMembership)(nil)
+	_ PostValidator = (*TeamMembership)(nil)
+	_ Model         = (*TeamInvitation)(nil)
+	_ PostValidator = (*TeamInvitation)(nil)
+)
+
+// Team defines a named group of auth records (aka. members).
+//
+// The team membership could be checked in the API rules with
+// @request.auth.memberOf(teamId) or @request.auth.memberOf(teamId, "role").
+type Team struct {
+	BaseModel
+
+	Name    string         `db:"name" json:"name"`
+	Created types.DateTime `db:"created" json:"created"`
+	Updated types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the Team model table name.
+func (m *Team) TableName() string {
+	return TeamsTableName
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *Team) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(&m.Name, validation.Required, validation.Length(1, 255)),
+	)
+}
+
+// TeamMembership defines a single auth record team membership.
+type TeamMembership struct {
+	BaseModel
+
+	TeamRef       string         `db:"teamRef" json:"teamRef"`
+	CollectionRef string         `db:"collectionRef" json:"collectionRef"`
+	RecordRef     string         `db:"recordRef" json:"recordRef"`
+	Role          string         `db:"role" json:"role"`
+	Created       types.DateTime `db:"created" json:"created"`
+	Updated       types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the TeamMembership model table name.
+func (m *TeamMembership) TableName() string {
+	return TeamMembershipsTableName
+}
+
+// IsFor reports whether the membership is of the provided auth record.
+func (m *TeamMembership) IsFor(authRecord *Record) bool {
+	return authRecord != nil &&
+		m.RecordRef == authRecord.Id &&
+		m.CollectionRef == authRecord.Collection().Id
+}
+
+// CanManage reports whether the membership role allows managing the
+// team members and invitations with the specified role.
+func (m *TeamMembership) CanManage(role string) bool {
+	switch m.Role {
+	case TeamRoleOwner:
+		return true
+	case TeamRoleAdmin:
+		return role != TeamRoleOwner
+	}
+
+	return false
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *TeamMembership) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(&m.TeamRef, validation.Required, validation.By(validateTeamId(app))),
+		validation.Field(&m.CollectionRef, validation.Required, validation.By(validateCollectionId(app, CollectionTypeAuth))),
+		validation.Field(&m.RecordRef, validation.Required, validation.By(validateRecordId(app, m.CollectionRef))),
+		validation.Field(&m.Role, validation.Required, validation.In(TeamRoleOwner, TeamRoleAdmin, TeamRoleMember)),
+	)
+}
+
+// TeamInvitation defines a single expiring team invitation sent by email.
+//
+// Only the invitation token hash is stored and the plain token is available
+// only once on creation (see [App.CreateTeamInvitation]).
+type TeamInvitation struct {
+	BaseModel
+
+	TeamRef              string         `db:"teamRef" json:"teamRef"`
+	Email                string         `db:"email" json:"email"`
+	Role                 string         `db:"role" json:"role"`
+	InviterCollectionRef string         `db:"inviterCollectionRef" json:"inviterCollectionRef"`
+	InviterRef           string         `db:"inviterRef" json:"inviterRef"`
+	TokenHash            string         `db:"tokenHash" json:"-"`
+	Expires              types.DateTime `db:"expires" json:"expires"`
+	Created              types.DateTime `db:"created" json:"created"`
+	Updated              types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the TeamInvitation model table name.
+func (m *TeamInvitation) TableName() string {
+	return TeamInvitationsTableName
+}
+
+// IsExpired reports whether the invitation has expired.
+func (m *TeamInvitation) IsExpired() bool {
+	return !m.Expires.Time().After(time.Now())
+}
+
+// PostValidate implements the [PostValidator] interface.
+func (m *TeamInvitation) PostValidate(ctx context.Context, app App) error {
+	return validation.ValidateStruct(m,
+		validation.Field(&m.Id, validation.Required, validation.Length(1, 100)),
+		validation.Field(&m.TeamRef, validation.Required, validation.By(validateTeamId(app))),
+		validation.Field(&m.Email, validation.Required, validation.Length(1, 255), is.EmailFormat),
+		validation.Field(&m.Role, validation.Required, validation.In(TeamRoleOwner, TeamRoleAdmin, TeamRoleMember)),
+		validation.Field(&m.InviterCollectionRef, validation.By(validateCollectionId(app, CollectionTypeAuth))),
+		validation.Field(
+			&m.InviterRef,
+			validation.When(m.InviterCollectionRef != "", validation.Required),
+			validation.By(validateRecordId(app, m.InviterCollectionRef)),
+		),
+		validation.Field(&m.TokenHash, validation.Required),
+		validation.Field(&m.Expires, validation.Required),
+	)
+}
+
+func validateTeamId(app App) validation.RuleFunc {
+	return func(value any) error {
+		v, _ := value.(string)
+		if v == "" {
+			return nil // nothing to check
+		}
+
+		if _, err := app.FindTeamById(v); err != nil {
+			return validation.NewError("validation_invalid_team", "Missing or invalid team.")
+		}
+
+		return nil
+	}
+}
+
+// -------------------------------------------------------------------
+
+// TeamQuery returns a new Team select query.
+func (app *BaseApp) TeamQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&Team{})
+}
+
+// TeamMembershipQuery returns a new TeamMembership select query.
+func (app *BaseApp) TeamMembershipQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&TeamMembership{})
+}
+
+// TeamInvitationQuery returns a new TeamInvitation select query.
+func (app *BaseApp) TeamInvitationQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&TeamInvitation{})
+}
+
+// FindTeamById returns a single Team model by its id.
+func (app *BaseApp) FindTeamById(id string) (*Team, error) {
+	result := &Team{}
+
+	err := app.TeamQuery().
+		AndWhere(dbx.HashExp{"id": id}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindAuthRecordTeams returns all teams that the provided auth record is member of (ordered by name).
+func (app *BaseApp) FindAuthRecordTeams(authRecord *Record) ([]*Team, error) {
+	result := []*Team{}
+
+	err := app.TeamQuery().
+		InnerJoin(
+			TeamMembershipsTableName+" tm",
+			dbx.NewExp("[[tm.teamRef]] = {{"+TeamsTableName+"}}.[[id]]"),
+		).
+		AndWhere(dbx.HashExp{
+			"tm.collectionRef": authRecord.Collection().Id,
+			"tm.recordRef":     authRecord.Id,
+		}).
+		OrderBy(TeamsTableName + ".name ASC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindTeamMemberships returns all memberships of the provided team (ordered by their creation date).
+func (app *BaseApp) FindTeamMemberships(team *Team) ([]*TeamMembership, error) {
+	result := []*TeamMembership{}
+
+	err := app.TeamMembershipQuery().
+		AndWhere(dbx.HashExp{"teamRef": team.Id}).
+		OrderBy("created ASC", "id ASC").
+		All(&result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// FindTeamMembership returns the team membership of the provided auth record.
+func (app *BaseApp) FindTeamMembership(team *Team, authRecord *Record) (*TeamMembership, error) {
+	if authRecord == nil {
+		return nil, sql.ErrNoRows
+	}
+
+	result := &TeamMembership{}
+
+	err := app.TeamMembershipQuery().
+		AndWhere(dbx.HashExp{
+			"teamRef":       team.Id,
+			"collectionRef": authRecord.Collection().Id,
+			"recordRef":     authRecord.Id,
+		}).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// AddTeamMember adds the provided auth record to the team with the specified role.
+//
+// If the auth record is already a team member, only its membership role is updated.
+func (app *BaseApp) AddTeamMember(team *Team, authRecord *Record, role string) (*TeamMembership, error) {
+	if !authRecord.Collection().IsAuth() {
+		return nil, errors.New("only auth records could be team members")
+	}
+
+	membership, err := app.FindTeamMembership(team, authRecord)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+
+		membership = &TeamMembership{
+			TeamRef:       team.Id,
+			CollectionRef: authRecord.Collection().Id,
+			RecordRef:     authRecord.Id,
+		}
+	}
+
+	membership.Role = role
+
+	if err := app.Save(membership); err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+// RemoveTeamMember removes the provided auth record from the team (if it is a member).
+func (app *BaseApp) RemoveTeamMember(team *Team, authRecord *Record) error {
+	membership, err := app.FindTeamMembership(team, authRecord)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil
+		}
+		return err
+	}
+
+	return app.Delete(membership)
+}
+
+// FindTeamInvitationByToken finds the active (aka. non-expired) team invitation by its plain token.
+func (app *BaseApp) FindTeamInvitationByToken(token string) (*TeamInvitation, error) {
+	if token == "" {
+		return nil, sql.ErrNoRows
+	}
+
+	result := &TeamInvitation{}
+
+	err := app.TeamInvitationQuery().
+		AndWhere(dbx.HashExp{"tokenHash": security.SHA256(token)}).
+		AndWhere(activeTeamInvitationExpr()).
+		Limit(1).
+		One(result)
+
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// CreateTeamInvitation creates a new team invitation for the specified
+// email address and role that expires after ttl.
+//
+// The inviter is optional (e.g. when the invitation is created by the system).
+//
+// Returns the created invitation and its plain token (it is not stored and cannot be retrieved later).
+// Note that the invitation email is not sent automatically (see mails.SendTeamInvitation).
+func (app *BaseApp) CreateTeamInvitation(team *Team, email string, role string, inviter *Record, ttl time.Duration) (*TeamInvitation, string, error) {
+	if ttl <= 0 {
+		return nil, "", errors.New("the invitation ttl must be positive")
+	}
+
+	token := security.RandomString(teamInvitationTokenLength)
+
+	invitation := &TeamInvitation{
+		TeamRef:   team.Id,
+		Email:     strings.ToLower(strings.TrimSpace(email)),
+		Role:      role,
+		TokenHash: security.SHA256(token),
+		Expires:   types.NowDateTime().Add(ttl),
+	}
+
+	if inviter != nil {
+		invitation.InviterCollectionRef = inviter.Collection().Id
+		invitation.InviterRef = inviter.Id
+	}
+
+	if err := app.Save(invitation); err != nil {
+		return nil, "", err
+	}
+
+	return invitation, token, nil
+}
+
+// AcceptTeamInvitation adds the provided auth record to the invitation
+// team and deletes the invitation.
+//
+// The auth record email must match with the invitation one.
+func (app *BaseApp) AcceptTeamInvitation(invitation *TeamInvitation, authRecord *Record) (*TeamMembership, error) {
+	if invitation.IsExpired() {
+		return nil, errors.New("the invitation has expired")
+	}
+
+	if authRecord == nil || !strings.EqualFold(authRecord.Email(), invitation.Email) {
+		return nil, errors.New("the invitation is for a different email address")
+	}
+
+	var membership *TeamMembership
+
+	err := app.RunInTransaction(func(txApp App) error {
+		team, err := txApp.FindTeamById(invitation.TeamRef)
+		if err != nil {
+			return err
+		}
+
+		membership, err = txApp.FindTeamMembership(team, authRecord)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+
+		// keep the existing membership role if the record is already a team member
+		if membership == nil {
+			membership, err = txApp.AddTeamMember(team, authRecord, invitation.Role)
+			if err != nil {
+				return err
+			}
+		}
+
+		return txApp.Delete(invitation)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return membership, nil
+}
+
+// DeleteExpiredTeamInvitations deletes all expired team invitations.
+func (app *BaseApp) DeleteExpiredTeamInvitations() error {
+	_, err := app.NonconcurrentDB().Delete(TeamInvitationsTableName, dbx.Not(activeTeamInvitationExpr())).Execute()
+
+	return err
+}
+
+func activeTeamInvitationExpr() dbx.Expression {
+	return dbx.NewExp("[[expires]] > {:now}", dbx.Params{"now": types.NowDateTime().String()})
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerTeamHooks() {
+	app.OnModelCreate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			var created *types.DateTime
+			var updated *types.DateTime
+
+			switch m := e.Model.(type) {
+			case *Team:
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				created, updated = &m.Created, &m.Updated
+			case *TeamMembership:
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				created, updated = &m.Created, &m.Updated
+			case *TeamInvitation:
+				if m.Id == "" {
+					m.Id = GenerateDefaultRandomId()
+				}
+				created, updated = &m.Created, &m.Updated
+			}
+
+			if created != nil {
+				*created = types.NowDateTime()
+				*updated = *created
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	app.OnModelUpdate().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			switch m := e.Model.(type) {
+			case *Team:
+				m.Updated = types.NowDateTime()
+			case *TeamMembership:
+				m.Updated = types.NowDateTime()
+			case *TeamInvitation:
+				m.Updated = types.NowDateTime()
+			}
+
+			return e.Next()
+		},
+		Priority: -99,
+	})
+
+	// delete the team memberships and invitations together with the team
+	app.OnModelDeleteExecute().Bind(&hook.Handler[*ModelEvent]{
+		Func: func(e *ModelEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			m, ok := e.Model.(*Team)
+			if !ok {
+				return nil
+			}
+
+			for _, table := range []string{TeamMembershipsTableName, TeamInvitationsTableName} {
+				_, err := e.App.NonconcurrentDB().Delete(table, dbx.HashExp{"teamRef": m.Id}).Execute()
+				if err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+		Priority: 99,
+	})
+
+	// delete the collection and auth record memberships
+	// (the invitations are not deleted since they are email based)
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil || !e.Collection.IsAuth() {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(TeamMembershipsTableName, dbx.HashExp{
+				"collectionRef": e.Collection.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Func: func(e *RecordEvent) error {
+			if err := e.Next(); err != nil || !e.Record.Collection().IsAuth() {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(TeamMembershipsTableName, dbx.HashExp{
+				"collectionRef": e.Record.Collection().Id,
+				"recordRef":     e.Record.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	app.Cron().Add("__pbTeamInvitationsCleanup__", "0 * * * *", func() {
+		if err := app.DeleteExpiredTeamInvitations(); err != nil {
+			app.Logger().Warn("Failed to delete expired team invitations", "error", err)
+		}
+	})
+}
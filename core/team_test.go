@@ -0,0 +1,447 @@
+package core_test
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/search"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestTeamTableNames(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		model    core.Model
+		expected string
+	}{
+		{&core.Team{}, "_teams"},
+		{&core.TeamMembership{}, "_teamMemberships"},
+		{&core.TeamInvitation{}, "_teamInvitations"},
+	}
+
+	for _, s := range scenarios {
+		if v := s.model.TableName(); v != s.expected {
+			t.Fatalf("Expected %q, got %q", s.expected, v)
+		}
+	}
+}
+
+func TestTeamMembershipCanManage(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		role     string
+		target   string
+		expected bool
+	}{
+		{core.TeamRoleOwner, core.TeamRoleOwner, true},
+		{core.TeamRoleOwner, core.TeamRoleMember, true},
+		{core.TeamRoleAdmin, core.TeamRoleOwner, false},
+		{core.TeamRoleAdmin, core.TeamRoleAdmin, true},
+		{core.TeamRoleAdmin, core.TeamRoleMember, true},
+		{core.TeamRoleMember, core.TeamRoleMember, false},
+		{"", core.TeamRoleMember, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.role+"_"+s.target, func(t *testing.T) {
+			m := &core.TeamMembership{Role: s.role}
+
+			if v := m.CanManage(s.target); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestTeamModelsValidate(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	team := &core.Team{Name: "test"}
+	if err := app.Save(team); err != nil {
+		t.Fatal(err)
+	}
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1, err := app.FindCollectionByNameOrId("demo1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name         string
+		model        core.Model
+		expectedErrs []string
+	}{
+		{
+			"empty team",
+			&core.Team{},
+			[]string{"id", "name"},
+		},
+		{
+			"valid team",
+			&core.Team{BaseModel: core.BaseModel{Id: "test"}, Name: "abc"},
+			[]string{},
+		},
+		{
+			"empty membership",
+			&core.TeamMembership{},
+			[]string{"id", "teamRef", "collectionRef", "recordRef", "role"},
+		},
+		{
+			"invalid membership",
+			&core.TeamMembership{
+				BaseModel:     core.BaseModel{Id: "test"},
+				TeamRef:       "missing",
+				CollectionRef: demo1.Id,
+				RecordRef:     "imy661ixudk5izi",
+				Role:          "invalid",
+			},
+			[]string{"teamRef", "collectionRef", "role"},
+		},
+		{
+			"valid membership",
+			&core.TeamMembership{
+				BaseModel:     core.BaseModel{Id: "test"},
+				TeamRef:       team.Id,
+				CollectionRef: users.Id,
+				RecordRef:     "4q1xlclmfloku33",
+				Role:          core.TeamRoleMember,
+			},
+			[]string{},
+		},
+		{
+			"empty invitation",
+			&core.TeamInvitation{},
+			[]string{"id", "teamRef", "email", "role", "TokenHash", "expires"},
+		},
+		{
+			"invalid invitation",
+			&core.TeamInvitation{
+				BaseModel:            core.BaseModel{Id: "test"},
+				TeamRef:              team.Id,
+				Email:                "invalid",
+				Role:                 core.TeamRoleAdmin,
+				InviterCollectionRef: users.Id,
+				InviterRef:           "missing",
+				TokenHash:            "test",
+				Expires:              types.NowDateTime(),
+			},
+			[]string{"email", "inviterRef"},
+		},
+		{
+			"valid invitation",
+			&core.TeamInvitation{
+				BaseModel: core.BaseModel{Id: "test"},
+				TeamRef:   team.Id,
+				Email:     "new@example.com",
+				Role:      core.TeamRoleAdmin,
+				TokenHash: "test",
+				Expires:   types.NowDateTime(),
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tests.TestValidationErrors(t, app.Validate(s.model), s.expectedErrs)
+		})
+	}
+}
+
+func TestTeamMembers(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user1, err := app.FindRecordById("users", "4q1xlclmfloku33")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user2, err := app.FindRecordById("users", "oap640cot4yru2s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo1Record, err := app.FindRecordById("demo1", "imy661ixudk5izi")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	teamB := &core.Team{Name: "b"}
+	if err := app.Save(teamB); err != nil {
+		t.Fatal(err)
+	}
+
+	teamA := &core.Team{Name: "a"}
+	if err := app.Save(teamA); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := app.AddTeamMember(teamA, demo1Record, core.TeamRoleMember); err == nil {
+		t.Fatal("Expected non-auth record error")
+	}
+
+	if _, err := app.AddTeamMember(teamA, user1, "invalid"); err == nil {
+		t.Fatal("Expected invalid role error")
+	}
+
+	if _, err := app.AddTeamMember(teamA, user1, core.TeamRoleMember); err != nil {
+		t.Fatal(err)
+	}
+
+	// update the existing membership role
+	m, err := app.AddTeamMember(teamA, user1, core.TeamRoleOwner)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Role != core.TeamRoleOwner || !m.IsFor(user1) || m.IsFor(user2) {
+		t.Fatalf("Unexpected membership %v", m)
+	}
+
+	if _, err := app.AddTeamMember(teamA, user2, core.TeamRoleMember); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.AddTeamMember(teamB, user1, core.TeamRoleMember); err != nil {
+		t.Fatal(err)
+	}
+
+	memberships, err := app.FindTeamMemberships(teamA)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(memberships) != 2 {
+		t.Fatalf("Expected 2 team memberships, got %d", len(memberships))
+	}
+
+	teams, err := app.FindAuthRecordTeams(user1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := make([]string, len(teams))
+	for i, team := range teams {
+		names[i] = team.Name
+	}
+	if expected := []string{"a", "b"}; !slices.Equal(names, expected) {
+		t.Fatalf("Expected teams %v, got %v", expected, names)
+	}
+
+	// remove (twice to ensure that it doesn't fail for non-members)
+	for i := 0; i < 2; i++ {
+		if err := app.RemoveTeamMember(teamA, user2); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := app.FindTeamMembership(teamA, user2); err == nil {
+		t.Fatal("Expected the membership to be removed")
+	}
+
+	// auth record delete
+	if err := app.Delete(user1); err != nil {
+		t.Fatal(err)
+	}
+	if total := countTeamRows(t, app, &core.TeamMembership{}); total != 0 {
+		t.Fatalf("Expected no memberships after the auth record delete, got %d", total)
+	}
+}
+
+func TestTeamInvitations(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	team := &core.Team{Name: "test"}
+	if err := app.Save(team); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err := app.CreateTeamInvitation(team, "test@example.com", core.TeamRoleAdmin, nil, 0); err == nil {
+		t.Fatal("Expected ttl error")
+	}
+
+	otherInvitation, otherToken, err := app.CreateTeamInvitation(team, "other@example.com", core.TeamRoleMember, user, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invitation, token, err := app.CreateTeamInvitation(team, " Test@example.com ", core.TeamRoleAdmin, nil, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if invitation.Email != "test@example.com" || invitation.TokenHash == token || len(token) != 40 {
+		t.Fatalf("Unexpected invitation %v (token %q)", invitation, token)
+	}
+
+	if _, err := app.FindTeamInvitationByToken("missing"); err == nil {
+		t.Fatal("Expected missing token error")
+	}
+
+	found, err := app.FindTeamInvitationByToken(token)
+	if err != nil || found.Id != invitation.Id {
+		t.Fatalf("Expected to find invitation %q, got %v (%v)", invitation.Id, found, err)
+	}
+
+	// different email
+	other, err := app.FindTeamInvitationByToken(otherToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.AcceptTeamInvitation(other, user); err == nil {
+		t.Fatal("Expected different email error")
+	}
+
+	membership, err := app.AcceptTeamInvitation(found, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if membership.Role != core.TeamRoleAdmin || !membership.IsFor(user) {
+		t.Fatalf("Unexpected membership %v", membership)
+	}
+
+	if _, err := app.FindTeamInvitationByToken(token); err == nil {
+		t.Fatal("Expected the accepted invitation to be deleted")
+	}
+
+	// expired invitations
+	otherInvitation.Expires = types.NowDateTime().Add(-time.Minute)
+	if err := app.Save(otherInvitation); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.FindTeamInvitationByToken(otherToken); err == nil {
+		t.Fatal("Expected expired invitation error")
+	}
+	if _, err := app.AcceptTeamInvitation(otherInvitation, user); err == nil {
+		t.Fatal("Expected expired invitation accept error")
+	}
+	if err := app.DeleteExpiredTeamInvitations(); err != nil {
+		t.Fatal(err)
+	}
+	if total := countTeamRows(t, app, &core.TeamInvitation{}); total != 0 {
+		t.Fatalf("Expected the expired invitations to be deleted, got %d", total)
+	}
+
+	// team delete
+	if _, _, err := app.CreateTeamInvitation(team, "new@example.com", core.TeamRoleMember, nil, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(team); err != nil {
+		t.Fatal(err)
+	}
+	if total := countTeamRows(t, app, &core.TeamMembership{}) + countTeamRows(t, app, &core.TeamInvitation{}); total != 0 {
+		t.Fatalf("Expected the team memberships and invitations to be deleted, got %d", total)
+	}
+}
+
+func TestRecordFieldResolverMemberOf(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	team := &core.Team{Name: "test"}
+	team.Id = "team_test"
+	if err := app.Save(team); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := app.AddTeamMember(team, user, core.TeamRoleAdmin); err != nil {
+		t.Fatal(err)
+	}
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		auth        *core.Record
+		filter      string
+		expectError bool
+		expected    int
+	}{
+		{"no arguments", user, `@request.auth.memberOf() = true`, true, 0},
+		{"too many arguments", user, `@request.auth.memberOf("team_test", "admin", "x") = true`, true, 0},
+		{"non-text role", user, `@request.auth.memberOf("team_test", title) = true`, true, 0},
+		{"unknown field", user, `@request.auth.memberOf(missing) = true`, true, 0},
+		{"guest", nil, `@request.auth.memberOf("team_test") = true`, false, 0},
+		{"missing team", user, `@request.auth.memberOf("missing") = true`, false, 0},
+		{"team member", user, `@request.auth.memberOf("team_test") = true`, false, 3},
+		{"team member negated", user, `@request.auth.memberOf("team_test") = false`, false, 0},
+		{"team member with role", user, `@request.auth.memberOf("team_test", "admin") = true`, false, 3},
+		{"team member with different role", user, `@request.auth.memberOf("team_test", "owner") = true`, false, 0},
+		{"field identifier", user, `@request.auth.memberOf(title) = true`, false, 0},
+		{"static request field", user, `@request.auth.memberOf(@request.query.team) = true && active = true`, false, 2},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			requestInfo := &core.RequestInfo{
+				Auth:  s.auth,
+				Query: map[string]string{"team": "team_test"},
+			}
+
+			resolver := core.NewRecordFieldResolver(app, collection, requestInfo, true)
+
+			expr, err := search.FilterData(s.filter).BuildExpr(resolver)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			ids := []string{}
+
+			query := app.RecordQuery(collection).Select("demo2.id").AndWhere(expr)
+			resolver.UpdateQuery(query)
+
+			if err := query.Column(&ids); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(ids) != s.expected {
+				t.Fatalf("Expected %d records, got %d (%v)", s.expected, len(ids), ids)
+			}
+		})
+	}
+}
+
+func countTeamRows(t testing.TB, app core.App, m core.Model) int {
+	var total int
+
+	if err := app.ModelQuery(m).Select("count(*)").Row(&total); err != nil {
+		t.Fatal(err)
+	}
+
+	return total
+}
@@ -0,0 +1,300 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const ThumbsAccessTableName = "_thumbsAccess"
+
+const (
+	storeKeyThumbsAccessCache = "@thumbsAccessCache"
+	storeKeyThumbsLifecycle   = "@thumbsLifecycle"
+)
+
+type thumbsAccessCache struct {
+	items map[string]string // thumb path -> last tracked UTC day
+	mu    sync.Mutex
+}
+
+func (app *BaseApp) thumbsAccessCache() *thumbsAccessCache {
+	return app.Store().GetOrSet(storeKeyThumbsAccessCache, func() any {
+		return &thumbsAccessCache{items: map[string]string{}}
+	}).(*thumbsAccessCache)
+}
+
+// TrackThumbAccess updates the last access date of the generated thumb
+// located at thumbPath (the storage file key).
+//
+// To minimize the db writes the access is persisted at most once per day for each thumb.
+//
+// It does nothing if the idle thumbs expiration is disabled (aka. Thumbs.MaxIdleDays is 0)
+// or it is delegated to an S3 bucket lifecycle rule (see [ThumbsConfig.S3Lifecycle]).
+func (app *BaseApp) TrackThumbAccess(thumbPath string) error {
+	if app.Settings().Thumbs.MaxIdleDays <= 0 || thumbPath == "" || app.isThumbsLifecycleDelegated() {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	day := now.Format(time.DateOnly)
+
+	cache := app.thumbsAccessCache()
+
+	cache.mu.Lock()
+	if cache.items[thumbPath] == day {
+		cache.mu.Unlock()
+		return nil
+	}
+	cache.items[thumbPath] = day
+	cache.mu.Unlock()
+
+	_, err := app.NonconcurrentDB().NewQuery(`
+		INSERT INTO {{` + ThumbsAccessTableName + `}} ([[path]], [[lastAccess]])
+		VALUES ({:path}, {:lastAccess})
+		ON CONFLICT ([[path]]) DO UPDATE SET [[lastAccess]] = excluded.[[lastAccess]]
+	`).Bind(dbx.Params{
+		"path":       thumbPath,
+		"lastAccess": now.Format(types.DefaultDateLayout),
+	}).Execute()
+
+	if err != nil {
+		// allow retrying with the next access
+		cache.mu.Lock()
+		delete(cache.items, thumbPath)
+		cache.mu.Unlock()
+	}
+
+	return err
+}
+
+// DeleteExpiredThumbs deletes all generated thumbs of the collections
+// files that were last accessed (or created if never accessed) before idleBefore.
+//
+// The deleted thumbs are regenerated on demand with the next file request.
+//
+// Returns the number of the deleted thumbs.
+func (app *BaseApp) DeleteExpiredThumbs(idleBefore time.Time) (int, error) {
+	collections := []*Collection{}
+	err := app.CollectionQuery().
+		AndWhere(dbx.NewExp("[[type]] != {:view}", dbx.Params{"view": CollectionTypeView})).
+		All(&collections)
+	if err != nil {
+		return 0, err
+	}
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		return 0, err
+	}
+	defer fsys.Close()
+
+	cutoff := idleBefore.UTC()
+	formattedCutoff := cutoff.Format(types.DefaultDateLayout)
+
+	// the recently accessed thumbs
+	recentPaths := []string{}
+	err = app.ConcurrentDB().Select("path").
+		From(ThumbsAccessTableName).
+		AndWhere(dbx.NewExp("[[lastAccess]] >= {:date}", dbx.Params{"date": formattedCutoff})).
+		Column(&recentPaths)
+	if err != nil {
+		return 0, err
+	}
+	recent := make(map[string]struct{}, len(recentPaths))
+	for _, p := range recentPaths {
+		recent[p] = struct{}{}
+	}
+
+	var deleted int
+	var errs []error
+
+	for _, c := range collections {
+		objects, err := fsys.List(c.Id + "/")
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		// group the thumbs by their "thumbs_" dir so that fully expired
+		// dirs could be removed at once (incl. the local empty dirs)
+		dirs := map[string][]string{} // dir -> expired thumbs
+		dirsTotal := map[string]int{}
+		for _, obj := range objects {
+			dir := path.Dir(obj.Key)
+			if !strings.HasPrefix(path.Base(dir), "thumbs_") {
+				continue
+			}
+
+			dirsTotal[dir]++
+
+			if _, ok := recent[obj.Key]; ok || !obj.ModTime.Before(cutoff) {
+				continue
+			}
+
+			dirs[dir] = append(dirs[dir], obj.Key)
+		}
+
+		for dir, expired := range dirs {
+			if len(expired) == dirsTotal[dir] {
+				if delErrs := fsys.DeletePrefix(dir + "/"); len(delErrs) > 0 {
+					errs = append(errs, delErrs...)
+					continue
+				}
+				deleted += len(expired)
+				continue
+			}
+
+			for _, key := range expired {
+				if err := fsys.Delete(key); err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				deleted++
+			}
+		}
+	}
+
+	// cleanup the stale access entries
+	_, err = app.NonconcurrentDB().Delete(ThumbsAccessTableName, dbx.NewExp(
+		"[[lastAccess]] < {:date}",
+		dbx.Params{"date": formattedCutoff},
+	)).Execute()
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	return deleted, errors.Join(errs...)
+}
+
+// isThumbsLifecycleDelegated reports whether the thumbs expiration
+// is handled by an S3 bucket lifecycle rule.
+func (app *BaseApp) isThumbsLifecycleDelegated() bool {
+	settings := app.Settings()
+
+	return settings.S3.Enabled && settings.Thumbs.S3Lifecycle && settings.Thumbs.MaxIdleDays > 0
+}
+
+// thumbsLifecycle holds the last applied thumbs bucket lifecycle rule state.
+type thumbsLifecycle struct {
+	s3   S3Config
+	days int
+	mu   sync.Mutex
+}
+
+// syncThumbsLifecycle creates, updates or removes the thumbs S3 bucket
+// lifecycle rule based on the current app settings.
+//
+// The rule of the previously configured bucket is removed when the
+// lifecycle delegation is disabled or the storage bucket is changed.
+func (app *BaseApp) syncThumbsLifecycle() error {
+	state := app.Store().GetOrSet(storeKeyThumbsLifecycle, func() any {
+		return &thumbsLifecycle{}
+	}).(*thumbsLifecycle)
+
+	state.mu.Lock()
+	defer state.mu.Unlock()
+
+	var s3Config S3Config
+	var days int
+	if app.isThumbsLifecycleDelegated() {
+		s3Config = app.Settings().S3
+		days = app.Settings().Thumbs.MaxIdleDays
+	}
+
+	if state.s3 == s3Config && state.days == days {
+		return nil // no changes
+	}
+
+	// remove the rule from the previous bucket
+	if state.days > 0 && state.s3 != s3Config {
+		if err := setThumbsExpiration(state.s3, 0); err != nil {
+			return err
+		}
+		state.s3 = S3Config{}
+		state.days = 0
+	}
+
+	if days > 0 {
+		if err := setThumbsExpiration(s3Config, days); err != nil {
+			return err
+		}
+	}
+
+	state.s3 = s3Config
+	state.days = days
+
+	return nil
+}
+
+func setThumbsExpiration(config S3Config, days int) error {
+	fsys, err := filesystem.NewS3(
+		config.Bucket,
+		config.Region,
+		config.Endpoint,
+		config.AccessKey,
+		config.Secret,
+		config.ForcePathStyle,
+	)
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	fsys.SetContext(ctx)
+
+	return fsys.SetThumbsExpiration(days)
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerThumbsHooks() {
+	// cleanup the idle generated thumbs
+	app.Cron().Add("__pbThumbsCleanup__", "0 */6 * * *", func() {
+		maxIdleDays := app.Settings().Thumbs.MaxIdleDays
+		if maxIdleDays <= 0 || app.isThumbsLifecycleDelegated() {
+			return // keep forever or expired by the bucket lifecycle rule
+		}
+
+		total, err := app.DeleteExpiredThumbs(time.Now().AddDate(0, 0, -1*maxIdleDays))
+		if err != nil {
+			app.Logger().Warn("Failed to delete expired thumbs", "error", err, "deleted", total)
+		}
+	})
+
+	// apply the thumbs bucket lifecycle rule
+	syncLifecycle := func() {
+		if err := app.syncThumbsLifecycle(); err != nil {
+			app.Logger().Warn("Failed to sync the thumbs bucket lifecycle rule", "error", err)
+		}
+	}
+
+	app.OnBootstrap().BindFunc(func(e *BootstrapEvent) error {
+		if err := e.Next(); err != nil {
+			return err
+		}
+
+		syncLifecycle()
+
+		return nil
+	})
+
+	app.OnSettingsReload().BindFunc(func(e *SettingsReloadEvent) error {
+		if err := e.Next(); err != nil {
+			return err
+		}
+
+		syncLifecycle()
+
+		return nil
+	})
+}
@@ -0,0 +1,141 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestTrackThumbAccess(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	thumbPath := "a/b/thumbs_test.png/100x100_test.png"
+
+	countAccess := func(t *testing.T) int {
+		var total int
+		err := app.DB().Select("count(*)").
+			From(core.ThumbsAccessTableName).
+			AndWhere(dbx.HashExp{"path": thumbPath}).
+			Row(&total)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return total
+	}
+
+	// disabled
+	if err := app.TrackThumbAccess(thumbPath); err != nil {
+		t.Fatal(err)
+	}
+	if total := countAccess(t); total != 0 {
+		t.Fatalf("Expected no tracked access, got %d", total)
+	}
+
+	// delegated to the S3 bucket lifecycle rule
+	app.Settings().Thumbs.MaxIdleDays = 1
+	app.Settings().Thumbs.S3Lifecycle = true
+	app.Settings().S3.Enabled = true
+	if err := app.TrackThumbAccess(thumbPath); err != nil {
+		t.Fatal(err)
+	}
+	if total := countAccess(t); total != 0 {
+		t.Fatalf("Expected no tracked access with delegated lifecycle, got %d", total)
+	}
+
+	// enabled
+	app.Settings().S3.Enabled = false
+	for i := 0; i < 2; i++ {
+		if err := app.TrackThumbAccess(thumbPath); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if total := countAccess(t); total != 1 {
+		t.Fatalf("Expected 1 tracked access, got %d", total)
+	}
+}
+
+func TestDeleteExpiredThumbs(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	fsys, err := app.NewFilesystem()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	thumbsDir := "_pb_users_auth_/4q1xlclmfloku33/thumbs_300_1SEi6Q6U72.png"
+	keptThumb := thumbsDir + "/70x50_300_1SEi6Q6U72.png"
+	removedDir := "wsmn24bux7wo113/al1h9ijdeojtsjy/thumbs_300_Jsjq7RdBgA.png"
+	original := "_pb_users_auth_/4q1xlclmfloku33/300_1SEi6Q6U72.png"
+
+	objects, err := fsys.List("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var totalThumbs int
+	for _, obj := range objects {
+		if strings.Contains(obj.Key, "/thumbs_") {
+			totalThumbs++
+		}
+	}
+
+	cutoff := time.Now().Add(1 * time.Hour)
+
+	// recently accessed and stale access entries
+	_, err = app.DB().Insert(core.ThumbsAccessTableName, dbx.Params{
+		"path":       keptThumb,
+		"lastAccess": cutoff.Add(1 * time.Hour).UTC().Format(types.DefaultDateLayout),
+	}).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = app.DB().Insert(core.ThumbsAccessTableName, dbx.Params{
+		"path":       "missing/thumbs_test.png/100x100_test.png",
+		"lastAccess": cutoff.Add(-48 * time.Hour).UTC().Format(types.DefaultDateLayout),
+	}).Execute()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	deleted, err := app.DeleteExpiredThumbs(cutoff)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if deleted != totalThumbs-1 {
+		t.Fatalf("Expected %d deleted thumbs, got %d", totalThumbs-1, deleted)
+	}
+
+	if exists, _ := fsys.Exists(keptThumb); !exists {
+		t.Fatalf("Expected the recently accessed thumb %q to be kept", keptThumb)
+	}
+
+	if exists, _ := fsys.Exists(original); !exists {
+		t.Fatalf("Expected the original file %q to be kept", original)
+	}
+
+	if _, err := os.Stat(filepath.Join(app.DataDir(), "storage", removedDir)); !os.IsNotExist(err) {
+		t.Fatalf("Expected the fully expired thumbs dir %q to be removed, got %v", removedDir, err)
+	}
+
+	var totalAccess int
+	if err := app.DB().Select("count(*)").From(core.ThumbsAccessTableName).Row(&totalAccess); err != nil {
+		t.Fatal(err)
+	}
+	if totalAccess != 1 {
+		t.Fatalf("Expected only 1 remaining access entry, got %d", totalAccess)
+	}
+}
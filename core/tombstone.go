@@ -0,0 +1,172 @@
+package core
+
+import (
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const TombstonesTableName = "_tombstones"
+
+// Tombstone defines a single deleted record entry from the deletions log.
+//
+// There is at most one tombstone per collection record and it is
+// removed automatically if a record with the same id is created again.
+type Tombstone struct {
+	CollectionId   string         `db:"collectionId" json:"collectionId"`
+	CollectionName string         `db:"collectionName" json:"collectionName"`
+	RecordId       string         `db:"recordId" json:"recordId"`
+	DeletedAt      types.DateTime `db:"deletedAt" json:"deletedAt"`
+}
+
+// TombstoneQuery returns a new deletions log select query.
+func (app *BaseApp) TombstoneQuery() *dbx.SelectQuery {
+	return app.ConcurrentDB().Select("{{" + TombstonesTableName + "}}.*").From(TombstonesTableName)
+}
+
+// FindTombstones returns up to limit collection tombstones deleted
+// after deletedAfter ordered by their delete date (oldest first).
+//
+// deletedAfter is optional and when zero all tombstones of the collection are returned.
+func (app *BaseApp) FindTombstones(collectionIdOrName string, deletedAfter time.Time, limit int) ([]*Tombstone, error) {
+	query := app.TombstoneQuery().
+		AndWhere(dbx.Or(
+			dbx.HashExp{"collectionId": collectionIdOrName},
+			dbx.HashExp{"collectionName": collectionIdOrName},
+		)).
+		OrderBy("deletedAt ASC", "recordId ASC")
+
+	if !deletedAfter.IsZero() {
+		query.AndWhere(dbx.NewExp("[[deletedAt]] > {:date}", dbx.Params{
+			"date": deletedAfter.UTC().Format(types.DefaultDateLayout),
+		}))
+	}
+
+	if limit > 0 {
+		query.Limit(int64(limit))
+	}
+
+	result := []*Tombstone{}
+
+	err := query.All(&result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// DeleteOldTombstones deletes all tombstones that are created before deletedBefore.
+//
+// For better performance the tombstones delete is executed as plain SQL statement,
+// aka. no delete model hook events will be fired.
+func (app *BaseApp) DeleteOldTombstones(deletedBefore time.Time) error {
+	formattedDate := deletedBefore.UTC().Format(types.DefaultDateLayout)
+	expr := dbx.NewExp("[[deletedAt]] <= {:date}", dbx.Params{"date": formattedDate})
+
+	_, err := app.NonconcurrentDB().Delete(TombstonesTableName, expr).Execute()
+
+	return err
+}
+
+// registerTombstoneHooks registers the record hooks that maintain the
+// deletions log (when enabled in the settings) and its cleanup job.
+func (app *BaseApp) registerTombstoneHooks() {
+	capture := func(action func(txApp App, record *Record) error) func(e *RecordEvent) error {
+		return func(e *RecordEvent) error {
+			collection := e.Record.Collection()
+			if !e.App.Settings().Tombstones.Enabled || collection.System || collection.IsView() {
+				return e.Next()
+			}
+
+			if e.App.IsTransactional() {
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				return action(e.App, e.Record)
+			}
+
+			originalApp := e.App
+			defer func() {
+				e.App = originalApp
+			}()
+
+			return originalApp.RunInTransaction(func(txApp App) error {
+				e.App = txApp
+
+				if err := e.Next(); err != nil {
+					return err
+				}
+
+				return action(txApp, e.Record)
+			})
+		}
+	}
+
+	app.OnRecordCreateExecute().Bind(&hook.Handler[*RecordEvent]{
+		Id:       "__pbTombstonesCreate__",
+		Func:     capture(deleteTombstone),
+		Priority: 99,
+	})
+
+	app.OnRecordDeleteExecute().Bind(&hook.Handler[*RecordEvent]{
+		Id:       "__pbTombstonesDelete__",
+		Func:     capture(upsertTombstone),
+		Priority: 99,
+	})
+
+	app.OnCollectionDeleteExecute().Bind(&hook.Handler[*CollectionEvent]{
+		Func: func(e *CollectionEvent) error {
+			if err := e.Next(); err != nil {
+				return err
+			}
+
+			_, err := e.App.NonconcurrentDB().Delete(TombstonesTableName, dbx.HashExp{
+				"collectionId": e.Collection.Id,
+			}).Execute()
+
+			return err
+		},
+		Priority: 99,
+	})
+
+	// cleanup old tombstones
+	app.Cron().Add("__pbTombstonesCleanup__", "0 */6 * * *", func() {
+		maxDays := app.Settings().Tombstones.MaxDays
+		if maxDays <= 0 {
+			return // no limit
+		}
+
+		deleteErr := app.DeleteOldTombstones(time.Now().AddDate(0, 0, -1*maxDays))
+		if deleteErr != nil {
+			app.Logger().Warn("Failed to delete old tombstones", "error", deleteErr)
+		}
+	})
+}
+
+func upsertTombstone(txApp App, record *Record) error {
+	_, err := txApp.NonconcurrentDB().NewQuery(
+		"INSERT INTO {{" + TombstonesTableName + "}} ([[collectionId]], [[collectionName]], [[recordId]], [[deletedAt]]) " +
+			"VALUES ({:collectionId}, {:collectionName}, {:recordId}, {:deletedAt}) " +
+			"ON CONFLICT DO UPDATE SET [[collectionName]] = excluded.[[collectionName]], [[deletedAt]] = excluded.[[deletedAt]]",
+	).Bind(dbx.Params{
+		"collectionId":   record.Collection().Id,
+		"collectionName": record.Collection().Name,
+		"recordId":       record.Id,
+		"deletedAt":      types.NowDateTime().String(),
+	}).Execute()
+
+	return err
+}
+
+func deleteTombstone(txApp App, record *Record) error {
+	_, err := txApp.NonconcurrentDB().Delete(TombstonesTableName, dbx.HashExp{
+		"collectionId": record.Collection().Id,
+		"recordId":     record.Id,
+	}).Execute()
+
+	return err
+}
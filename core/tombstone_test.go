@@ -0,0 +1,220 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestTombstonesCapture(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("tombstones_test")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	records := make([]*core.Record, 4)
+	for i := range records {
+		records[i] = core.NewRecord(collection)
+		if err := app.Save(records[i]); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// disabled
+	// ---
+	if err := app.Delete(records[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	tombstones, err := app.FindTombstones(collection.Id, time.Time{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tombstones) != 0 {
+		t.Fatalf("Expected no tombstones while disabled, got %d", len(tombstones))
+	}
+
+	// enabled
+	// ---
+	app.Settings().Tombstones.Enabled = true
+
+	if err := app.Delete(records[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(records[2]); err != nil {
+		t.Fatal(err)
+	}
+
+	// recreate a deleted record with the same id
+	recreated := core.NewRecord(collection)
+	recreated.Id = records[2].Id
+	if err := app.Save(recreated); err != nil {
+		t.Fatal(err)
+	}
+
+	// rollbacked transaction
+	txErr := app.RunInTransaction(func(txApp core.App) error {
+		if err := txApp.Delete(records[3]); err != nil {
+			return err
+		}
+
+		return errors.New("rollback")
+	})
+	if txErr == nil {
+		t.Fatal("Expected transaction error")
+	}
+
+	// system collection
+	superusers, err := app.FindCollectionByNameOrId(core.CollectionNameSuperusers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	superuser := core.NewRecord(superusers)
+	superuser.SetEmail("tombstones@example.com")
+	superuser.SetPassword("1234567890")
+	if err := app.Save(superuser); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.Delete(superuser); err != nil {
+		t.Fatal(err)
+	}
+	systemTombstones, err := app.FindTombstones(core.CollectionNameSuperusers, time.Time{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(systemTombstones) != 0 {
+		t.Fatalf("Expected no system collection tombstones, got %d", len(systemTombstones))
+	}
+
+	tombstones, err = app.FindTombstones(collection.Name, time.Time{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tombstones) != 1 {
+		t.Fatalf("Expected 1 tombstone, got %d", len(tombstones))
+	}
+
+	if tombstones[0].RecordId != records[1].Id {
+		t.Fatalf("Expected tombstone for record %q, got %q", records[1].Id, tombstones[0].RecordId)
+	}
+
+	if tombstones[0].CollectionId != collection.Id || tombstones[0].CollectionName != collection.Name {
+		t.Fatalf("Expected collection %q (%q), got %q (%q)", collection.Id, collection.Name, tombstones[0].CollectionId, tombstones[0].CollectionName)
+	}
+
+	if tombstones[0].DeletedAt.IsZero() {
+		t.Fatal("Expected non-zero deletedAt")
+	}
+
+	// delete the collection
+	// ---
+	if err := app.Delete(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	tombstones, err = app.FindTombstones(collection.Id, time.Time{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tombstones) != 0 {
+		t.Fatalf("Expected the collection tombstones to be deleted, got %d", len(tombstones))
+	}
+}
+
+func TestFindTombstones(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	seedTombstones(t, app, [][3]string{
+		{"c1", "r1", "2024-01-01 00:00:00.000Z"},
+		{"c2", "r2", "2024-01-02 00:00:00.000Z"},
+		{"c1", "r3", "2024-01-03 00:00:00.000Z"},
+		{"c1", "r4", "2024-01-04 00:00:00.000Z"},
+	})
+
+	scenarios := []struct {
+		name         string
+		collection   string
+		deletedAfter time.Time
+		limit        int
+		expected     []string
+	}{
+		{"by collection id", "c1", time.Time{}, 0, []string{"r1", "r3", "r4"}},
+		{"by collection name", "c2_name", time.Time{}, 0, []string{"r2"}},
+		{"deleted after", "c1", time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC), 0, []string{"r4"}},
+		{"with limit", "c1", time.Time{}, 2, []string{"r1", "r3"}},
+		{"missing collection", "missing", time.Time{}, 0, []string{}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			tombstones, err := app.FindTombstones(s.collection, s.deletedAfter, s.limit)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(tombstones) != len(s.expected) {
+				t.Fatalf("Expected %d tombstones, got %d", len(s.expected), len(tombstones))
+			}
+
+			for i, tombstone := range tombstones {
+				if tombstone.RecordId != s.expected[i] {
+					t.Fatalf("[%d] Expected recordId %q, got %q", i, s.expected[i], tombstone.RecordId)
+				}
+			}
+		})
+	}
+}
+
+func TestDeleteOldTombstones(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	seedTombstones(t, app, [][3]string{
+		{"c1", "r1", "2024-01-01 00:00:00.000Z"},
+		{"c1", "r2", "2024-01-02 00:00:00.000Z"},
+		{"c1", "r3", "2024-01-03 00:00:00.000Z"},
+	})
+
+	if err := app.DeleteOldTombstones(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)); err != nil {
+		t.Fatal(err)
+	}
+
+	tombstones, err := app.FindTombstones("c1", time.Time{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(tombstones) != 1 || tombstones[0].RecordId != "r3" {
+		t.Fatalf("Expected only the r3 tombstone to remain, got %v", tombstones)
+	}
+}
+
+func seedTombstones(t *testing.T, app core.App, items [][3]string) {
+	for _, item := range items {
+		_, err := app.DB().Insert(core.TombstonesTableName, dbx.Params{
+			"collectionId":   item[0],
+			"collectionName": item[0] + "_name",
+			"recordId":       item[1],
+			"deletedAt":      item[2],
+		}).Execute()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+}
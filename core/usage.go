@@ -0,0 +1,273 @@
+package core
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/hook"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const UsageTableName = "_usage"
+
+// The default metered usage units.
+const (
+	UsageUnitRequests = "requests" // number of the handled API requests
+	UsageUnitStorage  = "storage"  // uploaded files size in bytes
+	UsageUnitEgress   = "egress"   // response body size in bytes
+	UsageUnitEmails   = "emails"   // number of the sent record emails
+)
+
+const storeKeyUsageBuffer = "@usageBuffer"
+
+var _ Model = (*UsageRecord)(nil)
+
+// UsageRecord defines a single daily usage rollup of a tenant billable unit.
+type UsageRecord struct {
+	BaseModel
+
+	// Tenant is the usage owner identifier
+	// (by default in the format "collectionId:recordId" for auth records).
+	Tenant string `db:"tenant" json:"tenant"`
+
+	// Unit is the metered unit (e.g. [UsageUnitRequests]).
+	Unit string `db:"unit" json:"unit"`
+
+	// Period is the rollup UTC day start date.
+	Period types.DateTime `db:"period" json:"period"`
+
+	// Value is the total consumed unit amount for the period.
+	Value int64 `db:"value" json:"value"`
+
+	Created types.DateTime `db:"created" json:"created"`
+	Updated types.DateTime `db:"updated" json:"updated"`
+}
+
+// TableName returns the UsageRecord model table name.
+func (m *UsageRecord) TableName() string {
+	return UsageTableName
+}
+
+// UsageTenant returns the default usage tenant identifier of the provided auth record.
+//
+// Returns an empty string if record is nil.
+func UsageTenant(record *Record) string {
+	if record == nil {
+		return ""
+	}
+
+	return record.Collection().Id + ":" + record.Id
+}
+
+// UsagePeriod returns the usage rollup period (aka. UTC day start) of the provided time.
+func UsagePeriod(t time.Time) types.DateTime {
+	y, m, d := t.UTC().Date()
+
+	period, _ := types.ParseDateTime(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+
+	return period
+}
+
+// -------------------------------------------------------------------
+
+type usageKey struct {
+	tenant string
+	unit   string
+}
+
+type usageBuffer struct {
+	items map[usageKey]int64
+	mu    sync.Mutex
+}
+
+func (app *BaseApp) usageBuffer() *usageBuffer {
+	return app.Store().GetOrSet(storeKeyUsageBuffer, func() any {
+		return &usageBuffer{items: map[usageKey]int64{}}
+	}).(*usageBuffer)
+}
+
+// UsageQuery returns a new UsageRecord select query.
+func (app *BaseApp) UsageQuery() *dbx.SelectQuery {
+	return app.ModelQuery(&UsageRecord{})
+}
+
+// TrackUsage adds value to the in-memory usage counter of the tenant unit
+// that will be persisted with the next [App.FlushUsage] rollup.
+//
+// Each track triggers the [App.OnUsageTrack] hook allowing to adjust the
+// tenant identifier (e.g. to an organization id) or to skip the tracking.
+//
+// It does nothing if the metering is disabled, the tenant is empty or value is 0.
+func (app *BaseApp) TrackUsage(tenant string, unit string, value int64) error {
+	if !app.Settings().Metering.Enabled {
+		return nil
+	}
+
+	event := new(UsageTrackEvent)
+	event.App = app
+	event.Tenant = tenant
+	event.Unit = unit
+	event.Value = value
+
+	return app.OnUsageTrack().Trigger(event, func(e *UsageTrackEvent) error {
+		if e.Tenant == "" || e.Unit == "" || e.Value == 0 {
+			return nil
+		}
+
+		buf := app.usageBuffer()
+
+		buf.mu.Lock()
+		buf.items[usageKey{e.Tenant, e.Unit}] += e.Value
+		buf.mu.Unlock()
+
+		return nil
+	})
+}
+
+// FlushUsage persists the in-memory tracked usage counters
+// as increments of their current day rollups.
+//
+// The flushed rollup increments could be intercepted via the [App.OnUsageRollup] hook
+// (e.g. to forward them to an external billing provider).
+func (app *BaseApp) FlushUsage() error {
+	buf := app.usageBuffer()
+
+	buf.mu.Lock()
+	items := buf.items
+	buf.items = map[usageKey]int64{}
+	buf.mu.Unlock()
+
+	if len(items) == 0 {
+		return nil
+	}
+
+	period := UsagePeriod(time.Now())
+
+	event := new(UsageRollupEvent)
+	event.App = app
+	event.Items = make([]*UsageRecord, 0, len(items))
+	for k, v := range items {
+		event.Items = append(event.Items, &UsageRecord{
+			Tenant: k.tenant,
+			Unit:   k.unit,
+			Period: period,
+			Value:  v,
+		})
+	}
+
+	err := app.OnUsageRollup().Trigger(event, func(e *UsageRollupEvent) error {
+		return e.App.RunInTransaction(func(txApp App) error {
+			for _, item := range e.Items {
+				if err := upsertUsage(txApp, item); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		})
+	})
+
+	// restore the counters so that they could be retried with the next flush
+	if err != nil {
+		buf.mu.Lock()
+		for k, v := range items {
+			buf.items[k] += v
+		}
+		buf.mu.Unlock()
+	}
+
+	return err
+}
+
+func upsertUsage(txApp App, item *UsageRecord) error {
+	if item.Id == "" {
+		item.Id = GenerateDefaultRandomId()
+	}
+
+	now := types.NowDateTime().String()
+
+	_, err := txApp.NonconcurrentDB().NewQuery(`
+		INSERT INTO {{` + UsageTableName + `}} ([[id]], [[tenant]], [[unit]], [[period]], [[value]], [[created]], [[updated]])
+		VALUES ({:id}, {:tenant}, {:unit}, {:period}, {:value}, {:now}, {:now})
+		ON CONFLICT ([[tenant]], [[unit]], [[period]]) DO UPDATE SET
+			[[value]] = [[value]] + excluded.[[value]],
+			[[updated]] = excluded.[[updated]]
+	`).Bind(dbx.Params{
+		"id":     item.Id,
+		"tenant": item.Tenant,
+		"unit":   item.Unit,
+		"period": item.Period.String(),
+		"value":  item.Value,
+		"now":    now,
+	}).Execute()
+
+	return err
+}
+
+// DeleteOldUsage deletes all usage rollups with period before the provided date.
+func (app *BaseApp) DeleteOldUsage(periodBefore time.Time) error {
+	formattedDate := periodBefore.UTC().Format(types.DefaultDateLayout)
+
+	_, err := app.NonconcurrentDB().Delete(UsageTableName, dbx.NewExp(
+		"[[period]] < {:date}",
+		dbx.Params{"date": formattedDate},
+	)).Execute()
+
+	return err
+}
+
+// -------------------------------------------------------------------
+
+func (app *BaseApp) registerUsageHooks() {
+	trackEmail := func(e *MailerRecordEvent) error {
+		if err := e.Next(); err != nil {
+			return err
+		}
+
+		if err := e.App.TrackUsage(UsageTenant(e.Record), UsageUnitEmails, 1); err != nil {
+			e.App.Logger().Warn("Failed to track the email usage", "error", err)
+		}
+
+		return nil
+	}
+
+	app.OnMailerRecordPasswordResetSend().BindFunc(trackEmail)
+	app.OnMailerRecordVerificationSend().BindFunc(trackEmail)
+	app.OnMailerRecordEmailChangeSend().BindFunc(trackEmail)
+	app.OnMailerRecordOTPSend().BindFunc(trackEmail)
+	app.OnMailerRecordAuthAlertSend().BindFunc(trackEmail)
+	app.OnMailerRecordBulkSend().BindFunc(trackEmail)
+
+	app.Cron().Add("__pbUsageRollup__", "* * * * *", func() {
+		if err := app.FlushUsage(); err != nil {
+			app.Logger().Warn("Failed to flush the usage counters", "error", err)
+		}
+	})
+
+	// cleanup old usage rollups
+	app.Cron().Add("__pbUsageCleanup__", "0 */6 * * *", func() {
+		maxDays := app.Settings().Metering.MaxDays
+		if maxDays <= 0 {
+			return // no limit
+		}
+
+		deleteErr := app.DeleteOldUsage(time.Now().AddDate(0, 0, -1*maxDays))
+		if deleteErr != nil {
+			app.Logger().Warn("Failed to delete old usage rollups", "error", deleteErr)
+		}
+	})
+
+	// persist the remaining counters before exit
+	app.OnTerminate().Bind(&hook.Handler[*TerminateEvent]{
+		Id: "__pbUsageOnTerminate__",
+		Func: func(e *TerminateEvent) error {
+			if err := e.App.FlushUsage(); err != nil {
+				e.App.Logger().Warn("Failed to flush the usage counters", "error", err)
+			}
+
+			return e.Next()
+		},
+		Priority: -998, // before the logger flush
+	})
+}
@@ -0,0 +1,262 @@
+package core_test
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestUsageTenant(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if v := core.UsageTenant(nil); v != "" {
+		t.Fatalf("Expected empty tenant for nil record, got %q", v)
+	}
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := user.Collection().Id + ":" + user.Id
+	if v := core.UsageTenant(user); v != expected {
+		t.Fatalf("Expected tenant %q, got %q", expected, v)
+	}
+}
+
+func TestUsagePeriod(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("test", 3*60*60)
+
+	period := core.UsagePeriod(time.Date(2024, 1, 2, 1, 30, 0, 0, loc))
+
+	expected := "2024-01-01 00:00:00.000Z"
+	if v := period.String(); v != expected {
+		t.Fatalf("Expected period %q, got %q", expected, v)
+	}
+}
+
+func TestTrackAndFlushUsage(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	findUsage := func(t *testing.T, tenant, unit string) int64 {
+		item := &core.UsageRecord{}
+		err := app.UsageQuery().
+			AndWhere(dbx.HashExp{"tenant": tenant, "unit": unit}).
+			Limit(1).
+			One(item)
+		if err != nil {
+			return -1
+		}
+		return item.Value
+	}
+
+	// disabled metering
+	// ---
+	if err := app.TrackUsage("t1", core.UsageUnitRequests, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.FlushUsage(); err != nil {
+		t.Fatal(err)
+	}
+	if total := app.EventCalls["OnUsageTrack"] + app.EventCalls["OnUsageRollup"]; total != 0 {
+		t.Fatalf("Expected no usage hooks to be called, got %d", total)
+	}
+	if v := findUsage(t, "t1", core.UsageUnitRequests); v != -1 {
+		t.Fatalf("Expected no usage to be persisted, got %d", v)
+	}
+
+	// enabled metering
+	// ---
+	app.Settings().Metering.Enabled = true
+
+	// remap tenant
+	app.OnUsageTrack().BindFunc(func(e *core.UsageTrackEvent) error {
+		if e.Tenant == "remap" {
+			e.Tenant = "t2"
+		}
+		return e.Next()
+	})
+
+	trackCalls := [][]any{
+		{"t1", core.UsageUnitRequests, int64(1)},
+		{"t1", core.UsageUnitRequests, int64(2)},
+		{"t1", core.UsageUnitEgress, int64(100)},
+		{"remap", core.UsageUnitEgress, int64(10)},
+		{"", core.UsageUnitEgress, int64(10)},  // skipped
+		{"t2", core.UsageUnitEmails, int64(0)}, // skipped
+	}
+	for _, c := range trackCalls {
+		if err := app.TrackUsage(c[0].(string), c[1].(string), c[2].(int64)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var rollupItems []*core.UsageRecord
+	app.OnUsageRollup().BindFunc(func(e *core.UsageRollupEvent) error {
+		rollupItems = e.Items
+		return e.Next()
+	})
+
+	if err := app.FlushUsage(); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rollupItems) != 3 {
+		t.Fatalf("Expected 3 rollup items, got %d", len(rollupItems))
+	}
+
+	expectedPeriod := core.UsagePeriod(time.Now()).String()
+	for _, item := range rollupItems {
+		if item.Period.String() != expectedPeriod {
+			t.Fatalf("Expected period %q, got %q", expectedPeriod, item.Period.String())
+		}
+	}
+
+	expected := []struct {
+		tenant string
+		unit   string
+		value  int64
+	}{
+		{"t1", core.UsageUnitRequests, 3},
+		{"t1", core.UsageUnitEgress, 100},
+		{"t2", core.UsageUnitEgress, 10},
+		{"t2", core.UsageUnitEmails, -1},
+		{"remap", core.UsageUnitEgress, -1},
+	}
+	for _, e := range expected {
+		if v := findUsage(t, e.tenant, e.unit); v != e.value {
+			t.Fatalf("Expected %s %s usage %d, got %d", e.tenant, e.unit, e.value, v)
+		}
+	}
+
+	// flush with failed rollup should keep the counters for the next flush
+	// ---
+	if err := app.TrackUsage("t1", core.UsageUnitRequests, 5); err != nil {
+		t.Fatal(err)
+	}
+
+	failRollup := true
+	app.OnUsageRollup().BindFunc(func(e *core.UsageRollupEvent) error {
+		if failRollup {
+			return errors.New("test")
+		}
+		return e.Next()
+	})
+
+	if err := app.FlushUsage(); err == nil {
+		t.Fatal("Expected rollup error")
+	}
+	if v := findUsage(t, "t1", core.UsageUnitRequests); v != 3 {
+		t.Fatalf("Expected unchanged t1 requests usage 3, got %d", v)
+	}
+
+	failRollup = false
+	if err := app.TrackUsage("t1", core.UsageUnitRequests, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err := app.FlushUsage(); err != nil {
+		t.Fatal(err)
+	}
+	if v := findUsage(t, "t1", core.UsageUnitRequests); v != 9 {
+		t.Fatalf("Expected t1 requests usage 9, got %d", v)
+	}
+}
+
+func TestUsageEmailTracking(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Metering.Enabled = true
+
+	user, err := app.FindAuthRecordByEmail("users", "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	event := new(core.MailerRecordEvent)
+	event.App = app
+	event.Record = user
+
+	err = app.OnMailerRecordVerificationSend().Trigger(event, func(e *core.MailerRecordEvent) error {
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.FlushUsage(); err != nil {
+		t.Fatal(err)
+	}
+
+	item := &core.UsageRecord{}
+	err = app.UsageQuery().
+		AndWhere(dbx.HashExp{"tenant": core.UsageTenant(user), "unit": core.UsageUnitEmails}).
+		One(item)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if item.Value != 1 {
+		t.Fatalf("Expected 1 tracked email, got %d", item.Value)
+	}
+}
+
+func TestDeleteOldUsage(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	app.Settings().Metering.Enabled = true
+
+	now := time.Now()
+
+	periods := []time.Time{
+		now.AddDate(0, 0, -10),
+		now.AddDate(0, 0, -5),
+		now,
+	}
+
+	for i, p := range periods {
+		item := &core.UsageRecord{
+			Tenant: "t" + string(rune('0'+i)),
+			Unit:   core.UsageUnitRequests,
+			Period: core.UsagePeriod(p),
+			Value:  1,
+		}
+		item.Id = "usage" + string(rune('0'+i))
+		item.Created = types.NowDateTime()
+		item.Updated = item.Created
+		if err := app.Save(item); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.DeleteOldUsage(core.UsagePeriod(now.AddDate(0, 0, -5)).Time()); err != nil {
+		t.Fatal(err)
+	}
+
+	var total int
+	if err := app.UsageQuery().Select("count(*)").Row(&total); err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 2 {
+		t.Fatalf("Expected 2 remaining usage rollups, got %d", total)
+	}
+}
@@ -2,6 +2,8 @@ package validators
 
 import (
 	"fmt"
+	"image"
+	"io"
 	"strings"
 
 	"github.com/gabriel-vasile/mimetype"
@@ -94,3 +96,101 @@ func UploadedFileMimeType(validTypes []string) validation.RuleFunc {
 		)
 	}
 }
+
+// DefaultMaxImagePixels is the default max allowed number of image pixels (width*height)
+// used by [UploadedImageDecodable] to prevent decompression bomb uploads.
+const DefaultMaxImagePixels = 100_000_000
+
+// decodableImageTypes lists the image mime types with registered std or x/image decoders.
+var decodableImageTypes = []string{
+	"image/jpeg",
+	"image/png",
+	"image/gif",
+	"image/webp",
+	"image/bmp",
+	"image/tiff",
+}
+
+// UploadedImageDecodable checks whether the validated [*filesystem.File]
+// is a valid and fully decodable image (based on its content and not its extension).
+//
+// Only the common raster formats (jpeg, png, gif, webp, bmp, tiff) are decoded.
+// Files with other content types are left unchanged and are expected
+// to be restricted separately with [UploadedFileMimeType].
+//
+// Example:
+//
+//	validation.Field(&form.File, validation.By(validators.UploadedImageDecodable()))
+func UploadedImageDecodable() validation.RuleFunc {
+	return func(value any) error {
+		v, ok := value.(*filesystem.File)
+		if !ok {
+			return ErrUnsupportedValueType
+		}
+
+		if v == nil {
+			return nil // nothing to validate
+		}
+
+		baseErr := validation.NewError(
+			"validation_invalid_image",
+			"Failed to upload {{.file}} - the image is invalid or corrupted.",
+		).SetParams(map[string]any{
+			"file": v.OriginalName,
+		})
+
+		f, err := v.Reader.Open()
+		if err != nil {
+			return baseErr
+		}
+		defer f.Close()
+
+		filetype, err := mimetype.DetectReader(f)
+		if err != nil {
+			return baseErr
+		}
+
+		if !isDecodableImageType(filetype) {
+			return nil // not a decodable image format
+		}
+
+		// rewind for the actual decoding
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return baseErr
+		}
+
+		config, _, err := image.DecodeConfig(f)
+		if err != nil || config.Width <= 0 || config.Height <= 0 {
+			return baseErr
+		}
+
+		if int64(config.Width)*int64(config.Height) > DefaultMaxImagePixels {
+			return validation.NewError(
+				"validation_image_too_large",
+				"Failed to upload {{.file}} - the image dimensions are too large.",
+			).SetParams(map[string]any{
+				"file": v.OriginalName,
+			})
+		}
+
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return baseErr
+		}
+
+		if _, _, err := image.Decode(f); err != nil {
+			return baseErr
+		}
+
+		return nil
+	}
+}
+
+func isDecodableImageType(filetype *mimetype.MIME) bool {
+	for _, t := range decodableImageTypes {
+		if filetype.Is(t) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,7 +1,10 @@
 package validators_test
 
 import (
+	"bytes"
 	"fmt"
+	"image"
+	"image/png"
 	"strings"
 	"testing"
 
@@ -73,3 +76,59 @@ func TestUploadedFileMimeType(t *testing.T) {
 		})
 	}
 }
+
+func TestUploadedImageDecodable(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 2, 2))); err != nil {
+		t.Fatal(err)
+	}
+	validPNG := buf.Bytes()
+
+	validImage, err := filesystem.NewFileFromBytes(validPNG, "test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// valid png signature and header but truncated data
+	corruptedImage, err := filesystem.NewFileFromBytes(validPNG[:len(validPNG)-20], "test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// valid image content with non-image extension
+	renamedImage, err := filesystem.NewFileFromBytes(validPNG, "test.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// non-image content with image extension
+	textFile, err := filesystem.NewFileFromBytes([]byte("test"), "test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name        string
+		file        *filesystem.File
+		expectError bool
+	}{
+		{"nil file", nil, false},
+		{"valid image", validImage, false},
+		{"corrupted image", corruptedImage, true},
+		{"renamed valid image", renamedImage, false},
+		{"non-image content", textFile, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := validators.UploadedImageDecodable()(s.file)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr to be %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
@@ -9,9 +9,13 @@ import (
 	"github.com/pocketbase/pocketbase"
 	"github.com/pocketbase/pocketbase/apis"
 	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/plugins/dataimport"
+	"github.com/pocketbase/pocketbase/plugins/eventsink"
 	"github.com/pocketbase/pocketbase/plugins/ghupdate"
 	"github.com/pocketbase/pocketbase/plugins/jsvm"
 	"github.com/pocketbase/pocketbase/plugins/migratecmd"
+	"github.com/pocketbase/pocketbase/plugins/moderation"
+	"github.com/pocketbase/pocketbase/plugins/stripe"
 	"github.com/pocketbase/pocketbase/tools/hook"
 	"github.com/pocketbase/pocketbase/tools/osutils"
 )
@@ -79,6 +83,30 @@ func main() {
 		"fallback the request to index.html on missing static path, e.g. when pretty urls are used with SPA",
 	)
 
+	var staticMounts []string
+	app.RootCmd.PersistentFlags().StringArrayVar(
+		&staticMounts,
+		"staticMount",
+		nil,
+		"additional static directory mount in the format path=dir[?indexFallback=true&cacheControl=...&rule=...] (could be specified multiple times)",
+	)
+
+	var eventsinkDriver string
+	app.RootCmd.PersistentFlags().StringVar(
+		&eventsinkDriver,
+		"eventsinkDriver",
+		"",
+		"publish the record events to an external message broker (nats or kafkarest)",
+	)
+
+	var eventsinkURL string
+	app.RootCmd.PersistentFlags().StringVar(
+		&eventsinkURL,
+		"eventsinkURL",
+		"",
+		"the eventsink message broker url (e.g. nats://127.0.0.1:4222 or the Kafka REST Proxy http://127.0.0.1:8082)",
+	)
+
 	app.RootCmd.ParseFlags(os.Args[1:])
 
 	// ---------------------------------------------------------------
@@ -100,9 +128,55 @@ func main() {
 		Dir:          migrationsDir,
 	})
 
+	// record events publisher
+	if eventsinkDriver != "" {
+		eventsink.MustRegister(app, eventsink.Config{
+			Driver: eventsinkDriver,
+			URL:    eventsinkURL,
+		})
+	}
+
+	// Stripe customers and subscriptions sync
+	// (the secret is loaded from env to avoid exposing it in the process args)
+	if secret := os.Getenv("STRIPE_WEBHOOK_SECRET"); secret != "" {
+		stripe.MustRegister(app, stripe.Config{WebhookSecret: secret})
+	}
+
+	// uploaded images and videos content moderation
+	// (enabled only if the moderation API endpoint is set)
+	if url := os.Getenv("MODERATION_API_URL"); url != "" {
+		moderation.MustRegister(app, moderation.Config{
+			Moderator: &moderation.HTTPModerator{
+				URL:     url,
+				Headers: map[string]string{"Authorization": os.Getenv("MODERATION_API_AUTH")},
+			},
+			QuarantineFlagged: true,
+		})
+	}
+
 	// GitHub selfupdate
 	ghupdate.MustRegister(app, app.RootCmd, ghupdate.Config{})
 
+	// Firebase and Supabase data import
+	dataimport.MustRegister(app, app.RootCmd, dataimport.Config{})
+
+	// additional static directory mounts
+	if len(staticMounts) > 0 {
+		mounts := make([]core.StaticMount, 0, len(staticMounts))
+		for _, raw := range staticMounts {
+			mount, err := apis.ParseStaticMount(raw)
+			if err != nil {
+				log.Fatal(err)
+			}
+			mounts = append(mounts, mount)
+		}
+
+		app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+			e.Router.Bind(apis.StaticMounts(mounts...))
+			return e.Next()
+		})
+	}
+
 	// static route to serves files from the provided public dir
 	// (if publicDir exists and the route path is not already defined)
 	app.OnServe().Bind(&hook.Handler[*core.ServeEvent]{
@@ -145,7 +145,7 @@ func (form *RecordUpsert) validateFormFields() error {
 		"oldPassword":     form.oldPassword,
 	}
 
-	return validation.Validate(validateData,
+	err := validation.Validate(validateData,
 		validation.Map(
 			validation.Key(
 				"email",
@@ -193,6 +193,33 @@ func (form *RecordUpsert) validateFormFields() error {
 			),
 		),
 	)
+	if err != nil {
+		return err
+	}
+
+	// don't allow changing the phones verified state if the form doesn't have manage access permissions
+	// (aka. allow only admin or authorized auth models to directly change the fields)
+	if form.HasManageAccess() {
+		return nil
+	}
+
+	phonesData := map[string]any{}
+	phonesRules := []*validation.KeyRules{}
+	for _, f := range form.record.Collection().Fields {
+		phoneField, ok := f.(*core.PhoneField)
+		if !ok {
+			continue
+		}
+
+		key := phoneField.VerifiedFieldName()
+		phonesData[key] = form.record.PhoneVerified(phoneField.Name)
+		phonesRules = append(phonesRules, validation.Key(
+			key,
+			validation.By(validators.Equal(original.PhoneVerified(phoneField.Name))),
+		))
+	}
+
+	return validation.Validate(phonesData, validation.Map(phonesRules...))
 }
 
 func (form *RecordUpsert) checkOldPassword(value any) error {
@@ -761,6 +761,73 @@ func TestRecordUpsertSubmitValidations(t *testing.T) {
 	}
 }
 
+func TestRecordUpsertSubmitPhoneVerified(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name           string
+		data           map[string]any
+		managerAccess  bool
+		expectedErrors []string
+	}{
+		{
+			name: "phone change without manager access",
+			data: map[string]any{
+				"phone": "+359888000000",
+			},
+			expectedErrors: []string{},
+		},
+		{
+			name: "phoneVerified change without manager access",
+			data: map[string]any{
+				"phone":         "+359888000000",
+				"phoneVerified": true,
+			},
+			expectedErrors: []string{"phoneVerified"},
+		},
+		{
+			name: "phoneVerified change with manager access",
+			data: map[string]any{
+				"phone":         "+359888000000",
+				"phoneVerified": true,
+			},
+			managerAccess:  true,
+			expectedErrors: []string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			testApp, _ := tests.NewTestApp()
+			defer testApp.Cleanup()
+
+			usersCol, err := testApp.FindCollectionByNameOrId("users")
+			if err != nil {
+				t.Fatal(err)
+			}
+			usersCol.Fields.Add(&core.PhoneField{Name: "phone"})
+			if err = testApp.Save(usersCol); err != nil {
+				t.Fatal(err)
+			}
+
+			userRec, err := testApp.FindRecordById(usersCol, "4q1xlclmfloku33")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			form := forms.NewRecordUpsert(testApp, userRec)
+			if s.managerAccess {
+				form.GrantManagerAccess()
+			}
+			form.Load(s.data)
+
+			result := form.Submit()
+
+			tests.TestValidationErrors(t, result, s.expectedErrors)
+		})
+	}
+}
+
 func TestRecordUpsertSubmitFailure(t *testing.T) {
 	testApp, _ := tests.NewTestApp()
 	defer testApp.Cleanup()
@@ -0,0 +1,127 @@
+package mails
+
+import (
+	"errors"
+	"maps"
+	"net/mail"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+const bulkEmailBatchSize = 200
+
+// QueueBulkEmail resolves the provided email template for each auth record
+// of the collection matching the filter and adds the resulting messages
+// in the mail queue (see [core.App.QueueMail]).
+//
+// Similar to the other record emails, the template subject and body could contain
+// the {APP_NAME}, {APP_URL} and {RECORD:*} placeholders, as well as the
+// custom ones from the placeholders map (e.g. {"{DIGEST}": "..."}).
+// The message of each recipient could be further customized or skipped
+// with the [core.App.OnMailerRecordBulkSend] hook.
+//
+// The filter is optional and it is applied without the collection API rules.
+//
+// Returns the number of the queued emails.
+func QueueBulkEmail(
+	app core.App,
+	collection *core.Collection,
+	filter string,
+	emailTemplate core.EmailTemplate,
+	placeholders map[string]any,
+	params ...dbx.Params,
+) (int, error) {
+	if !collection.IsAuth() {
+		return 0, errors.New("bulk emails can be sent only to auth collection records")
+	}
+
+	if err := emailTemplate.Validate(); err != nil {
+		return 0, err
+	}
+
+	var total int
+
+	err := app.RunInTransaction(func(txApp core.App) error {
+		for offset := 0; ; offset += bulkEmailBatchSize {
+			records, err := txApp.FindRecordsByFilter(collection, filter, "id", bulkEmailBatchSize, offset, params...)
+			if err != nil {
+				return err
+			}
+
+			for _, record := range records {
+				queued, err := queueRecordBulkEmail(txApp, record, emailTemplate, placeholders)
+				if err != nil {
+					return err
+				}
+
+				if queued {
+					total++
+				}
+			}
+
+			if len(records) < bulkEmailBatchSize {
+				return nil
+			}
+		}
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func queueRecordBulkEmail(
+	app core.App,
+	authRecord *core.Record,
+	emailTemplate core.EmailTemplate,
+	placeholders map[string]any,
+) (bool, error) {
+	if authRecord.Email() == "" {
+		return false, nil
+	}
+
+	// the placeholders are populated with the recipient values
+	recordPlaceholders := maps.Clone(placeholders)
+	if recordPlaceholders == nil {
+		recordPlaceholders = map[string]any{}
+	}
+
+	subject, body, err := resolveEmailTemplate(app, authRecord, emailTemplate, recordPlaceholders)
+	if err != nil {
+		return false, err
+	}
+
+	message := &mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      []mail.Address{{Address: authRecord.Email()}},
+		Subject: subject,
+		HTML:    body,
+	}
+
+	event := new(core.MailerRecordEvent)
+	event.App = app
+	event.Mailer = app.NewMailClient()
+	event.Message = message
+	event.Record = authRecord
+	event.Meta = recordPlaceholders
+
+	var queued bool
+
+	err = app.OnMailerRecordBulkSend().Trigger(event, func(e *core.MailerRecordEvent) error {
+		if _, err := e.App.QueueMail(e.Message); err != nil {
+			return err
+		}
+
+		queued = true
+
+		return nil
+	})
+
+	return queued, err
+}
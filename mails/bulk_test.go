@@ -0,0 +1,108 @@
+package mails_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestQueueBulkEmail(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	users, err := testApp.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo2, err := testApp.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := core.EmailTemplate{
+		Subject: "Hello {RECORD:email}",
+		Body:    "<p>{PROMO} for {RECORD:email} from {APP_NAME}</p>",
+	}
+
+	// non-auth collection
+	if _, err := mails.QueueBulkEmail(testApp, demo2, "", template, nil); err == nil {
+		t.Fatal("Expected non-auth collection error")
+	}
+
+	// invalid template
+	if _, err := mails.QueueBulkEmail(testApp, users, "", core.EmailTemplate{}, nil); err == nil {
+		t.Fatal("Expected invalid template error")
+	}
+
+	// invalid filter
+	if _, err := mails.QueueBulkEmail(testApp, users, "missing = 1", template, nil); err == nil {
+		t.Fatal("Expected invalid filter error")
+	}
+
+	// skip a recipient
+	testApp.OnMailerRecordBulkSend().BindFunc(func(e *core.MailerRecordEvent) error {
+		if e.Record.Email() == "test2@example.com" {
+			return nil
+		}
+
+		if e.Meta["{PROMO}"] != "test_promo" {
+			t.Fatalf("Expected the custom placeholder in the event meta, got %v", e.Meta)
+		}
+
+		return e.Next()
+	})
+
+	total, err := mails.QueueBulkEmail(
+		testApp,
+		users,
+		"email != {:email}",
+		template,
+		map[string]any{"{PROMO}": "test_promo"},
+		dbx.Params{"email": "test3@example.com"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 1 {
+		t.Fatalf("Expected 1 queued email, got %d", total)
+	}
+
+	if testApp.TestMailer.TotalSend() != 0 {
+		t.Fatalf("Expected no directly sent emails, got %d", testApp.TestMailer.TotalSend())
+	}
+
+	items := []*core.QueuedMail{}
+	if err := testApp.MailQueueQuery().All(&items); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 queued email, got %d", len(items))
+	}
+
+	message, err := items[0].ParseMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(message.To) != 1 || message.To[0].Address != "test@example.com" {
+		t.Fatalf("Expected test@example.com recipient, got %v", message.To)
+	}
+
+	if message.Subject != "Hello test@example.com" {
+		t.Fatalf("Expected resolved subject, got %q", message.Subject)
+	}
+
+	expectedBody := "<p>test_promo for test@example.com from " + testApp.Settings().Meta.AppName + "</p>"
+	if !strings.Contains(message.HTML, expectedBody) {
+		t.Fatalf("Couldn't find %s \nin\n %s", expectedBody, message.HTML)
+	}
+}
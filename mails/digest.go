@@ -0,0 +1,117 @@
+package mails
+
+import (
+	"errors"
+	"html"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// QueueDigest renders the current period digest of each of the digest recipients
+// and adds the resulting emails in the mail queue (see [QueueBulkEmail]).
+//
+// Recipients without digest records are skipped.
+// On success the digest LastRun date is updated to mark the start of the next period.
+//
+// Returns the number of the queued emails.
+func QueueDigest(app core.App, digest *core.Digest) (int, error) {
+	recipientsCollection, err := app.FindCachedCollectionByNameOrId(digest.RecipientsCollectionRef)
+	if err != nil {
+		return 0, err
+	}
+
+	if !recipientsCollection.IsAuth() {
+		return 0, errors.New("digest emails can be sent only to auth collection records")
+	}
+
+	emailTemplate := digest.EmailTemplate()
+	if err := emailTemplate.Validate(); err != nil {
+		return 0, err
+	}
+
+	// the next period starts with the current run
+	lastRun := types.NowDateTime()
+
+	var total int
+
+	err = app.RunInTransaction(func(txApp core.App) error {
+		for offset := 0; ; offset += bulkEmailBatchSize {
+			recipients, err := txApp.FindRecordsByFilter(recipientsCollection, digest.RecipientsFilter, "id", bulkEmailBatchSize, offset)
+			if err != nil {
+				return err
+			}
+
+			for _, recipient := range recipients {
+				records, err := txApp.FindDigestRecords(digest, recipient)
+				if err != nil {
+					return err
+				}
+
+				if len(records) == 0 {
+					continue
+				}
+
+				placeholders := map[string]any{
+					core.EmailPlaceholderDigest:      resolveDigestItems(digest.ItemTemplate, records),
+					core.EmailPlaceholderDigestCount: strconv.Itoa(len(records)),
+				}
+
+				queued, err := queueRecordBulkEmail(txApp, recipient, emailTemplate, placeholders)
+				if err != nil {
+					return err
+				}
+
+				if queued {
+					total++
+				}
+			}
+
+			if len(recipients) < bulkEmailBatchSize {
+				break
+			}
+		}
+
+		digest.LastRun = lastRun
+
+		return txApp.Save(digest)
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+// resolveDigestItems renders each record with the digest item template
+// by replacing its {ITEM:*} field placeholders.
+func resolveDigestItems(itemTemplate string, records []*core.Record) string {
+	var sb strings.Builder
+
+	for _, record := range records {
+		placeholders := map[string]any{}
+
+		for _, field := range record.Collection().Fields {
+			if field.GetHidden() {
+				continue
+			}
+
+			val := record.GetString(field.GetName())
+
+			if !slices.Contains(nonescapeTypes, field.Type()) {
+				val = html.EscapeString(val)
+			}
+
+			placeholders["{ITEM:"+field.GetName()+"}"] = val
+		}
+
+		_, item := core.EmailTemplate{Body: itemTemplate}.Resolve(placeholders)
+
+		sb.WriteString(item)
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,119 @@
+package mails_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestQueueDigest(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	users, err := testApp.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	demo2, err := testApp.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lastRun, err := types.ParseDateTime("2022-10-12 11:42:52.000Z")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	digest := &core.Digest{
+		Name:                    "test",
+		Schedule:                "@daily",
+		CollectionRef:           demo2.Id,
+		Filter:                  "created > {:since} && title != @request.auth.name",
+		Sort:                    "title",
+		MaxItems:                10,
+		RecipientsCollectionRef: users.Id,
+		RecipientsFilter:        "verified = true",
+		Subject:                 "{DIGEST_COUNT} new for {RECORD:email}",
+		Body:                    "<ul>{DIGEST}</ul>",
+		ItemTemplate:            "<li>{ITEM:title}</li>",
+		LastRun:                 lastRun,
+	}
+	if err := testApp.Save(digest); err != nil {
+		t.Fatal(err)
+	}
+
+	// non-auth recipients collection
+	invalid := *digest
+	invalid.RecipientsCollectionRef = demo2.Id
+	if _, err := mails.QueueDigest(testApp, &invalid); err == nil {
+		t.Fatal("Expected non-auth collection error")
+	}
+
+	total, err := mails.QueueDigest(testApp, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// test3@example.com (test2) and test2@example.com (test2, test3)
+	if total != 2 {
+		t.Fatalf("Expected 2 queued emails, got %d", total)
+	}
+
+	if digest.LastRun.Time().Before(lastRun.Time().AddDate(1, 0, 0)) {
+		t.Fatalf("Expected LastRun to be updated, got %v", digest.LastRun)
+	}
+
+	items := []*core.QueuedMail{}
+	if err := testApp.MailQueueQuery().OrderBy("rowid ASC").All(&items); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 2 {
+		t.Fatalf("Expected 2 queued emails, got %d", len(items))
+	}
+
+	expectations := []struct {
+		to      string
+		subject string
+		body    string
+	}{
+		{"test3@example.com", "1 new for test3@example.com", "<ul><li>test2</li></ul>"},
+		{"test2@example.com", "2 new for test2@example.com", "<ul><li>test2</li><li>test3</li></ul>"},
+	}
+
+	for i, expected := range expectations {
+		message, err := items[i].ParseMessage()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if len(message.To) != 1 || message.To[0].Address != expected.to {
+			t.Fatalf("[%d] Expected %s recipient, got %v", i, expected.to, message.To)
+		}
+
+		if message.Subject != expected.subject {
+			t.Fatalf("[%d] Expected subject %q, got %q", i, expected.subject, message.Subject)
+		}
+
+		if !strings.Contains(message.HTML, expected.body) {
+			t.Fatalf("[%d] Couldn't find %s \nin\n %s", i, expected.body, message.HTML)
+		}
+	}
+
+	// no new records since the last run
+	total, err = mails.QueueDigest(testApp, digest)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if total != 0 {
+		t.Fatalf("Expected no queued emails for the next period, got %d", total)
+	}
+}
@@ -0,0 +1,107 @@
+package mails
+
+import (
+	"errors"
+	"html"
+	"html/template"
+	"net/mail"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails/templates"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+// Form submission email template placeholders.
+const (
+	EmailPlaceholderFormCollection = "{FORM_COLLECTION}"
+	EmailPlaceholderFormData       = "{FORM_DATA}"
+)
+
+// FormSubmissionTemplate is the default public form submission notification email template.
+//
+// Besides the {FORM_COLLECTION} and {FORM_DATA} placeholders, it could also contain
+// the common {APP_NAME}, {APP_URL}, {RECORD:collectionId} and {RECORD:id} ones.
+var FormSubmissionTemplate = core.EmailTemplate{
+	Subject: "New " + EmailPlaceholderFormCollection + " form submission",
+	Body: `<p>Hello,</p>
+<p>A new <strong>` + EmailPlaceholderFormCollection + `</strong> form was submitted at ` + core.EmailPlaceholderAppName + `:</p>
+` + EmailPlaceholderFormData + `
+<p>
+  <a class="btn" href="` + core.EmailPlaceholderAppURL + "/_/#/collections?collection={RECORD:collectionId}&recordId={RECORD:id}" + `" target="_blank" rel="noopener">View submission</a>
+</p>`,
+}
+
+// QueueFormSubmissionNotification adds in the mail queue (see [core.App.QueueMail])
+// a notification email with the submitted form values of the provided record
+// to each of its collection [core.FormConfig.NotifyEmails] recipients.
+//
+// It does nothing if the collection doesn't have notification recipients.
+func QueueFormSubmissionNotification(app core.App, record *core.Record) error {
+	config := record.Collection().Form
+	if config == nil {
+		return errors.New("the record collection doesn't have public form options")
+	}
+
+	if len(config.NotifyEmails) == 0 {
+		return nil
+	}
+
+	// first resolve the common placeholders so that they
+	// are not replaced in the submitted values
+	resolved := core.EmailTemplate{}
+	resolved.Subject, resolved.Body = FormSubmissionTemplate.Resolve(map[string]any{
+		core.EmailPlaceholderAppName:   app.Settings().Meta.AppName,
+		core.EmailPlaceholderAppURL:    app.Settings().Meta.AppURL,
+		EmailPlaceholderFormCollection: html.EscapeString(record.Collection().Name),
+		"{RECORD:collectionId}":        record.Collection().Id,
+		"{RECORD:id}":                  record.Id,
+	})
+
+	subject, rawBody := resolved.Resolve(map[string]any{
+		EmailPlaceholderFormData: resolveFormData(config.Fields, record),
+	})
+
+	body, err := resolveTemplateContent(struct {
+		HTMLContent template.HTML
+	}{
+		HTMLContent: template.HTML(rawBody),
+	}, templates.Layout, templates.HTMLBody)
+	if err != nil {
+		return err
+	}
+
+	to := make([]mail.Address, len(config.NotifyEmails))
+	for i, email := range config.NotifyEmails {
+		to[i] = mail.Address{Address: email}
+	}
+
+	_, err = app.QueueMail(&mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      to,
+		Subject: subject,
+		HTML:    body,
+	})
+
+	return err
+}
+
+// resolveFormData renders the escaped record fields values as html list.
+func resolveFormData(fields []string, record *core.Record) string {
+	var sb strings.Builder
+
+	sb.WriteString("<ul>")
+	for _, name := range fields {
+		sb.WriteString("<li><strong>")
+		sb.WriteString(html.EscapeString(name))
+		sb.WriteString(":</strong> ")
+		sb.WriteString(strings.ReplaceAll(html.EscapeString(record.GetString(name)), "\n", "<br/>"))
+		sb.WriteString("</li>")
+	}
+	sb.WriteString("</ul>")
+
+	return sb.String()
+}
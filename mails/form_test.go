@@ -0,0 +1,89 @@
+package mails_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestQueueFormSubmissionNotification(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	record, err := testApp.FindRecordById("demo1", "84nmscqy84lsi1t")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// missing form config
+	if err := mails.QueueFormSubmissionNotification(testApp, record); err == nil {
+		t.Fatal("Expected missing form config error")
+	}
+
+	countQueued := func() int {
+		items := []*core.QueuedMail{}
+		if err := testApp.MailQueueQuery().All(&items); err != nil {
+			t.Fatal(err)
+		}
+		return len(items)
+	}
+
+	// without recipients
+	record.Collection().Form = &core.FormConfig{Fields: []string{"text"}}
+	if err := mails.QueueFormSubmissionNotification(testApp, record); err != nil {
+		t.Fatal(err)
+	}
+	if total := countQueued(); total != 0 {
+		t.Fatalf("Expected no queued emails, got %d", total)
+	}
+
+	// with recipients
+	record.Collection().Form = &core.FormConfig{
+		Fields:       []string{"text", "email"},
+		NotifyEmails: []string{"a@example.com", "b@example.com"},
+	}
+	record.Set("text", "<b>{APP_NAME}</b>\nline2")
+	record.Set("email", "test@example.com")
+
+	if err := mails.QueueFormSubmissionNotification(testApp, record); err != nil {
+		t.Fatal(err)
+	}
+
+	items := []*core.QueuedMail{}
+	if err := testApp.MailQueueQuery().All(&items); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != 1 {
+		t.Fatalf("Expected 1 queued email, got %d", len(items))
+	}
+
+	message, err := items[0].ParseMessage()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(message.To) != 2 || message.To[0].Address != "a@example.com" || message.To[1].Address != "b@example.com" {
+		t.Fatalf("Expected a@example.com and b@example.com recipients, got %v", message.To)
+	}
+
+	if message.Subject != "New demo1 form submission" {
+		t.Fatalf("Expected subject %q, got %q", "New demo1 form submission", message.Subject)
+	}
+
+	expectedParts := []string{
+		"<li><strong>text:</strong> &lt;b&gt;{APP_NAME}&lt;/b&gt;<br/>line2</li>",
+		"<li><strong>email:</strong> test@example.com</li>",
+		"http://localhost:8090/_/#/collections?collection=wsmn24bux7wo113&recordId=84nmscqy84lsi1t",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(message.HTML, part) {
+			t.Fatalf("Couldn't find %s \nin\n %s", part, message.HTML)
+		}
+	}
+}
@@ -11,11 +11,36 @@ import (
 	"github.com/pocketbase/pocketbase/tools/mailer"
 )
 
+// AuthAlertInfo defines the new device login details
+// that are available in the auth alert email template.
+type AuthAlertInfo struct {
+	// IP is the client IP address of the login request.
+	IP string
+
+	// UserAgent is the client User-Agent header value of the login request.
+	UserAgent string
+
+	// Country is the resolved country code of the IP (if GeoIP is enabled).
+	Country string
+}
+
 // SendRecordAuthAlert sends a new device login alert to the specified auth record.
-func SendRecordAuthAlert(app core.App, authRecord *core.Record) error {
+//
+// The optional info is exposed as {ALERT_IP}, {ALERT_USER_AGENT} and {ALERT_COUNTRY}
+// template placeholders, as well as in the hook event Meta.
+func SendRecordAuthAlert(app core.App, authRecord *core.Record, optInfo ...AuthAlertInfo) error {
 	mailClient := app.NewMailClient()
 
-	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().AuthAlert.EmailTemplate, nil)
+	var info AuthAlertInfo
+	if len(optInfo) > 0 {
+		info = optInfo[0]
+	}
+
+	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().AuthAlert.EmailTemplate, map[string]any{
+		core.EmailPlaceholderAlertIP:        html.EscapeString(info.IP),
+		core.EmailPlaceholderAlertUserAgent: html.EscapeString(info.UserAgent),
+		core.EmailPlaceholderAlertCountry:   html.EscapeString(info.Country),
+	})
 	if err != nil {
 		return err
 	}
@@ -35,6 +60,11 @@ func SendRecordAuthAlert(app core.App, authRecord *core.Record) error {
 	event.Mailer = mailClient
 	event.Message = message
 	event.Record = authRecord
+	event.Meta = map[string]any{
+		"ip":        info.IP,
+		"userAgent": info.UserAgent,
+		"country":   info.Country,
+	}
 
 	return app.OnMailerRecordAuthAlertSend().Trigger(event, func(e *core.MailerRecordEvent) error {
 		return e.Mailer.Send(e.Message)
@@ -194,8 +224,16 @@ func SendRecordVerification(app core.App, authRecord *core.Record) error {
 }
 
 // SendRecordChangeEmail sends a change email confirmation email to the specified auth record.
+//
+// If the auth collection requires old email approval, the sent token
+// is marked as already approved (see [SendRecordChangeEmailApproval]).
 func SendRecordChangeEmail(app core.App, authRecord *core.Record, newEmail string) error {
-	token, tokenErr := authRecord.NewEmailChangeToken(newEmail)
+	var stage string
+	if authRecord.Collection().EmailChange.RequireOldEmailApproval {
+		stage = core.EmailChangeStageConfirm
+	}
+
+	token, tokenErr := authRecord.NewEmailChangeStageToken(newEmail, stage)
 	if tokenErr != nil {
 		return tokenErr
 	}
@@ -234,6 +272,91 @@ func SendRecordChangeEmail(app core.App, authRecord *core.Record, newEmail strin
 	})
 }
 
+// SendRecordChangeEmailApproval sends an email change approval email
+// to the current (old) email address of the specified auth record.
+//
+// Once approved, a regular change email confirmation email is sent to the new address.
+func SendRecordChangeEmailApproval(app core.App, authRecord *core.Record, newEmail string) error {
+	token, tokenErr := authRecord.NewEmailChangeStageToken(newEmail, core.EmailChangeStageApprove)
+	if tokenErr != nil {
+		return tokenErr
+	}
+
+	mailClient := app.NewMailClient()
+
+	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().EmailChange.ApprovalTemplateOrDefault(), map[string]any{
+		core.EmailPlaceholderToken:    token,
+		core.EmailPlaceholderNewEmail: html.EscapeString(newEmail),
+	})
+	if err != nil {
+		return err
+	}
+
+	message := &mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      []mail.Address{{Address: authRecord.Email()}},
+		Subject: subject,
+		HTML:    body,
+	}
+
+	event := new(core.MailerRecordEvent)
+	event.App = app
+	event.Mailer = mailClient
+	event.Message = message
+	event.Record = authRecord
+	event.Meta = map[string]any{
+		"token":    token,
+		"newEmail": newEmail,
+		"stage":    core.EmailChangeStageApprove,
+	}
+
+	return app.OnMailerRecordEmailChangeSend().Trigger(event, func(e *core.MailerRecordEvent) error {
+		return e.Mailer.Send(e.Message)
+	})
+}
+
+// SendRecordEmailChangedNotification sends a notification to the old
+// email address of the specified auth record after successful email change.
+func SendRecordEmailChangedNotification(app core.App, authRecord *core.Record, oldEmail string) error {
+	mailClient := app.NewMailClient()
+
+	subject, body, err := resolveEmailTemplate(app, authRecord, authRecord.Collection().EmailChange.NotifyTemplateOrDefault(), map[string]any{
+		core.EmailPlaceholderOldEmail: html.EscapeString(oldEmail),
+		core.EmailPlaceholderNewEmail: html.EscapeString(authRecord.Email()),
+	})
+	if err != nil {
+		return err
+	}
+
+	message := &mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      []mail.Address{{Address: oldEmail}},
+		Subject: subject,
+		HTML:    body,
+	}
+
+	event := new(core.MailerRecordEvent)
+	event.App = app
+	event.Mailer = mailClient
+	event.Message = message
+	event.Record = authRecord
+	event.Meta = map[string]any{
+		"oldEmail": oldEmail,
+		"newEmail": authRecord.Email(),
+		"stage":    "notify",
+	}
+
+	return app.OnMailerRecordEmailChangeSend().Trigger(event, func(e *core.MailerRecordEvent) error {
+		return e.Mailer.Send(e.Message)
+	})
+}
+
 var nonescapeTypes = []string{
 	core.FieldTypeAutodate,
 	core.FieldTypeDate,
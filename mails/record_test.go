@@ -5,6 +5,7 @@ import (
 	"strings"
 	"testing"
 
+	"github.com/pocketbase/pocketbase/core"
 	"github.com/pocketbase/pocketbase/mails"
 	"github.com/pocketbase/pocketbase/tests"
 )
@@ -42,6 +43,70 @@ func TestSendRecordAuthAlert(t *testing.T) {
 	}
 }
 
+func TestSendRecordAuthAlertWithInfo(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, _ := testApp.FindFirstRecordByData("users", "email", "test@example.com")
+
+	user.Collection().AuthAlert.EmailTemplate.Body = "ip:{ALERT_IP};ua:{ALERT_USER_AGENT};country:{ALERT_COUNTRY}"
+
+	var meta map[string]any
+	testApp.OnMailerRecordAuthAlertSend().BindFunc(func(e *core.MailerRecordEvent) error {
+		meta = e.Meta
+		return e.Next()
+	})
+
+	err := mails.SendRecordAuthAlert(testApp, user, mails.AuthAlertInfo{
+		IP:        "1.2.3.4",
+		UserAgent: "<b>test</b>",
+		Country:   "BG",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend() != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", testApp.TestMailer.TotalSend())
+	}
+
+	expectedBody := "ip:1.2.3.4;ua:&lt;b&gt;test&lt;/b&gt;;country:BG"
+	if body := testApp.TestMailer.LastMessage().HTML; !strings.Contains(body, expectedBody) {
+		t.Fatalf("Couldn't find %s \nin\n %s", expectedBody, body)
+	}
+
+	expectedMeta := map[string]any{"ip": "1.2.3.4", "userAgent": "<b>test</b>", "country": "BG"}
+	for k, v := range expectedMeta {
+		if meta[k] != v {
+			t.Fatalf("Expected meta %q to be %v, got %v", k, v, meta[k])
+		}
+	}
+}
+
+func TestSendRecordAuthAlertSuppress(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, _ := testApp.FindFirstRecordByData("users", "email", "test@example.com")
+
+	testApp.OnMailerRecordAuthAlertSend().BindFunc(func(e *core.MailerRecordEvent) error {
+		return nil // don't call e.Next()
+	})
+
+	err := mails.SendRecordAuthAlert(testApp, user)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend() != 0 {
+		t.Fatalf("Expected no emails to be sent, got %d", testApp.TestMailer.TotalSend())
+	}
+}
+
 func TestSendRecordPasswordReset(t *testing.T) {
 	t.Parallel()
 
@@ -135,6 +200,75 @@ func TestSendRecordChangeEmail(t *testing.T) {
 	}
 }
 
+func TestSendRecordChangeEmailApproval(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, _ := testApp.FindFirstRecordByData("users", "email", "test@example.com")
+
+	err := mails.SendRecordChangeEmailApproval(testApp, user, "new_test<b>@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend() != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", testApp.TestMailer.TotalSend())
+	}
+
+	message := testApp.TestMailer.LastMessage()
+
+	if len(message.To) != 1 || message.To[0].Address != "test@example.com" {
+		t.Fatalf("Expected the email to be sent to the old email address, got %v", message.To)
+	}
+
+	expectedParts := []string{
+		"new_test&lt;b&gt;@example.com",
+		"http://localhost:8090/_/#/auth/confirm-email-change/eyJhbGciOiJIUzI1NiIsInR5cCI6IkpXVCJ9.",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(message.HTML, part) {
+			t.Fatalf("Couldn't find %s \nin\n %s", part, message.HTML)
+		}
+	}
+}
+
+func TestSendRecordEmailChangedNotification(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	user, _ := testApp.FindFirstRecordByData("users", "email", "test@example.com")
+	user.SetEmail("new_test@example.com")
+
+	err := mails.SendRecordEmailChangedNotification(testApp, user, "test@example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend() != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", testApp.TestMailer.TotalSend())
+	}
+
+	message := testApp.TestMailer.LastMessage()
+
+	if len(message.To) != 1 || message.To[0].Address != "test@example.com" {
+		t.Fatalf("Expected the email to be sent to the old email address, got %v", message.To)
+	}
+
+	expectedParts := []string{
+		"<strong>test@example.com</strong>",
+		"<strong>new_test@example.com</strong>",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(message.HTML, part) {
+			t.Fatalf("Couldn't find %s \nin\n %s", part, message.HTML)
+		}
+	}
+}
+
 func TestSendRecordOTP(t *testing.T) {
 	t.Parallel()
 
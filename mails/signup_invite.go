@@ -0,0 +1,64 @@
+package mails
+
+import (
+	"errors"
+	"html/template"
+	"net/mail"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails/templates"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+// SignupInviteTemplate is the default signup invite email template.
+//
+// It could contain the common {APP_NAME}, {APP_URL} and {TOKEN} placeholders.
+var SignupInviteTemplate = core.EmailTemplate{
+	Subject: "You have been invited to " + core.EmailPlaceholderAppName,
+	Body: `<p>Hello,</p>
+<p>You have been invited to join ` + core.EmailPlaceholderAppName + `.</p>
+<p>Click on the button below to create your account.</p>
+<p>
+  <a class="btn" href="` + core.EmailPlaceholderAppURL + "/_/#/auth/signup-invite/" + core.EmailPlaceholderToken + `" target="_blank" rel="noopener">Sign up</a>
+</p>
+<p><i>If you didn't expect this invitation, you can ignore this email.</i></p>
+<p>
+  Thanks,<br/>
+  ` + core.EmailPlaceholderAppName + ` team
+</p>`,
+}
+
+// SendSignupInvite sends the signup invite email with the provided
+// plain invite token (see [core.App.CreateSignupInvite]).
+//
+// The invite must be restricted to a single email address.
+func SendSignupInvite(app core.App, invite *core.SignupInvite, token string) error {
+	if invite.Email == "" {
+		return errors.New("the signup invite doesn't have an email address")
+	}
+
+	subject, rawBody := SignupInviteTemplate.Resolve(map[string]any{
+		core.EmailPlaceholderAppName: app.Settings().Meta.AppName,
+		core.EmailPlaceholderAppURL:  app.Settings().Meta.AppURL,
+		core.EmailPlaceholderToken:   token,
+	})
+
+	body, err := resolveTemplateContent(struct {
+		HTMLContent template.HTML
+	}{
+		HTMLContent: template.HTML(rawBody),
+	}, templates.Layout, templates.HTMLBody)
+	if err != nil {
+		return err
+	}
+
+	return app.NewMailClient().Send(&mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      []mail.Address{{Address: invite.Email}},
+		Subject: subject,
+		HTML:    body,
+	})
+}
@@ -0,0 +1,44 @@
+package mails_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestSendSignupInvite(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	if err := mails.SendSignupInvite(testApp, &core.SignupInvite{}, "test_token"); err == nil {
+		t.Fatal("Expected missing email error")
+	}
+
+	if testApp.TestMailer.TotalSend() != 0 {
+		t.Fatalf("Expected no emails to be sent, got %d", testApp.TestMailer.TotalSend())
+	}
+
+	err := mails.SendSignupInvite(testApp, &core.SignupInvite{Email: "new@example.com"}, "test_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend() != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", testApp.TestMailer.TotalSend())
+	}
+
+	message := testApp.TestMailer.LastMessage()
+
+	if len(message.To) != 1 || message.To[0].Address != "new@example.com" {
+		t.Fatalf("Expected the email to be sent to new@example.com, got %v", message.To)
+	}
+
+	if !strings.Contains(message.HTML, "http://localhost:8090/_/#/auth/signup-invite/test_token") {
+		t.Fatalf("Couldn't find the invite link in\n %s", message.HTML)
+	}
+}
@@ -0,0 +1,67 @@
+package mails
+
+import (
+	"html"
+	"html/template"
+	"net/mail"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails/templates"
+	"github.com/pocketbase/pocketbase/tools/mailer"
+)
+
+// Team invitation email template placeholders.
+const (
+	EmailPlaceholderTeamName = "{TEAM_NAME}"
+	EmailPlaceholderTeamRole = "{TEAM_ROLE}"
+)
+
+// TeamInvitationTemplate is the default team invitation email template.
+//
+// Besides the {TEAM_NAME} and {TEAM_ROLE} placeholders, it could also contain
+// the common {APP_NAME}, {APP_URL} and {TOKEN} ones.
+var TeamInvitationTemplate = core.EmailTemplate{
+	Subject: "You have been invited to join " + EmailPlaceholderTeamName,
+	Body: `<p>Hello,</p>
+<p>You have been invited to join the <strong>` + EmailPlaceholderTeamName + `</strong> team at ` + core.EmailPlaceholderAppName + ` as ` + EmailPlaceholderTeamRole + `.</p>
+<p>Click on the button below to accept the invitation.</p>
+<p>
+  <a class="btn" href="` + core.EmailPlaceholderAppURL + "/_/#/teams/accept-invitation/" + core.EmailPlaceholderToken + `" target="_blank" rel="noopener">Accept invitation</a>
+</p>
+<p><i>If you didn't expect this invitation, you can ignore this email.</i></p>
+<p>
+  Thanks,<br/>
+  ` + core.EmailPlaceholderAppName + ` team
+</p>`,
+}
+
+// SendTeamInvitation sends the team invitation email with the provided
+// plain invitation token (see [core.App.CreateTeamInvitation]).
+func SendTeamInvitation(app core.App, team *core.Team, invitation *core.TeamInvitation, token string) error {
+	subject, rawBody := TeamInvitationTemplate.Resolve(map[string]any{
+		core.EmailPlaceholderAppName: app.Settings().Meta.AppName,
+		core.EmailPlaceholderAppURL:  app.Settings().Meta.AppURL,
+		core.EmailPlaceholderToken:   token,
+		EmailPlaceholderTeamName:     html.EscapeString(team.Name),
+		EmailPlaceholderTeamRole:     invitation.Role,
+	})
+
+	body, err := resolveTemplateContent(struct {
+		HTMLContent template.HTML
+	}{
+		HTMLContent: template.HTML(rawBody),
+	}, templates.Layout, templates.HTMLBody)
+	if err != nil {
+		return err
+	}
+
+	return app.NewMailClient().Send(&mailer.Message{
+		From: mail.Address{
+			Name:    app.Settings().Meta.SenderName,
+			Address: app.Settings().Meta.SenderAddress,
+		},
+		To:      []mail.Address{{Address: invitation.Email}},
+		Subject: subject,
+		HTML:    body,
+	})
+}
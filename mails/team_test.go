@@ -0,0 +1,47 @@
+package mails_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/mails"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestSendTeamInvitation(t *testing.T) {
+	t.Parallel()
+
+	testApp, _ := tests.NewTestApp()
+	defer testApp.Cleanup()
+
+	// to test that it is escaped
+	team := &core.Team{Name: "<p>test</p>"}
+	invitation := &core.TeamInvitation{Email: "new@example.com", Role: core.TeamRoleAdmin}
+
+	err := mails.SendTeamInvitation(testApp, team, invitation, "test_token")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if testApp.TestMailer.TotalSend() != 1 {
+		t.Fatalf("Expected one email to be sent, got %d", testApp.TestMailer.TotalSend())
+	}
+
+	message := testApp.TestMailer.LastMessage()
+
+	if len(message.To) != 1 || message.To[0].Address != "new@example.com" {
+		t.Fatalf("Expected the email to be sent to new@example.com, got %v", message.To)
+	}
+
+	expectedParts := []string{
+		"&lt;p&gt;test&lt;/p&gt;",
+		" as admin.",
+		"http://localhost:8090/_/#/teams/accept-invitation/test_token",
+	}
+	for _, part := range expectedParts {
+		if !strings.Contains(message.HTML, part) {
+			t.Fatalf("Couldn't find %s \nin\n %s", part, message.HTML)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_changes}} (
+					[[seq]]            INTEGER PRIMARY KEY AUTOINCREMENT NOT NULL,
+					[[collectionId]]   TEXT DEFAULT "" NOT NULL,
+					[[collectionName]] TEXT DEFAULT "" NOT NULL,
+					[[recordId]]       TEXT DEFAULT "" NOT NULL,
+					[[action]]         TEXT DEFAULT "" NOT NULL,
+					[[data]]           JSON DEFAULT "{}" NOT NULL,
+					[[created]]        TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_changes_collectionId on {{_changes}} ([[collectionId]]);
+				CREATE INDEX IF NOT EXISTS idx_changes_collectionName on {{_changes}} ([[collectionName]]);
+				CREATE INDEX IF NOT EXISTS idx_changes_created on {{_changes}} ([[created]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_changes").Execute()
+			return err
+		},
+	})
+}
@@ -0,0 +1,36 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_listPresets}} (
+					[[id]]                 TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[collectionRef]]      TEXT DEFAULT "" NOT NULL,
+					[[ownerCollectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[ownerRef]]           TEXT DEFAULT "" NOT NULL,
+					[[name]]               TEXT DEFAULT "" NOT NULL,
+					[[filter]]             TEXT DEFAULT "" NOT NULL,
+					[[sort]]               TEXT DEFAULT "" NOT NULL,
+					[[fields]]             TEXT DEFAULT "" NOT NULL,
+					[[expand]]             TEXT DEFAULT "" NOT NULL,
+					[[created]]            TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]            TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_listPresets_name on {{_listPresets}} ([[collectionRef]], [[ownerCollectionRef]], [[ownerRef]], [[name]]);
+				CREATE INDEX IF NOT EXISTS idx_listPresets_owner on {{_listPresets}} ([[ownerCollectionRef]], [[ownerRef]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_listPresets").Execute()
+			return err
+		},
+	})
+}
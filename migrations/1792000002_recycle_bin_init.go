@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_recycleBin}} (
+					[[id]]             TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[collectionId]]   TEXT DEFAULT "" NOT NULL,
+					[[collectionName]] TEXT DEFAULT "" NOT NULL,
+					[[recordId]]       TEXT DEFAULT "" NOT NULL,
+					[[data]]           JSON DEFAULT "{}" NOT NULL,
+					[[files]]          JSON DEFAULT "[]" NOT NULL,
+					[[created]]        TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_recycleBin_collectionId on {{_recycleBin}} ([[collectionId]]);
+				CREATE INDEX IF NOT EXISTS idx_recycleBin_recordId on {{_recycleBin}} ([[recordId]]);
+				CREATE INDEX IF NOT EXISTS idx_recycleBin_created on {{_recycleBin}} ([[created]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_recycleBin").Execute()
+			return err
+		},
+	})
+}
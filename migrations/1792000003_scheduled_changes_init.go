@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_scheduledChanges}} (
+					[[id]]            TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[collectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[recordRef]]     TEXT DEFAULT "" NOT NULL,
+					[[data]]          JSON DEFAULT "{}" NOT NULL,
+					[[scheduledAt]]   TEXT DEFAULT "" NOT NULL,
+					[[error]]         TEXT DEFAULT "" NOT NULL,
+					[[created]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_scheduledChanges_record on {{_scheduledChanges}} ([[collectionRef]], [[recordRef]]);
+				CREATE INDEX IF NOT EXISTS idx_scheduledChanges_scheduledAt on {{_scheduledChanges}} ([[scheduledAt]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_scheduledChanges").Execute()
+			return err
+		},
+	})
+}
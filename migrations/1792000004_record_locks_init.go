@@ -0,0 +1,34 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_recordLocks}} (
+					[[id]]                 TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[collectionRef]]      TEXT DEFAULT "" NOT NULL,
+					[[recordRef]]          TEXT DEFAULT "" NOT NULL,
+					[[ownerCollectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[ownerRef]]           TEXT DEFAULT "" NOT NULL,
+					[[expires]]            TEXT DEFAULT "" NOT NULL,
+					[[created]]            TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]            TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_recordLocks_record on {{_recordLocks}} ([[collectionRef]], [[recordRef]]);
+				CREATE INDEX IF NOT EXISTS idx_recordLocks_owner on {{_recordLocks}} ([[ownerCollectionRef]], [[ownerRef]]);
+				CREATE INDEX IF NOT EXISTS idx_recordLocks_expires on {{_recordLocks}} ([[expires]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_recordLocks").Execute()
+			return err
+		},
+	})
+}
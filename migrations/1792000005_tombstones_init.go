@@ -0,0 +1,30 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_tombstones}} (
+					[[collectionId]]   TEXT NOT NULL,
+					[[collectionName]] TEXT DEFAULT "" NOT NULL,
+					[[recordId]]       TEXT NOT NULL,
+					[[deletedAt]]      TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					PRIMARY KEY ([[collectionId]], [[recordId]])
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_tombstones_collectionId_deletedAt on {{_tombstones}} ([[collectionId]], [[deletedAt]]);
+				CREATE INDEX IF NOT EXISTS idx_tombstones_deletedAt on {{_tombstones}} ([[deletedAt]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_tombstones").Execute()
+			return err
+		},
+	})
+}
@@ -0,0 +1,31 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_mailQueue}} (
+					[[id]]       TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[message]]  JSON DEFAULT "{}" NOT NULL,
+					[[status]]   TEXT DEFAULT "pending" NOT NULL,
+					[[attempts]] INTEGER DEFAULT 0 NOT NULL,
+					[[error]]    TEXT DEFAULT "" NOT NULL,
+					[[created]]  TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]  TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_mailQueue_status_created on {{_mailQueue}} ([[status]], [[created]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_mailQueue").Execute()
+			return err
+		},
+	})
+}
@@ -0,0 +1,52 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// add the hidden _externalAuths OAuth2 tokens fields (if not already)
+func init() {
+	core.SystemMigrations.Register(func(txApp core.App) error {
+		collection, err := txApp.FindCollectionByNameOrId(core.CollectionNameExternalAuths)
+		if err != nil {
+			return err
+		}
+
+		if collection.Fields.GetByName("accessToken") == nil {
+			collection.Fields.Add(&core.TextField{
+				Name:   "accessToken",
+				System: true,
+				Hidden: true,
+			})
+		}
+
+		if collection.Fields.GetByName("refreshToken") == nil {
+			collection.Fields.Add(&core.TextField{
+				Name:   "refreshToken",
+				System: true,
+				Hidden: true,
+			})
+		}
+
+		if collection.Fields.GetByName("expiry") == nil {
+			collection.Fields.Add(&core.DateField{
+				Name:   "expiry",
+				System: true,
+				Hidden: true,
+			})
+		}
+
+		return txApp.Save(collection)
+	}, func(txApp core.App) error {
+		collection, err := txApp.FindCollectionByNameOrId(core.CollectionNameExternalAuths)
+		if err != nil {
+			return err
+		}
+
+		collection.Fields.RemoveByName("accessToken")
+		collection.Fields.RemoveByName("refreshToken")
+		collection.Fields.RemoveByName("expiry")
+
+		return txApp.Save(collection)
+	})
+}
@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_usage}} (
+					[[id]]      TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[tenant]]  TEXT NOT NULL,
+					[[unit]]    TEXT NOT NULL,
+					[[period]]  TEXT NOT NULL,
+					[[value]]   INTEGER DEFAULT 0 NOT NULL,
+					[[created]] TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]] TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_usage_tenant_unit_period on {{_usage}} ([[tenant]], [[unit]], [[period]]);
+				CREATE INDEX IF NOT EXISTS idx_usage_period on {{_usage}} ([[period]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_usage").Execute()
+			return err
+		},
+	})
+}
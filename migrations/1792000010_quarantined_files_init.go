@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_quarantinedFiles}} (
+					[[id]]            TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[collectionRef]] TEXT NOT NULL,
+					[[recordRef]]     TEXT NOT NULL,
+					[[fieldName]]     TEXT NOT NULL,
+					[[filename]]      TEXT NOT NULL,
+					[[reason]]        TEXT DEFAULT "" NOT NULL,
+					[[created]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_quarantinedFiles_file on {{_quarantinedFiles}} ([[collectionRef]], [[recordRef]], [[filename]]);
+				CREATE INDEX IF NOT EXISTS idx_quarantinedFiles_created on {{_quarantinedFiles}} ([[created]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_quarantinedFiles").Execute()
+			return err
+		},
+	})
+}
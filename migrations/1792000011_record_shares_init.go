@@ -0,0 +1,37 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_recordShares}} (
+					[[id]]                 TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[collectionRef]]      TEXT DEFAULT "" NOT NULL,
+					[[recordRef]]          TEXT DEFAULT "" NOT NULL,
+					[[ownerCollectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[ownerRef]]           TEXT DEFAULT "" NOT NULL,
+					[[tokenHash]]          TEXT DEFAULT "" NOT NULL,
+					[[scope]]              TEXT DEFAULT "" NOT NULL,
+					[[expires]]            TEXT DEFAULT "" NOT NULL,
+					[[created]]            TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]            TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_recordShares_tokenHash on {{_recordShares}} ([[tokenHash]]);
+				CREATE INDEX IF NOT EXISTS idx_recordShares_record on {{_recordShares}} ([[collectionRef]], [[recordRef]]);
+				CREATE INDEX IF NOT EXISTS idx_recordShares_owner on {{_recordShares}} ([[ownerCollectionRef]], [[ownerRef]]);
+				CREATE INDEX IF NOT EXISTS idx_recordShares_expires on {{_recordShares}} ([[expires]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_recordShares").Execute()
+			return err
+		},
+	})
+}
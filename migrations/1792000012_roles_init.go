@@ -0,0 +1,46 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_roles}} (
+					[[id]]          TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[name]]        TEXT DEFAULT "" NOT NULL,
+					[[description]] TEXT DEFAULT "" NOT NULL,
+					[[permissions]] JSON DEFAULT "[]" NOT NULL,
+					[[created]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_roles_name on {{_roles}} ([[name]]);
+
+				CREATE TABLE IF NOT EXISTS {{_roleAssignments}} (
+					[[id]]            TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[roleRef]]       TEXT DEFAULT "" NOT NULL,
+					[[collectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[recordRef]]     TEXT DEFAULT "" NOT NULL,
+					[[created]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_roleAssignments_unique on {{_roleAssignments}} ([[collectionRef]], [[recordRef]], [[roleRef]]);
+				CREATE INDEX IF NOT EXISTS idx_roleAssignments_roleRef on {{_roleAssignments}} ([[roleRef]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			if _, err := txApp.DB().DropTable("_roleAssignments").Execute(); err != nil {
+				return err
+			}
+
+			_, err := txApp.DB().DropTable("_roles").Execute()
+			return err
+		},
+	})
+}
@@ -0,0 +1,61 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_teams}} (
+					[[id]]      TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[name]]    TEXT DEFAULT "" NOT NULL,
+					[[created]] TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]] TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE TABLE IF NOT EXISTS {{_teamMemberships}} (
+					[[id]]            TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[teamRef]]       TEXT DEFAULT "" NOT NULL,
+					[[collectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[recordRef]]     TEXT DEFAULT "" NOT NULL,
+					[[role]]          TEXT DEFAULT "" NOT NULL,
+					[[created]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_teamMemberships_unique on {{_teamMemberships}} ([[teamRef]], [[collectionRef]], [[recordRef]]);
+				CREATE INDEX IF NOT EXISTS idx_teamMemberships_record on {{_teamMemberships}} ([[collectionRef]], [[recordRef]]);
+
+				CREATE TABLE IF NOT EXISTS {{_teamInvitations}} (
+					[[id]]                   TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[teamRef]]              TEXT DEFAULT "" NOT NULL,
+					[[email]]                TEXT DEFAULT "" NOT NULL,
+					[[role]]                 TEXT DEFAULT "" NOT NULL,
+					[[inviterCollectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[inviterRef]]           TEXT DEFAULT "" NOT NULL,
+					[[tokenHash]]            TEXT DEFAULT "" NOT NULL,
+					[[expires]]              TEXT DEFAULT "" NOT NULL,
+					[[created]]              TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]              TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_teamInvitations_tokenHash on {{_teamInvitations}} ([[tokenHash]]);
+				CREATE INDEX IF NOT EXISTS idx_teamInvitations_teamRef on {{_teamInvitations}} ([[teamRef]]);
+				CREATE INDEX IF NOT EXISTS idx_teamInvitations_expires on {{_teamInvitations}} ([[expires]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			for _, table := range []string{"_teamInvitations", "_teamMemberships", "_teams"} {
+				if _, err := txApp.DB().DropTable(table).Execute(); err != nil {
+					return err
+				}
+			}
+
+			return nil
+		},
+	})
+}
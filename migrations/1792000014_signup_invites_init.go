@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_signupInvites}} (
+					[[id]]            TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[collectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[email]]         TEXT DEFAULT "" NOT NULL,
+					[[maxUses]]       INTEGER DEFAULT 1 NOT NULL,
+					[[uses]]          INTEGER DEFAULT 0 NOT NULL,
+					[[roles]]         JSON DEFAULT "[]" NOT NULL,
+					[[teamRef]]       TEXT DEFAULT "" NOT NULL,
+					[[teamRole]]      TEXT DEFAULT "" NOT NULL,
+					[[tokenHash]]     TEXT DEFAULT "" NOT NULL,
+					[[expires]]       TEXT DEFAULT "" NOT NULL,
+					[[created]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_signupInvites_tokenHash on {{_signupInvites}} ([[tokenHash]]);
+				CREATE INDEX IF NOT EXISTS idx_signupInvites_collectionRef on {{_signupInvites}} ([[collectionRef]]);
+				CREATE INDEX IF NOT EXISTS idx_signupInvites_expires on {{_signupInvites}} ([[expires]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_signupInvites").Execute()
+			return err
+		},
+	})
+}
@@ -0,0 +1,16 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// add the _changes record payload schema version column
+func init() {
+	core.SystemMigrations.Register(func(txApp core.App) error {
+		_, err := txApp.DB().NewQuery("ALTER TABLE {{_changes}} ADD COLUMN [[version]] INTEGER DEFAULT 1 NOT NULL").Execute()
+		return err
+	}, func(txApp core.App) error {
+		_, err := txApp.DB().NewQuery("ALTER TABLE {{_changes}} DROP COLUMN [[version]]").Execute()
+		return err
+	})
+}
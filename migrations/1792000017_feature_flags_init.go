@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_featureFlags}} (
+					[[id]]          TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[key]]         TEXT DEFAULT "" NOT NULL,
+					[[description]] TEXT DEFAULT "" NOT NULL,
+					[[enabled]]     BOOLEAN DEFAULT FALSE NOT NULL,
+					[[rule]]        TEXT DEFAULT "" NOT NULL,
+					[[rollout]]     INTEGER DEFAULT 100 NOT NULL,
+					[[variants]]    JSON DEFAULT "[]" NOT NULL,
+					[[created]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_featureFlags_key on {{_featureFlags}} ([[key]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_featureFlags").Execute()
+			return err
+		},
+	})
+}
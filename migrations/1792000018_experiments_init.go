@@ -0,0 +1,47 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_experiments}} (
+					[[id]]          TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[key]]         TEXT DEFAULT "" NOT NULL,
+					[[description]] TEXT DEFAULT "" NOT NULL,
+					[[active]]      BOOLEAN DEFAULT FALSE NOT NULL,
+					[[flagKey]]     TEXT DEFAULT "" NOT NULL,
+					[[variants]]    JSON DEFAULT "[]" NOT NULL,
+					[[created]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_experiments_key on {{_experiments}} ([[key]]);
+
+				CREATE TABLE IF NOT EXISTS {{_experimentAssignments}} (
+					[[id]]            TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[experimentRef]] TEXT DEFAULT "" NOT NULL,
+					[[subject]]       TEXT DEFAULT "" NOT NULL,
+					[[variant]]       TEXT DEFAULT "" NOT NULL,
+					[[created]]       TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_experimentAssignments_unique on {{_experimentAssignments}} ([[experimentRef]], [[subject]]);
+				CREATE INDEX IF NOT EXISTS idx_experimentAssignments_subject on {{_experimentAssignments}} ([[subject]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			if _, err := txApp.DB().DropTable("_experimentAssignments").Execute(); err != nil {
+				return err
+			}
+
+			_, err := txApp.DB().DropTable("_experiments").Execute()
+			return err
+		},
+	})
+}
@@ -0,0 +1,40 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_digests}} (
+					[[id]]                      TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[name]]                    TEXT DEFAULT "" NOT NULL,
+					[[active]]                  BOOLEAN DEFAULT FALSE NOT NULL,
+					[[schedule]]                TEXT DEFAULT "" NOT NULL,
+					[[collectionRef]]           TEXT DEFAULT "" NOT NULL,
+					[[filter]]                  TEXT DEFAULT "" NOT NULL,
+					[[sort]]                    TEXT DEFAULT "" NOT NULL,
+					[[maxItems]]                INTEGER DEFAULT 50 NOT NULL,
+					[[recipientsCollectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[recipientsFilter]]        TEXT DEFAULT "" NOT NULL,
+					[[subject]]                 TEXT DEFAULT "" NOT NULL,
+					[[body]]                    TEXT DEFAULT "" NOT NULL,
+					[[itemTemplate]]            TEXT DEFAULT "" NOT NULL,
+					[[lastRun]]                 TEXT DEFAULT "" NOT NULL,
+					[[created]]                 TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]                 TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_digests_name on {{_digests}} ([[name]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_digests").Execute()
+			return err
+		},
+	})
+}
@@ -0,0 +1,33 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_redirects}} (
+					[[id]]      TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[path]]    TEXT DEFAULT "" NOT NULL,
+					[[target]]  TEXT DEFAULT "" NOT NULL,
+					[[status]]  INTEGER DEFAULT 302 NOT NULL,
+					[[hits]]    INTEGER DEFAULT 0 NOT NULL,
+					[[lastHit]] TEXT DEFAULT "" NOT NULL,
+					[[expires]] TEXT DEFAULT "" NOT NULL,
+					[[created]] TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]] TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_redirects_path on {{_redirects}} ([[path]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_redirects").Execute()
+			return err
+		},
+	})
+}
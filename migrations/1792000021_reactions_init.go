@@ -0,0 +1,32 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_reactions}} (
+					[[id]]                TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[collectionRef]]     TEXT DEFAULT "" NOT NULL,
+					[[recordRef]]         TEXT DEFAULT "" NOT NULL,
+					[[authCollectionRef]] TEXT DEFAULT "" NOT NULL,
+					[[authRef]]           TEXT DEFAULT "" NOT NULL,
+					[[type]]              TEXT DEFAULT "" NOT NULL,
+					[[created]]           TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE UNIQUE INDEX IF NOT EXISTS idx_reactions_unique on {{_reactions}} ([[collectionRef]], [[recordRef]], [[authCollectionRef]], [[authRef]], [[type]]);
+				CREATE INDEX IF NOT EXISTS idx_reactions_auth on {{_reactions}} ([[authCollectionRef]], [[authRef]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_reactions").Execute()
+			return err
+		},
+	})
+}
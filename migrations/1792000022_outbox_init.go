@@ -0,0 +1,35 @@
+package migrations
+
+import (
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func init() {
+	core.SystemMigrations.Add(&core.Migration{
+		Up: func(txApp core.App) error {
+			_, execErr := txApp.DB().NewQuery(`
+				CREATE TABLE IF NOT EXISTS {{_outbox}} (
+					[[id]]          TEXT PRIMARY KEY DEFAULT ('r'||lower(hex(randomblob(7)))) NOT NULL,
+					[[topic]]       TEXT DEFAULT "" NOT NULL,
+					[[payload]]     JSON DEFAULT "null" NOT NULL,
+					[[status]]      TEXT DEFAULT "pending" NOT NULL,
+					[[attempts]]    INTEGER DEFAULT 0 NOT NULL,
+					[[maxAttempts]] INTEGER DEFAULT 0 NOT NULL,
+					[[error]]       TEXT DEFAULT "" NOT NULL,
+					[[nextAttempt]] TEXT DEFAULT "" NOT NULL,
+					[[created]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL,
+					[[updated]]     TEXT DEFAULT (strftime('%Y-%m-%d %H:%M:%fZ')) NOT NULL
+				);
+
+				CREATE INDEX IF NOT EXISTS idx_outbox_status_nextAttempt on {{_outbox}} ([[status]], [[nextAttempt]]);
+				CREATE INDEX IF NOT EXISTS idx_outbox_topic on {{_outbox}} ([[topic]]);
+			`).Execute()
+
+			return execErr
+		},
+		Down: func(txApp core.App) error {
+			_, err := txApp.DB().DropTable("_outbox").Execute()
+			return err
+		},
+	})
+}
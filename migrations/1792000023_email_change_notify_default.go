@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+// enable the old email notification for the auth collections created
+// before the emailChange options were introduced (consistent with the new collections default)
+func init() {
+	core.SystemMigrations.Register(func(txApp core.App) error {
+		var ids []string
+
+		err := txApp.DB().Select("id").
+			From("_collections").
+			AndWhere(dbx.HashExp{"type": core.CollectionTypeAuth}).
+			AndWhere(dbx.NewExp("json_extract([[options]], '$.emailChange') IS NULL")).
+			Column(&ids)
+		if err != nil {
+			return err
+		}
+
+		for _, id := range ids {
+			collection, err := txApp.FindCollectionByNameOrId(id)
+			if err != nil {
+				return err
+			}
+
+			collection.EmailChange.NotifyOldEmail = true
+
+			if err := txApp.SaveNoValidate(collection); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}, nil)
+}
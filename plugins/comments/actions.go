@@ -0,0 +1,172 @@
+package comments
+
+import (
+	"errors"
+	"net/http"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+type reportForm struct {
+	Reason string `form:"reason" json:"reason"`
+}
+
+// reportHandler registers a report for the path comment
+// and moves it back to the moderation queue once the reports threshold is reached.
+//
+// Reporting the same comment multiple times is a no-op.
+func (p *plugin) reportHandler(e *core.RequestEvent) error {
+	comment, err := p.findVisibleComment(e)
+	if err != nil {
+		return e.NotFoundError("", err)
+	}
+
+	if comment.GetString("author") == e.Auth.Id {
+		return e.BadRequestError("You cannot report your own comment.", nil)
+	}
+
+	form := &reportForm{}
+	if err := e.BindBody(form); err != nil {
+		return e.BadRequestError("Failed to load the submitted data due to invalid formatting.", err)
+	}
+
+	err = e.App.RunInTransaction(func(txApp core.App) error {
+		existing, _ := txApp.FindFirstRecordByFilter(
+			p.config.ReportsCollection,
+			"comment = {:comment} && reporter = {:reporter}",
+			dbx.Params{"comment": comment.Id, "reporter": e.Auth.Id},
+		)
+		if existing != nil {
+			return nil // already reported
+		}
+
+		reports, err := txApp.FindCachedCollectionByNameOrId(p.config.ReportsCollection)
+		if err != nil {
+			return err
+		}
+
+		report := core.NewRecord(reports)
+		report.Set("comment", comment.Id)
+		report.Set("reporter", e.Auth.Id)
+		report.Set("reason", form.Reason)
+		if err := txApp.Save(report); err != nil {
+			return err
+		}
+
+		total, err := txApp.CountRecords(reports, dbx.HashExp{"comment": comment.Id})
+		if err != nil {
+			return err
+		}
+
+		comment.Set("reportsCount", total)
+		if total >= int64(p.config.ReportsThreshold) && comment.GetString("status") == StatusApproved {
+			comment.Set("status", StatusPending)
+		}
+
+		return txApp.Save(comment)
+	})
+	if err != nil {
+		var validationErrors validation.Errors
+		if errors.As(err, &validationErrors) {
+			return e.BadRequestError("Failed to report comment.", validationErrors)
+		}
+
+		return e.BadRequestError("Failed to report comment.", err)
+	}
+
+	return e.NoContent(http.StatusNoContent)
+}
+
+// statusHandler returns a moderators only handler that changes
+// the path comment status to the specified one.
+//
+// Approving a comment also clears its reports.
+func (p *plugin) statusHandler(status string) func(e *core.RequestEvent) error {
+	return func(e *core.RequestEvent) error {
+		comment, err := e.App.FindRecordById(p.config.Collection, e.Request.PathValue("id"))
+		if err != nil {
+			return e.NotFoundError("", err)
+		}
+
+		canModerate, err := p.canModerate(e, comment)
+		if err != nil || !canModerate {
+			return e.ForbiddenError("Only moderators can perform this action.", err)
+		}
+
+		err = e.App.RunInTransaction(func(txApp core.App) error {
+			comment.Set("status", status)
+
+			if status == StatusApproved {
+				reports, err := txApp.FindCachedCollectionByNameOrId(p.config.ReportsCollection)
+				if err != nil {
+					return err
+				}
+
+				_, err = txApp.DB().Delete(reports.Name, dbx.HashExp{"comment": comment.Id}).Execute()
+				if err != nil {
+					return err
+				}
+
+				comment.Set("reportsCount", 0)
+			}
+
+			return txApp.Save(comment)
+		})
+		if err != nil {
+			return e.BadRequestError("Failed to update comment status.", err)
+		}
+
+		if err := apis.EnrichRecord(e, comment); err != nil {
+			return e.InternalServerError("Failed to enrich comment.", err)
+		}
+
+		return e.JSON(http.StatusOK, comment)
+	}
+}
+
+// findVisibleComment loads the path comment and ensures
+// that it is accessible by the current request auth.
+func (p *plugin) findVisibleComment(e *core.RequestEvent) (*core.Record, error) {
+	comment, err := e.App.FindRecordById(p.config.Collection, e.Request.PathValue("id"))
+	if err != nil {
+		return nil, err
+	}
+
+	info, err := e.RequestInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	canAccess, err := e.App.CanAccessRecord(comment, info, comment.Collection().ViewRule, core.CollectionPolicyActionView)
+	if err != nil {
+		return nil, err
+	}
+
+	if !canAccess {
+		return nil, errors.New("the comment is not accessible")
+	}
+
+	return comment, nil
+}
+
+// canModerate checks whether the current request auth is a superuser
+// or satisfies the configured ModeratorRule.
+func (p *plugin) canModerate(e *core.RequestEvent, comment *core.Record) (bool, error) {
+	if e.HasSuperuserAuth() {
+		return true, nil
+	}
+
+	if p.config.ModeratorRule == "" || e.Auth == nil {
+		return false, nil
+	}
+
+	info, err := e.RequestInfo()
+	if err != nil {
+		return false, err
+	}
+
+	return e.App.CanAccessRecord(comment, info, &p.config.ModeratorRule)
+}
@@ -0,0 +1,312 @@
+package comments
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func createReport(t testing.TB, app core.App, commentId string, reporterId string) {
+	collection, err := app.FindCollectionByNameOrId(defaultReportsCollection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	report := core.NewRecord(collection)
+	report.Set("comment", commentId)
+	report.Set("reporter", reporterId)
+	if err := app.Save(report); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func countReports(t testing.TB, app core.App, commentId string) int64 {
+	collection, err := app.FindCollectionByNameOrId(defaultReportsCollection)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	total, err := app.CountRecords(collection, dbx.HashExp{"comment": commentId})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return total
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "guest",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/comments/records/comment00000001/report",
+			TestAppFactory: testAppFactory(Config{}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusApproved)
+			},
+			ExpectedStatus:  401,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "missing comment",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/missing/report",
+			Headers: map[string]string{
+				"Authorization": user2Token,
+			},
+			TestAppFactory:  testAppFactory(Config{}),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "non-visible comment",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/comment00000001/report",
+			Headers: map[string]string{
+				"Authorization": user2Token,
+			},
+			TestAppFactory: testAppFactory(Config{}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusHidden)
+			},
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "own comment",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/comment00000001/report",
+			Headers: map[string]string{
+				"Authorization": user1Token,
+			},
+			TestAppFactory: testAppFactory(Config{}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusApproved)
+			},
+			ExpectedStatus:  400,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "below the reports threshold",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/comment00000001/report",
+			Body:   strings.NewReader(`{"reason":"spam"}`),
+			Headers: map[string]string{
+				"Authorization": user2Token,
+			},
+			TestAppFactory: testAppFactory(Config{}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusApproved)
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnRecordCreate": 1,
+				"OnRecordUpdate": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				comment := findComment(t, app, "comment00000001")
+
+				if v := comment.GetInt("reportsCount"); v != 1 {
+					t.Fatalf("Expected reportsCount 1, got %d", v)
+				}
+
+				if v := comment.GetString("status"); v != StatusApproved {
+					t.Fatalf("Expected status %q, got %q", StatusApproved, v)
+				}
+
+				report, err := app.FindFirstRecordByData(defaultReportsCollection, "comment", comment.Id)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if v := report.GetString("reason"); v != "spam" {
+					t.Fatalf("Expected reason spam, got %q", v)
+				}
+			},
+		},
+		{
+			Name:   "already reported",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/comment00000001/report",
+			Headers: map[string]string{
+				"Authorization": user2Token,
+			},
+			TestAppFactory: testAppFactory(Config{ReportsThreshold: 1}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusApproved)
+				createReport(t, app, "comment00000001", user2Id)
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{"*": 0},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := countReports(t, app, "comment00000001"); v != 1 {
+					t.Fatalf("Expected 1 report, got %d", v)
+				}
+			},
+		},
+		{
+			Name:   "reaching the reports threshold",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/comment00000001/report",
+			Headers: map[string]string{
+				"Authorization": user2Token,
+			},
+			TestAppFactory: testAppFactory(Config{ReportsThreshold: 2}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusApproved)
+				createReport(t, app, "comment00000001", "bgs820n361vj1qd")
+			},
+			ExpectedStatus: 204,
+			ExpectedEvents: map[string]int{
+				"OnRecordCreate": 1,
+				"OnRecordUpdate": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				comment := findComment(t, app, "comment00000001")
+
+				if v := comment.GetInt("reportsCount"); v != 2 {
+					t.Fatalf("Expected reportsCount 2, got %d", v)
+				}
+
+				if v := comment.GetString("status"); v != StatusPending {
+					t.Fatalf("Expected status %q, got %q", StatusPending, v)
+				}
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
+
+func TestModerationActions(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []tests.ApiScenario{
+		{
+			Name:           "approve as guest",
+			Method:         http.MethodPost,
+			URL:            "/api/collections/comments/records/comment00000001/approve",
+			TestAppFactory: testAppFactory(Config{}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusPending)
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "approve as regular user",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/comment00000001/approve",
+			Headers: map[string]string{
+				"Authorization": user1Token,
+			},
+			TestAppFactory: testAppFactory(Config{}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusPending)
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "approve as user not matching the ModeratorRule",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/comment00000001/approve",
+			Headers: map[string]string{
+				"Authorization": user1Token,
+			},
+			TestAppFactory: testAppFactory(Config{ModeratorRule: `@request.auth.verified = true`}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusPending)
+			},
+			ExpectedStatus:  403,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "approve missing comment",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/missing/approve",
+			Headers: map[string]string{
+				"Authorization": superuserToken,
+			},
+			TestAppFactory:  testAppFactory(Config{}),
+			ExpectedStatus:  404,
+			ExpectedContent: []string{`"data":{}`},
+			ExpectedEvents:  map[string]int{"*": 0},
+		},
+		{
+			Name:   "approve as superuser",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/comment00000001/approve",
+			Headers: map[string]string{
+				"Authorization": superuserToken,
+			},
+			TestAppFactory: testAppFactory(Config{}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				comment := createComment(t, app, "comment00000001", user1Id, "", StatusPending)
+				comment.Set("reportsCount", 1)
+				if err := app.Save(comment); err != nil {
+					t.Fatal(err)
+				}
+				createReport(t, app, comment.Id, user2Id)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"comment00000001"`,
+				`"status":"approved"`,
+				`"reportsCount":0`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordUpdate": 1,
+				"OnRecordEnrich": 1,
+			},
+			AfterTestFunc: func(t testing.TB, app *tests.TestApp, res *http.Response) {
+				if v := countReports(t, app, "comment00000001"); v != 0 {
+					t.Fatalf("Expected the comment reports to be deleted, found %d", v)
+				}
+			},
+		},
+		{
+			Name:   "hide as user matching the ModeratorRule",
+			Method: http.MethodPost,
+			URL:    "/api/collections/comments/records/comment00000001/hide",
+			Headers: map[string]string{
+				"Authorization": user2Token,
+			},
+			TestAppFactory: testAppFactory(Config{ModeratorRule: `@request.auth.verified = true`}),
+			BeforeTestFunc: func(t testing.TB, app *tests.TestApp, e *core.ServeEvent) {
+				createComment(t, app, "comment00000001", user1Id, "", StatusApproved)
+			},
+			ExpectedStatus: 200,
+			ExpectedContent: []string{
+				`"id":"comment00000001"`,
+				`"status":"hidden"`,
+			},
+			NotExpectedContent: []string{
+				`"reportsCount"`,
+			},
+			ExpectedEvents: map[string]int{
+				"OnRecordUpdate": 1,
+				"OnRecordEnrich": 1,
+			},
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario.Test(t)
+	}
+}
@@ -0,0 +1,327 @@
+// Package comments implements a plugin with ready to use threaded comments
+// and moderation primitives.
+//
+// The plugin creates (if missing) a comments collection with carefully
+// scoped API rules (authors can manage only their own comments and
+// the moderation fields are server-controlled) and a reports collection
+// which is accessible only through the plugin endpoints:
+//
+//	POST /api/collections/{comments}/records/{id}/report  - report a visible comment (auth records only)
+//	POST /api/collections/{comments}/records/{id}/approve - approve a comment (moderators only)
+//	POST /api/collections/{comments}/records/{id}/hide    - hide a comment (moderators only)
+//
+// Comments with enough reports are moved back to the moderation queue
+// (aka. "pending" status). The comment changes are broadcasted
+// with the regular realtime record subscriptions (e.g. "comments/*").
+//
+// Example usage:
+//
+//	comments.MustRegister(app, comments.Config{
+//		RequireApproval: true,
+//		ModeratorRule:   `@request.auth.role = "moderator"`,
+//	})
+package comments
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// The supported comment statuses.
+const (
+	StatusPending  = "pending"
+	StatusApproved = "approved"
+	StatusHidden   = "hidden"
+)
+
+const (
+	defaultCollection        = "comments"
+	defaultReportsCollection = "comment_reports"
+	defaultAuthCollection    = "users"
+	defaultMaxDepth          = 5
+	defaultReportsThreshold  = 3
+	defaultRateLimitMax      = 5
+	defaultRateLimitWindow   = time.Minute
+)
+
+// Config defines the config options of the comments plugin.
+//
+// NB! This plugin is considered experimental and its config options may change in the future.
+type Config struct {
+	// Collection specifies the name of the comments collection (default to "comments").
+	//
+	// The collection is created automatically if missing.
+	Collection string
+
+	// ReportsCollection specifies the name of the comment reports collection
+	// (default to "comment_reports").
+	//
+	// The collection is created automatically if missing.
+	ReportsCollection string
+
+	// AuthCollection specifies the name of the auth collection
+	// which records could comment (default to "users").
+	AuthCollection string
+
+	// RequireApproval specifies whether the new (and edited)
+	// comments should be approved by a moderator before becoming public.
+	RequireApproval bool
+
+	// ModeratorRule is an optional @request.* rule that grants moderator
+	// permissions to non-superusers (e.g. `@request.auth.role = "moderator"`).
+	//
+	// Superusers are always allowed to moderate.
+	ModeratorRule string
+
+	// MaxDepth specifies the max allowed replies nesting level (default to 5).
+	MaxDepth int
+
+	// ReportsThreshold specifies the number of reports after which
+	// an approved comment is moved back to the moderation queue (default to 3).
+	ReportsThreshold int
+
+	// RateLimitMax specifies the max allowed new comments per
+	// author for the RateLimitWindow duration (default to 5).
+	RateLimitMax int
+
+	// RateLimitWindow specifies the new comments rate limit
+	// window duration (default to 1 minute).
+	RateLimitWindow time.Duration
+}
+
+// MustRegister registers the comments plugin to the provided app instance
+// and panic if it fails.
+func MustRegister(app core.App, config Config) {
+	if err := Register(app, config); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the comments plugin to the provided app instance.
+func Register(app core.App, config Config) error {
+	p := &plugin{app: app, config: config}
+
+	if p.config.Collection == "" {
+		p.config.Collection = defaultCollection
+	}
+
+	if p.config.ReportsCollection == "" {
+		p.config.ReportsCollection = defaultReportsCollection
+	}
+
+	if p.config.Collection == p.config.ReportsCollection {
+		return errors.New("the comments Collection and ReportsCollection must be different")
+	}
+
+	if p.config.AuthCollection == "" {
+		p.config.AuthCollection = defaultAuthCollection
+	}
+
+	if p.config.MaxDepth <= 0 {
+		p.config.MaxDepth = defaultMaxDepth
+	}
+
+	if p.config.ReportsThreshold <= 0 {
+		p.config.ReportsThreshold = defaultReportsThreshold
+	}
+
+	if p.config.RateLimitMax <= 0 {
+		p.config.RateLimitMax = defaultRateLimitMax
+	}
+
+	if p.config.RateLimitWindow <= 0 {
+		p.config.RateLimitWindow = defaultRateLimitWindow
+	}
+
+	p.app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		if err := p.ensureSchema(e.App); err != nil {
+			return err
+		}
+
+		sub := e.Router.Group("/api/collections/" + p.config.Collection + "/records/{id}")
+		sub.POST("/report", p.reportHandler).Bind(apis.RequireAuth(p.config.AuthCollection))
+		sub.POST("/approve", p.statusHandler(StatusApproved))
+		sub.POST("/hide", p.statusHandler(StatusHidden))
+
+		return e.Next()
+	})
+
+	p.app.OnRecordCreate(p.config.Collection).BindFunc(p.onCommentCreate)
+	p.app.OnRecordCreateRequest(p.config.Collection).BindFunc(p.onCommentCreateRequest)
+	p.app.OnRecordUpdateRequest(p.config.Collection).BindFunc(p.onCommentUpdateRequest)
+
+	return nil
+}
+
+type plugin struct {
+	app    core.App
+	config Config
+}
+
+// ensureSchema creates the comments and the reports collections (if missing).
+func (p *plugin) ensureSchema(app core.App) error {
+	authCollection, err := app.FindCollectionByNameOrId(p.config.AuthCollection)
+	if err != nil {
+		return err
+	}
+
+	if !authCollection.IsAuth() {
+		return errors.New("the comments AuthCollection must be an auth collection")
+	}
+
+	comments, err := app.FindCollectionByNameOrId(p.config.Collection)
+	if err != nil {
+		comments = core.NewBaseCollection(p.config.Collection)
+
+		visibleRule := `status = "` + StatusApproved + `" || author = @request.auth.id`
+		if p.config.ModeratorRule != "" {
+			visibleRule += " || (" + p.config.ModeratorRule + ")"
+		}
+
+		comments.ListRule = types.Pointer(visibleRule)
+		comments.ViewRule = types.Pointer(visibleRule)
+		comments.CreateRule = types.Pointer(fmt.Sprintf(
+			`@request.auth.collectionName = "%s" && author = @request.auth.id`,
+			authCollection.Name,
+		))
+		// the thread and moderation fields are allowed to be changed only by superusers
+		comments.UpdateRule = types.Pointer(`author = @request.auth.id` +
+			` && @request.body.author:isset = false` +
+			` && @request.body.thread:isset = false` +
+			` && @request.body.parent:isset = false` +
+			` && @request.body.status:isset = false` +
+			` && @request.body.depth:isset = false` +
+			` && @request.body.reportsCount:isset = false`)
+		comments.DeleteRule = types.Pointer(`author = @request.auth.id`)
+
+		comments.Fields.Add(
+			&core.TextField{Name: "thread", Required: true, Max: 255},
+			&core.RelationField{Name: "author", CollectionId: authCollection.Id, MaxSelect: 1, Required: true, CascadeDelete: true},
+			&core.TextField{Name: "body", Required: true, Max: 10000},
+			&core.SelectField{Name: "status", Values: []string{StatusPending, StatusApproved, StatusHidden}, MaxSelect: 1, Required: true},
+			&core.NumberField{Name: "depth", OnlyInt: true},
+			&core.NumberField{Name: "reportsCount", OnlyInt: true, Hidden: true},
+			&core.AutodateField{Name: "created", OnCreate: true},
+			&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true},
+		)
+		comments.AddIndex("idx_"+comments.Name+"_thread", false, "thread, status", "")
+		comments.AddIndex("idx_"+comments.Name+"_author", false, "author, created", "")
+
+		if err := app.Save(comments); err != nil {
+			return err
+		}
+
+		// the self-referencing relation can be added only after the collection is created
+		comments.Fields.AddAt(1, &core.RelationField{Name: "parent", CollectionId: comments.Id, MaxSelect: 1, CascadeDelete: true})
+		if err := app.Save(comments); err != nil {
+			return err
+		}
+	}
+
+	if _, err := app.FindCollectionByNameOrId(p.config.ReportsCollection); err != nil {
+		// note: the reports are managed only through the plugin endpoints (or by superusers)
+		reports := core.NewBaseCollection(p.config.ReportsCollection)
+		reports.Fields.Add(
+			&core.RelationField{Name: "comment", CollectionId: comments.Id, MaxSelect: 1, Required: true, CascadeDelete: true},
+			&core.RelationField{Name: "reporter", CollectionId: authCollection.Id, MaxSelect: 1, Required: true, CascadeDelete: true},
+			&core.TextField{Name: "reason", Max: 500},
+			&core.AutodateField{Name: "created", OnCreate: true},
+		)
+		reports.AddIndex("idx_"+reports.Name+"_comment_reporter", true, "comment, reporter", "")
+
+		if err := app.Save(reports); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// onCommentCreate resolves the new comment nesting depth
+// and ensures that its parent is a visible comment from the same thread.
+func (p *plugin) onCommentCreate(e *core.RecordEvent) error {
+	e.Record.Set("depth", 0)
+
+	parentId := e.Record.GetString("parent")
+	if parentId != "" {
+		parent, err := e.App.FindRecordById(e.Record.Collection(), parentId)
+		if err != nil {
+			return validation.Errors{"parent": validation.NewError("validation_invalid_parent", "Missing parent comment.")}
+		}
+
+		if parent.GetString("thread") != e.Record.GetString("thread") {
+			return validation.Errors{"parent": validation.NewError("validation_invalid_parent", "The parent comment must be from the same thread.")}
+		}
+
+		if parent.GetString("status") != StatusApproved {
+			return validation.Errors{"parent": validation.NewError("validation_invalid_parent", "The parent comment must be approved.")}
+		}
+
+		depth := parent.GetInt("depth") + 1
+		if depth > p.config.MaxDepth {
+			return validation.Errors{"parent": validation.NewError(
+				"validation_max_depth",
+				fmt.Sprintf("The max allowed replies nesting level is %d.", p.config.MaxDepth),
+			)}
+		}
+
+		e.Record.Set("depth", depth)
+	}
+
+	return e.Next()
+}
+
+// onCommentCreateRequest rate limits the new comments of the non-superusers
+// and initializes their server-controlled moderation fields.
+func (p *plugin) onCommentCreateRequest(e *core.RecordRequestEvent) error {
+	if e.HasSuperuserAuth() {
+		return e.Next()
+	}
+
+	if e.Auth != nil {
+		since := types.NowDateTime().Add(-p.config.RateLimitWindow)
+
+		total, err := e.App.CountRecords(
+			e.Collection,
+			dbx.HashExp{"author": e.Auth.Id},
+			dbx.NewExp("[[created]] >= {:since}", dbx.Params{"since": since.String()}),
+		)
+		if err != nil {
+			return e.InternalServerError("Failed to check the comments rate limit.", err)
+		}
+
+		if total >= int64(p.config.RateLimitMax) {
+			return e.TooManyRequestsError("Too many new comments. Please try again later.", nil)
+		}
+	}
+
+	e.Record.Set("status", p.initialStatus())
+	e.Record.Set("reportsCount", 0)
+
+	return e.Next()
+}
+
+// onCommentUpdateRequest moves the edited comments of the non-superusers
+// back to the moderation queue if approval is required.
+func (p *plugin) onCommentUpdateRequest(e *core.RecordRequestEvent) error {
+	if !e.HasSuperuserAuth() && p.config.RequireApproval && e.Record.GetString("body") != e.Record.Original().GetString("body") {
+		e.Record.Set("status", StatusPending)
+	}
+
+	return e.Next()
+}
+
+func (p *plugin) initialStatus() string {
+	if p.config.RequireApproval {
+		return StatusPending
+	}
+
+	return StatusApproved
+}
@@ -0,0 +1,39 @@
+package dataimport
+
+import (
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/apis"
+	"github.com/pocketbase/pocketbase/core"
+)
+
+func (p *plugin) bindApi(e *core.ServeEvent) error {
+	e.Router.POST("/api/import/{source}", p.importHandler).Bind(apis.RequireSuperuserAuth())
+
+	return e.Next()
+}
+
+func (p *plugin) importHandler(e *core.RequestEvent) error {
+	options := Options{}
+	if err := e.BindBody(&options); err != nil {
+		return e.BadRequestError("An error occurred while loading the submitted data.", err)
+	}
+
+	files, err := e.FindUploadedFiles("file")
+	if err != nil || len(files) != 1 {
+		return e.BadRequestError("Missing or invalid import file.", err)
+	}
+
+	f, err := files[0].Reader.Open()
+	if err != nil {
+		return e.BadRequestError("Failed to read the import file.", err)
+	}
+	defer f.Close()
+
+	result, err := Import(e.App, e.Request.PathValue("source"), f, options)
+	if err != nil {
+		return e.BadRequestError("Failed to import the submitted data.", err)
+	}
+
+	return e.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,76 @@
+package dataimport
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+
+	"github.com/spf13/cobra"
+)
+
+func (p *plugin) createCommand() *cobra.Command {
+	const cmdDesc = `Supported sources are:
+- firebase-auth - the JSON output of "firebase auth:export users.json"
+- firestore     - JSON Firestore export in the "node-firestore-import-export" format
+- supabase-auth - JSON array with the Supabase "auth.users" table rows
+- supabase      - JSON array with the rows of a single Supabase table
+`
+
+	var options Options
+
+	command := &cobra.Command{
+		Use:          "import [source] [file]",
+		Short:        "Imports Firebase or Supabase data exports into a collection",
+		Long:         cmdDesc,
+		Example:      "import firebase-auth users.json --collection=users",
+		ValidArgs:    []string{SourceFirebaseAuth, SourceFirestore, SourceSupabaseAuth, SourceSupabase},
+		Args:         cobra.ExactArgs(2),
+		SilenceUsage: true,
+		RunE: func(command *cobra.Command, args []string) error {
+			source := args[0]
+			if !slices.Contains(command.ValidArgs, source) {
+				return fmt.Errorf("unsupported import source %q", source)
+			}
+
+			f, err := os.Open(args[1])
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			result, err := Import(p.app, source, f, options)
+			if err != nil {
+				return err
+			}
+
+			fmt.Printf(
+				"Imported %d records (created: %d, updated: %d, failed: %d)\n",
+				result.Total, result.Created, result.Updated, result.Failed,
+			)
+
+			if result.Failed > 0 {
+				ids := make([]string, 0, len(result.Errors))
+				for id := range result.Errors {
+					ids = append(ids, id)
+				}
+				sort.Strings(ids)
+
+				for _, id := range ids {
+					fmt.Printf("  - %s: %s\n", id, result.Errors[id])
+				}
+
+				return errors.New("some of the records failed to import")
+			}
+
+			return nil
+		},
+	}
+
+	command.Flags().StringVar(&options.Collection, "collection", "", "the name or id of the collection where to import the records")
+	command.Flags().StringVar(&options.SourceCollection, "sourceCollection", "", "the Firestore collection to import (default to --collection)")
+	command.Flags().StringVar(&options.SourceIdField, "sourceIdField", "", "optional collection field where to store the original source id")
+
+	return command
+}
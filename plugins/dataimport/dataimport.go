@@ -0,0 +1,331 @@
+// Package dataimport implements a plugin that imports Firebase and Supabase
+// data exports into existing PocketBase collections.
+//
+// The supported sources are:
+//   - firebase-auth - the JSON output of "firebase auth:export users.json"
+//   - firestore     - JSON Firestore export in the "node-firestore-import-export" format
+//   - supabase-auth - JSON array with the "auth.users" table rows
+//   - supabase      - JSON array with the rows of a single Supabase table
+//
+// The source ids are converted to valid PocketBase record ids with [ImportId]
+// so that the related records could be imported separately while preserving their relations.
+// Importing the same export multiple times updates the previously imported records.
+//
+// Supabase bcrypt password hashes are imported as they are.
+// Firebase users are created with a random password (they will have to reset it)
+// because the Firebase scrypt hashes cannot be verified by PocketBase.
+//
+// The plugin registers the "import" command and the superuser only
+// "POST /api/import/{source}" endpoint (multipart/form-data with "file" and "collection" fields).
+//
+// Example usage:
+//
+//	dataimport.MustRegister(app, app.RootCmd, dataimport.Config{})
+//
+// and then:
+//
+//	./pocketbase import firebase-auth users.json --collection=users
+//	./pocketbase import firestore export.json --collection=posts
+package dataimport
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cobra"
+)
+
+const (
+	SourceFirebaseAuth = "firebase-auth"
+	SourceFirestore    = "firestore"
+	SourceSupabaseAuth = "supabase-auth"
+	SourceSupabase     = "supabase"
+)
+
+// Config defines the config options of the dataimport plugin.
+type Config struct {
+	// DisableAPI disables the "POST /api/import/{source}" endpoint registration.
+	DisableAPI bool
+}
+
+// Options defines the options of a single import.
+type Options struct {
+	// Collection is the name or id of the collection where to import the records (required).
+	Collection string `form:"collection" json:"collection"`
+
+	// SourceCollection is the name of the Firestore collection
+	// to import from the export (default to Collection).
+	//
+	// It is used only by the firestore source.
+	SourceCollection string `form:"sourceCollection" json:"sourceCollection"`
+
+	// SourceIdField is an optional collection text field where to store
+	// the original source record id.
+	SourceIdField string `form:"sourceIdField" json:"sourceIdField"`
+}
+
+// Result defines the summary of a single import.
+type Result struct {
+	Total   int `json:"total"`
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+	Failed  int `json:"failed"`
+
+	// Errors contains the import errors keyed by the source record id.
+	Errors map[string]string `json:"errors"`
+}
+
+// MustRegister registers the dataimport plugin to the provided app instance
+// and panic if it fails.
+//
+// Example usage:
+//
+//	dataimport.MustRegister(app, app.RootCmd, dataimport.Config{})
+func MustRegister(app core.App, rootCmd *cobra.Command, config Config) {
+	if err := Register(app, rootCmd, config); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the dataimport plugin to the provided app instance.
+func Register(app core.App, rootCmd *cobra.Command, config Config) error {
+	p := &plugin{app: app, config: config}
+
+	// attach the import command
+	if rootCmd != nil {
+		rootCmd.AddCommand(p.createCommand())
+	}
+
+	if !p.config.DisableAPI {
+		p.app.OnServe().BindFunc(p.bindApi)
+	}
+
+	return nil
+}
+
+type plugin struct {
+	app    core.App
+	config Config
+}
+
+// Import imports the data from r into the options collection
+// using the specified source format.
+func Import(app core.App, source string, r io.Reader, options Options) (*Result, error) {
+	switch source {
+	case SourceFirebaseAuth:
+		return ImportFirebaseAuth(app, r, options)
+	case SourceFirestore:
+		return ImportFirestore(app, r, options)
+	case SourceSupabaseAuth:
+		return ImportSupabaseAuth(app, r, options)
+	case SourceSupabase:
+		return ImportSupabase(app, r, options)
+	default:
+		return nil, fmt.Errorf("unsupported import source %q", source)
+	}
+}
+
+var validIdRegex = regexp.MustCompile(`^[a-z0-9]{15}$`)
+
+const idAlphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// ImportId converts the provided source id into a valid default record id.
+//
+// Ids that are already valid are returned as they are, otherwise
+// a deterministic 15 characters id is generated from the source id hash
+// so that the same source id always results in the same record id.
+func ImportId(sourceId string) string {
+	if validIdRegex.MatchString(sourceId) {
+		return sourceId
+	}
+
+	hash := sha256.Sum256([]byte(sourceId))
+
+	id := make([]byte, 15)
+	for i := range id {
+		id[i] = idAlphabet[int(hash[i])%len(idAlphabet)]
+	}
+
+	return string(id)
+}
+
+// findImportCollection returns the options target collection.
+func findImportCollection(app core.App, options Options, auth bool) (*core.Collection, error) {
+	if options.Collection == "" {
+		return nil, errors.New("missing target collection")
+	}
+
+	collection, err := app.FindCollectionByNameOrId(options.Collection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find collection %q: %w", options.Collection, err)
+	}
+
+	if collection.IsView() {
+		return nil, fmt.Errorf("cannot import into view collection %q", collection.Name)
+	}
+
+	if auth && !collection.IsAuth() {
+		return nil, fmt.Errorf("%q is not an auth collection", collection.Name)
+	}
+
+	if options.SourceIdField != "" && collection.Fields.GetByName(options.SourceIdField) == nil {
+		return nil, fmt.Errorf("missing source id field %q", options.SourceIdField)
+	}
+
+	return collection, nil
+}
+
+// importRecord creates or updates the record associated with sourceId
+// and stores the result in the provided Result.
+func importRecord(
+	app core.App,
+	collection *core.Collection,
+	options Options,
+	result *Result,
+	sourceId string,
+	load func(record *core.Record) error,
+) {
+	result.Total++
+
+	err := func() error {
+		if sourceId == "" {
+			return errors.New("missing source id")
+		}
+
+		record, err := app.FindRecordById(collection, ImportId(sourceId))
+		if err != nil {
+			record = core.NewRecord(collection)
+			record.Id = ImportId(sourceId)
+		}
+
+		if options.SourceIdField != "" {
+			record.Set(options.SourceIdField, sourceId)
+		}
+
+		if err := load(record); err != nil {
+			return err
+		}
+
+		isNew := record.IsNew()
+
+		if err := app.Save(record); err != nil {
+			return err
+		}
+
+		if isNew {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+
+		return nil
+	}()
+
+	if err != nil {
+		result.Failed++
+
+		if result.Errors == nil {
+			result.Errors = map[string]string{}
+		}
+
+		if sourceId == "" {
+			sourceId = fmt.Sprintf("#%d", result.Total)
+		}
+		result.Errors[sourceId] = err.Error()
+	}
+}
+
+// loadData sets the data values with matching collection field names
+// into the provided record.
+//
+// The system id, autodate, password and file fields are ignored and
+// the relation field values are converted with [ImportId].
+func loadData(record *core.Record, data map[string]any) {
+	for _, field := range record.Collection().Fields {
+		name := field.GetName()
+
+		if name == core.FieldNameId || name == core.FieldNameTokenKey {
+			continue
+		}
+
+		switch field.Type() {
+		case core.FieldTypeAutodate, core.FieldTypePassword, core.FieldTypeFile:
+			continue
+		}
+
+		value, ok := data[name]
+		if !ok {
+			continue
+		}
+
+		if field.Type() == core.FieldTypeRelation {
+			value = importRelationValue(value)
+		}
+
+		record.Set(name, value)
+	}
+}
+
+func importRelationValue(value any) any {
+	switch v := value.(type) {
+	case string:
+		if v == "" {
+			return v
+		}
+		return ImportId(v)
+	case []any:
+		ids := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok && str != "" {
+				ids = append(ids, ImportId(str))
+			}
+		}
+		return ids
+	case []string:
+		ids := make([]string, 0, len(v))
+		for _, str := range v {
+			if str != "" {
+				ids = append(ids, ImportId(str))
+			}
+		}
+		return ids
+	default:
+		return value
+	}
+}
+
+// setPasswordHash assigns an existing bcrypt password hash to the auth record.
+//
+// New records without a valid bcrypt hash are assigned a random password.
+func setPasswordHash(record *core.Record, hash string) {
+	if !bcryptHashRegex.MatchString(hash) {
+		if record.IsNew() {
+			record.SetRandomPassword()
+		}
+		return
+	}
+
+	if record.GetString(core.FieldNamePassword+":hash") == hash {
+		return // no change
+	}
+
+	record.SetRaw(core.FieldNamePassword, &core.PasswordFieldValue{Hash: hash})
+	record.RefreshTokenKey()
+}
+
+var bcryptHashRegex = regexp.MustCompile(`^\$2[abxy]?\$\d{2}\$[./A-Za-z0-9]{53}$`)
+
+// findPhoneField returns the name of the first phone collection field (if any).
+func findPhoneField(collection *core.Collection) string {
+	for _, f := range collection.Fields {
+		if f.Type() == core.FieldTypePhone {
+			return f.GetName()
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,185 @@
+package dataimport
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+)
+
+// FirebaseUser defines a single user entry from the "firebase auth:export" JSON output.
+type FirebaseUser struct {
+	LocalId       string `json:"localId"`
+	Email         string `json:"email"`
+	EmailVerified bool   `json:"emailVerified"`
+	DisplayName   string `json:"displayName"`
+	PhoneNumber   string `json:"phoneNumber"`
+}
+
+// ImportFirebaseAuth imports the users from a "firebase auth:export users.json" JSON file
+// into the options auth collection.
+//
+// The Firebase password hashes are not imported and the new users are
+// created with a random password, aka. they will have to reset it.
+//
+// The optional user displayName and phoneNumber are stored in the collection
+// "name" field and the first phone field (if they exist).
+func ImportFirebaseAuth(app core.App, r io.Reader, options Options) (*Result, error) {
+	collection, err := findImportCollection(app, options, true)
+	if err != nil {
+		return nil, err
+	}
+
+	export := struct {
+		Users []*FirebaseUser `json:"users"`
+	}{}
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed to decode Firebase auth export: %w", err)
+	}
+
+	phoneField := findPhoneField(collection)
+
+	result := &Result{}
+
+	for _, user := range export.Users {
+		importRecord(app, collection, options, result, user.LocalId, func(record *core.Record) error {
+			if user.Email != "" {
+				record.SetEmail(user.Email)
+			}
+			record.SetVerified(user.EmailVerified)
+
+			if user.DisplayName != "" && collection.Fields.GetByName("name") != nil {
+				record.Set("name", user.DisplayName)
+			}
+
+			if user.PhoneNumber != "" && phoneField != "" {
+				record.Set(phoneField, user.PhoneNumber)
+			}
+
+			setPasswordHash(record, "")
+
+			return nil
+		})
+	}
+
+	return result, nil
+}
+
+// ImportFirestore imports the documents from a Firestore JSON export
+// in the "node-firestore-import-export" format:
+//
+//	{"__collections__": {"posts": {"docId": {"title": "...", "__collections__": {}}}}}
+//
+// The documents are loaded from the top-level options SourceCollection
+// (default to the target collection name). An export without
+// "__collections__" is treated as a single collection documents map.
+//
+// The special "__datatype__" values are converted as follow:
+//   - timestamp              - datetime string
+//   - geopoint               - {"lon": ..., "lat": ...}
+//   - documentReferenceValue - the referenced document id
+func ImportFirestore(app core.App, r io.Reader, options Options) (*Result, error) {
+	collection, err := findImportCollection(app, options, false)
+	if err != nil {
+		return nil, err
+	}
+
+	export := map[string]any{}
+	if err := json.NewDecoder(r).Decode(&export); err != nil {
+		return nil, fmt.Errorf("failed to decode Firestore export: %w", err)
+	}
+
+	docs := export
+
+	if collections, ok := export["__collections__"].(map[string]any); ok {
+		sourceCollection := options.SourceCollection
+		if sourceCollection == "" {
+			sourceCollection = collection.Name
+		}
+
+		docs, ok = collections[sourceCollection].(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("missing Firestore collection %q", sourceCollection)
+		}
+	}
+
+	// sort the document ids to ensure deterministic import order
+	ids := make([]string, 0, len(docs))
+	for id := range docs {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	result := &Result{}
+
+	for _, id := range ids {
+		importRecord(app, collection, options, result, id, func(record *core.Record) error {
+			doc, ok := docs[id].(map[string]any)
+			if !ok {
+				return errors.New("invalid Firestore document")
+			}
+
+			data := make(map[string]any, len(doc))
+			for k, v := range doc {
+				if k == "__collections__" {
+					continue // subcollections are not supported
+				}
+				data[k] = convertFirestoreValue(v)
+			}
+
+			loadData(record, data)
+
+			if record.Collection().IsAuth() {
+				setPasswordHash(record, "")
+			}
+
+			return nil
+		})
+	}
+
+	return result, nil
+}
+
+func convertFirestoreValue(value any) any {
+	switch v := value.(type) {
+	case []any:
+		result := make([]any, len(v))
+		for i, item := range v {
+			result[i] = convertFirestoreValue(item)
+		}
+		return result
+	case map[string]any:
+		switch v["__datatype__"] {
+		case "timestamp":
+			ts, _ := v["value"].(map[string]any)
+			seconds := cast.ToInt64(ts["_seconds"])
+			nanoseconds := cast.ToInt64(ts["_nanoseconds"])
+			dt, _ := types.ParseDateTime(time.Unix(seconds, nanoseconds))
+			return dt.String()
+		case "geopoint":
+			point, _ := v["value"].(map[string]any)
+			return map[string]any{
+				"lon": cast.ToFloat64(point["_longitude"]),
+				"lat": cast.ToFloat64(point["_latitude"]),
+			}
+		case "documentReferenceValue":
+			ref := cast.ToString(v["value"])
+			return ref[strings.LastIndex(ref, "/")+1:]
+		}
+
+		result := make(map[string]any, len(v))
+		for k, item := range v {
+			result[k] = convertFirestoreValue(item)
+		}
+		return result
+	default:
+		return value
+	}
+}
@@ -0,0 +1,214 @@
+package dataimport_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/plugins/dataimport"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestImportFirebaseAuth(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users.Fields.Add(
+		&core.PhoneField{Name: "phone"},
+		&core.TextField{Name: "firebaseId"},
+	)
+	if err = app.Save(users); err != nil {
+		t.Fatal(err)
+	}
+
+	export := `{"users": [
+		{
+			"localId": "Xq8LPCVkS2N4hT9DqW1mZyJf7Bv2",
+			"email": "firebase1@example.com",
+			"emailVerified": true,
+			"passwordHash": "lSrfV15cpx95/sZS2W9c9Kp6i/LVgQNDNC/qzrCnh1SAyZvqmZqAjTdn3aoItz+VHjoZilo78198JAdRuid5lQ==",
+			"salt": "42xEC+ixf3L2lw==",
+			"displayName": "Firebase User",
+			"phoneNumber": "+15551234567",
+			"createdAt": "1486676186000"
+		},
+		{
+			"localId": "nu8JcGV6Mjc2ZW96Zg6iCpg7G3w1",
+			"email": "test@example.com"
+		},
+		{
+			"email": "firebase3@example.com"
+		}
+	]}`
+
+	result, err := dataimport.ImportFirebaseAuth(app, strings.NewReader(export), dataimport.Options{
+		Collection:    "users",
+		SourceIdField: "firebaseId",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Total != 3 || result.Created != 1 || result.Failed != 2 {
+		t.Fatalf("Unexpected result %#v", result)
+	}
+
+	// email uniqueness error
+	if _, ok := result.Errors["nu8JcGV6Mjc2ZW96Zg6iCpg7G3w1"]; !ok {
+		t.Fatalf("Expected nu8JcGV6Mjc2ZW96Zg6iCpg7G3w1 error, got %v", result.Errors)
+	}
+
+	// missing localId
+	if _, ok := result.Errors["#3"]; !ok {
+		t.Fatalf("Expected #3 error, got %v", result.Errors)
+	}
+
+	record, err := app.FindRecordById("users", dataimport.ImportId("Xq8LPCVkS2N4hT9DqW1mZyJf7Bv2"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]any{
+		"email":      "firebase1@example.com",
+		"verified":   true,
+		"name":       "Firebase User",
+		"phone":      "+15551234567",
+		"firebaseId": "Xq8LPCVkS2N4hT9DqW1mZyJf7Bv2",
+	}
+	for k, v := range expected {
+		if record.Get(k) != v {
+			t.Fatalf("Expected %q to be %v, got %v", k, v, record.Get(k))
+		}
+	}
+
+	if record.GetString("password:hash") == "" {
+		t.Fatal("Expected random password to be set")
+	}
+
+	// reimport should update the existing record and keep its password
+	oldHash := record.GetString("password:hash")
+
+	result, err = dataimport.ImportFirebaseAuth(app, strings.NewReader(`{"users":[{"localId":"Xq8LPCVkS2N4hT9DqW1mZyJf7Bv2","email":"firebase1_new@example.com"}]}`), dataimport.Options{
+		Collection: "users",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Total != 1 || result.Updated != 1 {
+		t.Fatalf("Unexpected reimport result %#v", result)
+	}
+
+	record, err = app.FindRecordById("users", record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if record.Email() != "firebase1_new@example.com" {
+		t.Fatalf("Expected the email to be updated, got %q", record.Email())
+	}
+
+	if record.GetString("password:hash") != oldHash {
+		t.Fatal("Expected the password to remain unchanged")
+	}
+}
+
+func TestImportFirestore(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	collection := core.NewBaseCollection("posts")
+	collection.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.DateField{Name: "published"},
+		&core.GeoPointField{Name: "location"},
+		&core.JSONField{Name: "meta"},
+	)
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	// self-reference
+	collection.Fields.Add(&core.RelationField{Name: "parent", CollectionId: collection.Id, MaxSelect: 1})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	export := `{"__collections__": {
+		"other": {
+			"ignored": {"title": "ignored"}
+		},
+		"posts": {
+			"postA": {
+				"title": "A",
+				"unknown": 123,
+				"published": {"__datatype__": "timestamp", "value": {"_seconds": 1700000000, "_nanoseconds": 0}},
+				"location": {"__datatype__": "geopoint", "value": {"_latitude": 42.5, "_longitude": 23.3}},
+				"meta": {"tags": ["a", "b"]},
+				"__collections__": {}
+			},
+			"postB": {
+				"title": "B",
+				"parent": {"__datatype__": "documentReferenceValue", "value": "posts/postA"}
+			}
+		}
+	}}`
+
+	result, err := dataimport.ImportFirestore(app, strings.NewReader(export), dataimport.Options{
+		Collection: "posts",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Total != 2 || result.Created != 2 || result.Failed != 0 {
+		t.Fatalf("Unexpected result %#v", result)
+	}
+
+	if total := countRecords(t, app, "posts"); total != 2 {
+		t.Fatalf("Expected 2 posts, got %d", total)
+	}
+
+	postA, err := app.FindRecordById("posts", dataimport.ImportId("postA"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := postA.GetDateTime("published").String(); v != "2023-11-14 22:13:20.000Z" {
+		t.Fatalf("Expected published date %q, got %q", "2023-11-14 22:13:20.000Z", v)
+	}
+
+	if v := postA.GetString("location"); v != `{"lon":23.3,"lat":42.5}` {
+		t.Fatalf("Expected location %q, got %q", `{"lon":23.3,"lat":42.5}`, v)
+	}
+
+	if v := postA.GetString("meta"); v != `{"tags":["a","b"]}` {
+		t.Fatalf("Expected meta %q, got %q", `{"tags":["a","b"]}`, v)
+	}
+
+	postB, err := app.FindRecordById("posts", dataimport.ImportId("postB"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := postB.GetString("parent"); v != postA.Id {
+		t.Fatalf("Expected parent %q, got %q", postA.Id, v)
+	}
+
+	// missing source collection
+	_, err = dataimport.ImportFirestore(app, strings.NewReader(export), dataimport.Options{
+		Collection:       "posts",
+		SourceCollection: "missing",
+	})
+	if err == nil {
+		t.Fatal("Expected missing source collection error")
+	}
+}
@@ -0,0 +1,111 @@
+package dataimport
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/spf13/cast"
+)
+
+// SupabaseUser defines a single "auth.users" table row.
+type SupabaseUser struct {
+	Id                string         `json:"id"`
+	Email             string         `json:"email"`
+	EncryptedPassword string         `json:"encrypted_password"`
+	EmailConfirmedAt  *string        `json:"email_confirmed_at"`
+	Phone             string         `json:"phone"`
+	RawUserMetaData   map[string]any `json:"raw_user_meta_data"`
+}
+
+// ImportSupabaseAuth imports the users from a JSON array with the
+// Supabase "auth.users" table rows into the options auth collection, e.g.:
+//
+//	psql -t -c "select json_agg(u) from auth.users u" > users.json
+//
+// The bcrypt "encrypted_password" hashes are imported as they are
+// (users without password hash are created with a random password).
+//
+// The optional "raw_user_meta_data" name (or full_name) and phone are stored in the
+// collection "name" field and the first phone field (if they exist).
+func ImportSupabaseAuth(app core.App, r io.Reader, options Options) (*Result, error) {
+	collection, err := findImportCollection(app, options, true)
+	if err != nil {
+		return nil, err
+	}
+
+	users := []*SupabaseUser{}
+	if err := json.NewDecoder(r).Decode(&users); err != nil {
+		return nil, fmt.Errorf("failed to decode Supabase auth export: %w", err)
+	}
+
+	phoneField := findPhoneField(collection)
+
+	result := &Result{}
+
+	for _, user := range users {
+		importRecord(app, collection, options, result, user.Id, func(record *core.Record) error {
+			if user.Email != "" {
+				record.SetEmail(user.Email)
+			}
+			record.SetVerified(user.EmailConfirmedAt != nil && *user.EmailConfirmedAt != "")
+
+			if collection.Fields.GetByName("name") != nil {
+				name := cast.ToString(user.RawUserMetaData["name"])
+				if name == "" {
+					name = cast.ToString(user.RawUserMetaData["full_name"])
+				}
+				if name != "" {
+					record.Set("name", name)
+				}
+			}
+
+			if user.Phone != "" && phoneField != "" {
+				// Supabase stores the phone numbers without the leading "+"
+				record.Set(phoneField, "+"+user.Phone)
+			}
+
+			setPasswordHash(record, user.EncryptedPassword)
+
+			return nil
+		})
+	}
+
+	return result, nil
+}
+
+// ImportSupabase imports the rows from a JSON array with a single Supabase
+// table rows into the options collection, e.g.:
+//
+//	psql -t -c "select json_agg(t) from public.posts t" > posts.json
+//
+// The row columns are mapped to the collection fields with the same name
+// and the "id" and the relation columns values are converted with [ImportId].
+func ImportSupabase(app core.App, r io.Reader, options Options) (*Result, error) {
+	collection, err := findImportCollection(app, options, false)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := []map[string]any{}
+	if err := json.NewDecoder(r).Decode(&rows); err != nil {
+		return nil, fmt.Errorf("failed to decode Supabase table export: %w", err)
+	}
+
+	result := &Result{}
+
+	for _, row := range rows {
+		importRecord(app, collection, options, result, cast.ToString(row["id"]), func(record *core.Record) error {
+			loadData(record, row)
+
+			if record.Collection().IsAuth() {
+				setPasswordHash(record, cast.ToString(row["encrypted_password"]))
+			}
+
+			return nil
+		})
+	}
+
+	return result, nil
+}
@@ -0,0 +1,180 @@
+package dataimport_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/plugins/dataimport"
+	"github.com/pocketbase/pocketbase/tests"
+	"golang.org/x/crypto/bcrypt"
+)
+
+func TestImportSupabaseAuth(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+	users.Fields.Add(&core.PhoneField{Name: "phone"})
+	if err = app.Save(users); err != nil {
+		t.Fatal(err)
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte("supabase123"), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	export := `[
+		{
+			"id": "8d0fd2b3-9ca3-4d1a-8c1d-7a1b2c3d4e5f",
+			"email": "supabase1@example.com",
+			"encrypted_password": "` + string(hash) + `",
+			"email_confirmed_at": "2024-01-02T03:04:05.123456+00:00",
+			"phone": "359888123456",
+			"raw_user_meta_data": {"full_name": "Supabase User"}
+		},
+		{
+			"id": "e2b8a6f0-1c7d-4f3e-9b2a-5d6c7e8f9a0b",
+			"email": "supabase2@example.com",
+			"encrypted_password": "",
+			"email_confirmed_at": null
+		}
+	]`
+
+	result, err := dataimport.ImportSupabaseAuth(app, strings.NewReader(export), dataimport.Options{
+		Collection: "users",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.Total != 2 || result.Created != 2 || result.Failed != 0 {
+		t.Fatalf("Unexpected result %#v", result)
+	}
+
+	user1, err := app.FindRecordById("users", dataimport.ImportId("8d0fd2b3-9ca3-4d1a-8c1d-7a1b2c3d4e5f"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !user1.ValidatePassword("supabase123") {
+		t.Fatal("Expected the imported bcrypt password to be valid")
+	}
+
+	expected := map[string]any{
+		"email":    "supabase1@example.com",
+		"verified": true,
+		"name":     "Supabase User",
+		"phone":    "+359888123456",
+	}
+	for k, v := range expected {
+		if user1.Get(k) != v {
+			t.Fatalf("Expected %q to be %v, got %v", k, v, user1.Get(k))
+		}
+	}
+
+	user2, err := app.FindRecordById("users", dataimport.ImportId("e2b8a6f0-1c7d-4f3e-9b2a-5d6c7e8f9a0b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user2.Verified() {
+		t.Fatal("Expected user2 to be unverified")
+	}
+
+	if user2.GetString("password:hash") == "" {
+		t.Fatal("Expected user2 random password to be set")
+	}
+}
+
+func TestImportSupabase(t *testing.T) {
+	t.Parallel()
+
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	authors := core.NewBaseCollection("authors")
+	authors.Fields.Add(&core.TextField{Name: "name"})
+	if err := app.Save(authors); err != nil {
+		t.Fatal(err)
+	}
+
+	books := core.NewBaseCollection("books")
+	books.Fields.Add(
+		&core.TextField{Name: "title"},
+		&core.NumberField{Name: "pages"},
+		&core.BoolField{Name: "available"},
+		&core.DateField{Name: "released_at"},
+		&core.RelationField{Name: "author_id", CollectionId: authors.Id, MaxSelect: 1},
+		&core.RelationField{Name: "coauthors", CollectionId: authors.Id, MaxSelect: 5},
+	)
+	if err := app.Save(books); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := dataimport.ImportSupabase(app, strings.NewReader(`[
+		{"id": 1, "name": "Author 1"},
+		{"id": 2, "name": "Author 2"}
+	]`), dataimport.Options{Collection: "authors"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 2 {
+		t.Fatalf("Unexpected authors result %#v", result)
+	}
+
+	result, err = dataimport.ImportSupabase(app, strings.NewReader(`[
+		{
+			"id": "book1",
+			"title": "Book 1",
+			"pages": 123,
+			"available": true,
+			"released_at": "2024-01-02T03:04:05.123456+00:00",
+			"author_id": "1",
+			"coauthors": ["1", "2"]
+		}
+	]`), dataimport.Options{Collection: "books"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Created != 1 || result.Failed != 0 {
+		t.Fatalf("Unexpected books result %#v", result)
+	}
+
+	book, err := app.FindRecordById("books", dataimport.ImportId("book1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"title":       "Book 1",
+		"pages":       "123",
+		"available":   "true",
+		"released_at": "2024-01-02 03:04:05.123Z",
+		"author_id":   dataimport.ImportId("1"),
+		"coauthors":   `[` + dataimport.ImportId("1") + ` ` + dataimport.ImportId("2") + `]`,
+	}
+	for k, v := range expected {
+		raw := book.Get(k)
+		if str, ok := raw.([]string); ok {
+			raw = "[" + strings.Join(str, " ") + "]"
+		}
+		if got := book.GetString(k); got != v && raw != v {
+			t.Fatalf("Expected %q to be %q, got %v", k, v, raw)
+		}
+	}
+
+	author, err := app.FindRecordById("authors", dataimport.ImportId("1"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if author.GetString("name") != "Author 1" {
+		t.Fatalf("Expected author name %q, got %q", "Author 1", author.GetString("name"))
+	}
+}
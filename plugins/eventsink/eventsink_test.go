@@ -0,0 +1,380 @@
+package eventsink
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+type testPublisher struct {
+	mu       sync.Mutex
+	messages []*Message
+	failures int
+	closed   bool
+}
+
+func (p *testPublisher) Publish(ctx context.Context, msg *Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failures > 0 {
+		p.failures--
+		return errors.New("test publish failure")
+	}
+
+	p.messages = append(p.messages, msg)
+
+	return nil
+}
+
+func (p *testPublisher) Close() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.closed = true
+
+	return nil
+}
+
+func (p *testPublisher) waitMessages(t *testing.T, total int) []*Message {
+	deadline := time.Now().Add(5 * time.Second)
+	for time.Now().Before(deadline) {
+		p.mu.Lock()
+		if len(p.messages) >= total {
+			result := append([]*Message{}, p.messages...)
+			p.mu.Unlock()
+			return result
+		}
+		p.mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	t.Fatalf("Expected %d published messages, got %d", total, len(p.messages))
+
+	return nil
+}
+
+func TestRegisterValidation(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	scenarios := []struct {
+		name        string
+		config      Config
+		expectError bool
+	}{
+		{"missing driver and publisher", Config{}, true},
+		{"unknown driver", Config{Driver: "unknown", URL: "nats://127.0.0.1"}, true},
+		{"invalid nats url", Config{Driver: DriverNATS, URL: "http://127.0.0.1"}, true},
+		{"invalid kafkarest url", Config{Driver: DriverKafkaREST, URL: "nats://127.0.0.1"}, true},
+		{"invalid format", Config{Publisher: &testPublisher{}, Format: "xml"}, true},
+		{"unsupported payload version", Config{Publisher: &testPublisher{}, PayloadVersion: 999}, true},
+		{"supported payload version", Config{Publisher: &testPublisher{}, PayloadVersion: core.RecordPayloadVersion1}, false},
+		{"nats driver", Config{Driver: DriverNATS, URL: "nats://127.0.0.1"}, false},
+		{"kafkarest driver", Config{Driver: DriverKafkaREST, URL: "http://127.0.0.1:8082"}, false},
+		{"custom publisher", Config{Publisher: &testPublisher{}, Format: FormatCloudEvents}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			err := Register(app, s.config)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+		})
+	}
+}
+
+func TestResolveTopic(t *testing.T) {
+	p := &plugin{config: Config{
+		Topic: "pb.{collection}.{action}",
+		TopicMap: map[string]string{
+			"posts.delete": "posts-deletions",
+			"posts.*":      "posts-{action}",
+			"*.auth":       "logins",
+		},
+	}}
+
+	scenarios := []struct {
+		action     string
+		collection string
+		expected   string
+	}{
+		{ActionCreate, "demo", "pb.demo.create"},
+		{ActionAuth, "users", "logins"},
+		{ActionCreate, "posts", "posts-create"},
+		{ActionAuth, "posts", "posts-auth"},
+		{ActionDelete, "posts", "posts-deletions"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.collection+"."+s.action, func(t *testing.T) {
+			result := p.resolveTopic(s.action, s.collection)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestRecordEvents(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	publisher := &testPublisher{}
+
+	MustRegister(app, Config{
+		Publisher:     publisher,
+		Actions:       []string{ActionCreate, ActionUpdate, ActionDelete},
+		RetryInterval: 10 * time.Millisecond,
+	})
+
+	collection := core.NewBaseCollection("eventsink_test")
+	collection.Fields.Add(&core.TextField{Name: "title"})
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("title", "a")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	record.Set("title", "b")
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := app.Delete(record); err != nil {
+		t.Fatal(err)
+	}
+
+	// system collections should be ignored
+	superusers, err := app.FindCollectionByNameOrId(core.CollectionNameSuperusers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	superuser := core.NewRecord(superusers)
+	superuser.SetEmail("eventsink@example.com")
+	superuser.SetPassword("1234567890")
+	if err := app.Save(superuser); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := publisher.waitMessages(t, 3)
+
+	// give some time for eventual unexpected messages
+	time.Sleep(50 * time.Millisecond)
+	publisher.mu.Lock()
+	total := len(publisher.messages)
+	publisher.mu.Unlock()
+	if total != 3 {
+		t.Fatalf("Expected exactly 3 messages, got %d", total)
+	}
+
+	expected := []struct {
+		action string
+		title  string
+	}{
+		{ActionCreate, "a"},
+		{ActionUpdate, "b"},
+		{ActionDelete, "b"},
+	}
+
+	for i, msg := range messages {
+		expectedTopic := "pocketbase.eventsink_test." + expected[i].action
+		if msg.Topic != expectedTopic {
+			t.Fatalf("[%d] Expected topic %q, got %q", i, expectedTopic, msg.Topic)
+		}
+
+		if msg.Key != record.Id {
+			t.Fatalf("[%d] Expected key %q, got %q", i, record.Id, msg.Key)
+		}
+
+		event := struct {
+			Id         string         `json:"id"`
+			Action     string         `json:"action"`
+			Record     map[string]any `json:"record"`
+			Collection struct {
+				Id   string `json:"id"`
+				Name string `json:"name"`
+			} `json:"collection"`
+			Version int `json:"version"`
+		}{}
+		if err := json.Unmarshal(msg.Payload, &event); err != nil {
+			t.Fatal(err)
+		}
+
+		if event.Id == "" {
+			t.Fatalf("[%d] Expected non-empty event id", i)
+		}
+
+		if event.Action != expected[i].action {
+			t.Fatalf("[%d] Expected action %q, got %q", i, expected[i].action, event.Action)
+		}
+
+		if event.Collection.Id != collection.Id || event.Collection.Name != collection.Name {
+			t.Fatalf("[%d] Expected collection %q (%q), got %q (%q)", i, collection.Id, collection.Name, event.Collection.Id, event.Collection.Name)
+		}
+
+		if event.Version != core.LatestRecordPayloadVersion {
+			t.Fatalf("[%d] Expected payload version %d, got %d", i, core.LatestRecordPayloadVersion, event.Version)
+		}
+
+		if event.Record["title"] != expected[i].title {
+			t.Fatalf("[%d] Expected record title %q, got %v", i, expected[i].title, event.Record["title"])
+		}
+	}
+}
+
+func TestCloudEventsFormatAndCollectionsFilter(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	publisher := &testPublisher{}
+
+	MustRegister(app, Config{
+		Publisher:   publisher,
+		Format:      FormatCloudEvents,
+		Collections: []string{"eventsink_test2"},
+	})
+
+	c1 := core.NewBaseCollection("eventsink_test1")
+	c2 := core.NewBaseCollection("eventsink_test2")
+	for _, c := range []*core.Collection{c1, c2} {
+		if err := app.Save(c); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := app.Save(core.NewRecord(c1)); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(c2)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := publisher.waitMessages(t, 1)
+	if len(messages) != 1 {
+		t.Fatalf("Expected 1 message, got %d", len(messages))
+	}
+
+	event := map[string]any{}
+	if err := json.Unmarshal(messages[0].Payload, &event); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedFields := map[string]any{
+		"specversion":     "1.0",
+		"source":          "pocketbase",
+		"type":            "pocketbase.record.create",
+		"subject":         "eventsink_test2/" + record.Id,
+		"datacontenttype": "application/json",
+		"payloadversion":  float64(core.LatestRecordPayloadVersion),
+	}
+	for k, v := range expectedFields {
+		if event[k] != v {
+			t.Fatalf("Expected %q to be %v, got %v", k, v, event[k])
+		}
+	}
+
+	data, _ := event["data"].(map[string]any)
+	if data["id"] != record.Id {
+		t.Fatalf("Expected data.id %q, got %v", record.Id, data["id"])
+	}
+}
+
+func TestRetryAndBuffer(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	publisher := &testPublisher{failures: 2}
+
+	MustRegister(app, Config{
+		Publisher:     publisher,
+		RetryInterval: 10 * time.Millisecond,
+	})
+
+	collection := core.NewBaseCollection("eventsink_test")
+	if err := app.Save(collection); err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	messages := publisher.waitMessages(t, 1)
+	if messages[0].Key != record.Id {
+		t.Fatalf("Expected the failed message to be retried, got %q", messages[0].Key)
+	}
+
+	publisher.mu.Lock()
+	failures := publisher.failures
+	publisher.mu.Unlock()
+	if failures != 0 {
+		t.Fatalf("Expected all failures to be consumed, got %d", failures)
+	}
+}
+
+func TestBufferDropOldest(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	p := &plugin{
+		app:     app,
+		config:  Config{Topic: defaultTopic, Format: FormatJSON, BufferSize: 2},
+		signal:  make(chan struct{}, 1),
+		pending: []*Message{},
+	}
+
+	collection := core.NewBaseCollection("eventsink_test")
+
+	records := make([]*core.Record, 3)
+	for i := range records {
+		records[i] = core.NewRecord(collection)
+		records[i].Id = "r" + string(rune('0'+i))
+		p.enqueue(ActionCreate, records[i])
+	}
+
+	if len(p.pending) != 2 {
+		t.Fatalf("Expected 2 pending messages, got %d", len(p.pending))
+	}
+
+	if p.pending[0].Key != "r1" || p.pending[1].Key != "r2" {
+		t.Fatalf("Expected the oldest message to be dropped, got %q, %q", p.pending[0].Key, p.pending[1].Key)
+	}
+}
+
+func TestTerminateClosesPublisher(t *testing.T) {
+	app, _ := tests.NewTestApp()
+
+	publisher := &testPublisher{}
+
+	MustRegister(app, Config{Publisher: publisher})
+
+	app.Cleanup()
+
+	publisher.mu.Lock()
+	defer publisher.mu.Unlock()
+
+	if !publisher.closed {
+		t.Fatal("Expected the publisher to be closed on terminate")
+	}
+}
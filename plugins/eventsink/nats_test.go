@@ -0,0 +1,162 @@
+package eventsink
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type natsTestServer struct {
+	listener net.Listener
+	received chan string
+	connects chan string
+}
+
+// newNATSTestServer starts a fake NATS server that accepts
+// CONNECT/PING/PUB commands and reports the published messages.
+func newNATSTestServer(t *testing.T) *natsTestServer {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &natsTestServer{
+		listener: listener,
+		received: make(chan string, 10),
+		connects: make(chan string, 10),
+	}
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go s.handle(conn)
+		}
+	}()
+
+	t.Cleanup(func() {
+		listener.Close()
+	})
+
+	return s
+}
+
+func (s *natsTestServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	fmt.Fprint(conn, "INFO {\"server_id\":\"test\"}\r\n")
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		switch {
+		case strings.HasPrefix(line, "CONNECT "):
+			s.connects <- strings.TrimPrefix(line, "CONNECT ")
+		case line == "PING":
+			fmt.Fprint(conn, "PONG\r\n")
+		case strings.HasPrefix(line, "PUB "):
+			parts := strings.Fields(line)
+			size, _ := strconv.Atoi(parts[len(parts)-1])
+			payload := make([]byte, size+2)
+			if _, err := io.ReadFull(reader, payload); err != nil {
+				return
+			}
+			if parts[1] == "fail" {
+				fmt.Fprint(conn, "-ERR 'Permissions Violation'\r\n")
+				return
+			}
+			s.received <- parts[1] + " " + string(payload[:size])
+		}
+	}
+}
+
+func TestNewNATSPublisher(t *testing.T) {
+	scenarios := []struct {
+		url          string
+		expectError  bool
+		expectedHost string
+	}{
+		{"", true, ""},
+		{"http://127.0.0.1:4222", true, ""},
+		{"nats://", true, ""},
+		{"nats://127.0.0.1", false, "127.0.0.1:4222"},
+		{"tls://example.com:1234", false, "example.com:1234"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.url, func(t *testing.T) {
+			p, err := NewNATSPublisher(s.url)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && p.url.Host != s.expectedHost {
+				t.Fatalf("Expected host %q, got %q", s.expectedHost, p.url.Host)
+			}
+		})
+	}
+}
+
+func TestNATSPublisherPublish(t *testing.T) {
+	server := newNATSTestServer(t)
+
+	p, err := NewNATSPublisher("nats://user:pass@" + server.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer p.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := p.Publish(ctx, &Message{Topic: "invalid subject", Payload: []byte("{}")}); err == nil {
+		t.Fatal("Expected invalid subject error")
+	}
+
+	if err := p.Publish(ctx, &Message{Topic: "a.b", Payload: []byte(`{"test":1}`)}); err != nil {
+		t.Fatal(err)
+	}
+
+	connect := <-server.connects
+	if !strings.Contains(connect, `"user":"user"`) || !strings.Contains(connect, `"pass":"pass"`) {
+		t.Fatalf("Expected the CONNECT options to contain the credentials, got %s", connect)
+	}
+
+	if received := <-server.received; received != `a.b {"test":1}` {
+		t.Fatalf("Unexpected received message %q", received)
+	}
+
+	// server error should reset the connection
+	if err := p.Publish(ctx, &Message{Topic: "fail", Payload: []byte("{}")}); err == nil {
+		t.Fatal("Expected server error")
+	}
+
+	if p.conn != nil {
+		t.Fatal("Expected the connection to be reset")
+	}
+
+	// reconnect
+	if err := p.Publish(ctx, &Message{Topic: "c", Payload: []byte("{}")}); err != nil {
+		t.Fatal(err)
+	}
+
+	if received := <-server.received; received != "c {}" {
+		t.Fatalf("Unexpected received message %q", received)
+	}
+}
@@ -242,6 +242,9 @@ func (p *plugin) update(withBackup bool) error {
 
 	color.HiBlack("---")
 	color.Green("Update completed successfully! You can start the executable as usual.")
+	if runtime.GOOS != "windows" {
+		color.HiBlack("(to restart an already running server without dropping connections send it the SIGUSR2 signal)")
+	}
 
 	// print the release notes
 	if latest.Body != "" {
@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
@@ -33,6 +34,7 @@ import (
 	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/store"
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
+	"github.com/pocketbase/pocketbase/tools/template"
 	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cast"
 	"github.com/spf13/cobra"
@@ -288,6 +290,84 @@ func wrapMiddlewares(executors *vmsPool, rawMiddlewares ...goja.Value) ([]*hook.
 	return wrappedMiddlewares, nil
 }
 
+// jsTemplateRegistry wraps [template.Registry] to allow
+// registering JS functions as global template funcs.
+type jsTemplateRegistry struct {
+	*template.Registry
+
+	executors *vmsPool
+}
+
+// AddFuncs registers new global template functions.
+//
+// The JS functions are executed with one of the executors pool vms
+// since the template could be rendered concurrently by multiple goroutines.
+func (r *jsTemplateRegistry) AddFuncs(funcs map[string]goja.Value) (*jsTemplateRegistry, error) {
+	wrapped := make(map[string]any, len(funcs))
+
+	for name, f := range funcs {
+		fn, err := wrapTemplateFunc(r.executors, f)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		wrapped[name] = fn
+	}
+
+	r.Registry.AddFuncs(wrapped)
+
+	return r, nil
+}
+
+func wrapTemplateFunc(executors *vmsPool, f goja.Value) (any, error) {
+	if f == nil {
+		return nil, errors.New("template func must be non-nil")
+	}
+
+	switch v := f.Export().(type) {
+	case func(goja.FunctionCall) goja.Value:
+		if executors == nil {
+			return nil, errors.New("JS template funcs are not supported in the current context")
+		}
+
+		pr := goja.MustCompile(defaultScriptPath, "{("+f.String()+").apply(undefined, __args)}", true)
+
+		return func(args ...any) (any, error) {
+			var result any
+
+			err := executors.run(func(executor *goja.Runtime) error {
+				executor.Set("__args", args)
+				res, err := executor.RunProgram(pr)
+				executor.Set("__args", goja.Undefined())
+				if err != nil {
+					return normalizeException(err)
+				}
+
+				if res != nil {
+					result = res.Export()
+				}
+
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+
+			if resErr, ok := result.(error); ok {
+				return nil, resErr
+			}
+
+			return result, nil
+		}, nil
+	default:
+		if reflect.TypeOf(v).Kind() != reflect.Func {
+			return nil, errors.New("unsupported goja template func type")
+		}
+
+		// "native" Go func - no need to wrap
+		return v, nil
+	}
+}
+
 var cachedArrayOfTypes = store.New[reflect.Type, reflect.Type](nil)
 
 func baseBinds(vm *goja.Runtime) {
@@ -501,6 +581,10 @@ func baseBinds(vm *goja.Runtime) {
 		instance := &core.EmailField{}
 		return structConstructorUnmarshal(vm, call, instance)
 	})
+	vm.Set("PhoneField", func(call goja.ConstructorCall) *goja.Object {
+		instance := &core.PhoneField{}
+		return structConstructorUnmarshal(vm, call, instance)
+	})
 	vm.Set("EditorField", func(call goja.ConstructorCall) *goja.Object {
 		instance := &core.EditorField{}
 		return structConstructorUnmarshal(vm, call, instance)
@@ -537,6 +621,18 @@ func baseBinds(vm *goja.Runtime) {
 		instance := &core.GeoPointField{}
 		return structConstructorUnmarshal(vm, call, instance)
 	})
+	vm.Set("StateField", func(call goja.ConstructorCall) *goja.Object {
+		instance := &core.StateField{}
+		return structConstructorUnmarshal(vm, call, instance)
+	})
+	vm.Set("SlugField", func(call goja.ConstructorCall) *goja.Object {
+		instance := &core.SlugField{}
+		return structConstructorUnmarshal(vm, call, instance)
+	})
+	vm.Set("SequenceField", func(call goja.ConstructorCall) *goja.Object {
+		instance := &core.SequenceField{}
+		return structConstructorUnmarshal(vm, call, instance)
+	})
 	// ---
 
 	vm.Set("MailerMessage", func(call goja.ConstructorCall) *goja.Object {
@@ -23,6 +23,7 @@ import (
 	"github.com/pocketbase/pocketbase/tools/filesystem"
 	"github.com/pocketbase/pocketbase/tools/mailer"
 	"github.com/pocketbase/pocketbase/tools/router"
+	"github.com/pocketbase/pocketbase/tools/template"
 	"github.com/pocketbase/pocketbase/tools/types"
 	"github.com/spf13/cast"
 )
@@ -46,7 +47,7 @@ func TestBaseBindsCount(t *testing.T) {
 	vm := goja.New()
 	baseBinds(vm)
 
-	testBindsCount(vm, "this", 35, t)
+	testBindsCount(vm, "this", 39, t)
 }
 
 func TestBaseBindsSleep(t *testing.T) {
@@ -408,6 +409,10 @@ func TestBaseBindsNamedFields(t *testing.T) {
 			"new EmailField({name: 'test'})",
 			isType[*core.EmailField],
 		},
+		{
+			"new PhoneField({name: 'test'})",
+			isType[*core.PhoneField],
+		},
 		{
 			"new EditorField({name: 'test'})",
 			isType[*core.EditorField],
@@ -444,6 +449,18 @@ func TestBaseBindsNamedFields(t *testing.T) {
 			"new GeoPointField({name: 'test'})",
 			isType[*core.GeoPointField],
 		},
+		{
+			"new StateField({name: 'test'})",
+			isType[*core.StateField],
+		},
+		{
+			"new SlugField({name: 'test'})",
+			isType[*core.SlugField],
+		},
+		{
+			"new SequenceField({name: 'test'})",
+			isType[*core.SequenceField],
+		},
 	}
 
 	for _, s := range scenarios {
@@ -1529,7 +1546,7 @@ func TestHooksBindsCount(t *testing.T) {
 	vm := goja.New()
 	hooksBinds(app, vm, nil)
 
-	testBindsCount(vm, "this", 82, t)
+	testBindsCount(vm, "this", 94, t)
 }
 
 func TestHooksBinds(t *testing.T) {
@@ -1620,6 +1637,53 @@ func TestHooksBinds(t *testing.T) {
 	}
 }
 
+func TestHooksBindsRecordRequestTransform(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	vmFactory := func() *goja.Runtime {
+		vm := goja.New()
+		baseBinds(vm)
+		vm.Set("$app", app)
+		return vm
+	}
+
+	pool := newPool(1, vmFactory)
+
+	vm := vmFactory()
+	hooksBinds(app, vm, pool)
+
+	_, err := vm.RunString(`
+		onRecordRequestTransform((e) => {
+			e.data.title = e.data.name
+			delete e.data.name
+			e.next()
+		}, "demo2")
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	collection, err := app.FindCollectionByNameOrId("demo2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requestEvent := new(core.RecordRequestTransformEvent)
+	requestEvent.RequestEvent = &core.RequestEvent{App: app}
+	requestEvent.Collection = collection
+	requestEvent.Record = core.NewRecord(collection)
+	requestEvent.Data = map[string]any{"name": "test"}
+
+	if err := app.OnRecordRequestTransform().Trigger(requestEvent); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := requestEvent.Data["name"]; ok || requestEvent.Data["title"] != "test" {
+		t.Fatalf("Expected the name field to be renamed to title, got %v", requestEvent.Data)
+	}
+}
+
 func TestHooksExceptionUnwrapping(t *testing.T) {
 	app, _ := tests.NewTestApp()
 	defer app.Cleanup()
@@ -1784,3 +1848,52 @@ func TestOsBindsCount(t *testing.T) {
 
 	testBindsCount(vm, "$os", 20, t)
 }
+
+func TestTemplateRegistryAddFuncs(t *testing.T) {
+	registry := &jsTemplateRegistry{Registry: template.NewRegistry()}
+
+	vmFactory := func() *goja.Runtime {
+		vm := goja.New()
+		vm.SetFieldNameMapper(FieldMapper{})
+		vm.Set("$template", registry)
+		return vm
+	}
+
+	registry.executors = newPool(1, vmFactory)
+
+	vm := vmFactory()
+
+	_, err := vm.RunString(`
+		$template.addFuncs({
+			"greet": (name, suffix) => "Hello " + name + suffix,
+			"fail":  () => { throw new Error("test_error") },
+		})
+
+		$template.addPartials({
+			"header": '<h1>{{greet .name "!"}}</h1>',
+		})
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := registry.LoadString(`{{template "header" .}}`).Render(map[string]any{"name": "John"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<h1>Hello John!</h1>"
+	if result != expected {
+		t.Fatalf("Expected %q, got %q", expected, result)
+	}
+
+	_, err = registry.LoadString(`{{fail}}`).Render(nil)
+	if err == nil || !strings.Contains(err.Error(), "test_error") {
+		t.Fatalf("Expected test_error, got %v", err)
+	}
+
+	_, err = vm.RunString(`$template.addFuncs({"invalid": 123})`)
+	if err == nil {
+		t.Fatal("Expected invalid template func error")
+	}
+}
@@ -157,6 +157,23 @@ declare var $app: PocketBase
  * ).render({"name": "John"})
  * ` + "```" + `
  *
+ * Shared partials (_e.g. layouts and components_) and custom template
+ * functions could be registered once and used by all loaded templates:
+ *
+ * ` + "```" + `js
+ * $template.addFuncs({
+ *     "upper": (str) => str.toUpperCase(),
+ * })
+ *
+ * $template.addPartials({
+ *     "header": "<h1>{{upper .title}}</h1>",
+ * })
+ *
+ * const html = $template.loadString('{{template "header" .}}').render({"title": "test"})
+ * ` + "```" + `
+ *
+ * In dev mode (_--dev_) the templates are reparsed on every load (_hot-reload_).
+ *
  * @namespace
  * @group PocketBase
  */
@@ -434,6 +451,16 @@ declare class EmailField implements core.EmailField {
   constructor(data?: Partial<core.EmailField>)
 }
 
+interface PhoneField extends core.PhoneField{} // merge
+/**
+ * {@inheritDoc core.PhoneField}
+ *
+ * @group PocketBase
+ */
+declare class PhoneField implements core.PhoneField {
+  constructor(data?: Partial<core.PhoneField>)
+}
+
 interface EditorField extends core.EditorField{} // merge
 /**
  * {@inheritDoc core.EditorField}
@@ -524,6 +551,36 @@ declare class GeoPointField implements core.GeoPointField {
   constructor(data?: Partial<core.GeoPointField>)
 }
 
+interface StateField extends core.StateField{} // merge
+/**
+ * {@inheritDoc core.StateField}
+ *
+ * @group PocketBase
+ */
+declare class StateField implements core.StateField {
+  constructor(data?: Partial<core.StateField>)
+}
+
+interface SlugField extends core.SlugField{} // merge
+/**
+ * {@inheritDoc core.SlugField}
+ *
+ * @group PocketBase
+ */
+declare class SlugField implements core.SlugField {
+  constructor(data?: Partial<core.SlugField>)
+}
+
+interface SequenceField extends core.SequenceField{} // merge
+/**
+ * {@inheritDoc core.SequenceField}
+ *
+ * @group PocketBase
+ */
+declare class SequenceField implements core.SequenceField {
+  constructor(data?: Partial<core.SequenceField>)
+}
+
 interface MailerMessage extends mailer.Message{} // merge
 /**
  * MailerMessage defines a single email message.
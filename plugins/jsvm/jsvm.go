@@ -280,7 +280,9 @@ func (p *plugin) registerHooks() error {
 
 	// safe to be shared across multiple vms
 	requireRegistry := new(require.Registry)
-	templateRegistry := template.NewRegistry()
+	templateRegistry := &jsTemplateRegistry{
+		Registry: template.NewRegistry().SetHotReload(p.app.IsDev()),
+	}
 
 	sharedBinds := func(vm *goja.Runtime) {
 		requireRegistry.Enable(vm)
@@ -315,6 +317,8 @@ func (p *plugin) registerHooks() error {
 		return executor
 	})
 
+	templateRegistry.executors = executors
+
 	// initialize the loader vm
 	loader := goja.New()
 	sharedBinds(loader)
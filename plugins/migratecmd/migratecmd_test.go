@@ -29,7 +29,7 @@ migrate((app) => {
   const collection = new Collection({
     "authAlert": {
       "emailTemplate": {
-        "body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+        "body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>\n  IP address: {ALERT_IP}<br/>\n  Device: {ALERT_USER_AGENT}\n</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
         "subject": "Login from a new location"
       },
       "enabled": true
@@ -44,17 +44,37 @@ migrate((app) => {
     },
     "createRule": null,
     "deleteRule": null,
+    "emailChange": {
+      "approvalTemplate": {
+        "body": "<p>Hello,</p>\n<p>We received a request to change your {APP_NAME} account email address to <strong>{NEW_EMAIL}</strong>.</p>\n<p>Click on the button below to approve the change. After that a confirmation link will be sent to the new email address.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Approve email change</a>\n</p>\n<p><strong>If you didn't ask to change your email address, you should immediately change your {APP_NAME} account password.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+        "subject": "Approve your {APP_NAME} email address change"
+      },
+      "notifyOldEmail": true,
+      "notifyTemplate": {
+        "body": "<p>Hello,</p>\n<p>The email address of your {APP_NAME} account was changed from <strong>{OLD_EMAIL}</strong> to <strong>{NEW_EMAIL}</strong>.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, please contact us as soon as possible to recover your account.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+        "subject": "Your {APP_NAME} email address was changed"
+      },
+      "requireOldEmailApproval": false
+    },
     "emailChangeToken": {
       "duration": 1800
     },
+    "emailDomains": {
+      "allowlist": null,
+      "checkMX": false,
+      "denyDisposable": false,
+      "denylist": null
+    },
     "fields": [
       {
         "autogeneratePattern": "[a-z0-9]{15}",
+        "collation": "",
         "hidden": false,
         "id": "text@TEST_RANDOM",
         "max": 15,
         "min": 15,
         "name": "id",
+        "normalize": false,
         "pattern": "^[a-z0-9]+$",
         "presentable": false,
         "primaryKey": true,
@@ -77,11 +97,13 @@ migrate((app) => {
       },
       {
         "autogeneratePattern": "[a-zA-Z0-9]{50}",
+        "collation": "",
         "hidden": true,
         "id": "text@TEST_RANDOM",
         "max": 60,
         "min": 30,
         "name": "tokenKey",
+        "normalize": false,
         "pattern": "",
         "presentable": false,
         "primaryKey": false,
@@ -128,6 +150,18 @@ migrate((app) => {
       "CREATE UNIQUE INDEX ` + "`" + `idx_tokenKey_@TEST_RANDOM` + "`" + ` ON ` + "`" + `new_name` + "`" + ` (` + "`" + `tokenKey` + "`" + `)",
       "CREATE UNIQUE INDEX ` + "`" + `idx_email_@TEST_RANDOM` + "`" + ` ON ` + "`" + `new_name` + "`" + ` (` + "`" + `email` + "`" + `) WHERE ` + "`" + `email` + "`" + ` != ''"
     ],
+    "ldapAuth": {
+      "baseDN": "",
+      "bindDN": "",
+      "emailAttribute": "mail",
+      "enabled": false,
+      "idAttribute": "",
+      "mappedFields": null,
+      "skipVerify": false,
+      "startTLS": false,
+      "url": "",
+      "userFilter": "(uid={username})"
+    },
     "listRule": "@request.auth.id != '' && 1 > 0 || 'backtick` + "`" + `test' = 0",
     "manageRule": "1 != 2",
     "mfa": {
@@ -140,6 +174,7 @@ migrate((app) => {
       "enabled": false,
       "mappedFields": {
         "avatarURL": "",
+        "groups": "",
         "id": "",
         "name": "",
         "username": ""
@@ -163,6 +198,10 @@ migrate((app) => {
     "passwordResetToken": {
       "duration": 1800
     },
+    "profileCompletion": {
+      "enabled": false,
+      "fields": null
+    },
     "resetPasswordTemplate": {
       "body": "<p>Hello,</p>\n<p>Click on the button below to reset your password.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Reset password</a>\n</p>\n<p><i>If you didn't ask to reset your password, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
       "subject": "Reset your {APP_NAME} password"
@@ -205,7 +244,7 @@ func init() {
 		jsonData := ` + "`" + `{
 			"authAlert": {
 				"emailTemplate": {
-					"body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+					"body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>\n  IP address: {ALERT_IP}<br/>\n  Device: {ALERT_USER_AGENT}\n</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 					"subject": "Login from a new location"
 				},
 				"enabled": true
@@ -220,17 +259,37 @@ func init() {
 			},
 			"createRule": null,
 			"deleteRule": null,
+			"emailChange": {
+				"approvalTemplate": {
+					"body": "<p>Hello,</p>\n<p>We received a request to change your {APP_NAME} account email address to <strong>{NEW_EMAIL}</strong>.</p>\n<p>Click on the button below to approve the change. After that a confirmation link will be sent to the new email address.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Approve email change</a>\n</p>\n<p><strong>If you didn't ask to change your email address, you should immediately change your {APP_NAME} account password.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+					"subject": "Approve your {APP_NAME} email address change"
+				},
+				"notifyOldEmail": true,
+				"notifyTemplate": {
+					"body": "<p>Hello,</p>\n<p>The email address of your {APP_NAME} account was changed from <strong>{OLD_EMAIL}</strong> to <strong>{NEW_EMAIL}</strong>.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, please contact us as soon as possible to recover your account.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+					"subject": "Your {APP_NAME} email address was changed"
+				},
+				"requireOldEmailApproval": false
+			},
 			"emailChangeToken": {
 				"duration": 1800
 			},
+			"emailDomains": {
+				"allowlist": null,
+				"checkMX": false,
+				"denyDisposable": false,
+				"denylist": null
+			},
 			"fields": [
 				{
 					"autogeneratePattern": "[a-z0-9]{15}",
+					"collation": "",
 					"hidden": false,
 					"id": "text@TEST_RANDOM",
 					"max": 15,
 					"min": 15,
 					"name": "id",
+					"normalize": false,
 					"pattern": "^[a-z0-9]+$",
 					"presentable": false,
 					"primaryKey": true,
@@ -253,11 +312,13 @@ func init() {
 				},
 				{
 					"autogeneratePattern": "[a-zA-Z0-9]{50}",
+					"collation": "",
 					"hidden": true,
 					"id": "text@TEST_RANDOM",
 					"max": 60,
 					"min": 30,
 					"name": "tokenKey",
+					"normalize": false,
 					"pattern": "",
 					"presentable": false,
 					"primaryKey": false,
@@ -304,6 +365,18 @@ func init() {
 				"CREATE UNIQUE INDEX ` + "` + \"`\" + `" + `idx_tokenKey_@TEST_RANDOM` + "` + \"`\" + `" + ` ON ` + "` + \"`\" + `" + `new_name` + "` + \"`\" + `" + ` (` + "` + \"`\" + `" + `tokenKey` + "` + \"`\" + `" + `)",
 				"CREATE UNIQUE INDEX ` + "` + \"`\" + `" + `idx_email_@TEST_RANDOM` + "` + \"`\" + `" + ` ON ` + "` + \"`\" + `" + `new_name` + "` + \"`\" + `" + ` (` + "` + \"`\" + `" + `email` + "` + \"`\" + `" + `) WHERE ` + "` + \"`\" + `" + `email` + "` + \"`\" + `" + ` != ''"
 			],
+			"ldapAuth": {
+				"baseDN": "",
+				"bindDN": "",
+				"emailAttribute": "mail",
+				"enabled": false,
+				"idAttribute": "",
+				"mappedFields": null,
+				"skipVerify": false,
+				"startTLS": false,
+				"url": "",
+				"userFilter": "(uid={username})"
+			},
 			"listRule": "@request.auth.id != '' && 1 > 0 || 'backtick` + "` + \"`\" + `" + `test' = 0",
 			"manageRule": "1 != 2",
 			"mfa": {
@@ -316,6 +389,7 @@ func init() {
 				"enabled": false,
 				"mappedFields": {
 					"avatarURL": "",
+					"groups": "",
 					"id": "",
 					"name": "",
 					"username": ""
@@ -339,6 +413,10 @@ func init() {
 			"passwordResetToken": {
 				"duration": 1800
 			},
+			"profileCompletion": {
+				"enabled": false,
+				"fields": null
+			},
 			"resetPasswordTemplate": {
 				"body": "<p>Hello,</p>\n<p>Click on the button below to reset your password.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Reset password</a>\n</p>\n<p><i>If you didn't ask to reset your password, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 				"subject": "Reset your {APP_NAME} password"
@@ -470,7 +548,7 @@ migrate((app) => {
   const collection = new Collection({
     "authAlert": {
       "emailTemplate": {
-        "body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+        "body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>\n  IP address: {ALERT_IP}<br/>\n  Device: {ALERT_USER_AGENT}\n</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
         "subject": "Login from a new location"
       },
       "enabled": true
@@ -485,17 +563,37 @@ migrate((app) => {
     },
     "createRule": null,
     "deleteRule": null,
+    "emailChange": {
+      "approvalTemplate": {
+        "body": "<p>Hello,</p>\n<p>We received a request to change your {APP_NAME} account email address to <strong>{NEW_EMAIL}</strong>.</p>\n<p>Click on the button below to approve the change. After that a confirmation link will be sent to the new email address.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Approve email change</a>\n</p>\n<p><strong>If you didn't ask to change your email address, you should immediately change your {APP_NAME} account password.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+        "subject": "Approve your {APP_NAME} email address change"
+      },
+      "notifyOldEmail": true,
+      "notifyTemplate": {
+        "body": "<p>Hello,</p>\n<p>The email address of your {APP_NAME} account was changed from <strong>{OLD_EMAIL}</strong> to <strong>{NEW_EMAIL}</strong>.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, please contact us as soon as possible to recover your account.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+        "subject": "Your {APP_NAME} email address was changed"
+      },
+      "requireOldEmailApproval": false
+    },
     "emailChangeToken": {
       "duration": 1800
     },
+    "emailDomains": {
+      "allowlist": null,
+      "checkMX": false,
+      "denyDisposable": false,
+      "denylist": null
+    },
     "fields": [
       {
         "autogeneratePattern": "[a-z0-9]{15}",
+        "collation": "",
         "hidden": false,
         "id": "text@TEST_RANDOM",
         "max": 15,
         "min": 15,
         "name": "id",
+        "normalize": false,
         "pattern": "^[a-z0-9]+$",
         "presentable": false,
         "primaryKey": true,
@@ -518,11 +616,13 @@ migrate((app) => {
       },
       {
         "autogeneratePattern": "[a-zA-Z0-9]{50}",
+        "collation": "",
         "hidden": true,
         "id": "text@TEST_RANDOM",
         "max": 60,
         "min": 30,
         "name": "tokenKey",
+        "normalize": false,
         "pattern": "",
         "presentable": false,
         "primaryKey": false,
@@ -569,6 +669,18 @@ migrate((app) => {
       "CREATE UNIQUE INDEX ` + "`" + `idx_tokenKey_@TEST_RANDOM` + "`" + ` ON ` + "`" + `test123` + "`" + ` (` + "`" + `tokenKey` + "`" + `)",
       "CREATE UNIQUE INDEX ` + "`" + `idx_email_@TEST_RANDOM` + "`" + ` ON ` + "`" + `test123` + "`" + ` (` + "`" + `email` + "`" + `) WHERE ` + "`" + `email` + "`" + ` != ''"
     ],
+    "ldapAuth": {
+      "baseDN": "",
+      "bindDN": "",
+      "emailAttribute": "mail",
+      "enabled": false,
+      "idAttribute": "",
+      "mappedFields": null,
+      "skipVerify": false,
+      "startTLS": false,
+      "url": "",
+      "userFilter": "(uid={username})"
+    },
     "listRule": "@request.auth.id != '' && 1 > 0 || 'backtick` + "`" + `test' = 0",
     "manageRule": "1 != 2",
     "mfa": {
@@ -581,6 +693,7 @@ migrate((app) => {
       "enabled": false,
       "mappedFields": {
         "avatarURL": "",
+        "groups": "",
         "id": "",
         "name": "",
         "username": ""
@@ -604,6 +717,10 @@ migrate((app) => {
     "passwordResetToken": {
       "duration": 1800
     },
+    "profileCompletion": {
+      "enabled": false,
+      "fields": null
+    },
     "resetPasswordTemplate": {
       "body": "<p>Hello,</p>\n<p>Click on the button below to reset your password.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Reset password</a>\n</p>\n<p><i>If you didn't ask to reset your password, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
       "subject": "Reset your {APP_NAME} password"
@@ -649,7 +766,7 @@ func init() {
 		jsonData := ` + "`" + `{
 			"authAlert": {
 				"emailTemplate": {
-					"body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+					"body": "<p>Hello,</p>\n<p>We noticed a login to your {APP_NAME} account from a new location.</p>\n<p>\n  IP address: {ALERT_IP}<br/>\n  Device: {ALERT_USER_AGENT}\n</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, you should immediately change your {APP_NAME} account password to revoke access from all other locations.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 					"subject": "Login from a new location"
 				},
 				"enabled": true
@@ -664,17 +781,37 @@ func init() {
 			},
 			"createRule": null,
 			"deleteRule": null,
+			"emailChange": {
+				"approvalTemplate": {
+					"body": "<p>Hello,</p>\n<p>We received a request to change your {APP_NAME} account email address to <strong>{NEW_EMAIL}</strong>.</p>\n<p>Click on the button below to approve the change. After that a confirmation link will be sent to the new email address.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-email-change/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Approve email change</a>\n</p>\n<p><strong>If you didn't ask to change your email address, you should immediately change your {APP_NAME} account password.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+					"subject": "Approve your {APP_NAME} email address change"
+				},
+				"notifyOldEmail": true,
+				"notifyTemplate": {
+					"body": "<p>Hello,</p>\n<p>The email address of your {APP_NAME} account was changed from <strong>{OLD_EMAIL}</strong> to <strong>{NEW_EMAIL}</strong>.</p>\n<p>If this was you, you may disregard this email.</p>\n<p><strong>If this wasn't you, please contact us as soon as possible to recover your account.</strong></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
+					"subject": "Your {APP_NAME} email address was changed"
+				},
+				"requireOldEmailApproval": false
+			},
 			"emailChangeToken": {
 				"duration": 1800
 			},
+			"emailDomains": {
+				"allowlist": null,
+				"checkMX": false,
+				"denyDisposable": false,
+				"denylist": null
+			},
 			"fields": [
 				{
 					"autogeneratePattern": "[a-z0-9]{15}",
+					"collation": "",
 					"hidden": false,
 					"id": "text@TEST_RANDOM",
 					"max": 15,
 					"min": 15,
 					"name": "id",
+					"normalize": false,
 					"pattern": "^[a-z0-9]+$",
 					"presentable": false,
 					"primaryKey": true,
@@ -697,11 +834,13 @@ func init() {
 				},
 				{
 					"autogeneratePattern": "[a-zA-Z0-9]{50}",
+					"collation": "",
 					"hidden": true,
 					"id": "text@TEST_RANDOM",
 					"max": 60,
 					"min": 30,
 					"name": "tokenKey",
+					"normalize": false,
 					"pattern": "",
 					"presentable": false,
 					"primaryKey": false,
@@ -748,6 +887,18 @@ func init() {
 				"CREATE UNIQUE INDEX ` + "` + \"`\" + `" + `idx_tokenKey_@TEST_RANDOM` + "` + \"`\" + `" + ` ON ` + "` + \"`\" + `" + `test123` + "` + \"`\" + `" + ` (` + "` + \"`\" + `" + `tokenKey` + "` + \"`\" + `" + `)",
 				"CREATE UNIQUE INDEX ` + "` + \"`\" + `" + `idx_email_@TEST_RANDOM` + "` + \"`\" + `" + ` ON ` + "` + \"`\" + `" + `test123` + "` + \"`\" + `" + ` (` + "` + \"`\" + `" + `email` + "` + \"`\" + `" + `) WHERE ` + "` + \"`\" + `" + `email` + "` + \"`\" + `" + ` != ''"
 			],
+			"ldapAuth": {
+				"baseDN": "",
+				"bindDN": "",
+				"emailAttribute": "mail",
+				"enabled": false,
+				"idAttribute": "",
+				"mappedFields": null,
+				"skipVerify": false,
+				"startTLS": false,
+				"url": "",
+				"userFilter": "(uid={username})"
+			},
 			"listRule": "@request.auth.id != '' && 1 > 0 || 'backtick` + "` + \"`\" + `" + `test' = 0",
 			"manageRule": "1 != 2",
 			"mfa": {
@@ -760,6 +911,7 @@ func init() {
 				"enabled": false,
 				"mappedFields": {
 					"avatarURL": "",
+					"groups": "",
 					"id": "",
 					"name": "",
 					"username": ""
@@ -783,6 +935,10 @@ func init() {
 			"passwordResetToken": {
 				"duration": 1800
 			},
+			"profileCompletion": {
+				"enabled": false,
+				"fields": null
+			},
 			"resetPasswordTemplate": {
 				"body": "<p>Hello,</p>\n<p>Click on the button below to reset your password.</p>\n<p>\n  <a class=\"btn\" href=\"{APP_URL}/_/#/auth/confirm-password-reset/{TOKEN}\" target=\"_blank\" rel=\"noopener\">Reset password</a>\n</p>\n<p><i>If you didn't ask to reset your password, you can ignore this email.</i></p>\n<p>\n  Thanks,<br/>\n  {APP_NAME} team\n</p>",
 				"subject": "Reset your {APP_NAME} password"
@@ -923,11 +1079,13 @@ migrate((app) => {
   // add field
   collection.fields.addAt(8, new Field({
     "autogeneratePattern": "",
+    "collation": "",
     "hidden": false,
     "id": "f4_id",
     "max": 0,
     "min": 0,
     "name": "f4_name",
+    "normalize": false,
     "pattern": "` + "`" + `test backtick` + "`" + `123",
     "presentable": false,
     "primaryKey": false,
@@ -1054,11 +1212,13 @@ func init() {
 		// add field
 		if err := collection.Fields.AddMarshaledJSONAt(8, []byte(` + "`" + `{
 			"autogeneratePattern": "",
+			"collation": "",
 			"hidden": false,
 			"id": "f4_id",
 			"max": 0,
 			"min": 0,
 			"name": "f4_name",
+			"normalize": false,
 			"pattern": "` + "` + \"`\" + `" + `test backtick` + "` + \"`\" + `" + `123",
 			"presentable": false,
 			"primaryKey": false,
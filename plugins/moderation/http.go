@@ -0,0 +1,84 @@
+package moderation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+var _ Moderator = (*HTTPModerator)(nil)
+
+// HTTPModerator is a reference [Moderator] implementation that sends
+// the file content to an external moderation HTTP API.
+//
+// The file is sent as raw POST request body with its mime type as
+// Content-Type header and the API is expected to respond with
+// a JSON serialized [Verdict], for example:
+//
+//	{"action":"flag","labels":["nudity"],"score":0.93}
+type HTTPModerator struct {
+	// Client is the HTTP client used to send the requests (default to [http.DefaultClient]).
+	Client *http.Client
+
+	// Headers is an optional list of extra request headers (e.g. Authorization).
+	//
+	// Headers with empty values are ignored.
+	Headers map[string]string
+
+	// URL is the moderation API endpoint.
+	URL string
+}
+
+// Moderate implements the [Moderator] interface.
+func (m *HTTPModerator) Moderate(ctx context.Context, file *filesystem.File, mimeType string) (*Verdict, error) {
+	f, err := file.Reader.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, m.URL, f)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", mimeType)
+	req.Header.Set("Accept", "application/json")
+	for k, v := range m.Headers {
+		if v != "" {
+			req.Header.Set(k, v)
+		}
+	}
+
+	client := m.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return nil, fmt.Errorf("moderation API responded with %d: %s", res.StatusCode, body)
+	}
+
+	verdict := &Verdict{}
+	if err := json.NewDecoder(res.Body).Decode(verdict); err != nil {
+		return nil, fmt.Errorf("failed to decode the moderation API verdict: %w", err)
+	}
+
+	switch verdict.Action {
+	case "", ActionAllow, ActionFlag, ActionBlock:
+		return verdict, nil
+	default:
+		return nil, fmt.Errorf("unknown moderation verdict action %q", verdict.Action)
+	}
+}
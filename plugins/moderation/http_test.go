@@ -0,0 +1,87 @@
+package moderation
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+func TestHTTPModerator(t *testing.T) {
+	t.Parallel()
+
+	var status int
+	var response string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("Expected POST request, got %s", r.Method)
+		}
+
+		if v := r.Header.Get("Content-Type"); v != "image/png" {
+			t.Errorf("Expected image/png Content-Type, got %q", v)
+		}
+
+		if v := r.Header.Get("Authorization"); v != "test_token" {
+			t.Errorf("Expected Authorization header, got %q", v)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != string(pngHeader) {
+			t.Errorf("Expected the file content as body, got %q", body)
+		}
+
+		w.WriteHeader(status)
+		w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	m := &HTTPModerator{
+		URL:     server.URL,
+		Headers: map[string]string{"Authorization": "test_token"},
+	}
+
+	file, err := filesystem.NewFileFromBytes(pngHeader, "test.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		name           string
+		status         int
+		response       string
+		expectedAction string
+		expectError    bool
+	}{
+		{"non-2xx response", 500, `{"action":"allow"}`, "", true},
+		{"invalid json", 200, `invalid`, "", true},
+		{"unknown action", 200, `{"action":"unknown"}`, "", true},
+		{"empty action", 200, `{}`, "", false},
+		{"flag action", 200, `{"action":"flag","labels":["a","b"],"score":0.5}`, ActionFlag, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			status = s.status
+			response = s.response
+
+			verdict, err := m.Moderate(context.Background(), file, "image/png")
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if verdict.Action != s.expectedAction {
+				t.Fatalf("Expected action %q, got %q", s.expectedAction, verdict.Action)
+			}
+		})
+	}
+}
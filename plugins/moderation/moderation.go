@@ -0,0 +1,285 @@
+// Package moderation implements a plugin that checks the new uploaded
+// image and video record files with a pluggable content [Moderator]
+// (e.g. an external moderation API or a local model).
+//
+// Depending on the moderator verdict the file could be stored as usual,
+// flagged (and optionally quarantined until approved) or blocked.
+// The verdicts are written to a record JSON metadata field keyed by the file name,
+// allowing them to be used in the API rules and filters, for example:
+//
+//	moderation !~ '"action":"flag"'
+//
+// Example usage:
+//
+//	moderation.MustRegister(app, moderation.Config{
+//		Moderator: &moderation.HTTPModerator{
+//			URL:     "https://moderation.example.com/check",
+//			Headers: map[string]string{"Authorization": "Bearer " + os.Getenv("MODERATION_TOKEN")},
+//		},
+//		Collections:       []string{"posts"},
+//		QuarantineFlagged: true,
+//	})
+package moderation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gabriel-vasile/mimetype"
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"github.com/pocketbase/pocketbase/tools/list"
+)
+
+// The supported moderation verdict actions.
+const (
+	ActionAllow = "allow"
+	ActionFlag  = "flag"
+	ActionBlock = "block"
+)
+
+const (
+	defaultMetadataField = "moderation"
+	defaultTimeout       = 30 * time.Second
+)
+
+var defaultMimeTypes = []string{"image/", "video/"}
+
+// Verdict defines a single file moderation result.
+type Verdict struct {
+	// Action is the moderation decision (ActionAllow, ActionFlag or ActionBlock).
+	//
+	// Empty action is treated as ActionAllow.
+	Action string `json:"action"`
+
+	// Labels is an optional list of the detected content categories (e.g. "nudity", "violence").
+	Labels []string `json:"labels,omitempty"`
+
+	// Score is an optional moderator confidence score.
+	Score float64 `json:"score,omitempty"`
+
+	// Reason is an optional human readable verdict description.
+	Reason string `json:"reason,omitempty"`
+}
+
+// Moderator defines the interface of a content moderation provider.
+type Moderator interface {
+	// Moderate checks the provided file content and returns its verdict.
+	Moderate(ctx context.Context, file *filesystem.File, mimeType string) (*Verdict, error)
+}
+
+// ModeratorFunc is an adapter to allow the use of an ordinary function as [Moderator].
+type ModeratorFunc func(ctx context.Context, file *filesystem.File, mimeType string) (*Verdict, error)
+
+// Moderate implements the [Moderator] interface.
+func (f ModeratorFunc) Moderate(ctx context.Context, file *filesystem.File, mimeType string) (*Verdict, error) {
+	return f(ctx, file, mimeType)
+}
+
+// Config defines the config options of the moderation plugin.
+//
+// NB! This plugin is considered experimental and its config options may change in the future.
+type Config struct {
+	// Moderator is the content moderation provider (required).
+	Moderator Moderator
+
+	// Collections is an optional list of collection names or ids
+	// which files to moderate (default to all collections).
+	Collections []string
+
+	// MimeTypes specifies the moderated file mime types or mime type
+	// prefixes ending with "/" (default to "image/" and "video/").
+	MimeTypes []string
+
+	// MetadataField specifies the record JSON field name where to
+	// store the file verdicts (default to "moderation").
+	//
+	// The field is created automatically as hidden JSON field for the
+	// listed Collections. For the other collections the verdicts are
+	// stored only if the field exists.
+	MetadataField string
+
+	// QuarantineFlagged specifies whether to quarantine the flagged
+	// files (aka. not servable until approved by a superuser).
+	QuarantineFlagged bool
+
+	// FailOpen specifies whether to allow the file upload if the
+	// moderator fails (by default the upload is rejected).
+	FailOpen bool
+
+	// Timeout specifies the max duration of a single file moderation (default to 30 seconds).
+	Timeout time.Duration
+}
+
+// MustRegister registers the moderation plugin to the provided app instance
+// and panic if it fails.
+func MustRegister(app core.App, config Config) {
+	if err := Register(app, config); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the moderation plugin to the provided app instance.
+func Register(app core.App, config Config) error {
+	if config.Moderator == nil {
+		return errors.New("the moderation Moderator is required")
+	}
+
+	p := &plugin{app: app, config: config}
+
+	if len(p.config.MimeTypes) == 0 {
+		p.config.MimeTypes = defaultMimeTypes
+	}
+
+	if p.config.MetadataField == "" {
+		p.config.MetadataField = defaultMetadataField
+	}
+
+	if p.config.Timeout <= 0 {
+		p.config.Timeout = defaultTimeout
+	}
+
+	p.app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		if err := p.ensureSchema(e.App); err != nil {
+			return err
+		}
+
+		return e.Next()
+	})
+
+	p.app.OnFileUpload(p.config.Collections...).BindFunc(p.onFileUpload)
+
+	return nil
+}
+
+type plugin struct {
+	app    core.App
+	config Config
+}
+
+// ensureSchema creates the metadata field of the listed collections (if missing).
+func (p *plugin) ensureSchema(app core.App) error {
+	for _, name := range p.config.Collections {
+		collection, err := app.FindCollectionByNameOrId(name)
+		if err != nil {
+			return err
+		}
+
+		if collection.IsView() || collection.Fields.GetByName(p.config.MetadataField) != nil {
+			continue
+		}
+
+		collection.Fields.Add(&core.JSONField{Name: p.config.MetadataField, Hidden: true})
+
+		if err := app.Save(collection); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *plugin) onFileUpload(e *core.FileUploadEvent) error {
+	mimeType, err := detectMimeType(e.File)
+	if err != nil {
+		return err
+	}
+
+	if !p.isModerated(mimeType) {
+		return e.Next()
+	}
+
+	ctx, cancel := context.WithTimeout(e.Context, p.config.Timeout)
+	defer cancel()
+
+	verdict, err := p.config.Moderator.Moderate(ctx, e.File, mimeType)
+	if err != nil {
+		if !p.config.FailOpen {
+			return fmt.Errorf("failed to moderate the file: %w", err)
+		}
+
+		e.App.Logger().Warn(
+			"Failed to moderate the file (fail open)",
+			"error", err,
+			"file", e.File.Name,
+			"collectionName", e.Record.Collection().Name,
+		)
+
+		return e.Next()
+	}
+
+	if verdict == nil {
+		verdict = &Verdict{}
+	}
+
+	if verdict.Action == "" {
+		verdict.Action = ActionAllow
+	}
+
+	if verdict.Action == ActionBlock {
+		return validation.Errors{
+			e.FileField.Name: validation.NewError(
+				"validation_file_blocked",
+				fmt.Sprintf("Failed to upload %q due to content moderation.", e.File.OriginalName),
+			),
+		}
+	}
+
+	if verdict.Action == ActionFlag && p.config.QuarantineFlagged {
+		e.Quarantine = true
+		e.QuarantineReason = "moderation"
+		if len(verdict.Labels) > 0 {
+			e.QuarantineReason += ": " + strings.Join(verdict.Labels, ", ")
+		}
+	}
+
+	p.writeVerdict(e.Record, e.File.Name, verdict)
+
+	return e.Next()
+}
+
+// writeVerdict stores the file verdict in the record metadata field (if exists).
+func (p *plugin) writeVerdict(record *core.Record, filename string, verdict *Verdict) {
+	if record.Collection().Fields.GetByName(p.config.MetadataField) == nil {
+		return
+	}
+
+	verdicts := map[string]*Verdict{}
+	_ = record.UnmarshalJSONField(p.config.MetadataField, &verdicts)
+
+	verdicts[filename] = verdict
+
+	record.Set(p.config.MetadataField, verdicts)
+}
+
+func (p *plugin) isModerated(mimeType string) bool {
+	for _, t := range p.config.MimeTypes {
+		if strings.HasSuffix(t, "/") && strings.HasPrefix(mimeType, t) {
+			return true
+		}
+	}
+
+	return list.ExistInSlice(mimeType, p.config.MimeTypes)
+}
+
+func detectMimeType(file *filesystem.File) (string, error) {
+	f, err := file.Reader.Open()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	mt, err := mimetype.DetectReader(f)
+	if err != nil {
+		return "", err
+	}
+
+	// strip the parameters (e.g. "text/plain; charset=utf-8")
+	mimeType, _, _ := strings.Cut(mt.String(), ";")
+
+	return mimeType, nil
+}
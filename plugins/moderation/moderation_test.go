@@ -0,0 +1,213 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+)
+
+// pngHeader is a minimal content detected as "image/png".
+var pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\x0dIHDR")
+
+func TestRegisterValidation(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := Register(app, Config{}); err == nil {
+		t.Fatal("Expected missing Moderator error")
+	}
+
+	err := Register(app, Config{Moderator: ModeratorFunc(func(ctx context.Context, file *filesystem.File, mimeType string) (*Verdict, error) {
+		return nil, nil
+	})})
+	if err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+}
+
+func TestEnsureSchema(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	p := &plugin{app: app, config: Config{
+		Collections:   []string{"demo3"},
+		MetadataField: defaultMetadataField,
+	}}
+
+	// run twice to ensure that it doesn't fail with existing field
+	for i := 0; i < 2; i++ {
+		if err := p.ensureSchema(app); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	collection, err := app.FindCollectionByNameOrId("demo3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	field, ok := collection.Fields.GetByName(defaultMetadataField).(*core.JSONField)
+	if !ok {
+		t.Fatalf("Expected %q json field to be created", defaultMetadataField)
+	}
+
+	if !field.Hidden {
+		t.Fatal("Expected the metadata field to be hidden")
+	}
+}
+
+func TestFileUploadModeration(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	var calls int
+
+	moderator := ModeratorFunc(func(ctx context.Context, file *filesystem.File, mimeType string) (*Verdict, error) {
+		calls++
+
+		if mimeType != "image/png" {
+			t.Fatalf("Expected image/png mime type, got %q", mimeType)
+		}
+
+		switch file.OriginalName {
+		case "flag.png":
+			return &Verdict{Action: ActionFlag, Labels: []string{"nudity"}, Score: 0.9}, nil
+		case "block.png":
+			return &Verdict{Action: ActionBlock}, nil
+		case "error.png":
+			return nil, errors.New("test")
+		default:
+			return nil, nil
+		}
+	})
+
+	MustRegister(app, Config{
+		Moderator:         moderator,
+		Collections:       []string{"demo3"},
+		QuarantineFlagged: true,
+	})
+
+	p := &plugin{app: app, config: Config{Collections: []string{"demo3"}, MetadataField: defaultMetadataField}}
+	if err := p.ensureSchema(app); err != nil {
+		t.Fatal(err)
+	}
+
+	collection, err := app.FindCollectionByNameOrId("demo3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	newFile := func(t *testing.T, name string, content []byte) *filesystem.File {
+		f, err := filesystem.NewFileFromBytes(content, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+
+	// allowed + flagged + not moderated
+	// ---
+	record := core.NewRecord(collection)
+	record.Set("title", "moderated")
+	record.Set("files", []any{
+		newFile(t, "allow.png", pngHeader),
+		newFile(t, "flag.png", pngHeader),
+		newFile(t, "notes.txt", []byte("test")),
+	})
+	if err := app.Save(record); err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 2 {
+		t.Fatalf("Expected 2 moderator calls, got %d", calls)
+	}
+
+	files := record.GetStringSlice("files")
+
+	record, err = app.FindRecordById(collection, record.Id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verdicts := map[string]*Verdict{}
+	if err := record.UnmarshalJSONField(defaultMetadataField, &verdicts); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(verdicts) != 2 {
+		t.Fatalf("Expected 2 stored verdicts, got %v", verdicts)
+	}
+
+	if v := verdicts[files[0]]; v == nil || v.Action != ActionAllow {
+		t.Fatalf("Expected allow verdict for %q, got %v", files[0], v)
+	}
+
+	if v := verdicts[files[1]]; v == nil || v.Action != ActionFlag || v.Labels[0] != "nudity" {
+		t.Fatalf("Expected flag verdict for %q, got %v", files[1], v)
+	}
+
+	if app.IsFileQuarantined(record, files[0]) {
+		t.Fatalf("Expected %q to not be quarantined", files[0])
+	}
+
+	if !app.IsFileQuarantined(record, files[1]) {
+		t.Fatalf("Expected %q to be quarantined", files[1])
+	}
+
+	// blocked
+	// ---
+	blocked := core.NewRecord(collection)
+	blocked.Set("title", "blocked")
+	blocked.Set("files", []any{newFile(t, "block.png", pngHeader)})
+
+	err = app.Save(blocked)
+
+	var validationErrs validation.Errors
+	if !errors.As(err, &validationErrs) || validationErrs["files"] == nil {
+		t.Fatalf("Expected files validation error, got %v", err)
+	}
+
+	// moderator failure
+	// ---
+	failed := core.NewRecord(collection)
+	failed.Set("title", "failed")
+	failed.Set("files", []any{newFile(t, "error.png", pngHeader)})
+	if err := app.Save(failed); err == nil {
+		t.Fatal("Expected moderator error to reject the upload")
+	}
+}
+
+func TestFileUploadModerationFailOpen(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	MustRegister(app, Config{
+		Moderator: ModeratorFunc(func(ctx context.Context, file *filesystem.File, mimeType string) (*Verdict, error) {
+			return nil, errors.New("test")
+		}),
+		Collections: []string{"demo3"},
+		FailOpen:    true,
+	})
+
+	collection, err := app.FindCollectionByNameOrId("demo3")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := filesystem.NewFileFromBytes(pngHeader, "error.png")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	record := core.NewRecord(collection)
+	record.Set("title", "fail open")
+	record.Set("files", []any{f})
+	if err := app.Save(record); err != nil {
+		t.Fatalf("Expected the upload to be allowed, got %v", err)
+	}
+}
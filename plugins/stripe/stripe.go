@@ -0,0 +1,201 @@
+// Package stripe implements a plugin that syncs the Stripe customers
+// and subscriptions to the app auth records via the Stripe webhooks.
+//
+// The synced subscriptions are stored in a dedicated collection and the
+// most relevant one is linked to its auth record with a single relation field,
+// allowing it to be used in the API rules, for example:
+//
+//	@request.auth.subscription.active = true
+//
+// Example usage:
+//
+//	stripe.MustRegister(app, stripe.Config{
+//		WebhookSecret: os.Getenv("STRIPE_WEBHOOK_SECRET"),
+//	})
+package stripe
+
+import (
+	"errors"
+	"time"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+const (
+	defaultWebhookPath             = "/api/stripe/webhook"
+	defaultAuthCollection          = "users"
+	defaultSubscriptionsCollection = "stripe_subscriptions"
+	defaultCustomerField           = "stripeCustomerId"
+	defaultSubscriptionField       = "subscription"
+	defaultMetadataKey             = "pocketbase_id"
+	defaultSignatureTolerance      = 5 * time.Minute
+)
+
+// Config defines the config options of the stripe plugin.
+//
+// NB! This plugin is considered experimental and its config options may change in the future.
+type Config struct {
+	// WebhookSecret is the Stripe webhook endpoint signing secret (whsec_...).
+	WebhookSecret string
+
+	// WebhookPath specifies the webhook endpoint route path
+	// (default to "/api/stripe/webhook").
+	WebhookPath string
+
+	// AuthCollection specifies the name of the auth collection
+	// which records are linked to the Stripe customers (default to "users").
+	AuthCollection string
+
+	// SubscriptionsCollection specifies the name of the collection where
+	// to store the synced subscriptions (default to "stripe_subscriptions").
+	//
+	// The collection is created automatically if missing.
+	SubscriptionsCollection string
+
+	// CustomerField specifies the auth collection field name that stores
+	// the Stripe customer id (default to "stripeCustomerId").
+	//
+	// The field is created automatically as hidden text field if missing.
+	CustomerField string
+
+	// SubscriptionField specifies the auth collection relation field name
+	// that links to the most relevant synced subscription (default to "subscription").
+	//
+	// The field is created automatically if missing.
+	SubscriptionField string
+
+	// MetadataKey specifies the Stripe customer (or subscription) metadata key
+	// that could hold the linked auth record id (default to "pocketbase_id").
+	//
+	// If the metadata key is not set, the customers are linked
+	// to the auth records by their email address.
+	MetadataKey string
+
+	// SignatureTolerance specifies the max allowed age of the webhook
+	// signature timestamp (default to 5 minutes).
+	SignatureTolerance time.Duration
+}
+
+// MustRegister registers the stripe plugin to the provided app instance
+// and panic if it fails.
+func MustRegister(app core.App, config Config) {
+	if err := Register(app, config); err != nil {
+		panic(err)
+	}
+}
+
+// Register registers the stripe plugin to the provided app instance.
+func Register(app core.App, config Config) error {
+	if config.WebhookSecret == "" {
+		return errors.New("the stripe WebhookSecret is required")
+	}
+
+	p := &plugin{app: app, config: config}
+
+	if p.config.WebhookPath == "" {
+		p.config.WebhookPath = defaultWebhookPath
+	}
+
+	if p.config.AuthCollection == "" {
+		p.config.AuthCollection = defaultAuthCollection
+	}
+
+	if p.config.SubscriptionsCollection == "" {
+		p.config.SubscriptionsCollection = defaultSubscriptionsCollection
+	}
+
+	if p.config.CustomerField == "" {
+		p.config.CustomerField = defaultCustomerField
+	}
+
+	if p.config.SubscriptionField == "" {
+		p.config.SubscriptionField = defaultSubscriptionField
+	}
+
+	if p.config.MetadataKey == "" {
+		p.config.MetadataKey = defaultMetadataKey
+	}
+
+	if p.config.SignatureTolerance <= 0 {
+		p.config.SignatureTolerance = defaultSignatureTolerance
+	}
+
+	p.app.OnServe().BindFunc(func(e *core.ServeEvent) error {
+		if err := p.ensureSchema(e.App); err != nil {
+			return err
+		}
+
+		e.Router.POST(p.config.WebhookPath, p.webhookHandler)
+
+		return e.Next()
+	})
+
+	return nil
+}
+
+type plugin struct {
+	app    core.App
+	config Config
+}
+
+// ensureSchema creates the subscriptions collection and
+// the auth collection Stripe fields (if missing).
+func (p *plugin) ensureSchema(app core.App) error {
+	authCollection, err := app.FindCollectionByNameOrId(p.config.AuthCollection)
+	if err != nil {
+		return err
+	}
+
+	if !authCollection.IsAuth() {
+		return errors.New("the stripe AuthCollection must be an auth collection")
+	}
+
+	subscriptions, err := app.FindCollectionByNameOrId(p.config.SubscriptionsCollection)
+	if err != nil {
+		subscriptions = core.NewBaseCollection(p.config.SubscriptionsCollection)
+		subscriptions.ListRule = types.Pointer("record = @request.auth.id")
+		subscriptions.ViewRule = types.Pointer("record = @request.auth.id")
+		subscriptions.Fields.Add(
+			&core.TextField{Name: "stripeId", Required: true},
+			&core.TextField{Name: "customer"},
+			&core.RelationField{Name: "record", CollectionId: authCollection.Id, MaxSelect: 1, CascadeDelete: true},
+			&core.TextField{Name: "status"},
+			&core.TextField{Name: "priceId"},
+			&core.DateField{Name: "currentPeriodEnd"},
+			&core.BoolField{Name: "cancelAtPeriodEnd"},
+			&core.BoolField{Name: "active"},
+			&core.NumberField{Name: "eventCreated", Hidden: true, OnlyInt: true},
+			&core.JSONField{Name: "data", Hidden: true},
+			&core.AutodateField{Name: "created", OnCreate: true},
+			&core.AutodateField{Name: "updated", OnCreate: true, OnUpdate: true},
+		)
+		subscriptions.AddIndex("idx_"+subscriptions.Name+"_stripeId", true, "stripeId", "")
+
+		if err := app.Save(subscriptions); err != nil {
+			return err
+		}
+	}
+
+	var changed bool
+
+	if authCollection.Fields.GetByName(p.config.CustomerField) == nil {
+		authCollection.Fields.Add(&core.TextField{Name: p.config.CustomerField, Hidden: true})
+		changed = true
+	}
+
+	if authCollection.Fields.GetByName(p.config.SubscriptionField) == nil {
+		authCollection.Fields.Add(&core.RelationField{
+			Name:         p.config.SubscriptionField,
+			CollectionId: subscriptions.Id,
+			MaxSelect:    1,
+		})
+		changed = true
+	}
+
+	if changed {
+		return app.Save(authCollection)
+	}
+
+	return nil
+}
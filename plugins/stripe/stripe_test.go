@@ -0,0 +1,80 @@
+package stripe
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tests"
+)
+
+func TestRegisterValidation(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	if err := Register(app, Config{}); err == nil {
+		t.Fatal("Expected missing WebhookSecret error")
+	}
+
+	if err := Register(app, Config{WebhookSecret: "test"}); err != nil {
+		t.Fatalf("Expected nil error, got %v", err)
+	}
+}
+
+func TestEnsureSchema(t *testing.T) {
+	app, _ := tests.NewTestApp()
+	defer app.Cleanup()
+
+	// non-auth collection
+	// ---
+	invalid := &plugin{app: app, config: Config{
+		AuthCollection:          "demo1",
+		SubscriptionsCollection: defaultSubscriptionsCollection,
+		CustomerField:           defaultCustomerField,
+		SubscriptionField:       defaultSubscriptionField,
+	}}
+	if err := invalid.ensureSchema(app); err == nil {
+		t.Fatal("Expected non-auth collection error")
+	}
+
+	// valid auth collection
+	// ---
+	p := &plugin{app: app, config: Config{
+		AuthCollection:          "users",
+		SubscriptionsCollection: "test_subs",
+		CustomerField:           "test_customer",
+		SubscriptionField:       "test_sub",
+	}}
+
+	// run twice to ensure that it is idempotent
+	for i := 0; i < 2; i++ {
+		if err := p.ensureSchema(app); err != nil {
+			t.Fatalf("[%d] Failed to ensure the schema: %v", i, err)
+		}
+	}
+
+	subs, err := app.FindCollectionByNameOrId("test_subs")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, name := range []string{"stripeId", "customer", "record", "status", "priceId", "currentPeriodEnd", "cancelAtPeriodEnd", "active", "eventCreated", "data"} {
+		if subs.Fields.GetByName(name) == nil {
+			t.Fatalf("Missing subscriptions collection field %q", name)
+		}
+	}
+
+	users, err := app.FindCollectionByNameOrId("users")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	customerField, ok := users.Fields.GetByName("test_customer").(*core.TextField)
+	if !ok || !customerField.Hidden {
+		t.Fatalf("Expected hidden test_customer text field, got %#v", users.Fields.GetByName("test_customer"))
+	}
+
+	subField, ok := users.Fields.GetByName("test_sub").(*core.RelationField)
+	if !ok || subField.CollectionId != subs.Id || subField.MaxSelect != 1 {
+		t.Fatalf("Expected test_sub single relation field, got %#v", users.Fields.GetByName("test_sub"))
+	}
+}
@@ -0,0 +1,332 @@
+package stripe
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/core"
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+// SignatureHeader is the Stripe webhook signature request header name.
+const SignatureHeader = "Stripe-Signature"
+
+// activeStatuses is a list of the subscription statuses considered as active.
+var activeStatuses = []string{"active", "trialing"}
+
+// VerifySignature verifies the Stripe webhook payload signature header
+// ("t=timestamp,v1=signature,...") with the provided endpoint secret.
+//
+// Signatures with timestamp older than tolerance are rejected.
+func VerifySignature(payload []byte, header string, secret string, tolerance time.Duration) error {
+	var timestamp string
+	var signatures []string
+
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+
+	if timestamp == "" || len(signatures) == 0 {
+		return errors.New("missing signature timestamp or v1 signatures")
+	}
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return errors.New("invalid signature timestamp")
+	}
+
+	if tolerance > 0 && time.Since(time.Unix(unix, 0)) > tolerance {
+		return errors.New("the signature timestamp is outside of the tolerance zone")
+	}
+
+	expected := computeSignature(timestamp, payload, secret)
+
+	for _, sig := range signatures {
+		raw, err := hex.DecodeString(sig)
+		if err == nil && hmac.Equal(raw, expected) {
+			return nil
+		}
+	}
+
+	return errors.New("no matching v1 signature")
+}
+
+func computeSignature(timestamp string, payload []byte, secret string) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+// -------------------------------------------------------------------
+
+type event struct {
+	Id      string `json:"id"`
+	Type    string `json:"type"`
+	Created int64  `json:"created"`
+	Data    struct {
+		Object json.RawMessage `json:"object"`
+	} `json:"data"`
+}
+
+type customer struct {
+	Metadata map[string]string `json:"metadata"`
+	Id       string            `json:"id"`
+	Email    string            `json:"email"`
+}
+
+type subscription struct {
+	Metadata          map[string]string `json:"metadata"`
+	Id                string            `json:"id"`
+	Customer          expandableId      `json:"customer"`
+	Status            string            `json:"status"`
+	CurrentPeriodEnd  int64             `json:"current_period_end"`
+	CancelAtPeriodEnd bool              `json:"cancel_at_period_end"`
+	Items             struct {
+		Data []struct {
+			Price struct {
+				Id string `json:"id"`
+			} `json:"price"`
+			CurrentPeriodEnd int64 `json:"current_period_end"`
+		} `json:"data"`
+	} `json:"items"`
+}
+
+// expandableId represents a Stripe object reference that
+// could be either a plain id string or an expanded object.
+type expandableId string
+
+func (v *expandableId) UnmarshalJSON(data []byte) error {
+	var id string
+	if err := json.Unmarshal(data, &id); err == nil {
+		*v = expandableId(id)
+		return nil
+	}
+
+	obj := struct {
+		Id string `json:"id"`
+	}{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return err
+	}
+
+	*v = expandableId(obj.Id)
+
+	return nil
+}
+
+func (p *plugin) webhookHandler(e *core.RequestEvent) error {
+	payload, err := io.ReadAll(e.Request.Body)
+	if err != nil {
+		return e.BadRequestError("Failed to read the webhook payload.", err)
+	}
+
+	err = VerifySignature(payload, e.Request.Header.Get(SignatureHeader), p.config.WebhookSecret, p.config.SignatureTolerance)
+	if err != nil {
+		return e.BadRequestError("Invalid webhook signature.", err)
+	}
+
+	ev := &event{}
+	if err := json.Unmarshal(payload, ev); err != nil {
+		return e.BadRequestError("Invalid webhook event.", err)
+	}
+
+	err = e.App.RunInTransaction(func(txApp core.App) error {
+		switch ev.Type {
+		case "customer.created", "customer.updated":
+			return p.syncCustomer(txApp, ev, false)
+		case "customer.deleted":
+			return p.syncCustomer(txApp, ev, true)
+		default:
+			if strings.HasPrefix(ev.Type, "customer.subscription.") {
+				return p.syncSubscription(txApp, ev)
+			}
+		}
+
+		return nil // ignore
+	})
+	if err != nil {
+		return e.InternalServerError("Failed to process the webhook event.", err)
+	}
+
+	return e.NoContent(204)
+}
+
+func (p *plugin) syncCustomer(txApp core.App, ev *event, deleted bool) error {
+	c := &customer{}
+	if err := json.Unmarshal(ev.Data.Object, c); err != nil {
+		return err
+	}
+
+	record, err := p.findAuthRecord(txApp, c.Id, c.Metadata, c.Email)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil // not linked
+		}
+		return err
+	}
+
+	if deleted {
+		if record.GetString(p.config.CustomerField) != c.Id {
+			return nil // already linked to another customer
+		}
+		record.Set(p.config.CustomerField, "")
+		record.Set(p.config.SubscriptionField, "")
+	} else {
+		record.Set(p.config.CustomerField, c.Id)
+	}
+
+	return txApp.Save(record)
+}
+
+func (p *plugin) syncSubscription(txApp core.App, ev *event) error {
+	s := &subscription{}
+	if err := json.Unmarshal(ev.Data.Object, s); err != nil {
+		return err
+	}
+
+	if s.Id == "" {
+		return errors.New("missing subscription id")
+	}
+
+	subscriptions, err := txApp.FindCollectionByNameOrId(p.config.SubscriptionsCollection)
+	if err != nil {
+		return err
+	}
+
+	sub, err := txApp.FindFirstRecordByData(subscriptions, "stripeId", s.Id)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		sub = core.NewRecord(subscriptions)
+		sub.Set("stripeId", s.Id)
+	} else if sub.GetInt("eventCreated") > int(ev.Created) {
+		return nil // out of order (stale) event
+	}
+
+	var authRecord *core.Record
+	authRecord, err = p.findAuthRecord(txApp, string(s.Customer), s.Metadata, "")
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return err
+	}
+
+	var priceId string
+	periodEnd := s.CurrentPeriodEnd
+	if len(s.Items.Data) > 0 {
+		priceId = s.Items.Data[0].Price.Id
+		if periodEnd == 0 {
+			periodEnd = s.Items.Data[0].CurrentPeriodEnd
+		}
+	}
+
+	active := ev.Type != "customer.subscription.deleted" && slices.Contains(activeStatuses, s.Status)
+
+	sub.Set("customer", string(s.Customer))
+	sub.Set("status", s.Status)
+	sub.Set("priceId", priceId)
+	sub.Set("cancelAtPeriodEnd", s.CancelAtPeriodEnd)
+	sub.Set("active", active)
+	sub.Set("eventCreated", ev.Created)
+	sub.Set("data", types.JSONRaw(ev.Data.Object))
+	if periodEnd > 0 {
+		sub.Set("currentPeriodEnd", time.Unix(periodEnd, 0).UTC())
+	}
+	if authRecord != nil {
+		sub.Set("record", authRecord.Id)
+	}
+
+	if err := txApp.Save(sub); err != nil {
+		return err
+	}
+
+	if authRecord == nil {
+		return nil
+	}
+
+	return p.linkSubscription(txApp, authRecord, sub)
+}
+
+// linkSubscription updates the auth record subscription relation
+// with the most relevant subscription (the changed one if active,
+// otherwise the first other active one, fallbacking to the changed one).
+func (p *plugin) linkSubscription(txApp core.App, authRecord *core.Record, sub *core.Record) error {
+	current := authRecord.GetString(p.config.SubscriptionField)
+
+	linked := sub.Id
+
+	if !sub.GetBool("active") {
+		other, err := txApp.FindFirstRecordByFilter(
+			sub.Collection(),
+			"record = {:record} && active = true && id != {:id}",
+			dbx.Params{"record": authRecord.Id, "id": sub.Id},
+		)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return err
+		}
+		if other != nil {
+			linked = other.Id
+		} else if current != "" && current != sub.Id {
+			return nil // keep the current (inactive) one
+		}
+	}
+
+	if current == linked {
+		return nil
+	}
+
+	authRecord.Set(p.config.SubscriptionField, linked)
+
+	return txApp.Save(authRecord)
+}
+
+// findAuthRecord looks for the auth record linked to the customer
+// by its customer id, metadata record id or email (in that order).
+func (p *plugin) findAuthRecord(txApp core.App, customerId string, metadata map[string]string, email string) (*core.Record, error) {
+	if customerId != "" {
+		record, err := txApp.FindFirstRecordByData(p.config.AuthCollection, p.config.CustomerField, customerId)
+		if err == nil {
+			return record, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	if id := metadata[p.config.MetadataKey]; id != "" {
+		record, err := txApp.FindRecordById(p.config.AuthCollection, id)
+		if err == nil {
+			return record, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, err
+		}
+	}
+
+	if email != "" {
+		return txApp.FindAuthRecordByEmail(p.config.AuthCollection, email)
+	}
+
+	return nil, sql.ErrNoRows
+}
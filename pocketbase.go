@@ -33,11 +33,12 @@ var Version = "(untracked)"
 type PocketBase struct {
 	core.App
 
-	devFlag           bool
-	dataDirFlag       string
-	encryptionEnvFlag string
-	queryTimeout      int
-	hideStartBanner   bool
+	devFlag             bool
+	dataDirFlag         string
+	encryptionEnvFlag   string
+	settingsOverlayFlag string
+	queryTimeout        int
+	hideStartBanner     bool
 
 	// RootCmd is the main console command
 	RootCmd *cobra.Command
@@ -49,10 +50,11 @@ type Config struct {
 	HideStartBanner bool
 
 	// optional default values for the console flags
-	DefaultDev           bool
-	DefaultDataDir       string // if not set, it will fallback to "./pb_data"
-	DefaultEncryptionEnv string
-	DefaultQueryTimeout  time.Duration // default to core.DefaultQueryTimeout (in seconds)
+	DefaultDev             bool
+	DefaultDataDir         string // if not set, it will fallback to "./pb_data"
+	DefaultEncryptionEnv   string
+	DefaultSettingsOverlay string        // optional environment specific settings json file
+	DefaultQueryTimeout    time.Duration // default to core.DefaultQueryTimeout (in seconds)
 
 	// optional DB configurations
 	DataMaxOpenConns int                // default to core.DefaultDataMaxOpenConns
@@ -111,10 +113,11 @@ func NewWithConfig(config Config) *PocketBase {
 				DisableDefaultCmd: true,
 			},
 		},
-		devFlag:           config.DefaultDev,
-		dataDirFlag:       config.DefaultDataDir,
-		encryptionEnvFlag: config.DefaultEncryptionEnv,
-		hideStartBanner:   config.HideStartBanner,
+		devFlag:             config.DefaultDev,
+		dataDirFlag:         config.DefaultDataDir,
+		encryptionEnvFlag:   config.DefaultEncryptionEnv,
+		settingsOverlayFlag: config.DefaultSettingsOverlay,
+		hideStartBanner:     config.HideStartBanner,
 	}
 
 	// replace with a colored stderr writer
@@ -126,15 +129,16 @@ func NewWithConfig(config Config) *PocketBase {
 
 	// initialize the app instance
 	pb.App = core.NewBaseApp(core.BaseAppConfig{
-		IsDev:            pb.devFlag,
-		DataDir:          pb.dataDirFlag,
-		EncryptionEnv:    pb.encryptionEnvFlag,
-		QueryTimeout:     time.Duration(pb.queryTimeout) * time.Second,
-		DataMaxOpenConns: config.DataMaxOpenConns,
-		DataMaxIdleConns: config.DataMaxIdleConns,
-		AuxMaxOpenConns:  config.AuxMaxOpenConns,
-		AuxMaxIdleConns:  config.AuxMaxIdleConns,
-		DBConnect:        config.DBConnect,
+		IsDev:               pb.devFlag,
+		DataDir:             pb.dataDirFlag,
+		EncryptionEnv:       pb.encryptionEnvFlag,
+		QueryTimeout:        time.Duration(pb.queryTimeout) * time.Second,
+		DataMaxOpenConns:    config.DataMaxOpenConns,
+		DataMaxIdleConns:    config.DataMaxIdleConns,
+		AuxMaxOpenConns:     config.AuxMaxOpenConns,
+		AuxMaxIdleConns:     config.AuxMaxIdleConns,
+		DBConnect:           config.DBConnect,
+		SettingsOverlayFile: pb.settingsOverlayFlag,
 	})
 
 	// hide the default help command (allow only `--help` flag)
@@ -162,11 +166,13 @@ func NewWithConfig(config Config) *PocketBase {
 }
 
 // Start starts the application, aka. registers the default system
-// commands (serve, superuser, version) and executes pb.RootCmd.
+// commands (serve, superuser, dump, maintenance, version) and executes pb.RootCmd.
 func (pb *PocketBase) Start() error {
 	// register system commands
 	pb.RootCmd.AddCommand(cmd.NewSuperuserCommand(pb))
 	pb.RootCmd.AddCommand(cmd.NewServeCommand(pb, !pb.hideStartBanner))
+	pb.RootCmd.AddCommand(cmd.NewDumpCommand(pb))
+	pb.RootCmd.AddCommand(cmd.NewMaintenanceCommand(pb))
 
 	return pb.Execute()
 }
@@ -229,6 +235,13 @@ func (pb *PocketBase) eagerParseFlags(config *Config) error {
 		"the env variable whose value of 32 characters will be used \nas encryption key for the app settings (default none)",
 	)
 
+	pb.RootCmd.PersistentFlags().StringVar(
+		&pb.settingsOverlayFlag,
+		"settingsOverlay",
+		config.DefaultSettingsOverlay,
+		"optional json file with environment specific read-only settings \nthat are applied on top of the stored ones (default none)",
+	)
+
 	pb.RootCmd.PersistentFlags().BoolVar(
 		&pb.devFlag,
 		"dev",
@@ -26,6 +26,8 @@ type TestApp struct {
 	EventCalls map[string]int
 
 	TestMailer *TestMailer
+
+	TestSMSClient *TestSMSClient
 }
 
 // Cleanup resets the test application state and removes the test
@@ -38,6 +40,7 @@ func (t *TestApp) Cleanup() {
 
 	t.OnTerminate().Trigger(event, func(e *core.TerminateEvent) error {
 		t.TestMailer.Reset()
+		t.TestSMSClient.Reset()
 		t.ResetEventCalls()
 		t.ResetBootstrapState()
 
@@ -134,6 +137,8 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		BaseApp:    app,
 		EventCalls: make(map[string]int),
 		TestMailer: &TestMailer{},
+
+		TestSMSClient: &TestSMSClient{},
 	}
 
 	t.OnBootstrap().Bind(&hook.Handler[*core.BootstrapEvent]{
@@ -360,6 +365,14 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnRecordTransition().Bind(&hook.Handler[*core.RecordTransitionEvent]{
+		Func: func(e *core.RecordTransitionEvent) error {
+			t.registerEventCall("OnRecordTransition")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnRecordDelete().Bind(&hook.Handler[*core.RecordEvent]{
 		Func: func(e *core.RecordEvent) error {
 			t.registerEventCall("OnRecordDelete")
@@ -508,6 +521,42 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnSMSSend().Bind(&hook.Handler[*core.SMSEvent]{
+		Func: func(e *core.SMSEvent) error {
+			if t.TestSMSClient == nil {
+				t.TestSMSClient = &TestSMSClient{}
+			}
+			e.Client = t.TestSMSClient
+			t.registerEventCall("OnSMSSend")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
+	t.OnUsageTrack().Bind(&hook.Handler[*core.UsageTrackEvent]{
+		Func: func(e *core.UsageTrackEvent) error {
+			t.registerEventCall("OnUsageTrack")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
+	t.OnUsageRollup().Bind(&hook.Handler[*core.UsageRollupEvent]{
+		Func: func(e *core.UsageRollupEvent) error {
+			t.registerEventCall("OnUsageRollup")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
+	t.OnOutboxMessageDeliver().Bind(&hook.Handler[*core.OutboxMessageEvent]{
+		Func: func(e *core.OutboxMessageEvent) error {
+			t.registerEventCall("OnOutboxMessageDeliver")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnMailerRecordAuthAlertSend().Bind(&hook.Handler[*core.MailerRecordEvent]{
 		Func: func(e *core.MailerRecordEvent) error {
 			t.registerEventCall("OnMailerRecordAuthAlertSend")
@@ -548,6 +597,14 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnMailerRecordBulkSend().Bind(&hook.Handler[*core.MailerRecordEvent]{
+		Func: func(e *core.MailerRecordEvent) error {
+			t.registerEventCall("OnMailerRecordBulkSend")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnRealtimeConnectRequest().Bind(&hook.Handler[*core.RealtimeConnectRequestEvent]{
 		Func: func(e *core.RealtimeConnectRequestEvent) error {
 			t.registerEventCall("OnRealtimeConnectRequest")
@@ -596,6 +653,14 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnFileUpload().Bind(&hook.Handler[*core.FileUploadEvent]{
+		Func: func(e *core.FileUploadEvent) error {
+			t.registerEventCall("OnFileUpload")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnFileDownloadRequest().Bind(&hook.Handler[*core.FileDownloadRequestEvent]{
 		Func: func(e *core.FileDownloadRequestEvent) error {
 			t.registerEventCall("OnFileDownloadRequest")
@@ -620,6 +685,22 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnRecordAuthFailure().Bind(&hook.Handler[*core.RecordAuthFailureEvent]{
+		Func: func(e *core.RecordAuthFailureEvent) error {
+			t.registerEventCall("OnRecordAuthFailure")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
+	t.OnRecordAuthTokenClaims().Bind(&hook.Handler[*core.RecordAuthTokenClaimsEvent]{
+		Func: func(e *core.RecordAuthTokenClaimsEvent) error {
+			t.registerEventCall("OnRecordAuthTokenClaims")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnRecordAuthWithPasswordRequest().Bind(&hook.Handler[*core.RecordAuthWithPasswordRequestEvent]{
 		Func: func(e *core.RecordAuthWithPasswordRequestEvent) error {
 			t.registerEventCall("OnRecordAuthWithPasswordRequest")
@@ -636,6 +717,14 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnRecordAuthWithLDAPRequest().Bind(&hook.Handler[*core.RecordAuthWithLDAPRequestEvent]{
+		Func: func(e *core.RecordAuthWithLDAPRequestEvent) error {
+			t.registerEventCall("OnRecordAuthWithLDAPRequest")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnRecordAuthRefreshRequest().Bind(&hook.Handler[*core.RecordAuthRefreshRequestEvent]{
 		Func: func(e *core.RecordAuthRefreshRequestEvent) error {
 			t.registerEventCall("OnRecordAuthRefreshRequest")
@@ -748,6 +837,22 @@ func NewTestAppWithConfig(config core.BaseAppConfig) (*TestApp, error) {
 		Priority: -99999,
 	})
 
+	t.OnRecordSyncConflictRequest().Bind(&hook.Handler[*core.RecordSyncConflictRequestEvent]{
+		Func: func(e *core.RecordSyncConflictRequestEvent) error {
+			t.registerEventCall("OnRecordSyncConflictRequest")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
+	t.OnRecordRequestTransform().Bind(&hook.Handler[*core.RecordRequestTransformEvent]{
+		Func: func(e *core.RecordRequestTransformEvent) error {
+			t.registerEventCall("OnRecordRequestTransform")
+			return e.Next()
+		},
+		Priority: -99999,
+	})
+
 	t.OnCollectionsListRequest().Bind(&hook.Handler[*core.CollectionsListRequestEvent]{
 		Func: func(e *core.CollectionsListRequestEvent) error {
 			t.registerEventCall("OnCollectionsListRequest")
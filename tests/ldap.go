@@ -0,0 +1,319 @@
+package tests
+
+import (
+	"bufio"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+// TestLDAPEntry defines a single [TestLDAPServer] directory entry.
+type TestLDAPEntry struct {
+	DN         string
+	Password   string
+	Attributes map[string][]string
+}
+
+// TestLDAPServer is a minimal in-memory LDAP server intended to be used in tests.
+//
+// It supports simple bind, search (with the and, or, not, equality,
+// presence and substrings filters) and StartTLS operations.
+type TestLDAPServer struct {
+	mux      sync.Mutex
+	listener net.Listener
+	entries  []*TestLDAPEntry
+	binds    []string
+	tls      *tls.Config
+}
+
+// NewTestLDAPServer starts a new [TestLDAPServer] on a random local port
+// with the specified directory entries.
+//
+// Don't forget to call [TestLDAPServer.Close] when done.
+func NewTestLDAPServer(entries ...*TestLDAPEntry) (*TestLDAPServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	tlsConfig, err := selfSignedTLSConfig()
+	if err != nil {
+		listener.Close()
+		return nil, err
+	}
+
+	s := &TestLDAPServer{
+		listener: listener,
+		entries:  entries,
+		tls:      tlsConfig,
+	}
+
+	go s.serve()
+
+	return s, nil
+}
+
+// URL returns the server "ldap://" url.
+func (s *TestLDAPServer) URL() string {
+	return "ldap://" + s.listener.Addr().String()
+}
+
+// Close stops the server.
+func (s *TestLDAPServer) Close() error {
+	return s.listener.Close()
+}
+
+// Binds returns the list of the DNs of all successful bind operations.
+func (s *TestLDAPServer) Binds() []string {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return slices.Clone(s.binds)
+}
+
+func (s *TestLDAPServer) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+
+		go s.handle(conn)
+	}
+}
+
+func (s *TestLDAPServer) handle(conn net.Conn) {
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+
+	for {
+		msg, err := ldap.ReadPacket(reader)
+		if err != nil || len(msg.Children) < 2 {
+			return
+		}
+
+		id := msg.Children[0].Int()
+		op := msg.Children[1]
+
+		reply := func(ops ...*ldap.Packet) error {
+			for _, op := range ops {
+				if _, err := conn.Write(ldap.NewSequence(ldap.NewInteger(id), op).Bytes()); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+
+		switch op.Tag {
+		case ldap.ApplicationUnbindRequest:
+			return
+		case ldap.ApplicationBindRequest:
+			if reply(s.bind(op)) != nil {
+				return
+			}
+		case ldap.ApplicationSearchRequest:
+			if reply(s.search(op)...) != nil {
+				return
+			}
+		case ldap.ApplicationExtendedRequest:
+			if op.Child(0).String() != ldap.StartTLSOID {
+				if reply(testLDAPResult(ldap.ApplicationExtendedResponse, ldap.ResultProtocolError, "unsupported operation")) != nil {
+					return
+				}
+				continue
+			}
+
+			if reply(testLDAPResult(ldap.ApplicationExtendedResponse, ldap.ResultSuccess, "")) != nil {
+				return
+			}
+
+			tlsConn := tls.Server(conn, s.tls)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn = tlsConn
+			reader = bufio.NewReader(tlsConn)
+		default:
+			return
+		}
+	}
+}
+
+func (s *TestLDAPServer) bind(op *ldap.Packet) *ldap.Packet {
+	dn := op.Child(1).String()
+	password := op.Child(2).String()
+
+	if dn == "" && password == "" {
+		return testLDAPResult(ldap.ApplicationBindResponse, ldap.ResultSuccess, "") // anonymous
+	}
+
+	for _, entry := range s.entries {
+		if strings.EqualFold(entry.DN, dn) && entry.Password != "" && entry.Password == password {
+			s.mux.Lock()
+			s.binds = append(s.binds, entry.DN)
+			s.mux.Unlock()
+
+			return testLDAPResult(ldap.ApplicationBindResponse, ldap.ResultSuccess, "")
+		}
+	}
+
+	return testLDAPResult(ldap.ApplicationBindResponse, ldap.ResultInvalidCredentials, "invalid credentials")
+}
+
+func (s *TestLDAPServer) search(op *ldap.Packet) []*ldap.Packet {
+	baseDN := strings.ToLower(op.Child(0).String())
+	sizeLimit := int(op.Child(3).Int())
+	filter := op.Child(6)
+
+	var attrs []string
+	for _, attr := range op.Child(7).Children {
+		attrs = append(attrs, attr.String())
+	}
+
+	var result []*ldap.Packet
+
+	for _, entry := range s.entries {
+		if !strings.HasSuffix(strings.ToLower(entry.DN), baseDN) || !testLDAPMatch(filter, entry) {
+			continue
+		}
+
+		if sizeLimit > 0 && len(result) >= sizeLimit {
+			return append(result, testLDAPResult(ldap.ApplicationSearchResultDone, ldap.ResultSizeLimitExceeded, ""))
+		}
+
+		attributes := ldap.NewSequence()
+		for name, values := range entry.Attributes {
+			if len(attrs) > 0 && !slices.ContainsFunc(attrs, func(a string) bool { return strings.EqualFold(a, name) }) {
+				continue
+			}
+
+			set := ldap.NewSet()
+			for _, v := range values {
+				set.Children = append(set.Children, ldap.NewString(v))
+			}
+			attributes.Children = append(attributes.Children, ldap.NewSequence(ldap.NewString(name), set))
+		}
+
+		result = append(result, ldap.NewConstructed(
+			ldap.ClassApplication,
+			ldap.ApplicationSearchResultEntry,
+			ldap.NewString(entry.DN),
+			attributes,
+		))
+	}
+
+	return append(result, testLDAPResult(ldap.ApplicationSearchResultDone, ldap.ResultSuccess, ""))
+}
+
+func testLDAPResult(tag byte, code int, message string) *ldap.Packet {
+	return ldap.NewConstructed(
+		ldap.ClassApplication,
+		tag,
+		ldap.NewEnumerated(int64(code)),
+		ldap.NewString(""),
+		ldap.NewString(message),
+	)
+}
+
+func testLDAPMatch(filter *ldap.Packet, entry *TestLDAPEntry) bool {
+	values := func(name string) []string {
+		for k, v := range entry.Attributes {
+			if strings.EqualFold(k, name) {
+				return v
+			}
+		}
+		return nil
+	}
+
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !testLDAPMatch(child, entry) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if testLDAPMatch(child, entry) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !testLDAPMatch(filter.Child(0), entry)
+	case ldap.FilterPresent:
+		return len(values(filter.String())) > 0
+	case ldap.FilterEqualityMatch, ldap.FilterApproxMatch:
+		expected := filter.Child(1).String()
+		return slices.ContainsFunc(values(filter.Child(0).String()), func(v string) bool {
+			return strings.EqualFold(v, expected)
+		})
+	case ldap.FilterSubstrings:
+		return slices.ContainsFunc(values(filter.Child(0).String()), func(v string) bool {
+			v = strings.ToLower(v)
+			for _, part := range filter.Child(1).Children {
+				sub := strings.ToLower(part.String())
+				switch part.Tag {
+				case ldap.FilterSubstringsInitial:
+					if !strings.HasPrefix(v, sub) {
+						return false
+					}
+					v = v[len(sub):]
+				case ldap.FilterSubstringsFinal:
+					if !strings.HasSuffix(v, sub) {
+						return false
+					}
+					v = v[:len(v)-len(sub)]
+				default:
+					i := strings.Index(v, sub)
+					if i < 0 {
+						return false
+					}
+					v = v[i+len(sub):]
+				}
+			}
+			return true
+		})
+	}
+
+	return false
+}
+
+func selfSignedTLSConfig() (*tls.Config, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     time.Now().Add(1 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{{Certificate: [][]byte{der}, PrivateKey: key}},
+	}, nil
+}
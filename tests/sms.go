@@ -0,0 +1,65 @@
+package tests
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/pocketbase/pocketbase/tools/sms"
+)
+
+var _ sms.Sender = (*TestSMSClient)(nil)
+
+// TestSMSClient is a mock [sms.Sender] implementation.
+type TestSMSClient struct {
+	mux      sync.Mutex
+	messages []*sms.Message
+}
+
+// Send implements [sms.Sender] interface.
+func (tc *TestSMSClient) Send(m *sms.Message) error {
+	tc.mux.Lock()
+	defer tc.mux.Unlock()
+
+	tc.messages = append(tc.messages, m)
+	return nil
+}
+
+// Reset clears any previously test collected data.
+func (tc *TestSMSClient) Reset() {
+	tc.mux.Lock()
+	defer tc.mux.Unlock()
+
+	tc.messages = nil
+}
+
+// TotalSend returns the total number of sent messages.
+func (tc *TestSMSClient) TotalSend() int {
+	tc.mux.Lock()
+	defer tc.mux.Unlock()
+
+	return len(tc.messages)
+}
+
+// Messages returns a shallow copy of all of the collected test messages.
+func (tc *TestSMSClient) Messages() []*sms.Message {
+	tc.mux.Lock()
+	defer tc.mux.Unlock()
+
+	return slices.Clone(tc.messages)
+}
+
+// LastMessage returns a shallow copy of the last sent message.
+//
+// Returns an empty sms.Message struct if there are no sent messages.
+func (tc *TestSMSClient) LastMessage() sms.Message {
+	tc.mux.Lock()
+	defer tc.mux.Unlock()
+
+	var m sms.Message
+
+	if len(tc.messages) > 0 {
+		return *tc.messages[len(tc.messages)-1]
+	}
+
+	return m
+}
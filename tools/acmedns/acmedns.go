@@ -0,0 +1,622 @@
+// Package acmedns implements an ACME certificate manager that solves the
+// DNS-01 challenges through a DNS provider API.
+//
+// Unlike the HTTP-01 and TLS-ALPN-01 challenges (used by [autocert.Manager]),
+// the DNS-01 challenge doesn't require the server to be publicly reachable
+// and it is the only challenge type that allows issuing wildcard certificates.
+//
+// Example:
+//
+//	provider, err := acmedns.NewProviderFromEnv("cloudflare")
+//	if err != nil {
+//		return err
+//	}
+//
+//	m := &acmedns.Manager{
+//		Provider: provider,
+//		Cache:    autocert.DirCache("certs"),
+//		Domains:  []string{"example.com", "*.example.com"},
+//	}
+//
+//	server := &http.Server{
+//		TLSConfig: &tls.Config{GetCertificate: m.GetCertificate},
+//	}
+package acmedns
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+const (
+	// DefaultRenewBefore is the default duration before the certificate
+	// expiration when its renewal will be attempted.
+	DefaultRenewBefore = 30 * 24 * time.Hour
+
+	// DefaultPropagationTimeout is the default max duration to wait
+	// for the challenge TXT record to become visible.
+	DefaultPropagationTimeout = 2 * time.Minute
+
+	propagationPollInterval = 5 * time.Second
+	renewRetryInterval      = 1 * time.Hour
+	issueTimeout            = 5 * time.Minute
+	accountKeyCacheKey      = "acme_account+dns01+key"
+	certCacheKeyPrefix      = "dns01+"
+	challengeLabel          = "_acme-challenge."
+)
+
+// Provider defines a DNS provider capable of solving the ACME DNS-01 challenges.
+type Provider interface {
+	// Present creates a TXT record with the specified fqdn and value.
+	Present(ctx context.Context, fqdn string, value string) error
+
+	// CleanUp removes the TXT record previously created with Present.
+	CleanUp(ctx context.Context, fqdn string, value string) error
+}
+
+// Manager is an ACME certificate manager that issues and renews
+// certificates using the DNS-01 challenge.
+//
+// Its GetCertificate method could be used directly as [tls.Config.GetCertificate].
+type Manager struct {
+	// Provider is the DNS provider used to solve the DNS-01 challenges (required).
+	Provider Provider
+
+	// Cache is an optional storage for the account key and the issued certificates.
+	//
+	// Without a cache a new certificate will be issued on each app start.
+	Cache autocert.Cache
+
+	// Domains is the list of allowed certificate domains.
+	//
+	// A domain could be a wildcard in the format "*.example.com" and
+	// in that case it matches only the direct subdomains (eg. "a.example.com").
+	//
+	// Each domain entry is issued as a separate certificate.
+	Domains []string
+
+	// HostPolicy is an optional function that allows issuing certificates
+	// on-demand for host names that don't match any of the Domains.
+	//
+	// The host is allowed if the function returns nil error.
+	HostPolicy func(ctx context.Context, host string) error
+
+	// Email is an optional ACME account contact email.
+	Email string
+
+	// DirectoryURL is the ACME directory url (default to [acme.LetsEncryptURL]).
+	DirectoryURL string
+
+	// RenewBefore specifies how early the certificate should be renewed
+	// before it expires (default to [DefaultRenewBefore]).
+	RenewBefore time.Duration
+
+	// PropagationTimeout specifies the max duration to wait for the
+	// challenge TXT record to be resolvable before notifying the
+	// ACME server (default to [DefaultPropagationTimeout]).
+	//
+	// Set it to a negative value to skip the propagation check.
+	PropagationTimeout time.Duration
+
+	// Resolver is an optional custom resolver used for the propagation check.
+	Resolver *net.Resolver
+
+	client    *acme.Client
+	certs     map[string]*tls.Certificate
+	lastRenew map[string]time.Time
+	mu        sync.Mutex
+	issueMu   sync.Mutex
+}
+
+// GetCertificate implements the [tls.Config.GetCertificate] hook
+// by loading or issuing a certificate for the SNI server name.
+func (m *Manager) GetCertificate(hello *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	name := strings.TrimSuffix(strings.ToLower(hello.ServerName), ".")
+	if name == "" {
+		return nil, errors.New("acmedns: missing server name")
+	}
+
+	domain, ok := m.matchDomain(name)
+	if !ok {
+		if m.HostPolicy == nil {
+			return nil, fmt.Errorf("acmedns: host %q is not configured", name)
+		}
+
+		ctx := hello.Context()
+		if ctx == nil {
+			ctx = context.Background()
+		}
+
+		if err := m.HostPolicy(ctx, name); err != nil {
+			return nil, err
+		}
+
+		domain = name
+	}
+
+	return m.cert(domain)
+}
+
+// matchDomain returns the configured domain that matches the provided host name.
+func (m *Manager) matchDomain(name string) (string, bool) {
+	// exact match
+	for _, domain := range m.Domains {
+		if strings.EqualFold(domain, name) {
+			return strings.ToLower(domain), true
+		}
+	}
+
+	// wildcard match
+	for _, domain := range m.Domains {
+		suffix, ok := strings.CutPrefix(strings.ToLower(domain), "*")
+		if !ok {
+			continue
+		}
+
+		label, ok := strings.CutSuffix(name, suffix)
+		if ok && label != "" && !strings.Contains(label, ".") {
+			return strings.ToLower(domain), true
+		}
+	}
+
+	return "", false
+}
+
+func (m *Manager) cert(domain string) (*tls.Certificate, error) {
+	m.mu.Lock()
+	if m.certs == nil {
+		m.certs = map[string]*tls.Certificate{}
+	}
+	cert := m.certs[domain]
+	m.mu.Unlock()
+
+	if cert == nil && m.Cache != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		cert, _ = m.loadCachedCert(ctx, domain)
+		cancel()
+
+		if cert != nil {
+			m.mu.Lock()
+			m.certs[domain] = cert
+			m.mu.Unlock()
+		}
+	}
+
+	if cert != nil && time.Now().Before(cert.Leaf.NotAfter) {
+		if time.Until(cert.Leaf.NotAfter) < m.renewBefore() {
+			m.renewInBackground(domain)
+		}
+
+		return cert, nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+	defer cancel()
+
+	return m.issue(ctx, domain, false)
+}
+
+func (m *Manager) renewBefore() time.Duration {
+	if m.RenewBefore > 0 {
+		return m.RenewBefore
+	}
+
+	return DefaultRenewBefore
+}
+
+func (m *Manager) renewInBackground(domain string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.lastRenew == nil {
+		m.lastRenew = map[string]time.Time{}
+	}
+
+	if time.Since(m.lastRenew[domain]) < renewRetryInterval {
+		return // renewal is in progress or was recently attempted
+	}
+
+	m.lastRenew[domain] = time.Now()
+
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), issueTimeout)
+		defer cancel()
+
+		// errors are ignored and the renewal will be retried on a later handshake
+		_, _ = m.issue(ctx, domain, true)
+	}()
+}
+
+// issue obtains a new certificate for the specified domain.
+//
+// The certificates issuing is serialized because the challenge records
+// of a domain and its wildcard share the same fqdn.
+func (m *Manager) issue(ctx context.Context, domain string, force bool) (*tls.Certificate, error) {
+	m.issueMu.Lock()
+	defer m.issueMu.Unlock()
+
+	// check again in case the certificate was issued while waiting for the lock
+	if !force {
+		m.mu.Lock()
+		cert := m.certs[domain]
+		m.mu.Unlock()
+
+		if cert != nil && time.Now().Before(cert.Leaf.NotAfter) {
+			return cert, nil
+		}
+	}
+
+	if m.Provider == nil {
+		return nil, errors.New("acmedns: missing DNS provider")
+	}
+
+	client, err := m.acmeClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := client.AuthorizeOrder(ctx, acme.DomainIDs(domain))
+	if err != nil {
+		return nil, fmt.Errorf("acmedns: failed to create order for %q: %w", domain, err)
+	}
+
+	for _, authzURL := range order.AuthzURLs {
+		if err := m.authorize(ctx, client, authzURL); err != nil {
+			return nil, err
+		}
+	}
+
+	order, err = client.WaitOrder(ctx, order.URI)
+	if err != nil {
+		return nil, fmt.Errorf("acmedns: order for %q failed: %w", domain, err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	csr, err := x509.CreateCertificateRequest(rand.Reader, &x509.CertificateRequest{
+		Subject:  pkix.Name{CommonName: domain},
+		DNSNames: []string{domain},
+	}, key)
+	if err != nil {
+		return nil, err
+	}
+
+	der, _, err := client.CreateOrderCert(ctx, order.FinalizeURL, csr, true)
+	if err != nil {
+		return nil, fmt.Errorf("acmedns: failed to finalize order for %q: %w", domain, err)
+	}
+
+	cert, err := newTLSCert(key, der)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Cache != nil {
+		data, err := encodeCert(key, der)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := m.Cache.Put(ctx, certCacheKey(domain), data); err != nil {
+			return nil, fmt.Errorf("acmedns: failed to cache the certificate: %w", err)
+		}
+	}
+
+	m.mu.Lock()
+	m.certs[domain] = cert
+	m.mu.Unlock()
+
+	return cert, nil
+}
+
+func (m *Manager) authorize(ctx context.Context, client *acme.Client, authzURL string) error {
+	authz, err := client.GetAuthorization(ctx, authzURL)
+	if err != nil {
+		return err
+	}
+
+	if authz.Status == acme.StatusValid {
+		return nil
+	}
+
+	var challenge *acme.Challenge
+	for _, c := range authz.Challenges {
+		if c.Type == "dns-01" {
+			challenge = c
+			break
+		}
+	}
+	if challenge == nil {
+		return fmt.Errorf("acmedns: no dns-01 challenge offered for %q", authz.Identifier.Value)
+	}
+
+	value, err := client.DNS01ChallengeRecord(challenge.Token)
+	if err != nil {
+		return err
+	}
+
+	// note: for wildcard certificates the identifier value is without the "*." prefix
+	fqdn := challengeLabel + strings.TrimPrefix(authz.Identifier.Value, "*.") + "."
+
+	if err := m.Provider.Present(ctx, fqdn, value); err != nil {
+		return fmt.Errorf("acmedns: failed to create the %q challenge record: %w", fqdn, err)
+	}
+	defer func() {
+		cleanupCtx, cancel := context.WithTimeout(context.Background(), 1*time.Minute)
+		defer cancel()
+
+		_ = m.Provider.CleanUp(cleanupCtx, fqdn, value)
+	}()
+
+	m.waitPropagation(ctx, fqdn, value)
+
+	if _, err := client.Accept(ctx, challenge); err != nil {
+		return fmt.Errorf("acmedns: failed to accept the %q challenge: %w", fqdn, err)
+	}
+
+	if _, err := client.WaitAuthorization(ctx, authz.URI); err != nil {
+		return fmt.Errorf("acmedns: authorization for %q failed: %w", authz.Identifier.Value, err)
+	}
+
+	return nil
+}
+
+// waitPropagation waits until the TXT record is resolvable or the
+// propagation timeout is reached.
+//
+// Reaching the timeout is not considered an error because the local
+// resolver could be different (or cached) from the one used by the ACME server.
+func (m *Manager) waitPropagation(ctx context.Context, fqdn string, value string) {
+	timeout := m.PropagationTimeout
+	if timeout < 0 {
+		return
+	}
+	if timeout == 0 {
+		timeout = DefaultPropagationTimeout
+	}
+
+	resolver := m.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		records, _ := resolver.LookupTXT(ctx, fqdn)
+		if slices.Contains(records, value) {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(propagationPollInterval):
+		}
+	}
+}
+
+func (m *Manager) acmeClient(ctx context.Context) (*acme.Client, error) {
+	if m.client != nil {
+		return m.client, nil
+	}
+
+	key, err := m.accountKey(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	directoryURL := m.DirectoryURL
+	if directoryURL == "" {
+		directoryURL = acme.LetsEncryptURL
+	}
+
+	client := &acme.Client{
+		Key:          key,
+		DirectoryURL: directoryURL,
+		UserAgent:    "pocketbase-acmedns",
+	}
+
+	account := &acme.Account{}
+	if m.Email != "" {
+		account.Contact = []string{"mailto:" + m.Email}
+	}
+
+	_, err = client.Register(ctx, account, acme.AcceptTOS)
+	if err != nil && !errors.Is(err, acme.ErrAccountAlreadyExists) {
+		return nil, fmt.Errorf("acmedns: failed to register ACME account: %w", err)
+	}
+
+	m.client = client
+
+	return client, nil
+}
+
+func (m *Manager) accountKey(ctx context.Context) (crypto.Signer, error) {
+	if m.Cache != nil {
+		data, err := m.Cache.Get(ctx, accountKeyCacheKey)
+		if err == nil {
+			block, _ := pem.Decode(data)
+			if block != nil {
+				if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+					return key, nil
+				}
+			}
+		} else if !errors.Is(err, autocert.ErrCacheMiss) {
+			return nil, err
+		}
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	if m.Cache != nil {
+		raw, err := x509.MarshalECPrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+
+		data := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: raw})
+		if err := m.Cache.Put(ctx, accountKeyCacheKey, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return key, nil
+}
+
+func (m *Manager) loadCachedCert(ctx context.Context, domain string) (*tls.Certificate, error) {
+	data, err := m.Cache.Get(ctx, certCacheKey(domain))
+	if err != nil {
+		return nil, err
+	}
+
+	cert, err := decodeCert(data)
+	if err != nil {
+		return nil, err
+	}
+
+	// the cached certificate must cover the domain
+	if err := cert.Leaf.VerifyHostname(verifiableHost(domain)); err != nil {
+		return nil, err
+	}
+
+	return cert, nil
+}
+
+// -------------------------------------------------------------------
+
+// certCacheKey returns the cache key of the domain certificate
+// (the wildcard is replaced since "*" is not allowed in file names on some OS).
+func certCacheKey(domain string) string {
+	return certCacheKeyPrefix + strings.ReplaceAll(domain, "*", "_")
+}
+
+// verifiableHost returns a host name that could be used to verify a domain certificate.
+func verifiableHost(domain string) string {
+	if suffix, ok := strings.CutPrefix(domain, "*"); ok {
+		return "wildcard" + suffix
+	}
+
+	return domain
+}
+
+// encodeCert encodes the private key and the certificate chain
+// in the same PEM format as [autocert.Manager].
+func encodeCert(key *ecdsa.PrivateKey, der [][]byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	rawKey, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := pem.Encode(&buf, &pem.Block{Type: "EC PRIVATE KEY", Bytes: rawKey}); err != nil {
+		return nil, err
+	}
+
+	for _, b := range der {
+		if err := pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: b}); err != nil {
+			return nil, err
+		}
+	}
+
+	return buf.Bytes(), nil
+}
+
+func decodeCert(data []byte) (*tls.Certificate, error) {
+	var key *ecdsa.PrivateKey
+	var der [][]byte
+
+	for {
+		var block *pem.Block
+		block, data = pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		switch block.Type {
+		case "EC PRIVATE KEY":
+			k, err := x509.ParseECPrivateKey(block.Bytes)
+			if err != nil {
+				return nil, err
+			}
+			key = k
+		case "CERTIFICATE":
+			der = append(der, block.Bytes)
+		}
+	}
+
+	if key == nil || len(der) == 0 {
+		return nil, errors.New("acmedns: invalid cached certificate")
+	}
+
+	return newTLSCert(key, der)
+}
+
+func newTLSCert(key *ecdsa.PrivateKey, der [][]byte) (*tls.Certificate, error) {
+	if len(der) == 0 {
+		return nil, errors.New("acmedns: empty certificate chain")
+	}
+
+	leaf, err := x509.ParseCertificate(der[0])
+	if err != nil {
+		return nil, err
+	}
+
+	return &tls.Certificate{
+		Certificate: der,
+		PrivateKey:  key,
+		Leaf:        leaf,
+	}, nil
+}
+
+// findZone walks up the fqdn labels and returns the first
+// non-empty result of the provided lookup function.
+func findZone(ctx context.Context, fqdn string, lookup func(ctx context.Context, candidate string) (string, error)) (string, error) {
+	name := strings.TrimSuffix(strings.TrimPrefix(fqdn, challengeLabel), ".")
+
+	for {
+		result, err := lookup(ctx, name)
+		if err != nil {
+			return "", err
+		}
+
+		if result != "" {
+			return result, nil
+		}
+
+		_, parent, ok := strings.Cut(name, ".")
+		if !ok || !strings.Contains(parent, ".") {
+			break // don't check the TLD
+		}
+
+		name = parent
+	}
+
+	return "", fmt.Errorf("acmedns: failed to find the DNS zone of %q", fqdn)
+}
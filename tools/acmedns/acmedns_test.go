@@ -0,0 +1,284 @@
+package acmedns
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestManagerMatchDomain(t *testing.T) {
+	t.Parallel()
+
+	m := &Manager{Domains: []string{"example.com", "*.example.com", "Test.ORG", "a.test.org"}}
+
+	scenarios := []struct {
+		name     string
+		expected string
+	}{
+		{"missing.com", ""},
+		{"example.com", "example.com"},
+		{"a.example.com", "*.example.com"},
+		{"a.b.example.com", ""},
+		{"test.org", "test.org"},
+		{"a.test.org", "a.test.org"},
+		{"b.test.org", ""},
+		{"xexample.com", ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			domain, ok := m.matchDomain(s.name)
+
+			if ok != (s.expected != "") {
+				t.Fatalf("Expected ok %v, got %v", s.expected != "", ok)
+			}
+
+			if domain != s.expected {
+				t.Fatalf("Expected domain %q, got %q", s.expected, domain)
+			}
+		})
+	}
+}
+
+func TestManagerGetCertificateErrors(t *testing.T) {
+	t.Parallel()
+
+	m := &Manager{Domains: []string{"example.com"}}
+
+	if _, err := m.GetCertificate(&tls.ClientHelloInfo{}); err == nil {
+		t.Fatal("Expected missing server name error")
+	}
+
+	if _, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "missing.com"}); err == nil {
+		t.Fatal("Expected unconfigured host error")
+	}
+
+	// no cached cert and no provider
+	if _, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "example.com"}); err == nil {
+		t.Fatal("Expected missing provider error")
+	}
+}
+
+func TestManagerGetCertificateHostPolicy(t *testing.T) {
+	t.Parallel()
+
+	cache := autocert.DirCache(t.TempDir())
+
+	data := testCertData(t, "tenant.com", time.Now().Add(90*24*time.Hour))
+	if err := cache.Put(context.Background(), certCacheKey("tenant.com"), data); err != nil {
+		t.Fatal(err)
+	}
+
+	var checked []string
+
+	m := &Manager{
+		Cache:   cache,
+		Domains: []string{"example.com"},
+		HostPolicy: func(ctx context.Context, host string) error {
+			checked = append(checked, host)
+			if host == "tenant.com" {
+				return nil
+			}
+			return errors.New("not allowed")
+		},
+	}
+
+	cert, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "Tenant.com."})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cert.Leaf.Subject.CommonName != "tenant.com" {
+		t.Fatalf("Expected the tenant.com certificate, got %q", cert.Leaf.Subject.CommonName)
+	}
+
+	_, err = m.GetCertificate(&tls.ClientHelloInfo{ServerName: "other.com"})
+	if err == nil || err.Error() != "not allowed" {
+		t.Fatalf("Expected host policy error, got %v", err)
+	}
+
+	if len(checked) != 2 || checked[0] != "tenant.com" || checked[1] != "other.com" {
+		t.Fatalf("Unexpected host policy checks %v", checked)
+	}
+}
+
+func TestManagerGetCertificateFromCache(t *testing.T) {
+	t.Parallel()
+
+	cache := autocert.DirCache(t.TempDir())
+
+	wildcardData := testCertData(t, "*.example.com", time.Now().Add(90*24*time.Hour))
+	if err := cache.Put(context.Background(), certCacheKey("*.example.com"), wildcardData); err != nil {
+		t.Fatal(err)
+	}
+
+	// cert for a different domain
+	mismatchData := testCertData(t, "other.com", time.Now().Add(90*24*time.Hour))
+	if err := cache.Put(context.Background(), certCacheKey("example.com"), mismatchData); err != nil {
+		t.Fatal(err)
+	}
+
+	m := &Manager{
+		Cache:   cache,
+		Domains: []string{"example.com", "*.example.com"},
+	}
+
+	cert, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "test.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cert.Leaf.Subject.CommonName != "*.example.com" {
+		t.Fatalf("Expected the wildcard certificate, got %q", cert.Leaf.Subject.CommonName)
+	}
+
+	// should be served from memory
+	if err := cache.Delete(context.Background(), certCacheKey("*.example.com")); err != nil {
+		t.Fatal(err)
+	}
+
+	cert2, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "another.example.com"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cert2 != cert {
+		t.Fatal("Expected the same certificate instance")
+	}
+
+	// the cached cert doesn't cover the domain and there is no provider to issue a new one
+	if _, err := m.GetCertificate(&tls.ClientHelloInfo{ServerName: "example.com"}); err == nil {
+		t.Fatal("Expected error for the mismatched cached certificate")
+	}
+}
+
+func TestEncodeDecodeCert(t *testing.T) {
+	t.Parallel()
+
+	data := testCertData(t, "example.com", time.Now().Add(1*time.Hour))
+
+	cert, err := decodeCert(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if cert.Leaf == nil || cert.Leaf.Subject.CommonName != "example.com" {
+		t.Fatalf("Unexpected decoded certificate %v", cert.Leaf)
+	}
+
+	if _, err := decodeCert([]byte("invalid")); err == nil {
+		t.Fatal("Expected decode error")
+	}
+}
+
+func TestCertCacheKey(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		domain   string
+		expected string
+	}{
+		{"example.com", "dns01+example.com"},
+		{"*.example.com", "dns01+_.example.com"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.domain, func(t *testing.T) {
+			if v := certCacheKey(s.domain); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestFindZone(t *testing.T) {
+	t.Parallel()
+
+	zones := map[string]string{
+		"example.com": "zone1",
+		"b.test.org":  "zone2",
+	}
+
+	var checked []string
+
+	lookup := func(ctx context.Context, candidate string) (string, error) {
+		checked = append(checked, candidate)
+		if candidate == "error.com" {
+			return "", errors.New("test")
+		}
+		return zones[candidate], nil
+	}
+
+	scenarios := []struct {
+		fqdn        string
+		expected    string
+		expectError bool
+	}{
+		{"_acme-challenge.example.com.", "zone1", false},
+		{"_acme-challenge.a.b.example.com.", "zone1", false},
+		{"_acme-challenge.a.b.test.org.", "zone2", false},
+		{"_acme-challenge.test.org.", "", true},
+		{"_acme-challenge.error.com.", "", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.fqdn, func(t *testing.T) {
+			checked = nil
+
+			zone, err := findZone(context.Background(), s.fqdn, lookup)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if zone != s.expected {
+				t.Fatalf("Expected zone %q, got %q", s.expected, zone)
+			}
+
+			for _, c := range checked {
+				if c == "com" || c == "org" {
+					t.Fatalf("The TLD shouldn't be checked: %v", checked)
+				}
+			}
+		})
+	}
+}
+
+// testCertData generates a self-signed certificate in the cache PEM format.
+func testCertData(t *testing.T, domain string, notAfter time.Time) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: domain},
+		DNSNames:     []string{domain},
+		NotBefore:    time.Now().Add(-1 * time.Hour),
+		NotAfter:     notAfter,
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := encodeCert(key, [][]byte{der})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return data
+}
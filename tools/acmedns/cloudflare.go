@@ -0,0 +1,176 @@
+package acmedns
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+const cloudflareDefaultBaseURL = "https://api.cloudflare.com/client/v4"
+
+var _ Provider = (*Cloudflare)(nil)
+
+// Cloudflare is a DNS-01 challenge provider that manages
+// the TXT records using the Cloudflare API.
+type Cloudflare struct {
+	// HTTPClient is an optional custom http client (default to [http.DefaultClient]).
+	HTTPClient *http.Client
+
+	// APIToken is a Cloudflare API token with "Zone:Read" and "DNS:Edit" permissions.
+	APIToken string
+
+	// BaseURL is the Cloudflare API base url (default to "https://api.cloudflare.com/client/v4").
+	BaseURL string
+
+	// TTL is the challenge record TTL in seconds (default to 120).
+	TTL int
+
+	records map[string]string // fqdn+value -> zoneId/recordId
+	mu      sync.Mutex
+}
+
+// Present implements [Provider.Present].
+func (p *Cloudflare) Present(ctx context.Context, fqdn string, value string) error {
+	zoneId, err := findZone(ctx, fqdn, p.lookupZone)
+	if err != nil {
+		return err
+	}
+
+	ttl := p.TTL
+	if ttl <= 0 {
+		ttl = 120
+	}
+
+	result := struct {
+		Id string `json:"id"`
+	}{}
+
+	err = p.send(ctx, http.MethodPost, "/zones/"+url.PathEscape(zoneId)+"/dns_records", map[string]any{
+		"type":    "TXT",
+		"name":    strings.TrimSuffix(fqdn, "."),
+		"content": value,
+		"ttl":     ttl,
+	}, &result)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if p.records == nil {
+		p.records = map[string]string{}
+	}
+	p.records[fqdn+" "+value] = zoneId + "/" + result.Id
+	p.mu.Unlock()
+
+	return nil
+}
+
+// CleanUp implements [Provider.CleanUp].
+func (p *Cloudflare) CleanUp(ctx context.Context, fqdn string, value string) error {
+	key := fqdn + " " + value
+
+	p.mu.Lock()
+	ref, ok := p.records[key]
+	delete(p.records, key)
+	p.mu.Unlock()
+
+	if !ok {
+		return nil // nothing to cleanup
+	}
+
+	zoneId, recordId, _ := strings.Cut(ref, "/")
+
+	return p.send(ctx, http.MethodDelete, "/zones/"+url.PathEscape(zoneId)+"/dns_records/"+url.PathEscape(recordId), nil, nil)
+}
+
+func (p *Cloudflare) lookupZone(ctx context.Context, name string) (string, error) {
+	zones := []struct {
+		Id string `json:"id"`
+	}{}
+
+	err := p.send(ctx, http.MethodGet, "/zones?name="+url.QueryEscape(name), nil, &zones)
+	if err != nil {
+		return "", err
+	}
+
+	if len(zones) == 0 {
+		return "", nil
+	}
+
+	return zones[0].Id, nil
+}
+
+func (p *Cloudflare) send(ctx context.Context, method string, path string, body any, result any) error {
+	if p.APIToken == "" {
+		return errors.New("acmedns: missing Cloudflare API token")
+	}
+
+	var bodyReader io.Reader
+	if body != nil {
+		rawBody, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		bodyReader = bytes.NewReader(rawBody)
+	}
+
+	baseURL := p.BaseURL
+	if baseURL == "" {
+		baseURL = cloudflareDefaultBaseURL
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(baseURL, "/")+path, bodyReader)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set("Authorization", "Bearer "+p.APIToken)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	data := struct {
+		Result json.RawMessage `json:"result"`
+		Errors []struct {
+			Message string `json:"message"`
+			Code    int    `json:"code"`
+		} `json:"errors"`
+		Success bool `json:"success"`
+	}{}
+
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&data); err != nil {
+		return fmt.Errorf("acmedns: failed to decode Cloudflare response (status %d): %w", res.StatusCode, err)
+	}
+
+	if !data.Success || res.StatusCode >= 400 {
+		messages := make([]string, 0, len(data.Errors))
+		for _, e := range data.Errors {
+			messages = append(messages, fmt.Sprintf("%d: %s", e.Code, e.Message))
+		}
+		return fmt.Errorf("acmedns: Cloudflare API error (status %d): %s", res.StatusCode, strings.Join(messages, "; "))
+	}
+
+	if result != nil && len(data.Result) > 0 {
+		return json.Unmarshal(data.Result, result)
+	}
+
+	return nil
+}
@@ -0,0 +1,108 @@
+package acmedns
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestCloudflarePresentAndCleanUp(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var calls []string
+	var createBody map[string]any
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls = append(calls, r.Method+" "+r.URL.RequestURI())
+
+		if r.Header.Get("Authorization") != "Bearer test_token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"success":false,"errors":[{"code":9109,"message":"Invalid access token"}]}`))
+			return
+		}
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/zones":
+			if r.URL.Query().Get("name") == "example.com" {
+				w.Write([]byte(`{"success":true,"result":[{"id":"zone1"}]}`))
+			} else {
+				w.Write([]byte(`{"success":true,"result":[]}`))
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/zones/zone1/dns_records":
+			body, _ := io.ReadAll(r.Body)
+			json.Unmarshal(body, &createBody)
+			w.Write([]byte(`{"success":true,"result":{"id":"record1"}}`))
+		case r.Method == http.MethodDelete && r.URL.Path == "/zones/zone1/dns_records/record1":
+			w.Write([]byte(`{"success":true,"result":{"id":"record1"}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"success":false,"errors":[{"code":404,"message":"Not found"}]}`))
+		}
+	}))
+	defer server.Close()
+
+	// invalid token
+	// ---
+	invalid := &Cloudflare{APIToken: "invalid", BaseURL: server.URL}
+	err := invalid.Present(context.Background(), "_acme-challenge.example.com.", "abc")
+	if err == nil || !strings.Contains(err.Error(), "Invalid access token") {
+		t.Fatalf("Expected Cloudflare API error, got %v", err)
+	}
+
+	// valid token
+	// ---
+	calls = nil
+
+	p := &Cloudflare{APIToken: "test_token", BaseURL: server.URL}
+
+	if err := p.Present(context.Background(), "_acme-challenge.a.example.com.", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedBody := map[string]any{
+		"type":    "TXT",
+		"name":    "_acme-challenge.a.example.com",
+		"content": "abc",
+		"ttl":     float64(120),
+	}
+	for k, v := range expectedBody {
+		if createBody[k] != v {
+			t.Fatalf("Expected body %q to be %v, got %v", k, v, createBody[k])
+		}
+	}
+
+	if err := p.CleanUp(context.Background(), "_acme-challenge.a.example.com.", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	// already cleaned up
+	if err := p.CleanUp(context.Background(), "_acme-challenge.a.example.com.", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCalls := []string{
+		"GET /zones?name=a.example.com",
+		"GET /zones?name=example.com",
+		"POST /zones/zone1/dns_records",
+		"DELETE /zones/zone1/dns_records/record1",
+	}
+
+	if len(calls) != len(expectedCalls) {
+		t.Fatalf("Expected calls\n%v\ngot\n%v", expectedCalls, calls)
+	}
+
+	for i, c := range expectedCalls {
+		if calls[i] != c {
+			t.Fatalf("Expected call %d to be %q, got %q", i, c, calls[i])
+		}
+	}
+}
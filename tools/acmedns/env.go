@@ -0,0 +1,74 @@
+package acmedns
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// NewProviderFromEnv creates a new DNS-01 challenge provider with
+// the specified name and loads its credentials from env variables.
+//
+// Supported providers and their env variables:
+//
+//	cloudflare - CLOUDFLARE_API_TOKEN
+//	route53    - AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY, AWS_SESSION_TOKEN (optional),
+//	             AWS_REGION (optional), AWS_HOSTED_ZONE_ID (optional)
+//	rfc2136    - RFC2136_NAMESERVER, RFC2136_ZONE (optional), RFC2136_TSIG_KEY (optional),
+//	             RFC2136_TSIG_SECRET (optional), RFC2136_TSIG_ALGORITHM (optional)
+func NewProviderFromEnv(name string) (Provider, error) {
+	switch strings.ToLower(name) {
+	case "cloudflare":
+		p := &Cloudflare{
+			APIToken: os.Getenv("CLOUDFLARE_API_TOKEN"),
+		}
+
+		if p.APIToken == "" {
+			return nil, missingEnvError(name, "CLOUDFLARE_API_TOKEN")
+		}
+
+		return p, nil
+	case "route53":
+		p := &Route53{
+			AccessKeyId:     os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+			SessionToken:    os.Getenv("AWS_SESSION_TOKEN"),
+			Region:          os.Getenv("AWS_REGION"),
+			HostedZoneId:    os.Getenv("AWS_HOSTED_ZONE_ID"),
+		}
+
+		if p.AccessKeyId == "" {
+			return nil, missingEnvError(name, "AWS_ACCESS_KEY_ID")
+		}
+
+		if p.SecretAccessKey == "" {
+			return nil, missingEnvError(name, "AWS_SECRET_ACCESS_KEY")
+		}
+
+		return p, nil
+	case "rfc2136":
+		p := &RFC2136{
+			Nameserver:    os.Getenv("RFC2136_NAMESERVER"),
+			Zone:          os.Getenv("RFC2136_ZONE"),
+			TSIGKey:       os.Getenv("RFC2136_TSIG_KEY"),
+			TSIGSecret:    os.Getenv("RFC2136_TSIG_SECRET"),
+			TSIGAlgorithm: os.Getenv("RFC2136_TSIG_ALGORITHM"),
+		}
+
+		if p.Nameserver == "" {
+			return nil, missingEnvError(name, "RFC2136_NAMESERVER")
+		}
+
+		if p.TSIGKey != "" && p.TSIGSecret == "" {
+			return nil, missingEnvError(name, "RFC2136_TSIG_SECRET")
+		}
+
+		return p, nil
+	default:
+		return nil, fmt.Errorf("acmedns: unsupported DNS provider %q (supported: cloudflare, route53, rfc2136)", name)
+	}
+}
+
+func missingEnvError(provider string, env string) error {
+	return fmt.Errorf("acmedns: missing %s env variable required by the %s DNS provider", env, provider)
+}
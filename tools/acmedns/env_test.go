@@ -0,0 +1,49 @@
+package acmedns
+
+import (
+	"testing"
+)
+
+func TestNewProviderFromEnv(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		provider    string
+		env         map[string]string
+		expectError bool
+	}{
+		{"unknown", "unknown", nil, true},
+		{"cloudflare missing token", "cloudflare", nil, true},
+		{"cloudflare", "Cloudflare", map[string]string{"CLOUDFLARE_API_TOKEN": "abc"}, false},
+		{"route53 missing secret", "route53", map[string]string{"AWS_ACCESS_KEY_ID": "abc"}, true},
+		{"route53", "route53", map[string]string{"AWS_ACCESS_KEY_ID": "abc", "AWS_SECRET_ACCESS_KEY": "def"}, false},
+		{"rfc2136 missing nameserver", "rfc2136", nil, true},
+		{"rfc2136 missing tsig secret", "rfc2136", map[string]string{"RFC2136_NAMESERVER": "127.0.0.1", "RFC2136_TSIG_KEY": "key"}, true},
+		{"rfc2136", "rfc2136", map[string]string{"RFC2136_NAMESERVER": "127.0.0.1"}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			for _, env := range []string{
+				"CLOUDFLARE_API_TOKEN",
+				"AWS_ACCESS_KEY_ID",
+				"AWS_SECRET_ACCESS_KEY",
+				"RFC2136_NAMESERVER",
+				"RFC2136_TSIG_KEY",
+				"RFC2136_TSIG_SECRET",
+			} {
+				t.Setenv(env, s.env[env])
+			}
+
+			provider, err := NewProviderFromEnv(s.provider)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && provider == nil {
+				t.Fatal("Expected non-nil provider")
+			}
+		})
+	}
+}
@@ -0,0 +1,353 @@
+package acmedns
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"net"
+	"strings"
+	"time"
+)
+
+const (
+	dnsTypeSOA  = 6
+	dnsTypeTXT  = 16
+	dnsTypeTSIG = 250
+
+	dnsClassIN   = 1
+	dnsClassNONE = 254
+	dnsClassANY  = 255
+
+	dnsOpcodeUpdate = 5
+
+	tsigFudge = 300
+)
+
+var dnsRcodeNames = map[int]string{
+	1:  "FORMERR",
+	2:  "SERVFAIL",
+	3:  "NXDOMAIN",
+	4:  "NOTIMP",
+	5:  "REFUSED",
+	6:  "YXDOMAIN",
+	7:  "YXRRSET",
+	8:  "NXRRSET",
+	9:  "NOTAUTH",
+	10: "NOTZONE",
+}
+
+var tsigAlgorithms = map[string]func() hash.Hash{
+	"hmac-sha1.":   sha1.New,
+	"hmac-sha256.": sha256.New,
+	"hmac-sha512.": sha512.New,
+}
+
+var _ Provider = (*RFC2136)(nil)
+
+// RFC2136 is a DNS-01 challenge provider that manages the TXT records
+// using the standard DNS UPDATE protocol (RFC 2136) with optional
+// TSIG authentication (RFC 8945).
+//
+// It works with most authoritative DNS servers like BIND, Knot, PowerDNS, etc.
+type RFC2136 struct {
+	// Nameserver is the primary DNS server address in the format "host[:port]" (the port default to 53).
+	Nameserver string
+
+	// Zone is the DNS zone to update, eg. "example.com".
+	//
+	// If not set, the last 2 labels of the challenge domain are used.
+	Zone string
+
+	// TSIGKey is the optional TSIG key name.
+	TSIGKey string
+
+	// TSIGSecret is the base64 encoded TSIG key secret.
+	TSIGSecret string
+
+	// TSIGAlgorithm is the TSIG algorithm - "hmac-sha1.", "hmac-sha256." (default) or "hmac-sha512.".
+	TSIGAlgorithm string
+
+	// TTL is the challenge record TTL in seconds (default to 120).
+	TTL int
+
+	// Timeout is the DNS exchange timeout (default to 10s).
+	Timeout time.Duration
+}
+
+// Present implements [Provider.Present].
+func (p *RFC2136) Present(ctx context.Context, fqdn string, value string) error {
+	ttl := p.TTL
+	if ttl <= 0 {
+		ttl = 120
+	}
+
+	return p.update(ctx, fqdn, value, dnsClassIN, uint32(ttl))
+}
+
+// CleanUp implements [Provider.CleanUp].
+func (p *RFC2136) CleanUp(ctx context.Context, fqdn string, value string) error {
+	// class NONE with TTL 0 deletes the specific RR from the RRset
+	return p.update(ctx, fqdn, value, dnsClassNONE, 0)
+}
+
+func (p *RFC2136) update(ctx context.Context, fqdn string, value string, class uint16, ttl uint32) error {
+	if p.Nameserver == "" {
+		return errors.New("acmedns: missing RFC2136 nameserver")
+	}
+
+	if len(value) > 255 {
+		return errors.New("acmedns: too long TXT record value")
+	}
+
+	zone := p.Zone
+	if zone == "" {
+		labels := strings.Split(strings.Trim(fqdn, "."), ".")
+		if len(labels) < 2 {
+			return fmt.Errorf("acmedns: failed to find the DNS zone of %q", fqdn)
+		}
+		zone = strings.Join(labels[len(labels)-2:], ".")
+	}
+
+	msg, id, err := buildDNSUpdate(zone, fqdn, value, class, ttl)
+	if err != nil {
+		return err
+	}
+
+	if p.TSIGKey != "" {
+		msg, err = p.sign(msg, id, time.Now())
+		if err != nil {
+			return err
+		}
+	}
+
+	res, err := p.exchange(ctx, msg)
+	if err != nil {
+		return err
+	}
+
+	if len(res) < 12 || binary.BigEndian.Uint16(res[0:2]) != id {
+		return errors.New("acmedns: invalid DNS UPDATE response")
+	}
+
+	if rcode := int(binary.BigEndian.Uint16(res[2:4]) & 0x000f); rcode != 0 {
+		name := dnsRcodeNames[rcode]
+		if name == "" {
+			name = fmt.Sprintf("RCODE %d", rcode)
+		}
+		return fmt.Errorf("acmedns: DNS UPDATE failed with %s", name)
+	}
+
+	return nil
+}
+
+// sign appends a TSIG record to the provided DNS message.
+func (p *RFC2136) sign(msg []byte, id uint16, now time.Time) ([]byte, error) {
+	algorithm := strings.ToLower(p.TSIGAlgorithm)
+	if algorithm == "" {
+		algorithm = "hmac-sha256."
+	}
+	if !strings.HasSuffix(algorithm, ".") {
+		algorithm += "."
+	}
+
+	hashFunc, ok := tsigAlgorithms[algorithm]
+	if !ok {
+		return nil, fmt.Errorf("acmedns: unsupported TSIG algorithm %q", p.TSIGAlgorithm)
+	}
+
+	secret, err := base64.StdEncoding.DecodeString(p.TSIGSecret)
+	if err != nil {
+		return nil, fmt.Errorf("acmedns: invalid TSIG secret: %w", err)
+	}
+
+	keyName, err := appendDNSName(nil, strings.ToLower(p.TSIGKey))
+	if err != nil {
+		return nil, err
+	}
+
+	algorithmName, err := appendDNSName(nil, algorithm)
+	if err != nil {
+		return nil, err
+	}
+
+	timeSigned := make([]byte, 6)
+	unix := uint64(now.Unix())
+	binary.BigEndian.PutUint16(timeSigned[0:2], uint16(unix>>32))
+	binary.BigEndian.PutUint32(timeSigned[2:6], uint32(unix))
+
+	// TSIG variables (RFC 8945 4.3.3)
+	variables := append([]byte{}, keyName...)
+	variables = binary.BigEndian.AppendUint16(variables, dnsClassANY)
+	variables = binary.BigEndian.AppendUint32(variables, 0) // TTL
+	variables = append(variables, algorithmName...)
+	variables = append(variables, timeSigned...)
+	variables = binary.BigEndian.AppendUint16(variables, tsigFudge)
+	variables = binary.BigEndian.AppendUint16(variables, 0) // error
+	variables = binary.BigEndian.AppendUint16(variables, 0) // other len
+
+	mac := hmac.New(hashFunc, secret)
+	mac.Write(msg)
+	mac.Write(variables)
+	sum := mac.Sum(nil)
+
+	rdata := append([]byte{}, algorithmName...)
+	rdata = append(rdata, timeSigned...)
+	rdata = binary.BigEndian.AppendUint16(rdata, tsigFudge)
+	rdata = binary.BigEndian.AppendUint16(rdata, uint16(len(sum)))
+	rdata = append(rdata, sum...)
+	rdata = binary.BigEndian.AppendUint16(rdata, id) // original id
+	rdata = binary.BigEndian.AppendUint16(rdata, 0)  // error
+	rdata = binary.BigEndian.AppendUint16(rdata, 0)  // other len
+
+	signed := append([]byte{}, msg...)
+	signed = append(signed, keyName...)
+	signed = binary.BigEndian.AppendUint16(signed, dnsTypeTSIG)
+	signed = binary.BigEndian.AppendUint16(signed, dnsClassANY)
+	signed = binary.BigEndian.AppendUint32(signed, 0)
+	signed = binary.BigEndian.AppendUint16(signed, uint16(len(rdata)))
+	signed = append(signed, rdata...)
+
+	// ARCOUNT
+	binary.BigEndian.PutUint16(signed[10:12], 1)
+
+	return signed, nil
+}
+
+func (p *RFC2136) exchange(ctx context.Context, msg []byte) ([]byte, error) {
+	addr := p.Nameserver
+	if _, _, err := net.SplitHostPort(addr); err != nil {
+		addr = net.JoinHostPort(strings.Trim(addr, "[]"), "53")
+	}
+
+	timeout := p.Timeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	dialer := &net.Dialer{}
+
+	// UDP
+	// ---
+	conn, err := dialer.DialContext(ctx, "udp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	if _, err := conn.Write(msg); err != nil {
+		return nil, err
+	}
+
+	res := make([]byte, 4096)
+	n, err := conn.Read(res)
+	if err != nil {
+		return nil, err
+	}
+	res = res[:n]
+
+	truncated := len(res) >= 4 && res[2]&0x02 != 0
+	if !truncated {
+		return res, nil
+	}
+
+	// TCP fallback
+	// ---
+	tcpConn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer tcpConn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		tcpConn.SetDeadline(deadline)
+	}
+
+	framed := binary.BigEndian.AppendUint16(nil, uint16(len(msg)))
+	if _, err := tcpConn.Write(append(framed, msg...)); err != nil {
+		return nil, err
+	}
+
+	size := make([]byte, 2)
+	if _, err := io.ReadFull(tcpConn, size); err != nil {
+		return nil, err
+	}
+
+	res = make([]byte, binary.BigEndian.Uint16(size))
+	if _, err := io.ReadFull(tcpConn, res); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// buildDNSUpdate creates a new DNS UPDATE message for a single TXT record.
+func buildDNSUpdate(zone string, fqdn string, value string, class uint16, ttl uint32) ([]byte, uint16, error) {
+	rawId := make([]byte, 2)
+	if _, err := rand.Read(rawId); err != nil {
+		return nil, 0, err
+	}
+	id := binary.BigEndian.Uint16(rawId)
+
+	// header
+	msg := binary.BigEndian.AppendUint16(nil, id)
+	msg = binary.BigEndian.AppendUint16(msg, dnsOpcodeUpdate<<11)
+	msg = binary.BigEndian.AppendUint16(msg, 1) // ZOCOUNT
+	msg = binary.BigEndian.AppendUint16(msg, 0) // PRCOUNT
+	msg = binary.BigEndian.AppendUint16(msg, 1) // UPCOUNT
+	msg = binary.BigEndian.AppendUint16(msg, 0) // ADCOUNT
+
+	// zone section
+	msg, err := appendDNSName(msg, zone)
+	if err != nil {
+		return nil, 0, err
+	}
+	msg = binary.BigEndian.AppendUint16(msg, dnsTypeSOA)
+	msg = binary.BigEndian.AppendUint16(msg, dnsClassIN)
+
+	// update section
+	msg, err = appendDNSName(msg, fqdn)
+	if err != nil {
+		return nil, 0, err
+	}
+	msg = binary.BigEndian.AppendUint16(msg, dnsTypeTXT)
+	msg = binary.BigEndian.AppendUint16(msg, class)
+	msg = binary.BigEndian.AppendUint32(msg, ttl)
+	msg = binary.BigEndian.AppendUint16(msg, uint16(len(value)+1))
+	msg = append(msg, byte(len(value)))
+	msg = append(msg, value...)
+
+	return msg, id, nil
+}
+
+// appendDNSName appends the uncompressed wire format of the domain name.
+func appendDNSName(b []byte, name string) ([]byte, error) {
+	name = strings.TrimSuffix(name, ".")
+
+	if name != "" {
+		for _, label := range strings.Split(name, ".") {
+			if label == "" || len(label) > 63 {
+				return nil, fmt.Errorf("acmedns: invalid domain name %q", name)
+			}
+			b = append(b, byte(len(label)))
+			b = append(b, label...)
+		}
+	}
+
+	return append(b, 0), nil
+}
@@ -0,0 +1,200 @@
+package acmedns
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"net"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestBuildDNSUpdate(t *testing.T) {
+	t.Parallel()
+
+	msg, id, err := buildDNSUpdate("example.com", "_acme-challenge.example.com.", "abc", dnsClassIN, 120)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if binary.BigEndian.Uint16(msg[0:2]) != id {
+		t.Fatal("Expected the message id to match")
+	}
+
+	expected := []byte{
+		0x28, 0x00, // opcode UPDATE
+		0, 1, 0, 0, 0, 1, 0, 0, // counts
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		0, 6, 0, 1, // SOA IN
+		15, '_', 'a', 'c', 'm', 'e', '-', 'c', 'h', 'a', 'l', 'l', 'e', 'n', 'g', 'e',
+		7, 'e', 'x', 'a', 'm', 'p', 'l', 'e', 3, 'c', 'o', 'm', 0,
+		0, 16, 0, 1, // TXT IN
+		0, 0, 0, 120, // TTL
+		0, 4, 3, 'a', 'b', 'c', // rdata
+	}
+
+	if !bytes.Equal(msg[2:], expected) {
+		t.Fatalf("Expected\n%v\ngot\n%v", expected, msg[2:])
+	}
+
+	if _, _, err := buildDNSUpdate("example.com", "invalid..name", "abc", dnsClassIN, 120); err == nil {
+		t.Fatal("Expected invalid name error")
+	}
+}
+
+func TestRFC2136Sign(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("test_secret")
+
+	p := &RFC2136{
+		TSIGKey:    "Test-Key",
+		TSIGSecret: base64.StdEncoding.EncodeToString(secret),
+	}
+
+	msg, id, err := buildDNSUpdate("example.com", "_acme-challenge.example.com", "abc", dnsClassIN, 120)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Unix(1700000000, 0)
+
+	signed, err := p.sign(msg, id, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if v := binary.BigEndian.Uint16(signed[10:12]); v != 1 {
+		t.Fatalf("Expected ARCOUNT 1, got %d", v)
+	}
+
+	if !bytes.Equal(signed[12:len(msg)], msg[12:]) {
+		t.Fatal("Expected the original message to be preserved")
+	}
+
+	verifyTestTSIG(t, signed, len(msg), secret)
+
+	// unsupported algorithm
+	p.TSIGAlgorithm = "hmac-md5"
+	if _, err := p.sign(msg, id, now); err == nil {
+		t.Fatal("Expected unsupported algorithm error")
+	}
+
+	// invalid secret
+	p.TSIGAlgorithm = ""
+	p.TSIGSecret = "!invalid"
+	if _, err := p.sign(msg, id, now); err == nil {
+		t.Fatal("Expected invalid secret error")
+	}
+}
+
+func TestRFC2136PresentAndCleanUp(t *testing.T) {
+	t.Parallel()
+
+	secret := []byte("test_secret")
+
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	received := make(chan []byte, 10)
+
+	go func() {
+		buf := make([]byte, 4096)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+
+			msg := append([]byte{}, buf[:n]...)
+			received <- msg
+
+			res := append([]byte{}, msg[:12]...)
+			res[2] |= 0x80 // QR
+
+			// refuse updates of a specific domain
+			if bytes.Contains(msg, []byte("refused")) {
+				res[3] |= 5
+			}
+
+			conn.WriteTo(res, addr)
+		}
+	}()
+
+	p := &RFC2136{
+		Nameserver: conn.LocalAddr().String(),
+		Zone:       "example.com",
+		TSIGKey:    "test-key",
+		TSIGSecret: base64.StdEncoding.EncodeToString(secret),
+		Timeout:    5 * time.Second,
+	}
+
+	if err := p.Present(context.Background(), "_acme-challenge.example.com.", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	msg := <-received
+	if !bytes.Contains(msg, []byte{0, 16, 0, dnsClassIN, 0, 0, 0, 120, 0, 4, 3, 'a', 'b', 'c'}) {
+		t.Fatalf("Expected TXT IN add record in\n%v", msg)
+	}
+
+	if err := p.CleanUp(context.Background(), "_acme-challenge.example.com.", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	msg = <-received
+	if !bytes.Contains(msg, []byte{0, 16, 0, dnsClassNONE, 0, 0, 0, 0, 0, 4, 3, 'a', 'b', 'c'}) {
+		t.Fatalf("Expected TXT NONE delete record in\n%v", msg)
+	}
+
+	err = p.Present(context.Background(), "_acme-challenge.refused.example.com.", "abc")
+	if err == nil || !strings.Contains(err.Error(), "REFUSED") {
+		t.Fatalf("Expected REFUSED error, got %v", err)
+	}
+}
+
+// verifyTestTSIG recomputes and compares the TSIG MAC of a signed message.
+func verifyTestTSIG(t *testing.T, signed []byte, msgLen int, secret []byte) {
+	unsigned := append([]byte{}, signed[:msgLen]...)
+	binary.BigEndian.PutUint16(unsigned[10:12], 0)
+
+	tsig := signed[msgLen:]
+
+	keyName := []byte{8, 't', 'e', 's', 't', '-', 'k', 'e', 'y', 0}
+	if !bytes.HasPrefix(tsig, keyName) {
+		t.Fatalf("Expected lowercased key name in the TSIG record, got %v", tsig)
+	}
+
+	rdata := tsig[len(keyName)+10:]
+
+	algorithmName := []byte{11, 'h', 'm', 'a', 'c', '-', 's', 'h', 'a', '2', '5', '6', 0}
+	if !bytes.HasPrefix(rdata, algorithmName) {
+		t.Fatalf("Expected hmac-sha256 algorithm, got %v", rdata)
+	}
+
+	rest := rdata[len(algorithmName):]
+	timeAndFudge := rest[:8]
+	macSize := binary.BigEndian.Uint16(rest[8:10])
+	mac := rest[10 : 10+macSize]
+
+	variables := append([]byte{}, keyName...)
+	variables = append(variables, 0, 255, 0, 0, 0, 0)
+	variables = append(variables, algorithmName...)
+	variables = append(variables, timeAndFudge...)
+	variables = append(variables, 0, 0, 0, 0)
+
+	h := hmac.New(sha256.New, secret)
+	h.Write(unsigned)
+	h.Write(variables)
+
+	if !hmac.Equal(h.Sum(nil), mac) {
+		t.Fatal("TSIG MAC mismatch")
+	}
+}
@@ -0,0 +1,306 @@
+package acmedns
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	route53DefaultEndpoint = "https://route53.amazonaws.com"
+	route53DefaultRegion   = "us-east-1"
+	route53APIVersion      = "2013-04-01"
+	route53SyncTimeout     = 2 * time.Minute
+	route53SyncInterval    = 5 * time.Second
+)
+
+var _ Provider = (*Route53)(nil)
+
+// Route53 is a DNS-01 challenge provider that manages
+// the TXT records using the AWS Route 53 API.
+type Route53 struct {
+	// HTTPClient is an optional custom http client (default to [http.DefaultClient]).
+	HTTPClient *http.Client
+
+	// AccessKeyId is the AWS access key id.
+	AccessKeyId string
+
+	// SecretAccessKey is the AWS secret access key.
+	SecretAccessKey string
+
+	// SessionToken is an optional AWS session token (for temporary credentials).
+	SessionToken string
+
+	// Region is the AWS signing region (default to "us-east-1").
+	Region string
+
+	// HostedZoneId is an optional hosted zone id.
+	//
+	// If not set, the hosted zone is looked up by the challenge domain name.
+	HostedZoneId string
+
+	// Endpoint is the Route 53 API endpoint (default to "https://route53.amazonaws.com").
+	Endpoint string
+
+	// TTL is the challenge record TTL in seconds (default to 60).
+	TTL int
+}
+
+// Present implements [Provider.Present].
+func (p *Route53) Present(ctx context.Context, fqdn string, value string) error {
+	return p.change(ctx, "UPSERT", fqdn, value)
+}
+
+// CleanUp implements [Provider.CleanUp].
+func (p *Route53) CleanUp(ctx context.Context, fqdn string, value string) error {
+	return p.change(ctx, "DELETE", fqdn, value)
+}
+
+type route53ChangeRequest struct {
+	XMLName xml.Name        `xml:"https://route53.amazonaws.com/doc/2013-04-01/ ChangeResourceRecordSetsRequest"`
+	Changes []route53Change `xml:"ChangeBatch>Changes>Change"`
+}
+
+type route53Change struct {
+	Action string           `xml:"Action"`
+	Set    route53RecordSet `xml:"ResourceRecordSet"`
+}
+
+type route53RecordSet struct {
+	Name    string   `xml:"Name"`
+	Type    string   `xml:"Type"`
+	TTL     int      `xml:"TTL"` // note: the elements order matters
+	Records []string `xml:"ResourceRecords>ResourceRecord>Value"`
+}
+
+type route53ChangeInfo struct {
+	Id     string `xml:"ChangeInfo>Id"`
+	Status string `xml:"ChangeInfo>Status"`
+}
+
+func (p *Route53) change(ctx context.Context, action string, fqdn string, value string) error {
+	zoneId := p.HostedZoneId
+	if zoneId == "" {
+		var err error
+		zoneId, err = findZone(ctx, fqdn, p.lookupZone)
+		if err != nil {
+			return err
+		}
+	}
+	zoneId = strings.TrimPrefix(zoneId, "/hostedzone/")
+
+	ttl := p.TTL
+	if ttl <= 0 {
+		ttl = 60
+	}
+
+	body := route53ChangeRequest{
+		Changes: []route53Change{{
+			Action: action,
+			Set: route53RecordSet{
+				Name:    fqdn,
+				Type:    "TXT",
+				TTL:     ttl,
+				Records: []string{`"` + value + `"`},
+			},
+		}},
+	}
+
+	rawBody, err := xml.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	info := route53ChangeInfo{}
+
+	err = p.send(ctx, http.MethodPost, "/"+route53APIVersion+"/hostedzone/"+zoneId+"/rrset/", nil, append([]byte(xml.Header), rawBody...), &info)
+	if err != nil {
+		return err
+	}
+
+	if action != "UPSERT" {
+		return nil
+	}
+
+	// wait for the change to be propagated to all Route 53 DNS servers
+	ctx, cancel := context.WithTimeout(ctx, route53SyncTimeout)
+	defer cancel()
+
+	changeId := strings.TrimPrefix(info.Id, "/change/")
+	for info.Status != "INSYNC" && changeId != "" {
+		select {
+		case <-ctx.Done():
+			return nil // let the ACME server try anyway
+		case <-time.After(route53SyncInterval):
+		}
+
+		err := p.send(ctx, http.MethodGet, "/"+route53APIVersion+"/change/"+changeId, nil, nil, &info)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (p *Route53) lookupZone(ctx context.Context, name string) (string, error) {
+	result := struct {
+		Zones []struct {
+			Id   string `xml:"Id"`
+			Name string `xml:"Name"`
+		} `xml:"HostedZones>HostedZone"`
+	}{}
+
+	query := url.Values{}
+	query.Set("dnsname", name)
+	query.Set("maxitems", "1")
+
+	err := p.send(ctx, http.MethodGet, "/"+route53APIVersion+"/hostedzonesbyname", query, nil, &result)
+	if err != nil {
+		return "", err
+	}
+
+	// the zones are sorted and the first one could be different if there is no exact match
+	if len(result.Zones) == 0 || !strings.EqualFold(result.Zones[0].Name, name+".") {
+		return "", nil
+	}
+
+	return result.Zones[0].Id, nil
+}
+
+func (p *Route53) send(ctx context.Context, method string, path string, query url.Values, body []byte, result any) error {
+	if p.AccessKeyId == "" || p.SecretAccessKey == "" {
+		return errors.New("acmedns: missing Route 53 credentials")
+	}
+
+	endpoint := p.Endpoint
+	if endpoint == "" {
+		endpoint = route53DefaultEndpoint
+	}
+
+	rawURL := strings.TrimRight(endpoint, "/") + path
+	if len(query) > 0 {
+		rawURL += "?" + query.Encode()
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	if body != nil {
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	p.sign(req, body, time.Now().UTC())
+
+	client := p.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	rawResult, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return err
+	}
+
+	if res.StatusCode >= 400 {
+		apiErr := struct {
+			Code    string `xml:"Error>Code"`
+			Message string `xml:"Error>Message"`
+		}{}
+		_ = xml.Unmarshal(rawResult, &apiErr)
+
+		return fmt.Errorf("acmedns: Route 53 API error (status %d): %s %s", res.StatusCode, apiErr.Code, apiErr.Message)
+	}
+
+	if result != nil {
+		return xml.Unmarshal(rawResult, result)
+	}
+
+	return nil
+}
+
+// sign signs the request with AWS Signature Version 4.
+func (p *Route53) sign(req *http.Request, body []byte, now time.Time) {
+	region := p.Region
+	if region == "" {
+		region = route53DefaultRegion
+	}
+
+	amzDate := now.Format("20060102T150405Z")
+	shortDate := now.Format("20060102")
+	payloadHash := sha256Hex(body)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	if p.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.SessionToken)
+	}
+
+	signedHeaders := "host;x-amz-date"
+	canonicalHeaders := "host:" + req.URL.Host + "\n" + "x-amz-date:" + amzDate + "\n"
+	if p.SessionToken != "" {
+		signedHeaders += ";x-amz-security-token"
+		canonicalHeaders += "x-amz-security-token:" + p.SessionToken + "\n"
+	}
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := shortDate + "/" + region + "/route53/aws4_request"
+
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	signingKey := hmacSHA256([]byte("AWS4"+p.SecretAccessKey), shortDate)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, "route53")
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		p.AccessKeyId,
+		scope,
+		signedHeaders,
+		signature,
+	))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	h := sha256.Sum256(data)
+	return hex.EncodeToString(h[:])
+}
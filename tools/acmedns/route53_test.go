@@ -0,0 +1,171 @@
+package acmedns
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestRoute53PresentAndCleanUp(t *testing.T) {
+	t.Parallel()
+
+	var mu sync.Mutex
+	var calls []string
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+
+		calls = append(calls, r.Method+" "+r.URL.RequestURI())
+
+		auth := r.Header.Get("Authorization")
+		if !strings.HasPrefix(auth, "AWS4-HMAC-SHA256 Credential=test_key/") ||
+			!strings.Contains(auth, "/us-east-1/route53/aws4_request") ||
+			r.Header.Get("X-Amz-Date") == "" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`<ErrorResponse><Error><Code>InvalidSignature</Code><Message>invalid</Message></Error></ErrorResponse>`))
+			return
+		}
+
+		switch {
+		case r.URL.Path == "/2013-04-01/hostedzonesbyname":
+			name := r.URL.Query().Get("dnsname")
+			if name == "example.com" {
+				w.Write([]byte(`<ListHostedZonesByNameResponse><HostedZones><HostedZone><Id>/hostedzone/Z1</Id><Name>example.com.</Name></HostedZone></HostedZones></ListHostedZonesByNameResponse>`))
+			} else {
+				// non-exact match
+				w.Write([]byte(`<ListHostedZonesByNameResponse><HostedZones><HostedZone><Id>/hostedzone/Z2</Id><Name>other.com.</Name></HostedZone></HostedZones></ListHostedZonesByNameResponse>`))
+			}
+		case r.URL.Path == "/2013-04-01/hostedzone/Z1/rrset/":
+			body, _ := io.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			w.Write([]byte(`<ChangeResourceRecordSetsResponse><ChangeInfo><Id>/change/C1</Id><Status>PENDING</Status></ChangeInfo></ChangeResourceRecordSetsResponse>`))
+		case r.URL.Path == "/2013-04-01/change/C1":
+			w.Write([]byte(`<GetChangeResponse><ChangeInfo><Id>/change/C1</Id><Status>INSYNC</Status></ChangeInfo></GetChangeResponse>`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	// missing credentials
+	// ---
+	invalid := &Route53{Endpoint: server.URL}
+	if err := invalid.Present(context.Background(), "_acme-challenge.example.com.", "abc"); err == nil {
+		t.Fatal("Expected missing credentials error")
+	}
+
+	// valid
+	// ---
+	calls = nil
+
+	p := &Route53{
+		AccessKeyId:     "test_key",
+		SecretAccessKey: "test_secret",
+		Endpoint:        server.URL,
+	}
+
+	start := time.Now()
+
+	if err := p.Present(context.Background(), "_acme-challenge.a.example.com.", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	if time.Since(start) < route53SyncInterval {
+		t.Fatal("Expected to wait for the change to be in sync")
+	}
+
+	if err := p.CleanUp(context.Background(), "_acme-challenge.a.example.com.", "abc"); err != nil {
+		t.Fatal(err)
+	}
+
+	expectedCalls := []string{
+		"GET /2013-04-01/hostedzonesbyname?dnsname=a.example.com&maxitems=1",
+		"GET /2013-04-01/hostedzonesbyname?dnsname=example.com&maxitems=1",
+		"POST /2013-04-01/hostedzone/Z1/rrset/",
+		"GET /2013-04-01/change/C1",
+		"GET /2013-04-01/hostedzonesbyname?dnsname=a.example.com&maxitems=1",
+		"GET /2013-04-01/hostedzonesbyname?dnsname=example.com&maxitems=1",
+		"POST /2013-04-01/hostedzone/Z1/rrset/",
+	}
+
+	if len(calls) != len(expectedCalls) {
+		t.Fatalf("Expected calls\n%v\ngot\n%v", expectedCalls, calls)
+	}
+
+	for i, c := range expectedCalls {
+		if calls[i] != c {
+			t.Fatalf("Expected call %d to be %q, got %q", i, c, calls[i])
+		}
+	}
+
+	expectedBodyParts := []string{
+		`<ChangeResourceRecordSetsRequest xmlns="https://route53.amazonaws.com/doc/2013-04-01/">`,
+		`<Name>_acme-challenge.a.example.com.</Name><Type>TXT</Type><TTL>60</TTL><ResourceRecords><ResourceRecord><Value>&#34;abc&#34;</Value></ResourceRecord></ResourceRecords>`,
+	}
+
+	for i, action := range []string{"UPSERT", "DELETE"} {
+		if !strings.Contains(bodies[i], "<Action>"+action+"</Action>") {
+			t.Fatalf("Expected %s action in\n%s", action, bodies[i])
+		}
+
+		for _, part := range expectedBodyParts {
+			if !strings.Contains(bodies[i], part) {
+				t.Fatalf("Expected %s in\n%s", part, bodies[i])
+			}
+		}
+	}
+}
+
+func TestRoute53Sign(t *testing.T) {
+	t.Parallel()
+
+	p := &Route53{
+		AccessKeyId:     "AKIDEXAMPLE",
+		SecretAccessKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		SessionToken:    "token",
+	}
+
+	req, err := http.NewRequest(http.MethodGet, "https://route53.amazonaws.com/2013-04-01/hostedzonesbyname?maxitems=1&dnsname=example.com", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	p.sign(req, nil, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+
+	if v := req.Header.Get("X-Amz-Date"); v != "20240102T030405Z" {
+		t.Fatalf("Unexpected X-Amz-Date %q", v)
+	}
+
+	if v := req.Header.Get("X-Amz-Security-Token"); v != "token" {
+		t.Fatalf("Unexpected X-Amz-Security-Token %q", v)
+	}
+
+	auth := req.Header.Get("Authorization")
+
+	expectedPrefix := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20240102/us-east-1/route53/aws4_request, SignedHeaders=host;x-amz-date;x-amz-security-token, Signature="
+	if !strings.HasPrefix(auth, expectedPrefix) {
+		t.Fatalf("Expected Authorization prefix\n%s\ngot\n%s", expectedPrefix, auth)
+	}
+
+	// the signature must be deterministic
+	req2, _ := http.NewRequest(http.MethodGet, req.URL.String(), nil)
+	p.sign(req2, nil, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	if auth != req2.Header.Get("Authorization") {
+		t.Fatal("Expected the same signature for the same request")
+	}
+
+	// different secret -> different signature
+	p.SecretAccessKey = "other"
+	req3, _ := http.NewRequest(http.MethodGet, req.URL.String(), nil)
+	p.sign(req3, nil, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC))
+	if auth == req3.Header.Get("Authorization") {
+		t.Fatal("Expected different signature for different secret")
+	}
+}
@@ -16,6 +16,7 @@ func init() {
 }
 
 var _ Provider = (*Apple)(nil)
+var _ NonceValidator = (*Apple)(nil)
 
 // NameApple is the unique name of the Apple provider.
 const NameApple string = "apple"
@@ -25,6 +26,7 @@ const NameApple string = "apple"
 // OIDC differences: https://bitbucket.org/openid/connect/src/master/How-Sign-in-with-Apple-differs-from-OpenID-Connect.md.
 type Apple struct {
 	BaseProvider
+	idTokenNonce
 
 	jwksURL string
 }
@@ -130,6 +132,11 @@ func (p *Apple) parseAndVerifyIdToken(idToken string) (jwt.MapClaims, error) {
 		return nil, err
 	}
 
+	err = p.validateNonce(claims)
+	if err != nil {
+		return nil, err
+	}
+
 	// validate id_token signature
 	//
 	// note: this step could be technically considered optional because we trust
@@ -137,7 +144,7 @@ func (p *Apple) parseAndVerifyIdToken(idToken string) (jwt.MapClaims, error) {
 	// (see also https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation)
 	// ---
 	kid, _ := t.Header["kid"].(string)
-	err = validateIdTokenSignature(p.ctx, idToken, p.jwksURL, kid)
+	err = validateIdTokenSignature(p.httpContext(), idToken, p.jwksURL, kid)
 	if err != nil {
 		return nil, err
 	}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
 
 	"github.com/pocketbase/pocketbase/tools/types"
 	"golang.org/x/oauth2"
@@ -36,6 +38,13 @@ type Provider interface {
 	// SetContext assigns the specified context to the current provider.
 	SetContext(ctx context.Context)
 
+	// HTTPClient returns the custom http client of the provider requests
+	// (nil means that the default one will be used).
+	HTTPClient() *http.Client
+
+	// SetHTTPClient sets a custom http client for the provider requests.
+	SetHTTPClient(client *http.Client)
+
 	// PKCE indicates whether the provider can use the PKCE flow.
 	PKCE() bool
 
@@ -86,6 +95,15 @@ type Provider interface {
 	// SetTokenURL sets the provider's TokenURL.
 	SetTokenURL(url string)
 
+	// DeviceAuthURL returns the provider's device authorization
+	// service url (RFC 8628).
+	//
+	// Empty url means that the provider doesn't support the device authorization grant.
+	DeviceAuthURL() string
+
+	// SetDeviceAuthURL sets the provider's DeviceAuthURL.
+	SetDeviceAuthURL(url string)
+
 	// UserInfoURL returns the provider's user info api url.
 	UserInfoURL() string
 
@@ -109,6 +127,20 @@ type Provider interface {
 	// FetchToken converts an authorization code to token.
 	FetchToken(code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error)
 
+	// RefreshToken exchanges the provided refresh token for a new token.
+	RefreshToken(refreshToken string) (*oauth2.Token, error)
+
+	// StartDeviceAuth initiates a new device authorization request and returns
+	// the device and user codes with the user verification uri.
+	StartDeviceAuth(opts ...oauth2.AuthCodeOption) (*oauth2.DeviceAuthResponse, error)
+
+	// PollDeviceToken polls the provider token endpoint until the
+	// device authorization is approved by the user and returns the issued token.
+	//
+	// The polling stops with an error if the user denies the authorization,
+	// the device code expires or the provider context is canceled.
+	PollDeviceToken(deviceAuth *oauth2.DeviceAuthResponse, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error)
+
 	// FetchRawUserInfo requests and marshalizes into `result` the
 	// the OAuth user api response.
 	FetchRawUserInfo(token *oauth2.Token) ([]byte, error)
@@ -118,6 +150,27 @@ type Provider interface {
 	FetchAuthUser(token *oauth2.Token) (user *AuthUser, err error)
 }
 
+// NonceValidator defines an optional Provider interface for the providers
+// that support validating the OpenID Connect id_token "nonce" claim.
+type NonceValidator interface {
+	// SetNonce sets the expected id_token "nonce" claim value.
+	//
+	// Empty nonce disables the validation.
+	SetNonce(nonce string)
+
+	// NonceValidated reports whether the expected nonce
+	// was validated as part of the last FetchAuthUser call.
+	NonceValidated() bool
+}
+
+// AvatarFetcher defines an optional Provider interface for the providers
+// that support downloading the user avatar image with the OAuth2 access token
+// (e.g. when the provider doesn't return a public avatar url).
+type AvatarFetcher interface {
+	// FetchAvatar downloads and returns the raw avatar image of the token owner.
+	FetchAvatar(token *oauth2.Token) ([]byte, error)
+}
+
 // wrapFactory is a helper that wraps a Provider specific factory
 // function and returns its result as Provider interface.
 func wrapFactory[T Provider](factory func() T) ProviderFactoryFunc {
@@ -138,12 +191,56 @@ type AuthUser struct {
 	AccessToken  string         `json:"accessToken"`
 	RefreshToken string         `json:"refreshToken"`
 
+	// Groups is the list of the user group memberships
+	// (available only for providers that support it, e.g. GitLab with "fetchGroups").
+	Groups []string `json:"groups,omitempty"`
+
+	// Claims is a list with extra mapped OAuth2 user claims
+	// where the key is the name of the auth record field to sync
+	// (e.g. populated from the collection OAuth2 provider claims mapping).
+	Claims map[string]any `json:"claims,omitempty"`
+
 	// @todo
 	// deprecated: use AvatarURL instead
 	// AvatarUrl will be removed after dropping v0.22 support
 	AvatarUrl string `json:"avatarUrl"`
 }
 
+// RawClaim returns the RawUser value at the specified dot-notation
+// path (e.g. "realm_access.roles" or "ocs.data.groups").
+//
+// Array items could be accessed by their index (e.g. "emails.0").
+//
+// Returns false if the path doesn't exist.
+func (au *AuthUser) RawClaim(path string) (any, bool) {
+	if path == "" {
+		return nil, false
+	}
+
+	var current any = au.RawUser
+
+	for _, key := range strings.Split(path, ".") {
+		switch v := current.(type) {
+		case map[string]any:
+			item, ok := v[key]
+			if !ok {
+				return nil, false
+			}
+			current = item
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(v) {
+				return nil, false
+			}
+			current = v[index]
+		default:
+			return nil, false
+		}
+	}
+
+	return current, true
+}
+
 // MarshalJSON implements the [json.Marshaler] interface.
 //
 // @todo remove after dropping v0.22 support
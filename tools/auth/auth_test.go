@@ -7,7 +7,7 @@ import (
 )
 
 func TestProvidersCount(t *testing.T) {
-	expected := 33
+	expected := 40
 
 	if total := len(auth.Providers); total != expected {
 		t.Fatalf("Expected %d providers, got %d", expected, total)
@@ -323,4 +323,110 @@ func TestNewProviderByName(t *testing.T) {
 	if _, ok := p.(*auth.Lark); !ok {
 		t.Error("Expected to be instance of *auth.Lark")
 	}
+
+	// wechat
+	p, err = auth.NewProviderByName(auth.NameWeChat)
+	if err != nil {
+		t.Errorf("Expected nil, got error %v", err)
+	}
+	if _, ok := p.(*auth.WeChat); !ok {
+		t.Error("Expected to be instance of *auth.WeChat")
+	}
+
+	// wechat mini program
+	p, err = auth.NewProviderByName(auth.NameWeChatMiniProgram)
+	if err != nil {
+		t.Errorf("Expected nil, got error %v", err)
+	}
+	if _, ok := p.(*auth.WeChatMiniProgram); !ok {
+		t.Error("Expected to be instance of *auth.WeChatMiniProgram")
+	}
+
+	// qq
+	p, err = auth.NewProviderByName(auth.NameQQ)
+	if err != nil {
+		t.Errorf("Expected nil, got error %v", err)
+	}
+	if _, ok := p.(*auth.QQ); !ok {
+		t.Error("Expected to be instance of *auth.QQ")
+	}
+
+	// line
+	p, err = auth.NewProviderByName(auth.NameLine)
+	if err != nil {
+		t.Errorf("Expected nil, got error %v", err)
+	}
+	if _, ok := p.(*auth.Line); !ok {
+		t.Error("Expected to be instance of *auth.Line")
+	}
+
+	// dropbox
+	p, err = auth.NewProviderByName(auth.NameDropbox)
+	if err != nil {
+		t.Errorf("Expected nil, got error %v", err)
+	}
+	if _, ok := p.(*auth.Dropbox); !ok {
+		t.Error("Expected to be instance of *auth.Dropbox")
+	}
+
+	// salesforce
+	p, err = auth.NewProviderByName(auth.NameSalesforce)
+	if err != nil {
+		t.Errorf("Expected nil, got error %v", err)
+	}
+	if _, ok := p.(*auth.Salesforce); !ok {
+		t.Error("Expected to be instance of *auth.Salesforce")
+	}
+
+	// shopify
+	p, err = auth.NewProviderByName(auth.NameShopify)
+	if err != nil {
+		t.Errorf("Expected nil, got error %v", err)
+	}
+	if _, ok := p.(*auth.Shopify); !ok {
+		t.Error("Expected to be instance of *auth.Shopify")
+	}
+}
+
+func TestAuthUserRawClaim(t *testing.T) {
+	user := &auth.AuthUser{
+		RawUser: map[string]any{
+			"a": "test",
+			"b": map[string]any{
+				"c": []any{"x", map[string]any{"d": 123}},
+			},
+			"e": nil,
+		},
+	}
+
+	scenarios := []struct {
+		path          string
+		expectedValue any
+		expectedOk    bool
+	}{
+		{"", nil, false},
+		{"missing", nil, false},
+		{"a", "test", true},
+		{"a.missing", nil, false},
+		{"b.c.0", "x", true},
+		{"b.c.1.d", 123, true},
+		{"b.c.2", nil, false},
+		{"b.c.-1", nil, false},
+		{"b.c.x", nil, false},
+		{"e", nil, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.path, func(t *testing.T) {
+			v, ok := user.RawClaim(s.path)
+
+			if ok != s.expectedOk {
+				t.Fatalf("Expected ok %v, got %v", s.expectedOk, ok)
+			}
+
+			if v != s.expectedValue {
+				t.Fatalf("Expected value %v, got %v", s.expectedValue, v)
+			}
+		})
+	}
 }
@@ -2,6 +2,7 @@ package auth
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"maps"
@@ -12,17 +13,19 @@ import (
 
 // BaseProvider defines common fields and methods used by OAuth2 client providers.
 type BaseProvider struct {
-	ctx          context.Context
-	clientId     string
-	clientSecret string
-	displayName  string
-	redirectURL  string
-	authURL      string
-	tokenURL     string
-	userInfoURL  string
-	scopes       []string
-	pkce         bool
-	extra        map[string]any
+	ctx           context.Context
+	clientId      string
+	clientSecret  string
+	displayName   string
+	redirectURL   string
+	authURL       string
+	tokenURL      string
+	userInfoURL   string
+	deviceAuthURL string
+	scopes        []string
+	pkce          bool
+	extra         map[string]any
+	httpClient    *http.Client
 }
 
 // Context implements Provider.Context() interface method.
@@ -35,6 +38,16 @@ func (p *BaseProvider) SetContext(ctx context.Context) {
 	p.ctx = ctx
 }
 
+// HTTPClient implements Provider.HTTPClient() interface method.
+func (p *BaseProvider) HTTPClient() *http.Client {
+	return p.httpClient
+}
+
+// SetHTTPClient implements Provider.SetHTTPClient() interface method.
+func (p *BaseProvider) SetHTTPClient(client *http.Client) {
+	p.httpClient = client
+}
+
 // PKCE implements Provider.PKCE() interface method.
 func (p *BaseProvider) PKCE() bool {
 	return p.pkce
@@ -115,6 +128,16 @@ func (p *BaseProvider) SetTokenURL(url string) {
 	p.tokenURL = url
 }
 
+// DeviceAuthURL implements Provider.DeviceAuthURL() interface method.
+func (p *BaseProvider) DeviceAuthURL() string {
+	return p.deviceAuthURL
+}
+
+// SetDeviceAuthURL implements Provider.SetDeviceAuthURL() interface method.
+func (p *BaseProvider) SetDeviceAuthURL(url string) {
+	p.deviceAuthURL = url
+}
+
 // UserInfoURL implements Provider.UserInfoURL() interface method.
 func (p *BaseProvider) UserInfoURL() string {
 	return p.userInfoURL
@@ -142,12 +165,47 @@ func (p *BaseProvider) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption)
 
 // FetchToken implements Provider.FetchToken() interface method.
 func (p *BaseProvider) FetchToken(code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
-	return p.oauth2Config().Exchange(p.ctx, code, opts...)
+	return p.oauth2Config().Exchange(p.httpContext(), code, opts...)
+}
+
+// RefreshToken implements Provider.RefreshToken() interface method.
+func (p *BaseProvider) RefreshToken(refreshToken string) (*oauth2.Token, error) {
+	if refreshToken == "" {
+		return nil, errors.New("missing refresh token")
+	}
+
+	// note: the token source preserves the provided refresh token if a new one is not returned
+	return p.oauth2Config().TokenSource(p.httpContext(), &oauth2.Token{RefreshToken: refreshToken}).Token()
+}
+
+// StartDeviceAuth implements Provider.StartDeviceAuth() interface method.
+func (p *BaseProvider) StartDeviceAuth(opts ...oauth2.AuthCodeOption) (*oauth2.DeviceAuthResponse, error) {
+	if p.deviceAuthURL == "" {
+		return nil, errors.New("the provider doesn't support the device authorization grant")
+	}
+
+	return p.oauth2Config().DeviceAuth(p.httpContext(), opts...)
+}
+
+// PollDeviceToken implements Provider.PollDeviceToken() interface method.
+func (p *BaseProvider) PollDeviceToken(deviceAuth *oauth2.DeviceAuthResponse, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	if deviceAuth == nil || deviceAuth.DeviceCode == "" {
+		return nil, errors.New("missing device code")
+	}
+
+	return p.oauth2Config().DeviceAccessToken(p.httpContext(), deviceAuth, opts...)
 }
 
 // Client implements Provider.Client() interface method.
 func (p *BaseProvider) Client(token *oauth2.Token) *http.Client {
-	return p.oauth2Config().Client(p.ctx, token)
+	client := p.oauth2Config().Client(p.httpContext(), token)
+
+	// the oauth2 client reuses only the transport of the context client
+	if p.httpClient != nil {
+		client.Timeout = p.httpClient.Timeout
+	}
+
+	return client
 }
 
 // FetchRawUserInfo implements Provider.FetchRawUserInfo() interface method.
@@ -188,6 +246,16 @@ func (p *BaseProvider) sendRawUserInfoRequest(req *http.Request, token *oauth2.T
 	return result, nil
 }
 
+// httpContext returns the provider context with the
+// custom provider http client (if any) as [oauth2.HTTPClient] value.
+func (p *BaseProvider) httpContext() context.Context {
+	if p.httpClient == nil {
+		return p.ctx
+	}
+
+	return context.WithValue(p.ctx, oauth2.HTTPClient, p.httpClient)
+}
+
 // oauth2Config constructs a oauth2.Config instance based on the provider settings.
 func (p *BaseProvider) oauth2Config() *oauth2.Config {
 	return &oauth2.Config{
@@ -196,8 +264,9 @@ func (p *BaseProvider) oauth2Config() *oauth2.Config {
 		ClientSecret: p.clientSecret,
 		Scopes:       p.scopes,
 		Endpoint: oauth2.Endpoint{
-			AuthURL:  p.authURL,
-			TokenURL: p.tokenURL,
+			AuthURL:       p.authURL,
+			TokenURL:      p.tokenURL,
+			DeviceAuthURL: p.deviceAuthURL,
 		},
 	}
 }
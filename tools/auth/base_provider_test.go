@@ -4,6 +4,9 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
 
 	"golang.org/x/oauth2"
@@ -153,6 +156,22 @@ func TestTokenURL(t *testing.T) {
 	}
 }
 
+func TestDeviceAuthURL(t *testing.T) {
+	b := BaseProvider{}
+
+	before := b.DeviceAuthURL()
+	if before != "" {
+		t.Fatalf("Expected deviceAuthURL to be empty, got %v", before)
+	}
+
+	b.SetDeviceAuthURL("test")
+
+	after := b.DeviceAuthURL()
+	if after != "test" {
+		t.Fatalf("Expected deviceAuthURL to be 'test', got %v", after)
+	}
+}
+
 func TestUserInfoURL(t *testing.T) {
 	b := BaseProvider{}
 
@@ -222,6 +241,99 @@ func TestBuildAuthURL(t *testing.T) {
 	}
 }
 
+func TestStartDeviceAuth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		if r.URL.Path != "/device" || r.Form.Get("client_id") != "test_client" || r.Form.Get("scope") != "test_scope" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"device_code":"test_device_code","user_code":"TEST-CODE","verification_uri":"https://example.com/device","expires_in":600,"interval":3}`))
+	}))
+	defer server.Close()
+
+	b := BaseProvider{
+		ctx:      context.Background(),
+		clientId: "test_client",
+		scopes:   []string{"test_scope"},
+	}
+
+	if _, err := b.StartDeviceAuth(); err == nil {
+		t.Fatal("Expected error for missing deviceAuthURL")
+	}
+
+	b.SetDeviceAuthURL(server.URL + "/device")
+
+	result, err := b.StartDeviceAuth()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if result.DeviceCode != "test_device_code" ||
+		result.UserCode != "TEST-CODE" ||
+		result.VerificationURI != "https://example.com/device" ||
+		result.Interval != 3 ||
+		result.Expiry.IsZero() {
+		t.Fatalf("Unexpected device auth response %#v", result)
+	}
+}
+
+func TestPollDeviceToken(t *testing.T) {
+	var calls atomic.Int32
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Form.Get("device_code") != "test_device_code" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"access_denied"}`))
+			return
+		}
+
+		// pending on the first call
+		if calls.Add(1) == 1 {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"error":"authorization_pending"}`))
+			return
+		}
+
+		w.Write([]byte(`{"access_token":"test_access_token","token_type":"Bearer"}`))
+	}))
+	defer server.Close()
+
+	b := BaseProvider{
+		ctx:      context.Background(),
+		clientId: "test_client",
+		tokenURL: server.URL + "/token",
+	}
+
+	if _, err := b.PollDeviceToken(&oauth2.DeviceAuthResponse{}); err == nil {
+		t.Fatal("Expected error for missing device code")
+	}
+
+	if _, err := b.PollDeviceToken(&oauth2.DeviceAuthResponse{DeviceCode: "invalid", Interval: 1}); err == nil {
+		t.Fatal("Expected access denied error")
+	}
+
+	token, err := b.PollDeviceToken(&oauth2.DeviceAuthResponse{DeviceCode: "test_device_code", Interval: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != "test_access_token" {
+		t.Fatalf("Expected access token %q, got %q", "test_access_token", token.AccessToken)
+	}
+
+	if v := calls.Load(); v != 2 {
+		t.Fatalf("Expected 2 token calls, got %d", v)
+	}
+}
+
 func TestClient(t *testing.T) {
 	b := BaseProvider{}
 
@@ -233,12 +345,13 @@ func TestClient(t *testing.T) {
 
 func TestOauth2Config(t *testing.T) {
 	b := BaseProvider{
-		authURL:      "authURL_test",
-		tokenURL:     "tokenURL_test",
-		redirectURL:  "redirectURL_test",
-		clientId:     "clientId_test",
-		clientSecret: "clientSecret_test",
-		scopes:       []string{"test"},
+		authURL:       "authURL_test",
+		tokenURL:      "tokenURL_test",
+		deviceAuthURL: "deviceAuthURL_test",
+		redirectURL:   "redirectURL_test",
+		clientId:      "clientId_test",
+		clientSecret:  "clientSecret_test",
+		scopes:        []string{"test"},
 	}
 
 	result := b.oauth2Config()
@@ -263,6 +376,10 @@ func TestOauth2Config(t *testing.T) {
 		t.Errorf("Expected authURL %s, got %s", b.TokenURL(), result.Endpoint.TokenURL)
 	}
 
+	if result.Endpoint.DeviceAuthURL != b.DeviceAuthURL() {
+		t.Errorf("Expected deviceAuthURL %s, got %s", b.DeviceAuthURL(), result.Endpoint.DeviceAuthURL)
+	}
+
 	if len(result.Scopes) != len(b.Scopes()) || result.Scopes[0] != b.Scopes()[0] {
 		t.Errorf("Expected scopes %s, got %s", b.Scopes(), result.Scopes)
 	}
@@ -3,9 +3,16 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"net/url"
+	"slices"
+	"strings"
 
+	"github.com/pocketbase/pocketbase/tools/list"
 	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
 	"golang.org/x/oauth2"
 )
 
@@ -19,6 +26,15 @@ var _ Provider = (*Discord)(nil)
 const NameDiscord string = "discord"
 
 // Discord allows authentication via Discord OAuth2.
+//
+// The provider support the following Extra config options:
+//   - "fetchGuilds" - if true, the ids of the user guilds are fetched and assigned to [AuthUser.Groups].
+//   - "requiredGuilds" - list of guild ids; if set, the user must be a member of at least one of them.
+//   - "rolesGuild" - guild id whose user member roles are fetched and assigned
+//     to [AuthUser.Groups] in the format "guildId/roleId".
+//   - "requiredRoles" - list of "rolesGuild" role ids; if set, the user must have at least one of them.
+//
+// The additional "guilds" and "guilds.members.read" scopes are automatically requested when needed.
 type Discord struct {
 	BaseProvider
 }
@@ -38,6 +54,19 @@ func NewDiscordProvider() *Discord {
 	}}
 }
 
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+func (p *Discord) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	if p.fetchGuilds() && !slices.Contains(p.scopes, "guilds") {
+		p.scopes = append(slices.Clone(p.scopes), "guilds")
+	}
+
+	if p.rolesGuild() != "" && !slices.Contains(p.scopes, "guilds.members.read") {
+		p.scopes = append(slices.Clone(p.scopes), "guilds.members.read")
+	}
+
+	return p.BaseProvider.BuildAuthURL(state, opts...)
+}
+
 // FetchAuthUser returns an AuthUser instance from Discord's user api.
 //
 // API reference:  https://discord.com/developers/docs/resources/user#user-object
@@ -87,5 +116,139 @@ func (p *Discord) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 		user.Email = extracted.Email
 	}
 
+	if err := p.fetchMemberships(token, user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
+
+func (p *Discord) fetchGuilds() bool {
+	return cast.ToBool(p.Extra()["fetchGuilds"]) || len(p.requiredGuilds()) > 0
+}
+
+func (p *Discord) requiredGuilds() []string {
+	return list.ToUniqueStringSlice(p.Extra()["requiredGuilds"])
+}
+
+func (p *Discord) rolesGuild() string {
+	return strings.TrimSpace(cast.ToString(p.Extra()["rolesGuild"]))
+}
+
+func (p *Discord) requiredRoles() []string {
+	return list.ToUniqueStringSlice(p.Extra()["requiredRoles"])
+}
+
+// fetchMemberships fetches the user guilds and roles (if configured),
+// assigns them to user.Groups and checks the guild and roles requirements.
+func (p *Discord) fetchMemberships(token *oauth2.Token, user *AuthUser) error {
+	if p.fetchGuilds() {
+		guilds, err := p.fetchUserGuilds(token)
+		if err != nil {
+			return err
+		}
+
+		required := p.requiredGuilds()
+		if len(required) > 0 && !slices.ContainsFunc(guilds, func(id string) bool {
+			return slices.Contains(required, id)
+		}) {
+			return errors.New("the Discord user is not a member of any of the required guilds")
+		}
+
+		user.Groups = append(user.Groups, guilds...)
+	}
+
+	if guildId := p.rolesGuild(); guildId != "" {
+		roles, err := p.fetchUserRoles(token, guildId)
+		if err != nil {
+			return err
+		}
+
+		required := p.requiredRoles()
+		if len(required) > 0 && !slices.ContainsFunc(roles, func(id string) bool {
+			return slices.Contains(required, id)
+		}) {
+			return errors.New("the Discord user doesn't have any of the required guild roles")
+		}
+
+		for _, role := range roles {
+			user.Groups = append(user.Groups, guildId+"/"+role)
+		}
+	} else if len(p.requiredRoles()) > 0 {
+		return errors.New(`the Discord "requiredRoles" option requires "rolesGuild" to be set`)
+	}
+
+	return nil
+}
+
+// fetchUserGuilds sends API request to retrieve the ids of the user guilds.
+//
+// API reference: https://discord.com/developers/docs/resources/user#get-current-user-guilds
+func (p *Discord) fetchUserGuilds(token *oauth2.Token) ([]string, error) {
+	items := []struct {
+		Id string `json:"id"`
+	}{}
+
+	// note: 200 is also the max number of guilds a (non-bot) user could join
+	err := p.sendMembershipRequest(token, p.userInfoURL+"/guilds?limit=200", &items)
+	if err != nil {
+		return nil, err
+	}
+
+	guilds := make([]string, 0, len(items))
+	for _, item := range items {
+		guilds = append(guilds, item.Id)
+	}
+
+	return guilds, nil
+}
+
+// fetchUserRoles sends API request to retrieve the user roles in the specified guild.
+//
+// Returns an empty slice if the user is not a member of the guild.
+//
+// API reference: https://discord.com/developers/docs/resources/user#get-current-user-guild-member
+func (p *Discord) fetchUserRoles(token *oauth2.Token, guildId string) ([]string, error) {
+	member := struct {
+		Roles []string `json:"roles"`
+	}{}
+
+	err := p.sendMembershipRequest(token, p.userInfoURL+"/guilds/"+url.PathEscape(guildId)+"/member", &member)
+	if err != nil {
+		if errors.Is(err, errDiscordNotFound) {
+			return []string{}, nil
+		}
+		return nil, err
+	}
+
+	return member.Roles, nil
+}
+
+var errDiscordNotFound = errors.New("not found")
+
+func (p *Discord) sendMembershipRequest(token *oauth2.Token, endpoint string, result any) error {
+	response, err := p.Client(token).Get(endpoint)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	content, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+
+	if response.StatusCode == 404 {
+		return errDiscordNotFound
+	}
+
+	if response.StatusCode >= 400 {
+		return fmt.Errorf(
+			"failed to fetch Discord user memberships (%d):\n%s",
+			response.StatusCode,
+			string(content),
+		)
+	}
+
+	return json.Unmarshal(content, result)
+}
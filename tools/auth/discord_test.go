@@ -0,0 +1,177 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func newTestDiscordServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/users/@me", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":       "123",
+			"username": "test",
+			"email":    "test@example.com",
+			"verified": true,
+		})
+	})
+	mux.HandleFunc("/api/users/@me/guilds", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]any{{"id": "g1"}, {"id": "g2"}})
+	})
+	mux.HandleFunc("/api/users/@me/guilds/g1/member", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"roles": []string{"r1", "r2"}})
+	})
+	mux.HandleFunc("/api/users/@me/guilds/g3/member", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("/api/users/@me/guilds/g4/member", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	})
+
+	server := httptest.NewServer(mux)
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestDiscordBuildAuthURL(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		extra       map[string]any
+		expectScope string
+	}{
+		{"without memberships", nil, "identify email"},
+		{"with fetchGuilds", map[string]any{"fetchGuilds": true}, "identify email guilds"},
+		{"with requiredGuilds", map[string]any{"requiredGuilds": []any{"g1"}}, "identify email guilds"},
+		{"with rolesGuild", map[string]any{"rolesGuild": "g1"}, "identify email guilds.members.read"},
+		{
+			"with requiredGuilds and rolesGuild",
+			map[string]any{"requiredGuilds": []any{"g1"}, "rolesGuild": "g1"},
+			"identify email guilds guilds.members.read",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewDiscordProvider()
+			p.SetExtra(s.extra)
+
+			authURL := p.BuildAuthURL("test_state")
+			if !strings.Contains(authURL, "scope="+strings.ReplaceAll(s.expectScope, " ", "+")+"&") {
+				t.Fatalf("Expected scope %q in %q", s.expectScope, authURL)
+			}
+		})
+	}
+}
+
+func TestDiscordFetchAuthUserMemberships(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		extra          map[string]any
+		expectError    bool
+		expectedGroups []string
+	}{
+		{
+			"without memberships",
+			nil,
+			false,
+			nil,
+		},
+		{
+			"with fetchGuilds",
+			map[string]any{"fetchGuilds": true},
+			false,
+			[]string{"g1", "g2"},
+		},
+		{
+			"with satisfied requiredGuilds",
+			map[string]any{"requiredGuilds": []any{"g3", "g2"}},
+			false,
+			[]string{"g1", "g2"},
+		},
+		{
+			"with unsatisfied requiredGuilds",
+			map[string]any{"requiredGuilds": []any{"g3"}},
+			true,
+			nil,
+		},
+		{
+			"with rolesGuild",
+			map[string]any{"rolesGuild": "g1"},
+			false,
+			[]string{"g1/r1", "g1/r2"},
+		},
+		{
+			"with satisfied requiredRoles",
+			map[string]any{"requiredGuilds": []any{"g1"}, "rolesGuild": "g1", "requiredRoles": []any{"r2"}},
+			false,
+			[]string{"g1", "g2", "g1/r1", "g1/r2"},
+		},
+		{
+			"with unsatisfied requiredRoles",
+			map[string]any{"rolesGuild": "g1", "requiredRoles": []any{"r3"}},
+			true,
+			nil,
+		},
+		{
+			"with requiredRoles for a non-member guild",
+			map[string]any{"rolesGuild": "g3", "requiredRoles": []any{"r1"}},
+			true,
+			nil,
+		},
+		{
+			"with non-member rolesGuild",
+			map[string]any{"rolesGuild": "g3"},
+			false,
+			nil,
+		},
+		{
+			"with failed roles request",
+			map[string]any{"rolesGuild": "g4"},
+			true,
+			nil,
+		},
+		{
+			"with requiredRoles but without rolesGuild",
+			map[string]any{"requiredRoles": []any{"r1"}},
+			true,
+			nil,
+		},
+	}
+
+	server := newTestDiscordServer(t)
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewDiscordProvider()
+			p.SetUserInfoURL(server.URL + "/api/users/@me")
+			p.SetExtra(s.extra)
+
+			user, err := p.FetchAuthUser(&oauth2.Token{AccessToken: "test"})
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if user.Id != "123" {
+				t.Fatalf("Expected user id %q, got %q", "123", user.Id)
+			}
+
+			if !slices.Equal(user.Groups, s.expectedGroups) {
+				t.Fatalf("Expected groups %v, got %v", s.expectedGroups, user.Groups)
+			}
+		})
+	}
+}
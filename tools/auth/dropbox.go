@@ -0,0 +1,114 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+	"golang.org/x/oauth2"
+)
+
+func init() {
+	Providers[NameDropbox] = wrapFactory(NewDropboxProvider)
+}
+
+var _ Provider = (*Dropbox)(nil)
+
+// NameDropbox is the unique name of the Dropbox provider.
+const NameDropbox string = "dropbox"
+
+// Dropbox allows authentication via Dropbox OAuth2.
+//
+// The provider support the following Extra config options:
+//   - "offlineAccess" - if true, requests a long-lived refresh token
+//     (aka. token_access_type=offline) that is returned as [AuthUser.RefreshToken].
+//
+// Note that Dropbox access tokens are short-lived (~4 hours) and without
+// offline access there is no refresh token.
+type Dropbox struct {
+	BaseProvider
+}
+
+// NewDropboxProvider creates new Dropbox provider instance with some defaults.
+//
+// Docs: https://developers.dropbox.com/oauth-guide
+func NewDropboxProvider() *Dropbox {
+	return &Dropbox{BaseProvider{
+		ctx:         context.Background(),
+		displayName: "Dropbox",
+		pkce:        true,
+		scopes:      []string{"account_info.read"},
+		authURL:     "https://www.dropbox.com/oauth2/authorize",
+		tokenURL:    "https://api.dropboxapi.com/oauth2/token",
+		userInfoURL: "https://api.dropboxapi.com/2/users/get_current_account",
+	}}
+}
+
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+func (p *Dropbox) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	if cast.ToBool(p.Extra()["offlineAccess"]) {
+		opts = append(opts, oauth2.SetAuthURLParam("token_access_type", "offline"))
+	}
+
+	return p.BaseProvider.BuildAuthURL(state, opts...)
+}
+
+// FetchAuthUser returns an AuthUser instance based on the Dropbox's current account api.
+//
+// API reference: https://www.dropbox.com/developers/documentation/http/documentation#users-get_current_account
+func (p *Dropbox) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	data, err := p.FetchRawUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		Id   string `json:"account_id"`
+		Name struct {
+			DisplayName string `json:"display_name"`
+		} `json:"name"`
+		Email           string `json:"email"`
+		ProfilePhotoURL string `json:"profile_photo_url"`
+		EmailVerified   bool   `json:"email_verified"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	user := &AuthUser{
+		Id:           extracted.Id,
+		Name:         extracted.Name.DisplayName,
+		AvatarURL:    extracted.ProfilePhotoURL,
+		RawUser:      rawUser,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}
+
+	user.Expiry, _ = types.ParseDateTime(token.Expiry)
+
+	if extracted.EmailVerified {
+		user.Email = extracted.Email
+	}
+
+	return user, nil
+}
+
+// FetchRawUserInfo implements Provider.FetchRawUserInfo() interface method.
+//
+// This differ from BaseProvider because the Dropbox RPC endpoints
+// expect POST requests (without body and Content-Type header).
+func (p *Dropbox) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
+	req, err := http.NewRequestWithContext(p.ctx, "POST", p.userInfoURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.sendRawUserInfoRequest(req, token)
+}
@@ -0,0 +1,102 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestDropboxBuildAuthURL(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		extra          map[string]any
+		expectedAccess string
+	}{
+		{"without offlineAccess", nil, ""},
+		{"with offlineAccess", map[string]any{"offlineAccess": true}, "offline"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewDropboxProvider()
+			p.SetClientId("test_client")
+			p.SetExtra(s.extra)
+
+			authURL, err := url.Parse(p.BuildAuthURL("test_state"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if v := authURL.Query().Get("token_access_type"); v != s.expectedAccess {
+				t.Fatalf("Expected token_access_type %q, got %q", s.expectedAccess, v)
+			}
+
+			if v := authURL.Query().Get("scope"); v != "account_info.read" {
+				t.Fatalf("Expected scope %q, got %q", "account_info.read", v)
+			}
+		})
+	}
+}
+
+func TestDropboxFetchAuthUser(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		emailVerified bool
+		expectedEmail string
+	}{
+		{"unverified email", false, ""},
+		{"verified email", true, "test@example.com"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.Method != http.MethodPost {
+					t.Errorf("Expected POST request, got %s", r.Method)
+				}
+
+				if r.Header.Get("Content-Type") != "" {
+					t.Errorf("Expected no Content-Type header, got %q", r.Header.Get("Content-Type"))
+				}
+
+				if r.Header.Get("Authorization") != "Bearer test_access" {
+					w.WriteHeader(http.StatusUnauthorized)
+					return
+				}
+
+				json.NewEncoder(w).Encode(map[string]any{
+					"account_id":        "dbid:test",
+					"name":              map[string]any{"display_name": "test_name"},
+					"email":             "test@example.com",
+					"email_verified":    s.emailVerified,
+					"profile_photo_url": "https://example.com/avatar.png",
+				})
+			}))
+			defer server.Close()
+
+			p := NewDropboxProvider()
+			p.SetUserInfoURL(server.URL)
+
+			user, err := p.FetchAuthUser(&oauth2.Token{AccessToken: "test_access", RefreshToken: "test_refresh"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.Id != "dbid:test" || user.Name != "test_name" || user.AvatarURL != "https://example.com/avatar.png" {
+				t.Fatalf("Unexpected user %#v", user)
+			}
+
+			if user.Email != s.expectedEmail {
+				t.Fatalf("Expected email %q, got %q", s.expectedEmail, user.Email)
+			}
+
+			if user.RefreshToken != "test_refresh" {
+				t.Fatalf("Expected refresh token %q, got %q", "test_refresh", user.RefreshToken)
+			}
+		})
+	}
+}
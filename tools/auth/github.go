@@ -28,13 +28,14 @@ type Github struct {
 // NewGithubProvider creates new Github provider instance with some defaults.
 func NewGithubProvider() *Github {
 	return &Github{BaseProvider{
-		ctx:         context.Background(),
-		displayName: "GitHub",
-		pkce:        true, // technically is not supported yet but it is safe as the PKCE params are just ignored
-		scopes:      []string{"read:user", "user:email"},
-		authURL:     github.Endpoint.AuthURL,
-		tokenURL:    github.Endpoint.TokenURL,
-		userInfoURL: "https://api.github.com/user",
+		ctx:           context.Background(),
+		displayName:   "GitHub",
+		pkce:          true, // technically is not supported yet but it is safe as the PKCE params are just ignored
+		scopes:        []string{"read:user", "user:email"},
+		authURL:       github.Endpoint.AuthURL,
+		tokenURL:      github.Endpoint.TokenURL,
+		deviceAuthURL: github.Endpoint.DeviceAuthURL,
+		userInfoURL:   "https://api.github.com/user",
 	}}
 }
 
@@ -3,9 +3,15 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
 	"golang.org/x/oauth2"
 )
 
@@ -18,7 +24,17 @@ var _ Provider = (*Gitlab)(nil)
 // NameGitlab is the unique name of the Gitlab provider.
 const NameGitlab string = "gitlab"
 
+// gitlabMaxGroupsPages is the max number of fetched groups pages (100 groups per page).
+const gitlabMaxGroupsPages = 10
+
 // Gitlab allows authentication via Gitlab OAuth2.
+//
+// The provider support the following Extra config options:
+//   - "fetchGroups" - if true, the user group memberships are fetched and
+//     assigned to [AuthUser.Groups] as list of group full paths (e.g. "org/subgroup").
+//     This requires the additional "read_api" scope, which is automatically requested.
+//   - "groupsMinAccessLevel" - the min access level of the fetched group memberships
+//     (optional, default to 10, aka. Guest; see https://docs.gitlab.com/ee/api/members.html#roles).
 type Gitlab struct {
 	BaseProvider
 }
@@ -26,16 +42,26 @@ type Gitlab struct {
 // NewGitlabProvider creates new Gitlab provider instance with some defaults.
 func NewGitlabProvider() *Gitlab {
 	return &Gitlab{BaseProvider{
-		ctx:         context.Background(),
-		displayName: "GitLab",
-		pkce:        true,
-		scopes:      []string{"read_user"},
-		authURL:     "https://gitlab.com/oauth/authorize",
-		tokenURL:    "https://gitlab.com/oauth/token",
-		userInfoURL: "https://gitlab.com/api/v4/user",
+		ctx:           context.Background(),
+		displayName:   "GitLab",
+		pkce:          true,
+		scopes:        []string{"read_user"},
+		authURL:       "https://gitlab.com/oauth/authorize",
+		tokenURL:      "https://gitlab.com/oauth/token",
+		deviceAuthURL: "https://gitlab.com/oauth/authorize_device",
+		userInfoURL:   "https://gitlab.com/api/v4/user",
 	}}
 }
 
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+func (p *Gitlab) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	if p.fetchGroups() && !slices.Contains(p.scopes, "read_api") {
+		p.scopes = append(slices.Clone(p.scopes), "read_api")
+	}
+
+	return p.BaseProvider.BuildAuthURL(state, opts...)
+}
+
 // FetchAuthUser returns an AuthUser instance based the Gitlab's user api.
 //
 // API reference: https://docs.gitlab.com/ee/api/users.html#for-admin
@@ -74,5 +100,78 @@ func (p *Gitlab) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 
 	user.Expiry, _ = types.ParseDateTime(token.Expiry)
 
+	if p.fetchGroups() {
+		user.Groups, err = p.fetchUserGroups(token)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return user, nil
 }
+
+func (p *Gitlab) fetchGroups() bool {
+	return cast.ToBool(p.Extra()["fetchGroups"])
+}
+
+// fetchUserGroups sends API requests to retrieve the
+// full paths of the user group memberships.
+//
+// API reference: https://docs.gitlab.com/ee/api/groups.html#list-groups
+func (p *Gitlab) fetchUserGroups(token *oauth2.Token) ([]string, error) {
+	client := p.Client(token)
+
+	minAccessLevel := 10
+	if v := cast.ToInt(p.Extra()["groupsMinAccessLevel"]); v > 0 {
+		minAccessLevel = v
+	}
+
+	// the groups api is relative to the user info one
+	// (e.g. https://gitlab.com/api/v4/user -> https://gitlab.com/api/v4/groups)
+	groupsURL := strings.TrimSuffix(p.userInfoURL, "/user") + "/groups"
+
+	groups := []string{}
+
+	for page := 1; page <= gitlabMaxGroupsPages; page++ {
+		query := url.Values{}
+		query.Set("min_access_level", strconv.Itoa(minAccessLevel))
+		query.Set("per_page", "100")
+		query.Set("page", strconv.Itoa(page))
+
+		response, err := client.Get(groupsURL + "?" + query.Encode())
+		if err != nil {
+			return nil, err
+		}
+
+		content, err := io.ReadAll(response.Body)
+		response.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+
+		if response.StatusCode >= 400 {
+			return nil, fmt.Errorf(
+				"failed to fetch GitLab user groups (%d):\n%s",
+				response.StatusCode,
+				string(content),
+			)
+		}
+
+		items := []struct {
+			FullPath string `json:"full_path"`
+		}{}
+		if err := json.Unmarshal(content, &items); err != nil {
+			return nil, err
+		}
+
+		for _, item := range items {
+			groups = append(groups, item.FullPath)
+		}
+
+		if response.Header.Get("X-Next-Page") == "" {
+			break
+		}
+	}
+
+	return groups, nil
+}
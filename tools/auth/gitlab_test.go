@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func newTestGitlabServer(t *testing.T, groupsStatus int) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/v4/user", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"id":       123,
+			"username": "test",
+			"email":    "test@example.com",
+		})
+	})
+	mux.HandleFunc("/api/v4/groups", func(w http.ResponseWriter, r *http.Request) {
+		if groupsStatus >= 400 {
+			w.WriteHeader(groupsStatus)
+			return
+		}
+
+		if r.URL.Query().Get("min_access_level") != "30" {
+			t.Errorf("Expected min_access_level 30, got %q", r.URL.Query().Get("min_access_level"))
+		}
+
+		switch r.URL.Query().Get("page") {
+		case "1":
+			w.Header().Set("X-Next-Page", "2")
+			json.NewEncoder(w).Encode([]map[string]any{{"full_path": "org"}, {"full_path": "org/a"}})
+		case "2":
+			json.NewEncoder(w).Encode([]map[string]any{{"full_path": "org/b"}})
+		default:
+			t.Errorf("Unexpected page %q", r.URL.Query().Get("page"))
+		}
+	})
+
+	server := httptest.NewServer(mux)
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestGitlabBuildAuthURL(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		extra       map[string]any
+		expectScope string
+	}{
+		{"without fetchGroups", nil, "read_user"},
+		{"with fetchGroups", map[string]any{"fetchGroups": true}, "read_user read_api"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewGitlabProvider()
+			p.SetExtra(s.extra)
+
+			authURL := p.BuildAuthURL("test_state")
+			if !strings.Contains(authURL, "scope="+strings.ReplaceAll(s.expectScope, " ", "+")) {
+				t.Fatalf("Expected scope %q in %q", s.expectScope, authURL)
+			}
+		})
+	}
+}
+
+func TestGitlabFetchAuthUserGroups(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		extra          map[string]any
+		groupsStatus   int
+		expectError    bool
+		expectedGroups []string
+	}{
+		{
+			"without fetchGroups",
+			nil,
+			200,
+			false,
+			nil,
+		},
+		{
+			"with fetchGroups",
+			map[string]any{"fetchGroups": true, "groupsMinAccessLevel": 30},
+			200,
+			false,
+			[]string{"org", "org/a", "org/b"},
+		},
+		{
+			"with fetchGroups and failed groups request",
+			map[string]any{"fetchGroups": true, "groupsMinAccessLevel": 30},
+			403,
+			true,
+			nil,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			server := newTestGitlabServer(t, s.groupsStatus)
+
+			p := NewGitlabProvider()
+			p.SetUserInfoURL(server.URL + "/api/v4/user")
+			p.SetExtra(s.extra)
+
+			user, err := p.FetchAuthUser(&oauth2.Token{AccessToken: "test"})
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if user.Id != "123" {
+				t.Fatalf("Expected user id %q, got %q", "123", user.Id)
+			}
+
+			if !slices.Equal(user.Groups, s.expectedGroups) {
+				t.Fatalf("Expected groups %v, got %v", s.expectedGroups, user.Groups)
+			}
+		})
+	}
+}
@@ -32,9 +32,10 @@ func NewGoogleProvider() *Google {
 			"https://www.googleapis.com/auth/userinfo.profile",
 			"https://www.googleapis.com/auth/userinfo.email",
 		},
-		authURL:     "https://accounts.google.com/o/oauth2/v2/auth",
-		tokenURL:    "https://oauth2.googleapis.com/token",
-		userInfoURL: "https://www.googleapis.com/oauth2/v3/userinfo",
+		authURL:       "https://accounts.google.com/o/oauth2/v2/auth",
+		tokenURL:      "https://oauth2.googleapis.com/token",
+		userInfoURL:   "https://www.googleapis.com/oauth2/v3/userinfo",
+		deviceAuthURL: "https://oauth2.googleapis.com/device/code",
 	}}
 }
 
@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"time"
+
+	validation "github.com/go-ozzo/ozzo-validation/v4"
+	"golang.org/x/oauth2"
+)
+
+// HTTPClientConfig defines the http client configuration of the
+// outgoing provider requests (auth url discovery, token exchange, user info, etc.).
+//
+// It is usually used for self-hosted identity providers
+// that are behind a proxy or use certificates issued by an internal CA.
+type HTTPClientConfig struct {
+	// ProxyURL is an optional proxy url used for the provider requests
+	// (supported schemes: "http", "https", "socks5" and "socks5h").
+	ProxyURL string `form:"proxyURL" json:"proxyURL"`
+
+	// CACerts is an optional PEM encoded CA certificates bundle
+	// that will be trusted in addition to the system ones.
+	CACerts string `form:"caCerts" json:"caCerts"`
+
+	// Timeout is the optional max duration in seconds of a single provider request.
+	Timeout int64 `form:"timeout" json:"timeout"`
+}
+
+// IsZero reports whether the config has no custom options.
+func (c HTTPClientConfig) IsZero() bool {
+	return c.ProxyURL == "" && c.CACerts == "" && c.Timeout == 0
+}
+
+// Merge returns a new config with the non-zero fields of
+// other overwriting the corresponding fields of the current config.
+func (c HTTPClientConfig) Merge(other HTTPClientConfig) HTTPClientConfig {
+	if other.ProxyURL != "" {
+		c.ProxyURL = other.ProxyURL
+	}
+
+	if other.CACerts != "" {
+		c.CACerts = other.CACerts
+	}
+
+	if other.Timeout != 0 {
+		c.Timeout = other.Timeout
+	}
+
+	return c
+}
+
+// Validate makes HTTPClientConfig validatable by implementing [validation.Validatable] interface.
+func (c HTTPClientConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.ProxyURL, validation.By(checkProxyURL)),
+		validation.Field(&c.CACerts, validation.By(checkCACerts)),
+		validation.Field(&c.Timeout, validation.Min(0), validation.Max(300)),
+	)
+}
+
+// NewClient creates a new [http.Client] based on the current config.
+func (c HTTPClientConfig) NewClient() (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if c.ProxyURL != "" {
+		proxyURL, err := url.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, err
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if c.CACerts != "" {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+
+		if !pool.AppendCertsFromPEM([]byte(c.CACerts)) {
+			return nil, errors.New("failed to load the PEM encoded CA certificates")
+		}
+
+		transport.TLSClientConfig = &tls.Config{
+			RootCAs:    pool,
+			MinVersion: tls.VersionTLS12,
+		}
+	}
+
+	return &http.Client{
+		Transport: transport,
+		Timeout:   time.Duration(c.Timeout) * time.Second,
+	}, nil
+}
+
+var supportedProxySchemes = []string{"http", "https", "socks5", "socks5h"}
+
+func checkProxyURL(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	u, err := url.Parse(v)
+	if err != nil || u.Host == "" || !slices.Contains(supportedProxySchemes, u.Scheme) {
+		return validation.NewError("validation_invalid_proxy_url", "Must be a valid http, https or socks5 proxy url.")
+	}
+
+	return nil
+}
+
+func checkCACerts(value any) error {
+	v, _ := value.(string)
+	if v == "" {
+		return nil // nothing to check
+	}
+
+	if !x509.NewCertPool().AppendCertsFromPEM([]byte(v)) {
+		return validation.NewError("validation_invalid_ca_certs", "Must contain at least one valid PEM encoded certificate.")
+	}
+
+	return nil
+}
+
+// contextHTTPClient returns the [oauth2.HTTPClient] value of ctx
+// or [http.DefaultClient] if ctx doesn't have one.
+func contextHTTPClient(ctx context.Context) *http.Client {
+	if client, ok := ctx.Value(oauth2.HTTPClient).(*http.Client); ok && client != nil {
+		return client
+	}
+
+	return http.DefaultClient
+}
@@ -0,0 +1,225 @@
+package auth
+
+import (
+	"encoding/json"
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestHTTPClientConfigValidate(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		config         HTTPClientConfig
+		expectedErrors []string
+	}{
+		{
+			"zero value",
+			HTTPClientConfig{},
+			[]string{},
+		},
+		{
+			"invalid data",
+			HTTPClientConfig{
+				ProxyURL: "ftp://example.com",
+				CACerts:  "invalid",
+				Timeout:  -1,
+			},
+			[]string{"proxyURL", "caCerts", "timeout"},
+		},
+		{
+			"proxy url without host",
+			HTTPClientConfig{ProxyURL: "http://"},
+			[]string{"proxyURL"},
+		},
+		{
+			"too large timeout",
+			HTTPClientConfig{Timeout: 301},
+			[]string{"timeout"},
+		},
+		{
+			"valid data",
+			HTTPClientConfig{
+				ProxyURL: "socks5://127.0.0.1:1080",
+				CACerts:  testPEMCert(t),
+				Timeout:  300,
+			},
+			[]string{},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.config.Validate()
+
+			errs := map[string]any{}
+			if result != nil {
+				raw, _ := json.Marshal(result)
+				json.Unmarshal(raw, &errs)
+			}
+
+			if len(errs) != len(s.expectedErrors) {
+				t.Fatalf("Expected error keys %v, got %v", s.expectedErrors, errs)
+			}
+
+			for _, k := range s.expectedErrors {
+				if _, ok := errs[k]; !ok {
+					t.Fatalf("Missing expected error key %q in %v", k, errs)
+				}
+			}
+		})
+	}
+}
+
+func TestHTTPClientConfigMerge(t *testing.T) {
+	base := HTTPClientConfig{ProxyURL: "http://a", CACerts: "a", Timeout: 1}
+
+	result := base.Merge(HTTPClientConfig{ProxyURL: "http://b", Timeout: 2})
+
+	expected := HTTPClientConfig{ProxyURL: "http://b", CACerts: "a", Timeout: 2}
+	if result != expected {
+		t.Fatalf("Expected %v, got %v", expected, result)
+	}
+
+	if base.ProxyURL != "http://a" {
+		t.Fatalf("Expected the base config to remain unchanged, got %v", base)
+	}
+
+	if !(HTTPClientConfig{}).IsZero() || result.IsZero() {
+		t.Fatal("Unexpected IsZero result")
+	}
+}
+
+func TestHTTPClientConfigNewClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	caCerts := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+
+	t.Run("invalid CA certs", func(t *testing.T) {
+		_, err := HTTPClientConfig{CACerts: "invalid"}.NewClient()
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("without CA certs", func(t *testing.T) {
+		client, err := HTTPClientConfig{Timeout: 5}.NewClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if client.Timeout != 5*time.Second {
+			t.Fatalf("Expected 5s timeout, got %v", client.Timeout)
+		}
+
+		if _, err := client.Get(server.URL); err == nil {
+			t.Fatal("Expected unknown authority error, got nil")
+		}
+	})
+
+	t.Run("with CA certs", func(t *testing.T) {
+		client, err := HTTPClientConfig{CACerts: caCerts}.NewClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := client.Get(server.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+	})
+
+	t.Run("with proxy", func(t *testing.T) {
+		var proxyHits atomic.Int32
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxyHits.Add(1)
+			w.Write([]byte(r.URL.String()))
+		}))
+		defer proxy.Close()
+
+		client, err := HTTPClientConfig{ProxyURL: proxy.URL}.NewClient()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := client.Get("http://example.invalid/test")
+		if err != nil {
+			t.Fatal(err)
+		}
+		res.Body.Close()
+
+		if proxyHits.Load() != 1 {
+			t.Fatalf("Expected 1 proxy request, got %d", proxyHits.Load())
+		}
+	})
+}
+
+func TestBaseProviderHTTPClient(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+
+		if strings.HasSuffix(r.URL.Path, "/token") {
+			w.Write([]byte(`{"access_token":"test_access","token_type":"Bearer"}`))
+			return
+		}
+
+		w.Write([]byte(`{"id":"test_id"}`))
+	}))
+	defer server.Close()
+
+	p := NewGitlabProvider()
+	p.SetTokenURL(server.URL + "/token")
+	p.SetUserInfoURL(server.URL + "/user")
+
+	// default client (unknown authority)
+	if _, err := p.FetchToken("test_code"); err == nil {
+		t.Fatal("Expected FetchToken error with the default http client")
+	}
+
+	client := server.Client()
+	client.Timeout = 5 * time.Second
+	p.SetHTTPClient(client)
+
+	if p.HTTPClient() != client {
+		t.Fatal("Expected the custom http client to be set")
+	}
+
+	token, err := p.FetchToken("test_code")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Client(token).Timeout != client.Timeout {
+		t.Fatalf("Expected the token client to inherit the %v timeout", client.Timeout)
+	}
+
+	raw, err := p.FetchRawUserInfo(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(raw) != `{"id":"test_id"}` {
+		t.Fatalf("Unexpected raw user info %s", raw)
+	}
+
+	// discovery helpers should also use the context client
+	if contextHTTPClient(p.httpContext()) != client {
+		t.Fatal("Expected the custom http client as context client")
+	}
+
+}
+
+func testPEMCert(t *testing.T) string {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	return string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}))
+}
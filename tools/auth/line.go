@@ -0,0 +1,132 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/oauth2"
+)
+
+func init() {
+	Providers[NameLine] = wrapFactory(NewLineProvider)
+}
+
+var _ Provider = (*Line)(nil)
+var _ NonceValidator = (*Line)(nil)
+
+// NameLine is the unique name of the LINE provider.
+const NameLine string = "line"
+
+// Line allows authentication via LINE Login v2.1.
+//
+// Note that LINE returns the user email only as part of the id_token
+// (and only if the channel has the email permission approved).
+type Line struct {
+	BaseProvider
+	idTokenNonce
+}
+
+// NewLineProvider creates new LINE provider instance with some defaults.
+//
+// Docs: https://developers.line.biz/en/docs/line-login/integrate-line-login/
+func NewLineProvider() *Line {
+	return &Line{BaseProvider: BaseProvider{
+		ctx:         context.Background(),
+		displayName: "LINE",
+		pkce:        true,
+		scopes:      []string{"profile", "openid", "email"},
+		authURL:     "https://access.line.me/oauth2/v2.1/authorize",
+		tokenURL:    "https://api.line.me/oauth2/v2.1/token",
+		userInfoURL: "https://api.line.me/v2/profile",
+	}}
+}
+
+// FetchAuthUser returns an AuthUser instance based on the LINE's profile api and id_token.
+//
+// API reference: https://developers.line.biz/en/reference/line-login/#get-user-profile
+func (p *Line) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	data, err := p.FetchRawUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		Id         string `json:"userId"`
+		Name       string `json:"displayName"`
+		PictureURL string `json:"pictureUrl"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	user := &AuthUser{
+		Id:           extracted.Id,
+		Name:         extracted.Name,
+		AvatarURL:    extracted.PictureURL,
+		RawUser:      rawUser,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}
+
+	user.Expiry, _ = types.ParseDateTime(token.Expiry)
+
+	// the id_token is available only with the "openid" scope
+	if idToken, _ := token.Extra("id_token").(string); idToken != "" {
+		claims, err := p.parseAndVerifyIdToken(idToken)
+		if err != nil {
+			return nil, err
+		}
+
+		if sub, _ := claims["sub"].(string); sub != user.Id {
+			return nil, errors.New("the id_token sub doesn't match the LINE user id")
+		}
+
+		if email, _ := claims["email"].(string); email != "" {
+			user.Email = email
+			user.RawUser["email"] = email
+		}
+	}
+
+	return user, nil
+}
+
+// parseAndVerifyIdToken parses and verifies the provided LINE id_token.
+//
+// LINE signs the id_token of web logins with HS256 using the channel secret.
+//
+// API reference: https://developers.line.biz/en/docs/line-login/verify-id-token/
+func (p *Line) parseAndVerifyIdToken(idToken string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+
+	_, err := jwt.ParseWithClaims(
+		idToken,
+		claims,
+		func(t *jwt.Token) (any, error) {
+			return []byte(p.clientSecret), nil
+		},
+		jwt.WithValidMethods([]string{"HS256"}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(idTokenLeeway),
+		jwt.WithIssuer("https://access.line.me"),
+		jwt.WithAudience(p.clientId),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = p.validateNonce(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	return claims, nil
+}
@@ -0,0 +1,208 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+func TestLineFetchAuthUser(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v2/profile", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test_access" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"userId":      "test_id",
+			"displayName": "test_name",
+			"pictureUrl":  "https://example.com/avatar.png",
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   "https://access.line.me",
+			"sub":   "test_id",
+			"aud":   "test_client",
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(1 * time.Hour).Unix(),
+			"email": "test@example.com",
+		}
+	}
+
+	idToken := func(t *testing.T, method jwt.SigningMethod, key any, claims jwt.MapClaims) string {
+		signed, err := jwt.NewWithClaims(method, claims).SignedString(key)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+
+	scenarios := []struct {
+		name          string
+		idToken       func(t *testing.T) string
+		expectError   bool
+		expectedEmail string
+	}{
+		{
+			"without id_token",
+			func(t *testing.T) string { return "" },
+			false,
+			"",
+		},
+		{
+			"valid id_token",
+			func(t *testing.T) string {
+				return idToken(t, jwt.SigningMethodHS256, []byte("test_secret"), validClaims())
+			},
+			false,
+			"test@example.com",
+		},
+		{
+			"id_token signed with different secret",
+			func(t *testing.T) string {
+				return idToken(t, jwt.SigningMethodHS256, []byte("other_secret"), validClaims())
+			},
+			true,
+			"",
+		},
+		{
+			"id_token with unsupported signing method",
+			func(t *testing.T) string {
+				return idToken(t, jwt.SigningMethodHS512, []byte("test_secret"), validClaims())
+			},
+			true,
+			"",
+		},
+		{
+			"id_token with invalid aud",
+			func(t *testing.T) string {
+				claims := validClaims()
+				claims["aud"] = "other_client"
+				return idToken(t, jwt.SigningMethodHS256, []byte("test_secret"), claims)
+			},
+			true,
+			"",
+		},
+		{
+			"id_token with different sub",
+			func(t *testing.T) string {
+				claims := validClaims()
+				claims["sub"] = "other_id"
+				return idToken(t, jwt.SigningMethodHS256, []byte("test_secret"), claims)
+			},
+			true,
+			"",
+		},
+		{
+			"expired id_token",
+			func(t *testing.T) string {
+				claims := validClaims()
+				claims["exp"] = time.Now().Add(-1 * time.Hour).Unix()
+				return idToken(t, jwt.SigningMethodHS256, []byte("test_secret"), claims)
+			},
+			true,
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewLineProvider()
+			p.SetClientId("test_client")
+			p.SetClientSecret("test_secret")
+			p.SetUserInfoURL(server.URL + "/v2/profile")
+
+			token := &oauth2.Token{AccessToken: "test_access"}
+			if raw := s.idToken(t); raw != "" {
+				token = token.WithExtra(map[string]any{"id_token": raw})
+			}
+
+			user, err := p.FetchAuthUser(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if user.Id != "test_id" || user.Name != "test_name" || user.AvatarURL != "https://example.com/avatar.png" {
+				t.Fatalf("Unexpected user %#v", user)
+			}
+
+			if user.Email != s.expectedEmail {
+				t.Fatalf("Expected email %q, got %q", s.expectedEmail, user.Email)
+			}
+		})
+	}
+}
+
+func TestLineFetchAuthUserNonce(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"userId": "test_id"})
+	}))
+	defer server.Close()
+
+	scenarios := []struct {
+		name              string
+		nonce             string
+		expectError       bool
+		expectedValidated bool
+	}{
+		{"missing nonce", "", true, false},
+		{"mismatched nonce", "other_nonce", true, false},
+		{"matching nonce", "test_nonce", false, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewLineProvider()
+			p.SetClientId("test_client")
+			p.SetClientSecret("test_secret")
+			p.SetUserInfoURL(server.URL)
+			p.SetNonce("test_nonce")
+
+			claims := jwt.MapClaims{
+				"iss": "https://access.line.me",
+				"sub": "test_id",
+				"aud": "test_client",
+				"iat": time.Now().Unix(),
+				"exp": time.Now().Add(1 * time.Hour).Unix(),
+			}
+			if s.nonce != "" {
+				claims["nonce"] = s.nonce
+			}
+
+			idToken, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test_secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			token := (&oauth2.Token{AccessToken: "test_access"}).WithExtra(map[string]any{"id_token": idToken})
+
+			_, err = p.FetchAuthUser(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if p.NonceValidated() != s.expectedValidated {
+				t.Fatalf("Expected nonce validated %v, got %v", s.expectedValidated, p.NonceValidated())
+			}
+		})
+	}
+}
@@ -27,13 +27,14 @@ type Microsoft struct {
 func NewMicrosoftProvider() *Microsoft {
 	endpoints := microsoft.AzureADEndpoint("")
 	return &Microsoft{BaseProvider{
-		ctx:         context.Background(),
-		displayName: "Microsoft",
-		pkce:        true,
-		scopes:      []string{"User.Read"},
-		authURL:     endpoints.AuthURL,
-		tokenURL:    endpoints.TokenURL,
-		userInfoURL: "https://graph.microsoft.com/v1.0/me",
+		ctx:           context.Background(),
+		displayName:   "Microsoft",
+		pkce:          true,
+		scopes:        []string{"User.Read"},
+		authURL:       endpoints.AuthURL,
+		tokenURL:      endpoints.TokenURL,
+		userInfoURL:   "https://graph.microsoft.com/v1.0/me",
+		deviceAuthURL: endpoints.DeviceAuthURL,
 	}}
 }
 
@@ -3,10 +3,18 @@ package auth
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
 
 	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
 	"golang.org/x/oauth2"
 )
 
@@ -14,12 +22,40 @@ func init() {
 	Providers[NameNextcloud] = wrapFactory(NewNextcloudProvider)
 }
 
-var _ Provider = (*Nextcloud)(nil)
+var (
+	_ Provider      = (*Nextcloud)(nil)
+	_ AvatarFetcher = (*Nextcloud)(nil)
+)
 
 // NameNextcloud is the unique name of the Nextcloud provider.
 const NameNextcloud string = "nextcloud"
 
+// the default placeholder Nextcloud endpoints that are expected to be replaced
+const (
+	nextcloudAuthURL     = "https://nextcloud.your.domain/apps/oauth2/authorize"
+	nextcloudTokenURL    = "https://nextcloud.your.domain/apps/oauth2/api/v1/token"
+	nextcloudUserInfoURL = "https://nextcloud.your.domain/ocs/v2.php/cloud/user?format=json"
+)
+
+var nextcloudScopes = []string{"read:user", "user:email"}
+
+// nextcloudAvatarSize is the requested Nextcloud avatar image size (in px).
+const nextcloudAvatarSize = 512
+
+// nextcloudMaxAvatarSize is the max allowed Nextcloud avatar image size (in bytes).
+const nextcloudMaxAvatarSize = 10 << 20
+
 // Nextcloud allows authentication via Nextcloud OAuth2.
+//
+// The provider support the following Extra config options:
+//   - "discoveryURL" - the Nextcloud OpenID Connect issuer or its "/.well-known/openid-configuration" url
+//     (optional and requires the Nextcloud "oidc" app)
+//
+// When "discoveryURL" is set, the not explicitly configured auth, token and user info urls
+// are discovered at runtime and the user data is fetched as with the generic OIDC provider
+// (aka. the id_token signature and issuer are validated against the published JWKS).
+//
+// The OCS user groups are assigned to [AuthUser.Groups].
 type Nextcloud struct {
 	BaseProvider
 }
@@ -30,17 +66,123 @@ func NewNextcloudProvider() *Nextcloud {
 		ctx:         context.Background(),
 		displayName: "Nextcloud",
 		pkce:        true,
-		scopes:      []string{"read:user", "user:email"},
-		authURL:     "https://nextcloud.your.domain/apps/oauth2/authorize",
-		tokenURL:    "https://nextcloud.your.domain/apps/oauth2/api/v1/token",
-		userInfoURL: "https://nextcloud.your.domain/ocs/v2.php/cloud/user?format=json",
+		scopes:      slices.Clone(nextcloudScopes),
+		authURL:     nextcloudAuthURL,
+		tokenURL:    nextcloudTokenURL,
+		userInfoURL: nextcloudUserInfoURL,
 	}}
 }
 
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+//
+// Note that if the discovery fails, the auth url is built
+// with the explicitly configured provider auth url.
+func (p *Nextcloud) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	if p.discoveryURL() != "" {
+		if oidc, err := p.oidc(); err == nil {
+			return oidc.BuildAuthURL(state, opts...)
+		}
+	}
+
+	return p.BaseProvider.BuildAuthURL(state, opts...)
+}
+
+// FetchToken implements Provider.FetchToken() interface method.
+func (p *Nextcloud) FetchToken(code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	if p.discoveryURL() != "" {
+		oidc, err := p.oidc()
+		if err != nil {
+			return nil, err
+		}
+
+		return oidc.FetchToken(code, opts...)
+	}
+
+	return p.BaseProvider.FetchToken(code, opts...)
+}
+
+// RefreshToken implements Provider.RefreshToken() interface method.
+func (p *Nextcloud) RefreshToken(refreshToken string) (*oauth2.Token, error) {
+	if p.discoveryURL() != "" {
+		oidc, err := p.oidc()
+		if err != nil {
+			return nil, err
+		}
+
+		return oidc.RefreshToken(refreshToken)
+	}
+
+	return p.BaseProvider.RefreshToken(refreshToken)
+}
+
+// discoveryURL returns the "discoveryURL" extra config option (if any).
+func (p *Nextcloud) discoveryURL() string {
+	return cast.ToString(p.Extra()["discoveryURL"])
+}
+
+// oidc returns a generic OIDC provider that shares the current provider configuration
+// and that resolves the not explicitly configured (aka. placeholder) urls
+// from the "discoveryURL" OpenID Provider Metadata.
+func (p *Nextcloud) oidc() (*OIDC, error) {
+	oidc := &OIDC{BaseProvider: p.BaseProvider}
+
+	// reset the placeholder urls so that they can be discovered
+	if oidc.authURL == nextcloudAuthURL {
+		oidc.authURL = ""
+	}
+	if oidc.tokenURL == nextcloudTokenURL {
+		oidc.tokenURL = ""
+	}
+	if oidc.userInfoURL == nextcloudUserInfoURL {
+		doc, err := fetchDiscoveryDocument(oidc.httpContext(), p.discoveryURL())
+		if err != nil {
+			return nil, err
+		}
+		oidc.userInfoURL = doc.UserInfoEndpoint
+	}
+
+	// the default scopes are not valid OIDC scopes
+	if slices.Equal(oidc.scopes, nextcloudScopes) {
+		oidc.scopes = []string{"openid", "email", "profile"}
+	}
+
+	if err := oidc.discover(); err != nil {
+		return nil, err
+	}
+
+	return oidc, nil
+}
+
+// discoveredOIDC returns the discovered generic OIDC provider
+// after validating the token id_token (if any).
+func (p *Nextcloud) discoveredOIDC(token *oauth2.Token) (*OIDC, error) {
+	oidc, err := p.oidc()
+	if err != nil {
+		return nil, err
+	}
+
+	if idToken, _ := token.Extra("id_token").(string); idToken != "" {
+		if _, err := oidc.parseIdToken(token); err != nil {
+			return nil, err
+		}
+	}
+
+	return oidc, nil
+}
+
 // FetchAuthUser returns an AuthUser instance based on Nextcloud's user api.
 //
 // API reference: https://nextcloud.com/api/v1/user
 func (p *Nextcloud) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	if p.discoveryURL() != "" {
+		oidc, err := p.discoveredOIDC(token)
+		if err != nil {
+			return nil, err
+		}
+
+		return oidc.FetchAuthUser(token)
+	}
+
 	slog.Debug("Nextcloud user data fetched", "data", token)
 	data, err := p.FetchRawUserInfo(token)
 	if err != nil {
@@ -56,9 +198,10 @@ func (p *Nextcloud) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 	var resp struct {
 		OCS struct {
 			Data struct {
-				ID          string `json:"id"`
-				DisplayName string `json:"displayname"`
-				Email       string `json:"email"`
+				ID          string   `json:"id"`
+				DisplayName string   `json:"displayname"`
+				Email       string   `json:"email"`
+				Groups      []string `json:"groups"`
 				// Add more fields if needed
 			} `json:"data"`
 		} `json:"ocs"`
@@ -77,6 +220,7 @@ func (p *Nextcloud) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 		RawUser:      rawUser,
 		AccessToken:  token.AccessToken,
 		RefreshToken: token.RefreshToken,
+		Groups:       resp.OCS.Data.Groups,
 	}
 
 	user.Expiry, _ = types.ParseDateTime(token.Expiry)
@@ -87,7 +231,19 @@ func (p *Nextcloud) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 // FetchRawUserInfo implements Provider.FetchRawUserInfo interface method.
 //
 // It either fetch the data from p.userInfoURL, or if not set - returns the id_token claims.
+//
+// Note that if "discoveryURL" is set and the token has an id_token,
+// the id_token is always parsed and validated.
 func (p *Nextcloud) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
+	if p.discoveryURL() != "" {
+		oidc, err := p.discoveredOIDC(token)
+		if err != nil {
+			return nil, err
+		}
+
+		return oidc.FetchRawUserInfo(token)
+	}
+
 	if p.userInfoURL != "" {
 		return p.BaseProvider.FetchRawUserInfo(token)
 	}
@@ -102,3 +258,83 @@ func (p *Nextcloud) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
 
 	return p.sendRawUserInfoRequest(req, token)
 }
+
+// FetchAvatar implements [AvatarFetcher.FetchAvatar] interface method.
+//
+// It downloads the user avatar from the Nextcloud "/index.php/avatar/{user}/{size}"
+// endpoint (the Nextcloud OCS user api doesn't return an avatar url).
+func (p *Nextcloud) FetchAvatar(token *oauth2.Token) ([]byte, error) {
+	user, err := p.FetchAuthUser(token)
+	if err != nil {
+		return nil, err
+	}
+
+	if user.Id == "" {
+		return nil, errors.New("missing Nextcloud user id")
+	}
+
+	baseURL, err := p.baseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	avatarURL := baseURL + "/index.php/avatar/" + url.PathEscape(user.Id) + "/" + strconv.Itoa(nextcloudAvatarSize)
+
+	req, err := http.NewRequestWithContext(p.ctx, "GET", avatarURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("OCS-APIRequest", "true")
+
+	res, err := p.Client(token).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return nil, fmt.Errorf("failed to fetch Nextcloud avatar via %s (%d)", avatarURL, res.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(res.Body, nextcloudMaxAvatarSize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(data) > nextcloudMaxAvatarSize {
+		return nil, errors.New("the Nextcloud avatar exceeds the max allowed size")
+	}
+
+	return data, nil
+}
+
+// baseURL returns the Nextcloud instance base url extracted from the provider auth url.
+func (p *Nextcloud) baseURL() (string, error) {
+	authURL := p.authURL
+	if p.discoveryURL() != "" {
+		oidc, err := p.oidc()
+		if err != nil {
+			return "", err
+		}
+		authURL = oidc.authURL
+	}
+
+	u, err := url.Parse(authURL)
+	if err != nil {
+		return "", err
+	}
+
+	for _, marker := range []string{"/index.php/", "/apps/", "/ocs/"} {
+		if i := strings.Index(u.Path, marker); i >= 0 {
+			u.Path = u.Path[:i]
+			break
+		}
+	}
+
+	u.RawPath = ""
+	u.RawQuery = ""
+	u.Fragment = ""
+
+	return strings.TrimSuffix(u.String(), "/"), nil
+}
@@ -0,0 +1,232 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+func TestNextcloudDiscover(t *testing.T) {
+	server := newTestOIDCServer(t)
+
+	t.Run("without discoveryURL", func(t *testing.T) {
+		p := NewNextcloudProvider()
+
+		if !strings.HasPrefix(p.BuildAuthURL("test_state"), nextcloudAuthURL) {
+			t.Fatalf("Expected the default auth url to be used, got %q", p.AuthURL())
+		}
+
+		if p.TokenURL() != nextcloudTokenURL {
+			t.Fatalf("Expected the default token url, got %q", p.TokenURL())
+		}
+	})
+
+	t.Run("with discoveryURL", func(t *testing.T) {
+		p := NewNextcloudProvider()
+		p.SetExtra(map[string]any{"discoveryURL": server.URL})
+
+		authURL := p.BuildAuthURL("test_state")
+		if !strings.HasPrefix(authURL, server.URL+"/auth?") {
+			t.Fatalf("Expected the discovered auth url, got %q", authURL)
+		}
+
+		if !strings.Contains(authURL, "scope=openid+email+profile") {
+			t.Fatalf("Expected the default OIDC scopes, got %q", authURL)
+		}
+
+		// the provider itself shouldn't be changed
+		if p.TokenURL() != nextcloudTokenURL {
+			t.Fatalf("Expected the provider token url to remain unchanged, got %q", p.TokenURL())
+		}
+
+		oidc, err := p.oidc()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if oidc.TokenURL() != server.URL+"/token" {
+			t.Fatalf("Expected the discovered token url, got %q", oidc.TokenURL())
+		}
+
+		if oidc.UserInfoURL() != server.URL+"/userinfo" {
+			t.Fatalf("Expected the discovered user info url, got %q", oidc.UserInfoURL())
+		}
+	})
+
+	t.Run("with discoveryURL and explicit urls", func(t *testing.T) {
+		p := NewNextcloudProvider()
+		p.SetExtra(map[string]any{"discoveryURL": server.URL})
+		p.SetTokenURL("https://example.com/token")
+		p.SetScopes([]string{"openid"})
+
+		oidc, err := p.oidc()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if oidc.AuthURL() != server.URL+"/auth" {
+			t.Fatalf("Expected the discovered auth url, got %q", oidc.AuthURL())
+		}
+
+		if oidc.TokenURL() != "https://example.com/token" {
+			t.Fatalf("Expected the explicit token url, got %q", oidc.TokenURL())
+		}
+
+		if scopes := strings.Join(oidc.Scopes(), " "); scopes != "openid" {
+			t.Fatalf("Expected the explicit scopes, got %q", scopes)
+		}
+	})
+}
+
+func TestNextcloudFetchAuthUserWithDiscovery(t *testing.T) {
+	server := newTestOIDCServer(t)
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub": "test_sub",
+			"iss": server.URL,
+			"aud": "test_client",
+			"iat": time.Now().Unix(),
+			"exp": time.Now().Add(1 * time.Hour).Unix(),
+		}
+	}
+
+	scenarios := []struct {
+		name        string
+		idToken     func() string
+		expectError bool
+	}{
+		{
+			"without id_token",
+			func() string { return "" },
+			false,
+		},
+		{
+			"valid id_token",
+			func() string { return server.idToken(t, server.key, "test_kid", validClaims()) },
+			false,
+		},
+		{
+			"id_token with invalid iss",
+			func() string {
+				claims := validClaims()
+				claims["iss"] = "https://example.com"
+				return server.idToken(t, server.key, "test_kid", claims)
+			},
+			true,
+		},
+		{
+			"id_token with unknown kid",
+			func() string { return server.idToken(t, server.key, "missing_kid", validClaims()) },
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewNextcloudProvider()
+			p.SetClientId("test_client")
+			p.SetExtra(map[string]any{"discoveryURL": server.URL})
+
+			token := &oauth2.Token{AccessToken: "test"}
+			if idToken := s.idToken(); idToken != "" {
+				token = token.WithExtra(map[string]any{"id_token": idToken})
+			}
+
+			user, err := p.FetchAuthUser(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if user.Id != "test_sub" || user.Name != "test_name" || user.Username != "test_username" || user.Email != "test@example.com" {
+				t.Fatalf("Unexpected auth user %#v", user)
+			}
+
+			if strings.Join(user.Groups, ",") != "group1,group2" {
+				t.Fatalf("Expected groups %v, got %v", []string{"group1", "group2"}, user.Groups)
+			}
+		})
+	}
+}
+
+func TestNextcloudFetchAuthUser(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ocs":{"data":{"id":"test_id","displayname":"test_name","email":"test@example.com","groups":["admin","editors"]}}}`))
+	}))
+	defer server.Close()
+
+	p := NewNextcloudProvider()
+	p.SetUserInfoURL(server.URL)
+
+	user, err := p.FetchAuthUser(&oauth2.Token{AccessToken: "test"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Id != "test_id" || user.Name != "test_name" || user.Username != "test_id" || user.Email != "test@example.com" {
+		t.Fatalf("Unexpected auth user %#v", user)
+	}
+
+	if strings.Join(user.Groups, ",") != "admin,editors" {
+		t.Fatalf("Expected groups %v, got %v", []string{"admin", "editors"}, user.Groups)
+	}
+
+	if v, _ := user.RawClaim("ocs.data.groups.1"); v != "editors" {
+		t.Fatalf("Expected raw groups claim, got %v", v)
+	}
+}
+
+func TestNextcloudFetchAvatar(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer test" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/nc/ocs/v2.php/cloud/user":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"ocs":{"data":{"id":"test id","displayname":"test_name"}}}`))
+		case "/nc/index.php/avatar/test id/512":
+			w.Header().Set("Content-Type", "image/png")
+			w.Write([]byte("test_avatar"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	p := NewNextcloudProvider()
+	p.SetAuthURL(server.URL + "/nc/apps/oauth2/authorize")
+	p.SetUserInfoURL(server.URL + "/nc/ocs/v2.php/cloud/user?format=json")
+
+	t.Run("invalid token", func(t *testing.T) {
+		_, err := p.FetchAvatar(&oauth2.Token{AccessToken: "invalid"})
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("valid token", func(t *testing.T) {
+		data, err := p.FetchAvatar(&oauth2.Token{AccessToken: "test"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if string(data) != "test_avatar" {
+			t.Fatalf("Expected avatar data %q, got %q", "test_avatar", data)
+		}
+	})
+}
@@ -0,0 +1,42 @@
+package auth
+
+import (
+	"errors"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/pocketbase/pocketbase/tools/security"
+)
+
+// idTokenNonce is a helper embeddable struct implementing the [NonceValidator] interface.
+type idTokenNonce struct {
+	nonce     string
+	validated bool
+}
+
+// SetNonce implements [NonceValidator.SetNonce] interface method.
+func (n *idTokenNonce) SetNonce(nonce string) {
+	n.nonce = nonce
+	n.validated = false
+}
+
+// NonceValidated implements [NonceValidator.NonceValidated] interface method.
+func (n *idTokenNonce) NonceValidated() bool {
+	return n.validated
+}
+
+// validateNonce checks whether the id_token claims "nonce"
+// matches with the expected one (if any).
+func (n *idTokenNonce) validateNonce(claims jwt.MapClaims) error {
+	if n.nonce == "" {
+		return nil
+	}
+
+	nonce, _ := claims["nonce"].(string)
+	if nonce == "" || !security.Equal(nonce, n.nonce) {
+		return errors.New("invalid or missing id_token nonce")
+	}
+
+	n.validated = true
+
+	return nil
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func TestIdTokenNonceValidate(t *testing.T) {
+	scenarios := []struct {
+		name              string
+		nonce             string
+		claims            jwt.MapClaims
+		expectError       bool
+		expectedValidated bool
+	}{
+		{
+			"no expected nonce",
+			"",
+			jwt.MapClaims{"nonce": "test"},
+			false,
+			false,
+		},
+		{
+			"missing claim",
+			"test",
+			jwt.MapClaims{},
+			true,
+			false,
+		},
+		{
+			"non-string claim",
+			"test",
+			jwt.MapClaims{"nonce": 123},
+			true,
+			false,
+		},
+		{
+			"mismatched claim",
+			"test",
+			jwt.MapClaims{"nonce": "other"},
+			true,
+			false,
+		},
+		{
+			"matching claim",
+			"test",
+			jwt.MapClaims{"nonce": "test"},
+			false,
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			n := &idTokenNonce{}
+			n.SetNonce(s.nonce)
+
+			err := n.validateNonce(s.claims)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if n.NonceValidated() != s.expectedValidated {
+				t.Fatalf("Expected validated %v, got %v", s.expectedValidated, n.NonceValidated())
+			}
+		})
+	}
+}
+
+func TestIdTokenNonceReset(t *testing.T) {
+	n := &idTokenNonce{}
+	n.SetNonce("test")
+
+	if err := n.validateNonce(jwt.MapClaims{"nonce": "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	if !n.NonceValidated() {
+		t.Fatal("Expected the nonce to be validated")
+	}
+
+	n.SetNonce("other")
+
+	if n.NonceValidated() {
+		t.Fatal("Expected the validated state to be reset after SetNonce")
+	}
+}
@@ -7,9 +7,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
 	"math/big"
-	"net/http"
 	"os"
 	"strconv"
 	"strings"
@@ -42,6 +40,7 @@ func init() {
 }
 
 var _ Provider = (*OIDC)(nil)
+var _ NonceValidator = (*OIDC)(nil)
 
 // NameOIDC is the unique name of the OpenID Connect (OIDC) provider.
 const NameOIDC string = "oidc"
@@ -52,15 +51,24 @@ const NameOIDC string = "oidc"
 // Otherwise - from the id_token payload.
 //
 // The provider support the following Extra config options:
+//   - "discoveryURL" - the issuer or its "/.well-known/openid-configuration" url (optional)
 //   - "jwksURL" - url to the keys to validate the id_token signature (optional and used only when reading the user data from the id_token)
 //   - "issuers" - list of valid issuers for the iss id_token claim (optioanl and used only when reading the user data from the id_token)
+//
+// When "discoveryURL" is set, the OpenID Provider Metadata is fetched (and cached)
+// at runtime and it is used as fallback for the not explicitly configured
+// auth and token urls, "jwksURL" and "issuers" options (aka. the id_token
+// signature and issuer are always validated).
+//
+// The non-standard "groups" user claim (if present) is assigned to [AuthUser.Groups].
 type OIDC struct {
 	BaseProvider
+	idTokenNonce
 }
 
 // NewOIDCProvider creates new OpenID Connect (OIDC) provider instance with some defaults.
 func NewOIDCProvider() *OIDC {
-	return &OIDC{BaseProvider{
+	return &OIDC{BaseProvider: BaseProvider{
 		ctx:         context.Background(),
 		displayName: "OIDC",
 		pkce:        true,
@@ -72,6 +80,85 @@ func NewOIDCProvider() *OIDC {
 	}}
 }
 
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+//
+// Note that if the discovery fails, the auth url is built
+// with the explicitly configured provider auth url.
+func (p *OIDC) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	_ = p.discover()
+
+	return p.BaseProvider.BuildAuthURL(state, opts...)
+}
+
+// FetchToken implements Provider.FetchToken() interface method.
+func (p *OIDC) FetchToken(code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	if err := p.discover(); err != nil {
+		return nil, err
+	}
+
+	return p.BaseProvider.FetchToken(code, opts...)
+}
+
+// RefreshToken implements Provider.RefreshToken() interface method.
+func (p *OIDC) RefreshToken(refreshToken string) (*oauth2.Token, error) {
+	if err := p.discover(); err != nil {
+		return nil, err
+	}
+
+	return p.BaseProvider.RefreshToken(refreshToken)
+}
+
+// discover loads the OIDC discovery document (if "discoveryURL" extra config is set)
+// and applies its values for the not explicitly configured provider options.
+func (p *OIDC) discover() error {
+	discoveryURL := cast.ToString(p.Extra()["discoveryURL"])
+	if discoveryURL == "" {
+		return nil
+	}
+
+	doc, err := fetchDiscoveryDocument(p.httpContext(), discoveryURL)
+	if err != nil {
+		return err
+	}
+
+	if p.authURL == "" {
+		p.authURL = doc.AuthorizationEndpoint
+	}
+
+	if p.tokenURL == "" {
+		p.tokenURL = doc.TokenEndpoint
+	}
+
+	return nil
+}
+
+// resolveJWKSURLAndIssuers returns the explicitly configured "jwksURL" and "issuers"
+// extra options or their discovery document fallbacks (if "discoveryURL" is set).
+func (p *OIDC) resolveJWKSURLAndIssuers() (string, []string, error) {
+	jwksURL := cast.ToString(p.Extra()["jwksURL"])
+	issuers := cast.ToStringSlice(p.Extra()["issuers"])
+
+	discoveryURL := cast.ToString(p.Extra()["discoveryURL"])
+	if discoveryURL == "" || (jwksURL != "" && len(issuers) > 0) {
+		return jwksURL, issuers, nil
+	}
+
+	doc, err := fetchDiscoveryDocument(p.httpContext(), discoveryURL)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if jwksURL == "" {
+		jwksURL = doc.JWKSURI
+	}
+
+	if len(issuers) == 0 {
+		issuers = []string{doc.Issuer}
+	}
+
+	return jwksURL, issuers, nil
+}
+
 // FetchAuthUser returns an AuthUser instance based the provider's user api.
 //
 // API reference: https://openid.net/specs/openid-connect-core-1_0.html#StandardClaims
@@ -93,6 +180,7 @@ func (p *OIDC) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 		Picture       string `json:"picture"`
 		Email         string `json:"email"`
 		EmailVerified any    `json:"email_verified"` // see #6657
+		Groups        any    `json:"groups"`
 	}{}
 	if err := json.Unmarshal(data, &extracted); err != nil {
 		return nil, err
@@ -110,6 +198,11 @@ func (p *OIDC) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 
 	user.Expiry, _ = types.ParseDateTime(token.Expiry)
 
+	// the groups claim is not standard but it is commonly used by the IdPs
+	if extracted.Groups != nil {
+		user.Groups = cast.ToStringSlice(extracted.Groups)
+	}
+
 	if cast.ToBool(extracted.EmailVerified) {
 		user.Email = extracted.Email
 	}
@@ -120,8 +213,17 @@ func (p *OIDC) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 // FetchRawUserInfo implements Provider.FetchRawUserInfo interface method.
 //
 // It either fetch the data from p.userInfoURL, or if not set - returns the id_token claims.
+//
+// Note that if a nonce is set and the token has an id_token, the id_token
+// is always parsed and validated (even when p.userInfoURL is set).
 func (p *OIDC) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
 	if p.userInfoURL != "" {
+		if idToken, _ := token.Extra("id_token").(string); idToken != "" && p.nonce != "" {
+			if _, err := p.parseIdToken(token); err != nil {
+				return nil, err
+			}
+		}
+
 		return p.BaseProvider.FetchRawUserInfo(token)
 	}
 
@@ -134,7 +236,7 @@ func (p *OIDC) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
 }
 
 func (p *OIDC) parseIdToken(token *oauth2.Token) (jwt.MapClaims, error) {
-	idToken := token.Extra("id_token").(string)
+	idToken, _ := token.Extra("id_token").(string)
 	if idToken == "" {
 		return nil, errors.New("empty id_token")
 	}
@@ -156,8 +258,17 @@ func (p *OIDC) parseIdToken(token *oauth2.Token) (jwt.MapClaims, error) {
 		return nil, err
 	}
 
-	// validate iss (if "issuers" extra config is set)
-	issuers := cast.ToStringSlice(p.Extra()["issuers"])
+	err = p.validateNonce(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	jwksURL, issuers, err := p.resolveJWKSURLAndIssuers()
+	if err != nil {
+		return nil, err
+	}
+
+	// validate iss (if "issuers" extra config is set or discovered)
 	if len(issuers) > 0 {
 		var isIssValid bool
 		claimIssuer, _ := claims.GetIssuer()
@@ -174,15 +285,14 @@ func (p *OIDC) parseIdToken(token *oauth2.Token) (jwt.MapClaims, error) {
 		}
 	}
 
-	// validate signature (if "jwksURL" extra config is set)
+	// validate signature (if "jwksURL" extra config is set or discovered)
 	//
 	// note: this step could be technically considered optional because we trust
 	// the token which is a result of direct TLS communication with the provider
 	// (see also https://openid.net/specs/openid-connect-core-1_0.html#IDTokenValidation)
-	jwksURL := cast.ToString(p.Extra()["jwksURL"])
 	if jwksURL != "" {
 		kid, _ := t.Header["kid"].(string)
-		err = validateIdTokenSignature(p.ctx, idToken, jwksURL, kid)
+		err = validateIdTokenSignature(p.httpContext(), idToken, jwksURL, kid)
 		if err != nil {
 			return nil, err
 		}
@@ -250,43 +360,34 @@ type jwk struct {
 }
 
 func fetchJWK(ctx context.Context, jwksURL string, kid string) (*jwk, error) {
-	req, err := http.NewRequestWithContext(ctx, "GET", jwksURL, nil)
+	keys, err := fetchJWKS(ctx, jwksURL, false)
 	if err != nil {
 		return nil, err
 	}
 
-	res, err := http.DefaultClient.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	rawBody, err := io.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
+	key := findJWK(keys, kid)
+	if key == nil {
+		// the keys could have been rotated - try to refetch them
+		keys, err = fetchJWKS(ctx, jwksURL, true)
+		if err != nil {
+			return nil, err
+		}
+		key = findJWK(keys, kid)
 	}
 
-	// http.Client.Get doesn't treat non 2xx responses as error
-	if res.StatusCode >= 400 {
-		return nil, fmt.Errorf(
-			"failed to verify the provided id_token (%d):\n%s",
-			res.StatusCode,
-			string(rawBody),
-		)
+	if key == nil {
+		return nil, fmt.Errorf("jwk with kid %q was not found", kid)
 	}
 
-	jwks := struct {
-		Keys []*jwk
-	}{}
-	if err := json.Unmarshal(rawBody, &jwks); err != nil {
-		return nil, err
-	}
+	return key, nil
+}
 
-	for _, key := range jwks.Keys {
+func findJWK(keys []*jwk, kid string) *jwk {
+	for _, key := range keys {
 		if key.Kid == kid {
-			return key, nil
+			return key
 		}
 	}
 
-	return nil, fmt.Errorf("jwk with kid %q was not found", kid)
+	return nil
 }
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const oidcDiscoveryPath = "/.well-known/openid-configuration"
+
+// discoveryCacheTTL is the max duration for which
+// a fetched OIDC discovery document is reused.
+var discoveryCacheTTL = 1 * time.Hour
+
+// jwksCacheTTL is the max duration for which a fetched JWKS is reused.
+var jwksCacheTTL = 1 * time.Hour
+
+// jwksMinRefreshInterval is the min duration between two JWKS fetches
+// of the same url triggered by an unknown kid (aka. keys rotation).
+var jwksMinRefreshInterval = 1 * time.Minute
+
+// oidcDiscoveryDocument defines the used fields from the
+// OpenID Provider Metadata (https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata).
+type oidcDiscoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserInfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+
+	DeviceAuthorizationEndpoint string `json:"device_authorization_endpoint"`
+}
+
+type cachedDiscoveryDocument struct {
+	doc       *oidcDiscoveryDocument
+	fetchedAt time.Time
+}
+
+type cachedJWKS struct {
+	keys      []*jwk
+	fetchedAt time.Time
+}
+
+var (
+	discoveryCacheMu sync.Mutex
+	discoveryCache   = map[string]cachedDiscoveryDocument{}
+
+	jwksCacheMu sync.Mutex
+	jwksCache   = map[string]cachedJWKS{}
+)
+
+// normalizeDiscoveryURL returns the full discovery document url
+// and the expected issuer identifier of the provided
+// issuer or "/.well-known/openid-configuration" url.
+func normalizeDiscoveryURL(rawURL string) (discoveryURL string, issuer string) {
+	rawURL = strings.TrimSpace(rawURL)
+
+	issuer = strings.TrimSuffix(strings.TrimSuffix(rawURL, "/"), oidcDiscoveryPath)
+
+	return issuer + oidcDiscoveryPath, issuer
+}
+
+// fetchDiscoveryDocument fetches (or returns the cached) OIDC discovery document
+// of the provided issuer or "/.well-known/openid-configuration" url.
+//
+// Per the OIDC discovery spec, the document issuer
+// must be identical to the one used for the discovery.
+func fetchDiscoveryDocument(ctx context.Context, rawURL string) (*oidcDiscoveryDocument, error) {
+	discoveryURL, expectedIssuer := normalizeDiscoveryURL(rawURL)
+
+	discoveryCacheMu.Lock()
+	cached, ok := discoveryCache[discoveryURL]
+	discoveryCacheMu.Unlock()
+	if ok && time.Since(cached.fetchedAt) < discoveryCacheTTL {
+		return cached.doc, nil
+	}
+
+	rawBody, err := sendGetRequest(ctx, discoveryURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch the OIDC discovery document: %w", err)
+	}
+
+	doc := &oidcDiscoveryDocument{}
+	if err := json.Unmarshal(rawBody, doc); err != nil {
+		return nil, fmt.Errorf("failed to parse the OIDC discovery document: %w", err)
+	}
+
+	if strings.TrimSuffix(doc.Issuer, "/") != expectedIssuer {
+		return nil, fmt.Errorf("the OIDC discovery document issuer %q doesn't match the expected %q", doc.Issuer, expectedIssuer)
+	}
+
+	if doc.AuthorizationEndpoint == "" || doc.TokenEndpoint == "" || doc.JWKSURI == "" {
+		return nil, errors.New("the OIDC discovery document is missing required endpoints")
+	}
+
+	discoveryCacheMu.Lock()
+	discoveryCache[discoveryURL] = cachedDiscoveryDocument{doc: doc, fetchedAt: time.Now()}
+	discoveryCacheMu.Unlock()
+
+	return doc, nil
+}
+
+// fetchJWKS fetches (or returns the cached) public key set of the provided url.
+//
+// If forceRefresh is set, the cached keys are refetched
+// unless they were fetched less than jwksMinRefreshInterval ago.
+func fetchJWKS(ctx context.Context, jwksURL string, forceRefresh bool) ([]*jwk, error) {
+	jwksCacheMu.Lock()
+	cached, ok := jwksCache[jwksURL]
+	jwksCacheMu.Unlock()
+
+	if ok {
+		age := time.Since(cached.fetchedAt)
+		if age < jwksMinRefreshInterval || (!forceRefresh && age < jwksCacheTTL) {
+			return cached.keys, nil
+		}
+	}
+
+	rawBody, err := sendGetRequest(ctx, jwksURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to verify the provided id_token: %w", err)
+	}
+
+	jwks := struct {
+		Keys []*jwk
+	}{}
+	if err := json.Unmarshal(rawBody, &jwks); err != nil {
+		return nil, err
+	}
+
+	jwksCacheMu.Lock()
+	jwksCache[jwksURL] = cachedJWKS{keys: jwks.Keys, fetchedAt: time.Now()}
+	jwksCacheMu.Unlock()
+
+	return jwks.Keys, nil
+}
+
+func sendGetRequest(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := contextHTTPClient(ctx).Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	rawBody, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	// http.Client.Get doesn't treat non 2xx responses as error
+	if res.StatusCode >= 400 {
+		return nil, fmt.Errorf("%s (%d):\n%s", url, res.StatusCode, string(rawBody))
+	}
+
+	return rawBody, nil
+}
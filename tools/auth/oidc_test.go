@@ -0,0 +1,357 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"golang.org/x/oauth2"
+)
+
+type testOIDCServer struct {
+	*httptest.Server
+	key           *rsa.PrivateKey
+	issuer        string
+	discoveryHits atomic.Int32
+	jwksHits      atomic.Int32
+}
+
+func newTestOIDCServer(t *testing.T) *testOIDCServer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &testOIDCServer{key: key}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		s.discoveryHits.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"issuer":                 s.issuer,
+			"authorization_endpoint": s.URL + "/auth",
+			"token_endpoint":         s.URL + "/token",
+			"userinfo_endpoint":      s.URL + "/userinfo",
+			"jwks_uri":               s.URL + "/jwks",
+
+			"device_authorization_endpoint": s.URL + "/device",
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"sub":                "test_sub",
+			"name":               "test_name",
+			"preferred_username": "test_username",
+			"email":              "test@example.com",
+			"email_verified":     true,
+			"groups":             []string{"group1", "group2"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		s.jwksHits.Add(1)
+		json.NewEncoder(w).Encode(map[string]any{
+			"keys": []map[string]any{{
+				"kty": "RSA",
+				"kid": "test_kid",
+				"use": "sig",
+				"alg": "RS256",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	})
+
+	s.Server = httptest.NewServer(mux)
+	s.issuer = s.URL
+
+	t.Cleanup(s.Close)
+
+	return s
+}
+
+func (s *testOIDCServer) idToken(t *testing.T, key *rsa.PrivateKey, kid string, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = kid
+
+	signed, err := token.SignedString(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return signed
+}
+
+func newTestOIDCProvider(discoveryURL string) *OIDC {
+	p := NewOIDCProvider()
+	p.SetClientId("test_client")
+	p.SetExtra(map[string]any{"discoveryURL": discoveryURL})
+	return p
+}
+
+func TestOIDCDiscoverEndpoints(t *testing.T) {
+	server := newTestOIDCServer(t)
+
+	scenarios := []struct {
+		name         string
+		discoveryURL string
+	}{
+		{"issuer url", server.URL},
+		{"issuer url with trailing slash", server.URL + "/"},
+		{"full discovery url", server.URL + "/.well-known/openid-configuration"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := newTestOIDCProvider(s.discoveryURL)
+
+			authURL := p.BuildAuthURL("test_state")
+			if !strings.HasPrefix(authURL, server.URL+"/auth?") {
+				t.Fatalf("Expected the discovered auth url, got %q", authURL)
+			}
+
+			if p.TokenURL() != server.URL+"/token" {
+				t.Fatalf("Expected the discovered token url, got %q", p.TokenURL())
+			}
+		})
+	}
+
+	t.Run("explicit urls are not overwritten", func(t *testing.T) {
+		p := newTestOIDCProvider(server.URL)
+		p.SetAuthURL("https://example.com/auth")
+		p.SetTokenURL("https://example.com/token")
+
+		if err := p.discover(); err != nil {
+			t.Fatal(err)
+		}
+
+		if p.AuthURL() != "https://example.com/auth" {
+			t.Fatalf("Expected the explicit auth url, got %q", p.AuthURL())
+		}
+
+		if p.TokenURL() != "https://example.com/token" {
+			t.Fatalf("Expected the explicit token url, got %q", p.TokenURL())
+		}
+	})
+
+	// the discovery document should be cached
+	if hits := server.discoveryHits.Load(); hits != 1 {
+		t.Fatalf("Expected 1 discovery request, got %d", hits)
+	}
+}
+
+func TestOIDCDiscoverIssuerMismatch(t *testing.T) {
+	server := newTestOIDCServer(t)
+	server.issuer = "https://example.com"
+
+	p := newTestOIDCProvider(server.URL)
+
+	if _, err := p.FetchToken("test_code"); err == nil || !strings.Contains(err.Error(), "issuer") {
+		t.Fatalf("Expected issuer mismatch error, got %v", err)
+	}
+}
+
+func TestOIDCFetchRawUserInfoWithDiscovery(t *testing.T) {
+	server := newTestOIDCServer(t)
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	validClaims := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"sub": "test_sub",
+			"iss": server.URL,
+			"aud": "test_client",
+			"iat": time.Now().Unix(),
+			"exp": time.Now().Add(1 * time.Hour).Unix(),
+		}
+	}
+
+	scenarios := []struct {
+		name        string
+		key         *rsa.PrivateKey
+		kid         string
+		claims      func() jwt.MapClaims
+		expectError bool
+	}{
+		{
+			"valid id_token",
+			server.key,
+			"test_kid",
+			validClaims,
+			false,
+		},
+		{
+			"invalid iss",
+			server.key,
+			"test_kid",
+			func() jwt.MapClaims {
+				claims := validClaims()
+				claims["iss"] = "https://example.com"
+				return claims
+			},
+			true,
+		},
+		{
+			"invalid aud",
+			server.key,
+			"test_kid",
+			func() jwt.MapClaims {
+				claims := validClaims()
+				claims["aud"] = "other_client"
+				return claims
+			},
+			true,
+		},
+		{
+			"unknown kid",
+			server.key,
+			"missing_kid",
+			validClaims,
+			true,
+		},
+		{
+			"invalid signature",
+			otherKey,
+			"test_kid",
+			validClaims,
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := newTestOIDCProvider(server.URL)
+
+			token := (&oauth2.Token{}).WithExtra(map[string]any{
+				"id_token": server.idToken(t, s.key, s.kid, s.claims()),
+			})
+
+			raw, err := p.FetchRawUserInfo(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if !hasErr && !strings.Contains(string(raw), `"sub":"test_sub"`) {
+				t.Fatalf("Expected the id_token claims, got %s", raw)
+			}
+		})
+	}
+}
+
+func TestFetchJWKSCache(t *testing.T) {
+	server := newTestOIDCServer(t)
+
+	jwksURL := server.URL + "/jwks"
+
+	for i := 0; i < 3; i++ {
+		if _, err := fetchJWK(context.Background(), jwksURL, "test_kid"); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if hits := server.jwksHits.Load(); hits != 1 {
+		t.Fatalf("Expected 1 jwks request, got %d", hits)
+	}
+
+	// unknown kid within the min refresh interval
+	if _, err := fetchJWK(context.Background(), jwksURL, "missing_kid"); err == nil {
+		t.Fatal("Expected missing kid error")
+	}
+
+	if hits := server.jwksHits.Load(); hits != 1 {
+		t.Fatalf("Expected still 1 jwks request, got %d", hits)
+	}
+
+	// unknown kid after the min refresh interval
+	oldInterval := jwksMinRefreshInterval
+	jwksMinRefreshInterval = 0
+	defer func() {
+		jwksMinRefreshInterval = oldInterval
+	}()
+
+	if _, err := fetchJWK(context.Background(), jwksURL, "missing_kid"); err == nil {
+		t.Fatal("Expected missing kid error")
+	}
+
+	if hits := server.jwksHits.Load(); hits != 2 {
+		t.Fatalf("Expected 2 jwks requests, got %d", hits)
+	}
+}
+
+func TestOIDCNonceValidation(t *testing.T) {
+	server := newTestOIDCServer(t)
+
+	userInfoServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{"sub": "test_sub"})
+	}))
+	defer userInfoServer.Close()
+
+	claims := func(nonce string) jwt.MapClaims {
+		c := jwt.MapClaims{
+			"sub": "test_sub",
+			"iss": server.URL,
+			"aud": "test_client",
+			"iat": time.Now().Unix(),
+			"exp": time.Now().Add(1 * time.Hour).Unix(),
+		}
+		if nonce != "" {
+			c["nonce"] = nonce
+		}
+		return c
+	}
+
+	scenarios := []struct {
+		name              string
+		expectedNonce     string
+		idTokenNonce      string
+		userInfoURL       string
+		expectError       bool
+		expectedValidated bool
+	}{
+		{"no expected nonce", "", "test_nonce", "", false, false},
+		{"missing id_token nonce", "test_nonce", "", "", true, false},
+		{"mismatched id_token nonce", "test_nonce", "other_nonce", "", true, false},
+		{"matching id_token nonce", "test_nonce", "test_nonce", "", false, true},
+		{"mismatched id_token nonce with user info url", "test_nonce", "other_nonce", userInfoServer.URL, true, false},
+		{"matching id_token nonce with user info url", "test_nonce", "test_nonce", userInfoServer.URL, false, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := newTestOIDCProvider(server.URL)
+			p.SetNonce(s.expectedNonce)
+			if s.userInfoURL != "" {
+				p.SetUserInfoURL(s.userInfoURL)
+			}
+
+			token := (&oauth2.Token{AccessToken: "test"}).WithExtra(map[string]any{
+				"id_token": server.idToken(t, server.key, "test_kid", claims(s.idTokenNonce)),
+			})
+
+			_, err := p.FetchRawUserInfo(token)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if p.NonceValidated() != s.expectedValidated {
+				t.Fatalf("Expected nonce validated %v, got %v", s.expectedValidated, p.NonceValidated())
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/oauth2"
+)
+
+func init() {
+	Providers[NameQQ] = wrapFactory(NewQQProvider)
+}
+
+var _ Provider = (*QQ)(nil)
+
+// NameQQ is the unique name of the QQ provider.
+const NameQQ string = "qq"
+
+// QQ allows authentication via QQ Connect OAuth2.
+//
+// QQ doesn't return the user identifier with the token and the user info api
+// requires it as query parameter, so the token exchange additionally fetches
+// the user "openid" (and "unionid" if available) and stores them as token extra fields.
+//
+// The AuthUser.Id is the user "unionid" if available, otherwise it fallbacks to the "openid".
+type QQ struct {
+	BaseProvider
+}
+
+// NewQQProvider creates new QQ provider instance with some defaults.
+//
+// Docs: https://wiki.connect.qq.com/
+func NewQQProvider() *QQ {
+	return &QQ{BaseProvider{
+		ctx:         context.Background(),
+		displayName: "QQ",
+		pkce:        false,
+		scopes:      []string{"get_user_info"},
+		authURL:     "https://graph.qq.com/oauth2.0/authorize",
+		tokenURL:    "https://graph.qq.com/oauth2.0/token",
+		userInfoURL: "https://graph.qq.com/user/get_user_info",
+	}}
+}
+
+// FetchToken implements Provider.FetchToken() interface method.
+//
+// API reference: https://wiki.connect.qq.com/%E4%BD%BF%E7%94%A8authorization_code%E8%8E%B7%E5%8F%96access_token
+func (p *QQ) FetchToken(code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	query := url.Values{}
+	query.Set("grant_type", "authorization_code")
+	query.Set("client_id", p.clientId)
+	query.Set("client_secret", p.clientSecret)
+	query.Set("code", code)
+	query.Set("redirect_uri", p.redirectURL)
+	query.Set("fmt", "json")
+
+	result := struct {
+		AccessToken  string      `json:"access_token"`
+		RefreshToken string      `json:"refresh_token"`
+		ExpiresIn    json.Number `json:"expires_in"`
+	}{}
+	if err := sendQQRequest(p.httpContext(), p.tokenURL+"?"+query.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	if result.AccessToken == "" {
+		return nil, errors.New("missing QQ access_token")
+	}
+
+	// API reference: https://wiki.connect.qq.com/unionid%E4%BB%8B%E7%BB%8D
+	meQuery := url.Values{}
+	meQuery.Set("access_token", result.AccessToken)
+	meQuery.Set("unionid", "1")
+	meQuery.Set("fmt", "json")
+
+	// the "me" api is relative to the token one
+	// (e.g. https://graph.qq.com/oauth2.0/token -> https://graph.qq.com/oauth2.0/me)
+	meURL := strings.TrimSuffix(p.tokenURL, "/token") + "/me"
+
+	me := struct {
+		OpenId  string `json:"openid"`
+		UnionId string `json:"unionid"`
+	}{}
+	if err := sendQQRequest(p.httpContext(), meURL+"?"+meQuery.Encode(), &me); err != nil {
+		return nil, err
+	}
+
+	if me.OpenId == "" {
+		return nil, errors.New("missing QQ openid")
+	}
+
+	token := &oauth2.Token{
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		TokenType:    "Bearer",
+	}
+
+	if expiresIn, _ := result.ExpiresIn.Int64(); expiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(expiresIn) * time.Second)
+	}
+
+	return token.WithExtra(map[string]any{
+		"openid":  me.OpenId,
+		"unionid": me.UnionId,
+	}), nil
+}
+
+// FetchRawUserInfo implements Provider.FetchRawUserInfo() interface method.
+//
+// This differ from BaseProvider because QQ expects the access token,
+// the app id and the openid as query parameters.
+func (p *QQ) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
+	query := url.Values{}
+	query.Set("access_token", token.AccessToken)
+	query.Set("oauth_consumer_key", p.clientId)
+	query.Set("openid", fmt.Sprint(token.Extra("openid")))
+
+	raw := json.RawMessage{}
+	if err := sendQQRequest(p.httpContext(), p.userInfoURL+"?"+query.Encode(), &raw); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// FetchAuthUser returns an AuthUser instance based on the QQ's get_user_info api.
+//
+// API reference: https://wiki.connect.qq.com/get_user_info
+func (p *QQ) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	data, err := p.FetchRawUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		Nickname  string `json:"nickname"`
+		AvatarURL string `json:"figureurl_qq_2"`
+		Avatar    string `json:"figureurl_qq_1"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	openId, _ := token.Extra("openid").(string)
+	unionId, _ := token.Extra("unionid").(string)
+
+	// store the identifiers as part of the raw user data
+	// since the get_user_info api doesn't return them
+	rawUser["openid"] = openId
+	rawUser["unionid"] = unionId
+
+	user := &AuthUser{
+		Id:           unionOrOpenId(unionId, openId),
+		Name:         extracted.Nickname,
+		AvatarURL:    extracted.AvatarURL,
+		RawUser:      rawUser,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}
+
+	// the 100x100 avatar is not available for all users
+	if user.AvatarURL == "" {
+		user.AvatarURL = extracted.Avatar
+	}
+
+	user.Expiry, _ = types.ParseDateTime(token.Expiry)
+
+	return user, nil
+}
+
+// sendQQRequest sends a GET request to the specified QQ
+// api url and unmarshalizes its response body into result.
+//
+// QQ returns errors with 200 status code and either
+// "error" and "error_description" or "ret" and "msg" body fields.
+func sendQQRequest(ctx context.Context, endpoint string, result any) error {
+	rawBody, err := sendGetRequest(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	apiErr := struct {
+		ErrorDescription string `json:"error_description"`
+		Msg              string `json:"msg"`
+		Error            int    `json:"error"`
+		Ret              int    `json:"ret"`
+	}{}
+	if err := json.Unmarshal(rawBody, &apiErr); err != nil {
+		return err
+	}
+
+	if apiErr.Error != 0 {
+		return fmt.Errorf("QQ api error (%d): %s", apiErr.Error, apiErr.ErrorDescription)
+	}
+
+	if apiErr.Ret != 0 {
+		return fmt.Errorf("QQ api error (%d): %s", apiErr.Ret, apiErr.Msg)
+	}
+
+	return json.Unmarshal(rawBody, result)
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func newTestQQServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/oauth2.0/token", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("client_id") != "test_app" || q.Get("client_secret") != "test_secret" || q.Get("fmt") != "json" {
+			t.Errorf("Unexpected token request query %v", q)
+		}
+
+		if q.Get("code") != "valid" {
+			json.NewEncoder(w).Encode(map[string]any{"error": 100019, "error_description": "code to access token error"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "test_access",
+			"refresh_token": "test_refresh",
+			"expires_in":    "7776000",
+		})
+	})
+	mux.HandleFunc("/oauth2.0/me", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("access_token") != "test_access" {
+			json.NewEncoder(w).Encode(map[string]any{"error": 100016, "error_description": "access token check failed"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"client_id": "test_app",
+			"openid":    "test_openid",
+		})
+	})
+	mux.HandleFunc("/user/get_user_info", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("oauth_consumer_key") != "test_app" || q.Get("openid") != "test_openid" {
+			json.NewEncoder(w).Encode(map[string]any{"ret": 1002, "msg": "invalid openid"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"ret":            0,
+			"msg":            "",
+			"nickname":       "test_name",
+			"figureurl_qq_1": "https://example.com/avatar_40.png",
+		})
+	})
+
+	server := httptest.NewServer(mux)
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestQQFetchAuthUser(t *testing.T) {
+	server := newTestQQServer(t)
+
+	p := NewQQProvider()
+	p.SetClientId("test_app")
+	p.SetClientSecret("test_secret")
+	p.SetTokenURL(server.URL + "/oauth2.0/token")
+	p.SetUserInfoURL(server.URL + "/user/get_user_info")
+
+	if _, err := p.FetchToken("invalid"); err == nil || !strings.Contains(err.Error(), "100019") {
+		t.Fatalf("Expected QQ api error, got %v", err)
+	}
+
+	token, err := p.FetchToken("valid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != "test_access" || token.RefreshToken != "test_refresh" || token.Expiry.IsZero() {
+		t.Fatalf("Unexpected token %#v", token)
+	}
+
+	if openId := token.Extra("openid"); openId != "test_openid" {
+		t.Fatalf("Expected openid token extra %q, got %v", "test_openid", openId)
+	}
+
+	user, err := p.FetchAuthUser(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Id != "test_openid" {
+		t.Fatalf("Expected the openid to be used as user id, got %q", user.Id)
+	}
+
+	if user.Name != "test_name" {
+		t.Fatalf("Expected name %q, got %q", "test_name", user.Name)
+	}
+
+	// fallback to the smaller avatar
+	if user.AvatarURL != "https://example.com/avatar_40.png" {
+		t.Fatalf("Expected avatar %q, got %q", "https://example.com/avatar_40.png", user.AvatarURL)
+	}
+
+	if user.RawUser["openid"] != "test_openid" {
+		t.Fatalf("Expected the openid to be part of the raw user data, got %v", user.RawUser)
+	}
+}
+
+func TestSendQQRequestRetError(t *testing.T) {
+	server := newTestQQServer(t)
+
+	p := NewQQProvider()
+	p.SetClientId("test_app")
+	p.SetUserInfoURL(server.URL + "/user/get_user_info")
+
+	token := (&oauth2.Token{AccessToken: "test_access"}).WithExtra(map[string]any{"openid": "missing"})
+
+	if _, err := p.FetchAuthUser(token); err == nil || !strings.Contains(err.Error(), "1002") {
+		t.Fatalf("Expected QQ api ret error, got %v", err)
+	}
+}
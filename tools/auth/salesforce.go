@@ -0,0 +1,152 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+	"golang.org/x/oauth2"
+)
+
+func init() {
+	Providers[NameSalesforce] = wrapFactory(NewSalesforceProvider)
+}
+
+var _ Provider = (*Salesforce)(nil)
+
+// NameSalesforce is the unique name of the Salesforce provider.
+const NameSalesforce string = "salesforce"
+
+const (
+	salesforceProductionHost = "https://login.salesforce.com"
+	salesforceSandboxHost    = "https://test.salesforce.com"
+)
+
+// Salesforce allows authentication via Salesforce OAuth2.
+//
+// The provider support the following Extra config options:
+//   - "sandbox" - if true, the default production login host
+//     (login.salesforce.com) is replaced with the sandbox one (test.salesforce.com).
+//
+// Custom "My Domain" login hosts could be used by changing the provider endpoint urls.
+type Salesforce struct {
+	BaseProvider
+}
+
+// NewSalesforceProvider creates new Salesforce provider instance with some defaults.
+//
+// Docs: https://help.salesforce.com/s/articleView?id=sf.remoteaccess_oauth_web_server_flow.htm
+func NewSalesforceProvider() *Salesforce {
+	return &Salesforce{BaseProvider{
+		ctx:         context.Background(),
+		displayName: "Salesforce",
+		pkce:        true,
+		scopes:      []string{"id"},
+		authURL:     salesforceProductionHost + "/services/oauth2/authorize",
+		tokenURL:    salesforceProductionHost + "/services/oauth2/token",
+		userInfoURL: salesforceProductionHost + "/services/oauth2/userinfo",
+	}}
+}
+
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+func (p *Salesforce) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	p.applySandboxHost()
+
+	return p.BaseProvider.BuildAuthURL(state, opts...)
+}
+
+// FetchToken implements Provider.FetchToken() interface method.
+func (p *Salesforce) FetchToken(code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	p.applySandboxHost()
+
+	return p.BaseProvider.FetchToken(code, opts...)
+}
+
+// FetchAuthUser returns an AuthUser instance based on the Salesforce's identity url response.
+//
+// API reference: https://help.salesforce.com/s/articleView?id=sf.remoteaccess_using_openid.htm
+func (p *Salesforce) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	data, err := p.FetchRawUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		Photos struct {
+			Picture string `json:"picture"`
+		} `json:"photos"`
+		UserId        string `json:"user_id"`
+		Username      string `json:"username"`
+		DisplayName   string `json:"display_name"`
+		Email         string `json:"email"`
+		EmailVerified bool   `json:"email_verified"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	user := &AuthUser{
+		Id:           extracted.UserId,
+		Name:         extracted.DisplayName,
+		Username:     extracted.Username,
+		AvatarURL:    extracted.Photos.Picture,
+		RawUser:      rawUser,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}
+
+	user.Expiry, _ = types.ParseDateTime(token.Expiry)
+
+	if extracted.EmailVerified {
+		user.Email = extracted.Email
+	}
+
+	return user, nil
+}
+
+// FetchRawUserInfo implements Provider.FetchRawUserInfo() interface method.
+//
+// This differ from BaseProvider because Salesforce returns the user
+// specific identity url as part of the token response ("id" field).
+// If missing, it fallbacks to the configured user info url.
+func (p *Salesforce) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
+	identityURL, _ := token.Extra("id").(string)
+	if identityURL == "" {
+		identityURL = p.userInfoURL
+	}
+
+	req, err := http.NewRequestWithContext(p.ctx, "GET", identityURL, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.sendRawUserInfoRequest(req, token)
+}
+
+// applySandboxHost replaces the default production login host
+// of the provider endpoints with the sandbox one (if enabled).
+func (p *Salesforce) applySandboxHost() {
+	if !cast.ToBool(p.Extra()["sandbox"]) {
+		return
+	}
+
+	p.authURL = replaceURLHost(p.authURL, salesforceProductionHost, salesforceSandboxHost)
+	p.tokenURL = replaceURLHost(p.tokenURL, salesforceProductionHost, salesforceSandboxHost)
+	p.userInfoURL = replaceURLHost(p.userInfoURL, salesforceProductionHost, salesforceSandboxHost)
+}
+
+func replaceURLHost(rawURL string, oldHost string, newHost string) string {
+	if rest, ok := strings.CutPrefix(rawURL, oldHost+"/"); ok {
+		return newHost + "/" + rest
+	}
+
+	return rawURL
+}
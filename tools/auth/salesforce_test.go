@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func TestSalesforceSandboxHost(t *testing.T) {
+	scenarios := []struct {
+		name             string
+		extra            map[string]any
+		authURL          string
+		expectedAuthURL  string
+		expectedTokenURL string
+	}{
+		{
+			"production",
+			nil,
+			"",
+			"https://login.salesforce.com/services/oauth2/authorize?",
+			"https://login.salesforce.com/services/oauth2/token",
+		},
+		{
+			"sandbox",
+			map[string]any{"sandbox": true},
+			"",
+			"https://test.salesforce.com/services/oauth2/authorize?",
+			"https://test.salesforce.com/services/oauth2/token",
+		},
+		{
+			"sandbox with custom domain",
+			map[string]any{"sandbox": true},
+			"https://example.my.salesforce.com/services/oauth2/authorize",
+			"https://example.my.salesforce.com/services/oauth2/authorize?",
+			"https://test.salesforce.com/services/oauth2/token",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewSalesforceProvider()
+			p.SetExtra(s.extra)
+			if s.authURL != "" {
+				p.SetAuthURL(s.authURL)
+			}
+
+			authURL := p.BuildAuthURL("test_state")
+			if !strings.HasPrefix(authURL, s.expectedAuthURL) {
+				t.Fatalf("Expected auth url with prefix %q, got %q", s.expectedAuthURL, authURL)
+			}
+
+			if p.TokenURL() != s.expectedTokenURL {
+				t.Fatalf("Expected token url %q, got %q", s.expectedTokenURL, p.TokenURL())
+			}
+		})
+	}
+}
+
+func TestSalesforceFetchAuthUser(t *testing.T) {
+	mux := http.NewServeMux()
+	identityHandler := func(verified bool) http.HandlerFunc {
+		return func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Authorization") != "Bearer test_access" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+
+			json.NewEncoder(w).Encode(map[string]any{
+				"user_id":         "005test",
+				"organization_id": "00Dtest",
+				"username":        "test@example.com.sandbox",
+				"display_name":    "test_name",
+				"email":           "test@example.com",
+				"email_verified":  verified,
+				"photos":          map[string]any{"picture": "https://example.com/avatar.png"},
+			})
+		}
+	}
+	mux.HandleFunc("/id/00Dtest/005test", identityHandler(true))
+	mux.HandleFunc("/services/oauth2/userinfo", identityHandler(false))
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	scenarios := []struct {
+		name          string
+		token         *oauth2.Token
+		expectedEmail string
+	}{
+		{
+			"with identity url",
+			(&oauth2.Token{AccessToken: "test_access"}).WithExtra(map[string]any{"id": server.URL + "/id/00Dtest/005test"}),
+			"test@example.com",
+		},
+		{
+			"fallback to the user info url",
+			&oauth2.Token{AccessToken: "test_access"},
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewSalesforceProvider()
+			p.SetUserInfoURL(server.URL + "/services/oauth2/userinfo")
+
+			user, err := p.FetchAuthUser(s.token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.Id != "005test" ||
+				user.Name != "test_name" ||
+				user.Username != "test@example.com.sandbox" ||
+				user.AvatarURL != "https://example.com/avatar.png" {
+				t.Fatalf("Unexpected user %#v", user)
+			}
+
+			if user.Email != s.expectedEmail {
+				t.Fatalf("Expected email %q, got %q", s.expectedEmail, user.Email)
+			}
+		})
+	}
+}
@@ -0,0 +1,276 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
+	"golang.org/x/oauth2"
+)
+
+func init() {
+	Providers[NameShopify] = wrapFactory(NewShopifyProvider)
+}
+
+var _ Provider = (*Shopify)(nil)
+
+// NameShopify is the unique name of the Shopify provider.
+const NameShopify string = "shopify"
+
+// ShopifyShopPlaceholder is the shop domain placeholder
+// of the default Shopify provider endpoint urls.
+const ShopifyShopPlaceholder = "{shop}"
+
+var shopifyShopRegex = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9\-]*\.myshopify\.com$`)
+
+// Shopify allows authentication via Shopify OAuth2 (authorization code grant).
+//
+// Shopify endpoints are shop specific and the default endpoint urls contain
+// a [ShopifyShopPlaceholder] that is replaced with the configured shop domain.
+//
+// The provider support the following Extra config options:
+//   - "shop" - the default shop domain (e.g. "example.myshopify.com").
+//     If not set, the shop must be loaded from a verified callback with [Shopify.VerifyCallback]
+//     and the auth url will contain the [ShopifyShopPlaceholder] that has to be replaced client-side.
+//   - "offlineAccess" - if true, requests an offline (shop) access token instead of an
+//     online (per-user) one; in this case the authenticated identity is the shop itself.
+//
+// The AuthUser.Id is in the format "shopDomain/userId" for online tokens
+// and just "shopDomain" for offline tokens, ensuring that the
+// external auth is scoped to the specific shop.
+type Shopify struct {
+	BaseProvider
+
+	shop string
+}
+
+// NewShopifyProvider creates new Shopify provider instance with some defaults.
+//
+// Docs: https://shopify.dev/docs/apps/build/authentication-authorization/access-tokens/authorization-code-grant
+func NewShopifyProvider() *Shopify {
+	return &Shopify{BaseProvider: BaseProvider{
+		ctx:         context.Background(),
+		displayName: "Shopify",
+		pkce:        false,
+		scopes:      []string{"read_products"},
+		authURL:     "https://" + ShopifyShopPlaceholder + "/admin/oauth/authorize",
+		tokenURL:    "https://" + ShopifyShopPlaceholder + "/admin/oauth/access_token",
+		userInfoURL: "https://" + ShopifyShopPlaceholder + "/admin/api/2025-01/shop.json",
+	}}
+}
+
+// Shop returns the current shop domain (either explicitly set
+// with [Shopify.SetShop] or the "shop" Extra config option).
+func (p *Shopify) Shop() string {
+	if p.shop != "" {
+		return p.shop
+	}
+
+	return strings.TrimSpace(cast.ToString(p.Extra()["shop"]))
+}
+
+// SetShop sets the shop domain of the provider endpoints.
+//
+// Returns an error if shop is not a valid "*.myshopify.com" domain.
+func (p *Shopify) SetShop(shop string) error {
+	if !shopifyShopRegex.MatchString(shop) {
+		return fmt.Errorf("invalid Shopify shop domain %q", shop)
+	}
+
+	p.shop = shop
+
+	return nil
+}
+
+// VerifyCallback verifies the HMAC signature of the Shopify
+// redirect callback query parameters and sets the provider shop
+// to the one from the query.
+//
+// If the provider has already a shop, it must match with the callback one.
+//
+// API reference: https://shopify.dev/docs/apps/build/authentication-authorization/access-tokens/authorization-code-grant#step-1-verify-the-installation-request
+func (p *Shopify) VerifyCallback(query url.Values) error {
+	signature := query.Get("hmac")
+	if signature == "" {
+		return errors.New("missing Shopify hmac parameter")
+	}
+
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		if k != "hmac" && k != "signature" {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+"="+strings.Join(query[k], ","))
+	}
+
+	mac := hmac.New(sha256.New, []byte(p.clientSecret))
+	mac.Write([]byte(strings.Join(parts, "&")))
+	expected := hex.EncodeToString(mac.Sum(nil))
+
+	if !hmac.Equal([]byte(expected), []byte(strings.ToLower(signature))) {
+		return errors.New("invalid Shopify hmac signature")
+	}
+
+	shop := query.Get("shop")
+	if current := p.Shop(); current != "" && current != shop {
+		return fmt.Errorf("the Shopify callback shop %q doesn't match the configured one", shop)
+	}
+
+	return p.SetShop(shop)
+}
+
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+func (p *Shopify) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	if !cast.ToBool(p.Extra()["offlineAccess"]) {
+		opts = append(opts, oauth2.SetAuthURLParam("grant_options[]", "per-user"))
+	}
+
+	config := p.oauth2Config()
+	config.Endpoint.AuthURL = p.shopURL(p.authURL)
+
+	return config.AuthCodeURL(state, opts...)
+}
+
+// FetchToken implements Provider.FetchToken() interface method.
+func (p *Shopify) FetchToken(code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	if !shopifyShopRegex.MatchString(p.Shop()) {
+		return nil, fmt.Errorf("missing or invalid Shopify shop domain %q", p.Shop())
+	}
+
+	config := p.oauth2Config()
+	config.Endpoint.TokenURL = p.shopURL(p.tokenURL)
+	config.Endpoint.AuthStyle = oauth2.AuthStyleInParams
+
+	return config.Exchange(p.httpContext(), code, opts...)
+}
+
+// FetchAuthUser returns an AuthUser instance based on the Shopify's
+// online token associated user or the shop api (for offline tokens).
+func (p *Shopify) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	data, err := p.FetchRawUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, err
+	}
+
+	shop := p.Shop()
+
+	user := &AuthUser{
+		RawUser:      rawUser,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}
+
+	user.Expiry, _ = types.ParseDateTime(token.Expiry)
+
+	if _, isOnline := token.Extra("associated_user").(map[string]any); isOnline {
+		extracted := struct {
+			FirstName     string `json:"first_name"`
+			LastName      string `json:"last_name"`
+			Email         string `json:"email"`
+			Id            int64  `json:"id"`
+			EmailVerified bool   `json:"email_verified"`
+		}{}
+		if err := json.Unmarshal(data, &extracted); err != nil {
+			return nil, err
+		}
+
+		if extracted.Id == 0 {
+			return nil, errors.New("missing Shopify associated user id")
+		}
+
+		user.Id = fmt.Sprintf("%s/%d", shop, extracted.Id)
+		user.Name = strings.TrimSpace(extracted.FirstName + " " + extracted.LastName)
+		if extracted.EmailVerified {
+			user.Email = extracted.Email
+		}
+	} else {
+		extracted := struct {
+			Name string `json:"name"`
+		}{}
+		if err := json.Unmarshal(data, &extracted); err != nil {
+			return nil, err
+		}
+
+		user.Id = shop
+		user.Name = extracted.Name
+	}
+
+	return user, nil
+}
+
+// FetchRawUserInfo implements Provider.FetchRawUserInfo() interface method.
+//
+// This differ from BaseProvider because Shopify returns the user data
+// of the online tokens as part of the token response ("associated_user") and
+// for the offline tokens the shop api expects the "X-Shopify-Access-Token" header.
+//
+// The shop domain is available as "shop" raw user field.
+func (p *Shopify) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
+	if associatedUser, ok := token.Extra("associated_user").(map[string]any); ok {
+		raw := make(map[string]any, len(associatedUser)+1)
+		for k, v := range associatedUser {
+			raw[k] = v
+		}
+		raw["shop"] = p.Shop()
+
+		return json.Marshal(raw)
+	}
+
+	req, err := http.NewRequestWithContext(p.ctx, "GET", p.shopURL(p.userInfoURL), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("X-Shopify-Access-Token", token.AccessToken)
+
+	data, err := p.sendRawUserInfoRequest(req, token)
+	if err != nil {
+		return nil, err
+	}
+
+	wrapper := struct {
+		Shop map[string]any `json:"shop"`
+	}{}
+	if err := json.Unmarshal(data, &wrapper); err != nil {
+		return nil, err
+	}
+
+	if wrapper.Shop == nil {
+		wrapper.Shop = map[string]any{}
+	}
+	wrapper.Shop["shop"] = p.Shop()
+
+	return json.Marshal(wrapper.Shop)
+}
+
+// shopURL replaces the [ShopifyShopPlaceholder] of rawURL
+// with the current shop domain (if any).
+func (p *Shopify) shopURL(rawURL string) string {
+	shop := p.Shop()
+	if shop == "" {
+		return rawURL
+	}
+
+	return strings.ReplaceAll(rawURL, ShopifyShopPlaceholder, shop)
+}
@@ -0,0 +1,277 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func signShopifyQuery(secret string, query url.Values) url.Values {
+	// url.Values.Encode sorts the keys
+	message, _ := url.QueryUnescape(query.Encode())
+
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(message))
+
+	signed := url.Values{}
+	for k, v := range query {
+		signed[k] = v
+	}
+	signed.Set("hmac", hex.EncodeToString(mac.Sum(nil)))
+
+	return signed
+}
+
+func TestShopifyVerifyCallback(t *testing.T) {
+	query := url.Values{
+		"code":      {"test_code"},
+		"shop":      {"example.myshopify.com"},
+		"state":     {"test_state"},
+		"timestamp": {"1337178173"},
+	}
+
+	scenarios := []struct {
+		name         string
+		query        url.Values
+		shop         string
+		expectError  bool
+		expectedShop string
+	}{
+		{
+			"missing hmac",
+			query,
+			"",
+			true,
+			"",
+		},
+		{
+			"invalid hmac",
+			signShopifyQuery("other_secret", query),
+			"",
+			true,
+			"",
+		},
+		{
+			"valid hmac",
+			signShopifyQuery("test_secret", query),
+			"",
+			false,
+			"example.myshopify.com",
+		},
+		{
+			"valid hmac with matching configured shop",
+			signShopifyQuery("test_secret", query),
+			"example.myshopify.com",
+			false,
+			"example.myshopify.com",
+		},
+		{
+			"valid hmac with different configured shop",
+			signShopifyQuery("test_secret", query),
+			"other.myshopify.com",
+			true,
+			"other.myshopify.com",
+		},
+		{
+			"valid hmac with invalid shop domain",
+			signShopifyQuery("test_secret", url.Values{"code": {"test_code"}, "shop": {"example.com"}}),
+			"",
+			true,
+			"",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewShopifyProvider()
+			p.SetClientSecret("test_secret")
+			p.SetExtra(map[string]any{"shop": s.shop})
+
+			err := p.VerifyCallback(s.query)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if p.Shop() != s.expectedShop {
+				t.Fatalf("Expected shop %q, got %q", s.expectedShop, p.Shop())
+			}
+		})
+	}
+}
+
+func TestShopifyBuildAuthURL(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		extra          map[string]any
+		expectedPrefix string
+		expectPerUser  bool
+	}{
+		{
+			"without shop",
+			nil,
+			"https://" + ShopifyShopPlaceholder + "/admin/oauth/authorize?",
+			true,
+		},
+		{
+			"with shop",
+			map[string]any{"shop": "example.myshopify.com"},
+			"https://example.myshopify.com/admin/oauth/authorize?",
+			true,
+		},
+		{
+			"with offlineAccess",
+			map[string]any{"shop": "example.myshopify.com", "offlineAccess": true},
+			"https://example.myshopify.com/admin/oauth/authorize?",
+			false,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewShopifyProvider()
+			p.SetExtra(s.extra)
+
+			authURL := p.BuildAuthURL("test_state")
+			if !strings.HasPrefix(authURL, s.expectedPrefix) {
+				t.Fatalf("Expected auth url with prefix %q, got %q", s.expectedPrefix, authURL)
+			}
+
+			hasPerUser := strings.Contains(authURL, url.QueryEscape("grant_options[]")+"=per-user")
+			if hasPerUser != s.expectPerUser {
+				t.Fatalf("Expected per-user %v, got %v (%q)", s.expectPerUser, hasPerUser, authURL)
+			}
+		})
+	}
+}
+
+func TestShopifyFetchAuthUser(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/example.myshopify.com/admin/oauth/access_token", func(w http.ResponseWriter, r *http.Request) {
+		r.ParseForm()
+		if r.Form.Get("client_id") != "test_client" || r.Form.Get("client_secret") != "test_secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+
+		if r.Form.Get("code") == "offline" {
+			json.NewEncoder(w).Encode(map[string]any{
+				"access_token": "test_offline",
+				"scope":        "read_products",
+			})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token": "test_online",
+			"scope":        "read_products",
+			"expires_in":   86399,
+			"associated_user": map[string]any{
+				"id":             902541635,
+				"first_name":     "John",
+				"last_name":      "Smith",
+				"email":          "john@example.com",
+				"email_verified": true,
+			},
+		})
+	})
+	mux.HandleFunc("/example.myshopify.com/admin/api/2025-01/shop.json", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Shopify-Access-Token") != "test_offline" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"shop": map[string]any{"id": 123, "name": "Example shop"},
+		})
+	})
+
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	newProvider := func(shop string) *Shopify {
+		p := NewShopifyProvider()
+		p.SetClientId("test_client")
+		p.SetClientSecret("test_secret")
+		p.SetTokenURL(server.URL + "/" + ShopifyShopPlaceholder + "/admin/oauth/access_token")
+		p.SetUserInfoURL(server.URL + "/" + ShopifyShopPlaceholder + "/admin/api/2025-01/shop.json")
+		p.SetExtra(map[string]any{"shop": shop})
+		return p
+	}
+
+	t.Run("missing shop", func(t *testing.T) {
+		if _, err := newProvider("").FetchToken("online"); err == nil {
+			t.Fatal("Expected missing shop error")
+		}
+	})
+
+	scenarios := []struct {
+		code         string
+		expectedId   string
+		expectedName string
+		expectedMail string
+	}{
+		{"online", "example.myshopify.com/902541635", "John Smith", "john@example.com"},
+		{"offline", "example.myshopify.com", "Example shop", ""},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.code, func(t *testing.T) {
+			p := newProvider("example.myshopify.com")
+
+			token, err := p.FetchToken(s.code)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			user, err := p.FetchAuthUser(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.Id != s.expectedId {
+				t.Fatalf("Expected id %q, got %q", s.expectedId, user.Id)
+			}
+
+			if user.Name != s.expectedName {
+				t.Fatalf("Expected name %q, got %q", s.expectedName, user.Name)
+			}
+
+			if user.Email != s.expectedMail {
+				t.Fatalf("Expected email %q, got %q", s.expectedMail, user.Email)
+			}
+
+			if user.RawUser["shop"] != "example.myshopify.com" {
+				t.Fatalf("Expected the shop raw user field, got %v", user.RawUser)
+			}
+		})
+	}
+}
+
+func TestShopifyVerifiedCallbackShopFetchToken(t *testing.T) {
+	p := NewShopifyProvider()
+	p.SetClientSecret("test_secret")
+
+	query := signShopifyQuery("test_secret", url.Values{"code": {"test_code"}, "shop": {"example.myshopify.com"}})
+	if err := p.VerifyCallback(query); err != nil {
+		t.Fatal(err)
+	}
+
+	// ensures that the shop placeholder is replaced
+	p.SetTokenURL("http://127.0.0.1:0/" + ShopifyShopPlaceholder)
+	_, err := p.FetchToken("test_code", oauth2.AccessTypeOffline)
+	if err == nil || !strings.Contains(err.Error(), "example.myshopify.com") {
+		t.Fatalf("Expected request error to the shop token url, got %v", err)
+	}
+}
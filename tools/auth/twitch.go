@@ -4,9 +4,15 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"io"
 	"net/http"
+	"net/url"
+	"slices"
+	"strings"
 
 	"github.com/pocketbase/pocketbase/tools/types"
+	"github.com/spf13/cast"
 	"golang.org/x/oauth2"
 	"golang.org/x/oauth2/twitch"
 )
@@ -21,6 +27,16 @@ var _ Provider = (*Twitch)(nil)
 const NameTwitch string = "twitch"
 
 // Twitch allows authentication via Twitch OAuth2.
+//
+// The provider support the following Extra config options:
+//   - "channelId" - the broadcaster id of the channel to check the follow and subscription status for.
+//   - "fetchFollow" - if true, checks whether the user follows "channelId" and stores the result
+//     as "channelFollower" bool [AuthUser.RawUser] key ("follower" is also added to [AuthUser.Groups]).
+//   - "fetchSubscription" - if true, checks whether the user is subscribed to "channelId" and stores
+//     the result as "channelSubscriber" bool and "channelSubscriptionTier" string [AuthUser.RawUser] keys
+//     ("subscriber" is also added to [AuthUser.Groups]).
+//
+// The additional "user:read:follows" and "user:read:subscriptions" scopes are automatically requested when needed.
 type Twitch struct {
 	BaseProvider
 }
@@ -82,9 +98,138 @@ func (p *Twitch) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
 
 	user.Expiry, _ = types.ParseDateTime(token.Expiry)
 
+	if err := p.fetchChannelStatus(token, user); err != nil {
+		return nil, err
+	}
+
 	return user, nil
 }
 
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+func (p *Twitch) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	if p.fetchFollow() && !slices.Contains(p.scopes, "user:read:follows") {
+		p.scopes = append(slices.Clone(p.scopes), "user:read:follows")
+	}
+
+	if p.fetchSubscription() && !slices.Contains(p.scopes, "user:read:subscriptions") {
+		p.scopes = append(slices.Clone(p.scopes), "user:read:subscriptions")
+	}
+
+	return p.BaseProvider.BuildAuthURL(state, opts...)
+}
+
+func (p *Twitch) channelId() string {
+	return strings.TrimSpace(cast.ToString(p.Extra()["channelId"]))
+}
+
+func (p *Twitch) fetchFollow() bool {
+	return cast.ToBool(p.Extra()["fetchFollow"])
+}
+
+func (p *Twitch) fetchSubscription() bool {
+	return cast.ToBool(p.Extra()["fetchSubscription"])
+}
+
+// fetchChannelStatus fetches the user follow and subscription
+// status for the configured channel (if enabled).
+func (p *Twitch) fetchChannelStatus(token *oauth2.Token, user *AuthUser) error {
+	if !p.fetchFollow() && !p.fetchSubscription() {
+		return nil
+	}
+
+	channelId := p.channelId()
+	if channelId == "" {
+		return errors.New(`the Twitch "fetchFollow" and "fetchSubscription" options require "channelId" to be set`)
+	}
+
+	query := url.Values{}
+	query.Set("broadcaster_id", channelId)
+	query.Set("user_id", user.Id)
+
+	// the helix endpoints are relative to the user info one
+	// (e.g. https://api.twitch.tv/helix/users -> https://api.twitch.tv/helix/channels/followed)
+	baseURL := strings.TrimSuffix(p.userInfoURL, "/users")
+
+	if p.fetchFollow() {
+		// API reference: https://dev.twitch.tv/docs/api/reference/#get-followed-channels
+		follows := struct {
+			Data []struct {
+				BroadcasterId string `json:"broadcaster_id"`
+			} `json:"data"`
+		}{}
+		if _, err := p.sendHelixRequest(token, baseURL+"/channels/followed?"+query.Encode(), &follows); err != nil {
+			return err
+		}
+
+		isFollower := len(follows.Data) > 0
+		user.RawUser["channelFollower"] = isFollower
+		if isFollower {
+			user.Groups = append(user.Groups, "follower")
+		}
+	}
+
+	if p.fetchSubscription() {
+		// API reference: https://dev.twitch.tv/docs/api/reference/#check-user-subscription
+		subs := struct {
+			Data []struct {
+				Tier string `json:"tier"`
+			} `json:"data"`
+		}{}
+		found, err := p.sendHelixRequest(token, baseURL+"/subscriptions/user?"+query.Encode(), &subs)
+		if err != nil {
+			return err
+		}
+
+		isSubscriber := found && len(subs.Data) > 0
+		user.RawUser["channelSubscriber"] = isSubscriber
+		user.RawUser["channelSubscriptionTier"] = ""
+		if isSubscriber {
+			user.RawUser["channelSubscriptionTier"] = subs.Data[0].Tier
+			user.Groups = append(user.Groups, "subscriber")
+		}
+	}
+
+	return nil
+}
+
+// sendHelixRequest sends an authorized GET request to the provided Helix API url
+// and unmarshalizes its response into result.
+//
+// Returns false if the response status is 404 (e.g. for a missing subscription).
+func (p *Twitch) sendHelixRequest(token *oauth2.Token, endpoint string, result any) (bool, error) {
+	req, err := http.NewRequestWithContext(p.ctx, "GET", endpoint, nil)
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Client-Id", p.clientId)
+
+	res, err := p.Client(token).Do(req)
+	if err != nil {
+		return false, err
+	}
+	defer res.Body.Close()
+
+	content, err := io.ReadAll(res.Body)
+	if err != nil {
+		return false, err
+	}
+
+	if res.StatusCode == http.StatusNotFound {
+		return false, nil
+	}
+
+	if res.StatusCode >= 400 {
+		return false, fmt.Errorf(
+			"failed to fetch Twitch channel status (%d):\n%s",
+			res.StatusCode,
+			string(content),
+		)
+	}
+
+	return true, json.Unmarshal(content, result)
+}
+
 // FetchRawUserInfo implements Provider.FetchRawUserInfo interface method.
 //
 // This differ from BaseProvider because Twitch requires the Client-Id header.
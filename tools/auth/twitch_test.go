@@ -0,0 +1,186 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"golang.org/x/oauth2"
+)
+
+func newTestTwitchServer(t *testing.T) *httptest.Server {
+	checkRequest := func(r *http.Request) {
+		if r.Header.Get("Client-Id") != "test_client" {
+			t.Errorf("Expected Client-Id header %q, got %q", "test_client", r.Header.Get("Client-Id"))
+		}
+		if r.URL.Query().Get("user_id") != "123" {
+			t.Errorf("Expected user_id %q, got %q", "123", r.URL.Query().Get("user_id"))
+		}
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/helix/users", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]any{
+			"data": []map[string]any{{"id": "123", "login": "test"}},
+		})
+	})
+	mux.HandleFunc("/helix/channels/followed", func(w http.ResponseWriter, r *http.Request) {
+		checkRequest(r)
+
+		data := []map[string]any{}
+		if r.URL.Query().Get("broadcaster_id") == "c1" {
+			data = append(data, map[string]any{"broadcaster_id": "c1"})
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{"data": data})
+	})
+	mux.HandleFunc("/helix/subscriptions/user", func(w http.ResponseWriter, r *http.Request) {
+		checkRequest(r)
+
+		switch r.URL.Query().Get("broadcaster_id") {
+		case "c1":
+			json.NewEncoder(w).Encode(map[string]any{
+				"data": []map[string]any{{"broadcaster_id": "c1", "tier": "2000"}},
+			})
+		case "c3":
+			w.WriteHeader(http.StatusUnauthorized)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	})
+
+	server := httptest.NewServer(mux)
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestTwitchBuildAuthURL(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		extra       map[string]any
+		expectScope string
+	}{
+		{"without channel status", nil, "user:read:email"},
+		{"with fetchFollow", map[string]any{"fetchFollow": true}, "user:read:email user:read:follows"},
+		{"with fetchSubscription", map[string]any{"fetchSubscription": true}, "user:read:email user:read:subscriptions"},
+		{
+			"with fetchFollow and fetchSubscription",
+			map[string]any{"fetchFollow": true, "fetchSubscription": true},
+			"user:read:email user:read:follows user:read:subscriptions",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewTwitchProvider()
+			p.SetExtra(s.extra)
+
+			authURL := p.BuildAuthURL("test_state")
+
+			expected := strings.NewReplacer(" ", "+", ":", "%3A").Replace(s.expectScope)
+			if !strings.Contains(authURL, "scope="+expected+"&") {
+				t.Fatalf("Expected scope %q in %q", s.expectScope, authURL)
+			}
+		})
+	}
+}
+
+func TestTwitchFetchAuthUserChannelStatus(t *testing.T) {
+	scenarios := []struct {
+		name           string
+		extra          map[string]any
+		expectError    bool
+		expectedRaw    map[string]any
+		expectedGroups []string
+	}{
+		{
+			"without channel status",
+			map[string]any{"channelId": "c1"},
+			false,
+			map[string]any{},
+			nil,
+		},
+		{
+			"missing channelId",
+			map[string]any{"fetchFollow": true},
+			true,
+			nil,
+			nil,
+		},
+		{
+			"follower and subscriber",
+			map[string]any{"channelId": "c1", "fetchFollow": true, "fetchSubscription": true},
+			false,
+			map[string]any{
+				"channelFollower":         true,
+				"channelSubscriber":       true,
+				"channelSubscriptionTier": "2000",
+			},
+			[]string{"follower", "subscriber"},
+		},
+		{
+			"non-follower and non-subscriber",
+			map[string]any{"channelId": "c2", "fetchFollow": true, "fetchSubscription": true},
+			false,
+			map[string]any{
+				"channelFollower":         false,
+				"channelSubscriber":       false,
+				"channelSubscriptionTier": "",
+			},
+			nil,
+		},
+		{
+			"only follow check",
+			map[string]any{"channelId": "c1", "fetchFollow": true},
+			false,
+			map[string]any{"channelFollower": true},
+			[]string{"follower"},
+		},
+		{
+			"failed subscription request",
+			map[string]any{"channelId": "c3", "fetchSubscription": true},
+			true,
+			nil,
+			nil,
+		},
+	}
+
+	server := newTestTwitchServer(t)
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			p := NewTwitchProvider()
+			p.SetClientId("test_client")
+			p.SetUserInfoURL(server.URL + "/helix/users")
+			p.SetExtra(s.extra)
+
+			user, err := p.FetchAuthUser(&oauth2.Token{AccessToken: "test"})
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			for _, k := range []string{"channelFollower", "channelSubscriber", "channelSubscriptionTier"} {
+				expected, ok := s.expectedRaw[k]
+				actual, exists := user.RawUser[k]
+				if ok != exists || expected != actual {
+					t.Fatalf("Expected RawUser[%q] %v (exists: %v), got %v (exists: %v)", k, expected, ok, actual, exists)
+				}
+			}
+
+			if !slices.Equal(user.Groups, s.expectedGroups) {
+				t.Fatalf("Expected groups %v, got %v", s.expectedGroups, user.Groups)
+			}
+		})
+	}
+}
@@ -0,0 +1,310 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+	"golang.org/x/oauth2"
+)
+
+func init() {
+	Providers[NameWeChat] = wrapFactory(NewWeChatProvider)
+	Providers[NameWeChatMiniProgram] = wrapFactory(NewWeChatMiniProgramProvider)
+}
+
+var (
+	_ Provider = (*WeChat)(nil)
+	_ Provider = (*WeChatMiniProgram)(nil)
+)
+
+// NameWeChat is the unique name of the WeChat (website app) provider.
+const NameWeChat string = "wechat"
+
+// NameWeChatMiniProgram is the unique name of the WeChat Mini Program provider.
+const NameWeChatMiniProgram string = "wechatmp"
+
+// WeChat allows authentication via WeChat website app QR code login.
+//
+// WeChat doesn't follow the OAuth2 spec (e.g. it uses "appid" instead of "client_id",
+// GET token requests and errors with 200 status code), so the auth url
+// and the token exchange are handled separately.
+//
+// The AuthUser.Id is the user "unionid" if available (aka. the app is bound
+// to a WeChat Open Platform account), otherwise it fallbacks to the "openid".
+type WeChat struct {
+	BaseProvider
+}
+
+// NewWeChatProvider creates new WeChat provider instance with some defaults.
+//
+// Docs: https://developers.weixin.qq.com/doc/oplatform/en/Website_App/WeChat_Login/Wechat_Login.html
+func NewWeChatProvider() *WeChat {
+	return &WeChat{BaseProvider{
+		ctx:         context.Background(),
+		displayName: "WeChat",
+		pkce:        false,
+		scopes:      []string{"snsapi_login"},
+		authURL:     "https://open.weixin.qq.com/connect/qrconnect",
+		tokenURL:    "https://api.weixin.qq.com/sns/oauth2/access_token",
+		userInfoURL: "https://api.weixin.qq.com/sns/userinfo",
+	}}
+}
+
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+func (p *WeChat) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	query := url.Values{}
+	query.Set("appid", p.clientId)
+	query.Set("response_type", "code")
+	query.Set("scope", strings.Join(p.scopes, ","))
+	query.Set("state", state)
+	if p.redirectURL != "" {
+		query.Set("redirect_uri", p.redirectURL)
+	}
+
+	return p.authURL + "?" + query.Encode()
+}
+
+// FetchToken implements Provider.FetchToken() interface method.
+//
+// API reference: https://developers.weixin.qq.com/doc/oplatform/en/Website_App/WeChat_Login/Wechat_Login.html
+func (p *WeChat) FetchToken(code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	query := url.Values{}
+	query.Set("appid", p.clientId)
+	query.Set("secret", p.clientSecret)
+	query.Set("code", code)
+	query.Set("grant_type", "authorization_code")
+
+	result := struct {
+		AccessToken  string `json:"access_token"`
+		RefreshToken string `json:"refresh_token"`
+		OpenId       string `json:"openid"`
+		UnionId      string `json:"unionid"`
+		Scope        string `json:"scope"`
+		ExpiresIn    int64  `json:"expires_in"`
+	}{}
+	if err := sendWeChatRequest(p.httpContext(), p.tokenURL+"?"+query.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	if result.AccessToken == "" || result.OpenId == "" {
+		return nil, errors.New("missing WeChat access_token or openid")
+	}
+
+	token := &oauth2.Token{
+		AccessToken:  result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		TokenType:    "Bearer",
+	}
+
+	if result.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(result.ExpiresIn) * time.Second)
+	}
+
+	return token.WithExtra(map[string]any{
+		"openid":  result.OpenId,
+		"unionid": result.UnionId,
+		"scope":   result.Scope,
+	}), nil
+}
+
+// FetchRawUserInfo implements Provider.FetchRawUserInfo() interface method.
+//
+// This differ from BaseProvider because WeChat expects the
+// access token and the openid as query parameters.
+func (p *WeChat) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
+	query := url.Values{}
+	query.Set("access_token", token.AccessToken)
+	query.Set("openid", fmt.Sprint(token.Extra("openid")))
+	query.Set("lang", "en")
+
+	raw := json.RawMessage{}
+	if err := sendWeChatRequest(p.httpContext(), p.userInfoURL+"?"+query.Encode(), &raw); err != nil {
+		return nil, err
+	}
+
+	return raw, nil
+}
+
+// FetchAuthUser returns an AuthUser instance based on the WeChat's userinfo api.
+//
+// API reference: https://developers.weixin.qq.com/doc/oplatform/en/Website_App/WeChat_Login/Authorized_API_call_UnionID.html
+func (p *WeChat) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	data, err := p.FetchRawUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, err
+	}
+
+	extracted := struct {
+		OpenId     string `json:"openid"`
+		UnionId    string `json:"unionid"`
+		Nickname   string `json:"nickname"`
+		HeadImgURL string `json:"headimgurl"`
+	}{}
+	if err := json.Unmarshal(data, &extracted); err != nil {
+		return nil, err
+	}
+
+	user := &AuthUser{
+		Id:           unionOrOpenId(extracted.UnionId, extracted.OpenId),
+		Name:         extracted.Nickname,
+		AvatarURL:    extracted.HeadImgURL,
+		RawUser:      rawUser,
+		AccessToken:  token.AccessToken,
+		RefreshToken: token.RefreshToken,
+	}
+
+	user.Expiry, _ = types.ParseDateTime(token.Expiry)
+
+	return user, nil
+}
+
+// -------------------------------------------------------------------
+
+// WeChatMiniProgram allows authentication of WeChat Mini Program users
+// by exchanging the wx.login() code via the code2Session api.
+//
+// There is no auth url (the code is obtained client-side in the Mini Program)
+// and no user profile api, so the AuthUser contains only the user identifiers.
+//
+// Note that the code2Session "session_key" is intentionally not exposed
+// because it must never be sent to the client.
+//
+// The AuthUser.Id is the user "unionid" if available (aka. the app is bound
+// to a WeChat Open Platform account), otherwise it fallbacks to the "openid".
+type WeChatMiniProgram struct {
+	BaseProvider
+}
+
+// NewWeChatMiniProgramProvider creates new WeChat Mini Program provider instance with some defaults.
+//
+// Docs: https://developers.weixin.qq.com/miniprogram/en/dev/framework/open-ability/login.html
+func NewWeChatMiniProgramProvider() *WeChatMiniProgram {
+	return &WeChatMiniProgram{BaseProvider{
+		ctx:         context.Background(),
+		displayName: "WeChat Mini Program",
+		pkce:        false,
+		tokenURL:    "https://api.weixin.qq.com/sns/jscode2session",
+	}}
+}
+
+// BuildAuthURL implements Provider.BuildAuthURL() interface method.
+//
+// It always returns an empty string because the Mini Program login
+// code is obtained client-side with wx.login().
+func (p *WeChatMiniProgram) BuildAuthURL(state string, opts ...oauth2.AuthCodeOption) string {
+	return ""
+}
+
+// FetchToken implements Provider.FetchToken() interface method.
+//
+// The returned token doesn't have an access token and contains only
+// the "openid" and "unionid" extra fields.
+//
+// API reference: https://developers.weixin.qq.com/miniprogram/en/dev/api-backend/open-api/login/auth.code2Session.html
+func (p *WeChatMiniProgram) FetchToken(code string, opts ...oauth2.AuthCodeOption) (*oauth2.Token, error) {
+	query := url.Values{}
+	query.Set("appid", p.clientId)
+	query.Set("secret", p.clientSecret)
+	query.Set("js_code", code)
+	query.Set("grant_type", "authorization_code")
+
+	result := struct {
+		OpenId  string `json:"openid"`
+		UnionId string `json:"unionid"`
+	}{}
+	if err := sendWeChatRequest(p.httpContext(), p.tokenURL+"?"+query.Encode(), &result); err != nil {
+		return nil, err
+	}
+
+	if result.OpenId == "" {
+		return nil, errors.New("missing WeChat Mini Program openid")
+	}
+
+	return (&oauth2.Token{}).WithExtra(map[string]any{
+		"openid":  result.OpenId,
+		"unionid": result.UnionId,
+	}), nil
+}
+
+// FetchRawUserInfo implements Provider.FetchRawUserInfo() interface method.
+//
+// This differ from BaseProvider because there is no user profile api
+// and the raw user data is constructed from the token identifiers.
+func (p *WeChatMiniProgram) FetchRawUserInfo(token *oauth2.Token) ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"openid":  token.Extra("openid"),
+		"unionid": token.Extra("unionid"),
+	})
+}
+
+// FetchAuthUser returns an AuthUser instance based on the code2Session identifiers.
+func (p *WeChatMiniProgram) FetchAuthUser(token *oauth2.Token) (*AuthUser, error) {
+	data, err := p.FetchRawUserInfo(token)
+	if err != nil {
+		return nil, err
+	}
+
+	rawUser := map[string]any{}
+	if err := json.Unmarshal(data, &rawUser); err != nil {
+		return nil, err
+	}
+
+	openId, _ := token.Extra("openid").(string)
+	unionId, _ := token.Extra("unionid").(string)
+	if openId == "" {
+		return nil, errors.New("missing WeChat Mini Program openid")
+	}
+
+	return &AuthUser{
+		Id:      unionOrOpenId(unionId, openId),
+		RawUser: rawUser,
+	}, nil
+}
+
+// -------------------------------------------------------------------
+
+// unionOrOpenId returns unionId if not empty, otherwise openId
+// (used by the WeChat and QQ providers).
+func unionOrOpenId(unionId string, openId string) string {
+	if unionId != "" {
+		return unionId
+	}
+
+	return openId
+}
+
+// sendWeChatRequest sends a GET request to the specified WeChat
+// api url and unmarshalizes its response body into result.
+//
+// WeChat returns errors with 200 status code and "errcode" and "errmsg" body fields.
+func sendWeChatRequest(ctx context.Context, endpoint string, result any) error {
+	rawBody, err := sendGetRequest(ctx, endpoint)
+	if err != nil {
+		return err
+	}
+
+	apiErr := struct {
+		ErrMsg  string `json:"errmsg"`
+		ErrCode int    `json:"errcode"`
+	}{}
+	if err := json.Unmarshal(rawBody, &apiErr); err != nil {
+		return err
+	}
+
+	if apiErr.ErrCode != 0 {
+		return fmt.Errorf("WeChat api error (%d): %s", apiErr.ErrCode, apiErr.ErrMsg)
+	}
+
+	return json.Unmarshal(rawBody, result)
+}
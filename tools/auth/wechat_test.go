@@ -0,0 +1,205 @@
+package auth
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+func newTestWeChatServer(t *testing.T) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/sns/oauth2/access_token", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("appid") != "test_app" || q.Get("secret") != "test_secret" || q.Get("grant_type") != "authorization_code" {
+			t.Errorf("Unexpected token request query %v", q)
+		}
+
+		if q.Get("code") != "valid" {
+			json.NewEncoder(w).Encode(map[string]any{"errcode": 40029, "errmsg": "invalid code"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"access_token":  "test_access",
+			"refresh_token": "test_refresh",
+			"expires_in":    7200,
+			"openid":        "test_openid",
+			"scope":         "snsapi_login",
+		})
+	})
+	mux.HandleFunc("/sns/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("access_token") != "test_access" || q.Get("openid") != "test_openid" {
+			json.NewEncoder(w).Encode(map[string]any{"errcode": 40003, "errmsg": "invalid openid"})
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]any{
+			"openid":     "test_openid",
+			"unionid":    "test_unionid",
+			"nickname":   "test_name",
+			"headimgurl": "https://example.com/avatar.png",
+		})
+	})
+	mux.HandleFunc("/sns/jscode2session", func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("appid") != "test_app" || q.Get("secret") != "test_secret" {
+			t.Errorf("Unexpected code2session request query %v", q)
+		}
+
+		switch q.Get("js_code") {
+		case "valid":
+			json.NewEncoder(w).Encode(map[string]any{"openid": "test_openid", "session_key": "test_session_key"})
+		case "valid_unionid":
+			json.NewEncoder(w).Encode(map[string]any{"openid": "test_openid", "unionid": "test_unionid", "session_key": "test_session_key"})
+		default:
+			json.NewEncoder(w).Encode(map[string]any{"errcode": 40029, "errmsg": "invalid code"})
+		}
+	})
+
+	server := httptest.NewServer(mux)
+
+	t.Cleanup(server.Close)
+
+	return server
+}
+
+func TestWeChatBuildAuthURL(t *testing.T) {
+	p := NewWeChatProvider()
+	p.SetClientId("test_app")
+
+	authURL, err := url.Parse(p.BuildAuthURL("test_state"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[string]string{
+		"appid":         "test_app",
+		"response_type": "code",
+		"scope":         "snsapi_login",
+		"state":         "test_state",
+	}
+
+	q := authURL.Query()
+	for k, v := range expected {
+		if q.Get(k) != v {
+			t.Fatalf("Expected %q query param %q, got %q", k, v, q.Get(k))
+		}
+	}
+
+	if q.Has("client_id") || q.Has("redirect_uri") {
+		t.Fatalf("Expected no client_id and redirect_uri query params, got %v", q)
+	}
+}
+
+func TestWeChatFetchAuthUser(t *testing.T) {
+	server := newTestWeChatServer(t)
+
+	p := NewWeChatProvider()
+	p.SetClientId("test_app")
+	p.SetClientSecret("test_secret")
+	p.SetTokenURL(server.URL + "/sns/oauth2/access_token")
+	p.SetUserInfoURL(server.URL + "/sns/userinfo")
+
+	if _, err := p.FetchToken("invalid"); err == nil || !strings.Contains(err.Error(), "40029") {
+		t.Fatalf("Expected WeChat api error, got %v", err)
+	}
+
+	token, err := p.FetchToken("valid")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != "test_access" || token.RefreshToken != "test_refresh" || token.Expiry.IsZero() {
+		t.Fatalf("Unexpected token %#v", token)
+	}
+
+	user, err := p.FetchAuthUser(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if user.Id != "test_unionid" {
+		t.Fatalf("Expected the unionid to be used as user id, got %q", user.Id)
+	}
+
+	if user.Name != "test_name" {
+		t.Fatalf("Expected name %q, got %q", "test_name", user.Name)
+	}
+
+	if user.AvatarURL != "https://example.com/avatar.png" {
+		t.Fatalf("Expected avatar %q, got %q", "https://example.com/avatar.png", user.AvatarURL)
+	}
+
+	if user.AccessToken != "test_access" {
+		t.Fatalf("Expected access token %q, got %q", "test_access", user.AccessToken)
+	}
+}
+
+func TestWeChatMiniProgramBuildAuthURL(t *testing.T) {
+	p := NewWeChatMiniProgramProvider()
+	p.SetClientId("test_app")
+
+	if authURL := p.BuildAuthURL("test_state"); authURL != "" {
+		t.Fatalf("Expected empty auth url, got %q", authURL)
+	}
+}
+
+func TestWeChatMiniProgramFetchAuthUser(t *testing.T) {
+	server := newTestWeChatServer(t)
+
+	scenarios := []struct {
+		code        string
+		expectError bool
+		expectedId  string
+	}{
+		{"invalid", true, ""},
+		{"valid", false, "test_openid"},
+		{"valid_unionid", false, "test_unionid"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.code, func(t *testing.T) {
+			p := NewWeChatMiniProgramProvider()
+			p.SetClientId("test_app")
+			p.SetClientSecret("test_secret")
+			p.SetTokenURL(server.URL + "/sns/jscode2session")
+
+			token, err := p.FetchToken(s.code)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			user, err := p.FetchAuthUser(token)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if user.Id != s.expectedId {
+				t.Fatalf("Expected user id %q, got %q", s.expectedId, user.Id)
+			}
+
+			if user.AccessToken != "" {
+				t.Fatalf("Expected empty access token, got %q", user.AccessToken)
+			}
+
+			raw, err := json.Marshal(user)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if strings.Contains(string(raw), "test_session_key") {
+				t.Fatalf("The session_key must not be exposed, got %s", raw)
+			}
+		})
+	}
+}
@@ -0,0 +1,126 @@
+// Package captcha implements a minimal server-side verification of
+// the Cloudflare Turnstile, hCaptcha and Google reCAPTCHA response tokens.
+package captcha
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// The supported captcha providers.
+const (
+	ProviderTurnstile = "turnstile"
+	ProviderHCaptcha  = "hcaptcha"
+	ProviderReCAPTCHA = "recaptcha"
+)
+
+// ErrInvalidToken is returned when the captcha response token is missing or rejected by the provider.
+var ErrInvalidToken = errors.New("missing or invalid captcha token")
+
+var verifyURLs = map[string]string{
+	ProviderTurnstile: "https://challenges.cloudflare.com/turnstile/v0/siteverify",
+	ProviderHCaptcha:  "https://api.hcaptcha.com/siteverify",
+	ProviderReCAPTCHA: "https://www.google.com/recaptcha/api/siteverify",
+}
+
+var responseParams = map[string]string{
+	ProviderTurnstile: "cf-turnstile-response",
+	ProviderHCaptcha:  "h-captcha-response",
+	ProviderReCAPTCHA: "g-recaptcha-response",
+}
+
+// ResponseParam returns the default form parameter name in which
+// the provider client widget submits its response token
+// (e.g. "cf-turnstile-response").
+//
+// Returns empty string for unknown providers.
+func ResponseParam(provider string) string {
+	return responseParams[provider]
+}
+
+// Verifier defines a captcha response tokens verifier.
+//
+// All supported providers share the same "siteverify" API.
+type Verifier struct {
+	// Provider is the captcha provider ("turnstile", "hcaptcha" or "recaptcha").
+	Provider string
+
+	// Secret is the provider server-side secret key.
+	Secret string
+
+	// VerifyURL is an optional custom siteverify endpoint
+	// (default to the Provider one).
+	VerifyURL string
+
+	// HTTPClient is an optional custom HTTP client (default to a client with 10s timeout).
+	HTTPClient *http.Client
+}
+
+// Verify verifies the provided client response token.
+//
+// remoteIP is optional and it is forwarded to the provider as additional check.
+//
+// Returns [ErrInvalidToken] if the token is missing or rejected.
+func (v *Verifier) Verify(ctx context.Context, token string, remoteIP string) error {
+	if token == "" {
+		return ErrInvalidToken
+	}
+
+	endpoint := v.VerifyURL
+	if endpoint == "" {
+		endpoint = verifyURLs[v.Provider]
+	}
+	if endpoint == "" {
+		return fmt.Errorf("unsupported captcha provider %q", v.Provider)
+	}
+
+	form := url.Values{}
+	form.Set("secret", v.Secret)
+	form.Set("response", token)
+	if remoteIP != "" {
+		form.Set("remoteip", remoteIP)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	client := v.HTTPClient
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("failed to verify captcha token (%d): %s", res.StatusCode, body)
+	}
+
+	result := struct {
+		Success bool `json:"success"`
+	}{}
+
+	if err := json.NewDecoder(io.LimitReader(res.Body, 1<<20)).Decode(&result); err != nil {
+		return err
+	}
+
+	if !result.Success {
+		return ErrInvalidToken
+	}
+
+	return nil
+}
@@ -0,0 +1,97 @@
+package captcha_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/captcha"
+)
+
+func TestResponseParam(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		provider string
+		expected string
+	}{
+		{"", ""},
+		{"missing", ""},
+		{captcha.ProviderTurnstile, "cf-turnstile-response"},
+		{captcha.ProviderHCaptcha, "h-captcha-response"},
+		{captcha.ProviderReCAPTCHA, "g-recaptcha-response"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.provider, func(t *testing.T) {
+			if v := captcha.ResponseParam(s.provider); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestVerifierVerify(t *testing.T) {
+	t.Parallel()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			t.Error(err)
+		}
+
+		if v := r.PostForm.Get("secret"); v != "test_secret" {
+			t.Errorf("Expected secret %q, got %q", "test_secret", v)
+		}
+
+		switch r.PostForm.Get("response") {
+		case "valid":
+			if v := r.PostForm.Get("remoteip"); v != "1.2.3.4" {
+				t.Errorf("Expected remoteip %q, got %q", "1.2.3.4", v)
+			}
+			w.Write([]byte(`{"success":true}`))
+		case "error":
+			w.WriteHeader(http.StatusInternalServerError)
+		default:
+			w.Write([]byte(`{"success":false,"error-codes":["invalid-input-response"]}`))
+		}
+	}))
+	defer server.Close()
+
+	scenarios := []struct {
+		name          string
+		provider      string
+		verifyURL     string
+		token         string
+		expectError   bool
+		expectInvalid bool
+	}{
+		{"empty token", captcha.ProviderTurnstile, server.URL, "", true, true},
+		{"unknown provider without verify url", "missing", "", "valid", true, false},
+		{"rejected token", captcha.ProviderTurnstile, server.URL, "invalid", true, true},
+		{"provider error", captcha.ProviderHCaptcha, server.URL, "error", true, false},
+		{"valid token", captcha.ProviderReCAPTCHA, server.URL, "valid", false, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			v := &captcha.Verifier{
+				Provider:  s.provider,
+				Secret:    "test_secret",
+				VerifyURL: s.verifyURL,
+			}
+
+			err := v.Verify(context.Background(), s.token, "1.2.3.4")
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if isInvalid := errors.Is(err, captcha.ErrInvalidToken); isInvalid != s.expectInvalid {
+				t.Fatalf("Expected ErrInvalidToken %v, got %v (%v)", s.expectInvalid, isInvalid, err)
+			}
+		})
+	}
+}
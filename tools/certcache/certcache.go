@@ -0,0 +1,109 @@
+// Package certcache implements shared [autocert.Cache] backends that
+// allow multiple app instances (eg. behind a load balancer) to reuse
+// the same issued TLS certificates instead of each of them requesting
+// its own from the ACME CA.
+//
+// Example:
+//
+//	m := &autocert.Manager{
+//		Prompt: autocert.AcceptTOS,
+//		Cache:  certcache.NewFilesystem(app.NewFilesystem, ".autocert_cache"),
+//	}
+package certcache
+
+import (
+	"context"
+	"errors"
+	"io"
+	"path"
+
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+var _ autocert.Cache = (*Filesystem)(nil)
+
+// Filesystem is an [autocert.Cache] implementation that stores
+// the certificates in a [filesystem.System] (eg. S3 bucket).
+type Filesystem struct {
+	factory func() (*filesystem.System, error)
+	prefix  string
+}
+
+// NewFilesystem creates a new filesystem certificates cache.
+//
+// factory is called on each cache operation to initialize a new
+// filesystem instance (usually [core.App.NewFilesystem]) so that
+// the latest storage settings are always used.
+//
+// prefix is the directory in which the certificates will be stored.
+func NewFilesystem(factory func() (*filesystem.System, error), prefix string) *Filesystem {
+	return &Filesystem{
+		factory: factory,
+		prefix:  prefix,
+	}
+}
+
+// Get implements [autocert.Cache.Get] interface method.
+func (c *Filesystem) Get(ctx context.Context, key string) ([]byte, error) {
+	fsys, err := c.fsys(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer fsys.Close()
+
+	r, err := fsys.GetReader(c.fileKey(key))
+	if err != nil {
+		if errors.Is(err, filesystem.ErrNotFound) {
+			return nil, autocert.ErrCacheMiss
+		}
+		return nil, err
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}
+
+// Put implements [autocert.Cache.Put] interface method.
+func (c *Filesystem) Put(ctx context.Context, key string, data []byte) error {
+	fsys, err := c.fsys(ctx)
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	return fsys.Upload(data, c.fileKey(key))
+}
+
+// Delete implements [autocert.Cache.Delete] interface method.
+func (c *Filesystem) Delete(ctx context.Context, key string) error {
+	fsys, err := c.fsys(ctx)
+	if err != nil {
+		return err
+	}
+	defer fsys.Close()
+
+	err = fsys.Delete(c.fileKey(key))
+	if err != nil && !errors.Is(err, filesystem.ErrNotFound) {
+		return err
+	}
+
+	return nil
+}
+
+func (c *Filesystem) fsys(ctx context.Context) (*filesystem.System, error) {
+	fsys, err := c.factory()
+	if err != nil {
+		return nil, err
+	}
+
+	fsys.SetContext(ctx)
+
+	return fsys, nil
+}
+
+func (c *Filesystem) fileKey(key string) string {
+	// the autocert keys are domain names (optionally with "+rsa" or "+token" suffixes)
+	// but normalize them just in case to prevent writing outside of the prefix dir
+	return path.Join(c.prefix, path.Base(path.Clean("/"+key)))
+}
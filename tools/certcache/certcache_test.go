@@ -0,0 +1,117 @@
+package certcache_test
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/certcache"
+	"github.com/pocketbase/pocketbase/tools/filesystem"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+func TestFilesystem(t *testing.T) {
+	dir := t.TempDir()
+
+	factoryCalls := 0
+	factory := func() (*filesystem.System, error) {
+		factoryCalls++
+		return filesystem.NewLocal(dir)
+	}
+
+	c := certcache.NewFilesystem(factory, "certs")
+
+	testCache(t, c)
+
+	if factoryCalls == 0 {
+		t.Fatal("Expected the filesystem factory to be called")
+	}
+
+	// the cache keys should be stored only inside the prefix dir
+	if err := c.Put(context.Background(), "../../test.com", []byte("test")); err != nil {
+		t.Fatal(err)
+	}
+	fsys, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+	if exists, _ := fsys.Exists(filepath.Join("certs", "test.com")); !exists {
+		t.Fatal("Expected the certs/test.com file to exist")
+	}
+}
+
+func TestFilesystemFactoryError(t *testing.T) {
+	c := certcache.NewFilesystem(func() (*filesystem.System, error) {
+		return nil, errors.New("test")
+	}, "certs")
+
+	if _, err := c.Get(context.Background(), "example.com"); err == nil || errors.Is(err, autocert.ErrCacheMiss) {
+		t.Fatalf("Expected factory error, got %v", err)
+	}
+
+	if err := c.Put(context.Background(), "example.com", []byte("test")); err == nil {
+		t.Fatal("Expected factory error")
+	}
+
+	if err := c.Delete(context.Background(), "example.com"); err == nil {
+		t.Fatal("Expected factory error")
+	}
+}
+
+// testCache runs common checks for the specified cache implementation.
+func testCache(t *testing.T, c autocert.Cache) {
+	ctx := context.Background()
+
+	if _, err := c.Get(ctx, "example.com"); !errors.Is(err, autocert.ErrCacheMiss) {
+		t.Fatalf("Expected ErrCacheMiss, got %v", err)
+	}
+
+	// deleting missing key shouldn't fail
+	if err := c.Delete(ctx, "example.com"); err != nil {
+		t.Fatalf("Expected nil delete error, got %v", err)
+	}
+
+	if err := c.Put(ctx, "example.com", []byte("test1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Put(ctx, "example.com+rsa", []byte("test2")); err != nil {
+		t.Fatal(err)
+	}
+
+	// overwrite
+	if err := c.Put(ctx, "example.com", []byte("test3")); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := c.Get(ctx, "example.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "test3" {
+		t.Fatalf("Expected %q, got %q", "test3", data)
+	}
+
+	data, err = c.Get(ctx, "example.com+rsa")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "test2" {
+		t.Fatalf("Expected %q, got %q", "test2", data)
+	}
+
+	if err := c.Delete(ctx, "example.com"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := c.Get(ctx, "example.com"); !errors.Is(err, autocert.ErrCacheMiss) {
+		t.Fatalf("Expected ErrCacheMiss after delete, got %v", err)
+	}
+
+	// the other key should remain
+	if _, err := c.Get(ctx, "example.com+rsa"); err != nil {
+		t.Fatalf("Expected example.com+rsa to remain, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+// Package collation implements unicode aware string comparison
+// and normalization helpers suitable for custom SQLite collations.
+package collation
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NFC returns the Unicode Normalization Form C of s
+// (aka. canonically composed characters).
+func NFC(s string) string {
+	return norm.NFC.String(s)
+}
+
+// NoCase compares a and b case-insensitively using the full Unicode case folding.
+//
+// The result is 0 if a == b, -1 if a < b, and +1 if a > b.
+func NoCase(a, b string) int {
+	if isASCII(a) && isASCII(b) {
+		return compareASCIIFold(a, b)
+	}
+
+	return strings.Compare(NoCaseKey(a), NoCaseKey(b))
+}
+
+// NoAccent compares a and b case and diacritic insensitively
+// (e.g. "Élan" == "elan").
+//
+// The result is 0 if a == b, -1 if a < b, and +1 if a > b.
+func NoAccent(a, b string) int {
+	if isASCII(a) && isASCII(b) {
+		return compareASCIIFold(a, b)
+	}
+
+	return strings.Compare(NoAccentKey(a), NoAccentKey(b))
+}
+
+// NoCaseKey returns the NFC normalized and case folded form of s
+// that is used for the [NoCase] comparison.
+func NoCaseKey(s string) string {
+	return cases.Fold().String(norm.NFC.String(s))
+}
+
+// NoAccentKey returns the case folded form of s without the combining
+// diacritical marks that is used for the [NoAccent] comparison.
+func NoAccentKey(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+
+	result, _, err := transform.String(t, s)
+	if err != nil {
+		result = s
+	}
+
+	return cases.Fold().String(result)
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+
+	return true
+}
+
+// compareASCIIFold compares two ASCII strings without allocations
+// as if they were lowercased.
+func compareASCIIFold(a, b string) int {
+	n := min(len(a), len(b))
+
+	for i := 0; i < n; i++ {
+		ca, cb := lowerASCII(a[i]), lowerASCII(b[i])
+		if ca < cb {
+			return -1
+		}
+		if ca > cb {
+			return 1
+		}
+	}
+
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	default:
+		return 0
+	}
+}
+
+func lowerASCII(c byte) byte {
+	if 'A' <= c && c <= 'Z' {
+		return c + ('a' - 'A')
+	}
+
+	return c
+}
@@ -0,0 +1,110 @@
+package collation_test
+
+import (
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/collation"
+)
+
+func TestNFC(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"abc", "abc"},
+		{"e\u0301", "\u00e9"},           // decomposed é
+		{"\u00e9", "\u00e9"},            // already composed
+		{"A\u030angstr", "\u00c5ngstr"}, // decomposed Å
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			if v := collation.NFC(s.value); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestNoCase(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "ABC", 0},
+		{"abc", "abd", -1},
+		{"ABD", "abc", 1},
+		{"ab", "abc", -1},
+		{"abc", "AB", 1},
+		{"ÉCOLE", "école", 0},
+		{"école", "ÉCOLE", 0},
+		{"straße", "STRASSE", 0},
+		{"école", "ecole", 1},
+		{"ecole", "école", -1},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.a+"_"+s.b, func(t *testing.T) {
+			if v := collation.NoCase(s.a, s.b); v != s.expected {
+				t.Fatalf("Expected %d, got %d", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestNoAccent(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		a        string
+		b        string
+		expected int
+	}{
+		{"", "", 0},
+		{"abc", "ABC", 0},
+		{"abc", "abd", -1},
+		{"ÉCOLE", "ecole", 0},
+		{"école", "Ecole", 0},
+		{"Crème Brûlée", "creme brulee", 0},
+		{"naïve", "naivf", -1},
+		{"Zürich", "zurich", 0},
+		{"Zürich", "zurica", 1},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.a+"_"+s.b, func(t *testing.T) {
+			if v := collation.NoAccent(s.a, s.b); v != s.expected {
+				t.Fatalf("Expected %d, got %d", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestNoAccentKey(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"ABC", "abc"},
+		{"Crème Brûlée", "creme brulee"},
+		{"Ångström", "angstrom"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			if v := collation.NoAccentKey(s.value); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,208 @@
+// Package feed implements minimal RSS 2.0 and Atom 1.0 feed encoders.
+package feed
+
+import (
+	"encoding/xml"
+	"io"
+	"time"
+)
+
+// Feed content types.
+const (
+	RSSContentType  = "application/rss+xml; charset=utf-8"
+	AtomContentType = "application/atom+xml; charset=utf-8"
+)
+
+// Feed defines a single syndication feed.
+type Feed struct {
+	// Id is the permanent feed identifier (used only by Atom).
+	Id string
+
+	Title       string
+	Link        string
+	Description string
+	Updated     time.Time
+
+	Items []Item
+}
+
+// Item defines a single feed entry.
+type Item struct {
+	// Id is the permanent entry identifier (RSS guid and Atom id).
+	Id string
+
+	Title string
+
+	// Link is the optional entry url.
+	Link string
+
+	// Content is the optional entry HTML content.
+	Content string
+
+	// Author is the optional entry author name.
+	Author string
+
+	Published time.Time
+	Updated   time.Time
+}
+
+// EncodeRSS writes the feed as RSS 2.0 document into w.
+func (f *Feed) EncodeRSS(w io.Writer) error {
+	channel := rssChannel{
+		Title:         f.Title,
+		Link:          f.Link,
+		Description:   f.Description,
+		LastBuildDate: formatRSSDate(f.Updated),
+		Items:         make([]rssItem, 0, len(f.Items)),
+	}
+
+	for _, item := range f.Items {
+		channel.Items = append(channel.Items, rssItem{
+			Title:       item.Title,
+			Link:        item.Link,
+			Description: item.Content,
+			Author:      item.Author,
+			Guid:        rssGuid{Value: item.Id, IsPermaLink: "false"},
+			PubDate:     formatRSSDate(item.Published),
+		})
+	}
+
+	return encode(w, rss{Version: "2.0", Channel: channel})
+}
+
+// EncodeAtom writes the feed as Atom 1.0 document into w.
+func (f *Feed) EncodeAtom(w io.Writer) error {
+	doc := atom{
+		Xmlns:   "http://www.w3.org/2005/Atom",
+		Id:      f.Id,
+		Title:   f.Title,
+		Updated: formatAtomDate(f.Updated),
+		Entries: make([]atomEntry, 0, len(f.Items)),
+	}
+
+	if f.Description != "" {
+		doc.Subtitle = f.Description
+	}
+
+	if f.Link != "" {
+		doc.Links = []atomLink{{Href: f.Link, Rel: "alternate"}}
+	}
+
+	for _, item := range f.Items {
+		entry := atomEntry{
+			Id:        item.Id,
+			Title:     item.Title,
+			Published: formatAtomDate(item.Published),
+			Updated:   formatAtomDate(item.Updated),
+		}
+
+		if item.Updated.IsZero() {
+			entry.Updated = entry.Published
+		}
+
+		if item.Link != "" {
+			entry.Links = []atomLink{{Href: item.Link, Rel: "alternate"}}
+		}
+
+		if item.Content != "" {
+			entry.Content = &atomContent{Type: "html", Value: item.Content}
+		}
+
+		if item.Author != "" {
+			entry.Author = &atomAuthor{Name: item.Author}
+		}
+
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	return encode(w, doc)
+}
+
+func encode(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	return xml.NewEncoder(w).Encode(v)
+}
+
+func formatRSSDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC1123Z)
+}
+
+func formatAtomDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// -------------------------------------------------------------------
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	Description string  `xml:"description,omitempty"`
+	Author      string  `xml:"author,omitempty"`
+	Guid        rssGuid `xml:"guid"`
+	PubDate     string  `xml:"pubDate,omitempty"`
+}
+
+type rssGuid struct {
+	Value       string `xml:",chardata"`
+	IsPermaLink string `xml:"isPermaLink,attr"`
+}
+
+type atom struct {
+	XMLName  xml.Name    `xml:"feed"`
+	Xmlns    string      `xml:"xmlns,attr"`
+	Id       string      `xml:"id"`
+	Title    string      `xml:"title"`
+	Subtitle string      `xml:"subtitle,omitempty"`
+	Updated  string      `xml:"updated"`
+	Links    []atomLink  `xml:"link"`
+	Entries  []atomEntry `xml:"entry"`
+}
+
+type atomEntry struct {
+	Id        string       `xml:"id"`
+	Title     string       `xml:"title"`
+	Links     []atomLink   `xml:"link"`
+	Published string       `xml:"published,omitempty"`
+	Updated   string       `xml:"updated"`
+	Author    *atomAuthor  `xml:"author"`
+	Content   *atomContent `xml:"content"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomContent struct {
+	Type  string `xml:"type,attr"`
+	Value string `xml:",chardata"`
+}
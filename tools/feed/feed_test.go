@@ -0,0 +1,88 @@
+package feed_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/feed"
+)
+
+func testFeed() *feed.Feed {
+	return &feed.Feed{
+		Id:          "https://example.com/feed",
+		Title:       "Test & feed",
+		Link:        "https://example.com",
+		Description: "Test description",
+		Updated:     time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+		Items: []feed.Item{
+			{
+				Id:        "item1",
+				Title:     "Item 1",
+				Link:      "https://example.com/item1",
+				Content:   "<p>Content 1</p>",
+				Author:    "test",
+				Published: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+				Updated:   time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+			},
+			{
+				Id:        "item2",
+				Title:     "Item 2",
+				Published: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			},
+		},
+	}
+}
+
+func TestFeedEncodeRSS(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := testFeed().EncodeRSS(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<rss version="2.0"><channel>` +
+		`<title>Test &amp; feed</title>` +
+		`<link>https://example.com</link>` +
+		`<description>Test description</description>` +
+		`<lastBuildDate>Wed, 03 Jan 2024 10:00:00 +0000</lastBuildDate>` +
+		`<item><title>Item 1</title><link>https://example.com/item1</link>` +
+		`<description>&lt;p&gt;Content 1&lt;/p&gt;</description><author>test</author>` +
+		`<guid isPermaLink="false">item1</guid><pubDate>Tue, 02 Jan 2024 10:00:00 +0000</pubDate></item>` +
+		`<item><title>Item 2</title><guid isPermaLink="false">item2</guid>` +
+		`<pubDate>Mon, 01 Jan 2024 10:00:00 +0000</pubDate></item>` +
+		`</channel></rss>`
+
+	if v := buf.String(); v != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, v)
+	}
+}
+
+func TestFeedEncodeAtom(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	if err := testFeed().EncodeAtom(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<feed xmlns="http://www.w3.org/2005/Atom">` +
+		`<id>https://example.com/feed</id>` +
+		`<title>Test &amp; feed</title>` +
+		`<subtitle>Test description</subtitle>` +
+		`<updated>2024-01-03T10:00:00Z</updated>` +
+		`<link href="https://example.com" rel="alternate"></link>` +
+		`<entry><id>item1</id><title>Item 1</title><link href="https://example.com/item1" rel="alternate"></link>` +
+		`<published>2024-01-02T10:00:00Z</published><updated>2024-01-03T10:00:00Z</updated>` +
+		`<author><name>test</name></author><content type="html">&lt;p&gt;Content 1&lt;/p&gt;</content></entry>` +
+		`<entry><id>item2</id><title>Item 2</title>` +
+		`<published>2024-01-01T10:00:00Z</published><updated>2024-01-01T10:00:00Z</updated></entry>` +
+		`</feed>`
+
+	if v := buf.String(); v != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, v)
+	}
+}
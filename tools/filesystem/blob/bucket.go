@@ -536,6 +536,12 @@ type WriterOptions struct {
 	// Duplicate case-insensitive keys (e.g., "foo" and "FOO") will result in
 	// an error.
 	Metadata map[string]string
+
+	// Tags holds key/value strings to be associated with the blob as
+	// object tags (e.g. to be matched by a bucket lifecycle rule), or nil.
+	//
+	// This option may be ignored by some drivers.
+	Tags map[string]string
 }
 
 // NewWriter returns a Writer that writes to the blob stored at key.
@@ -569,6 +575,7 @@ func (b *Bucket) NewWriter(ctx context.Context, key string, opts *WriterOptions)
 		BufferSize:                  opts.BufferSize,
 		MaxConcurrency:              opts.MaxConcurrency,
 		DisableContentTypeDetection: opts.DisableContentTypeDetection,
+		Tags:                        opts.Tags,
 	}
 
 	if len(opts.Metadata) > 0 {
@@ -1,6 +1,7 @@
 package filesystem
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"image"
@@ -11,6 +12,7 @@ import (
 	"path"
 	"path/filepath"
 	"regexp"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
@@ -31,11 +33,26 @@ import (
 // note: the same as blob.ErrNotFound for backward compatibility with earlier versions
 var ErrNotFound = blob.ErrNotFound
 
+// ErrLifecycleUnsupported is returned when trying to manage the
+// bucket lifecycle rules of a non-S3 filesystem.
+var ErrLifecycleUnsupported = errors.New("the bucket lifecycle rules are supported only by the S3 filesystem")
+
 const metadataOriginalName = "original-filename"
 
+const (
+	// ThumbObjectTagKey and ThumbObjectTagValue define the object tag
+	// assigned to the generated thumbs (used to match the thumbs lifecycle rule).
+	ThumbObjectTagKey   = "pb-thumb"
+	ThumbObjectTagValue = "true"
+
+	// ThumbsLifecycleRuleId is the id of the bucket lifecycle rule that expires the generated thumbs.
+	ThumbsLifecycleRuleId = "pocketbase-thumbs-expiration"
+)
+
 type System struct {
 	ctx    context.Context
 	bucket *blob.Bucket
+	s3     *s3.S3 // nil for the local filesystem
 }
 
 // NewS3 initializes an S3 filesystem instance.
@@ -65,7 +82,7 @@ func NewS3(
 		return nil, err
 	}
 
-	return &System{ctx: ctx, bucket: blob.NewBucket(drv)}, nil
+	return &System{ctx: ctx, bucket: blob.NewBucket(drv), s3: client}, nil
 }
 
 // NewLocal initializes a new local filesystem instance.
@@ -214,6 +231,40 @@ func (s *System) Upload(content []byte, fileKey string) error {
 	return w.Close()
 }
 
+// UploadReader streams the content of the provided reader into the fileKey location.
+//
+// If reading from r fails, the upload is aborted and the read error is returned.
+func (s *System) UploadReader(r io.Reader, fileKey string) error {
+	// read only the content head for the mimetype detection
+	head := make([]byte, 3072)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	head = head[:n]
+
+	opts := &blob.WriterOptions{
+		ContentType: mimetype.Detect(head).String(),
+	}
+
+	// cancel the writer context on read error to abort the upload
+	ctx, cancel := context.WithCancel(s.ctx)
+	defer cancel()
+
+	w, err := s.bucket.NewWriter(ctx, fileKey, opts)
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.ReadFrom(io.MultiReader(bytes.NewReader(head), r)); err != nil {
+		cancel()
+		w.Close()
+		return err
+	}
+
+	return w.Close()
+}
+
 // UploadFile uploads the provided File to the fileKey location.
 func (s *System) UploadFile(file *File, fileKey string) error {
 	f, err := file.Reader.Open()
@@ -538,6 +589,7 @@ func (s *System) CreateThumb(originalKey string, thumbKey, thumbSize string) err
 
 	opts := &blob.WriterOptions{
 		ContentType: r.ContentType(),
+		Tags:        map[string]string{ThumbObjectTagKey: ThumbObjectTagValue},
 	}
 
 	// open a thumb storage writer (aka. prepare for upload)
@@ -562,3 +614,44 @@ func (s *System) CreateThumb(originalKey string, thumbKey, thumbSize string) err
 	// check for close errors to ensure that the thumb was really saved
 	return w.Close()
 }
+
+// SetThumbsExpiration creates or updates the bucket lifecycle rule that
+// expires (aka. deletes) the generated thumbs days after their creation.
+//
+// If days is <= 0, the thumbs lifecycle rule is removed.
+// The other existing bucket lifecycle rules are preserved.
+//
+// Note that only the thumbs created with an object tag are matched by the rule
+// (the thumbs generated prior to v0.31.0 are left untouched).
+//
+// Returns [ErrLifecycleUnsupported] for the local filesystem.
+func (s *System) SetThumbsExpiration(days int) error {
+	if s.s3 == nil {
+		return ErrLifecycleUnsupported
+	}
+
+	rules, err := s.s3.GetBucketLifecycleRules(s.ctx)
+	if err != nil {
+		return err
+	}
+
+	total := len(rules)
+
+	rules = slices.DeleteFunc(rules, func(r s3.LifecycleRule) bool {
+		return r.ID == ThumbsLifecycleRuleId
+	})
+
+	if days <= 0 && len(rules) == total {
+		return nil // nothing to remove
+	}
+
+	if days > 0 {
+		rule, err := s3.NewTagExpirationLifecycleRule(ThumbsLifecycleRuleId, ThumbObjectTagKey, ThumbObjectTagValue, days)
+		if err != nil {
+			return err
+		}
+		rules = append(rules, rule)
+	}
+
+	return s.s3.PutBucketLifecycleRules(s.ctx, rules)
+}
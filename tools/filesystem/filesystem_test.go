@@ -14,6 +14,7 @@ import (
 	"path/filepath"
 	"strings"
 	"testing"
+	"testing/iotest"
 
 	"github.com/gabriel-vasile/mimetype"
 	"github.com/pocketbase/pocketbase/tools/filesystem"
@@ -327,6 +328,56 @@ func TestFileSystemUpload(t *testing.T) {
 	}
 }
 
+func TestFileSystemUploadReader(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fsys, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	t.Run("successful upload", func(t *testing.T) {
+		fileKey := "newdir/newkey.txt"
+
+		uploadErr := fsys.UploadReader(strings.NewReader("demo"), fileKey)
+		if uploadErr != nil {
+			t.Fatal(uploadErr)
+		}
+
+		attrs, err := fsys.Attributes(fileKey)
+		if err != nil {
+			t.Fatalf("Failed to fetch file attributes: %v", err)
+		}
+
+		if attrs.Size != 4 {
+			t.Fatalf("Expected size %d, got %d", 4, attrs.Size)
+		}
+
+		if !strings.HasPrefix(attrs.ContentType, "text/plain") {
+			t.Fatalf("Expected text/plain content type, got %q", attrs.ContentType)
+		}
+	})
+
+	t.Run("aborted upload on read error", func(t *testing.T) {
+		fileKey := "newdir/aborted.txt"
+
+		readErr := errors.New("test")
+
+		r := io.MultiReader(strings.NewReader(strings.Repeat("a", 5000)), iotest.ErrReader(readErr))
+
+		uploadErr := fsys.UploadReader(r, fileKey)
+		if !errors.Is(uploadErr, readErr) {
+			t.Fatalf("Expected the read error, got %v", uploadErr)
+		}
+
+		if exists, _ := fsys.Exists(fileKey); exists {
+			t.Fatalf("Expected %q to not exist", fileKey)
+		}
+	})
+}
+
 func TestFileSystemServe(t *testing.T) {
 	dir := createTestDir(t)
 	defer os.RemoveAll(dir)
@@ -896,6 +947,22 @@ func TestFileSystemCreateThumb(t *testing.T) {
 	}
 }
 
+func TestFileSystemSetThumbsExpirationLocal(t *testing.T) {
+	dir := createTestDir(t)
+	defer os.RemoveAll(dir)
+
+	fsys, err := filesystem.NewLocal(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer fsys.Close()
+
+	err = fsys.SetThumbsExpiration(1)
+	if !errors.Is(err, filesystem.ErrLifecycleUnsupported) {
+		t.Fatalf("Expected ErrLifecycleUnsupported, got %v", err)
+	}
+}
+
 // ---
 
 func createTestDir(t *testing.T) string {
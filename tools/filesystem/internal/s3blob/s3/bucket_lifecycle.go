@@ -0,0 +1,166 @@
+package s3
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// LifecycleRule defines a single bucket lifecycle configuration rule.
+//
+// Only the rule ID is parsed and the rest of the rule definition is kept
+// as raw XML so that the existing rules could be preserved on update.
+type LifecycleRule struct {
+	// ID is the unique rule identifier.
+	ID string
+
+	// Raw is the raw inner rule XML (including the ID element).
+	Raw string
+}
+
+// NewTagExpirationLifecycleRule creates a new LifecycleRule that expires
+// (aka. deletes) the objects with the specified tag days after their creation.
+//
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/intro-lifecycle-rules.html
+func NewTagExpirationLifecycleRule(id string, tagKey string, tagValue string, days int) (LifecycleRule, error) {
+	rule := struct {
+		XMLName xml.Name `xml:"Rule"`
+		ID      string   `xml:"ID"`
+		Filter  struct {
+			Tag struct {
+				Key   string `xml:"Key"`
+				Value string `xml:"Value"`
+			} `xml:"Tag"`
+		} `xml:"Filter"`
+		Status     string `xml:"Status"`
+		Expiration struct {
+			Days int `xml:"Days"`
+		} `xml:"Expiration"`
+	}{
+		ID:     id,
+		Status: "Enabled",
+	}
+	rule.Filter.Tag.Key = tagKey
+	rule.Filter.Tag.Value = tagValue
+	rule.Expiration.Days = days
+
+	raw, err := xml.Marshal(rule)
+	if err != nil {
+		return LifecycleRule{}, err
+	}
+
+	inner := strings.TrimSuffix(strings.TrimPrefix(string(raw), "<Rule>"), "</Rule>")
+
+	return LifecycleRule{ID: id, Raw: inner}, nil
+}
+
+// GetBucketLifecycleRules returns the rules of the bucket lifecycle configuration.
+//
+// Returns an empty slice if the bucket doesn't have a lifecycle configuration.
+//
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_GetBucketLifecycleConfiguration.html
+func (s3 *S3) GetBucketLifecycleRules(ctx context.Context, optFuncs ...func(*http.Request)) ([]LifecycleRule, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s3.URL("?lifecycle"), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// apply optional request funcs
+	for _, fn := range optFuncs {
+		if fn != nil {
+			fn(req)
+		}
+	}
+
+	resp, err := s3.SignAndSend(req)
+	if err != nil {
+		var respErr *ResponseError
+		if errors.As(err, &respErr) && respErr.Code == "NoSuchLifecycleConfiguration" {
+			return []LifecycleRule{}, nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	config := struct {
+		XMLName xml.Name `xml:"LifecycleConfiguration"`
+		Rules   []struct {
+			ID  string `xml:"ID"`
+			Raw string `xml:",innerxml"`
+		} `xml:"Rule"`
+	}{}
+	if err := xml.Unmarshal(body, &config); err != nil {
+		return nil, err
+	}
+
+	rules := make([]LifecycleRule, len(config.Rules))
+	for i, r := range config.Rules {
+		rules[i] = LifecycleRule{ID: r.ID, Raw: r.Raw}
+	}
+
+	return rules, nil
+}
+
+// PutBucketLifecycleRules replaces the bucket lifecycle configuration with the provided rules.
+//
+// If rules is empty, the bucket lifecycle configuration is deleted.
+//
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_PutBucketLifecycleConfiguration.html
+func (s3 *S3) PutBucketLifecycleRules(ctx context.Context, rules []LifecycleRule, optFuncs ...func(*http.Request)) error {
+	var req *http.Request
+	var err error
+
+	if len(rules) == 0 {
+		// https://docs.aws.amazon.com/AmazonS3/latest/API/API_DeleteBucketLifecycle.html
+		req, err = http.NewRequestWithContext(ctx, http.MethodDelete, s3.URL("?lifecycle"), nil)
+		if err != nil {
+			return err
+		}
+	} else {
+		var payload bytes.Buffer
+		payload.WriteString(xml.Header)
+		payload.WriteString(`<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">`)
+		for _, r := range rules {
+			payload.WriteString("<Rule>")
+			payload.WriteString(r.Raw)
+			payload.WriteString("</Rule>")
+		}
+		payload.WriteString("</LifecycleConfiguration>")
+
+		// the Content-MD5 header is required for the lifecycle configuration requests
+		hash := md5.Sum(payload.Bytes())
+
+		req, err = http.NewRequestWithContext(ctx, http.MethodPut, s3.URL("?lifecycle"), bytes.NewReader(payload.Bytes()))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-MD5", base64.StdEncoding.EncodeToString(hash[:]))
+		req.Header.Set("Content-Type", "application/xml")
+	}
+
+	// apply optional request funcs
+	for _, fn := range optFuncs {
+		if fn != nil {
+			fn(req)
+		}
+	}
+
+	resp, err := s3.SignAndSend(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return nil
+}
@@ -0,0 +1,230 @@
+package s3_test
+
+import (
+	"context"
+	"crypto/md5"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/filesystem/internal/s3blob/s3"
+	"github.com/pocketbase/pocketbase/tools/filesystem/internal/s3blob/s3/tests"
+)
+
+func TestNewTagExpirationLifecycleRule(t *testing.T) {
+	t.Parallel()
+
+	rule, err := s3.NewTagExpirationLifecycleRule("test_id", "test_key", "test_value", 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if rule.ID != "test_id" {
+		t.Fatalf("Expected ID %q, got %q", "test_id", rule.ID)
+	}
+
+	expectedRaw := `<ID>test_id</ID><Filter><Tag><Key>test_key</Key><Value>test_value</Value></Tag></Filter><Status>Enabled</Status><Expiration><Days>3</Days></Expiration>`
+	if rule.Raw != expectedRaw {
+		t.Fatalf("Expected Raw\n%s\ngot\n%s", expectedRaw, rule.Raw)
+	}
+}
+
+func TestS3GetBucketLifecycleRules(t *testing.T) {
+	t.Parallel()
+
+	httpClient := tests.NewClient(
+		&tests.RequestStub{
+			Method: http.MethodGet,
+			URL:    "http://test_bucket.example.com/?lifecycle",
+			Match: func(req *http.Request) bool {
+				return tests.ExpectHeaders(req.Header, map[string]string{
+					"test_header":   "test",
+					"Authorization": "^.+Credential=123/.+$",
+				})
+			},
+			Response: &http.Response{
+				Body: io.NopCloser(strings.NewReader(`
+					<?xml version="1.0" encoding="UTF-8"?>
+					<LifecycleConfiguration>
+						<Rule><ID>rule1</ID><Filter><Prefix>a/</Prefix></Filter><Status>Enabled</Status></Rule>
+						<Rule><ID>rule2</ID><Status>Disabled</Status></Rule>
+					</LifecycleConfiguration>
+				`)),
+			},
+		},
+	)
+
+	s3Client := &s3.S3{
+		Client:    httpClient,
+		Region:    "test_region",
+		Bucket:    "test_bucket",
+		Endpoint:  "http://example.com",
+		AccessKey: "123",
+		SecretKey: "abc",
+	}
+
+	rules, err := s3Client.GetBucketLifecycleRules(context.Background(), func(r *http.Request) {
+		r.Header.Set("test_header", "test")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = httpClient.AssertNoRemaining()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []s3.LifecycleRule{
+		{ID: "rule1", Raw: `<ID>rule1</ID><Filter><Prefix>a/</Prefix></Filter><Status>Enabled</Status>`},
+		{ID: "rule2", Raw: `<ID>rule2</ID><Status>Disabled</Status>`},
+	}
+
+	if len(rules) != len(expected) {
+		t.Fatalf("Expected %d rules, got %d: %v", len(expected), len(rules), rules)
+	}
+
+	for i, r := range rules {
+		if r != expected[i] {
+			t.Fatalf("[%d] Expected rule %v, got %v", i, expected[i], r)
+		}
+	}
+}
+
+func TestS3GetBucketLifecycleRulesMissing(t *testing.T) {
+	t.Parallel()
+
+	httpClient := tests.NewClient(
+		&tests.RequestStub{
+			Method: http.MethodGet,
+			URL:    "http://test_bucket.example.com/?lifecycle",
+			Response: &http.Response{
+				StatusCode: 404,
+				Body: io.NopCloser(strings.NewReader(`
+					<?xml version="1.0" encoding="UTF-8"?>
+					<Error>
+						<Code>NoSuchLifecycleConfiguration</Code>
+						<Message>The lifecycle configuration does not exist</Message>
+					</Error>
+				`)),
+			},
+		},
+	)
+
+	s3Client := &s3.S3{
+		Client:    httpClient,
+		Region:    "test_region",
+		Bucket:    "test_bucket",
+		Endpoint:  "http://example.com",
+		AccessKey: "123",
+		SecretKey: "abc",
+	}
+
+	rules, err := s3Client.GetBucketLifecycleRules(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = httpClient.AssertNoRemaining()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(rules) != 0 {
+		t.Fatalf("Expected no rules, got %v", rules)
+	}
+}
+
+func TestS3PutBucketLifecycleRules(t *testing.T) {
+	t.Parallel()
+
+	expectedBody := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<LifecycleConfiguration xmlns="http://s3.amazonaws.com/doc/2006-03-01/">` +
+		`<Rule><ID>rule1</ID><Status>Enabled</Status></Rule>` +
+		`<Rule><ID>rule2</ID><Status>Disabled</Status></Rule>` +
+		`</LifecycleConfiguration>`
+
+	expectedHash := md5.Sum([]byte(expectedBody))
+
+	httpClient := tests.NewClient(
+		&tests.RequestStub{
+			Method: http.MethodPut,
+			URL:    "http://test_bucket.example.com/?lifecycle",
+			Match: func(req *http.Request) bool {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return false
+				}
+
+				return string(body) == expectedBody && tests.ExpectHeaders(req.Header, map[string]string{
+					"Content-MD5":   base64.StdEncoding.EncodeToString(expectedHash[:]),
+					"test_header":   "test",
+					"Authorization": "^.+Credential=123/.+$",
+				})
+			},
+		},
+	)
+
+	s3Client := &s3.S3{
+		Client:    httpClient,
+		Region:    "test_region",
+		Bucket:    "test_bucket",
+		Endpoint:  "http://example.com",
+		AccessKey: "123",
+		SecretKey: "abc",
+	}
+
+	rules := []s3.LifecycleRule{
+		{ID: "rule1", Raw: `<ID>rule1</ID><Status>Enabled</Status>`},
+		{ID: "rule2", Raw: `<ID>rule2</ID><Status>Disabled</Status>`},
+	}
+
+	err := s3Client.PutBucketLifecycleRules(context.Background(), rules, func(r *http.Request) {
+		r.Header.Set("test_header", "test")
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = httpClient.AssertNoRemaining()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestS3PutBucketLifecycleRulesEmpty(t *testing.T) {
+	t.Parallel()
+
+	httpClient := tests.NewClient(
+		&tests.RequestStub{
+			Method: http.MethodDelete,
+			URL:    "http://test_bucket.example.com/?lifecycle",
+			Match: func(req *http.Request) bool {
+				return tests.ExpectHeaders(req.Header, map[string]string{
+					"Authorization": "^.+Credential=123/.+$",
+				})
+			},
+		},
+	)
+
+	s3Client := &s3.S3{
+		Client:    httpClient,
+		Region:    "test_region",
+		Bucket:    "test_bucket",
+		Endpoint:  "http://example.com",
+		AccessKey: "123",
+		SecretKey: "abc",
+	}
+
+	err := s3Client.PutBucketLifecycleRules(context.Background(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = httpClient.AssertNoRemaining()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
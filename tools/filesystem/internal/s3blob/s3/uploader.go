@@ -41,6 +41,10 @@ type Uploader struct {
 	// Metadata specifies the optional metadata to write with the object upload.
 	Metadata map[string]string
 
+	// Tags specifies the optional object tags to write with the object upload
+	// (e.g. to be matched by a bucket lifecycle rule).
+	Tags map[string]string
+
 	// MaxConcurrency specifies the max number of workers to use when
 	// performing chunked/multipart upload.
 	//
@@ -153,6 +157,8 @@ func (u *Uploader) singleUpload(ctx context.Context, part []byte, optReqFuncs ..
 		req.Header.Set(metadataPrefix+k, v)
 	}
 
+	u.setTagging(req)
+
 	// apply optional request funcs
 	for _, fn := range optReqFuncs {
 		if fn != nil {
@@ -169,6 +175,20 @@ func (u *Uploader) singleUpload(ctx context.Context, part []byte, optReqFuncs ..
 	return nil
 }
 
+// setTagging sets the object tagging request header (if there are tags).
+func (u *Uploader) setTagging(req *http.Request) {
+	if len(u.Tags) == 0 {
+		return
+	}
+
+	tags := url.Values{}
+	for k, v := range u.Tags {
+		tags.Set(k, v)
+	}
+
+	req.Header.Set("x-amz-tagging", tags.Encode())
+}
+
 // -------------------------------------------------------------------
 
 type mpPart struct {
@@ -192,6 +212,8 @@ func (u *Uploader) multipartInit(ctx context.Context, optReqFuncs ...func(*http.
 		req.Header.Set(metadataPrefix+k, v)
 	}
 
+	u.setTagging(req)
+
 	// apply optional request funcs
 	for _, fn := range optReqFuncs {
 		if fn != nil {
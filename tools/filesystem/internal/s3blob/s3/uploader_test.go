@@ -86,6 +86,7 @@ func TestUploaderSingleUpload(t *testing.T) {
 					"Content-Length": "7",
 					"x-amz-meta-a":   "123",
 					"x-amz-meta-b":   "456",
+					"x-amz-tagging":  "tag_a=1&tag_b=2",
 					"test_header":    "test",
 					"Authorization":  "^.+Credential=123/.+$",
 				})
@@ -105,6 +106,7 @@ func TestUploaderSingleUpload(t *testing.T) {
 		Key:         "test_key",
 		Payload:     strings.NewReader("abcdefg"),
 		Metadata:    map[string]string{"a": "123", "b": "456"},
+		Tags:        map[string]string{"tag_b": "2", "tag_a": "1"},
 		MinPartSize: 8,
 	}
 
@@ -132,6 +134,7 @@ func TestUploaderMultipartUploadSuccess(t *testing.T) {
 				return tests.ExpectHeaders(req.Header, map[string]string{
 					"x-amz-meta-a":  "123",
 					"x-amz-meta-b":  "456",
+					"x-amz-tagging": "tag_a=1&tag_b=2",
 					"test_header":   "test",
 					"Authorization": "^.+Credential=123/.+$",
 				})
@@ -234,6 +237,7 @@ func TestUploaderMultipartUploadSuccess(t *testing.T) {
 		Key:         "test_key",
 		Payload:     strings.NewReader("abcdefg"),
 		Metadata:    map[string]string{"a": "123", "b": "456"},
+		Tags:        map[string]string{"tag_b": "2", "tag_a": "1"},
 		MinPartSize: 3,
 	}
 
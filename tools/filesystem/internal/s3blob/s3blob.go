@@ -252,6 +252,7 @@ func (drv *driver) NewTypedWriter(ctx context.Context, key string, contentType s
 		md[k] = url.PathEscape(v)
 	}
 	u.Metadata = md
+	u.Tags = opts.Tags
 
 	var reqOptions []func(*http.Request)
 	reqOptions = append(reqOptions, func(r *http.Request) {
@@ -0,0 +1,208 @@
+// Package geoip implements a minimal in-memory IP ranges database
+// for resolving the country and ASN (Autonomous System Number) of an IP address.
+//
+// The database could be loaded from CSV files in the free DB-IP Lite
+// (https://db-ip.com/db/lite.php) "IP to Country" and "IP to ASN" formats:
+//
+//	start_ip,end_ip,country_code
+//	start_ip,end_ip,asn,asn_organization
+package geoip
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"net/netip"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Info defines the resolved geo information of a single IP address.
+type Info struct {
+	// Country is the ISO 3166-1 alpha-2 country code (e.g. "BG").
+	Country string `json:"country"`
+
+	// ASN is the autonomous system number of the IP address network (e.g. 15169).
+	ASN int `json:"asn"`
+
+	// ASNOrg is the autonomous system organization name (e.g. "Google LLC").
+	ASNOrg string `json:"asnOrg"`
+}
+
+// IsEmpty reports whether the Info doesn't contain any resolved data.
+func (info Info) IsEmpty() bool {
+	return info.Country == "" && info.ASN == 0 && info.ASNOrg == ""
+}
+
+type ipRange struct {
+	start [16]byte
+	end   [16]byte
+	info  Info
+}
+
+// DB is a concurrent safe IP ranges database.
+//
+// The country and ASN ranges are stored separately so that
+// the lookup result could combine the data from both.
+type DB struct {
+	mu        sync.RWMutex
+	countries []ipRange
+	asns      []ipRange
+}
+
+// New creates a new empty DB instance.
+func New() *DB {
+	return &DB{}
+}
+
+// Len returns the total number of the loaded IP ranges.
+func (db *DB) Len() int {
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	return len(db.countries) + len(db.asns)
+}
+
+// LoadCSV parses and loads the IP ranges from the provided CSV reader.
+//
+// The format of each row is detected based on its number of columns:
+//   - 3 columns: start_ip,end_ip,country_code
+//   - 4 columns: start_ip,end_ip,asn,asn_organization
+//
+// Empty lines and lines starting with "#" are ignored.
+// The ranges are expected to not overlap with the already loaded ones from the same type.
+func (db *DB) LoadCSV(r io.Reader) error {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.Comment = '#'
+	reader.ReuseRecord = true
+
+	var countries, asns []ipRange
+
+	for line := 1; ; line++ {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		if len(row) < 3 || len(row) > 4 {
+			return fmt.Errorf("line %d: expected 3 or 4 columns, got %d", line, len(row))
+		}
+
+		item, err := parseRange(row[0], row[1])
+		if err != nil {
+			return fmt.Errorf("line %d: %w", line, err)
+		}
+
+		if len(row) == 3 {
+			item.info.Country = strings.ToUpper(strings.TrimSpace(row[2]))
+			countries = append(countries, item)
+			continue
+		}
+
+		item.info.ASN, err = strconv.Atoi(strings.TrimPrefix(strings.TrimSpace(row[2]), "AS"))
+		if err != nil {
+			return fmt.Errorf("line %d: invalid asn: %w", line, err)
+		}
+		item.info.ASNOrg = strings.TrimSpace(row[3])
+		asns = append(asns, item)
+	}
+
+	db.mu.Lock()
+	defer db.mu.Unlock()
+
+	db.countries = mergeRanges(db.countries, countries)
+	db.asns = mergeRanges(db.asns, asns)
+
+	return nil
+}
+
+// Lookup returns the geo info of the provided IP address.
+//
+// Returns false if the IP address is invalid or no matching range was found.
+func (db *DB) Lookup(ip string) (Info, bool) {
+	addr, err := netip.ParseAddr(ip)
+	if err != nil {
+		return Info{}, false
+	}
+	key := addr.As16()
+
+	db.mu.RLock()
+	defer db.mu.RUnlock()
+
+	var info Info
+
+	if item, ok := findRange(db.countries, key); ok {
+		info.Country = item.info.Country
+	}
+
+	if item, ok := findRange(db.asns, key); ok {
+		info.ASN = item.info.ASN
+		info.ASNOrg = item.info.ASNOrg
+	}
+
+	return info, !info.IsEmpty()
+}
+
+func parseRange(rawStart, rawEnd string) (ipRange, error) {
+	start, err := netip.ParseAddr(strings.TrimSpace(rawStart))
+	if err != nil {
+		return ipRange{}, err
+	}
+
+	end, err := netip.ParseAddr(strings.TrimSpace(rawEnd))
+	if err != nil {
+		return ipRange{}, err
+	}
+
+	item := ipRange{start: start.As16(), end: end.As16()}
+
+	if bytes.Compare(item.start[:], item.end[:]) > 0 {
+		return ipRange{}, errors.New("the range start ip must be before the end ip")
+	}
+
+	return item, nil
+}
+
+func mergeRanges(existing []ipRange, ranges []ipRange) []ipRange {
+	if len(ranges) == 0 {
+		return existing
+	}
+
+	result := append(slices.Clip(existing), ranges...)
+
+	slices.SortStableFunc(result, func(a, b ipRange) int {
+		return bytes.Compare(a.start[:], b.start[:])
+	})
+
+	return result
+}
+
+// findRange searches for the range with the largest start
+// that is <= key and checks whether it contains the key.
+func findRange(ranges []ipRange, key [16]byte) (ipRange, bool) {
+	i, found := slices.BinarySearchFunc(ranges, key, func(item ipRange, target [16]byte) int {
+		return bytes.Compare(item.start[:], target[:])
+	})
+
+	if !found {
+		if i == 0 {
+			return ipRange{}, false
+		}
+		i--
+	}
+
+	item := ranges[i]
+	if bytes.Compare(key[:], item.end[:]) > 0 {
+		return ipRange{}, false
+	}
+
+	return item, true
+}
@@ -0,0 +1,123 @@
+package geoip_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/geoip"
+)
+
+func TestInfoIsEmpty(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		info     geoip.Info
+		expected bool
+	}{
+		{geoip.Info{}, true},
+		{geoip.Info{Country: "BG"}, false},
+		{geoip.Info{ASN: 1}, false},
+		{geoip.Info{ASNOrg: "test"}, false},
+	}
+
+	for i, s := range scenarios {
+		if v := s.info.IsEmpty(); v != s.expected {
+			t.Errorf("[%d] Expected %v, got %v", i, s.expected, v)
+		}
+	}
+}
+
+func TestDBLoadCSV(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name        string
+		csv         string
+		expectError bool
+		expectedLen int
+	}{
+		{"empty", "", false, 0},
+		{"comments only", "# test\n\n", false, 0},
+		{"invalid columns count", "1.0.0.0,1.0.0.255", true, 0},
+		{"invalid start ip", "invalid,1.0.0.255,AU", true, 0},
+		{"invalid end ip", "1.0.0.0,invalid,AU", true, 0},
+		{"start after end", "1.0.0.255,1.0.0.0,AU", true, 0},
+		{"invalid asn", "1.0.0.0,1.0.0.255,abc,test", true, 0},
+		{
+			"valid countries and asns",
+			"1.0.0.0,1.0.0.255,AU\n1.0.1.0,1.0.3.255,cn\n1.0.0.0,1.0.0.255,13335,Cloudflare\n::,::ff,ZZ",
+			false,
+			4,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			db := geoip.New()
+
+			err := db.LoadCSV(strings.NewReader(s.csv))
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if l := db.Len(); l != s.expectedLen {
+				t.Fatalf("Expected %d ranges, got %d", s.expectedLen, l)
+			}
+		})
+	}
+}
+
+func TestDBLookup(t *testing.T) {
+	t.Parallel()
+
+	db := geoip.New()
+
+	// separate loads to check the ranges merging
+	err := db.LoadCSV(strings.NewReader("1.0.1.0,1.0.3.255,CN\n2001:db8::,2001:db8::ffff,DE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = db.LoadCSV(strings.NewReader(`
+1.0.0.0,1.0.0.255,au
+1.0.0.0,1.0.0.255,AS13335,"Cloudflare, Inc."
+1.0.2.0,1.0.2.255,4134,Chinanet
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		ip            string
+		expectedFound bool
+		expected      geoip.Info
+	}{
+		{"", false, geoip.Info{}},
+		{"invalid", false, geoip.Info{}},
+		{"0.255.255.255", false, geoip.Info{}},
+		{"1.0.4.0", false, geoip.Info{}},
+		{"1.0.0.0", true, geoip.Info{Country: "AU", ASN: 13335, ASNOrg: "Cloudflare, Inc."}},
+		{"1.0.0.255", true, geoip.Info{Country: "AU", ASN: 13335, ASNOrg: "Cloudflare, Inc."}},
+		{"1.0.1.10", true, geoip.Info{Country: "CN"}},
+		{"1.0.2.10", true, geoip.Info{Country: "CN", ASN: 4134, ASNOrg: "Chinanet"}},
+		{"::ffff:1.0.1.10", true, geoip.Info{Country: "CN"}},
+		{"2001:db8::1", true, geoip.Info{Country: "DE"}},
+		{"2001:db8::1:0", false, geoip.Info{}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.ip, func(t *testing.T) {
+			info, found := db.Lookup(s.ip)
+
+			if found != s.expectedFound {
+				t.Fatalf("Expected found %v, got %v", s.expectedFound, found)
+			}
+
+			if info != s.expected {
+				t.Fatalf("Expected %#v, got %#v", s.expected, info)
+			}
+		})
+	}
+}
@@ -0,0 +1,224 @@
+// Package i18n implements a minimal concurrent safe translations catalog
+// and helpers for resolving the preferred client locale.
+//
+// Example:
+//
+//	catalog := i18n.NewCatalog()
+//
+//	catalog.Load("cs", map[string]string{
+//		"validation_required": "Nesmí být prázdné.",
+//		"Failed to create record.": "Nepodařilo se vytvořit záznam.",
+//	})
+//
+//	locale := catalog.MatchLocale(i18n.ParseAcceptLanguage("cs-CZ,cs;q=0.9,en;q=0.8")) // "cs"
+//
+//	msg, ok := catalog.Get(locale, "validation_required") // "Nesmí být prázdné.", true
+package i18n
+
+import (
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Catalog defines a concurrent safe store for translation messages grouped by locale.
+type Catalog struct {
+	messages map[string]map[string]string
+	mu       sync.RWMutex
+}
+
+// NewCatalog creates and returns a new empty translations Catalog.
+func NewCatalog() *Catalog {
+	return &Catalog{
+		messages: map[string]map[string]string{},
+	}
+}
+
+// Set registers (or replaces) a single translation message.
+func (c *Catalog) Set(locale string, key string, message string) {
+	locale = NormalizeLocale(locale)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = map[string]string{}
+	}
+
+	c.messages[locale][key] = message
+}
+
+// Load registers (or replaces) the provided translation messages
+// for the specified locale.
+//
+// Existing locale messages that are not part of the provided map are preserved.
+func (c *Catalog) Load(locale string, messages map[string]string) {
+	locale = NormalizeLocale(locale)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.messages[locale] == nil {
+		c.messages[locale] = make(map[string]string, len(messages))
+	}
+
+	for k, v := range messages {
+		c.messages[locale][k] = v
+	}
+}
+
+// Remove removes a single translation message from the specified locale.
+func (c *Catalog) Remove(locale string, key string) {
+	locale = NormalizeLocale(locale)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.messages[locale], key)
+
+	if len(c.messages[locale]) == 0 {
+		delete(c.messages, locale)
+	}
+}
+
+// RemoveLocale removes all translation messages of the specified locale.
+func (c *Catalog) RemoveLocale(locale string) {
+	locale = NormalizeLocale(locale)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.messages, locale)
+}
+
+// Locales returns a sorted list with all registered catalog locales.
+func (c *Catalog) Locales() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	result := make([]string, 0, len(c.messages))
+	for locale := range c.messages {
+		result = append(result, locale)
+	}
+
+	slices.Sort(result)
+
+	return result
+}
+
+// Get returns the translation message associated with the provided locale and key.
+//
+// If there is no exact locale match, it fallbacks to the locale base language
+// (e.g. "cs-CZ" -> "cs").
+func (c *Catalog) Get(locale string, key string) (string, bool) {
+	locale = NormalizeLocale(locale)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if msg, ok := c.messages[locale][key]; ok {
+		return msg, true
+	}
+
+	if base := BaseLanguage(locale); base != locale {
+		if msg, ok := c.messages[base][key]; ok {
+			return msg, true
+		}
+	}
+
+	return "", false
+}
+
+// MatchLocale returns the first locale from the preferred list that
+// exists in the catalog (either as exact match or via its base language).
+//
+// Returns empty string if none of the preferred locales are available.
+func (c *Catalog) MatchLocale(preferred []string) string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	for _, locale := range preferred {
+		locale = NormalizeLocale(locale)
+
+		if _, ok := c.messages[locale]; ok {
+			return locale
+		}
+
+		if base := BaseLanguage(locale); base != locale {
+			if _, ok := c.messages[base]; ok {
+				return base
+			}
+		}
+	}
+
+	return ""
+}
+
+// NormalizeLocale returns a lowercased and trimmed locale string
+// with "_" replaced by "-" (e.g. " pt_BR " -> "pt-br").
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// BaseLanguage returns the primary language subtag of the provided locale
+// (e.g. "pt-br" -> "pt").
+func BaseLanguage(locale string) string {
+	base, _, _ := strings.Cut(NormalizeLocale(locale), "-")
+	return base
+}
+
+// ParseAcceptLanguage parses the provided Accept-Language header value
+// and returns its normalized locales sorted by their quality value (highest first).
+//
+// Locales with q=0 and the "*" wildcard are ignored.
+func ParseAcceptLanguage(header string) []string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	parts := strings.Split(header, ",")
+
+	items := make([]weighted, 0, len(parts))
+
+	for _, part := range parts {
+		locale, params, _ := strings.Cut(part, ";")
+
+		locale = NormalizeLocale(locale)
+		if locale == "" || locale == "*" {
+			continue
+		}
+
+		q := 1.0
+		for _, param := range strings.Split(params, ";") {
+			name, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+			if strings.TrimSpace(name) != "q" {
+				continue
+			}
+
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err == nil {
+				q = parsed
+			}
+		}
+
+		if q <= 0 {
+			continue
+		}
+
+		items = append(items, weighted{locale, q})
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].q > items[j].q
+	})
+
+	result := make([]string, len(items))
+	for i, item := range items {
+		result[i] = item.locale
+	}
+
+	return result
+}
@@ -0,0 +1,130 @@
+package i18n_test
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/i18n"
+)
+
+func TestCatalogSetGetRemove(t *testing.T) {
+	c := i18n.NewCatalog()
+
+	c.Set("cs", "a", "cs_a")
+	c.Set("pt_BR", "a", "pt_br_a")
+	c.Load("pt", map[string]string{"a": "pt_a", "b": "pt_b"})
+
+	scenarios := []struct {
+		locale     string
+		key        string
+		expected   string
+		expectedOk bool
+	}{
+		{"", "a", "", false},
+		{"en", "a", "", false},
+		{"cs", "missing", "", false},
+		{"cs", "a", "cs_a", true},
+		{"CS", "a", "cs_a", true},
+		{"cs-CZ", "a", "cs_a", true},
+		{"pt-br", "a", "pt_br_a", true},
+		{"pt-br", "b", "pt_b", true},
+		{"pt", "a", "pt_a", true},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s_%s", i, s.locale, s.key), func(t *testing.T) {
+			msg, ok := c.Get(s.locale, s.key)
+			if ok != s.expectedOk {
+				t.Fatalf("Expected ok %v, got %v", s.expectedOk, ok)
+			}
+			if msg != s.expected {
+				t.Fatalf("Expected message %q, got %q", s.expected, msg)
+			}
+		})
+	}
+
+	locales := c.Locales()
+	if !slices.Equal(locales, []string{"cs", "pt", "pt-br"}) {
+		t.Fatalf("Unexpected locales %v", locales)
+	}
+
+	c.Remove("cs", "a")
+	c.RemoveLocale("pt")
+
+	locales = c.Locales()
+	if !slices.Equal(locales, []string{"pt-br"}) {
+		t.Fatalf("Unexpected locales after remove %v", locales)
+	}
+}
+
+func TestCatalogMatchLocale(t *testing.T) {
+	c := i18n.NewCatalog()
+	c.Set("cs", "a", "a")
+	c.Set("de-at", "a", "a")
+
+	scenarios := []struct {
+		preferred []string
+		expected  string
+	}{
+		{nil, ""},
+		{[]string{"en", "fr"}, ""},
+		{[]string{"en", "cs"}, "cs"},
+		{[]string{"cs-CZ", "en"}, "cs"},
+		{[]string{"de-AT", "cs"}, "de-at"},
+		{[]string{"de", "cs"}, "cs"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%v", i, s.preferred), func(t *testing.T) {
+			result := c.MatchLocale(s.preferred)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestParseAcceptLanguage(t *testing.T) {
+	scenarios := []struct {
+		header   string
+		expected []string
+	}{
+		{"", []string{}},
+		{"*", []string{}},
+		{"cs", []string{"cs"}},
+		{"en-US,en;q=0.5,cs;q=0.8", []string{"en-us", "cs", "en"}},
+		{"fr;q=0, de ; q=0.3 ,*;q=0.1, it", []string{"it", "de"}},
+		{"pt_BR;q=invalid", []string{"pt-br"}},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.header), func(t *testing.T) {
+			result := i18n.ParseAcceptLanguage(s.header)
+			if !slices.Equal(result, s.expected) {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestBaseLanguage(t *testing.T) {
+	scenarios := []struct {
+		locale   string
+		expected string
+	}{
+		{"", ""},
+		{"cs", "cs"},
+		{"pt_BR", "pt"},
+		{" EN-us ", "en"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.locale), func(t *testing.T) {
+			result := i18n.BaseLanguage(s.locale)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
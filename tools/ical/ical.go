@@ -0,0 +1,129 @@
+// Package ical implements a minimal RFC 5545 iCalendar feed encoder.
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// ContentType is the iCalendar feed MIME type.
+const ContentType = "text/calendar; charset=utf-8"
+
+// maxLineLength is the max content line length in octets (excluding the line break).
+const maxLineLength = 75
+
+const dateTimeLayout = "20060102T150405Z"
+
+// Calendar defines a single iCalendar feed.
+type Calendar struct {
+	// ProdId is the identifier of the product that created the feed.
+	ProdId string
+
+	// Name is the optional calendar display name (X-WR-CALNAME).
+	Name string
+
+	Events []Event
+}
+
+// Event defines a single VEVENT calendar component.
+type Event struct {
+	// UID is the globally unique event identifier.
+	UID string
+
+	// Stamp is the date when the event was last modified.
+	Stamp time.Time
+
+	Start time.Time
+
+	// End is the optional event end date.
+	End time.Time
+
+	Summary string
+
+	// Description is the optional event description.
+	Description string
+
+	// URL is the optional event details url.
+	URL string
+}
+
+// Encode writes the calendar as iCalendar stream into w.
+func (c *Calendar) Encode(w io.Writer) error {
+	bw := bufio.NewWriter(w)
+
+	writeLine(bw, "BEGIN:VCALENDAR")
+	writeLine(bw, "VERSION:2.0")
+	writeLine(bw, "PRODID:"+EscapeText(c.ProdId))
+	writeLine(bw, "CALSCALE:GREGORIAN")
+	if c.Name != "" {
+		writeLine(bw, "X-WR-CALNAME:"+EscapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		writeLine(bw, "BEGIN:VEVENT")
+		writeLine(bw, "UID:"+EscapeText(event.UID))
+		writeLine(bw, "DTSTAMP:"+FormatDateTime(event.Stamp))
+		writeLine(bw, "DTSTART:"+FormatDateTime(event.Start))
+		if !event.End.IsZero() {
+			writeLine(bw, "DTEND:"+FormatDateTime(event.End))
+		}
+		writeLine(bw, "SUMMARY:"+EscapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(bw, "DESCRIPTION:"+EscapeText(event.Description))
+		}
+		if event.URL != "" {
+			writeLine(bw, "URL:"+event.URL)
+		}
+		writeLine(bw, "END:VEVENT")
+	}
+
+	writeLine(bw, "END:VCALENDAR")
+
+	return bw.Flush()
+}
+
+// FormatDateTime formats t as UTC iCalendar DATE-TIME value.
+func FormatDateTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+var textReplacer = strings.NewReplacer(
+	`\`, `\\`,
+	`;`, `\;`,
+	`,`, `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+// EscapeText escapes the special characters of an iCalendar TEXT value.
+func EscapeText(str string) string {
+	return textReplacer.Replace(str)
+}
+
+// writeLine writes a single CRLF terminated content line
+// folding it at [maxLineLength] octets (without splitting multi-byte characters).
+func writeLine(w *bufio.Writer, line string) {
+	limit := maxLineLength
+
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		w.WriteString(line[:cut])
+		w.WriteString("\r\n ")
+
+		line = line[cut:]
+
+		// the continuation lines start with a single space
+		limit = maxLineLength - 1
+	}
+
+	w.WriteString(line)
+	w.WriteString("\r\n")
+}
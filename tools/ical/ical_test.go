@@ -0,0 +1,107 @@
+package ical_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/ical"
+)
+
+func TestEscapeText(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"test", "test"},
+		{`a\b;c,d`, `a\\b\;c\,d`},
+		{"a\nb\r\nc\rd", `a\nb\nc\nd`},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.value, func(t *testing.T) {
+			if v := ical.EscapeText(s.value); v != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestFormatDateTime(t *testing.T) {
+	t.Parallel()
+
+	loc := time.FixedZone("test", 2*3600)
+
+	v := ical.FormatDateTime(time.Date(2024, 1, 2, 12, 30, 15, 0, loc))
+	if v != "20240102T103015Z" {
+		t.Fatalf("Expected %q, got %q", "20240102T103015Z", v)
+	}
+}
+
+func TestCalendarEncode(t *testing.T) {
+	t.Parallel()
+
+	stamp := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	calendar := &ical.Calendar{
+		ProdId: "-//test//EN",
+		Name:   "Test, calendar",
+		Events: []ical.Event{
+			{
+				UID:     "e1@test",
+				Stamp:   stamp,
+				Start:   time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC),
+				Summary: "Event 1",
+			},
+			{
+				UID:         "e2@test",
+				Stamp:       stamp,
+				Start:       time.Date(2024, 1, 3, 10, 0, 0, 0, time.UTC),
+				End:         time.Date(2024, 1, 3, 11, 0, 0, 0, time.UTC),
+				Summary:     "Event 2",
+				Description: strings.Repeat("ж", 50),
+				URL:         "https://example.com/e2",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := calendar.Encode(&buf); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//test//EN",
+		"CALSCALE:GREGORIAN",
+		`X-WR-CALNAME:Test\, calendar`,
+		"BEGIN:VEVENT",
+		"UID:e1@test",
+		"DTSTAMP:20240101T000000Z",
+		"DTSTART:20240102T100000Z",
+		"SUMMARY:Event 1",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:e2@test",
+		"DTSTAMP:20240101T000000Z",
+		"DTSTART:20240103T100000Z",
+		"DTEND:20240103T110000Z",
+		"SUMMARY:Event 2",
+		// 12 + 31*2 = 74 octets (the next 2-byte character doesn't fit)
+		"DESCRIPTION:" + strings.Repeat("ж", 31),
+		" " + strings.Repeat("ж", 19),
+		"URL:https://example.com/e2",
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+
+	if v := buf.String(); v != expected {
+		t.Fatalf("Expected\n%q\ngot\n%q", expected, v)
+	}
+}
@@ -0,0 +1,101 @@
+package inflector
+
+import (
+	"strings"
+	"unicode"
+)
+
+// transliterations is a basic lowercase characters map for the most
+// common Latin (with diacritics), Cyrillic and Greek letters.
+var transliterations = map[rune]string{
+	// latin
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ĉ': "c", 'ċ': "c", 'ď': "d", 'đ': "d", 'ð': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ė': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ģ': "g", 'ĝ': "g", 'ħ': "h", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i",
+	'į': "i", 'ı': "i", 'ķ': "k", 'ĺ': "l", 'ļ': "l", 'ľ': "l", 'ł': "l", 'ñ': "n", 'ń': "n",
+	'ņ': "n", 'ň': "n", 'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o",
+	'ő': "o", 'œ': "oe", 'ŕ': "r", 'ř': "r", 'ś': "s", 'š': "s", 'ş': "s", 'ș': "s", 'ß': "ss",
+	'ť': "t", 'ţ': "t", 'ț': "t", 'þ': "th", 'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u",
+	'ů': "u", 'ű': "u", 'ų': "u", 'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+
+	// cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "h", 'ц': "ts", 'ч': "ch", 'ш': "sh", 'щ': "sht",
+	'ъ': "a", 'ы': "y", 'ь': "y", 'э': "e", 'ю': "yu", 'я': "ya", 'є': "ye", 'і': "i", 'ї': "yi",
+	'ґ': "g", 'ђ': "dj", 'ј': "j", 'љ': "lj", 'њ': "nj", 'ћ': "c", 'џ': "dz", 'ѓ': "gj", 'ќ': "kj",
+
+	// greek
+	'α': "a", 'ά': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'έ': "e", 'ζ': "z", 'η': "i",
+	'ή': "i", 'θ': "th", 'ι': "i", 'ί': "i", 'ϊ': "i", 'ΐ': "i", 'κ': "k", 'λ': "l", 'μ': "m",
+	'ν': "n", 'ξ': "x", 'ο': "o", 'ό': "o", 'π': "p", 'ρ': "r", 'σ': "s", 'ς': "s", 'τ': "t",
+	'υ': "y", 'ύ': "y", 'ϋ': "y", 'ΰ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o", 'ώ': "o",
+}
+
+// Transliterate replaces the most common Latin (with diacritics),
+// Cyrillic and Greek letters in str with their ASCII equivalent.
+//
+// Characters without known transliteration are left unchanged.
+//
+// For example:
+//
+//	inflector.Transliterate("Ще бъде") // "Shte bade"
+func Transliterate(str string) string {
+	var result strings.Builder
+	result.Grow(len(str))
+
+	for _, c := range str {
+		if c < unicode.MaxASCII {
+			result.WriteRune(c)
+			continue
+		}
+
+		lower := unicode.ToLower(c)
+
+		replacement, ok := transliterations[lower]
+		if !ok {
+			result.WriteRune(c)
+			continue
+		}
+
+		if lower != c {
+			replacement = UcFirst(replacement)
+		}
+
+		result.WriteString(replacement)
+	}
+
+	return result.String()
+}
+
+// Slugify converts str into a lowercase URL friendly slug by replacing
+// every sequence of non letter and non number characters with a single dash.
+//
+// Non-ASCII letters are preserved (use [Transliterate] if you want to convert them).
+//
+// For example:
+//
+//	inflector.Slugify(" Hello, World! ") // "hello-world"
+func Slugify(str string) string {
+	var result strings.Builder
+	result.Grow(len(str))
+
+	var hasSeparator bool
+
+	for _, c := range strings.ToLower(str) {
+		if !unicode.IsLetter(c) && !unicode.IsNumber(c) {
+			hasSeparator = true
+			continue
+		}
+
+		if hasSeparator && result.Len() > 0 {
+			result.WriteByte('-')
+		}
+		hasSeparator = false
+
+		result.WriteRune(c)
+	}
+
+	return result.String()
+}
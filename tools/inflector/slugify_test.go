@@ -0,0 +1,60 @@
+package inflector_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/inflector"
+)
+
+func TestTransliterate(t *testing.T) {
+	scenarios := []struct {
+		val      string
+		expected string
+	}{
+		{"", ""},
+		{"abc 123!", "abc 123!"},
+		{"Crème Brûlée", "Creme Brulee"},
+		{"Straße", "Strasse"},
+		{"Łódź", "Lodz"},
+		{"Ще бъде", "Shte bade"},
+		{"ЖЕЛЯЗО", "ZhELYaZO"},
+		{"Αθήνα", "Athina"},
+		{"日本", "日本"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.val), func(t *testing.T) {
+			result := inflector.Transliterate(s.val)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	scenarios := []struct {
+		val      string
+		expected string
+	}{
+		{"", ""},
+		{"  ", ""},
+		{"!@#$", ""},
+		{"abc", "abc"},
+		{" Hello, World! ", "hello-world"},
+		{"test_123--abc__", "test-123-abc"},
+		{"MyTestDB 2.0", "mytestdb-2-0"},
+		{"Здравей свят", "здравей-свят"},
+		{"日本 語", "日本-語"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.val), func(t *testing.T) {
+			result := inflector.Slugify(s.val)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package ldap
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// UsernamePlaceholder is the [Config.UserFilter] placeholder
+// that is replaced with the escaped login username.
+const UsernamePlaceholder = "{username}"
+
+// ErrInvalidCredentials is returned by [Authenticate] when the user
+// is not found or the provided password is invalid.
+var ErrInvalidCredentials = errors.New("ldap: invalid credentials")
+
+// Config defines the LDAP directory settings used for authenticating users.
+type Config struct {
+	// URL is the LDAP server url (eg. "ldaps://ldap.example.com").
+	URL string
+
+	// StartTLS upgrades the plain "ldap://" connection to TLS before binding.
+	StartTLS bool
+
+	// SkipVerify disables the server TLS certificate verification.
+	SkipVerify bool
+
+	// BindDN and BindPassword are the optional service account credentials
+	// used for the user search (leave empty for anonymous search).
+	BindDN       string
+	BindPassword string
+
+	// BaseDN is the search base of the users (eg. "ou=users,dc=example,dc=com").
+	BaseDN string
+
+	// UserFilter is the users search filter.
+	//
+	// The {username} placeholder is replaced with the escaped login username
+	// (eg. "(&(objectClass=person)(uid={username}))").
+	UserFilter string
+
+	// Attributes is the list of the user entry attributes to fetch
+	// (leave empty to fetch all user attributes).
+	Attributes []string
+
+	// Timeout is the max duration of a single LDAP operation
+	// (default to [DefaultTimeout]).
+	Timeout time.Duration
+}
+
+// Authenticate verifies the provided user credentials against the configured LDAP directory
+// by searching for the user entry and binding with its DN and password.
+//
+// Returns [ErrInvalidCredentials] if the user doesn't exist,
+// multiple users match the filter or the password is invalid.
+func Authenticate(ctx context.Context, config Config, username string, password string) (*Entry, error) {
+	// empty passwords are rejected to prevent unauthenticated (aka. anonymous) binds
+	if username == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	if !strings.Contains(config.UserFilter, UsernamePlaceholder) {
+		return nil, errors.New("ldap: the user filter must contain the " + UsernamePlaceholder + " placeholder")
+	}
+
+	tlsConfig := &tls.Config{InsecureSkipVerify: config.SkipVerify}
+
+	conn, err := Dial(ctx, config.URL, tlsConfig)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if config.Timeout > 0 {
+		conn.Timeout = config.Timeout
+	}
+
+	if config.StartTLS && !conn.IsTLS() {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			return nil, err
+		}
+	}
+
+	if config.BindDN != "" {
+		if err := conn.Bind(config.BindDN, config.BindPassword); err != nil {
+			return nil, fmt.Errorf("ldap: failed to bind the service account: %w", err)
+		}
+	}
+
+	entries, err := conn.Search(&SearchRequest{
+		BaseDN:     config.BaseDN,
+		Scope:      ScopeWholeSubtree,
+		Filter:     strings.ReplaceAll(config.UserFilter, UsernamePlaceholder, EscapeFilter(username)),
+		Attributes: config.Attributes,
+		SizeLimit:  2,
+	})
+	if err != nil && !IsErrorWithCode(err, ResultSizeLimitExceeded) {
+		return nil, fmt.Errorf("ldap: failed to search for the user: %w", err)
+	}
+
+	if len(entries) != 1 || entries[0].DN == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	if err := conn.Bind(entries[0].DN, password); err != nil {
+		if IsErrorWithCode(err, ResultInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	return entries[0], nil
+}
@@ -0,0 +1,207 @@
+package ldap_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tests"
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+func newTestLDAPServer(t *testing.T) *tests.TestLDAPServer {
+	server, err := tests.NewTestLDAPServer(
+		&tests.TestLDAPEntry{
+			DN:       "cn=service,dc=example,dc=com",
+			Password: "service_pass",
+		},
+		&tests.TestLDAPEntry{
+			DN:       "uid=john,ou=users,dc=example,dc=com",
+			Password: "john_pass",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"john"},
+				"cn":          {"John Doe"},
+				"mail":        {"john@example.com"},
+			},
+		},
+		&tests.TestLDAPEntry{
+			DN:       "uid=jane,ou=users,dc=example,dc=com",
+			Password: "jane_pass",
+			Attributes: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"jane"},
+				"cn":          {"Jane Doe"},
+			},
+		},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() { server.Close() })
+
+	return server
+}
+
+func TestAuthenticate(t *testing.T) {
+	server := newTestLDAPServer(t)
+
+	baseConfig := ldap.Config{
+		URL:          server.URL(),
+		BindDN:       "cn=service,dc=example,dc=com",
+		BindPassword: "service_pass",
+		BaseDN:       "ou=users,dc=example,dc=com",
+		UserFilter:   "(&(objectClass=person)(uid={username}))",
+	}
+
+	scenarios := []struct {
+		name            string
+		config          func() ldap.Config
+		username        string
+		password        string
+		expectedDN      string
+		expectedInvalid bool
+		expectedError   bool
+	}{
+		{
+			name:            "empty password",
+			config:          func() ldap.Config { return baseConfig },
+			username:        "john",
+			password:        "",
+			expectedInvalid: true,
+			expectedError:   true,
+		},
+		{
+			name: "missing filter placeholder",
+			config: func() ldap.Config {
+				c := baseConfig
+				c.UserFilter = "(uid=john)"
+				return c
+			},
+			username:      "john",
+			password:      "john_pass",
+			expectedError: true,
+		},
+		{
+			name: "invalid service account credentials",
+			config: func() ldap.Config {
+				c := baseConfig
+				c.BindPassword = "invalid"
+				return c
+			},
+			username:      "john",
+			password:      "john_pass",
+			expectedError: true,
+		},
+		{
+			name:            "missing user",
+			config:          func() ldap.Config { return baseConfig },
+			username:        "missing",
+			password:        "john_pass",
+			expectedInvalid: true,
+			expectedError:   true,
+		},
+		{
+			name:            "filter injection",
+			config:          func() ldap.Config { return baseConfig },
+			username:        "*",
+			password:        "john_pass",
+			expectedInvalid: true,
+			expectedError:   true,
+		},
+		{
+			name: "multiple matching users",
+			config: func() ldap.Config {
+				c := baseConfig
+				c.UserFilter = "(|(uid={username})(objectClass=person))"
+				return c
+			},
+			username:        "john",
+			password:        "john_pass",
+			expectedInvalid: true,
+			expectedError:   true,
+		},
+		{
+			name:            "invalid user password",
+			config:          func() ldap.Config { return baseConfig },
+			username:        "john",
+			password:        "jane_pass",
+			expectedInvalid: true,
+			expectedError:   true,
+		},
+		{
+			name:       "valid credentials",
+			config:     func() ldap.Config { return baseConfig },
+			username:   "john",
+			password:   "john_pass",
+			expectedDN: "uid=john,ou=users,dc=example,dc=com",
+		},
+		{
+			name: "valid credentials with anonymous search and StartTLS",
+			config: func() ldap.Config {
+				c := baseConfig
+				c.BindDN = ""
+				c.BindPassword = ""
+				c.StartTLS = true
+				c.SkipVerify = true
+				return c
+			},
+			username:   "JANE",
+			password:   "jane_pass",
+			expectedDN: "uid=jane,ou=users,dc=example,dc=com",
+		},
+		{
+			name: "StartTLS with unverified certificate",
+			config: func() ldap.Config {
+				c := baseConfig
+				c.StartTLS = true
+				return c
+			},
+			username:      "john",
+			password:      "john_pass",
+			expectedError: true,
+		},
+		{
+			name: "unsupported url scheme",
+			config: func() ldap.Config {
+				c := baseConfig
+				c.URL = strings.Replace(c.URL, "ldap://", "http://", 1)
+				return c
+			},
+			username:      "john",
+			password:      "john_pass",
+			expectedError: true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			entry, err := ldap.Authenticate(context.Background(), s.config(), s.username, s.password)
+
+			hasErr := err != nil
+			if hasErr != s.expectedError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectedError, hasErr, err)
+			}
+
+			isInvalid := errors.Is(err, ldap.ErrInvalidCredentials)
+			if isInvalid != s.expectedInvalid {
+				t.Fatalf("Expected ErrInvalidCredentials %v, got %v (%v)", s.expectedInvalid, isInvalid, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			if entry.DN != s.expectedDN {
+				t.Fatalf("Expected DN %q, got %q", s.expectedDN, entry.DN)
+			}
+
+			if !slices.Contains(server.Binds(), s.expectedDN) {
+				t.Fatalf("Expected user bind for %q, got %v", s.expectedDN, server.Binds())
+			}
+		})
+	}
+}
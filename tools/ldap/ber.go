@@ -0,0 +1,314 @@
+package ldap
+
+import (
+	"errors"
+	"fmt"
+	"io"
+)
+
+// BER identifier classes.
+const (
+	ClassUniversal   byte = 0x00
+	ClassApplication byte = 0x40
+	ClassContext     byte = 0x80
+)
+
+// Universal BER tags used by the LDAP protocol.
+const (
+	TagBoolean     byte = 0x01
+	TagInteger     byte = 0x02
+	TagOctetString byte = 0x04
+	TagNull        byte = 0x05
+	TagEnumerated  byte = 0x0a
+	TagSequence    byte = 0x10
+	TagSet         byte = 0x11
+)
+
+// maxPacketSize is the max allowed size of a single decoded BER packet (16MB).
+const maxPacketSize = 16 << 20
+
+// Packet defines a single BER encoded element.
+//
+// Constructed packets hold their nested elements in Children
+// and primitive packets hold their raw content in Value.
+type Packet struct {
+	Class       byte
+	Constructed bool
+	Tag         byte
+	Value       []byte
+	Children    []*Packet
+}
+
+// NewSequence creates a new universal constructed SEQUENCE packet.
+func NewSequence(children ...*Packet) *Packet {
+	return &Packet{Class: ClassUniversal, Constructed: true, Tag: TagSequence, Children: children}
+}
+
+// NewSet creates a new universal constructed SET packet.
+func NewSet(children ...*Packet) *Packet {
+	return &Packet{Class: ClassUniversal, Constructed: true, Tag: TagSet, Children: children}
+}
+
+// NewConstructed creates a new constructed packet with the specified class and tag.
+func NewConstructed(class byte, tag byte, children ...*Packet) *Packet {
+	return &Packet{Class: class, Constructed: true, Tag: tag, Children: children}
+}
+
+// NewPrimitive creates a new primitive packet with the specified class, tag and raw value.
+func NewPrimitive(class byte, tag byte, value []byte) *Packet {
+	return &Packet{Class: class, Tag: tag, Value: value}
+}
+
+// NewString creates a new universal OCTET STRING packet.
+func NewString(str string) *Packet {
+	return NewPrimitive(ClassUniversal, TagOctetString, []byte(str))
+}
+
+// NewInteger creates a new universal INTEGER packet.
+func NewInteger(v int64) *Packet {
+	return NewPrimitive(ClassUniversal, TagInteger, encodeInt(v))
+}
+
+// NewEnumerated creates a new universal ENUMERATED packet.
+func NewEnumerated(v int64) *Packet {
+	return NewPrimitive(ClassUniversal, TagEnumerated, encodeInt(v))
+}
+
+// NewBoolean creates a new universal BOOLEAN packet.
+func NewBoolean(v bool) *Packet {
+	if v {
+		return NewPrimitive(ClassUniversal, TagBoolean, []byte{0xff})
+	}
+	return NewPrimitive(ClassUniversal, TagBoolean, []byte{0x00})
+}
+
+// Is reports whether the packet has the specified class and tag.
+func (p *Packet) Is(class byte, tag byte) bool {
+	return p.Class == class && p.Tag == tag
+}
+
+// String returns the packet raw value as string.
+func (p *Packet) String() string {
+	return string(p.Value)
+}
+
+// Int returns the packet value decoded as two's complement integer
+// (usually used with INTEGER and ENUMERATED packets).
+func (p *Packet) Int() int64 {
+	var result int64
+
+	for i, b := range p.Value {
+		if i == 0 && b&0x80 != 0 {
+			result = -1 // negative number sign extension
+		}
+		result = result<<8 | int64(b)
+	}
+
+	return result
+}
+
+// Bool returns the packet value decoded as BER boolean.
+func (p *Packet) Bool() bool {
+	return len(p.Value) > 0 && p.Value[0] != 0
+}
+
+// Child returns the i-th nested packet or nil if there is no such packet.
+func (p *Packet) Child(i int) *Packet {
+	if i < 0 || i >= len(p.Children) {
+		return nil
+	}
+
+	return p.Children[i]
+}
+
+// Bytes encodes the packet into its BER binary representation.
+func (p *Packet) Bytes() []byte {
+	content := p.Value
+	if p.Constructed {
+		content = nil
+		for _, child := range p.Children {
+			content = append(content, child.Bytes()...)
+		}
+	}
+
+	identifier := p.Class | (p.Tag & 0x1f)
+	if p.Constructed {
+		identifier |= 0x20
+	}
+
+	result := make([]byte, 0, len(content)+6)
+	result = append(result, identifier)
+	result = append(result, encodeLength(len(content))...)
+	result = append(result, content...)
+
+	return result
+}
+
+// ReadPacket reads and decodes a single BER packet from r.
+//
+// For better performance r should implement [io.ByteReader] (eg. [bufio.Reader]).
+func ReadPacket(r io.Reader) (*Packet, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = &singleByteReader{r}
+	}
+
+	identifier, err := br.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+
+	if identifier&0x1f == 0x1f {
+		return nil, errors.New("ldap: multi-byte BER tags are not supported")
+	}
+
+	length, err := readLength(br)
+	if err != nil {
+		return nil, err
+	}
+
+	content := make([]byte, length)
+	if _, err := io.ReadFull(r, content); err != nil {
+		return nil, err
+	}
+
+	return decodePacket(identifier, content)
+}
+
+func decodePacket(identifier byte, content []byte) (*Packet, error) {
+	p := &Packet{
+		Class:       identifier & 0xc0,
+		Constructed: identifier&0x20 != 0,
+		Tag:         identifier & 0x1f,
+	}
+
+	if !p.Constructed {
+		p.Value = content
+		return p, nil
+	}
+
+	for len(content) > 0 {
+		child, n, err := parsePacket(content)
+		if err != nil {
+			return nil, err
+		}
+		p.Children = append(p.Children, child)
+		content = content[n:]
+	}
+
+	return p, nil
+}
+
+// parsePacket parses the first BER packet from data
+// and returns it together with the total number of the consumed bytes.
+func parsePacket(data []byte) (*Packet, int, error) {
+	if len(data) < 2 {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+
+	identifier := data[0]
+	if identifier&0x1f == 0x1f {
+		return nil, 0, errors.New("ldap: multi-byte BER tags are not supported")
+	}
+
+	offset := 2
+	length := int(data[1])
+	if length&0x80 != 0 {
+		n := length & 0x7f
+		if n == 0 || n > 4 || len(data) < 2+n {
+			return nil, 0, errors.New("ldap: invalid BER length")
+		}
+
+		length = 0
+		for _, b := range data[2 : 2+n] {
+			length = length<<8 | int(b)
+		}
+		offset += n
+	}
+
+	if length < 0 || len(data)-offset < length {
+		return nil, 0, io.ErrUnexpectedEOF
+	}
+
+	p, err := decodePacket(identifier, data[offset:offset+length])
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return p, offset + length, nil
+}
+
+func readLength(br io.ByteReader) (int, error) {
+	first, err := br.ReadByte()
+	if err != nil {
+		return 0, err
+	}
+
+	if first&0x80 == 0 {
+		return int(first), nil
+	}
+
+	n := int(first & 0x7f)
+	if n == 0 || n > 4 {
+		return 0, errors.New("ldap: invalid BER length")
+	}
+
+	var length int
+	for i := 0; i < n; i++ {
+		b, err := br.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+		length = length<<8 | int(b)
+	}
+
+	if length < 0 || length > maxPacketSize {
+		return 0, fmt.Errorf("ldap: BER packet size %d exceeds the allowed limit", length)
+	}
+
+	return length, nil
+}
+
+func encodeLength(length int) []byte {
+	if length < 0x80 {
+		return []byte{byte(length)}
+	}
+
+	var buf []byte
+	for length > 0 {
+		buf = append([]byte{byte(length)}, buf...)
+		length >>= 8
+	}
+
+	return append([]byte{0x80 | byte(len(buf))}, buf...)
+}
+
+func encodeInt(v int64) []byte {
+	// find the minimal number of bytes for the two's complement representation
+	n := 1
+	for i := v; i > 127 || i < -128; i >>= 8 {
+		n++
+	}
+
+	result := make([]byte, n)
+	for i := n - 1; i >= 0; i-- {
+		result[i] = byte(v)
+		v >>= 8
+	}
+
+	return result
+}
+
+// singleByteReader is an unbuffered [io.ByteReader] adapter
+// (it reads only the requested bytes from the underlying reader).
+type singleByteReader struct {
+	r io.Reader
+}
+
+func (sbr *singleByteReader) ReadByte() (byte, error) {
+	var b [1]byte
+	if _, err := io.ReadFull(sbr.r, b[:]); err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
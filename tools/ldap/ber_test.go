@@ -0,0 +1,133 @@
+package ldap_test
+
+import (
+	"bytes"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+func TestPacketBytes(t *testing.T) {
+	scenarios := []struct {
+		name     string
+		packet   *ldap.Packet
+		expected string
+	}{
+		{"integer 0", ldap.NewInteger(0), "020100"},
+		{"integer 127", ldap.NewInteger(127), "02017f"},
+		{"integer 128", ldap.NewInteger(128), "02020080"},
+		{"integer 256", ldap.NewInteger(256), "02020100"},
+		{"integer -1", ldap.NewInteger(-1), "0201ff"},
+		{"integer -129", ldap.NewInteger(-129), "0202ff7f"},
+		{"enumerated", ldap.NewEnumerated(2), "0a0102"},
+		{"boolean true", ldap.NewBoolean(true), "0101ff"},
+		{"boolean false", ldap.NewBoolean(false), "010100"},
+		{"string", ldap.NewString("abc"), "0403616263"},
+		{"empty sequence", ldap.NewSequence(), "3000"},
+		{
+			"bind request",
+			ldap.NewSequence(
+				ldap.NewInteger(1),
+				ldap.NewConstructed(ldap.ClassApplication, ldap.ApplicationBindRequest,
+					ldap.NewInteger(3),
+					ldap.NewString("cn=a"),
+					ldap.NewPrimitive(ldap.ClassContext, 0, []byte("pass")),
+				),
+			),
+			"3014020101600f0201030404636e3d61800470617373",
+		},
+		{
+			"long form length",
+			ldap.NewString(strings.Repeat("a", 200)),
+			"0481c8" + strings.Repeat("61", 200),
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := hex.EncodeToString(s.packet.Bytes())
+			if result != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestReadPacket(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		scenarios := []string{
+			"",
+			"04",
+			"0405616263",     // shorter content
+			"1f0100",         // multi-byte tag
+			"0485ffffffffff", // too long length
+			"3003040561",     // invalid child length
+		}
+
+		for i, s := range scenarios {
+			t.Run(fmt.Sprintf("%d_%s", i, s), func(t *testing.T) {
+				raw, _ := hex.DecodeString(s)
+
+				_, err := ldap.ReadPacket(bytes.NewReader(raw))
+				if err == nil {
+					t.Fatal("Expected error, got nil")
+				}
+			})
+		}
+	})
+
+	t.Run("roundtrip", func(t *testing.T) {
+		original := ldap.NewSequence(
+			ldap.NewInteger(-300),
+			ldap.NewBoolean(true),
+			ldap.NewConstructed(ldap.ClassApplication, ldap.ApplicationSearchResultEntry,
+				ldap.NewString("cn=test"),
+				ldap.NewSequence(ldap.NewSequence(ldap.NewString("mail"), ldap.NewSet(ldap.NewString(strings.Repeat("a", 300))))),
+			),
+		)
+
+		// two consecutive packets to ensure that only the first one is consumed
+		buf := bytes.NewBuffer(append(original.Bytes(), ldap.NewInteger(5).Bytes()...))
+
+		packet, err := ldap.ReadPacket(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(packet.Bytes(), original.Bytes()) {
+			t.Fatalf("Expected\n%x\ngot\n%x", original.Bytes(), packet.Bytes())
+		}
+
+		if v := packet.Child(0).Int(); v != -300 {
+			t.Fatalf("Expected int -300, got %d", v)
+		}
+
+		if !packet.Child(1).Bool() {
+			t.Fatal("Expected bool true")
+		}
+
+		entry := packet.Child(2)
+		if !entry.Is(ldap.ClassApplication, ldap.ApplicationSearchResultEntry) || !entry.Constructed {
+			t.Fatalf("Expected constructed search result entry, got %#v", entry)
+		}
+
+		if v := entry.Child(1).Child(0).Child(1).Child(0).String(); v != strings.Repeat("a", 300) {
+			t.Fatalf("Invalid nested string value %q", v)
+		}
+
+		if packet.Child(3) != nil {
+			t.Fatal("Expected nil child for out of range index")
+		}
+
+		next, err := ldap.ReadPacket(buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if v := next.Int(); v != 5 {
+			t.Fatalf("Expected the second packet int 5, got %d", v)
+		}
+	})
+}
@@ -0,0 +1,260 @@
+package ldap
+
+import (
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// Filter choice tags (RFC 4511 4.5.1).
+const (
+	FilterAnd            byte = 0
+	FilterOr             byte = 1
+	FilterNot            byte = 2
+	FilterEqualityMatch  byte = 3
+	FilterSubstrings     byte = 4
+	FilterGreaterOrEqual byte = 5
+	FilterLessOrEqual    byte = 6
+	FilterPresent        byte = 7
+	FilterApproxMatch    byte = 8
+)
+
+// Substring filter choice tags.
+const (
+	FilterSubstringsInitial byte = 0
+	FilterSubstringsAny     byte = 1
+	FilterSubstringsFinal   byte = 2
+)
+
+// maxFilterDepth limits the nesting of the compiled filters.
+const maxFilterDepth = 20
+
+// EscapeFilter escapes the special filter characters in value
+// as defined in RFC 4515 (eg. "a*b" -> "a\2ab").
+//
+// It should be used with every untrusted value that is
+// inserted in a filter string.
+func EscapeFilter(value string) string {
+	var result strings.Builder
+	result.Grow(len(value))
+
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch c {
+		case '*', '(', ')', '\\', 0:
+			result.WriteByte('\\')
+			result.WriteString(hex.EncodeToString([]byte{c}))
+		default:
+			result.WriteByte(c)
+		}
+	}
+
+	return result.String()
+}
+
+// CompileFilter compiles the provided RFC 4515 string filter
+// (eg. "(&(objectClass=person)(uid=john))") into its BER representation.
+//
+// Extensible match filters are not supported.
+func CompileFilter(filter string) (*Packet, error) {
+	filter = strings.TrimSpace(filter)
+	if filter == "" {
+		return nil, errors.New("ldap: empty filter")
+	}
+
+	// allow omitting the outer parenthesis for simple filters (eg. "uid=john")
+	if filter[0] != '(' {
+		filter = "(" + filter + ")"
+	}
+
+	p := &filterParser{str: filter}
+
+	packet, err := p.parse(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos != len(p.str) {
+		return nil, errors.New("ldap: unexpected characters after the end of the filter")
+	}
+
+	return packet, nil
+}
+
+type filterParser struct {
+	str string
+	pos int
+}
+
+func (p *filterParser) parse(depth int) (*Packet, error) {
+	if depth > maxFilterDepth {
+		return nil, errors.New("ldap: the filter is too deeply nested")
+	}
+
+	if p.pos >= len(p.str) || p.str[p.pos] != '(' {
+		return nil, errors.New("ldap: expected ( in the filter")
+	}
+	p.pos++
+
+	if p.pos >= len(p.str) {
+		return nil, errors.New("ldap: unexpected end of the filter")
+	}
+
+	var packet *Packet
+	var err error
+
+	switch p.str[p.pos] {
+	case '&':
+		p.pos++
+		packet, err = p.parseList(FilterAnd, depth)
+	case '|':
+		p.pos++
+		packet, err = p.parseList(FilterOr, depth)
+	case '!':
+		p.pos++
+		var inner *Packet
+		inner, err = p.parse(depth + 1)
+		packet = NewConstructed(ClassContext, FilterNot, inner)
+	default:
+		packet, err = p.parseItem()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if p.pos >= len(p.str) || p.str[p.pos] != ')' {
+		return nil, errors.New("ldap: expected ) in the filter")
+	}
+	p.pos++
+
+	return packet, nil
+}
+
+func (p *filterParser) parseList(tag byte, depth int) (*Packet, error) {
+	packet := NewConstructed(ClassContext, tag)
+
+	for p.pos < len(p.str) && p.str[p.pos] == '(' {
+		child, err := p.parse(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		packet.Children = append(packet.Children, child)
+	}
+
+	if len(packet.Children) == 0 {
+		return nil, errors.New("ldap: empty filter list")
+	}
+
+	return packet, nil
+}
+
+func (p *filterParser) parseItem() (*Packet, error) {
+	end := strings.IndexByte(p.str[p.pos:], ')')
+	if end < 0 {
+		return nil, errors.New("ldap: expected ) in the filter")
+	}
+
+	item := p.str[p.pos : p.pos+end]
+	p.pos += end
+
+	eqIndex := strings.IndexByte(item, '=')
+	if eqIndex <= 0 {
+		return nil, errors.New("ldap: invalid filter item " + item)
+	}
+
+	attr := item[:eqIndex]
+	rawValue := item[eqIndex+1:]
+
+	tag := FilterEqualityMatch
+	switch attr[len(attr)-1] {
+	case '~':
+		tag = FilterApproxMatch
+		attr = attr[:len(attr)-1]
+	case '>':
+		tag = FilterGreaterOrEqual
+		attr = attr[:len(attr)-1]
+	case '<':
+		tag = FilterLessOrEqual
+		attr = attr[:len(attr)-1]
+	case ':':
+		return nil, errors.New("ldap: extensible match filters are not supported")
+	}
+
+	attr = strings.TrimSpace(attr)
+	if attr == "" || strings.ContainsAny(attr, "()*\\") {
+		return nil, errors.New("ldap: invalid filter attribute " + attr)
+	}
+
+	if tag == FilterEqualityMatch && rawValue == "*" {
+		return NewPrimitive(ClassContext, FilterPresent, []byte(attr)), nil
+	}
+
+	if tag == FilterEqualityMatch && strings.Contains(rawValue, "*") {
+		return compileSubstrings(attr, rawValue)
+	}
+
+	value, err := unescapeFilterValue(rawValue)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewConstructed(ClassContext, tag, NewString(attr), NewString(value)), nil
+}
+
+func compileSubstrings(attr string, rawValue string) (*Packet, error) {
+	parts := strings.Split(rawValue, "*")
+
+	substrings := NewSequence()
+
+	for i, part := range parts {
+		if part == "" {
+			continue
+		}
+
+		value, err := unescapeFilterValue(part)
+		if err != nil {
+			return nil, err
+		}
+
+		tag := FilterSubstringsAny
+		if i == 0 {
+			tag = FilterSubstringsInitial
+		} else if i == len(parts)-1 {
+			tag = FilterSubstringsFinal
+		}
+
+		substrings.Children = append(substrings.Children, NewPrimitive(ClassContext, tag, []byte(value)))
+	}
+
+	return NewConstructed(ClassContext, FilterSubstrings, NewString(attr), substrings), nil
+}
+
+func unescapeFilterValue(value string) (string, error) {
+	if !strings.Contains(value, "\\") {
+		return value, nil
+	}
+
+	var result strings.Builder
+	result.Grow(len(value))
+
+	for i := 0; i < len(value); i++ {
+		if value[i] != '\\' {
+			result.WriteByte(value[i])
+			continue
+		}
+
+		if i+2 >= len(value) {
+			return "", errors.New("ldap: invalid filter escape sequence")
+		}
+
+		decoded, err := hex.DecodeString(value[i+1 : i+3])
+		if err != nil {
+			return "", errors.New("ldap: invalid filter escape sequence")
+		}
+
+		result.Write(decoded)
+		i += 2
+	}
+
+	return result.String(), nil
+}
@@ -0,0 +1,127 @@
+package ldap_test
+
+import (
+	"encoding/hex"
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+func TestEscapeFilter(t *testing.T) {
+	scenarios := []struct {
+		value    string
+		expected string
+	}{
+		{"", ""},
+		{"john.doe", "john.doe"},
+		{"*", `\2a`},
+		{"a*b(c)d\\e\x00", `a\2ab\28c\29d\5ce\00`},
+		{"*)(uid=*", `\2a\29\28uid=\2a`},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.value), func(t *testing.T) {
+			result := ldap.EscapeFilter(s.value)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+		})
+	}
+}
+
+func TestCompileFilter(t *testing.T) {
+	scenarios := []struct {
+		filter      string
+		expectError bool
+		expected    string
+	}{
+		{"", true, ""},
+		{"()", true, ""},
+		{"(uid=john", true, ""},
+		{"(uid=john))", true, ""},
+		{"(=john)", true, ""},
+		{"(&)", true, ""},
+		{"(!(uid=a)(uid=b))", true, ""},
+		{"(uid:dn:=john)", true, ""},
+		{`(uid=\zz)`, true, ""},
+		{`(uid=\2)`, true, ""},
+		{
+			"(uid=john)",
+			false,
+			"a30b0403756964" + "04046a6f686e",
+		},
+		{
+			"uid=john",
+			false,
+			"a30b0403756964" + "04046a6f686e",
+		},
+		{
+			`(uid=a\2ab)`,
+			false,
+			"a30a0403756964" + "0403612a62",
+		},
+		{
+			"(mail=*)",
+			false,
+			"87046d61696c",
+		},
+		{
+			"(age>=18)",
+			false,
+			"a509040361676504023138",
+		},
+		{
+			"(age<=18)",
+			false,
+			"a609040361676504023138",
+		},
+		{
+			"(cn~=john)",
+			false,
+			"a80a0402636e04046a6f686e",
+		},
+		{
+			"(cn=jo*h*n)",
+			false,
+			"a4100402636e300a80026a6f81016882016e",
+		},
+		{
+			"(cn=*oh*)",
+			false,
+			"a40a0402636e300481026f68",
+		},
+		{
+			"(&(objectClass=person)(!(uid=a)))",
+			false,
+			"a023" +
+				"a315040b" + hex.EncodeToString([]byte("objectClass")) + "0406" + hex.EncodeToString([]byte("person")) +
+				"a20aa3080403756964040161",
+		},
+		{
+			"(|(uid=a)(mail=*))",
+			false,
+			"a110" + "a3080403756964040161" + "87046d61696c",
+		},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%s", i, s.filter), func(t *testing.T) {
+			packet, err := ldap.CompileFilter(s.filter)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			result := hex.EncodeToString(packet.Bytes())
+			if result != s.expected {
+				t.Fatalf("Expected\n%s\ngot\n%s", s.expected, result)
+			}
+		})
+	}
+}
@@ -0,0 +1,462 @@
+// Package ldap implements a minimal LDAP v3 client (RFC 4511)
+// with support for simple bind, search and StartTLS operations.
+package ldap
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// LDAP protocol operation tags (RFC 4511 4.2).
+const (
+	ApplicationBindRequest           byte = 0
+	ApplicationBindResponse          byte = 1
+	ApplicationUnbindRequest         byte = 2
+	ApplicationSearchRequest         byte = 3
+	ApplicationSearchResultEntry     byte = 4
+	ApplicationSearchResultDone      byte = 5
+	ApplicationSearchResultReference byte = 19
+	ApplicationExtendedRequest       byte = 23
+	ApplicationExtendedResponse      byte = 24
+)
+
+// Common LDAP result codes.
+const (
+	ResultSuccess             = 0
+	ResultOperationsError     = 1
+	ResultProtocolError       = 2
+	ResultSizeLimitExceeded   = 4
+	ResultNoSuchObject        = 32
+	ResultInvalidCredentials  = 49
+	ResultInsufficientAccess  = 50
+	ResultUnavailable         = 52
+	ResultUnwillingToPerform  = 53
+	ResultOther               = 80
+	ResultUnsolicitedShutdown = -1 // non-standard code used for unexpected connection termination
+)
+
+// Search scopes.
+const (
+	ScopeBaseObject   = 0
+	ScopeSingleLevel  = 1
+	ScopeWholeSubtree = 2
+)
+
+// StartTLSOID is the StartTLS extended operation identifier.
+const StartTLSOID = "1.3.6.1.4.1.1466.20037"
+
+// DefaultTimeout is the default Conn network operations timeout.
+const DefaultTimeout = 10 * time.Second
+
+// Error defines a non-success LDAP operation result.
+type Error struct {
+	ResultCode int
+	Message    string
+}
+
+// Error implements the [error] interface.
+func (err *Error) Error() string {
+	if err.Message == "" {
+		return fmt.Sprintf("ldap: result code %d", err.ResultCode)
+	}
+	return fmt.Sprintf("ldap: result code %d: %s", err.ResultCode, err.Message)
+}
+
+// IsErrorWithCode checks whether err is an [*Error] with the specified result code.
+func IsErrorWithCode(err error, code int) bool {
+	var ldapErr *Error
+	return errors.As(err, &ldapErr) && ldapErr.ResultCode == code
+}
+
+// Entry defines a single LDAP search result entry.
+type Entry struct {
+	DN         string              `json:"dn"`
+	Attributes map[string][]string `json:"attributes"`
+}
+
+// GetAttributeValues returns all values of the specified attribute
+// (the attribute name is case-insensitive).
+func (e *Entry) GetAttributeValues(name string) []string {
+	if values, ok := e.Attributes[name]; ok {
+		return values
+	}
+
+	for k, values := range e.Attributes {
+		if strings.EqualFold(k, name) {
+			return values
+		}
+	}
+
+	return nil
+}
+
+// GetAttributeValue returns the first value of the specified attribute
+// or empty string if the attribute is missing.
+func (e *Entry) GetAttributeValue(name string) string {
+	values := e.GetAttributeValues(name)
+	if len(values) == 0 {
+		return ""
+	}
+
+	return values[0]
+}
+
+// SearchRequest defines the options of a single search operation.
+type SearchRequest struct {
+	BaseDN     string
+	Scope      int
+	Filter     string
+	Attributes []string
+	SizeLimit  int
+	TimeLimit  int
+}
+
+// Conn defines a single LDAP server connection.
+//
+// Conn operations are synchronous and must not be used concurrently.
+type Conn struct {
+	conn      net.Conn
+	reader    *bufio.Reader
+	host      string
+	messageId int64
+	isTLS     bool
+
+	// Timeout is the max duration of a single request-response operation.
+	Timeout time.Duration
+}
+
+// Dial opens a new LDAP connection to the specified server url.
+//
+// Supported schemes are "ldap" (default port 389) and "ldaps" (default port 636).
+// tlsConfig is used only for "ldaps" connections and could be nil.
+func Dial(ctx context.Context, rawURL string, tlsConfig *tls.Config) (*Conn, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("ldap: invalid server url: %w", err)
+	}
+
+	host := u.Hostname()
+	if host == "" {
+		return nil, errors.New("ldap: missing server host")
+	}
+
+	port := u.Port()
+
+	var useTLS bool
+	switch strings.ToLower(u.Scheme) {
+	case "ldap":
+		if port == "" {
+			port = "389"
+		}
+	case "ldaps":
+		useTLS = true
+		if port == "" {
+			port = "636"
+		}
+	default:
+		return nil, fmt.Errorf("ldap: unsupported server url scheme %q", u.Scheme)
+	}
+
+	addr := net.JoinHostPort(host, port)
+
+	var conn net.Conn
+	if useTLS {
+		dialer := &tls.Dialer{Config: withServerName(tlsConfig, host)}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	} else {
+		dialer := &net.Dialer{}
+		conn, err = dialer.DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("ldap: failed to connect: %w", err)
+	}
+
+	c := NewConn(conn, useTLS)
+	c.host = host
+
+	return c, nil
+}
+
+// NewConn creates a new LDAP [Conn] from an already established network connection.
+func NewConn(conn net.Conn, isTLS bool) *Conn {
+	return &Conn{
+		conn:    conn,
+		reader:  bufio.NewReader(conn),
+		isTLS:   isTLS,
+		Timeout: DefaultTimeout,
+	}
+}
+
+// IsTLS reports whether the connection is encrypted
+// (either with "ldaps" or after successful StartTLS).
+func (c *Conn) IsTLS() bool {
+	return c.isTLS
+}
+
+// Close sends an unbind request and closes the underlying connection.
+func (c *Conn) Close() error {
+	c.messageId++
+	msg := NewSequence(NewInteger(c.messageId), NewPrimitive(ClassApplication, ApplicationUnbindRequest, nil))
+
+	// unbind has no response and could fail if the server has already closed the connection
+	_ = c.conn.SetWriteDeadline(time.Now().Add(time.Second))
+	_, _ = c.conn.Write(msg.Bytes())
+
+	return c.conn.Close()
+}
+
+// StartTLS upgrades the current plain connection to TLS.
+func (c *Conn) StartTLS(tlsConfig *tls.Config) error {
+	if c.isTLS {
+		return errors.New("ldap: the connection is already encrypted")
+	}
+
+	op := NewConstructed(ClassApplication, ApplicationExtendedRequest,
+		NewPrimitive(ClassContext, 0, []byte(StartTLSOID)),
+	)
+
+	response, err := c.request(op, ApplicationExtendedResponse)
+	if err != nil {
+		return err
+	}
+
+	if err := resultError(response); err != nil {
+		return err
+	}
+
+	host := c.host
+	if host == "" {
+		host, _, _ = net.SplitHostPort(c.conn.RemoteAddr().String())
+	}
+
+	tlsConn := tls.Client(c.conn, withServerName(tlsConfig, host))
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout())
+	defer cancel()
+
+	if err := tlsConn.HandshakeContext(ctx); err != nil {
+		return fmt.Errorf("ldap: StartTLS handshake failed: %w", err)
+	}
+
+	c.conn = tlsConn
+	c.reader = bufio.NewReader(tlsConn)
+	c.isTLS = true
+
+	return nil
+}
+
+// Bind performs a simple bind operation with the specified credentials.
+//
+// Note that an empty password results in an unauthenticated bind
+// which most servers accept as anonymous. Use it with care when
+// verifying user credentials.
+func (c *Conn) Bind(dn string, password string) error {
+	op := NewConstructed(ClassApplication, ApplicationBindRequest,
+		NewInteger(3),
+		NewString(dn),
+		NewPrimitive(ClassContext, 0, []byte(password)),
+	)
+
+	response, err := c.request(op, ApplicationBindResponse)
+	if err != nil {
+		return err
+	}
+
+	return resultError(response)
+}
+
+// Search performs a search operation and returns the found entries.
+//
+// Search result references (aka. referrals) are ignored.
+func (c *Conn) Search(req *SearchRequest) ([]*Entry, error) {
+	filter, err := CompileFilter(req.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	attrs := NewSequence()
+	for _, attr := range req.Attributes {
+		attrs.Children = append(attrs.Children, NewString(attr))
+	}
+
+	op := NewConstructed(ClassApplication, ApplicationSearchRequest,
+		NewString(req.BaseDN),
+		NewEnumerated(int64(req.Scope)),
+		NewEnumerated(0), // neverDerefAliases
+		NewInteger(int64(req.SizeLimit)),
+		NewInteger(int64(req.TimeLimit)),
+		NewBoolean(false),
+		filter,
+		attrs,
+	)
+
+	messageId, err := c.send(op)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []*Entry
+
+	for {
+		response, err := c.receive(messageId)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case response.Is(ClassApplication, ApplicationSearchResultEntry):
+			entry, err := parseEntry(response)
+			if err != nil {
+				return nil, err
+			}
+			entries = append(entries, entry)
+		case response.Is(ClassApplication, ApplicationSearchResultReference):
+			continue
+		case response.Is(ClassApplication, ApplicationSearchResultDone):
+			if err := resultError(response); err != nil {
+				return entries, err
+			}
+			return entries, nil
+		default:
+			return nil, fmt.Errorf("ldap: unexpected search response operation %d", response.Tag)
+		}
+	}
+}
+
+// request sends a single protocol operation and waits for its response.
+func (c *Conn) request(op *Packet, expectedResponseTag byte) (*Packet, error) {
+	messageId, err := c.send(op)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := c.receive(messageId)
+	if err != nil {
+		return nil, err
+	}
+
+	if !response.Is(ClassApplication, expectedResponseTag) {
+		return nil, fmt.Errorf("ldap: unexpected response operation %d", response.Tag)
+	}
+
+	return response, nil
+}
+
+func (c *Conn) send(op *Packet) (int64, error) {
+	c.messageId++
+
+	msg := NewSequence(NewInteger(c.messageId), op)
+
+	if err := c.conn.SetDeadline(time.Now().Add(c.timeout())); err != nil {
+		return 0, err
+	}
+
+	if _, err := c.conn.Write(msg.Bytes()); err != nil {
+		return 0, fmt.Errorf("ldap: failed to send request: %w", err)
+	}
+
+	return c.messageId, nil
+}
+
+// receive reads the next response operation for the specified message id.
+func (c *Conn) receive(messageId int64) (*Packet, error) {
+	for {
+		if err := c.conn.SetDeadline(time.Now().Add(c.timeout())); err != nil {
+			return nil, err
+		}
+
+		msg, err := ReadPacket(c.reader)
+		if err != nil {
+			return nil, fmt.Errorf("ldap: failed to read response: %w", err)
+		}
+
+		if !msg.Is(ClassUniversal, TagSequence) || len(msg.Children) < 2 {
+			return nil, errors.New("ldap: invalid response message")
+		}
+
+		id := msg.Children[0].Int()
+
+		// unsolicited notification (eg. Notice of Disconnection)
+		if id == 0 {
+			return nil, &Error{ResultCode: ResultUnsolicitedShutdown, Message: "the server has terminated the connection"}
+		}
+
+		if id != messageId {
+			continue // not for us
+		}
+
+		return msg.Children[1], nil
+	}
+}
+
+func (c *Conn) timeout() time.Duration {
+	if c.Timeout <= 0 {
+		return DefaultTimeout
+	}
+
+	return c.Timeout
+}
+
+// resultError extracts the LDAPResult of the response operation
+// and returns an [*Error] if its result code is not success.
+func resultError(response *Packet) error {
+	if len(response.Children) < 3 {
+		return errors.New("ldap: invalid operation result")
+	}
+
+	code := int(response.Children[0].Int())
+	if code == ResultSuccess {
+		return nil
+	}
+
+	return &Error{ResultCode: code, Message: response.Children[2].String()}
+}
+
+func parseEntry(response *Packet) (*Entry, error) {
+	if len(response.Children) < 2 {
+		return nil, errors.New("ldap: invalid search result entry")
+	}
+
+	entry := &Entry{
+		DN:         response.Children[0].String(),
+		Attributes: make(map[string][]string, len(response.Children[1].Children)),
+	}
+
+	for _, attr := range response.Children[1].Children {
+		if len(attr.Children) < 2 {
+			continue
+		}
+
+		name := attr.Children[0].String()
+
+		values := make([]string, 0, len(attr.Children[1].Children))
+		for _, v := range attr.Children[1].Children {
+			values = append(values, v.String())
+		}
+
+		entry.Attributes[name] = values
+	}
+
+	return entry, nil
+}
+
+func withServerName(tlsConfig *tls.Config, host string) *tls.Config {
+	if tlsConfig == nil {
+		tlsConfig = &tls.Config{}
+	} else {
+		tlsConfig = tlsConfig.Clone()
+	}
+
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = host
+	}
+
+	return tlsConfig
+}
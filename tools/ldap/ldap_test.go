@@ -0,0 +1,78 @@
+package ldap_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/ldap"
+)
+
+func TestConnSearch(t *testing.T) {
+	server := newTestLDAPServer(t)
+
+	conn, err := ldap.Dial(context.Background(), server.URL(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if conn.IsTLS() {
+		t.Fatal("Expected plain connection")
+	}
+
+	if err := conn.Bind("cn=service,dc=example,dc=com", "invalid"); !ldap.IsErrorWithCode(err, ldap.ResultInvalidCredentials) {
+		t.Fatalf("Expected invalid credentials error, got %v", err)
+	}
+
+	if err := conn.Bind("cn=service,dc=example,dc=com", "service_pass"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := conn.Search(&ldap.SearchRequest{Filter: "(invalid"}); err == nil {
+		t.Fatal("Expected invalid filter error")
+	}
+
+	entries, err := conn.Search(&ldap.SearchRequest{
+		BaseDN:     "ou=users,dc=example,dc=com",
+		Scope:      ldap.ScopeWholeSubtree,
+		Filter:     "(&(objectClass=person)(cn=*doe))",
+		Attributes: []string{"cn", "MAIL"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("Expected 2 entries, got %d", len(entries))
+	}
+
+	if v := entries[0].GetAttributeValue("cn"); v != "John Doe" {
+		t.Fatalf("Expected cn %q, got %q", "John Doe", v)
+	}
+
+	if v := entries[0].GetAttributeValue("Mail"); v != "john@example.com" {
+		t.Fatalf("Expected mail %q, got %q", "john@example.com", v)
+	}
+
+	if v := entries[0].GetAttributeValues("uid"); v != nil {
+		t.Fatalf("Expected non-requested uid attribute to be missing, got %v", v)
+	}
+
+	if v := entries[1].GetAttributeValue("mail"); v != "" {
+		t.Fatalf("Expected empty mail, got %q", v)
+	}
+
+	// size limit
+	entries, err = conn.Search(&ldap.SearchRequest{
+		BaseDN:    "dc=example,dc=com",
+		Scope:     ldap.ScopeWholeSubtree,
+		Filter:    "(objectClass=*)",
+		SizeLimit: 1,
+	})
+	if !ldap.IsErrorWithCode(err, ldap.ResultSizeLimitExceeded) {
+		t.Fatalf("Expected size limit exceeded error, got %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+}
@@ -0,0 +1,127 @@
+// Package listenfd implements helpers for passing open network listeners
+// to a replacement process (e.g. started with execve) allowing
+// graceful restarts without refusing the incoming connections.
+//
+// While the listener file descriptor is kept open the new connections
+// are queued in the socket backlog and they are accepted by the new
+// process once it is ready.
+package listenfd
+
+import (
+	"errors"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// EnvName is the name of the environment variable with the passed listeners
+// in the format "addr1=fd1,addr2=fd2".
+const EnvName = "PB_LISTENERS"
+
+var (
+	mu sync.Mutex
+
+	// passed holds the passed listener files to prevent
+	// closing their descriptors on garbage collection
+	passed = map[string]*os.File{}
+)
+
+// Pass marks the provided TCP listener to be inherited by the next
+// execve-ed process under the addr key (see [Inherit]).
+//
+// The listener descriptor is duplicated, so the original listener
+// could be closed as usual without closing the underlying socket.
+//
+// Pass is supported only on UNIX based systems.
+func Pass(addr string, l net.Listener) error {
+	tcpListener, ok := l.(*net.TCPListener)
+	if !ok {
+		return errors.New("only TCP listeners could be passed")
+	}
+
+	f, err := tcpListener.File()
+	if err != nil {
+		return err
+	}
+
+	if err := clearCloseOnExec(f); err != nil {
+		f.Close()
+		return err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+
+	if old, ok := passed[addr]; ok {
+		old.Close()
+	}
+	passed[addr] = f
+
+	entries := make([]string, 0, len(passed))
+	for a, pf := range passed {
+		entries = append(entries, a+"="+strconv.FormatUint(uint64(pf.Fd()), 10))
+	}
+
+	return os.Setenv(EnvName, strings.Join(entries, ","))
+}
+
+// Inherit returns the listener passed by the parent process
+// for the specified addr key.
+//
+// Returns nil listener and nil error if there is no inherited
+// listener for addr.
+//
+// Each inherited listener could be retrieved only once.
+func Inherit(addr string) (net.Listener, error) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	env := os.Getenv(EnvName)
+	if env == "" {
+		return nil, nil
+	}
+
+	fd := -1
+
+	remaining := make([]string, 0, strings.Count(env, ",")+1)
+	for _, entry := range strings.Split(env, ",") {
+		a, rawFd, _ := strings.Cut(entry, "=")
+		if a != addr || fd >= 0 {
+			remaining = append(remaining, entry)
+			continue
+		}
+
+		var err error
+		fd, err = strconv.Atoi(rawFd)
+		if err != nil {
+			return nil, errors.New("invalid inherited listener descriptor " + rawFd)
+		}
+	}
+
+	if fd < 0 {
+		return nil, nil
+	}
+
+	if len(remaining) == 0 {
+		os.Unsetenv(EnvName)
+	} else {
+		os.Setenv(EnvName, strings.Join(remaining, ","))
+	}
+
+	// passed within the same process
+	f, ok := passed[addr]
+	if ok {
+		delete(passed, addr)
+	} else {
+		f = os.NewFile(uintptr(fd), "listener_"+addr)
+		if f == nil {
+			return nil, errors.New("invalid inherited listener descriptor " + strconv.Itoa(fd))
+		}
+	}
+	// FileListener works with a duplicate of the descriptor
+	defer f.Close()
+
+	return net.FileListener(f)
+}
@@ -0,0 +1,12 @@
+//go:build !unix
+
+package listenfd
+
+import (
+	"errors"
+	"os"
+)
+
+func clearCloseOnExec(f *os.File) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,16 @@
+//go:build unix
+
+package listenfd
+
+import (
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// clearCloseOnExec removes the FD_CLOEXEC flag of f
+// so that it remains open after execve.
+func clearCloseOnExec(f *os.File) error {
+	_, err := unix.FcntlInt(f.Fd(), unix.F_SETFD, 0)
+	return err
+}
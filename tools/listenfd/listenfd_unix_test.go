@@ -0,0 +1,96 @@
+//go:build unix
+
+package listenfd_test
+
+import (
+	"io"
+	"net"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/listenfd"
+)
+
+func TestPassAndInherit(t *testing.T) {
+	t.Setenv(listenfd.EnvName, "")
+
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	addr := l.Addr().String()
+
+	if err := listenfd.Pass(addr, l); err != nil {
+		t.Fatal(err)
+	}
+
+	if env := os.Getenv(listenfd.EnvName); !strings.HasPrefix(env, addr+"=") {
+		t.Fatalf("Expected %s env to contain the %q listener, got %q", listenfd.EnvName, addr, env)
+	}
+
+	// the passed socket should remain open after closing the original listener
+	l.Close()
+
+	missing, err := listenfd.Inherit("127.0.0.1:1")
+	if err != nil || missing != nil {
+		t.Fatalf("Expected nil listener and error for non-passed address, got %v (%v)", missing, err)
+	}
+
+	inherited, err := listenfd.Inherit(addr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer inherited.Close()
+
+	if inherited.Addr().String() != addr {
+		t.Fatalf("Expected inherited listener address %q, got %q", addr, inherited.Addr().String())
+	}
+
+	if env := os.Getenv(listenfd.EnvName); env != "" {
+		t.Fatalf("Expected the inherited listener to be removed from the env, got %q", env)
+	}
+
+	again, err := listenfd.Inherit(addr)
+	if err != nil || again != nil {
+		t.Fatalf("Expected the listener to be inherited only once, got %v (%v)", again, err)
+	}
+
+	go func() {
+		conn, err := net.Dial("tcp", addr)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		conn.Write([]byte("test"))
+	}()
+
+	conn, err := inherited.Accept()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	data, err := io.ReadAll(conn)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(data) != "test" {
+		t.Fatalf("Expected %q, got %q", "test", data)
+	}
+}
+
+func TestPassNonTCPListener(t *testing.T) {
+	l, err := net.Listen("unix", t.TempDir()+"/test.sock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer l.Close()
+
+	if err := listenfd.Pass("test", l); err == nil {
+		t.Fatal("Expected error for non-TCP listener")
+	}
+}
@@ -0,0 +1,269 @@
+// Package ogimage implements a minimal Open Graph preview image renderer.
+package ogimage
+
+import (
+	"errors"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/disintegration/imaging"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+// ContentType is the rendered image MIME type.
+const ContentType = "image/png"
+
+// The recommended Open Graph image dimensions.
+const (
+	Width  = 1200
+	Height = 630
+)
+
+const (
+	padding = 80
+
+	titleSize       = 64
+	titleLineHeight = 80
+	titleMaxLines   = 3
+
+	subtitleSize       = 36
+	subtitleLineHeight = 48
+	subtitleMaxLines   = 2
+
+	subtitleGap = 24
+
+	ellipsis = "…"
+)
+
+var (
+	// DefaultBackgroundColor is the image background color if none is specified.
+	DefaultBackgroundColor = color.NRGBA{R: 0x1e, G: 0x1e, B: 0x2e, A: 0xff}
+
+	// DefaultTextColor is the image text color if none is specified.
+	DefaultTextColor = color.NRGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff}
+
+	// backgroundOverlay is drawn over the background images to improve the text readability.
+	backgroundOverlay = color.NRGBA{R: 0, G: 0, B: 0, A: 0x8c}
+)
+
+// Options defines the image render options.
+type Options struct {
+	Title    string
+	Subtitle string
+
+	// Background is an optional background image
+	// (it is resized and cropped from the center to fill the whole image).
+	Background image.Image
+
+	// BackgroundColor is the image background color if no Background image is set.
+	BackgroundColor color.Color
+
+	TextColor color.Color
+}
+
+// Draw renders the provided options as a new [Width]x[Height] image.
+//
+// The title and subtitle are word wrapped and truncated
+// with ellipsis if they don't fit in the image.
+func Draw(opts Options) (*image.NRGBA, error) {
+	titleFace, subtitleFace, err := newFaces()
+	if err != nil {
+		return nil, err
+	}
+	defer titleFace.Close()
+	defer subtitleFace.Close()
+
+	bounds := image.Rect(0, 0, Width, Height)
+
+	canvas := image.NewNRGBA(bounds)
+
+	backgroundColor := opts.BackgroundColor
+	if backgroundColor == nil {
+		backgroundColor = DefaultBackgroundColor
+	}
+	draw.Draw(canvas, bounds, image.NewUniform(backgroundColor), image.Point{}, draw.Src)
+
+	if opts.Background != nil {
+		background := imaging.Fill(opts.Background, Width, Height, imaging.Center, imaging.Linear)
+		draw.Draw(canvas, bounds, background, image.Point{}, draw.Over)
+		draw.Draw(canvas, bounds, image.NewUniform(backgroundOverlay), image.Point{}, draw.Over)
+	}
+
+	textColor := opts.TextColor
+	if textColor == nil {
+		textColor = DefaultTextColor
+	}
+
+	maxWidth := fixed.I(Width - 2*padding)
+
+	drawer := &font.Drawer{
+		Dst: canvas,
+		Src: image.NewUniform(textColor),
+	}
+
+	y := padding
+
+	drawer.Face = titleFace
+	for _, line := range wrap(titleFace, opts.Title, maxWidth, titleMaxLines) {
+		y += titleLineHeight
+		drawer.Dot = fixed.P(padding, y)
+		drawer.DrawString(line)
+	}
+
+	y += subtitleGap
+
+	drawer.Face = subtitleFace
+	for _, line := range wrap(subtitleFace, opts.Subtitle, maxWidth, subtitleMaxLines) {
+		y += subtitleLineHeight
+		drawer.Dot = fixed.P(padding, y)
+		drawer.DrawString(line)
+	}
+
+	return canvas, nil
+}
+
+// Render renders the provided options as PNG image into w (see [Draw]).
+func Render(w io.Writer, opts Options) error {
+	img, err := Draw(opts)
+	if err != nil {
+		return err
+	}
+
+	return png.Encode(w, img)
+}
+
+// ParseHexColor parses a "#RRGGBB" hex color string.
+func ParseHexColor(str string) (color.NRGBA, error) {
+	if len(str) != 7 || str[0] != '#' {
+		return color.NRGBA{}, errors.New("the color must be in #RRGGBB format")
+	}
+
+	v, err := strconv.ParseUint(str[1:], 16, 32)
+	if err != nil {
+		return color.NRGBA{}, errors.New("the color must be in #RRGGBB format")
+	}
+
+	return color.NRGBA{R: uint8(v >> 16), G: uint8(v >> 8), B: uint8(v), A: 0xff}, nil
+}
+
+// -------------------------------------------------------------------
+
+var parseFonts = sync.OnceValues(func() ([]*opentype.Font, error) {
+	bold, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, err
+	}
+
+	regular, err := opentype.Parse(goregular.TTF)
+	if err != nil {
+		return nil, err
+	}
+
+	return []*opentype.Font{bold, regular}, nil
+})
+
+// newFaces returns new title and subtitle font faces.
+//
+// Note that the faces are not safe for concurrent use
+// and are expected to be created for each render.
+func newFaces() (font.Face, font.Face, error) {
+	fonts, err := parseFonts()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	titleFace, err := opentype.NewFace(fonts[0], &opentype.FaceOptions{Size: titleSize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	subtitleFace, err := opentype.NewFace(fonts[1], &opentype.FaceOptions{Size: subtitleSize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		titleFace.Close()
+		return nil, nil, err
+	}
+
+	return titleFace, subtitleFace, nil
+}
+
+// wrap splits text into lines that fit in maxWidth.
+//
+// Words longer than maxWidth are split and if the lines are more
+// than maxLines, the last allowed line is truncated with ellipsis.
+func wrap(face font.Face, text string, maxWidth fixed.Int26_6, maxLines int) []string {
+	lines := []string{}
+
+	var line string
+
+	for _, word := range strings.Fields(text) {
+		candidate := word
+		if line != "" {
+			candidate = line + " " + word
+		}
+
+		if font.MeasureString(face, candidate) <= maxWidth {
+			line = candidate
+			continue
+		}
+
+		if line != "" {
+			lines = append(lines, line)
+		}
+
+		line = word
+
+		for font.MeasureString(face, line) > maxWidth {
+			n := fitPrefix(face, line, maxWidth)
+			lines = append(lines, line[:n])
+			line = line[n:]
+		}
+	}
+
+	if line != "" {
+		lines = append(lines, line)
+	}
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = truncate(face, lines[maxLines-1], maxWidth)
+	}
+
+	return lines
+}
+
+// fitPrefix returns the byte length of the longest str prefix
+// that fits in maxWidth (but at least 1 rune).
+func fitPrefix(face font.Face, str string, maxWidth fixed.Int26_6) int {
+	var n int
+
+	for i, r := range str {
+		end := i + utf8.RuneLen(r)
+		if n > 0 && font.MeasureString(face, str[:end]) > maxWidth {
+			break
+		}
+		n = end
+	}
+
+	return n
+}
+
+// truncate trims str from the end until it fits in maxWidth with an ellipsis suffix.
+func truncate(face font.Face, str string, maxWidth fixed.Int26_6) string {
+	for str != "" && font.MeasureString(face, str+ellipsis) > maxWidth {
+		_, size := utf8.DecodeLastRuneInString(str)
+		str = str[:len(str)-size]
+	}
+
+	return strings.TrimRight(str, " ") + ellipsis
+}
@@ -0,0 +1,161 @@
+package ogimage_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/ogimage"
+)
+
+func TestParseHexColor(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		str         string
+		expected    color.NRGBA
+		expectError bool
+	}{
+		{"", color.NRGBA{}, true},
+		{"ff0000", color.NRGBA{}, true},
+		{"#ff00", color.NRGBA{}, true},
+		{"#gg0000", color.NRGBA{}, true},
+		{"#ff0000ff", color.NRGBA{}, true},
+		{"#ff0000", color.NRGBA{R: 0xff, A: 0xff}, false},
+		{"#1E1e2E", color.NRGBA{R: 0x1e, G: 0x1e, B: 0x2e, A: 0xff}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.str, func(t *testing.T) {
+			c, err := ogimage.ParseHexColor(s.str)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if c != s.expected {
+				t.Fatalf("Expected color %v, got %v", s.expected, c)
+			}
+		})
+	}
+}
+
+func TestDraw(t *testing.T) {
+	t.Parallel()
+
+	background := image.NewNRGBA(image.Rect(0, 0, 10, 10))
+	for i := range background.Pix {
+		background.Pix[i] = 0xff // white
+	}
+
+	scenarios := []struct {
+		name            string
+		opts            ogimage.Options
+		expectedCorner  color.NRGBA
+		expectTextColor bool
+	}{
+		{
+			"empty",
+			ogimage.Options{},
+			ogimage.DefaultBackgroundColor,
+			false,
+		},
+		{
+			"with title and subtitle",
+			ogimage.Options{
+				Title:    "Lorem ipsum dolor sit amet",
+				Subtitle: "Consectetur adipiscing elit",
+			},
+			ogimage.DefaultBackgroundColor,
+			true,
+		},
+		{
+			"with custom colors",
+			ogimage.Options{
+				Title:           "Lorem ipsum",
+				BackgroundColor: color.NRGBA{R: 0xff, A: 0xff},
+				TextColor:       color.NRGBA{B: 0xff, A: 0xff},
+			},
+			color.NRGBA{R: 0xff, A: 0xff},
+			true,
+		},
+		{
+			"with very long text",
+			ogimage.Options{
+				Title:    strings.Repeat("a", 500) + " " + strings.Repeat("lorem ipsum ", 100),
+				Subtitle: strings.Repeat("ä", 500),
+			},
+			ogimage.DefaultBackgroundColor,
+			true,
+		},
+		{
+			"with background image",
+			ogimage.Options{
+				Title:           "Lorem ipsum",
+				Background:      background,
+				BackgroundColor: color.NRGBA{R: 0xff, A: 0xff},
+			},
+			color.NRGBA{R: 0x73, G: 0x73, B: 0x73, A: 0xff}, // white + dark overlay
+			true,
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			img, err := ogimage.Draw(s.opts)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if w, h := img.Bounds().Dx(), img.Bounds().Dy(); w != ogimage.Width || h != ogimage.Height {
+				t.Fatalf("Expected %dx%d image, got %dx%d", ogimage.Width, ogimage.Height, w, h)
+			}
+
+			if c := img.NRGBAAt(0, 0); c != s.expectedCorner {
+				t.Fatalf("Expected corner color %v, got %v", s.expectedCorner, c)
+			}
+
+			textColor := ogimage.DefaultTextColor
+			if s.opts.TextColor != nil {
+				textColor = s.opts.TextColor.(color.NRGBA)
+			}
+
+			var hasTextColor bool
+			for y := 0; y < ogimage.Height && !hasTextColor; y++ {
+				for x := 0; x < ogimage.Width; x++ {
+					if img.NRGBAAt(x, y) == textColor {
+						hasTextColor = true
+						break
+					}
+				}
+			}
+
+			if hasTextColor != s.expectTextColor {
+				t.Fatalf("Expected text color pixels %v, got %v", s.expectTextColor, hasTextColor)
+			}
+		})
+	}
+}
+
+func TestRender(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+
+	if err := ogimage.Render(&buf, ogimage.Options{Title: "test"}); err != nil {
+		t.Fatal(err)
+	}
+
+	img, err := png.Decode(&buf)
+	if err != nil {
+		t.Fatalf("Expected valid PNG image, got %v", err)
+	}
+
+	if w, h := img.Bounds().Dx(), img.Bounds().Dy(); w != ogimage.Width || h != ogimage.Height {
+		t.Fatalf("Expected %dx%d image, got %dx%d", ogimage.Width, ogimage.Height, w, h)
+	}
+}
@@ -8,6 +8,15 @@ import (
 	"github.com/pocketbase/pocketbase/tools/tokenizer"
 )
 
+// SearchResultWrapper defines an interface for the types that extend
+// a [search.Result] with additional top level fields (e.g. response metadata).
+//
+// Similar to the plain search results, the fields of the wrapped
+// result are picked only from its "items".
+type SearchResultWrapper interface {
+	SearchResult() *search.Result
+}
+
 // Pick converts data into a []any, map[string]any, etc. (using json marshal->unmarshal)
 // containing only the fields from the parsed rawFields expression.
 //
@@ -45,7 +54,7 @@ func Pick(data any, rawFields string) (any, error) {
 	// special cases to preserve the same fields format when used with single item or search results data.
 	var isSearchResult bool
 	switch data.(type) {
-	case search.Result, *search.Result:
+	case search.Result, *search.Result, SearchResultWrapper:
 		isSearchResult = true
 	}
 
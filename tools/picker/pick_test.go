@@ -157,6 +157,25 @@ func TestPickFields(t *testing.T) {
 			false,
 			`{"items":[{"a":11,"c":"test1"},{"a":22,"c":"test2"}],"page":1,"perPage":10,"totalItems":20,"totalPages":30}`,
 		},
+		{
+			"SearchResultWrapper",
+			testSearchResultWrapper{
+				Result: &search.Result{
+					Page:       1,
+					PerPage:    10,
+					TotalItems: 20,
+					TotalPages: 30,
+					Items: []any{
+						map[string]any{"a": 11, "b": 11, "c": "test1"},
+						map[string]any{"a": 22, "b": 22, "c": "test2"},
+					},
+				},
+				Extra: []string{"x"},
+			},
+			"a",
+			false,
+			`{"extra":["x"],"items":[{"a":11},{"a":22}],"page":1,"perPage":10,"totalItems":20,"totalPages":30}`,
+		},
 		{
 			"root wildcard",
 			&search.Result{
@@ -274,3 +293,13 @@ func TestPickFields(t *testing.T) {
 		})
 	}
 }
+
+type testSearchResultWrapper struct {
+	*search.Result
+
+	Extra []string `json:"extra"`
+}
+
+func (w testSearchResultWrapper) SearchResult() *search.Result {
+	return w.Result
+}
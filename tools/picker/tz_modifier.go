@@ -0,0 +1,60 @@
+package picker
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func init() {
+	Modifiers["tz"] = func(args ...string) (Modifier, error) {
+		return newTzModifier(args...)
+	}
+}
+
+var _ Modifier = (*tzModifier)(nil)
+
+type tzModifier struct {
+	loc *time.Location
+}
+
+// newTzModifier validates the specified raw string arguments and
+// initializes a new tzModifier.
+//
+// This method is usually invoked in initModifer().
+func newTzModifier(args ...string) (*tzModifier, error) {
+	if len(args) != 1 {
+		return nil, errors.New("expected a single IANA timezone argument - (zone)")
+	}
+
+	loc, err := time.LoadLocation(args[0])
+	if err != nil {
+		return nil, fmt.Errorf("invalid timezone argument: %w", err)
+	}
+
+	return &tzModifier{loc}, nil
+}
+
+// Modify implements the [Modifier.Modify] interface method.
+//
+// It converts a date string into a date string with explicit offset
+// relative to the modifier timezone (e.g. "2024-01-02 11:00:00.000+01:00").
+//
+// Non-date values are kept untouched.
+func (m *tzModifier) Modify(value any) (any, error) {
+	switch v := value.(type) {
+	case types.DateTime:
+		return v.StringIn(m.loc), nil
+	case string:
+		dt, _ := types.ParseDateTime(v)
+		if dt.IsZero() {
+			// not a date -> return as it is without applying the modifier
+			return value, nil
+		}
+		return dt.StringIn(m.loc), nil
+	default:
+		return value, nil
+	}
+}
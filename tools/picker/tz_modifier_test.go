@@ -0,0 +1,77 @@
+package picker
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/types"
+)
+
+func TestNewTzModifier(t *testing.T) {
+	scenarios := []struct {
+		name        string
+		args        []string
+		expectError bool
+	}{
+		{"no arguments", nil, true},
+		{"too many arguments", []string{"UTC", "Europe/Prague"}, true},
+		{"invalid timezone", []string{"Europe/Missing"}, true},
+		{"valid timezone", []string{"Europe/Prague"}, false},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			m, err := newTzModifier(s.args...)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				if m != nil {
+					t.Fatalf("Expected nil modifier, got %v", m)
+				}
+				return
+			}
+
+			if m.loc == nil || m.loc.String() != s.args[0] {
+				t.Fatalf("Expected location %q, got %v", s.args[0], m.loc)
+			}
+		})
+	}
+}
+
+func TestTzModifierModify(t *testing.T) {
+	m, err := newTzModifier("Europe/Prague")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dt, _ := types.ParseDateTime("2024-07-01 10:00:00.123Z")
+
+	scenarios := []struct {
+		value    any
+		expected any
+	}{
+		{nil, nil},
+		{123, 123},
+		{"", ""},
+		{"invalid", "invalid"},
+		{"2024-01-01 10:00:00.123Z", "2024-01-01 11:00:00.123+01:00"},
+		{dt, "2024-07-01 12:00:00.123+02:00"},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.value), func(t *testing.T) {
+			result, err := m.Modify(s.value)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, result)
+			}
+		})
+	}
+}
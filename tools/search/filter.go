@@ -305,12 +305,16 @@ func resolveToken(token fexpr.Token, fieldResolver FieldResolver) (*ResolverResu
 			Params:     dbx.Params{placeholder: cast.ToFloat64(token.Literal)},
 		}, nil
 	case fexpr.TokenFunction:
+		args, _ := token.Meta.([]fexpr.Token)
+
 		fn, ok := TokenFunctions[token.Literal]
 		if !ok {
+			if fr, ok := fieldResolver.(FunctionResolver); ok {
+				return fr.ResolveFunction(token.Literal, args...)
+			}
 			return nil, fmt.Errorf("unknown function %q", token.Literal)
 		}
 
-		args, _ := token.Meta.([]fexpr.Token)
 		return fn(func(argToken fexpr.Token) (*ResolverResult, error) {
 			return resolveToken(argToken, fieldResolver)
 		}, args...)
@@ -9,6 +9,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/ganigeorgiev/fexpr"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/search"
 )
@@ -145,6 +146,18 @@ func TestFilterDataBuildExpr(t *testing.T) {
 			false,
 			"(6371 * acos(cos(radians({:TEST})) * cos(radians({:TEST})) * cos(radians({:TEST}) - radians({:TEST})) + sin(radians({:TEST})) * sin(radians({:TEST})))) < {:TEST}",
 		},
+		{
+			"zoned macro function",
+			"test1 >= @todayStartIn('Europe/Prague') && test2 = @hourIn('America/New_York')",
+			false,
+			"([[test1]] >= {:TEST} AND [[test2]] = {:TEST})",
+		},
+		{
+			"zoned macro function with invalid timezone",
+			"test1 >= @todayStartIn('invalid')",
+			true,
+			"",
+		},
 	}
 
 	for _, s := range scenarios {
@@ -339,3 +352,92 @@ func TestLikeParamsWrapping(t *testing.T) {
 		t.Fatalf("Expected query \n%s, \ngot \n%s", expectedQuery, calledQueries[0])
 	}
 }
+
+type testFunctionResolver struct {
+	*search.SimpleFieldResolver
+}
+
+func (r *testFunctionResolver) ResolveFunction(name string, args ...fexpr.Token) (*search.ResolverResult, error) {
+	if name != "@custom.fn" {
+		return nil, fmt.Errorf("unknown function %q", name)
+	}
+
+	if len(args) != 1 {
+		return nil, fmt.Errorf("expected 1 argument, got %d", len(args))
+	}
+
+	return &search.ResolverResult{Identifier: "CUSTOM(" + args[0].Literal + ")"}, nil
+}
+
+func TestFilterDataBuildExprWithFunctionResolver(t *testing.T) {
+	scenarios := []struct {
+		name          string
+		resolver      search.FieldResolver
+		filterData    search.FilterData
+		expectError   bool
+		expectPattern string
+	}{
+		{
+			"non function resolver",
+			search.NewSimpleFieldResolver("test1"),
+			"@custom.fn('a') = test1",
+			true,
+			"",
+		},
+		{
+			"unknown resolver function",
+			&testFunctionResolver{search.NewSimpleFieldResolver("test1")},
+			"@custom.missing('a') = test1",
+			true,
+			"",
+		},
+		{
+			"invalid resolver function args",
+			&testFunctionResolver{search.NewSimpleFieldResolver("test1")},
+			"@custom.fn('a', 'b') = test1",
+			true,
+			"",
+		},
+		{
+			"valid resolver function",
+			&testFunctionResolver{search.NewSimpleFieldResolver("test1")},
+			"@custom.fn('a') = test1",
+			false,
+			"COALESCE(CUSTOM(a), '') = COALESCE([[test1]], '')",
+		},
+		{
+			"global token functions have precedence",
+			&testFunctionResolver{search.NewSimpleFieldResolver("test1")},
+			"lower(test1) = 'a'",
+			false,
+			"LOWER([[test1]]) = {:TEST}",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			expr, err := s.filterData.BuildExpr(s.resolver)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			rawSql := expr.Build(&dbx.DB{}, dbx.Params{})
+
+			expectPattern := strings.ReplaceAll(
+				"^"+regexp.QuoteMeta(s.expectPattern)+"$",
+				"TEST",
+				`\w+`,
+			)
+
+			if !regexp.MustCompile(expectPattern).MatchString(rawSql) {
+				t.Fatalf("Pattern %v don't match with expression: \n%v", expectPattern, rawSql)
+			}
+		})
+	}
+}
@@ -4,6 +4,9 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
+	"github.com/pocketbase/pocketbase/tools/security"
 	"github.com/pocketbase/pocketbase/tools/types"
 )
 
@@ -12,124 +15,119 @@ var timeNow = func() time.Time {
 	return time.Now()
 }
 
-var identifierMacros = map[string]func() (any, error){
-	"@now": func() (any, error) {
-		today := timeNow().UTC()
-
-		d, err := types.ParseDateTime(today)
-		if err != nil {
-			return "", fmt.Errorf("@now: %w", err)
-		}
-
-		return d.String(), nil
+// datetimeMacros defines the datetime identifier macros values
+// relative to the provided now time and its location.
+var datetimeMacros = map[string]func(now time.Time) (any, error){
+	"@now": func(now time.Time) (any, error) {
+		return macroDateString(now)
 	},
-	"@yesterday": func() (any, error) {
-		yesterday := timeNow().UTC().AddDate(0, 0, -1)
-
-		d, err := types.ParseDateTime(yesterday)
-		if err != nil {
-			return "", fmt.Errorf("@yesterday: %w", err)
-		}
-
-		return d.String(), nil
+	"@yesterday": func(now time.Time) (any, error) {
+		return macroDateString(now.AddDate(0, 0, -1))
 	},
-	"@tomorrow": func() (any, error) {
-		tomorrow := timeNow().UTC().AddDate(0, 0, 1)
-
-		d, err := types.ParseDateTime(tomorrow)
-		if err != nil {
-			return "", fmt.Errorf("@tomorrow: %w", err)
-		}
-
-		return d.String(), nil
+	"@tomorrow": func(now time.Time) (any, error) {
+		return macroDateString(now.AddDate(0, 0, 1))
+	},
+	"@second": func(now time.Time) (any, error) {
+		return now.Second(), nil
+	},
+	"@minute": func(now time.Time) (any, error) {
+		return now.Minute(), nil
+	},
+	"@hour": func(now time.Time) (any, error) {
+		return now.Hour(), nil
+	},
+	"@day": func(now time.Time) (any, error) {
+		return now.Day(), nil
+	},
+	"@month": func(now time.Time) (any, error) {
+		return int(now.Month()), nil
+	},
+	"@weekday": func(now time.Time) (any, error) {
+		return int(now.Weekday()), nil
 	},
-	"@second": func() (any, error) {
-		return timeNow().UTC().Second(), nil
+	"@year": func(now time.Time) (any, error) {
+		return now.Year(), nil
 	},
-	"@minute": func() (any, error) {
-		return timeNow().UTC().Minute(), nil
+	"@todayStart": func(now time.Time) (any, error) {
+		return macroDateString(time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location()))
 	},
-	"@hour": func() (any, error) {
-		return timeNow().UTC().Hour(), nil
+	"@todayEnd": func(now time.Time) (any, error) {
+		return macroDateString(time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 999999999, now.Location()))
 	},
-	"@day": func() (any, error) {
-		return timeNow().UTC().Day(), nil
+	"@monthStart": func(now time.Time) (any, error) {
+		return macroDateString(time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()))
 	},
-	"@month": func() (any, error) {
-		return int(timeNow().UTC().Month()), nil
+	"@monthEnd": func(now time.Time) (any, error) {
+		start := time.Date(now.Year(), now.Month(), 1, 23, 59, 59, 999999999, now.Location())
+		return macroDateString(start.AddDate(0, 1, -1))
 	},
-	"@weekday": func() (any, error) {
-		return int(timeNow().UTC().Weekday()), nil
+	"@yearStart": func(now time.Time) (any, error) {
+		return macroDateString(time.Date(now.Year(), 1, 1, 0, 0, 0, 0, now.Location()))
 	},
-	"@year": func() (any, error) {
-		return timeNow().UTC().Year(), nil
+	"@yearEnd": func(now time.Time) (any, error) {
+		return macroDateString(time.Date(now.Year(), 12, 31, 23, 59, 59, 999999999, now.Location()))
 	},
-	"@todayStart": func() (any, error) {
-		today := timeNow().UTC()
-		start := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+}
 
-		d, err := types.ParseDateTime(start)
-		if err != nil {
-			return "", fmt.Errorf("@todayStart: %w", err)
+// identifierMacros defines the filter identifier macros evaluated in UTC (e.g. @todayStart).
+var identifierMacros = func() map[string]func() (any, error) {
+	result := make(map[string]func() (any, error), len(datetimeMacros))
+
+	for name, macro := range datetimeMacros {
+		result[name] = func() (any, error) {
+			v, err := macro(timeNow().UTC())
+			if err != nil {
+				return "", fmt.Errorf("%s: %w", name, err)
+			}
+			return v, nil
 		}
+	}
 
-		return d.String(), nil
-	},
-	"@todayEnd": func() (any, error) {
-		today := timeNow().UTC()
+	return result
+}()
 
-		start := time.Date(today.Year(), today.Month(), today.Day(), 23, 59, 59, 999999999, time.UTC)
+func init() {
+	// register the timezone aware "In" variants of the datetime macros
+	// as token functions, e.g. @todayStartIn("Europe/Prague")
+	for name, macro := range datetimeMacros {
+		TokenFunctions[name+"In"] = zonedMacroTokenFunction(name+"In", macro)
+	}
+}
 
-		d, err := types.ParseDateTime(start)
-		if err != nil {
-			return "", fmt.Errorf("@todayEnd: %w", err)
+func zonedMacroTokenFunction(
+	name string,
+	macro func(now time.Time) (any, error),
+) func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+	return func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		if len(args) != 1 || args[0].Type != fexpr.TokenText {
+			return nil, fmt.Errorf("[%s] expected a single timezone text argument", name)
 		}
 
-		return d.String(), nil
-	},
-	"@monthStart": func() (any, error) {
-		today := timeNow().UTC()
-		start := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC)
-
-		d, err := types.ParseDateTime(start)
+		loc, err := time.LoadLocation(args[0].Literal)
 		if err != nil {
-			return "", fmt.Errorf("@monthStart: %w", err)
+			return nil, fmt.Errorf("[%s] invalid timezone: %w", name, err)
 		}
 
-		return d.String(), nil
-	},
-	"@monthEnd": func() (any, error) {
-		today := timeNow().UTC()
-		start := time.Date(today.Year(), today.Month(), 1, 23, 59, 59, 999999999, time.UTC)
-		end := start.AddDate(0, 1, -1)
-
-		d, err := types.ParseDateTime(end)
+		value, err := macro(timeNow().In(loc))
 		if err != nil {
-			return "", fmt.Errorf("@monthEnd: %w", err)
+			return nil, fmt.Errorf("[%s] %w", name, err)
 		}
 
-		return d.String(), nil
-	},
-	"@yearStart": func() (any, error) {
-		today := timeNow().UTC()
-		start := time.Date(today.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		placeholder := "t" + security.PseudorandomString(8)
 
-		d, err := types.ParseDateTime(start)
-		if err != nil {
-			return "", fmt.Errorf("@yearStart: %w", err)
-		}
-
-		return d.String(), nil
-	},
-	"@yearEnd": func() (any, error) {
-		today := timeNow().UTC()
-		end := time.Date(today.Year(), 12, 31, 23, 59, 59, 999999999, time.UTC)
+		return &ResolverResult{
+			Identifier: "{:" + placeholder + "}",
+			Params:     dbx.Params{placeholder: value},
+		}, nil
+	}
+}
 
-		d, err := types.ParseDateTime(end)
-		if err != nil {
-			return "", fmt.Errorf("@yearEnd: %w", err)
-		}
+// macroDateString returns the provided time as UTC formatted date string.
+func macroDateString(t time.Time) (string, error) {
+	d, err := types.ParseDateTime(t)
+	if err != nil {
+		return "", err
+	}
 
-		return d.String(), nil
-	},
+	return d.String(), nil
 }
@@ -3,6 +3,8 @@ package search
 import (
 	"testing"
 	"time"
+
+	"github.com/ganigeorgiev/fexpr"
 )
 
 func TestIdentifierMacros(t *testing.T) {
@@ -56,3 +58,85 @@ func TestIdentifierMacros(t *testing.T) {
 	// restore
 	timeNow = originalTimeNow
 }
+
+func TestZonedIdentifierMacros(t *testing.T) {
+	originalTimeNow := timeNow
+	defer func() {
+		timeNow = originalTimeNow
+	}()
+
+	// 2023-02-03 23:30 in Europe/Prague
+	timeNow = func() time.Time {
+		return time.Date(2023, 2, 3, 22, 30, 6, 7, time.UTC)
+	}
+
+	testMacros := map[string]any{
+		"@nowIn":        "2023-02-03 22:30:06.000Z",
+		"@yesterdayIn":  "2023-02-02 22:30:06.000Z",
+		"@tomorrowIn":   "2023-02-04 22:30:06.000Z",
+		"@secondIn":     6,
+		"@minuteIn":     30,
+		"@hourIn":       23,
+		"@dayIn":        3,
+		"@monthIn":      2,
+		"@weekdayIn":    5,
+		"@yearIn":       2023,
+		"@todayStartIn": "2023-02-02 23:00:00.000Z",
+		"@todayEndIn":   "2023-02-03 22:59:59.999Z",
+		"@monthStartIn": "2023-01-31 23:00:00.000Z",
+		"@monthEndIn":   "2023-02-28 22:59:59.999Z",
+		"@yearStartIn":  "2022-12-31 23:00:00.000Z",
+		"@yearEndIn":    "2023-12-31 22:59:59.999Z",
+	}
+
+	for key, expected := range testMacros {
+		t.Run(key, func(t *testing.T) {
+			fn, ok := TokenFunctions[key]
+			if !ok {
+				t.Fatalf("Missing %s token function", key)
+			}
+
+			result, err := fn(nil, fexpr.Token{Type: fexpr.TokenText, Literal: "Europe/Prague"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(result.Params) != 1 {
+				t.Fatalf("Expected 1 param, got %v", result.Params)
+			}
+
+			for k, v := range result.Params {
+				if result.Identifier != "{:"+k+"}" {
+					t.Fatalf("Expected identifier %q, got %q", "{:"+k+"}", result.Identifier)
+				}
+
+				if v != expected {
+					t.Fatalf("Expected %v, got %v", expected, v)
+				}
+			}
+		})
+	}
+}
+
+func TestZonedIdentifierMacrosInvalidArgs(t *testing.T) {
+	fn := TokenFunctions["@todayStartIn"]
+
+	scenarios := []struct {
+		name string
+		args []fexpr.Token
+	}{
+		{"no args", nil},
+		{"too many args", []fexpr.Token{{Type: fexpr.TokenText, Literal: "UTC"}, {Type: fexpr.TokenText, Literal: "UTC"}}},
+		{"non-text arg", []fexpr.Token{{Type: fexpr.TokenIdentifier, Literal: "UTC"}}},
+		{"invalid timezone", []fexpr.Token{{Type: fexpr.TokenText, Literal: "Europe/Missing"}}},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			_, err := fn(nil, s.args...)
+			if err == nil {
+				t.Fatal("Expected error, got nil")
+			}
+		})
+	}
+}
@@ -22,6 +22,10 @@ const (
 	// DefaultSortExprLimit specifies the default sort expressions limit.
 	DefaultSortExprLimit int = 8
 
+	// DefaultEstimateTotalLimit specifies the default max number of
+	// items that are counted when the estimated total mode is enabled.
+	DefaultEstimateTotalLimit int = 10000
+
 	// MaxPerPage specifies the max allowed search result items returned in a single page.
 	MaxPerPage int = 1000
 
@@ -48,6 +52,9 @@ const (
 	SortQueryParam      string = "sort"
 	FilterQueryParam    string = "filter"
 	SkipTotalQueryParam string = "skipTotal"
+
+	CountOnlyQueryParam     string = "countOnly"
+	EstimateTotalQueryParam string = "estimateTotal"
 )
 
 // Result defines the returned search result structure.
@@ -69,6 +76,9 @@ type Provider struct {
 	page               int
 	perPage            int
 	skipTotal          bool
+	countOnly          bool
+	estimateTotal      bool
+	estimateTotalLimit int
 	maxFilterExprLimit int
 	maxSortExprLimit   int
 }
@@ -92,6 +102,7 @@ func NewProvider(fieldResolver FieldResolver) *Provider {
 		perPage:            DefaultPerPage,
 		sort:               []SortField{},
 		filter:             []FilterData{},
+		estimateTotalLimit: DefaultEstimateTotalLimit,
 		maxFilterExprLimit: DefaultFilterExprLimit,
 		maxSortExprLimit:   DefaultSortExprLimit,
 	}
@@ -123,6 +134,34 @@ func (s *Provider) SkipTotal(skipTotal bool) *Provider {
 	return s
 }
 
+// CountOnly changes the `countOnly` field of the current search provider.
+//
+// When enabled, only the total count query is executed and the result
+// items are not fetched (the skipTotal field is ignored).
+func (s *Provider) CountOnly(countOnly bool) *Provider {
+	s.countOnly = countOnly
+	return s
+}
+
+// EstimateTotal changes the `estimateTotal` field of the current search provider.
+//
+// When enabled, the total count query stops counting after the configured
+// estimate total limit (see [Provider.EstimateTotalLimit]) and if the limit
+// is exceeded the result totalItems and totalPages are set to -1.
+//
+// This is useful for large tables where the exact count is too slow.
+func (s *Provider) EstimateTotal(estimateTotal bool) *Provider {
+	s.estimateTotal = estimateTotal
+	return s
+}
+
+// EstimateTotalLimit changes the default max number of items
+// that are counted when the estimated total mode is enabled.
+func (s *Provider) EstimateTotalLimit(limit int) *Provider {
+	s.estimateTotalLimit = limit
+	return s
+}
+
 // CountCol allows changing the default column (id) that is used
 // to generate the COUNT SQL query statement.
 //
@@ -194,6 +233,22 @@ func (s *Provider) Parse(urlQuery string) error {
 		s.SkipTotal(v)
 	}
 
+	if raw := params.Get(CountOnlyQueryParam); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		s.CountOnly(v)
+	}
+
+	if raw := params.Get(EstimateTotalQueryParam); raw != "" {
+		v, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+		s.EstimateTotal(v)
+	}
+
 	if raw := params.Get(PageQueryParam); raw != "" {
 		v, err := strconv.Atoi(raw)
 		if err != nil {
@@ -223,9 +278,10 @@ func (s *Provider) Parse(urlQuery string) error {
 	return nil
 }
 
-// Exec executes the search provider and fills/scans
-// the provided `items` slice with the found models.
-func (s *Provider) Exec(items any) (*Result, error) {
+// BuildQuery returns a shallow copy of the provider's query with the applied
+// filter and sort expressions and field resolver modifications
+// (the pagination is not applied).
+func (s *Provider) BuildQuery() (*dbx.SelectQuery, error) {
 	if s.query == nil {
 		return nil, ErrEmptyQuery
 	}
@@ -277,6 +333,17 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		return nil, err
 	}
 
+	return &modelsQuery, nil
+}
+
+// Exec executes the search provider and fills/scans
+// the provided `items` slice with the found models.
+func (s *Provider) Exec(items any) (*Result, error) {
+	modelsQuery, err := s.BuildQuery()
+	if err != nil {
+		return nil, err
+	}
+
 	// normalize page
 	if s.page <= 0 {
 		s.page = 1
@@ -294,7 +361,7 @@ func (s *Provider) Exec(items any) (*Result, error) {
 	totalPages := -1
 
 	// prepare a count query from the base one
-	countQuery := modelsQuery // shallow clone
+	countQuery := *modelsQuery // shallow clone
 	countExec := func() error {
 		queryInfo := countQuery.Info()
 		countCol := s.countCol
@@ -303,12 +370,33 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		}
 
 		// note: countQuery is shallow cloned and slice/map in-place modifications should be avoided
-		err := countQuery.Distinct(false).
-			Select("COUNT(DISTINCT [[" + countCol + "]])").
-			OrderBy( /* reset */ ).
-			Row(&totalCount)
-		if err != nil {
-			return err
+		if s.estimateTotal && s.estimateTotalLimit > 0 {
+			// wrap in a subquery to stop counting after the limit, aka.
+			// SELECT COUNT(*) FROM (SELECT DISTINCT [[countCol]] FROM ... LIMIT estimateTotalLimit+1)
+			err := countQuery.Distinct(true).
+				Select("[[" + countCol + "]]").
+				OrderBy( /* reset */ ).
+				Limit(int64(s.estimateTotalLimit) + 1).
+				Offset(0).
+				PreFragment(strings.TrimSpace(queryInfo.PreFragment + " SELECT COUNT(*) FROM (")).
+				PostFragment(strings.TrimSpace(queryInfo.PostFragment + ")")).
+				Row(&totalCount)
+			if err != nil {
+				return err
+			}
+
+			if totalCount > s.estimateTotalLimit {
+				totalCount = -1
+				return nil
+			}
+		} else {
+			err := countQuery.Distinct(false).
+				Select("COUNT(DISTINCT [[" + countCol + "]])").
+				OrderBy( /* reset */ ).
+				Row(&totalCount)
+			if err != nil {
+				return err
+			}
 		}
 
 		totalPages = int(math.Ceil(float64(totalCount) / float64(s.perPage)))
@@ -324,7 +412,16 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		return modelsQuery.All(items)
 	}
 
-	if !s.skipTotal {
+	switch {
+	case s.countOnly:
+		if err := countExec(); err != nil {
+			return nil, err
+		}
+	case s.skipTotal:
+		if err := modelsExec(); err != nil {
+			return nil, err
+		}
+	default:
 		// execute the 2 queries concurrently
 		errg := new(errgroup.Group)
 		errg.SetLimit(2)
@@ -333,10 +430,6 @@ func (s *Provider) Exec(items any) (*Result, error) {
 		if err := errg.Wait(); err != nil {
 			return nil, err
 		}
-	} else {
-		if err := modelsExec(); err != nil {
-			return nil, err
-		}
 	}
 
 	result := &Result{
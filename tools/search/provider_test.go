@@ -98,6 +98,48 @@ func TestProviderSkipTotal(t *testing.T) {
 	}
 }
 
+func TestProviderCountOnly(t *testing.T) {
+	p := NewProvider(&testFieldResolver{})
+
+	if p.countOnly {
+		t.Fatalf("Expected the default countOnly to be %v, got %v", false, p.countOnly)
+	}
+
+	p.CountOnly(true)
+
+	if !p.countOnly {
+		t.Fatalf("Expected countOnly to change to %v, got %v", true, p.countOnly)
+	}
+}
+
+func TestProviderEstimateTotal(t *testing.T) {
+	p := NewProvider(&testFieldResolver{})
+
+	if p.estimateTotal {
+		t.Fatalf("Expected the default estimateTotal to be %v, got %v", false, p.estimateTotal)
+	}
+
+	p.EstimateTotal(true)
+
+	if !p.estimateTotal {
+		t.Fatalf("Expected estimateTotal to change to %v, got %v", true, p.estimateTotal)
+	}
+}
+
+func TestProviderEstimateTotalLimit(t *testing.T) {
+	p := NewProvider(&testFieldResolver{})
+
+	if p.estimateTotalLimit != DefaultEstimateTotalLimit {
+		t.Fatalf("Expected the default estimateTotalLimit to be %d, got %d", DefaultEstimateTotalLimit, p.estimateTotalLimit)
+	}
+
+	p.EstimateTotalLimit(10)
+
+	if p.estimateTotalLimit != 10 {
+		t.Fatalf("Expected estimateTotalLimit to change to %d, got %d", 10, p.estimateTotalLimit)
+	}
+}
+
 func TestProviderCountCol(t *testing.T) {
 	p := NewProvider(&testFieldResolver{})
 
@@ -258,6 +300,61 @@ func TestProviderParse(t *testing.T) {
 	}
 }
 
+func TestProviderBuildQuery(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	t.Run("empty query", func(t *testing.T) {
+		_, err := NewProvider(&testFieldResolver{}).BuildQuery()
+		if !errors.Is(err, ErrEmptyQuery) {
+			t.Fatalf("Expected ErrEmptyQuery, got %v", err)
+		}
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		_, err := NewProvider(&testFieldResolver{}).
+			Query(testDB.Select("*").From("test")).
+			Filter([]FilterData{"invalid"}).
+			BuildQuery()
+		if err == nil {
+			t.Fatal("Expected error, got nil")
+		}
+	})
+
+	t.Run("valid filter and sort", func(t *testing.T) {
+		baseQuery := testDB.Select("*").From("test")
+
+		testResolver := &testFieldResolver{}
+		query, err := NewProvider(testResolver).
+			Query(baseQuery).
+			Page(2).
+			PerPage(1).
+			Filter([]FilterData{"test2 != null"}).
+			Sort([]SortField{{"test2", SortDesc}}).
+			BuildQuery()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if testResolver.UpdateQueryCalls != 1 {
+			t.Fatalf("Expected resolver.Update to be called %d, got %d", 1, testResolver.UpdateQueryCalls)
+		}
+
+		expected := "SELECT * FROM `test` WHERE ((test2 IS NOT '' AND test2 IS NOT NULL)) ORDER BY `test2` DESC"
+		if sql := query.Build().SQL(); sql != expected {
+			t.Fatalf("Expected query\n%s\ngot\n%s", expected, sql)
+		}
+
+		// the provider query should remain unchanged
+		if sql := baseQuery.Build().SQL(); sql != "SELECT * FROM `test`" {
+			t.Fatalf("Expected the base query to remain unchanged, got\n%s", sql)
+		}
+	})
+}
+
 func TestProviderExecEmptyQuery(t *testing.T) {
 	p := NewProvider(&testFieldResolver{}).
 		Query(nil)
@@ -470,6 +567,123 @@ func TestProviderExecNonEmptyQuery(t *testing.T) {
 	}
 }
 
+func TestProviderExecCountModes(t *testing.T) {
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	query := testDB.Select("*").
+		From("test").
+		Where(dbx.Not(dbx.HashExp{"test1": nil})).
+		OrderBy("test1 ASC")
+
+	scenarios := []struct {
+		name               string
+		filter             []FilterData
+		countOnly          bool
+		estimateTotal      bool
+		estimateTotalLimit int
+		expectResult       string
+		expectQueries      []string
+	}{
+		{
+			"countOnly",
+			[]FilterData{"test1 >= 2"},
+			true,
+			false,
+			0,
+			`{"items":[],"page":1,"perPage":10,"totalItems":1,"totalPages":1}`,
+			[]string{
+				"SELECT COUNT(DISTINCT [[test.id]]) FROM `test` WHERE (NOT (`test1` IS NULL)) AND (test1 >= 2)",
+			},
+		},
+		{
+			"estimateTotal (within the limit)",
+			[]FilterData{},
+			false,
+			true,
+			2,
+			`{"items":[{"test1":1,"test2":"test2.1","test3":""},{"test1":2,"test2":"test2.2","test3":""}],"page":1,"perPage":10,"totalItems":2,"totalPages":1}`,
+			[]string{
+				"SELECT COUNT(*) FROM ( SELECT DISTINCT [[test.id]] FROM `test` WHERE NOT (`test1` IS NULL) LIMIT 3 )",
+				"SELECT * FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC LIMIT 10",
+			},
+		},
+		{
+			"estimateTotal (exceeding the limit)",
+			[]FilterData{},
+			false,
+			true,
+			1,
+			`{"items":[{"test1":1,"test2":"test2.1","test3":""},{"test1":2,"test2":"test2.2","test3":""}],"page":1,"perPage":10,"totalItems":-1,"totalPages":-1}`,
+			[]string{
+				"SELECT COUNT(*) FROM ( SELECT DISTINCT [[test.id]] FROM `test` WHERE NOT (`test1` IS NULL) LIMIT 2 )",
+				"SELECT * FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC LIMIT 10",
+			},
+		},
+		{
+			"estimateTotal with zero limit (fallback to exact count)",
+			[]FilterData{},
+			false,
+			true,
+			0,
+			`{"items":[{"test1":1,"test2":"test2.1","test3":""},{"test1":2,"test2":"test2.2","test3":""}],"page":1,"perPage":10,"totalItems":2,"totalPages":1}`,
+			[]string{
+				"SELECT COUNT(DISTINCT [[test.id]]) FROM `test` WHERE NOT (`test1` IS NULL)",
+				"SELECT * FROM `test` WHERE NOT (`test1` IS NULL) ORDER BY `test1` ASC LIMIT 10",
+			},
+		},
+		{
+			"countOnly and estimateTotal (exceeding the limit)",
+			[]FilterData{},
+			true,
+			true,
+			1,
+			`{"items":[],"page":1,"perPage":10,"totalItems":-1,"totalPages":-1}`,
+			[]string{
+				"SELECT COUNT(*) FROM ( SELECT DISTINCT [[test.id]] FROM `test` WHERE NOT (`test1` IS NULL) LIMIT 2 )",
+			},
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			testDB.CalledQueries = []string{} // reset
+
+			p := NewProvider(&testFieldResolver{}).
+				Query(query).
+				Page(1).
+				PerPage(10).
+				Filter(s.filter).
+				CountOnly(s.countOnly).
+				EstimateTotal(s.estimateTotal).
+				EstimateTotalLimit(s.estimateTotalLimit)
+
+			result, err := p.Exec(&[]testTableStruct{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			encoded, _ := json.Marshal(result)
+			if string(encoded) != s.expectResult {
+				t.Fatalf("Expected result %v, got \n%v", s.expectResult, string(encoded))
+			}
+
+			if len(s.expectQueries) != len(testDB.CalledQueries) {
+				t.Fatalf("Expected %d queries, got %d: \n%v", len(s.expectQueries), len(testDB.CalledQueries), testDB.CalledQueries)
+			}
+
+			for _, q := range testDB.CalledQueries {
+				if !list.ExistInSliceWithRegex(q, s.expectQueries) {
+					t.Fatalf("Didn't expect query \n%v \nin \n%v", q, s.expectQueries)
+				}
+			}
+		})
+	}
+}
+
 func TestProviderFilterAndSortLimits(t *testing.T) {
 	testDB, err := createTestDB()
 	if err != nil {
@@ -653,6 +867,18 @@ func TestProviderParseAndExec(t *testing.T) {
 			true,
 			"",
 		},
+		{
+			"invalid countOnly",
+			"countOnly=a",
+			true,
+			"",
+		},
+		{
+			"invalid estimateTotal",
+			"estimateTotal=a",
+			true,
+			"",
+		},
 		{
 			"invalid sorting field",
 			"sort=-unknown",
@@ -683,6 +909,18 @@ func TestProviderParseAndExec(t *testing.T) {
 			false,
 			`{"items":[{"test1":2,"test2":"test2.2","test3":""}],"page":1,"perPage":1000,"totalItems":-1,"totalPages":-1}`,
 		},
+		{
+			"valid query params with countOnly=1",
+			"page=1&perPage=9999&filter=test1>1&sort=-test2,test3&countOnly=1&skipTotal=1",
+			false,
+			`{"items":[],"page":1,"perPage":1000,"totalItems":1,"totalPages":1}`,
+		},
+		{
+			"valid query params with estimateTotal=1",
+			"page=1&perPage=9999&filter=test1>1&sort=-test2,test3&estimateTotal=1",
+			false,
+			`{"items":[{"test1":2,"test2":"test2.2","test3":""}],"page":1,"perPage":1000,"totalItems":1,"totalPages":1}`,
+		},
 	}
 
 	for _, s := range scenarios {
@@ -713,7 +951,7 @@ func TestProviderParseAndExec(t *testing.T) {
 			}
 
 			expectedQueries := 2
-			if provider.skipTotal {
+			if provider.skipTotal || provider.countOnly {
 				expectedQueries = 1
 			}
 
@@ -5,6 +5,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/ganigeorgiev/fexpr"
 	"github.com/pocketbase/dbx"
 	"github.com/pocketbase/pocketbase/tools/inflector"
 	"github.com/pocketbase/pocketbase/tools/list"
@@ -46,6 +47,16 @@ type FieldResolver interface {
 	Resolve(field string) (*ResolverResult, error)
 }
 
+// FunctionResolver defines an optional [FieldResolver] interface
+// for resolving resolver specific function calls that are not part
+// of the global [TokenFunctions] (eg. "@request.auth.hasRole('admin')").
+type FunctionResolver interface {
+	// ResolveFunction resolves the named function call with the provided raw arguments.
+	//
+	// It should return an error if the function is unknown or its arguments are invalid.
+	ResolveFunction(name string, args ...fexpr.Token) (*ResolverResult, error)
+}
+
 // NewSimpleFieldResolver creates a new `SimpleFieldResolver` with the
 // provided `allowedFields`.
 //
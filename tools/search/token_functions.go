@@ -1,9 +1,13 @@
 package search
 
 import (
+	"errors"
 	"fmt"
+	"slices"
+	"strings"
 
 	"github.com/ganigeorgiev/fexpr"
+	"github.com/pocketbase/dbx"
 )
 
 var TokenFunctions = map[string]func(
@@ -53,4 +57,212 @@ var TokenFunctions = map[string]func(
 			Params: mergeParams(resolvedArgs[0].Params, resolvedArgs[1].Params, resolvedArgs[2].Params, resolvedArgs[3].Params),
 		}, nil
 	},
+
+	// length(value) returns the number of characters of a text value (NULL is treated as empty string).
+	//
+	// For the number of items of a multi-valued field use the ":length" modifier instead (e.g. "tags:length").
+	"length": func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		return singleArgTokenFunction("length", "LENGTH(COALESCE(%s, ''))", argTokenResolverFunc, args...)
+	},
+
+	// lower(value) returns the lowercase version of a text value.
+	"lower": func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		return singleArgTokenFunction("lower", "LOWER(%s)", argTokenResolverFunc, args...)
+	},
+
+	// upper(value) returns the uppercase version of a text value.
+	"upper": func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		return singleArgTokenFunction("upper", "UPPER(%s)", argTokenResolverFunc, args...)
+	},
+
+	// trim(value) returns a text value without its leading and trailing spaces.
+	"trim": func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		return singleArgTokenFunction("trim", "TRIM(%s)", argTokenResolverFunc, args...)
+	},
+
+	// coalesce(a, b, ...) returns the first non-NULL argument value.
+	//
+	// Note that empty strings are not NULL, aka. coalesce(a, b) returns a if a is an empty text field value.
+	"coalesce": func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		if len(args) < 2 {
+			return nil, fmt.Errorf("[coalesce] expected at least 2 arguments, got %d", len(args))
+		}
+
+		resolvedArgs, err := resolveTokenFunctionArgs("coalesce", argTokenResolverFunc, args, valueTokenTypes...)
+		if err != nil {
+			return nil, err
+		}
+
+		identifiers := make([]string, len(resolvedArgs))
+		params := make([]dbx.Params, len(resolvedArgs))
+		for i, arg := range resolvedArgs {
+			identifiers[i] = arg.Identifier
+			params[i] = arg.Params
+		}
+
+		return &ResolverResult{
+			Identifier: "COALESCE(" + strings.Join(identifiers, ", ") + ")",
+			Params:     mergeParams(params...),
+		}, nil
+	},
+
+	// dateAdd(date, amount, unit) returns a new datetime string by adding the specified
+	// amount (could be negative) of units to the date, e.g. dateAdd(@now, -7, "days").
+	//
+	// The supported units are "seconds", "minutes", "hours", "days", "months" and "years".
+	//
+	// Returns an empty string if the date argument is not a valid datetime value.
+	"dateAdd": func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		if len(args) != 3 {
+			return nil, fmt.Errorf("[dateAdd] expected 3 arguments, got %d", len(args))
+		}
+
+		if args[2].Type != fexpr.TokenText || !slices.Contains(dateAddUnits, args[2].Literal) {
+			return nil, fmt.Errorf("[dateAdd] the unit argument must be one of %v", dateAddUnits)
+		}
+
+		date, err := resolveTokenFunctionArgs("dateAdd", argTokenResolverFunc, args[:1], fexpr.TokenIdentifier, fexpr.TokenText, fexpr.TokenFunction)
+		if err != nil {
+			return nil, err
+		}
+
+		if args[1].Type != fexpr.TokenNumber && args[1].Type != fexpr.TokenIdentifier {
+			return nil, errors.New("[dateAdd] the amount argument must be an identifier or number")
+		}
+
+		amount, err := argTokenResolverFunc(args[1])
+		if err != nil {
+			return nil, fmt.Errorf("[dateAdd] failed to resolve the amount argument: %w", err)
+		}
+
+		return &ResolverResult{
+			Identifier: fmt.Sprintf(
+				"COALESCE(strftime('%%Y-%%m-%%d %%H:%%M:%%fZ', %s, (%s) || ' %s'), '')",
+				date[0].Identifier,
+				amount.Identifier,
+				args[2].Literal,
+			),
+			Params: mergeParams(date[0].Params, amount.Params),
+		}, nil
+	},
+
+	// any(array, value1, value2, ...) returns 1 (true) if the array contains
+	// at least one of the provided values, otherwise - 0 (false).
+	//
+	// Non-array arguments are treated as single item array,
+	// e.g. any(tags, "a", "b") = true.
+	"any": func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		return arrayQuantifierTokenFunction("any", " OR ", argTokenResolverFunc, args...)
+	},
+
+	// all(array, value1, value2, ...) returns 1 (true) if the array contains
+	// all of the provided values, otherwise - 0 (false).
+	//
+	// Non-array arguments are treated as single item array,
+	// e.g. all(tags, "a", "b") = true.
+	"all": func(argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error), args ...fexpr.Token) (*ResolverResult, error) {
+		return arrayQuantifierTokenFunction("all", " AND ", argTokenResolverFunc, args...)
+	},
+}
+
+// Note that similar to geoDistance, the functions below don't apply
+// a "match-all" constraint in case of multiple relation field arguments,
+// aka. they evaluate to true if at-least-one of the related records satisfies the condition.
+
+var valueTokenTypes = []fexpr.TokenType{
+	fexpr.TokenIdentifier,
+	fexpr.TokenText,
+	fexpr.TokenNumber,
+	fexpr.TokenFunction,
+}
+
+var dateAddUnits = []string{"seconds", "minutes", "hours", "days", "months", "years"}
+
+// resolveTokenFunctionArgs resolves the provided function arguments
+// ensuring that they are from one of the allowed token types.
+func resolveTokenFunctionArgs(
+	name string,
+	argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error),
+	args []fexpr.Token,
+	allowedTypes ...fexpr.TokenType,
+) ([]*ResolverResult, error) {
+	resolvedArgs := make([]*ResolverResult, len(args))
+
+	for i, arg := range args {
+		if !slices.Contains(allowedTypes, arg.Type) {
+			return nil, fmt.Errorf("[%s] unsupported argument %d type %q", name, i, arg.Type)
+		}
+
+		resolved, err := argTokenResolverFunc(arg)
+		if err != nil {
+			return nil, fmt.Errorf("[%s] failed to resolve argument %d: %w", name, i, err)
+		}
+
+		resolvedArgs[i] = resolved
+	}
+
+	return resolvedArgs, nil
+}
+
+func singleArgTokenFunction(
+	name string,
+	format string,
+	argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error),
+	args ...fexpr.Token,
+) (*ResolverResult, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("[%s] expected 1 argument, got %d", name, len(args))
+	}
+
+	resolvedArgs, err := resolveTokenFunctionArgs(name, argTokenResolverFunc, args, fexpr.TokenIdentifier, fexpr.TokenText, fexpr.TokenFunction)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ResolverResult{
+		Identifier: fmt.Sprintf(format, resolvedArgs[0].Identifier),
+		Params:     resolvedArgs[0].Params,
+	}, nil
+}
+
+func arrayQuantifierTokenFunction(
+	name string,
+	operator string,
+	argTokenResolverFunc func(fexpr.Token) (*ResolverResult, error),
+	args ...fexpr.Token,
+) (*ResolverResult, error) {
+	if len(args) < 2 {
+		return nil, fmt.Errorf("[%s] expected at least 2 arguments, got %d", name, len(args))
+	}
+
+	array, err := resolveTokenFunctionArgs(name, argTokenResolverFunc, args[:1], fexpr.TokenIdentifier, fexpr.TokenFunction)
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := resolveTokenFunctionArgs(name, argTokenResolverFunc, args[1:], valueTokenTypes...)
+	if err != nil {
+		return nil, err
+	}
+
+	// normalize non-json and non-array values
+	// (similar to dbutils.JSONEach but for an arbitrary identifier expression)
+	column := array[0].Identifier
+	jsonEach := fmt.Sprintf(
+		"json_each(CASE WHEN iif(json_valid(%s), json_type(%s)='array', FALSE) THEN %s ELSE json_array(%s) END)",
+		column, column, column, column,
+	)
+
+	conditions := make([]string, len(values))
+	params := make([]dbx.Params, 0, len(values)+1)
+	params = append(params, array[0].Params)
+	for i, v := range values {
+		conditions[i] = fmt.Sprintf("EXISTS (SELECT 1 FROM %s {{__q}} WHERE [[__q.value]] = %s)", jsonEach, v.Identifier)
+		params = append(params, v.Params)
+	}
+
+	return &ResolverResult{
+		Identifier: "(" + strings.Join(conditions, operator) + ")",
+		Params:     mergeParams(params...),
+	}, nil
 }
@@ -275,3 +275,90 @@ func testCompareResults(t *testing.T, a, b *ResolverResult) {
 		t.Fatalf("Expected resolved identifiers to match, got\n%s\nvs\n%s", aResolved, bResolved)
 	}
 }
+
+func TestTokenFunctionsExpansion(t *testing.T) {
+	t.Parallel()
+
+	testDB, err := createTestDB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer testDB.Close()
+
+	resolver := NewSimpleFieldResolver("test1", "test2", "test3")
+
+	scenarios := []struct {
+		filter        string
+		expectError   bool
+		expectedTotal int
+	}{
+		// length
+		{`length() = 0`, true, 0},
+		{`length(test2, test3) = 0`, true, 0},
+		{`length(1) = 0`, true, 0},
+		{`length(test2) = 7`, false, 2},
+		{`length(test3) = 0`, false, 2},
+		{`length(null) = 0`, false, 2},
+		{`length("abc") = 3`, false, 2},
+
+		// lower, upper, trim
+		{`lower() = ""`, true, 0},
+		{`lower("ABC") = "abc"`, false, 2},
+		{`upper(test2) = "TEST2.1"`, false, 1},
+		{`trim("  a ") = "a"`, false, 2},
+		{`length(trim(upper(" a "))) = 1`, false, 2},
+
+		// coalesce
+		{`coalesce(test2) = ""`, true, 0},
+		{`coalesce(null, test2) = "test2.2"`, false, 1},
+		{`coalesce(null, null, 123) = 123`, false, 2},
+
+		// dateAdd
+		{`dateAdd("2024-01-31 10:00:00.000Z", 1) = null`, true, 0},
+		{`dateAdd("2024-01-31 10:00:00.000Z", 1, "weeks") = null`, true, 0},
+		{`dateAdd("2024-01-31 10:00:00.000Z", "1", "days") = null`, true, 0},
+		{`dateAdd("2024-01-31 10:00:00.000Z", 1, "days") = "2024-02-01 10:00:00.000Z"`, false, 2},
+		{`dateAdd("2024-01-01 10:00:00.123Z", -2, "hours") = "2024-01-01 08:00:00.123Z"`, false, 2},
+		{`dateAdd("2024-01-01 10:00:00.000Z", test1, "months") = "2024-02-01 10:00:00.000Z"`, false, 1},
+		{`dateAdd("invalid", 1, "days") = null`, false, 2},
+		{`dateAdd(@now, 1, "years") > @now`, false, 2},
+
+		// any, all
+		{`any(test2) = true`, true, 0},
+		{`any("a", "b") = true`, true, 0},
+		{`all(test2) = true`, true, 0},
+		{`any(test2, "test2.1") = true`, false, 1},
+		{`any(test2, "test2.1", "test2.2") = true`, false, 2},
+		{`all(test2, "test2.1", "test2.2") = true`, false, 0},
+		{`any(lower(upper(test2)), "test2.2") = true`, false, 1},
+		{`any(coalesce(null, '["a","b"]'), "b", "c") = true`, false, 2},
+		{`any(coalesce(null, '["a","b"]'), "c") = true`, false, 0},
+		{`all(coalesce(null, '["a","b",1]'), "a", "b", 1) = true`, false, 2},
+		{`all(coalesce(null, '["a","b"]'), "a", "c") = true`, false, 0},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.filter, func(t *testing.T) {
+			expr, err := FilterData(s.filter).BuildExpr(resolver)
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if hasErr {
+				return
+			}
+
+			var total int
+			err = testDB.Select("count(distinct id)").From("test").Where(expr).Row(&total)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if total != s.expectedTotal {
+				t.Fatalf("Expected %d rows, got %d", s.expectedTotal, total)
+			}
+		})
+	}
+}
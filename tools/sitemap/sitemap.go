@@ -0,0 +1,127 @@
+// Package sitemap implements minimal sitemaps.org protocol encoders.
+package sitemap
+
+import (
+	"encoding/xml"
+	"io"
+	"strconv"
+	"time"
+)
+
+// ContentType is the sitemap MIME type.
+const ContentType = "application/xml; charset=utf-8"
+
+// MaxURLs is the max number of urls allowed in a single sitemap file.
+const MaxURLs = 50000
+
+const xmlns = "http://www.sitemaps.org/schemas/sitemap/0.9"
+
+// Supported URL.ChangeFreq values.
+const (
+	ChangeFreqAlways  = "always"
+	ChangeFreqHourly  = "hourly"
+	ChangeFreqDaily   = "daily"
+	ChangeFreqWeekly  = "weekly"
+	ChangeFreqMonthly = "monthly"
+	ChangeFreqYearly  = "yearly"
+	ChangeFreqNever   = "never"
+)
+
+// URL defines a single sitemap url entry.
+type URL struct {
+	Loc string
+
+	// Lastmod is the optional last modification date of the page.
+	Lastmod time.Time
+
+	// ChangeFreq is the optional page change frequency hint.
+	ChangeFreq string
+
+	// Priority is the optional page priority in the 0-1 range (zero is omitted).
+	Priority float64
+}
+
+// Sitemap defines a single sitemap index entry.
+type Sitemap struct {
+	Loc string
+
+	// Lastmod is the optional last modification date of the sitemap.
+	Lastmod time.Time
+}
+
+// EncodeURLSet writes the provided urls as sitemap urlset document into w.
+func EncodeURLSet(w io.Writer, urls []URL) error {
+	doc := urlset{Xmlns: xmlns, URLs: make([]xmlURL, 0, len(urls))}
+
+	for _, u := range urls {
+		item := xmlURL{
+			Loc:        u.Loc,
+			Lastmod:    formatDate(u.Lastmod),
+			ChangeFreq: u.ChangeFreq,
+		}
+
+		if u.Priority > 0 {
+			item.Priority = strconv.FormatFloat(u.Priority, 'f', 1, 64)
+		}
+
+		doc.URLs = append(doc.URLs, item)
+	}
+
+	return encode(w, doc)
+}
+
+// EncodeIndex writes the provided sitemaps as sitemap index document into w.
+func EncodeIndex(w io.Writer, sitemaps []Sitemap) error {
+	doc := sitemapindex{Xmlns: xmlns, Sitemaps: make([]xmlSitemap, 0, len(sitemaps))}
+
+	for _, s := range sitemaps {
+		doc.Sitemaps = append(doc.Sitemaps, xmlSitemap{
+			Loc:     s.Loc,
+			Lastmod: formatDate(s.Lastmod),
+		})
+	}
+
+	return encode(w, doc)
+}
+
+func encode(w io.Writer, v any) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	return xml.NewEncoder(w).Encode(v)
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// -------------------------------------------------------------------
+
+type urlset struct {
+	XMLName xml.Name `xml:"urlset"`
+	Xmlns   string   `xml:"xmlns,attr"`
+	URLs    []xmlURL `xml:"url"`
+}
+
+type xmlURL struct {
+	Loc        string `xml:"loc"`
+	Lastmod    string `xml:"lastmod,omitempty"`
+	ChangeFreq string `xml:"changefreq,omitempty"`
+	Priority   string `xml:"priority,omitempty"`
+}
+
+type sitemapindex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	Xmlns    string       `xml:"xmlns,attr"`
+	Sitemaps []xmlSitemap `xml:"sitemap"`
+}
+
+type xmlSitemap struct {
+	Loc     string `xml:"loc"`
+	Lastmod string `xml:"lastmod,omitempty"`
+}
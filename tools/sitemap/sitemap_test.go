@@ -0,0 +1,63 @@
+package sitemap_test
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/pocketbase/pocketbase/tools/sitemap"
+)
+
+func TestEncodeURLSet(t *testing.T) {
+	t.Parallel()
+
+	urls := []sitemap.URL{
+		{Loc: "https://example.com/a?b=1&c=2"},
+		{
+			Loc:        "https://example.com/b",
+			Lastmod:    time.Date(2024, 1, 2, 10, 0, 0, 0, time.FixedZone("test", 3600)),
+			ChangeFreq: sitemap.ChangeFreqDaily,
+			Priority:   0.8,
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := sitemap.EncodeURLSet(&buf, urls); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<url><loc>https://example.com/a?b=1&amp;c=2</loc></url>` +
+		`<url><loc>https://example.com/b</loc><lastmod>2024-01-02T09:00:00Z</lastmod>` +
+		`<changefreq>daily</changefreq><priority>0.8</priority></url>` +
+		`</urlset>`
+
+	if v := buf.String(); v != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, v)
+	}
+}
+
+func TestEncodeIndex(t *testing.T) {
+	t.Parallel()
+
+	sitemaps := []sitemap.Sitemap{
+		{Loc: "https://example.com/sitemap1.xml"},
+		{Loc: "https://example.com/sitemap2.xml", Lastmod: time.Date(2024, 1, 2, 10, 0, 0, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	if err := sitemap.EncodeIndex(&buf, sitemaps); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+		`<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">` +
+		`<sitemap><loc>https://example.com/sitemap1.xml</loc></sitemap>` +
+		`<sitemap><loc>https://example.com/sitemap2.xml</loc><lastmod>2024-01-02T10:00:00Z</lastmod></sitemap>` +
+		`</sitemapindex>`
+
+	if v := buf.String(); v != expected {
+		t.Fatalf("Expected\n%s\ngot\n%s", expected, v)
+	}
+}
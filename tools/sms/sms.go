@@ -0,0 +1,68 @@
+// Package sms implements a minimal SMS sending abstraction
+// with Twilio and generic webhook based providers.
+package sms
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+// Message defines a generic SMS message struct.
+type Message struct {
+	// To is the recipient phone number in E.164 format (eg. "+359888123456").
+	To string `json:"to"`
+
+	// Body is the plain text message content.
+	Body string `json:"body"`
+}
+
+// Sender defines a base SMS client interface.
+type Sender interface {
+	// Send sends a SMS with the provided Message.
+	Send(message *Message) error
+}
+
+// ErrInvalidPhone is returned when the phone number couldn't be normalized to E.164.
+var ErrInvalidPhone = errors.New("invalid E.164 phone number")
+
+var e164Regex = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+
+var phoneSeparatorsReplacer = strings.NewReplacer(
+	" ", "",
+	"-", "",
+	".", "",
+	"(", "",
+	")", "",
+	"\t", "",
+	"\u00a0", "",
+)
+
+// NormalizePhone normalizes the provided phone number to the E.164 format
+// by removing the common separators (spaces, dashes, dots and brackets)
+// and replacing the international "00" prefix with "+".
+//
+// Numbers without a country code are not supported and return ErrInvalidPhone.
+//
+// Example:
+//
+//	NormalizePhone("+1 (555) 123-4567") // "+15551234567"
+//	NormalizePhone("0044 20 7946 0958") // "+442079460958"
+func NormalizePhone(phone string) (string, error) {
+	normalized := phoneSeparatorsReplacer.Replace(strings.TrimSpace(phone))
+
+	if strings.HasPrefix(normalized, "00") {
+		normalized = "+" + normalized[2:]
+	}
+
+	if !e164Regex.MatchString(normalized) {
+		return "", ErrInvalidPhone
+	}
+
+	return normalized, nil
+}
+
+// IsE164 reports whether the provided phone number is in a normalized E.164 format.
+func IsE164(phone string) bool {
+	return e164Regex.MatchString(phone)
+}
@@ -0,0 +1,77 @@
+package sms_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/sms"
+)
+
+func TestNormalizePhone(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		phone    string
+		expected string
+	}{
+		{"", ""},
+		{"abc", ""},
+		{"123456789", ""},
+		{"+0123456789", ""},
+		{"+123", ""},
+		{"+1234567890123456", ""},
+		{"+1 555 abc 4567", ""},
+		{"+15551234567", "+15551234567"},
+		{" +1 (555) 123-4567 ", "+15551234567"},
+		{"+359.888.123.456", "+359888123456"},
+		{"0044 20 7946 0958", "+442079460958"},
+		{"+44 20 7946 0958", "+442079460958"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.phone, func(t *testing.T) {
+			result, err := sms.NormalizePhone(s.phone)
+
+			if s.expected == "" {
+				if !errors.Is(err, sms.ErrInvalidPhone) {
+					t.Fatalf("Expected ErrInvalidPhone, got %v", err)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+
+			if !sms.IsE164(result) {
+				t.Fatalf("Expected %q to be a valid E.164 number", result)
+			}
+		})
+	}
+}
+
+func TestIsE164(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		phone    string
+		expected bool
+	}{
+		{"", false},
+		{"15551234567", false},
+		{"+1 5551234567", false},
+		{"+15551234567", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.phone, func(t *testing.T) {
+			if v := sms.IsE164(s.phone); v != s.expected {
+				t.Fatalf("Expected %v, got %v", s.expected, v)
+			}
+		})
+	}
+}
@@ -0,0 +1,83 @@
+package sms
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+var _ Sender = (*Twilio)(nil)
+
+const twilioDefaultBaseURL = "https://api.twilio.com"
+
+// Twilio defines a Twilio Programmable Messaging SMS client.
+type Twilio struct {
+	// AccountSid is the Twilio account identifier.
+	AccountSid string
+
+	// AuthToken is the Twilio account auth token.
+	AuthToken string
+
+	// From is the sender phone number or Messaging Service SID.
+	From string
+
+	// BaseURL is an optional API base url (default to "https://api.twilio.com").
+	BaseURL string
+
+	// HTTPClient is an optional custom HTTP client (default to a client with 30s timeout).
+	HTTPClient *http.Client
+}
+
+// Send implements [Sender.Send] interface.
+func (c *Twilio) Send(m *Message) error {
+	baseURL := c.BaseURL
+	if baseURL == "" {
+		baseURL = twilioDefaultBaseURL
+	}
+
+	form := url.Values{}
+	form.Set("To", m.To)
+	form.Set("Body", m.Body)
+	if strings.HasPrefix(c.From, "MG") {
+		form.Set("MessagingServiceSid", c.From)
+	} else {
+		form.Set("From", c.From)
+	}
+
+	endpoint := strings.TrimSuffix(baseURL, "/") + "/2010-04-01/Accounts/" + url.PathEscape(c.AccountSid) + "/Messages.json"
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.SetBasicAuth(c.AccountSid, c.AuthToken)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	return doRequest(c.HTTPClient, req)
+}
+
+func doRequest(client *http.Client, req *http.Request) error {
+	if client == nil {
+		client = &http.Client{Timeout: 30 * time.Second}
+	}
+
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 1024))
+		return fmt.Errorf("failed to send SMS (%d): %s", res.StatusCode, body)
+	}
+
+	return nil
+}
@@ -0,0 +1,85 @@
+package sms_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/sms"
+)
+
+func TestTwilioSend(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name          string
+		from          string
+		status        int
+		expectedField string
+		expectError   bool
+	}{
+		{"phone sender", "+15550001111", 201, "From", false},
+		{"messaging service sender", "MG123", 201, "MessagingServiceSid", false},
+		{"error response", "+15550001111", 400, "From", true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var calls int
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+
+				if r.Method != http.MethodPost {
+					t.Errorf("Expected POST request, got %s", r.Method)
+				}
+
+				if r.URL.Path != "/2010-04-01/Accounts/AC123/Messages.json" {
+					t.Errorf("Unexpected request path %q", r.URL.Path)
+				}
+
+				user, pass, _ := r.BasicAuth()
+				if user != "AC123" || pass != "secret" {
+					t.Errorf("Unexpected basic auth credentials %q:%q", user, pass)
+				}
+
+				if err := r.ParseForm(); err != nil {
+					t.Error(err)
+				}
+
+				if v := r.PostForm.Get("To"); v != "+15551234567" {
+					t.Errorf("Expected To %q, got %q", "+15551234567", v)
+				}
+
+				if v := r.PostForm.Get("Body"); v != "test" {
+					t.Errorf("Expected Body %q, got %q", "test", v)
+				}
+
+				if v := r.PostForm.Get(s.expectedField); v != s.from {
+					t.Errorf("Expected %s %q, got %q", s.expectedField, s.from, v)
+				}
+
+				w.WriteHeader(s.status)
+			}))
+			defer server.Close()
+
+			client := &sms.Twilio{
+				AccountSid: "AC123",
+				AuthToken:  "secret",
+				From:       s.from,
+				BaseURL:    server.URL,
+			}
+
+			err := client.Send(&sms.Message{To: "+15551234567", Body: "test"})
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if calls != 1 {
+				t.Fatalf("Expected 1 call, got %d", calls)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package sms
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+var _ Sender = (*Webhook)(nil)
+
+// Webhook defines a generic SMS client that submits the messages
+// as JSON POST request to a custom HTTP endpoint (eg. an SMS gateway proxy).
+//
+// The request body has the following format:
+//
+//	{"to": "+359888123456", "body": "..."}
+type Webhook struct {
+	// URL is the webhook endpoint.
+	URL string
+
+	// Headers is an optional list of extra request headers (eg. "Authorization").
+	Headers map[string]string
+
+	// HTTPClient is an optional custom HTTP client (default to a client with 30s timeout).
+	HTTPClient *http.Client
+}
+
+// Send implements [Sender.Send] interface.
+func (c *Webhook) Send(m *Message) error {
+	body, err := json.Marshal(m)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range c.Headers {
+		req.Header.Set(k, v)
+	}
+
+	return doRequest(c.HTTPClient, req)
+}
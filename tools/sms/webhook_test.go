@@ -0,0 +1,73 @@
+package sms_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/pocketbase/pocketbase/tools/sms"
+)
+
+func TestWebhookSend(t *testing.T) {
+	t.Parallel()
+
+	scenarios := []struct {
+		name        string
+		status      int
+		expectError bool
+	}{
+		{"success response", 200, false},
+		{"error response", 500, true},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			var calls int
+
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				calls++
+
+				if r.Method != http.MethodPost {
+					t.Errorf("Expected POST request, got %s", r.Method)
+				}
+
+				if v := r.Header.Get("Authorization"); v != "Bearer 123" {
+					t.Errorf("Expected Authorization header %q, got %q", "Bearer 123", v)
+				}
+
+				if v := r.Header.Get("Content-Type"); v != "application/json" {
+					t.Errorf("Expected json Content-Type, got %q", v)
+				}
+
+				m := &sms.Message{}
+				if err := json.NewDecoder(r.Body).Decode(m); err != nil {
+					t.Error(err)
+				}
+
+				if m.To != "+15551234567" || m.Body != "test" {
+					t.Errorf("Unexpected message %#v", m)
+				}
+
+				w.WriteHeader(s.status)
+			}))
+			defer server.Close()
+
+			client := &sms.Webhook{
+				URL:     server.URL,
+				Headers: map[string]string{"Authorization": "Bearer 123"},
+			}
+
+			err := client.Send(&sms.Message{To: "+15551234567", Body: "test"})
+
+			hasErr := err != nil
+			if hasErr != s.expectError {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectError, hasErr, err)
+			}
+
+			if calls != 1 {
+				t.Fatalf("Expected 1 call, got %d", calls)
+			}
+		})
+	}
+}
@@ -2,6 +2,9 @@ package subscriptions
 
 import (
 	"io"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // Message defines a client's channel data.
@@ -35,3 +38,31 @@ func (m *Message) WriteSSE(w io.Writer, eventId string) error {
 
 	return nil
 }
+
+// WriteSSEComment writes the provided comment in a SSE format into the provided writer.
+//
+// SSE comments are ignored by the clients and are usually used as
+// keep-alive heartbeats to prevent idle proxy connection drops.
+//
+// Multiline comments are split into separate comment lines.
+func WriteSSEComment(w io.Writer, comment string) error {
+	var sb strings.Builder
+
+	for _, line := range strings.Split(comment, "\n") {
+		sb.WriteString(":" + line + "\n")
+	}
+	sb.WriteString("\n")
+
+	_, err := io.WriteString(w, sb.String())
+
+	return err
+}
+
+// WriteSSERetry writes a SSE "retry:" field into the provided writer
+// instructing the client how long to wait before reconnecting
+// after a connection drop (with millisecond precision).
+func WriteSSERetry(w io.Writer, retry time.Duration) error {
+	_, err := io.WriteString(w, "retry:"+strconv.FormatInt(retry.Milliseconds(), 10)+"\n\n")
+
+	return err
+}
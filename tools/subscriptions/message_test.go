@@ -3,6 +3,7 @@ package subscriptions_test
 import (
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/pocketbase/pocketbase/tools/subscriptions"
 )
@@ -23,3 +24,42 @@ func TestMessageWrite(t *testing.T) {
 		t.Fatalf("Expected writer content\n%q\ngot\n%q", expected, v)
 	}
 }
+
+func TestWriteSSEComment(t *testing.T) {
+	scenarios := []struct {
+		comment  string
+		expected string
+	}{
+		{"", ":\n\n"},
+		{"ping", ":ping\n\n"},
+		{"a\nb", ":a\n:b\n\n"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.comment, func(t *testing.T) {
+			var sb strings.Builder
+
+			if err := subscriptions.WriteSSEComment(&sb, s.comment); err != nil {
+				t.Fatal(err)
+			}
+
+			if v := sb.String(); v != s.expected {
+				t.Fatalf("Expected writer content\n%q\ngot\n%q", s.expected, v)
+			}
+		})
+	}
+}
+
+func TestWriteSSERetry(t *testing.T) {
+	var sb strings.Builder
+
+	if err := subscriptions.WriteSSERetry(&sb, 1500*time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "retry:1500\n\n"
+
+	if v := sb.String(); v != expected {
+		t.Fatalf("Expected writer content\n%q\ngot\n%q", expected, v)
+	}
+}
@@ -19,14 +19,29 @@
 //		"layout.html",
 //		"content.html",
 //	).Render(map[string]any{"name": "Jane"})
+//
+// Shared components (aka. partials and layouts) could be registered once
+// and then used by all loaded templates:
+//
+//	registry.AddPartials(map[string]string{
+//		"header": `<header>{{.title}}</header>`,
+//		"layout": `<html>{{template "header" .}}{{block "content" .}}{{end}}</html>`,
+//	})
+//
+//	html3, err := registry.LoadString(
+//		`{{define "content"}}Hello {{.name}}!{{end}}{{template "layout" .}}`,
+//	).Render(map[string]any{"title": "Example", "name": "John"})
 package template
 
 import (
 	"fmt"
 	"html/template"
 	"io/fs"
+	"maps"
+	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 
 	"github.com/pocketbase/pocketbase/tools/store"
 )
@@ -37,7 +52,8 @@ import (
 // Use the Registry.Load* methods to load templates into the registry.
 func NewRegistry() *Registry {
 	return &Registry{
-		cache: store.New[string, *Renderer](nil),
+		cache:    store.New[string, *Renderer](nil),
+		partials: map[string]partial{},
 		funcs: template.FuncMap{
 			"raw": func(str string) template.HTML {
 				return template.HTML(str)
@@ -50,8 +66,35 @@ func NewRegistry() *Registry {
 //
 // Use the Registry.Load* methods to load templates into the registry.
 type Registry struct {
-	cache *store.Store[string, *Renderer]
-	funcs template.FuncMap
+	cache    *store.Store[string, *Renderer]
+	funcs    template.FuncMap
+	partials map[string]partial
+	mu       sync.RWMutex
+
+	hotReload bool
+}
+
+// partial defines a single named template that is available in all registry templates.
+//
+// If filename is set, the partial text is (re)read from the file on each template parse.
+type partial struct {
+	text     string
+	filename string
+}
+
+// SetHotReload enables or disables the registry templates hot-reload
+// (usually enabled only in dev mode).
+//
+// When enabled, the templates (including the partial files)
+// are parsed on every Load* call instead of being cached.
+func (r *Registry) SetHotReload(enable bool) *Registry {
+	r.mu.Lock()
+	r.hotReload = enable
+	r.mu.Unlock()
+
+	r.cache.RemoveAll()
+
+	return r
 }
 
 // AddFuncs registers new global template functions.
@@ -71,13 +114,68 @@ type Registry struct {
 //	  ...
 //	})
 func (r *Registry) AddFuncs(funcs map[string]any) *Registry {
+	r.mu.Lock()
 	for name, f := range funcs {
 		r.funcs[name] = f
 	}
+	r.mu.Unlock()
+
+	// reset the already parsed templates to apply the new funcs
+	r.cache.RemoveAll()
 
 	return r
 }
 
+// AddPartials registers new named partial templates (e.g. shared components or layouts)
+// that could be used by all registry templates with {{template "name" .}}.
+//
+// If a partial with the map entry name already exists it will be replaced with the new one.
+//
+// Example:
+//
+//	r.AddPartials(map[string]string{
+//	  "button": `<button type="button">{{.}}</button>`,
+//	  ...
+//	})
+func (r *Registry) AddPartials(partials map[string]string) *Registry {
+	r.mu.Lock()
+	for name, text := range partials {
+		r.partials[name] = partial{text: text}
+	}
+	r.mu.Unlock()
+
+	// reset the already parsed templates to apply the new partials
+	r.cache.RemoveAll()
+
+	return r
+}
+
+// AddPartialFiles registers the specified files as named partial templates
+// (the name of each partial is its file base name, e.g. "layout.html").
+//
+// It returns an error if any of the files is missing or it is not readable.
+func (r *Registry) AddPartialFiles(filenames ...string) error {
+	partials := make(map[string]partial, len(filenames))
+
+	for _, filename := range filenames {
+		raw, err := os.ReadFile(filename)
+		if err != nil {
+			return err
+		}
+
+		partials[filepath.Base(filename)] = partial{text: string(raw), filename: filename}
+	}
+
+	r.mu.Lock()
+	maps.Copy(r.partials, partials)
+	r.mu.Unlock()
+
+	// reset the already parsed templates to apply the new partials
+	r.cache.RemoveAll()
+
+	return nil
+}
+
 // LoadFiles caches (if not already) the specified filenames set as a
 // single template and returns a ready to use Renderer instance.
 //
@@ -85,31 +183,33 @@ func (r *Registry) AddFuncs(funcs map[string]any) *Registry {
 func (r *Registry) LoadFiles(filenames ...string) *Renderer {
 	key := strings.Join(filenames, ",")
 
-	found := r.cache.Get(key)
+	return r.load(key, func() (*template.Template, error) {
+		var name string
+		if len(filenames) > 0 {
+			name = filepath.Base(filenames[0])
+		}
 
-	if found == nil {
-		// parse and cache
-		tpl, err := template.New(filepath.Base(filenames[0])).Funcs(r.funcs).ParseFiles(filenames...)
-		found = &Renderer{template: tpl, parseError: err}
-		r.cache.Set(key, found)
-	}
+		tpl, err := r.newTemplate(name)
+		if err != nil {
+			return nil, err
+		}
 
-	return found
+		return tpl.ParseFiles(filenames...)
+	})
 }
 
 // LoadString caches (if not already) the specified inline string as a
 // single template and returns a ready to use Renderer instance.
 func (r *Registry) LoadString(text string) *Renderer {
-	found := r.cache.Get(text)
-
-	if found == nil {
-		// parse and cache (using the text as key)
-		tpl, err := template.New("").Funcs(r.funcs).Parse(text)
-		found = &Renderer{template: tpl, parseError: err}
-		r.cache.Set(text, found)
-	}
+	// use the text as key
+	return r.load(text, func() (*template.Template, error) {
+		tpl, err := r.newTemplate("")
+		if err != nil {
+			return nil, err
+		}
 
-	return found
+		return tpl.Parse(text)
+	})
 }
 
 // LoadFS caches (if not already) the specified fs and globPatterns
@@ -120,9 +220,7 @@ func (r *Registry) LoadString(text string) *Renderer {
 func (r *Registry) LoadFS(fsys fs.FS, globPatterns ...string) *Renderer {
 	key := fmt.Sprintf("%v%v", fsys, globPatterns)
 
-	found := r.cache.Get(key)
-
-	if found == nil {
+	return r.load(key, func() (*template.Template, error) {
 		// find the first file to use as template name (it is required when specifying Funcs)
 		var firstFilename string
 		if len(globPatterns) > 0 {
@@ -132,10 +230,65 @@ func (r *Registry) LoadFS(fsys fs.FS, globPatterns ...string) *Renderer {
 			}
 		}
 
-		tpl, err := template.New(firstFilename).Funcs(r.funcs).ParseFS(fsys, globPatterns...)
-		found = &Renderer{template: tpl, parseError: err}
+		tpl, err := r.newTemplate(firstFilename)
+		if err != nil {
+			return nil, err
+		}
+
+		return tpl.ParseFS(fsys, globPatterns...)
+	})
+}
+
+// load returns the cached Renderer with the specified key
+// or parses and caches a new one (if hot-reload is not enabled).
+func (r *Registry) load(key string, parse func() (*template.Template, error)) *Renderer {
+	r.mu.RLock()
+	hotReload := r.hotReload
+	r.mu.RUnlock()
+
+	if !hotReload {
+		if found := r.cache.Get(key); found != nil {
+			return found
+		}
+	}
+
+	tpl, err := parse()
+	if err != nil {
+		tpl = nil
+	}
+
+	found := &Renderer{template: tpl, parseError: err}
+
+	if !hotReload {
 		r.cache.Set(key, found)
 	}
 
 	return found
 }
+
+// newTemplate creates a new named template with the registered funcs and partials.
+func (r *Registry) newTemplate(name string) (*template.Template, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	tpl := template.New(name).Funcs(r.funcs)
+
+	for partialName, p := range r.partials {
+		text := p.text
+
+		// reload the partial file content
+		if p.filename != "" && r.hotReload {
+			raw, err := os.ReadFile(p.filename)
+			if err != nil {
+				return nil, err
+			}
+			text = string(raw)
+		}
+
+		if _, err := tpl.New(partialName).Parse(text); err != nil {
+			return nil, fmt.Errorf("failed to parse partial %q: %w", partialName, err)
+		}
+	}
+
+	return tpl, nil
+}
@@ -54,6 +54,171 @@ func TestRegistryAddFuncs(t *testing.T) {
 	}
 }
 
+func TestRegistryAddFuncsResetCache(t *testing.T) {
+	r := NewRegistry()
+
+	r.LoadString(`{{.}}`)
+
+	if v := r.cache.Length(); v != 1 {
+		t.Fatalf("Expected 1 cached template, got %d", v)
+	}
+
+	r.AddFuncs(map[string]any{"test": func() string { return "" }})
+
+	if v := r.cache.Length(); v != 0 {
+		t.Fatalf("Expected the cache to be reset, got %d", v)
+	}
+}
+
+func TestRegistryAddPartials(t *testing.T) {
+	r := NewRegistry()
+
+	r.LoadString(`{{.}}`)
+
+	r.AddPartials(map[string]string{
+		"header": `<h1>{{.title}}</h1>`,
+		"layout": `<main>{{template "header" .}}{{block "content" .}}default{{end}}</main>`,
+	})
+
+	if v := r.cache.Length(); v != 0 {
+		t.Fatalf("Expected the cache to be reset, got %d", v)
+	}
+
+	scenarios := []struct {
+		name     string
+		text     string
+		expected string
+	}{
+		{
+			"partial",
+			`{{template "header" .}}`,
+			"<h1>test</h1>",
+		},
+		{
+			"layout with default block",
+			`{{template "layout" .}}`,
+			"<main><h1>test</h1>default</main>",
+		},
+		{
+			"layout with overwritten block",
+			`{{define "content"}}custom{{end}}{{template "layout" .}}`,
+			"<main><h1>test</h1>custom</main>",
+		},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result, err := r.LoadString(s.text).Render(map[string]any{"title": "test"})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if result != s.expected {
+				t.Fatalf("Expected Render() result %q, got %q", s.expected, result)
+			}
+		})
+	}
+
+	t.Run("invalid partial", func(t *testing.T) {
+		r.AddPartials(map[string]string{"invalid": `{{end}}`})
+
+		_, err := r.LoadString(`test`).Render(nil)
+		if err == nil || !strings.Contains(err.Error(), "invalid") {
+			t.Fatalf("Expected invalid partial parse error, got %v", err)
+		}
+	})
+}
+
+func TestRegistryAddPartialFiles(t *testing.T) {
+	dir := t.TempDir()
+
+	partialFile := filepath.Join(dir, "header.html")
+	if err := os.WriteFile(partialFile, []byte(`<h1>{{.}}</h1>`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRegistry()
+
+	if err := r.AddPartialFiles(filepath.Join(dir, "missing.html")); err == nil {
+		t.Fatal("Expected error for missing partial file")
+	}
+
+	if err := r.AddPartialFiles(partialFile); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := r.LoadString(`{{template "header.html" .}}`).Render("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "<h1>test</h1>"
+	if result != expected {
+		t.Fatalf("Expected Render() result %q, got %q", expected, result)
+	}
+}
+
+func TestRegistrySetHotReload(t *testing.T) {
+	dir := t.TempDir()
+
+	partialFile := filepath.Join(dir, "partial.html")
+	contentFile := filepath.Join(dir, "content.html")
+
+	write := func(partial, content string) {
+		if err := os.WriteFile(partialFile, []byte(partial), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(contentFile, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	render := func(r *Registry) string {
+		result, err := r.LoadFiles(contentFile).Render(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return result
+	}
+
+	scenarios := []struct {
+		hotReload bool
+		expected  string
+	}{
+		{false, "content1:partial1"},
+		{true, "content2:partial2"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(fmt.Sprintf("%v", s.hotReload), func(t *testing.T) {
+			write("partial1", `content1:{{template "partial.html"}}`)
+
+			r := NewRegistry().SetHotReload(s.hotReload)
+			if err := r.AddPartialFiles(partialFile); err != nil {
+				t.Fatal(err)
+			}
+
+			if result := render(r); result != "content1:partial1" {
+				t.Fatalf("Expected initial result %q, got %q", "content1:partial1", result)
+			}
+
+			write("partial2", `content2:{{template "partial.html"}}`)
+
+			if result := render(r); result != s.expected {
+				t.Fatalf("Expected result %q, got %q", s.expected, result)
+			}
+
+			expectedCached := 1
+			if s.hotReload {
+				expectedCached = 0
+			}
+			if v := r.cache.Length(); v != expectedCached {
+				t.Fatalf("Expected %d cached templates, got %d", expectedCached, v)
+			}
+		})
+	}
+}
+
 func TestRegistryLoadFiles(t *testing.T) {
 	r := NewRegistry()
 
@@ -15,6 +15,21 @@ type Renderer struct {
 // Render executes the template with the specified data as the dot object
 // and returns the result as plain string.
 func (r *Renderer) Render(data any) (string, error) {
+	return r.execute("", data)
+}
+
+// RenderTemplate is similar to [Renderer.Render] but executes
+// the associated template with the specified name
+// (e.g. a registered layout partial or a {{define "name"}} block).
+func (r *Renderer) RenderTemplate(name string, data any) (string, error) {
+	if name == "" {
+		return "", errors.New("missing template name")
+	}
+
+	return r.execute(name, data)
+}
+
+func (r *Renderer) execute(name string, data any) (string, error) {
 	if r.parseError != nil {
 		return "", r.parseError
 	}
@@ -25,7 +40,13 @@ func (r *Renderer) Render(data any) (string, error) {
 
 	buf := new(bytes.Buffer)
 
-	if err := r.template.Execute(buf, data); err != nil {
+	var err error
+	if name == "" {
+		err = r.template.Execute(buf, data)
+	} else {
+		err = r.template.ExecuteTemplate(buf, name, data)
+	}
+	if err != nil {
 		return "", err
 	}
 
@@ -61,3 +61,64 @@ func TestRendererRender(t *testing.T) {
 		})
 	}
 }
+
+func TestRendererRenderTemplate(t *testing.T) {
+	tpl, _ := template.New("").Parse(`{{define "a"}}A:{{.Name}}{{end}}{{define "b"}}B:{{.Name}}{{end}}root`)
+
+	scenarios := map[string]struct {
+		renderer       *Renderer
+		name           string
+		expectedHasErr bool
+		expectedResult string
+	}{
+		"with nil template": {
+			&Renderer{},
+			"a",
+			true,
+			"",
+		},
+		"with parse error": {
+			&Renderer{
+				template:   tpl,
+				parseError: errors.New("test"),
+			},
+			"a",
+			true,
+			"",
+		},
+		"empty name": {
+			&Renderer{template: tpl},
+			"",
+			true,
+			"",
+		},
+		"missing name": {
+			&Renderer{template: tpl},
+			"missing",
+			true,
+			"",
+		},
+		"existing name": {
+			&Renderer{template: tpl},
+			"b",
+			false,
+			"B:world",
+		},
+	}
+
+	for name, s := range scenarios {
+		t.Run(name, func(t *testing.T) {
+			result, err := s.renderer.RenderTemplate(s.name, struct{ Name string }{"world"})
+
+			hasErr := err != nil
+
+			if s.expectedHasErr != hasErr {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectedHasErr, hasErr, err)
+			}
+
+			if s.expectedResult != result {
+				t.Fatalf("Expected result %v, got %v", s.expectedResult, result)
+			}
+		})
+	}
+}
@@ -11,6 +11,10 @@ import (
 // DefaultDateLayout specifies the default app date strings layout.
 const DefaultDateLayout = "2006-01-02 15:04:05.000Z"
 
+// DateLayoutWithOffset specifies the app date strings layout
+// with explicit timezone offset (e.g. when rendering a date in a specific timezone).
+const DateLayoutWithOffset = "2006-01-02 15:04:05.000-07:00"
+
 // NowDateTime returns new DateTime instance with the current local time.
 func NowDateTime() DateTime {
 	return DateTime{t: time.Now()}
@@ -24,6 +28,29 @@ func ParseDateTime(value any) (DateTime, error) {
 	return d, err
 }
 
+// ParseDateTimeIn is similar to [ParseDateTime] but the date strings
+// without explicit timezone (e.g. "2024-01-02 10:00:00") are interpreted in loc.
+//
+// If loc is nil, it fallbacks to UTC.
+func ParseDateTimeIn(value any, loc *time.Location) (DateTime, error) {
+	str, ok := value.(string)
+	if !ok || str == "" || loc == nil || loc == time.UTC {
+		return ParseDateTime(value)
+	}
+
+	// the default layout always has explicit UTC timezone
+	if t, err := time.Parse(DefaultDateLayout, str); err == nil {
+		return DateTime{t: t}, nil
+	}
+
+	t, err := cast.ToTimeInDefaultLocationE(str, loc)
+	if err != nil {
+		return DateTime{}, err
+	}
+
+	return DateTime{t: t}, nil
+}
+
 // DateTime represents a [time.Time] instance in UTC that is wrapped
 // and serialized using the app default date layout.
 type DateTime struct {
@@ -105,6 +132,26 @@ func (d DateTime) String() string {
 	return t.UTC().Format(DefaultDateLayout)
 }
 
+// StringIn serializes the current DateTime instance into a formatted
+// date string with explicit timezone offset relative to loc
+// (e.g. "2024-01-02 11:00:00.000+01:00").
+//
+// If loc is nil, it fallbacks to UTC.
+//
+// The zero value is serialized to an empty string.
+func (d DateTime) StringIn(loc *time.Location) string {
+	t := d.Time()
+	if t.IsZero() {
+		return ""
+	}
+
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	return t.In(loc).Format(DateLayoutWithOffset)
+}
+
 // MarshalJSON implements the [json.Marshaler] interface.
 func (d DateTime) MarshalJSON() ([]byte, error) {
 	return []byte(`"` + d.String() + `"`), nil
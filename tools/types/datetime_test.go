@@ -55,6 +55,47 @@ func TestParseDateTime(t *testing.T) {
 	}
 }
 
+func TestParseDateTimeIn(t *testing.T) {
+	prague, err := time.LoadLocation("Europe/Prague")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	scenarios := []struct {
+		value       any
+		loc         *time.Location
+		expected    string
+		expectedErr bool
+	}{
+		{nil, prague, "", false},
+		{"", prague, "", false},
+		{"invalid", prague, "", true},
+		{"invalid", nil, "", false}, // fallback to ParseDateTime
+		{"2022-01-01 11:23:45.678", nil, "2022-01-01 11:23:45.678Z", false},
+		{"2022-01-01 11:23:45.678", time.UTC, "2022-01-01 11:23:45.678Z", false},
+		{"2022-01-01 11:23:45.678", prague, "2022-01-01 10:23:45.678Z", false},
+		{"2022-07-01 11:23:45", prague, "2022-07-01 09:23:45.000Z", false}, // DST
+		{"2022-01-01 11:23:45.678Z", prague, "2022-01-01 11:23:45.678Z", false},
+		{"2022-01-01 11:23:45.678+03:00", prague, "2022-01-01 08:23:45.678Z", false},
+		{1641024040, prague, "2022-01-01 08:00:40.000Z", false},
+	}
+
+	for i, s := range scenarios {
+		t.Run(fmt.Sprintf("%d_%#v", i, s.value), func(t *testing.T) {
+			dt, err := types.ParseDateTimeIn(s.value, s.loc)
+
+			hasErr := err != nil
+			if hasErr != s.expectedErr {
+				t.Fatalf("Expected hasErr %v, got %v (%v)", s.expectedErr, hasErr, err)
+			}
+
+			if dt.String() != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, dt.String())
+			}
+		})
+	}
+}
+
 func TestDateTimeTime(t *testing.T) {
 	str := "2022-01-01 11:23:45.678Z"
 
@@ -303,6 +344,44 @@ func TestDateTimeString(t *testing.T) {
 	}
 }
 
+func TestDateTimeStringIn(t *testing.T) {
+	prague, err := time.LoadLocation("Europe/Prague")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dt, _ := types.ParseDateTime("2022-01-01 11:23:45.678Z")
+
+	scenarios := []struct {
+		name     string
+		dt       types.DateTime
+		loc      *time.Location
+		expected string
+	}{
+		{"zero", types.DateTime{}, prague, ""},
+		{"nil loc", dt, nil, "2022-01-01 11:23:45.678+00:00"},
+		{"utc", dt, time.UTC, "2022-01-01 11:23:45.678+00:00"},
+		{"Europe/Prague", dt, prague, "2022-01-01 12:23:45.678+01:00"},
+	}
+
+	for _, s := range scenarios {
+		t.Run(s.name, func(t *testing.T) {
+			result := s.dt.StringIn(s.loc)
+			if result != s.expected {
+				t.Fatalf("Expected %q, got %q", s.expected, result)
+			}
+
+			// should be parsable back to the same instant
+			if result != "" {
+				parsed, _ := types.ParseDateTime(result)
+				if !parsed.Equal(s.dt) {
+					t.Fatalf("Expected %v to be equal to %v", parsed, s.dt)
+				}
+			}
+		})
+	}
+}
+
 func TestDateTimeMarshalJSON(t *testing.T) {
 	scenarios := []struct {
 		date     string
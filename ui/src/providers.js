@@ -1,9 +1,15 @@
 import AppleOptions from "@/components/collections/providers/AppleOptions.svelte";
+import DiscordOptions from "@/components/collections/providers/DiscordOptions.svelte";
+import DropboxOptions from "@/components/collections/providers/DropboxOptions.svelte";
+import GitlabOptions from "@/components/collections/providers/GitlabOptions.svelte";
 import LarkOptions from "@/components/collections/providers/LarkOptions.svelte";
 import MicrosoftOptions from "@/components/collections/providers/MicrosoftOptions.svelte";
 import NextcloudOptions from "@/components/collections/providers/NextcloudOptions.svelte";
 import OIDCOptions from "@/components/collections/providers/OIDCOptions.svelte";
+import SalesforceOptions from "@/components/collections/providers/SalesforceOptions.svelte";
 import SelfHostedOptions from "@/components/collections/providers/SelfHostedOptions.svelte";
+import ShopifyOptions from "@/components/collections/providers/ShopifyOptions.svelte";
+import TwitchOptions from "@/components/collections/providers/TwitchOptions.svelte";
 
 // @todo remove after allowing custom OAuth2 UI extendability
 //
@@ -60,8 +66,7 @@ export default [
         key: "gitlab",
         title: "GitLab",
         logo: "gitlab.svg",
-        optionsComponent: SelfHostedOptions,
-        optionsComponentProps: { title: "Self-hosted endpoints (optional)" },
+        optionsComponent: GitlabOptions,
     },
     {
         key: "bitbucket",
@@ -84,6 +89,7 @@ export default [
         key: "discord",
         title: "Discord",
         logo: "discord.svg",
+        optionsComponent: DiscordOptions,
     },
     {
         key: "twitter",
@@ -126,6 +132,12 @@ export default [
         title: "Box",
         logo:  "box.svg",
     },
+    {
+        key: "dropbox",
+        title: "Dropbox",
+        logo: "dropbox.svg",
+        optionsComponent: DropboxOptions,
+    },
     {
         key: "spotify",
         title: "Spotify",
@@ -140,6 +152,7 @@ export default [
         key: "twitch",
         title: "Twitch",
         logo: "twitch.svg",
+        optionsComponent: TwitchOptions,
     },
     {
         key: "patreon",
@@ -180,6 +193,38 @@ export default [
         title: "Planning Center",
         logo: "planningcenter.svg",
     },
+    {
+        key: "salesforce",
+        title: "Salesforce",
+        logo: "salesforce.svg",
+        optionsComponent: SalesforceOptions,
+    },
+    {
+        key: "shopify",
+        title: "Shopify",
+        logo: "shopify.svg",
+        optionsComponent: ShopifyOptions,
+    },
+    {
+        key: "line",
+        title: "LINE",
+        logo: "line.svg",
+    },
+    {
+        key: "wechat",
+        title: "WeChat",
+        logo: "wechat.svg",
+    },
+    {
+        key: "wechatmp",
+        title: "WeChat Mini Program",
+        logo: "wechat.svg",
+    },
+    {
+        key: "qq",
+        title: "QQ",
+        logo: "qq.svg",
+    },
     {
         key: "oidc",
         title: "OpenID Connect",
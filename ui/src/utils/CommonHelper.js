@@ -1899,11 +1899,15 @@ export default class CommonHelper {
 
         result.push("@request.context");
         result.push("@request.method");
+        result.push("@request.ip");
         result.push("@request.query.");
         result.push("@request.body.");
         result.push("@request.headers.");
+        result.push("@request.custom.");
+        result.push("@request.geo.country");
         result.push("@request.auth.collectionId");
         result.push("@request.auth.collectionName");
+        result.push("@request.auth.sessionAge");
 
         // load auth collection fields
         const authCollections = collections.filter((collection) => collection.type === "auth");